@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var activityCmd = &cobra.Command{
+	Use:     "activity",
+	Aliases: []string{"logs"},
+	Short:   "Show a timeline of recent activity",
+	Long: `Show what arrgo has done recently: grabs, downloads, imports, cleanups,
+and library changes, oldest first, with the movie or series each event is about.
+
+With --follow, new events are printed as they happen until interrupted.`,
+	Example: `  arrgo activity                              # The last hour
+  arrgo activity --since 24h --type import.completed
+  arrgo activity -f                           # Keep watching for new events`,
+	RunE: runActivityCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(activityCmd)
+	activityCmd.Flags().String("since", "1h", "Only events since a time (RFC3339) or duration ago (e.g. 24h)")
+	activityCmd.Flags().StringArrayP("type", "t", nil, "Only events of this type (repeatable)")
+	activityCmd.Flags().IntP("limit", "l", 100, "Maximum number of events to show at once")
+	activityCmd.Flags().BoolP("follow", "f", false, "Keep polling for new events")
+	activityCmd.Flags().Duration("interval", 5*time.Second, "Polling interval with --follow")
+}
+
+func runActivityCmd(cmd *cobra.Command, args []string) error {
+	opts := EventsOptions{Enrich: true}
+	opts.Limit, _ = cmd.Flags().GetInt("limit")
+	opts.EventTypes, _ = cmd.Flags().GetStringArray("type")
+	follow, _ := cmd.Flags().GetBool("follow")
+	interval, _ := cmd.Flags().GetDuration("interval")
+
+	var err error
+	since, _ := cmd.Flags().GetString("since")
+	if opts.Since, err = parseEventTime(since); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if follow && interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	client := NewClient(serverURL)
+	resp, err := client.Events(opts)
+	if err != nil {
+		return fmt.Errorf("failed to fetch events: %w", err)
+	}
+
+	if !follow {
+		if jsonOutput {
+			printJSON(resp)
+			return nil
+		}
+		if len(resp.Items) == 0 {
+			fmt.Println("No activity")
+			return nil
+		}
+		if resp.Total > len(resp.Items) && !quietOutput {
+			fmt.Printf("Showing the latest %d of %d events\n\n", len(resp.Items), resp.Total)
+		}
+	}
+
+	lastID := printActivity(resp.Items, 0)
+	if !follow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// The API filters by second, so the newest event is fetched again and skipped by ID
+		if t, ok := latestEventTime(resp.Items); ok {
+			opts.Since = t
+		}
+		next, err := client.Events(opts)
+		if err != nil {
+			if !quietOutput {
+				fmt.Fprintf(os.Stderr, "failed to fetch events: %v\n", err)
+			}
+			continue
+		}
+		if len(next.Items) > 0 {
+			resp = next
+		}
+		lastID = printActivity(next.Items, lastID)
+	}
+}
+
+// printActivity prints events with an ID above lastID, oldest first, and
+// returns the highest ID printed (or lastID if none were new).
+// items are newest first, as returned by the API.
+func printActivity(items []EventResponse, lastID int64) int64 {
+	for _, e := range newActivity(items, lastID) {
+		if jsonOutput {
+			printJSON(e)
+		} else {
+			t, _ := time.Parse(time.RFC3339, e.OccurredAt)
+			fmt.Printf("  %-10s %-24s %s\n", formatTimeAgo(t.Unix()), e.EventType, activityEntity(e))
+		}
+		lastID = e.ID
+	}
+	return lastID
+}
+
+// newActivity returns the events with an ID above lastID in chronological order.
+func newActivity(items []EventResponse, lastID int64) []EventResponse {
+	var out []EventResponse
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].ID > lastID {
+			out = append(out, items[i])
+		}
+	}
+	return out
+}
+
+// latestEventTime returns the time of the newest event in items (newest first).
+func latestEventTime(items []EventResponse) (time.Time, bool) {
+	if len(items) == 0 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, items[0].OccurredAt)
+	return t, err == nil
+}
+
+// activityEntity describes an event's entity by its title, falling back to
+// "type #id" when the server could not resolve it (e.g. deleted content).
+func activityEntity(e EventResponse) string {
+	if e.Title != "" {
+		return e.Title
+	}
+	return fmt.Sprintf("%s #%d", e.EntityType, e.EntityID)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewActivity(t *testing.T) {
+	// Newest first, as returned by the API
+	items := []EventResponse{
+		{ID: 12, EventType: "import.completed"},
+		{ID: 11, EventType: "download.completed"},
+		{ID: 10, EventType: "grab.requested"},
+	}
+
+	got := newActivity(items, 0)
+	require.Len(t, got, 3)
+	assert.Equal(t, []int64{10, 11, 12}, []int64{got[0].ID, got[1].ID, got[2].ID})
+
+	got = newActivity(items, 11)
+	require.Len(t, got, 1)
+	assert.Equal(t, int64(12), got[0].ID)
+
+	assert.Empty(t, newActivity(items, 12))
+}
+
+func TestLatestEventTime(t *testing.T) {
+	_, ok := latestEventTime(nil)
+	assert.False(t, ok)
+
+	got, ok := latestEventTime([]EventResponse{
+		{ID: 2, OccurredAt: "2024-01-15T10:05:00Z"},
+		{ID: 1, OccurredAt: "2024-01-15T10:00:00Z"},
+	})
+	require.True(t, ok)
+	assert.True(t, got.Equal(time.Date(2024, 1, 15, 10, 5, 0, 0, time.UTC)))
+}
+
+func TestActivityEntity(t *testing.T) {
+	assert.Equal(t, "The Matrix (1999)", activityEntity(EventResponse{EntityType: "content", EntityID: 42, Title: "The Matrix (1999)"}))
+	assert.Equal(t, "download #7", activityEntity(EventResponse{EntityType: "download", EntityID: 7}))
+}
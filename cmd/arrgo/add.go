@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// addMaxMatches is how many lookup matches are offered for selection.
+const addMaxMatches = 5
+
+// overviewSnippetLen is the maximum length of an overview shown beside a match.
+const overviewSnippetLen = 70
+
+var addCmd = &cobra.Command{
+	Use:   "add <movie|series> <title|tmdb:ID|tvdb:ID>...",
+	Short: "Look up and add a movie or series",
+	Long: `Look up a movie on TMDB or a series on TVDB and add it to the library.
+
+Matches are listed with year and overview so you can pick one. An explicit
+tmdb:ID or tvdb:ID is added without prompting, for scripting.
+
+Examples:
+  arrgo add movie "the matrix"
+  arrgo add movie tmdb:603 --profile uhd
+  arrgo add series "breaking bad" --yes
+  arrgo add series tvdb:81189`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runAddCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	addCmd.Flags().String("profile", "hd", "Quality profile")
+	addCmd.Flags().BoolP("yes", "y", false, "Add the match without prompting when there is a single confident match")
+}
+
+func runAddCmd(cmd *cobra.Command, args []string) error {
+	contentType := args[0]
+	if contentType != contentTypeMovie && contentType != contentTypeSeries {
+		return fmt.Errorf("type must be 'movie' or 'series', got: %s", contentType)
+	}
+	query := strings.Join(args[1:], " ")
+	profile, _ := cmd.Flags().GetString("profile")
+	assumeYes, _ := cmd.Flags().GetBool("yes")
+
+	client := NewClient(serverURL)
+	resp, err := client.Lookup(contentType, query)
+	if err != nil {
+		return fmt.Errorf("lookup failed: %w", err)
+	}
+	if len(resp.Results) == 0 {
+		return fmt.Errorf("no %s found for %q", contentType, query)
+	}
+	matches := resp.Results
+	if len(matches) > addMaxMatches {
+		matches = matches[:addMaxMatches]
+	}
+
+	choice := confidentMatch(matches, query, isExternalIDQuery(query), assumeYes)
+	if choice == nil {
+		if assumeYes {
+			printLookupMatches(matches)
+			return errors.New("no single confident match; pick one by ID, e.g. arrgo add " + contentType + " " + externalIDArg(&matches[0]))
+		}
+		if choice = promptLookupMatch(matches); choice == nil {
+			fmt.Println("Canceled.")
+			return nil
+		}
+	}
+
+	if choice.ContentID != nil {
+		fmt.Printf("Already in library: %s (%d) [ID: %d]\n", choice.Title, choice.Year, *choice.ContentID)
+		return nil
+	}
+
+	var tmdbID, tvdbID int64
+	if choice.TMDBID != nil {
+		tmdbID = *choice.TMDBID
+	}
+	if choice.TVDBID != nil {
+		tvdbID = *choice.TVDBID
+	}
+	content, err := client.AddContent(contentType, choice.Title, choice.Year, profile, tmdbID, tvdbID)
+	if err != nil {
+		return fmt.Errorf("add failed: %w", err)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(content)
+	}
+	fmt.Printf("Added: %s (%d) [ID: %d, %s, profile: %s]\n",
+		content.Title, content.Year, content.ID, externalIDArg(choice), content.QualityProfile)
+	return nil
+}
+
+// isExternalIDQuery reports whether the query names a tmdb: or tvdb: ID.
+func isExternalIDQuery(query string) bool {
+	lower := strings.ToLower(strings.TrimSpace(query))
+	return strings.HasPrefix(lower, "tmdb:") || strings.HasPrefix(lower, "tvdb:")
+}
+
+// confidentMatch returns the match to add without prompting, or nil if the user must choose.
+// An explicit ID query is always confident. With assumeYes, a lone match is
+// confident, as is the only match whose title equals the query.
+func confidentMatch(matches []LookupResult, query string, explicitID, assumeYes bool) *LookupResult {
+	if explicitID && len(matches) == 1 {
+		return &matches[0]
+	}
+	if !assumeYes {
+		return nil
+	}
+	if len(matches) == 1 {
+		return &matches[0]
+	}
+
+	var exact *LookupResult
+	for i := range matches {
+		if strings.EqualFold(matches[i].Title, strings.TrimSpace(query)) {
+			if exact != nil {
+				return nil // e.g. a remake with the same title
+			}
+			exact = &matches[i]
+		}
+	}
+	return exact
+}
+
+// promptLookupMatch lists matches and asks the user to pick one; nil if canceled.
+func promptLookupMatch(matches []LookupResult) *LookupResult {
+	printLookupMatches(matches)
+	input := prompt(fmt.Sprintf("Select [1-%d, n to cancel]: ", len(matches)))
+	idx, err := strconv.Atoi(input)
+	if err != nil || idx < 1 || idx > len(matches) {
+		return nil
+	}
+	return &matches[idx-1]
+}
+
+// printLookupMatches prints numbered matches with year, ID and an overview snippet.
+func printLookupMatches(matches []LookupResult) {
+	for i := range matches {
+		m := &matches[i]
+		line := fmt.Sprintf("  %d. %s (%d) [%s]", i+1, m.Title, m.Year, externalIDArg(m))
+		if m.ContentID != nil {
+			line += " (in library)"
+		}
+		fmt.Println(line)
+		if snippet := overviewSnippet(m.Overview); snippet != "" {
+			fmt.Printf("     %s\n", snippet)
+		}
+	}
+}
+
+// externalIDArg formats a match's ID as accepted by arrgo add, e.g. "tmdb:603".
+func externalIDArg(m *LookupResult) string {
+	if m.TMDBID != nil {
+		return fmt.Sprintf("tmdb:%d", *m.TMDBID)
+	}
+	if m.TVDBID != nil {
+		return fmt.Sprintf("tvdb:%d", *m.TVDBID)
+	}
+	return "?"
+}
+
+// overviewSnippet shortens an overview to one line of at most overviewSnippetLen characters.
+func overviewSnippet(overview string) string {
+	s := strings.Join(strings.Fields(overview), " ")
+	runes := []rune(s)
+	if len(runes) <= overviewSnippetLen {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:overviewSnippetLen-3])) + "..."
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -91,12 +92,34 @@ type StatusResponse struct {
 	Version string `json:"version"`
 }
 
+// HealthResponse is the response of GET /health, served with 200 when
+// healthy and 503 when not.
+type HealthResponse struct {
+	Status string        `json:"status"` // ok or unavailable
+	Ready  bool          `json:"ready"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+type HealthCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"` // ok, failed, or skipped
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type ClientConnection struct {
+	Name      string `json:"name"`
+	Protocol  string `json:"protocol"`
+	Connected bool   `json:"connected"`
+	Error     string `json:"error,omitempty"`
+}
+
 type DashboardResponse struct {
 	Version     string `json:"version"`
 	Connections struct {
-		Server  bool `json:"server"`
-		Plex    bool `json:"plex"`
-		SABnzbd bool `json:"sabnzbd"`
+		Server  bool               `json:"server"`
+		Plex    bool               `json:"plex"`
+		Clients []ClientConnection `json:"clients"`
 	} `json:"connections"`
 	Downloads struct {
 		Queued      int `json:"queued"`
@@ -106,15 +129,50 @@ type DashboardResponse struct {
 		Imported    int `json:"imported"`
 		Cleaned     int `json:"cleaned"`
 		Failed      int `json:"failed"`
+		// Season packs with episodes that failed to import
+		PartiallyImported int `json:"partially_imported"`
+		ImportQueued      int `json:"import_queued"`
 	} `json:"downloads"`
 	Stuck struct {
-		Count     int   `json:"count"`
-		Threshold int64 `json:"threshold_minutes"`
+		Count      int              `json:"count"`
+		Threshold  int64            `json:"threshold_minutes"`
+		ByStatus   map[string]int   `json:"by_status"`
+		Thresholds map[string]int64 `json:"thresholds_minutes"`
 	} `json:"stuck"`
 	Library struct {
-		Movies int `json:"movies"`
-		Series int `json:"series"`
+		Movies    int   `json:"movies"`
+		Series    int   `json:"series"`
+		SizeBytes int64 `json:"size_bytes"`
 	} `json:"library"`
+	Recent      []DashboardImport  `json:"recent"`
+	Upcoming    []DashboardEpisode `json:"upcoming"`
+	Problems    []DashboardProblem `json:"problems"`
+	PlexChecked bool               `json:"plex_checked"`
+}
+
+type DashboardImport struct {
+	ContentID  int64  `json:"content_id"`
+	EpisodeID  *int64 `json:"episode_id,omitempty"`
+	Title      string `json:"title"`
+	Quality    string `json:"quality,omitempty"`
+	ImportedAt string `json:"imported_at"`
+}
+
+type DashboardEpisode struct {
+	ContentID    int64  `json:"content_id"`
+	EpisodeID    int64  `json:"episode_id"`
+	Series       string `json:"series"`
+	Season       int    `json:"season"`
+	Episode      int    `json:"episode"`
+	EpisodeTitle string `json:"episode_title,omitempty"`
+	AirDate      string `json:"air_date"`
+}
+
+type DashboardProblem struct {
+	Check   string `json:"check"`
+	Count   int    `json:"count"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
 }
 
 type DownloadResponse struct {
@@ -127,14 +185,30 @@ type DownloadResponse struct {
 	ClientID         string  `json:"client_id"`
 	Status           string  `json:"status"`
 	ReleaseName      string  `json:"release_name"`
+	ReleaseGroup     string  `json:"release_group,omitempty"`
 	Indexer          string  `json:"indexer"`
+	LastError        string  `json:"last_error,omitempty"`
 	AddedAt          string  `json:"added_at"`
 	CompletedAt      *string `json:"completed_at,omitempty"`
+	ImportPosition   int     `json:"import_position,omitempty"` // Place in the import queue, from 1
 	// Live status fields
 	Progress *float64 `json:"progress,omitempty"`
 	Size     *int64   `json:"size,omitempty"`
 	Speed    *int64   `json:"speed,omitempty"`
 	ETA      *string  `json:"eta,omitempty"`
+	// Per-episode import outcome of a season pack
+	EpisodeResults []EpisodeResultResponse `json:"episode_results,omitempty"`
+}
+
+type EpisodeResultResponse struct {
+	SourceFile      string `json:"source_file"`
+	FileID          int64  `json:"file_id,omitempty"`
+	Season          int    `json:"season"`
+	Episode         int    `json:"episode"`
+	Success         bool   `json:"success"`
+	FilePath        string `json:"file_path,omitempty"`
+	Error           string `json:"error,omitempty"`
+	AlreadyImported bool   `json:"already_imported,omitempty"`
 }
 
 type ListDownloadsResponse struct {
@@ -153,9 +227,45 @@ type ReleaseResponse struct {
 	Score       int    `json:"score"`
 }
 
+type RejectedReleaseResponse struct {
+	Title     string   `json:"title"`
+	Indexer   string   `json:"indexer"`
+	Reason    string   `json:"reason"`
+	Languages []string `json:"languages,omitempty"`
+}
+
+// DeferredReleaseResponse is a usenet release younger than the minimum age.
+type DeferredReleaseResponse struct {
+	ReleaseResponse
+	RemainingSeconds int64 `json:"remaining_seconds"`
+}
+
 type SearchResponse struct {
-	Releases []ReleaseResponse `json:"releases"`
-	Errors   []string          `json:"errors,omitempty"`
+	Releases   []ReleaseResponse         `json:"releases"`
+	Rejected   []RejectedReleaseResponse `json:"rejected"`
+	Deferred   []DeferredReleaseResponse `json:"deferred,omitempty"`
+	Errors     []SearchError             `json:"errors,omitempty"`
+	Cached     bool                      `json:"cached"`
+	AgeSeconds int64                     `json:"age_seconds,omitempty"`
+}
+
+type SearchError struct {
+	Indexer           string `json:"indexer,omitempty"`
+	Code              int    `json:"code,omitempty"`
+	Message           string `json:"message"`
+	RetryAfterSeconds int64  `json:"retry_after_seconds,omitempty"`
+}
+
+// String formats the error as "indexer: message (retry in 10m0s)".
+func (e SearchError) String() string {
+	msg := e.Message
+	if e.Indexer != "" {
+		msg = e.Indexer + ": " + msg
+	}
+	if e.RetryAfterSeconds > 0 {
+		msg += fmt.Sprintf(" (retry in %s)", time.Duration(e.RetryAfterSeconds)*time.Second)
+	}
+	return msg
 }
 
 type EpisodeStatsResponse struct {
@@ -196,15 +306,22 @@ type ImportRequest struct {
 	Quality    string `json:"quality,omitempty"`
 	Season     *int   `json:"season,omitempty"`
 	Episode    *int   `json:"episode,omitempty"`
+
+	AllowDowngrade bool `json:"allow_downgrade,omitempty"`
 }
 
 type ImportResponse struct {
-	FileID       int64  `json:"file_id"`
-	ContentID    int64  `json:"content_id"`
-	SourcePath   string `json:"source_path"`
-	DestPath     string `json:"dest_path"`
-	SizeBytes    int64  `json:"size_bytes"`
-	PlexNotified bool   `json:"plex_notified"`
+	FileID        int64  `json:"file_id"`
+	ContentID     int64  `json:"content_id"`
+	SourcePath    string `json:"source_path"`
+	DestPath      string `json:"dest_path"`
+	SizeBytes     int64  `json:"size_bytes"`
+	PlexNotified  bool   `json:"plex_notified"`
+	PlexScheduled bool   `json:"plex_scheduled"` // The scan waits to be sent with those of other imports
+	// Season packs
+	EpisodeCount   int                     `json:"episode_count,omitempty"`
+	Status         string                  `json:"status,omitempty"`
+	EpisodeResults []EpisodeResultResponse `json:"episode_results,omitempty"`
 }
 
 type PlexLibrary struct {
@@ -235,9 +352,38 @@ func (c *Client) Status() (*StatusResponse, error) {
 	return &resp, nil
 }
 
-func (c *Client) Dashboard() (*DashboardResponse, error) {
+// Health runs the server's health checks, with ready also its readiness
+// checks. An unhealthy server is not an error: check the response's Status.
+func (c *Client) Health(ready bool) (*HealthResponse, error) {
+	path := "/api/v1/health"
+	if ready {
+		path += "?ready=true"
+	}
+	resp, err := c.httpClient.Get(c.baseURL + path)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("server error %d: %s", resp.StatusCode, string(body))
+	}
+	var result HealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode health: %w", err)
+	}
+	return &result, nil
+}
+
+// Dashboard fetches the dashboard. skipPlex skips the Plex library check.
+func (c *Client) Dashboard(skipPlex bool) (*DashboardResponse, error) {
+	path := "/api/v1/dashboard"
+	if skipPlex {
+		path += "?skip_plex=true"
+	}
 	var resp DashboardResponse
-	if err := c.get("/api/v1/dashboard", &resp); err != nil {
+	if err := c.get(path, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -255,7 +401,8 @@ func (c *Client) Downloads(activeOnly bool) (*ListDownloadsResponse, error) {
 	return &resp, nil
 }
 
-func (c *Client) Search(query, contentType, profile string) (*SearchResponse, error) {
+// Search queries the indexers. With refresh, cached indexer results are bypassed.
+func (c *Client) Search(query, contentType, profile, protocol string, refresh bool) (*SearchResponse, error) {
 	params := url.Values{}
 	params.Set("query", query)
 	if contentType != "" {
@@ -264,6 +411,12 @@ func (c *Client) Search(query, contentType, profile string) (*SearchResponse, er
 	if profile != "" {
 		params.Set("profile", profile)
 	}
+	if protocol != "" {
+		params.Set("protocol", protocol)
+	}
+	if refresh {
+		params.Set("refresh", "true")
+	}
 
 	var resp SearchResponse
 	if err := c.get("/api/v1/search?"+params.Encode(), &resp); err != nil {
@@ -272,13 +425,16 @@ func (c *Client) Search(query, contentType, profile string) (*SearchResponse, er
 	return &resp, nil
 }
 
-func (c *Client) AddContent(contentType, title string, year int, profile string, tvdbID int64) (*ContentResponse, error) {
+func (c *Client) AddContent(contentType, title string, year int, profile string, tmdbID, tvdbID int64) (*ContentResponse, error) {
 	req := map[string]any{
 		"type":            contentType,
 		"title":           title,
 		"year":            year,
 		"quality_profile": profile,
 	}
+	if tmdbID > 0 {
+		req["tmdb_id"] = tmdbID
+	}
 	if tvdbID > 0 {
 		req["tvdb_id"] = tvdbID
 	}
@@ -320,13 +476,16 @@ func (c *Client) FindContentByTitle(contentType, title string) (*ContentResponse
 	return &resp.Items[0], nil
 }
 
-func (c *Client) Grab(contentID int64, downloadURL, title, indexer string) (*GrabResponse, error) {
+func (c *Client) Grab(contentID int64, downloadURL, title, indexer, guid string) (*GrabResponse, error) {
 	req := map[string]any{
 		"content_id":   contentID,
 		"download_url": downloadURL,
 		"title":        title,
 		"indexer":      indexer,
 	}
+	if guid != "" {
+		req["guid"] = guid
+	}
 
 	var resp GrabResponse
 	if err := c.post("/api/v1/grab", req, &resp); err != nil {
@@ -419,20 +578,43 @@ type VerifyProblem struct {
 
 type VerifyResponse struct {
 	Connections struct {
-		Plex    bool   `json:"plex"`
-		PlexErr string `json:"plex_error,omitempty"`
-		SABnzbd bool   `json:"sabnzbd"`
-		SABErr  string `json:"sabnzbd_error,omitempty"`
+		Plex    bool               `json:"plex"`
+		PlexErr string             `json:"plex_error,omitempty"`
+		Clients []ClientConnection `json:"clients"`
 	} `json:"connections"`
-	Checked  int             `json:"checked"`
-	Passed   int             `json:"passed"`
-	Problems []VerifyProblem `json:"problems"`
+	Checked      int             `json:"checked"`
+	Passed       int             `json:"passed"`
+	Problems     []VerifyProblem `json:"problems"`
+	DryRun       bool            `json:"dry_run"`
+	AppliedFixes []AppliedFix    `json:"applied_fixes"`
 }
 
-func (c *Client) Verify(id *int64) (*VerifyResponse, error) {
-	path := "/api/v1/verify"
+// AppliedFix is a state change made by verify in fix mode.
+type AppliedFix struct {
+	DownloadID int64  `json:"download_id"`
+	Action     string `json:"action"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+}
+
+// Verify checks downloads against live systems. With fix, the server applies
+// safe state corrections; reimport additionally allows re-importing downloads
+// whose library file is missing.
+func (c *Client) Verify(id *int64, fix, reimport bool) (*VerifyResponse, error) {
+	params := url.Values{}
 	if id != nil {
-		path += fmt.Sprintf("?id=%d", *id)
+		params.Set("id", strconv.FormatInt(*id, 10))
+	}
+	if fix {
+		params.Set("fix", "true")
+	}
+	if reimport {
+		params.Set("reimport", "true")
+	}
+	path := "/api/v1/verify"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
 	}
 	var resp VerifyResponse
 	if err := c.get(path, &resp); err != nil {
@@ -458,6 +640,7 @@ type EventResponse struct {
 	EntityType string `json:"entity_type"`
 	EntityID   int64  `json:"entity_id"`
 	OccurredAt string `json:"occurred_at"`
+	Title      string `json:"title,omitempty"` // Entity description; only with Enrich
 }
 
 type ListEventsResponse struct {
@@ -465,8 +648,50 @@ type ListEventsResponse struct {
 	Total int             `json:"total"`
 }
 
-func (c *Client) Events(limit int) (*ListEventsResponse, error) {
-	path := fmt.Sprintf("/api/v1/events?limit=%d", limit)
+// EventsOptions filters the event log. Zero fields are not sent.
+type EventsOptions struct {
+	Limit      int
+	Offset     int
+	EntityType string   // e.g. "content", "download"
+	EntityID   int64    // Requires EntityType
+	EventTypes []string // Matches any of these types
+	Since      time.Time
+	Until      time.Time
+	Enrich     bool // Ask the server to describe each event's entity (e.g. "The Matrix (1999)")
+}
+
+// Events lists events newest first, filtered by opts.
+func (c *Client) Events(opts EventsOptions) (*ListEventsResponse, error) {
+	params := url.Values{}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		params.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.EntityType != "" {
+		params.Set("entity_type", opts.EntityType)
+	}
+	if opts.EntityID > 0 {
+		params.Set("entity_id", strconv.FormatInt(opts.EntityID, 10))
+	}
+	for _, t := range opts.EventTypes {
+		params.Add("event_type", t)
+	}
+	if !opts.Since.IsZero() {
+		params.Set("since", opts.Since.Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		params.Set("until", opts.Until.Format(time.RFC3339))
+	}
+	if opts.Enrich {
+		params.Set("enrich", "true")
+	}
+
+	path := "/api/v1/events"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
 	var resp ListEventsResponse
 	if err := c.get(path, &resp); err != nil {
 		return nil, err
@@ -494,14 +719,39 @@ func (c *Client) DownloadEvents(id int64) (*ListEventsResponse, error) {
 
 // RetryResponse is the response from retrying a failed download.
 type RetryResponse struct {
-	NewDownloadID int64  `json:"new_download_id,omitempty"`
-	ReleaseName   string `json:"release_name"`
-	Message       string `json:"message"`
+	NewDownloadID int64       `json:"new_download_id,omitempty"`
+	ReleaseName   string      `json:"release_name"`
+	Message       string      `json:"message"`
+	PreviousError string      `json:"previous_error,omitempty"`
+	Strategy      string      `json:"strategy,omitempty"`
+	Grabs         []RetryGrab `json:"grabs,omitempty"`
+	Warnings      []string    `json:"warnings,omitempty"`
+}
+
+// RetryGrab is a grab queued by a retry.
+type RetryGrab struct {
+	ReleaseName string  `json:"release_name"`
+	Indexer     string  `json:"indexer"`
+	Score       int     `json:"score"`
+	Season      *int    `json:"season,omitempty"`
+	EpisodeIDs  []int64 `json:"episode_ids,omitempty"`
 }
 
-// RetryDownload re-searches indexers for the content and grabs the best matching release.
-func (c *Client) RetryDownload(id int64) (*RetryResponse, error) {
+// RetryDownload re-searches indexers for the content and grabs the best
+// matching release. strategy is "same", "episodes" or "auto" ("" for the
+// server default); minScore is the score a new season pack needs under auto.
+func (c *Client) RetryDownload(id int64, strategy string, minScore int) (*RetryResponse, error) {
 	path := fmt.Sprintf("/api/v1/downloads/%d/retry", id)
+	params := url.Values{}
+	if strategy != "" {
+		params.Set("strategy", strategy)
+	}
+	if minScore > 0 {
+		params.Set("min_score", strconv.Itoa(minScore))
+	}
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
 	var resp RetryResponse
 	if err := c.post(path, nil, &resp); err != nil {
 		return nil, err
@@ -509,14 +759,88 @@ func (c *Client) RetryDownload(id int64) (*RetryResponse, error) {
 	return &resp, nil
 }
 
+func (c *Client) ReimportDownload(id int64) (*ImportResponse, error) {
+	path := fmt.Sprintf("/api/v1/downloads/%d/reimport", id)
+	var resp ImportResponse
+	if err := c.post(path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ImportNext moves a completed download to the front of the import queue.
+func (c *Client) ImportNext(id int64) (*DownloadResponse, error) {
+	path := fmt.Sprintf("/api/v1/downloads/%d/import-next", id)
+	var resp DownloadResponse
+	if err := c.post(path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// OrphanResponse is an entry of the download root no active download refers to.
+type OrphanResponse struct {
+	Path       string `json:"path"`
+	SizeBytes  int64  `json:"size_bytes"`
+	ModifiedAt string `json:"modified_at"`
+}
+
+type ListOrphansResponse struct {
+	Items      []OrphanResponse `json:"items"`
+	TotalBytes int64            `json:"total_bytes"`
+	MinAgeDays int              `json:"min_age_days"`
+}
+
+type CleanOrphansResponse struct {
+	Removed    []string `json:"removed"`
+	Skipped    []string `json:"skipped"`
+	FreedBytes int64    `json:"freed_bytes"`
+}
+
+// Orphans lists the download root entries no active download refers to.
+func (c *Client) Orphans() (*ListOrphansResponse, error) {
+	var resp ListOrphansResponse
+	if err := c.get("/api/v1/downloads/orphans", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CleanOrphans removes the given orphans, or all of them if paths is empty.
+func (c *Client) CleanOrphans(paths []string) (*CleanOrphansResponse, error) {
+	body := map[string]any{}
+	if len(paths) > 0 {
+		body["paths"] = paths
+	}
+	var resp CleanOrphansResponse
+	if err := c.post("/api/v1/downloads/orphans/clean", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // Indexer types
 
 type IndexerResponse struct {
-	Name       string `json:"name"`
-	URL        string `json:"url"`
-	Status     string `json:"status,omitempty"`
-	Error      string `json:"error,omitempty"`
-	ResponseMs int64  `json:"response_ms,omitempty"`
+	Name       string               `json:"name"`
+	URL        string               `json:"url"`
+	Priority   int                  `json:"priority"`
+	Categories []string             `json:"categories"`
+	Status     string               `json:"status,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	ResponseMs int64                `json:"response_ms,omitempty"`
+	Keys       []IndexerKeyResponse `json:"keys"`
+}
+
+type IndexerKeyResponse struct {
+	Key         string `json:"key"`
+	Requests    int    `json:"requests"`
+	Grabs       int    `json:"grabs"`
+	Exhausted   bool   `json:"exhausted"`
+	APICurrent  int    `json:"api_current,omitempty"`
+	APIMax      int    `json:"api_max,omitempty"`
+	GrabCurrent int    `json:"grab_current,omitempty"`
+	GrabMax     int    `json:"grab_max,omitempty"`
 }
 
 type ListIndexersResponse struct {
@@ -538,12 +862,15 @@ func (c *Client) Indexers(test bool) (*ListIndexersResponse, error) {
 // Profile types
 
 type ProfileResponse struct {
+	ID     int64    `json:"id"`
 	Name   string   `json:"name"`
 	Accept []string `json:"accept"`
+	Cutoff string   `json:"cutoff,omitempty"`
 }
 
 type ListProfilesResponse struct {
 	Profiles []ProfileResponse `json:"profiles"`
+	ReadOnly bool              `json:"read_only"`
 }
 
 func (c *Client) Profiles() (*ListProfilesResponse, error) {
@@ -554,6 +881,50 @@ func (c *Client) Profiles() (*ListProfilesResponse, error) {
 	return &resp, nil
 }
 
+// ExclusionResponse is an excluded TMDB or TVDB ID.
+type ExclusionResponse struct {
+	ID         int64     `json:"id"`
+	Source     string    `json:"source"`
+	ExternalID int64     `json:"external_id"`
+	Title      string    `json:"title,omitempty"`
+	Year       int       `json:"year,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ListExclusionsResponse struct {
+	Items []ExclusionResponse `json:"items"`
+	Total int                 `json:"total"`
+}
+
+// AddExclusionRequest is the request body for POST /api/v1/exclusions.
+type AddExclusionRequest struct {
+	Source     string `json:"source"`
+	ExternalID int64  `json:"external_id"`
+	Title      string `json:"title,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+func (c *Client) Exclusions() (*ListExclusionsResponse, error) {
+	var resp ListExclusionsResponse
+	if err := c.get("/api/v1/exclusions", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) AddExclusion(req *AddExclusionRequest) (*ExclusionResponse, error) {
+	var resp ExclusionResponse
+	if err := c.post("/api/v1/exclusions", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) DeleteExclusion(id int64) error {
+	return c.delete(fmt.Sprintf("/api/v1/exclusions/%d", id))
+}
+
 func (c *Client) Files(contentID *int64) (*ListFilesResponse, error) {
 	path := "/api/v1/files"
 	if contentID != nil {
@@ -566,12 +937,86 @@ func (c *Client) Files(contentID *int64) (*ListFilesResponse, error) {
 	return &resp, nil
 }
 
+func (c *Client) Wanted(contentType, sort string, limit, offset int) (*WantedResponse, error) {
+	params := url.Values{}
+	if contentType != "" {
+		params.Set("type", contentType)
+	}
+	if sort != "" {
+		params.Set("sort", sort)
+	}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	params.Set("offset", fmt.Sprintf("%d", offset))
+
+	var resp WantedResponse
+	if err := c.get("/api/v1/wanted?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StorageGroup is the size of one group of library files.
+type StorageGroup struct {
+	Name         string `json:"name"`
+	FileCount    int    `json:"file_count"`
+	SizeBytes    int64  `json:"size_bytes"`
+	AverageBytes int64  `json:"average_size_bytes"`
+}
+
+// LargestContent is a content item in the library stats' largest listing.
+type LargestContent struct {
+	ID         int64  `json:"id"`
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Year       int    `json:"year"`
+	SizeOnDisk int64  `json:"size_on_disk"`
+}
+
+// LibraryStatsResponse is the response from library stats.
+type LibraryStatsResponse struct {
+	FileCount    int              `json:"file_count"`
+	SizeBytes    int64            `json:"size_bytes"`
+	AverageBytes int64            `json:"average_size_bytes"`
+	ByType       []StorageGroup   `json:"by_type"`
+	ByQuality    []StorageGroup   `json:"by_quality"`
+	Largest      []LargestContent `json:"largest"`
+}
+
+// LibraryStats returns library storage statistics with the largest items.
+func (c *Client) LibraryStats(largest int) (*LibraryStatsResponse, error) {
+	var resp LibraryStatsResponse
+	if err := c.get(fmt.Sprintf("/api/v1/library/stats?largest=%d", largest), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Export streams the library snapshot from GET /api/v1/export to w.
+func (c *Client) Export(w io.Writer) error {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/v1/export")
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server error %d: %s", resp.StatusCode, string(body))
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("read export: %w", err)
+	}
+	return nil
+}
+
 // LibraryImportRequest is the request for library import.
 type LibraryImportRequest struct {
-	Source          string `json:"source"`
-	Library         string `json:"library"`
-	QualityOverride string `json:"quality_override,omitempty"`
-	DryRun          bool   `json:"dry_run,omitempty"`
+	Source          string            `json:"source"`
+	Library         string            `json:"library,omitempty"`
+	Path            string            `json:"path,omitempty"`
+	QualityMap      map[string]string `json:"quality_map,omitempty"`
+	QualityOverride string            `json:"quality_override,omitempty"`
+	DryRun          bool              `json:"dry_run,omitempty"`
 }
 
 // LibraryImportItem represents a single imported/skipped/errored item.
@@ -581,23 +1026,27 @@ type LibraryImportItem struct {
 	Type      string `json:"type"`
 	Quality   string `json:"quality,omitempty"`
 	ContentID int64  `json:"content_id,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Episodes  int    `json:"episodes,omitempty"`
 	Reason    string `json:"reason,omitempty"`
 	Error     string `json:"error,omitempty"`
 }
 
 // LibraryImportResponse is the response from library import.
 type LibraryImportResponse struct {
-	Imported []LibraryImportItem `json:"imported"`
-	Skipped  []LibraryImportItem `json:"skipped"`
-	Errors   []LibraryImportItem `json:"errors"`
-	Summary  struct {
-		Imported int `json:"imported"`
-		Skipped  int `json:"skipped"`
-		Errors   int `json:"errors"`
+	Imported    []LibraryImportItem `json:"imported"`
+	Skipped     []LibraryImportItem `json:"skipped"`
+	Errors      []LibraryImportItem `json:"errors"`
+	NeedsReview []LibraryImportItem `json:"needs_review"`
+	Summary     struct {
+		Imported    int `json:"imported"`
+		Skipped     int `json:"skipped"`
+		Errors      int `json:"errors"`
+		NeedsReview int `json:"needs_review"`
 	} `json:"summary"`
 }
 
-// LibraryImport imports a Plex library.
+// LibraryImport imports a Plex library or a scanned directory.
 func (c *Client) LibraryImport(req *LibraryImportRequest) (*LibraryImportResponse, error) {
 	var resp LibraryImportResponse
 	if err := c.post("/api/v1/library/import", req, &resp); err != nil {
@@ -614,6 +1063,36 @@ type TVDBSearchResult struct {
 	Status string `json:"status"`
 }
 
+// LookupResult is a TMDB movie or TVDB series match.
+type LookupResult struct {
+	Type      string `json:"type"`
+	TMDBID    *int64 `json:"tmdb_id,omitempty"`
+	TVDBID    *int64 `json:"tvdb_id,omitempty"`
+	Title     string `json:"title"`
+	Year      int    `json:"year"`
+	Overview  string `json:"overview,omitempty"`
+	ContentID *int64 `json:"content_id,omitempty"` // Set if already in the library
+}
+
+// LookupResponse is the response from the lookup endpoint.
+type LookupResponse struct {
+	Results []LookupResult `json:"results"`
+}
+
+// Lookup finds movies (TMDB) or series (TVDB) by title or by a tmdb:ID / tvdb:ID query.
+func (c *Client) Lookup(contentType, query string) (*LookupResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if contentType != "" {
+		params.Set("type", contentType)
+	}
+	var resp LookupResponse
+	if err := c.get("/api/v1/lookup?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // TVDBSearch searches TVDB for series matching the query.
 func (c *Client) TVDBSearch(query string) ([]TVDBSearchResult, error) {
 	var results []TVDBSearchResult
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,13 +19,13 @@ func TestClient_Dashboard_Success(t *testing.T) {
 		RespondJSON(DashboardResponse{
 			Version: "1.2.3",
 			Connections: struct {
-				Server  bool `json:"server"`
-				Plex    bool `json:"plex"`
-				SABnzbd bool `json:"sabnzbd"`
+				Server  bool               `json:"server"`
+				Plex    bool               `json:"plex"`
+				Clients []ClientConnection `json:"clients"`
 			}{
 				Server:  true,
 				Plex:    true,
-				SABnzbd: true,
+				Clients: []ClientConnection{{Name: "sabnzbd", Protocol: "usenet", Connected: true}},
 			},
 			Downloads: struct {
 				Queued      int `json:"queued"`
@@ -32,6 +35,9 @@ func TestClient_Dashboard_Success(t *testing.T) {
 				Imported    int `json:"imported"`
 				Cleaned     int `json:"cleaned"`
 				Failed      int `json:"failed"`
+				// Season packs with episodes that failed to import
+				PartiallyImported int `json:"partially_imported"`
+				ImportQueued      int `json:"import_queued"`
 			}{
 				Queued:      2,
 				Downloading: 1,
@@ -42,25 +48,31 @@ func TestClient_Dashboard_Success(t *testing.T) {
 				Failed:      1,
 			},
 			Stuck: struct {
-				Count     int   `json:"count"`
-				Threshold int64 `json:"threshold_minutes"`
+				Count      int              `json:"count"`
+				Threshold  int64            `json:"threshold_minutes"`
+				ByStatus   map[string]int   `json:"by_status"`
+				Thresholds map[string]int64 `json:"thresholds_minutes"`
 			}{
-				Count:     1,
-				Threshold: 60,
+				Count:      1,
+				Threshold:  30,
+				ByStatus:   map[string]int{"completed": 1},
+				Thresholds: map[string]int64{"queued": 120, "downloading": 360, "completed": 30, "importing": 30},
 			},
 			Library: struct {
-				Movies int `json:"movies"`
-				Series int `json:"series"`
+				Movies    int   `json:"movies"`
+				Series    int   `json:"series"`
+				SizeBytes int64 `json:"size_bytes"`
 			}{
-				Movies: 150,
-				Series: 25,
+				Movies:    150,
+				Series:    25,
+				SizeBytes: 4 << 40,
 			},
 		}).
 		Build()
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.Dashboard()
+	resp, err := client.Dashboard(false)
 	require.NoError(t, err)
 
 	// Verify version
@@ -69,7 +81,9 @@ func TestClient_Dashboard_Success(t *testing.T) {
 	// Verify connections
 	assert.True(t, resp.Connections.Server)
 	assert.True(t, resp.Connections.Plex)
-	assert.True(t, resp.Connections.SABnzbd)
+	require.Len(t, resp.Connections.Clients, 1)
+	assert.Equal(t, "sabnzbd", resp.Connections.Clients[0].Name)
+	assert.True(t, resp.Connections.Clients[0].Connected)
 
 	// Verify downloads
 	assert.Equal(t, 2, resp.Downloads.Queued)
@@ -82,11 +96,39 @@ func TestClient_Dashboard_Success(t *testing.T) {
 
 	// Verify stuck
 	assert.Equal(t, 1, resp.Stuck.Count)
-	assert.Equal(t, int64(60), resp.Stuck.Threshold)
+	assert.Equal(t, int64(30), resp.Stuck.Threshold)
+	assert.Equal(t, map[string]int{"completed": 1}, resp.Stuck.ByStatus)
+	assert.Equal(t, int64(360), resp.Stuck.Thresholds["downloading"])
 
 	// Verify library
 	assert.Equal(t, 150, resp.Library.Movies)
 	assert.Equal(t, 25, resp.Library.Series)
+	assert.Equal(t, int64(4<<40), resp.Library.SizeBytes)
+}
+
+func TestClient_Dashboard_SkipPlex(t *testing.T) {
+	srv := newMockServer(t).
+		ExpectPath("/api/v1/dashboard").
+		ExpectGET().
+		Handler(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "true", r.URL.Query().Get("skip_plex"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"recent": [{"content_id": 1, "title": "Movie (2024)", "quality": "1080p"}],
+				"upcoming": [{"content_id": 2, "episode_id": 5, "series": "Show", "season": 1, "episode": 3}],
+				"problems": [{"check": "failed_downloads", "count": 2, "message": "2 failed downloads"}]}`))
+		}).
+		Build()
+	defer srv.Close()
+
+	resp, err := NewClient(srv.URL).Dashboard(true)
+	require.NoError(t, err)
+	require.Len(t, resp.Recent, 1)
+	assert.Equal(t, "Movie (2024)", resp.Recent[0].Title)
+	require.Len(t, resp.Upcoming, 1)
+	assert.Equal(t, 3, resp.Upcoming[0].Episode)
+	require.Len(t, resp.Problems, 1)
+	assert.Equal(t, 2, resp.Problems[0].Count)
+	assert.False(t, resp.PlexChecked)
 }
 
 func TestClient_Dashboard_ServerError(t *testing.T) {
@@ -98,7 +140,7 @@ func TestClient_Dashboard_ServerError(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	_, err := client.Dashboard()
+	_, err := client.Dashboard(false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "500")
 	assert.Contains(t, err.Error(), "database connection failed")
@@ -110,13 +152,12 @@ func TestClient_Verify_Success(t *testing.T) {
 		ExpectGET().
 		RespondJSON(VerifyResponse{
 			Connections: struct {
-				Plex    bool   `json:"plex"`
-				PlexErr string `json:"plex_error,omitempty"`
-				SABnzbd bool   `json:"sabnzbd"`
-				SABErr  string `json:"sabnzbd_error,omitempty"`
+				Plex    bool               `json:"plex"`
+				PlexErr string             `json:"plex_error,omitempty"`
+				Clients []ClientConnection `json:"clients"`
 			}{
 				Plex:    true,
-				SABnzbd: true,
+				Clients: []ClientConnection{{Name: "sabnzbd", Protocol: "usenet", Connected: true}},
 			},
 			Checked: 5,
 			Passed:  4,
@@ -137,14 +178,15 @@ func TestClient_Verify_Success(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.Verify(nil)
+	resp, err := client.Verify(nil, false, false)
 	require.NoError(t, err)
 
 	// Verify connections
 	assert.True(t, resp.Connections.Plex)
-	assert.True(t, resp.Connections.SABnzbd)
 	assert.Empty(t, resp.Connections.PlexErr)
-	assert.Empty(t, resp.Connections.SABErr)
+	require.Len(t, resp.Connections.Clients, 1)
+	assert.True(t, resp.Connections.Clients[0].Connected)
+	assert.Empty(t, resp.Connections.Clients[0].Error)
 
 	// Verify counts
 	assert.Equal(t, 5, resp.Checked)
@@ -181,7 +223,7 @@ func TestClient_Verify_WithID(t *testing.T) {
 
 	client := NewClient(srv.URL)
 	id := int64(123)
-	resp, err := client.Verify(&id)
+	resp, err := client.Verify(&id, false, false)
 	require.NoError(t, err)
 
 	// Verify the ID was sent as query parameter
@@ -193,6 +235,36 @@ func TestClient_Verify_WithID(t *testing.T) {
 	assert.Empty(t, resp.Problems)
 }
 
+func TestClient_Verify_Fix(t *testing.T) {
+	var receivedPath string
+
+	srv := newMockServer(t).
+		ExpectGET().
+		Handler(func(w http.ResponseWriter, r *http.Request) {
+			receivedPath = r.URL.String()
+			respondJSON(t, w, VerifyResponse{
+				Checked:  1,
+				Problems: []VerifyProblem{{DownloadID: 42, Status: "downloading"}},
+				AppliedFixes: []AppliedFix{
+					{DownloadID: 42, Action: "mark_completed", FromStatus: "downloading", ToStatus: "completed", Reason: "completed in sabnzbd"},
+				},
+			})
+		}).
+		Build()
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	id := int64(42)
+	resp, err := client.Verify(&id, true, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/api/v1/verify?fix=true&id=42&reimport=true", receivedPath)
+	assert.False(t, resp.DryRun)
+	require.Len(t, resp.AppliedFixes, 1)
+	assert.Equal(t, "mark_completed", resp.AppliedFixes[0].Action)
+	assert.Equal(t, "completed", resp.AppliedFixes[0].ToStatus)
+}
+
 func TestClient_PlexStatus_Success(t *testing.T) {
 	srv := newMockServer(t).
 		ExpectPath("/api/v1/plex/status").
@@ -517,6 +589,40 @@ func TestClient_Import_ManualPath(t *testing.T) {
 	assert.True(t, resp.PlexNotified)
 }
 
+func TestClient_Events_Filters(t *testing.T) {
+	var query url.Values
+
+	srv := newMockServer(t).
+		ExpectGET().
+		Handler(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query()
+			respondJSON(t, w, ListEventsResponse{})
+		}).
+		Build()
+	defer srv.Close()
+
+	since := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	client := NewClient(srv.URL)
+	_, err := client.Events(EventsOptions{
+		Limit:      10,
+		EntityType: "content",
+		EntityID:   42,
+		EventTypes: []string{"download.failed", "import.failed"},
+		Since:      since,
+		Enrich:     true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "10", query.Get("limit"))
+	assert.Equal(t, "content", query.Get("entity_type"))
+	assert.Equal(t, "42", query.Get("entity_id"))
+	assert.Equal(t, []string{"download.failed", "import.failed"}, query["event_type"])
+	assert.Equal(t, "2024-01-15T10:00:00Z", query.Get("since"))
+	assert.Equal(t, "true", query.Get("enrich"))
+	assert.Empty(t, query.Get("until"))
+	assert.Empty(t, query.Get("offset"))
+}
+
 func TestClient_Events_Success(t *testing.T) {
 	var receivedPath string
 
@@ -555,7 +661,7 @@ func TestClient_Events_Success(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.Events(50)
+	resp, err := client.Events(EventsOptions{Limit: 50})
 	require.NoError(t, err)
 
 	// Verify the limit was sent as query parameter
@@ -716,6 +822,10 @@ func TestClient_Indexers_Success(t *testing.T) {
 					{
 						Name: "nzbgeek",
 						URL:  "https://api.nzbgeek.info",
+						Keys: []IndexerKeyResponse{
+							{Key: "****abcd", Requests: 40, Exhausted: true},
+							{Key: "****wxyz", Requests: 3, Grabs: 1, GrabCurrent: 7, GrabMax: 25},
+						},
 					},
 					{
 						Name: "drunkenslug",
@@ -740,6 +850,10 @@ func TestClient_Indexers_Success(t *testing.T) {
 	assert.Equal(t, "https://api.nzbgeek.info", resp.Indexers[0].URL)
 	assert.Equal(t, "drunkenslug", resp.Indexers[1].Name)
 	assert.Equal(t, "https://api.drunkenslug.com", resp.Indexers[1].URL)
+	require.Len(t, resp.Indexers[0].Keys, 2)
+	assert.True(t, resp.Indexers[0].Keys[0].Exhausted)
+	assert.Equal(t, 25, resp.Indexers[0].Keys[1].GrabMax)
+	assert.Equal(t, "3 requests, 7/25 grabs", formatKeyUsage(resp.Indexers[0].Keys[1]))
 }
 
 func TestClient_Indexers_WithTest(t *testing.T) {
@@ -948,7 +1062,7 @@ func TestClient_RetryDownload_Success(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.RetryDownload(123)
+	resp, err := client.RetryDownload(123, "", 0)
 	require.NoError(t, err)
 
 	// Verify the download ID was included in the path
@@ -986,7 +1100,7 @@ func TestClient_AddContent_Success(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.AddContent("movie", "The Matrix", 1999, "hd", 0)
+	resp, err := client.AddContent("movie", "The Matrix", 1999, "hd", 603, 0)
 	require.NoError(t, err)
 
 	// Verify request body was sent correctly
@@ -994,6 +1108,7 @@ func TestClient_AddContent_Success(t *testing.T) {
 	assert.Equal(t, "The Matrix", receivedReq["title"])
 	assert.InDelta(t, 1999, receivedReq["year"], 0.001)
 	assert.Equal(t, "hd", receivedReq["quality_profile"])
+	assert.InDelta(t, 603, receivedReq["tmdb_id"], 0.001)
 	// tvdb_id should not be present when 0
 	assert.Nil(t, receivedReq["tvdb_id"])
 
@@ -1033,7 +1148,7 @@ func TestClient_AddContent_WithTVDBID(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.AddContent("series", "Breaking Bad", 2008, "hd", 81189)
+	resp, err := client.AddContent("series", "Breaking Bad", 2008, "hd", 0, 81189)
 	require.NoError(t, err)
 
 	// Verify request body was sent correctly
@@ -1138,7 +1253,7 @@ func TestClient_Grab_Success(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.Grab(42, "https://api.nzbgeek.info/api?t=get&id=abc123", "Test.Movie.2024.1080p.WEB-DL", "nzbgeek")
+	resp, err := client.Grab(42, "https://api.nzbgeek.info/api?t=get&id=abc123", "Test.Movie.2024.1080p.WEB-DL", "nzbgeek", "")
 	require.NoError(t, err)
 
 	// Verify request body was sent correctly
@@ -1151,3 +1266,120 @@ func TestClient_Grab_Success(t *testing.T) {
 	assert.Equal(t, int64(789), resp.DownloadID)
 	assert.Equal(t, "queued", resp.Status)
 }
+
+func TestClient_Wanted_Success(t *testing.T) {
+	var receivedQuery string
+	season, episode := 1, 2
+
+	srv := newMockServer(t).
+		ExpectPath("/api/v1/wanted").
+		ExpectGET().
+		Handler(func(w http.ResponseWriter, r *http.Request) {
+			receivedQuery = r.URL.RawQuery
+			respondJSON(t, w, WantedResponse{
+				Missing: WantedSectionResponse{
+					Items: []WantedItemResponse{{ContentID: 2, Type: "series", Title: "Show", Season: &season, Episode: &episode, Reason: "missing"}},
+					Total: 1,
+				},
+				CutoffUnmet: WantedSectionResponse{
+					Items: []WantedItemResponse{{ContentID: 1, Type: "movie", Title: "Movie", Year: 2020, CurrentQuality: "720p", WantedQuality: "1080p", Reason: "cutoff_unmet"}},
+					Total: 1,
+				},
+				Limit: 10,
+			})
+		}).
+		Build()
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	resp, err := client.Wanted("series", "air_date", 10, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "limit=10&offset=0&sort=air_date&type=series", receivedQuery)
+	require.Len(t, resp.Missing.Items, 1)
+	assert.Equal(t, "Show S01E02", wantedLabel(resp.Missing.Items[0]))
+	require.Len(t, resp.CutoffUnmet.Items, 1)
+	assert.Equal(t, "1080p", resp.CutoffUnmet.Items[0].WantedQuality)
+	assert.Equal(t, "Movie (2020)", wantedLabel(resp.CutoffUnmet.Items[0]))
+}
+
+func TestClient_Export(t *testing.T) {
+	snapshot := `{"version":1,"exported_at":"2026-01-01T00:00:00Z","content":[]}`
+	srv := newMockServer(t).
+		ExpectPath("/api/v1/export").
+		ExpectGET().
+		Handler(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(snapshot))
+		}).
+		Build()
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, NewClient(srv.URL).Export(&buf))
+	assert.Equal(t, snapshot, buf.String(), "the snapshot is copied as is")
+}
+
+func TestClient_Export_ServerError(t *testing.T) {
+	srv := newMockServer(t).RespondError(http.StatusInternalServerError, "database locked").Build()
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	err := NewClient(srv.URL).Export(&buf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+	assert.Empty(t, buf.String())
+}
+
+func TestClient_LibraryStats(t *testing.T) {
+	var receivedQuery string
+
+	srv := newMockServer(t).
+		ExpectPath("/api/v1/library/stats").
+		ExpectGET().
+		Handler(func(w http.ResponseWriter, r *http.Request) {
+			receivedQuery = r.URL.RawQuery
+			respondJSON(t, w, LibraryStatsResponse{
+				FileCount: 2,
+				SizeBytes: 3000,
+				ByType:    []StorageGroup{{Name: "movie", FileCount: 2, SizeBytes: 3000, AverageBytes: 1500}},
+				Largest:   []LargestContent{{ID: 1, Type: "movie", Title: "Dune", Year: 2021, SizeOnDisk: 2000}},
+			})
+		}).
+		Build()
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	resp, err := client.LibraryStats(5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "largest=5", receivedQuery)
+	assert.Equal(t, int64(3000), resp.SizeBytes)
+	require.Len(t, resp.ByType, 1)
+	assert.Equal(t, int64(1500), resp.ByType[0].AverageBytes)
+	require.Len(t, resp.Largest, 1)
+	assert.Equal(t, "Dune", resp.Largest[0].Title)
+}
+
+func TestClient_Lookup(t *testing.T) {
+	tmdbID := int64(603)
+	srv := newMockServer(t).
+		ExpectPath("/api/v1/lookup").
+		ExpectGET().
+		Handler(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "movie", r.URL.Query().Get("type"))
+			assert.Equal(t, "the matrix", r.URL.Query().Get("q"))
+			respondJSON(t, w, LookupResponse{Results: []LookupResult{
+				{Type: "movie", TMDBID: &tmdbID, Title: "The Matrix", Year: 1999},
+			}})
+		}).
+		Build()
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	resp, err := client.Lookup("movie", "the matrix")
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, "The Matrix", resp.Results[0].Title)
+	assert.Equal(t, int64(603), *resp.Results[0].TMDBID)
+}
@@ -148,7 +148,7 @@ func grabRelease(client *Client, rel ReleaseResponse, contentType, profile strin
 		fmt.Printf("Found in library (ID: %d)\n", content.ID)
 	} else {
 		// Create new content entry
-		content, err = client.AddContent(contentType, info.Title, info.Year, profile, tvdbID)
+		content, err = client.AddContent(contentType, info.Title, info.Year, profile, 0, tvdbID)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error adding content: %v\n", err)
 			return
@@ -157,7 +157,7 @@ func grabRelease(client *Client, rel ReleaseResponse, contentType, profile strin
 	}
 
 	// Grab the release
-	grab, err := client.Grab(content.ID, rel.DownloadURL, rel.Title, rel.Indexer)
+	grab, err := client.Grab(content.ID, rel.DownloadURL, rel.Title, rel.Indexer, rel.GUID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error grabbing: %v\n", err)
 		return
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -229,3 +230,47 @@ func TestBuildBadges(t *testing.T) {
 		})
 	}
 }
+
+func TestConfidentMatch(t *testing.T) {
+	id := func(n int64) *int64 { return &n }
+	matrix := LookupResult{TMDBID: id(603), Title: "The Matrix", Year: 1999}
+	reloaded := LookupResult{TMDBID: id(604), Title: "The Matrix Reloaded", Year: 2003}
+	dune1984 := LookupResult{TMDBID: id(841), Title: "Dune", Year: 1984}
+	dune2021 := LookupResult{TMDBID: id(438631), Title: "Dune", Year: 2021}
+
+	tests := []struct {
+		name       string
+		matches    []LookupResult
+		query      string
+		explicitID bool
+		yes        bool
+		want       *int64
+	}{
+		{"explicit id", []LookupResult{matrix}, "tmdb:603", true, false, matrix.TMDBID},
+		{"prompt without --yes", []LookupResult{matrix}, "the matrix", false, false, nil},
+		{"single match", []LookupResult{matrix}, "matrix", false, true, matrix.TMDBID},
+		{"exact title", []LookupResult{matrix, reloaded}, "the matrix", false, true, matrix.TMDBID},
+		{"no exact title", []LookupResult{matrix, reloaded}, "matrix", false, true, nil},
+		{"ambiguous exact title", []LookupResult{dune2021, dune1984}, "dune", false, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := confidentMatch(tt.matches, tt.query, tt.explicitID, tt.yes)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			if assert.NotNil(t, got) {
+				assert.Equal(t, *tt.want, *got.TMDBID)
+			}
+		})
+	}
+}
+
+func TestOverviewSnippet(t *testing.T) {
+	assert.Equal(t, "Short overview.", overviewSnippet("Short\n overview."))
+	long := strings.Repeat("word ", 30)
+	got := overviewSnippet(long)
+	assert.LessOrEqual(t, len([]rune(got)), overviewSnippetLen)
+	assert.True(t, strings.HasSuffix(got, "..."))
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
-	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/database"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/profile"
 )
 
 var configCmd = &cobra.Command{
@@ -14,20 +17,25 @@ var configCmd = &cobra.Command{
 	Short: "Configuration management",
 }
 
-var configTestCmd = &cobra.Command{
-	Use:   "test [path]",
-	Short: "Validate configuration file",
-	Long:  "Validates config.toml syntax, required fields, and environment variable substitution without starting the server.",
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runConfigTest,
+var configCheckCmd = &cobra.Command{
+	Use:     "check [path]",
+	Aliases: []string{"test"},
+	Short:   "Validate configuration file",
+	Long: `Validates config.toml syntax, required fields, environment variable substitution,
+library directories, and unknown keys without starting the server.
+
+If the database exists, quality profiles used by library content are checked too.
+Exits non-zero when there are errors; warnings are printed but do not fail the check.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigCheck,
 }
 
 func init() {
 	rootCmd.AddCommand(configCmd)
-	configCmd.AddCommand(configTestCmd)
+	configCmd.AddCommand(configCheckCmd)
 }
 
-func runConfigTest(cmd *cobra.Command, args []string) error {
+func runConfigCheck(cmd *cobra.Command, args []string) error {
 	path := "config.toml"
 	if len(args) > 0 {
 		path = args[0]
@@ -35,21 +43,47 @@ func runConfigTest(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Validating %s...\n\n", path)
 
-	cfg, err := config.Load(path)
+	cfg, configErr, err := config.Check(path)
 	if err != nil {
-		var configErr *config.Error
-		if errors.As(err, &configErr) {
-			printConfigErrors(configErr)
-			return fmt.Errorf("configuration invalid")
-		}
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	configErr.Warnings = append(configErr.Warnings, checkProfilesInUse(cfg)...)
+
+	printConfigErrors(configErr)
+	if configErr.HasErrors() {
+		return fmt.Errorf("configuration invalid")
+	}
 
 	printConfigSummary(cfg)
 	fmt.Println("\nConfiguration valid!")
 	return nil
 }
 
+// checkProfilesInUse checks content quality profiles against the stored
+// profiles, or the config's with quality.legacy_profiles or before the
+// database has profiles. Skipped when the database has not been created yet.
+func checkProfilesInUse(cfg *config.Config) []config.Issue {
+	if _, err := os.Stat(cfg.Database.Path); err != nil {
+		return nil
+	}
+	db, err := database.Open(cfg.Database.Path)
+	if err != nil {
+		return []config.Issue{{Key: "database.path", Severity: config.SeverityWarning, Message: fmt.Sprintf("cannot check quality profiles in use: %v", err)}}
+	}
+	defer func() { _ = db.Close() }()
+
+	counts, err := library.NewStore(db).QualityProfileCounts()
+	if err != nil {
+		return []config.Issue{{Key: "database.path", Severity: config.SeverityWarning, Message: fmt.Sprintf("cannot check quality profiles in use: %v", err)}}
+	}
+	if !cfg.Quality.LegacyProfiles {
+		if defined, err := profile.NewStore(db).Definitions(); err == nil && len(defined) > 0 {
+			return config.ValidateProfilesDefined(defined, counts)
+		}
+	}
+	return cfg.ValidateProfiles(counts)
+}
+
 func printConfigErrors(e *config.Error) {
 	if len(e.Missing) > 0 {
 		fmt.Println("Missing environment variables:")
@@ -61,8 +95,16 @@ func printConfigErrors(e *config.Error) {
 
 	if len(e.Errors) > 0 {
 		fmt.Println("Validation errors:")
-		for _, err := range e.Errors {
-			fmt.Printf("  - %s\n", err)
+		for _, issue := range e.Errors {
+			fmt.Printf("  - %s\n", issue)
+		}
+		fmt.Println()
+	}
+
+	if len(e.Warnings) > 0 {
+		fmt.Println("Warnings:")
+		for _, issue := range e.Warnings {
+			fmt.Printf("  - %s\n", issue)
 		}
 		fmt.Println()
 	}
@@ -10,7 +10,7 @@ import (
 )
 
 // Valid download states for --state flag validation
-var validStates = []string{"queued", "downloading", "completed", "importing", "imported", "cleaned", "failed"}
+var validStates = []string{"queued", "downloading", "completed", "importing", "imported", "partially_imported", "cleaned", "failed"}
 
 var downloadsCmd = &cobra.Command{
 	Use:   "downloads",
@@ -24,7 +24,12 @@ Examples:
   arrgo downloads show 42             # Show detailed info for download #42
   arrgo downloads cancel 42           # Cancel download #42
   arrgo downloads cancel 42 --delete  # Cancel and delete files
-  arrgo downloads retry 42            # Retry a failed download`,
+  arrgo downloads retry 42            # Retry a failed download
+  arrgo downloads retry 42 --strategy episodes  # Grab a failed pack by episode
+  arrgo downloads reimport 42         # Reimport failed episodes of a season pack
+  arrgo downloads import-next 42      # Import #42 before other completed downloads
+  arrgo downloads orphans             # List leftovers in the download folder
+  arrgo downloads orphans --clean     # Remove them`,
 	RunE: runDownloadsCmd,
 }
 
@@ -43,23 +48,60 @@ var downloadsShowCmd = &cobra.Command{
 	RunE:  runDownloadsShow,
 }
 
+var downloadsReimportCmd = &cobra.Command{
+	Use:   "reimport <id>",
+	Short: "Reimport the failed episodes of a season pack",
+	Long:  "Retries the episodes of a partially imported season pack that failed to import. Episodes already imported are skipped.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDownloadsReimport,
+}
+
+var downloadsImportNextCmd = &cobra.Command{
+	Use:   "import-next <id>",
+	Short: "Move a completed download to the front of the import queue",
+	Long:  "Completed downloads are imported a few at a time, in the order they completed. This makes the download the next one imported.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDownloadsImportNext,
+}
+
 var downloadsRetryCmd = &cobra.Command{
 	Use:   "retry <id>",
 	Short: "Retry a failed download",
-	Long:  "Re-searches indexers for the content and grabs the best matching release.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runDownloadsRetry,
+	Long: `Re-searches indexers for the content and grabs the best matching release.
+
+For a failed season pack, --strategy episodes grabs each missing episode of
+the season instead, and --strategy auto grabs another pack scoring at least
+--min-score, falling back to episodes.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDownloadsRetry,
+}
+
+var downloadsOrphansCmd = &cobra.Command{
+	Use:   "orphans [path...]",
+	Short: "List or remove leftovers in the download folder",
+	Long: `Lists the entries of the download folder that no active download refers to
+and that haven't changed for downloads.orphan_min_age_days (default 7).
+
+With --clean they are removed; given paths, only those. Entries that became
+active since they were listed are never removed.`,
+	RunE: runDownloadsOrphans,
 }
 
 func init() {
 	rootCmd.AddCommand(downloadsCmd)
 	downloadsCmd.Flags().BoolP("all", "a", false, "Include terminal states (cleaned, failed)")
-	downloadsCmd.Flags().StringP("state", "s", "", "Filter by state (queued, downloading, completed, importing, imported, cleaned, failed)")
+	downloadsCmd.Flags().StringP("state", "s", "", "Filter by state (queued, downloading, completed, importing, imported, partially_imported, cleaned, failed)")
 
 	downloadsCancelCmd.Flags().BoolP("delete", "d", false, "Also delete downloaded files")
 	downloadsCmd.AddCommand(downloadsCancelCmd)
 	downloadsCmd.AddCommand(downloadsShowCmd)
+	downloadsRetryCmd.Flags().String("strategy", "", "same, episodes or auto (default same)")
+	downloadsRetryCmd.Flags().Int("min-score", 0, "Score a new season pack needs with --strategy auto")
 	downloadsCmd.AddCommand(downloadsRetryCmd)
+	downloadsCmd.AddCommand(downloadsReimportCmd)
+	downloadsCmd.AddCommand(downloadsImportNextCmd)
+	downloadsOrphansCmd.Flags().Bool("clean", false, "Remove the orphans")
+	downloadsCmd.AddCommand(downloadsOrphansCmd)
 }
 
 func runDownloadsCancel(cmd *cobra.Command, args []string) error {
@@ -166,6 +208,8 @@ func printDownloadsActive(d *ListDownloadsResponse) {
 		eta := "-"
 		if dl.ETA != nil {
 			eta = *dl.ETA
+		} else if dl.ImportPosition > 0 {
+			eta = fmt.Sprintf("import #%d", dl.ImportPosition)
 		}
 		fmt.Printf("  %-4d %-12s %-46s %-8s %-10s %s\n", dl.ID, dl.Status, title, progress, speed, eta)
 	}
@@ -212,6 +256,9 @@ func printDownloadsAll(d *ListDownloadsResponse) {
 			}
 		}
 		fmt.Printf("  %-4d %-12s %-40s %-12s\n", dl.ID, dl.Status, title, completed)
+		if dl.LastError != "" {
+			fmt.Printf("       %s\n", dl.LastError)
+		}
 	}
 }
 
@@ -242,7 +289,17 @@ func runDownloadsShow(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %-12s %d\n", "Season:", *dl.Season)
 		}
 	}
-	fmt.Printf("  %-12s %s\n", "Status:", dl.Status)
+	if dl.ImportPosition > 0 {
+		fmt.Printf("  %-12s %s (#%d in import queue)\n", "Status:", dl.Status, dl.ImportPosition)
+	} else {
+		fmt.Printf("  %-12s %s\n", "Status:", dl.Status)
+	}
+	if dl.LastError != "" {
+		fmt.Printf("  %-12s %s\n", "Error:", dl.LastError)
+	}
+	if dl.ReleaseGroup != "" {
+		fmt.Printf("  %-12s %s\n", "Group:", dl.ReleaseGroup)
+	}
 	fmt.Printf("  %-12s %s\n", "Indexer:", dl.Indexer)
 	fmt.Printf("  %-12s %s (%s)\n", "Client:", dl.Client, dl.ClientID)
 	fmt.Printf("  %-12s %s\n", "Added:", dl.AddedAt)
@@ -250,6 +307,17 @@ func runDownloadsShow(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %-12s %s\n", "Completed:", *dl.CompletedAt)
 	}
 
+	if len(dl.EpisodeResults) > 0 {
+		fmt.Printf("\n  Episodes:\n")
+		for _, ep := range dl.EpisodeResults {
+			result := "imported"
+			if !ep.Success {
+				result = "failed: " + ep.Error
+			}
+			fmt.Printf("    S%02dE%02d  %-40s  %s\n", ep.Season, ep.Episode, truncatePath(ep.SourceFile, 40), result)
+		}
+	}
+
 	// Fetch and display events
 	events, err := client.DownloadEvents(id)
 	if err == nil && len(events.Items) > 0 {
@@ -269,12 +337,19 @@ func runDownloadsRetry(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid ID: %s", args[0])
 	}
 
+	var strategy string
+	var minScore int
+	if cmd != nil {
+		strategy, _ = cmd.Flags().GetString("strategy")
+		minScore, _ = cmd.Flags().GetInt("min-score")
+	}
+
 	client := NewClient(serverURL)
 
 	if !quietOutput {
 		fmt.Printf("Retrying download #%d...\n", id)
 	}
-	result, err := client.RetryDownload(id)
+	result, err := client.RetryDownload(id, strategy, minScore)
 	if err != nil {
 		return fmt.Errorf("retry failed: %w", err)
 	}
@@ -284,7 +359,124 @@ func runDownloadsRetry(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	fmt.Printf("Retry queued: %s\n", result.ReleaseName)
+	if result.PreviousError != "" {
+		fmt.Printf("Previous failure: %s\n", result.PreviousError)
+	}
+	if len(result.Grabs) > 1 {
+		fmt.Printf("Retry queued %d grabs (%s):\n", len(result.Grabs), result.Strategy)
+		for _, grab := range result.Grabs {
+			fmt.Printf("  %s\n", grab.ReleaseName)
+		}
+	} else {
+		fmt.Printf("Retry queued: %s\n", result.ReleaseName)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("  Skipped %s\n", warning)
+	}
 	fmt.Println("Use 'arrgo downloads' to monitor progress")
 	return nil
 }
+
+func runDownloadsReimport(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %s", args[0])
+	}
+
+	client := NewClient(serverURL)
+
+	if !quietOutput {
+		fmt.Printf("Reimporting download #%d...\n", id)
+	}
+	result, err := client.ReimportDownload(id)
+	if err != nil {
+		return fmt.Errorf("reimport failed: %w", err)
+	}
+
+	if jsonOutput {
+		printJSON(result)
+		return nil
+	}
+
+	imported, failed := 0, 0
+	for _, ep := range result.EpisodeResults {
+		switch {
+		case !ep.Success:
+			failed++
+			fmt.Printf("  S%02dE%02d  failed: %s (%s)\n", ep.Season, ep.Episode, ep.Error, ep.SourceFile)
+		case !ep.AlreadyImported:
+			imported++
+		}
+	}
+	fmt.Printf("Imported %d episodes, %d still failing (status: %s)\n", imported, failed, result.Status)
+	return nil
+}
+
+func runDownloadsImportNext(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %s", args[0])
+	}
+
+	client := NewClient(serverURL)
+	result, err := client.ImportNext(id)
+	if err != nil {
+		return fmt.Errorf("import-next failed: %w", err)
+	}
+
+	if jsonOutput {
+		printJSON(result)
+		return nil
+	}
+	fmt.Printf("Download #%d is next to import: %s\n", result.ID, result.ReleaseName)
+	return nil
+}
+
+func runDownloadsOrphans(cmd *cobra.Command, args []string) error {
+	clean, _ := cmd.Flags().GetBool("clean")
+	client := NewClient(serverURL)
+
+	if clean {
+		result, err := client.CleanOrphans(args)
+		if err != nil {
+			return fmt.Errorf("clean failed: %w", err)
+		}
+		if jsonOutput {
+			printJSON(result)
+			return nil
+		}
+		for _, path := range result.Removed {
+			fmt.Printf("Removed %s\n", path)
+		}
+		for _, path := range result.Skipped {
+			fmt.Printf("Skipped %s (not an orphan or removal failed)\n", path)
+		}
+		fmt.Printf("Freed %s\n", formatSize(result.FreedBytes))
+		return nil
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("paths are only accepted with --clean")
+	}
+
+	result, err := client.Orphans()
+	if err != nil {
+		return fmt.Errorf("fetch failed: %w", err)
+	}
+	if jsonOutput {
+		printJSON(result)
+		return nil
+	}
+	if len(result.Items) == 0 {
+		fmt.Printf("No orphans (unchanged for %d days and not part of an active download)\n", result.MinAgeDays)
+		return nil
+	}
+	for _, o := range result.Items {
+		modified := o.ModifiedAt
+		if t, err := time.Parse(time.RFC3339, o.ModifiedAt); err == nil {
+			modified = t.Local().Format("2006-01-02")
+		}
+		fmt.Printf("%10s  %s  %s\n", formatSize(o.SizeBytes), modified, o.Path)
+	}
+	fmt.Printf("\n%d orphans, %s. Remove with: arrgo downloads orphans --clean\n", len(result.Items), formatSize(result.TotalBytes))
+	return nil
+}
@@ -11,19 +11,53 @@ import (
 var eventsCmd = &cobra.Command{
 	Use:   "events",
 	Short: "Show recent events",
-	RunE:  runEventsCmd,
+	Example: `  arrgo events --content 42                   # Events for one movie or series
+  arrgo events --download 7 --since 24h       # A download's events from the last day
+  arrgo events --type download.failed --type import.failed
+  arrgo events --since 2024-01-15T00:00:00Z --until 2024-01-16T00:00:00Z`,
+	RunE: runEventsCmd,
 }
 
 func init() {
 	rootCmd.AddCommand(eventsCmd)
 	eventsCmd.Flags().IntP("limit", "l", 20, "Number of events to show")
+	eventsCmd.Flags().Int("offset", 0, "Number of events to skip")
+	eventsCmd.Flags().Int64("content", 0, "Only events for this content ID")
+	eventsCmd.Flags().Int64("download", 0, "Only events for this download ID")
+	eventsCmd.Flags().StringArrayP("type", "t", nil, "Only events of this type (repeatable)")
+	eventsCmd.Flags().String("since", "", "Only events since a time (RFC3339) or duration ago (e.g. 24h)")
+	eventsCmd.Flags().String("until", "", "Only events before a time (RFC3339) or duration ago")
 }
 
 func runEventsCmd(cmd *cobra.Command, args []string) error {
-	limit, _ := cmd.Flags().GetInt("limit")
+	opts := EventsOptions{}
+	opts.Limit, _ = cmd.Flags().GetInt("limit")
+	opts.Offset, _ = cmd.Flags().GetInt("offset")
+	opts.EventTypes, _ = cmd.Flags().GetStringArray("type")
+
+	contentID, _ := cmd.Flags().GetInt64("content")
+	downloadID, _ := cmd.Flags().GetInt64("download")
+	switch {
+	case contentID > 0 && downloadID > 0:
+		return fmt.Errorf("--content and --download cannot be combined")
+	case contentID > 0:
+		opts.EntityType, opts.EntityID = "content", contentID
+	case downloadID > 0:
+		opts.EntityType, opts.EntityID = "download", downloadID
+	}
+
+	var err error
+	since, _ := cmd.Flags().GetString("since")
+	if opts.Since, err = parseEventTime(since); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until, _ := cmd.Flags().GetString("until")
+	if opts.Until, err = parseEventTime(until); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
 
 	client := NewClient(serverURL)
-	events, err := client.Events(limit)
+	events, err := client.Events(opts)
 	if err != nil {
 		return fmt.Errorf("failed to fetch events: %w", err)
 	}
@@ -51,3 +85,19 @@ func runEventsCmd(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseEventTime accepts an RFC3339 time or a duration ago (e.g. "24h").
+// An empty string is the zero time.
+func parseEventTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC3339 time nor a duration", s)
+	}
+	return t, nil
+}
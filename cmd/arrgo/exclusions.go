@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var exclusionsCmd = &cobra.Command{
+	Use:   "exclusions",
+	Short: "Show and manage excluded titles",
+	Long: `Show and manage the exclusion list: TMDB (movie) and TVDB (series) IDs that are
+never added to the library, whether from Overseerr or arrgo add.
+
+Examples:
+  arrgo exclusions                                 # List exclusions
+  arrgo exclusions add tmdb:603 --reason "seen it" # Exclude a movie
+  arrgo exclusions remove 3                        # Allow it to be added again
+  arrgo library delete 42 --exclude                # Delete content and exclude it`,
+	RunE: runExclusionsCmd,
+}
+
+var exclusionsAddCmd = &cobra.Command{
+	Use:   "add <tmdb:ID|tvdb:ID>",
+	Short: "Exclude a movie (tmdb:ID) or series (tvdb:ID)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runExclusionsAdd,
+}
+
+var exclusionsRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove an exclusion",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runExclusionsRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(exclusionsCmd)
+	exclusionsAddCmd.Flags().String("reason", "", "Why the title is excluded")
+	exclusionsAddCmd.Flags().String("title", "", "Title to show in the list")
+	exclusionsCmd.AddCommand(exclusionsAddCmd)
+	exclusionsCmd.AddCommand(exclusionsRemoveCmd)
+}
+
+func runExclusionsCmd(cmd *cobra.Command, args []string) error {
+	client := NewClient(serverURL)
+	resp, err := client.Exclusions()
+	if err != nil {
+		return fmt.Errorf("failed to fetch exclusions: %w", err)
+	}
+
+	if jsonOutput {
+		printJSON(resp)
+		return nil
+	}
+
+	if len(resp.Items) == 0 {
+		fmt.Println("No exclusions.")
+		return nil
+	}
+
+	fmt.Printf("Exclusions (%d):\n\n", resp.Total)
+	fmt.Printf("  %-4s %-14s %-35s %-12s %s\n", "ID", "EXTERNAL ID", "TITLE", "ADDED", "REASON")
+	fmt.Println("  " + strings.Repeat("-", 80))
+	for _, e := range resp.Items {
+		title := e.Title
+		if e.Year > 0 {
+			title = fmt.Sprintf("%s (%d)", title, e.Year)
+		}
+		if len(title) > 35 {
+			title = title[:32] + "..."
+		}
+		fmt.Printf("  %-4d %-14s %-35s %-12s %s\n",
+			e.ID, fmt.Sprintf("%s:%d", e.Source, e.ExternalID), title, e.CreatedAt.Format("2006-01-02"), e.Reason)
+	}
+	return nil
+}
+
+func runExclusionsAdd(cmd *cobra.Command, args []string) error {
+	source, idStr, ok := strings.Cut(strings.ToLower(args[0]), ":")
+	if !ok || (source != "tmdb" && source != "tvdb") {
+		return fmt.Errorf("expected tmdb:ID or tvdb:ID, got %q", args[0])
+	}
+	externalID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || externalID <= 0 {
+		return fmt.Errorf("invalid ID: %s", idStr)
+	}
+	reason, _ := cmd.Flags().GetString("reason")
+	title, _ := cmd.Flags().GetString("title")
+
+	client := NewClient(serverURL)
+	e, err := client.AddExclusion(&AddExclusionRequest{Source: source, ExternalID: externalID, Title: title, Reason: reason})
+	if err != nil {
+		return fmt.Errorf("exclude failed: %w", err)
+	}
+	if !quietOutput {
+		fmt.Printf("Excluded %s:%d (exclusion %d)\n", e.Source, e.ExternalID, e.ID)
+	}
+	return nil
+}
+
+func runExclusionsRemove(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %s", args[0])
+	}
+
+	client := NewClient(serverURL)
+	if err := client.DeleteExclusion(id); err != nil {
+		return fmt.Errorf("remove failed: %w", err)
+	}
+	if !quietOutput {
+		fmt.Printf("Exclusion %d removed\n", id)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the library as a JSON snapshot",
+	Long: `Write the library (content, episodes, files and quality profile
+assignments) as a portable JSON snapshot, to stdout or the -o file.
+
+Restore it on another instance with POST /api/v1/import/snapshot.`,
+	Example: `  arrgo export > lib.json
+  arrgo export -o lib.json`,
+	RunE: runExportCmd,
+}
+
+func init() {
+	exportCmd.Flags().StringP("output", "o", "", "Write to this file instead of stdout")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExportCmd(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("create %s: %w", output, err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	client := NewClient(serverURL)
+	if err := client.Export(w); err != nil {
+		return fmt.Errorf("failed to export library: %w", err)
+	}
+	if output != "" {
+		fmt.Fprintf(os.Stderr, "Library exported to %s\n", output)
+	}
+	return nil
+}
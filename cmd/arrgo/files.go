@@ -14,11 +14,14 @@ type FileResponse struct {
 	ID        int64     `json:"id"`
 	ContentID int64     `json:"content_id"`
 	EpisodeID *int64    `json:"episode_id,omitempty"`
+	Season    *int      `json:"season,omitempty"`
+	Episode   *int      `json:"episode,omitempty"`
 	Path      string    `json:"path"`
 	SizeBytes int64     `json:"size_bytes"`
 	Quality   string    `json:"quality"`
 	Source    string    `json:"source"`
 	AddedAt   time.Time `json:"added_at"`
+	Missing   bool      `json:"missing"` // Gone from disk
 }
 
 // ListFilesResponse matches the API response for listing files.
@@ -90,7 +93,7 @@ func printFilesAll(f *ListFilesResponse) {
 			file.ContentID,
 			path,
 			formatSize(file.SizeBytes),
-			file.Quality)
+			fileQuality(file))
 	}
 }
 
@@ -106,10 +109,18 @@ func printFilesForContent(f *ListFilesResponse, contentID int64) {
 			file.ID,
 			path,
 			formatSize(file.SizeBytes),
-			file.Quality)
+			fileQuality(file))
 	}
 }
 
+// fileQuality is the quality column of a file, flagging files gone from disk.
+func fileQuality(file *FileResponse) string {
+	if file.Missing {
+		return file.Quality + " (missing)"
+	}
+	return file.Quality
+}
+
 // truncatePath shortens a path for display, keeping the end visible.
 // If the path is longer than maxLen, it will be shown as ".../<end>".
 func truncatePath(path string, maxLen int) string {
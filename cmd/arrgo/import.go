@@ -27,6 +27,7 @@ Examples:
   arrgo import 42
   arrgo import --manual "/downloads/Movie.Name.2024.1080p.WEB-DL.mkv"
   arrgo import --manual "/downloads/Show.S01E05.720p.HDTV.mkv" --dry-run
+  arrgo import 42 --allow-downgrade
   arrgo import list
   arrgo import list --pending`,
 	Args: cobra.MaximumNArgs(1),
@@ -43,6 +44,7 @@ func init() {
 	rootCmd.AddCommand(importCmd)
 	importCmd.Flags().String("manual", "", "Path to file for manual import")
 	importCmd.Flags().Bool("dry-run", false, "Preview import without making changes")
+	importCmd.Flags().Bool("allow-downgrade", false, "Import even if the library has a higher-quality file")
 
 	// Add list subcommand
 	importCmd.AddCommand(importListCmd)
@@ -53,10 +55,11 @@ func init() {
 func runImportCmd(cmd *cobra.Command, args []string) error {
 	manualPath, _ := cmd.Flags().GetString("manual")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	allowDowngrade, _ := cmd.Flags().GetBool("allow-downgrade")
 
 	// Determine mode
 	if manualPath != "" {
-		return runManualImport(manualPath, dryRun)
+		return runManualImport(manualPath, dryRun, allowDowngrade)
 	}
 
 	if len(args) == 0 {
@@ -68,10 +71,10 @@ func runImportCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid download ID: %s", args[0])
 	}
 
-	return runTrackedImport(downloadID, dryRun)
+	return runTrackedImport(downloadID, dryRun, allowDowngrade)
 }
 
-func runManualImport(path string, dryRun bool) error {
+func runManualImport(path string, dryRun, allowDowngrade bool) error {
 	// Extract filename for parsing
 	filename := filepath.Base(path)
 	info := release.Parse(filename)
@@ -99,6 +102,8 @@ func runManualImport(path string, dryRun bool) error {
 		Year:    info.Year,
 		Type:    contentType,
 		Quality: quality,
+
+		AllowDowngrade: allowDowngrade,
 	}
 	if info.Season > 0 {
 		req.Season = &info.Season
@@ -122,7 +127,7 @@ func runManualImport(path string, dryRun bool) error {
 	return nil
 }
 
-func runTrackedImport(downloadID int64, dryRun bool) error {
+func runTrackedImport(downloadID int64, dryRun, allowDowngrade bool) error {
 	if dryRun {
 		fmt.Println("Dry-run mode for tracked imports requires the server.")
 		fmt.Println("Use --dry-run with --manual to preview release parsing locally.")
@@ -130,7 +135,8 @@ func runTrackedImport(downloadID int64, dryRun bool) error {
 	}
 
 	req := &ImportRequest{
-		DownloadID: &downloadID,
+		DownloadID:     &downloadID,
+		AllowDowngrade: allowDowngrade,
 	}
 
 	client := NewClient(serverURL)
@@ -204,9 +210,12 @@ func printImportResult(resp *ImportResponse) {
 	fmt.Printf("  Dest:       %s\n", resp.DestPath)
 	fmt.Printf("  Size:       %s\n", formatSize(resp.SizeBytes))
 
-	if resp.PlexNotified {
+	switch {
+	case resp.PlexScheduled:
+		fmt.Println("  Plex:       scan scheduled")
+	case resp.PlexNotified:
 		fmt.Println("  Plex:       notified")
-	} else {
+	default:
 		fmt.Println("  Plex:       not notified")
 	}
 }
@@ -51,12 +51,40 @@ func runIndexersCmd(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %-15s %-8s %s\n", idx.Name, status, detail)
 		}
 	} else {
-		fmt.Printf("  %-15s %s\n", "NAME", "URL")
+		fmt.Printf("  %-15s %-8s %-13s %s\n", "NAME", "PRIORITY", "CATEGORIES", "URL")
 		fmt.Println("  " + strings.Repeat("-", 60))
 		for _, idx := range resp.Indexers {
-			fmt.Printf("  %-15s %s\n", idx.Name, idx.URL)
+			categories := "all"
+			if len(idx.Categories) > 0 {
+				categories = strings.Join(idx.Categories, ",")
+			}
+			fmt.Printf("  %-15s %-8d %-13s %s\n", idx.Name, idx.Priority, categories, idx.URL)
+			// Key usage is only worth a line each when keys rotate or one ran out
+			if len(idx.Keys) > 1 || (len(idx.Keys) == 1 && idx.Keys[0].Exhausted) {
+				for _, k := range idx.Keys {
+					fmt.Printf("    key %s  %s\n", k.Key, formatKeyUsage(k))
+				}
+			}
 		}
 	}
 
 	return nil
 }
+
+// formatKeyUsage describes an API key's usage today, with the indexer's
+// reported limits when it sends them.
+func formatKeyUsage(k IndexerKeyResponse) string {
+	requests := fmt.Sprintf("%d requests", k.Requests)
+	if k.APIMax > 0 {
+		requests = fmt.Sprintf("%d/%d api", k.APICurrent, k.APIMax)
+	}
+	grabs := fmt.Sprintf("%d grabs", k.Grabs)
+	if k.GrabMax > 0 {
+		grabs = fmt.Sprintf("%d/%d grabs", k.GrabCurrent, k.GrabMax)
+	}
+	s := requests + ", " + grabs
+	if k.Exhausted {
+		s += "  (limit reached)"
+	}
+	return s
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -49,6 +50,7 @@ type LibraryContentResponse struct {
 	RootPath       string               `json:"root_path"`
 	AddedAt        time.Time            `json:"added_at"`
 	UpdatedAt      time.Time            `json:"updated_at"`
+	SizeOnDisk     int64                `json:"size_on_disk"`
 	EpisodeStats   *LibraryEpisodeStats `json:"episode_stats,omitempty"`
 }
 
@@ -61,6 +63,9 @@ type EpisodeResponse struct {
 	Title     string     `json:"title"`
 	Status    string     `json:"status"`
 	AirDate   *time.Time `json:"air_date,omitempty"`
+
+	QualityProfile   string `json:"quality_profile"`
+	ProfileInherited bool   `json:"quality_profile_inherited"`
 }
 
 // ListEpisodesResponse matches the API response for listing episodes.
@@ -99,6 +104,23 @@ type LibraryCheckResponse struct {
 	Total      int                `json:"total"`
 	Healthy    int                `json:"healthy"`
 	WithIssues int                `json:"with_issues"`
+	PlexError  string             `json:"plex_error,omitempty"`
+}
+
+// LibraryCheckJobResponse matches the API response for a background library check.
+type LibraryCheckJobResponse struct {
+	ID         int64              `json:"id"`
+	Status     string             `json:"status"`
+	Filter     string             `json:"filter,omitempty"`
+	Total      int                `json:"total"`
+	Checked    int                `json:"checked"`
+	Healthy    int                `json:"healthy"`
+	WithIssues int                `json:"with_issues"`
+	Error      string             `json:"error,omitempty"`
+	PlexError  string             `json:"plex_error,omitempty"`
+	StartedAt  string             `json:"started_at"`
+	FinishedAt string             `json:"finished_at,omitempty"`
+	Items      []LibraryCheckItem `json:"items"`
 }
 
 func init() {
@@ -129,23 +151,33 @@ func init() {
 	checkCmd := &cobra.Command{
 		Use:   "check",
 		Short: "Verify files exist and Plex awareness",
-		Long:  "Checks each item in the library to verify files exist at expected paths and are present in Plex.",
-		RunE:  runLibraryCheck,
+		Long: `Checks items in the library to verify files exist at expected paths and are present in Plex.
+Without --all, up to --limit items (at most 200) are checked right away. With --all, the whole
+library is checked in the background on the server and progress is shown until it finishes;
+--last shows the results of the most recent background check again.`,
+		RunE: runLibraryCheck,
 	}
 
 	checkCmd.Flags().StringP("type", "t", "", "Filter by type (movie, series)")
 	checkCmd.Flags().StringP("status", "s", "", "Filter by status (wanted, available, missing)")
-	checkCmd.Flags().IntP("limit", "l", 100, "Maximum number of items to check")
+	checkCmd.Flags().IntP("limit", "l", 50, "Maximum number of items to check")
 	checkCmd.Flags().Bool("issues-only", false, "Only show items with issues")
+	checkCmd.Flags().Bool("all", false, "Check the whole library in the background and wait for it")
+	checkCmd.Flags().Bool("last", false, "Show the results of the most recent background check")
 
 	deleteCmd := &cobra.Command{
 		Use:   "delete <id>",
 		Short: "Delete content from library",
-		Long:  "Removes content and associated file records from the library. Does not delete actual files on disk.",
+		Long:  "Removes content and associated file records from the library. Files on disk are kept unless --delete-files is set.",
 		Args:  cobra.ExactArgs(1),
 		RunE:  runLibraryDelete,
 	}
 
+	deleteCmd.Flags().Bool("delete-files", false, "Also delete the content's files from disk")
+	deleteCmd.Flags().Bool("cancel-downloads", false, "Cancel active downloads for the content")
+	deleteCmd.Flags().Bool("exclude", false, "Also exclude the content's TMDB/TVDB ID so it is never added again")
+	deleteCmd.Flags().String("reason", "", "Why the content is excluded (with --exclude)")
+
 	addCmd := &cobra.Command{
 		Use:   "add",
 		Short: "Add content to library",
@@ -167,20 +199,46 @@ func init() {
 	importCmd := &cobra.Command{
 		Use:   "import",
 		Short: "Import existing media library",
-		Long:  "Import untracked items from Plex into arrgo for tracking.",
-		RunE:  runLibraryImport,
+		Long: `Import untracked items from Plex, a folder of movies and series, or a Radarr or
+Sonarr database into arrgo for tracking.
+
+With --from-dir, each top-level folder is identified from its name and video files.
+Items that can't be identified reliably are listed for review instead of being imported.
+
+With --from-radarr or --from-sonarr, movies or series are read from the database file
+(radarr.db or sonarr.db, on the server) with their episodes, files and monitoring.
+Their quality profiles map to arrgo profiles with --map-profile, by ID or name:
+
+  arrgo library import --from-sonarr /config/sonarr.db --map-profile HD-1080p=hd --map-profile 5=uhd
+
+Unmapped profiles are inferred from the resolution of the files.`,
+		RunE: runLibraryImport,
 	}
 
-	importCmd.Flags().String("from-plex", "", "Import from Plex library by name (required)")
+	importCmd.Flags().String("from-plex", "", "Import from Plex library by name")
+	importCmd.Flags().String("from-dir", "", "Import by scanning a directory on the server")
+	importCmd.Flags().String("from-radarr", "", "Import from a Radarr database file on the server")
+	importCmd.Flags().String("from-sonarr", "", "Import from a Sonarr database file on the server")
+	importCmd.Flags().StringToString("map-profile", nil, "Map a Radarr/Sonarr quality profile ID or name to a profile (repeatable, e.g. HD-1080p=hd)")
 	importCmd.Flags().String("quality", "", "Override quality profile for all imports")
 	importCmd.Flags().Bool("dry-run", false, "Preview import without making changes")
 
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show library storage usage",
+		Long:  "Shows the size of the library's files by content type and quality, and the items using the most space.",
+		RunE:  runLibraryStats,
+	}
+
+	statsCmd.Flags().IntP("largest", "n", 10, "Number of largest items to list")
+
 	libraryCmd.AddCommand(listCmd)
 	libraryCmd.AddCommand(showCmd)
 	libraryCmd.AddCommand(checkCmd)
 	libraryCmd.AddCommand(deleteCmd)
 	libraryCmd.AddCommand(addCmd)
 	libraryCmd.AddCommand(importCmd)
+	libraryCmd.AddCommand(statsCmd)
 	rootCmd.AddCommand(libraryCmd)
 }
 
@@ -324,6 +382,9 @@ func printLibraryShow(content *LibraryContentResponse, episodes *ListEpisodesRes
 		fmt.Printf("  TVDB ID:  %d\n", *content.TVDBID)
 	}
 
+	if content.SizeOnDisk > 0 {
+		fmt.Printf("  Size:     %s\n", formatSize(content.SizeOnDisk))
+	}
 	fmt.Printf("  Added:    %s\n", content.AddedAt.Format("2006-01-02 15:04"))
 
 	// For series, show episode breakdown
@@ -382,7 +443,11 @@ func printLibraryShow(content *LibraryContentResponse, episodes *ListEpisodesRes
 				if len(title) > 40 {
 					title = title[:37] + "..."
 				}
-				fmt.Printf("      %-4d %-40s %s\n", ep.Episode, title, ep.Status)
+				status := ep.Status
+				if !ep.ProfileInherited && ep.QualityProfile != "" {
+					status += " (profile: " + ep.QualityProfile + ")"
+				}
+				fmt.Printf("      %-4d %-40s %s\n", ep.Episode, title, status)
 			}
 		}
 	} else if content.Type == contentTypeSeries {
@@ -470,6 +535,8 @@ func runLibraryCheck(cmd *cobra.Command, args []string) error {
 	statusFilter, _ := cmd.Flags().GetString("status")
 	limit, _ := cmd.Flags().GetInt("limit")
 	issuesOnly, _ := cmd.Flags().GetBool("issues-only")
+	all, _ := cmd.Flags().GetBool("all")
+	last, _ := cmd.Flags().GetBool("last")
 
 	// Build query params
 	params := url.Values{}
@@ -479,6 +546,14 @@ func runLibraryCheck(cmd *cobra.Command, args []string) error {
 	if statusFilter != "" {
 		params.Set("status", statusFilter)
 	}
+
+	if last {
+		return showLibraryCheckJob(fmt.Sprintf("%s/api/v1/library/check/latest", serverURL), issuesOnly)
+	}
+	if all {
+		return runLibraryCheckAll(params, issuesOnly)
+	}
+
 	params.Set("limit", fmt.Sprintf("%d", limit))
 
 	urlStr := fmt.Sprintf("%s/api/v1/library/check", serverURL)
@@ -515,8 +590,107 @@ func runLibraryCheck(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runLibraryCheckAll starts a background check of the whole library and
+// shows its progress until it finishes, then its results.
+func runLibraryCheckAll(params url.Values, issuesOnly bool) error {
+	urlStr := fmt.Sprintf("%s/api/v1/library/check", serverURL)
+	if len(params) > 0 {
+		urlStr += "?" + params.Encode()
+	}
+	resp, err := http.Post(urlStr, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict {
+		return fmt.Errorf("a library check is already running; see 'arrgo library check --last'")
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+	var job LibraryCheckJobResponse
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	jobURL := fmt.Sprintf("%s/api/v1/library/check/%d", serverURL, job.ID)
+	for job.Status == "running" {
+		fmt.Printf("\rChecking library: %d/%d", job.Checked, job.Total)
+		time.Sleep(2 * time.Second)
+		// Items are fetched once the check is done
+		next, err := getLibraryCheckJob(jobURL + "?limit=1")
+		if err != nil {
+			fmt.Println()
+			return err
+		}
+		job = *next
+	}
+	fmt.Printf("\rChecking library: %d/%d\n\n", job.Checked, job.Total)
+
+	return showLibraryCheckJob(jobURL, issuesOnly)
+}
+
+// showLibraryCheckJob fetches a background library check and prints it.
+func showLibraryCheckJob(urlStr string, issuesOnly bool) error {
+	if issuesOnly {
+		urlStr += "?issues_only=true"
+	}
+	job, err := getLibraryCheckJob(urlStr)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Check #%d %s, started %s", job.ID, job.Status, job.StartedAt)
+	if job.Filter != "" {
+		fmt.Printf(" (%s)", job.Filter)
+	}
+	fmt.Println()
+	if job.Error != "" {
+		fmt.Printf("Error: %s\n", job.Error)
+	}
+	if job.Status != "completed" {
+		fmt.Printf("Checked %d of %d items.\n", job.Checked, job.Total)
+	}
+	fmt.Println()
+
+	if job.Checked == 0 {
+		fmt.Println("No content checked.")
+		return nil
+	}
+	printLibraryCheck(&LibraryCheckResponse{
+		Items:      job.Items,
+		Total:      job.Checked,
+		Healthy:    job.Healthy,
+		WithIssues: job.WithIssues,
+		PlexError:  job.PlexError,
+	}, issuesOnly)
+	return nil
+}
+
+func getLibraryCheckJob(urlStr string) (*LibraryCheckJobResponse, error) {
+	resp, err := http.Get(urlStr)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("no library check found; run 'arrgo library check --all'")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+	var job LibraryCheckJobResponse
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &job, nil
+}
+
 func printLibraryCheck(data *LibraryCheckResponse, issuesOnly bool) {
 	fmt.Printf("Library Check (%d items, %d healthy, %d with issues):\n\n", data.Total, data.Healthy, data.WithIssues)
+	if data.PlexError != "" {
+		fmt.Printf("Plex not checked: %s\n\n", data.PlexError)
+	}
 
 	for i := range data.Items {
 		item := &data.Items[i]
@@ -599,6 +773,26 @@ func runLibraryDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	// Now delete
+	deleteFiles, _ := cmd.Flags().GetBool("delete-files")
+	cancelDownloads, _ := cmd.Flags().GetBool("cancel-downloads")
+	params := url.Values{}
+	if deleteFiles {
+		params.Set("delete_files", "true")
+	}
+	if cancelDownloads {
+		params.Set("cancel_downloads", "true")
+	}
+	exclude, _ := cmd.Flags().GetBool("exclude")
+	if exclude {
+		params.Set("add_exclusion", "true")
+		if reason, _ := cmd.Flags().GetString("reason"); reason != "" {
+			params.Set("exclusion_reason", reason)
+		}
+	}
+	if len(params) > 0 {
+		urlStr += "?" + params.Encode()
+	}
+
 	req, err = http.NewRequest(http.MethodDelete, urlStr, nil)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
@@ -611,10 +805,19 @@ func runLibraryDelete(cmd *cobra.Command, args []string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil && errResp.Error != "" {
+			return fmt.Errorf("delete failed: %s", errResp.Error)
+		}
 		return fmt.Errorf("delete failed: server returned %d", resp.StatusCode)
 	}
 
 	fmt.Printf("Deleted: %s (%d)\n", content.Title, content.Year)
+	if exclude {
+		fmt.Println("Excluded from being added again (see 'arrgo exclusions')")
+	}
 	return nil
 }
 
@@ -700,22 +903,110 @@ func runLibraryAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runLibraryStats(cmd *cobra.Command, args []string) error {
+	largest, _ := cmd.Flags().GetInt("largest")
+
+	client := NewClient(serverURL)
+	stats, err := client.LibraryStats(largest)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(stats)
+		return nil
+	}
+
+	printLibraryStats(stats)
+	return nil
+}
+
+func printLibraryStats(s *LibraryStatsResponse) {
+	fmt.Printf("Library: %s in %d files", formatSize(s.SizeBytes), s.FileCount)
+	if s.FileCount > 0 {
+		fmt.Printf(" (average %s)", formatSize(s.AverageBytes))
+	}
+	fmt.Println()
+
+	printStorageGroups("By type", s.ByType)
+	printStorageGroups("By quality", s.ByQuality)
+
+	if len(s.Largest) > 0 {
+		fmt.Println("\nLargest:")
+		for _, c := range s.Largest {
+			fmt.Printf("  %10s  %s (%d) [%s, id %d]\n", formatSize(c.SizeOnDisk), c.Title, c.Year, c.Type, c.ID)
+		}
+	}
+}
+
+func printStorageGroups(heading string, groups []StorageGroup) {
+	if len(groups) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", heading)
+	for _, g := range groups {
+		fmt.Printf("  %-10s %10s  %5d files  avg %s\n", g.Name, formatSize(g.SizeBytes), g.FileCount, formatSize(g.AverageBytes))
+	}
+}
+
 func runLibraryImport(cmd *cobra.Command, args []string) error {
 	plexLibrary, _ := cmd.Flags().GetString("from-plex")
+	dir, _ := cmd.Flags().GetString("from-dir")
+	radarrDB, _ := cmd.Flags().GetString("from-radarr")
+	sonarrDB, _ := cmd.Flags().GetString("from-sonarr")
+	profileMap, _ := cmd.Flags().GetStringToString("map-profile")
 	quality, _ := cmd.Flags().GetString("quality")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 
-	if plexLibrary == "" {
-		return fmt.Errorf("--from-plex is required")
+	sources := 0
+	for _, s := range []string{plexLibrary, dir, radarrDB, sonarrDB} {
+		if s != "" {
+			sources++
+		}
+	}
+	if len(profileMap) > 0 && radarrDB == "" && sonarrDB == "" {
+		return fmt.Errorf("--map-profile requires --from-radarr or --from-sonarr")
 	}
 
-	client := NewClient(serverURL)
-	resp, err := client.LibraryImport(&LibraryImportRequest{
-		Source:          "plex",
-		Library:         plexLibrary,
+	req := &LibraryImportRequest{
+		QualityMap:      profileMap,
 		QualityOverride: quality,
 		DryRun:          dryRun,
-	})
+	}
+	var source string
+	switch {
+	case sources > 1:
+		return fmt.Errorf("use only one of --from-plex, --from-dir, --from-radarr or --from-sonarr")
+	case plexLibrary != "":
+		req.Source = "plex"
+		req.Library = plexLibrary
+		source = fmt.Sprintf("Plex library %q", plexLibrary)
+	case dir != "":
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("invalid directory: %w", err)
+		}
+		req.Source = "filesystem"
+		req.Path = abs
+		source = abs
+	case radarrDB != "" || sonarrDB != "":
+		req.Source, source = "radarr", "Radarr database"
+		path := radarrDB
+		if sonarrDB != "" {
+			req.Source, source, path = "sonarr", "Sonarr database", sonarrDB
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("invalid database path: %w", err)
+		}
+		req.Path = abs
+		source += " " + abs
+	default:
+		return fmt.Errorf("--from-plex, --from-dir, --from-radarr or --from-sonarr is required")
+	}
+
+	client := NewClient(serverURL)
+	resp, err := client.LibraryImport(req)
 	if err != nil {
 		return err
 	}
@@ -725,16 +1016,16 @@ func runLibraryImport(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	printLibraryImport(resp, plexLibrary, dryRun)
+	printLibraryImport(resp, source, dryRun)
 	return nil
 }
 
-func printLibraryImport(r *LibraryImportResponse, library string, dryRun bool) {
+func printLibraryImport(r *LibraryImportResponse, source string, dryRun bool) {
 	action := "Importing"
 	if dryRun {
 		action = "Would import"
 	}
-	fmt.Printf("%s from Plex library %q...\n\n", action, library)
+	fmt.Printf("%s from %s...\n\n", action, source)
 
 	for _, item := range r.Imported {
 		quality := item.Quality
@@ -749,13 +1040,19 @@ func printLibraryImport(r *LibraryImportResponse, library string, dryRun bool) {
 	for _, item := range r.Errors {
 		fmt.Printf("  ! %s (%d) - %s\n", item.Title, item.Year, item.Error)
 	}
+	if len(r.NeedsReview) > 0 {
+		fmt.Println("\nNeeds review:")
+		for _, item := range r.NeedsReview {
+			fmt.Printf("  ? %s - %s\n", item.Path, item.Reason)
+		}
+	}
 
 	fmt.Println()
 	if dryRun {
-		fmt.Printf("Would import: %d new, %d skipped, %d errors\n",
-			r.Summary.Imported, r.Summary.Skipped, r.Summary.Errors)
+		fmt.Printf("Would import: %d new, %d skipped, %d errors, %d need review\n",
+			r.Summary.Imported, r.Summary.Skipped, r.Summary.Errors, r.Summary.NeedsReview)
 	} else {
-		fmt.Printf("Imported: %d new, %d skipped, %d errors\n",
-			r.Summary.Imported, r.Summary.Skipped, r.Summary.Errors)
+		fmt.Printf("Imported: %d new, %d skipped, %d errors, %d need review\n",
+			r.Summary.Imported, r.Summary.Skipped, r.Summary.Errors, r.Summary.NeedsReview)
 	}
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/database"
+	"github.com/vmunix/arrgo/internal/migrations"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending database migrations (local, no server needed)",
+	Long: `Applies pending schema migrations to the database named in the config.
+
+arrgod applies them at startup unless database.auto_migrate is false.
+Stop arrgod before migrating by hand.`,
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the schema version and pending migrations",
+	Args:  cobra.NoArgs,
+	RunE:  runMigrateStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+	migrateCmd.PersistentFlags().String("config", "config.toml", "Path to config file")
+}
+
+// migrationJSON is the JSON form of a migration.
+type migrationJSON struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+// migrateStatusJSON is the JSON output of migrate status.
+type migrateStatusJSON struct {
+	Version int             `json:"version"`
+	Latest  int             `json:"latest"`
+	Pending []migrationJSON `json:"pending"`
+}
+
+func runMigrate(cmd *cobra.Command, _ []string) error {
+	db, err := openMigrateDB(cmd, true)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	applied, err := migrations.Up(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if !jsonOutput && !quietOutput {
+		for _, m := range applied {
+			fmt.Printf("Applied %03d_%s\n", m.Version, m.Name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		out := make([]migrationJSON, 0, len(applied))
+		for _, m := range applied {
+			out = append(out, migrationJSON{Version: m.Version, Name: m.Name})
+		}
+		printJSON(map[string]any{"applied": out, "version": migrations.Latest()})
+		return nil
+	}
+	if len(applied) == 0 {
+		fmt.Printf("Database is up to date (version %d)\n", migrations.Latest())
+	} else if !quietOutput {
+		fmt.Printf("\nDatabase is now at version %d\n", migrations.Latest())
+	}
+	return nil
+}
+
+func runMigrateStatus(cmd *cobra.Command, _ []string) error {
+	db, err := openMigrateDB(cmd, false)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	version, err := migrations.CurrentVersion(db)
+	if err != nil {
+		return err
+	}
+	pending, err := migrations.Pending(db)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		out := migrateStatusJSON{Version: version, Latest: migrations.Latest(), Pending: make([]migrationJSON, 0, len(pending))}
+		for _, m := range pending {
+			out.Pending = append(out.Pending, migrationJSON{Version: m.Version, Name: m.Name})
+		}
+		printJSON(out)
+		return nil
+	}
+
+	fmt.Printf("Schema version: %d (latest %d)\n", version, migrations.Latest())
+	if len(pending) == 0 {
+		fmt.Println("No pending migrations")
+		return nil
+	}
+	fmt.Printf("\nPending migrations (%d):\n", len(pending))
+	for _, m := range pending {
+		fmt.Printf("  %03d_%s\n", m.Version, m.Name)
+	}
+	fmt.Println("\nRun 'arrgo migrate' to apply them.")
+	return nil
+}
+
+// openMigrateDB opens the database named by the --config file, creating it
+// if create is set. The config is not validated: only the database path is
+// needed.
+func openMigrateDB(cmd *cobra.Command, create bool) (*sql.DB, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.LoadWithoutValidation(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	path := cfg.Database.Path
+	if create {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("create db dir: %w", err)
+		}
+	} else if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("database %s does not exist; arrgod or 'arrgo migrate' creates it", path)
+	}
+	return database.Open(path)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -49,6 +50,8 @@ type ParseResultJSON struct {
 	IsRemux          bool         `json:"remux"`
 	Edition          string       `json:"edition,omitempty"`
 	Service          string       `json:"service,omitempty"`
+	Languages        []string     `json:"languages,omitempty"`
+	Multi            bool         `json:"multi,omitempty"`
 	Group            string       `json:"group,omitempty"`
 	Proper           bool         `json:"proper,omitempty"`
 	Repack           bool         `json:"repack,omitempty"`
@@ -78,6 +81,8 @@ func (r ParseResult) toJSON() ParseResultJSON {
 		IsRemux:          info.IsRemux,
 		Edition:          info.Edition,
 		Service:          info.Service,
+		Languages:        info.Languages,
+		Multi:            info.Multi,
 		Group:            info.Group,
 		Proper:           info.Proper,
 		Repack:           info.Repack,
@@ -237,6 +242,15 @@ func scoreWithBreakdown(info release.Info, profile config.QualityProfile) (int,
 			Note:      "release rejected",
 		}}
 	}
+	if info.Group != "" && slices.ContainsFunc(profile.BannedGroups, func(g string) bool { return strings.EqualFold(g, info.Group) }) {
+		return 0, []ScoreBonus{{
+			Attribute: "Group",
+			Value:     info.Group,
+			Position:  -1,
+			Bonus:     0,
+			Note:      "banned group",
+		}}
+	}
 
 	var breakdown []ScoreBonus
 	totalScore := 0
@@ -300,9 +314,42 @@ func scoreWithBreakdown(info release.Info, profile config.QualityProfile) (int,
 		totalScore += scoring.BonusRemux
 	}
 
+	// Group bonus by preferred group tier
+	if info.Group != "" && len(profile.PreferredGroups) > 0 {
+		bonus := scoreGroup(info.Group, profile.PreferredGroups)
+		breakdown = append(breakdown, bonus)
+		totalScore += bonus.Bonus
+	}
+
 	return totalScore, breakdown
 }
 
+// scoreGroup calculates the bonus for the preferred group tier a release group is in.
+func scoreGroup(group string, tiers [][]string) ScoreBonus {
+	bonus := ScoreBonus{
+		Attribute: "Group",
+		Value:     group,
+		Position:  -1,
+		Bonus:     0,
+		Note:      noteNotInPrefList,
+	}
+
+	for i, tier := range tiers {
+		if slices.ContainsFunc(tier, func(g string) bool { return strings.EqualFold(g, group) }) {
+			multiplier := 1.0 - 0.2*float64(i)
+			if multiplier < 0 {
+				multiplier = 0
+			}
+			bonus.Position = i
+			bonus.Bonus = int(float64(scoring.BonusGroup) * multiplier)
+			bonus.Note = fmt.Sprintf("tier %d", i+1)
+			return bonus
+		}
+	}
+
+	return bonus
+}
+
 // scoreResolution returns the base resolution score and breakdown entry.
 func scoreResolution(res release.Resolution, preferences []string) (int, ScoreBonus) {
 	resStr := res.String()
@@ -471,6 +518,11 @@ func printHumanReadable(result ParseResult) {
 	if info.Service != "" {
 		fmt.Printf("Service:     %s\n", info.Service)
 	}
+	if info.Multi {
+		fmt.Printf("Languages:   MULTi %s\n", strings.Join(info.Languages, ", "))
+	} else if len(info.Languages) > 0 {
+		fmt.Printf("Languages:   %s\n", strings.Join(info.Languages, ", "))
+	}
 	if info.Group != "" {
 		fmt.Printf("Group:       %s\n", info.Group)
 	}
@@ -187,6 +187,24 @@ func TestScoreWithBreakdown_RejectList(t *testing.T) {
 	assert.Equal(t, "Reject", breakdown[0].Attribute)
 }
 
+func TestScoreWithBreakdown_Groups(t *testing.T) {
+	profile := config.QualityProfile{
+		Resolution:      []string{"1080p"},
+		PreferredGroups: [][]string{{"FraMeSToR"}, {"FLUX"}},
+		BannedGroups:    []string{"YIFY"},
+	}
+
+	score, breakdown := scoreWithBreakdown(release.Info{Resolution: release.Resolution1080p, Group: "flux"}, profile)
+	assert.Equal(t, 80+12, score, "second tier earns 80%")
+	require.Len(t, breakdown, 2)
+	assert.Equal(t, "tier 2", breakdown[1].Note)
+
+	score, breakdown = scoreWithBreakdown(release.Info{Resolution: release.Resolution1080p, Group: "YIFY"}, profile)
+	assert.Equal(t, 0, score, "banned group is rejected")
+	require.Len(t, breakdown, 1)
+	assert.Equal(t, "banned group", breakdown[0].Note)
+}
+
 func TestScoreWithBreakdown_ResolutionNotAllowed(t *testing.T) {
 	info := release.Info{
 		Resolution: release.Resolution720p,
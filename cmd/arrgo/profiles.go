@@ -34,13 +34,17 @@ func runProfilesCmd(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	fmt.Printf("Quality Profiles (%d):\n\n", len(resp.Profiles))
+	source := ""
+	if resp.ReadOnly {
+		source = ", read-only from config"
+	}
+	fmt.Printf("Quality Profiles (%d%s):\n\n", len(resp.Profiles), source)
 
-	fmt.Printf("  %-12s %s\n", "NAME", "RESOLUTIONS")
+	fmt.Printf("  %-4s %-12s %-8s %s\n", "ID", "NAME", "CUTOFF", "RESOLUTIONS")
 	fmt.Println("  " + strings.Repeat("-", 50))
 	for _, p := range resp.Profiles {
 		resolutions := strings.Join(p.Accept, ", ")
-		fmt.Printf("  %-12s %s\n", p.Name, resolutions)
+		fmt.Printf("  %-4d %-12s %-8s %s\n", p.ID, p.Name, p.Cutoff, resolutions)
 	}
 
 	return nil
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -11,6 +13,7 @@ var version = "dev"
 
 var (
 	serverURL   string
+	apiKey      string
 	jsonOutput  bool
 	quietOutput bool
 )
@@ -24,10 +27,44 @@ A unified CLI for searching indexers, managing downloads,
 and automating your media library.
 
 Run 'arrgod' to start the server daemon.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if apiKey == "" {
+			apiKey = os.Getenv("ARRGO_API_KEY")
+		}
+		if apiKey != "" {
+			http.DefaultTransport = &apiKeyTransport{key: apiKey, base: http.DefaultTransport}
+		}
+	},
+}
+
+// apiKeyTransport sends the API key with every request to the server.
+type apiKeyTransport struct {
+	key  string
+	base http.RoundTripper
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Api-Key", t.key)
+	return t.base.RoundTrip(req)
+}
+
+// exitError makes the command exit with code instead of 1, for scripts that
+// tell failures apart.
+type exitError struct {
+	code int
+	err  error
 }
 
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -42,6 +79,7 @@ var versionCmd = &cobra.Command{
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&serverURL, "server", "http://localhost:8484", "Server URL")
+	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "API key, when the server requires one (default: $ARRGO_API_KEY)")
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
 	rootCmd.PersistentFlags().BoolVarP(&quietOutput, "quiet", "q", false, "Suppress non-essential output")
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vmunix/arrgo/pkg/release"
@@ -60,10 +61,12 @@ Examples:
 
 func init() {
 	rootCmd.AddCommand(searchCmd)
-	searchCmd.Flags().BoolP("verbose", "v", false, "Show indexer, group, service")
+	searchCmd.Flags().BoolP("verbose", "v", false, "Show indexer, group, service and keyword rejections")
 	searchCmd.Flags().String("type", "", "Content type (movie or series)")
 	searchCmd.Flags().String("profile", "", "Quality profile")
+	searchCmd.Flags().String("protocol", "", "Only releases of this protocol (usenet or torrent)")
 	searchCmd.Flags().String("grab", "", "Grab release: number or 'best'")
+	searchCmd.Flags().Bool("refresh", false, "Query the indexers even if results are cached")
 }
 
 func runSearchCmd(cmd *cobra.Command, args []string) error {
@@ -72,6 +75,8 @@ func runSearchCmd(cmd *cobra.Command, args []string) error {
 	contentType, _ := cmd.Flags().GetString("type")
 	profile, _ := cmd.Flags().GetString("profile")
 	grabFlag, _ := cmd.Flags().GetString("grab")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	protocol, _ := cmd.Flags().GetString("protocol")
 
 	client := NewClient(serverURL)
 
@@ -83,7 +88,7 @@ func runSearchCmd(cmd *cobra.Command, args []string) error {
 		// The tvdbID will be 0 if canceled or not found
 	}
 
-	results, err := client.Search(query, contentType, profile)
+	results, err := client.Search(query, contentType, profile, protocol, refresh)
 	if err != nil {
 		return fmt.Errorf("search failed: %w", err)
 	}
@@ -95,6 +100,8 @@ func runSearchCmd(cmd *cobra.Command, args []string) error {
 
 	if len(results.Releases) == 0 {
 		fmt.Println("No releases found")
+		printDeferred(results.Deferred)
+		printRejected(results.Rejected, verbose)
 		return nil
 	}
 
@@ -164,7 +171,53 @@ func printSearchHumanCobra(query string, r *SearchResponse, verbose bool) {
 		}
 	}
 
+	printDeferred(r.Deferred)
+	printRejected(r.Rejected, verbose)
+
 	if len(r.Errors) > 0 {
-		fmt.Printf("\nWarnings: %s\n", strings.Join(r.Errors, ", "))
+		warnings := make([]string, len(r.Errors))
+		for i, e := range r.Errors {
+			warnings[i] = e.String()
+		}
+		fmt.Printf("\nWarnings: %s\n", strings.Join(warnings, ", "))
+	}
+	if r.Cached {
+		fmt.Printf("\nCached results from %s ago (--refresh to query indexers)\n", time.Duration(r.AgeSeconds)*time.Second)
+	}
+}
+
+// printDeferred lists usenet releases held back until they reach the
+// minimum age, with the time left.
+func printDeferred(deferred []DeferredReleaseResponse) {
+	if len(deferred) == 0 {
+		return
+	}
+	fmt.Printf("\nToo new to grab automatically:\n")
+	for _, d := range deferred {
+		title := d.Title
+		if len(title) > 50 {
+			title = title[:47] + "..."
+		}
+		fmt.Printf("  %-50s  %5d  in %s\n", title, d.Score, time.Duration(d.RemainingSeconds)*time.Second)
+	}
+}
+
+// printRejected reports releases filtered out by the profile's keyword or language lists.
+// Verbose mode lists each release with the reason it was rejected.
+func printRejected(rejected []RejectedReleaseResponse, verbose bool) {
+	if len(rejected) == 0 {
+		return
+	}
+	if !verbose {
+		fmt.Printf("\n%d releases rejected by profile filters (use --verbose to list)\n", len(rejected))
+		return
+	}
+	fmt.Printf("\nRejected by profile filters:\n")
+	for _, rej := range rejected {
+		title := rej.Title
+		if len(title) > 50 {
+			title = title[:47] + "..."
+		}
+		fmt.Printf("  %-50s  %s\n", title, rej.Reason)
 	}
 }
@@ -43,7 +43,7 @@ func TestClientSearch_Success(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.Search("The Matrix 1999", "", "")
+	resp, err := client.Search("The Matrix 1999", "", "", "", false)
 	require.NoError(t, err)
 	require.Len(t, resp.Releases, 2)
 	assert.Equal(t, "The Matrix 1999 1080p BluRay x264", resp.Releases[0].Title)
@@ -61,7 +61,7 @@ func TestClientSearch_EmptyResults(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.Search("Nonexistent Movie 2099", "", "")
+	resp, err := client.Search("Nonexistent Movie 2099", "", "", "", false)
 	require.NoError(t, err)
 	assert.Empty(t, resp.Releases)
 }
@@ -77,20 +77,21 @@ func TestClientSearch_WithErrors(t *testing.T) {
 					Indexer: "NZBgeek",
 				},
 			},
-			Errors: []string{
-				"DrunkenSlug: connection timeout",
-				"NZBfinder: rate limited",
+			Errors: []SearchError{
+				{Indexer: "DrunkenSlug", Message: "connection timeout"},
+				{Indexer: "NZBfinder", Code: 429, Message: "newznab error 429: too many requests", RetryAfterSeconds: 600},
 			},
 		}).
 		Build()
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	resp, err := client.Search("query", "", "")
+	resp, err := client.Search("query", "", "", "", false)
 	require.NoError(t, err)
 	assert.Len(t, resp.Releases, 1)
 	assert.Len(t, resp.Errors, 2)
-	assert.Equal(t, "DrunkenSlug: connection timeout", resp.Errors[0])
+	assert.Equal(t, "DrunkenSlug: connection timeout", resp.Errors[0].String())
+	assert.Equal(t, "NZBfinder: newznab error 429: too many requests (retry in 10m0s)", resp.Errors[1].String())
 }
 
 func TestClientSearch_QueryParams(t *testing.T) {
@@ -148,7 +149,7 @@ func TestClientSearch_QueryParams(t *testing.T) {
 			defer srv.Close()
 
 			client := NewClient(srv.URL)
-			_, err := client.Search(tt.query, tt.contentType, tt.profile)
+			_, err := client.Search(tt.query, tt.contentType, tt.profile, "", false)
 			require.NoError(t, err)
 		})
 	}
@@ -162,7 +163,7 @@ func TestClientSearch_ServerError(t *testing.T) {
 	defer srv.Close()
 
 	client := NewClient(srv.URL)
-	_, err := client.Search("query", "", "")
+	_, err := client.Search("query", "", "", "", false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "500")
 }
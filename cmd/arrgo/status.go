@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -13,13 +14,22 @@ var statusCmd = &cobra.Command{
 	Short: "System status and verification",
 	Long: `Show system status and verify download states against live systems.
 
-Without arguments, shows system dashboard (connections, downloads, library).
-With a download ID, verifies that specific download against SABnzbd/filesystem/Plex.
+Without arguments, shows system dashboard (connections, downloads, library,
+recent imports, upcoming episodes, and a summary of problems).
+With a download ID, verifies that specific download against the download client/filesystem/Plex.
 
 Examples:
   arrgo status                # Show system dashboard
+  arrgo status --skip-plex    # Dashboard without the Plex library check
   arrgo status --verify       # Dashboard + run verification on all downloads
-  arrgo status 42             # Verify specific download #42`,
+  arrgo status 42             # Verify specific download #42
+  arrgo status --verify --fix # Verify and correct download states
+  arrgo status 42 --fix --reimport  # Re-import #42 if its library file is missing
+  arrgo status --wait-healthy --timeout 2m  # Block until the server is ready
+
+--wait-healthy polls the server's readiness checks until they pass and exits
+0 when ready, 2 if the server never answered, or 3 if it answered but was
+still unhealthy when the timeout ran out.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runStatusCmd,
 }
@@ -27,11 +37,38 @@ Examples:
 func init() {
 	rootCmd.AddCommand(statusCmd)
 	statusCmd.Flags().Bool("verify", false, "Run verification on all downloads")
+	statusCmd.Flags().Bool("fix", false, "Apply safe fixes for problems found by verification")
+	statusCmd.Flags().Bool("reimport", false, "With --fix, re-import downloads whose library file is missing")
+	statusCmd.Flags().Bool("skip-plex", false, "Skip the dashboard's Plex library check")
+	statusCmd.Flags().Bool("wait-healthy", false, "Wait until the server's readiness checks pass")
+	statusCmd.Flags().Duration("timeout", time.Minute, "With --wait-healthy, how long to wait")
 }
 
+// Exit codes of --wait-healthy.
+const (
+	exitUnreachable = 2 // The server never answered
+	exitUnhealthy   = 3 // The server answered but a check failed
+)
+
+// healthPollInterval is how often --wait-healthy checks the server. The
+// server caches readiness for 30s, so polling faster only repeats answers.
+const healthPollInterval = 2 * time.Second
+
 func runStatusCmd(cmd *cobra.Command, args []string) error {
 	client := NewClient(serverURL)
 	runVerify, _ := cmd.Flags().GetBool("verify")
+	fix, _ := cmd.Flags().GetBool("fix")
+	reimport, _ := cmd.Flags().GetBool("reimport")
+	skipPlex, _ := cmd.Flags().GetBool("skip-plex")
+	if waitHealthy, _ := cmd.Flags().GetBool("wait-healthy"); waitHealthy {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		return runWaitHealthy(client, timeout, healthPollInterval)
+	}
+	if reimport && !fix {
+		return fmt.Errorf("--reimport requires --fix")
+	}
+	// --fix implies verification
+	runVerify = runVerify || fix
 
 	// If a download ID is provided, verify that specific download
 	if len(args) > 0 {
@@ -39,11 +76,11 @@ func runStatusCmd(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("invalid download ID: %s", args[0])
 		}
-		return runVerifyDownload(client, &id)
+		return runVerifyDownload(client, &id, fix, reimport)
 	}
 
 	// Get dashboard
-	dash, err := client.Dashboard()
+	dash, err := client.Dashboard(skipPlex)
 	if err != nil {
 		return fmt.Errorf("status check failed: %w", err)
 	}
@@ -51,7 +88,7 @@ func runStatusCmd(cmd *cobra.Command, args []string) error {
 	if jsonOutput {
 		if runVerify {
 			// Combine dashboard and verify results
-			verify, err := client.Verify(nil)
+			verify, err := client.Verify(nil, fix, reimport)
 			if err != nil {
 				return fmt.Errorf("verify failed: %w", err)
 			}
@@ -72,14 +109,53 @@ func runStatusCmd(cmd *cobra.Command, args []string) error {
 	// (failed downloads are shown in verify output but don't trigger auto-verify)
 	if runVerify || dash.Stuck.Count > 0 {
 		fmt.Println()
-		return runVerifyDownload(client, nil)
+		return runVerifyDownload(client, nil, fix, reimport)
 	}
 
 	return nil
 }
 
-func runVerifyDownload(client *Client, id *int64) error {
-	result, err := client.Verify(id)
+// runWaitHealthy polls the server's readiness until it passes or timeout
+// runs out, returning an exitError that tells unreachable from unhealthy.
+func runWaitHealthy(client *Client, timeout, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		health, err := client.Health(true)
+		if err == nil && health.Status == "ok" {
+			if jsonOutput {
+				printJSON(health)
+			} else if !quietOutput {
+				fmt.Println("Server is healthy")
+			}
+			return nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			if err != nil {
+				return &exitError{code: exitUnreachable, err: fmt.Errorf("server not reachable after %s: %w", timeout, err)}
+			}
+			if jsonOutput {
+				printJSON(health)
+			}
+			return &exitError{code: exitUnhealthy, err: fmt.Errorf("server unhealthy after %s: %s", timeout, failedChecks(health))}
+		}
+		time.Sleep(interval)
+	}
+}
+
+// failedChecks lists the failed checks of a health response, e.g.
+// "indexers (no indexer reachable)".
+func failedChecks(h *HealthResponse) string {
+	var failed []string
+	for _, c := range h.Checks {
+		if c.Status == "failed" {
+			failed = append(failed, fmt.Sprintf("%s (%s)", c.Name, c.Error))
+		}
+	}
+	return strings.Join(failed, ", ")
+}
+
+func runVerifyDownload(client *Client, id *int64, fix, reimport bool) error {
+	result, err := client.Verify(id, fix, reimport)
 	if err != nil {
 		return fmt.Errorf("verify failed: %w", err)
 	}
@@ -99,35 +175,88 @@ func printDashboard(server string, d *DashboardResponse) {
 	if d.Connections.Plex {
 		plexStatus = "connected"
 	}
-	sabStatus := "disconnected"
-	if d.Connections.SABnzbd {
-		sabStatus = "connected"
+	clients := "none"
+	if len(d.Connections.Clients) > 0 {
+		names := make([]string, 0, len(d.Connections.Clients))
+		for _, c := range d.Connections.Clients {
+			names = append(names, fmt.Sprintf("%s (%s)", c.Name, c.Protocol))
+		}
+		clients = strings.Join(names, ", ")
 	}
 
-	fmt.Printf("arrgo v%s | Server: %s | Plex: %s | SABnzbd: %s\n\n",
-		d.Version, server, plexStatus, sabStatus)
+	fmt.Printf("arrgo v%s | Server: %s | Plex: %s | Clients: %s\n\n",
+		d.Version, server, plexStatus, clients)
 
 	// Downloads
 	fmt.Println("Downloads")
 	fmt.Printf("  Queued:       %d\n", d.Downloads.Queued)
 	fmt.Printf("  Downloading:  %d\n", d.Downloads.Downloading)
-	fmt.Printf("  Completed:    %d\n", d.Downloads.Completed)
+	if d.Downloads.ImportQueued > 0 {
+		fmt.Printf("  Completed:    %d  (%d waiting to import)\n", d.Downloads.Completed, d.Downloads.ImportQueued)
+	} else {
+		fmt.Printf("  Completed:    %d\n", d.Downloads.Completed)
+	}
 	fmt.Printf("  Importing:    %d\n", d.Downloads.Importing)
 	fmt.Printf("  Imported:     %d  (awaiting Plex verification)\n", d.Downloads.Imported)
+	if d.Downloads.PartiallyImported > 0 {
+		fmt.Printf("  Partial:      %d  (episodes failed, see downloads show)\n", d.Downloads.PartiallyImported)
+	}
 	fmt.Println()
 
 	// Library
 	fmt.Println("Library")
 	fmt.Printf("  Movies:     %d tracked\n", d.Library.Movies)
 	fmt.Printf("  Series:     %d tracked\n", d.Library.Series)
+	fmt.Printf("  Size:       %s\n", formatSize(d.Library.SizeBytes))
 	fmt.Println()
 
+	// Recent imports
+	if len(d.Recent) > 0 {
+		fmt.Println("Recent imports")
+		for _, imp := range d.Recent {
+			title := imp.Title
+			if len(title) > 40 {
+				title = title[:37] + "..."
+			}
+			t, _ := time.Parse(time.RFC3339, imp.ImportedAt)
+			fmt.Printf("  %-10s %-40s %s\n", formatTimeAgo(t.Unix()), title, imp.Quality)
+		}
+		fmt.Println()
+	}
+
+	// Upcoming episodes
+	if len(d.Upcoming) > 0 {
+		fmt.Println("Upcoming")
+		for _, ep := range d.Upcoming {
+			airDate := ep.AirDate
+			if t, err := time.Parse(time.RFC3339, ep.AirDate); err == nil {
+				airDate = t.Format(time.DateOnly)
+			}
+			series := ep.Series
+			if len(series) > 33 {
+				series = series[:30] + "..."
+			}
+			label := fmt.Sprintf("%s S%02dE%02d", series, ep.Season, ep.Episode)
+			fmt.Printf("  %-10s %-40s %s\n", airDate, label, ep.EpisodeTitle)
+		}
+		fmt.Println()
+	}
+
 	// Problems summary
-	if d.Stuck.Count > 0 {
-		fmt.Printf("Problems: %d stuck downloads (running verification...)\n", d.Stuck.Count)
+	if len(d.Problems) == 0 {
+		fmt.Println("Problems: none")
+		return
 	}
-	if d.Downloads.Failed > 0 {
-		fmt.Printf("Failed: %d downloads (use 'arrgo downloads -s failed' to see)\n", d.Downloads.Failed)
+	fmt.Println("Problems")
+	for _, p := range d.Problems {
+		if p.Fix != "" {
+			fmt.Printf("  %s (%s)\n", p.Message, p.Fix)
+		} else {
+			fmt.Printf("  %s\n", p.Message)
+		}
+	}
+	if d.Stuck.Count > 0 {
+		fmt.Println("  Running verification for stuck downloads...")
 	}
 }
 
@@ -139,15 +268,25 @@ func printVerifyResult(r *VerifyResponse) {
 	if !r.Connections.Plex {
 		plexStatus = "FAIL " + r.Connections.PlexErr
 	}
-	sabStatus := "ok"
-	if !r.Connections.SABnzbd {
-		sabStatus = "FAIL " + r.Connections.SABErr
+	for _, c := range r.Connections.Clients {
+		status := "ok"
+		if !c.Connected {
+			status = "FAIL " + c.Error
+		}
+		fmt.Printf("  %s: %s\n", c.Name, status)
 	}
-	fmt.Printf("  SABnzbd: %s\n", sabStatus)
 	fmt.Printf("  Plex:    %s\n", plexStatus)
 	fmt.Printf("  Passed:  %d/%d\n", r.Passed, r.Checked)
 	fmt.Println()
 
+	if len(r.AppliedFixes) > 0 {
+		fmt.Printf("Applied fixes (%d):\n", len(r.AppliedFixes))
+		for _, f := range r.AppliedFixes {
+			fmt.Printf("  ID %d | %s | %s -> %s | %s\n", f.DownloadID, f.Action, f.FromStatus, f.ToStatus, f.Reason)
+		}
+		fmt.Println()
+	}
+
 	if len(r.Problems) == 0 {
 		fmt.Println("No problems detected.")
 		return
@@ -168,5 +307,9 @@ func printVerifyResult(r *VerifyResponse) {
 		fmt.Println()
 	}
 
-	fmt.Printf("%d problems found. Run suggested commands to resolve.\n", len(r.Problems))
+	if r.DryRun {
+		fmt.Printf("%d problems found. Run suggested commands or 'arrgo verify --fix' to resolve.\n", len(r.Problems))
+		return
+	}
+	fmt.Printf("%d problems found, %d fixed.\n", len(r.Problems), len(r.AppliedFixes))
 }
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -76,3 +78,73 @@ func TestClientStatus_EmptyResponse(t *testing.T) {
 	assert.Empty(t, status.Status)
 	assert.Empty(t, status.Version)
 }
+
+func TestRunWaitHealthy(t *testing.T) {
+	healthy := HealthResponse{Status: "ok", Ready: true, Checks: []HealthCheck{{Name: "database", Status: "ok"}}}
+	unhealthy := HealthResponse{Status: "unavailable", Ready: true, Checks: []HealthCheck{
+		{Name: "database", Status: "ok"},
+		{Name: "indexers", Status: "failed", Error: "no indexer reachable"},
+	}}
+
+	t.Run("healthy", func(t *testing.T) {
+		srv := newMockServer(t).
+			ExpectPath("/api/v1/health").
+			Handler(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "true", r.URL.Query().Get("ready"))
+				writeTestJSON(w, http.StatusOK, healthy)
+			}).
+			Build()
+		defer srv.Close()
+
+		require.NoError(t, runWaitHealthy(NewClient(srv.URL), time.Second, time.Millisecond))
+	})
+
+	t.Run("becomes healthy", func(t *testing.T) {
+		calls := 0
+		srv := newMockServer(t).
+			Handler(func(w http.ResponseWriter, _ *http.Request) {
+				calls++
+				if calls < 3 {
+					writeTestJSON(w, http.StatusServiceUnavailable, unhealthy)
+					return
+				}
+				writeTestJSON(w, http.StatusOK, healthy)
+			}).
+			Build()
+		defer srv.Close()
+
+		require.NoError(t, runWaitHealthy(NewClient(srv.URL), time.Second, time.Millisecond))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		srv := newMockServer(t).
+			Handler(func(w http.ResponseWriter, _ *http.Request) {
+				writeTestJSON(w, http.StatusServiceUnavailable, unhealthy)
+			}).
+			Build()
+		defer srv.Close()
+
+		err := runWaitHealthy(NewClient(srv.URL), 20*time.Millisecond, 5*time.Millisecond)
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, exitUnhealthy, exitErr.code)
+		assert.Contains(t, err.Error(), "indexers (no indexer reachable)")
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		srv := newMockServer(t).Build()
+		srv.Close()
+
+		err := runWaitHealthy(NewClient(srv.URL), 20*time.Millisecond, 5*time.Millisecond)
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, exitUnreachable, exitErr.code)
+	})
+}
+
+func writeTestJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
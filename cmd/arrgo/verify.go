@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify downloads against live systems",
+	Long: `Check active and failed downloads against the download clients, the
+filesystem and Plex, and explain each problem found with suggested fixes.
+
+With --fix the server applies the safe reconciliations it can (marking
+downloads completed or failed to match the client) and each is listed.
+--reimport also re-imports downloads whose library file is missing.
+
+Exits 4 while problems remain unresolved, so it can run from cron:
+
+  */30 * * * * arrgo verify --fix || echo "arrgo needs attention"
+
+Examples:
+  arrgo verify              # Report problems, change nothing
+  arrgo verify --id 42      # Verify download #42 only
+  arrgo verify --fix        # Apply safe fixes
+  arrgo verify --json       # Full response for scripts`,
+	Args: cobra.NoArgs,
+	RunE: runVerifyCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().Int64("id", 0, "Verify only this download")
+	verifyCmd.Flags().Bool("fix", false, "Apply safe fixes for the problems found")
+	verifyCmd.Flags().Bool("reimport", false, "With --fix, re-import downloads whose library file is missing")
+}
+
+// exitUnresolved is the exit code of verify while problems remain.
+const exitUnresolved = 4
+
+func runVerifyCmd(cmd *cobra.Command, _ []string) error {
+	fix, _ := cmd.Flags().GetBool("fix")
+	reimport, _ := cmd.Flags().GetBool("reimport")
+	if reimport && !fix {
+		return fmt.Errorf("--reimport requires --fix")
+	}
+	var id *int64
+	if cmd.Flags().Changed("id") {
+		v, _ := cmd.Flags().GetInt64("id")
+		id = &v
+	}
+	return runVerify(NewClient(serverURL), id, fix, reimport)
+}
+
+// runVerify verifies downloads and prints the result, returning an exitError
+// while problems remain that no applied fix resolved.
+func runVerify(client *Client, id *int64, fix, reimport bool) error {
+	result, err := client.Verify(id, fix, reimport)
+	if err != nil {
+		return fmt.Errorf("verify failed: %w", err)
+	}
+
+	if jsonOutput {
+		printJSON(result)
+	} else {
+		printVerifyResult(result)
+	}
+
+	if n := unresolvedProblems(result); n > 0 {
+		return &exitError{code: exitUnresolved, err: fmt.Errorf("%d problems unresolved", n)}
+	}
+	return nil
+}
+
+// unresolvedProblems counts the problems of downloads no fix was applied to.
+func unresolvedProblems(r *VerifyResponse) int {
+	fixed := make(map[int64]bool, len(r.AppliedFixes))
+	for _, f := range r.AppliedFixes {
+		fixed[f.DownloadID] = true
+	}
+	n := 0
+	for _, p := range r.Problems {
+		if !fixed[p.DownloadID] {
+			n++
+		}
+	}
+	return n
+}
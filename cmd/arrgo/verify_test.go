@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunVerify(t *testing.T) {
+	t.Run("no problems", func(t *testing.T) {
+		srv := newMockServer(t).
+			ExpectPath("/api/v1/verify").
+			Handler(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "42", r.URL.Query().Get("id"))
+				assert.Empty(t, r.URL.Query().Get("fix"))
+				writeTestJSON(w, http.StatusOK, VerifyResponse{Checked: 1, Passed: 1, DryRun: true})
+			}).
+			Build()
+		defer srv.Close()
+
+		id := int64(42)
+		require.NoError(t, runVerify(NewClient(srv.URL), &id, false, false))
+	})
+
+	t.Run("fixed problems resolve", func(t *testing.T) {
+		srv := newMockServer(t).
+			Handler(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "true", r.URL.Query().Get("fix"))
+				writeTestJSON(w, http.StatusOK, VerifyResponse{
+					Checked:      2,
+					Problems:     []VerifyProblem{{DownloadID: 1, Status: "downloading", Title: "Dune", Since: "3h0m0s"}, {DownloadID: 2, Status: "imported", Title: "Heat"}},
+					AppliedFixes: []AppliedFix{{DownloadID: 1, Action: "mark_completed", FromStatus: "downloading", ToStatus: "completed"}},
+				})
+			}).
+			Build()
+		defer srv.Close()
+
+		err := runVerify(NewClient(srv.URL), nil, true, false)
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, exitUnresolved, exitErr.code)
+		assert.Contains(t, err.Error(), "1 problems unresolved")
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv := newMockServer(t).
+			Handler(func(w http.ResponseWriter, _ *http.Request) {
+				writeTestJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "fix mode requires the event bus"})
+			}).
+			Build()
+		defer srv.Close()
+
+		err := runVerify(NewClient(srv.URL), nil, true, false)
+		require.Error(t, err)
+		var exitErr *exitError
+		assert.NotErrorAs(t, err, &exitErr)
+	})
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// WantedItemResponse matches the API response for a wanted movie or episode.
+type WantedItemResponse struct {
+	ContentID      int64      `json:"content_id"`
+	Type           string     `json:"type"`
+	Title          string     `json:"title"`
+	Year           int        `json:"year"`
+	EpisodeID      *int64     `json:"episode_id,omitempty"`
+	Season         *int       `json:"season,omitempty"`
+	Episode        *int       `json:"episode,omitempty"`
+	EpisodeTitle   string     `json:"episode_title,omitempty"`
+	AirDate        *time.Time `json:"air_date,omitempty"`
+	AddedAt        time.Time  `json:"added_at"`
+	QualityProfile string     `json:"quality_profile"`
+	CurrentQuality string     `json:"current_quality,omitempty"`
+	WantedQuality  string     `json:"wanted_quality,omitempty"`
+	Reason         string     `json:"reason"`
+}
+
+// WantedSectionResponse matches one section of the wanted API response.
+type WantedSectionResponse struct {
+	Items []WantedItemResponse `json:"items"`
+	Total int                  `json:"total"`
+}
+
+// WantedResponse matches the API response for listing wanted content.
+type WantedResponse struct {
+	Missing     WantedSectionResponse `json:"missing"`
+	CutoffUnmet WantedSectionResponse `json:"cutoff_unmet"`
+	Limit       int                   `json:"limit"`
+	Offset      int                   `json:"offset"`
+}
+
+func init() {
+	wantedCmd := &cobra.Command{
+		Use:   "wanted",
+		Short: "List missing content and content below its quality cutoff",
+		Long: `Lists movies and episodes that are wanted but have no file (missing),
+and those whose best file is below the quality profile's best resolution (cutoff unmet).`,
+		RunE: runWantedCmd,
+	}
+	wantedCmd.Flags().String("type", "", "Filter by type (movie, series)")
+	wantedCmd.Flags().String("sort", "", "Sort by added_at or air_date (default: added_at)")
+	wantedCmd.Flags().Int("limit", 50, "Maximum items per section")
+	wantedCmd.Flags().Int("offset", 0, "Items to skip per section")
+
+	rootCmd.AddCommand(wantedCmd)
+}
+
+func runWantedCmd(cmd *cobra.Command, args []string) error {
+	contentType, _ := cmd.Flags().GetString("type")
+	sort, _ := cmd.Flags().GetString("sort")
+	limit, _ := cmd.Flags().GetInt("limit")
+	offset, _ := cmd.Flags().GetInt("offset")
+
+	client := NewClient(serverURL)
+	resp, err := client.Wanted(contentType, sort, limit, offset)
+	if err != nil {
+		return fmt.Errorf("failed to fetch wanted: %w", err)
+	}
+
+	if jsonOutput {
+		printJSON(resp)
+		return nil
+	}
+
+	printWantedSection("Missing", resp.Missing)
+	fmt.Println()
+	printWantedSection("Cutoff unmet", resp.CutoffUnmet)
+	return nil
+}
+
+func printWantedSection(name string, section WantedSectionResponse) {
+	fmt.Printf("%s (%d):\n", name, section.Total)
+	if len(section.Items) == 0 {
+		fmt.Println("  (none)")
+		return
+	}
+
+	fmt.Printf("  %-40s %-8s %-8s %-8s\n", "TITLE", "PROFILE", "HAVE", "WANT")
+	fmt.Println("  " + strings.Repeat("-", 68))
+	for _, item := range section.Items {
+		have := item.CurrentQuality
+		if have == "" {
+			have = "-"
+		}
+		want := item.WantedQuality
+		if want == "" {
+			want = "any"
+		}
+		label := wantedLabel(item)
+		if len(label) > 40 {
+			label = label[:37] + "..."
+		}
+		fmt.Printf("  %-40s %-8s %-8s %-8s\n", label, item.QualityProfile, have, want)
+	}
+}
+
+// wantedLabel formats a wanted item as "Title (Year)" or "Title S01E02".
+func wantedLabel(item WantedItemResponse) string {
+	if item.Season != nil && item.Episode != nil {
+		return fmt.Sprintf("%s S%02dE%02d", item.Title, *item.Season, *item.Episode)
+	}
+	return fmt.Sprintf("%s (%d)", item.Title, item.Year)
+}
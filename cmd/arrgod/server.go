@@ -12,21 +12,24 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	_ "modernc.org/sqlite"
-
 	"github.com/vmunix/arrgo/internal/adapters/plex"
+	"github.com/vmunix/arrgo/internal/api/auth"
 	"github.com/vmunix/arrgo/internal/api/compat"
+	"github.com/vmunix/arrgo/internal/api/requestlog"
 	v1 "github.com/vmunix/arrgo/internal/api/v1"
 	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/database"
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
 	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
 	"github.com/vmunix/arrgo/internal/metadata"
 	"github.com/vmunix/arrgo/internal/migrations"
+	"github.com/vmunix/arrgo/internal/profile"
 	"github.com/vmunix/arrgo/internal/search"
 	"github.com/vmunix/arrgo/internal/server"
 	"github.com/vmunix/arrgo/internal/tmdb"
@@ -34,6 +37,17 @@ import (
 	"github.com/vmunix/arrgo/pkg/tvdb"
 )
 
+const (
+	metadataRefreshInterval = 6 * time.Hour      // How often to look for stale content metadata
+	metadataMaxAge          = 7 * 24 * time.Hour // Metadata older than this is refetched
+	episodeSyncInterval     = 24 * time.Hour     // How often continuing series are re-synced from TVDB
+	episodeSyncJitter       = time.Hour          // Random delay added to each episode sync
+	bandwidthInterval       = time.Minute        // How often the bandwidth schedule is re-evaluated
+	wantedSearchInterval    = 6 * time.Hour      // How often missing movies are searched for
+	shutdownTimeout         = 30 * time.Second   // Grace period for HTTP requests and in-flight imports
+	jobStopTimeout          = 5 * time.Second    // How long to wait for background jobs after cancel
+)
+
 func parseLogLevel(s string) slog.Level {
 	switch strings.ToLower(s) {
 	case "debug":
@@ -47,43 +61,21 @@ func parseLogLevel(s string) slog.Level {
 	}
 }
 
-type statusRecorder struct {
-	http.ResponseWriter
-	status int
-}
-
-func (r *statusRecorder) WriteHeader(code int) {
-	if r.status == 200 { // Only capture first WriteHeader call
-		r.status = code
-	}
-	r.ResponseWriter.WriteHeader(code)
-}
-
-func logRequests(next http.Handler, log *slog.Logger) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		wrapped := &statusRecorder{ResponseWriter: w, status: 200}
-		next.ServeHTTP(wrapped, r)
-		log.Info("http request",
-			"method", r.Method,
-			"path", r.URL.Path,
-			"status", wrapped.status,
-			"duration_ms", time.Since(start).Milliseconds(),
-		)
-	})
-}
-
 func runServer(configPath string) error {
-	// Load config
-	cfg, err := config.Load(configPath)
+	// Load and check config; refuse to start on errors, log warnings
+	cfg, configErr, err := config.Check(configPath)
 	if err != nil {
 		return fmt.Errorf("config: %w", err)
 	}
+	if configErr.HasErrors() {
+		return fmt.Errorf("config: %w", configErr)
+	}
 
 	// Create logger
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: parseLogLevel(cfg.Server.LogLevel),
 	}))
+	logConfigWarnings(logger, configErr.Warnings)
 
 	// Ensure database directory exists
 	dbDir := filepath.Dir(cfg.Database.Path)
@@ -91,123 +83,56 @@ func runServer(configPath string) error {
 		return fmt.Errorf("create db dir: %w", err)
 	}
 
-	// Open database
-	db, err := sql.Open("sqlite", cfg.Database.Path)
+	// Open database (WAL, busy timeout and immediate transactions; see database.DSN)
+	db, err := database.Open(cfg.Database.Path)
 	if err != nil {
-		return fmt.Errorf("open db: %w", err)
-	}
-	defer func() { _ = db.Close() }()
-
-	// Configure SQLite for better concurrency:
-	// - WAL mode allows concurrent reads during writes
-	// - busy_timeout waits instead of failing immediately on lock contention
-	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
-		return fmt.Errorf("enable WAL mode: %w", err)
-	}
-	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
-		return fmt.Errorf("set busy timeout: %w", err)
-	}
-
-	// Run migrations with version checking
-	// Helper to get current schema version
-	getVersion := func() int {
-		var version int
-		_ = db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
-		return version
-	}
-	setVersion := func(v int) error {
-		_, err := db.Exec("UPDATE schema_migrations SET version = ?", v)
 		return err
 	}
+	defer func() { _ = db.Close() }()
 
-	// Migration 001 - initial schema (creates schema_migrations table)
-	if _, err := db.Exec(migrations.InitialSQL); err != nil {
-		return fmt.Errorf("migrate 001: %w", err)
-	}
-
-	currentVersion := getVersion()
-
-	// Migration 002 - add last_transition_at column
-	if currentVersion < 2 {
-		if _, err := db.Exec(migrations.Migration002LastTransitionAt); err != nil {
-			if !strings.Contains(err.Error(), "duplicate column") {
-				return fmt.Errorf("migrate 002: %w", err)
-			}
-		}
-		if err := setVersion(2); err != nil {
-			return fmt.Errorf("migrate 002 version: %w", err)
-		}
-		currentVersion = 2
+	// Schema migrations: refuse a database from a newer arrgo, then apply
+	// pending migrations unless that is left to `arrgo migrate`
+	pending, err := migrations.Pending(db)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
 	}
-
-	// Migration 003 - adds 'cleaned' to status CHECK constraint
-	if currentVersion < 3 {
-		if _, err := db.Exec(migrations.Migration003DownloadsStatusCleaned); err != nil {
-			return fmt.Errorf("migrate 003: %w", err)
+	if len(pending) > 0 {
+		if !cfg.Database.ShouldAutoMigrate() {
+			return fmt.Errorf("database has %d pending migration(s); run 'arrgo migrate' or set database.auto_migrate", len(pending))
 		}
-		if err := setVersion(3); err != nil {
-			return fmt.Errorf("migrate 003 version: %w", err)
+		if _, err := migrations.Up(db, logger.With("component", "migrations")); err != nil {
+			return fmt.Errorf("migrate: %w", err)
 		}
-		currentVersion = 3
 	}
 
-	// Migration 004 skipped (was merged into 003)
-
-	// Migration 005 - events table
-	if currentVersion < 5 {
-		if _, err := db.Exec(migrations.Migration005Events); err != nil {
-			if !strings.Contains(err.Error(), "already exists") {
-				return fmt.Errorf("migrate 005: %w", err)
-			}
-		}
-		if err := setVersion(5); err != nil {
-			return fmt.Errorf("migrate 005 version: %w", err)
-		}
-		currentVersion = 5
-	}
+	// === Stores (always created) ===
+	libraryStore := library.NewStore(db)
+	downloadStore := download.NewStore(db)
+	historyStore := importer.NewHistoryStore(db)
 
-	// Migration 006 - download_episodes junction table
-	if currentVersion < 6 {
-		if _, err := db.Exec(migrations.Migration006DownloadEpisodes); err != nil {
-			if !strings.Contains(err.Error(), "already exists") && !strings.Contains(err.Error(), "duplicate column") {
-				return fmt.Errorf("migrate 006: %w", err)
-			}
-		}
-		if err := setVersion(6); err != nil {
-			return fmt.Errorf("migrate 006 version: %w", err)
-		}
+	// Quality profiles live in the database, seeded from config on first run,
+	// unless quality.legacy_profiles keeps serving them read-only from config
+	profileStore, err := openProfiles(cfg, db, logger)
+	if err != nil {
+		return err
 	}
 
-	// Migration 007 - metadata cache table for TVDB/TMDB
-	if currentVersion < 7 {
-		if _, err := db.Exec(migrations.Migration007MetadataCache); err != nil {
-			if !strings.Contains(err.Error(), "already exists") {
-				return fmt.Errorf("migrate 007: %w", err)
-			}
-		}
-		if err := setVersion(7); err != nil {
-			return fmt.Errorf("migrate 007 version: %w", err)
-		}
-		currentVersion = 7
+	// Content may reference quality profiles that have since been removed
+	if counts, err := libraryStore.QualityProfileCounts(); err != nil {
+		logger.Warn("could not check quality profiles in use", "error", err)
+	} else if defined, err := profileStore.Definitions(); err != nil {
+		logger.Warn("could not check quality profiles in use", "error", err)
+	} else {
+		logConfigWarnings(logger, config.ValidateProfilesDefined(defined, counts))
 	}
 
-	// Migration 008 - download progress columns
-	if currentVersion < 8 {
-		if _, err := db.Exec(migrations.Migration008DownloadProgress); err != nil {
-			if !strings.Contains(err.Error(), "duplicate column") {
-				return fmt.Errorf("migrate 008: %w", err)
-			}
-		}
-		if err := setVersion(8); err != nil {
-			return fmt.Errorf("migrate 008 version: %w", err)
-		}
+	// Library checks run in the background and do not survive a restart
+	if n, err := libraryStore.AbandonCheckJobs(); err != nil {
+		logger.Warn("could not close interrupted library checks", "error", err)
+	} else if n > 0 {
+		logger.Info("library checks interrupted by restart", "count", n)
 	}
 
-	// === Stores (always created) ===
-	libraryStore := library.NewStore(db)
-	downloadStore := download.NewStore(db)
-	historyStore := importer.NewHistoryStore(db)
-
 	// Log all state transitions
 	downloadStore.OnTransition(func(e download.TransitionEvent) {
 		logger.Info("download status changed",
@@ -218,20 +143,48 @@ func runServer(configPath string) error {
 	})
 
 	// === Clients (optional - nil if not configured) ===
-	var sabClient *download.SABnzbdClient
-	if cfg.Downloaders.SABnzbd != nil {
-		sabClient = download.NewSABnzbdClient(
-			cfg.Downloaders.SABnzbd.URL,
-			cfg.Downloaders.SABnzbd.APIKey,
-			cfg.Downloaders.SABnzbd.Category,
-			logger,
-		)
+	// Download clients are registered in routing order: the first client
+	// for each protocol receives new grabs of that protocol.
+	var downloadManager *download.Manager
+	var runnerClients []server.ClientConfig
+	downloadClients := cfg.Downloaders.All()
+	for _, name := range downloadClientNames(cfg) {
+		c := downloadClients[name]
+		if downloadManager == nil {
+			downloadManager = download.NewManager(downloadStore, logger.With("component", "download"))
+			downloadManager.SetRetryPolicy(downloadRetryPolicy(cfg))
+			if ttl := cfg.Downloaders.StatusCacheTTL; ttl != 0 {
+				downloadManager.SetStatusCacheTTL(ttl)
+			}
+		}
+		sabClient := download.NewSABnzbdClient(c.URL, c.APIKey, c.Category, logger.With("client", name))
+		downloadManager.AddClient(download.Client(name), download.ProtocolUsenet, sabClient)
+		runnerClients = append(runnerClients, server.ClientConfig{
+			Name:         download.Client(name),
+			PollInterval: c.PollInterval,
+			RemotePath:   c.RemotePath,
+			LocalPath:    c.LocalPath,
+			Categories: download.Categories{
+				Default: c.Category,
+				Movie:   c.MovieCategory,
+				Series:  c.SeriesCategory,
+			},
+		})
+	}
+
+	// Uploaded NZBs wait next to the database until the client takes them,
+	// so grabs still queued at shutdown can be sent after a restart
+	var uploads *download.Uploads
+	if downloadManager != nil {
+		uploads = download.NewUploads(filepath.Join(filepath.Dir(cfg.Database.Path), "uploads"))
+		downloadManager.SetUploads(uploads)
 	}
 
 	// Create Newznab clients for all configured indexers
-	newznabClients := make([]*newznab.Client, 0, len(cfg.Indexers))
+	newznabClients := make([]*search.Indexer, 0, len(cfg.Indexers))
 	for name, indexer := range cfg.Indexers {
-		newznabClients = append(newznabClients, newznab.NewClient(name, indexer.URL, indexer.APIKey, logger))
+		client := newznab.NewClient(name, indexer.URL, indexer.APIKey, logger, newznab.WithAPIKeys(indexer.APIKeys...))
+		newznabClients = append(newznabClients, search.NewIndexer(client, indexer.Priority, indexer.Categories))
 	}
 	var indexerPool *search.IndexerPool
 	if len(newznabClients) > 0 {
@@ -258,56 +211,142 @@ func runServer(configPath string) error {
 	}
 
 	// === Services ===
-	var downloadManager *download.Manager
-	if sabClient != nil {
-		downloadManager = download.NewManager(sabClient, downloadStore, logger.With("component", "download"))
-	}
-
 	var searcher *search.Searcher
+	var searchCache *search.ResultCache
+	var searchBudget *search.Budget
 	if indexerPool != nil {
-		scorer := search.NewScorer(cfg.Quality.Profiles)
+		scorer := search.NewScorerFrom(profileStore)
+		scorer.SetIndexerPriorities(indexerPool.Priorities())
+		scorer.SetMinSeeders(cfg.Quality.MinSeeders)
+		if downloadManager != nil {
+			var protocols []download.Protocol
+			for _, c := range downloadManager.Clients() {
+				protocols = append(protocols, c.Protocol)
+			}
+			scorer.SetProtocols(protocols)
+		}
 		searcher = search.NewSearcher(indexerPool, scorer, logger.With("component", "search"))
+		searcher.SetBlocklist(downloadStore)
+		searcher.SetAliases(libraryStore)
+		searcher.SetSeasonPackMinMissing(cfg.Search.SeasonPackMinMissing)
+
+		// Daily call budgets and the result cache spare low-quota indexers
+		limits := make(map[string]int)
+		minAges := make(map[string]time.Duration)
+		for name, indexer := range cfg.Indexers {
+			if indexer.DailyLimit > 0 {
+				limits[name] = indexer.DailyLimit
+			}
+			if indexer.MinimumAgeMinutes > 0 {
+				minAges[name] = time.Duration(indexer.MinimumAgeMinutes) * time.Minute
+			}
+		}
+		scorer.SetMinimumAge(time.Duration(cfg.Search.MinimumAgeMinutes)*time.Minute, minAges)
+		searchBudget = search.NewBudget(limits)
+		indexerPool.SetBudget(searchBudget)
+		if cfg.Search.CacheTTL >= 0 {
+			searchCache = search.NewResultCache(cfg.Search.CacheTTL, cfg.Search.CacheMaxEntries)
+			searcher.SetCache(searchCache)
+		}
+		if cfg.Search.CacheFile != "" {
+			if err := search.LoadState(cfg.Search.CacheFile, searchCache, searchBudget); err != nil {
+				logger.Warn("search state not restored", "path", cfg.Search.CacheFile, "error", err)
+			}
+		}
+	}
+
+	// Root folders for new content, shared so round_robin rotates across both APIs
+	movieRoots := library.NewRootFolders(cfg.Libraries.Movies.Paths(), cfg.Libraries.Movies.RootStrategy)
+	seriesRoots := library.NewRootFolders(cfg.Libraries.Series.Paths(), cfg.Libraries.Series.RootStrategy)
+	books := cfg.Libraries.Audiobooks
+	if !books.Enabled {
+		books = config.AudiobookLibraryConfig{} // No root: audiobooks can't be added or imported
 	}
+	bookRoots := library.NewRootFolders(books.Paths(), books.RootStrategy)
 
 	// Create importer
 	imp := importer.New(db, importer.Config{
-		MovieRoot:      cfg.Libraries.Movies.Root,
-		SeriesRoot:     cfg.Libraries.Series.Root,
-		MovieTemplate:  cfg.Libraries.Movies.Naming,
-		SeriesTemplate: cfg.Libraries.Series.Naming,
-		PlexURL:        plexURLFromConfig(cfg),
-		PlexToken:      plexTokenFromConfig(cfg),
-		PlexLocalPath:  plexLocalPathFromConfig(cfg),
-		PlexRemotePath: plexRemotePathFromConfig(cfg),
+		MovieRoot:        cfg.Libraries.Movies.Root,
+		SeriesRoot:       cfg.Libraries.Series.Root,
+		MovieRoots:       cfg.Libraries.Movies.Roots,
+		SeriesRoots:      cfg.Libraries.Series.Roots,
+		MovieTemplate:    cfg.Libraries.Movies.Naming,
+		SeriesTemplate:   cfg.Libraries.Series.Naming,
+		PlexURL:          plexURLFromConfig(cfg),
+		PlexToken:        plexTokenFromConfig(cfg),
+		PlexLocalPath:    plexLocalPathFromConfig(cfg),
+		PlexRemotePath:   plexRemotePathFromConfig(cfg),
+		PlexScanWindow:   plexScanWindowFromConfig(cfg),
+		PlexScanMaxPaths: plexScanMaxPathsFromConfig(cfg),
+		WatchDir:         cfg.Importer.WatchDir,
+		WatchAutoAdd:     cfg.Importer.AutoAdd,
+		PostImportHook:   cfg.Importer.PostImportHook,
+		HookTimeout:      cfg.Importer.HookTimeout,
+		VerifyChecksum:   cfg.Importer.VerifyChecksum,
+		MinPartRatio:     cfg.Importer.MinPartRatio,
+
+		SeasonPackConcurrency: cfg.Importer.SeasonPackConcurrency,
+
+		Subtitles:         cfg.Importer.ShouldImportSubtitles(),
+		SubtitleLanguages: cfg.Importer.SubtitleLanguages,
+
+		AudiobookRoot:     books.Root,
+		AudiobookRoots:    books.Roots,
+		AudiobookTemplate: books.Naming,
 	}, logger.With("component", "importer"))
 
 	// === Background Jobs ===
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	var jobs sync.WaitGroup // Background jobs; shutdown waits for them to exit
 
 	// === Event-Driven Runner ===
 	var eventBus *events.Bus
 	var eventLog *events.EventLog
+	var runner *server.Runner
 
-	if sabClient != nil {
+	// Progress samples recorded by the client adapters, for download speed
+	// charts and smoothed ETAs
+	var downloadSamples *download.Samples
+	if downloadManager != nil && cfg.Downloaders.ProgressSamples >= 0 {
+		downloadSamples = download.NewSamples(cfg.Downloaders.ProgressSamples)
+	}
+
+	stuckThresholds, stuckAlertThresholds := cfg.Downloads.Thresholds()
+	if downloadManager != nil {
 		// Create plex checker adapter if plex is configured
 		var plexChecker plex.Checker
 		if plexClient != nil {
 			plexChecker = &plexCheckerAdapter{client: plexClient, lib: libraryStore}
 		}
 
-		runner := server.NewRunner(db, server.Config{
-			SABnzbdPollInterval: sabPollInterval(cfg),
-			PlexPollInterval:    plexPollInterval(cfg),
-			DownloadRoot:        sabDownloadRoot(cfg),
-			DownloadRemotePath:  sabRemotePath(cfg),
-			DownloadLocalPath:   sabLocalPath(cfg),
-			CleanupEnabled:      cfg.Importer.ShouldCleanupSource(),
-		}, logger, sabClient, imp, plexChecker)
+		runnerCfg := server.Config{
+			Clients:          runnerClients,
+			PlexPollInterval: plexPollInterval(cfg),
+			DownloadRoot:     downloadRoot(cfg),
+			CleanupEnabled:   cfg.Importer.ShouldCleanupSource(),
+			PreCleanupHook:   importer.NewHook(importer.HookPreCleanup, cfg.Importer.PreCleanupHook, cfg.Importer.HookTimeout),
+			GrabFallbacks:    cfg.Downloaders.GrabFallbacks,
+			Outbox:           cfg.Server.ShouldUseEventOutbox(),
+			Samples:          downloadSamples,
+			TorrentDefaults:  torrentDefaults(cfg),
+
+			StuckAlertThresholds: stuckAlertThresholds,
+			Junk:                 junkRules(cfg),
+
+			ImportConcurrency: cfg.Importer.Concurrency,
+		}
+		if indexerPool != nil {
+			runnerCfg.GrabURLs = indexerPool
+		}
+		runner = server.NewRunner(db, runnerCfg, logger, downloadManager, imp, plexChecker)
 
 		eventBus = runner.Start()
+		imp.SetEvents(eventBus)
 		eventLog = runner.EventLog()
+		jobs.Add(1)
 		go func() {
+			defer jobs.Done()
 			if err := runner.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
 				logger.Error("runner error", "error", err)
 			}
@@ -317,21 +356,184 @@ func runServer(configPath string) error {
 		eventLog = events.NewEventLog(db)
 	}
 
-	// === HTTP Setup ===
-	mux := http.NewServeMux()
+	// Media server scans after imports, coalesced per library section
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		if err := imp.RunScans(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error("media server scan scheduler error", "error", err)
+		}
+	}()
 
-	// Build quality profiles map for API
-	profiles := make(map[string][]string)
-	for name := range cfg.Quality.Profiles {
-		profiles[name] = cfg.Quality.Profiles[name].Resolution
+	// Import watch directory (publishes ImportCompleted like manual imports)
+	if cfg.Importer.WatchDir != "" {
+		watcher := importer.NewWatcher(imp, func(ctx context.Context, wi *importer.WatchedImport) {
+			if eventBus == nil {
+				return
+			}
+			_ = eventBus.Publish(ctx, &events.ImportCompleted{
+				BaseEvent:  events.NewBaseEvent(events.EventImportCompleted, events.EntityDownload, wi.DownloadID),
+				DownloadID: wi.DownloadID,
+				ContentID:  wi.ContentID,
+				EpisodeID:  wi.EpisodeID,
+				FilePath:   wi.Result.DestPath,
+				FileSize:   wi.Result.SizeBytes,
+			})
+		})
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := watcher.Run(ctx, importWatchInterval(cfg)); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("import watcher error", "error", err)
+			}
+		}()
+	}
+
+	// Bandwidth schedule (interface left nil without a download client)
+	var apiSpeed v1.SpeedLimiter
+	if downloadManager != nil {
+		windows := make([]download.BandwidthWindow, 0, len(cfg.Bandwidth.Schedule))
+		for i, w := range cfg.Bandwidth.Schedule {
+			win, err := download.ParseBandwidthWindow(w.From, w.To, w.Limit)
+			if err != nil {
+				return fmt.Errorf("bandwidth.schedule[%d]: %w", i, err)
+			}
+			windows = append(windows, win)
+		}
+		speedScheduler := download.NewSpeedScheduler(downloadManager, windows, logger.With("component", "bandwidth"))
+		apiSpeed = speedScheduler
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := speedScheduler.Run(ctx, bandwidthInterval); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("bandwidth scheduler error", "error", err)
+			}
+		}()
+	}
+
+	// Periodic search for missing movies and episodes (needs indexers and the event bus to grab)
+	var wantedSearcher *search.WantedSearcher
+	if searcher != nil && eventBus != nil {
+		wantedSearcher = search.NewWantedSearcher(searcher, libraryStore, downloadStore, eventBus, logger.With("component", "wanted"))
+		wantedSearcher.SetSeries(libraryStore)
+		wantedSearcher.SetPins(libraryStore)
+		wantedSearcher.SetCutoffLister(libraryStore)
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := wantedSearcher.Run(ctx, wantedSearchInterval); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("wanted search error", "error", err)
+			}
+		}()
+
+		// Content whose pinned release failed is searched for as usual
+		pinFailures := eventBus.Subscribe(events.EventPinnedGrabFailed, 100)
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := wantedSearcher.RunPinFallback(ctx, pinFailures); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("pinned release fallback error", "error", err)
+			}
+		}()
+	}
+
+	// Frequent check of the indexers' newest releases for recently aired episodes
+	if interval := recentSyncInterval(cfg); indexerPool != nil && eventBus != nil && interval > 0 {
+		recentSync := search.NewRecentSync(searcher, indexerPool, libraryStore, downloadStore, eventBus, cfg.Search.RecentWindow, logger.With("component", "recent"))
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := recentSync.Run(ctx, interval); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("recent sync error", "error", err)
+			}
+		}()
+	}
+
+	// Background check for library files deleted outside arrgo
+	reconcileInterval := fileReconcileInterval(cfg)
+	if reconcileInterval > 0 {
+		var reconcileBus importer.Publisher // Left nil without the event bus
+		if eventBus != nil {
+			reconcileBus = eventBus
+		}
+		reconciler := importer.NewReconciler(libraryStore, reconcileBus, cfg.Importer.ReconcileBatch, logger.With("component", "reconcile"))
+		if cfg.Importer.ResearchMissing && wantedSearcher != nil {
+			reconciler.SetResearch(func(ctx context.Context, contentID int64) {
+				if _, err := wantedSearcher.SearchContent(ctx, contentID); err != nil {
+					logger.Warn("search for missing file failed", "content_id", contentID, "error", err)
+				}
+			})
+		}
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := reconciler.Run(ctx, reconcileInterval); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("file reconcile error", "error", err)
+			}
+		}()
 	}
 
+	// === HTTP Setup ===
+	mux := http.NewServeMux()
+	httpMetrics := requestlog.NewMetrics()
+
 	// Build indexer list for API
 	apiIndexers := make([]v1.IndexerAPI, 0, len(newznabClients))
 	for _, c := range newznabClients {
 		apiIndexers = append(apiIndexers, c)
 	}
 
+	// === Metadata Providers ===
+	var tvdbSvc *metadata.TVDBService
+	if cfg.TVDB != nil && cfg.TVDB.APIKey != "" {
+		tvdbClient := tvdb.New(cfg.TVDB.APIKey, tvdb.WithLogger(logger))
+		metadataCache := metadata.NewCache(db)
+		tvdbSvc = metadata.NewTVDBService(tvdbClient, metadataCache, logger.With("component", "tvdb"))
+	}
+
+	var tmdbClient *tmdb.Client
+	if cfg.TMDB != nil && cfg.TMDB.APIKey != "" {
+		tmdbClient = tmdb.NewClient(cfg.TMDB.APIKey, tmdb.WithLogger(logger))
+	}
+
+	// Content metadata refresher (only with at least one provider;
+	// interfaces are left nil rather than holding typed nils)
+	var contentMetadata *metadata.ContentRefresher
+	var apiMetadata v1.MetadataRefresher
+	if tmdbClient != nil || tvdbSvc != nil {
+		var movies metadata.MovieProvider
+		if tmdbClient != nil {
+			movies = tmdbClient
+		}
+		var series metadata.SeriesProvider
+		if tvdbSvc != nil {
+			series = tvdbSvc
+		}
+		contentMetadata = metadata.NewContentRefresher(libraryStore, movies, series, logger.With("component", "metadata"))
+		apiMetadata = contentMetadata
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := contentMetadata.Run(ctx, metadataRefreshInterval, metadataMaxAge); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("metadata refresh error", "error", err)
+			}
+		}()
+	}
+
+	// TVDB episode sync (picks up new episodes and seasons of continuing series)
+	var apiEpisodes v1.EpisodeSyncer
+	if tvdbSvc != nil {
+		episodeSyncer := metadata.NewEpisodeSyncer(libraryStore, tvdbSvc, eventBus, logger.With("component", "episodes"))
+		apiEpisodes = episodeSyncer
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := episodeSyncer.Run(ctx, episodeSyncInterval, episodeSyncJitter); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("episode sync error", "error", err)
+			}
+		}()
+	}
+
 	// Native API v1
 	apiV1, err := v1.NewWithDeps(v1.ServerDeps{
 		Library:   libraryStore,
@@ -344,57 +546,71 @@ func runServer(configPath string) error {
 		Bus:       eventBus,
 		EventLog:  eventLog,
 		Indexers:  apiIndexers,
+		Metadata:  apiMetadata,
+		Episodes:  apiEpisodes,
+		Speed:     apiSpeed,
+		Metrics:   httpMetrics,
+		Profiles:  profileStore,
+		Samples:   downloadSamples,
+		Uploads:   uploads,
+		DB:        db,
 	}, v1.Config{
-		MovieRoot:       cfg.Libraries.Movies.Root,
-		SeriesRoot:      cfg.Libraries.Series.Root,
-		DownloadRoot:    sabDownloadRoot(cfg),
-		QualityProfiles: profiles,
+		MovieRoot:     cfg.Libraries.Movies.Root,
+		SeriesRoot:    cfg.Libraries.Series.Root,
+		MovieRoots:    movieRoots,
+		SeriesRoots:   seriesRoots,
+		DownloadRoot:  downloadRoot(cfg),
+		Categories:    clientCategories(runnerClients),
+		PublicStatus:  cfg.Server.PublicStatus,
+		ClientPaths:   clientPaths(runnerClients),
+		AdoptExisting: plexAdoptExisting(cfg),
+
+		FilesReconciled: reconcileInterval > 0,
+		StuckThresholds: stuckThresholds,
+		Junk:            junkRules(cfg),
+		OrphanMinAge:    cfg.Downloads.OrphanMinAge(),
+
+		Audiobooks:     books.Enabled,
+		AudiobookRoot:  books.Root,
+		AudiobookRoots: bookRoots,
 	})
 	if err != nil {
 		return fmt.Errorf("create api: %w", err)
 	}
 
 	// Wire TVDB to v1 API if configured
-	var tvdbSvc *metadata.TVDBService
-	if cfg.TVDB != nil && cfg.TVDB.APIKey != "" {
-		tvdbClient := tvdb.New(cfg.TVDB.APIKey, tvdb.WithLogger(logger))
-		metadataCache := metadata.NewCache(db)
-		tvdbSvc = metadata.NewTVDBService(tvdbClient, metadataCache, logger.With("component", "tvdb"))
+	if tvdbSvc != nil {
 		apiV1.SetTVDB(tvdbSvc)
 		logger.Info("TVDB integration enabled")
 	}
+	if tmdbClient != nil {
+		apiV1.SetTMDB(tmdbClient)
+	}
 
+	apiV1.SetContext(ctx)
 	apiV1.RegisterRoutes(mux)
 
 	// Compat API (if enabled)
 	if cfg.Compat.Radarr || cfg.Compat.Sonarr {
-		// Build quality profile ID map (sorted for deterministic IDs across restarts)
-		names := make([]string, 0, len(cfg.Quality.Profiles))
-		for name := range cfg.Quality.Profiles {
-			names = append(names, name)
-		}
-		sort.Strings(names)
-		profileIDs := make(map[string]int)
-		for i, name := range names {
-			profileIDs[name] = i + 1
-		}
-
 		compatCfg := compat.Config{
-			APIKey:          cfg.Compat.APIKey,
-			MovieRoot:       cfg.Libraries.Movies.Root,
-			SeriesRoot:      cfg.Libraries.Series.Root,
-			QualityProfiles: profileIDs,
+			APIKey:      cfg.Compat.APIKey,
+			MovieRoot:   cfg.Libraries.Movies.Root,
+			SeriesRoot:  cfg.Libraries.Series.Root,
+			MovieRoots:  movieRoots,
+			SeriesRoots: seriesRoots,
 		}
 		apiCompat := compat.New(compatCfg, libraryStore, downloadStore, logger.With("component", "compat"))
 		apiCompat.SetSearcher(searcher)
 		apiCompat.SetManager(downloadManager)
+		apiCompat.SetHistory(historyStore)
+		apiCompat.SetProfiles(profileStore)
+		apiCompat.SetContext(ctx)
 		if eventBus != nil {
 			apiCompat.SetBus(eventBus)
 		}
 
 		// Wire TMDB client if configured
-		if cfg.TMDB != nil && cfg.TMDB.APIKey != "" {
-			tmdbClient := tmdb.NewClient(cfg.TMDB.APIKey, tmdb.WithLogger(logger))
+		if tmdbClient != nil {
 			apiCompat.SetTMDB(tmdbClient)
 			logger.Info("TMDB client configured")
 		}
@@ -405,6 +621,14 @@ func runServer(configPath string) error {
 			logger.Info("TVDB wired to compat API")
 		}
 
+		if contentMetadata != nil {
+			apiCompat.SetMetadata(contentMetadata)
+		}
+
+		if plexClient != nil && plexAdoptExisting(cfg) {
+			apiCompat.SetPlex(plexClient)
+		}
+
 		apiCompat.RegisterRoutes(mux)
 	}
 
@@ -413,16 +637,24 @@ func runServer(configPath string) error {
 	logger.Info("server starting",
 		"addr", addr,
 		"database", cfg.Database.Path,
-		"sabnzbd", sabClient != nil,
+		"download_clients", len(runnerClients),
 		"indexers", len(cfg.Indexers),
 		"plex", plexClient != nil,
+		"api_keys", len(cfg.Server.APIKeys),
 		"log_level", cfg.Server.LogLevel,
 	)
 
 	// === HTTP Server ===
+	// State-changing API requests are audited in the event log
+	handler := requestlog.Audit(mux, eventLog, logger.With("component", "audit"))
+	// With API keys configured, the native API needs one; the key is resolved
+	// before auditing so entries name it
+	if len(cfg.Server.APIKeys) > 0 {
+		handler = auth.Middleware(handler, apiKeys(cfg), "/api/v1/", v1.PublicPathPrefix, logger.With("component", "auth"))
+	}
 	srv := &http.Server{
 		Addr:              addr,
-		Handler:           logRequests(mux, logger),
+		Handler:           requestlog.Middleware(handler, logger.With("component", "http"), httpMetrics, cfg.Server.SlowRequestThreshold),
 		ReadHeaderTimeout: 10 * time.Second, // Prevent Slowloris attacks
 	}
 
@@ -439,21 +671,81 @@ func runServer(configPath string) error {
 	sig := <-sigCh
 	logger.Info("received signal, shutting down", "signal", sig.String())
 
-	// Cancel background jobs (this stops the poller)
+	// Cancel background jobs (this stops the pollers); in-flight imports
+	// keep running until the grace period ends
 	cancel()
 
-	// Graceful HTTP shutdown with 30s timeout
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Graceful HTTP shutdown and import drain share the grace period
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer shutdownCancel()
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		return fmt.Errorf("shutdown: %w", err)
+	shutdownErr := srv.Shutdown(shutdownCtx)
+	if runner != nil {
+		if err := runner.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("in-flight imports canceled; they will be retried", "error", err)
+		}
+	}
+
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(jobsDone)
+	}()
+	select {
+	case <-jobsDone:
+	case <-time.After(jobStopTimeout):
+		logger.Warn("background jobs did not stop in time")
+	}
+
+	if shutdownErr != nil {
+		return fmt.Errorf("shutdown: %w", shutdownErr)
+	}
+
+	if cfg.Search.CacheFile != "" && searcher != nil {
+		if err := search.SaveState(cfg.Search.CacheFile, searchCache, searchBudget); err != nil {
+			logger.Warn("search state not saved", "path", cfg.Search.CacheFile, "error", err)
+		}
 	}
 
 	logger.Info("server stopped")
 	return nil
 }
 
+// openProfiles returns the quality profile store. The database store is
+// seeded from config when empty; config profiles it doesn't have are
+// logged, since editing them in config.toml no longer takes effect.
+func openProfiles(cfg *config.Config, db *sql.DB, logger *slog.Logger) (*profile.Store, error) {
+	if cfg.Quality.LegacyProfiles {
+		return profile.NewConfigStore(cfg.Quality.Profiles), nil
+	}
+	store := profile.NewStore(db)
+	n, err := store.Seed(cfg.Quality.Profiles)
+	if err != nil {
+		return nil, fmt.Errorf("quality profiles: %w", err)
+	}
+	if n > 0 {
+		logger.Info("quality profiles seeded from config", "count", n)
+		return store, nil
+	}
+	for name := range cfg.Quality.Profiles {
+		if _, ok := store.Profile(name); !ok {
+			logger.Warn("quality profile in config is not in the database; add it through the API or set quality.legacy_profiles", "profile", name)
+		}
+	}
+	return store, nil
+}
+
+// logConfigWarnings logs non-fatal config issues found at startup.
+func logConfigWarnings(logger *slog.Logger, issues []config.Issue) {
+	for _, issue := range issues {
+		args := []any{"key", issue.Key}
+		if issue.Line > 0 {
+			args = append(args, "line", issue.Line)
+		}
+		logger.Warn("config: "+issue.Message, args...)
+	}
+}
+
 func plexURLFromConfig(cfg *config.Config) string {
 	if cfg.Notifications.Plex != nil {
 		return cfg.Notifications.Plex.URL
@@ -482,36 +774,98 @@ func plexRemotePathFromConfig(cfg *config.Config) string {
 	return ""
 }
 
-// sabDownloadRoot returns the local path for SABnzbd downloads.
-func sabDownloadRoot(cfg *config.Config) string {
-	if cfg.Downloaders.SABnzbd != nil && cfg.Downloaders.SABnzbd.LocalPath != "" {
-		return cfg.Downloaders.SABnzbd.LocalPath
+func plexScanWindowFromConfig(cfg *config.Config) time.Duration {
+	if cfg.Notifications.Plex != nil {
+		return cfg.Notifications.Plex.ScanWindow
 	}
-	return ""
+	return 0
 }
 
-// sabRemotePath returns the remote path prefix as seen by SABnzbd.
-func sabRemotePath(cfg *config.Config) string {
-	if cfg.Downloaders.SABnzbd != nil {
-		return cfg.Downloaders.SABnzbd.RemotePath
+func plexScanMaxPathsFromConfig(cfg *config.Config) int {
+	if cfg.Notifications.Plex != nil {
+		return cfg.Notifications.Plex.ScanMaxPaths
 	}
-	return ""
+	return 0
 }
 
-// sabLocalPath returns the local path prefix for SABnzbd downloads.
-func sabLocalPath(cfg *config.Config) string {
+// downloadClientNames returns configured download client names in routing order:
+// the legacy [downloaders.sabnzbd] client first, then named clients alphabetically.
+func downloadClientNames(cfg *config.Config) []string {
+	named := make([]string, 0, len(cfg.Downloaders.Clients))
+	for name := range cfg.Downloaders.Clients {
+		if name != config.ClientTypeSABnzbd || cfg.Downloaders.SABnzbd == nil {
+			named = append(named, name)
+		}
+	}
+	sort.Strings(named)
+
 	if cfg.Downloaders.SABnzbd != nil {
-		return cfg.Downloaders.SABnzbd.LocalPath
+		return append([]string{config.ClientTypeSABnzbd}, named...)
 	}
-	return ""
+	return named
 }
 
-// sabPollInterval returns the SABnzbd poll interval, defaulting to 5 seconds.
-func sabPollInterval(cfg *config.Config) time.Duration {
-	if cfg.Downloaders.SABnzbd != nil && cfg.Downloaders.SABnzbd.PollInterval > 0 {
-		return cfg.Downloaders.SABnzbd.PollInterval
+// clientCategories maps each download client to its categories.
+func clientCategories(clients []server.ClientConfig) map[download.Client]download.Categories {
+	categories := make(map[download.Client]download.Categories, len(clients))
+	for _, c := range clients {
+		categories[c.Name] = c.Categories
 	}
-	return 5 * time.Second
+	return categories
+}
+
+// clientPaths maps each client's download path prefix to the local one.
+func clientPaths(clients []server.ClientConfig) map[download.Client]importer.PathMapping {
+	paths := make(map[download.Client]importer.PathMapping, len(clients))
+	for _, c := range clients {
+		paths[c.Name] = importer.PathMapping{Remote: c.RemotePath, Local: c.LocalPath}
+	}
+	return paths
+}
+
+// torrentDefaults returns the torrent options of grabs that don't choose them.
+func torrentDefaults(cfg *config.Config) download.TorrentOptions {
+	qb := cfg.Downloaders.QBittorrent
+	if qb == nil {
+		return download.TorrentOptions{}
+	}
+	return download.TorrentOptions{
+		Sequential:         qb.SequentialDownload,
+		FirstLastPiecePrio: qb.FirstLastPiecePrio,
+	}
+}
+
+// junkRules returns the leftovers that don't keep a release directory from
+// being removed after import or cancel.
+func junkRules(cfg *config.Config) importer.JunkRules {
+	rules := importer.DefaultJunkRules()
+	if cfg.Downloads.JunkPatterns != nil {
+		rules.Patterns = cfg.Downloads.JunkPatterns
+	}
+	switch kb := cfg.Downloads.JunkTextMaxKB; {
+	case kb < 0:
+		rules.TextMaxSize = 0
+	case kb > 0:
+		rules.TextMaxSize = int64(kb) << 10
+	}
+	return rules
+}
+
+// downloadRoot returns the local download path of the first client that has one.
+// It bounds source cleanup and locates completed downloads for tracked imports.
+func downloadRoot(cfg *config.Config) string {
+	all := cfg.Downloaders.All()
+	for _, name := range downloadClientNames(cfg) {
+		if all[name].LocalPath != "" {
+			return all[name].LocalPath
+		}
+	}
+	return ""
+}
+
+// plexAdoptExisting returns whether movies Plex already has are adopted when added.
+func plexAdoptExisting(cfg *config.Config) bool {
+	return cfg.Notifications.Plex != nil && cfg.Notifications.Plex.ShouldAdoptExisting()
 }
 
 // plexPollInterval returns the Plex poll interval, defaulting to 60 seconds.
@@ -522,6 +876,64 @@ func plexPollInterval(cfg *config.Config) time.Duration {
 	return 60 * time.Second
 }
 
+// downloadRetryPolicy returns the download client retry policy, with unset values
+// taken from the default policy.
+func downloadRetryPolicy(cfg *config.Config) download.RetryPolicy {
+	p := download.DefaultRetryPolicy()
+	r := cfg.Downloaders.Retry
+	if r.MaxAttempts > 0 {
+		p.MaxAttempts = r.MaxAttempts
+	}
+	if r.InitialDelay > 0 {
+		p.InitialDelay = r.InitialDelay
+	}
+	if r.MaxDelay > 0 {
+		p.MaxDelay = r.MaxDelay
+	}
+	return p
+}
+
+// importWatchInterval returns the import watch directory scan interval, defaulting to 1 minute.
+func importWatchInterval(cfg *config.Config) time.Duration {
+	if cfg.Importer.WatchInterval > 0 {
+		return cfg.Importer.WatchInterval
+	}
+	return time.Minute
+}
+
+// fileReconcileInterval returns the time between file reconcile batches,
+// defaulting to 1 minute; 0 means disabled.
+func fileReconcileInterval(cfg *config.Config) time.Duration {
+	switch {
+	case cfg.Importer.ReconcileInterval < 0:
+		return 0
+	case cfg.Importer.ReconcileInterval > 0:
+		return cfg.Importer.ReconcileInterval
+	}
+	return time.Minute
+}
+
+// apiKeys returns the named API keys of the native API.
+func apiKeys(cfg *config.Config) *auth.Keys {
+	keys := make([]auth.Key, 0, len(cfg.Server.APIKeys))
+	for name, k := range cfg.Server.APIKeys {
+		keys = append(keys, auth.Key{Name: name, Scope: auth.Scope(k.Scope), Values: k.Values})
+	}
+	return auth.NewKeys(keys)
+}
+
+// recentSyncInterval returns the time between recent release syncs,
+// defaulting to 15 minutes; 0 means disabled.
+func recentSyncInterval(cfg *config.Config) time.Duration {
+	switch {
+	case cfg.Search.RecentInterval < 0:
+		return 0
+	case cfg.Search.RecentInterval > 0:
+		return cfg.Search.RecentInterval
+	}
+	return 15 * time.Minute
+}
+
 // plexCheckerAdapter adapts PlexClient to the plex.Checker interface.
 type plexCheckerAdapter struct {
 	client *importer.PlexClient
@@ -533,9 +945,49 @@ func (a *plexCheckerAdapter) HasContentByID(ctx context.Context, contentID int64
 	if err != nil {
 		return false, "", err
 	}
-	// Use appropriate finder based on content type
-	if content.Type == library.ContentTypeSeries {
-		return a.client.FindShow(ctx, content.Title)
+	// Audiobooks are in a music section, which isn't searched; treat them as
+	// present so cleanup isn't held back waiting for them
+	if content.Type == library.ContentTypeAudiobook {
+		return true, "", nil
+	}
+	// The item of a stored rating key is fetched directly. Search only without
+	// one or once Plex no longer has that item, deleted or matched again.
+	if content.PlexKey != "" {
+		_, err := a.client.GetItem(ctx, content.PlexKey)
+		if err == nil {
+			return true, content.PlexKey, nil
+		}
+		if !errors.Is(err, importer.ErrPlexItemNotFound) {
+			return false, "", err
+		}
+	}
+	// Plex may list the content under an alias, such as its original title
+	titles, err := a.lib.Titles(content)
+	if err != nil {
+		titles = []string{content.Title}
+	}
+	for _, title := range titles {
+		// Use appropriate finder based on content type
+		var found bool
+		var key string
+		if content.Type == library.ContentTypeSeries {
+			found, key, err = a.client.FindShow(ctx, title)
+		} else {
+			found, key, err = a.client.FindMovie(ctx, title, content.Year)
+		}
+		if err != nil {
+			return false, "", err
+		}
+		if found {
+			// Stored for next time; a failure only means searching again
+			if key != content.PlexKey {
+				_ = a.lib.SetPlexKey(content.ID, key)
+			}
+			return true, key, nil
+		}
+	}
+	if content.PlexKey != "" {
+		_ = a.lib.SetPlexKey(content.ID, "")
 	}
-	return a.client.FindMovie(ctx, content.Title, content.Year)
+	return false, "", nil
 }
@@ -337,6 +337,7 @@ func setupPlexTestDB(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL,
 			release_name TEXT NOT NULL,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT NOT NULL,
 			added_at TIMESTAMP NOT NULL,
 			completed_at TIMESTAMP,
@@ -346,7 +347,10 @@ func setupPlexTestDB(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
 		);
 		CREATE TABLE download_episodes (
 			download_id INTEGER NOT NULL,
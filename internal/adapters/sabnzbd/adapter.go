@@ -14,11 +14,18 @@ import (
 
 // Config for the SABnzbd adapter.
 type Config struct {
+	Client     download.Client // Client name recorded on downloads it polls (default: sabnzbd)
 	Interval   time.Duration
-	RemotePath string // Path prefix as seen by SABnzbd (e.g., /data/usenet)
-	LocalPath  string // Local path prefix (e.g., /srv/data/usenet)
+	Timeout    time.Duration     // Bound on each poll of the client (default: 1m)
+	RemotePath string            // Path prefix as seen by SABnzbd (e.g., /data/usenet)
+	LocalPath  string            // Local path prefix (e.g., /srv/data/usenet)
+	Samples    *download.Samples // Records each poll's progress (optional)
 }
 
+// DefaultPollTimeout bounds a single poll when Config.Timeout is unset, so a
+// hung client cannot stall the poll loop.
+const DefaultPollTimeout = time.Minute
+
 // Adapter polls SABnzbd and emits events for status changes.
 type Adapter struct {
 	client download.Downloader
@@ -36,6 +43,12 @@ func New(bus *events.Bus, client download.Downloader, store *download.Store, cfg
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if cfg.Client == "" {
+		cfg.Client = download.ClientSABnzbd
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultPollTimeout
+	}
 	return &Adapter{
 		client:     client,
 		bus:        bus,
@@ -83,8 +96,11 @@ func (a *Adapter) Start(ctx context.Context) error {
 
 // poll retrieves tracked downloads and checks their status.
 func (a *Adapter) poll(ctx context.Context) {
-	// Get active SABnzbd downloads from store (no pagination - poll all)
-	client := download.ClientSABnzbd
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	// Get this client's active downloads from store (no pagination - poll all)
+	client := a.config.Client
 	downloads, _, err := a.store.List(download.Filter{
 		Client: &client,
 		Active: true,
@@ -95,6 +111,11 @@ func (a *Adapter) poll(ctx context.Context) {
 	}
 
 	for _, dl := range downloads {
+		if ctx.Err() != nil {
+			a.logger.Warn("poll interrupted", "error", ctx.Err())
+			return
+		}
+
 		// Skip downloads already in terminal states
 		if isTerminalStatus(dl.Status) {
 			continue
@@ -105,6 +126,8 @@ func (a *Adapter) poll(ctx context.Context) {
 }
 
 // checkDownload queries the client for status and emits appropriate events.
+// Downloads are matched to client jobs by ClientID (SABnzbd's nzo_id) alone:
+// job names change when SABnzbd deduplicates them or a user renames them.
 func (a *Adapter) checkDownload(ctx context.Context, dl *download.Download) {
 	status, err := a.client.Status(ctx, dl.ClientID)
 	if err != nil {
@@ -126,6 +149,8 @@ func (a *Adapter) checkDownload(ctx context.Context, dl *download.Download) {
 
 // processStatus compares status and emits appropriate events.
 func (a *Adapter) processStatus(ctx context.Context, dl *download.Download, status *download.ClientStatus) {
+	a.refreshReleaseName(ctx, dl, status.Name)
+
 	// Check if this is a state transition we've already emitted
 	lastStatus, seen := a.lastStatus[dl.ID]
 	if seen && lastStatus == status.Status {
@@ -145,7 +170,12 @@ func (a *Adapter) processStatus(ctx context.Context, dl *download.Download, stat
 
 	case download.StatusFailed:
 		if !seen || lastStatus != download.StatusFailed {
-			a.emitFailed(ctx, dl, "download reported failed by client", true)
+			reason := status.Error
+			if reason == "" {
+				reason = "download reported failed by client"
+			}
+			// Missing articles fail the same way on every grab of this release
+			a.emitFailed(ctx, dl, reason, !download.IsMissingArticles(reason))
 			a.lastStatus[dl.ID] = download.StatusFailed
 		}
 
@@ -155,6 +185,40 @@ func (a *Adapter) processStatus(ctx context.Context, dl *download.Download, stat
 	}
 }
 
+// refreshReleaseName records the job name the client reports when it differs
+// from the download's release name, so paths built from the name (the
+// download root fallback of imports, cleanup, orphans) follow the job.
+func (a *Adapter) refreshReleaseName(ctx context.Context, dl *download.Download, name string) {
+	if name == "" || name == dl.ReleaseName {
+		return
+	}
+	if err := a.store.SetReleaseName(dl.ID, name); err != nil {
+		a.logger.Error("failed to record renamed download",
+			"download_id", dl.ID,
+			"error", err)
+		return
+	}
+	oldName := dl.ReleaseName
+	dl.ReleaseName = name
+
+	evt := &events.DownloadRenamed{
+		BaseEvent:  events.NewBaseEvent(events.EventDownloadRenamed, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		OldName:    oldName,
+		NewName:    name,
+	}
+	if err := a.bus.Publish(ctx, evt); err != nil {
+		a.logger.Error("failed to publish DownloadRenamed event",
+			"download_id", dl.ID,
+			"error", err)
+	}
+
+	a.logger.Info("download renamed by client",
+		"download_id", dl.ID,
+		"old_name", oldName,
+		"new_name", name)
+}
+
 // processDisappeared handles when a download is no longer in the client.
 func (a *Adapter) processDisappeared(ctx context.Context, dl *download.Download) {
 	// Only emit if we haven't already marked it as failed
@@ -168,6 +232,8 @@ func (a *Adapter) processDisappeared(ctx context.Context, dl *download.Download)
 
 // emitCompleted transitions the download to completed and publishes a DownloadCompleted event.
 func (a *Adapter) emitCompleted(ctx context.Context, dl *download.Download, status *download.ClientStatus) {
+	a.dropSamples(dl.ID)
+
 	// Transition status before emitting event - ImportHandler requires completed status
 	if err := a.store.Transition(dl, download.StatusCompleted); err != nil {
 		a.logger.Error("failed to transition download to completed",
@@ -196,8 +262,11 @@ func (a *Adapter) emitCompleted(ctx context.Context, dl *download.Download, stat
 		"path", localPath)
 }
 
-// emitFailed transitions the download to failed and publishes a DownloadFailed event.
+// emitFailed transitions the download to failed, records the reason on it,
+// and publishes a DownloadFailed event.
 func (a *Adapter) emitFailed(ctx context.Context, dl *download.Download, reason string, retryable bool) {
+	a.dropSamples(dl.ID)
+
 	// Transition status before emitting event
 	if err := a.store.Transition(dl, download.StatusFailed); err != nil {
 		a.logger.Error("failed to transition download to failed",
@@ -205,6 +274,12 @@ func (a *Adapter) emitFailed(ctx context.Context, dl *download.Download, reason
 			"error", err)
 		return
 	}
+	if err := a.store.SetLastError(dl.ID, reason); err != nil {
+		a.logger.Error("failed to record download failure reason",
+			"download_id", dl.ID,
+			"error", err)
+	}
+	dl.LastError = reason
 
 	evt := &events.DownloadFailed{
 		BaseEvent:  events.NewBaseEvent(events.EventDownloadFailed, events.EntityDownload, dl.ID),
@@ -251,6 +326,15 @@ func (a *Adapter) emitProgressed(ctx context.Context, dl *download.Download, sta
 		}
 	}
 
+	if a.config.Samples != nil {
+		a.config.Samples.Record(dl.ID, download.Sample{
+			At:       time.Now(),
+			Progress: status.Progress,
+			Speed:    status.Speed,
+			Size:     status.Size,
+		})
+	}
+
 	// Update progress in database
 	if err := a.store.UpdateProgress(dl.ID, status.Progress, status.Speed, int64(status.ETA.Seconds()), status.Size); err != nil {
 		a.logger.Error("failed to update download progress",
@@ -279,11 +363,18 @@ func (a *Adapter) emitProgressed(ctx context.Context, dl *download.Download, sta
 		"speed", status.Speed)
 }
 
+// dropSamples forgets the progress samples of a download that is done.
+func (a *Adapter) dropSamples(downloadID int64) {
+	if a.config.Samples != nil {
+		a.config.Samples.Drop(downloadID)
+	}
+}
+
 // isTerminalStatus returns true if the status is a terminal state
 // from the perspective of the SABnzbd adapter (i.e., no further polling needed).
 func isTerminalStatus(s download.Status) bool {
 	switch s {
-	case download.StatusCompleted, download.StatusImporting, download.StatusImported, download.StatusCleaned, download.StatusFailed:
+	case download.StatusCompleted, download.StatusImporting, download.StatusImported, download.StatusPartiallyImported, download.StatusCleaned, download.StatusFailed:
 		return true
 	default:
 		return false
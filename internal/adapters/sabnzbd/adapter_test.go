@@ -2,8 +2,6 @@ package sabnzbd
 
 import (
 	"context"
-	"database/sql"
-	_ "embed"
 	"log/slog"
 	"testing"
 	"time"
@@ -13,37 +11,12 @@ import (
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/download/mocks"
 	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/testutil"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
 	"go.uber.org/mock/gomock"
 	_ "modernc.org/sqlite"
 )
 
-//go:embed testdata/schema.sql
-var testSchema string
-
-func setupTestDB(t *testing.T) *sql.DB {
-	t.Helper()
-	db, err := sql.Open("sqlite", ":memory:?_foreign_keys=on")
-	require.NoError(t, err)
-	t.Cleanup(func() { _ = db.Close() })
-
-	_, err = db.Exec(testSchema)
-	require.NoError(t, err)
-	return db
-}
-
-// insertTestContent inserts a test content row and returns its ID.
-func insertTestContent(t *testing.T, db *sql.DB) int64 {
-	t.Helper()
-	result, err := db.Exec(`
-		INSERT INTO content (type, title, year, status, quality_profile, root_path)
-		VALUES ('movie', 'Test Movie', 2000, 'wanted', 'hd', '/movies')`,
-	)
-	require.NoError(t, err)
-	id, err := result.LastInsertId()
-	require.NoError(t, err)
-	return id
-}
-
 func TestAdapter_Name(t *testing.T) {
 	adapter := &Adapter{}
 	assert.Equal(t, "sabnzbd", adapter.Name())
@@ -53,7 +26,7 @@ func TestAdapter_EmitsDownloadCompleted(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockClient := mocks.NewMockDownloader(ctrl)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
 	bus := events.NewBus(nil, slog.Default())
 	t.Cleanup(func() { _ = bus.Close() })
@@ -62,7 +35,7 @@ func TestAdapter_EmitsDownloadCompleted(t *testing.T) {
 	completedCh := bus.Subscribe(events.EventDownloadCompleted, 10)
 
 	// Create tracked download in store
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 	dl := &download.Download{
 		ContentID:   contentID,
 		Client:      download.ClientSABnzbd,
@@ -111,7 +84,7 @@ func TestAdapter_EmitsDownloadProgressed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockClient := mocks.NewMockDownloader(ctrl)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
 	bus := events.NewBus(nil, slog.Default())
 	t.Cleanup(func() { _ = bus.Close() })
@@ -120,7 +93,7 @@ func TestAdapter_EmitsDownloadProgressed(t *testing.T) {
 	progressCh := bus.Subscribe(events.EventDownloadProgressed, 10)
 
 	// Create tracked download in store
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 	dl := &download.Download{
 		ContentID:   contentID,
 		Client:      download.ClientSABnzbd,
@@ -169,11 +142,103 @@ func TestAdapter_EmitsDownloadProgressed(t *testing.T) {
 	}
 }
 
+func TestAdapter_RefreshesRenamedJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mocks.NewMockDownloader(ctrl)
+
+	db := testutil.OpenTestDB(t)
+	store := download.NewStore(db)
+	bus := events.NewBus(nil, slog.Default())
+	t.Cleanup(func() { _ = bus.Close() })
+	renamedCh := bus.Subscribe(events.EventDownloadRenamed, 10)
+
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
+	dl := &download.Download{
+		ContentID:   contentID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "nzo_dup",
+		Status:      download.StatusDownloading,
+		ReleaseName: "Test.Movie.2024.1080p.WEB-DL",
+		Indexer:     "nzbgeek",
+	}
+	require.NoError(t, store.Add(dl))
+
+	// SABnzbd deduplicated the job name; it is matched by nzo_id regardless
+	mockClient.EXPECT().
+		Status(gomock.Any(), "nzo_dup").
+		Return(&download.ClientStatus{
+			ID:       "nzo_dup",
+			Name:     "Test.Movie.2024.1080p.WEB-DL.1",
+			Status:   download.StatusDownloading,
+			Progress: 10,
+		}, nil).
+		Times(2)
+
+	adapter := New(bus, mockClient, store, Config{Interval: time.Hour}, slog.Default())
+	adapter.poll(context.Background())
+	adapter.poll(context.Background())
+
+	select {
+	case evt := <-renamedCh:
+		renamed := evt.(*events.DownloadRenamed)
+		assert.Equal(t, dl.ID, renamed.DownloadID)
+		assert.Equal(t, "Test.Movie.2024.1080p.WEB-DL", renamed.OldName)
+		assert.Equal(t, "Test.Movie.2024.1080p.WEB-DL.1", renamed.NewName)
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("timed out waiting for DownloadRenamed event")
+	}
+	select {
+	case <-renamedCh:
+		t.Fatal("renamed once only")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	got, err := store.Get(dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Test.Movie.2024.1080p.WEB-DL.1", got.ReleaseName)
+}
+
+func TestAdapter_RecordsSamples(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mocks.NewMockDownloader(ctrl)
+
+	db := testutil.OpenTestDB(t)
+	store := download.NewStore(db)
+	bus := events.NewBus(nil, slog.Default())
+	t.Cleanup(func() { _ = bus.Close() })
+
+	dl := fixtures.NewDownload().ForContent(fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID).
+		WithClient(download.ClientSABnzbd, "nzo_samples").Insert(t, db)
+
+	gomock.InOrder(
+		mockClient.EXPECT().Status(gomock.Any(), "nzo_samples").
+			Return(&download.ClientStatus{ID: "nzo_samples", Status: download.StatusDownloading, Progress: 10, Speed: 1000, Size: 5000}, nil),
+		mockClient.EXPECT().Status(gomock.Any(), "nzo_samples").
+			Return(&download.ClientStatus{ID: "nzo_samples", Status: download.StatusDownloading, Progress: 20, Speed: 2000, Size: 5000}, nil),
+		mockClient.EXPECT().Status(gomock.Any(), "nzo_samples").
+			Return(&download.ClientStatus{ID: "nzo_samples", Status: download.StatusCompleted, Progress: 100, Path: "/downloads/done"}, nil),
+	)
+
+	samples := download.NewSamples(0)
+	adapter := New(bus, mockClient, store, Config{Interval: time.Hour, Samples: samples}, slog.Default())
+	ctx := t.Context()
+
+	adapter.poll(ctx)
+	adapter.poll(ctx)
+	got := samples.Get(dl.ID)
+	require.Len(t, got, 2)
+	assert.InDelta(t, 10.0, got[0].Progress, 0.001)
+	assert.Equal(t, int64(2000), got[1].Speed)
+
+	adapter.poll(ctx)
+	assert.Nil(t, samples.Get(dl.ID), "samples dropped once the download completes")
+}
+
 func TestAdapter_EmitsDownloadFailed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockClient := mocks.NewMockDownloader(ctrl)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
 	bus := events.NewBus(nil, slog.Default())
 	t.Cleanup(func() { _ = bus.Close() })
@@ -182,7 +247,7 @@ func TestAdapter_EmitsDownloadFailed(t *testing.T) {
 	failedCh := bus.Subscribe(events.EventDownloadFailed, 10)
 
 	// Create tracked download in store
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 	dl := &download.Download{
 		ContentID:   contentID,
 		Client:      download.ClientSABnzbd,
@@ -224,11 +289,62 @@ func TestAdapter_EmitsDownloadFailed(t *testing.T) {
 	}
 }
 
+func TestAdapter_EmitsDownloadFailed_WithClientReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mocks.NewMockDownloader(ctrl)
+
+	db := testutil.OpenTestDB(t)
+	store := download.NewStore(db)
+	bus := events.NewBus(nil, slog.Default())
+	t.Cleanup(func() { _ = bus.Close() })
+
+	failedCh := bus.Subscribe(events.EventDownloadFailed, 10)
+
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
+	dl := &download.Download{
+		ContentID:   contentID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "nzo_fail789",
+		Status:      download.StatusDownloading,
+		ReleaseName: "Test.Movie.2024.1080p.WEB-DL",
+		Indexer:     "nzbgeek",
+	}
+	require.NoError(t, store.Add(dl))
+
+	reason := "Aborted, cannot be completed - https://sabnzbd.org/not-complete"
+	mockClient.EXPECT().
+		Status(gomock.Any(), "nzo_fail789").
+		Return(&download.ClientStatus{
+			ID:     "nzo_fail789",
+			Name:   "Test.Movie.2024.1080p.WEB-DL",
+			Status: download.StatusFailed,
+			Error:  reason,
+		}, nil)
+
+	adapter := New(bus, mockClient, store, Config{Interval: time.Hour}, slog.Default())
+	adapter.poll(context.Background())
+
+	select {
+	case evt := <-failedCh:
+		failed, ok := evt.(*events.DownloadFailed)
+		require.True(t, ok, "expected DownloadFailed event")
+		assert.Equal(t, reason, failed.Reason)
+		assert.False(t, failed.Retryable, "missing articles fail again with the same NZB")
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("timed out waiting for DownloadFailed event")
+	}
+
+	got, err := store.Get(dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, download.StatusFailed, got.Status)
+	assert.Equal(t, reason, got.LastError)
+}
+
 func TestAdapter_EmitsDownloadFailed_WhenDisappeared(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockClient := mocks.NewMockDownloader(ctrl)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
 	bus := events.NewBus(nil, slog.Default())
 	t.Cleanup(func() { _ = bus.Close() })
@@ -237,7 +353,7 @@ func TestAdapter_EmitsDownloadFailed_WhenDisappeared(t *testing.T) {
 	failedCh := bus.Subscribe(events.EventDownloadFailed, 10)
 
 	// Create tracked download in store
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 	dl := &download.Download{
 		ContentID:   contentID,
 		Client:      download.ClientSABnzbd,
@@ -279,7 +395,7 @@ func TestAdapter_NoDuplicateStateTransitionEvents(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockClient := mocks.NewMockDownloader(ctrl)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
 	bus := events.NewBus(nil, slog.Default())
 	t.Cleanup(func() { _ = bus.Close() })
@@ -288,7 +404,7 @@ func TestAdapter_NoDuplicateStateTransitionEvents(t *testing.T) {
 	completedCh := bus.Subscribe(events.EventDownloadCompleted, 10)
 
 	// Create tracked download already completed
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 	dl := &download.Download{
 		ContentID:   contentID,
 		Client:      download.ClientSABnzbd,
@@ -342,13 +458,13 @@ func TestAdapter_OnlyPollsSABnzbdDownloads(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockClient := mocks.NewMockDownloader(ctrl)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
 	bus := events.NewBus(nil, slog.Default())
 	t.Cleanup(func() { _ = bus.Close() })
 
 	// Create a manual download (should be ignored)
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 	manualDL := &download.Download{
 		ContentID:   contentID,
 		Client:      download.ClientManual,
@@ -375,13 +491,13 @@ func TestAdapter_SkipsTerminalStates(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockClient := mocks.NewMockDownloader(ctrl)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
 	bus := events.NewBus(nil, slog.Default())
 	t.Cleanup(func() { _ = bus.Close() })
 
 	// Create downloads in various terminal states
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 	states := []download.Status{
 		download.StatusCompleted,
 		download.StatusImported,
@@ -416,7 +532,7 @@ func TestAdapter_RemapsPath(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockClient := mocks.NewMockDownloader(ctrl)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
 	bus := events.NewBus(nil, slog.Default())
 	t.Cleanup(func() { _ = bus.Close() })
@@ -425,7 +541,7 @@ func TestAdapter_RemapsPath(t *testing.T) {
 	completedCh := bus.Subscribe(events.EventDownloadCompleted, 10)
 
 	// Create tracked download in store
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 	dl := &download.Download{
 		ContentID:   contentID,
 		Client:      download.ClientSABnzbd,
@@ -490,3 +606,67 @@ func TestAdapter_RemapPath_NoMatch(t *testing.T) {
 	// Path that doesn't match remote prefix should pass through unchanged
 	assert.Equal(t, "/other/path/file.mkv", adapter.remapPath("/other/path/file.mkv"))
 }
+
+func TestAdapter_PollsOnlyItsClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mocks.NewMockDownloader(ctrl)
+
+	db := testutil.OpenTestDB(t)
+	store := download.NewStore(db)
+	bus := events.NewBus(nil, slog.Default())
+	t.Cleanup(func() { _ = bus.Close() })
+
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
+	for _, dl := range []*download.Download{
+		{ContentID: contentID, Client: "sab-4k", ClientID: "nzo_mine", Status: download.StatusDownloading, ReleaseName: "Mine"},
+		{ContentID: contentID, Client: download.ClientSABnzbd, ClientID: "nzo_other", Status: download.StatusDownloading, ReleaseName: "Other"},
+	} {
+		require.NoError(t, store.Add(dl))
+	}
+
+	// Only the download recorded against this adapter's client is queried
+	mockClient.EXPECT().
+		Status(gomock.Any(), "nzo_mine").
+		Return(&download.ClientStatus{ID: "nzo_mine", Status: download.StatusDownloading, Progress: 10}, nil)
+
+	adapter := New(bus, mockClient, store, Config{Client: "sab-4k", Interval: time.Hour}, slog.Default())
+	adapter.poll(context.Background())
+}
+
+func TestAdapter_PollTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mocks.NewMockDownloader(ctrl)
+
+	db := testutil.OpenTestDB(t)
+	store := download.NewStore(db)
+	bus := events.NewBus(nil, slog.Default())
+	t.Cleanup(func() { _ = bus.Close() })
+
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
+	for _, id := range []string{"nzo_hung1", "nzo_hung2"} {
+		require.NoError(t, store.Add(&download.Download{
+			ContentID:   contentID,
+			Client:      download.ClientSABnzbd,
+			ClientID:    id,
+			Status:      download.StatusDownloading,
+			ReleaseName: "Test.Movie.2024.1080p.WEB-DL",
+			Indexer:     "nzbgeek",
+		}))
+	}
+
+	// Client never answers; the first status call blocks until the poll deadline
+	// and the rest of the poll is abandoned
+	mockClient.EXPECT().
+		Status(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string) (*download.ClientStatus, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).
+		Times(1)
+
+	adapter := New(bus, mockClient, store, Config{Interval: time.Hour, Timeout: 50 * time.Millisecond}, slog.Default())
+
+	start := time.Now()
+	adapter.poll(context.Background())
+	assert.Less(t, time.Since(start), 2*time.Second, "poll should give up after the configured timeout")
+}
@@ -0,0 +1,147 @@
+// Package auth checks the API keys of the native API and the scope each
+// key grants.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Scope is what an API key may do.
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"  // GET and HEAD requests only
+	ScopeWrite Scope = "write" // Everything but destructive operations
+	ScopeAdmin Scope = "admin" // Everything, including deleting files and canceling downloads
+)
+
+// scopeRank orders scopes; each grants everything the ones below it do.
+var scopeRank = map[Scope]int{ScopeRead: 1, ScopeWrite: 2, ScopeAdmin: 3}
+
+// Valid reports whether s is a known scope.
+func (s Scope) Valid() bool {
+	return scopeRank[s] > 0
+}
+
+// Allows reports whether s grants what required does.
+func (s Scope) Allows(required Scope) bool {
+	return s.Valid() && scopeRank[s] >= scopeRank[required]
+}
+
+// MaxValues is how many values a key may have at once: the current one and,
+// while clients move over, the one replacing it.
+const MaxValues = 2
+
+// Key is a named API key.
+type Key struct {
+	Name   string
+	Scope  Scope
+	Values []string // Any of these authenticates as the key
+}
+
+// Keys resolves presented API keys to the keys configured.
+type Keys struct {
+	keys []Key
+}
+
+// NewKeys returns the configured keys; values that are empty are ignored.
+func NewKeys(keys []Key) *Keys {
+	return &Keys{keys: keys}
+}
+
+// Resolve returns the key one of whose values is value.
+func (k *Keys) Resolve(value string) (Key, bool) {
+	if value == "" {
+		return Key{}, false
+	}
+	for _, key := range k.keys {
+		for _, v := range key.Values {
+			if v != "" && subtle.ConstantTimeCompare([]byte(v), []byte(value)) == 1 {
+				return key, true
+			}
+		}
+	}
+	return Key{}, false
+}
+
+// Presented returns the API key a request carries in the X-Api-Key header or
+// the apikey or api_key query parameter, or "" without one.
+func Presented(r *http.Request) string {
+	if key := r.Header.Get("X-Api-Key"); key != "" {
+		return key
+	}
+	for name, values := range r.URL.Query() {
+		switch strings.ToLower(name) {
+		case "apikey", "api_key":
+			return values[0]
+		}
+	}
+	return ""
+}
+
+type contextKey struct{}
+
+// WithKey returns a copy of ctx carrying the key a request authenticated as.
+func WithKey(ctx context.Context, key Key) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext returns the key a request authenticated as; ok is false when
+// the request wasn't authenticated, as when no keys are configured.
+func FromContext(ctx context.Context) (Key, bool) {
+	key, ok := ctx.Value(contextKey{}).(Key)
+	return key, ok
+}
+
+// Middleware requires an API key for requests whose path starts with prefix,
+// except those under public, and attaches the key to the request context.
+// Requests without a known key get 401; requests that change state with a
+// read key get 403. Refused requests are logged here, since they never reach
+// the handlers or the audit log.
+func Middleware(next http.Handler, keys *Keys, prefix, public string, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, prefix) || (public != "" && strings.HasPrefix(r.URL.Path, public)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		value := Presented(r)
+		key, ok := keys.Resolve(value)
+		if !ok {
+			msg := "invalid API key"
+			if value == "" {
+				msg = "API key required"
+			}
+			logger.Warn("api request refused", "method", r.Method, "path", r.URL.Path, "reason", msg)
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", msg)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && !key.Scope.Allows(ScopeWrite) {
+			logger.Warn("api request refused", "method", r.Method, "path", r.URL.Path, "key", key.Name, "reason", "scope")
+			Forbidden(w, key, ScopeWrite)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithKey(r.Context(), key)))
+	})
+}
+
+// Forbidden writes the 403 for a request whose key lacks the required scope.
+func Forbidden(w http.ResponseWriter, key Key, required Scope) {
+	writeError(w, http.StatusForbidden, "INSUFFICIENT_SCOPE",
+		fmt.Sprintf("requires the %s scope; key %q has %s", required, key.Name, key.Scope))
+}
+
+func writeError(w http.ResponseWriter, code int, errCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}{message, errCode})
+}
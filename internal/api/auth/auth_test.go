@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope_Allows(t *testing.T) {
+	assert.True(t, ScopeAdmin.Allows(ScopeWrite))
+	assert.True(t, ScopeWrite.Allows(ScopeWrite))
+	assert.True(t, ScopeWrite.Allows(ScopeRead))
+	assert.False(t, ScopeWrite.Allows(ScopeAdmin))
+	assert.False(t, ScopeRead.Allows(ScopeWrite))
+	assert.False(t, Scope("").Allows(ScopeRead))
+}
+
+func TestMiddleware(t *testing.T) {
+	keys := NewKeys([]Key{
+		{Name: "widget", Scope: ScopeRead, Values: []string{"r1"}},
+		{Name: "automation", Scope: ScopeAdmin, Values: []string{"new", "old"}},
+	})
+	var got Key
+	var authed bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, authed = FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	h := Middleware(next, keys, "/api/v1/", "/api/v1/public/", slog.New(slog.DiscardHandler))
+
+	do := func(method, target, key string) *httptest.ResponseRecorder {
+		got, authed = Key{}, false
+		req := httptest.NewRequest(method, target, nil)
+		if key != "" {
+			req.Header.Set("X-Api-Key", key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodGet, "/api/v1/content", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "API key required")
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/api/v1/content", "wrong").Code)
+
+	// Either value of a key being rotated works
+	for _, value := range []string{"new", "old"} {
+		require.Equal(t, http.StatusOK, do(http.MethodDelete, "/api/v1/downloads/1", value).Code)
+		assert.True(t, authed)
+		assert.Equal(t, "automation", got.Name)
+		assert.Equal(t, ScopeAdmin, got.Scope)
+	}
+
+	// A read key may only look
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/content", "r1").Code)
+	assert.Equal(t, "widget", got.Name)
+	w = do(http.MethodPost, "/api/v1/content", "r1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `requires the write scope; key \"widget\" has read`)
+
+	// The query parameter works too
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/content?apikey=r1", "").Code)
+
+	// Public and other paths need no key
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/public/summary", "").Code)
+	assert.False(t, authed)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v3/movie", "").Code)
+}
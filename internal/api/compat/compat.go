@@ -4,20 +4,28 @@ package compat
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
 	"github.com/vmunix/arrgo/internal/metadata"
+	"github.com/vmunix/arrgo/internal/profile"
 	"github.com/vmunix/arrgo/internal/search"
 	"github.com/vmunix/arrgo/internal/tmdb"
+	"github.com/vmunix/arrgo/pkg/release"
 )
 
 // Config holds compat API configuration.
@@ -25,7 +33,9 @@ type Config struct {
 	APIKey          string
 	MovieRoot       string
 	SeriesRoot      string
-	QualityProfiles map[string]int // name -> id mapping
+	MovieRoots      *library.RootFolders // Picks the root for new movies (nil: always MovieRoot)
+	SeriesRoots     *library.RootFolders // Picks the root for new series (nil: always SeriesRoot)
+	QualityProfiles map[string]int       // name -> id mapping; unused once SetProfiles is called
 }
 
 // radarrAddRequest is the Radarr format for adding a movie.
@@ -60,21 +70,38 @@ type radarrMovieResponse struct {
 	FolderName       string `json:"folderName"`
 	TitleSlug        string `json:"titleSlug"`
 	QualityProfileID int    `json:"qualityProfileId"`
+	MinAvailability  string `json:"minimumAvailability"`
 	Tags             []int  `json:"tags"`
 	Added            string `json:"added"`
+	// On add: the file Plex already had, adopted instead of searching (arrgo extension)
+	ExistingFile string `json:"existingFile,omitempty"`
 }
 
 // sonarrSeason represents a season in Sonarr format.
 type sonarrSeason struct {
-	SeasonNumber int  `json:"seasonNumber"`
-	Monitored    bool `json:"monitored"`
-	Statistics   *struct {
-		EpisodeFileCount  int `json:"episodeFileCount"`
-		EpisodeCount      int `json:"episodeCount"`
-		TotalEpisodeCount int `json:"totalEpisodeCount"`
-		SizeOnDisk        int `json:"sizeOnDisk"`
-		PercentOfEpisodes int `json:"percentOfEpisodes"`
-	} `json:"statistics,omitempty"`
+	SeasonNumber int                     `json:"seasonNumber"`
+	Monitored    bool                    `json:"monitored"`
+	Statistics   *sonarrSeasonStatistics `json:"statistics,omitempty"`
+}
+
+// sonarrSeasonStatistics is the Sonarr statistics block for a season.
+type sonarrSeasonStatistics struct {
+	PreviousAiring    string  `json:"previousAiring,omitempty"`
+	EpisodeFileCount  int     `json:"episodeFileCount"`
+	EpisodeCount      int     `json:"episodeCount"`
+	TotalEpisodeCount int     `json:"totalEpisodeCount"`
+	SizeOnDisk        int64   `json:"sizeOnDisk"`
+	PercentOfEpisodes float64 `json:"percentOfEpisodes"`
+}
+
+// sonarrSeriesStatistics is the Sonarr statistics block for a series.
+type sonarrSeriesStatistics struct {
+	SeasonCount       int     `json:"seasonCount"`
+	EpisodeFileCount  int     `json:"episodeFileCount"`
+	EpisodeCount      int     `json:"episodeCount"`
+	TotalEpisodeCount int     `json:"totalEpisodeCount"`
+	SizeOnDisk        int64   `json:"sizeOnDisk"`
+	PercentOfEpisodes float64 `json:"percentOfEpisodes"`
 }
 
 // sonarrSeriesResponse is the full Sonarr format for a series.
@@ -94,29 +121,22 @@ type sonarrSeriesResponse struct {
 		CoverType string `json:"coverType"`
 		URL       string `json:"url"`
 	} `json:"images,omitempty"`
-	SeriesType        string   `json:"seriesType"`
-	Monitored         bool     `json:"monitored"`
-	QualityProfileID  int      `json:"qualityProfileId"`
-	LanguageProfileID int      `json:"languageProfileId"`
-	SeasonFolder      bool     `json:"seasonFolder"`
-	Path              string   `json:"path,omitempty"`
-	RootFolderPath    string   `json:"rootFolderPath,omitempty"`
-	TitleSlug         string   `json:"titleSlug"`
-	Certification     string   `json:"certification,omitempty"`
-	Genres            []string `json:"genres,omitempty"`
-	Tags              []int    `json:"tags"`
-	Added             string   `json:"added,omitempty"`
-	FirstAired        string   `json:"firstAired,omitempty"`
-	CleanTitle        string   `json:"cleanTitle"`
-	ImdbID            string   `json:"imdbId,omitempty"`
-	Statistics        *struct {
-		SeasonCount       int `json:"seasonCount"`
-		EpisodeFileCount  int `json:"episodeFileCount"`
-		EpisodeCount      int `json:"episodeCount"`
-		TotalEpisodeCount int `json:"totalEpisodeCount"`
-		SizeOnDisk        int `json:"sizeOnDisk"`
-		PercentOfEpisodes int `json:"percentOfEpisodes"`
-	} `json:"statistics,omitempty"`
+	SeriesType        string                  `json:"seriesType"`
+	Monitored         bool                    `json:"monitored"`
+	QualityProfileID  int                     `json:"qualityProfileId"`
+	LanguageProfileID int                     `json:"languageProfileId"`
+	SeasonFolder      bool                    `json:"seasonFolder"`
+	Path              string                  `json:"path,omitempty"`
+	RootFolderPath    string                  `json:"rootFolderPath,omitempty"`
+	TitleSlug         string                  `json:"titleSlug"`
+	Certification     string                  `json:"certification,omitempty"`
+	Genres            []string                `json:"genres,omitempty"`
+	Tags              []int                   `json:"tags"`
+	Added             string                  `json:"added,omitempty"`
+	FirstAired        string                  `json:"firstAired,omitempty"`
+	CleanTitle        string                  `json:"cleanTitle"`
+	ImdbID            string                  `json:"imdbId,omitempty"`
+	Statistics        *sonarrSeriesStatistics `json:"statistics,omitempty"`
 }
 
 // sonarrAddRequest is the Sonarr format for adding a series (full Overseerr format).
@@ -147,11 +167,23 @@ type Server struct {
 	manager      *download.Manager
 	tmdb         *tmdb.Client
 	tvdbSvc      *metadata.TVDBService
+	metadata     *metadata.ContentRefresher
+	plex         *importer.PlexClient
+	history      *importer.HistoryStore
+	profiles     *profile.Store  // Optional: quality profiles (nil: Config.QualityProfiles)
 	bus          *events.Bus     // Optional event bus for event-driven grabs
 	pendingTasks *sync.WaitGroup // Optional WaitGroup for test synchronization
+	baseCtx      context.Context // Parent of background work; canceled on shutdown
 	log          *slog.Logger
 }
 
+// Background work started by requests outlives the request, so it runs
+// under the server's base context with its own deadline.
+const (
+	backgroundSearchTimeout = 2 * time.Minute // Per indexer search (one per season for series)
+	backgroundSyncTimeout   = 2 * time.Minute // TVDB episode sync
+)
+
 // New creates a new compatibility server.
 func New(cfg Config, lib *library.Store, dl *download.Store, log *slog.Logger) *Server {
 	return &Server{
@@ -187,11 +219,79 @@ func (s *Server) SetTVDB(svc *metadata.TVDBService) {
 	s.tvdbSvc = svc
 }
 
+// SetMetadata configures the content metadata refresher (optional).
+func (s *Server) SetMetadata(refresher *metadata.ContentRefresher) {
+	s.metadata = refresher
+}
+
+// SetPlex configures Plex for adopting movies it already has (optional).
+// A movie added that Plex has is marked available with Plex's file and not
+// searched for.
+func (s *Server) SetPlex(client *importer.PlexClient) {
+	s.plex = client
+}
+
+// SetHistory configures the history store behind the Sonarr history
+// endpoints (optional; without it they report no history).
+func (s *Server) SetHistory(history *importer.HistoryStore) {
+	s.history = history
+}
+
+// SetProfiles configures the quality profile store, whose profile IDs
+// replace Config.QualityProfiles (optional).
+func (s *Server) SetProfiles(profiles *profile.Store) {
+	s.profiles = profiles
+}
+
+// qualityProfiles returns quality profile IDs by name.
+func (s *Server) qualityProfiles() map[string]int {
+	if s.profiles == nil {
+		return s.cfg.QualityProfiles
+	}
+	profiles, err := s.profiles.List()
+	if err != nil {
+		s.log.Warn("could not list quality profiles", "error", err)
+		return nil
+	}
+	ids := make(map[string]int, len(profiles))
+	for _, p := range profiles {
+		ids[p.Name] = int(p.ID)
+	}
+	return ids
+}
+
+// SetContext sets the parent context for background searches and syncs
+// started by requests. Canceling it stops that work (default: never canceled).
+func (s *Server) SetContext(ctx context.Context) {
+	s.baseCtx = ctx
+}
+
+// backgroundContext returns a context for background work, derived from
+// the base context and bounded by timeout.
+func (s *Server) backgroundContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	parent := s.baseCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
 // SetPendingWaitGroup sets a WaitGroup for tests to wait on async operations.
 func (s *Server) SetPendingWaitGroup(wg *sync.WaitGroup) {
 	s.pendingTasks = wg
 }
 
+// applyMetadata fills in display metadata before content is added.
+// Failures are logged; content is still added without metadata.
+func (s *Server) applyMetadata(ctx context.Context, c *library.Content) {
+	if s.metadata == nil {
+		return
+	}
+	if _, err := s.metadata.Apply(ctx, c); err != nil {
+		s.log.Warn("metadata lookup failed", "title", c.Title, "error", err)
+	}
+}
+
 // RegisterRoutes registers compatibility API routes.
 func (s *Server) RegisterRoutes(mux *http.ServeMux) {
 	// System endpoints (used by Overseerr to test connection)
@@ -218,6 +318,7 @@ func (s *Server) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/v3/series", s.authMiddleware(s.addSeries))
 	mux.HandleFunc("PUT /api/v3/series", s.authMiddleware(s.updateSeries))
 	mux.HandleFunc("GET /api/v3/languageprofile", s.authMiddleware(s.listLanguageProfiles))
+	mux.HandleFunc("GET /api/v3/history/since", s.authMiddleware(s.historySince))
 }
 
 // authMiddleware validates the X-Api-Key header.
@@ -293,26 +394,79 @@ func (s *Server) lookupMovie(w http.ResponseWriter, r *http.Request) {
 	// Parse tmdb:12345 format
 	var tmdbID int64
 	if _, err := fmt.Sscanf(term, "tmdb:%d", &tmdbID); err != nil {
-		// Not a TMDB lookup, return empty
-		writeJSON(w, http.StatusOK, []any{})
+		// Not a TMDB lookup, search by title
+		s.lookupMovieByTitle(w, r, term)
 		return
 	}
 
 	// Check if we have this movie
-	contents, _, err := s.library.ListContent(library.ContentFilter{TMDBID: &tmdbID, Limit: 1})
-	if err == nil && len(contents) > 0 {
-		// Found in library - return with ID
-		// For "wanted" items (no file yet), return monitored=false so Overseerr
-		// sends a PUT to re-enable monitoring, which triggers a search.
-		resp := s.contentToRadarrMovie(contents[0])
-		if contents[0].Status == library.StatusWanted {
-			resp.Monitored = false
-		}
+	if resp, ok := s.libraryMovie(tmdbID); ok {
 		writeJSON(w, http.StatusOK, []radarrMovieResponse{resp})
 		return
 	}
 
 	// Not in library - fetch metadata from TMDB if available
+	var movie *tmdb.Movie
+	if s.tmdb != nil {
+		// On error, continue with stub - graceful degradation
+		movie, _ = s.tmdb.GetMovie(r.Context(), tmdbID)
+	}
+
+	writeJSON(w, http.StatusOK, []map[string]any{movieLookupStub(tmdbID, movie)})
+}
+
+// maxLookupResults caps the results of a title lookup.
+const maxLookupResults = 10
+
+// lookupMovieByTitle answers a movie lookup with a plain title term by
+// searching TMDB. Movies already in the library are returned as they are for
+// an ID lookup. Returns an empty list when TMDB is not configured.
+func (s *Server) lookupMovieByTitle(w http.ResponseWriter, r *http.Request, term string) {
+	if s.tmdb == nil {
+		writeJSON(w, http.StatusOK, []any{})
+		return
+	}
+
+	movies, err := s.tmdb.SearchMovies(r.Context(), term)
+	if err != nil {
+		s.log.Warn("TMDB title lookup failed", "term", term, "error", err)
+		writeJSON(w, http.StatusOK, []any{})
+		return
+	}
+	if len(movies) > maxLookupResults {
+		movies = movies[:maxLookupResults]
+	}
+
+	results := make([]any, 0, len(movies))
+	for i := range movies {
+		if resp, ok := s.libraryMovie(movies[i].ID); ok {
+			results = append(results, resp)
+			continue
+		}
+		results = append(results, movieLookupStub(movies[i].ID, &movies[i]))
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// libraryMovie returns the lookup response for a movie already in the
+// library, if there is one.
+func (s *Server) libraryMovie(tmdbID int64) (radarrMovieResponse, bool) {
+	contents, _, err := s.library.ListContent(library.ContentFilter{TMDBID: &tmdbID, Limit: 1})
+	if err != nil || len(contents) == 0 {
+		return radarrMovieResponse{}, false
+	}
+	// For "wanted" items (no file yet), return monitored=false so Overseerr
+	// sends a PUT to re-enable monitoring, which triggers a search.
+	resp := s.contentToRadarrMovie(contents[0])
+	if contents[0].Status == library.StatusWanted {
+		resp.Monitored = false
+	}
+	return resp, true
+}
+
+// movieLookupStub builds the lookup response for a movie not in the library,
+// enriched with TMDB metadata when movie is non-nil.
+func movieLookupStub(tmdbID int64, movie *tmdb.Movie) map[string]any {
 	response := map[string]any{
 		"tmdbId":      tmdbID,
 		"title":       "",
@@ -321,40 +475,38 @@ func (s *Server) lookupMovie(w http.ResponseWriter, r *http.Request) {
 		"hasFile":     false,
 		"isAvailable": false,
 	}
+	if movie == nil {
+		return response
+	}
 
-	// Enrich with TMDB metadata if client configured
-	if s.tmdb != nil {
-		movie, err := s.tmdb.GetMovie(r.Context(), tmdbID)
-		if err == nil {
-			response["title"] = movie.Title
-			response["year"] = movie.Year()
-			response["overview"] = movie.Overview
-			response["runtime"] = movie.Runtime
-			if movie.IMDBID != "" {
-				response["imdbId"] = movie.IMDBID
-			}
-			if movie.PosterPath != "" {
-				response["images"] = []map[string]any{{
-					"coverType": "poster",
-					"url":       movie.PosterURL("w500"),
-				}}
-			}
-			if movie.VoteAverage > 0 {
-				response["ratings"] = map[string]any{
-					"tmdb": map[string]any{
-						"value": movie.VoteAverage,
-						"votes": movie.VoteCount,
-					},
-				}
-			}
-			if len(movie.Genres) > 0 {
-				response["genres"] = movie.Genres
-			}
+	response["title"] = movie.Title
+	response["year"] = movie.Year()
+	response["overview"] = movie.Overview
+	response["titleSlug"] = fmt.Sprintf("%d", tmdbID)
+	if movie.Runtime > 0 {
+		response["runtime"] = movie.Runtime
+	}
+	if movie.IMDBID != "" {
+		response["imdbId"] = movie.IMDBID
+	}
+	if movie.PosterPath != "" {
+		response["images"] = []map[string]any{{
+			"coverType": "poster",
+			"url":       movie.PosterURL("w500"),
+		}}
+	}
+	if movie.VoteAverage > 0 {
+		response["ratings"] = map[string]any{
+			"tmdb": map[string]any{
+				"value": movie.VoteAverage,
+				"votes": movie.VoteCount,
+			},
 		}
-		// On error, continue with stub - graceful degradation
 	}
-
-	writeJSON(w, http.StatusOK, []map[string]any{response})
+	if len(movie.Genres) > 0 {
+		response["genres"] = movie.Genres
+	}
+	return response
 }
 
 func (s *Server) getMovie(w http.ResponseWriter, r *http.Request) {
@@ -383,7 +535,7 @@ func (s *Server) contentToRadarrMovie(c *library.Content) radarrMovieResponse {
 
 	// Determine profile ID from name
 	profileID := 1
-	for name, id := range s.cfg.QualityProfiles {
+	for name, id := range s.qualityProfiles() {
 		if name == c.QualityProfile {
 			profileID = id
 			break
@@ -398,14 +550,15 @@ func (s *Server) contentToRadarrMovie(c *library.Content) radarrMovieResponse {
 		TMDBID:           tmdbID,
 		Title:            c.Title,
 		Year:             c.Year,
-		Monitored:        c.Status == library.StatusWanted || c.Status == library.StatusAvailable,
+		Monitored:        c.Status == library.StatusWanted || c.Status == library.StatusAvailable, // Abandoned is not pending
 		Status:           "released",
 		HasFile:          c.Status == library.StatusAvailable,
-		IsAvailable:      true,
+		IsAvailable:      c.IsAvailable(time.Now()),
 		Path:             path,
 		FolderName:       folderName,
 		TitleSlug:        fmt.Sprintf("%d", tmdbID),
 		QualityProfileID: profileID,
+		MinAvailability:  string(c.MinimumAvailability),
 		Tags:             []int{},
 		Added:            c.AddedAt.Format("2006-01-02T15:04:05Z"),
 	}
@@ -420,7 +573,7 @@ func (s *Server) addMovie(w http.ResponseWriter, r *http.Request) {
 
 	// Map quality profile ID to name
 	profileName := "hd" // default
-	for name, id := range s.cfg.QualityProfiles {
+	for name, id := range s.qualityProfiles() {
 		if id == req.QualityProfileID {
 			profileName = name
 			break
@@ -430,7 +583,7 @@ func (s *Server) addMovie(w http.ResponseWriter, r *http.Request) {
 	// Determine root path
 	rootPath := req.RootFolderPath
 	if rootPath == "" {
-		rootPath = s.cfg.MovieRoot
+		rootPath = s.movieRoots().Select()
 	}
 
 	// Add to library
@@ -444,11 +597,36 @@ func (s *Server) addMovie(w http.ResponseWriter, r *http.Request) {
 		QualityProfile: profileName,
 		RootPath:       rootPath,
 	}
+	if availability, ok := library.ParseMinimumAvailability(req.MinimumAvailability); ok {
+		content.MinimumAvailability = availability
+	}
+	s.applyMetadata(r.Context(), content)
+	if s.rejectExcluded(w, content, "This movie is excluded") {
+		return
+	}
+
+	// A movie Plex already has is available with Plex's file
+	existing := s.findExistingMovie(r.Context(), content)
+	if existing != "" {
+		content.Status = library.StatusAvailable
+	}
 
 	if err := s.library.AddContent(content); err != nil {
+		if errors.Is(err, library.ErrDuplicate) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "This movie has already been added"})
+			return
+		}
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
+	if existing != "" {
+		if err := s.library.AddFile(importer.ExistingMovieFile(content.ID, existing)); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		s.log.Info("movie already in Plex, skipping search",
+			"content_id", content.ID, "title", content.Title, "year", content.Year, "path", existing)
+	}
 
 	// Publish ContentAdded event
 	if s.bus != nil {
@@ -463,12 +641,56 @@ func (s *Server) addMovie(w http.ResponseWriter, r *http.Request) {
 		_ = s.bus.Publish(r.Context(), evt)
 	}
 
-	// Auto-search if requested and searcher available
-	if req.AddOptions.SearchForMovie && s.searcher != nil && s.bus != nil {
-		go s.searchAndGrab(content.ID, req.Title, req.Year, profileName)
+	// Auto-search if requested and searcher available. Movies that haven't reached their
+	// minimum availability are left to the periodic wanted search.
+	if req.AddOptions.SearchForMovie && existing == "" && s.searcher != nil && s.bus != nil {
+		if content.IsAvailable(time.Now()) {
+			go s.searchAndGrab(content.ID, req.Title, req.Year, profileName)
+		} else {
+			s.log.Info("deferring search until minimum availability",
+				"content_id", content.ID, "availability", content.MinimumAvailability, "available_at", content.AvailableAt())
+		}
+	}
+
+	resp := s.contentToRadarrMovie(content)
+	resp.ExistingFile = existing
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// rejectExcluded responds 400 with msg, as Radarr and Sonarr do for an
+// excluded title, and returns true if content's external ID is excluded.
+// Overseerr shows the message instead of retrying the request.
+func (s *Server) rejectExcluded(w http.ResponseWriter, content *library.Content, msg string) bool {
+	e, err := s.library.ContentExclusion(content)
+	if errors.Is(err, library.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return true
 	}
+	s.log.Info("rejected excluded content",
+		"title", content.Title, "year", content.Year, "source", e.Source, "external_id", e.ExternalID, "reason", e.Reason)
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+	return true
+}
 
-	writeJSON(w, http.StatusCreated, s.contentToRadarrMovie(content))
+// findExistingMovie returns the local path of the file Plex has for a movie
+// being added, or "" if Plex isn't configured, doesn't have it, or can't be
+// reached. A failed lookup never blocks adding the movie.
+func (s *Server) findExistingMovie(ctx context.Context, c *library.Content) string {
+	if s.plex == nil {
+		return ""
+	}
+	item, err := s.plex.FindMovieItem(ctx, c.Title, c.Year)
+	if err != nil {
+		s.log.Warn("plex lookup failed", "title", c.Title, "year", c.Year, "error", err)
+		return ""
+	}
+	if item == nil || item.FilePath == "" {
+		return ""
+	}
+	return s.plex.TranslateToLocal(item.FilePath)
 }
 
 func (s *Server) updateMovie(w http.ResponseWriter, r *http.Request) {
@@ -501,7 +723,7 @@ func (s *Server) updateMovie(w http.ResponseWriter, r *http.Request) {
 
 	// Update quality profile if provided
 	if req.QualityProfileID > 0 {
-		for name, id := range s.cfg.QualityProfiles {
+		for name, id := range s.qualityProfiles() {
 			if id == req.QualityProfileID {
 				content.QualityProfile = name
 				break
@@ -530,40 +752,47 @@ func (s *Server) updateMovie(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, s.contentToRadarrMovie(content))
 }
 
+// listRootFolders returns every movie and series root with its free space,
+// so Overseerr can offer a choice.
 func (s *Server) listRootFolders(w http.ResponseWriter, r *http.Request) {
 	folders := []map[string]any{}
 
-	if s.cfg.MovieRoot != "" {
-		folders = append(folders, map[string]any{
-			"id":        1,
-			"path":      s.cfg.MovieRoot,
-			"freeSpace": getFreeSpace(s.cfg.MovieRoot),
-			"type":      "movie",
-		})
-	}
-	if s.cfg.SeriesRoot != "" {
-		folders = append(folders, map[string]any{
-			"id":        2,
-			"path":      s.cfg.SeriesRoot,
-			"freeSpace": getFreeSpace(s.cfg.SeriesRoot),
-			"type":      "series",
-		})
+	id := 0
+	for _, group := range []struct {
+		roots *library.RootFolders
+		typ   string
+	}{
+		{s.movieRoots(), "movie"},
+		{s.seriesRoots(), "series"},
+	} {
+		for _, path := range group.roots.Paths() {
+			id++
+			folders = append(folders, map[string]any{
+				"id":        id,
+				"path":      path,
+				"freeSpace": library.FreeSpace(path),
+				"type":      group.typ,
+			})
+		}
 	}
 
 	writeJSON(w, http.StatusOK, folders)
 }
 
-// getFreeSpace returns the free space in bytes for a given path.
-func getFreeSpace(path string) uint64 {
-	var stat syscall.Statfs_t
-	if err := syscall.Statfs(path, &stat); err != nil {
-		return 0
+// movieRoots returns the configured movie roots, falling back to MovieRoot.
+func (s *Server) movieRoots() *library.RootFolders {
+	if s.cfg.MovieRoots != nil {
+		return s.cfg.MovieRoots
 	}
-	// Bsize is always positive, safe to convert
-	if stat.Bsize < 0 {
-		return 0
+	return library.NewRootFolders([]string{s.cfg.MovieRoot}, "")
+}
+
+// seriesRoots returns the configured series roots, falling back to SeriesRoot.
+func (s *Server) seriesRoots() *library.RootFolders {
+	if s.cfg.SeriesRoots != nil {
+		return s.cfg.SeriesRoots
 	}
-	return stat.Bavail * uint64(stat.Bsize) //nolint:gosec // Bsize checked above
+	return library.NewRootFolders([]string{s.cfg.SeriesRoot}, "")
 }
 
 func (s *Server) listQualityProfiles(w http.ResponseWriter, r *http.Request) {
@@ -577,8 +806,9 @@ func (s *Server) listQualityProfiles(w http.ResponseWriter, r *http.Request) {
 		"uhd":    "Ultra-HD",
 	}
 
-	profiles := make([]map[string]any, 0, len(s.cfg.QualityProfiles))
-	for name, id := range s.cfg.QualityProfiles {
+	ids := s.qualityProfiles()
+	profiles := make([]map[string]any, 0, len(ids))
+	for name, id := range ids {
 		displayName := name
 		if dn, ok := displayNames[strings.ToLower(name)]; ok {
 			displayName = dn
@@ -592,7 +822,25 @@ func (s *Server) listQualityProfiles(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, profiles)
 }
 
+// listQueue handles GET /api/v3/queue for both Radarr and Sonarr clients.
+// Movie downloads carry movieId; series downloads carry seriesId and are
+// listed once per episode, as Sonarr does for season packs, with episodeId,
+// seasonNumber and the episode. Sonarr's seriesId (or seriesIds) and
+// episodeIds parameters narrow the queue; includeUnknownSeriesItems is
+// accepted, but every download belongs to library content.
 func (s *Server) listQueue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	seriesIDs, err := queryIDs(query, "seriesId", "seriesIds")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	episodeIDs, err := queryIDs(query, "episodeIds")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
 	// Note: No pagination for compat API - returns all active for Radarr/Sonarr compatibility
 	downloads, _, err := s.downloads.List(download.Filter{Active: true})
 	if err != nil {
@@ -600,40 +848,117 @@ func (s *Server) listQueue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Live progress from the download clients, if available. Statuses may be
+	// cached briefly; the Age header gives the oldest one's age in seconds.
+	live := make(map[int64]*download.ClientStatus)
+	if s.manager != nil {
+		if active, err := s.manager.GetActive(r.Context()); err == nil {
+			var oldest time.Time
+			for _, a := range active {
+				if a.Live != nil {
+					live[a.Download.ID] = a.Live
+					if oldest.IsZero() || a.LiveAt.Before(oldest) {
+						oldest = a.LiveAt
+					}
+				}
+			}
+			if !oldest.IsZero() {
+				w.Header().Set("Age", strconv.Itoa(int(time.Since(oldest).Seconds())))
+			}
+		}
+	}
+
+	contents, episodes, err := s.queueContent(downloads)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
 	records := make([]map[string]any, 0, len(downloads))
 	for _, dl := range downloads {
+		content := contents[dl.ContentID]
+		isSeries := content != nil && content.Type == library.ContentTypeSeries
+		if len(seriesIDs) > 0 && (!isSeries || !slices.Contains(seriesIDs, dl.ContentID)) {
+			continue
+		}
+
 		record := map[string]any{
 			"id":                    dl.ID,
-			"movieId":               dl.ContentID,
+			"downloadId":            dl.ClientID,
 			"title":                 dl.ReleaseName,
 			"status":                string(dl.Status),
 			"trackedDownloadStatus": "ok",
 			"indexer":               dl.Indexer,
 		}
 
-		// Fetch live progress from download client if available
-		if s.manager != nil {
-			if clientStatus, err := s.manager.Client().Status(r.Context(), dl.ClientID); err == nil && clientStatus != nil {
-				record["size"] = clientStatus.Size
-				record["sizeleft"] = int64(float64(clientStatus.Size) * (100 - clientStatus.Progress) / 100)
-				record["status"] = string(clientStatus.Status)
-
-				// Format timeleft as HH:MM:SS
-				if clientStatus.ETA > 0 {
-					eta := clientStatus.ETA
-					hours := int(eta.Hours())
-					minutes := int(eta.Minutes()) % 60
-					seconds := int(eta.Seconds()) % 60
-					record["timeleft"] = fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
-					record["estimatedCompletionTime"] = time.Now().Add(eta).UTC().Format(time.RFC3339)
-				} else {
-					record["timeleft"] = "00:00:00"
-					record["estimatedCompletionTime"] = time.Now().UTC().Format(time.RFC3339)
-				}
+		if clientStatus := live[dl.ID]; clientStatus != nil {
+			record["size"] = clientStatus.Size
+			record["sizeleft"] = int64(float64(clientStatus.Size) * (100 - clientStatus.Progress) / 100)
+			record["status"] = string(clientStatus.Status)
+
+			// Format timeleft as HH:MM:SS
+			if clientStatus.ETA > 0 {
+				eta := clientStatus.ETA
+				hours := int(eta.Hours())
+				minutes := int(eta.Minutes()) % 60
+				seconds := int(eta.Seconds()) % 60
+				record["timeleft"] = fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+				record["estimatedCompletionTime"] = time.Now().Add(eta).UTC().Format(time.RFC3339)
+			} else {
+				record["timeleft"] = "00:00:00"
+				record["estimatedCompletionTime"] = time.Now().UTC().Format(time.RFC3339)
 			}
+		} else if dl.Size > 0 {
+			// Progress recorded by the last adapter poll
+			record["size"] = dl.Size
+			record["sizeleft"] = int64(float64(dl.Size) * (100 - dl.Progress) / 100)
 		}
 
-		records = append(records, record)
+		if !isSeries {
+			if len(episodeIDs) > 0 {
+				continue
+			}
+			record["movieId"] = dl.ContentID
+			records = append(records, record)
+			continue
+		}
+
+		record["seriesId"] = dl.ContentID
+		ids := dl.EpisodeIDs
+		if len(ids) == 0 && dl.EpisodeID != nil {
+			ids = []int64{*dl.EpisodeID}
+		}
+		if len(ids) == 0 {
+			// Season pack whose episodes are not known yet
+			if len(episodeIDs) > 0 {
+				continue
+			}
+			if dl.Season != nil {
+				record["seasonNumber"] = *dl.Season
+			}
+			records = append(records, record)
+			continue
+		}
+		for _, id := range ids {
+			if len(episodeIDs) > 0 && !slices.Contains(episodeIDs, id) {
+				continue
+			}
+			epRecord := maps.Clone(record)
+			epRecord["episodeId"] = id
+			if ep := episodes[id]; ep != nil {
+				epRecord["seasonNumber"] = ep.Season
+				epRecord["episode"] = map[string]any{
+					"id":            ep.ID,
+					"seriesId":      ep.ContentID,
+					"seasonNumber":  ep.Season,
+					"episodeNumber": ep.Episode,
+					"title":         ep.Title,
+				}
+			} else if dl.Season != nil {
+				epRecord["seasonNumber"] = *dl.Season
+			}
+			records = append(records, epRecord)
+		}
 	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
@@ -644,6 +969,187 @@ func (s *Server) listQueue(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// queueContent loads the content and episodes of the queued downloads,
+// filling in the episode IDs of series downloads, which List leaves out.
+func (s *Server) queueContent(downloads []*download.Download) (map[int64]*library.Content, map[int64]*library.Episode, error) {
+	contents := make(map[int64]*library.Content)
+	episodes := make(map[int64]*library.Episode)
+	if len(downloads) == 0 {
+		return contents, episodes, nil
+	}
+
+	var contentIDs, episodeIDs []int64
+	for _, dl := range downloads {
+		if dl.Season != nil || dl.EpisodeID != nil {
+			full, err := s.downloads.Get(dl.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			dl.EpisodeIDs = full.EpisodeIDs
+		}
+		contentIDs = append(contentIDs, dl.ContentID)
+		episodeIDs = append(episodeIDs, dl.EpisodeIDs...)
+		if dl.EpisodeID != nil {
+			episodeIDs = append(episodeIDs, *dl.EpisodeID)
+		}
+	}
+
+	list, _, err := s.library.ListContent(library.ContentFilter{IDs: contentIDs})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, c := range list {
+		contents[c.ID] = c
+	}
+	if len(episodeIDs) > 0 {
+		eps, _, err := s.library.ListEpisodes(library.EpisodeFilter{IDs: episodeIDs})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, ep := range eps {
+			episodes[ep.ID] = ep
+		}
+	}
+	return contents, episodes, nil
+}
+
+// queryIDs returns the IDs in the named query parameters, each either
+// repeated or comma-separated.
+func queryIDs(query url.Values, names ...string) ([]int64, error) {
+	var ids []int64
+	for _, name := range names {
+		for _, value := range query[name] {
+			for _, part := range strings.Split(value, ",") {
+				part = strings.TrimSpace(part)
+				if part == "" {
+					continue
+				}
+				id, err := strconv.ParseInt(part, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s: %q", name, part)
+				}
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// sonarrHistoryEvents maps history events to Sonarr/Radarr history event
+// types. Events not listed are not reported.
+var sonarrHistoryEvents = map[string]string{
+	importer.EventGrabbed:  "grabbed",
+	importer.EventImported: "downloadFolderImported",
+	importer.EventFailed:   "downloadFailed",
+}
+
+// historySince handles GET /api/v3/history/since?date=...&eventType=...
+// Returns grabs, imports and failures recorded since date, oldest first, in
+// Sonarr's format: series entries carry seriesId and episodeId, movie entries
+// movieId.
+func (s *Server) historySince(w http.ResponseWriter, r *http.Request) {
+	since, err := time.Parse(time.RFC3339, r.URL.Query().Get("date"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "date must be an RFC 3339 time"})
+		return
+	}
+
+	filter := importer.HistoryFilter{Since: &since}
+	if eventType := r.URL.Query().Get("eventType"); eventType != "" {
+		var event string
+		for ours, theirs := range sonarrHistoryEvents {
+			if strings.EqualFold(theirs, eventType) {
+				event = ours
+			}
+		}
+		if event == "" {
+			writeJSON(w, http.StatusOK, []map[string]any{})
+			return
+		}
+		filter.Event = &event
+	}
+
+	records := []map[string]any{}
+	if s.history == nil {
+		writeJSON(w, http.StatusOK, records)
+		return
+	}
+	entries, _, err := s.history.List(filter)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var contentIDs, downloadIDs []int64
+	data := make([]map[string]any, len(entries))
+	for i, h := range entries {
+		contentIDs = append(contentIDs, h.ContentID)
+		_ = json.Unmarshal([]byte(h.Data), &data[i])
+		if id, ok := data[i]["download_id"].(float64); ok {
+			downloadIDs = append(downloadIDs, int64(id))
+		}
+	}
+	contents := make(map[int64]*library.Content)
+	if len(contentIDs) > 0 {
+		list, _, err := s.library.ListContent(library.ContentFilter{IDs: contentIDs})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		for _, c := range list {
+			contents[c.ID] = c
+		}
+	}
+	clientIDs := make(map[int64]string)
+	if len(downloadIDs) > 0 {
+		dls, _, err := s.downloads.List(download.Filter{IDs: downloadIDs})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		for _, dl := range dls {
+			clientIDs[dl.ID] = dl.ClientID
+		}
+	}
+
+	// History is listed newest first; Sonarr returns it oldest first
+	for i := len(entries) - 1; i >= 0; i-- {
+		h := entries[i]
+		eventType, ok := sonarrHistoryEvents[h.Event]
+		if !ok {
+			continue
+		}
+		record := map[string]any{
+			"id":        h.ID,
+			"eventType": eventType,
+			"date":      h.CreatedAt.UTC().Format(time.RFC3339),
+			"data":      map[string]any{},
+		}
+		if title, ok := data[i]["release_name"].(string); ok {
+			record["sourceTitle"] = title
+		}
+		if quality, ok := data[i]["quality"].(string); ok && quality != "" {
+			record["quality"] = map[string]any{"quality": map[string]any{"name": quality}}
+		}
+		if id, ok := data[i]["download_id"].(float64); ok {
+			if clientID := clientIDs[int64(id)]; clientID != "" {
+				record["downloadId"] = clientID
+			}
+		}
+		if c := contents[h.ContentID]; c != nil && c.Type == library.ContentTypeSeries {
+			record["seriesId"] = h.ContentID
+			if h.EpisodeID != nil {
+				record["episodeId"] = *h.EpisodeID
+			}
+		} else {
+			record["movieId"] = h.ContentID
+		}
+		records = append(records, record)
+	}
+
+	writeJSON(w, http.StatusOK, records)
+}
+
 func (s *Server) executeCommand(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Name     string  `json:"name"`
@@ -671,11 +1177,11 @@ func (s *Server) executeCommand(w http.ResponseWriter, r *http.Request) {
 		if s.searcher != nil && s.bus != nil && req.SeriesID > 0 {
 			content, err := s.library.GetContent(req.SeriesID)
 			if err == nil {
-				// Search for season 1 by default (full series search not supported yet)
+				// Search the monitored seasons
 				if s.pendingTasks != nil {
 					s.pendingTasks.Add(1)
 				}
-				go s.searchAndGrabSeries(content.ID, content.Title, content.QualityProfile, []int{1})
+				go s.searchAndGrabSeries(content.ID, content.Title, content.QualityProfile, nil)
 			}
 		}
 	}
@@ -697,9 +1203,24 @@ func (s *Server) listSeries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Episode and file counts of every series come from one aggregate query
+	ids := make([]int64, len(contents))
+	for i, c := range contents {
+		ids[i] = c.ID
+	}
+	stats, err := s.library.GetSeriesStatsBatch(ids)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
 	series := make([]sonarrSeriesResponse, 0, len(contents))
 	for _, c := range contents {
-		series = append(series, s.contentToSonarrSeries(c))
+		st := stats[c.ID]
+		if st == nil {
+			st = &library.SeriesStats{} // No episodes synced yet
+		}
+		series = append(series, s.sonarrSeries(c, st))
 	}
 	writeJSON(w, http.StatusOK, series)
 }
@@ -736,22 +1257,13 @@ func (s *Server) lookupSeries(w http.ResponseWriter, r *http.Request) {
 	// Parse tvdb:12345 format
 	var tvdbID int64
 	if _, err := fmt.Sscanf(term, "tvdb:%d", &tvdbID); err != nil {
-		// Not a TVDB lookup - could be title search, return empty for now
-		writeJSON(w, http.StatusOK, []any{})
+		// Not a TVDB lookup, search by title
+		s.lookupSeriesByTitle(w, r, term)
 		return
 	}
 
 	// Check if we have this series in library
-	contents, _, err := s.library.ListContent(library.ContentFilter{TVDBID: &tvdbID, Limit: 1})
-	if err == nil && len(contents) > 0 {
-		// Found in library - return with ID (signals "already exists")
-		resp := s.contentToSonarrSeries(contents[0])
-		// For "wanted" items, return monitored=false so Overseerr sends PUT to trigger search
-		// (Overseerr skips items with monitored=true, thinking they're already handled)
-		if contents[0].Status == library.StatusWanted {
-			resp.Monitored = false
-		}
-
+	if resp, ok := s.librarySeries(tvdbID); ok {
 		// Enrich with TVDB metadata if year is missing and TVDB is configured
 		if resp.Year == 0 && s.tvdbSvc != nil {
 			series, err := s.tvdbSvc.GetSeries(r.Context(), int(tvdbID))
@@ -768,40 +1280,13 @@ func (s *Server) lookupSeries(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Not in library - return stub that Overseerr can use to add
-	response := sonarrSeriesResponse{
-		TVDBID:            tvdbID,
-		Title:             "",
-		SortTitle:         "",
-		Year:              0,
-		SeasonCount:       1,
-		Seasons:           []sonarrSeason{{SeasonNumber: 1, Monitored: false}},
-		Status:            "continuing",
-		SeriesType:        "standard",
-		Monitored:         false,
-		QualityProfileID:  1,
-		LanguageProfileID: 1,
-		SeasonFolder:      true,
-		TitleSlug:         fmt.Sprintf("tvdb-%d", tvdbID),
-		Tags:              []int{},
-		CleanTitle:        "",
-	}
+	response := seriesLookupStub(tvdbID)
 
 	// Enrich with TVDB metadata if service is configured
 	if s.tvdbSvc != nil {
 		series, err := s.tvdbSvc.GetSeries(r.Context(), int(tvdbID))
 		if err == nil {
-			response.Title = series.Name
-			response.SortTitle = strings.ToLower(series.Name)
-			response.Year = series.Year
-			response.Overview = series.Overview
-			response.CleanTitle = strings.ToLower(strings.ReplaceAll(series.Name, " ", ""))
-
-			// Map TVDB status to Sonarr status format
-			if series.Status == "Ended" {
-				response.Status = "ended"
-			} else {
-				response.Status = "continuing"
-			}
+			applySeriesMetadata(&response, series.Name, series.Year, series.Overview, series.Status)
 
 			// Get episodes to determine accurate season count
 			episodes, err := s.tvdbSvc.GetEpisodes(r.Context(), int(tvdbID))
@@ -831,8 +1316,114 @@ func (s *Server) lookupSeries(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, []sonarrSeriesResponse{response})
 }
 
+// lookupSeriesByTitle answers a series lookup with a plain title term by
+// searching TVDB. Series already in the library are returned as they are for
+// an ID lookup; others get a stub without per-series episode fetches, which
+// would multiply the TVDB calls per lookup. Returns an empty list when TVDB
+// is not configured.
+func (s *Server) lookupSeriesByTitle(w http.ResponseWriter, r *http.Request, term string) {
+	if s.tvdbSvc == nil {
+		writeJSON(w, http.StatusOK, []any{})
+		return
+	}
+
+	// Search results are cached by the TVDB service
+	results, err := s.tvdbSvc.Search(r.Context(), term)
+	if err != nil {
+		s.log.Warn("TVDB title lookup failed", "term", term, "error", err)
+		writeJSON(w, http.StatusOK, []any{})
+		return
+	}
+	if len(results) > maxLookupResults {
+		results = results[:maxLookupResults]
+	}
+
+	series := make([]sonarrSeriesResponse, 0, len(results))
+	for _, result := range results {
+		tvdbID := int64(result.ID)
+		if resp, ok := s.librarySeries(tvdbID); ok {
+			if resp.Year == 0 {
+				resp.Year = result.Year
+			}
+			if resp.Overview == "" {
+				resp.Overview = result.Overview
+			}
+			series = append(series, resp)
+			continue
+		}
+		resp := seriesLookupStub(tvdbID)
+		applySeriesMetadata(&resp, result.Name, result.Year, result.Overview, result.Status)
+		resp.Network = result.Network
+		series = append(series, resp)
+	}
+	writeJSON(w, http.StatusOK, series)
+}
+
+// librarySeries returns the lookup response for a series already in the
+// library, if there is one.
+func (s *Server) librarySeries(tvdbID int64) (sonarrSeriesResponse, bool) {
+	contents, _, err := s.library.ListContent(library.ContentFilter{TVDBID: &tvdbID, Limit: 1})
+	if err != nil || len(contents) == 0 {
+		return sonarrSeriesResponse{}, false
+	}
+	// Found in library - return with ID (signals "already exists")
+	resp := s.contentToSonarrSeries(contents[0])
+	// For "wanted" items, return monitored=false so Overseerr sends PUT to trigger search
+	// (Overseerr skips items with monitored=true, thinking they're already handled)
+	if contents[0].Status == library.StatusWanted {
+		resp.Monitored = false
+	}
+	return resp, true
+}
+
+// seriesLookupStub builds the lookup response for a series not in the library.
+func seriesLookupStub(tvdbID int64) sonarrSeriesResponse {
+	return sonarrSeriesResponse{
+		TVDBID:            tvdbID,
+		Title:             "",
+		SortTitle:         "",
+		Year:              0,
+		SeasonCount:       1,
+		Seasons:           []sonarrSeason{{SeasonNumber: 1, Monitored: false}},
+		Status:            "continuing",
+		SeriesType:        "standard",
+		Monitored:         false,
+		QualityProfileID:  1,
+		LanguageProfileID: 1,
+		SeasonFolder:      true,
+		TitleSlug:         fmt.Sprintf("tvdb-%d", tvdbID),
+		Tags:              []int{},
+		CleanTitle:        "",
+	}
+}
+
+// applySeriesMetadata fills a lookup stub from TVDB series metadata.
+func applySeriesMetadata(resp *sonarrSeriesResponse, name string, year int, overview, status string) {
+	resp.Title = name
+	resp.SortTitle = strings.ToLower(name)
+	resp.Year = year
+	resp.Overview = overview
+	resp.CleanTitle = strings.ToLower(strings.ReplaceAll(name, " ", ""))
+
+	// Map TVDB status to Sonarr status format
+	if status == "Ended" {
+		resp.Status = "ended"
+	} else {
+		resp.Status = "continuing"
+	}
+}
+
 // contentToSonarrSeries converts library content to Sonarr response format.
 func (s *Server) contentToSonarrSeries(c *library.Content) sonarrSeriesResponse {
+	stats, _ := s.library.GetSeriesStats(c.ID) // nil on error, leaving statistics out
+	return s.sonarrSeries(c, stats)
+}
+
+// sonarrSeries converts library content with its episode statistics to Sonarr
+// response format. Overseerr compares each season's episodeFileCount to its
+// totalEpisodeCount to tell available seasons from partially available ones.
+// A nil stats leaves the statistics blocks out.
+func (s *Server) sonarrSeries(c *library.Content, stats *library.SeriesStats) sonarrSeriesResponse {
 	var tvdbID int64
 	if c.TVDBID != nil {
 		tvdbID = *c.TVDBID
@@ -840,7 +1431,7 @@ func (s *Server) contentToSonarrSeries(c *library.Content) sonarrSeriesResponse
 
 	// Determine profile ID from name
 	profileID := 1
-	for name, id := range s.cfg.QualityProfiles {
+	for name, id := range s.qualityProfiles() {
 		if name == c.QualityProfile {
 			profileID = id
 			break
@@ -850,34 +1441,60 @@ func (s *Server) contentToSonarrSeries(c *library.Content) sonarrSeriesResponse
 	// Build path
 	path := fmt.Sprintf("%s/%s", c.RootPath, c.Title)
 
-	// Query episodes to get actual season numbers and availability
-	seasons := []sonarrSeason{}
-	episodes, _, err := s.library.ListEpisodes(library.EpisodeFilter{ContentID: &c.ID})
-	if err == nil && len(episodes) > 0 {
-		// Track season numbers and whether each has any available episodes
-		seasonHasAvailable := make(map[int]bool)
-		for _, ep := range episodes {
-			if ep.Season > 0 {
-				// Initialize season if not seen yet
-				if _, exists := seasonHasAvailable[ep.Season]; !exists {
-					seasonHasAvailable[ep.Season] = false
-				}
-				// Mark season as available if any episode is available
-				if ep.Status == library.StatusAvailable {
-					seasonHasAvailable[ep.Season] = true
-				}
+	// Seasons come from requested season monitoring and from episode stats.
+	// A season is monitored if it was requested or has available episodes;
+	// seasons without either stay unmonitored so Overseerr doesn't think
+	// every season is already requested.
+	seasonMonitored := make(map[int]bool)
+	if recorded, err := s.library.ListSeasons(c.ID); err == nil {
+		for _, ss := range recorded {
+			if ss.Season > 0 {
+				seasonMonitored[ss.Season] = ss.Monitored
 			}
 		}
-		// Build seasons array - only mark as monitored if season has available episodes
-		// This prevents Overseerr from thinking all seasons are already requested
-		for seasonNum, hasAvailable := range seasonHasAvailable {
-			seasons = append(seasons, sonarrSeason{SeasonNumber: seasonNum, Monitored: hasAvailable})
+	}
+	seasonStats := make(map[int]*sonarrSeasonStatistics)
+	var statistics *sonarrSeriesStatistics
+	if stats != nil {
+		statistics = &sonarrSeriesStatistics{}
+		for _, ss := range stats.Seasons {
+			if ss.Season <= 0 {
+				continue
+			}
+			if ss.Available > 0 || ss.Files > 0 {
+				seasonMonitored[ss.Season] = true
+			} else if _, exists := seasonMonitored[ss.Season]; !exists {
+				seasonMonitored[ss.Season] = false
+			}
+			seasonStats[ss.Season] = toSonarrSeasonStatistics(ss)
+			statistics.SeasonCount++
+			statistics.EpisodeFileCount += ss.Files
+			statistics.EpisodeCount += ss.Total
+			statistics.TotalEpisodeCount += ss.Total
+			statistics.SizeOnDisk += ss.SizeBytes
 		}
+		statistics.PercentOfEpisodes = percentOf(statistics.EpisodeFileCount, statistics.EpisodeCount)
+	}
+	seasons := make([]sonarrSeason, 0, len(seasonMonitored))
+	for seasonNum, monitored := range seasonMonitored {
+		seasons = append(seasons, sonarrSeason{SeasonNumber: seasonNum, Monitored: monitored, Statistics: seasonStats[seasonNum]})
 	}
+	sort.Slice(seasons, func(i, j int) bool { return seasons[i].SeasonNumber < seasons[j].SeasonNumber })
 	// Default to 1 season if we don't have episode data
 	if len(seasons) == 0 {
 		seasons = []sonarrSeason{{SeasonNumber: 1, Monitored: false}}
 	}
+	// The seasons TVDB sync knows episodes of; requested seasons without
+	// synced episodes only count until the first sync
+	seasonCount := len(seasons)
+	if statistics != nil && statistics.SeasonCount > 0 {
+		seasonCount = statistics.SeasonCount
+	}
+
+	seriesType := "standard"
+	if c.Daily {
+		seriesType = "daily"
+	}
 
 	return sonarrSeriesResponse{
 		ID:                c.ID,
@@ -885,23 +1502,48 @@ func (s *Server) contentToSonarrSeries(c *library.Content) sonarrSeriesResponse
 		Title:             c.Title,
 		SortTitle:         strings.ToLower(c.Title),
 		Year:              c.Year,
-		SeasonCount:       len(seasons),
+		SeasonCount:       seasonCount,
 		Seasons:           seasons,
 		Status:            "continuing",
-		SeriesType:        "standard",
-		Monitored:         c.Status == library.StatusWanted,
+		SeriesType:        seriesType,
+		Monitored:         c.Status == library.StatusWanted, // Abandoned is not pending
 		QualityProfileID:  profileID,
 		LanguageProfileID: 1,
-		SeasonFolder:      true,
+		SeasonFolder:      c.SeasonFolder == nil || *c.SeasonFolder,
 		Path:              path,
 		RootFolderPath:    c.RootPath,
 		TitleSlug:         fmt.Sprintf("tvdb-%d", tvdbID),
 		Tags:              []int{},
 		Added:             c.AddedAt.Format("2006-01-02T15:04:05Z"),
 		CleanTitle:        strings.ToLower(strings.ReplaceAll(c.Title, " ", "")),
+		Statistics:        statistics,
 	}
 }
 
+// toSonarrSeasonStatistics converts library season stats to the Sonarr statistics block.
+func toSonarrSeasonStatistics(ss library.SeasonStats) *sonarrSeasonStatistics {
+	st := &sonarrSeasonStatistics{
+		EpisodeFileCount:  ss.Files,
+		EpisodeCount:      ss.Total,
+		TotalEpisodeCount: ss.Total,
+		SizeOnDisk:        ss.SizeBytes,
+		PercentOfEpisodes: percentOf(ss.Files, ss.Total),
+	}
+	// The newest air date may be an upcoming episode, which Sonarr reports as nextAiring
+	if ss.LastAirDate != nil && ss.LastAirDate.Before(time.Now()) {
+		st.PreviousAiring = ss.LastAirDate.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	return st
+}
+
+// percentOf returns n as a percentage of total, or 0 if total is 0.
+func percentOf(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
 func (s *Server) addSeries(w http.ResponseWriter, r *http.Request) {
 	var req sonarrAddRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -937,13 +1579,23 @@ func (s *Server) addSeries(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
+		// Newly requested seasons become monitored; earlier requests are kept
+		if req.Monitored && len(monitoredSeasons) > 0 {
+			requested := make(map[int]bool, len(monitoredSeasons))
+			for _, season := range monitoredSeasons {
+				requested[season] = true
+			}
+			if err := s.library.SetSeasonMonitoring(existing.ID, requested); err != nil {
+				s.log.Warn("failed to record season monitoring", "id", existing.ID, "error", err)
+			}
+		}
 		writeJSON(w, http.StatusOK, s.contentToSonarrSeries(existing))
 		return
 	}
 
 	// Map quality profile ID to name
 	profileName := "hd"
-	for name, id := range s.cfg.QualityProfiles {
+	for name, id := range s.qualityProfiles() {
 		if id == req.QualityProfileID {
 			profileName = name
 			break
@@ -953,7 +1605,7 @@ func (s *Server) addSeries(w http.ResponseWriter, r *http.Request) {
 	// Determine root path
 	rootPath := req.RootFolderPath
 	if rootPath == "" {
-		rootPath = s.cfg.SeriesRoot
+		rootPath = s.seriesRoots().Select()
 	}
 
 	// Add to library
@@ -966,13 +1618,29 @@ func (s *Server) addSeries(w http.ResponseWriter, r *http.Request) {
 		Status:         library.StatusWanted,
 		QualityProfile: profileName,
 		RootPath:       rootPath,
+		Daily:          req.SeriesType == "daily",
+	}
+	s.applyMetadata(r.Context(), content)
+	if s.rejectExcluded(w, content, "This series is excluded") {
+		return
 	}
 
 	if err := s.library.AddContent(content); err != nil {
+		if errors.Is(err, library.ErrDuplicate) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "This series has already been added"})
+			return
+		}
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
 
+	// Record which seasons were requested (applied to episodes once synced)
+	if len(req.Seasons) > 0 {
+		if err := s.library.SetSeasonMonitoring(content.ID, seasonMonitoring(req.Seasons)); err != nil {
+			s.log.Warn("failed to record season monitoring", "id", content.ID, "error", err)
+		}
+	}
+
 	// Sync episodes from TVDB if available
 	if s.tvdbSvc != nil && tvdbID > 0 {
 		go s.syncEpisodesFromTVDB(content.ID, int(tvdbID))
@@ -1063,6 +1731,21 @@ func (s *Server) updateSeries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Record and apply per-season monitored flags (unmonitored seasons stop being wanted)
+	if len(req.Seasons) > 0 {
+		if err := s.library.SetSeasonMonitoring(content.ID, seasonMonitoring(req.Seasons)); err != nil {
+			s.log.Warn("failed to record season monitoring", "id", content.ID, "error", err)
+		}
+	}
+	if len(monitoredSeasons) > 0 {
+		changed, err := s.library.BulkUpdateEpisodeStatus(content.ID, library.EpisodeSelection{Seasons: monitoredSeasons})
+		if err != nil {
+			s.log.Warn("failed to apply season monitoring", "id", content.ID, "error", err)
+		} else if len(changed) > 0 {
+			s.log.Debug("applied season monitoring", "id", content.ID, "changed", changed)
+		}
+	}
+
 	// Trigger search for re-request flow (Overseerr sends PUT when re-requesting wanted series)
 	if shouldSearch || req.AddOptions.SearchForMissingEpisodes {
 		// Find which seasons already have available episodes (already downloaded)
@@ -1140,7 +1823,8 @@ func (s *Server) listLanguageProfiles(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// searchAndGrab performs a background search and grabs the best result.
+// searchAndGrab performs a background search and grabs the best result, or
+// grabs the release pinned for the movie.
 func (s *Server) searchAndGrab(contentID int64, title string, year int, profile string) {
 	if s.searcher == nil {
 		s.log.Warn("no searcher configured, cannot search")
@@ -1150,11 +1834,16 @@ func (s *Server) searchAndGrab(contentID int64, title string, year int, profile
 		s.log.Warn("no event bus configured, cannot grab")
 		return
 	}
-	ctx := context.Background()
+	ctx, cancel := s.backgroundContext(backgroundSearchTimeout)
+	defer cancel()
+	if s.grabPinned(ctx, contentID, nil) {
+		return
+	}
 
 	query := search.Query{
-		Text: fmt.Sprintf("%s %d", title, year),
-		Type: "movie",
+		ContentID: contentID,
+		Text:      fmt.Sprintf("%s %d", title, year),
+		Type:      "movie",
 	}
 
 	result, err := s.searcher.Search(ctx, query, profile)
@@ -1172,6 +1861,10 @@ func (s *Server) searchAndGrab(contentID int64, title string, year int, profile
 		DownloadURL: best.DownloadURL,
 		ReleaseName: best.Title,
 		Indexer:     best.Indexer,
+		GUID:        best.GUID,
+		Protocol:    string(best.Protocol),
+		Decision:    search.NewGrabDecision(result, best, profile),
+		Alternates:  search.GrabAlternates(result, best),
 	}); err != nil {
 		s.log.Error("failed to publish GrabRequested", "error", err)
 	}
@@ -1192,48 +1885,130 @@ func (s *Server) searchAndGrabSeries(contentID int64, title string, profile stri
 		s.log.Warn("no event bus configured, cannot grab")
 		return
 	}
-	ctx := context.Background()
 
-	// Default to season 1 if no specific seasons requested
+	// Without explicit seasons, search the seasons the series is monitored for
 	if len(seasons) == 0 {
-		seasons = []int{1}
+		seasons = s.monitoredSeasons(contentID)
+	}
+	if len(seasons) == 0 {
+		s.log.Info("no monitored seasons to search", "content_id", contentID, "title", title)
+		return
 	}
 
 	// Search for each monitored season
 	for _, seasonNum := range seasons {
-		season := seasonNum // Create a copy for the pointer
-		query := search.Query{
-			Text:   fmt.Sprintf("%s S%02d", title, season),
-			Type:   "series",
-			Season: &season, // Signal we want season packs, not individual episodes
+		if !s.searchAndGrabSeason(contentID, title, s.seasonProfile(contentID, seasonNum, profile), seasonNum) {
+			return
 		}
+	}
+}
 
-		result, err := s.searcher.Search(ctx, query, profile)
-		if err != nil || len(result.Releases) == 0 {
-			s.log.Warn("search failed or no results", "title", title, "season", season)
-			continue
-		}
+// searchAndGrabSeason searches for one season and grabs the best results:
+// a season pack, or the missing episodes one by one when only a few of the
+// season's episodes are missing (see search.Searcher.PlanSeason). A release
+// pinned for the season is grabbed instead.
+// Returns false if background work has been canceled and the caller should stop.
+func (s *Server) searchAndGrabSeason(contentID int64, title, profile string, season int) bool {
+	ctx, cancel := s.backgroundContext(backgroundSearchTimeout)
+	defer cancel()
+	if s.grabPinned(ctx, contentID, &season) {
+		return true
+	}
 
-		// Grab the best match for this season
-		best := result.Releases[0]
+	episodes, _, err := s.library.ListEpisodes(library.EpisodeFilter{ContentID: &contentID, Season: &season})
+	if err != nil {
+		s.log.Warn("failed to list episodes", "content_id", contentID, "season", season, "error", err)
+	}
+	plan := s.searcher.PlanSeason(episodes)
+	if plan.Strategy == "" {
+		s.log.Info("no missing episodes to search", "title", title, "season", season)
+		return true
+	}
 
-		if err := s.bus.Publish(ctx, &events.GrabRequested{
-			BaseEvent:        events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
-			ContentID:        contentID,
-			Season:           &season,
-			IsCompleteSeason: true,
-			DownloadURL:      best.DownloadURL,
-			ReleaseName:      best.Title,
-			Indexer:          best.Indexer,
-		}); err != nil {
+	grabs := s.searcher.SearchSeason(ctx, contentID, title, profile, season, plan)
+	if s.baseCtx != nil && s.baseCtx.Err() != nil {
+		return false
+	}
+	if len(grabs) == 0 {
+		s.log.Warn("search failed or no results", "title", title, "season", season, "strategy", plan.Strategy)
+		return true
+	}
+
+	content, err := s.library.GetContent(contentID)
+	if err != nil {
+		s.log.Warn("failed to get content", "content_id", contentID, "error", err)
+	}
+	for _, grab := range grabs {
+		if content != nil {
+			if err := s.linkGrabEpisodes(content, grab); err != nil {
+				s.log.Warn("failed to link grab episodes", "release", grab.ReleaseName, "error", err)
+			}
+		}
+		if err := s.bus.Publish(ctx, grab); err != nil {
 			s.log.Error("failed to publish GrabRequested", "error", err)
 		}
 	}
+	return true
+}
+
+// grabPinned requests a grab of the release pinned for a content item's
+// season (nil for movies), reporting whether there was one.
+func (s *Server) grabPinned(ctx context.Context, contentID int64, season *int) bool {
+	pin, err := s.library.PinnedRelease(contentID)
+	if err != nil {
+		s.log.Warn("failed to read pinned release", "content_id", contentID, "error", err)
+		return false
+	}
+	if pin == nil || !pin.ForSeason(season) {
+		return false
+	}
+	if err := s.bus.Publish(ctx, search.PinnedGrab(contentID, pin)); err != nil {
+		s.log.Error("failed to publish GrabRequested", "error", err)
+		return false
+	}
+	s.log.Info("grabbing pinned release", "content_id", contentID, "release", pin.Title)
+	return true
+}
+
+// linkGrabEpisodes parses the release a season search chose and, when it
+// covers specific episodes, links the grab to their records, creating any
+// missing, as the v1 grab handler does. An episode release grabbed in place
+// of a season pack then marks its episode and imports against it. Grabs of
+// season packs, or of releases that don't name episodes, are left alone.
+func (s *Server) linkGrabEpisodes(content *library.Content, grab *events.GrabRequested) error {
+	target, err := release.ResolveSeriesTarget(grab.ReleaseName, content.Daily, grab.Season, nil)
+	if err != nil || len(target.Episodes) == 0 {
+		return nil
+	}
+	eps, err := s.library.FindOrCreateEpisodes(content.ID, target.Season, target.Episodes)
+	if err != nil {
+		return err
+	}
+	ids := make([]int64, len(eps))
+	for i, ep := range eps {
+		ids[i] = ep.ID
+	}
+	grab.EpisodeIDs = ids
+	grab.EpisodeID = &ids[0]
+	grab.IsCompleteSeason = false
+	return nil
+}
+
+// seasonProfile returns the quality profile for a season pack: the episodes'
+// override when every episode in the season shares one, otherwise the series profile.
+func (s *Server) seasonProfile(contentID int64, season int, seriesProfile string) string {
+	episodes, _, err := s.library.ListEpisodes(library.EpisodeFilter{ContentID: &contentID, Season: &season})
+	if err != nil {
+		s.log.Warn("failed to list episodes", "content_id", contentID, "season", season, "error", err)
+		return seriesProfile
+	}
+	return search.SeasonProfile(episodes, seriesProfile)
 }
 
 // syncEpisodesFromTVDB fetches episodes from TVDB and creates Episode records.
 func (s *Server) syncEpisodesFromTVDB(contentID int64, tvdbID int) {
-	ctx := context.Background()
+	ctx, cancel := s.backgroundContext(backgroundSyncTimeout)
+	defer cancel()
 
 	episodes, err := s.tvdbSvc.GetEpisodes(ctx, tvdbID)
 	if err != nil {
@@ -1276,4 +2051,65 @@ func (s *Server) syncEpisodesFromTVDB(contentID int64, tvdbID int) {
 		"total", len(episodes),
 		"inserted", inserted,
 	)
+
+	// Skip episodes in seasons that weren't requested
+	skipped, err := s.library.ApplySeasonMonitoring(contentID)
+	if err != nil {
+		s.log.Warn("failed to apply season monitoring", "content_id", contentID, "error", err)
+	} else if skipped > 0 {
+		s.log.Debug("skipped episodes in unmonitored seasons", "content_id", contentID, "count", skipped)
+	}
+
+	series, err := s.tvdbSvc.GetSeries(ctx, tvdbID)
+	if err != nil {
+		s.log.Warn("failed to fetch series aliases from TVDB", "tvdb_id", tvdbID, "error", err)
+		return
+	}
+	if _, err := s.library.ReplaceAliases(contentID, library.AliasSourceTVDB, series.Aliases); err != nil {
+		s.log.Warn("failed to save series aliases", "content_id", contentID, "error", err)
+	}
+}
+
+// seasonMonitoring maps Sonarr season numbers to their monitored flag.
+func seasonMonitoring(seasons []sonarrSeason) map[int]bool {
+	m := make(map[int]bool, len(seasons))
+	for _, season := range seasons {
+		m[season.SeasonNumber] = season.Monitored
+	}
+	return m
+}
+
+// monitoredSeasons returns the seasons to search for a series: the recorded
+// monitored seasons, or if none were ever recorded, seasons with wanted episodes.
+// Specials (season 0) are never included.
+func (s *Server) monitoredSeasons(contentID int64) []int {
+	recorded, err := s.library.ListSeasons(contentID)
+	if err != nil {
+		s.log.Warn("failed to list seasons", "content_id", contentID, "error", err)
+		return nil
+	}
+
+	var seasons []int
+	if len(recorded) > 0 {
+		for _, ss := range recorded {
+			if ss.Monitored && ss.Season > 0 {
+				seasons = append(seasons, ss.Season)
+			}
+		}
+		return seasons
+	}
+
+	wanted := library.StatusWanted
+	episodes, _, err := s.library.ListEpisodes(library.EpisodeFilter{ContentID: &contentID, Status: &wanted})
+	if err != nil {
+		s.log.Warn("failed to list wanted episodes", "content_id", contentID, "error", err)
+		return nil
+	}
+	for _, ep := range episodes {
+		if ep.Season > 0 && !slices.Contains(seasons, ep.Season) {
+			seasons = append(seasons, ep.Season)
+		}
+	}
+	sort.Ints(seasons)
+	return seasons
 }
@@ -2,6 +2,7 @@ package compat
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
 )
 
 // TestOverseerrSeriesFlow simulates the exact API flow Overseerr uses
@@ -109,11 +112,7 @@ func TestOverseerrSeriesFlow_ExistingSeries(t *testing.T) {
 	_, mux, db := setupServer(t, testAPIKey)
 
 	// Pre-populate with existing series
-	_, err := db.Exec(`
-		INSERT INTO content (id, type, tvdb_id, title, year, status, quality_profile, root_path)
-		VALUES (100, 'series', 71470, 'Star Trek: The Next Generation', 1987, 'wanted', 'hd', '/tv')
-	`)
-	require.NoError(t, err)
+	series := fixtures.NewSeries("Star Trek: The Next Generation", 1987).WithTVDBID(71470).Insert(t, db)
 
 	// Step 1: Lookup existing series
 	t.Run("lookup_existing_series", func(t *testing.T) {
@@ -129,7 +128,7 @@ func TestOverseerrSeriesFlow_ExistingSeries(t *testing.T) {
 
 		require.Len(t, results, 1)
 		// Existing series should have ID
-		assert.EqualValues(t, 100, results[0]["id"], "existing series should have id")
+		assert.EqualValues(t, series.ID, results[0]["id"], "existing series should have id")
 		assert.EqualValues(t, 71470, results[0]["tvdbId"])
 		// For wanted items, monitored should be false to trigger PUT
 		assert.Equal(t, false, results[0]["monitored"], "wanted series should return monitored=false")
@@ -137,8 +136,8 @@ func TestOverseerrSeriesFlow_ExistingSeries(t *testing.T) {
 
 	// Step 2: Overseerr sends PUT to update series (re-request flow)
 	t.Run("update_series_add_season", func(t *testing.T) {
-		payload := `{
-			"id": 100,
+		payload := fmt.Sprintf(`{
+			"id": %d,
 			"monitored": true,
 			"seasons": [
 				{"seasonNumber": 1, "monitored": true},
@@ -148,7 +147,7 @@ func TestOverseerrSeriesFlow_ExistingSeries(t *testing.T) {
 			"addOptions": {
 				"searchForMissingEpisodes": true
 			}
-		}`
+		}`, series.ID)
 
 		req := httptest.NewRequest(http.MethodPut, "/api/v3/series", strings.NewReader(payload))
 		req.Header.Set("X-Api-Key", testAPIKey)
@@ -161,7 +160,7 @@ func TestOverseerrSeriesFlow_ExistingSeries(t *testing.T) {
 		var result map[string]any
 		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
 
-		assert.EqualValues(t, 100, result["id"])
+		assert.EqualValues(t, series.ID, result["id"])
 		assert.Equal(t, "Star Trek: The Next Generation", result["title"])
 	})
 }
@@ -230,11 +229,7 @@ func TestOverseerrMovieFlow_ExistingWanted(t *testing.T) {
 	_, mux, db := setupServer(t, testAPIKey)
 
 	// Pre-populate with wanted movie
-	_, err := db.Exec(`
-		INSERT INTO content (id, type, tmdb_id, title, year, status, quality_profile, root_path)
-		VALUES (200, 'movie', 533535, 'Deadpool & Wolverine', 2024, 'wanted', 'hd', '/movies')
-	`)
-	require.NoError(t, err)
+	movie := fixtures.NewMovie("Deadpool & Wolverine", 2024).WithTMDBID(533535).Insert(t, db)
 
 	// Lookup should return monitored=false for wanted items
 	t.Run("lookup_wanted_movie", func(t *testing.T) {
@@ -247,20 +242,20 @@ func TestOverseerrMovieFlow_ExistingWanted(t *testing.T) {
 		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
 
 		require.Len(t, results, 1)
-		assert.EqualValues(t, 200, results[0]["id"])
+		assert.EqualValues(t, movie.ID, results[0]["id"])
 		assert.Equal(t, false, results[0]["monitored"], "wanted movie should return monitored=false")
 	})
 
 	// PUT should trigger search
 	t.Run("update_wanted_movie", func(t *testing.T) {
-		payload := `{
-			"id": 200,
+		payload := fmt.Sprintf(`{
+			"id": %d,
 			"monitored": true,
 			"tags": [],
 			"addOptions": {
 				"searchForMovie": true
 			}
-		}`
+		}`, movie.ID)
 
 		req := httptest.NewRequest(http.MethodPut, "/api/v3/movie", strings.NewReader(payload))
 		req.Header.Set("X-Api-Key", testAPIKey)
@@ -278,11 +273,7 @@ func TestOverseerrMovieFlow_ExistingAvailable(t *testing.T) {
 	_, mux, db := setupServer(t, testAPIKey)
 
 	// Pre-populate with available movie (already downloaded)
-	_, err := db.Exec(`
-		INSERT INTO content (id, type, tmdb_id, title, year, status, quality_profile, root_path)
-		VALUES (300, 'movie', 157336, 'Interstellar', 2014, 'available', 'hd', '/movies')
-	`)
-	require.NoError(t, err)
+	movie := fixtures.NewMovie("Interstellar", 2014).WithTMDBID(157336).WithStatus(library.StatusAvailable).Insert(t, db)
 
 	// Lookup should return hasFile=true and monitored=true
 	// This tells Overseerr the movie is already available - skip it
@@ -298,7 +289,7 @@ func TestOverseerrMovieFlow_ExistingAvailable(t *testing.T) {
 		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
 
 		require.Len(t, results, 1)
-		assert.EqualValues(t, 300, results[0]["id"], "available movie should have id")
+		assert.EqualValues(t, movie.ID, results[0]["id"], "available movie should have id")
 		assert.Equal(t, true, results[0]["hasFile"], "available movie should have hasFile=true")
 		assert.Equal(t, true, results[0]["monitored"], "available movie should have monitored=true")
 	})
@@ -309,17 +300,13 @@ func TestOverseerrCommandMoviesSearch(t *testing.T) {
 	_, mux, db := setupServer(t, testAPIKey)
 
 	// Pre-populate with a movie
-	_, err := db.Exec(`
-		INSERT INTO content (id, type, tmdb_id, title, year, status, quality_profile, root_path)
-		VALUES (400, 'movie', 680, 'Pulp Fiction', 1994, 'wanted', 'hd', '/movies')
-	`)
-	require.NoError(t, err)
+	movie := fixtures.NewMovie("Pulp Fiction", 1994).WithTMDBID(680).Insert(t, db)
 
 	// Send MoviesSearch command
-	payload := `{
+	payload := fmt.Sprintf(`{
 		"name": "MoviesSearch",
-		"movieIds": [400]
-	}`
+		"movieIds": [%d]
+	}`, movie.ID)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v3/command", strings.NewReader(payload))
 	req.Header.Set("X-Api-Key", testAPIKey)
@@ -379,3 +366,111 @@ func TestOverseerrRequiredEndpoints(t *testing.T) {
 		})
 	}
 }
+
+// TestOverseerrSeriesFlow_UpdateAppliesSeasonMonitoring verifies per-season
+// monitored flags on PUT /series are applied to episode statuses.
+func TestOverseerrSeriesFlow_UpdateAppliesSeasonMonitoring(t *testing.T) {
+	_, mux, db := setupServer(t, testAPIKey)
+
+	series := fixtures.NewSeries("Star Trek: The Next Generation", 1987).WithTVDBID(71470).Insert(t, db)
+	fixtures.NewEpisode(series.ID, 1, 1).WithTitle("Encounter at Farpoint").Insert(t, db)
+	fixtures.NewEpisode(series.ID, 1, 2).WithTitle("The Naked Now").WithStatus(library.StatusAvailable).Insert(t, db)
+	fixtures.NewEpisode(series.ID, 2, 1).WithTitle("The Child").Insert(t, db)
+	fixtures.NewEpisode(series.ID, 2, 2).WithTitle("Where Silence Has Lease").Insert(t, db)
+
+	payload := fmt.Sprintf(`{
+		"id": %d,
+		"monitored": true,
+		"seasons": [
+			{"seasonNumber": 1, "monitored": false},
+			{"seasonNumber": 2, "monitored": true}
+		]
+	}`, series.ID)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v3/series", strings.NewReader(payload))
+	req.Header.Set("X-Api-Key", testAPIKey)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+
+	statuses := map[string]string{}
+	rows, err := db.Query(`SELECT season, episode, status FROM episodes WHERE content_id = ?`, series.ID)
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var season, episode int
+		var status string
+		require.NoError(t, rows.Scan(&season, &episode, &status))
+		statuses[fmt.Sprintf("S%02dE%02d", season, episode)] = status
+	}
+	require.NoError(t, rows.Err())
+
+	assert.Equal(t, map[string]string{
+		"S01E01": "unmonitored",
+		"S01E02": "available",
+		"S02E01": "wanted",
+		"S02E02": "wanted",
+	}, statuses)
+}
+
+// TestOverseerrSeriesFlow_PartialStatistics verifies the statistics Overseerr's
+// Sonarr client reads for a half-downloaded series: season 1 complete, season 2
+// partial (one file present, one deleted outside arrgo) and season 3 not
+// requested. A season requested before TVDB synced episodes of it doesn't
+// count towards seasonCount.
+func TestOverseerrSeriesFlow_PartialStatistics(t *testing.T) {
+	_, mux, db := setupServer(t, testAPIKey)
+	lib := library.NewStore(db)
+
+	series := fixtures.NewSeries("Star Trek: The Next Generation", 1987).WithTVDBID(71470).Insert(t, db)
+	for _, ep := range []struct{ season, episode int }{{1, 1}, {1, 2}, {2, 1}, {2, 2}} {
+		e := fixtures.NewEpisode(series.ID, ep.season, ep.episode).WithStatus(library.StatusAvailable).Insert(t, db)
+		fixtures.NewFile(series.ID, fmt.Sprintf("/tv/TNG/S%02dE%02d.mkv", ep.season, ep.episode)).
+			ForEpisode(e.ID).WithSize(1000).Insert(t, db)
+	}
+	fixtures.NewEpisode(series.ID, 2, 3).Insert(t, db)
+	fixtures.NewEpisode(series.ID, 3, 1).WithStatus(library.StatusUnmonitored).Insert(t, db)
+	_, err := db.Exec("UPDATE files SET missing_at = CURRENT_TIMESTAMP WHERE path = ?", "/tv/TNG/S02E02.mkv")
+	require.NoError(t, err)
+	require.NoError(t, lib.SetSeasonMonitoring(series.ID, map[int]bool{1: true, 2: true, 4: true}))
+
+	want := `{
+		"seasonCount": 3,
+		"seasons": [
+			{"seasonNumber": 1, "monitored": true, "statistics": {"episodeFileCount": 2, "episodeCount": 2, "totalEpisodeCount": 2, "sizeOnDisk": 2000, "percentOfEpisodes": 100}},
+			{"seasonNumber": 2, "monitored": true, "statistics": {"episodeFileCount": 1, "episodeCount": 3, "totalEpisodeCount": 3, "sizeOnDisk": 1000, "percentOfEpisodes": 33.333333333333336}},
+			{"seasonNumber": 3, "monitored": false, "statistics": {"episodeFileCount": 0, "episodeCount": 1, "totalEpisodeCount": 1, "sizeOnDisk": 0, "percentOfEpisodes": 0}},
+			{"seasonNumber": 4, "monitored": true}
+		],
+		"statistics": {"seasonCount": 3, "episodeFileCount": 3, "episodeCount": 6, "totalEpisodeCount": 6, "sizeOnDisk": 3000, "percentOfEpisodes": 50}
+	}`
+
+	get := func(path string) []byte {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Api-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+		return w.Body.Bytes()
+	}
+	shape := func(raw json.RawMessage) string {
+		var full map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(raw, &full))
+		subset, err := json.Marshal(map[string]json.RawMessage{
+			"seasonCount": full["seasonCount"],
+			"seasons":     full["seasons"],
+			"statistics":  full["statistics"],
+		})
+		require.NoError(t, err)
+		return string(subset)
+	}
+
+	assert.JSONEq(t, want, shape(get(fmt.Sprintf("/api/v3/series/%d", series.ID))), "GET /series/{id}")
+
+	var list []json.RawMessage
+	require.NoError(t, json.Unmarshal(get("/api/v3/series"), &list))
+	require.Len(t, list, 1)
+	assert.JSONEq(t, want, shape(list[0]), "GET /series")
+}
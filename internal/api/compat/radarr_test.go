@@ -4,14 +4,15 @@ package compat
 import (
 	"context"
 	"database/sql"
-	_ "embed"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -22,11 +23,18 @@ import (
 
 	"github.com/vmunix/arrgo/internal/config"
 	"github.com/vmunix/arrgo/internal/download"
+	downloadmocks "github.com/vmunix/arrgo/internal/download/mocks"
 	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/metadata"
+	"github.com/vmunix/arrgo/internal/profile"
 	"github.com/vmunix/arrgo/internal/search"
 	"github.com/vmunix/arrgo/internal/search/mocks"
+	"github.com/vmunix/arrgo/internal/testutil"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
 	"github.com/vmunix/arrgo/internal/tmdb"
+	"github.com/vmunix/arrgo/pkg/tvdb"
 )
 
 // Test constants
@@ -69,23 +77,9 @@ type testErrorResponse struct {
 	Error string `json:"error"`
 }
 
-//go:embed testdata/schema.sql
-var testSchema string
-
-func setupTestDB(t *testing.T) *sql.DB {
-	t.Helper()
-	db, err := sql.Open("sqlite", ":memory:?_foreign_keys=on")
-	require.NoError(t, err, "open db")
-	t.Cleanup(func() { _ = db.Close() })
-
-	_, err = db.Exec(testSchema)
-	require.NoError(t, err, "apply schema")
-	return db
-}
-
 func setupServer(t *testing.T, apiKey string) (*Server, *http.ServeMux, *sql.DB) {
 	t.Helper()
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	lib := library.NewStore(db)
 	dl := download.NewStore(db)
 
@@ -205,6 +199,40 @@ func TestListQualityProfiles_IncludesIDAndName(t *testing.T) {
 	assert.True(t, foundUHD, "Ultra-HD profile with id=2 not found")
 }
 
+func TestListQualityProfiles_FromStore(t *testing.T) {
+	srv, mux, db := setupServer(t, testAPIKey)
+	profiles := profile.NewStore(db)
+	_, err := profiles.Seed(map[string]config.QualityProfile{"hd": {}, "uhd": {}})
+	require.NoError(t, err)
+	require.NoError(t, profiles.Create(&profile.Profile{Name: "anime"}))
+	srv.SetProfiles(profiles)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v3/qualityprofile", nil)
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	var listed []testQualityProfile
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed, 3, "profiles created through the store are listed")
+	ids := make(map[string]int)
+	for _, p := range listed {
+		ids[p.Name] = p.ID
+	}
+	assert.Equal(t, map[string]int{"HD-1080p": 1, "Ultra-HD": 2, "anime": 3}, ids)
+
+	// Adding a movie maps the profile ID back to its name
+	body := `{"tmdbId": 603, "title": "The Matrix", "year": 1999, "qualityProfileId": 3, "rootFolderPath": "/movies", "monitored": true}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v3/movie", strings.NewReader(body))
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var movie radarrMovieResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &movie))
+	assert.Equal(t, 3, movie.QualityProfileID)
+}
+
 // Root Folders Tests
 
 func TestListRootFolders_ReturnsConfiguredRoots(t *testing.T) {
@@ -236,7 +264,7 @@ func TestListRootFolders_ReturnsConfiguredRoots(t *testing.T) {
 }
 
 func TestListRootFolders_EmptyWhenNoRootsConfigured(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	lib := library.NewStore(db)
 	dlStore := download.NewStore(db)
 
@@ -265,6 +293,63 @@ func TestListRootFolders_EmptyWhenNoRootsConfigured(t *testing.T) {
 	assert.Empty(t, folders)
 }
 
+func TestListRootFolders_MultipleRoots(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	disk1, disk2, tv := t.TempDir(), t.TempDir(), t.TempDir()
+	cfg := Config{
+		APIKey:          testAPIKey,
+		MovieRoot:       disk1,
+		SeriesRoot:      tv,
+		MovieRoots:      library.NewRootFolders([]string{disk1, disk2}, "most_free_space"),
+		QualityProfiles: map[string]int{"hd": 1},
+	}
+	srv := New(cfg, library.NewStore(db), download.NewStore(db), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v3/rootfolder", nil)
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var folders []testRootFolder
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &folders))
+	require.Len(t, folders, 3)
+	assert.Equal(t, testRootFolder{ID: 1, Path: disk1, FreeSpace: folders[0].FreeSpace, Type: "movie"}, folders[0])
+	assert.Equal(t, testRootFolder{ID: 2, Path: disk2, FreeSpace: folders[1].FreeSpace, Type: "movie"}, folders[1])
+	assert.Equal(t, testRootFolder{ID: 3, Path: tv, FreeSpace: folders[2].FreeSpace, Type: "series"}, folders[2])
+	for _, f := range folders {
+		assert.Positive(t, f.FreeSpace, "free space of %s", f.Path)
+	}
+}
+
+func TestAddMovie_SelectsRootWhenNotGiven(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	cfg := Config{
+		APIKey:          testAPIKey,
+		MovieRoot:       "/movies",
+		MovieRoots:      library.NewRootFolders([]string{"/movies", "/movies2"}, "round_robin"),
+		QualityProfiles: map[string]int{"hd": 1},
+	}
+	srv := New(cfg, library.NewStore(db), download.NewStore(db), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	for i, want := range []string{"/movies", "/movies2", "/movies"} {
+		body := fmt.Sprintf(`{"tmdbId": %d, "title": "Movie %d", "year": 2024, "qualityProfileId": 1}`, 100+i, i)
+		req := httptest.NewRequest(http.MethodPost, "/api/v3/movie", strings.NewReader(body))
+		req.Header.Set("X-Api-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "response body: %s", w.Body.String())
+
+		var rootPath string
+		require.NoError(t, db.QueryRow("SELECT root_path FROM content WHERE tmdb_id = ?", 100+i).Scan(&rootPath))
+		assert.Equal(t, want, rootPath, "movie %d", i)
+	}
+}
+
 // Add Movie Tests
 
 func TestAddMovie_CreatesContentInLibrary(t *testing.T) {
@@ -335,6 +420,183 @@ func TestAddMovie_ReturnsRadarrFormatResponse(t *testing.T) {
 	assert.Equal(t, "released", resp.Status)
 }
 
+func TestAddMovie_DefersSearchUntilMinimumAvailability(t *testing.T) {
+	// Mock TMDB server returning a release date next month
+	releaseDate := time.Now().UTC().AddDate(0, 1, 0).Format(time.DateOnly)
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":           12345,
+			"title":        "Upcoming Movie",
+			"release_date": releaseDate,
+		})
+	}))
+	defer tmdbServer.Close()
+
+	ctrl := gomock.NewController(t)
+	mockIndexer := mocks.NewMockIndexerAPI(ctrl)
+	mockIndexer.EXPECT().Search(gomock.Any(), gomock.Any()).Times(0)
+
+	db := testutil.OpenTestDB(t)
+	lib := library.NewStore(db)
+	testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	bus := events.NewBus(nil, testLogger)
+	defer bus.Close()
+
+	srv := New(Config{APIKey: testAPIKey, MovieRoot: testMovieRoot, QualityProfiles: map[string]int{"hd": 1}}, lib, download.NewStore(db), testLogger)
+	srv.SetSearcher(search.NewSearcher(mockIndexer, search.NewScorer(nil), testLogger))
+	srv.SetBus(bus)
+	tmdbClient := tmdb.NewClient("fake-key", tmdb.WithBaseURL(tmdbServer.URL))
+	srv.SetMetadata(metadata.NewContentRefresher(lib, tmdbClient, nil, testLogger))
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := `{
+		"tmdbId": 12345,
+		"title": "Upcoming Movie",
+		"year": 2026,
+		"qualityProfileId": 1,
+		"monitored": true,
+		"minimumAvailability": "inCinemas",
+		"addOptions": {"searchForMovie": true}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v3/movie", strings.NewReader(body))
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, "response body: %s", w.Body.String())
+	var resp radarrMovieResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.IsAvailable)
+	assert.Equal(t, "inCinemas", resp.MinAvailability)
+
+	content, err := lib.GetContent(resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, library.AvailabilityInCinemas, content.MinimumAvailability)
+	require.NotNil(t, content.ReleaseDate)
+	assert.Equal(t, releaseDate, content.ReleaseDate.Format(time.DateOnly))
+}
+
+func TestAddMovie_AdoptsMovieInPlex(t *testing.T) {
+	plexServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = fmt.Fprint(w, `<?xml version="1.0"?>
+<MediaContainer>
+  <Video ratingKey="42" title="Owned Movie" year="2023" type="movie">
+    <Media><Part file="/data/movies/Owned Movie (2023)/Owned Movie (2023) 1080p.mkv"/></Media>
+  </Video>
+</MediaContainer>`)
+	}))
+	defer plexServer.Close()
+
+	ctrl := gomock.NewController(t)
+	mockIndexer := mocks.NewMockIndexerAPI(ctrl)
+	mockIndexer.EXPECT().Search(gomock.Any(), gomock.Any()).Times(0)
+
+	db := testutil.OpenTestDB(t)
+	lib := library.NewStore(db)
+	testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	bus := events.NewBus(nil, testLogger)
+	defer bus.Close()
+
+	srv := New(Config{APIKey: testAPIKey, MovieRoot: testMovieRoot, QualityProfiles: map[string]int{"hd": 1}}, lib, download.NewStore(db), testLogger)
+	srv.SetSearcher(search.NewSearcher(mockIndexer, search.NewScorer(nil), testLogger))
+	srv.SetBus(bus)
+	srv.SetPlex(importer.NewPlexClientWithPathMapping(plexServer.URL, "token", "/srv/movies", "/data/movies", testLogger))
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := `{
+		"tmdbId": 12345,
+		"title": "Owned Movie",
+		"year": 2024,
+		"qualityProfileId": 1,
+		"monitored": true,
+		"addOptions": {"searchForMovie": true}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v3/movie", strings.NewReader(body))
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, "response body: %s", w.Body.String())
+	var resp radarrMovieResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.HasFile)
+	assert.Equal(t, "/srv/movies/Owned Movie (2023)/Owned Movie (2023) 1080p.mkv", resp.ExistingFile)
+
+	content, err := lib.GetContent(resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, library.StatusAvailable, content.Status)
+
+	files, _, err := lib.ListFiles(library.FileFilter{ContentID: &resp.ID})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, resp.ExistingFile, files[0].Path)
+	assert.Equal(t, "1080p", files[0].Quality)
+	assert.Equal(t, importer.SourcePlexExisting, files[0].Source)
+}
+
+func TestAddMovie_DuplicateTitleRejected(t *testing.T) {
+	_, mux, db := setupServer(t, testAPIKey)
+
+	// Added earlier via the v1 API with different spacing and case
+	lib := library.NewStore(db)
+	require.NoError(t, lib.AddContent(&library.Content{
+		Type: library.ContentTypeMovie, Title: "se7en ", Year: 1995,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: testMovieRoot,
+	}))
+
+	body := `{"tmdbId": 807, "title": "Se7en", "year": 1995, "qualityProfileId": 1, "monitored": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v3/movie", strings.NewReader(body))
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code, "response body: %s", w.Body.String())
+	var resp testErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "This movie has already been added", resp.Error)
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM content").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestAddMovie_ExcludedRejected(t *testing.T) {
+	_, mux, db := setupServer(t, testAPIKey)
+	lib := library.NewStore(db)
+	require.NoError(t, lib.AddExclusion(&library.Exclusion{Source: library.ExclusionTMDB, ExternalID: 807, Reason: "deleted"}))
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("X-Api-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/api/v3/movie", `{"tmdbId": 807, "title": "Se7en", "year": 1995, "qualityProfileId": 1, "monitored": true}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "response body: %s", w.Body.String())
+	var resp testErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "This movie is excluded", resp.Error)
+
+	// Series are excluded by TVDB ID, so the same number is not excluded for them
+	require.NoError(t, lib.AddExclusion(&library.Exclusion{Source: library.ExclusionTVDB, ExternalID: 81189}))
+	w = post("/api/v3/series", `{"tvdbId": 81189, "title": "Breaking Bad", "year": 2008, "qualityProfileId": 1, "monitored": true}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "response body: %s", w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "This series is excluded", resp.Error)
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM content").Scan(&count))
+	assert.Zero(t, count)
+
+	w = post("/api/v3/series", `{"tvdbId": 807, "title": "Other Show", "year": 2010, "qualityProfileId": 1, "monitored": true}`)
+	assert.Equal(t, http.StatusCreated, w.Code, "response body: %s", w.Body.String())
+}
+
 func TestAddMovie_InvalidJSON(t *testing.T) {
 	_, mux, _ := setupServer(t, testAPIKey)
 
@@ -433,6 +695,54 @@ func TestListQueue_ReturnsDownloadsInRadarrFormat(t *testing.T) {
 	assert.Equal(t, "NZBgeek", record.Indexer)
 }
 
+func TestListQueue_SharesLiveStatus(t *testing.T) {
+	srv, mux, db := setupServer(t, testAPIKey)
+	movie := fixtures.NewMovie("Test Movie", 2024).Insert(t, db)
+	fixtures.NewDownload().ForContent(movie.ID).WithStatus(download.StatusDownloading).
+		WithClient(download.ClientSABnzbd, "sab-123").Insert(t, db)
+
+	// SABnzbd answers slowly, so the polls overlap
+	release := make(chan struct{})
+	var calls atomic.Int32
+	client := downloadmocks.NewMockDownloader(gomock.NewController(t))
+	client.EXPECT().Status(gomock.Any(), "sab-123").DoAndReturn(
+		func(context.Context, string) (*download.ClientStatus, error) {
+			calls.Add(1)
+			<-release
+			return &download.ClientStatus{ID: "sab-123", Status: download.StatusDownloading, Size: 1000, Progress: 40}, nil
+		}).AnyTimes()
+	mgr := download.NewManager(srv.downloads, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mgr.AddClient(download.ClientSABnzbd, download.ProtocolUsenet, client)
+	srv.SetManager(mgr)
+
+	const polls = 8
+	var wg sync.WaitGroup
+	recorders := make([]*httptest.ResponseRecorder, polls)
+	for i := range polls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/api/v3/queue", nil)
+			req.Header.Set("X-Api-Key", testAPIKey)
+			recorders[i] = httptest.NewRecorder()
+			mux.ServeHTTP(recorders[i], req)
+		}()
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "one SABnzbd call for all polls")
+	for _, w := range recorders {
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "0", w.Header().Get("Age"), "age of the live status in seconds")
+		var resp testQueueResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Records, 1)
+	}
+}
+
 func TestListQueue_EmptyQueueReturnsEmptyRecords(t *testing.T) {
 	_, mux, _ := setupServer(t, testAPIKey)
 
@@ -451,6 +761,141 @@ func TestListQueue_EmptyQueueReturnsEmptyRecords(t *testing.T) {
 	assert.Zero(t, resp.TotalRecords)
 }
 
+func TestListQueue_SeriesEpisodes(t *testing.T) {
+	srv, mux, db := setupServer(t, testAPIKey)
+	lib := library.NewStore(db)
+
+	movie := &library.Content{Type: library.ContentTypeMovie, Title: "Test Movie", Year: 2024,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: testMovieRoot}
+	require.NoError(t, lib.AddContent(movie))
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Test Show", Year: 2024,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: testSeriesRoot}
+	require.NoError(t, lib.AddContent(series))
+	var episodeIDs []int64
+	for n := 1; n <= 2; n++ {
+		ep := &library.Episode{ContentID: series.ID, Season: 1, Episode: n, Title: fmt.Sprintf("Episode %d", n), Status: library.StatusWanted}
+		require.NoError(t, lib.AddEpisode(ep))
+		episodeIDs = append(episodeIDs, ep.ID)
+	}
+
+	require.NoError(t, srv.downloads.Add(&download.Download{ContentID: movie.ID, Client: download.ClientSABnzbd,
+		ClientID: "sab-movie", Status: download.StatusDownloading, ReleaseName: "Test.Movie.2024.1080p"}))
+	season := 1
+	pack := &download.Download{ContentID: series.ID, Season: &season, IsCompleteSeason: true, Client: download.ClientSABnzbd,
+		ClientID: "sab-pack", Status: download.StatusDownloading, ReleaseName: "Test.Show.S01.1080p"}
+	require.NoError(t, srv.downloads.Add(pack))
+	require.NoError(t, srv.downloads.SetEpisodeIDs(pack.ID, episodeIDs))
+	require.NoError(t, srv.downloads.UpdateProgress(pack.ID, 45, 0, 0, 1000))
+
+	queue := func(query string) []map[string]any {
+		req := httptest.NewRequest(http.MethodGet, "/api/v3/queue"+query, nil)
+		req.Header.Set("X-Api-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+		var resp struct {
+			Records []map[string]any `json:"records"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Records
+	}
+
+	// A season pack is listed once per episode
+	records := queue("")
+	require.Len(t, records, 3)
+	assert.InDelta(t, movie.ID, records[0]["movieId"], 0)
+	assert.NotContains(t, records[0], "seriesId")
+	for n, record := range records[1:] {
+		assert.InDelta(t, series.ID, record["seriesId"], 0)
+		assert.InDelta(t, episodeIDs[n], record["episodeId"], 0)
+		assert.InDelta(t, 1, record["seasonNumber"], 0)
+		assert.NotContains(t, record, "movieId")
+		assert.InDelta(t, 1000, record["size"], 0)
+		assert.InDelta(t, 550, record["sizeleft"], 0)
+		assert.Equal(t, "sab-pack", record["downloadId"])
+		episode, ok := record["episode"].(map[string]any)
+		require.True(t, ok)
+		assert.InDelta(t, n+1, episode["episodeNumber"], 0)
+	}
+
+	records = queue(fmt.Sprintf("?seriesId=%d&includeUnknownSeriesItems=true", series.ID))
+	assert.Len(t, records, 2)
+
+	records = queue(fmt.Sprintf("?seriesId=%d&episodeIds=%d", series.ID, episodeIDs[1]))
+	require.Len(t, records, 1)
+	assert.InDelta(t, episodeIDs[1], records[0]["episodeId"], 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v3/queue?seriesId=abc", nil)
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHistorySince(t *testing.T) {
+	srv, mux, db := setupServer(t, testAPIKey)
+	lib := library.NewStore(db)
+	history := importer.NewHistoryStore(db)
+	srv.SetHistory(history)
+
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Test Show", Year: 2024,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: testSeriesRoot}
+	require.NoError(t, lib.AddContent(series))
+	ep := &library.Episode{ContentID: series.ID, Season: 1, Episode: 1, Status: library.StatusWanted}
+	require.NoError(t, lib.AddEpisode(ep))
+	dl := &download.Download{ContentID: series.ID, EpisodeID: &ep.ID, Client: download.ClientSABnzbd,
+		ClientID: "sab-ep", Status: download.StatusImported, ReleaseName: "Test.Show.S01E01.1080p"}
+	require.NoError(t, srv.downloads.Add(dl))
+
+	start := time.Now().Add(-time.Second)
+	for _, h := range []*importer.HistoryEntry{
+		{ContentID: series.ID, EpisodeID: &ep.ID, Event: importer.EventGrabbed,
+			Data: fmt.Sprintf(`{"download_id":%d,"release_name":"Test.Show.S01E01.1080p"}`, dl.ID)},
+		{ContentID: series.ID, EpisodeID: &ep.ID, Event: importer.EventGrabDecision, Data: `{}`},
+		{ContentID: series.ID, EpisodeID: &ep.ID, Event: importer.EventImported,
+			Data: fmt.Sprintf(`{"download_id":%d,"release_name":"Test.Show.S01E01.1080p","quality":"1080p"}`, dl.ID)},
+	} {
+		require.NoError(t, history.Add(h))
+		time.Sleep(time.Millisecond) // Distinct timestamps keep the order stable
+	}
+
+	since := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v3/history/since?"+query, nil)
+		req.Header.Set("X-Api-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := since("date=" + start.UTC().Format(time.RFC3339))
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var records []map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &records))
+	require.Len(t, records, 2, "grab decisions are not reported")
+	assert.Equal(t, "grabbed", records[0]["eventType"])
+	assert.Equal(t, "downloadFolderImported", records[1]["eventType"])
+	for _, record := range records {
+		assert.InDelta(t, series.ID, record["seriesId"], 0)
+		assert.InDelta(t, ep.ID, record["episodeId"], 0)
+		assert.Equal(t, "Test.Show.S01E01.1080p", record["sourceTitle"])
+		assert.Equal(t, "sab-ep", record["downloadId"])
+	}
+
+	w = since("date=" + start.UTC().Format(time.RFC3339) + "&eventType=downloadFolderImported")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, "downloadFolderImported", records[0]["eventType"])
+
+	w = since("date=" + time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &records))
+	assert.Empty(t, records)
+
+	w = since("date=yesterday")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 // List Movies Tests
 
 func TestListMovies_ReturnsEmptyArray(t *testing.T) {
@@ -469,6 +914,26 @@ func TestListMovies_ReturnsEmptyArray(t *testing.T) {
 	assert.Empty(t, resp)
 }
 
+func TestGetMovie_AbandonedNotMonitored(t *testing.T) {
+	srv, mux, _ := setupServer(t, testAPIKey)
+
+	tmdbID := int64(603)
+	c := &library.Content{Type: library.ContentTypeMovie, TMDBID: &tmdbID, Title: "Obscure Movie", Year: 1971,
+		Status: library.StatusAbandoned, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, srv.library.AddContent(c))
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v3/movie/%d", c.ID), nil)
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp radarrMovieResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Monitored, "abandoned content is not pending")
+	assert.False(t, resp.HasFile)
+}
+
 // Get Movie Tests
 
 func TestGetMovie_NotFound(t *testing.T) {
@@ -486,7 +951,7 @@ func TestGetMovie_NotFound(t *testing.T) {
 // Auth middleware: API key not configured (testing mode - auth skipped)
 
 func TestAuthMiddleware_APIKeyNotConfigured_SkipsAuth(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	lib := library.NewStore(db)
 	dlStore := download.NewStore(db)
 
@@ -533,7 +998,7 @@ func TestLookupMovie_WithTMDB(t *testing.T) {
 	defer tmdbServer.Close()
 
 	// Create server with TMDB client
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := library.NewStore(db)
 	dlStore := download.NewStore(db)
 	testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
@@ -579,6 +1044,65 @@ func TestLookupMovie_WithTMDB(t *testing.T) {
 	assert.InDelta(t, 8.5, tmdbRating["value"], 0.001)
 }
 
+func TestLookupMovie_ByTitle(t *testing.T) {
+	tmdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/3/search/movie", r.URL.Path)
+		assert.Equal(t, "the matrix", r.URL.Query().Get("query"))
+		_, _ = w.Write([]byte(`{"page":1,"results":[
+			{"id":603,"title":"The Matrix","release_date":"1999-03-31","poster_path":"/matrix.jpg"},
+			{"id":604,"title":"The Matrix Reloaded","release_date":"2003-05-15"}
+		]}`))
+	}))
+	defer tmdbServer.Close()
+
+	srv, mux, db := setupServer(t, testAPIKey)
+	srv.SetTMDB(tmdb.NewClient("fake-key", tmdb.WithBaseURL(tmdbServer.URL)))
+
+	// The sequel is already wanted in the library
+	tmdbID := int64(604)
+	content := &library.Content{
+		Type:           library.ContentTypeMovie,
+		TMDBID:         &tmdbID,
+		Title:          "The Matrix Reloaded",
+		Year:           2003,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       testMovieRoot,
+	}
+	require.NoError(t, library.NewStore(db).AddContent(content))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v3/movie/lookup?term=the+matrix", nil)
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results, 2)
+
+	assert.EqualValues(t, 603, results[0]["tmdbId"])
+	assert.Equal(t, "The Matrix", results[0]["title"])
+	assert.EqualValues(t, 1999, results[0]["year"])
+	assert.Nil(t, results[0]["id"], "movie not in library has no internal ID")
+
+	assert.EqualValues(t, 604, results[1]["tmdbId"])
+	assert.EqualValues(t, content.ID, results[1]["id"], "library movie carries its internal ID")
+	assert.Equal(t, false, results[1]["monitored"], "wanted movie reports unmonitored like the ID lookup")
+}
+
+func TestLookupMovie_ByTitleWithoutTMDB(t *testing.T) {
+	_, mux, _ := setupServer(t, testAPIKey)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v3/movie/lookup?term=the+matrix", nil)
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+}
+
 // Sonarr endpoint tests
 
 func TestSonarrLookup(t *testing.T) {
@@ -600,6 +1124,59 @@ func TestSonarrLookup(t *testing.T) {
 	assert.Equal(t, "continuing", results[0]["status"])
 }
 
+func TestSonarrLookup_ByTitle(t *testing.T) {
+	tvdbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"token":"test-token"}}`))
+		case "/search":
+			assert.Equal(t, "breaking bad", r.URL.Query().Get("query"))
+			_, _ = w.Write([]byte(`{"status":"success","data":[
+				{"objectID":"series-81189","name":"Breaking Bad","year":"2008","status":"Ended","network":"AMC","tvdb_id":"81189"},
+				{"objectID":"series-273181","name":"Better Call Saul","year":"2015","status":"Ended","tvdb_id":"273181"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer tvdbServer.Close()
+
+	srv, mux, db := setupServer(t, testAPIKey)
+	srv.SetTVDB(metadata.NewTVDBService(tvdb.New("api-key", tvdb.WithBaseURL(tvdbServer.URL)), metadata.NewCache(db), nil))
+
+	tvdbID := int64(273181)
+	content := &library.Content{
+		Type:           library.ContentTypeSeries,
+		TVDBID:         &tvdbID,
+		Title:          "Better Call Saul",
+		Year:           2015,
+		Status:         library.StatusAvailable,
+		QualityProfile: "hd",
+		RootPath:       testSeriesRoot,
+	}
+	require.NoError(t, library.NewStore(db).AddContent(content))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v3/series/lookup?term=breaking+bad", nil)
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results, 2)
+
+	assert.EqualValues(t, 81189, results[0]["tvdbId"])
+	assert.Equal(t, "Breaking Bad", results[0]["title"])
+	assert.EqualValues(t, 2008, results[0]["year"])
+	assert.Equal(t, "ended", results[0]["status"])
+	assert.Equal(t, "AMC", results[0]["network"])
+	assert.Nil(t, results[0]["id"], "series not in library has no internal ID")
+
+	assert.EqualValues(t, 273181, results[1]["tvdbId"])
+	assert.EqualValues(t, content.ID, results[1]["id"], "library series carries its internal ID")
+}
+
 func TestSonarrAddSeries(t *testing.T) {
 	_, mux, db := setupServer(t, testAPIKey)
 
@@ -638,6 +1215,37 @@ func TestSonarrAddSeries(t *testing.T) {
 	assert.Equal(t, 1, count)
 }
 
+func TestSonarrAddSeries_Daily(t *testing.T) {
+	_, mux, db := setupServer(t, testAPIKey)
+
+	body := `{
+		"tvdbId": 71256,
+		"title": "The Daily Show",
+		"year": 1996,
+		"qualityProfileId": 1,
+		"rootFolderPath": "/series",
+		"seriesType": "daily",
+		"monitored": true,
+		"seasons": [],
+		"addOptions": {"searchForMissingEpisodes": false}
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v3/series", strings.NewReader(body))
+	req.Header.Set("X-Api-Key", testAPIKey)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, "response body: %s", w.Body.String())
+
+	var resp sonarrSeriesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "daily", resp.SeriesType)
+
+	content, err := library.NewStore(db).GetContent(resp.ID)
+	require.NoError(t, err)
+	assert.True(t, content.Daily)
+}
+
 func TestLanguageProfiles(t *testing.T) {
 	_, mux, _ := setupServer(t, testAPIKey)
 
@@ -684,9 +1292,50 @@ func TestGetSeries_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestGetSeries_SeasonStatistics(t *testing.T) {
+	_, mux, db := setupServer(t, testAPIKey)
+	lib := library.NewStore(db)
+
+	tvdbID := int64(81189)
+	series := &library.Content{Type: library.ContentTypeSeries, TVDBID: &tvdbID, Title: "Breaking Bad", Year: 2008, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, lib.AddContent(series))
+	aired := time.Date(2008, 1, 20, 0, 0, 0, 0, time.UTC)
+	ep1 := &library.Episode{ContentID: series.ID, Season: 1, Episode: 1, Status: library.StatusAvailable, AirDate: &aired}
+	require.NoError(t, lib.AddEpisode(ep1))
+	require.NoError(t, lib.AddEpisode(&library.Episode{ContentID: series.ID, Season: 1, Episode: 2, Status: library.StatusWanted}))
+	require.NoError(t, lib.AddEpisode(&library.Episode{ContentID: series.ID, Season: 2, Episode: 1, Status: library.StatusWanted}))
+	require.NoError(t, lib.AddFile(&library.File{ContentID: series.ID, EpisodeID: &ep1.ID, Path: "/tv/Breaking Bad/S01E01.mkv", SizeBytes: 1 << 30}))
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v3/series/%d", series.ID), nil)
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp sonarrSeriesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Seasons, 2)
+
+	s1 := resp.Seasons[0]
+	assert.True(t, s1.Monitored, "season with available episodes is monitored")
+	require.NotNil(t, s1.Statistics)
+	assert.Equal(t, 1, s1.Statistics.EpisodeFileCount)
+	assert.Equal(t, 2, s1.Statistics.TotalEpisodeCount)
+	assert.Equal(t, int64(1<<30), s1.Statistics.SizeOnDisk)
+	assert.InDelta(t, 50.0, s1.Statistics.PercentOfEpisodes, 0.001)
+	assert.Equal(t, "2008-01-20T00:00:00Z", s1.Statistics.PreviousAiring)
+
+	assert.False(t, resp.Seasons[1].Monitored)
+	require.NotNil(t, resp.Statistics)
+	assert.Equal(t, 2, resp.Statistics.SeasonCount)
+	assert.Equal(t, 1, resp.Statistics.EpisodeFileCount)
+	assert.Equal(t, 3, resp.Statistics.TotalEpisodeCount)
+	assert.Equal(t, int64(1<<30), resp.Statistics.SizeOnDisk)
+}
+
 func TestSonarrAddSeries_WithAutoSearch(t *testing.T) {
 	// Set up database and stores
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	lib := library.NewStore(db)
 	dlStore := download.NewStore(db)
 
@@ -788,7 +1437,7 @@ func TestSonarrAddSeries_WithAutoSearch(t *testing.T) {
 
 func TestSonarrAddSeries_WithAutoSearch_MultipleSeasons(t *testing.T) {
 	// Set up database and stores
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	lib := library.NewStore(db)
 	dlStore := download.NewStore(db)
 
@@ -902,3 +1551,235 @@ func TestSonarrAddSeries_WithAutoSearch_MultipleSeasons(t *testing.T) {
 	assert.True(t, receivedSeasons[2], "should grab season 2")
 	assert.False(t, receivedSeasons[3], "should NOT grab season 3 (not monitored)")
 }
+
+func TestSearchAndGrabSeries_StopsOnShutdown(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	bus := events.NewBus(nil, testLogger)
+	t.Cleanup(func() { bus.Close() })
+	grabChan := bus.Subscribe(events.EventGrabRequested, 10)
+
+	baseCtx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+
+	// The first season search hangs until the server shuts down
+	ctrl := gomock.NewController(t)
+	mockIndexer := mocks.NewMockIndexerAPI(ctrl)
+	mockIndexer.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, q search.Query) ([]search.Release, []error) {
+			shutdown()
+			<-ctx.Done()
+			return nil, []error{ctx.Err()}
+		}).
+		Times(1)
+
+	srv := New(Config{APIKey: testAPIKey, SeriesRoot: testSeriesRoot}, library.NewStore(db), download.NewStore(db), testLogger)
+	srv.SetSearcher(search.NewSearcher(mockIndexer, search.NewScorer(nil), testLogger))
+	srv.SetBus(bus)
+	srv.SetContext(baseCtx)
+
+	done := make(chan struct{})
+	go func() {
+		srv.searchAndGrabSeries(1, "Some Show", "hd", []int{1, 2, 3})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("background search did not stop on shutdown")
+	}
+	assert.Empty(t, grabChan, "no grabs after shutdown")
+}
+
+func TestSearchAndGrabSeries_SearchesEpisodesWhenFewMissing(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	lib := library.NewStore(db)
+	testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	bus := events.NewBus(nil, testLogger)
+	t.Cleanup(func() { bus.Close() })
+	grabChan := bus.Subscribe(events.EventGrabRequested, 10)
+
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Some Show", Year: 2020, Status: library.StatusWanted, QualityProfile: "hd", RootPath: testSeriesRoot}
+	require.NoError(t, lib.AddContent(series))
+	aired := time.Now().AddDate(0, -1, 0)
+	var finale *library.Episode
+	for n := 1; n <= 10; n++ {
+		ep := &library.Episode{ContentID: series.ID, Season: 1, Episode: n, Status: library.StatusAvailable, AirDate: &aired}
+		if n == 10 {
+			ep.Status = library.StatusWanted
+			finale = ep
+		}
+		require.NoError(t, lib.AddEpisode(ep))
+	}
+
+	// Only the finale is missing, so it is searched on its own instead of a season pack
+	mockIndexer := mocks.NewMockIndexerAPI(gomock.NewController(t))
+	mockIndexer.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query) ([]search.Release, []error) {
+			require.NotNil(t, q.Episode)
+			assert.Equal(t, 10, *q.Episode)
+			return []search.Release{
+				{Title: "Some.Show.S01E10.1080p.WEB-DL.x264-GRP", GUID: "e10", Indexer: "test", DownloadURL: "http://nzb/e10"},
+			}, nil
+		})
+	scorer := search.NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+
+	srv := New(Config{APIKey: testAPIKey, SeriesRoot: testSeriesRoot}, lib, download.NewStore(db), testLogger)
+	srv.SetSearcher(search.NewSearcher(mockIndexer, scorer, testLogger))
+	srv.SetBus(bus)
+	srv.searchAndGrabSeries(series.ID, series.Title, "hd", []int{1})
+
+	select {
+	case evt := <-grabChan:
+		grab, ok := evt.(*events.GrabRequested)
+		require.True(t, ok)
+		assert.Equal(t, "e10", grab.GUID)
+		assert.False(t, grab.IsCompleteSeason)
+		assert.Equal(t, []int64{finale.ID}, grab.EpisodeIDs)
+		require.NotNil(t, grab.Decision)
+		assert.Equal(t, search.StrategyEpisodes, grab.Decision.Strategy)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the episode grab")
+	}
+}
+
+func TestLinkGrabEpisodes(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	lib := library.NewStore(db)
+	srv := New(Config{APIKey: testAPIKey, SeriesRoot: testSeriesRoot}, lib, download.NewStore(db), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	series := fixtures.NewSeries("Some Show", 2020).Insert(t, db)
+	season := 2
+
+	// The best result of a season search is a single episode
+	grab := &events.GrabRequested{ContentID: series.ID, Season: &season, IsCompleteSeason: true,
+		ReleaseName: "Some.Show.S02E05.1080p.WEB-DL.x264-GRP"}
+	require.NoError(t, srv.linkGrabEpisodes(series, grab))
+
+	episodes, _, err := lib.ListEpisodes(library.EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	require.Len(t, episodes, 1, "episode record created")
+	assert.Equal(t, 2, episodes[0].Season)
+	assert.Equal(t, 5, episodes[0].Episode)
+	assert.Equal(t, []int64{episodes[0].ID}, grab.EpisodeIDs)
+	require.NotNil(t, grab.EpisodeID)
+	assert.Equal(t, episodes[0].ID, *grab.EpisodeID)
+	assert.False(t, grab.IsCompleteSeason)
+
+	// Season packs stay packs
+	pack := &events.GrabRequested{ContentID: series.ID, Season: &season, IsCompleteSeason: true,
+		ReleaseName: "Some.Show.S02.1080p.WEB-DL.x264-GRP"}
+	require.NoError(t, srv.linkGrabEpisodes(series, pack))
+	assert.Empty(t, pack.EpisodeIDs)
+	assert.True(t, pack.IsCompleteSeason)
+}
+
+func TestSeasonProfile(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	lib := library.NewStore(db)
+	testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := New(Config{APIKey: testAPIKey, SeriesRoot: testSeriesRoot}, lib, download.NewStore(db), testLogger)
+
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Some Show", Year: 2020, Status: library.StatusWanted, QualityProfile: "uhd", RootPath: testSeriesRoot}
+	require.NoError(t, lib.AddContent(series))
+	episodes := []*library.Episode{
+		{Season: 1, Episode: 1, QualityProfile: "hd"},
+		{Season: 1, Episode: 2, QualityProfile: "hd"},
+		{Season: 2, Episode: 1, QualityProfile: "hd"},
+		{Season: 2, Episode: 2},
+		{Season: 3, Episode: 1},
+	}
+	for _, ep := range episodes {
+		ep.ContentID = series.ID
+		ep.Status = library.StatusWanted
+		require.NoError(t, lib.AddEpisode(ep))
+	}
+
+	assert.Equal(t, "hd", srv.seasonProfile(series.ID, 1, "uhd"), "shared override")
+	assert.Equal(t, "uhd", srv.seasonProfile(series.ID, 2, "uhd"), "mixed season uses the series profile")
+	assert.Equal(t, "uhd", srv.seasonProfile(series.ID, 3, "uhd"), "no override")
+	assert.Equal(t, "uhd", srv.seasonProfile(series.ID, 4, "uhd"), "no episodes")
+}
+
+func TestSonarrSeriesSearch_SearchesMonitoredSeasons(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	lib := library.NewStore(db)
+	testLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	bus := events.NewBus(nil, testLogger)
+	t.Cleanup(func() { bus.Close() })
+
+	var mu sync.Mutex
+	var searchedSeasons []int
+	mockIndexer := mocks.NewMockIndexerAPI(gomock.NewController(t))
+	mockIndexer.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query) ([]search.Release, []error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if q.Season != nil {
+				searchedSeasons = append(searchedSeasons, *q.Season)
+			}
+			return nil, nil
+		}).
+		AnyTimes()
+	scorer := search.NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+
+	var pendingTasks sync.WaitGroup
+	srv := New(Config{APIKey: testAPIKey, SeriesRoot: testSeriesRoot, QualityProfiles: map[string]int{"hd": 1}},
+		lib, download.NewStore(db), testLogger)
+	srv.SetSearcher(search.NewSearcher(mockIndexer, scorer, testLogger))
+	srv.SetBus(bus)
+	srv.SetPendingWaitGroup(&pendingTasks)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Overseerr requests only season 3
+	body := `{
+		"tvdbId": 77777,
+		"title": "Season Three Show",
+		"qualityProfileId": 1,
+		"monitored": true,
+		"seasons": [
+			{"seasonNumber": 0, "monitored": false},
+			{"seasonNumber": 1, "monitored": false},
+			{"seasonNumber": 2, "monitored": false},
+			{"seasonNumber": 3, "monitored": true}
+		],
+		"addOptions": {"searchForMissingEpisodes": false}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v3/series", strings.NewReader(body))
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, "response body: %s", w.Body.String())
+
+	var added sonarrSeriesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &added))
+	assert.Equal(t, []sonarrSeason{
+		{SeasonNumber: 1, Monitored: false},
+		{SeasonNumber: 2, Monitored: false},
+		{SeasonNumber: 3, Monitored: true},
+	}, added.Seasons, "response reports the requested seasons")
+
+	// Episodes synced later in unrequested seasons are skipped
+	fixtures.NewEpisode(added.ID, 1, 1).Insert(t, db)
+	fixtures.NewEpisode(added.ID, 3, 1).Insert(t, db)
+	changed, err := lib.ApplySeasonMonitoring(added.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	// SeriesSearch searches exactly the monitored seasons
+	req = httptest.NewRequest(http.MethodPost, "/api/v3/command",
+		strings.NewReader(fmt.Sprintf(`{"name": "SeriesSearch", "seriesId": %d}`, added.ID)))
+	req.Header.Set("X-Api-Key", testAPIKey)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	pendingTasks.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int{3}, searchedSeasons)
+}
@@ -0,0 +1,213 @@
+package requestlog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/api/auth"
+	"github.com/vmunix/arrgo/internal/events"
+)
+
+// maxAuditBody is how much of a JSON request body is read to summarize it.
+const maxAuditBody = 1 << 20
+
+// auditPrefixes are the API paths whose state-changing requests are audited.
+var auditPrefixes = []string{"/api/v1/", "/api/v3/"}
+
+// AuditLog persists audit entries.
+// Satisfied by *events.EventLog.
+type AuditLog interface {
+	Append(e events.Event) (int64, error)
+}
+
+// Audit records an api.audit event for every POST, PUT, PATCH and DELETE
+// request to the native and compat APIs, after the handler has run and
+// whatever status it returned, including when it panics.
+//
+// The entry keeps the path wildcards, the query with API keys redacted, and
+// the IDs and flags of a JSON body (numbers, booleans, and lists of numbers)
+// rather than the body itself, which may carry titles, URLs or uploads. A
+// request presenting an API key is identified by a fingerprint of the key,
+// and by the key's name when auth.Middleware authenticated it.
+func Audit(next http.Handler, log AuditLog, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !audited(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		body := auditBody(r)
+		wrapped := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			p := recover()
+			if p != nil && !wrapped.wrote {
+				wrapped.status = http.StatusInternalServerError
+			}
+			entry := auditEntry(r, body, wrapped.status, time.Since(start))
+			if _, err := log.Append(entry); err != nil {
+				logger.Error("failed to record api audit", "method", r.Method, "path", r.URL.Path, "error", err)
+			}
+			if p != nil {
+				panic(p)
+			}
+		}()
+		next.ServeHTTP(wrapped, r)
+	})
+}
+
+// audited reports whether a request is one Audit records.
+func audited(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return false
+	}
+	for _, prefix := range auditPrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func auditEntry(r *http.Request, body map[string]any, status int, elapsed time.Duration) *events.APIAudit {
+	entry := &events.APIAudit{
+		BaseEvent:  events.NewBaseEvent(events.EventAPIAudit, events.EntityAPI, 0),
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Route:      r.Pattern,
+		Body:       body,
+		Remote:     remoteIP(r),
+		KeyPrint:   keyFingerprint(r),
+		KeyName:    keyName(r),
+		Status:     status,
+		DurationMS: elapsed.Milliseconds(),
+	}
+
+	// ServeMux sets the matched pattern on the request it was given, so
+	// wildcards can be read back by name
+	if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+		for _, segment := range strings.Split(path, "/") {
+			name, ok := strings.CutPrefix(segment, "{")
+			if !ok {
+				continue
+			}
+			name = strings.TrimSuffix(strings.TrimSuffix(name, "}"), "...")
+			if value := r.PathValue(name); value != "" {
+				if entry.Params == nil {
+					entry.Params = make(map[string]string)
+				}
+				entry.Params[name] = value
+			}
+		}
+	}
+
+	if r.URL.RawQuery != "" {
+		if values, err := url.ParseQuery(ScrubQuery(r.URL.RawQuery)); err == nil {
+			entry.Query = make(map[string]string, len(values))
+			for key, vals := range values {
+				entry.Query[key] = strings.Join(vals, ",")
+			}
+		}
+	}
+	return entry
+}
+
+// auditBody summarizes a JSON request body, leaving the body for the handler
+// to read in full. Returns nil for other content types.
+func auditBody(r *http.Request) map[string]any {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxAuditBody))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), r.Body), r.Body}
+	if err != nil {
+		return nil
+	}
+
+	var decoded map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&decoded); err != nil {
+		return nil
+	}
+	return summarize(decoded)
+}
+
+// summarize keeps the numbers, booleans and lists of numbers of a JSON object,
+// descending into nested objects; nil if nothing is kept.
+func summarize(obj map[string]any) map[string]any {
+	out := make(map[string]any)
+	for key, value := range obj {
+		switch v := value.(type) {
+		case json.Number, bool:
+			out[key] = v
+		case []any:
+			if len(v) > 0 && allNumbers(v) {
+				out[key] = v
+			}
+		case map[string]any:
+			if nested := summarize(v); nested != nil {
+				out[key] = nested
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func allNumbers(values []any) bool {
+	for _, v := range values {
+		if _, ok := v.(json.Number); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// remoteIP returns the IP the request came from, without the port.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// keyFingerprint returns the first 16 hex characters of the SHA-256 of the
+// API key presented, or "" without one; enough to tell keys apart without
+// recording them.
+func keyFingerprint(r *http.Request) string {
+	key := auth.Presented(r)
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// keyName returns the name of the API key the request authenticated as, or
+// "" when it wasn't authenticated.
+func keyName(r *http.Request) string {
+	key, _ := auth.FromContext(r.Context())
+	return key.Name
+}
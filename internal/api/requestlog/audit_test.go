@@ -0,0 +1,88 @@
+package requestlog
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmunix/arrgo/internal/api/auth"
+	"github.com/vmunix/arrgo/internal/events"
+)
+
+type fakeAuditLog struct {
+	mu      sync.Mutex
+	entries []*events.APIAudit
+}
+
+func (f *fakeAuditLog) Append(e events.Event) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = append(f.entries, e.(*events.APIAudit))
+	return int64(len(f.entries)), nil
+}
+
+func TestAudit(t *testing.T) {
+	log := &fakeAuditLog{}
+	var gotBody string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /api/v1/content/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("POST /api/v3/movie", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /api/v1/content", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("POST /api/v1/panic", func(http.ResponseWriter, *http.Request) { panic("boom") })
+	h := Audit(mux, log, slog.New(slog.DiscardHandler))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/content/42?delete_files=true", nil)
+	req.RemoteAddr = "192.0.2.1:51234"
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	body := `{"title":"Dune","tmdbId":438631,"monitored":true,"tags":[1,2],"addOptions":{"searchForMovie":true,"note":"x"}}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v3/movie?apikey=secret", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req = req.WithContext(auth.WithKey(req.Context(), auth.Key{Name: "automation", Scope: auth.ScopeWrite}))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/content", nil))
+	assert.Panics(t, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/panic", nil))
+	})
+
+	require.Len(t, log.entries, 3, "GET requests aren't audited")
+
+	del := log.entries[0]
+	assert.Equal(t, events.EventAPIAudit, del.EventType())
+	assert.Equal(t, "DELETE /api/v1/content/{id}", del.Route)
+	assert.Equal(t, map[string]string{"id": "42"}, del.Params)
+	assert.Equal(t, map[string]string{"delete_files": "true"}, del.Query)
+	assert.Equal(t, "192.0.2.1", del.Remote)
+	assert.Equal(t, http.StatusInternalServerError, del.Status, "failed attempts are recorded")
+	assert.Empty(t, del.KeyPrint)
+	assert.Empty(t, del.KeyName)
+
+	add := log.entries[1]
+	assert.Equal(t, body, gotBody, "handler still reads the whole body")
+	assert.Equal(t, http.StatusCreated, add.Status)
+	assert.Equal(t, map[string]string{"apikey": "REDACTED"}, add.Query)
+	assert.Len(t, add.KeyPrint, 16)
+	assert.NotContains(t, add.KeyPrint, "secret")
+	assert.Equal(t, "automation", add.KeyName)
+	summary, err := json.Marshal(add.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tmdbId":438631,"monitored":true,"tags":[1,2],"addOptions":{"searchForMovie":true}}`, string(summary))
+
+	assert.Equal(t, http.StatusInternalServerError, log.entries[2].Status, "panicking handler recorded as 500")
+}
@@ -0,0 +1,112 @@
+package requestlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the histogram upper bounds in seconds.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// routeMetrics holds the counters for one route.
+type routeMetrics struct {
+	count   uint64
+	sum     float64   // Total latency in seconds
+	buckets []uint64  // Per-bucket counts (not cumulative), one per latencyBuckets entry
+	classes [6]uint64 // Requests by status class; index 2 is 2xx, 5 is 5xx
+}
+
+// Metrics is an in-memory registry of request counts and latencies per route.
+// It is safe for concurrent use.
+type Metrics struct {
+	mu     sync.Mutex
+	routes map[string]*routeMetrics
+}
+
+// NewMetrics creates an empty metrics registry.
+func NewMetrics() *Metrics {
+	return &Metrics{routes: make(map[string]*routeMetrics)}
+}
+
+// Observe records a request for route with its status code and duration.
+func (m *Metrics) Observe(route string, status int, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rm, ok := m.routes[route]
+	if !ok {
+		rm = &routeMetrics{buckets: make([]uint64, len(latencyBuckets))}
+		m.routes[route] = rm
+	}
+
+	seconds := d.Seconds()
+	rm.count++
+	rm.sum += seconds
+	for i, le := range latencyBuckets {
+		if seconds <= le {
+			rm.buckets[i]++
+			break
+		}
+	}
+	if class := status / 100; class >= 1 && class <= 5 {
+		rm.classes[class]++
+	}
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	routes := make([]string, 0, len(m.routes))
+	for route := range m.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "# HELP arrgo_http_requests_total HTTP requests by route and status class.")
+	fmt.Fprintln(bw, "# TYPE arrgo_http_requests_total counter")
+	for _, route := range routes {
+		rm := m.routes[route]
+		for class := 1; class <= 5; class++ {
+			if rm.classes[class] == 0 {
+				continue
+			}
+			fmt.Fprintf(bw, "arrgo_http_requests_total{route=%s,code=\"%dxx\"} %d\n",
+				quoteLabel(route), class, rm.classes[class])
+		}
+	}
+
+	fmt.Fprintln(bw, "# HELP arrgo_http_request_duration_seconds HTTP request latency by route.")
+	fmt.Fprintln(bw, "# TYPE arrgo_http_request_duration_seconds histogram")
+	for _, route := range routes {
+		rm := m.routes[route]
+		label := quoteLabel(route)
+		var cumulative uint64
+		for i, le := range latencyBuckets {
+			cumulative += rm.buckets[i]
+			fmt.Fprintf(bw, "arrgo_http_request_duration_seconds_bucket{route=%s,le=\"%s\"} %d\n",
+				label, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(bw, "arrgo_http_request_duration_seconds_bucket{route=%s,le=\"+Inf\"} %d\n", label, rm.count)
+		fmt.Fprintf(bw, "arrgo_http_request_duration_seconds_sum{route=%s} %s\n",
+			label, strconv.FormatFloat(rm.sum, 'g', -1, 64))
+		fmt.Fprintf(bw, "arrgo_http_request_duration_seconds_count{route=%s} %d\n", label, rm.count)
+	}
+
+	return bw.Flush()
+}
+
+// quoteLabel quotes a label value, escaping backslashes, quotes and newlines.
+func quoteLabel(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(v) + `"`
+}
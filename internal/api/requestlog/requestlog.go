@@ -0,0 +1,93 @@
+// Package requestlog provides HTTP request logging and per-route metrics
+// shared by the native and compat APIs.
+package requestlog
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// unmatchedRoute labels requests that matched no registered pattern,
+// so probes for random paths don't create a metric series each.
+const unmatchedRoute = "unmatched"
+
+// redacted replaces sensitive query parameter values in logs.
+const redacted = "REDACTED"
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wrote { // Only capture first WriteHeader call
+		r.status = code
+		r.wrote = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wrote = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Middleware logs each request and records it in m.
+// Requests slower than slow are logged at WARN; zero disables slow request warnings.
+// Routes are identified by their ServeMux pattern, e.g. "GET /api/v1/content/{id}".
+func Middleware(next http.Handler, log *slog.Logger, m *Metrics, slow time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		wrapped := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(wrapped, r)
+		elapsed := time.Since(start)
+
+		// ServeMux sets the matched pattern on the request it was given
+		route := r.Pattern
+		if route == "" {
+			route = unmatchedRoute
+		}
+		if m != nil {
+			m.Observe(route, wrapped.status, elapsed)
+		}
+
+		level := slog.LevelInfo
+		if slow > 0 && elapsed > slow {
+			level = slog.LevelWarn
+		}
+		attrs := []any{
+			"method", r.Method,
+			"route", route,
+			"path", r.URL.Path,
+			"status", wrapped.status,
+			"duration_ms", elapsed.Milliseconds(),
+			"remote", r.RemoteAddr,
+		}
+		if r.URL.RawQuery != "" {
+			attrs = append(attrs, "query", ScrubQuery(r.URL.RawQuery))
+		}
+		log.Log(r.Context(), level, "http request", attrs...)
+	})
+}
+
+// ScrubQuery redacts API keys from a raw query string.
+// The compat API accepts ?apikey= as an alternative to the X-Api-Key header.
+func ScrubQuery(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		// Don't risk logging a key we failed to parse out
+		return redacted
+	}
+	for key := range values {
+		switch strings.ToLower(key) {
+		case "apikey", "api_key":
+			values[key] = []string{redacted}
+		}
+	}
+	return values.Encode()
+}
@@ -0,0 +1,83 @@
+package requestlog
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrubQuery(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"apikey=secret", "apikey=REDACTED"},
+		{"ApiKey=secret&term=dune", "ApiKey=REDACTED&term=dune"},
+		{"api_key=secret", "api_key=REDACTED"},
+		{"term=dune", "term=dune"},
+		{"%zz=secret", "REDACTED"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ScrubQuery(tt.raw), tt.raw)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&logs, nil))
+	m := NewMetrics()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/movie/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /slow", func(_ http.ResponseWriter, _ *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+	})
+	h := Middleware(mux, log, m, time.Millisecond)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v3/movie/42?apikey=secret", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	out := logs.String()
+	assert.NotContains(t, out, "secret")
+	assert.Contains(t, out, `route="GET /api/v3/movie/{id}"`)
+	assert.Contains(t, out, "status=404")
+	assert.Contains(t, out, `query="apikey=REDACTED"`)
+	assert.Contains(t, out, `level=WARN msg="http request" method=GET route="GET /slow"`)
+
+	var prom bytes.Buffer
+	require.NoError(t, m.WritePrometheus(&prom))
+	metrics := prom.String()
+	assert.Contains(t, metrics, `arrgo_http_requests_total{route="GET /api/v3/movie/{id}",code="4xx"} 1`)
+	assert.Contains(t, metrics, `arrgo_http_requests_total{route="GET /slow",code="2xx"} 1`)
+	assert.Contains(t, metrics, `arrgo_http_requests_total{route="unmatched",code="4xx"} 1`)
+	assert.Contains(t, metrics, `arrgo_http_request_duration_seconds_count{route="GET /slow"} 1`)
+}
+
+func TestMetrics_Histogram(t *testing.T) {
+	m := NewMetrics()
+	m.Observe("GET /a", http.StatusOK, 3*time.Millisecond)
+	m.Observe("GET /a", http.StatusOK, 200*time.Millisecond)
+	m.Observe("GET /a", http.StatusInternalServerError, 20*time.Second)
+
+	var buf bytes.Buffer
+	require.NoError(t, m.WritePrometheus(&buf))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Contains(t, lines, `arrgo_http_request_duration_seconds_bucket{route="GET /a",le="0.005"} 1`)
+	assert.Contains(t, lines, `arrgo_http_request_duration_seconds_bucket{route="GET /a",le="0.25"} 2`)
+	assert.Contains(t, lines, `arrgo_http_request_duration_seconds_bucket{route="GET /a",le="10"} 2`)
+	assert.Contains(t, lines, `arrgo_http_request_duration_seconds_bucket{route="GET /a",le="+Inf"} 3`)
+	assert.Contains(t, lines, `arrgo_http_request_duration_seconds_count{route="GET /a"} 3`)
+	assert.Contains(t, lines, `arrgo_http_requests_total{route="GET /a",code="2xx"} 2`)
+	assert.Contains(t, lines, `arrgo_http_requests_total{route="GET /a",code="5xx"} 1`)
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// aliasToResponse converts a library alias to its API representation.
+func aliasToResponse(a *library.Alias) aliasResponse {
+	return aliasResponse{
+		ID:        a.ID,
+		ContentID: a.ContentID,
+		Alias:     a.Alias,
+		Source:    string(a.Source),
+		AddedAt:   a.AddedAt,
+	}
+}
+
+// listAliases handles GET /api/v1/content/{id}/aliases.
+func (s *Server) listAliases(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	if _, err := s.deps.Library.GetContent(id); err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	aliases, err := s.deps.Library.ListAliases(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	resp := listAliasesResponse{
+		Items: make([]aliasResponse, len(aliases)),
+		Total: len(aliases),
+	}
+	for i, a := range aliases {
+		resp.Items[i] = aliasToResponse(a)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// addAlias handles POST /api/v1/content/{id}/aliases.
+// Manual aliases cover alternate titles TVDB doesn't list and are kept across syncs.
+func (s *Server) addAlias(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	var req addAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Alias) == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "alias is required")
+		return
+	}
+
+	a := &library.Alias{ContentID: id, Alias: req.Alias, Source: library.AliasSourceManual}
+	if err := s.deps.Library.AddAlias(a); err != nil {
+		switch {
+		case errors.Is(err, library.ErrNotFound):
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+		case errors.Is(err, library.ErrDuplicate):
+			writeError(w, http.StatusConflict, "DUPLICATE", "Alias matches the title or an existing alias")
+		case errors.Is(err, library.ErrConstraint):
+			writeError(w, http.StatusBadRequest, "INVALID_ALIAS", "alias must contain letters or digits")
+		default:
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, aliasToResponse(a))
+}
+
+// deleteAlias handles DELETE /api/v1/content/{id}/aliases/{alias_id}.
+func (s *Server) deleteAlias(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+	aliasID, err := strconv.ParseInt(r.PathValue("alias_id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	if err := s.deps.Library.DeleteAlias(id, aliasID); err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Alias not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
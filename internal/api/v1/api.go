@@ -10,14 +10,18 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/vmunix/arrgo/internal/api/auth"
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
 	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/profile"
 	"github.com/vmunix/arrgo/internal/search"
 	"github.com/vmunix/arrgo/pkg/release"
 )
@@ -26,10 +30,28 @@ const queryTrue = "true"
 
 // Config holds API server configuration.
 type Config struct {
-	MovieRoot       string
-	SeriesRoot      string
-	DownloadRoot    string // Root path for completed downloads (for tracked imports)
-	QualityProfiles map[string][]string
+	MovieRoot     string
+	SeriesRoot    string
+	MovieRoots    *library.RootFolders                     // Picks the root for new movies (nil: always MovieRoot)
+	SeriesRoots   *library.RootFolders                     // Picks the root for new series (nil: always SeriesRoot)
+	DownloadRoot  string                                   // Root path for completed downloads (for tracked imports)
+	Categories    map[download.Client]download.Categories  // Per-client categories (for grab dry runs)
+	PublicStatus  bool                                     // Serve the unauthenticated public summary
+	ClientPaths   map[download.Client]importer.PathMapping // Per-client remote to local download paths
+	AdoptExisting bool                                     // Adopt Plex's file when a movie Plex already has is added
+	// FilesReconciled means library files are checked on disk in the
+	// background, so checks read their missing flag instead of statting them
+	FilesReconciled bool
+	// How long a download may stay in each status before it's stuck (nil: download.DefaultStuckThresholds)
+	StuckThresholds map[download.Status]time.Duration
+	// Leftovers that don't keep a release directory from being removed on cancel (zero: none)
+	Junk importer.JunkRules
+	// How long an unreferenced download root entry must be unchanged to be an orphan (0: 7 days)
+	OrphanMinAge time.Duration
+
+	Audiobooks     bool                 // Audiobook content can be added
+	AudiobookRoot  string               // Root for new audiobooks
+	AudiobookRoots *library.RootFolders // Picks the root for new audiobooks (nil: always AudiobookRoot)
 }
 
 // Server is the v1 API server.
@@ -37,8 +59,30 @@ type Server struct {
 	deps    ServerDeps
 	cfg     Config
 	tvdbSvc TVDBService
+	tmdbSvc TMDBService
+
+	previews *previewCache // Releases from recent previews, grabbable by GUID
+
+	baseCtx context.Context // Parent of background work; canceled on shutdown
+
+	checkMu      sync.Mutex
+	checkRunning bool // A background library check is running
+
+	health healthCache // Last readiness checks of GET /health
 }
 
+// stuckThresholds returns how long a download may stay in each status
+// before it counts as stuck.
+func (s *Server) stuckThresholds() map[download.Status]time.Duration {
+	if s.cfg.StuckThresholds != nil {
+		return s.cfg.StuckThresholds
+	}
+	return download.DefaultStuckThresholds()
+}
+
+// tvdbSyncTimeout bounds a background episode sync started by a request.
+const tvdbSyncTimeout = 2 * time.Minute
+
 // NewWithDeps creates a new v1 API server with explicit dependencies.
 // Required dependencies (Library, Downloads, History) must be non-nil.
 // Optional dependencies (Searcher, Manager, Plex, Importer) may be nil.
@@ -46,7 +90,7 @@ func NewWithDeps(deps ServerDeps, cfg Config) (*Server, error) {
 	if err := deps.Validate(); err != nil {
 		return nil, err
 	}
-	return &Server{deps: deps, cfg: cfg}, nil
+	return &Server{deps: deps, cfg: cfg, previews: newPreviewCache(releasePreviewTTL)}, nil
 }
 
 // New creates a new v1 API server with default stores from the database.
@@ -57,8 +101,57 @@ func New(db *sql.DB, cfg Config) *Server {
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
+		Profiles:  profile.NewStore(db),
+		DB:        db,
+	}
+	return &Server{deps: deps, cfg: cfg, previews: newPreviewCache(releasePreviewTTL)}
+}
+
+// defaultRoot picks the root folder for new content of the given type.
+func (s *Server) defaultRoot(contentType library.ContentType) string {
+	switch contentType {
+	case library.ContentTypeSeries:
+		if s.cfg.SeriesRoots != nil {
+			return s.cfg.SeriesRoots.Select()
+		}
+		return s.cfg.SeriesRoot
+	case library.ContentTypeAudiobook:
+		if s.cfg.AudiobookRoots != nil {
+			return s.cfg.AudiobookRoots.Select()
+		}
+		return s.cfg.AudiobookRoot
+	}
+	if s.cfg.MovieRoots != nil {
+		return s.cfg.MovieRoots.Select()
+	}
+	return s.cfg.MovieRoot
+}
+
+// contentTypeAllowed reports whether content of the type can be added:
+// movies and series always, audiobooks when their library is enabled.
+func (s *Server) contentTypeAllowed(t library.ContentType) bool {
+	switch t {
+	case library.ContentTypeMovie, library.ContentTypeSeries:
+		return true
+	case library.ContentTypeAudiobook:
+		return s.cfg.Audiobooks
+	}
+	return false
+}
+
+// contentTypeMessage describes the content types contentTypeAllowed accepts.
+func (s *Server) contentTypeMessage() string {
+	if s.cfg.Audiobooks {
+		return "type must be 'movie', 'series' or 'audiobook'"
 	}
-	return &Server{deps: deps, cfg: cfg}
+	return "type must be 'movie' or 'series'"
+}
+
+// SetContext sets the parent context for background work started by
+// requests, such as episode syncs. Canceling it stops that work
+// (default: never canceled).
+func (s *Server) SetContext(ctx context.Context) {
+	s.baseCtx = ctx
 }
 
 // SetTVDB configures the TVDB service (optional).
@@ -66,6 +159,11 @@ func (s *Server) SetTVDB(svc TVDBService) {
 	s.tvdbSvc = svc
 }
 
+// SetTMDB configures the TMDB service (optional).
+func (s *Server) SetTMDB(svc TMDBService) {
+	s.tmdbSvc = svc
+}
+
 // syncEpisodesFromTVDB fetches episodes from TVDB and creates Episode records.
 // This runs in the background and logs errors but doesn't fail the request.
 func (s *Server) syncEpisodesFromTVDB(contentID int64, tvdbID int) {
@@ -73,7 +171,12 @@ func (s *Server) syncEpisodesFromTVDB(contentID int64, tvdbID int) {
 		return
 	}
 
-	ctx := context.Background()
+	parent := s.baseCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, tvdbSyncTimeout)
+	defer cancel()
 
 	episodes, err := s.tvdbSvc.GetEpisodes(ctx, tvdbID)
 	if err != nil {
@@ -104,11 +207,30 @@ func (s *Server) syncEpisodesFromTVDB(contentID int64, tvdbID int) {
 		})
 	}
 
-	_, _ = s.deps.Library.BulkAddEpisodes(libEpisodes)
+	if _, err := s.deps.Library.BulkAddEpisodes(libEpisodes); err != nil {
+		return
+	}
+	// Skip episodes in seasons that weren't requested
+	_, _ = s.deps.Library.ApplySeasonMonitoring(contentID)
+	_, _ = s.syncAliases(ctx, contentID, tvdbID)
+}
+
+// syncAliases replaces a series' TVDB aliases with the ones TVDB lists now
+// and stores its status. Returns the number of aliases added.
+func (s *Server) syncAliases(ctx context.Context, contentID int64, tvdbID int) (int, error) {
+	series, err := s.tvdbSvc.GetSeries(ctx, tvdbID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.deps.Library.UpdateSeriesStatus(contentID, series.Status); err != nil {
+		return 0, err
+	}
+	return s.deps.Library.ReplaceAliases(contentID, library.AliasSourceTVDB, series.Aliases)
 }
 
 // syncEpisodes handles POST /api/v1/content/{id}/sync-episodes.
-// Fetches episodes from TVDB and populates the database.
+// Re-fetches episodes from TVDB, adding new ones as wanted and updating
+// changed titles and air dates, like the daily sync of continuing series.
 func (s *Server) syncEpisodes(w http.ResponseWriter, r *http.Request) {
 	id, err := pathID(r)
 	if err != nil {
@@ -136,50 +258,60 @@ func (s *Server) syncEpisodes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if s.tvdbSvc == nil {
+	if s.deps.Episodes == nil {
 		writeError(w, http.StatusServiceUnavailable, "TVDB_NOT_CONFIGURED", "TVDB service not available")
 		return
 	}
 
-	// Fetch and insert episodes (synchronously for this endpoint)
-	episodes, err := s.tvdbSvc.GetEpisodes(r.Context(), int(*c.TVDBID))
+	result, err := s.deps.Episodes.Sync(r.Context(), c)
 	if err != nil {
 		writeError(w, http.StatusBadGateway, "TVDB_ERROR", err.Error())
 		return
 	}
 
-	// Convert to library.Episode
-	libEpisodes := make([]*library.Episode, 0, len(episodes))
-	for _, ep := range episodes {
-		if ep.Season == 0 || ep.Episode == 0 {
-			continue
-		}
-		var airDate *time.Time
-		if !ep.AirDate.IsZero() {
-			airDate = &ep.AirDate
-		}
-		libEpisodes = append(libEpisodes, &library.Episode{
-			ContentID: id,
-			Season:    ep.Season,
-			Episode:   ep.Episode,
-			Title:     ep.Name,
-			Status:    library.StatusWanted,
-			AirDate:   airDate,
-		})
+	writeJSON(w, http.StatusOK, syncEpisodesResponse{
+		ContentID:  id,
+		TVDBID:     *c.TVDBID,
+		Total:      result.Total,
+		Inserted:   result.Inserted,
+		Updated:    result.Updated,
+		Aliases:    result.Aliases,
+		NewSeasons: result.NewSeasons,
+	})
+}
+
+// refreshMetadata handles POST /api/v1/content/{id}/refresh-metadata.
+// Fetches overview, poster, runtime, and genres from TMDB (movies) or TVDB (series).
+// If no provider is configured for the content, it is returned unchanged.
+func (s *Server) refreshMetadata(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
 	}
 
-	inserted, err := s.deps.Library.BulkAddEpisodes(libEpisodes)
+	c, err := s.deps.Library.GetContent(id)
 	if err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"content_id": id,
-		"tvdb_id":    *c.TVDBID,
-		"total":      len(libEpisodes),
-		"inserted":   inserted,
-	})
+	if s.deps.Metadata != nil {
+		if _, err := s.deps.Metadata.Refresh(r.Context(), c); err != nil {
+			writeError(w, http.StatusBadGateway, "METADATA_ERROR", err.Error())
+			return
+		}
+	}
+
+	var stats *library.SeriesStats
+	if c.Type == library.ContentTypeSeries {
+		stats, _ = s.deps.Library.GetSeriesStats(c.ID)
+	}
+	writeJSON(w, http.StatusOK, s.contentResponse(c, stats))
 }
 
 // RegisterRoutes registers API routes on the given mux.
@@ -190,44 +322,91 @@ func (s *Server) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/v1/content", s.addContent)
 	mux.HandleFunc("PUT /api/v1/content/{id}", s.updateContent)
 	mux.HandleFunc("DELETE /api/v1/content/{id}", s.deleteContent)
+	mux.HandleFunc("POST /api/v1/content/{id}/refresh-metadata", s.refreshMetadata)
+	mux.HandleFunc("GET /api/v1/content/{id}/events", s.listContentEvents)
+	mux.HandleFunc("GET /api/v1/content/{id}/releases", s.requireSearcher(s.previewReleases))
+	mux.HandleFunc("POST /api/v1/content/{id}/releases", s.requireManager(s.grabPreviewedRelease))
+	mux.HandleFunc("POST /api/v1/content/{id}/pin", s.pinRelease)
+	mux.HandleFunc("DELETE /api/v1/content/{id}/pin", s.unpinRelease)
+	mux.HandleFunc("GET /api/v1/content/{id}/blocklist", s.listBlocklist)
+	mux.HandleFunc("DELETE /api/v1/content/{id}/blocklist/{guid...}", s.unblockRelease)
+	mux.HandleFunc("GET /api/v1/content/{id}/aliases", s.listAliases)
+	mux.HandleFunc("POST /api/v1/content/{id}/aliases", s.addAlias)
+	mux.HandleFunc("DELETE /api/v1/content/{id}/aliases/{alias_id}", s.deleteAlias)
+	mux.HandleFunc("GET /api/v1/content/{id}/collection", s.getCollection)
+	mux.HandleFunc("POST /api/v1/content/{id}/collection/add-missing", s.addMissingCollection)
 
 	// Episodes
 	mux.HandleFunc("GET /api/v1/content/{id}/episodes", s.listEpisodes)
+	mux.HandleFunc("GET /api/v1/content/{id}/seasons", s.listSeasons)
+	mux.HandleFunc("PUT /api/v1/content/{id}/episodes/monitor", s.monitorEpisodes)
 	mux.HandleFunc("POST /api/v1/content/{id}/sync-episodes", s.syncEpisodes)
 	mux.HandleFunc("PUT /api/v1/episodes/{id}", s.updateEpisode)
+	mux.HandleFunc("GET /api/v1/episodes/{id}/files", s.listEpisodeFiles)
 
 	// Search & grab (require optional dependencies)
 	mux.HandleFunc("GET /api/v1/search", s.requireSearcher(s.search))
 	mux.HandleFunc("POST /api/v1/grab", s.requireManager(s.grab))
+	mux.HandleFunc("POST /api/v1/grab/upload", s.requireManager(s.grabUploadedFile))
 
 	// Downloads
 	mux.HandleFunc("GET /api/v1/downloads", s.listDownloads)
 	mux.HandleFunc("GET /api/v1/downloads/{id}", s.getDownload)
+	mux.HandleFunc("GET /api/v1/downloads/orphans", s.listOrphans)
+	mux.HandleFunc("POST /api/v1/downloads/orphans/clean", requireScope(auth.ScopeAdmin, s.cleanOrphans))
 	mux.HandleFunc("GET /api/v1/downloads/{id}/events", s.listDownloadEvents)
-	mux.HandleFunc("DELETE /api/v1/downloads/{id}", s.requireManager(s.deleteDownload))
+	mux.HandleFunc("GET /api/v1/downloads/{id}/samples", s.listDownloadSamples)
+	mux.HandleFunc("GET /api/v1/downloads/{id}/decision", s.getDownloadDecision)
+	mux.HandleFunc("DELETE /api/v1/downloads/{id}", requireScope(auth.ScopeAdmin, s.requireManager(s.deleteDownload)))
 	mux.HandleFunc("POST /api/v1/downloads/{id}/retry", s.requireManager(s.requireSearcher(s.retryDownload)))
+	mux.HandleFunc("POST /api/v1/downloads/{id}/reimport", s.requireImporter(s.reimportDownload))
+	mux.HandleFunc("POST /api/v1/downloads/{id}/import-next", s.importNext)
+	mux.HandleFunc("POST /api/v1/downloads/{id}/options", s.requireManager(s.setDownloadOptions))
+	mux.HandleFunc("PUT /api/v1/downloads/speed-limit", s.setSpeedLimit)
+
+	// Wanted
+	mux.HandleFunc("GET /api/v1/wanted", s.listWanted)
 
 	// History
 	mux.HandleFunc("GET /api/v1/history", s.listHistory)
 
 	// Events
 	mux.HandleFunc("GET /api/v1/events", s.listEvents)
+	mux.HandleFunc("POST /api/v1/events/{id}/replay", s.replayEvent)
+	mux.HandleFunc("GET /api/v1/audit", s.listAudit)
 
 	// Files
 	mux.HandleFunc("GET /api/v1/files", s.listFiles)
 	mux.HandleFunc("DELETE /api/v1/files/{id}", s.deleteFile)
+	mux.HandleFunc("POST /api/v1/files/{id}/verify", s.verifyFile)
 
 	// Library check - validates content records against actual files and Plex.
 	// Note: There is no /library resource. "Library" represents the validated state
-	// of content + files + Plex awareness, not a standalone entity. This endpoint
-	// performs cross-system health checks rather than CRUD operations.
+	// of content + files + Plex awareness, not a standalone entity. These endpoints
+	// perform cross-system health checks rather than CRUD operations. GET checks a
+	// few items synchronously; POST checks everything as a background job.
 	mux.HandleFunc("GET /api/v1/library/check", s.checkLibrary)
+	mux.HandleFunc("POST /api/v1/library/check", s.startLibraryCheck)
+	mux.HandleFunc("GET /api/v1/library/check/latest", s.getLatestLibraryCheck)
+	mux.HandleFunc("GET /api/v1/library/check/{id}", s.getLibraryCheck)
+	mux.HandleFunc("GET /api/v1/library/stats", s.libraryStats)
+
+	// Exclusions - external IDs never added to the library again
+	mux.HandleFunc("GET /api/v1/exclusions", s.listExclusions)
+	mux.HandleFunc("POST /api/v1/exclusions", s.addExclusion)
+	mux.HandleFunc("DELETE /api/v1/exclusions/{id}", s.deleteExclusion)
 
 	// System
 	mux.HandleFunc("GET /api/v1/status", s.getStatus)
+	mux.HandleFunc("GET /api/v1/health", s.getHealth)
 	mux.HandleFunc("GET /api/v1/dashboard", s.getDashboard)
+	mux.HandleFunc("GET /api/v1/metrics", s.getMetrics)
 	mux.HandleFunc("GET /api/v1/verify", s.verify)
 	mux.HandleFunc("GET /api/v1/profiles", s.listProfiles)
+	mux.HandleFunc("POST /api/v1/profiles", s.createProfile)
+	mux.HandleFunc("GET /api/v1/profiles/{name}", s.getProfile)
+	mux.HandleFunc("PUT /api/v1/profiles/{name}", s.updateProfile)
+	mux.HandleFunc("DELETE /api/v1/profiles/{name}", s.deleteProfile)
 	mux.HandleFunc("GET /api/v1/indexers", s.listIndexers)
 
 	// Plex (getPlexStatus handles nil gracefully, others require Plex)
@@ -235,15 +414,29 @@ func (s *Server) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/v1/plex/scan", s.requirePlex(s.scanPlexLibraries))
 	mux.HandleFunc("GET /api/v1/plex/libraries/{name}/items", s.requirePlex(s.listPlexLibraryItems))
 	mux.HandleFunc("GET /api/v1/plex/search", s.requirePlex(s.searchPlex))
+	mux.HandleFunc("GET /api/v1/plex/missing", s.requirePlex(s.getPlexMissing))
+	mux.HandleFunc("GET /api/v1/plex/path-mappings", s.requirePlex(s.listPathMappings))
+	mux.HandleFunc("POST /api/v1/plex/path-mappings/validate", s.requirePlex(s.validatePathMappings))
 
 	// Import
 	mux.HandleFunc("POST /api/v1/import", s.requireImporter(s.importContent))
 
 	// Library import (from external sources like Plex)
 	mux.HandleFunc("POST /api/v1/library/import", s.importLibrary)
+	mux.HandleFunc("POST /api/v1/library/rename", requireScope(auth.ScopeAdmin, s.requireImporter(s.renameLibrary)))
+
+	// Library snapshot (portable JSON backup)
+	mux.HandleFunc("GET /api/v1/export", s.exportLibrary)
+	mux.HandleFunc("POST /api/v1/import/snapshot", s.importSnapshot)
 
 	// TVDB metadata
 	mux.HandleFunc("GET /api/v1/tvdb/search", s.handleTVDBSearch)
+	mux.HandleFunc("GET /api/v1/lookup", s.lookup)
+
+	// Public status (opt-in, no authentication)
+	if s.cfg.PublicStatus {
+		mux.HandleFunc("GET "+PublicPathPrefix+"summary", s.publicSummary)
+	}
 }
 
 // Error response
@@ -295,10 +488,26 @@ func queryString(r *http.Request, name string) *string {
 	return &val
 }
 
-// fileExists checks if a file exists at the given path.
-func fileExists(path string) bool {
-	_, err := os.Stat(path)
-	return err == nil
+// queryOrder reads the order of a sorted list from the query string: "asc",
+// or "desc" (the default). ok is false for any other value.
+func queryOrder(r *http.Request) (desc, ok bool) {
+	switch r.URL.Query().Get("order") {
+	case "", "desc":
+		return true, true
+	case "asc":
+		return false, true
+	}
+	return false, false
+}
+
+// fileMissing reports whether a library file is gone from disk: its missing
+// flag when files are reconciled in the background, otherwise a stat.
+func (s *Server) fileMissing(f *library.File) bool {
+	if s.cfg.FilesReconciled {
+		return f.MissingAt != nil
+	}
+	_, err := os.Stat(f.Path)
+	return errors.Is(err, os.ErrNotExist)
 }
 
 // Handlers (stubs)
@@ -327,6 +536,22 @@ func (s *Server) listContent(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Recently added first unless asked otherwise
+	filter.Sort = library.ContentSortAddedAt
+	if sortStr := queryString(r, "sort"); sortStr != nil {
+		filter.Sort = library.ContentSort(*sortStr)
+		if !library.ValidContentSort(filter.Sort) {
+			writeError(w, http.StatusBadRequest, "INVALID_SORT", "sort must be one of added_at, updated_at, title, year")
+			return
+		}
+	}
+	desc, ok := queryOrder(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "INVALID_ORDER", "order must be 'asc' or 'desc'")
+		return
+	}
+	filter.Desc = desc
+
 	items, total, err := s.deps.Library.ListContent(filter)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
@@ -351,8 +576,10 @@ func (s *Server) listContent(w http.ResponseWriter, r *http.Request) {
 		Offset: filter.Offset,
 	}
 
+	met := s.qualityMetByContent(items)
 	for i, c := range items {
 		resp.Items[i] = contentToResponse(c, seriesStats[c.ID])
+		resp.Items[i].QualityMet = met[c.ID]
 	}
 
 	writeJSON(w, http.StatusOK, resp)
@@ -381,22 +608,103 @@ func (s *Server) getContent(w http.ResponseWriter, r *http.Request) {
 		stats, _ = s.deps.Library.GetSeriesStats(c.ID)
 	}
 
-	writeJSON(w, http.StatusOK, contentToResponse(c, stats))
+	writeJSON(w, http.StatusOK, s.contentResponse(c, stats))
+}
+
+// contentResponse converts a content item to its API response, with whether
+// its files meet the cutoff of its quality profile.
+func (s *Server) contentResponse(c *library.Content, stats *library.SeriesStats) contentResponse {
+	resp := contentToResponse(c, stats)
+	resp.QualityMet = s.qualityMetByContent([]*library.Content{c})[c.ID]
+	return resp
+}
+
+// qualityMetByContent reports, by content ID, whether the files of each item
+// meet the cutoff of its quality profile, loading the files in one query: a
+// movie's best file does, and for a series each episode's best file does.
+// Content with no file on disk has not met it; an audiobook has once it has a
+// file, since audiobooks have no resolution.
+func (s *Server) qualityMetByContent(items []*library.Content) map[int64]bool {
+	met := make(map[int64]bool, len(items))
+	if len(items) == 0 {
+		return met
+	}
+	ids := make([]int64, 0, len(items))
+	byID := make(map[int64]*library.Content, len(items))
+	for _, c := range items {
+		ids = append(ids, c.ID)
+		byID[c.ID] = c
+	}
+	present := false
+	files, _, err := s.deps.Library.ListFiles(library.FileFilter{ContentIDs: ids, Missing: &present})
+	if err != nil {
+		return met
+	}
+
+	// Whether any file of each movie or episode meets the cutoff
+	type unit struct {
+		contentID int64
+		episodeID int64
+	}
+	units := make(map[unit]bool)
+	for _, f := range files {
+		c := byID[f.ContentID]
+		u := unit{contentID: f.ContentID}
+		if f.EpisodeID != nil {
+			u.episodeID = *f.EpisodeID
+		}
+		units[u] = units[u] || c.Type == library.ContentTypeAudiobook || s.qualityMet(f.Quality, c.QualityProfile)
+	}
+	for u, ok := range units {
+		if prev, seen := met[u.contentID]; seen && !prev {
+			continue
+		}
+		met[u.contentID] = ok
+	}
+	return met
 }
 
 func contentToResponse(c *library.Content, stats *library.SeriesStats) contentResponse {
 	resp := contentResponse{
-		ID:             c.ID,
-		Type:           string(c.Type),
-		TMDBID:         c.TMDBID,
-		TVDBID:         c.TVDBID,
-		Title:          c.Title,
-		Year:           c.Year,
-		Status:         string(c.Status),
-		QualityProfile: c.QualityProfile,
-		RootPath:       c.RootPath,
-		AddedAt:        c.AddedAt,
-		UpdatedAt:      c.UpdatedAt,
+		ID:                  c.ID,
+		Type:                string(c.Type),
+		TMDBID:              c.TMDBID,
+		TVDBID:              c.TVDBID,
+		Title:               c.Title,
+		Year:                c.Year,
+		Status:              string(c.Status),
+		QualityProfile:      c.QualityProfile,
+		RootPath:            c.RootPath,
+		AddedAt:             c.AddedAt,
+		UpdatedAt:           c.UpdatedAt,
+		MinimumAvailability: string(c.MinimumAvailability),
+		ReleaseDate:         c.ReleaseDate,
+		Overview:            c.Overview,
+		PosterURL:           c.PosterURL,
+		Runtime:             c.Runtime,
+		Genres:              c.Genres,
+		Daily:               c.Daily,
+		SeasonFolder:        c.SeasonFolder,
+		NamingTemplate:      c.NamingTemplate,
+		SizeOnDisk:          c.SizeOnDisk,
+		Author:              c.Author,
+		CollectionID:        c.CollectionID,
+		CollectionName:      c.CollectionName,
+		PlexKey:             c.PlexKey,
+	}
+	if resp.Genres == nil {
+		resp.Genres = []string{}
+	}
+	if p := c.Pin; p != nil {
+		resp.PinnedRelease = &pinnedReleaseResponse{
+			GUID:        p.GUID,
+			DownloadURL: p.DownloadURL,
+			Title:       p.Title,
+			Indexer:     p.Indexer,
+			Protocol:    p.Protocol,
+			Season:      p.Season,
+			PinnedAt:    p.PinnedAt,
+		}
 	}
 
 	// For series, compute status from episode stats and include stats in response
@@ -437,19 +745,15 @@ func (s *Server) addContent(w http.ResponseWriter, r *http.Request) {
 
 	// Validate type
 	contentType := library.ContentType(req.Type)
-	if contentType != library.ContentTypeMovie && contentType != library.ContentTypeSeries {
-		writeError(w, http.StatusBadRequest, "INVALID_TYPE", "type must be 'movie' or 'series'")
+	if !s.contentTypeAllowed(contentType) {
+		writeError(w, http.StatusBadRequest, "INVALID_TYPE", s.contentTypeMessage())
 		return
 	}
 
 	// Default root path based on type
 	rootPath := req.RootPath
 	if rootPath == "" {
-		if contentType == library.ContentTypeMovie {
-			rootPath = s.cfg.MovieRoot
-		} else {
-			rootPath = s.cfg.SeriesRoot
-		}
+		rootPath = s.defaultRoot(contentType)
 	}
 
 	c := &library.Content{
@@ -461,6 +765,25 @@ func (s *Server) addContent(w http.ResponseWriter, r *http.Request) {
 		Status:         library.StatusWanted,
 		QualityProfile: req.QualityProfile,
 		RootPath:       rootPath,
+		Daily:          req.Daily && contentType == library.ContentTypeSeries,
+	}
+	if contentType == library.ContentTypeAudiobook {
+		c.Author = strings.TrimSpace(req.Author)
+	}
+
+	// Metadata is best effort: content is still added if the provider fails
+	if s.deps.Metadata != nil {
+		_, _ = s.deps.Metadata.Apply(r.Context(), c)
+	}
+
+	if s.checkExcluded(w, c) {
+		return
+	}
+
+	// A movie Plex already has is available with Plex's file
+	existing := s.findExistingMovie(r.Context(), c)
+	if existing != "" {
+		c.Status = library.StatusAvailable
 	}
 
 	if err := s.deps.Library.AddContent(c); err != nil {
@@ -471,26 +794,53 @@ func (s *Server) addContent(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
-
-	// Emit ContentAdded event
-	if s.deps.Bus != nil {
-		evt := &events.ContentAdded{
-			BaseEvent:      events.NewBaseEvent(events.EventContentAdded, events.EntityContent, c.ID),
-			ContentID:      c.ID,
-			ContentType:    string(c.Type),
-			Title:          c.Title,
-			Year:           c.Year,
-			QualityProfile: c.QualityProfile,
+	if existing != "" {
+		if err := s.deps.Library.AddFile(importer.ExistingMovieFile(c.ID, existing)); err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
 		}
-		_ = s.deps.Bus.Publish(r.Context(), evt)
 	}
 
+	s.publishContentAdded(r.Context(), c)
+
 	// For series with TVDB ID, fetch and populate episodes
 	if c.Type == library.ContentTypeSeries && c.TVDBID != nil {
 		go s.syncEpisodesFromTVDB(c.ID, int(*c.TVDBID))
 	}
 
-	writeJSON(w, http.StatusCreated, contentToResponse(c, nil))
+	resp := s.contentResponse(c, nil)
+	resp.ExistingFile = existing
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// publishContentAdded emits ContentAdded for new content, which starts a
+// search for it.
+func (s *Server) publishContentAdded(ctx context.Context, c *library.Content) {
+	if s.deps.Bus == nil {
+		return
+	}
+	_ = s.deps.Bus.Publish(ctx, &events.ContentAdded{
+		BaseEvent:      events.NewBaseEvent(events.EventContentAdded, events.EntityContent, c.ID),
+		ContentID:      c.ID,
+		ContentType:    string(c.Type),
+		Title:          c.Title,
+		Year:           c.Year,
+		QualityProfile: c.QualityProfile,
+	})
+}
+
+// findExistingMovie returns the local path of the file Plex has for a movie
+// being added, or "" if Plex doesn't have it, adopting is disabled, or Plex
+// can't be reached. A failed lookup never blocks adding the movie.
+func (s *Server) findExistingMovie(ctx context.Context, c *library.Content) string {
+	if !s.cfg.AdoptExisting || s.deps.Plex == nil || c.Type != library.ContentTypeMovie {
+		return ""
+	}
+	item, err := s.deps.Plex.FindMovieItem(ctx, c.Title, c.Year)
+	if err != nil || item == nil || item.FilePath == "" {
+		return ""
+	}
+	return s.deps.Plex.TranslateToLocal(item.FilePath)
 }
 
 func (s *Server) updateContent(w http.ResponseWriter, r *http.Request) {
@@ -516,22 +866,153 @@ func (s *Server) updateContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Capture old status for event
+	// Capture the old values for events
+	old := *c
 	oldStatus := c.Status
 
 	// Apply updates
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			writeError(w, http.StatusBadRequest, "INVALID_TITLE", "title must not be empty")
+			return
+		}
+		c.Title = title
+	}
+	if req.Year != nil {
+		if *req.Year < 0 {
+			writeError(w, http.StatusBadRequest, "INVALID_YEAR", "year must not be negative")
+			return
+		}
+		c.Year = *req.Year
+	}
+	if req.TMDBID != nil {
+		if c.Type != library.ContentTypeMovie || *req.TMDBID <= 0 {
+			writeError(w, http.StatusBadRequest, "INVALID_TMDB_ID", "tmdb_id must be a positive ID and only applies to movies")
+			return
+		}
+		c.TMDBID = req.TMDBID
+	}
+	if req.TVDBID != nil {
+		if c.Type != library.ContentTypeSeries || *req.TVDBID <= 0 {
+			writeError(w, http.StatusBadRequest, "INVALID_TVDB_ID", "tvdb_id must be a positive ID and only applies to series")
+			return
+		}
+		c.TVDBID = req.TVDBID
+	}
+	if req.RootPath != nil {
+		if strings.TrimSpace(*req.RootPath) == "" {
+			writeError(w, http.StatusBadRequest, "INVALID_ROOT_PATH", "root_path must not be empty")
+			return
+		}
+		c.RootPath = *req.RootPath
+	}
+	idChanged := !equalIDs(old.TMDBID, c.TMDBID) || !equalIDs(old.TVDBID, c.TVDBID)
+	if idChanged {
+		owner, err := s.externalIDOwner(c)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+		if owner != 0 {
+			writeError(w, http.StatusConflict, "DUPLICATE", fmt.Sprintf("External ID is already used by content %d", owner))
+			return
+		}
+	}
+
 	if req.Status != nil {
-		c.Status = library.ContentStatus(*req.Status)
+		status := library.ContentStatus(*req.Status)
+		if !status.Valid() {
+			writeError(w, http.StatusBadRequest, "INVALID_STATUS",
+				fmt.Sprintf("invalid status %q (want wanted, available, unmonitored or abandoned)", *req.Status))
+			return
+		}
+		c.Status = status
 	}
+	abandoning := c.Status == library.StatusAbandoned && oldStatus != library.StatusAbandoned
 	if req.QualityProfile != nil {
 		c.QualityProfile = *req.QualityProfile
 	}
+	if req.MinimumAvailability != nil {
+		availability, ok := library.ParseMinimumAvailability(*req.MinimumAvailability)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "INVALID_AVAILABILITY",
+				fmt.Sprintf("invalid minimum_availability %q (want announced, inCinemas or released)", *req.MinimumAvailability))
+			return
+		}
+		c.MinimumAvailability = availability
+	}
+	if req.Daily != nil {
+		if *req.Daily && c.Type != library.ContentTypeSeries {
+			writeError(w, http.StatusBadRequest, "INVALID_DAILY", "only series can be daily")
+			return
+		}
+		c.Daily = *req.Daily
+	}
+	if req.SeasonFolder.Set {
+		if req.SeasonFolder.Value != nil && c.Type != library.ContentTypeSeries {
+			writeError(w, http.StatusBadRequest, "INVALID_SEASON_FOLDER", "only series have season folders")
+			return
+		}
+		c.SeasonFolder = req.SeasonFolder.Value
+	}
+	if req.NamingTemplate != nil {
+		if err := importer.ValidateTemplate(*req.NamingTemplate, c.Type); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_TEMPLATE", err.Error())
+			return
+		}
+		c.NamingTemplate = *req.NamingTemplate
+	}
+	if req.Author != nil {
+		if c.Type != library.ContentTypeAudiobook {
+			writeError(w, http.StatusBadRequest, "INVALID_AUTHOR", "only audiobooks have an author")
+			return
+		}
+		c.Author = strings.TrimSpace(*req.Author)
+	}
+
+	// Abandoned content is never imported, so stop anything still downloading for it
+	var canceled []int64
+	if abandoning {
+		active, err := s.inFlightDownloads(c.ID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+		if len(active) > 0 && s.deps.Manager == nil {
+			writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Download manager not configured")
+			return
+		}
+		for _, dl := range active {
+			if err := s.deps.Manager.Cancel(r.Context(), dl.ID, true); err != nil {
+				writeError(w, http.StatusInternalServerError, "CANCEL_ERROR", err.Error())
+				return
+			}
+			canceled = append(canceled, dl.ID)
+		}
+	}
 
 	if err := s.deps.Library.UpdateContent(c); err != nil {
+		if errors.Is(err, library.ErrDuplicate) {
+			writeError(w, http.StatusConflict, "DUPLICATE", "Content with this title and year already exists")
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
+	// Metadata is keyed on the external ID; refreshing is best effort
+	if s.deps.Metadata != nil && (idChanged || req.RefreshMetadata) {
+		_, _ = s.deps.Metadata.Refresh(r.Context(), c)
+	}
+
+	if abandoning {
+		if err := s.recordAbandoned(c, oldStatus, req.Reason, canceled); err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+	}
+
 	// Emit ContentStatusChanged event if status changed
 	if s.deps.Bus != nil && oldStatus != c.Status {
 		evt := &events.ContentStatusChanged{
@@ -542,6 +1023,14 @@ func (s *Server) updateContent(w http.ResponseWriter, r *http.Request) {
 		}
 		_ = s.deps.Bus.Publish(r.Context(), evt)
 	}
+	changes := contentChanges(&old, c)
+	if s.deps.Bus != nil && len(changes) > 0 {
+		_ = s.deps.Bus.Publish(r.Context(), &events.ContentUpdated{
+			BaseEvent: events.NewBaseEvent(events.EventContentUpdated, events.EntityContent, c.ID),
+			ContentID: c.ID,
+			Changes:   changes,
+		})
+	}
 
 	// Fetch stats for series
 	var stats *library.SeriesStats
@@ -549,190 +1038,499 @@ func (s *Server) updateContent(w http.ResponseWriter, r *http.Request) {
 		stats, _ = s.deps.Library.GetSeriesStats(c.ID)
 	}
 
-	writeJSON(w, http.StatusOK, contentToResponse(c, stats))
+	resp := s.contentResponse(c, stats)
+	if old.Title != c.Title || old.Year != c.Year || old.RootPath != c.RootPath || old.Author != c.Author ||
+		old.NamingTemplate != c.NamingTemplate || !equalBools(old.SeasonFolder, c.SeasonFolder) {
+		if n := s.misnamedFiles(c.ID); n > 0 {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf(
+				"%d file(s) are still named after the old title, year, root, or naming; POST /api/v1/library/rename with content_id %d to move them", n, c.ID))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
 }
 
-func (s *Server) deleteContent(w http.ResponseWriter, r *http.Request) {
-	id, err := pathID(r)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
-		return
+// equalIDs reports whether two optional external IDs are the same.
+func equalIDs(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
+	return *a == *b
+}
 
-	if err := s.deps.Library.DeleteContent(id); err != nil {
-		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
-		return
+// equalBools reports whether two optional booleans are the same.
+func equalBools(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
-
-	w.WriteHeader(http.StatusNoContent)
+	return *a == *b
 }
 
-func (s *Server) listEpisodes(w http.ResponseWriter, r *http.Request) {
-	contentID, err := pathID(r)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
-		return
+// externalIDOwner returns the ID of other content of the same type with c's
+// TMDB (movies) or TVDB (series) ID, or 0 if there is none.
+func (s *Server) externalIDOwner(c *library.Content) (int64, error) {
+	filter := library.ContentFilter{Type: &c.Type}
+	switch {
+	case c.Type == library.ContentTypeMovie && c.TMDBID != nil:
+		filter.TMDBID = c.TMDBID
+	case c.Type == library.ContentTypeSeries && c.TVDBID != nil:
+		filter.TVDBID = c.TVDBID
+	default:
+		return 0, nil
 	}
-
-	filter := library.EpisodeFilter{ContentID: &contentID}
-	episodes, total, err := s.deps.Library.ListEpisodes(filter)
+	matches, _, err := s.deps.Library.ListContent(filter)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
-		return
+		return 0, err
 	}
-
-	resp := listEpisodesResponse{
-		Items: make([]episodeResponse, len(episodes)),
-		Total: total,
+	for _, m := range matches {
+		if m.ID != c.ID {
+			return m.ID, nil
+		}
 	}
+	return 0, nil
+}
 
-	for i, ep := range episodes {
-		resp.Items[i] = episodeToResponse(ep)
+// misnamedFiles returns how many of the content's files are not where the
+// naming templates now put them. Returns 0 without an importer.
+func (s *Server) misnamedFiles(contentID int64) int {
+	if s.deps.Importer == nil {
+		return 0
+	}
+	plans, err := s.deps.Importer.PreviewRename(contentID)
+	if err != nil {
+		return 0
 	}
+	n := 0
+	for _, p := range plans {
+		if p.Status != importer.RenameUnchanged {
+			n++
+		}
+	}
+	return n
+}
 
-	writeJSON(w, http.StatusOK, resp)
+// contentChanges lists the editable fields that differ between old and c.
+func contentChanges(old, c *library.Content) []events.FieldChange {
+	formatID := func(id *int64) string {
+		if id == nil {
+			return ""
+		}
+		return strconv.FormatInt(*id, 10)
+	}
+	formatBool := func(b *bool) string {
+		if b == nil {
+			return ""
+		}
+		return strconv.FormatBool(*b)
+	}
+	var changes []events.FieldChange
+	add := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, events.FieldChange{Field: field, Old: before, New: after})
+		}
+	}
+	add("title", old.Title, c.Title)
+	add("year", strconv.Itoa(old.Year), strconv.Itoa(c.Year))
+	add("tmdb_id", formatID(old.TMDBID), formatID(c.TMDBID))
+	add("tvdb_id", formatID(old.TVDBID), formatID(c.TVDBID))
+	add("root_path", old.RootPath, c.RootPath)
+	add("status", string(old.Status), string(c.Status))
+	add("quality_profile", old.QualityProfile, c.QualityProfile)
+	add("minimum_availability", string(old.MinimumAvailability), string(c.MinimumAvailability))
+	add("daily", strconv.FormatBool(old.Daily), strconv.FormatBool(c.Daily))
+	add("season_folder", formatBool(old.SeasonFolder), formatBool(c.SeasonFolder))
+	add("naming_template", old.NamingTemplate, c.NamingTemplate)
+	add("author", old.Author, c.Author)
+	return changes
 }
 
-func episodeToResponse(ep *library.Episode) episodeResponse {
-	return episodeResponse{
-		ID:        ep.ID,
-		ContentID: ep.ContentID,
-		Season:    ep.Season,
-		Episode:   ep.Episode,
-		Title:     ep.Title,
-		Status:    string(ep.Status),
-		AirDate:   ep.AirDate,
+// recordAbandoned records an abandoned history entry with the operator's reason.
+func (s *Server) recordAbandoned(c *library.Content, oldStatus library.ContentStatus, reason string, canceled []int64) error {
+	data, err := json.Marshal(struct {
+		Reason            string  `json:"reason,omitempty"`
+		PreviousStatus    string  `json:"previous_status"`
+		CanceledDownloads []int64 `json:"canceled_downloads,omitempty"`
+	}{reason, string(oldStatus), canceled})
+	if err != nil {
+		return err
 	}
+	return s.deps.History.Add(&importer.HistoryEntry{
+		ContentID: c.ID,
+		Event:     importer.EventAbandoned,
+		Data:      string(data),
+	})
 }
 
-func (s *Server) updateEpisode(w http.ResponseWriter, r *http.Request) {
+func (s *Server) deleteContent(w http.ResponseWriter, r *http.Request) {
 	id, err := pathID(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
 		return
 	}
 
-	var req updateEpisodeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+	deleteFiles := r.URL.Query().Get("delete_files") == queryTrue
+	cancelDownloads := r.URL.Query().Get("cancel_downloads") == queryTrue
+	// Removing the record alone is a write; files and downloads need admin
+	if (deleteFiles || cancelDownloads) && !hasScope(w, r, auth.ScopeAdmin) {
 		return
 	}
 
-	ep, err := s.deps.Library.GetEpisode(id)
+	content, err := s.deps.Library.GetContent(id)
 	if err != nil {
 		if errors.Is(err, library.ErrNotFound) {
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Episode not found")
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "content not found")
 			return
 		}
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
+	addExclusion := r.URL.Query().Get("add_exclusion") == queryTrue
 
-	if req.Status != nil {
-		ep.Status = library.ContentStatus(*req.Status)
+	var exclusion *library.Exclusion
+	if addExclusion {
+		source, externalID, ok := library.ExclusionKey(content)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "NO_EXTERNAL_ID",
+				"content has no TMDB (movie) or TVDB (series) ID to exclude")
+			return
+		}
+		reason := r.URL.Query().Get("exclusion_reason")
+		if reason == "" {
+			reason = "deleted from library"
+		}
+		exclusion = &library.Exclusion{Source: source, ExternalID: externalID,
+			Title: content.Title, Year: content.Year, Reason: reason}
 	}
 
-	if err := s.deps.Library.UpdateEpisode(ep); err != nil {
+	// Downloads still in flight would fail at import once the content is gone
+	active, err := s.inFlightDownloads(id)
+	if err != nil {
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
+	if len(active) > 0 && !cancelDownloads {
+		writeError(w, http.StatusConflict, "ACTIVE_DOWNLOADS",
+			fmt.Sprintf("content has %d active download(s); set cancel_downloads=true to cancel them", len(active)))
+		return
+	}
+	if len(active) > 0 && s.deps.Manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Download manager not configured")
+		return
+	}
 
-	writeJSON(w, http.StatusOK, episodeToResponse(ep))
-}
-
-func (s *Server) search(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("query")
-	if query == "" {
-		writeError(w, http.StatusBadRequest, "MISSING_QUERY", "query parameter is required")
+	var canceled []int64
+	for _, dl := range active {
+		if err := s.deps.Manager.Cancel(r.Context(), dl.ID, true); err != nil {
+			writeError(w, http.StatusInternalServerError, "CANCEL_ERROR", err.Error())
+			return
+		}
+		canceled = append(canceled, dl.ID)
+	}
+
+	var deletedFiles []string
+	if deleteFiles {
+		deletedFiles, err = s.deleteContentFiles(content)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "DELETE_ERROR", err.Error())
+			return
+		}
+	}
+
+	if err := s.removeContent(id, exclusion); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
-	profile := r.URL.Query().Get("profile")
-	if profile == "" {
-		profile = "hd"
+	if s.deps.Bus != nil {
+		evt := &events.ContentDeleted{
+			BaseEvent:         events.NewBaseEvent(events.EventContentDeleted, events.EntityContent, id),
+			ContentID:         id,
+			ContentType:       string(content.Type),
+			Title:             content.Title,
+			Year:              content.Year,
+			DeletedFiles:      deletedFiles,
+			CanceledDownloads: canceled,
+			Excluded:          exclusion != nil,
+		}
+		// Best effort - the content is already gone
+		_ = s.deps.Bus.Publish(r.Context(), evt)
 	}
 
-	q := search.Query{
-		Text: query,
-		Type: r.URL.Query().Get("type"),
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// removeContent deletes a content record and, if exclusion is set, excludes
+// its external ID in the same transaction. An ID already excluded is kept.
+func (s *Server) removeContent(id int64, exclusion *library.Exclusion) error {
+	if exclusion == nil {
+		return s.deps.Library.DeleteContent(id)
 	}
 
-	// Parse optional season/episode
-	if seasonStr := r.URL.Query().Get("season"); seasonStr != "" {
-		if season, err := strconv.Atoi(seasonStr); err == nil {
-			q.Season = &season
+	tx, err := s.deps.Library.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := tx.DeleteContent(id); err != nil {
+		return err
+	}
+	if err := tx.AddExclusion(exclusion); err != nil && !errors.Is(err, library.ErrDuplicate) {
+		return err
+	}
+	return tx.Commit()
+}
+
+// inFlightDownloads returns downloads for the content that have not finished importing.
+func (s *Server) inFlightDownloads(contentID int64) ([]*download.Download, error) {
+	downloads, _, err := s.deps.Downloads.List(download.Filter{ContentID: &contentID, Active: true})
+	if err != nil {
+		return nil, err
+	}
+	var active []*download.Download
+	for _, dl := range downloads {
+		if dl.Status.IsTerminal() || dl.Status == download.StatusImported {
+			continue
 		}
+		active = append(active, dl)
 	}
-	if episodeStr := r.URL.Query().Get("episode"); episodeStr != "" {
-		if episode, err := strconv.Atoi(episodeStr); err == nil {
-			q.Episode = &episode
+	return active, nil
+}
+
+// deleteContentFiles removes the content's files, subtitles included, from
+// disk, then any directories left empty between each file and the content's
+// root path. Files already missing from disk are skipped. Returns the paths
+// removed.
+func (s *Server) deleteContentFiles(content *library.Content) ([]string, error) {
+	files, _, err := s.deps.Library.ListFiles(library.FileFilter{
+		ContentID: &content.ID,
+		Kinds:     []library.FileKind{library.FileKindMedia, library.FileKindSubtitle},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+
+	var deleted []string
+	for _, f := range files {
+		if err := os.Remove(f.Path); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return deleted, fmt.Errorf("remove %s: %w", f.Path, err)
+		}
+		deleted = append(deleted, f.Path)
+
+		// os.Remove fails on non-empty directories, which stops the walk
+		root := filepath.Clean(content.RootPath)
+		for dir := filepath.Dir(f.Path); dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
 		}
 	}
+	return deleted, nil
+}
 
-	// Parse optional content_id
-	if contentIDStr := r.URL.Query().Get("content_id"); contentIDStr != "" {
-		if contentID, err := strconv.ParseInt(contentIDStr, 10, 64); err == nil {
-			q.ContentID = contentID
+func (s *Server) listBlocklist(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	entries, err := s.deps.Downloads.ListBlocked(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	resp := listBlocklistResponse{
+		Items: make([]blocklistResponse, len(entries)),
+		Total: len(entries),
+	}
+	for i, e := range entries {
+		resp.Items[i] = blocklistResponse{
+			ContentID:   e.ContentID,
+			GUID:        e.GUID,
+			ReleaseName: e.ReleaseName,
+			Indexer:     e.Indexer,
+			Reason:      e.Reason,
+			CreatedAt:   e.CreatedAt,
 		}
 	}
 
-	result, err := s.deps.Searcher.Search(r.Context(), q, profile)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) unblockRelease(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "SEARCH_ERROR", err.Error())
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
 		return
 	}
 
-	resp := searchResponse{
-		Releases: make([]releaseResponse, len(result.Releases)),
+	guid := r.PathValue("guid")
+	if guid == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "guid is required")
+		return
 	}
 
-	for i, rel := range result.Releases {
-		quality := ""
-		if rel.Quality != nil {
-			quality = rel.Quality.Resolution.String()
+	if err := s.deps.Downloads.Unblock(id, guid); err != nil {
+		if errors.Is(err, download.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Release not blocklisted")
+			return
 		}
-		resp.Releases[i] = releaseResponse{
-			Title:       rel.Title,
-			Indexer:     rel.Indexer,
-			GUID:        rel.GUID,
-			DownloadURL: rel.DownloadURL,
-			Size:        rel.Size,
-			PublishDate: rel.PublishDate,
-			Quality:     quality,
-			Score:       rel.Score,
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listEpisodes(w http.ResponseWriter, r *http.Request) {
+	contentID, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	c, err := s.deps.Library.GetContent(contentID)
+	if err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+			return
 		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
 	}
 
-	for _, e := range result.Errors {
-		resp.Errors = append(resp.Errors, e.Error())
+	filter := library.EpisodeFilter{ContentID: &contentID}
+	episodes, total, err := s.deps.Library.ListEpisodes(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	resp := listEpisodesResponse{
+		Items: make([]episodeResponse, len(episodes)),
+		Total: total,
+	}
+
+	for i, ep := range episodes {
+		resp.Items[i] = episodeToResponse(ep, c.QualityProfile)
 	}
 
 	writeJSON(w, http.StatusOK, resp)
 }
 
-func (s *Server) grab(w http.ResponseWriter, r *http.Request) {
-	var req grabRequest
+func episodeToResponse(ep *library.Episode, seriesProfile string) episodeResponse {
+	profile, inherited := ep.EffectiveProfile(seriesProfile)
+	return episodeResponse{
+		ID:               ep.ID,
+		ContentID:        ep.ContentID,
+		Season:           ep.Season,
+		Episode:          ep.Episode,
+		Title:            ep.Title,
+		Status:           string(ep.Status),
+		AirDate:          ep.AirDate,
+		QualityProfile:   profile,
+		ProfileInherited: inherited,
+	}
+}
+
+// knownProfile reports whether profile is defined. Any profile is accepted
+// when none are defined.
+func (s *Server) knownProfile(profile string) bool {
+	if s.deps.Profiles == nil {
+		return true
+	}
+	profiles, err := s.deps.Profiles.List()
+	if err != nil || len(profiles) == 0 {
+		return true
+	}
+	_, ok := s.deps.Profiles.Profile(profile)
+	return ok
+}
+
+// listSeasons handles GET /api/v1/content/{id}/seasons.
+// Each season's episode counts, file sizes and newest air date are computed in SQL.
+func (s *Server) listSeasons(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	c, err := s.deps.Library.GetContent(id)
+	if err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	if c.Type != library.ContentTypeSeries {
+		writeError(w, http.StatusBadRequest, "NOT_SERIES", "Seasons only apply to series")
+		return
+	}
+
+	stats, err := s.deps.Library.GetSeriesStats(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	resp := listSeasonsResponse{
+		Items: make([]seasonSummaryResponse, len(stats.Seasons)),
+		Total: len(stats.Seasons),
+	}
+	for i, ss := range stats.Seasons {
+		resp.Items[i] = seasonSummaryResponse{
+			Season:         ss.Season,
+			EpisodeCount:   ss.Total,
+			AvailableCount: ss.Available,
+			SizeBytes:      ss.SizeBytes,
+			LastAirDate:    ss.LastAirDate,
+			Complete:       ss.Complete(),
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// monitorEpisodes handles PUT /api/v1/content/{id}/episodes/monitor.
+// Selected episodes become wanted and the rest of the series unmonitored.
+// With a quality profile in the request, the selected episodes get that profile
+// override instead and monitoring is unchanged.
+func (s *Server) monitorEpisodes(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	var req monitorEpisodesRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
 		return
 	}
 
-	// Validate required fields
-	if req.ContentID == 0 {
-		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "content_id is required")
-		return
+	selectors := 0
+	for _, set := range []bool{len(req.Seasons) > 0, len(req.EpisodeIDs) > 0, req.FutureOnly} {
+		if set {
+			selectors++
+		}
 	}
-	if req.DownloadURL == "" {
-		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "download_url is required")
+	if selectors != 1 {
+		writeError(w, http.StatusBadRequest, "INVALID_SELECTION", "exactly one of seasons, episode_ids, or future_only is required")
 		return
 	}
-	if req.Title == "" {
-		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "title is required")
+	if req.QualityProfile != nil && *req.QualityProfile != "" && !s.knownProfile(*req.QualityProfile) {
+		writeError(w, http.StatusBadRequest, "INVALID_PROFILE", fmt.Sprintf("unknown quality profile %q", *req.QualityProfile))
 		return
 	}
 
-	// Verify content exists and get type
-	content, err := s.deps.Library.GetContent(req.ContentID)
+	c, err := s.deps.Library.GetContent(id)
 	if err != nil {
 		if errors.Is(err, library.ErrNotFound) {
 			writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
@@ -741,80 +1539,220 @@ func (s *Server) grab(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
+	if c.Type != library.ContentTypeSeries {
+		writeError(w, http.StatusBadRequest, "NOT_SERIES", "Episode monitoring only applies to series")
+		return
+	}
 
-	// Require event bus for grab operations
-	if s.deps.Bus == nil {
-		writeError(w, http.StatusServiceUnavailable, "NO_DOWNLOAD_CLIENT", "download client not configured")
+	sel := library.EpisodeSelection{
+		Seasons:    req.Seasons,
+		EpisodeIDs: req.EpisodeIDs,
+		FutureOnly: req.FutureOnly,
+	}
+	var changed map[int]int
+	if req.QualityProfile != nil {
+		changed, err = s.deps.Library.BulkSetEpisodeProfile(id, sel, *req.QualityProfile)
+	} else {
+		changed, err = s.deps.Library.BulkUpdateEpisodeStatus(id, sel)
+	}
+	if err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusBadRequest, "INVALID_EPISODE", err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
-	// Build the event
-	event := &events.GrabRequested{
-		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
-		ContentID:   req.ContentID,
-		DownloadURL: req.DownloadURL,
-		ReleaseName: req.Title,
-		Indexer:     req.Indexer,
+	resp := monitorEpisodesResponse{
+		ContentID: id,
+		Seasons:   make([]seasonChangeResponse, 0, len(changed)),
 	}
+	for season, n := range changed {
+		resp.Seasons = append(resp.Seasons, seasonChangeResponse{Season: season, Changed: n})
+		resp.Changed += n
+	}
+	sort.Slice(resp.Seasons, func(i, j int) bool { return resp.Seasons[i].Season < resp.Seasons[j].Season })
 
-	// For series, parse release name to detect episodes
-	if content.Type == library.ContentTypeSeries {
-		parsed := release.Parse(req.Title)
+	writeJSON(w, http.StatusOK, resp)
+}
 
-		// Use overrides if provided, otherwise use parsed values
-		season := parsed.Season
-		if req.Season != nil {
-			season = *req.Season
-		}
+func (s *Server) updateEpisode(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
 
-		episodes := parsed.Episodes
-		if len(req.Episodes) > 0 {
-			episodes = req.Episodes
+	var req updateEpisodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+
+	ep, err := s.deps.Library.GetEpisode(id)
+	if err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Episode not found")
+			return
 		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
 
-		// Season is required for series
-		if season == 0 {
-			writeError(w, http.StatusBadRequest, "INVALID_RELEASE", "cannot determine season from release title")
+	if req.Status != nil {
+		ep.Status = library.ContentStatus(*req.Status)
+	}
+	if req.QualityProfile != nil {
+		if *req.QualityProfile != "" && !s.knownProfile(*req.QualityProfile) {
+			writeError(w, http.StatusBadRequest, "INVALID_PROFILE", fmt.Sprintf("unknown quality profile %q", *req.QualityProfile))
 			return
 		}
+		ep.QualityProfile = *req.QualityProfile
+	}
 
-		event.Season = &season
+	c, err := s.deps.Library.GetContent(ep.ContentID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
 
-		// Handle season packs vs specific episodes
-		switch {
-		case parsed.IsCompleteSeason && len(episodes) == 0:
-			// Season pack: set IsCompleteSeason, no EpisodeIDs yet
-			event.IsCompleteSeason = true
-		case len(episodes) > 0:
-			// Specific episodes: find or create episode records
-			eps, err := s.deps.Library.FindOrCreateEpisodes(req.ContentID, season, episodes)
-			if err != nil {
-				writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
-				return
-			}
-			for _, ep := range eps {
-				event.EpisodeIDs = append(event.EpisodeIDs, ep.ID)
-			}
-			// Backward compatibility: set EpisodeID for single-episode grabs
-			if len(event.EpisodeIDs) == 1 {
-				event.EpisodeID = &event.EpisodeIDs[0]
-			}
-		default:
-			// No episode info and not a season pack
-			writeError(w, http.StatusBadRequest, "INVALID_RELEASE", "cannot determine episodes from release title")
-			return
+	if err := s.deps.Library.UpdateEpisode(ep); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, episodeToResponse(ep, c.QualityProfile))
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("query")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_QUERY", "query parameter is required")
+		return
+	}
+
+	profile := r.URL.Query().Get("profile")
+	if profile == "" {
+		profile = "hd"
+	}
+	protocol, err := queryProtocol(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PROTOCOL", err.Error())
+		return
+	}
+
+	q := search.Query{
+		Text:     query,
+		Type:     r.URL.Query().Get("type"),
+		Refresh:  r.URL.Query().Get("refresh") == queryTrue,
+		Protocol: protocol,
+	}
+
+	// Parse optional season/episode
+	if seasonStr := r.URL.Query().Get("season"); seasonStr != "" {
+		if season, err := strconv.Atoi(seasonStr); err == nil {
+			q.Season = &season
+		}
+	}
+	if episodeStr := r.URL.Query().Get("episode"); episodeStr != "" {
+		if episode, err := strconv.Atoi(episodeStr); err == nil {
+			q.Episode = &episode
 		}
-	} else {
-		// For movies, preserve legacy EpisodeID if provided (shouldn't happen, but handle gracefully)
-		event.EpisodeID = req.EpisodeID
 	}
 
-	if err := s.deps.Bus.Publish(r.Context(), event); err != nil {
-		writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
+	// Parse optional content_id
+	if contentIDStr := r.URL.Query().Get("content_id"); contentIDStr != "" {
+		if contentID, err := strconv.ParseInt(contentIDStr, 10, 64); err == nil {
+			q.ContentID = contentID
+		}
+	}
+
+	result, err := s.deps.Searcher.Search(r.Context(), q, profile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "SEARCH_ERROR", err.Error())
 		return
 	}
 
-	writeJSON(w, http.StatusAccepted, map[string]string{"status": "accepted"})
+	writeJSON(w, http.StatusOK, searchResultToResponse(result))
+}
+
+// searchResultToResponse converts a search result to its API representation.
+func searchResultToResponse(result *search.Result) searchResponse {
+	resp := searchResponse{
+		Releases: make([]releaseResponse, len(result.Releases)),
+		Rejected: make([]rejectedReleaseResponse, len(result.Rejected)),
+	}
+
+	for i, rel := range result.Releases {
+		resp.Releases[i] = releaseToResponse(rel)
+	}
+
+	for _, d := range result.Deferred {
+		remaining := max(time.Until(d.Until), 0)
+		resp.Deferred = append(resp.Deferred, deferredReleaseResponse{
+			releaseResponse:  releaseToResponse(d.Release),
+			DeferredUntil:    d.Until,
+			RemainingSeconds: int64(remaining.Round(time.Second) / time.Second),
+		})
+	}
+
+	for i, rej := range result.Rejected {
+		resp.Rejected[i] = rejectedReleaseResponse{
+			Title:     rej.Title,
+			Indexer:   rej.Indexer,
+			Reason:    rej.Reason,
+			Languages: rej.Languages,
+		}
+	}
+
+	for _, e := range result.Errors {
+		resp.Errors = append(resp.Errors, searchErrorToResponse(e))
+		resp.ErrorMessages = append(resp.ErrorMessages, e.Error())
+	}
+	if result.Cached {
+		resp.Cached = true
+		resp.AgeSeconds = int64(time.Since(result.CachedAt).Seconds())
+	}
+	return resp
+}
+
+// releaseToResponse converts a scored release to its API representation.
+func releaseToResponse(rel *search.Release) releaseResponse {
+	quality := ""
+	if rel.Quality != nil {
+		quality = rel.Quality.Resolution.String()
+	}
+	return releaseResponse{
+		Title:       rel.Title,
+		Indexer:     rel.Indexer,
+		GUID:        rel.GUID,
+		DownloadURL: rel.DownloadURL,
+		Size:        rel.Size,
+		PublishDate: rel.PublishDate,
+		Quality:     quality,
+		Score:       rel.Score,
+		Protocol:    string(rel.Protocol),
+		Seeders:     rel.Seeders,
+		Peers:       rel.Peers,
+
+		ScoreBreakdown: rel.Breakdown,
+	}
+}
+
+// searchErrorToResponse describes a search error, per indexer where it
+// came from one.
+func searchErrorToResponse(err error) searchErrorResponse {
+	var ie *search.IndexerError
+	if !errors.As(err, &ie) {
+		return searchErrorResponse{Message: err.Error()}
+	}
+	return searchErrorResponse{
+		Indexer:           ie.Indexer,
+		Code:              ie.Code,
+		Message:           ie.Err.Error(),
+		RetryAfterSeconds: int64(ie.RetryAfter.Round(time.Second) / time.Second),
+	}
 }
 
 func (s *Server) listDownloads(w http.ResponseWriter, r *http.Request) {
@@ -837,6 +1775,22 @@ func (s *Server) listDownloads(w http.ResponseWriter, r *http.Request) {
 		filter.Active = true
 	}
 
+	// Recently added first unless asked otherwise
+	filter.Sort = download.SortAddedAt
+	if sortStr := queryString(r, "sort"); sortStr != nil {
+		filter.Sort = download.Sort(*sortStr)
+		if !download.ValidSort(filter.Sort) {
+			writeError(w, http.StatusBadRequest, "INVALID_SORT", "sort must be one of added_at, completed_at, status")
+			return
+		}
+	}
+	desc, ok := queryOrder(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "INVALID_ORDER", "order must be 'asc' or 'desc'")
+		return
+	}
+	filter.Desc = desc
+
 	downloads, total, err := s.deps.Downloads.List(filter)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
@@ -850,13 +1804,46 @@ func (s *Server) listDownloads(w http.ResponseWriter, r *http.Request) {
 		Offset: filter.Offset,
 	}
 
+	positions, err := s.importPositions()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	ids := make([]int64, len(downloads))
+	for i, d := range downloads {
+		ids[i] = d.ID
+	}
+	torrentOpts, err := s.deps.Downloads.TorrentOptions(ids...)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
 	for i, d := range downloads {
 		resp.Items[i] = downloadToResponse(d)
+		resp.Items[i].ImportPosition = positions[d.ID]
+		resp.Items[i].SmoothedETA = s.smoothedETA(d.ID)
+		if opts, ok := torrentOpts[d.ID]; ok {
+			resp.Items[i].TorrentOptions = torrentOptionsToResponse(opts)
+		}
 	}
 
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// importPositions maps each download waiting for an import worker to its
+// place in the import queue, counting from 1.
+func (s *Server) importPositions() (map[int64]int, error) {
+	queue, err := s.deps.Downloads.ImportQueue()
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[int64]int, len(queue))
+	for i, q := range queue {
+		positions[q.DownloadID] = i + 1
+	}
+	return positions, nil
+}
+
 func downloadToResponse(d *download.Download) downloadResponse {
 	resp := downloadResponse{
 		ID:               d.ID,
@@ -868,7 +1855,11 @@ func downloadToResponse(d *download.Download) downloadResponse {
 		ClientID:         d.ClientID,
 		Status:           string(d.Status),
 		ReleaseName:      d.ReleaseName,
+		ReleaseGroup:     d.ReleaseGroup,
 		Indexer:          d.Indexer,
+		GUID:             d.GUID,
+		Category:         d.Category,
+		LastError:        d.LastError,
 		AddedAt:          d.AddedAt,
 		CompletedAt:      d.CompletedAt,
 		Progress:         &d.Progress,
@@ -899,7 +1890,133 @@ func (s *Server) getDownload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, downloadToResponse(d))
+	resp := downloadToResponse(d)
+	positions, err := s.importPositions()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	resp.ImportPosition = positions[d.ID]
+	resp.SmoothedETA = s.smoothedETA(d.ID)
+	torrentOpts, err := s.deps.Downloads.TorrentOptions(d.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	if opts, ok := torrentOpts[d.ID]; ok {
+		resp.TorrentOptions = torrentOptionsToResponse(opts)
+	}
+	results, err := s.deps.Downloads.EpisodeResults(d.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	for _, r := range results {
+		resp.EpisodeResults = append(resp.EpisodeResults, episodeResultResponse{
+			SourceFile: r.SourceFile,
+			EpisodeID:  r.EpisodeID,
+			Season:     r.Season,
+			Episode:    r.Episode,
+			Success:    r.Success,
+			FilePath:   r.FilePath,
+			Error:      r.Error,
+			UpdatedAt:  r.UpdatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// listDownloadSamples handles GET /api/v1/downloads/{id}/samples.
+// Returns the progress recorded at each recent poll of an active download,
+// for speed charts, with the ETA smoothed over them.
+func (s *Server) listDownloadSamples(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+	if s.deps.Samples == nil {
+		writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "progress samples are disabled (downloaders.progress_samples)")
+		return
+	}
+	if _, err := s.deps.Downloads.Get(id); err != nil {
+		if errors.Is(err, download.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Download not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	samples := s.deps.Samples.Get(id)
+	resp := downloadSamplesResponse{
+		DownloadID:  id,
+		Samples:     make([]downloadSampleResponse, len(samples)),
+		SmoothedETA: s.smoothedETA(id),
+	}
+	for i, sample := range samples {
+		resp.Samples[i] = downloadSampleResponse{
+			At:       sample.At,
+			Progress: sample.Progress,
+			Speed:    sample.Speed,
+			Size:     sample.Size,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// smoothedETA returns a download's ETA from its progress samples, or nil
+// without enough of them.
+func (s *Server) smoothedETA(downloadID int64) *string {
+	if s.deps.Samples == nil {
+		return nil
+	}
+	eta, ok := s.deps.Samples.ETA(downloadID)
+	if !ok || eta <= 0 {
+		return nil
+	}
+	str := eta.String()
+	return &str
+}
+
+// getDownloadDecision handles GET /api/v1/downloads/{id}/decision.
+// Returns the score breakdown and runners-up recorded when an automatic search
+// grabbed the download. Manual grabs have no decision.
+func (s *Server) getDownloadDecision(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	if _, err := s.deps.Downloads.Get(id); err != nil {
+		if errors.Is(err, download.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Download not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	entry, err := s.deps.History.LatestForDownload(importer.EventGrabDecision, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	if entry == nil {
+		writeError(w, http.StatusNotFound, "NO_DECISION", "No grab decision recorded for this download")
+		return
+	}
+
+	resp := grabDecisionResponse{RecordedAt: entry.CreatedAt}
+	if err := json.Unmarshal([]byte(entry.Data), &resp); err != nil {
+		writeError(w, http.StatusInternalServerError, "DECODE_ERROR", err.Error())
+		return
+	}
+	resp.Summary = resp.GrabDecision.Summary()
+
+	writeJSON(w, http.StatusOK, resp)
 }
 
 func (s *Server) deleteDownload(w http.ResponseWriter, r *http.Request) {
@@ -910,23 +2027,38 @@ func (s *Server) deleteDownload(w http.ResponseWriter, r *http.Request) {
 	}
 
 	deleteFiles := r.URL.Query().Get("delete_files") == queryTrue
+	// The record is gone after canceling; keep it to find the release directory
+	var dl *download.Download
+	if deleteFiles && s.cfg.DownloadRoot != "" && len(s.cfg.Junk.Patterns) > 0 {
+		dl, _ = s.deps.Downloads.Get(id)
+	}
 	if err := s.deps.Manager.Cancel(r.Context(), id, deleteFiles); err != nil {
 		writeError(w, http.StatusInternalServerError, "CANCEL_ERROR", err.Error())
 		return
 	}
+	if dl != nil {
+		s.removeJunk(r.Context(), dl)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (s *Server) retryDownload(w http.ResponseWriter, r *http.Request) {
+// setDownloadOptions handles POST /api/v1/downloads/{id}/options.
+// Changes the torrent options of a queued or downloading torrent, e.g. to
+// turn on sequential download to play it before it finishes.
+func (s *Server) setDownloadOptions(w http.ResponseWriter, r *http.Request) {
 	id, err := pathID(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
 		return
 	}
+	var req torrentOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
 
-	// Get the failed download
-	dl, err := s.deps.Downloads.Get(id)
+	d, err := s.deps.Downloads.Get(id)
 	if err != nil {
 		if errors.Is(err, download.ErrNotFound) {
 			writeError(w, http.StatusNotFound, "NOT_FOUND", "Download not found")
@@ -935,75 +2067,212 @@ func (s *Server) retryDownload(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
+	if d.Status != download.StatusQueued && d.Status != download.StatusDownloading {
+		writeError(w, http.StatusConflict, "NOT_DOWNLOADING", fmt.Sprintf("download is %s; options only apply while it downloads", d.Status))
+		return
+	}
 
-	// Only allow retry on failed downloads
-	if dl.Status != download.StatusFailed {
-		writeError(w, http.StatusBadRequest, "INVALID_STATE",
-			fmt.Sprintf("Can only retry failed downloads, current status: %s", dl.Status))
+	current, err := s.deps.Downloads.TorrentOptions(d.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	opts := current[d.ID]
+	if req.Sequential != nil {
+		opts.Sequential = *req.Sequential
+	}
+	if req.FirstLastPiecePrio != nil {
+		opts.FirstLastPiecePrio = *req.FirstLastPiecePrio
+	}
+	if err := s.deps.Manager.SetTorrentOptions(r.Context(), d, opts); err != nil {
+		if errors.Is(err, download.ErrUnsupported) {
+			writeError(w, http.StatusConflict, "UNSUPPORTED", err.Error())
+			return
+		}
+		writeError(w, http.StatusBadGateway, "CLIENT_ERROR", err.Error())
 		return
 	}
 
-	// Require event bus for retry operations (grabs go through event bus)
-	if s.deps.Bus == nil {
-		writeError(w, http.StatusServiceUnavailable, "NO_EVENT_BUS", "event bus not configured")
+	resp := downloadToResponse(d)
+	resp.TorrentOptions = torrentOptionsToResponse(opts)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func torrentOptionsToResponse(opts download.TorrentOptions) *torrentOptionsResponse {
+	return &torrentOptionsResponse{
+		Sequential:         opts.Sequential,
+		FirstLastPiecePrio: opts.FirstLastPiecePrio,
+	}
+}
+
+// setSpeedLimit handles PUT /api/v1/downloads/speed-limit.
+// The limit overrides the bandwidth schedule until the duration elapses.
+func (s *Server) setSpeedLimit(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Speed == nil {
+		writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "download client not configured")
 		return
 	}
 
-	// Get content to search for
-	content, err := s.deps.Library.GetContent(dl.ContentID)
+	var req setSpeedLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+
+	limit, err := download.ParseSpeedLimit(req.Limit)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "CONTENT_ERROR", err.Error())
+		writeError(w, http.StatusBadRequest, "INVALID_LIMIT", err.Error())
+		return
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil || duration <= 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_DURATION", "duration must be a positive duration such as \"2h\"")
+		return
+	}
+
+	if err := s.deps.Speed.Override(r.Context(), limit, time.Now().Add(duration)); err != nil {
+		if errors.Is(err, download.ErrUnsupported) {
+			writeError(w, http.StatusNotImplemented, "NOT_SUPPORTED", err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "SPEED_LIMIT_ERROR", err.Error())
 		return
 	}
 
-	// Build search query
-	query := content.Title
-	if content.Year > 0 {
-		query = fmt.Sprintf("%s %d", content.Title, content.Year)
+	writeJSON(w, http.StatusOK, speedLimitToResponse(s.deps.Speed.Current()))
+}
+
+// speedLimitToResponse converts the applied speed limit state to its API form.
+func speedLimitToResponse(st download.SpeedLimitState) *speedLimitResponse {
+	return &speedLimitResponse{
+		Limit:     st.Limit,
+		Display:   download.FormatSpeedLimit(st.Limit),
+		Source:    st.Source,
+		ExpiresAt: st.Expires,
 	}
+}
 
-	// Search indexers
-	q := search.Query{
-		Text:      query,
-		ContentID: dl.ContentID,
-		Type:      string(content.Type),
+// listWanted handles GET /api/v1/wanted.
+// Returns missing items (wanted, no file) and items whose best file is below the
+// quality profile's cutoff. Limit and offset apply to each section.
+func (s *Server) listWanted(w http.ResponseWriter, r *http.Request) {
+	filter := library.WantedFilter{
+		Limit:            queryInt(r, "limit", 50),
+		Offset:           queryInt(r, "offset", 0),
+		IncludeAbandoned: r.URL.Query().Get("include_abandoned") == queryTrue,
 	}
-	profile := content.QualityProfile
-	if profile == "" {
-		profile = "hd"
+	if filter.Limit < 0 || filter.Offset < 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_PAGINATION", "limit and offset must be non-negative")
+		return
 	}
 
-	result, err := s.deps.Searcher.Search(r.Context(), q, profile)
+	if typeStr := queryString(r, "type"); typeStr != nil {
+		t := library.ContentType(*typeStr)
+		if !s.contentTypeAllowed(t) {
+			writeError(w, http.StatusBadRequest, "INVALID_TYPE", s.contentTypeMessage())
+			return
+		}
+		filter.Type = &t
+	}
+	if sortStr := queryString(r, "sort"); sortStr != nil {
+		filter.Sort = library.WantedSort(*sortStr)
+		if filter.Sort != library.WantedSortAddedAt && filter.Sort != library.WantedSortAirDate {
+			writeError(w, http.StatusBadRequest, "INVALID_SORT", "sort must be 'added_at' or 'air_date'")
+			return
+		}
+	}
+
+	missing, missingTotal, err := s.deps.Library.ListMissing(filter)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "SEARCH_ERROR", err.Error())
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
-	if len(result.Releases) == 0 {
-		writeError(w, http.StatusNotFound, "NO_RESULTS", "No releases found")
+	withFiles, err := s.deps.Library.ListWithFiles(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
-	// Grab best result (first one, already sorted by score)
-	best := result.Releases[0]
+	resp := wantedResponse{
+		Missing:     wantedSectionResponse{Items: make([]wantedItemResponse, 0, len(missing)), Total: missingTotal},
+		CutoffUnmet: wantedSectionResponse{Items: []wantedItemResponse{}},
+		Limit:       filter.Limit,
+		Offset:      filter.Offset,
+	}
+	for _, item := range missing {
+		resp.Missing.Items = append(resp.Missing.Items, wantedToResponse(item, s.profileCutoff(item.QualityProfile), "missing"))
+	}
 
-	// Publish grab request via event bus (same pattern as grab handler)
-	if err := s.deps.Bus.Publish(r.Context(), &events.GrabRequested{
-		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
-		ContentID:   dl.ContentID,
-		EpisodeID:   dl.EpisodeID,
-		DownloadURL: best.DownloadURL,
-		ReleaseName: best.Title,
-		Indexer:     best.Indexer,
-	}); err != nil {
-		writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
-		return
+	// Cutoff is computed here against the profile, so paginate after filtering
+	var unmet []wantedItemResponse
+	for _, item := range withFiles {
+		if s.qualityMet(item.Quality, item.QualityProfile) {
+			continue
+		}
+		unmet = append(unmet, wantedToResponse(item, s.profileCutoff(item.QualityProfile), "cutoff_unmet"))
+	}
+	resp.CutoffUnmet.Total = len(unmet)
+	if filter.Offset < len(unmet) {
+		end := len(unmet)
+		if filter.Limit > 0 && filter.Offset+filter.Limit < end {
+			end = filter.Offset + filter.Limit
+		}
+		resp.CutoffUnmet.Items = unmet[filter.Offset:end]
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// qualityMet reports whether a file of a quality meets the cutoff of a
+// quality profile. It does for an unknown profile, which has no cutoff.
+func (s *Server) qualityMet(quality, profile string) bool {
+	if s.deps.Profiles == nil {
+		return true
+	}
+	p, err := s.deps.Profiles.Get(profile)
+	if err != nil {
+		return true
+	}
+	return search.CutoffMet(quality, p.Settings)
+}
+
+// profileCutoff returns the quality profile's cutoff, else the best
+// resolution it accepts, or "" if the profile is unknown or accepts any
+// resolution.
+func (s *Server) profileCutoff(profile string) string {
+	if s.deps.Profiles == nil {
+		return ""
+	}
+	p, err := s.deps.Profiles.Get(profile)
+	if err != nil {
+		return ""
 	}
+	return p.Cutoff()
+}
 
-	writeJSON(w, http.StatusAccepted, retryResponse{
-		ReleaseName: best.Title,
-		Message:     "Retry queued",
-	})
+// wantedToResponse converts a library wanted item to its API response.
+func wantedToResponse(item *library.WantedItem, cutoff, reason string) wantedItemResponse {
+	resp := wantedItemResponse{
+		ContentID:      item.ContentID,
+		Type:           string(item.Type),
+		Title:          item.Title,
+		Year:           item.Year,
+		AddedAt:        item.AddedAt,
+		QualityProfile: item.QualityProfile,
+		CurrentQuality: item.Quality,
+		WantedQuality:  cutoff,
+		Reason:         reason,
+		Abandoned:      item.Status == library.StatusAbandoned,
+	}
+	if item.EpisodeID != nil {
+		resp.EpisodeID = item.EpisodeID
+		resp.Season = &item.Season
+		resp.Episode = &item.Episode
+		resp.EpisodeTitle = item.EpisodeTitle
+		resp.AirDate = item.AirDate
+	}
+	return resp
 }
 
 func (s *Server) listHistory(w http.ResponseWriter, r *http.Request) {
@@ -1074,6 +2343,36 @@ func (s *Server) listFiles(w http.ResponseWriter, r *http.Request) {
 		id, _ := strconv.ParseInt(contentIDStr, 10, 64)
 		filter.ContentID = &id
 	}
+	if v := r.URL.Query().Get("episode_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_FILTER", "episode_id must be an integer")
+			return
+		}
+		filter.EpisodeID = &id
+	}
+	if v := r.URL.Query().Get("season"); v != "" {
+		season, err := strconv.Atoi(v)
+		if err != nil || season < 0 {
+			writeError(w, http.StatusBadRequest, "INVALID_FILTER", "season must be a non-negative integer")
+			return
+		}
+		filter.Season = &season
+	}
+	if v := r.URL.Query().Get("missing"); v != "" {
+		missing := v == queryTrue
+		filter.Missing = &missing
+	}
+	switch kind := r.URL.Query().Get("kind"); kind {
+	case "", string(library.FileKindMedia):
+	case string(library.FileKindSubtitle):
+		filter.Kinds = []library.FileKind{library.FileKindSubtitle}
+	case "all":
+		filter.Kinds = []library.FileKind{library.FileKindMedia, library.FileKindSubtitle}
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_FILTER", "kind must be media, subtitle, or all")
+		return
+	}
 
 	files, total, err := s.deps.Library.ListFiles(filter)
 	if err != nil {
@@ -1081,157 +2380,172 @@ func (s *Server) listFiles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := listFilesResponse{
-		Items:  make([]fileResponse, len(files)),
+	writeJSON(w, http.StatusOK, listFilesResponse{
+		Items:  s.fileResponses(files),
 		Total:  total,
 		Limit:  filter.Limit,
 		Offset: filter.Offset,
-	}
-
-	for i, f := range files {
-		resp.Items[i] = fileResponse{
-			ID:        f.ID,
-			ContentID: f.ContentID,
-			EpisodeID: f.EpisodeID,
-			Path:      f.Path,
-			SizeBytes: f.SizeBytes,
-			Quality:   f.Quality,
-			Source:    f.Source,
-			AddedAt:   f.AddedAt,
-		}
-	}
-
-	writeJSON(w, http.StatusOK, resp)
+	})
 }
 
-func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request) {
+// listEpisodeFiles handles GET /api/v1/episodes/{id}/files.
+// Lists the media files of an episode. A multi-episode file is recorded on
+// its first episode, so it is listed there.
+func (s *Server) listEpisodeFiles(w http.ResponseWriter, r *http.Request) {
 	id, err := pathID(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
 		return
 	}
-
-	if err := s.deps.Library.DeleteFile(id); err != nil {
+	if _, err := s.deps.Library.GetEpisode(id); err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Episode not found")
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
-}
-
-func (s *Server) checkLibrary(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	// Parse filters
-	filter := library.ContentFilter{
-		Limit:  queryInt(r, "limit", 100),
-		Offset: queryInt(r, "offset", 0),
-	}
-
-	if typeStr := queryString(r, "type"); typeStr != nil {
-		t := library.ContentType(*typeStr)
-		filter.Type = &t
-	}
-	if statusStr := queryString(r, "status"); statusStr != nil {
-		st := library.ContentStatus(*statusStr)
-		filter.Status = &st
-	}
-
-	// Get content
-	contents, total, err := s.deps.Library.ListContent(filter)
+	files, total, err := s.deps.Library.ListFiles(library.FileFilter{EpisodeID: &id})
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
-
-	resp := libraryCheckResponse{
-		Items: make([]libraryCheckItem, 0, len(contents)),
+	writeJSON(w, http.StatusOK, listFilesResponse{
+		Items: s.fileResponses(files),
 		Total: total,
-	}
+	})
+}
 
-	for _, c := range contents {
-		item := libraryCheckItem{
-			ID:     c.ID,
-			Type:   string(c.Type),
-			Title:  c.Title,
-			Year:   c.Year,
-			Status: string(c.Status),
+// fileResponses converts library files to their API responses, with the
+// season and episode numbers of their episodes loaded in one query.
+func (s *Server) fileResponses(files []*library.File) []fileResponse {
+	var episodeIDs []int64
+	for _, f := range files {
+		if f.EpisodeID != nil {
+			episodeIDs = append(episodeIDs, *f.EpisodeID)
 		}
-
-		// Get files for this content
-		files, _, err := s.deps.Library.ListFiles(library.FileFilter{ContentID: &c.ID})
+	}
+	episodes := make(map[int64]*library.Episode)
+	if len(episodeIDs) > 0 {
+		list, _, err := s.deps.Library.ListEpisodes(library.EpisodeFilter{IDs: uniqueIDs(episodeIDs)})
 		if err == nil {
-			item.FileCount = len(files)
-			allExist := true
-			for _, f := range files {
-				item.Files = append(item.Files, f.Path)
-				if !fileExists(f.Path) {
-					allExist = false
-					item.FileMissing = append(item.FileMissing, f.Path)
-					item.Issues = append(item.Issues, "File missing: "+f.Path)
-				}
+			for _, ep := range list {
+				episodes[ep.ID] = ep
 			}
-			item.FileExists = allExist && len(files) > 0
 		}
+	}
 
-		// Check content status vs file presence
-		if c.Status == library.StatusAvailable && len(files) == 0 {
-			item.Issues = append(item.Issues, "Status is 'available' but no files in database")
-		}
-		if c.Status == library.StatusWanted && len(files) > 0 {
-			item.Issues = append(item.Issues, "Status is 'wanted' but has files")
+	items := make([]fileResponse, len(files))
+	for i, f := range files {
+		items[i] = fileResponse{
+			ID:           f.ID,
+			ContentID:    f.ContentID,
+			EpisodeID:    f.EpisodeID,
+			Path:         f.Path,
+			SizeBytes:    f.SizeBytes,
+			Quality:      f.Quality,
+			Source:       f.Source,
+			Checksum:     f.Checksum,
+			ReleaseGroup: f.ReleaseGroup,
+			AddedAt:      f.AddedAt,
+			Missing:      f.MissingAt != nil,
+			MissingAt:    f.MissingAt,
+			Kind:         string(f.Kind),
+		}
+		if f.EpisodeID != nil {
+			if ep := episodes[*f.EpisodeID]; ep != nil {
+				items[i].Season = &ep.Season
+				items[i].Episode = &ep.Episode
+			}
 		}
+	}
+	return items
+}
 
-		// Check Plex if available
-		if s.deps.Plex != nil {
-			results, err := s.deps.Plex.Search(ctx, c.Title)
-			if err == nil {
-				// Look for matching title + year
-				for _, result := range results {
-					if result.Year == c.Year && strings.EqualFold(result.Title, c.Title) {
-						item.InPlex = true
-						item.PlexTitle = result.Title
-						break
-					}
-				}
-				// Check for approximate match if exact not found
-				if !item.InPlex {
-					for _, result := range results {
-						if result.Year == c.Year {
-							item.InPlex = true
-							item.PlexTitle = result.Title + " (year match)"
-							break
-						}
-					}
-				}
-			}
+// verifyFile handles POST /api/v1/files/{id}/verify.
+// Re-hashes the file on disk and compares it with the checksum recorded at
+// import, to detect corruption since. Files imported without verification
+// have no checksum and return 409.
+func (s *Server) verifyFile(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
 
-			// Check Plex consistency
-			if c.Status == library.StatusAvailable && !item.InPlex {
-				item.Issues = append(item.Issues, "Status is 'available' but not found in Plex")
-			}
+	f, err := s.deps.Library.GetFile(id)
+	if err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "File not found")
+			return
 		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	if f.Checksum == "" {
+		writeError(w, http.StatusConflict, "NO_CHECKSUM", "File was imported without checksum verification")
+		return
+	}
 
-		if len(item.Issues) > 0 {
-			resp.WithIssues++
-		} else {
-			resp.Healthy++
-		}
+	resp := verifyFileResponse{ID: f.ID, Path: f.Path, Expected: f.Checksum}
+	actual, err := importer.FileChecksum(r.Context(), f.Path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		resp.Status = fileVerifyMissing
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "FILE_ERROR", err.Error())
+		return
+	case actual == f.Checksum:
+		resp.Status = fileVerifyOK
+		resp.Actual = actual
+	default:
+		resp.Status = fileVerifyMismatch
+		resp.Actual = actual
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
 
-		resp.Items = append(resp.Items, item)
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	if err := s.deps.Library.DeleteFile(id); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (s *Server) getStatus(w http.ResponseWriter, r *http.Request) {
-	writeJSON(w, http.StatusOK, statusResponse{
+	resp := statusResponse{
 		Status:  "ok",
 		Version: "0.1.0",
-	})
+	}
+	if s.deps.Speed != nil {
+		resp.SpeedLimit = speedLimitToResponse(s.deps.Speed.Current())
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getMetrics handles GET /api/v1/metrics.
+// Returns per-route request counts and latency histograms in Prometheus text format.
+func (s *Server) getMetrics(w http.ResponseWriter, _ *http.Request) {
+	if s.deps.Metrics == nil {
+		writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Metrics not configured")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = s.deps.Metrics.WritePrometheus(w)
 }
 
-func (s *Server) getDashboard(w http.ResponseWriter, _ *http.Request) {
+// getDashboard handles GET /api/v1/dashboard.
+// With skip_plex=true the Plex library is not fetched for the not_in_plex check.
+func (s *Server) getDashboard(w http.ResponseWriter, r *http.Request) {
 	resp := DashboardResponse{
 		Version: "0.1.0",
 	}
@@ -1239,7 +2553,16 @@ func (s *Server) getDashboard(w http.ResponseWriter, _ *http.Request) {
 	// Connection status
 	resp.Connections.Server = true
 	resp.Connections.Plex = s.deps.Plex != nil
-	resp.Connections.SABnzbd = s.deps.Manager != nil
+	resp.Connections.Clients = []ClientConnection{}
+	if s.deps.Manager != nil {
+		for _, info := range s.deps.Manager.Clients() {
+			resp.Connections.Clients = append(resp.Connections.Clients, ClientConnection{
+				Name:      string(info.Name),
+				Protocol:  string(info.Protocol),
+				Connected: true,
+			})
+		}
+	}
 
 	// Download counts by status (single GROUP BY query)
 	counts, _ := s.deps.Downloads.CountByStatus()
@@ -1250,17 +2573,27 @@ func (s *Server) getDashboard(w http.ResponseWriter, _ *http.Request) {
 	resp.Downloads.Imported = counts[download.StatusImported]
 	resp.Downloads.Cleaned = counts[download.StatusCleaned]
 	resp.Downloads.Failed = counts[download.StatusFailed]
+	resp.Downloads.PartiallyImported = counts[download.StatusPartiallyImported]
+	if queue, err := s.deps.Downloads.ImportQueue(); err == nil {
+		resp.Downloads.ImportQueued = len(queue)
+	}
 
-	// Stuck count (>1hr in non-terminal state)
-	resp.Stuck.Threshold = 60
-	thresholds := map[download.Status]time.Duration{
-		download.StatusQueued:      time.Hour,
-		download.StatusDownloading: time.Hour,
-		download.StatusCompleted:   time.Hour,
-		download.StatusImporting:   time.Hour,
+	// Stuck downloads, past their status's threshold
+	thresholds := s.stuckThresholds()
+	resp.Stuck.ByStatus = make(map[string]int)
+	resp.Stuck.Thresholds = make(map[string]int64, len(thresholds))
+	for status, d := range thresholds {
+		minutes := int64(d / time.Minute)
+		resp.Stuck.Thresholds[string(status)] = minutes
+		if resp.Stuck.Threshold == 0 || minutes < resp.Stuck.Threshold {
+			resp.Stuck.Threshold = minutes
+		}
 	}
 	stuck, _ := s.deps.Downloads.ListStuck(thresholds)
 	resp.Stuck.Count = len(stuck)
+	for _, d := range stuck {
+		resp.Stuck.ByStatus[string(d.Status)]++
+	}
 
 	// Library counts
 	movieType := library.ContentTypeMovie
@@ -1269,20 +2602,13 @@ func (s *Server) getDashboard(w http.ResponseWriter, _ *http.Request) {
 	series, _, _ := s.deps.Library.ListContent(library.ContentFilter{Type: &seriesType})
 	resp.Library.Movies = len(movies)
 	resp.Library.Series = len(series)
-
-	writeJSON(w, http.StatusOK, resp)
-}
-
-func (s *Server) listProfiles(w http.ResponseWriter, r *http.Request) {
-	profiles := make([]profileResponse, 0, len(s.cfg.QualityProfiles))
-	for name, accept := range s.cfg.QualityProfiles {
-		profiles = append(profiles, profileResponse{
-			Name:   name,
-			Accept: accept,
-		})
+	if stats, err := s.deps.Library.StorageStats(); err == nil {
+		resp.Library.SizeBytes = stats.SizeBytes
 	}
 
-	writeJSON(w, http.StatusOK, listProfilesResponse{Profiles: profiles})
+	s.dashboardActivity(r.Context(), &resp, r.URL.Query().Get("skip_plex") != queryTrue)
+
+	writeJSON(w, http.StatusOK, resp)
 }
 
 func (s *Server) listIndexers(w http.ResponseWriter, r *http.Request) {
@@ -1294,9 +2620,28 @@ func (s *Server) listIndexers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for i, idx := range s.deps.Indexers {
+		categories := idx.Categories()
+		if categories == nil {
+			categories = []string{}
+		}
 		resp.Indexers[i] = indexerResponse{
-			Name: idx.Name(),
-			URL:  idx.URL(),
+			Name:       idx.Name(),
+			URL:        idx.URL(),
+			Priority:   idx.Priority(),
+			Categories: categories,
+			Keys:       []indexerKeyResponse{},
+		}
+		for _, k := range idx.KeyUsage() {
+			resp.Indexers[i].Keys = append(resp.Indexers[i].Keys, indexerKeyResponse{
+				Key:         k.Key,
+				Requests:    k.Requests,
+				Grabs:       k.Grabs,
+				Exhausted:   k.Exhausted,
+				APICurrent:  k.APICurrent,
+				APIMax:      k.APIMax,
+				GrabCurrent: k.GrabCurrent,
+				GrabMax:     k.GrabMax,
+			})
 		}
 
 		if testConn {
@@ -1502,184 +2847,341 @@ func (s *Server) searchPlex(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	items, err := s.deps.Plex.Search(ctx, query)
+	items, err := s.deps.Plex.Search(ctx, query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "PLEX_ERROR", err.Error())
+		return
+	}
+
+	resp := plexSearchResponse{
+		Query: query,
+		Items: make([]plexItemResponse, len(items)),
+		Total: len(items),
+	}
+
+	for i, item := range items {
+		resp.Items[i] = plexItemResponse{
+			Title:    item.Title,
+			Year:     item.Year,
+			Type:     item.Type,
+			AddedAt:  item.AddedAt,
+			FilePath: item.FilePath,
+		}
+
+		// Check if tracked in arrgo
+		content, _ := s.deps.Library.GetByTitleYear(item.Title, item.Year)
+		if content != nil {
+			resp.Items[i].Tracked = true
+			resp.Items[i].ContentID = &content.ID
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) importContent(w http.ResponseWriter, r *http.Request) {
+	var req importRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+
+	// Route to appropriate handler based on mode
+	if req.DownloadID != nil {
+		s.importTracked(w, r, req)
+	} else {
+		s.importManual(w, r, req)
+	}
+}
+
+// writeImportError writes the response for a failed import. A downgrade of
+// an existing file is a conflict the caller can override.
+func writeImportError(w http.ResponseWriter, err error) {
+	if errors.Is(err, importer.ErrImportWouldDowngrade) {
+		writeError(w, http.StatusConflict, "IMPORT_WOULD_DOWNGRADE", err.Error()+"; set allow_downgrade to import anyway")
+		return
+	}
+	writeError(w, http.StatusInternalServerError, "IMPORT_ERROR", err.Error())
+}
+
+// clientPath returns the local path of a download as reported by its client,
+// or "" if the client is not configured or does not know the download.
+func (s *Server) clientPath(ctx context.Context, dl *download.Download) string {
+	if s.deps.Manager == nil || dl.ClientID == "" {
+		return ""
+	}
+	status, err := s.deps.Manager.Status(ctx, dl)
+	if err != nil || status == nil || status.Path == "" {
+		return ""
+	}
+	return s.cfg.ClientPaths[dl.Client].ToLocal(status.Path)
+}
+
+// importTracked handles import of a tracked download by ID.
+func (s *Server) importTracked(w http.ResponseWriter, r *http.Request, req importRequest) {
+	ctx := r.Context()
+
+	// Get download from store
+	dl, err := s.deps.Downloads.Get(*req.DownloadID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "download not found")
+		return
+	}
+
+	// Verify download is in importable state (completed)
+	if dl.Status == download.StatusImporting {
+		writeError(w, http.StatusConflict, "IMPORT_IN_PROGRESS", "download is already being imported")
+		return
+	}
+	if dl.Status != download.StatusCompleted {
+		writeError(w, http.StatusBadRequest, "INVALID_STATE",
+			fmt.Sprintf("download must be in 'completed' status, currently '%s'", dl.Status))
+		return
+	}
+
+	// Construct source path from download root + (category) + release name
+	if s.cfg.DownloadRoot == "" {
+		writeError(w, http.StatusInternalServerError, "CONFIG_ERROR", "download_root not configured")
+		return
+	}
+
+	// Get content for response
+	content, err := s.deps.Library.GetContent(dl.ContentID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	// The client's storage path follows jobs it renamed or deduplicated; then
+	// the expected path, then a scan of the download root for obfuscated releases
+	sourcePath, err := importer.LocateDownload(s.cfg.DownloadRoot, dl, content,
+		s.clientPath(ctx, dl), download.SourcePath(s.cfg.DownloadRoot, dl))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "PATH_NOT_FOUND",
+			fmt.Sprintf("source path not found: %s: %v", download.SourcePath(s.cfg.DownloadRoot, dl), err))
+		return
+	}
+
+	// Claim the download: the import pipeline may be taking it at the same time
+	if !s.claimImport(w, dl) {
+		return
+	}
+	_ = s.deps.Downloads.DequeueImport(dl.ID) // Imported here, not by a worker
+
+	// Call appropriate importer method based on download type
+	if dl.IsCompleteSeason {
+		s.importSeasonPack(w, r, dl, content, sourcePath)
+		return
+	}
+
+	// Single file import
+	result, err := s.deps.Importer.ImportWithOptions(ctx, dl.ID, sourcePath, importer.ImportOptions{AllowDowngrade: req.AllowDowngrade})
+	if err != nil {
+		if errors.Is(err, importer.ErrImportWouldDowngrade) {
+			// Nothing was imported; leave it to be retried with allow_downgrade
+			_ = s.deps.Downloads.Transition(dl, download.StatusCompleted)
+		}
+		writeImportError(w, err)
+		return
+	}
+
+	// Transition to imported status
+	if err := s.deps.Downloads.Transition(dl, download.StatusImported); err != nil {
+		writeError(w, http.StatusInternalServerError, "TRANSITION_ERROR", err.Error())
+		return
+	}
+
+	// Publish ImportCompleted event for event-driven pipeline
+	if s.deps.Bus != nil {
+		evt := &events.ImportCompleted{
+			BaseEvent:  events.NewBaseEvent(events.EventImportCompleted, events.EntityDownload, dl.ID),
+			DownloadID: dl.ID,
+			ContentID:  dl.ContentID,
+			EpisodeID:  dl.EpisodeID,
+			FilePath:   result.DestPath,
+			FileSize:   result.SizeBytes,
+		}
+		_ = s.deps.Bus.Publish(ctx, evt)
+	}
+
+	writeJSON(w, http.StatusOK, importResponse{
+		FileID:        result.FileID,
+		ContentID:     content.ID,
+		EpisodeID:     dl.EpisodeID,
+		SourcePath:    result.SourcePath,
+		DestPath:      result.DestPath,
+		SizeBytes:     result.SizeBytes,
+		PlexNotified:  result.PlexNotified,
+		PlexScheduled: result.PlexScheduled,
+	})
+}
+
+// claimImport moves a download to importing if no one else has moved it
+// since it was read, writing a conflict if another import took it first.
+// Reports whether the caller may import it.
+func (s *Server) claimImport(w http.ResponseWriter, dl *download.Download) bool {
+	won, err := s.deps.Downloads.Claim(dl, download.StatusImporting)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "TRANSITION_ERROR", err.Error())
+		return false
+	}
+	if !won {
+		writeError(w, http.StatusConflict, "IMPORT_IN_PROGRESS", "download is already being imported")
+		return false
+	}
+	return true
+}
+
+// importSeasonPack imports a season pack download that is importing and
+// writes the response. The download becomes imported, or partially imported
+// if any episode failed.
+func (s *Server) importSeasonPack(w http.ResponseWriter, r *http.Request, dl *download.Download, content *library.Content, sourcePath string) {
+	ctx := r.Context()
+
+	packResult, err := s.deps.Importer.ImportSeasonPack(ctx, dl.ID, sourcePath)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "PLEX_ERROR", err.Error())
+		writeError(w, http.StatusInternalServerError, "IMPORT_ERROR", err.Error())
 		return
 	}
 
-	resp := plexSearchResponse{
-		Query: query,
-		Items: make([]plexItemResponse, len(items)),
-		Total: len(items),
+	status := packResult.DownloadStatus()
+	if err := s.deps.Downloads.Transition(dl, status); err != nil {
+		writeError(w, http.StatusInternalServerError, "TRANSITION_ERROR", err.Error())
+		return
 	}
 
-	for i, item := range items {
-		resp.Items[i] = plexItemResponse{
-			Title:    item.Title,
-			Year:     item.Year,
-			Type:     item.Type,
-			AddedAt:  item.AddedAt,
-			FilePath: item.FilePath,
+	episodeResults := make([]events.EpisodeImportResult, 0, len(packResult.Episodes))
+	for _, ep := range packResult.Episodes {
+		var errStr string
+		if ep.Error != nil {
+			errStr = ep.Error.Error()
 		}
+		episodeResults = append(episodeResults, events.EpisodeImportResult{
+			EpisodeID:       ep.EpisodeID,
+			FileID:          ep.FileID,
+			Season:          ep.Season,
+			Episode:         ep.Episode,
+			Success:         ep.Error == nil,
+			SourceFile:      ep.SourceFile,
+			FilePath:        ep.FilePath,
+			Error:           errStr,
+			AlreadyImported: ep.AlreadyImported,
+		})
+	}
 
-		// Check if tracked in arrgo
-		content, _ := s.deps.Library.GetByTitleYear(item.Title, item.Year)
-		if content != nil {
-			resp.Items[i].Tracked = true
-			resp.Items[i].ContentID = &content.ID
+	// Publish ImportCompleted event for event-driven pipeline
+	if s.deps.Bus != nil {
+		evt := &events.ImportCompleted{
+			BaseEvent:      events.NewBaseEvent(events.EventImportCompleted, events.EntityDownload, dl.ID),
+			DownloadID:     dl.ID,
+			ContentID:      dl.ContentID,
+			FileSize:       packResult.TotalSize,
+			EpisodeResults: episodeResults,
 		}
+		_ = s.deps.Bus.Publish(ctx, evt)
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	writeJSON(w, http.StatusOK, importResponse{
+		ContentID:      content.ID,
+		SourcePath:     sourcePath,
+		SizeBytes:      packResult.TotalSize,
+		PlexNotified:   packResult.PlexNotified,
+		PlexScheduled:  packResult.PlexScheduled,
+		EpisodeCount:   len(packResult.Episodes),
+		Status:         string(status),
+		EpisodeResults: episodeResults,
+	})
 }
 
-func (s *Server) importContent(w http.ResponseWriter, r *http.Request) {
-	var req importRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+// reimportDownload handles POST /api/v1/downloads/{id}/reimport.
+// Retries the episodes of a partially imported season pack that have no
+// successful file record; episodes already imported are skipped.
+func (s *Server) reimportDownload(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
 		return
 	}
 
-	// Route to appropriate handler based on mode
-	if req.DownloadID != nil {
-		s.importTracked(w, r, req)
-	} else {
-		s.importManual(w, r, req)
-	}
-}
-
-// importTracked handles import of a tracked download by ID.
-func (s *Server) importTracked(w http.ResponseWriter, r *http.Request, req importRequest) {
-	ctx := r.Context()
-
-	// Get download from store
-	dl, err := s.deps.Downloads.Get(*req.DownloadID)
+	dl, err := s.deps.Downloads.Get(id)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "NOT_FOUND", "download not found")
+		if errors.Is(err, download.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Download not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
-	// Verify download is in importable state (completed)
-	if dl.Status != download.StatusCompleted {
+	if dl.Status != download.StatusPartiallyImported {
 		writeError(w, http.StatusBadRequest, "INVALID_STATE",
-			fmt.Sprintf("download must be in 'completed' status, currently '%s'", dl.Status))
+			fmt.Sprintf("download must be in '%s' status, currently '%s'", download.StatusPartiallyImported, dl.Status))
 		return
 	}
-
-	// Construct source path from download root + release name
 	if s.cfg.DownloadRoot == "" {
 		writeError(w, http.StatusInternalServerError, "CONFIG_ERROR", "download_root not configured")
 		return
 	}
-	sourcePath := filepath.Join(s.cfg.DownloadRoot, dl.ReleaseName)
 
-	// Verify path exists
-	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		writeError(w, http.StatusNotFound, "PATH_NOT_FOUND",
-			fmt.Sprintf("source path not found: %s", sourcePath))
+	content, err := s.deps.Library.GetContent(dl.ContentID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
-	// Get content for response
-	content, err := s.deps.Library.GetContent(dl.ContentID)
+	sourcePath, err := importer.LocateDownload(s.cfg.DownloadRoot, dl, content,
+		s.clientPath(r.Context(), dl), download.SourcePath(s.cfg.DownloadRoot, dl))
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		writeError(w, http.StatusNotFound, "PATH_NOT_FOUND",
+			fmt.Sprintf("source path not found: %s: %v", download.SourcePath(s.cfg.DownloadRoot, dl), err))
 		return
 	}
 
-	// Transition to importing status
-	if err := s.deps.Downloads.Transition(dl, download.StatusImporting); err != nil {
-		writeError(w, http.StatusInternalServerError, "TRANSITION_ERROR", err.Error())
+	if !s.claimImport(w, dl) {
 		return
 	}
 
-	// Call appropriate importer method based on download type
-	if dl.IsCompleteSeason {
-		// Season pack import
-		packResult, err := s.deps.Importer.ImportSeasonPack(ctx, dl.ID, sourcePath)
-		if err != nil {
-			writeError(w, http.StatusInternalServerError, "IMPORT_ERROR", err.Error())
-			return
-		}
+	s.importSeasonPack(w, r, dl, content, sourcePath)
+}
 
-		// Transition to imported status
-		if err := s.deps.Downloads.Transition(dl, download.StatusImported); err != nil {
-			writeError(w, http.StatusInternalServerError, "TRANSITION_ERROR", err.Error())
-			return
-		}
+// importNext handles POST /api/v1/downloads/{id}/import-next.
+// Moves a completed download waiting in the import queue to the front, so the
+// next free import worker takes it.
+func (s *Server) importNext(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
 
-		// Publish ImportCompleted event for event-driven pipeline
-		if s.deps.Bus != nil {
-			episodeResults := make([]events.EpisodeImportResult, 0, len(packResult.Episodes))
-			for _, ep := range packResult.Episodes {
-				var errStr string
-				if ep.Error != nil {
-					errStr = ep.Error.Error()
-				}
-				episodeResults = append(episodeResults, events.EpisodeImportResult{
-					EpisodeID: ep.EpisodeID,
-					Season:    ep.Season,
-					Episode:   ep.Episode,
-					Success:   ep.Error == nil,
-					FilePath:  ep.FilePath,
-					Error:     errStr,
-				})
-			}
-			evt := &events.ImportCompleted{
-				BaseEvent:      events.NewBaseEvent(events.EventImportCompleted, events.EntityDownload, dl.ID),
-				DownloadID:     dl.ID,
-				ContentID:      dl.ContentID,
-				FileSize:       packResult.TotalSize,
-				EpisodeResults: episodeResults,
-			}
-			_ = s.deps.Bus.Publish(ctx, evt)
+	dl, err := s.deps.Downloads.Get(id)
+	if err != nil {
+		if errors.Is(err, download.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Download not found")
+			return
 		}
-
-		writeJSON(w, http.StatusOK, importResponse{
-			ContentID:    content.ID,
-			SourcePath:   sourcePath,
-			SizeBytes:    packResult.TotalSize,
-			PlexNotified: packResult.PlexNotified,
-			EpisodeCount: len(packResult.Episodes),
-		})
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
 
-	// Single file import
-	result, err := s.deps.Importer.Import(ctx, dl.ID, sourcePath)
+	positions, err := s.importPositions()
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "IMPORT_ERROR", err.Error())
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 		return
 	}
-
-	// Transition to imported status
-	if err := s.deps.Downloads.Transition(dl, download.StatusImported); err != nil {
-		writeError(w, http.StatusInternalServerError, "TRANSITION_ERROR", err.Error())
+	if positions[id] == 0 {
+		writeError(w, http.StatusConflict, "NOT_QUEUED",
+			fmt.Sprintf("download is not waiting for import (status '%s')", dl.Status))
 		return
 	}
-
-	// Publish ImportCompleted event for event-driven pipeline
-	if s.deps.Bus != nil {
-		evt := &events.ImportCompleted{
-			BaseEvent:  events.NewBaseEvent(events.EventImportCompleted, events.EntityDownload, dl.ID),
-			DownloadID: dl.ID,
-			ContentID:  dl.ContentID,
-			EpisodeID:  dl.EpisodeID,
-			FilePath:   result.DestPath,
-			FileSize:   result.SizeBytes,
-		}
-		_ = s.deps.Bus.Publish(ctx, evt)
+	if err := s.deps.Downloads.PrioritizeImport(id); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
 	}
 
-	writeJSON(w, http.StatusOK, importResponse{
-		FileID:       result.FileID,
-		ContentID:    content.ID,
-		SourcePath:   result.SourcePath,
-		DestPath:     result.DestPath,
-		SizeBytes:    result.SizeBytes,
-		PlexNotified: result.PlexNotified,
-	})
+	resp := downloadToResponse(dl)
+	resp.ImportPosition = 1
+	writeJSON(w, http.StatusOK, resp)
 }
 
 // importManual handles manual file import with metadata.
@@ -1706,8 +3208,8 @@ func (s *Server) importManual(w http.ResponseWriter, r *http.Request, req import
 
 	// Validate type
 	contentType := library.ContentType(req.Type)
-	if contentType != library.ContentTypeMovie && contentType != library.ContentTypeSeries {
-		writeError(w, http.StatusBadRequest, "INVALID_TYPE", "type must be 'movie' or 'series'")
+	if !s.contentTypeAllowed(contentType) {
+		writeError(w, http.StatusBadRequest, "INVALID_TYPE", s.contentTypeMessage())
 		return
 	}
 
@@ -1726,10 +3228,7 @@ func (s *Server) importManual(w http.ResponseWriter, r *http.Request, req import
 	}
 	if content == nil {
 		// Create new content
-		rootPath := s.cfg.MovieRoot
-		if contentType == library.ContentTypeSeries {
-			rootPath = s.cfg.SeriesRoot
-		}
+		rootPath := s.defaultRoot(contentType)
 
 		content = &library.Content{
 			Type:           contentType,
@@ -1739,6 +3238,9 @@ func (s *Server) importManual(w http.ResponseWriter, r *http.Request, req import
 			QualityProfile: "hd",
 			RootPath:       rootPath,
 		}
+		if contentType == library.ContentTypeAudiobook {
+			content.Author = strings.TrimSpace(req.Author)
+		}
 		if err := s.deps.Library.AddContent(content); err != nil {
 			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
 			return
@@ -1786,9 +3288,9 @@ func (s *Server) importManual(w http.ResponseWriter, r *http.Request, req import
 	}
 
 	// Call importer
-	result, err := s.deps.Importer.Import(ctx, dl.ID, req.Path)
+	result, err := s.deps.Importer.ImportWithOptions(ctx, dl.ID, req.Path, importer.ImportOptions{AllowDowngrade: req.AllowDowngrade})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "IMPORT_ERROR", err.Error())
+		writeImportError(w, err)
 		return
 	}
 
@@ -1807,12 +3309,13 @@ func (s *Server) importManual(w http.ResponseWriter, r *http.Request, req import
 	}
 
 	writeJSON(w, http.StatusOK, importResponse{
-		FileID:       result.FileID,
-		ContentID:    content.ID,
-		SourcePath:   result.SourcePath,
-		DestPath:     result.DestPath,
-		SizeBytes:    result.SizeBytes,
-		PlexNotified: result.PlexNotified,
+		FileID:        result.FileID,
+		ContentID:     content.ID,
+		SourcePath:    result.SourcePath,
+		DestPath:      result.DestPath,
+		SizeBytes:     result.SizeBytes,
+		PlexNotified:  result.PlexNotified,
+		PlexScheduled: result.PlexScheduled,
 	})
 }
 
@@ -1859,15 +3362,22 @@ func (s *Server) importLibrary(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate source
-	if req.Source == "" {
+	switch req.Source {
+	case "":
 		writeError(w, http.StatusBadRequest, "MISSING_SOURCE", "source is required")
-		return
-	}
-	if req.Source != "plex" {
+	case "plex":
+		s.importPlexLibrary(w, r, req)
+	case "filesystem":
+		s.importFilesystemLibrary(w, req)
+	case "radarr", "sonarr":
+		s.importArrLibrary(w, req)
+	default:
 		writeError(w, http.StatusBadRequest, "INVALID_SOURCE", "unsupported source: "+req.Source)
-		return
 	}
+}
 
+// importPlexLibrary imports the items of a Plex library section.
+func (s *Server) importPlexLibrary(w http.ResponseWriter, r *http.Request, req libraryImportRequest) {
 	// Validate library
 	if req.Library == "" {
 		writeError(w, http.StatusBadRequest, "MISSING_LIBRARY", "library is required")
@@ -1898,18 +3408,47 @@ func (s *Server) importLibrary(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Process items (stub for now - will be implemented in Task 5)
+	// Fail fast on a broken path mapping instead of erroring on every item
+	if !req.DryRun {
+		if err := s.checkImportPaths(items); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "PATH_MAPPING_FAILED", err.Error())
+			return
+		}
+	}
+
 	resp := s.processPlexImport(r.Context(), items, req.QualityOverride, req.DryRun)
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// importFilesystemLibrary imports the movies and series found by scanning a directory.
+func (s *Server) importFilesystemLibrary(w http.ResponseWriter, req libraryImportRequest) {
+	if req.Path == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PATH", "path is required")
+		return
+	}
+	root := filepath.Clean(req.Path)
+	if !filepath.IsAbs(root) {
+		writeError(w, http.StatusBadRequest, "INVALID_PATH", "path must be absolute")
+		return
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		writeError(w, http.StatusBadRequest, "INVALID_PATH", "not a directory: "+root)
+		return
+	}
+
+	items, err := importer.ScanDirectory(root)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "SCAN_FAILED", err.Error())
+		return
+	}
+
+	resp := s.processFilesystemImport(items, root, req.QualityOverride, req.DryRun)
+	writeJSON(w, http.StatusOK, resp)
+}
+
 // processPlexImport processes Plex items for import.
 func (s *Server) processPlexImport(ctx context.Context, items []importer.PlexItem, qualityOverride string, dryRun bool) libraryImportResponse {
-	resp := libraryImportResponse{
-		Imported: []libraryImportItem{},
-		Skipped:  []libraryImportItem{},
-		Errors:   []libraryImportItem{},
-	}
+	resp := newLibraryImportResponse()
 
 	for _, item := range items {
 		// Map Plex type to our type
@@ -1981,6 +3520,134 @@ func (s *Server) processPlexImport(ctx context.Context, items []importer.PlexIte
 	return resp
 }
 
+// newLibraryImportResponse returns a response with empty, non-nil item lists.
+func newLibraryImportResponse() libraryImportResponse {
+	return libraryImportResponse{
+		Imported:    []libraryImportItem{},
+		Skipped:     []libraryImportItem{},
+		Errors:      []libraryImportItem{},
+		NeedsReview: []libraryImportItem{},
+	}
+}
+
+// processFilesystemImport processes items found by scanning a directory.
+// Items that could not be identified go to needs_review rather than being imported.
+func (s *Server) processFilesystemImport(items []*importer.ScannedItem, root, qualityOverride string, dryRun bool) libraryImportResponse {
+	resp := newLibraryImportResponse()
+
+	for _, item := range items {
+		importItem := libraryImportItem{
+			Title: item.Title,
+			Year:  item.Year,
+			Type:  string(item.Type),
+			Path:  item.Path,
+		}
+		if item.Type == library.ContentTypeSeries {
+			importItem.Episodes = len(item.Files)
+		}
+
+		if item.Review != "" {
+			importItem.Reason = item.Review
+			resp.NeedsReview = append(resp.NeedsReview, importItem)
+			continue
+		}
+
+		// Check if already tracked
+		contentType := item.Type
+		title := item.Title
+		year := item.Year
+		existing, _, _ := s.deps.Library.ListContent(library.ContentFilter{
+			Type:  &contentType,
+			Title: &title,
+			Year:  &year,
+			Limit: 1,
+		})
+		if len(existing) > 0 {
+			importItem.ContentID = existing[0].ID
+			importItem.Reason = "already tracked"
+			resp.Skipped = append(resp.Skipped, importItem)
+			continue
+		}
+
+		importItem.Quality = mapResolutionToProfile(item.Files[0].Resolution)
+		if qualityOverride != "" {
+			importItem.Quality = qualityOverride
+		}
+
+		if !dryRun {
+			contentID, err := s.createScannedContent(item, root, importItem.Quality)
+			if err != nil {
+				importItem.Error = err.Error()
+				resp.Errors = append(resp.Errors, importItem)
+				continue
+			}
+			importItem.ContentID = contentID
+		}
+
+		resp.Imported = append(resp.Imported, importItem)
+	}
+
+	resp.Summary.Imported = len(resp.Imported)
+	resp.Summary.Skipped = len(resp.Skipped)
+	resp.Summary.Errors = len(resp.Errors)
+	resp.Summary.NeedsReview = len(resp.NeedsReview)
+
+	return resp
+}
+
+// createScannedContent creates content, episode and file records for a scanned item
+// in one transaction, so a failure leaves nothing behind.
+func (s *Server) createScannedContent(item *importer.ScannedItem, root, qualityProfile string) (int64, error) {
+	tx, err := s.deps.Library.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	content := &library.Content{
+		Type:           item.Type,
+		Title:          item.Title,
+		Year:           item.Year,
+		Status:         library.StatusAvailable,
+		QualityProfile: qualityProfile,
+		RootPath:       root,
+	}
+	if err := tx.AddContent(content); err != nil {
+		return 0, fmt.Errorf("create content: %w", err)
+	}
+
+	for _, f := range item.Files {
+		file := &library.File{
+			ContentID:    content.ID,
+			Path:         f.Path,
+			SizeBytes:    f.Size,
+			Quality:      f.Resolution.String(),
+			Source:       "filesystem-import",
+			ReleaseGroup: f.Group,
+		}
+		if item.Type == library.ContentTypeSeries {
+			ep := &library.Episode{
+				ContentID: content.ID,
+				Season:    f.Season,
+				Episode:   f.Episode,
+				Status:    library.StatusAvailable,
+			}
+			if err := tx.AddEpisode(ep); err != nil {
+				return 0, fmt.Errorf("create episode S%02dE%02d: %w", f.Season, f.Episode, err)
+			}
+			file.EpisodeID = &ep.ID
+		}
+		if err := tx.AddFile(file); err != nil {
+			return 0, fmt.Errorf("create file %s: %w", f.Path, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return content.ID, nil
+}
+
 // mapResolutionToProfile maps a resolution string to a quality profile name.
 func mapResolutionToProfile(resolution release.Resolution) string {
 	switch resolution {
@@ -2027,6 +3694,7 @@ func (s *Server) createImportedContent(_ context.Context, item importer.PlexItem
 		Status:         library.StatusAvailable,
 		QualityProfile: qualityProfile,
 		RootPath:       rootPath,
+		PlexKey:        item.RatingKey,
 	}
 
 	if err := s.deps.Library.AddContent(content); err != nil {
@@ -2037,11 +3705,12 @@ func (s *Server) createImportedContent(_ context.Context, item importer.PlexItem
 	if contentType == library.ContentTypeMovie && localPath != "" {
 		parsed := release.Parse(filepath.Base(localPath))
 		file := &library.File{
-			ContentID: content.ID,
-			Path:      localPath,
-			SizeBytes: fileSize,
-			Quality:   parsed.Resolution.String(),
-			Source:    "plex-import",
+			ContentID:    content.ID,
+			Path:         localPath,
+			SizeBytes:    fileSize,
+			Quality:      parsed.Resolution.String(),
+			Source:       "plex-import",
+			ReleaseGroup: parsed.Group,
 		}
 		if err := s.deps.Library.AddFile(file); err != nil {
 			// Best effort - content was created successfully
@@ -2053,6 +3722,75 @@ func (s *Server) createImportedContent(_ context.Context, item importer.PlexItem
 	return content.ID, nil
 }
 
+// renameLibrary handles POST /api/v1/library/rename.
+// Moves a content item's files to the paths given by the current naming
+// templates. With dry_run, only reports what would change.
+func (s *Server) renameLibrary(w http.ResponseWriter, r *http.Request) {
+	var req libraryRenameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+	if req.ContentID == 0 {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "content_id is required")
+		return
+	}
+
+	resp := libraryRenameResponse{ContentID: req.ContentID, DryRun: req.DryRun}
+	var files []importer.FileRename
+	if req.DryRun {
+		plans, err := s.deps.Importer.PreviewRename(req.ContentID)
+		if err != nil {
+			writeRenameError(w, err)
+			return
+		}
+		files = plans
+	} else {
+		result, err := s.deps.Importer.Rename(r.Context(), req.ContentID)
+		if err != nil {
+			writeRenameError(w, err)
+			return
+		}
+		files = result.Files
+		resp.PlexNotified = result.PlexNotified
+	}
+
+	resp.Files = make([]libraryRenameFile, 0, len(files))
+	for _, f := range files {
+		item := libraryRenameFile{
+			FileID:    f.FileID,
+			EpisodeID: f.EpisodeID,
+			Kind:      string(f.Kind),
+			OldPath:   f.OldPath,
+			NewPath:   f.NewPath,
+			Status:    string(f.Status),
+		}
+		if f.Error != nil {
+			item.Error = f.Error.Error()
+		}
+		switch f.Status {
+		case importer.RenamePending, importer.RenameRenamed:
+			resp.Summary.Renamed++
+		case importer.RenameUnchanged:
+			resp.Summary.Unchanged++
+		default:
+			resp.Summary.Failed++
+		}
+		resp.Files = append(resp.Files, item)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeRenameError maps an error from computing renames to an HTTP response.
+func writeRenameError(w http.ResponseWriter, err error) {
+	if errors.Is(err, library.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+		return
+	}
+	writeError(w, http.StatusInternalServerError, "RENAME_ERROR", err.Error())
+}
+
 // handleTVDBSearch handles GET /api/v1/tvdb/search?q=query
 func (s *Server) handleTVDBSearch(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
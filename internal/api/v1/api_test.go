@@ -2,48 +2,55 @@
 package v1
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
-	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	_ "modernc.org/sqlite"
 
+	"github.com/vmunix/arrgo/internal/api/auth"
+	"github.com/vmunix/arrgo/internal/api/requestlog"
 	"github.com/vmunix/arrgo/internal/api/v1/mocks"
+	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/database"
 	"github.com/vmunix/arrgo/internal/download"
+	downloadmocks "github.com/vmunix/arrgo/internal/download/mocks"
 	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/handlers"
 	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/metadata"
+	"github.com/vmunix/arrgo/internal/profile"
 	"github.com/vmunix/arrgo/internal/search"
+	"github.com/vmunix/arrgo/internal/testutil"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
+	"github.com/vmunix/arrgo/internal/tmdb"
+	"github.com/vmunix/arrgo/pkg/newznab"
 	"github.com/vmunix/arrgo/pkg/tvdb"
 	"go.uber.org/mock/gomock"
 )
 
-//go:embed testdata/schema.sql
-var testSchema string
-
-func setupTestDB(t *testing.T) *sql.DB {
-	t.Helper()
-	db, err := sql.Open("sqlite", ":memory:?_foreign_keys=on")
-	require.NoError(t, err, "open db")
-	t.Cleanup(func() { _ = db.Close() })
-
-	_, err = db.Exec(testSchema)
-	require.NoError(t, err, "apply schema")
-	return db
-}
-
 func TestNew(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	cfg := Config{
 		MovieRoot:  "/movies",
 		SeriesRoot: "/tv",
@@ -57,7 +64,7 @@ func TestNew(t *testing.T) {
 }
 
 func TestListContent_Empty(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/content", nil)
@@ -74,7 +81,7 @@ func TestListContent_Empty(t *testing.T) {
 }
 
 func TestListContent_WithItems(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
 	// Add test content
@@ -103,7 +110,7 @@ func TestListContent_WithItems(t *testing.T) {
 }
 
 func TestListContent_WithFilters(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
 	// Add movie
@@ -146,8 +153,45 @@ func TestListContent_WithFilters(t *testing.T) {
 	assert.Len(t, resp.Items, 1, "filter by status: items")
 }
 
+func TestListContent_Sort(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	for _, title := range []string{"Beta", "Alpha", "Gamma"} {
+		fixtures.NewMovie(title, 2024).Insert(t, db)
+	}
+
+	titles := func(query string) []string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/content"+query, nil)
+		w := httptest.NewRecorder()
+		srv.listContent(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp listContentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		var got []string
+		for _, item := range resp.Items {
+			got = append(got, item.Title)
+		}
+		return got
+	}
+
+	// Added in the same second; recently added first falls back to newest ID
+	assert.Equal(t, []string{"Gamma", "Alpha", "Beta"}, titles(""))
+	assert.Equal(t, []string{"Alpha", "Beta", "Gamma"}, titles("?sort=title&order=asc"))
+	assert.Equal(t, []string{"Alpha", "Gamma"}, append(titles("?sort=title&order=asc&limit=1"),
+		titles("?sort=title&order=asc&limit=1&offset=2")...))
+
+	for _, query := range []string{"?sort=root_path", "?sort=title--", "?order=up"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/content"+query, nil)
+		w := httptest.NewRecorder()
+		srv.listContent(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
 func TestGetContent_Found(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
 	c := &library.Content{
@@ -174,7 +218,7 @@ func TestGetContent_Found(t *testing.T) {
 }
 
 func TestGetContent_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/content/999", nil)
@@ -187,7 +231,7 @@ func TestGetContent_NotFound(t *testing.T) {
 }
 
 func TestAddContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{MovieRoot: "/movies", SeriesRoot: "/tv"})
 
 	body := `{"type":"movie","title":"New Movie","year":2024,"quality_profile":"hd"}`
@@ -207,8 +251,101 @@ func TestAddContent(t *testing.T) {
 	assert.Equal(t, "/movies", resp.RootPath)
 }
 
+func TestAddContent_SelectsRoot(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{
+		MovieRoot:   "/movies",
+		SeriesRoot:  "/tv",
+		MovieRoots:  library.NewRootFolders([]string{"/movies", "/movies2"}, "round_robin"),
+		SeriesRoots: library.NewRootFolders([]string{"/tv", "/tv2"}, "round_robin"),
+	})
+
+	add := func(body string) contentResponse {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/content", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.addContent(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "response body: %s", w.Body.String())
+		var resp contentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	assert.Equal(t, "/movies", add(`{"type":"movie","title":"One","year":2024,"quality_profile":"hd"}`).RootPath)
+	assert.Equal(t, "/movies2", add(`{"type":"movie","title":"Two","year":2024,"quality_profile":"hd"}`).RootPath)
+	assert.Equal(t, "/tv", add(`{"type":"series","title":"Show","year":2024,"quality_profile":"hd"}`).RootPath)
+	assert.Equal(t, "/custom", add(`{"type":"movie","title":"Three","year":2024,"quality_profile":"hd","root_path":"/custom"}`).RootPath,
+		"an explicit root path is kept")
+}
+
+func TestAddContent_AdoptsMovieInPlex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{MovieRoot: "/movies", AdoptExisting: true})
+	require.NoError(t, err)
+
+	mockPlex.EXPECT().FindMovieItem(gomock.Any(), "Owned Movie", 2024).
+		Return(&importer.PlexItem{Title: "Owned Movie", Year: 2024, Type: "movie", FilePath: "/data/Owned Movie (2024) 2160p.mkv"}, nil)
+	mockPlex.EXPECT().TranslateToLocal("/data/Owned Movie (2024) 2160p.mkv").Return("/movies/Owned Movie (2024) 2160p.mkv")
+	mockPlex.EXPECT().FindMovieItem(gomock.Any(), "New Movie", 2024).Return(nil, nil)
+
+	add := func(body string) contentResponse {
+		w := httptest.NewRecorder()
+		srv.addContent(w, httptest.NewRequest(http.MethodPost, "/api/v1/content", strings.NewReader(body)))
+		require.Equal(t, http.StatusCreated, w.Code, "response body: %s", w.Body.String())
+		var resp contentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	owned := add(`{"type":"movie","title":"Owned Movie","year":2024,"quality_profile":"hd"}`)
+	assert.Equal(t, "available", owned.Status)
+	assert.Equal(t, "/movies/Owned Movie (2024) 2160p.mkv", owned.ExistingFile)
+	files, _, err := deps.Library.ListFiles(library.FileFilter{ContentID: &owned.ID})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "2160p", files[0].Quality)
+	assert.Equal(t, importer.SourcePlexExisting, files[0].Source)
+
+	missing := add(`{"type":"movie","title":"New Movie","year":2024,"quality_profile":"hd"}`)
+	assert.Equal(t, "wanted", missing.Status)
+	assert.Empty(t, missing.ExistingFile)
+
+	// Series are never looked up
+	show := add(`{"type":"series","title":"Show","year":2024,"quality_profile":"hd"}`)
+	assert.Equal(t, "wanted", show.Status)
+}
+
+func TestAddContent_AdoptExistingDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv, err := NewWithDeps(ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}, Config{MovieRoot: "/movies"})
+	require.NoError(t, err)
+
+	// No Plex calls expected: a re-download is wanted
+	w := httptest.NewRecorder()
+	body := `{"type":"movie","title":"Owned Movie","year":2024,"quality_profile":"hd"}`
+	srv.addContent(w, httptest.NewRequest(http.MethodPost, "/api/v1/content", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, w.Code, "response body: %s", w.Body.String())
+	var resp contentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "wanted", resp.Status)
+}
+
 func TestAddContent_InvalidType(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
 	body := `{"type":"invalid","title":"Test","year":2024,"quality_profile":"hd"}`
@@ -220,8 +357,30 @@ func TestAddContent_InvalidType(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestAddContent_Audiobook(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	body := `{"type":"audiobook","title":"Dune","year":1965,"author":" Frank Herbert ","quality_profile":"audiobook"}`
+
+	srv := New(db, Config{})
+	w := httptest.NewRecorder()
+	srv.addContent(w, httptest.NewRequest(http.MethodPost, "/api/v1/content", strings.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "audiobooks need their library enabled")
+	assert.Contains(t, w.Body.String(), "type must be 'movie' or 'series'")
+
+	srv = New(db, Config{Audiobooks: true, AudiobookRoot: "/audiobooks"})
+	w = httptest.NewRecorder()
+	srv.addContent(w, httptest.NewRequest(http.MethodPost, "/api/v1/content", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp contentResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "audiobook", resp.Type)
+	assert.Equal(t, "Frank Herbert", resp.Author)
+	assert.Equal(t, "/audiobooks", resp.RootPath)
+}
+
 func TestUpdateContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
 	// Add content first
@@ -250,11 +409,10 @@ func TestUpdateContent(t *testing.T) {
 	assert.Equal(t, library.StatusAvailable, updated.Status)
 }
 
-func TestDeleteContent(t *testing.T) {
-	db := setupTestDB(t)
+func TestUpdateContent_MinimumAvailability(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Add content first
 	c := &library.Content{
 		Type:           library.ContentTypeMovie,
 		Title:          "Test",
@@ -265,770 +423,5486 @@ func TestDeleteContent(t *testing.T) {
 	}
 	require.NoError(t, srv.deps.Library.AddContent(c))
 
-	req := httptest.NewRequest(http.MethodDelete, "/api/v1/content/1", nil)
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/content/1", strings.NewReader(`{"minimum_availability":"released"}`))
 	req.SetPathValue("id", "1")
 	w := httptest.NewRecorder()
+	srv.updateContent(w, req)
 
-	srv.deleteContent(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	var resp contentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "released", resp.MinimumAvailability)
 
-	assert.Equal(t, http.StatusNoContent, w.Code)
+	updated, err := srv.deps.Library.GetContent(1)
+	require.NoError(t, err)
+	assert.Equal(t, library.AvailabilityReleased, updated.MinimumAvailability)
 
-	// Verify deleted
-	_, err := srv.deps.Library.GetContent(1)
-	assert.ErrorIs(t, err, library.ErrNotFound, "expected ErrNotFound")
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/content/1", strings.NewReader(`{"minimum_availability":"soon"}`))
+	req.SetPathValue("id", "1")
+	w = httptest.NewRecorder()
+	srv.updateContent(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestListEpisodes(t *testing.T) {
-	db := setupTestDB(t)
+func TestUpdateContent_Daily(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Add series
-	series := &library.Content{
-		Type:           library.ContentTypeSeries,
-		Title:          "Test Series",
-		Year:           2024,
-		Status:         library.StatusWanted,
-		QualityProfile: "hd",
-		RootPath:       "/tv",
-	}
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "The Daily Show", Year: 1996, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
 	require.NoError(t, srv.deps.Library.AddContent(series))
+	movie := &library.Content{Type: library.ContentTypeMovie, Title: "Test", Year: 2024, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, srv.deps.Library.AddContent(movie))
 
-	// Add episodes
-	for i := 1; i <= 3; i++ {
-		ep := &library.Episode{
-			ContentID: series.ID,
-			Season:    1,
-			Episode:   i,
-			Title:     fmt.Sprintf("Episode %d", i),
-			Status:    library.StatusWanted,
-		}
-		require.NoError(t, srv.deps.Library.AddEpisode(ep))
+	update := func(id int64, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/content/%d", id), strings.NewReader(body))
+		req.SetPathValue("id", strconv.FormatInt(id, 10))
+		w := httptest.NewRecorder()
+		srv.updateContent(w, req)
+		return w
 	}
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/content/1/episodes", nil)
-	req.SetPathValue("id", "1")
-	w := httptest.NewRecorder()
-
-	srv.listEpisodes(w, req)
+	w := update(series.ID, `{"daily":true}`)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	var resp contentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Daily)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	updated, err := srv.deps.Library.GetContent(series.ID)
+	require.NoError(t, err)
+	assert.True(t, updated.Daily)
 
-	var resp listEpisodesResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Len(t, resp.Items, 3)
+	w = update(movie.ID, `{"daily":true}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_DAILY")
 }
 
-func TestUpdateEpisode(t *testing.T) {
-	db := setupTestDB(t)
+func TestUpdateContent_NamingOverrides(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Add series and episode
-	series := &library.Content{
-		Type:           library.ContentTypeSeries,
-		Title:          "Test Series",
-		Year:           2024,
-		Status:         library.StatusWanted,
-		QualityProfile: "hd",
-		RootPath:       "/tv",
-	}
-	require.NoError(t, srv.deps.Library.AddContent(series))
+	series := fixtures.NewSeries("Frieren", 2023).Insert(t, db)
+	movie := fixtures.NewMovie("Test", 2024).Insert(t, db)
 
-	ep := &library.Episode{
-		ContentID: series.ID,
-		Season:    1,
-		Episode:   1,
-		Title:     "Pilot",
-		Status:    library.StatusWanted,
+	update := func(id int64, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/content/%d", id), strings.NewReader(body))
+		req.SetPathValue("id", strconv.FormatInt(id, 10))
+		w := httptest.NewRecorder()
+		srv.updateContent(w, req)
+		return w
 	}
-	require.NoError(t, srv.deps.Library.AddEpisode(ep))
 
-	body := `{"status":"available"}`
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/episodes/1", strings.NewReader(body))
-	req.SetPathValue("id", "1")
-	w := httptest.NewRecorder()
+	w := update(series.ID, `{"season_folder":false,"naming_template":"{title}/{title} - {episode:03}.{ext}"}`)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	var resp contentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.SeasonFolder)
+	assert.False(t, *resp.SeasonFolder)
+	assert.Equal(t, "{title}/{title} - {episode:03}.{ext}", resp.NamingTemplate)
+
+	// Leaving the fields out keeps the overrides
+	w = update(series.ID, `{"quality_profile":"uhd"}`)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	got, err := srv.deps.Library.GetContent(series.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.SeasonFolder)
+	assert.Equal(t, "{title}/{title} - {episode:03}.{ext}", got.NamingTemplate)
 
-	srv.updateEpisode(w, req)
+	// null and "" inherit the library settings again
+	w = update(series.ID, `{"season_folder":null,"naming_template":""}`)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	got, err = srv.deps.Library.GetContent(series.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.SeasonFolder)
+	assert.Empty(t, got.NamingTemplate)
 
-	assert.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	w = update(series.ID, `{"naming_template":"{title} - {absolute}.{ext}"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_TEMPLATE")
+	assert.Contains(t, w.Body.String(), "{absolute}")
+	assert.Contains(t, w.Body.String(), "{title}, {season}, {episode}, {quality}, {ext}")
 
-	// Verify update
-	updated, err := srv.deps.Library.GetEpisode(1)
-	require.NoError(t, err)
-	assert.Equal(t, library.StatusAvailable, updated.Status)
+	w = update(movie.ID, `{"naming_template":"{title} ({year}){part}.{ext}"}`)
+	assert.Equal(t, http.StatusOK, w.Code, "movie placeholders are valid for movies")
+	w = update(movie.ID, `{"season_folder":true}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_SEASON_FOLDER")
+	w = update(series.ID, `{"season_folder":"yes"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestSearch_NoSearcher(t *testing.T) {
-	db := setupTestDB(t)
-	srv := New(db, Config{})
+func TestUpdateContent_TitleYearAndIDs(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	ctrl := gomock.NewController(t)
+	mockImporter := mocks.NewMockFileImporter(ctrl)
+	mockMetadata := mocks.NewMockMetadataRefresher(ctrl)
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+	updated := bus.Subscribe(events.EventContentUpdated, 10)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?query=test+movie", nil)
-	w := httptest.NewRecorder()
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Importer:  mockImporter,
+		Metadata:  mockMetadata,
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
 
-	mux.ServeHTTP(w, req)
+	matrixID, reloadedID := int64(603), int64(604)
+	c := &library.Content{Type: library.ContentTypeMovie, TMDBID: &matrixID, Title: "The Matrx", Year: 1998,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, deps.Library.AddContent(c))
+	other := &library.Content{Type: library.ContentTypeMovie, TMDBID: &reloadedID, Title: "The Matrix Reloaded", Year: 2003,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, deps.Library.AddContent(other))
+
+	update := func(id int64, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/content/%d", id), strings.NewReader(body))
+		req.SetPathValue("id", strconv.FormatInt(id, 10))
+		w := httptest.NewRecorder()
+		srv.updateContent(w, req)
+		return w
+	}
 
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
-}
+	mockImporter.EXPECT().PreviewRename(c.ID).Return([]importer.FileRename{
+		{FileID: 1, OldPath: "/movies/The Matrx (1998)/The Matrx (1998).mkv", NewPath: "/movies/The Matrix (1999)/The Matrix (1999).mkv", Status: importer.RenamePending},
+	}, nil)
 
-func TestSearch_MissingQuery(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	w := update(c.ID, `{"title":"The Matrix","year":1999}`)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	var resp contentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "The Matrix", resp.Title)
+	assert.Equal(t, 1999, resp.Year)
+	require.Len(t, resp.Warnings, 1)
+	assert.Contains(t, resp.Warnings[0], "/api/v1/library/rename")
 
-	db := setupTestDB(t)
-	srv := New(db, Config{})
+	select {
+	case e := <-updated:
+		evt, ok := e.(*events.ContentUpdated)
+		require.True(t, ok)
+		assert.Equal(t, []events.FieldChange{
+			{Field: "title", Old: "The Matrx", New: "The Matrix"},
+			{Field: "year", Old: "1998", New: "1999"},
+		}, evt.Changes)
+	case <-time.After(time.Second):
+		t.Fatal("ContentUpdated not published")
+	}
 
-	// Need a searcher for the endpoint to be available
-	mockSearcher := mocks.NewMockSearcher(ctrl)
-	srv.deps.Searcher = mockSearcher
+	// A new external ID refetches metadata
+	mockMetadata.EXPECT().Refresh(gomock.Any(), gomock.Any()).Return(true, nil)
+	w = update(c.ID, `{"tmdb_id":605}`)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	got, err := deps.Library.GetContent(c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(605), *got.TMDBID)
+
+	// Collisions with other content
+	w = update(c.ID, `{"tmdb_id":604}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = update(other.ID, `{"title":"the matrix","year":1999}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Validation
+	for _, body := range []string{`{"title":"  "}`, `{"year":-1}`, `{"tvdb_id":81189}`, `{"tmdb_id":0}`, `{"root_path":""}`} {
+		w = update(c.ID, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+func TestUpdateContent_Abandon(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
 
-	// GET without query param
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/search", nil)
-	w := httptest.NewRecorder()
+	store := library.NewStore(db)
+	c := &library.Content{Type: library.ContentTypeMovie, Title: "Obscure Movie", Year: 1971, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(c))
 
-	mux.ServeHTTP(w, req)
+	downloads := download.NewStore(db)
+	active := &download.Download{ContentID: c.ID, Client: download.ClientSABnzbd, ClientID: "nzo_active", Status: download.StatusDownloading, ReleaseName: "Obscure.Movie.1971.DVDRip", Indexer: "test"}
+	require.NoError(t, downloads.Add(active))
+	mockManager.EXPECT().Cancel(gomock.Any(), active.ID, true).Return(nil)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	history := importer.NewHistoryStore(db)
+	srv, err := NewWithDeps(ServerDeps{Library: store, Downloads: downloads, History: history, Manager: mockManager}, Config{})
+	require.NoError(t, err)
 
-	var errResp errorResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
-	assert.Equal(t, "MISSING_QUERY", errResp.Code)
-}
+	update := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/content/%d", c.ID), strings.NewReader(body))
+		req.SetPathValue("id", strconv.FormatInt(c.ID, 10))
+		w := httptest.NewRecorder()
+		srv.updateContent(w, req)
+		return w
+	}
 
-func TestGrab_NoManager(t *testing.T) {
-	db := setupTestDB(t)
-	srv := New(db, Config{})
+	w := update(`{"status":"abandoned","reason":"wrong TMDB entry"}`)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+	updated, err := store.GetContent(c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, library.StatusAbandoned, updated.Status)
 
-	body := `{"content_id":1,"download_url":"http://example.com/nzb","title":"Test","indexer":"TestIndexer"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
-	w := httptest.NewRecorder()
+	event := importer.EventAbandoned
+	entries, _, err := history.List(importer.HistoryFilter{ContentID: &c.ID, Event: &event})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.JSONEq(t, fmt.Sprintf(`{"reason":"wrong TMDB entry","previous_status":"wanted","canceled_downloads":[%d]}`, active.ID), entries[0].Data)
 
-	mux.ServeHTTP(w, req)
+	// Already abandoned: no second history entry or cancel
+	w = update(`{"status":"abandoned","reason":"still wrong"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	entries, _, err = history.List(importer.HistoryFilter{ContentID: &c.ID, Event: &event})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
 
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	w = update(`{"status":"gone"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_STATUS")
 }
 
-func TestListDownloads_Empty(t *testing.T) {
-	db := setupTestDB(t)
+func TestDeleteContent(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads", nil)
+	// Add content first
+	c := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(c))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/content/1", nil)
+	req.SetPathValue("id", "1")
 	w := httptest.NewRecorder()
 
-	srv.listDownloads(w, req)
+	srv.deleteContent(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusNoContent, w.Code)
 
-	var resp listDownloadsResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Empty(t, resp.Items)
+	// Verify deleted
+	_, err := srv.deps.Library.GetContent(1)
+	assert.ErrorIs(t, err, library.ErrNotFound, "expected ErrNotFound")
 }
 
-func TestGetDownload_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+func TestDestructiveScope(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads/999", nil)
-	req.SetPathValue("id", "999")
-	w := httptest.NewRecorder()
+	c := &library.Content{Type: library.ContentTypeMovie, Title: "Test", Year: 2024,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, srv.deps.Library.AddContent(c))
 
-	srv.getDownload(w, req)
+	do := func(method, target string, key *auth.Key) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if key != nil {
+			req = req.WithContext(auth.WithKey(req.Context(), *key))
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	automation := &auth.Key{Name: "automation", Scope: auth.ScopeWrite}
+
+	for _, target := range []string{
+		fmt.Sprintf("/api/v1/content/%d?delete_files=true", c.ID),
+		"/api/v1/downloads/1",
+	} {
+		w := do(http.MethodDelete, target, automation)
+		assert.Equal(t, http.StatusForbidden, w.Code, target)
+		assert.Contains(t, w.Body.String(), "INSUFFICIENT_SCOPE")
+		assert.Contains(t, w.Body.String(), "requires the admin scope")
+	}
+	for _, target := range []string{"/api/v1/library/rename", "/api/v1/downloads/orphans/clean"} {
+		assert.Equal(t, http.StatusForbidden, do(http.MethodPost, target, automation).Code, target)
+	}
+	_, err := srv.deps.Library.GetContent(c.ID)
+	require.NoError(t, err, "content is kept")
 
-	assert.Equal(t, http.StatusNotFound, w.Code)
+	// A write key may still remove the record alone, and an admin key anything
+	assert.Equal(t, http.StatusServiceUnavailable, do(http.MethodDelete, "/api/v1/downloads/1", &auth.Key{Name: "root", Scope: auth.ScopeAdmin}).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, do(http.MethodDelete, "/api/v1/downloads/1", nil).Code, "unrestricted without keys")
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, fmt.Sprintf("/api/v1/content/%d", c.ID), automation).Code)
 }
 
-func TestDeleteDownload_NoManager(t *testing.T) {
-	db := setupTestDB(t)
+func TestDeleteContent_AddExclusion(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+	tmdbID := int64(603)
+	c := &library.Content{Type: library.ContentTypeMovie, TMDBID: &tmdbID, Title: "The Matrix", Year: 1999,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, srv.deps.Library.AddContent(c))
+	noID := &library.Content{Type: library.ContentTypeMovie, Title: "Home Video", Year: 2001,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, srv.deps.Library.AddContent(noID))
+
+	del := func(id int64, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/content/%d?%s", id, query), nil)
+		req.SetPathValue("id", strconv.FormatInt(id, 10))
+		w := httptest.NewRecorder()
+		srv.deleteContent(w, req)
+		return w
+	}
 
-	req := httptest.NewRequest(http.MethodDelete, "/api/v1/downloads/1", nil)
-	w := httptest.NewRecorder()
+	// Nothing to exclude: the content is kept
+	w := del(noID.ID, "add_exclusion=true")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	_, err := srv.deps.Library.GetContent(noID.ID)
+	require.NoError(t, err)
 
-	mux.ServeHTTP(w, req)
+	w = del(c.ID, "add_exclusion=true&exclusion_reason=seen+it")
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	_, err = srv.deps.Library.GetContent(c.ID)
+	require.ErrorIs(t, err, library.ErrNotFound)
 
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	e, err := srv.deps.Library.FindExclusion(library.ExclusionTMDB, 603)
+	require.NoError(t, err)
+	assert.Equal(t, "The Matrix", e.Title)
+	assert.Equal(t, 1999, e.Year)
+	assert.Equal(t, "seen it", e.Reason)
+
+	// Adding it again is refused
+	body := `{"type": "movie", "tmdb_id": 603, "title": "The Matrix", "year": 1999, "quality_profile": "hd"}`
+	w = httptest.NewRecorder()
+	srv.addContent(w, httptest.NewRequest(http.MethodPost, "/api/v1/content", strings.NewReader(body)))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var errResp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "EXCLUDED", errResp.Code)
+	assert.Contains(t, errResp.Error, "seen it")
 }
 
-func TestListHistory_Empty(t *testing.T) {
-	db := setupTestDB(t)
+func TestExclusions(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/history", nil)
-	w := httptest.NewRecorder()
-
-	srv.listHistory(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return w
+	}
 
-	var resp listHistoryResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Empty(t, resp.Items)
+	w := do(http.MethodPost, "/api/v1/exclusions", `{"source": "tvdb", "external_id": 81189, "title": "Breaking Bad", "reason": "finished"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created exclusionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "tvdb", created.Source)
+
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/api/v1/exclusions", `{"source": "tvdb", "external_id": 81189}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/api/v1/exclusions", `{"source": "imdb", "external_id": 1}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/api/v1/exclusions", `{"source": "tmdb"}`).Code)
+
+	w = do(http.MethodGet, "/api/v1/exclusions", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list listExclusionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, "finished", list.Items[0].Reason)
+
+	w = do(http.MethodDelete, fmt.Sprintf("/api/v1/exclusions/%d", created.ID), "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = do(http.MethodDelete, fmt.Sprintf("/api/v1/exclusions/%d", created.ID), "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
-func TestListFiles_Empty(t *testing.T) {
-	db := setupTestDB(t)
+func TestDeleteContent_NotFound(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/files", nil)
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/content/999", nil)
+	req.SetPathValue("id", "999")
 	w := httptest.NewRecorder()
 
-	srv.listFiles(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
+	srv.deleteContent(w, req)
 
-	var resp listFilesResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Empty(t, resp.Items)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
-func TestDeleteFile(t *testing.T) {
-	db := setupTestDB(t)
+func TestDeleteContent_ActiveDownloadsConflict(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Add content and file
 	c := &library.Content{
 		Type:           library.ContentTypeMovie,
 		Title:          "Test",
 		Year:           2024,
-		Status:         library.StatusAvailable,
+		Status:         library.StatusWanted,
 		QualityProfile: "hd",
 		RootPath:       "/movies",
 	}
 	require.NoError(t, srv.deps.Library.AddContent(c))
+	require.NoError(t, srv.deps.Downloads.Add(&download.Download{
+		ContentID:   c.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "nzo_abc",
+		Status:      download.StatusDownloading,
+		ReleaseName: "Test.2024.1080p",
+		Indexer:     "test",
+	}))
 
-	f := &library.File{
-		ContentID: c.ID,
-		Path:      "/movies/test.mkv",
-		SizeBytes: 1000,
-		Quality:   "1080p",
-		Source:    "test",
-	}
-	require.NoError(t, srv.deps.Library.AddFile(f))
-
-	req := httptest.NewRequest(http.MethodDelete, "/api/v1/files/1", nil)
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/content/1", nil)
 	req.SetPathValue("id", "1")
 	w := httptest.NewRecorder()
 
-	srv.deleteFile(w, req)
+	srv.deleteContent(w, req)
 
-	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "ACTIVE_DOWNLOADS", resp.Code)
+
+	// Content must still exist
+	_, err := srv.deps.Library.GetContent(c.ID)
+	assert.NoError(t, err)
 }
 
-func TestGetStatus(t *testing.T) {
-	db := setupTestDB(t)
-	srv := New(db, Config{})
+func TestDeleteContent_CancelDownloadsAndDeleteFiles(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
-	w := httptest.NewRecorder()
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+	eventCh := bus.Subscribe(events.EventContentDeleted, 10)
 
-	srv.getStatus(w, req)
+	root := t.TempDir()
+	seasonDir := filepath.Join(root, "Test Series", "Season 01")
+	require.NoError(t, os.MkdirAll(seasonDir, 0755))
+	filePath := filepath.Join(seasonDir, "Test Series - S01E01.mkv")
+	require.NoError(t, os.WriteFile(filePath, []byte("video"), 0644))
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	store := library.NewStore(db)
+	c := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Test Series",
+		Year:           2024,
+		Status:         library.StatusAvailable,
+		QualityProfile: "hd",
+		RootPath:       root,
+	}
+	require.NoError(t, store.AddContent(c))
+	require.NoError(t, store.AddFile(&library.File{ContentID: c.ID, Path: filePath, SizeBytes: 5, Quality: "1080p"}))
 
-	var resp statusResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Equal(t, "ok", resp.Status)
-}
+	downloads := download.NewStore(db)
+	active := &download.Download{
+		ContentID:   c.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "nzo_active",
+		Status:      download.StatusQueued,
+		ReleaseName: "Test.Series.S01E02.1080p",
+		Indexer:     "test",
+	}
+	require.NoError(t, downloads.Add(active))
+	done := &download.Download{
+		ContentID:   c.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "nzo_done",
+		Status:      download.StatusImported,
+		ReleaseName: "Test.Series.S01E01.1080p",
+		Indexer:     "test",
+	}
+	require.NoError(t, downloads.Add(done))
 
-func TestListProfiles(t *testing.T) {
-	db := setupTestDB(t)
-	srv := New(db, Config{
-		QualityProfiles: map[string][]string{
-			"hd":  {"1080p bluray", "1080p webdl"},
-			"uhd": {"2160p bluray"},
-		},
-	})
+	// Only the in-flight download is canceled
+	mockManager.EXPECT().Cancel(gomock.Any(), active.ID, true).Return(nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles", nil)
+	srv, err := NewWithDeps(ServerDeps{
+		Library:   store,
+		Downloads: downloads,
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}, Config{})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/content/1?delete_files=true&cancel_downloads=true", nil)
+	req.SetPathValue("id", "1")
 	w := httptest.NewRecorder()
 
-	srv.listProfiles(w, req)
+	srv.deleteContent(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusNoContent, w.Code, "response body: %s", w.Body.String())
+	assert.NoFileExists(t, filePath)
+	assert.NoDirExists(t, filepath.Join(root, "Test Series"), "empty content folder should be removed")
+	assert.DirExists(t, root, "library root must be kept")
 
-	var resp listProfilesResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Len(t, resp.Profiles, 2)
+	select {
+	case evt := <-eventCh:
+		deleted, ok := evt.(*events.ContentDeleted)
+		require.True(t, ok, "expected ContentDeleted event")
+		assert.Equal(t, c.ID, deleted.ContentID)
+		assert.Equal(t, "Test Series", deleted.Title)
+		assert.Equal(t, []string{filePath}, deleted.DeletedFiles)
+		assert.Equal(t, []int64{active.ID}, deleted.CanceledDownloads)
+	default:
+		t.Fatal("expected event to be published")
+	}
 }
 
-func TestSearch_WithMockSearcher(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	db := setupTestDB(t)
+func TestListEpisodes(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Create mock searcher
-	mockSearcher := mocks.NewMockSearcher(ctrl)
-	srv.deps.Searcher = mockSearcher
-
-	// Set up expectation
-	mockSearcher.EXPECT().
-		Search(gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(&search.Result{
-			Releases: []*search.Release{
-				{Title: "Test Movie", Indexer: "TestIndexer"},
-			},
-		}, nil)
+	// Add series
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Test Series",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(series))
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+	// Add episodes
+	for i := 1; i <= 3; i++ {
+		ep := &library.Episode{
+			ContentID: series.ID,
+			Season:    1,
+			Episode:   i,
+			Title:     fmt.Sprintf("Episode %d", i),
+			Status:    library.StatusWanted,
+		}
+		require.NoError(t, srv.deps.Library.AddEpisode(ep))
+	}
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?query=test+movie", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/content/1/episodes", nil)
+	req.SetPathValue("id", "1")
 	w := httptest.NewRecorder()
 
-	mux.ServeHTTP(w, req)
+	srv.listEpisodes(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var resp searchResponse
+	var resp listEpisodesResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Len(t, resp.Releases, 1)
-	assert.Equal(t, "Test Movie", resp.Releases[0].Title)
+	assert.Len(t, resp.Items, 3)
 }
 
-func TestListEvents_Success(t *testing.T) {
-	db := setupTestDB(t)
+func TestListSeasons(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Create event log and attach to server
-	eventLog := events.NewEventLog(db)
-	srv.deps.EventLog = eventLog
-
-	// Insert a test event using EventLog.Append
-	testEvent := events.NewBaseEvent("test.event", "content", 1)
-	_, err := eventLog.Append(testEvent)
-	require.NoError(t, err)
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Test Series", Year: 2024, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, srv.deps.Library.AddContent(series))
+	aired := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	ep := &library.Episode{ContentID: series.ID, Season: 1, Episode: 1, Status: library.StatusAvailable, AirDate: &aired}
+	require.NoError(t, srv.deps.Library.AddEpisode(ep))
+	require.NoError(t, srv.deps.Library.AddFile(&library.File{ContentID: series.ID, EpisodeID: &ep.ID, Path: "/tv/s01e01.mkv", SizeBytes: 4096}))
+	require.NoError(t, srv.deps.Library.AddEpisode(&library.Episode{ContentID: series.ID, Season: 2, Episode: 1, Status: library.StatusWanted}))
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/content/1/seasons", nil)
+	req.SetPathValue("id", "1")
 	w := httptest.NewRecorder()
+	srv.listSeasons(w, req)
 
-	srv.listEvents(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var resp listEventsResponse
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp listSeasonsResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Len(t, resp.Items, 1)
-	assert.Equal(t, 1, resp.Total)
-	assert.Equal(t, 50, resp.Limit) // default limit
-	assert.Equal(t, 0, resp.Offset) // default offset
-	assert.Equal(t, "test.event", resp.Items[0].EventType)
-	assert.Equal(t, "content", resp.Items[0].EntityType)
-	assert.Equal(t, int64(1), resp.Items[0].EntityID)
+	require.Equal(t, 2, resp.Total)
+	assert.Equal(t, 1, resp.Items[0].Season)
+	assert.Equal(t, 1, resp.Items[0].EpisodeCount)
+	assert.Equal(t, 1, resp.Items[0].AvailableCount)
+	assert.Equal(t, int64(4096), resp.Items[0].SizeBytes)
+	require.NotNil(t, resp.Items[0].LastAirDate)
+	assert.True(t, aired.Equal(*resp.Items[0].LastAirDate))
+	assert.True(t, resp.Items[0].Complete)
+	assert.Equal(t, 2, resp.Items[1].Season)
+	assert.False(t, resp.Items[1].Complete)
+	assert.Nil(t, resp.Items[1].LastAirDate)
 }
 
-func TestListEvents_Empty(t *testing.T) {
-	db := setupTestDB(t)
+func TestListSeasons_Movie(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
+	require.NoError(t, srv.deps.Library.AddContent(&library.Content{Type: library.ContentTypeMovie, Title: "Movie", Year: 2024, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}))
 
-	// Create event log with no events
-	eventLog := events.NewEventLog(db)
-	srv.deps.EventLog = eventLog
-
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/content/1/seasons", nil)
+	req.SetPathValue("id", "1")
 	w := httptest.NewRecorder()
+	srv.listSeasons(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_SERIES")
 
-	srv.listEvents(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var resp listEventsResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Empty(t, resp.Items)
-	assert.Zero(t, resp.Total)
-	assert.Equal(t, 50, resp.Limit) // default limit
-	assert.Equal(t, 0, resp.Offset) // default offset
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/content/99/seasons", nil)
+	req.SetPathValue("id", "99")
+	w = httptest.NewRecorder()
+	srv.listSeasons(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
-func TestListDownloadEvents_Success(t *testing.T) {
-	db := setupTestDB(t)
+func TestUpdateEpisode(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Create event log and attach to server
-	eventLog := events.NewEventLog(db)
-	srv.deps.EventLog = eventLog
-
-	// Add content first (required for download foreign key)
-	c := &library.Content{
-		Type:           library.ContentTypeMovie,
-		Title:          "Test Movie",
+	// Add series and episode
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Test Series",
 		Year:           2024,
 		Status:         library.StatusWanted,
 		QualityProfile: "hd",
-		RootPath:       "/movies",
+		RootPath:       "/tv",
 	}
-	require.NoError(t, srv.deps.Library.AddContent(c))
+	require.NoError(t, srv.deps.Library.AddContent(series))
 
-	// Add a download
-	d := &download.Download{
-		ContentID:   c.ID,
-		Client:      download.ClientSABnzbd,
-		ClientID:    "test-client-id",
-		Status:      download.StatusDownloading,
-		ReleaseName: "Test.Movie.2024.1080p",
-		Indexer:     "TestIndexer",
+	ep := &library.Episode{
+		ContentID: series.ID,
+		Season:    1,
+		Episode:   1,
+		Title:     "Pilot",
+		Status:    library.StatusWanted,
 	}
-	require.NoError(t, srv.deps.Downloads.Add(d))
-
-	// Insert event for this download
-	testEvent := events.NewBaseEvent("download.started", "download", d.ID)
-	_, err := eventLog.Append(testEvent)
-	require.NoError(t, err)
+	require.NoError(t, srv.deps.Library.AddEpisode(ep))
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads/1/events", nil)
+	body := `{"status":"available"}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/episodes/1", strings.NewReader(body))
 	req.SetPathValue("id", "1")
 	w := httptest.NewRecorder()
 
-	srv.listDownloadEvents(w, req)
+	srv.updateEpisode(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
 
-	var resp listEventsResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Len(t, resp.Items, 1)
-	assert.Equal(t, 1, resp.Total)
-	assert.Equal(t, "download.started", resp.Items[0].EventType)
-	assert.Equal(t, "download", resp.Items[0].EntityType)
+	// Verify update
+	updated, err := srv.deps.Library.GetEpisode(1)
+	require.NoError(t, err)
+	assert.Equal(t, library.StatusAvailable, updated.Status)
 }
 
-func TestListDownloadEvents_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+func TestSearch_NoSearcher(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Create event log
-	eventLog := events.NewEventLog(db)
-	srv.deps.EventLog = eventLog
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads/999/events", nil)
-	req.SetPathValue("id", "999")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?query=test+movie", nil)
 	w := httptest.NewRecorder()
 
-	srv.listDownloadEvents(w, req)
+	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
 }
 
-func TestGetDashboard_Success(t *testing.T) {
-	db := setupTestDB(t)
-	srv := New(db, Config{})
-
-	// Add movies and series
-	movie1 := &library.Content{
-		Type:           library.ContentTypeMovie,
-		Title:          "Test Movie 1",
-		Year:           2024,
-		Status:         library.StatusAvailable,
-		QualityProfile: "hd",
-		RootPath:       "/movies",
-	}
-	require.NoError(t, srv.deps.Library.AddContent(movie1))
+func TestSearch_MissingQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	movie2 := &library.Content{
-		Type:           library.ContentTypeMovie,
-		Title:          "Test Movie 2",
-		Year:           2024,
-		Status:         library.StatusWanted,
-		QualityProfile: "hd",
-		RootPath:       "/movies",
-	}
-	require.NoError(t, srv.deps.Library.AddContent(movie2))
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
 
-	series1 := &library.Content{
-		Type:           library.ContentTypeSeries,
-		Title:          "Test Series",
-		Year:           2024,
-		Status:         library.StatusWanted,
-		QualityProfile: "hd",
-		RootPath:       "/tv",
-	}
-	require.NoError(t, srv.deps.Library.AddContent(series1))
+	// Need a searcher for the endpoint to be available
+	mockSearcher := mocks.NewMockSearcher(ctrl)
+	srv.deps.Searcher = mockSearcher
 
-	// Add downloads in various states
-	dlQueued := &download.Download{
-		ContentID:   movie1.ID,
-		Client:      download.ClientSABnzbd,
-		ClientID:    "queued-1",
-		Status:      download.StatusQueued,
-		ReleaseName: "Test.Movie.1.2024.1080p",
-		Indexer:     "TestIndexer",
-	}
-	require.NoError(t, srv.deps.Downloads.Add(dlQueued))
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
 
-	dlDownloading := &download.Download{
-		ContentID:   movie2.ID,
-		Client:      download.ClientSABnzbd,
-		ClientID:    "downloading-1",
-		Status:      download.StatusDownloading,
-		ReleaseName: "Test.Movie.2.2024.1080p",
-		Indexer:     "TestIndexer",
-	}
-	require.NoError(t, srv.deps.Downloads.Add(dlDownloading))
+	// GET without query param
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search", nil)
+	w := httptest.NewRecorder()
 
-	dlFailed := &download.Download{
-		ContentID:   series1.ID,
-		Client:      download.ClientSABnzbd,
-		ClientID:    "failed-1",
-		Status:      download.StatusFailed,
-		ReleaseName: "Test.Series.S01E01.1080p",
-		Indexer:     "TestIndexer",
-	}
-	require.NoError(t, srv.deps.Downloads.Add(dlFailed))
+	mux.ServeHTTP(w, req)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
-	w := httptest.NewRecorder()
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	srv.getDashboard(w, req)
+	var errResp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "MISSING_QUERY", errResp.Code)
+}
 
-	assert.Equal(t, http.StatusOK, w.Code)
+func TestGrab_NoManager(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
 
-	var resp DashboardResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
 
-	// Verify version and connections
-	assert.Equal(t, "0.1.0", resp.Version)
-	assert.True(t, resp.Connections.Server)
-	assert.False(t, resp.Connections.Plex)    // No Plex configured
-	assert.False(t, resp.Connections.SABnzbd) // No Manager configured
+	body := `{"content_id":1,"download_url":"http://example.com/nzb","title":"Test","indexer":"TestIndexer"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+	w := httptest.NewRecorder()
 
-	// Verify download counts
-	assert.Equal(t, 1, resp.Downloads.Queued)
-	assert.Equal(t, 1, resp.Downloads.Downloading)
-	assert.Equal(t, 1, resp.Downloads.Failed)
-	assert.Equal(t, 0, resp.Downloads.Completed)
-	assert.Equal(t, 0, resp.Downloads.Importing)
-	assert.Equal(t, 0, resp.Downloads.Imported)
-	assert.Equal(t, 0, resp.Downloads.Cleaned)
+	mux.ServeHTTP(w, req)
 
-	// Verify library counts
-	assert.Equal(t, 2, resp.Library.Movies)
-	assert.Equal(t, 1, resp.Library.Series)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
 }
 
-func TestVerify_NoProblems(t *testing.T) {
-	db := setupTestDB(t)
+func TestListDownloads_Empty(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// No downloads, no problems expected
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/verify", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads", nil)
 	w := httptest.NewRecorder()
 
-	srv.verify(w, req)
+	srv.listDownloads(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var resp VerifyResponse
+	var resp listDownloadsResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-
-	assert.Equal(t, 0, resp.Checked)
-	assert.Equal(t, 0, resp.Passed)
-	assert.Empty(t, resp.Problems)
-	// Connections should be false since no Plex or Manager configured
-	assert.False(t, resp.Connections.Plex)
-	assert.False(t, resp.Connections.SABnzbd)
+	assert.Empty(t, resp.Items)
 }
 
-func TestVerify_WithDownloadID(t *testing.T) {
-	db := setupTestDB(t)
+func TestListDownloads_Sort(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Add content for downloads
-	content := &library.Content{
-		Type:           library.ContentTypeMovie,
-		Title:          "Test Movie",
-		Year:           2024,
-		Status:         library.StatusWanted,
-		QualityProfile: "hd",
-		RootPath:       "/movies",
-	}
-	require.NoError(t, srv.deps.Library.AddContent(content))
+	movie := fixtures.NewMovie("Sorted", 2024).Insert(t, db)
+	queued := fixtures.NewDownload().ForContent(movie.ID).WithReleaseName("a").Insert(t, db)
+	failed := fixtures.NewDownload().ForContent(movie.ID).WithReleaseName("b").WithStatus(download.StatusFailed).Insert(t, db)
 
-	// Add multiple downloads (using Active states so verify finds them)
-	dl1 := &download.Download{
-		ContentID:   content.ID,
-		Client:      download.ClientSABnzbd,
-		ClientID:    "client-1",
-		Status:      download.StatusDownloading,
-		ReleaseName: "Test.Movie.2024.1080p.Release1",
-		Indexer:     "TestIndexer",
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads"+query, nil)
+		w := httptest.NewRecorder()
+		srv.listDownloads(w, req)
+		return w
 	}
-	require.NoError(t, srv.deps.Downloads.Add(dl1))
 
-	dl2 := &download.Download{
-		ContentID:   content.ID,
-		Client:      download.ClientSABnzbd,
-		ClientID:    "client-2",
-		Status:      download.StatusCompleted,
-		ReleaseName: "Test.Movie.2024.1080p.Release2",
-		Indexer:     "TestIndexer",
-	}
-	require.NoError(t, srv.deps.Downloads.Add(dl2))
+	w := list("")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp listDownloadsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, failed.ID, resp.Items[0].ID, "recently added first")
 
-	// Verify with specific ID filter (should only check dl2)
-	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/verify?id=%d", dl2.ID), nil)
+	w = list("?sort=status&order=desc")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, queued.ID, resp.Items[0].ID)
+
+	assert.Equal(t, http.StatusBadRequest, list("?sort=release_name").Code)
+	assert.Equal(t, http.StatusBadRequest, list("?order=random").Code)
+}
+
+func TestGetDownload_NotFound(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads/999", nil)
+	req.SetPathValue("id", "999")
 	w := httptest.NewRecorder()
 
-	srv.verify(w, req)
+	srv.getDownload(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
 
-	var resp VerifyResponse
+func TestListDownloadSamples(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	movie := fixtures.NewMovie("Heat", 1995).Insert(t, db)
+	dl := fixtures.NewDownload().ForContent(movie.ID).WithStatus(download.StatusDownloading).Insert(t, db)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads/"+id+"/samples", nil)
+		req.SetPathValue("id", id)
+		w := httptest.NewRecorder()
+		srv.listDownloadSamples(w, req)
+		return w
+	}
+	id := strconv.FormatInt(dl.ID, 10)
+	assert.Equal(t, http.StatusServiceUnavailable, get(id).Code, "samples disabled")
+
+	samples := download.NewSamples(0)
+	srv.deps.Samples = samples
+	start := time.Now().Add(-time.Minute)
+	for i := range 5 {
+		samples.Record(dl.ID, download.Sample{At: start.Add(time.Duration(i) * 10 * time.Second), Progress: float64(50 + i), Speed: 1 << 20, Size: 1 << 30})
+	}
+
+	w := get(id)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp downloadSamplesResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Samples, 5)
+	assert.InDelta(t, 54.0, resp.Samples[4].Progress, 0.001)
+	// 1% per 10s with 46% left
+	require.NotNil(t, resp.SmoothedETA)
+	assert.Equal(t, "7m40s", *resp.SmoothedETA)
+
+	// The download itself carries the smoothed ETA too
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads/"+id, nil)
+	req.SetPathValue("id", id)
+	w = httptest.NewRecorder()
+	srv.getDownload(w, req)
+	var got downloadResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	require.NotNil(t, got.SmoothedETA)
+	assert.Equal(t, "7m40s", *got.SmoothedETA)
 
-	// Should only have checked 1 download (the one we filtered for)
-	assert.Equal(t, 1, resp.Checked)
-	// Without manager, verify doesn't find problems for completed status
-	assert.Equal(t, 1, resp.Passed)
-	assert.Empty(t, resp.Problems)
+	assert.Equal(t, http.StatusNotFound, get("999").Code)
 }
 
-func TestGetPlexStatus_Connected(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestImportNext(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	store := srv.deps.Downloads
+
+	var ids []int64
+	for _, title := range []string{"First", "Second", "Third"} {
+		movie := fixtures.NewMovie(title, 2024).Insert(t, db)
+		dl := fixtures.NewDownload().ForContent(movie.ID).WithStatus(download.StatusCompleted).Insert(t, db)
+		require.NoError(t, store.QueueImport(dl.ID, ""))
+		ids = append(ids, dl.ID)
+	}
+	movie := fixtures.NewMovie("Downloading", 2024).Insert(t, db)
+	active := fixtures.NewDownload().ForContent(movie.ID).WithStatus(download.StatusDownloading).Insert(t, db)
+
+	importNext := func(id int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/downloads/%d/import-next", id), nil)
+		req.SetPathValue("id", strconv.FormatInt(id, 10))
+		w := httptest.NewRecorder()
+		srv.importNext(w, req)
+		return w
+	}
 
-	db := setupTestDB(t)
-	mockPlex := mocks.NewMockPlexClient(ctrl)
+	w := importNext(ids[2])
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var got downloadResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, 1, got.ImportPosition)
 
-	// Setup Plex mock expectations
-	mockPlex.EXPECT().
-		GetIdentity(gomock.Any()).
-		Return(&importer.Identity{
-			Name:    "Test Plex Server",
-			Version: "1.32.0",
-		}, nil)
+	assert.Equal(t, http.StatusConflict, importNext(active.ID).Code, "not waiting for import")
+	assert.Equal(t, http.StatusNotFound, importNext(999).Code)
 
-	mockPlex.EXPECT().
-		GetSections(gomock.Any()).
-		Return([]importer.Section{
-			{
-				Key:       "1",
-				Title:     "Movies",
-				Type:      "movie",
-				Locations: []importer.Location{{Path: "/media/movies"}},
-				ScannedAt: 1700000000,
-			},
-			{
-				Key:       "2",
-				Title:     "TV Shows",
-				Type:      "show",
-				Locations: []importer.Location{{Path: "/media/tv"}},
-				ScannedAt: 1700000001,
-			},
-		}, nil)
+	// Downloads list the new order
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads", nil)
+	w = httptest.NewRecorder()
+	srv.listDownloads(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list listDownloadsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	positions := map[int64]int{}
+	for _, d := range list.Items {
+		positions[d.ID] = d.ImportPosition
+	}
+	assert.Equal(t, map[int64]int{ids[0]: 2, ids[1]: 3, ids[2]: 1, active.ID: 0}, positions)
 
-	// GetLibraryCount is called for each section
-	mockPlex.EXPECT().
-		GetLibraryCount(gomock.Any(), "1").
-		Return(150, nil)
-	mockPlex.EXPECT().
-		GetLibraryCount(gomock.Any(), "2").
-		Return(50, nil)
+	// The dashboard shows the queue depth
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/dashboard?skip_plex=true", nil)
+	w = httptest.NewRecorder()
+	srv.getDashboard(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var dashboard DashboardResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dashboard))
+	assert.Equal(t, 3, dashboard.Downloads.ImportQueued)
+}
+
+func TestReimportDownload(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	ctrl := gomock.NewController(t)
+	mockImporter := mocks.NewMockFileImporter(ctrl)
+	downloadRoot := t.TempDir()
 
 	deps := ServerDeps{
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Plex:      mockPlex,
+		Importer:  mockImporter,
 	}
-	srv, err := NewWithDeps(deps, Config{})
+	srv, err := NewWithDeps(deps, Config{DownloadRoot: downloadRoot})
+	require.NoError(t, err)
+
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Test Show", Year: 2024,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, deps.Library.AddContent(series))
+	season := 1
+	dl := &download.Download{ContentID: series.ID, Season: &season, IsCompleteSeason: true,
+		Client: download.ClientSABnzbd, ClientID: "sab-1", Status: download.StatusPartiallyImported,
+		ReleaseName: "Test.Show.S01.1080p"}
+	require.NoError(t, deps.Downloads.Add(dl))
+	sourcePath := filepath.Join(downloadRoot, dl.ReleaseName)
+	require.NoError(t, os.MkdirAll(sourcePath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourcePath, "Test.Show.S01E01.mkv"), []byte("x"), 0644))
+	require.NoError(t, deps.Downloads.SetEpisodeResults(dl.ID, []*download.EpisodeResult{
+		{SourceFile: "Test.Show.S01E01.mkv", Season: 1, Episode: 1, Success: true, FilePath: "/tv/Test Show/S01E01.mkv"},
+		{SourceFile: "Test.Show.S01E02.mkv", Season: 1, Episode: 2, Error: "copy file: disk full"},
+	}))
+
+	call := func(method, action string, id int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, fmt.Sprintf("/api/v1/downloads/%d%s", id, action), nil)
+		req.SetPathValue("id", strconv.FormatInt(id, 10))
+		w := httptest.NewRecorder()
+		if action == "" {
+			srv.getDownload(w, req)
+		} else {
+			srv.reimportDownload(w, req)
+		}
+		return w
+	}
+
+	// The download shows each episode's outcome
+	w := call(http.MethodGet, "", dl.ID)
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var got downloadResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "partially_imported", got.Status)
+	require.Len(t, got.EpisodeResults, 2)
+	assert.True(t, got.EpisodeResults[0].Success)
+	assert.Equal(t, "copy file: disk full", got.EpisodeResults[1].Error)
+
+	mockImporter.EXPECT().ImportSeasonPack(gomock.Any(), dl.ID, sourcePath).Return(&importer.SeasonPackResult{
+		TotalSize: 1000,
+		Episodes: []importer.EpisodeResult{
+			{EpisodeID: 1, Season: 1, Episode: 1, Success: true, SourceFile: "Test.Show.S01E01.mkv", AlreadyImported: true},
+			{EpisodeID: 2, Season: 1, Episode: 2, Success: true, SourceFile: "Test.Show.S01E02.mkv", SizeBytes: 1000},
+		},
+	}, nil)
+
+	w = call(http.MethodPost, "/reimport", dl.ID)
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp importResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "imported", resp.Status)
+	require.Len(t, resp.EpisodeResults, 2)
+	assert.True(t, resp.EpisodeResults[0].AlreadyImported)
+
+	updated, err := deps.Downloads.Get(dl.ID)
 	require.NoError(t, err)
+	assert.Equal(t, download.StatusImported, updated.Status)
+
+	// Only partially imported downloads can be reimported
+	w = call(http.MethodPost, "/reimport", dl.ID)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_STATE")
+
+	w = call(http.MethodPost, "/reimport", 999)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSetDownloadOptions(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv, mockManager, _, content := setupVerifyFix(t, db, Config{})
+	store := download.NewStore(db)
+
+	torrent := fixtures.NewDownload().ForContent(content.ID).WithReleaseName("Test.Movie.2024.2160p").WithStatus(download.StatusDownloading).Insert(t, db)
+	usenet := fixtures.NewDownload().ForContent(content.ID).WithReleaseName("Test.Movie.2024.1080p").WithStatus(download.StatusDownloading).Insert(t, db)
+	done := fixtures.NewDownload().ForContent(content.ID).WithReleaseName("Test.Movie.2024.720p").WithStatus(download.StatusImported).Insert(t, db)
+
+	mockManager.EXPECT().SetTorrentOptions(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, d *download.Download, opts download.TorrentOptions) error {
+			if d.ID != torrent.ID {
+				return fmt.Errorf("sabnzbd has no torrent options: %w", download.ErrUnsupported)
+			}
+			return store.SetTorrentOptions(d.ID, opts)
+		}).AnyTimes()
+	require.NoError(t, store.SetTorrentOptions(torrent.ID, download.TorrentOptions{FirstLastPiecePrio: true}))
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
+	post := func(id int64, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/downloads/%d/options", id), strings.NewReader(body)))
+		return w
+	}
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/status", nil)
+	w := post(torrent.ID, `{"sequential": true}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp downloadResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.TorrentOptions)
+	assert.True(t, resp.TorrentOptions.Sequential)
+	assert.True(t, resp.TorrentOptions.FirstLastPiecePrio, "omitted option kept")
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/downloads", nil))
+	var list listDownloadsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	for _, item := range list.Items {
+		if item.ID == torrent.ID {
+			assert.NotNil(t, item.TorrentOptions)
+		} else {
+			assert.Nil(t, item.TorrentOptions, "no options for clients without them")
+		}
+	}
+
+	w = post(usenet.ID, `{"sequential": true}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "UNSUPPORTED")
+
+	w = post(done.ID, `{"sequential": true}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_DOWNLOADING")
+
+	assert.Equal(t, http.StatusNotFound, post(999, `{}`).Code)
+}
+
+func TestDeleteDownload_NoManager(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/downloads/1", nil)
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
 
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestDeleteDownload_RemovesJunk(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	downloadRoot := t.TempDir()
+	srv, mockManager, bus, content := setupVerifyFix(t, db, Config{DownloadRoot: downloadRoot, Junk: importer.DefaultJunkRules()})
+	junk := bus.Subscribe(events.EventJunkRemoved, 10)
+
+	releaseDir := filepath.Join(downloadRoot, "Test.Movie.2024.1080p")
+	require.NoError(t, os.MkdirAll(releaseDir, 0755))
+	video := filepath.Join(releaseDir, "test.movie.mkv")
+	require.NoError(t, os.WriteFile(video, []byte("video"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(releaseDir, "test.movie.nfo"), []byte("info"), 0644))
+
+	dl := &download.Download{ContentID: content.ID, Client: download.ClientSABnzbd, ClientID: "nzo_1",
+		Status: download.StatusDownloading, ReleaseName: "Test.Movie.2024.1080p"}
+	require.NoError(t, srv.deps.Downloads.Add(dl))
+
+	// The client deletes the files it downloaded but not the release folder's leftovers
+	mockManager.EXPECT().Cancel(gomock.Any(), dl.ID, true).DoAndReturn(func(context.Context, int64, bool) error {
+		require.NoError(t, os.Remove(video))
+		return srv.deps.Downloads.Delete(dl.ID)
+	})
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/downloads/%d?delete_files=true", dl.ID), nil))
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	assert.NoDirExists(t, releaseDir)
+	select {
+	case e := <-junk:
+		removed := e.(*events.JunkRemoved)
+		assert.Equal(t, dl.ID, removed.DownloadID)
+		assert.Equal(t, "cancel", removed.Trigger)
+		assert.Equal(t, []string{filepath.Join(releaseDir, "test.movie.nfo"), releaseDir}, removed.Removed)
+	case <-time.After(time.Second):
+		t.Fatal("expected JunkRemoved event")
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	downloadRoot := t.TempDir()
+	srv := New(db, Config{DownloadRoot: downloadRoot, OrphanMinAge: 24 * time.Hour})
+	content := fixtures.NewMovie("Active Movie", 2024).Insert(t, db)
+	fixtures.NewDownload().ForContent(content.ID).WithStatus(download.StatusDownloading).
+		WithReleaseName("Active.Movie.2024.1080p").Insert(t, db)
+
+	old := time.Now().Add(-48 * time.Hour)
+	mkdir := func(name string, mtime time.Time) string {
+		dir := filepath.Join(downloadRoot, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		file := filepath.Join(dir, "leftover.rar")
+		require.NoError(t, os.WriteFile(file, []byte("1234"), 0644))
+		require.NoError(t, os.Chtimes(file, mtime, mtime))
+		require.NoError(t, os.Chtimes(dir, mtime, mtime))
+		return dir
+	}
+	stale := mkdir("Stale.Movie.2019.1080p", old)
+	other := mkdir("Other.Movie.2018.1080p", old)
+	mkdir("Active.Movie.2024.1080p", old)
+	mkdir("Fresh.Movie.2024.1080p", time.Now())
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/downloads/orphans", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list listOrphansResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, other, list.Items[0].Path)
+	assert.Equal(t, stale, list.Items[1].Path)
+	assert.Equal(t, int64(8), list.TotalBytes)
+	assert.Equal(t, 1, list.MinAgeDays)
+
+	body := fmt.Sprintf(`{"paths": [%q, %q]}`, stale, filepath.Join(downloadRoot, "Active.Movie.2024.1080p"))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/downloads/orphans/clean", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var cleaned cleanOrphansResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cleaned))
+	assert.Equal(t, []string{stale}, cleaned.Removed)
+	assert.Equal(t, []string{filepath.Join(downloadRoot, "Active.Movie.2024.1080p")}, cleaned.Skipped, "an active download's directory is never removed")
+	assert.Equal(t, int64(4), cleaned.FreedBytes)
+	assert.NoDirExists(t, stale)
+	assert.DirExists(t, other, "only the requested orphans are removed")
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/downloads/orphans/clean", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cleaned))
+	assert.Equal(t, []string{other}, cleaned.Removed, "without paths every orphan is removed")
+}
+
+func TestListHistory_Empty(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/history", nil)
+	w := httptest.NewRecorder()
+
+	srv.listHistory(w, req)
+
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var resp plexStatusResponse
+	var resp listHistoryResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Items)
+}
 
-	assert.True(t, resp.Connected)
-	assert.Equal(t, "Test Plex Server", resp.ServerName)
-	assert.Equal(t, "1.32.0", resp.Version)
-	assert.Empty(t, resp.Error)
-	assert.Len(t, resp.Libraries, 2)
+func TestListFiles_Empty(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
 
-	// Verify first library
-	assert.Equal(t, "1", resp.Libraries[0].Key)
-	assert.Equal(t, "Movies", resp.Libraries[0].Title)
-	assert.Equal(t, "movie", resp.Libraries[0].Type)
-	assert.Equal(t, 150, resp.Libraries[0].ItemCount)
-	assert.Equal(t, "/media/movies", resp.Libraries[0].Location)
-	assert.Equal(t, int64(1700000000), resp.Libraries[0].ScannedAt)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/files", nil)
+	w := httptest.NewRecorder()
 
-	// Verify second library
-	assert.Equal(t, "2", resp.Libraries[1].Key)
-	assert.Equal(t, "TV Shows", resp.Libraries[1].Title)
-	assert.Equal(t, "show", resp.Libraries[1].Type)
-	assert.Equal(t, 50, resp.Libraries[1].ItemCount)
+	srv.listFiles(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp listFilesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Items)
 }
 
-func TestGetPlexStatus_NotConfigured(t *testing.T) {
-	db := setupTestDB(t)
+func TestListFiles_ReleaseGroup(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	movie := fixtures.NewMovie("Test", 2024).Insert(t, db)
+	f := &library.File{ContentID: movie.ID, Path: "/movies/test.mkv", SizeBytes: 1000, ReleaseGroup: "FraMeSToR"}
+	require.NoError(t, srv.deps.Library.AddFile(f))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/files", nil)
+	w := httptest.NewRecorder()
+	srv.listFiles(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp listFilesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "FraMeSToR", resp.Items[0].ReleaseGroup)
+}
+
+func TestListFiles_Missing(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	movie := fixtures.NewMovie("Test", 2024).Insert(t, db)
+	present := &library.File{ContentID: movie.ID, Path: "/movies/present.mkv", SizeBytes: 1000}
+	gone := &library.File{ContentID: movie.ID, Path: "/movies/gone.mkv", SizeBytes: 1000}
+	require.NoError(t, srv.deps.Library.AddFile(present))
+	require.NoError(t, srv.deps.Library.AddFile(gone))
+	_, err := srv.deps.Library.MarkFileMissing(gone)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/files?missing=true", nil)
+	w := httptest.NewRecorder()
+	srv.listFiles(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp listFilesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, gone.ID, resp.Items[0].ID)
+	assert.True(t, resp.Items[0].Missing)
+	assert.NotNil(t, resp.Items[0].MissingAt)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/files?missing=false", nil)
+	w = httptest.NewRecorder()
+	srv.listFiles(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, present.ID, resp.Items[0].ID)
+	assert.False(t, resp.Items[0].Missing)
+}
+
+func TestListFiles_Kind(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	movie := fixtures.NewMovie("Test", 2024).Insert(t, db)
+	video := &library.File{ContentID: movie.ID, Path: "/movies/test.mkv", SizeBytes: 1000}
+	sub := &library.File{ContentID: movie.ID, Path: "/movies/test.en.srt", SizeBytes: 10, Kind: library.FileKindSubtitle}
+	require.NoError(t, srv.deps.Library.AddFile(video))
+	require.NoError(t, srv.deps.Library.AddFile(sub))
+
+	for query, want := range map[string][]int64{
+		"":               {video.ID},
+		"?kind=subtitle": {sub.ID},
+		"?kind=all":      {video.ID, sub.ID},
+	} {
+		w := httptest.NewRecorder()
+		srv.listFiles(w, httptest.NewRequest(http.MethodGet, "/api/v1/files"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, query)
+		var resp listFilesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		var ids []int64
+		for _, item := range resp.Items {
+			ids = append(ids, item.ID)
+		}
+		assert.Equal(t, want, ids, query)
+	}
+
+	w := httptest.NewRecorder()
+	srv.listFiles(w, httptest.NewRequest(http.MethodGet, "/api/v1/files?kind=nfo", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListFiles_Episodes(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	show := fixtures.NewSeries("Show", 2024).Insert(t, db)
+	e1 := fixtures.NewEpisode(show.ID, 1, 1).Insert(t, db)
+	e2 := fixtures.NewEpisode(show.ID, 1, 2).Insert(t, db)
+	e3 := fixtures.NewEpisode(show.ID, 1, 3).Insert(t, db)
+	s2e1 := fixtures.NewEpisode(show.ID, 2, 1).Insert(t, db)
+	// A multi-episode file is recorded on its first episode
+	double := fixtures.NewFile(show.ID, "/tv/Show/Season 01/Show - S01E01E02.mkv").ForEpisode(e1.ID).Insert(t, db)
+	single := fixtures.NewFile(show.ID, "/tv/Show/Season 01/Show - S01E03.mkv").ForEpisode(e3.ID).Insert(t, db)
+	fixtures.NewFile(show.ID, "/tv/Show/Season 02/Show - S02E01.mkv").ForEpisode(s2e1.ID).Insert(t, db)
+
+	list := func(path string) listFilesResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, "%s: %s", path, w.Body.String())
+		var resp listFilesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := list(fmt.Sprintf("/api/v1/files?content_id=%d&season=1", show.ID))
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, 2, resp.Total)
+	assert.Equal(t, double.ID, resp.Items[0].ID)
+	require.NotNil(t, resp.Items[0].Season)
+	require.NotNil(t, resp.Items[0].Episode)
+	assert.Equal(t, 1, *resp.Items[0].Season)
+	assert.Equal(t, 1, *resp.Items[0].Episode)
+	assert.Equal(t, single.ID, resp.Items[1].ID)
+	assert.Equal(t, 3, *resp.Items[1].Episode)
+
+	resp = list(fmt.Sprintf("/api/v1/files?episode_id=%d", e3.ID))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, single.ID, resp.Items[0].ID)
+
+	resp = list(fmt.Sprintf("/api/v1/episodes/%d/files", e1.ID))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, double.ID, resp.Items[0].ID)
+	assert.Empty(t, list(fmt.Sprintf("/api/v1/episodes/%d/files", e2.ID)).Items)
+
+	// Movie files carry no episode numbers
+	movie := fixtures.NewMovie("Film", 2024).Insert(t, db)
+	fixtures.NewFile(movie.ID, "/movies/film.mkv").Insert(t, db)
+	resp = list(fmt.Sprintf("/api/v1/files?content_id=%d", movie.ID))
+	require.Len(t, resp.Items, 1)
+	assert.Nil(t, resp.Items[0].Season)
+
+	for _, path := range []string{"/api/v1/files?season=x", "/api/v1/files?episode_id=x", "/api/v1/files?season=-1"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/episodes/999/files", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteFile(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Add content and file
+	c := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test",
+		Year:           2024,
+		Status:         library.StatusAvailable,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(c))
+
+	f := &library.File{
+		ContentID: c.ID,
+		Path:      "/movies/test.mkv",
+		SizeBytes: 1000,
+		Quality:   "1080p",
+		Source:    "test",
+	}
+	require.NoError(t, srv.deps.Library.AddFile(f))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/files/1", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	srv.deleteFile(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestVerifyFile(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	c := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test",
+		Year:           2024,
+		Status:         library.StatusAvailable,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(c))
+
+	path := filepath.Join(t.TempDir(), "test.mkv")
+	require.NoError(t, os.WriteFile(path, []byte("test video content"), 0644))
+	checksum, err := importer.FileChecksum(context.Background(), path)
+	require.NoError(t, err)
+
+	f := &library.File{ContentID: c.ID, Path: path, SizeBytes: 18, Checksum: checksum}
+	require.NoError(t, srv.deps.Library.AddFile(f))
+	unverified := &library.File{ContentID: c.ID, Path: "/movies/other.mkv", SizeBytes: 1000}
+	require.NoError(t, srv.deps.Library.AddFile(unverified))
+
+	verify := func(id int64) (*httptest.ResponseRecorder, verifyFileResponse) {
+		idStr := strconv.FormatInt(id, 10)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/files/"+idStr+"/verify", nil)
+		req.SetPathValue("id", idStr)
+		w := httptest.NewRecorder()
+		srv.verifyFile(w, req)
+		var resp verifyFileResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w, resp
+	}
+
+	w, resp := verify(f.ID)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, fileVerifyOK, resp.Status)
+	assert.Equal(t, checksum, resp.Actual)
+
+	// Simulate bit-rot: same size, different bytes
+	require.NoError(t, os.WriteFile(path, []byte("test video c0ntent"), 0644))
+	w, resp = verify(f.ID)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, fileVerifyMismatch, resp.Status)
+	assert.Equal(t, checksum, resp.Expected)
+	assert.NotEqual(t, checksum, resp.Actual)
+
+	require.NoError(t, os.Remove(path))
+	w, resp = verify(f.ID)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, fileVerifyMissing, resp.Status)
+
+	w, _ = verify(unverified.ID)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, _ = verify(999)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetStatus(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+	w := httptest.NewRecorder()
+
+	srv.getStatus(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp statusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "ok", resp.Status)
+}
+
+// seedProfiles adds quality profiles accepting the given resolutions.
+func seedProfiles(t *testing.T, srv *Server, profiles map[string][]string) {
+	t.Helper()
+	settings := make(map[string]config.QualityProfile, len(profiles))
+	for name, resolutions := range profiles {
+		settings[name] = config.QualityProfile{Resolution: resolutions}
+	}
+	_, err := srv.deps.Profiles.Seed(settings)
+	require.NoError(t, err)
+}
+
+func TestListProfiles(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	seedProfiles(t, srv, map[string][]string{
+		"hd":  {"720p", "1080p"},
+		"uhd": {"2160p"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles", nil)
+	w := httptest.NewRecorder()
+
+	srv.listProfiles(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp listProfilesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Profiles, 2)
+	assert.False(t, resp.ReadOnly)
+	assert.Equal(t, "hd", resp.Profiles[0].Name)
+	assert.Equal(t, []string{"720p", "1080p"}, resp.Profiles[0].Accept)
+	assert.Equal(t, "1080p", resp.Profiles[0].Cutoff)
+}
+
+func TestProfiles_CRUD(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
+	seedProfiles(t, srv, map[string][]string{"hd": {"1080p"}})
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/api/v1/profiles", `{"name": "anime", "settings": {"resolution": ["1080p", "720p"], "preferred_groups": [["SubsPlease"]]}}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created profileResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, [][]string{{"SubsPlease"}}, created.Settings.PreferredGroups)
+
+	w = do(http.MethodPost, "/api/v1/profiles", `{"name": "anime", "settings": {}}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = do(http.MethodPost, "/api/v1/profiles", `{"name": "bad name", "settings": {}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = do(http.MethodPost, "/api/v1/profiles", `{"name": "sized", "settings": {"min_size_mb": 900, "max_size_mb": 500}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "settings.min_size_mb")
+
+	// Content can use the new profile once it exists
+	assert.True(t, srv.knownProfile("anime"))
+	assert.False(t, srv.knownProfile("uhd"))
+
+	w = do(http.MethodPut, "/api/v1/profiles/anime", `{"settings": {"resolution": ["2160p"]}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = do(http.MethodGet, "/api/v1/profiles/anime", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var updated profileResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, created.ID, updated.ID)
+	assert.Equal(t, "2160p", updated.Cutoff)
+	assert.Empty(t, updated.Settings.PreferredGroups, "update replaces the settings")
+
+	w = do(http.MethodPut, "/api/v1/profiles/anime", `{"name": "cartoons", "settings": {}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "profiles can't be renamed")
+	w = do(http.MethodPut, "/api/v1/profiles/missing", `{"settings": {}}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Profiles in use can't be deleted
+	fixtures.NewSeries("Frieren", 2023).WithProfile("anime").Insert(t, db)
+	w = do(http.MethodDelete, "/api/v1/profiles/anime", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "PROFILE_IN_USE")
+
+	w = do(http.MethodDelete, "/api/v1/profiles/hd", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = do(http.MethodGet, "/api/v1/profiles/hd", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestProfiles_ReadOnly(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	srv.deps.Profiles = profile.NewConfigStore(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var resp listProfilesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.ReadOnly)
+	require.Len(t, resp.Profiles, 1)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/profiles/hd", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "READ_ONLY")
+}
+
+func TestSearch_WithMockSearcher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Create mock searcher
+	mockSearcher := mocks.NewMockSearcher(ctrl)
+	srv.deps.Searcher = mockSearcher
+
+	// Set up expectation
+	mockSearcher.EXPECT().
+		Search(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&search.Result{
+			Releases: []*search.Release{
+				{Title: "Test Movie", Indexer: "TestIndexer"},
+			},
+			Rejected: []*search.Rejection{
+				{Title: "Test.Movie.HDTS", Indexer: "TestIndexer", Reason: `forbidden keyword "HDTS"`},
+			},
+		}, nil)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?query=test+movie", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp searchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Releases, 1)
+	assert.Equal(t, "Test Movie", resp.Releases[0].Title)
+	require.Len(t, resp.Rejected, 1)
+	assert.Equal(t, `forbidden keyword "HDTS"`, resp.Rejected[0].Reason)
+}
+
+func TestSearch_Protocol(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mockSearcher := mocks.NewMockSearcher(ctrl)
+	srv.deps.Searcher = mockSearcher
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	mockSearcher.EXPECT().
+		Search(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query, _ string) (*search.Result, error) {
+			assert.Equal(t, download.ProtocolTorrent, q.Protocol)
+			return &search.Result{Releases: []*search.Release{
+				{Title: "Test.Movie.2024.1080p-GRP", Indexer: "jackett", Protocol: download.ProtocolTorrent},
+			}}, nil
+		})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?query=test+movie&protocol=torrent", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp searchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Releases, 1)
+	assert.Equal(t, "torrent", resp.Releases[0].Protocol)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/search?query=test+movie&protocol=nzb", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_PROTOCOL")
+}
+
+func TestSearch_IndexerErrors(t *testing.T) {
+	resp := searchResultToResponse(&search.Result{Errors: []error{
+		&search.IndexerError{
+			Indexer:    "nzbgeek",
+			Code:       429,
+			RetryAfter: 10 * time.Minute,
+			Err:        errors.New("newznab error 429: too many requests"),
+		},
+		search.ErrNoIndexers,
+	}})
+
+	require.Len(t, resp.Errors, 2)
+	assert.Equal(t, searchErrorResponse{
+		Indexer:           "nzbgeek",
+		Code:              429,
+		Message:           "newznab error 429: too many requests",
+		RetryAfterSeconds: 600,
+	}, resp.Errors[0])
+	assert.Equal(t, searchErrorResponse{Message: "no indexers configured"}, resp.Errors[1])
+	assert.Equal(t, []string{"nzbgeek: newznab error 429: too many requests", "no indexers configured"},
+		resp.ErrorMessages, "legacy strings kept")
+}
+
+func TestSearch_Deferred(t *testing.T) {
+	until := time.Now().Add(25 * time.Minute)
+	resp := searchResultToResponse(&search.Result{Deferred: []*search.Deferred{{
+		Release: &search.Release{Title: "Dune.2024.1080p.BluRay.x264-GROUP", GUID: "g1", Indexer: "nzbgeek", Score: 90, Protocol: "usenet"},
+		Until:   until,
+	}}})
+
+	assert.Empty(t, resp.Releases)
+	require.Len(t, resp.Deferred, 1)
+	assert.Equal(t, "g1", resp.Deferred[0].GUID)
+	assert.Equal(t, 90, resp.Deferred[0].Score)
+	assert.Equal(t, until, resp.Deferred[0].DeferredUntil)
+	assert.InDelta(t, 1500, resp.Deferred[0].RemainingSeconds, 2)
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"deferred":[{"title":"Dune.2024.1080p.BluRay.x264-GROUP"`, "release fields are inlined")
+}
+
+func TestSearch_CachedAndRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mockSearcher := mocks.NewMockSearcher(ctrl)
+	srv.deps.Searcher = mockSearcher
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Cached answer reports its age
+	mockSearcher.EXPECT().
+		Search(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query, _ string) (*search.Result, error) {
+			assert.False(t, q.Refresh)
+			return &search.Result{Cached: true, CachedAt: time.Now().Add(-90 * time.Second)}, nil
+		})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?query=test+movie", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp searchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Cached)
+	assert.InDelta(t, 90, resp.AgeSeconds, 5)
+
+	// refresh=true is passed through to the searcher
+	mockSearcher.EXPECT().
+		Search(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query, _ string) (*search.Result, error) {
+			assert.True(t, q.Refresh)
+			return &search.Result{}, nil
+		})
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/search?query=test+movie&refresh=true", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp = searchResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Cached)
+	assert.Zero(t, resp.AgeSeconds)
+}
+
+func TestListEvents_Success(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Create event log and attach to server
+	eventLog := events.NewEventLog(db)
+	srv.deps.EventLog = eventLog
+
+	// Insert a test event using EventLog.Append
+	testEvent := events.NewBaseEvent("test.event", "content", 1)
+	_, err := eventLog.Append(testEvent)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	w := httptest.NewRecorder()
+
+	srv.listEvents(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp listEventsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Items, 1)
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, 50, resp.Limit) // default limit
+	assert.Equal(t, 0, resp.Offset) // default offset
+	assert.Equal(t, "test.event", resp.Items[0].EventType)
+	assert.Equal(t, "content", resp.Items[0].EntityType)
+	assert.Equal(t, int64(1), resp.Items[0].EntityID)
+}
+
+func TestListEvents_Empty(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Create event log with no events
+	eventLog := events.NewEventLog(db)
+	srv.deps.EventLog = eventLog
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	w := httptest.NewRecorder()
+
+	srv.listEvents(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp listEventsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Items)
+	assert.Zero(t, resp.Total)
+	assert.Equal(t, 50, resp.Limit) // default limit
+	assert.Equal(t, 0, resp.Offset) // default offset
+}
+
+func TestListEvents_Filter(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	eventLog := events.NewEventLog(db)
+	srv.deps.EventLog = eventLog
+
+	for _, e := range []events.BaseEvent{
+		events.NewBaseEvent(events.EventContentAdded, events.EntityContent, 1),
+		events.NewBaseEvent(events.EventContentStatusChanged, events.EntityContent, 1),
+		events.NewBaseEvent(events.EventContentAdded, events.EntityContent, 2),
+		events.NewBaseEvent(events.EventDownloadCreated, events.EntityDownload, 1),
+	} {
+		_, err := eventLog.Append(e)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	since := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	params := url.Values{
+		"entity_type": {"content"},
+		"entity_id":   {"1"},
+		"event_type":  {events.EventContentAdded, events.EventContentStatusChanged},
+		"since":       {since},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events?"+params.Encode(), nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp listEventsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Total)
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, events.EventContentStatusChanged, resp.Items[0].EventType)
+	assert.Equal(t, events.EventContentAdded, resp.Items[1].EventType)
+
+	// Nothing happened in the future
+	params.Set("since", time.Now().Add(time.Hour).Format(time.RFC3339))
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/events?"+params.Encode(), nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Zero(t, resp.Total)
+}
+
+func TestListAudit(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	eventLog := events.NewEventLog(db)
+	srv.deps.EventLog = eventLog
+
+	for _, e := range []*events.APIAudit{
+		{Method: http.MethodDelete, Path: "/api/v1/content/1", Params: map[string]string{"id": "1"}, Status: http.StatusNoContent},
+		{Method: http.MethodDelete, Path: "/api/v1/files/2", Status: http.StatusConflict},
+		{Method: http.MethodPost, Path: "/api/v3/movie", Body: map[string]any{"tmdbId": 438631}, Status: http.StatusCreated},
+	} {
+		e.BaseEvent = events.NewBaseEvent(events.EventAPIAudit, events.EntityAPI, 0)
+		_, err := eventLog.Append(e)
+		require.NoError(t, err)
+	}
+	_, err := eventLog.Append(events.NewBaseEvent(events.EventContentAdded, events.EntityContent, 1))
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+	list := func(query string) (int, listAuditResponse) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit?"+query, nil))
+		var resp listAuditResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp
+	}
+
+	code, resp := list("")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 3, resp.Total, "only audit entries")
+	require.Len(t, resp.Items, 3)
+	assert.Equal(t, "/api/v3/movie", resp.Items[0].Path)
+	assert.InDelta(t, 438631, resp.Items[0].Body["tmdbId"], 0)
+
+	_, resp = list("method=DELETE&failed=true")
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "/api/v1/files/2", resp.Items[0].Path)
+	assert.Equal(t, http.StatusConflict, resp.Items[0].Status)
+
+	_, resp = list("path=/api/v1/content")
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, map[string]string{"id": "1"}, resp.Items[0].Params)
+
+	code, _ = list("failed=maybe")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestListEvents_InvalidFilter(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	srv.deps.EventLog = events.NewEventLog(db)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	for query, code := range map[string]string{
+		"since=yesterday":            "INVALID_TIME",
+		"until=2024-01-15":           "INVALID_TIME",
+		"entity_id=abc":              "INVALID_ID",
+		"limit=-1":                   "INVALID_PAGINATION",
+		"since=2024-01-15T10:00:00Z": "",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/events?"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		if code == "" {
+			assert.Equal(t, http.StatusOK, w.Code, query)
+			continue
+		}
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		var resp errorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, code, resp.Code, query)
+	}
+}
+
+func TestListEvents_Enrich(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	eventLog := events.NewEventLog(db)
+	srv.deps.EventLog = eventLog
+
+	movie := &library.Content{Type: library.ContentTypeMovie, Title: "The Matrix", Year: 1999,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, srv.deps.Library.AddContent(movie))
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Severance", Year: 2022,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, srv.deps.Library.AddContent(series))
+	ep := &library.Episode{ContentID: series.ID, Season: 2, Episode: 3, Status: library.StatusWanted}
+	require.NoError(t, srv.deps.Library.AddEpisode(ep))
+	season := 2
+	d := &download.Download{ContentID: series.ID, Season: &season, Client: download.ClientSABnzbd, ClientID: "nzo_1",
+		Status: download.StatusDownloading, ReleaseName: "Severance.S02.1080p", Indexer: "test"}
+	require.NoError(t, srv.deps.Downloads.Add(d))
+
+	for _, e := range []events.BaseEvent{
+		events.NewBaseEvent(events.EventContentAdded, events.EntityContent, movie.ID),
+		events.NewBaseEvent(events.EventGrabRequested, events.EntityEpisode, ep.ID),
+		events.NewBaseEvent(events.EventDownloadCreated, events.EntityDownload, d.ID),
+		events.NewBaseEvent(events.EventContentDeleted, events.EntityContent, 999),
+	} {
+		_, err := eventLog.Append(e)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events?enrich=true", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp listEventsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 4)
+	titles := make(map[string]string)
+	for _, e := range resp.Items {
+		titles[e.EventType] = e.Title
+	}
+	assert.Equal(t, "The Matrix (1999)", titles[events.EventContentAdded])
+	assert.Equal(t, "Severance S02E03", titles[events.EventGrabRequested])
+	assert.Equal(t, "Severance (2022) S02", titles[events.EventDownloadCreated])
+	assert.Empty(t, titles[events.EventContentDeleted], "deleted content has no title")
+
+	// Titles are only added on request
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var plain listEventsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &plain))
+	for _, e := range plain.Items {
+		assert.Empty(t, e.Title)
+	}
+}
+
+func TestListContentEvents(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	eventLog := events.NewEventLog(db)
+	srv.deps.EventLog = eventLog
+
+	c := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test Movie",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(c))
+
+	for _, e := range []events.BaseEvent{
+		events.NewBaseEvent(events.EventContentAdded, events.EntityContent, c.ID),
+		events.NewBaseEvent(events.EventGrabSkipped, events.EntityContent, c.ID),
+		events.NewBaseEvent(events.EventContentAdded, events.EntityContent, c.ID+1),
+		events.NewBaseEvent(events.EventDownloadCreated, events.EntityDownload, c.ID),
+	} {
+		_, err := eventLog.Append(e)
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/content/%d/events?limit=1", c.ID), nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp listEventsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Total)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, events.EventGrabSkipped, resp.Items[0].EventType)
+	assert.Equal(t, c.ID, resp.Items[0].EntityID)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/content/999/events", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListDownloadEvents_Success(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Create event log and attach to server
+	eventLog := events.NewEventLog(db)
+	srv.deps.EventLog = eventLog
+
+	// Add content first (required for download foreign key)
+	c := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test Movie",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(c))
+
+	// Add a download
+	d := &download.Download{
+		ContentID:   c.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "test-client-id",
+		Status:      download.StatusDownloading,
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "TestIndexer",
+	}
+	require.NoError(t, srv.deps.Downloads.Add(d))
+
+	// Insert event for this download
+	testEvent := events.NewBaseEvent("download.started", "download", d.ID)
+	_, err := eventLog.Append(testEvent)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads/1/events", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	srv.listDownloadEvents(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp listEventsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Items, 1)
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, "download.started", resp.Items[0].EventType)
+	assert.Equal(t, "download", resp.Items[0].EntityType)
+}
+
+func TestReplayEvent(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Without a bus there is nothing to deliver to
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/replay", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	srv.replayEvent(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	eventLog := events.NewEventLog(db)
+	bus := events.NewBus(eventLog, nil)
+	t.Cleanup(func() { _ = bus.Close() })
+	srv.deps.EventLog = eventLog
+	srv.deps.Bus = bus
+	grabs := bus.Subscribe(events.EventGrabRequested, 10)
+
+	// A grab left pending in the outbox
+	id, err := eventLog.AppendPending(&events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		ReleaseName: "Test.Movie.2024.1080p",
+	})
+	require.NoError(t, err)
+
+	req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/events/%d/replay", id), nil)
+	req.SetPathValue("id", strconv.FormatInt(id, 10))
+	w = httptest.NewRecorder()
+	srv.replayEvent(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp EventResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, id, resp.ID)
+	assert.Equal(t, events.EventGrabRequested, resp.EventType)
+	assert.False(t, resp.Pending)
+	assert.NotEmpty(t, resp.ProcessedAt)
+
+	select {
+	case e := <-grabs:
+		assert.Equal(t, int64(42), e.(*events.GrabRequested).ContentID)
+	case <-time.After(time.Second):
+		t.Fatal("replayed event not delivered")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/events/999/replay", nil)
+	req.SetPathValue("id", "999")
+	w = httptest.NewRecorder()
+	srv.replayEvent(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListDownloadEvents_NotFound(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Create event log
+	eventLog := events.NewEventLog(db)
+	srv.deps.EventLog = eventLog
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/downloads/999/events", nil)
+	req.SetPathValue("id", "999")
+	w := httptest.NewRecorder()
+
+	srv.listDownloadEvents(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetDownloadDecision(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	c := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test Movie",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(c))
+
+	auto := &download.Download{ContentID: c.ID, Client: download.ClientSABnzbd, ClientID: "auto", Status: download.StatusQueued, ReleaseName: "Test.Movie.2024.1080p.BluRay"}
+	require.NoError(t, srv.deps.Downloads.Add(auto))
+	manual := &download.Download{ContentID: c.ID, Client: download.ClientSABnzbd, ClientID: "manual", Status: download.StatusQueued, ReleaseName: "Test.Movie.2024.720p"}
+	require.NoError(t, srv.deps.Downloads.Add(manual))
+
+	require.NoError(t, srv.deps.History.Add(&importer.HistoryEntry{
+		ContentID: c.ID,
+		Event:     importer.EventGrabDecision,
+		Data: fmt.Sprintf(`{"download_id": %d, "release_name": "Test.Movie.2024.1080p.BluRay", "profile": "hd",
+			"chosen": {"title": "Test.Movie.2024.1080p.BluRay-GRP", "indexer": "nzbgeek", "group": "GRP", "size_bytes": 100, "score": 90,
+				"breakdown": {"resolution": 80, "source": 10, "total": 90}},
+			"runners_up": [{"title": "Test.Movie.2024.720p", "indexer": "nzbgeek", "size_bytes": 50, "score": 60}],
+			"candidates": 2}`, auto.ID),
+	}))
+
+	get := func(id int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/downloads/%d/decision", id), nil)
+		req.SetPathValue("id", strconv.FormatInt(id, 10))
+		w := httptest.NewRecorder()
+		srv.getDownloadDecision(w, req)
+		return w
+	}
+
+	w := get(auto.ID)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp grabDecisionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, auto.ID, resp.DownloadID)
+	assert.Equal(t, "hd", resp.Profile)
+	assert.Equal(t, 80, resp.Chosen.Breakdown.Resolution)
+	assert.Equal(t, "GRP", resp.Chosen.Group)
+	require.Len(t, resp.RunnersUp, 1)
+	assert.Equal(t, 60, resp.RunnersUp[0].Score)
+	assert.Equal(t, "score 90 (resolution 80, source 10) in profile hd; best of 2, runner-up 60", resp.Summary)
+	assert.False(t, resp.RecordedAt.IsZero())
+
+	w = get(manual.ID)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "NO_DECISION")
+
+	w = get(999)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_FOUND")
+}
+
+func TestGetDashboard_Success(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Add movies and series
+	movie1 := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test Movie 1",
+		Year:           2024,
+		Status:         library.StatusAvailable,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(movie1))
+
+	movie2 := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test Movie 2",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(movie2))
+
+	series1 := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Test Series",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(series1))
+	require.NoError(t, srv.deps.Library.AddFile(&library.File{ContentID: movie1.ID, Path: "/movies/test1.mkv", SizeBytes: 4096, Quality: "1080p"}))
+
+	// Add downloads in various states
+	dlQueued := &download.Download{
+		ContentID:   movie1.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "queued-1",
+		Status:      download.StatusQueued,
+		ReleaseName: "Test.Movie.1.2024.1080p",
+		Indexer:     "TestIndexer",
+	}
+	require.NoError(t, srv.deps.Downloads.Add(dlQueued))
+
+	dlDownloading := &download.Download{
+		ContentID:   movie2.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "downloading-1",
+		Status:      download.StatusDownloading,
+		ReleaseName: "Test.Movie.2.2024.1080p",
+		Indexer:     "TestIndexer",
+	}
+	require.NoError(t, srv.deps.Downloads.Add(dlDownloading))
+
+	dlFailed := &download.Download{
+		ContentID:   series1.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "failed-1",
+		Status:      download.StatusFailed,
+		ReleaseName: "Test.Series.S01E01.1080p",
+		Indexer:     "TestIndexer",
+	}
+	require.NoError(t, srv.deps.Downloads.Add(dlFailed))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
+	w := httptest.NewRecorder()
+
+	srv.getDashboard(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp DashboardResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// Verify version and connections
+	assert.Equal(t, "0.1.0", resp.Version)
+	assert.True(t, resp.Connections.Server)
+	assert.False(t, resp.Connections.Plex)    // No Plex configured
+	assert.Empty(t, resp.Connections.Clients) // No Manager configured
+
+	// Verify download counts
+	assert.Equal(t, 1, resp.Downloads.Queued)
+	assert.Equal(t, 1, resp.Downloads.Downloading)
+	assert.Equal(t, 1, resp.Downloads.Failed)
+	assert.Equal(t, 0, resp.Downloads.Completed)
+	assert.Equal(t, 0, resp.Downloads.Importing)
+	assert.Equal(t, 0, resp.Downloads.Imported)
+	assert.Equal(t, 0, resp.Downloads.Cleaned)
+
+	// Verify library counts
+	assert.Equal(t, 2, resp.Library.Movies)
+	assert.Equal(t, 1, resp.Library.Series)
+	assert.Equal(t, int64(4096), resp.Library.SizeBytes)
+}
+
+func TestGetDashboard_Stuck(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{StuckThresholds: map[download.Status]time.Duration{
+		download.StatusQueued:      10 * time.Minute,
+		download.StatusDownloading: time.Hour,
+	}})
+	content := fixtures.NewMovie("Stuck Movie", 2024).Insert(t, db)
+
+	ages := map[string]time.Duration{"queued-1": time.Hour, "queued-2": 20 * time.Minute, "downloading-1": 30 * time.Minute}
+	for clientID, age := range ages {
+		status := download.StatusQueued
+		if clientID == "downloading-1" {
+			status = download.StatusDownloading
+		}
+		dl := &download.Download{ContentID: content.ID, Client: download.ClientSABnzbd, ClientID: clientID,
+			Status: status, ReleaseName: "Stuck.Movie." + clientID}
+		require.NoError(t, srv.deps.Downloads.Add(dl))
+		_, err := db.Exec("UPDATE downloads SET last_transition_at = ? WHERE id = ?", time.Now().Add(-age), dl.ID)
+		require.NoError(t, err)
+	}
+
+	w := httptest.NewRecorder()
+	srv.getDashboard(w, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp DashboardResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, 2, resp.Stuck.Count)
+	assert.Equal(t, map[string]int{"queued": 2}, resp.Stuck.ByStatus, "downloading is within its threshold")
+	assert.Equal(t, map[string]int64{"queued": 10, "downloading": 60}, resp.Stuck.Thresholds)
+	assert.Equal(t, int64(10), resp.Stuck.Threshold, "shortest threshold")
+	require.Len(t, resp.Problems, 1)
+	assert.Equal(t, checkStuckDownloads, resp.Problems[0].Check)
+	assert.Equal(t, "2 downloads stuck (2 queued)", resp.Problems[0].Message)
+}
+
+func TestGetDashboard_Activity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	movie := &library.Content{Type: library.ContentTypeMovie, Title: "Recent Movie", Year: 2024,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, deps.Library.AddContent(movie))
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Airing Show", Year: 2025,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, deps.Library.AddContent(series))
+
+	// An imported movie whose file is gone, and a series with one upcoming episode
+	require.NoError(t, deps.Library.AddFile(&library.File{ContentID: movie.ID, Path: filepath.Join(t.TempDir(), "gone.mkv"), Quality: "1080p"}))
+	dl := &download.Download{ContentID: movie.ID, Client: download.ClientSABnzbd, ClientID: "imp-1",
+		Status: download.StatusImported, ReleaseName: "Recent.Movie.2024.1080p"}
+	require.NoError(t, deps.Downloads.Add(dl))
+	require.NoError(t, deps.History.Add(&importer.HistoryEntry{ContentID: movie.ID, Event: importer.EventImported,
+		Data: `{"quality": "1080p"}`}))
+	airs := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	require.NoError(t, deps.Library.AddEpisode(&library.Episode{ContentID: series.ID, Season: 2, Episode: 1,
+		Title: "Premiere", Status: library.StatusWanted, AirDate: &airs}))
+
+	mockPlex.EXPECT().GetSections(gomock.Any()).Return([]importer.Section{{Key: "1", Type: "movie"}}, nil)
+	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "1").Return([]importer.PlexItem{{Title: "Other Movie", Year: 2020}}, nil)
+
+	get := func(url string) DashboardResponse {
+		w := httptest.NewRecorder()
+		srv.getDashboard(w, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp DashboardResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := get("/api/v1/dashboard")
+	require.Len(t, resp.Recent, 1)
+	assert.Equal(t, "Recent Movie (2024)", resp.Recent[0].Title)
+	assert.Equal(t, "1080p", resp.Recent[0].Quality)
+
+	require.Len(t, resp.Upcoming, 1)
+	assert.Equal(t, "Airing Show", resp.Upcoming[0].Series)
+	assert.Equal(t, 2, resp.Upcoming[0].Season)
+	assert.Equal(t, "Premiere", resp.Upcoming[0].EpisodeTitle)
+
+	assert.True(t, resp.PlexChecked)
+	checks := make(map[string]int)
+	for _, p := range resp.Problems {
+		checks[p.Check] = p.Count
+	}
+	assert.Equal(t, map[string]int{checkMissingFiles: 1, checkNotInPlex: 1}, checks)
+
+	// skip_plex leaves Plex alone (the mock allows no further calls)
+	resp = get("/api/v1/dashboard?skip_plex=true")
+	assert.False(t, resp.PlexChecked)
+	require.Len(t, resp.Problems, 1)
+	assert.Equal(t, checkMissingFiles, resp.Problems[0].Check)
+}
+
+func TestLibraryStats(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	add := func(contentType library.ContentType, title string) *library.Content {
+		c := &library.Content{Type: contentType, Title: title, Year: 2024, Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/media"}
+		require.NoError(t, srv.deps.Library.AddContent(c))
+		return c
+	}
+	small := add(library.ContentTypeMovie, "Small Movie")
+	big := add(library.ContentTypeMovie, "Big Movie")
+	show := add(library.ContentTypeSeries, "Show")
+	add(library.ContentTypeMovie, "Wanted Movie")
+
+	require.NoError(t, srv.deps.Library.AddFile(&library.File{ContentID: small.ID, Path: "/media/small.mkv", SizeBytes: 1000, Quality: "1080p"}))
+	require.NoError(t, srv.deps.Library.AddFile(&library.File{ContentID: big.ID, Path: "/media/big.mkv", SizeBytes: 9000, Quality: "2160p"}))
+	require.NoError(t, srv.deps.Library.AddFile(&library.File{ContentID: show.ID, Path: "/media/s01e01.mkv", SizeBytes: 2000, Quality: "1080p"}))
+	require.NoError(t, srv.deps.Library.AddFile(&library.File{ContentID: show.ID, Path: "/media/s01e02.mkv", SizeBytes: 4000, Quality: "1080p"}))
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/library/stats?largest=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp libraryStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 4, resp.FileCount)
+	assert.Equal(t, int64(16000), resp.SizeBytes)
+	assert.Equal(t, int64(4000), resp.AverageBytes)
+	assert.Equal(t, []storageGroupResponse{
+		{Name: "movie", FileCount: 2, SizeBytes: 10000, AverageBytes: 5000},
+		{Name: "series", FileCount: 2, SizeBytes: 6000, AverageBytes: 3000},
+	}, resp.ByType)
+	assert.Equal(t, []storageGroupResponse{
+		{Name: "2160p", FileCount: 1, SizeBytes: 9000, AverageBytes: 9000},
+		{Name: "1080p", FileCount: 3, SizeBytes: 7000, AverageBytes: 2333},
+	}, resp.ByQuality)
+	require.Len(t, resp.Largest, 2)
+	assert.Equal(t, "Big Movie", resp.Largest[0].Title)
+	assert.Equal(t, int64(9000), resp.Largest[0].SizeOnDisk)
+	assert.Equal(t, "Show", resp.Largest[1].Title)
+
+	// Content responses carry their size on disk
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/content", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var list listContentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	sizes := map[string]int64{}
+	for _, c := range list.Items {
+		sizes[c.Title] = c.SizeOnDisk
+	}
+	assert.Equal(t, map[string]int64{"Small Movie": 1000, "Big Movie": 9000, "Show": 6000, "Wanted Movie": 0}, sizes)
+}
+
+func TestVerify_NoProblems(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// No downloads, no problems expected
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/verify", nil)
+	w := httptest.NewRecorder()
+
+	srv.verify(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp VerifyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, 0, resp.Checked)
+	assert.Equal(t, 0, resp.Passed)
+	assert.Empty(t, resp.Problems)
+	// Connections should be false since no Plex or Manager configured
+	assert.False(t, resp.Connections.Plex)
+	assert.Empty(t, resp.Connections.Clients)
+}
+
+// setupVerifyFix creates a server with a mock manager and event bus, and a movie to attach downloads to.
+func setupVerifyFix(t *testing.T, db *sql.DB, cfg Config) (*Server, *mocks.MockDownloadManager, *events.Bus, *library.Content) {
+	t.Helper()
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+	mockManager.EXPECT().Clients().Return(nil).AnyTimes()
+	bus := events.NewBus(nil, nil)
+	t.Cleanup(func() { bus.Close() })
+
+	store := library.NewStore(db)
+	content := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test Movie",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, store.AddContent(content))
+
+	srv, err := NewWithDeps(ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}, cfg)
+	require.NoError(t, err)
+	return srv, mockManager, bus, content
+}
+
+func runVerify(t *testing.T, srv *Server, query string) VerifyResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/verify"+query, nil)
+	w := httptest.NewRecorder()
+	srv.verify(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+
+	var resp VerifyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestVerify_FixMarksCompleted(t *testing.T) {
+	srv, mockManager, bus, content := setupVerifyFix(t, testutil.OpenTestDB(t), Config{})
+	completed := bus.Subscribe(events.EventDownloadCompleted, 10)
+	reconciled := bus.Subscribe(events.EventDownloadReconciled, 10)
+
+	dl := &download.Download{
+		ContentID:   content.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "nzo_1",
+		Status:      download.StatusDownloading,
+		ReleaseName: "Test.Movie.2024.1080p",
+	}
+	require.NoError(t, srv.deps.Downloads.Add(dl))
+	mockManager.EXPECT().Status(gomock.Any(), gomock.Any()).
+		Return(&download.ClientStatus{ID: "nzo_1", Status: download.StatusCompleted, Path: "/downloads/Test.Movie.2024.1080p"}, nil).
+		AnyTimes()
+
+	// Dry run reports the problem without changing anything
+	resp := runVerify(t, srv, "")
+	assert.True(t, resp.DryRun)
+	require.Len(t, resp.Problems, 1)
+	assert.Contains(t, resp.Problems[0].Issue, "Completed in sabnzbd")
+	assert.Empty(t, resp.AppliedFixes)
+	got, err := srv.deps.Downloads.Get(dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, download.StatusDownloading, got.Status)
+
+	resp = runVerify(t, srv, "?fix=true")
+	assert.False(t, resp.DryRun)
+	require.Len(t, resp.AppliedFixes, 1)
+	assert.Equal(t, AppliedFix{
+		DownloadID: dl.ID,
+		Action:     fixMarkCompleted,
+		FromStatus: "downloading",
+		ToStatus:   "completed",
+		Reason:     "completed in sabnzbd",
+	}, resp.AppliedFixes[0])
+
+	got, err = srv.deps.Downloads.Get(dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, download.StatusCompleted, got.Status)
+
+	select {
+	case e := <-reconciled:
+		assert.Equal(t, "downloading", e.(*events.DownloadReconciled).FromStatus)
+	case <-time.After(time.Second):
+		t.Fatal("expected DownloadReconciled event")
+	}
+	select {
+	case e := <-completed:
+		assert.Equal(t, "/downloads/Test.Movie.2024.1080p", e.(*events.DownloadCompleted).SourcePath)
+	case <-time.After(time.Second):
+		t.Fatal("expected DownloadCompleted event")
+	}
+}
+
+func TestVerify_FixMarksMissingFailed(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv, mockManager, _, content := setupVerifyFix(t, db, Config{})
+
+	recent := &download.Download{ContentID: content.ID, Client: download.ClientSABnzbd, ClientID: "nzo_new",
+		Status: download.StatusDownloading, ReleaseName: "Recent"}
+	require.NoError(t, srv.deps.Downloads.Add(recent))
+	old := &download.Download{ContentID: content.ID, Client: download.ClientSABnzbd, ClientID: "nzo_old",
+		Status: download.StatusDownloading, ReleaseName: "Old"}
+	require.NoError(t, srv.deps.Downloads.Add(old))
+	unreachable := &download.Download{ContentID: content.ID, Client: download.ClientSABnzbd, ClientID: "nzo_down",
+		Status: download.StatusDownloading, ReleaseName: "Unreachable"}
+	require.NoError(t, srv.deps.Downloads.Add(unreachable))
+
+	_, err := db.Exec("UPDATE downloads SET last_transition_at = ? WHERE id IN (?, ?)",
+		time.Now().Add(-2*download.DefaultStuckThresholds()[download.StatusDownloading]), old.ID, unreachable.ID)
+	require.NoError(t, err)
+
+	mockManager.EXPECT().Status(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, d *download.Download) (*download.ClientStatus, error) {
+			if d.ClientID == "nzo_down" {
+				return nil, download.ErrClientUnavailable
+			}
+			return nil, download.ErrDownloadNotFound
+		}).AnyTimes()
+
+	resp := runVerify(t, srv, "?fix=true")
+	assert.Len(t, resp.Problems, 3)
+	require.Len(t, resp.AppliedFixes, 1, "only the old, definitely missing download is failed")
+	assert.Equal(t, old.ID, resp.AppliedFixes[0].DownloadID)
+	assert.Equal(t, fixMarkFailed, resp.AppliedFixes[0].Action)
+
+	for id, want := range map[int64]download.Status{
+		recent.ID:      download.StatusDownloading,
+		old.ID:         download.StatusFailed,
+		unreachable.ID: download.StatusDownloading,
+	} {
+		got, err := srv.deps.Downloads.Get(id)
+		require.NoError(t, err)
+		assert.Equal(t, want, got.Status, "download %d", id)
+	}
+}
+
+func TestVerify_Stuck(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv, _, _, content := setupVerifyFix(t, db, Config{StuckThresholds: map[download.Status]time.Duration{
+		download.StatusQueued: 10 * time.Minute,
+	}})
+
+	fresh := &download.Download{ContentID: content.ID, Client: download.ClientSABnzbd, ClientID: "nzo_fresh",
+		Status: download.StatusQueued, ReleaseName: "Fresh"}
+	require.NoError(t, srv.deps.Downloads.Add(fresh))
+	stale := &download.Download{ContentID: content.ID, Client: download.ClientSABnzbd, ClientID: "nzo_stale",
+		Status: download.StatusQueued, ReleaseName: "Stale"}
+	require.NoError(t, srv.deps.Downloads.Add(stale))
+	_, err := db.Exec("UPDATE downloads SET last_transition_at = ? WHERE id = ?", time.Now().Add(-time.Hour), stale.ID)
+	require.NoError(t, err)
+
+	resp := runVerify(t, srv, "")
+	assert.Equal(t, 1, resp.Passed)
+	require.Len(t, resp.Problems, 1)
+	assert.Equal(t, stale.ID, resp.Problems[0].DownloadID)
+	assert.Equal(t, "Stuck in queued", resp.Problems[0].Issue)
+	assert.Contains(t, resp.Problems[0].Checks[0], "threshold 10m0s")
+}
+
+func TestVerify_PlexKey(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	ctrl := gomock.NewController(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	mockPlex.EXPECT().GetIdentity(gomock.Any()).Return(&importer.Identity{}, nil).AnyTimes()
+
+	store := library.NewStore(db)
+	content := &library.Content{Type: library.ContentTypeMovie, Title: "Test Movie", Year: 2024,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies", PlexKey: "111"}
+	require.NoError(t, store.AddContent(content))
+	srv, err := NewWithDeps(ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}, Config{})
+	require.NoError(t, err)
+	dl := &download.Download{ContentID: content.ID, Client: download.ClientSABnzbd, ClientID: "nzo_1",
+		Status: download.StatusImported, ReleaseName: "Test.Movie.2024.1080p"}
+	require.NoError(t, srv.deps.Downloads.Add(dl))
+	plexKey := func() string {
+		c, err := store.GetContent(content.ID)
+		require.NoError(t, err)
+		return c.PlexKey
+	}
+
+	// The stored key is fetched directly, without a search
+	mockPlex.EXPECT().GetItem(gomock.Any(), "111").Return(&importer.PlexItem{RatingKey: "111", Title: "Test Movie (Director's Cut)"}, nil)
+	resp := runVerify(t, srv, "")
+	assert.Equal(t, 1, resp.Passed)
+
+	// An item matched again in Plex is searched for and its new key stored
+	mockPlex.EXPECT().GetItem(gomock.Any(), "111").Return(nil, importer.ErrPlexItemNotFound)
+	mockPlex.EXPECT().FindMovieItem(gomock.Any(), "Test Movie", 2024).Return(&importer.PlexItem{RatingKey: "222"}, nil)
+	resp = runVerify(t, srv, "")
+	assert.Equal(t, 1, resp.Passed)
+	assert.Equal(t, "222", plexKey())
+
+	// An item gone from Plex is a problem, and its key is dropped
+	mockPlex.EXPECT().GetItem(gomock.Any(), "222").Return(nil, importer.ErrPlexItemNotFound)
+	mockPlex.EXPECT().FindMovieItem(gomock.Any(), "Test Movie", 2024).Return(nil, nil)
+	resp = runVerify(t, srv, "")
+	require.Len(t, resp.Problems, 1)
+	assert.Equal(t, "Not found in Plex library", resp.Problems[0].Issue)
+	assert.Empty(t, plexKey())
+}
+
+func TestVerify_FixReimportsMissingFile(t *testing.T) {
+	downloadRoot := t.TempDir()
+	srv, mockManager, bus, content := setupVerifyFix(t, testutil.OpenTestDB(t), Config{DownloadRoot: downloadRoot})
+	completed := bus.Subscribe(events.EventDownloadCompleted, 10)
+	mockManager.EXPECT().Status(gomock.Any(), gomock.Any()).Return(nil, download.ErrDownloadNotFound).AnyTimes()
+
+	dl := &download.Download{ContentID: content.ID, Client: download.ClientSABnzbd, ClientID: "nzo_1",
+		Status: download.StatusCompleted, ReleaseName: "Test.Movie.2024.1080p"}
+	require.NoError(t, srv.deps.Downloads.Add(dl))
+	require.NoError(t, srv.deps.Downloads.Transition(dl, download.StatusImporting))
+	require.NoError(t, srv.deps.Downloads.Transition(dl, download.StatusImported))
+	require.NoError(t, os.MkdirAll(filepath.Join(downloadRoot, dl.ReleaseName), 0755))
+
+	file := &library.File{ContentID: content.ID, Path: "/movies/gone/Test Movie (2024).mkv", Quality: "1080p"}
+	require.NoError(t, srv.deps.Library.AddFile(file))
+
+	// Without reimport=true the problem is only reported
+	resp := runVerify(t, srv, "?fix=true")
+	require.Len(t, resp.Problems, 1)
+	assert.Equal(t, "Imported file missing from library", resp.Problems[0].Issue)
+	assert.Empty(t, resp.AppliedFixes)
+
+	resp = runVerify(t, srv, "?fix=true&reimport=true")
+	require.Len(t, resp.AppliedFixes, 1)
+	assert.Equal(t, fixReimport, resp.AppliedFixes[0].Action)
+	assert.Equal(t, "imported", resp.AppliedFixes[0].FromStatus)
+
+	got, err := srv.deps.Downloads.Get(dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, download.StatusCompleted, got.Status)
+	_, err = srv.deps.Library.GetFile(file.ID)
+	require.ErrorIs(t, err, library.ErrNotFound, "stale file record should be removed")
+
+	select {
+	case e := <-completed:
+		assert.Equal(t, filepath.Join(downloadRoot, dl.ReleaseName), e.(*events.DownloadCompleted).SourcePath)
+	case <-time.After(time.Second):
+		t.Fatal("expected DownloadCompleted event")
+	}
+}
+
+func TestVerify_FixRequiresBus(t *testing.T) {
+	srv := New(testutil.OpenTestDB(t), Config{})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/verify?fix=true", nil)
+	w := httptest.NewRecorder()
+	srv.verify(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestVerify_WithDownloadID(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Add content for downloads
+	content := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test Movie",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(content))
+
+	// Add multiple downloads (using Active states so verify finds them)
+	dl1 := &download.Download{
+		ContentID:   content.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "client-1",
+		Status:      download.StatusDownloading,
+		ReleaseName: "Test.Movie.2024.1080p.Release1",
+		Indexer:     "TestIndexer",
+	}
+	require.NoError(t, srv.deps.Downloads.Add(dl1))
+
+	dl2 := &download.Download{
+		ContentID:   content.ID,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "client-2",
+		Status:      download.StatusCompleted,
+		ReleaseName: "Test.Movie.2024.1080p.Release2",
+		Indexer:     "TestIndexer",
+	}
+	require.NoError(t, srv.deps.Downloads.Add(dl2))
+
+	// Verify with specific ID filter (should only check dl2)
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/verify?id=%d", dl2.ID), nil)
+	w := httptest.NewRecorder()
+
+	srv.verify(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp VerifyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// Should only have checked 1 download (the one we filtered for)
+	assert.Equal(t, 1, resp.Checked)
+	// Without manager, verify doesn't find problems for completed status
+	assert.Equal(t, 1, resp.Passed)
+	assert.Empty(t, resp.Problems)
+}
+
+func TestGetPlexStatus_Connected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+
+	// Setup Plex mock expectations
+	mockPlex.EXPECT().
+		GetIdentity(gomock.Any()).
+		Return(&importer.Identity{
+			Name:    "Test Plex Server",
+			Version: "1.32.0",
+		}, nil)
+
+	mockPlex.EXPECT().
+		GetSections(gomock.Any()).
+		Return([]importer.Section{
+			{
+				Key:       "1",
+				Title:     "Movies",
+				Type:      "movie",
+				Locations: []importer.Location{{Path: "/media/movies"}},
+				ScannedAt: 1700000000,
+			},
+			{
+				Key:       "2",
+				Title:     "TV Shows",
+				Type:      "show",
+				Locations: []importer.Location{{Path: "/media/tv"}},
+				ScannedAt: 1700000001,
+			},
+		}, nil)
+
+	// GetLibraryCount is called for each section
+	mockPlex.EXPECT().
+		GetLibraryCount(gomock.Any(), "1").
+		Return(150, nil)
+	mockPlex.EXPECT().
+		GetLibraryCount(gomock.Any(), "2").
+		Return(50, nil)
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/status", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp plexStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.True(t, resp.Connected)
+	assert.Equal(t, "Test Plex Server", resp.ServerName)
+	assert.Equal(t, "1.32.0", resp.Version)
+	assert.Empty(t, resp.Error)
+	assert.Len(t, resp.Libraries, 2)
+
+	// Verify first library
+	assert.Equal(t, "1", resp.Libraries[0].Key)
+	assert.Equal(t, "Movies", resp.Libraries[0].Title)
+	assert.Equal(t, "movie", resp.Libraries[0].Type)
+	assert.Equal(t, 150, resp.Libraries[0].ItemCount)
+	assert.Equal(t, "/media/movies", resp.Libraries[0].Location)
+	assert.Equal(t, int64(1700000000), resp.Libraries[0].ScannedAt)
+
+	// Verify second library
+	assert.Equal(t, "2", resp.Libraries[1].Key)
+	assert.Equal(t, "TV Shows", resp.Libraries[1].Title)
+	assert.Equal(t, "show", resp.Libraries[1].Type)
+	assert.Equal(t, 50, resp.Libraries[1].ItemCount)
+}
+
+func TestGetPlexStatus_NotConfigured(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/status", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var resp plexStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.False(t, resp.Connected)
+	assert.Empty(t, resp.ServerName)
+	assert.Empty(t, resp.Version)
+	assert.Empty(t, resp.Libraries)
+	assert.Equal(t, "Plex not configured", resp.Error)
+}
+
+func TestScanPlexLibraries_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+
+	// Setup mock expectations
+	mockPlex.EXPECT().
+		GetSections(gomock.Any()).
+		Return([]importer.Section{
+			{Key: "1", Title: "Movies", Type: "movie"},
+			{Key: "2", Title: "TV Shows", Type: "show"},
+		}, nil)
+
+	// Scan both libraries
+	mockPlex.EXPECT().
+		RefreshLibrary(gomock.Any(), "1").
+		Return(nil)
+	mockPlex.EXPECT().
+		RefreshLibrary(gomock.Any(), "2").
+		Return(nil)
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Request to scan all libraries (empty array)
+	body := `{"libraries":[]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/plex/scan", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp plexScanResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Len(t, resp.Scanned, 2)
+	assert.Contains(t, resp.Scanned, "Movies")
+	assert.Contains(t, resp.Scanned, "TV Shows")
+}
+
+func TestScanPlexLibraries_NoPlex(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := `{"libraries":[]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/plex/scan", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "SERVICE_UNAVAILABLE", resp.Code)
+	assert.Equal(t, "Plex not configured", resp.Error)
+}
+
+func TestSearchPlex_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+
+	// Setup mock expectations
+	mockPlex.EXPECT().
+		Search(gomock.Any(), "inception").
+		Return([]importer.PlexItem{
+			{
+				RatingKey: "12345",
+				Title:     "Inception",
+				Year:      2010,
+				Type:      "movie",
+				AddedAt:   1700000000,
+				FilePath:  "/media/movies/Inception (2010)/Inception.mkv",
+			},
+			{
+				RatingKey: "12346",
+				Title:     "Inception: The Cobol Job",
+				Year:      2010,
+				Type:      "movie",
+				AddedAt:   1700000001,
+				FilePath:  "/media/movies/Inception The Cobol Job (2010)/cobol.mkv",
+			},
+		}, nil)
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/search?query=inception", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp plexSearchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, "inception", resp.Query)
+	assert.Equal(t, 2, resp.Total)
+	assert.Len(t, resp.Items, 2)
+
+	// Verify first item
+	assert.Equal(t, "Inception", resp.Items[0].Title)
+	assert.Equal(t, 2010, resp.Items[0].Year)
+	assert.Equal(t, "movie", resp.Items[0].Type)
+	assert.Equal(t, int64(1700000000), resp.Items[0].AddedAt)
+	assert.Equal(t, "/media/movies/Inception (2010)/Inception.mkv", resp.Items[0].FilePath)
+	assert.False(t, resp.Items[0].Tracked)
+	assert.Nil(t, resp.Items[0].ContentID)
+
+	// Verify second item
+	assert.Equal(t, "Inception: The Cobol Job", resp.Items[1].Title)
+	assert.Equal(t, 2010, resp.Items[1].Year)
+}
+
+func TestSearchPlex_MissingQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+
+	// No EXPECT calls - search should not be called when query is missing
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/search", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "MISSING_QUERY", resp.Code)
+	assert.Equal(t, "query parameter is required", resp.Error)
+}
+
+func TestListPlexLibraryItems_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+
+	// Setup mock expectations
+	mockPlex.EXPECT().
+		FindSectionByName(gomock.Any(), "Movies").
+		Return(&importer.Section{
+			Key:   "1",
+			Title: "Movies",
+			Type:  "movie",
+		}, nil)
+
+	mockPlex.EXPECT().
+		ListLibraryItems(gomock.Any(), "1").
+		Return([]importer.PlexItem{
+			{
+				RatingKey: "100",
+				Title:     "The Matrix",
+				Year:      1999,
+				Type:      "movie",
+				AddedAt:   1699000000,
+				FilePath:  "/media/movies/The Matrix (1999)/matrix.mkv",
+			},
+			{
+				RatingKey: "101",
+				Title:     "The Matrix Reloaded",
+				Year:      2003,
+				Type:      "movie",
+				AddedAt:   1699000001,
+				FilePath:  "/media/movies/The Matrix Reloaded (2003)/reloaded.mkv",
+			},
+			{
+				RatingKey: "102",
+				Title:     "The Matrix Revolutions",
+				Year:      2003,
+				Type:      "movie",
+				AddedAt:   1699000002,
+				FilePath:  "/media/movies/The Matrix Revolutions (2003)/revolutions.mkv",
+			},
+		}, nil)
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/libraries/Movies/items", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp plexListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, "Movies", resp.Library)
+	assert.Equal(t, 3, resp.Total)
+	assert.Len(t, resp.Items, 3)
+
+	// Verify first item
+	assert.Equal(t, "The Matrix", resp.Items[0].Title)
+	assert.Equal(t, 1999, resp.Items[0].Year)
+	assert.Equal(t, "movie", resp.Items[0].Type)
+	assert.Equal(t, int64(1699000000), resp.Items[0].AddedAt)
+	assert.Equal(t, "/media/movies/The Matrix (1999)/matrix.mkv", resp.Items[0].FilePath)
+	assert.False(t, resp.Items[0].Tracked)
+	assert.Nil(t, resp.Items[0].ContentID)
+
+	// Verify second and third items exist
+	assert.Equal(t, "The Matrix Reloaded", resp.Items[1].Title)
+	assert.Equal(t, "The Matrix Revolutions", resp.Items[2].Title)
+}
+
+func TestListPlexLibraryItems_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+
+	// Return nil section (not found)
+	mockPlex.EXPECT().
+		FindSectionByName(gomock.Any(), "NonExistent").
+		Return(nil, nil)
+
+	// GetSections called to list available libraries
+	mockPlex.EXPECT().
+		GetSections(gomock.Any()).
+		Return([]importer.Section{
+			{Key: "1", Title: "Movies", Type: "movie"},
+			{Key: "2", Title: "TV Shows", Type: "show"},
+		}, nil)
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/libraries/NonExistent/items", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "LIBRARY_NOT_FOUND", resp.Code)
+	assert.Contains(t, resp.Error, "NonExistent")
+	assert.Contains(t, resp.Error, "Movies")
+	assert.Contains(t, resp.Error, "TV Shows")
+}
+
+// mockIndexer implements IndexerAPI for testing
+type mockIndexer struct {
+	name       string
+	url        string
+	priority   int
+	categories []string
+	keys       []newznab.KeyUsage
+	capsErr    error
+	capsCalls  atomic.Int32
+}
+
+func (m *mockIndexer) Name() string                 { return m.name }
+func (m *mockIndexer) URL() string                  { return m.url }
+func (m *mockIndexer) Priority() int                { return m.priority }
+func (m *mockIndexer) Categories() []string         { return m.categories }
+func (m *mockIndexer) Caps(_ context.Context) error { m.capsCalls.Add(1); return m.capsErr }
+func (m *mockIndexer) KeyUsage() []newznab.KeyUsage { return m.keys }
+
+func TestGetHealth_Liveness(t *testing.T) {
+	srv := New(testutil.OpenTestDB(t), Config{})
+	failing := &mockIndexer{name: "down", capsErr: errors.New("timeout")}
+	srv.deps.Indexers = []IndexerAPI{failing}
+
+	w := httptest.NewRecorder()
+	srv.getHealth(w, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp healthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "ok", resp.Status)
+	assert.False(t, resp.Ready)
+	require.Len(t, resp.Checks, 1)
+	assert.Equal(t, "database", resp.Checks[0].Name)
+	assert.Equal(t, "ok", resp.Checks[0].Status)
+	assert.Zero(t, failing.capsCalls.Load(), "liveness makes no external calls")
+}
+
+func TestGetHealth_Readiness(t *testing.T) {
+	srv := New(testutil.OpenTestDB(t), Config{})
+	down := &mockIndexer{name: "down", capsErr: errors.New("timeout")}
+	up := &mockIndexer{name: "up"}
+	srv.deps.Indexers = []IndexerAPI{down, up}
+
+	get := func() (int, healthResponse) {
+		w := httptest.NewRecorder()
+		srv.getHealth(w, httptest.NewRequest(http.MethodGet, "/api/v1/health?ready=true", nil))
+		var resp healthResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+
+	// One reachable indexer is enough; no download client is configured
+	code, resp := get()
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, resp.Ready)
+	statuses := map[string]string{}
+	for _, c := range resp.Checks {
+		statuses[c.Name] = c.Status
+	}
+	assert.Equal(t, map[string]string{
+		"database":          "ok",
+		"database_writable": "ok",
+		"indexers":          "ok",
+		"download_clients":  "skipped",
+	}, statuses)
+
+	// Results are cached: a failure isn't noticed until they expire
+	up.capsErr = errors.New("timeout")
+	code, _ = get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int32(1), up.capsCalls.Load())
+
+	srv.health.expires = time.Now()
+	code, resp = get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", resp.Status)
+	assert.Equal(t, "indexers", resp.Checks[2].Name)
+	assert.Contains(t, resp.Checks[2].Error, "no indexer reachable")
+}
+
+func TestGetHealth_DownloadClientDown(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	client := downloadmocks.NewMockDownloader(gomock.NewController(t))
+	client.EXPECT().List(gomock.Any()).Return(nil, errors.New("connection refused"))
+	mgr := download.NewManager(download.NewStore(db), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mgr.AddClient("sabnzbd", download.ProtocolUsenet, client)
+	srv.deps.Manager = mgr
+
+	w := httptest.NewRecorder()
+	srv.getHealth(w, httptest.NewRequest(http.MethodGet, "/api/v1/health?ready=true", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var resp healthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	last := resp.Checks[len(resp.Checks)-1]
+	assert.Equal(t, "download_client:sabnzbd", last.Name)
+	assert.Equal(t, "failed", last.Status)
+	assert.Equal(t, "connection refused", last.Error)
+	assert.Equal(t, "skipped", resp.Checks[2].Status, "no indexers configured")
+}
+
+func TestCheckLibrary_Success(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// Add content with different statuses
+	available := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Available Movie",
+		Year:           2024,
+		Status:         library.StatusAvailable,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(available))
+
+	wanted := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Wanted Movie",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(wanted))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/library/check", nil)
+	w := httptest.NewRecorder()
+
+	srv.checkLibrary(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp libraryCheckResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, 2, resp.Total)
+	assert.Len(t, resp.Items, 2)
+
+	// Find each item
+	var foundAvailable, foundWanted bool
+	for _, item := range resp.Items {
+		if item.Title == "Available Movie" {
+			foundAvailable = true
+			assert.Equal(t, "available", item.Status)
+			assert.Equal(t, int64(1), item.ID)
+			// Status is 'available' but no files - should have issue
+			assert.Contains(t, item.Issues, "Status is 'available' but no files in database")
+		}
+		if item.Title == "Wanted Movie" {
+			foundWanted = true
+			assert.Equal(t, "wanted", item.Status)
+			assert.Equal(t, int64(2), item.ID)
+			// Status is 'wanted' with no files - no issue
+			assert.Empty(t, item.Issues)
+		}
+	}
+	assert.True(t, foundAvailable, "should find Available Movie")
+	assert.True(t, foundWanted, "should find Wanted Movie")
+
+	// 'available' with no files = issue, 'wanted' with no files = healthy
+	assert.Equal(t, 1, resp.Healthy)
+	assert.Equal(t, 1, resp.WithIssues)
+}
+
+func TestCheckLibrary_FilesReconciled(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{FilesReconciled: true})
+
+	// On disk but flagged missing: the flag wins, without a stat
+	path := filepath.Join(t.TempDir(), "movie.mkv")
+	require.NoError(t, os.WriteFile(path, []byte("video"), 0o644))
+	movie := fixtures.NewMovie("Flagged", 2024).WithStatus(library.StatusAvailable).Insert(t, db)
+	f := &library.File{ContentID: movie.ID, Path: path, SizeBytes: 5}
+	require.NoError(t, srv.deps.Library.AddFile(f))
+	_, err := srv.deps.Library.MarkFileMissing(f)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/library/check", nil)
+	w := httptest.NewRecorder()
+	srv.checkLibrary(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp libraryCheckResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, []string{path}, resp.Items[0].FileMissing)
+}
+
+func TestCheckLibrary_Empty(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// No content added
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/library/check", nil)
+	w := httptest.NewRecorder()
+
+	srv.checkLibrary(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp libraryCheckResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, 0, resp.Total)
+	assert.Empty(t, resp.Items)
+	assert.Equal(t, 0, resp.Healthy)
+	assert.Equal(t, 0, resp.WithIssues)
+}
+
+func TestCheckLibrary_PlexListedOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	contents := []*library.Content{
+		{Type: library.ContentTypeMovie, Title: "In Plex", Year: 2020},
+		{Type: library.ContentTypeMovie, Title: "Alien", Year: 1979},
+		{Type: library.ContentTypeMovie, Title: "Missing", Year: 2021},
+		{Type: library.ContentTypeMovie, Title: "Keyed", Year: 2022, PlexKey: "7"},
+	}
+	for _, c := range contents {
+		c.Status, c.QualityProfile, c.RootPath = library.StatusAvailable, "hd", "/movies"
+		require.NoError(t, deps.Library.AddContent(c))
+		path := filepath.Join(t.TempDir(), c.Title+".mkv")
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0o644))
+		require.NoError(t, deps.Library.AddFile(&library.File{ContentID: c.ID, Path: path, Quality: "1080p"}))
+	}
+
+	// One listing per section for the whole check; no per-item Search
+	mockPlex.EXPECT().GetSections(gomock.Any()).Return([]importer.Section{{Key: "1", Type: "movie"}, {Key: "2", Type: "artist"}}, nil)
+	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "1").Return([]importer.PlexItem{
+		{RatingKey: "5", Title: "in plex", Year: 2020},
+		{RatingKey: "6", Title: "Alien (Director's Cut)", Year: 1979},
+		{RatingKey: "7", Title: "Renamed in Plex", Year: 2023},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	srv.checkLibrary(w, httptest.NewRequest(http.MethodGet, "/api/v1/library/check", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp libraryCheckResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 4)
+	assert.True(t, resp.Items[0].InPlex)
+	assert.Equal(t, "in plex", resp.Items[0].PlexTitle)
+	assert.True(t, resp.Items[0].FileExists)
+	assert.True(t, resp.Items[1].InPlex)
+	assert.Equal(t, "Alien (Director's Cut) (year match)", resp.Items[1].PlexTitle)
+	assert.False(t, resp.Items[2].InPlex)
+	assert.Contains(t, resp.Items[2].Issues, "Status is 'available' but not found in Plex")
+	// The stored rating key matches whatever the title in Plex
+	assert.True(t, resp.Items[3].InPlex)
+	assert.Equal(t, "Renamed in Plex", resp.Items[3].PlexTitle)
+	assert.Equal(t, 3, resp.Healthy)
+	assert.Equal(t, 1, resp.WithIssues)
+
+	// An exact title match stores its key; an approximate one does not
+	c, err := deps.Library.GetContent(contents[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "5", c.PlexKey)
+	c, err = deps.Library.GetContent(contents[1].ID)
+	require.NoError(t, err)
+	assert.Empty(t, c.PlexKey)
+}
+
+func TestCheckLibrary_PlexUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Plex:      mockPlex,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+	require.NoError(t, deps.Library.AddContent(&library.Content{Type: library.ContentTypeMovie, Title: "Wanted",
+		Year: 2024, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}))
+
+	mockPlex.EXPECT().GetSections(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	w := httptest.NewRecorder()
+	srv.checkLibrary(w, httptest.NewRequest(http.MethodGet, "/api/v1/library/check", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp libraryCheckResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.PlexError, "connection refused")
+	assert.Equal(t, 1, resp.Healthy)
+}
+
+func TestCheckLibrary_LimitCapped(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	for i := range maxSyncLibraryCheck + 1 {
+		require.NoError(t, srv.deps.Library.AddContent(&library.Content{Type: library.ContentTypeMovie,
+			Title: fmt.Sprintf("Movie %d", i), Year: 2024, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}))
+	}
+
+	get := func(url string) libraryCheckResponse {
+		w := httptest.NewRecorder()
+		srv.checkLibrary(w, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp libraryCheckResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := get("/api/v1/library/check")
+	assert.Len(t, resp.Items, syncLibraryCheckLimit)
+	assert.Equal(t, maxSyncLibraryCheck+1, resp.Total)
+	resp = get("/api/v1/library/check?limit=5000")
+	assert.Len(t, resp.Items, maxSyncLibraryCheck)
+}
+
+func TestStartLibraryCheck(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	db.SetMaxOpenConns(1) // The background check must share the in-memory database
+	srv := New(db, Config{})
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	for i := range libraryCheckBatchSize + 5 {
+		status := library.StatusWanted
+		if i%2 == 0 {
+			status = library.StatusAvailable // No files: an issue
+		}
+		require.NoError(t, srv.deps.Library.AddContent(&library.Content{Type: library.ContentTypeMovie,
+			Title: fmt.Sprintf("Movie %d", i), Year: 2024, Status: status, QualityProfile: "hd", RootPath: "/movies"}))
+	}
+	require.NoError(t, srv.deps.Library.AddContent(&library.Content{Type: library.ContentTypeSeries,
+		Title: "Show", Year: 2024, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}))
+
+	do := func(method, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, url, nil))
+		return w
+	}
+
+	// Nothing checked yet
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/v1/library/check/latest").Code)
+
+	w := do(http.MethodPost, "/api/v1/library/check?type=movie")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var started libraryCheckJobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	assert.Equal(t, "running", started.Status)
+	assert.Equal(t, "type=movie", started.Filter)
+	assert.Equal(t, libraryCheckBatchSize+5, started.Total)
+
+	var job libraryCheckJobResponse
+	require.Eventually(t, func() bool {
+		w := do(http.MethodGet, fmt.Sprintf("/api/v1/library/check/%d", started.ID))
+		if w.Code != http.StatusOK {
+			return false
+		}
+		job = libraryCheckJobResponse{}
+		return json.Unmarshal(w.Body.Bytes(), &job) == nil && job.Status != "running"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "completed", job.Status)
+	assert.Equal(t, libraryCheckBatchSize+5, job.Checked)
+	assert.Equal(t, 53, job.WithIssues)
+	assert.Equal(t, 52, job.Healthy)
+	assert.Len(t, job.Items, libraryCheckBatchSize+5)
+	assert.NotEmpty(t, job.FinishedAt)
+
+	// The last scan stays viewable, with healthy items left out on request
+	w = do(http.MethodGet, "/api/v1/library/check/latest?issues_only=true&limit=10")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var latest libraryCheckJobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &latest))
+	assert.Equal(t, started.ID, latest.ID)
+	require.Len(t, latest.Items, 10)
+	for _, item := range latest.Items {
+		assert.Contains(t, item.Issues, "Status is 'available' but no files in database")
+	}
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/api/v1/library/check/999").Code)
+
+	// One check at a time
+	srv.checkMu.Lock()
+	srv.checkRunning = true
+	srv.checkMu.Unlock()
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/api/v1/library/check").Code)
+}
+
+func TestListIndexers_Success(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+
+	// Create mock indexers
+	indexers := []IndexerAPI{
+		&mockIndexer{name: "NZBgeek", url: "https://api.nzbgeek.info", priority: 10, keys: []newznab.KeyUsage{
+			{Key: "****abcd", Requests: 40, Exhausted: true, APICurrent: 40, APIMax: 40},
+			{Key: "****wxyz", Requests: 3, Grabs: 2, GrabCurrent: 7, GrabMax: 25},
+		}},
+		&mockIndexer{name: "DrunkenSlug", url: "https://api.drunkenslug.com", priority: 25, categories: []string{"series"}},
+	}
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Indexers:  indexers,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/indexers", nil)
+	w := httptest.NewRecorder()
+
+	srv.listIndexers(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp listIndexersResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Len(t, resp.Indexers, 2)
+	assert.Equal(t, "NZBgeek", resp.Indexers[0].Name)
+	assert.Equal(t, "https://api.nzbgeek.info", resp.Indexers[0].URL)
+	assert.Equal(t, 10, resp.Indexers[0].Priority)
+	assert.Empty(t, resp.Indexers[0].Categories)
+	assert.Equal(t, "DrunkenSlug", resp.Indexers[1].Name)
+	assert.Equal(t, "https://api.drunkenslug.com", resp.Indexers[1].URL)
+	assert.Equal(t, 25, resp.Indexers[1].Priority)
+	assert.Equal(t, []string{"series"}, resp.Indexers[1].Categories)
+
+	require.Len(t, resp.Indexers[0].Keys, 2)
+	assert.Equal(t, indexerKeyResponse{Key: "****abcd", Requests: 40, Exhausted: true, APICurrent: 40, APIMax: 40}, resp.Indexers[0].Keys[0])
+	assert.Equal(t, indexerKeyResponse{Key: "****wxyz", Requests: 3, Grabs: 2, GrabCurrent: 7, GrabMax: 25}, resp.Indexers[0].Keys[1])
+	assert.Empty(t, resp.Indexers[1].Keys)
+}
+
+func TestListIndexers_Empty(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	// No indexers configured (srv.deps.Indexers is nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/indexers", nil)
+	w := httptest.NewRecorder()
+
+	srv.listIndexers(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp listIndexersResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// Should return empty array, not 503
+	assert.Empty(t, resp.Indexers)
+}
+
+func TestRetryDownload_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	mockSearcher := mocks.NewMockSearcher(ctrl)
+	mockManager := mocks.NewMockDownloadManager(ctrl)
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Searcher:  mockSearcher,
+		Manager:   mockManager,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Try to retry a non-existent download
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/downloads/999/retry", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "NOT_FOUND", resp.Code)
+	assert.Equal(t, "Download not found", resp.Error)
+}
+func TestRetryDownload_SeasonPackStrategies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	mockSearcher := mocks.NewMockSearcher(ctrl)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+	grabbed := bus.Subscribe(events.EventGrabRequested, 10)
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Searcher:  mockSearcher,
+		Manager:   mocks.NewMockDownloadManager(ctrl),
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	series := fixtures.NewSeries("Severance", 2022).Insert(t, db)
+	aired := time.Now().AddDate(0, 0, -7)
+	ep1 := fixtures.NewEpisode(series.ID, 2, 1).WithAirDate(aired).Insert(t, db)
+	ep2 := fixtures.NewEpisode(series.ID, 2, 2).WithAirDate(aired).Insert(t, db)
+	fixtures.NewEpisode(series.ID, 2, 3).WithAirDate(aired).WithStatus(library.StatusAvailable).Insert(t, db)
+	dl := fixtures.NewDownload().ForContent(series.ID).ForSeason(2).WithStatus(download.StatusFailed).
+		WithReleaseName("Severance.S02.1080p.WEB-DL-DEAD").Insert(t, db)
+
+	retry := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/downloads/%d/retry?%s", dl.ID, query), nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	drain := func(n int) []*events.GrabRequested {
+		var out []*events.GrabRequested
+		for range n {
+			select {
+			case e := <-grabbed:
+				out = append(out, e.(*events.GrabRequested))
+			case <-time.After(time.Second):
+				t.Fatal("GrabRequested not published")
+			}
+		}
+		return out
+	}
+
+	// Auto: the only pack found is the one that failed, so the missing episodes are grabbed one by one
+	mockSearcher.EXPECT().Search(gomock.Any(), gomock.Any(), "hd").
+		DoAndReturn(func(_ context.Context, q search.Query, _ string) (*search.Result, error) {
+			if q.Episode == nil {
+				return &search.Result{Releases: []*search.Release{{Title: "Severance.S02.1080p.WEB-DL-DEAD", Score: 120}}}, nil
+			}
+			title := fmt.Sprintf("Severance.S02E%02d.1080p.WEB-DL-GRP", *q.Episode)
+			return &search.Result{Releases: []*search.Release{{Title: title, Indexer: "nzbgeek", Score: 90}}}, nil
+		}).Times(3)
+
+	w := retry("strategy=auto")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var resp retryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "episodes", resp.Strategy)
+	require.Len(t, resp.Grabs, 2, "episode 3 has a file")
+	assert.Equal(t, "Severance.S02E01.1080p.WEB-DL-GRP", resp.Grabs[0].ReleaseName)
+	assert.Equal(t, []int64{ep1.ID}, resp.Grabs[0].EpisodeIDs)
+	assert.Equal(t, []int64{ep2.ID}, resp.Grabs[1].EpisodeIDs)
+	assert.Equal(t, resp.Grabs[0].ReleaseName, resp.ReleaseName)
+	for _, grab := range drain(2) {
+		assert.False(t, grab.IsCompleteSeason)
+		require.Len(t, grab.EpisodeIDs, 1)
+		assert.Equal(t, search.StrategyEpisodes, grab.Decision.Strategy)
+	}
+
+	// Auto: a new pack scoring at least min_score is grabbed instead
+	mockSearcher.EXPECT().Search(gomock.Any(), gomock.Any(), "hd").
+		Return(&search.Result{Releases: []*search.Release{{Title: "Severance.S02.1080p.BluRay-GRP", Indexer: "nzbgeek", Score: 120}}}, nil)
+	w = retry("strategy=auto&min_score=100")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "season_pack", resp.Strategy)
+	require.Len(t, resp.Grabs, 1)
+	grab := drain(1)[0]
+	assert.True(t, grab.IsCompleteSeason)
+	assert.Equal(t, 2, *grab.Season)
+
+	// Unknown strategies are rejected
+	w = retry("strategy=bogus")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRetryPinnedGrab(t *testing.T) {
+	season := 2
+	pin := &library.PinnedRelease{GUID: "pin", DownloadURL: "http://nzb/pin", Title: "Show.S02.1080p", Season: &season}
+	series := &library.Content{ID: 7, Type: library.ContentTypeSeries, Pin: pin}
+
+	grab := retryPinnedGrab(&download.Download{GUID: "other", ReleaseName: "Show.S02.720p", Season: &season}, series)
+	require.NotNil(t, grab)
+	assert.True(t, grab.Pinned)
+	assert.True(t, grab.IsCompleteSeason)
+	assert.Equal(t, "http://nzb/pin", grab.DownloadURL)
+
+	one := 1
+	assert.Nil(t, retryPinnedGrab(&download.Download{GUID: "other", Season: &one}, series), "pin is for another season")
+	assert.Nil(t, retryPinnedGrab(&download.Download{GUID: "pin", Season: &season}, series), "the pin itself failed")
+}
+
+func TestRetryDownload_EpisodesRequiresSeasonPack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Searcher:  mocks.NewMockSearcher(ctrl),
+		Manager:   mocks.NewMockDownloadManager(ctrl),
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	movie := fixtures.NewMovie("Dune", 2021).Insert(t, db)
+	dl := fixtures.NewDownload().ForContent(movie.ID).WithStatus(download.StatusFailed).Insert(t, db)
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/downloads/%d/retry?strategy=episodes", dl.ID), nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_STRATEGY")
+}
+
+func TestLibraryImport_ValidationErrors(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:     "missing source",
+			body:     `{"library": "Movies"}`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  "source is required",
+		},
+		{
+			name:     "invalid source",
+			body:     `{"source": "invalid", "library": "Movies"}`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  "unsupported source",
+		},
+		{
+			name:     "missing library",
+			body:     `{"source": "plex"}`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  "library is required",
+		},
+		{
+			name:     "missing path",
+			body:     `{"source": "filesystem"}`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  "path is required",
+		},
+		{
+			name:     "relative path",
+			body:     `{"source": "filesystem", "path": "movies"}`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  "path must be absolute",
+		},
+		{
+			name:     "path not found",
+			body:     `{"source": "filesystem", "path": "/nonexistent/movies"}`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  "not a directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantErr)
+		})
+	}
+}
+
+func TestLibraryImport_PlexNotConfigured(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	srv.deps.Plex = nil // Ensure Plex is not configured
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := `{"source": "plex", "library": "Movies"}` //nolint:goconst
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "Plex not configured")
+}
+
+func TestLibraryImport_LibraryNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+
+	// Mock FindSectionByName to return nil (not found)
+	mockPlex.EXPECT().
+		FindSectionByName(gomock.Any(), "NonExistent").
+		Return(nil, nil)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := `{"source": "plex", "library": "NonExistent"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Plex library not found")
+}
+
+func TestLibraryImport_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+
+	// Mock FindSectionByName to return a section
+	mockPlex.EXPECT().
+		FindSectionByName(gomock.Any(), "Movies").
+		Return(&importer.Section{Key: "1", Title: "Movies", Type: "movie"}, nil)
+
+	// Mock ListLibraryItems to return some items
+	mockPlex.EXPECT().
+		ListLibraryItems(gomock.Any(), "1").
+		Return([]importer.PlexItem{
+			{Title: "Test Movie", Year: 2024, Type: "movie", FilePath: "/movies/Test.Movie.2024.mkv"},
+		}, nil)
+
+	// Mock TranslateToLocal (identity transform)
+	mockPlex.EXPECT().
+		TranslateToLocal("/movies/Test.Movie.2024.mkv").
+		Return("/movies/Test.Movie.2024.mkv")
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Use dry_run since we're just testing response structure (no real files)
+	body := `{"source": "plex", "library": "Movies", "dry_run": true}` //nolint:goconst
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp libraryImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	// Verify response structure has expected fields
+	assert.NotNil(t, resp.Imported)
+	assert.NotNil(t, resp.Skipped)
+	assert.NotNil(t, resp.Errors)
+}
+
+func TestLibraryImport_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+
+	// Mock Plex to return section and items
+	mockPlex.EXPECT().
+		FindSectionByName(gomock.Any(), "Movies").
+		Return(&importer.Section{Key: "1", Title: "Movies"}, nil)
+	mockPlex.EXPECT().
+		ListLibraryItems(gomock.Any(), "1").
+		Return([]importer.PlexItem{
+			{Title: "Test Movie", Year: 2024, Type: "movie", FilePath: "/data/media/movies/Test.Movie.2024.2160p.BluRay.mkv"},
+		}, nil)
+	mockPlex.EXPECT().
+		TranslateToLocal("/data/media/movies/Test.Movie.2024.2160p.BluRay.mkv").
+		Return("/srv/media/movies/Test.Movie.2024.2160p.BluRay.mkv")
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := `{"source": "plex", "library": "Movies", "dry_run": true}` //nolint:goconst
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp libraryImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// Dry run should show what would be imported
+	assert.Len(t, resp.Imported, 1)
+	assert.Equal(t, "Test Movie", resp.Imported[0].Title)
+	assert.Equal(t, 2024, resp.Imported[0].Year)
+	assert.Equal(t, "uhd", resp.Imported[0].Quality) // parsed from 2160p
+
+	// ContentID should be 0 because dry_run doesn't create records
+	assert.Zero(t, resp.Imported[0].ContentID)
+
+	// Summary should be correct
+	assert.Equal(t, 1, resp.Summary.Imported)
+	assert.Equal(t, 0, resp.Summary.Skipped)
+	assert.Equal(t, 0, resp.Summary.Errors)
+}
+
+func TestLibraryImport_SkipsAlreadyTracked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+
+	// Pre-create content that should be skipped
+	content := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Existing Movie",
+		Year:           2020,
+		Status:         library.StatusAvailable,
+		QualityProfile: "hd",
+	}
+	err := srv.deps.Library.AddContent(content)
+	require.NoError(t, err)
+
+	mockPlex.EXPECT().
+		FindSectionByName(gomock.Any(), "Movies").
+		Return(&importer.Section{Key: "1", Title: "Movies"}, nil)
+	mockPlex.EXPECT().
+		ListLibraryItems(gomock.Any(), "1").
+		Return([]importer.PlexItem{
+			{Title: "Existing Movie", Year: 2020, Type: "movie", FilePath: "/data/media/movies/file.mkv"},
+		}, nil)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := `{"source": "plex", "library": "Movies", "dry_run": true}` //nolint:goconst
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp libraryImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Empty(t, resp.Imported)
+	assert.Len(t, resp.Skipped, 1)
+	assert.Equal(t, "Existing Movie", resp.Skipped[0].Title)
+	assert.Equal(t, "already tracked", resp.Skipped[0].Reason)
+	assert.Equal(t, content.ID, resp.Skipped[0].ContentID)
+}
+
+func TestLibraryImport_CreatesRecords(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+
+	// Create a temp file for the test
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "New.Movie.2024.1080p.BluRay.mkv")
+	err := os.WriteFile(testFile, []byte("test content for size"), 0644)
+	require.NoError(t, err)
+
+	mockPlex.EXPECT().FindSectionByName(gomock.Any(), "Movies").Return(&importer.Section{Key: "1", Title: "Movies"}, nil)
+	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "1").Return([]importer.PlexItem{
+		{RatingKey: "321", Title: "New Movie", Year: 2024, Type: "movie", FilePath: "/data/media/movies/New.Movie.2024.1080p.BluRay.mkv"},
+	}, nil)
+	// TranslateToLocal is called three times: when checking path mappings, in processPlexImport
+	// for quality parsing, and in createImportedContent
+	mockPlex.EXPECT().TranslateToLocal("/data/media/movies/New.Movie.2024.1080p.BluRay.mkv").Return(testFile).Times(3)
+	mockPlex.EXPECT().PathMappings().Return([]importer.PathMapping{{Remote: "/data/media", Local: tmpDir}})
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := `{"source": "plex", "library": "Movies"}` //nolint:goconst
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp libraryImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Imported, 1)
+	assert.NotZero(t, resp.Imported[0].ContentID)
+
+	// Verify content was created
+	content, err := srv.deps.Library.GetContent(resp.Imported[0].ContentID)
+	require.NoError(t, err)
+	assert.Equal(t, "New Movie", content.Title)
+	assert.Equal(t, 2024, content.Year)
+	assert.Equal(t, library.StatusAvailable, content.Status)
+	assert.Equal(t, "hd", content.QualityProfile) // from 1080p
+	assert.Equal(t, "321", content.PlexKey)
+
+	// Verify file was created
+	files, _, err := srv.deps.Library.ListFiles(library.FileFilter{ContentID: &content.ID})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, testFile, files[0].Path)
+	assert.Equal(t, "1080p", files[0].Quality)
+	assert.Equal(t, "plex-import", files[0].Source)
+	assert.Equal(t, int64(21), files[0].SizeBytes) // "test content for size" is 21 bytes
+}
+
+func TestLibraryImport_FileStatError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+
+	mockPlex.EXPECT().FindSectionByName(gomock.Any(), "Movies").Return(&importer.Section{Key: "1", Title: "Movies"}, nil)
+	// One of two files is missing: too few to fail the path mapping check
+	presentFile := filepath.Join(t.TempDir(), "Present.mkv")
+	require.NoError(t, os.WriteFile(presentFile, []byte("x"), 0644))
+	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "1").Return([]importer.PlexItem{
+		{Title: "Missing File", Year: 2024, Type: "movie", FilePath: "/data/media/movies/Missing.mkv"},
+		{Title: "Present File", Year: 2024, Type: "movie", FilePath: "/data/media/movies/Present.mkv"},
+	}, nil)
+	mockPlex.EXPECT().PathMappings().Return(nil)
+	// Return a path that doesn't exist (for the path check, quality parsing, and content creation)
+	mockPlex.EXPECT().TranslateToLocal("/data/media/movies/Missing.mkv").Return("/nonexistent/path/Missing.mkv").Times(3)
+	mockPlex.EXPECT().TranslateToLocal("/data/media/movies/Present.mkv").Return(presentFile).Times(3)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := `{"source": "plex", "library": "Movies"}` //nolint:goconst
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp libraryImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// Should be in errors, not imported
+	assert.Len(t, resp.Imported, 1)
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, "Missing File", resp.Errors[0].Title)
+	assert.Contains(t, resp.Errors[0].Error, "cannot access file")
+}
+
+func TestLibraryImport_PathMappingFailsFast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+
+	var items []importer.PlexItem
+	for i := range 10 {
+		items = append(items, importer.PlexItem{Title: fmt.Sprintf("Movie %d", i), Year: 2024, Type: "movie",
+			FilePath: fmt.Sprintf("/data/movies/Movie.%d.mkv", i)})
+	}
+	mockPlex.EXPECT().FindSectionByName(gomock.Any(), "Movies").Return(&importer.Section{Key: "1", Title: "Movies"}, nil)
+	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "1").Return(items, nil)
+	mockPlex.EXPECT().PathMappings().Return([]importer.PathMapping{{Remote: "/data", Local: "/nonexistent"}}).AnyTimes()
+	mockPlex.EXPECT().TranslateToLocal(gomock.Any()).DoAndReturn(func(p string) string {
+		return "/nonexistent" + strings.TrimPrefix(p, "/data")
+	}).Times(10)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(`{"source": "plex", "library": "Movies"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var errResp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "PATH_MAPPING_FAILED", errResp.Code)
+	assert.Contains(t, errResp.Error, "10 of 10 sampled Plex paths do not exist locally")
+	assert.Contains(t, errResp.Error, "/data/movies/Movie.0.mkv -> /nonexistent/movies/Movie.0.mkv")
+
+	_, total, err := srv.deps.Library.ListContent(library.ContentFilter{})
+	require.NoError(t, err)
+	assert.Zero(t, total, "nothing imported")
+}
+
+func TestListPathMappings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	srv := New(testutil.OpenTestDB(t), Config{})
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+	mockPlex.EXPECT().PathMappings().Return([]importer.PathMapping{{Remote: "/data/media", Local: "/srv/media"}})
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/plex/path-mappings", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp pathMappingsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []pathMapping{{Remote: "/data/media", Local: "/srv/media"}}, resp.Mappings)
+}
+
+func TestValidatePathMappings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	srv := New(testutil.OpenTestDB(t), Config{})
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+
+	local := t.TempDir()
+	writeLibraryFile(t, local, "movies/Good.mkv", 1)
+	mapping := importer.PathMapping{Remote: "/data", Local: local}
+
+	mockPlex.EXPECT().GetSections(gomock.Any()).Return([]importer.Section{
+		{Key: "1", Title: "Movies"},
+		{Key: "2", Title: "TV Shows"},
+	}, nil)
+	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "1").Return([]importer.PlexItem{
+		{Title: "Good", Type: "movie", FilePath: "/data/movies/Good.mkv"},
+		{Title: "Gone", Type: "movie", FilePath: "/data/movies/Gone.mkv"},
+		{Title: "Elsewhere", Type: "movie", FilePath: "/mnt/other/Elsewhere.mkv"},
+	}, nil)
+	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "2").Return(nil, errors.New("timeout"))
+	mockPlex.EXPECT().PathMappings().Return([]importer.PathMapping{mapping})
+	mockPlex.EXPECT().TranslateToLocal(gomock.Any()).DoAndReturn(func(p string) string {
+		if strings.HasPrefix(p, "/data") {
+			return local + strings.TrimPrefix(p, "/data")
+		}
+		return p
+	}).Times(3)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/plex/path-mappings/validate", strings.NewReader(`{"sample": 5}`)))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp pathMappingValidateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Sampled)
+	assert.Equal(t, 1, resp.Resolved)
+	assert.Equal(t, []pathSampleLibrary{{Name: "Movies", Sampled: 3}, {Name: "TV Shows", Error: "timeout"}}, resp.Libraries)
+
+	require.Len(t, resp.Mappings, 2)
+	assert.Equal(t, "/data", resp.Mappings[0].Remote)
+	assert.Equal(t, 1, resp.Mappings[0].Hits)
+	assert.Equal(t, 1, resp.Mappings[0].Misses)
+	require.Len(t, resp.Mappings[0].Failing, 1)
+	assert.Equal(t, filepath.Join(local, "movies/Gone.mkv"), resp.Mappings[0].Failing[0].LocalPath)
+
+	// Paths outside every mapping are reported separately
+	assert.Empty(t, resp.Mappings[1].Remote)
+	assert.Equal(t, 0, resp.Mappings[1].Hits)
+	assert.Equal(t, 1, resp.Mappings[1].Misses)
+	assert.Equal(t, "/mnt/other/Elsewhere.mkv", resp.Mappings[1].Failing[0].PlexPath)
+}
+
+func TestPlexMissing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	mockPlex := mocks.NewMockPlexClient(ctrl)
+	srv.deps.Plex = mockPlex
+	store := library.NewStore(db)
+
+	local := t.TempDir()
+	addFile := func(contentType library.ContentType, title, rel string) *library.Content {
+		c := &library.Content{Type: contentType, Title: title, Year: 2020, Status: library.StatusAvailable, QualityProfile: "hd", RootPath: local}
+		require.NoError(t, store.AddContent(c))
+		path := writeLibraryFile(t, local, rel, 10)
+		require.NoError(t, store.AddFile(&library.File{ContentID: c.ID, Path: path, Quality: "1080p"}))
+		return c
+	}
+	addFile(library.ContentTypeMovie, "Heat", "movies/Heat.mkv")
+	alien := addFile(library.ContentTypeMovie, "Alien", "movies/Alien.mkv")
+	require.NoError(t, os.Chmod(filepath.Join(local, "movies/Alien.mkv"), 0o640))
+	addFile(library.ContentTypeMovie, "Dune", "movies/Dune.mkv")
+	addFile(library.ContentTypeSeries, "The Wire", "tv/The Wire/Season 01/The.Wire.S01E01.mkv")
+	lost := addFile(library.ContentTypeSeries, "Lost", "tv/Lost/Season 01/Lost.S01E01.mkv")
+	addFile(library.ContentTypeMovie, "Stray", "other/Stray.mkv")
+
+	sections := []importer.Section{
+		{Key: "1", Title: "Movies", Type: "movie", Locations: []importer.Location{{Path: "/data/movies"}}},
+		{Key: "2", Title: "TV Shows", Type: "show", Locations: []importer.Location{{Path: "/data/tv"}}},
+		{Key: "3", Title: "Music", Type: "artist", Locations: []importer.Location{{Path: "/data/music"}}},
+	}
+	mockPlex.EXPECT().GetSections(gomock.Any()).Return(sections, nil).Times(2)
+	mockPlex.EXPECT().TranslateToLocal(gomock.Any()).DoAndReturn(func(p string) string {
+		return local + strings.TrimPrefix(p, "/data")
+	}).AnyTimes()
+	// Each section is listed once per report, never searched per item
+	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "1").Return([]importer.PlexItem{
+		{RatingKey: "10", Title: "Heat", Type: "movie", FilePath: "/data/movies/Heat.mkv"},
+		{RatingKey: "11", Title: "Ghost", Type: "movie", FilePath: "/data/movies/Ghost.mkv"},
+	}, nil)
+	tvItems := []importer.PlexItem{
+		{RatingKey: "20", Title: "The Wire", Type: "show", Locations: []string{"/data/tv/The Wire"}},
+		{RatingKey: "21", Title: "Fargo", Type: "show", Locations: []string{"/data/tv/Fargo"}},
+	}
+	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "2").Return(tvItems, nil).Times(2)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/plex/missing", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp plexMissingResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 6, resp.Summary.Files)
+	require.Len(t, resp.Sections, 2)
+	assert.Equal(t, "Movies", resp.Sections[0].Name)
+	assert.Equal(t, 3, resp.Sections[0].Files)
+	assert.Equal(t, 2, resp.Sections[0].Missing)
+	assert.Equal(t, 1, resp.Sections[1].Missing)
+
+	missing := make(map[string]plexMissingFile)
+	for _, m := range resp.Missing {
+		missing[m.Title] = m
+	}
+	require.Len(t, missing, 4)
+	assert.Equal(t, []string{`arrgo plex scan "Movies"`}, missing["Dune"].Fixes)
+	assert.Equal(t, "-rw-r-----", missing["Alien"].Mode)
+	assert.NotEmpty(t, missing["Alien"].Owner)
+	require.Len(t, missing["Alien"].Fixes, 2)
+	assert.Contains(t, missing["Alien"].Fixes[0], "check permissions")
+	assert.Equal(t, "TV Shows", missing["Lost"].Section)
+	assert.Empty(t, missing["Stray"].Section)
+	assert.Contains(t, missing["Stray"].Fixes[0], "to a Plex library")
+
+	require.Len(t, resp.Untracked, 2)
+	assert.Equal(t, "Ghost", resp.Untracked[0].Title)
+	assert.Equal(t, filepath.Join(local, "movies/Ghost.mkv"), resp.Untracked[0].Path)
+	assert.Equal(t, "Fargo", resp.Untracked[1].Title)
+	assert.Equal(t, `arrgo library import --from-plex "TV Shows"`, resp.Untracked[1].Fix)
+
+	// Scoped to one series, only its section is listed
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/plex/missing?content_id=%d", lost.ID), nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	resp = plexMissingResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Sections, 1)
+	require.Len(t, resp.Missing, 1)
+	assert.Equal(t, lost.ID, resp.Missing[0].ContentID)
+	assert.Empty(t, resp.Untracked)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/plex/missing?content_id=%d", alien.ID+100), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// writeLibraryFile creates a file of the given size under root, creating parent folders.
+func writeLibraryFile(t *testing.T, root, rel string, size int) string {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	return path
+}
+
+func TestLibraryImport_Filesystem(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	store := library.NewStore(db)
+
+	root := t.TempDir()
+	moviePath := writeLibraryFile(t, root, "The Matrix (1999)/The.Matrix.1999.2160p.UHD.BluRay.mkv", 1000)
+	writeLibraryFile(t, root, "The Matrix (1999)/The.Matrix.1999.sample.mkv", 10)
+	writeLibraryFile(t, root, "Breaking Bad (2008)/Season 01/Breaking.Bad.S01E01.720p.mkv", 200)
+	writeLibraryFile(t, root, "Breaking Bad (2008)/Season 01/Breaking.Bad.S01E02.720p.mkv", 200)
+	writeLibraryFile(t, root, "Heat/Heat.mkv", 100)
+	writeLibraryFile(t, root, "Alien (1979)/Alien.1979.1080p.mkv", 100)
+
+	existing := &library.Content{Type: library.ContentTypeMovie, Title: "Alien", Year: 1979, Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(existing))
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := fmt.Sprintf(`{"source": "filesystem", "path": %q}`, root)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp libraryImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	require.Len(t, resp.Imported, 2)
+	series, movie := resp.Imported[0], resp.Imported[1]
+	assert.Equal(t, "Breaking Bad", series.Title)
+	assert.Equal(t, "series", series.Type)
+	assert.Equal(t, 2, series.Episodes)
+	assert.Equal(t, "hd720", series.Quality)
+	assert.Equal(t, "The Matrix", movie.Title)
+	assert.Equal(t, 1999, movie.Year)
+	assert.Equal(t, "uhd", movie.Quality)
+
+	require.Len(t, resp.Skipped, 1)
+	assert.Equal(t, existing.ID, resp.Skipped[0].ContentID)
+	assert.Equal(t, "already tracked", resp.Skipped[0].Reason)
+
+	require.Len(t, resp.NeedsReview, 1)
+	assert.Equal(t, "Heat", resp.NeedsReview[0].Title)
+	assert.Equal(t, filepath.Join(root, "Heat"), resp.NeedsReview[0].Path)
+	assert.NotEmpty(t, resp.NeedsReview[0].Reason)
+
+	assert.Equal(t, 2, resp.Summary.Imported)
+	assert.Equal(t, 1, resp.Summary.Skipped)
+	assert.Equal(t, 0, resp.Summary.Errors)
+	assert.Equal(t, 1, resp.Summary.NeedsReview)
+
+	// Movie content and its main file
+	content, err := store.GetContent(movie.ContentID)
+	require.NoError(t, err)
+	assert.Equal(t, library.StatusAvailable, content.Status)
+	assert.Equal(t, root, content.RootPath)
+	files, _, err := store.ListFiles(library.FileFilter{ContentID: &movie.ContentID})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, moviePath, files[0].Path)
+	assert.Equal(t, int64(1000), files[0].SizeBytes)
+	assert.Equal(t, "filesystem-import", files[0].Source)
+
+	// Series content with an episode and file per episode file
+	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &series.ContentID})
+	require.NoError(t, err)
+	require.Len(t, episodes, 2)
+	for _, ep := range episodes {
+		assert.Equal(t, 1, ep.Season)
+		assert.Equal(t, library.StatusAvailable, ep.Status)
+	}
+	files, _, err = store.ListFiles(library.FileFilter{ContentID: &series.ContentID})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		assert.NotNil(t, f.EpisodeID)
+	}
+}
+
+func TestLibraryImport_FilesystemDryRun(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	root := t.TempDir()
+	writeLibraryFile(t, root, "The Matrix (1999)/The.Matrix.1999.1080p.mkv", 100)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := fmt.Sprintf(`{"source": "filesystem", "path": %q, "dry_run": true, "quality_override": "uhd"}`, root)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp libraryImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Imported, 1)
+	assert.Equal(t, "uhd", resp.Imported[0].Quality)
+	assert.Zero(t, resp.Imported[0].ContentID)
+
+	contents, total, err := library.NewStore(db).ListContent(library.ContentFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, contents)
+	assert.Zero(t, total)
+}
+
+func TestLibraryImport_Radarr(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	store := library.NewStore(db)
+
+	// Already tracked by TMDB ID under another title
+	tmdbID := int64(27205)
+	existing := &library.Content{Type: library.ContentTypeMovie, TMDBID: &tmdbID, Title: "Inception (Extended)", Year: 2010, Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(existing))
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	path, err := filepath.Abs("../../importer/testdata/radarr_v4.db")
+	require.NoError(t, err)
+	body := fmt.Sprintf(`{"source": "radarr", "path": %q, "quality_map": {"HD-1080p": "hd720"}}`, path)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp libraryImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Imported, 2)
+	matrix, interstellar := resp.Imported[0], resp.Imported[1]
+	assert.Equal(t, "The Matrix", matrix.Title)
+	assert.Equal(t, "hd720", matrix.Quality, "mapped by profile name")
+	assert.Equal(t, "Interstellar", interstellar.Title)
+	assert.Equal(t, "uhd", interstellar.Quality, "unmapped profile falls back to the file's resolution")
+	require.Len(t, resp.Skipped, 1)
+	assert.Equal(t, existing.ID, resp.Skipped[0].ContentID)
+	assert.Equal(t, 2, resp.Summary.Imported)
+	assert.Equal(t, 1, resp.Summary.Skipped)
+
+	content, err := store.GetContent(matrix.ContentID)
+	require.NoError(t, err)
+	require.NotNil(t, content.TMDBID)
+	assert.Equal(t, int64(603), *content.TMDBID)
+	assert.Equal(t, library.StatusAvailable, content.Status)
+	assert.Equal(t, "/movies", content.RootPath)
+	files, _, err := store.ListFiles(library.FileFilter{ContentID: &matrix.ContentID})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "/movies/The Matrix (1999)/The.Matrix.1999.1080p.BluRay.x264-GRP.mkv", files[0].Path)
+	assert.Equal(t, "1080p", files[0].Quality)
+	assert.Equal(t, "radarr-import", files[0].Source)
+	assert.Equal(t, "GRP", files[0].ReleaseGroup)
+
+	// Unmonitored in Radarr, so not searched for
+	content, err = store.GetContent(interstellar.ContentID)
+	require.NoError(t, err)
+	assert.Equal(t, library.StatusUnmonitored, content.Status)
+
+	// Importing again skips everything
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Imported)
+	assert.Len(t, resp.Skipped, 3)
+}
+
+func TestLibraryImport_Sonarr(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	store := library.NewStore(db)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	path, err := filepath.Abs("../../importer/testdata/sonarr_v4.db")
+	require.NoError(t, err)
+	body := fmt.Sprintf(`{"source": "sonarr", "path": %q, "quality_map": {"4": "uhd"}}`, path)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp libraryImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Imported, 2)
+	bb, daily := resp.Imported[0], resp.Imported[1]
+	assert.Equal(t, "Breaking Bad", bb.Title)
+	assert.Equal(t, "uhd", bb.Quality, "mapped by profile ID")
+	assert.Equal(t, 5, bb.Episodes)
+	assert.Equal(t, "hd", daily.Quality, "no mapping and no files")
+
+	content, err := store.GetContent(bb.ContentID)
+	require.NoError(t, err)
+	require.NotNil(t, content.TVDBID)
+	assert.Equal(t, int64(81189), *content.TVDBID)
+	assert.Equal(t, library.StatusAvailable, content.Status)
+	assert.Equal(t, "/tv", content.RootPath)
+
+	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &bb.ContentID})
+	require.NoError(t, err)
+	require.Len(t, episodes, 5)
+	status := make(map[string]library.ContentStatus)
+	for _, ep := range episodes {
+		status[fmt.Sprintf("S%02dE%02d", ep.Season, ep.Episode)] = ep.Status
+	}
+	assert.Equal(t, map[string]library.ContentStatus{
+		"S01E01": library.StatusAvailable,
+		"S01E02": library.StatusAvailable,
+		"S01E03": library.StatusAvailable,
+		"S01E04": library.StatusWanted,
+		"S02E01": library.StatusUnmonitored,
+	}, status)
+
+	// The S01E02-E03 file is recorded once
+	files, _, err := store.ListFiles(library.FileFilter{ContentID: &bb.ContentID})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		assert.NotNil(t, f.EpisodeID)
+		assert.Equal(t, "sonarr-import", f.Source)
+	}
+
+	seasons, err := store.ListSeasons(bb.ContentID)
+	require.NoError(t, err)
+	monitored := make(map[int]bool)
+	for _, s := range seasons {
+		monitored[s.Season] = s.Monitored
+	}
+	assert.Equal(t, map[int]bool{1: true, 2: false}, monitored)
+
+	content, err = store.GetContent(daily.ContentID)
+	require.NoError(t, err)
+	assert.True(t, content.Daily)
+	assert.Equal(t, library.StatusWanted, content.Status)
+}
+
+func TestLibraryImport_ArrErrors(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Mapped profiles are checked once any are defined
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/profiles", strings.NewReader(`{"name": "hd", "settings": {}}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	radarr, err := filepath.Abs("../../importer/testdata/radarr_v4.db")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		body string
+		code int
+		err  string
+	}{
+		{"missing path", `{"source": "radarr"}`, http.StatusBadRequest, "MISSING_PATH"},
+		{"relative path", `{"source": "radarr", "path": "radarr.db"}`, http.StatusBadRequest, "INVALID_PATH"},
+		{"missing file", fmt.Sprintf(`{"source": "radarr", "path": %q}`, filepath.Join(t.TempDir(), "radarr.db")), http.StatusBadRequest, "INVALID_PATH"},
+		{"unknown mapped profile", fmt.Sprintf(`{"source": "radarr", "path": %q, "quality_map": {"4": "nope"}}`, radarr), http.StatusBadRequest, "INVALID_PROFILE"},
+		{"wrong database", fmt.Sprintf(`{"source": "sonarr", "path": %q}`, radarr), http.StatusUnprocessableEntity, "INVALID_DATABASE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			require.Equal(t, tt.code, w.Code, w.Body.String())
+			var resp errorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.err, resp.Code)
+		})
+	}
+}
+
+func TestGrab_ContentNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(ctrl)
+
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Grab with non-existent content_id
+	body := `{"content_id":999,"download_url":"http://example.com/nzb","title":"Test","indexer":"TestIndexer"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "NOT_FOUND", resp.Code)
+}
+
+func TestGrab_MissingRequiredFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(ctrl)
+
+	// Add content so content_id validation passes
+	store := library.NewStore(db)
+	c := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test Movie",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, store.AddContent(c))
+
+	deps := ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			name:    "missing content_id",
+			body:    `{"download_url":"http://example.com/nzb","title":"Test","indexer":"TestIndexer"}`,
+			wantErr: "content_id",
+		},
+		{
+			name:    "missing download_url",
+			body:    `{"content_id":1,"title":"Test","indexer":"TestIndexer"}`,
+			wantErr: "download_url",
+		},
+		{
+			name:    "missing title",
+			body:    `{"content_id":1,"download_url":"http://example.com/nzb","indexer":"TestIndexer"}`,
+			wantErr: "title",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantErr)
+		})
+	}
+}
+
+func TestGrab_SeriesWithEpisodeDetection(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+	mockManager.EXPECT().Route(gomock.Any(), gomock.Any()).Return(download.Client("sabnzbd"), nil, nil)
+
+	// Create event bus to capture published events
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	// Subscribe to capture events
+	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+
+	// Add series content
+	store := library.NewStore(db)
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Breaking Bad",
+		Year:           2008,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, store.AddContent(series))
+
+	deps := ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Grab with release name that contains episode info
+	body := fmt.Sprintf(`{
+		"content_id": %d,
+		"download_url": "http://example.com/nzb",
+		"title": "Breaking.Bad.S05E12.1080p.BluRay.x264-DEMAND",
+		"indexer": "NZBgeek"
+	}`, series.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+
+	// Verify the event was published
+	select {
+	case evt := <-eventCh:
+		grabEvt, ok := evt.(*events.GrabRequested)
+		require.True(t, ok, "expected GrabRequested event")
+		assert.Equal(t, series.ID, grabEvt.ContentID)
+		assert.NotNil(t, grabEvt.Season)
+		assert.Equal(t, 5, *grabEvt.Season)
+		assert.Len(t, grabEvt.EpisodeIDs, 1)
+		assert.NotNil(t, grabEvt.EpisodeID) // Backward compatibility
+		assert.False(t, grabEvt.IsCompleteSeason)
+	default:
+		t.Fatal("expected event to be published")
+	}
+
+	// Verify episode was created in database
+	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	assert.Len(t, episodes, 1)
+	assert.Equal(t, 5, episodes[0].Season)
+	assert.Equal(t, 12, episodes[0].Episode)
+}
+
+func TestGrab_SeasonPack(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+	mockManager.EXPECT().Route(gomock.Any(), gomock.Any()).Return(download.Client("sabnzbd"), nil, nil)
+
+	// Create event bus
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+
+	// Add series content
+	store := library.NewStore(db)
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Peaky Blinders",
+		Year:           2013,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, store.AddContent(series))
+
+	deps := ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Grab a season pack
+	body := fmt.Sprintf(`{
+		"content_id": %d,
+		"download_url": "http://example.com/nzb",
+		"title": "Peaky.Blinders.S01.1080p.BluRay.x264-DEMAND",
+		"indexer": "NZBgeek"
+	}`, series.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+
+	// Verify the event
+	select {
+	case evt := <-eventCh:
+		grabEvt, ok := evt.(*events.GrabRequested)
+		require.True(t, ok, "expected GrabRequested event")
+		assert.Equal(t, series.ID, grabEvt.ContentID)
+		assert.NotNil(t, grabEvt.Season)
+		assert.Equal(t, 1, *grabEvt.Season)
+		assert.True(t, grabEvt.IsCompleteSeason)
+		assert.Empty(t, grabEvt.EpisodeIDs, "season pack should not have episode IDs yet")
+	default:
+		t.Fatal("expected event to be published")
+	}
+}
+
+func TestGrab_SeriesNoEpisodeInfo(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+
+	// Create event bus
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	// Add series content
+	store := library.NewStore(db)
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Game of Thrones",
+		Year:           2011,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, store.AddContent(series))
+
+	deps := ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Grab without season/episode info (should fail)
+	body := fmt.Sprintf(`{
+		"content_id": %d,
+		"download_url": "http://example.com/nzb",
+		"title": "Game.of.Thrones.1080p.BluRay.x264-DEMAND",
+		"indexer": "NZBgeek"
+	}`, series.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "INVALID_RELEASE", resp.Code)
+	assert.Contains(t, resp.Error, "cannot determine season")
+}
+
+func TestGrab_SeriesWithOverrides(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+	mockManager.EXPECT().Route(gomock.Any(), gomock.Any()).Return(download.Client("sabnzbd"), nil, nil)
+
+	// Create event bus
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+
+	// Add series content
+	store := library.NewStore(db)
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "The Mandalorian",
+		Year:           2019,
+		Status:         library.StatusWanted,
+		QualityProfile: "uhd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, store.AddContent(series))
+
+	deps := ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Grab with overrides (ignoring the release name parsing)
+	body := fmt.Sprintf(`{
+		"content_id": %d,
+		"download_url": "http://example.com/nzb",
+		"title": "The.Mandalorian.2160p.WEB-DL.DDP5.1.Atmos.HDR.x265",
+		"indexer": "NZBgeek",
+		"season": 2,
+		"episodes": [1, 2, 3]
+	}`, series.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+
+	// Verify the event uses overrides
+	select {
+	case evt := <-eventCh:
+		grabEvt, ok := evt.(*events.GrabRequested)
+		require.True(t, ok, "expected GrabRequested event")
+		assert.NotNil(t, grabEvt.Season)
+		assert.Equal(t, 2, *grabEvt.Season)
+		assert.Len(t, grabEvt.EpisodeIDs, 3)
+		assert.False(t, grabEvt.IsCompleteSeason)
+	default:
+		t.Fatal("expected event to be published")
+	}
+
+	// Verify episodes were created
+	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	assert.Len(t, episodes, 3)
+}
+
+func TestGrab_MovieIgnoresEpisodeDetection(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+	mockManager.EXPECT().Route(gomock.Any(), gomock.Any()).Return(download.Client("sabnzbd"), nil, nil)
+
+	// Create event bus
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+
+	// Add movie content
+	store := library.NewStore(db)
+	movie := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Inception",
+		Year:           2010,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, store.AddContent(movie))
+
+	deps := ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Grab movie (should not trigger episode detection)
+	body := fmt.Sprintf(`{
+		"content_id": %d,
+		"download_url": "http://example.com/nzb",
+		"title": "Inception.2010.1080p.BluRay.x264-DEMAND",
+		"indexer": "NZBgeek"
+	}`, movie.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+
+	// Verify the event has no episode info
+	select {
+	case evt := <-eventCh:
+		grabEvt, ok := evt.(*events.GrabRequested)
+		require.True(t, ok, "expected GrabRequested event")
+		assert.Nil(t, grabEvt.Season)
+		assert.Nil(t, grabEvt.EpisodeID)
+		assert.Empty(t, grabEvt.EpisodeIDs)
+		assert.False(t, grabEvt.IsCompleteSeason)
+	default:
+		t.Fatal("expected event to be published")
+	}
+}
+
+func TestGrab_MultiEpisodeRelease(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+	mockManager.EXPECT().Route(gomock.Any(), gomock.Any()).Return(download.Client("sabnzbd"), nil, nil)
+
+	// Create event bus
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+
+	// Add series content
+	store := library.NewStore(db)
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "House of the Dragon",
+		Year:           2022,
+		Status:         library.StatusWanted,
+		QualityProfile: "uhd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, store.AddContent(series))
+
+	deps := ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// Grab multi-episode release (e.g., S01E01-E03)
+	body := fmt.Sprintf(`{
+		"content_id": %d,
+		"download_url": "http://example.com/nzb",
+		"title": "House.of.the.Dragon.S01E01-E03.2160p.HMAX.WEB-DL.x265",
+		"indexer": "NZBgeek"
+	}`, series.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+
+	// Verify the event
+	select {
+	case evt := <-eventCh:
+		grabEvt, ok := evt.(*events.GrabRequested)
+		require.True(t, ok, "expected GrabRequested event")
+		assert.NotNil(t, grabEvt.Season)
+		assert.Equal(t, 1, *grabEvt.Season)
+		assert.Len(t, grabEvt.EpisodeIDs, 3, "should have 3 episode IDs for E01-E03")
+		assert.Nil(t, grabEvt.EpisodeID, "EpisodeID should be nil for multi-episode")
+		assert.False(t, grabEvt.IsCompleteSeason)
+	default:
+		t.Fatal("expected event to be published")
+	}
+
+	// Verify episodes were created
+	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	assert.Len(t, episodes, 3)
+}
+
+func TestGrab_DailyRelease(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+	mockManager.EXPECT().Route(gomock.Any(), gomock.Any()).Return(download.Client("sabnzbd"), nil, nil).AnyTimes()
+
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+
+	store := library.NewStore(db)
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "The Daily Show",
+		Year:           1996,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+		Daily:          true,
+	}
+	require.NoError(t, store.AddContent(series))
+
+	deps := ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := fmt.Sprintf(`{
+		"content_id": %d,
+		"download_url": "http://example.com/nzb",
+		"title": "The.Daily.Show.2024.03.14.Guest.Name.1080p.WEB"
+	}`, series.ID)
+
+	// A dry run reports the air date without creating the episode
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab?dry_run=true", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	var plan grabPlanResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+	assert.Equal(t, "2024-03-14", plan.AirDate)
+	require.NotNil(t, plan.Season)
+	assert.Equal(t, 2024, *plan.Season)
+	assert.Empty(t, plan.EpisodeIDs)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+
+	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	require.Len(t, episodes, 1)
+	assert.Equal(t, 2024, episodes[0].Season)
+	require.NotNil(t, episodes[0].AirDate)
+	assert.Equal(t, "2024-03-14", episodes[0].AirDate.Format(time.DateOnly))
+
+	select {
+	case evt := <-eventCh:
+		grabEvt, ok := evt.(*events.GrabRequested)
+		require.True(t, ok, "expected GrabRequested event")
+		require.NotNil(t, grabEvt.Season)
+		assert.Equal(t, 2024, *grabEvt.Season)
+		assert.Equal(t, []int64{episodes[0].ID}, grabEvt.EpisodeIDs)
+	default:
+		t.Fatal("expected event to be published")
+	}
+}
+
+func TestResolveGrabTarget(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	tests := []struct {
+		name         string
+		contentType  library.ContentType
+		daily        bool
+		req          grabRequest
+		wantSeason   *int
+		wantEpisodes []int
+		wantPack     bool
+		wantAirDate  string
+		wantErr      string
+	}{
+		{
+			name:        "movie",
+			contentType: library.ContentTypeMovie,
+			req:         grabRequest{Title: "Dune.2021.1080p.BluRay.x264-GROUP"},
+		},
+		{
+			name:         "single episode",
+			contentType:  library.ContentTypeSeries,
+			req:          grabRequest{Title: "Breaking.Bad.S05E12.1080p.BluRay.x264-DEMAND"},
+			wantSeason:   intPtr(5),
+			wantEpisodes: []int{12},
+		},
+		{
+			name:        "season pack",
+			contentType: library.ContentTypeSeries,
+			req:         grabRequest{Title: "Breaking.Bad.S03.1080p.BluRay.x264-DEMAND"},
+			wantSeason:  intPtr(3),
+			wantPack:    true,
+		},
+		{
+			name:         "overrides",
+			contentType:  library.ContentTypeSeries,
+			req:          grabRequest{Title: "Some.Show.1080p.WEB-DL", Season: intPtr(2), Episodes: []int{4, 5}},
+			wantSeason:   intPtr(2),
+			wantEpisodes: []int{4, 5},
+		},
+		{
+			name:        "no season",
+			contentType: library.ContentTypeSeries,
+			req:         grabRequest{Title: "Some.Show.1080p.WEB-DL"},
+			wantErr:     "cannot determine season from release title",
+		},
+		{
+			name:        "no episodes",
+			contentType: library.ContentTypeSeries,
+			req:         grabRequest{Title: "Some.Show.1080p.WEB-DL", Season: intPtr(2)},
+			wantErr:     "cannot determine episodes from release title",
+		},
+		{
+			name:        "dated release",
+			contentType: library.ContentTypeSeries,
+			req:         grabRequest{Title: "The.Daily.Show.2024.03.14.Guest.Name.1080p.WEB"},
+			wantAirDate: "2024-03-14",
+		},
+		{
+			name:        "dated release of daily series",
+			contentType: library.ContentTypeSeries,
+			daily:       true,
+			req:         grabRequest{Title: "The.Daily.Show.2024-03-14.1080p.WEB"},
+			wantAirDate: "2024-03-14",
+		},
+		{
+			name:         "dated release with overrides",
+			contentType:  library.ContentTypeSeries,
+			daily:        true,
+			req:          grabRequest{Title: "The.Daily.Show.2024.03.14.1080p.WEB", Season: intPtr(29), Episodes: []int{30}},
+			wantSeason:   intPtr(29),
+			wantEpisodes: []int{30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := resolveGrabTarget(&library.Content{Type: tt.contentType, Daily: tt.daily}, &tt.req)
+			if tt.wantErr != "" {
+				var ge *grabError
+				require.ErrorAs(t, err, &ge)
+				assert.Equal(t, http.StatusBadRequest, ge.status)
+				assert.Equal(t, "INVALID_RELEASE", ge.code)
+				assert.Equal(t, tt.wantErr, ge.message)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSeason, target.Season)
+			assert.Equal(t, tt.wantEpisodes, target.Episodes)
+			assert.Equal(t, tt.wantPack, target.IsCompleteSeason)
+			if tt.wantAirDate == "" {
+				assert.Nil(t, target.AirDate)
+			} else {
+				require.NotNil(t, target.AirDate)
+				assert.Equal(t, tt.wantAirDate, target.AirDate.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestGrab_DryRun(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+	mockManager.EXPECT().Route("http://example.com/nzb", download.Protocol("")).Return(download.Client("sabnzbd"), nil, nil)
+
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+
+	store := library.NewStore(db)
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Breaking Bad",
+		Year:           2008,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, store.AddContent(series))
+	existing := &library.Episode{ContentID: series.ID, Season: 1, Episode: 1, Status: library.StatusWanted}
+	require.NoError(t, store.AddEpisode(existing))
+
+	deps := ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mockManager,
+		Bus:       bus,
+	}
+	srv, err := NewWithDeps(deps, Config{
+		Categories: map[download.Client]download.Categories{
+			"sabnzbd": {Default: "arrgo", Series: "tv"},
+		},
+	})
+	require.NoError(t, err)
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/status", nil)
+	body := fmt.Sprintf(`{
+		"content_id": %d,
+		"download_url": "http://example.com/nzb",
+		"title": "Breaking.Bad.S01E01-E02.1080p.BluRay.x264-DEMAND",
+		"indexer": "NZBgeek",
+		"guid": "abc123"
+	}`, series.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab?dry_run=true", strings.NewReader(body))
 	w := httptest.NewRecorder()
-
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
-
-	var resp plexStatusResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-
-	assert.False(t, resp.Connected)
-	assert.Empty(t, resp.ServerName)
-	assert.Empty(t, resp.Version)
-	assert.Empty(t, resp.Libraries)
-	assert.Equal(t, "Plex not configured", resp.Error)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+
+	var resp grabPlanResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, series.ID, resp.ContentID)
+	assert.Equal(t, "series", resp.ContentType)
+	require.NotNil(t, resp.Season)
+	assert.Equal(t, 1, *resp.Season)
+	assert.Equal(t, []int{1, 2}, resp.Episodes)
+	assert.Equal(t, []int64{existing.ID}, resp.EpisodeIDs)
+	assert.Equal(t, []int{2}, resp.NewEpisodes)
+	assert.Equal(t, "sabnzbd", resp.Client)
+	assert.Equal(t, "tv", resp.Category)
+	assert.Equal(t, "usenet", resp.Protocol)
+	assert.False(t, resp.Blocklisted)
+
+	// Nothing published, nothing created
+	select {
+	case evt := <-eventCh:
+		t.Fatalf("unexpected event published: %v", evt)
+	default:
+	}
+	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	assert.Len(t, episodes, 1)
 }
 
-func TestScanPlexLibraries_Success(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	db := setupTestDB(t)
-	mockPlex := mocks.NewMockPlexClient(ctrl)
-
-	// Setup mock expectations
-	mockPlex.EXPECT().
-		GetSections(gomock.Any()).
-		Return([]importer.Section{
-			{Key: "1", Title: "Movies", Type: "movie"},
-			{Key: "2", Title: "TV Shows", Type: "show"},
-		}, nil)
+func TestGrab_DryRunWithoutBus(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+	mockManager.EXPECT().Route(gomock.Any(), gomock.Any()).Return(download.Client(""), nil, errors.New("no download client for protocol torrent"))
 
-	// Scan both libraries
-	mockPlex.EXPECT().
-		RefreshLibrary(gomock.Any(), "1").
-		Return(nil)
-	mockPlex.EXPECT().
-		RefreshLibrary(gomock.Any(), "2").
-		Return(nil)
+	store := library.NewStore(db)
+	movie := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Dune",
+		Year:           2021,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, store.AddContent(movie))
 
 	deps := ServerDeps{
-		Library:   library.NewStore(db),
+		Library:   store,
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Plex:      mockPlex,
+		Manager:   mockManager,
 	}
 	srv, err := NewWithDeps(deps, Config{})
 	require.NoError(t, err)
@@ -1036,199 +5910,245 @@ func TestScanPlexLibraries_Success(t *testing.T) {
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	// Request to scan all libraries (empty array)
-	body := `{"libraries":[]}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/plex/scan", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	body := fmt.Sprintf(`{"content_id": %d, "download_url": "magnet:?xt=urn:btih:abc", "title": "Dune.2021.1080p.BluRay.x264-GROUP"}`, movie.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab?dry_run=true", strings.NewReader(body))
 	w := httptest.NewRecorder()
-
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var resp plexScanResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
 
-	assert.Len(t, resp.Scanned, 2)
-	assert.Contains(t, resp.Scanned, "Movies")
-	assert.Contains(t, resp.Scanned, "TV Shows")
+	var resp grabPlanResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "movie", resp.ContentType)
+	assert.Nil(t, resp.Season)
+	assert.Empty(t, resp.Client)
+	assert.Equal(t, "no download client for protocol torrent", resp.ClientError)
 }
 
-func TestScanPlexLibraries_NoPlex(t *testing.T) {
-	db := setupTestDB(t)
-	srv := New(db, Config{})
+func TestGrab_TorrentWithoutTorrentClient(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mgr := download.NewManager(download.NewStore(db), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mgr.AddClient("sabnzbd", download.ProtocolUsenet, downloadmocks.NewMockDownloader(gomock.NewController(t)))
+
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+
+	store := library.NewStore(db)
+	movie := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Dune",
+		Year:           2021,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, store.AddContent(movie))
+
+	srv, err := NewWithDeps(ServerDeps{
+		Library:   store,
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Manager:   mgr,
+		Bus:       bus,
+	}, Config{})
+	require.NoError(t, err)
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	body := `{"libraries":[]}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/plex/scan", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	body := fmt.Sprintf(`{"content_id": %d, "download_url": "http://jackett/dl/1", "title": "Dune.2021.1080p.BluRay.x264-GROUP", "protocol": "torrent"}`, movie.ID)
 	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body)))
 
-	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp errorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "NO_DOWNLOAD_CLIENT", resp.Code)
+	assert.Contains(t, resp.Error, "no download client for protocol: torrent")
+	assert.Contains(t, resp.Error, "sabnzbd (usenet)")
 
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	select {
+	case evt := <-eventCh:
+		t.Fatalf("unexpected event published: %v", evt)
+	default:
+	}
 
-	var resp errorResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Equal(t, "SERVICE_UNAVAILABLE", resp.Code)
-	assert.Equal(t, "Plex not configured", resp.Error)
+	// Unknown protocols are rejected up front
+	body = fmt.Sprintf(`{"content_id": %d, "download_url": "http://x/1", "title": "Dune.2021.1080p.BluRay.x264-GROUP", "protocol": "ftp"}`, movie.ID)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestSearchPlex_Success(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+// uploadRequest builds a POST /grab/upload request with the given form
+// fields and a file in the named part.
+func uploadRequest(t *testing.T, fields map[string]string, part, filename, data string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for k, v := range fields {
+		require.NoError(t, form.WriteField(k, v))
+	}
+	if part != "" {
+		fw, err := form.CreateFormFile(part, filename)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, form.Close())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab/upload", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
 
-	db := setupTestDB(t)
-	mockPlex := mocks.NewMockPlexClient(ctrl)
+func TestGrabUploadedFile(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	mgr := download.NewManager(download.NewStore(db), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mgr.AddClient("sabnzbd", download.ProtocolUsenet, downloadmocks.NewMockDownloader(gomock.NewController(t)))
+	uploadDir := t.TempDir()
+	uploads := download.NewUploads(uploadDir)
 
-	// Setup mock expectations
-	mockPlex.EXPECT().
-		Search(gomock.Any(), "inception").
-		Return([]importer.PlexItem{
-			{
-				RatingKey: "12345",
-				Title:     "Inception",
-				Year:      2010,
-				Type:      "movie",
-				AddedAt:   1700000000,
-				FilePath:  "/media/movies/Inception (2010)/Inception.mkv",
-			},
-			{
-				RatingKey: "12346",
-				Title:     "Inception: The Cobol Job",
-				Year:      2010,
-				Type:      "movie",
-				AddedAt:   1700000001,
-				FilePath:  "/media/movies/Inception The Cobol Job (2010)/cobol.mkv",
-			},
-		}, nil)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
 
-	deps := ServerDeps{
+	movie := fixtures.NewMovie("Dune", 2021).Insert(t, db)
+	srv, err := NewWithDeps(ServerDeps{
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Plex:      mockPlex,
-	}
-	srv, err := NewWithDeps(deps, Config{})
+		Manager:   mgr,
+		Bus:       bus,
+		Uploads:   uploads,
+	}, Config{})
 	require.NoError(t, err)
-
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/search?query=inception", nil)
-	w := httptest.NewRecorder()
+	nzb := `<?xml version="1.0"?><nzb><file subject="Dune"><segments><segment number="1">a@b</segment></segments></file></nzb>`
+	contentID := strconv.FormatInt(movie.ID, 10)
+
+	t.Run("grabs the nzb", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, uploadRequest(t, map[string]string{"content_id": contentID}, "nzb", "Dune.2021.1080p.BluRay.x264-GROUP.nzb", nzb))
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+		select {
+		case evt := <-eventCh:
+			grab, ok := evt.(*events.GrabRequested)
+			require.True(t, ok)
+			assert.Equal(t, movie.ID, grab.ContentID)
+			assert.Equal(t, "Dune.2021.1080p.BluRay.x264-GROUP", grab.ReleaseName, "title defaults to the file name")
+			assert.Equal(t, "upload", grab.Indexer)
+			assert.Equal(t, string(download.ProtocolUsenet), grab.Protocol)
+			name, data, err := uploads.Read(grab.DownloadURL)
+			require.NoError(t, err, "event refers to the saved file")
+			assert.Equal(t, "Dune.2021.1080p.BluRay.x264-GROUP.nzb", name)
+			assert.Equal(t, nzb, string(data))
+		default:
+			t.Fatal("expected GrabRequested event")
+		}
+	})
 
-	mux.ServeHTTP(w, req)
+	errorCode := func(t *testing.T, req *http.Request, status int) string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, status, w.Code, w.Body.String())
+		var resp errorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return resp.Code
+	}
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	t.Run("rejects a malformed nzb", func(t *testing.T) {
+		req := uploadRequest(t, map[string]string{"content_id": contentID}, "nzb", "Dune.nzb", "<nzb><file>")
+		assert.Equal(t, "INVALID_NZB", errorCode(t, req, http.StatusBadRequest))
+	})
 
-	var resp plexSearchResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	t.Run("requires content and a file", func(t *testing.T) {
+		req := uploadRequest(t, nil, "nzb", "Dune.nzb", nzb)
+		assert.Equal(t, "MISSING_FIELD", errorCode(t, req, http.StatusBadRequest))
+		req = uploadRequest(t, map[string]string{"content_id": contentID}, "", "", "")
+		assert.Equal(t, "MISSING_FIELD", errorCode(t, req, http.StatusBadRequest))
+	})
 
-	assert.Equal(t, "inception", resp.Query)
-	assert.Equal(t, 2, resp.Total)
-	assert.Len(t, resp.Items, 2)
+	t.Run("torrent needs a torrent client", func(t *testing.T) {
+		req := uploadRequest(t, map[string]string{"content_id": contentID}, "torrent", "Dune.torrent", "d4:infod4:name4:Duneee")
+		assert.Equal(t, "NO_DOWNLOAD_CLIENT", errorCode(t, req, http.StatusUnprocessableEntity))
+	})
 
-	// Verify first item
-	assert.Equal(t, "Inception", resp.Items[0].Title)
-	assert.Equal(t, 2010, resp.Items[0].Year)
-	assert.Equal(t, "movie", resp.Items[0].Type)
-	assert.Equal(t, int64(1700000000), resp.Items[0].AddedAt)
-	assert.Equal(t, "/media/movies/Inception (2010)/Inception.mkv", resp.Items[0].FilePath)
-	assert.False(t, resp.Items[0].Tracked)
-	assert.Nil(t, resp.Items[0].ContentID)
+	t.Run("json grabs can't name local files", func(t *testing.T) {
+		body := fmt.Sprintf(`{"content_id": %d, "download_url": "file:///etc/passwd", "title": "Dune.2021.1080p.BluRay.x264-GROUP"}`, movie.ID)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
+		assert.Equal(t, "INVALID_RELEASE", errorCode(t, req, http.StatusBadRequest))
+	})
 
-	// Verify second item
-	assert.Equal(t, "Inception: The Cobol Job", resp.Items[1].Title)
-	assert.Equal(t, 2010, resp.Items[1].Year)
+	select {
+	case evt := <-eventCh:
+		t.Fatalf("unexpected event published: %v", evt)
+	default:
+	}
+	entries, err := os.ReadDir(uploadDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "only the accepted upload is kept")
 }
 
-func TestSearchPlex_MissingQuery(t *testing.T) {
+func TestTVDBSearch_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	db := setupTestDB(t)
-	mockPlex := mocks.NewMockPlexClient(ctrl)
+	db := testutil.OpenTestDB(t)
+	mockTVDB := mocks.NewMockTVDBService(ctrl)
 
-	// No EXPECT calls - search should not be called when query is missing
+	// Set up expectation for search
+	mockTVDB.EXPECT().
+		Search(gomock.Any(), "breaking bad").
+		Return([]tvdb.SearchResult{
+			{
+				ID:       81189,
+				Name:     "Breaking Bad",
+				Year:     2008,
+				Status:   "Ended",
+				Overview: "A high school chemistry teacher diagnosed with inoperable lung cancer...",
+				Network:  "AMC",
+			},
+		}, nil)
 
 	deps := ServerDeps{
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Plex:      mockPlex,
 	}
 	srv, err := NewWithDeps(deps, Config{})
 	require.NoError(t, err)
+	srv.SetTVDB(mockTVDB)
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/search", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tvdb/search?q=breaking+bad", nil)
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
-
-	var resp errorResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Equal(t, "MISSING_QUERY", resp.Code)
-	assert.Equal(t, "query parameter is required", resp.Error)
-}
-
-func TestListPlexLibraryItems_Success(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	db := setupTestDB(t)
-	mockPlex := mocks.NewMockPlexClient(ctrl)
-
-	// Setup mock expectations
-	mockPlex.EXPECT().
-		FindSectionByName(gomock.Any(), "Movies").
-		Return(&importer.Section{
-			Key:   "1",
-			Title: "Movies",
-			Type:  "movie",
-		}, nil)
-
-	mockPlex.EXPECT().
-		ListLibraryItems(gomock.Any(), "1").
-		Return([]importer.PlexItem{
-			{
-				RatingKey: "100",
-				Title:     "The Matrix",
-				Year:      1999,
-				Type:      "movie",
-				AddedAt:   1699000000,
-				FilePath:  "/media/movies/The Matrix (1999)/matrix.mkv",
-			},
-			{
-				RatingKey: "101",
-				Title:     "The Matrix Reloaded",
-				Year:      2003,
-				Type:      "movie",
-				AddedAt:   1699000001,
-				FilePath:  "/media/movies/The Matrix Reloaded (2003)/reloaded.mkv",
-			},
-			{
-				RatingKey: "102",
-				Title:     "The Matrix Revolutions",
-				Year:      2003,
-				Type:      "movie",
-				AddedAt:   1699000002,
-				FilePath:  "/media/movies/The Matrix Revolutions (2003)/revolutions.mkv",
-			},
-		}, nil)
-
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var results []tvdb.SearchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	assert.Len(t, results, 1)
+	assert.Equal(t, 81189, results[0].ID)
+	assert.Equal(t, "Breaking Bad", results[0].Name)
+	assert.Equal(t, 2008, results[0].Year)
+}
+
+func TestTVDBSearch_MissingQuery(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+
 	deps := ServerDeps{
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Plex:      mockPlex,
 	}
 	srv, err := NewWithDeps(deps, Config{})
 	require.NoError(t, err)
@@ -1236,246 +6156,471 @@ func TestListPlexLibraryItems_Success(t *testing.T) {
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/libraries/Movies/items", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tvdb/search", nil)
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var resp plexListResponse
+	var resp errorResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Error, "query parameter is required")
+}
 
-	assert.Equal(t, "Movies", resp.Library)
-	assert.Equal(t, 3, resp.Total)
-	assert.Len(t, resp.Items, 3)
+func TestTVDBSearch_NotConfigured(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 
-	// Verify first item
-	assert.Equal(t, "The Matrix", resp.Items[0].Title)
-	assert.Equal(t, 1999, resp.Items[0].Year)
-	assert.Equal(t, "movie", resp.Items[0].Type)
-	assert.Equal(t, int64(1699000000), resp.Items[0].AddedAt)
-	assert.Equal(t, "/media/movies/The Matrix (1999)/matrix.mkv", resp.Items[0].FilePath)
-	assert.False(t, resp.Items[0].Tracked)
-	assert.Nil(t, resp.Items[0].ContentID)
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
+	// Note: Not calling SetTVDB, so tvdbSvc remains nil
 
-	// Verify second and third items exist
-	assert.Equal(t, "The Matrix Reloaded", resp.Items[1].Title)
-	assert.Equal(t, "The Matrix Revolutions", resp.Items[2].Title)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tvdb/search?q=test", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Error, "TVDB not configured")
 }
 
-func TestListPlexLibraryItems_NotFound(t *testing.T) {
+func TestTVDBSearch_SearchError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	db := setupTestDB(t)
-	mockPlex := mocks.NewMockPlexClient(ctrl)
-
-	// Return nil section (not found)
-	mockPlex.EXPECT().
-		FindSectionByName(gomock.Any(), "NonExistent").
-		Return(nil, nil)
+	db := testutil.OpenTestDB(t)
+	mockTVDB := mocks.NewMockTVDBService(ctrl)
 
-	// GetSections called to list available libraries
-	mockPlex.EXPECT().
-		GetSections(gomock.Any()).
-		Return([]importer.Section{
-			{Key: "1", Title: "Movies", Type: "movie"},
-			{Key: "2", Title: "TV Shows", Type: "show"},
-		}, nil)
+	// Set up expectation for search failure
+	mockTVDB.EXPECT().
+		Search(gomock.Any(), "test").
+		Return(nil, fmt.Errorf("TVDB API error"))
 
 	deps := ServerDeps{
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Plex:      mockPlex,
 	}
 	srv, err := NewWithDeps(deps, Config{})
 	require.NoError(t, err)
+	srv.SetTVDB(mockTVDB)
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/plex/libraries/NonExistent/items", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tvdb/search?q=test", nil)
 	w := httptest.NewRecorder()
 
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	var resp errorResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Equal(t, "LIBRARY_NOT_FOUND", resp.Code)
-	assert.Contains(t, resp.Error, "NonExistent")
-	assert.Contains(t, resp.Error, "Movies")
-	assert.Contains(t, resp.Error, "TV Shows")
-}
-
-// mockIndexer implements IndexerAPI for testing
-type mockIndexer struct {
-	name string
-	url  string
+	assert.Contains(t, resp.Error, "TVDB API error")
 }
 
-func (m *mockIndexer) Name() string                 { return m.name }
-func (m *mockIndexer) URL() string                  { return m.url }
-func (m *mockIndexer) Caps(_ context.Context) error { return nil }
-
-func TestCheckLibrary_Success(t *testing.T) {
-	db := setupTestDB(t)
+func TestBlocklist_ListAndUnblock(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// Add content with different statuses
-	available := &library.Content{
+	c := &library.Content{
 		Type:           library.ContentTypeMovie,
-		Title:          "Available Movie",
+		Title:          "Test",
 		Year:           2024,
-		Status:         library.StatusAvailable,
+		Status:         library.StatusWanted,
 		QualityProfile: "hd",
 		RootPath:       "/movies",
 	}
-	require.NoError(t, srv.deps.Library.AddContent(available))
+	require.NoError(t, srv.deps.Library.AddContent(c))
+	require.NoError(t, srv.deps.Downloads.Block(&download.BlocklistEntry{
+		ContentID:   c.ID,
+		GUID:        "https://indexer.example/details/abc",
+		ReleaseName: "Test.2024.1080p.BluRay.x264-BROKEN",
+		Indexer:     "nzbgeek",
+		Reason:      "Unpacking failed",
+	}))
 
-	wanted := &library.Content{
-		Type:           library.ContentTypeMovie,
-		Title:          "Wanted Movie",
-		Year:           2024,
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	// List
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/content/%d/blocklist", c.ID), nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp listBlocklistResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "https://indexer.example/details/abc", resp.Items[0].GUID)
+	assert.Equal(t, "Unpacking failed", resp.Items[0].Reason)
+
+	// Unblock (GUID containing slashes must be URL-escaped)
+	path := fmt.Sprintf("/api/v1/content/%d/blocklist/%s", c.ID, url.PathEscape("https://indexer.example/details/abc"))
+	req = httptest.NewRequest(http.MethodDelete, path, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	blocked, err := srv.deps.Downloads.IsBlocked(c.ID, "https://indexer.example/details/abc")
+	require.NoError(t, err)
+	assert.False(t, blocked)
+
+	// Unblocking again is a 404
+	req = httptest.NewRequest(http.MethodDelete, path, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestContentAliases(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	c := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Money Heist",
+		Year:           2017,
 		Status:         library.StatusWanted,
 		QualityProfile: "hd",
-		RootPath:       "/movies",
+		RootPath:       "/tv",
 	}
-	require.NoError(t, srv.deps.Library.AddContent(wanted))
+	require.NoError(t, srv.deps.Library.AddContent(c))
+	_, err := srv.deps.Library.ReplaceAliases(c.ID, library.AliasSourceTVDB, []string{"La Casa de Papel"})
+	require.NoError(t, err)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/library/check", nil)
-	w := httptest.NewRecorder()
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+	path := fmt.Sprintf("/api/v1/content/%d/aliases", c.ID)
 
-	srv.checkLibrary(w, req)
+	// Add a manual alias
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"alias": "Haus des Geldes"}`)))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var added aliasResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &added))
+	assert.Equal(t, "manual", added.Source)
+
+	// Duplicates of the title or an alias conflict
+	for _, body := range []string{`{"alias": "money heist"}`, `{"alias": "LA CASA DE PAPEL"}`} {
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		assert.Equal(t, http.StatusConflict, w.Code, body)
+	}
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"alias": " "}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var resp libraryCheckResponse
+	// List
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp listAliasesResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 2, resp.Total)
+	assert.Equal(t, "La Casa de Papel", resp.Items[0].Alias)
+	assert.Equal(t, "tvdb", resp.Items[0].Source)
+	assert.Equal(t, "Haus des Geldes", resp.Items[1].Alias)
 
-	assert.Equal(t, 2, resp.Total)
-	assert.Len(t, resp.Items, 2)
+	// Delete
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", path, added.ID), nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", path, added.ID), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
 
-	// Find each item
-	var foundAvailable, foundWanted bool
-	for _, item := range resp.Items {
-		if item.Title == "Available Movie" {
-			foundAvailable = true
-			assert.Equal(t, "available", item.Status)
-			assert.Equal(t, int64(1), item.ID)
-			// Status is 'available' but no files - should have issue
-			assert.Contains(t, item.Issues, "Status is 'available' but no files in database")
-		}
-		if item.Title == "Wanted Movie" {
-			foundWanted = true
-			assert.Equal(t, "wanted", item.Status)
-			assert.Equal(t, int64(2), item.ID)
-			// Status is 'wanted' with no files - no issue
-			assert.Empty(t, item.Issues)
+	// Unknown content
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/content/999/aliases", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/content/999/aliases", strings.NewReader(`{"alias": "x"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMonitorEpisodes(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Test Series",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(series))
+	for season := 1; season <= 3; season++ {
+		for ep := 1; ep <= 2; ep++ {
+			require.NoError(t, srv.deps.Library.AddEpisode(&library.Episode{
+				ContentID: series.ID, Season: season, Episode: ep, Status: library.StatusWanted,
+			}))
 		}
 	}
-	assert.True(t, foundAvailable, "should find Available Movie")
-	assert.True(t, foundWanted, "should find Wanted Movie")
 
-	// 'available' with no files = issue, 'wanted' with no files = healthy
-	assert.Equal(t, 1, resp.Healthy)
-	assert.Equal(t, 1, resp.WithIssues)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	path := fmt.Sprintf("/api/v1/content/%d/episodes/monitor", series.ID)
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"seasons":[2,3]}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp monitorEpisodesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Changed)
+	assert.Equal(t, []seasonChangeResponse{{Season: 1, Changed: 2}}, resp.Seasons)
+
+	// Monitor season 1 only: season 1 flips back, seasons 2 and 3 are unmonitored
+	req = httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"seasons":[1]}`))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 6, resp.Changed)
+	assert.Equal(t, []seasonChangeResponse{
+		{Season: 1, Changed: 2},
+		{Season: 2, Changed: 2},
+		{Season: 3, Changed: 2},
+	}, resp.Seasons)
 }
 
-func TestCheckLibrary_Empty(t *testing.T) {
-	db := setupTestDB(t)
+func TestMonitorEpisodes_Validation(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	// No content added
+	movie := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Test Movie",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(movie))
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Test Series",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(series))
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/library/check", nil)
-	w := httptest.NewRecorder()
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	tests := []struct {
+		name     string
+		id       int64
+		body     string
+		wantCode int
+		wantErr  string
+	}{
+		{"no selector", series.ID, `{}`, http.StatusBadRequest, "INVALID_SELECTION"},
+		{"multiple selectors", series.ID, `{"seasons":[1],"future_only":true}`, http.StatusBadRequest, "INVALID_SELECTION"},
+		{"movie", movie.ID, `{"seasons":[1]}`, http.StatusBadRequest, "NOT_SERIES"},
+		{"unknown content", 999, `{"seasons":[1]}`, http.StatusNotFound, "NOT_FOUND"},
+		{"foreign episode", series.ID, `{"episode_ids":[12345]}`, http.StatusBadRequest, "INVALID_EPISODE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := fmt.Sprintf("/api/v1/content/%d/episodes/monitor", tt.id)
+			req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			var resp errorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp.Code)
+		})
+	}
+}
+
+func TestEpisodeQualityProfileOverride(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	seedProfiles(t, srv, map[string][]string{
+		"hd":  {"1080p"},
+		"uhd": {"2160p"},
+	})
+
+	series := &library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          "Test Series",
+		Year:           2024,
+		Status:         library.StatusWanted,
+		QualityProfile: "uhd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, srv.deps.Library.AddContent(series))
+	for season := 1; season <= 2; season++ {
+		require.NoError(t, srv.deps.Library.AddEpisode(&library.Episode{
+			ContentID: series.ID, Season: season, Episode: 1, Status: library.StatusWanted,
+		}))
+	}
 
-	srv.checkLibrary(w, req)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
 
-	var resp libraryCheckResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	// Season 1 is grabbed in hd; monitoring of season 2 is untouched
+	monitorPath := fmt.Sprintf("/api/v1/content/%d/episodes/monitor", series.ID)
+	w := do(http.MethodPut, monitorPath, `{"seasons":[1],"quality_profile":"hd"}`)
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var monitorResp monitorEpisodesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &monitorResp))
+	assert.Equal(t, []seasonChangeResponse{{Season: 1, Changed: 1}}, monitorResp.Seasons)
+
+	w = do(http.MethodGet, fmt.Sprintf("/api/v1/content/%d/episodes", series.ID), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list listEpisodesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "hd", list.Items[0].QualityProfile)
+	assert.False(t, list.Items[0].ProfileInherited)
+	assert.Equal(t, "uhd", list.Items[1].QualityProfile)
+	assert.True(t, list.Items[1].ProfileInherited)
+	assert.Equal(t, "wanted", list.Items[1].Status)
+
+	// Unknown profiles are rejected
+	w = do(http.MethodPut, monitorPath, `{"seasons":[2],"quality_profile":"sd"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	episodePath := fmt.Sprintf("/api/v1/episodes/%d", list.Items[0].ID)
+	w = do(http.MethodPut, episodePath, `{"quality_profile":"sd"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	assert.Equal(t, 0, resp.Total)
-	assert.Empty(t, resp.Items)
-	assert.Equal(t, 0, resp.Healthy)
-	assert.Equal(t, 0, resp.WithIssues)
+	// An empty profile restores inheritance
+	w = do(http.MethodPut, episodePath, `{"quality_profile":""}`)
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var ep episodeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ep))
+	assert.Equal(t, "uhd", ep.QualityProfile)
+	assert.True(t, ep.ProfileInherited)
 }
 
-func TestListIndexers_Success(t *testing.T) {
-	db := setupTestDB(t)
-
-	// Create mock indexers
-	indexers := []IndexerAPI{
-		&mockIndexer{name: "NZBgeek", url: "https://api.nzbgeek.info"},
-		&mockIndexer{name: "DrunkenSlug", url: "https://api.drunkenslug.com"},
-	}
+func TestRenameLibrary(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	ctrl := gomock.NewController(t)
+	mockImporter := mocks.NewMockFileImporter(ctrl)
+
+	mockImporter.EXPECT().
+		Rename(gomock.Any(), int64(1)).
+		Return(&importer.RenameResult{
+			ContentID: 1,
+			Files: []importer.FileRename{
+				{FileID: 10, OldPath: "/movies/a.mkv", NewPath: "/movies/A/a.mkv", Status: importer.RenameRenamed},
+				{FileID: 11, OldPath: "/movies/b.mkv", NewPath: "/movies/A/a.mkv", Status: importer.RenameConflict, Error: importer.ErrDestinationExists},
+			},
+			PlexNotified: true,
+		}, nil)
 
 	deps := ServerDeps{
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Indexers:  indexers,
+		Importer:  mockImporter,
 	}
 	srv, err := NewWithDeps(deps, Config{})
 	require.NoError(t, err)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/indexers", nil)
-	w := httptest.NewRecorder()
-
-	srv.listIndexers(w, req)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/rename", strings.NewReader(`{"content_id":1}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
 
-	var resp listIndexersResponse
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp libraryRenameResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-
-	assert.Len(t, resp.Indexers, 2)
-	assert.Equal(t, "NZBgeek", resp.Indexers[0].Name)
-	assert.Equal(t, "https://api.nzbgeek.info", resp.Indexers[0].URL)
-	assert.Equal(t, "DrunkenSlug", resp.Indexers[1].Name)
-	assert.Equal(t, "https://api.drunkenslug.com", resp.Indexers[1].URL)
+	assert.False(t, resp.DryRun)
+	assert.True(t, resp.PlexNotified)
+	require.Len(t, resp.Files, 2)
+	assert.Equal(t, "renamed", resp.Files[0].Status)
+	assert.Equal(t, "conflict", resp.Files[1].Status)
+	assert.NotEmpty(t, resp.Files[1].Error)
+	assert.Equal(t, 1, resp.Summary.Renamed)
+	assert.Equal(t, 1, resp.Summary.Failed)
 }
 
-func TestListIndexers_Empty(t *testing.T) {
-	db := setupTestDB(t)
-	srv := New(db, Config{})
-
-	// No indexers configured (srv.deps.Indexers is nil)
+func TestRenameLibrary_DryRun(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	ctrl := gomock.NewController(t)
+	mockImporter := mocks.NewMockFileImporter(ctrl)
+
+	// Dry run must only preview
+	mockImporter.EXPECT().
+		PreviewRename(int64(1)).
+		Return([]importer.FileRename{
+			{FileID: 10, OldPath: "/movies/a.mkv", NewPath: "/movies/A/a.mkv", Status: importer.RenamePending},
+			{FileID: 11, OldPath: "/movies/B/b.mkv", NewPath: "/movies/B/b.mkv", Status: importer.RenameUnchanged},
+		}, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/indexers", nil)
-	w := httptest.NewRecorder()
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Importer:  mockImporter,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
 
-	srv.listIndexers(w, req)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/rename", strings.NewReader(`{"content_id":1,"dry_run":true}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
 
-	var resp listIndexersResponse
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp libraryRenameResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-
-	// Should return empty array, not 503
-	assert.Empty(t, resp.Indexers)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 1, resp.Summary.Renamed)
+	assert.Equal(t, 1, resp.Summary.Unchanged)
 }
 
-func TestRetryDownload_NotFound(t *testing.T) {
+func TestRenameLibrary_ContentNotFound(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	mockImporter := mocks.NewMockFileImporter(ctrl)
 
-	db := setupTestDB(t)
-	mockSearcher := mocks.NewMockSearcher(ctrl)
-	mockManager := mocks.NewMockDownloadManager(ctrl)
+	mockImporter.EXPECT().
+		PreviewRename(int64(99)).
+		Return(nil, fmt.Errorf("get content: %w", library.ErrNotFound))
 
 	deps := ServerDeps{
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Searcher:  mockSearcher,
-		Manager:   mockManager,
+		Importer:  mockImporter,
 	}
 	srv, err := NewWithDeps(deps, Config{})
 	require.NoError(t, err)
@@ -1483,996 +6628,1328 @@ func TestRetryDownload_NotFound(t *testing.T) {
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	// Try to retry a non-existent download
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/downloads/999/retry", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/rename", strings.NewReader(`{"content_id":99,"dry_run":true}`))
 	w := httptest.NewRecorder()
-
 	mux.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
-
-	var resp errorResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Equal(t, "NOT_FOUND", resp.Code)
-	assert.Equal(t, "Download not found", resp.Error)
 }
-func TestLibraryImport_ValidationErrors(t *testing.T) {
-	db := setupTestDB(t)
-	srv := New(db, Config{})
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+// countingImporter counts the imports of each download.
+type countingImporter struct {
+	mu      sync.Mutex
+	imports map[int64]int
+}
 
-	tests := []struct {
-		name     string
-		body     string
-		wantCode int
-		wantErr  string
-	}{
-		{
-			name:     "missing source",
-			body:     `{"library": "Movies"}`,
-			wantCode: http.StatusBadRequest,
-			wantErr:  "source is required",
-		},
-		{
-			name:     "invalid source",
-			body:     `{"source": "invalid", "library": "Movies"}`,
-			wantCode: http.StatusBadRequest,
-			wantErr:  "unsupported source",
-		},
-		{
-			name:     "missing library",
-			body:     `{"source": "plex"}`,
-			wantCode: http.StatusBadRequest,
-			wantErr:  "library is required",
-		},
-	}
+func (c *countingImporter) count(downloadID int64) {
+	time.Sleep(5 * time.Millisecond) // Long enough for the other path to try
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.imports[downloadID]++
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(tt.body))
-			req.Header.Set("Content-Type", "application/json")
-			w := httptest.NewRecorder()
+func (c *countingImporter) Import(ctx context.Context, downloadID int64, path string) (*importer.ImportResult, error) {
+	return c.ImportWithOptions(ctx, downloadID, path, importer.ImportOptions{})
+}
 
-			mux.ServeHTTP(w, req)
+func (c *countingImporter) ImportWithOptions(_ context.Context, downloadID int64, path string, _ importer.ImportOptions) (*importer.ImportResult, error) {
+	c.count(downloadID)
+	return &importer.ImportResult{FileID: downloadID, SourcePath: path, DestPath: "/movies/movie.mkv"}, nil
+}
 
-			assert.Equal(t, tt.wantCode, w.Code)
-			assert.Contains(t, w.Body.String(), tt.wantErr)
-		})
-	}
+func (c *countingImporter) ImportSeasonPack(_ context.Context, downloadID int64, _ string) (*importer.SeasonPackResult, error) {
+	c.count(downloadID)
+	return &importer.SeasonPackResult{}, nil
 }
 
-func TestLibraryImport_PlexNotConfigured(t *testing.T) {
-	db := setupTestDB(t)
-	srv := New(db, Config{})
-	srv.deps.Plex = nil // Ensure Plex is not configured
+func (c *countingImporter) PreviewRename(int64) ([]importer.FileRename, error) { return nil, nil }
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+func (c *countingImporter) Rename(context.Context, int64) (*importer.RenameResult, error) {
+	return &importer.RenameResult{}, nil
+}
 
-	body := `{"source": "plex", "library": "Movies"}` //nolint:goconst
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+func TestImportTracked_RaceWithPipeline(t *testing.T) {
+	// File-backed so the handler and the API use separate pooled connections, as in production
+	db, err := database.Open(filepath.Join(t.TempDir(), "arrgo.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	testutil.ApplySchema(t, db)
 
-	mux.ServeHTTP(w, req)
+	bus := events.NewBus(nil, nil)
+	t.Cleanup(func() { _ = bus.Close() })
+	imp := &countingImporter{imports: make(map[int64]int)}
+	downloadRoot := t.TempDir()
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Importer:  imp,
+	}
+	srv, err := NewWithDeps(deps, Config{DownloadRoot: downloadRoot})
+	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
-	assert.Contains(t, w.Body.String(), "Plex not configured")
+	handler := handlers.NewImportHandler(bus, deps.Downloads, nil, imp, nil)
+	handler.SetConcurrency(4)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = handler.Start(ctx) }()
+	time.Sleep(10 * time.Millisecond) // Let the handler subscribe
+
+	movie := &library.Content{Type: library.ContentTypeMovie, Title: "Race", Year: 2024,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, deps.Library.AddContent(movie))
+
+	// The pipeline and the API take each completed download at the same moment
+	const downloads = 10
+	ids := make([]int64, downloads)
+	codes := make([]int, downloads)
+	var wg sync.WaitGroup
+	for i := range ids {
+		dl := &download.Download{ContentID: movie.ID, Client: download.ClientSABnzbd, ClientID: fmt.Sprintf("sab-%d", i),
+			Status: download.StatusCompleted, ReleaseName: fmt.Sprintf("Race.2024.1080p.%d", i), Indexer: "test"}
+		require.NoError(t, deps.Downloads.Add(dl))
+		ids[i] = dl.ID
+		sourcePath := filepath.Join(downloadRoot, dl.ReleaseName)
+		require.NoError(t, os.MkdirAll(sourcePath, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(sourcePath, "movie.mkv"), []byte("x"), 0644))
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, bus.Publish(ctx, &events.DownloadCompleted{
+				BaseEvent:  events.NewBaseEvent(events.EventDownloadCompleted, events.EntityDownload, dl.ID),
+				DownloadID: dl.ID,
+				SourcePath: sourcePath,
+			}))
+		}()
+		go func() {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"download_id": %d}`, dl.ID)
+			w := httptest.NewRecorder()
+			srv.importContent(w, httptest.NewRequest(http.MethodPost, "/api/v1/import", strings.NewReader(body)))
+			codes[i] = w.Code
+		}()
+	}
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		list, _, err := deps.Downloads.List(download.Filter{IDs: ids})
+		require.NoError(t, err)
+		for _, dl := range list {
+			if dl.Status != download.StatusImported {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	for i, id := range ids {
+		assert.Equal(t, 1, imp.imports[id], "download %d imported once", id)
+		// The API loses with a conflict, or finds the download already imported
+		assert.Contains(t, []int{http.StatusOK, http.StatusConflict, http.StatusBadRequest}, codes[i], "download %d", id)
+	}
 }
 
-func TestLibraryImport_LibraryNotFound(t *testing.T) {
+func TestImportManual_WouldDowngrade(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	db := setupTestDB(t)
-	srv := New(db, Config{})
+	mockImporter := mocks.NewMockFileImporter(ctrl)
 
-	mockPlex := mocks.NewMockPlexClient(ctrl)
-	srv.deps.Plex = mockPlex
-
-	// Mock FindSectionByName to return nil (not found)
-	mockPlex.EXPECT().
-		FindSectionByName(gomock.Any(), "NonExistent").
-		Return(nil, nil)
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Importer:  mockImporter,
+	}
+	srv, err := NewWithDeps(deps, Config{MovieRoot: "/movies"})
+	require.NoError(t, err)
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/import", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
 
-	body := `{"source": "plex", "library": "NonExistent"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	mux.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusNotFound, w.Code)
-	assert.Contains(t, w.Body.String(), "Plex library not found")
+	mockImporter.EXPECT().
+		ImportWithOptions(gomock.Any(), gomock.Any(), "/downloads/movie", importer.ImportOptions{}).
+		Return(nil, fmt.Errorf("%w: existing file is 2160p, import is 1080p", importer.ErrImportWouldDowngrade))
+	w := post(`{"path":"/downloads/movie","title":"Dune","year":2021,"type":"movie","quality":"1080p"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "IMPORT_WOULD_DOWNGRADE")
+
+	mockImporter.EXPECT().
+		ImportWithOptions(gomock.Any(), gomock.Any(), "/downloads/movie", importer.ImportOptions{AllowDowngrade: true}).
+		Return(&importer.ImportResult{FileID: 7, DestPath: "/movies/Dune (2021)/Dune (2021) - 1080p.mkv", Quality: "1080p"}, nil)
+	w = post(`{"path":"/downloads/movie","title":"Dune","year":2021,"type":"movie","quality":"1080p","allow_downgrade":true}`)
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+
+	mockImporter.EXPECT().
+		ImportWithOptions(gomock.Any(), gomock.Any(), "/downloads/movie", gomock.Any()).
+		Return(nil, errors.New("disk full"))
+	w = post(`{"path":"/downloads/movie","title":"Dune","year":2021,"type":"movie"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "IMPORT_ERROR")
 }
 
-func TestLibraryImport_Success(t *testing.T) {
+func TestAddContent_AppliesMetadata(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	db := setupTestDB(t)
-	srv := New(db, Config{})
-
-	mockPlex := mocks.NewMockPlexClient(ctrl)
-	srv.deps.Plex = mockPlex
-
-	// Mock FindSectionByName to return a section
-	mockPlex.EXPECT().
-		FindSectionByName(gomock.Any(), "Movies").
-		Return(&importer.Section{Key: "1", Title: "Movies", Type: "movie"}, nil)
-
-	// Mock ListLibraryItems to return some items
-	mockPlex.EXPECT().
-		ListLibraryItems(gomock.Any(), "1").
-		Return([]importer.PlexItem{
-			{Title: "Test Movie", Year: 2024, Type: "movie", FilePath: "/movies/Test.Movie.2024.mkv"},
-		}, nil)
-
-	// Mock TranslateToLocal (identity transform)
-	mockPlex.EXPECT().
-		TranslateToLocal("/movies/Test.Movie.2024.mkv").
-		Return("/movies/Test.Movie.2024.mkv")
+	mockMetadata := mocks.NewMockMetadataRefresher(ctrl)
+
+	mockMetadata.EXPECT().
+		Apply(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, c *library.Content) (bool, error) {
+			c.Overview = "A thief who steals secrets."
+			c.PosterURL = "https://image.tmdb.org/t/p/w500/inception.jpg"
+			c.Runtime = 148
+			c.Genres = []string{"Action", "Science Fiction"}
+			return true, nil
+		})
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Metadata:  mockMetadata,
+	}
+	srv, err := NewWithDeps(deps, Config{MovieRoot: "/movies"})
+	require.NoError(t, err)
 
-	// Use dry_run since we're just testing response structure (no real files)
-	body := `{"source": "plex", "library": "Movies", "dry_run": true}` //nolint:goconst
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	body := `{"type":"movie","tmdb_id":27205,"title":"Inception","year":2010,"quality_profile":"hd"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content", strings.NewReader(body))
 	w := httptest.NewRecorder()
+	srv.addContent(w, req)
 
-	mux.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var resp libraryImportResponse
+	require.Equal(t, http.StatusCreated, w.Code, "response: %s", w.Body.String())
+	var resp contentResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	// Verify response structure has expected fields
-	assert.NotNil(t, resp.Imported)
-	assert.NotNil(t, resp.Skipped)
-	assert.NotNil(t, resp.Errors)
+	assert.Equal(t, "A thief who steals secrets.", resp.Overview)
+	assert.Equal(t, 148, resp.Runtime)
+	assert.Equal(t, []string{"Action", "Science Fiction"}, resp.Genres)
+
+	// Metadata is stored with the content
+	stored, err := deps.Library.GetContent(resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://image.tmdb.org/t/p/w500/inception.jpg", stored.PosterURL)
 }
 
-func TestLibraryImport_DryRun(t *testing.T) {
+func TestRefreshMetadata(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	mockMetadata := mocks.NewMockMetadataRefresher(ctrl)
 
-	db := setupTestDB(t)
-	srv := New(db, Config{})
+	mockMetadata.EXPECT().
+		Refresh(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, c *library.Content) (bool, error) {
+			c.Overview = "Refreshed overview"
+			return true, nil
+		})
 
-	mockPlex := mocks.NewMockPlexClient(ctrl)
-	srv.deps.Plex = mockPlex
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Metadata:  mockMetadata,
+	}
+	srv, err := NewWithDeps(deps, Config{})
+	require.NoError(t, err)
 
-	// Mock Plex to return section and items
-	mockPlex.EXPECT().
-		FindSectionByName(gomock.Any(), "Movies").
-		Return(&importer.Section{Key: "1", Title: "Movies"}, nil)
-	mockPlex.EXPECT().
-		ListLibraryItems(gomock.Any(), "1").
-		Return([]importer.PlexItem{
-			{Title: "Test Movie", Year: 2024, Type: "movie", FilePath: "/data/media/movies/Test.Movie.2024.2160p.BluRay.mkv"},
-		}, nil)
-	mockPlex.EXPECT().
-		TranslateToLocal("/data/media/movies/Test.Movie.2024.2160p.BluRay.mkv").
-		Return("/srv/media/movies/Test.Movie.2024.2160p.BluRay.mkv")
+	c := &library.Content{Type: library.ContentTypeMovie, Title: "Inception", Year: 2010,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, deps.Library.AddContent(c))
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	body := `{"source": "plex", "library": "Movies", "dry_run": true}` //nolint:goconst
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/content/%d/refresh-metadata", c.ID), nil)
 	w := httptest.NewRecorder()
-
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var resp libraryImportResponse
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp contentResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Refreshed overview", resp.Overview)
+}
 
-	// Dry run should show what would be imported
-	assert.Len(t, resp.Imported, 1)
-	assert.Equal(t, "Test Movie", resp.Imported[0].Title)
-	assert.Equal(t, 2024, resp.Imported[0].Year)
-	assert.Equal(t, "uhd", resp.Imported[0].Quality) // parsed from 2160p
+func TestRefreshMetadata_NoProvider(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
 
-	// ContentID should be 0 because dry_run doesn't create records
-	assert.Zero(t, resp.Imported[0].ContentID)
+	c := &library.Content{Type: library.ContentTypeMovie, Title: "Inception", Year: 2010,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, srv.deps.Library.AddContent(c))
 
-	// Summary should be correct
-	assert.Equal(t, 1, resp.Summary.Imported)
-	assert.Equal(t, 0, resp.Summary.Skipped)
-	assert.Equal(t, 0, resp.Summary.Errors)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/content/%d/refresh-metadata", c.ID), nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp contentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Overview)
+	assert.NotNil(t, resp.Genres)
 }
 
-func TestLibraryImport_SkipsAlreadyTracked(t *testing.T) {
+func TestSyncEpisodes(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	mockEpisodes := mocks.NewMockEpisodeSyncer(ctrl)
 
-	db := setupTestDB(t)
-	srv := New(db, Config{})
-
-	mockPlex := mocks.NewMockPlexClient(ctrl)
-	srv.deps.Plex = mockPlex
+	mockEpisodes.EXPECT().
+		Sync(gomock.Any(), gomock.Any()).
+		Return(&metadata.EpisodeSyncResult{Total: 20, Inserted: 10, Updated: 1, NewSeasons: []int{2}}, nil)
 
-	// Pre-create content that should be skipped
-	content := &library.Content{
-		Type:           library.ContentTypeMovie,
-		Title:          "Existing Movie",
-		Year:           2020,
-		Status:         library.StatusAvailable,
-		QualityProfile: "hd",
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Episodes:  mockEpisodes,
 	}
-	err := srv.deps.Library.AddContent(content)
+	srv, err := NewWithDeps(deps, Config{})
 	require.NoError(t, err)
 
-	mockPlex.EXPECT().
-		FindSectionByName(gomock.Any(), "Movies").
-		Return(&importer.Section{Key: "1", Title: "Movies"}, nil)
-	mockPlex.EXPECT().
-		ListLibraryItems(gomock.Any(), "1").
-		Return([]importer.PlexItem{
-			{Title: "Existing Movie", Year: 2020, Type: "movie", FilePath: "/data/media/movies/file.mkv"},
-		}, nil)
+	tvdbID := int64(81189)
+	c := &library.Content{Type: library.ContentTypeSeries, TVDBID: &tvdbID, Title: "Breaking Bad", Year: 2008,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, deps.Library.AddContent(c))
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	body := `{"source": "plex", "library": "Movies", "dry_run": true}` //nolint:goconst
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/content/%d/sync-episodes", c.ID), nil)
 	w := httptest.NewRecorder()
-
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var resp libraryImportResponse
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp syncEpisodesResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-
-	assert.Empty(t, resp.Imported)
-	assert.Len(t, resp.Skipped, 1)
-	assert.Equal(t, "Existing Movie", resp.Skipped[0].Title)
-	assert.Equal(t, "already tracked", resp.Skipped[0].Reason)
-	assert.Equal(t, content.ID, resp.Skipped[0].ContentID)
+	assert.Equal(t, tvdbID, resp.TVDBID)
+	assert.Equal(t, 10, resp.Inserted)
+	assert.Equal(t, 1, resp.Updated)
+	assert.Equal(t, []int{2}, resp.NewSeasons)
 }
 
-func TestLibraryImport_CreatesRecords(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	db := setupTestDB(t)
+func TestSyncEpisodes_NotConfigured(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
 
-	mockPlex := mocks.NewMockPlexClient(ctrl)
-	srv.deps.Plex = mockPlex
-
-	// Create a temp file for the test
-	tmpDir := t.TempDir()
-	testFile := filepath.Join(tmpDir, "New.Movie.2024.1080p.BluRay.mkv")
-	err := os.WriteFile(testFile, []byte("test content for size"), 0644)
-	require.NoError(t, err)
-
-	mockPlex.EXPECT().FindSectionByName(gomock.Any(), "Movies").Return(&importer.Section{Key: "1", Title: "Movies"}, nil)
-	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "1").Return([]importer.PlexItem{
-		{Title: "New Movie", Year: 2024, Type: "movie", FilePath: "/data/media/movies/New.Movie.2024.1080p.BluRay.mkv"},
-	}, nil)
-	// TranslateToLocal is called twice: once in processPlexImport for quality parsing, once in createImportedContent
-	mockPlex.EXPECT().TranslateToLocal("/data/media/movies/New.Movie.2024.1080p.BluRay.mkv").Return(testFile).Times(2)
+	tvdbID := int64(81189)
+	c := &library.Content{Type: library.ContentTypeSeries, TVDBID: &tvdbID, Title: "Breaking Bad", Year: 2008,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, srv.deps.Library.AddContent(c))
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	body := `{"source": "plex", "library": "Movies"}` //nolint:goconst
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/content/%d/sync-episodes", c.ID), nil)
 	w := httptest.NewRecorder()
-
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
 
-	var resp libraryImportResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	require.Len(t, resp.Imported, 1)
-	assert.NotZero(t, resp.Imported[0].ContentID)
+func TestRefreshMetadata_Errors(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	ctrl := gomock.NewController(t)
+	mockMetadata := mocks.NewMockMetadataRefresher(ctrl)
 
-	// Verify content was created
-	content, err := srv.deps.Library.GetContent(resp.Imported[0].ContentID)
-	require.NoError(t, err)
-	assert.Equal(t, "New Movie", content.Title)
-	assert.Equal(t, 2024, content.Year)
-	assert.Equal(t, library.StatusAvailable, content.Status)
-	assert.Equal(t, "hd", content.QualityProfile) // from 1080p
+	mockMetadata.EXPECT().
+		Refresh(gomock.Any(), gomock.Any()).
+		Return(false, errors.New("tmdb unavailable"))
 
-	// Verify file was created
-	files, _, err := srv.deps.Library.ListFiles(library.FileFilter{ContentID: &content.ID})
+	deps := ServerDeps{
+		Library:   library.NewStore(db),
+		Downloads: download.NewStore(db),
+		History:   importer.NewHistoryStore(db),
+		Metadata:  mockMetadata,
+	}
+	srv, err := NewWithDeps(deps, Config{})
 	require.NoError(t, err)
-	require.Len(t, files, 1)
-	assert.Equal(t, testFile, files[0].Path)
-	assert.Equal(t, "1080p", files[0].Quality)
-	assert.Equal(t, "plex-import", files[0].Source)
-	assert.Equal(t, int64(21), files[0].SizeBytes) // "test content for size" is 21 bytes
-}
 
-func TestLibraryImport_FileStatError(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	c := &library.Content{Type: library.ContentTypeMovie, Title: "Inception", Year: 2010,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, deps.Library.AddContent(c))
+
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	tests := []struct {
+		name     string
+		id       int64
+		wantCode int
+		wantErr  string
+	}{
+		{"not found", 999, http.StatusNotFound, "NOT_FOUND"},
+		{"provider error", c.ID, http.StatusBadGateway, "METADATA_ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/content/%d/refresh-metadata", tt.id), nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			var resp errorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp.Code)
+		})
+	}
+}
 
-	db := setupTestDB(t)
+func TestListWanted(t *testing.T) {
+	db := testutil.OpenTestDB(t)
 	srv := New(db, Config{})
+	seedProfiles(t, srv, map[string][]string{"hd": {"720p", "1080p"}})
+	lib := srv.deps.Library
 
-	mockPlex := mocks.NewMockPlexClient(ctrl)
-	srv.deps.Plex = mockPlex
+	missing := &library.Content{Type: library.ContentTypeMovie, Title: "Missing Movie", Year: 2020,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, lib.AddContent(missing))
 
-	mockPlex.EXPECT().FindSectionByName(gomock.Any(), "Movies").Return(&importer.Section{Key: "1", Title: "Movies"}, nil)
-	mockPlex.EXPECT().ListLibraryItems(gomock.Any(), "1").Return([]importer.PlexItem{
-		{Title: "Missing File", Year: 2024, Type: "movie", FilePath: "/data/media/movies/Missing.mkv"},
-	}, nil)
-	// Return a path that doesn't exist (called twice: once for quality parsing, once for content creation)
-	mockPlex.EXPECT().TranslateToLocal("/data/media/movies/Missing.mkv").Return("/nonexistent/path/Missing.mkv").Times(2)
+	low := &library.Content{Type: library.ContentTypeMovie, Title: "Low Movie", Year: 2021,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, lib.AddContent(low))
+	require.NoError(t, lib.AddFile(&library.File{ContentID: low.ID, Path: "/movies/low.mkv", Quality: "720p"}))
+
+	best := &library.Content{Type: library.ContentTypeMovie, Title: "Best Movie", Year: 2022,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, lib.AddContent(best))
+	require.NoError(t, lib.AddFile(&library.File{ContentID: best.ID, Path: "/movies/best.mkv", Quality: "1080p"}))
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	body := `{"source": "plex", "library": "Movies"}` //nolint:goconst
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/import", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/wanted", nil)
 	w := httptest.NewRecorder()
-
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var resp libraryImportResponse
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp wantedResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 
-	// Should be in errors, not imported
-	assert.Empty(t, resp.Imported)
-	assert.Len(t, resp.Errors, 1)
-	assert.Equal(t, "Missing File", resp.Errors[0].Title)
-	assert.Contains(t, resp.Errors[0].Error, "cannot access file")
+	assert.Equal(t, 1, resp.Missing.Total)
+	require.Len(t, resp.Missing.Items, 1)
+	assert.Equal(t, "Missing Movie", resp.Missing.Items[0].Title)
+	assert.Equal(t, "missing", resp.Missing.Items[0].Reason)
+	assert.Equal(t, "1080p", resp.Missing.Items[0].WantedQuality)
+
+	assert.Equal(t, 1, resp.CutoffUnmet.Total)
+	require.Len(t, resp.CutoffUnmet.Items, 1)
+	item := resp.CutoffUnmet.Items[0]
+	assert.Equal(t, "Low Movie", item.Title)
+	assert.Equal(t, "cutoff_unmet", item.Reason)
+	assert.Equal(t, "720p", item.CurrentQuality)
+	assert.Equal(t, "1080p", item.WantedQuality)
+	assert.Equal(t, "hd", item.QualityProfile)
 }
 
-func TestGrab_ContentNotFound(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestListWanted_Cutoff(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	_, err := srv.deps.Profiles.Seed(map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"720p", "1080p", "2160p"}, Cutoff: "1080p"},
+	})
+	require.NoError(t, err)
+	lib := srv.deps.Library
 
-	db := setupTestDB(t)
-	mockManager := mocks.NewMockDownloadManager(ctrl)
+	low := &library.Content{Type: library.ContentTypeMovie, Title: "Low Movie", Year: 2021,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, lib.AddContent(low))
+	require.NoError(t, lib.AddFile(&library.File{ContentID: low.ID, Path: "/movies/low.mkv", Quality: "720p"}))
 
-	deps := ServerDeps{
-		Library:   library.NewStore(db),
-		Downloads: download.NewStore(db),
-		History:   importer.NewHistoryStore(db),
-		Manager:   mockManager,
-	}
-	srv, err := NewWithDeps(deps, Config{})
-	require.NoError(t, err)
+	// Below the best accepted resolution, but good enough
+	good := &library.Content{Type: library.ContentTypeMovie, Title: "Good Movie", Year: 2022,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, lib.AddContent(good))
+	require.NoError(t, lib.AddFile(&library.File{ContentID: good.ID, Path: "/movies/good.mkv", Quality: "1080p"}))
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	// Grab with non-existent content_id
-	body := `{"content_id":999,"download_url":"http://example.com/nzb","title":"Test","indexer":"TestIndexer"}`
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/wanted", nil)
 	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var resp wantedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.CutoffUnmet.Items, 1)
+	assert.Equal(t, "Low Movie", resp.CutoffUnmet.Items[0].Title)
+	assert.Equal(t, "1080p", resp.CutoffUnmet.Items[0].WantedQuality)
 
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/content", nil)
+	w = httptest.NewRecorder()
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var list listContentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	met := make(map[string]bool)
+	for _, item := range list.Items {
+		met[item.Title] = item.QualityMet
+	}
+	assert.Equal(t, map[string]bool{"Low Movie": false, "Good Movie": true}, met)
 
-	var resp errorResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Equal(t, "NOT_FOUND", resp.Code)
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/content/%d", good.ID), nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	var content contentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &content))
+	assert.True(t, content.QualityMet)
 }
 
-func TestGrab_MissingRequiredFields(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestListWanted_IncludeAbandoned(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
+	lib := srv.deps.Library
 
-	db := setupTestDB(t)
-	mockManager := mocks.NewMockDownloadManager(ctrl)
+	require.NoError(t, lib.AddContent(&library.Content{Type: library.ContentTypeMovie, Title: "Wanted Movie", Year: 2020,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}))
+	require.NoError(t, lib.AddContent(&library.Content{Type: library.ContentTypeMovie, Title: "Abandoned Movie", Year: 1971,
+		Status: library.StatusAbandoned, QualityProfile: "hd", RootPath: "/movies"}))
 
-	// Add content so content_id validation passes
-	store := library.NewStore(db)
-	c := &library.Content{
-		Type:           library.ContentTypeMovie,
-		Title:          "Test Movie",
-		Year:           2024,
-		Status:         library.StatusWanted,
-		QualityProfile: "hd",
-		RootPath:       "/movies",
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	list := func(url string) wantedResponse {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+		var resp wantedResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
 	}
-	require.NoError(t, store.AddContent(c))
 
-	deps := ServerDeps{
-		Library:   store,
-		Downloads: download.NewStore(db),
-		History:   importer.NewHistoryStore(db),
-		Manager:   mockManager,
+	resp := list("/api/v1/wanted")
+	require.Len(t, resp.Missing.Items, 1)
+	assert.Equal(t, "Wanted Movie", resp.Missing.Items[0].Title)
+	assert.False(t, resp.Missing.Items[0].Abandoned)
+
+	resp = list("/api/v1/wanted?include_abandoned=true")
+	assert.Equal(t, 2, resp.Missing.Total)
+	abandoned := map[string]bool{}
+	for _, item := range resp.Missing.Items {
+		abandoned[item.Title] = item.Abandoned
 	}
-	srv, err := NewWithDeps(deps, Config{})
-	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"Wanted Movie": false, "Abandoned Movie": true}, abandoned)
+}
 
+func TestListWanted_Validation(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
 	tests := []struct {
 		name    string
-		body    string
+		query   string
 		wantErr string
 	}{
-		{
-			name:    "missing content_id",
-			body:    `{"download_url":"http://example.com/nzb","title":"Test","indexer":"TestIndexer"}`,
-			wantErr: "content_id",
-		},
-		{
-			name:    "missing download_url",
-			body:    `{"content_id":1,"title":"Test","indexer":"TestIndexer"}`,
-			wantErr: "download_url",
-		},
-		{
-			name:    "missing title",
-			body:    `{"content_id":1,"download_url":"http://example.com/nzb","indexer":"TestIndexer"}`,
-			wantErr: "title",
-		},
+		{"invalid type", "?type=music", "INVALID_TYPE"},
+		{"invalid sort", "?sort=title", "INVALID_SORT"},
+		{"negative offset", "?offset=-1", "INVALID_PAGINATION"},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(tt.body))
-			req.Header.Set("Content-Type", "application/json")
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/wanted"+tt.query, nil)
 			w := httptest.NewRecorder()
-
 			mux.ServeHTTP(w, req)
 
-			assert.Equal(t, http.StatusBadRequest, w.Code)
-			assert.Contains(t, w.Body.String(), tt.wantErr)
-		})
-	}
-}
-
-func TestGrab_SeriesWithEpisodeDetection(t *testing.T) {
-	db := setupTestDB(t)
-	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
-
-	// Create event bus to capture published events
-	bus := events.NewBus(nil, nil)
-	defer bus.Close()
-
-	// Subscribe to capture events
-	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
-
-	// Add series content
-	store := library.NewStore(db)
-	series := &library.Content{
-		Type:           library.ContentTypeSeries,
-		Title:          "Breaking Bad",
-		Year:           2008,
-		Status:         library.StatusWanted,
-		QualityProfile: "hd",
-		RootPath:       "/tv",
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp errorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantErr, resp.Code)
+		})
 	}
-	require.NoError(t, store.AddContent(series))
+}
 
-	deps := ServerDeps{
-		Library:   store,
+func setupSpeedServer(t *testing.T, speed SpeedLimiter) *http.ServeMux {
+	t.Helper()
+	db := testutil.OpenTestDB(t)
+	srv, err := NewWithDeps(ServerDeps{
+		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Manager:   mockManager,
-		Bus:       bus,
-	}
-	srv, err := NewWithDeps(deps, Config{})
+		Speed:     speed,
+	}, Config{})
 	require.NoError(t, err)
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
+	return mux
+}
 
-	// Grab with release name that contains episode info
-	body := fmt.Sprintf(`{
-		"content_id": %d,
-		"download_url": "http://example.com/nzb",
-		"title": "Breaking.Bad.S05E12.1080p.BluRay.x264-DEMAND",
-		"indexer": "NZBgeek"
-	}`, series.ID)
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+func TestSetSpeedLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSpeed := mocks.NewMockSpeedLimiter(ctrl)
+
+	var until time.Time
+	mockSpeed.EXPECT().
+		Override(gomock.Any(), int64(5<<20), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, u time.Time) error {
+			until = u
+			return nil
+		})
+	mockSpeed.EXPECT().Current().DoAndReturn(func() download.SpeedLimitState {
+		return download.SpeedLimitState{Limit: 5 << 20, Source: download.SpeedSourceOverride, Expires: &until}
+	})
+	mux := setupSpeedServer(t, mockSpeed)
 
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/downloads/speed-limit",
+		strings.NewReader(`{"limit":"5MB","duration":"2h"}`))
+	w := httptest.NewRecorder()
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), until, time.Minute)
 
-	// Verify the event was published
-	select {
-	case evt := <-eventCh:
-		grabEvt, ok := evt.(*events.GrabRequested)
-		require.True(t, ok, "expected GrabRequested event")
-		assert.Equal(t, series.ID, grabEvt.ContentID)
-		assert.NotNil(t, grabEvt.Season)
-		assert.Equal(t, 5, *grabEvt.Season)
-		assert.Len(t, grabEvt.EpisodeIDs, 1)
-		assert.NotNil(t, grabEvt.EpisodeID) // Backward compatibility
-		assert.False(t, grabEvt.IsCompleteSeason)
-	default:
-		t.Fatal("expected event to be published")
+	var resp speedLimitResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(5<<20), resp.Limit)
+	assert.Equal(t, "5.0 MB/s", resp.Display)
+	assert.Equal(t, download.SpeedSourceOverride, resp.Source)
+	assert.NotNil(t, resp.ExpiresAt)
+}
+
+func TestSetSpeedLimit_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		override error
+		status   int
+		code     string
+	}{
+		{"invalid limit", `{"limit":"fast","duration":"2h"}`, nil, http.StatusBadRequest, "INVALID_LIMIT"},
+		{"missing duration", `{"limit":"5MB"}`, nil, http.StatusBadRequest, "INVALID_DURATION"},
+		{"unsupported client", `{"limit":"5MB","duration":"2h"}`, download.ErrUnsupported, http.StatusNotImplemented, "NOT_SUPPORTED"},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockSpeed := mocks.NewMockSpeedLimiter(ctrl)
+			if tt.override != nil {
+				mockSpeed.EXPECT().Override(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.override)
+			}
+			mux := setupSpeedServer(t, mockSpeed)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/downloads/speed-limit", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
 
-	// Verify episode was created in database
-	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &series.ID})
-	require.NoError(t, err)
-	assert.Len(t, episodes, 1)
-	assert.Equal(t, 5, episodes[0].Season)
-	assert.Equal(t, 12, episodes[0].Episode)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.code)
+		})
+	}
 }
 
-func TestGrab_SeasonPack(t *testing.T) {
-	db := setupTestDB(t)
-	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
+func TestSetSpeedLimit_NoClient(t *testing.T) {
+	mux := setupSpeedServer(t, nil)
 
-	// Create event bus
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/downloads/speed-limit",
+		strings.NewReader(`{"limit":"5MB","duration":"2h"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestGetStatus_SpeedLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSpeed := mocks.NewMockSpeedLimiter(ctrl)
+	mockSpeed.EXPECT().Current().Return(download.SpeedLimitState{Limit: 1 << 20, Source: download.SpeedSourceSchedule})
+	mux := setupSpeedServer(t, mockSpeed)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp statusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.SpeedLimit)
+	assert.Equal(t, int64(1<<20), resp.SpeedLimit.Limit)
+	assert.Equal(t, download.SpeedSourceSchedule, resp.SpeedLimit.Source)
+	assert.Nil(t, resp.SpeedLimit.ExpiresAt)
+}
+
+// setupReleasePreview creates a server with a mock searcher, mock manager and event bus.
+func setupReleasePreview(t *testing.T, content *library.Content) (*http.ServeMux, *mocks.MockSearcher, *events.Bus) {
+	t.Helper()
+	db := testutil.OpenTestDB(t)
+	ctrl := gomock.NewController(t)
+	mockSearcher := mocks.NewMockSearcher(ctrl)
 	bus := events.NewBus(nil, nil)
-	defer bus.Close()
+	t.Cleanup(func() { bus.Close() })
 
-	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+	manager := mocks.NewMockDownloadManager(ctrl)
+	manager.EXPECT().Route(gomock.Any(), gomock.Any()).Return(download.Client("sabnzbd"), nil, nil).AnyTimes()
 
-	// Add series content
 	store := library.NewStore(db)
-	series := &library.Content{
-		Type:           library.ContentTypeSeries,
-		Title:          "Peaky Blinders",
-		Year:           2013,
-		Status:         library.StatusWanted,
-		QualityProfile: "hd",
-		RootPath:       "/tv",
-	}
-	require.NoError(t, store.AddContent(series))
+	require.NoError(t, store.AddContent(content))
 
-	deps := ServerDeps{
+	srv, err := NewWithDeps(ServerDeps{
 		Library:   store,
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Manager:   mockManager,
+		Searcher:  mockSearcher,
+		Manager:   manager,
 		Bus:       bus,
-	}
-	srv, err := NewWithDeps(deps, Config{})
+	}, Config{})
 	require.NoError(t, err)
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
+	return mux, mockSearcher, bus
+}
 
-	// Grab a season pack
-	body := fmt.Sprintf(`{
-		"content_id": %d,
-		"download_url": "http://example.com/nzb",
-		"title": "Peaky.Blinders.S01.1080p.BluRay.x264-DEMAND",
-		"indexer": "NZBgeek"
-	}`, series.ID)
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+func TestPreviewReleases_GrabByGUID(t *testing.T) {
+	movie := &library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          "Dune",
+		Year:           2021,
+		Status:         library.StatusWanted,
+		QualityProfile: "uhd",
+		RootPath:       "/movies",
+	}
+	mux, mockSearcher, bus := setupReleasePreview(t, movie)
+	grabs := bus.Subscribe(events.EventGrabRequested, 10)
 
-	mux.ServeHTTP(w, req)
+	mockSearcher.EXPECT().
+		Search(gomock.Any(), gomock.Any(), "uhd").
+		DoAndReturn(func(_ context.Context, q search.Query, _ string) (*search.Result, error) {
+			assert.Equal(t, search.Query{ContentID: movie.ID, Text: "Dune 2021", Type: "movie"}, q)
+			return &search.Result{
+				Releases: []*search.Release{
+					{Title: "Dune.2021.2160p.UHD.BluRay-A", GUID: "g1", Indexer: "nzbgeek", DownloadURL: "http://nzb/1", Score: 90},
+					{Title: "Dune.2021.2160p.WEB-DL-B", GUID: "g2", Indexer: "jackett", DownloadURL: "http://jackett/dl/2", Score: 80, Protocol: download.ProtocolTorrent, Seeders: 30},
+				},
+				Rejected: []*search.Rejection{
+					{Title: "Dune.2021.HDCAM", Indexer: "nzbgeek", Reason: `forbidden keyword "CAM"`},
+				},
+			}, nil
+		}).Times(1) // the grab must not search again
+
+	url := fmt.Sprintf("/api/v1/content/%d/releases", movie.ID)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
 
-	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+	var resp releasePreviewResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Dune 2021", resp.Query)
+	assert.Equal(t, "uhd", resp.Profile)
+	require.Len(t, resp.Releases, 2)
+	require.Len(t, resp.Rejected, 1)
+	assert.Equal(t, `forbidden keyword "CAM"`, resp.Rejected[0].Reason)
+	assert.Equal(t, "torrent", resp.Releases[1].Protocol)
+	assert.Equal(t, 30, resp.Releases[1].Seeders)
+
+	// Grab the second-best release, exactly as previewed
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"guid": "g2"}`)))
+	require.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
 
-	// Verify the event
 	select {
-	case evt := <-eventCh:
-		grabEvt, ok := evt.(*events.GrabRequested)
-		require.True(t, ok, "expected GrabRequested event")
-		assert.Equal(t, series.ID, grabEvt.ContentID)
-		assert.NotNil(t, grabEvt.Season)
-		assert.Equal(t, 1, *grabEvt.Season)
-		assert.True(t, grabEvt.IsCompleteSeason)
-		assert.Empty(t, grabEvt.EpisodeIDs, "season pack should not have episode IDs yet")
-	default:
-		t.Fatal("expected event to be published")
+	case e := <-grabs:
+		grab := e.(*events.GrabRequested)
+		assert.Equal(t, movie.ID, grab.ContentID)
+		assert.Equal(t, "g2", grab.GUID)
+		assert.Equal(t, "http://jackett/dl/2", grab.DownloadURL)
+		assert.Equal(t, "Dune.2021.2160p.WEB-DL-B", grab.ReleaseName)
+		assert.Equal(t, "torrent", grab.Protocol)
+	case <-time.After(time.Second):
+		t.Fatal("expected GrabRequested event")
 	}
-}
-
-func TestGrab_SeriesNoEpisodeInfo(t *testing.T) {
-	db := setupTestDB(t)
-	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
 
-	// Create event bus
-	bus := events.NewBus(nil, nil)
-	defer bus.Close()
+	// Unknown GUIDs are not grabbed
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"guid": "g3"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
 
-	// Add series content
-	store := library.NewStore(db)
+func TestPreviewReleases_SeriesEpisode(t *testing.T) {
 	series := &library.Content{
 		Type:           library.ContentTypeSeries,
-		Title:          "Game of Thrones",
-		Year:           2011,
+		Title:          "Severance",
+		Year:           2022,
 		Status:         library.StatusWanted,
 		QualityProfile: "hd",
 		RootPath:       "/tv",
 	}
-	require.NoError(t, store.AddContent(series))
-
-	deps := ServerDeps{
-		Library:   store,
-		Downloads: download.NewStore(db),
-		History:   importer.NewHistoryStore(db),
-		Manager:   mockManager,
-		Bus:       bus,
-	}
-	srv, err := NewWithDeps(deps, Config{})
-	require.NoError(t, err)
+	mux, mockSearcher, bus := setupReleasePreview(t, series)
+	grabs := bus.Subscribe(events.EventGrabRequested, 10)
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+	mockSearcher.EXPECT().
+		Search(gomock.Any(), gomock.Any(), "hd").
+		DoAndReturn(func(_ context.Context, q search.Query, _ string) (*search.Result, error) {
+			assert.Equal(t, "Severance S02E05", q.Text)
+			require.NotNil(t, q.Season)
+			require.NotNil(t, q.Episode)
+			return &search.Result{Releases: []*search.Release{
+				{Title: "Severance.S02E05.1080p.WEB-DL-X", GUID: "s2e5", Indexer: "nzbgeek", DownloadURL: "http://nzb/5"},
+			}}, nil
+		})
 
-	// Grab without season/episode info (should fail)
-	body := fmt.Sprintf(`{
-		"content_id": %d,
-		"download_url": "http://example.com/nzb",
-		"title": "Game.of.Thrones.1080p.BluRay.x264-DEMAND",
-		"indexer": "NZBgeek"
-	}`, series.ID)
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	url := fmt.Sprintf("/api/v1/content/%d/releases", series.ID)
 	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url+"?season=2&episode=5", nil))
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
 
-	mux.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"guid": "s2e5"}`)))
+	require.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
 
-	var resp errorResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Equal(t, "INVALID_RELEASE", resp.Code)
-	assert.Contains(t, resp.Error, "cannot determine season")
+	select {
+	case e := <-grabs:
+		grab := e.(*events.GrabRequested)
+		require.NotNil(t, grab.Season)
+		assert.Equal(t, 2, *grab.Season)
+		assert.Len(t, grab.EpisodeIDs, 1)
+	case <-time.After(time.Second):
+		t.Fatal("expected GrabRequested event")
+	}
 }
 
-func TestGrab_SeriesWithOverrides(t *testing.T) {
-	db := setupTestDB(t)
-	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
-
-	// Create event bus
-	bus := events.NewBus(nil, nil)
-	defer bus.Close()
-
-	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+func TestPreviewReleases_InvalidParams(t *testing.T) {
+	movie := &library.Content{Type: library.ContentTypeMovie, Title: "Dune", Year: 2021,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	mux, _, _ := setupReleasePreview(t, movie)
 
-	// Add series content
-	store := library.NewStore(db)
-	series := &library.Content{
-		Type:           library.ContentTypeSeries,
-		Title:          "The Mandalorian",
-		Year:           2019,
-		Status:         library.StatusWanted,
-		QualityProfile: "uhd",
-		RootPath:       "/tv",
+	tests := []struct {
+		url  string
+		code int
+	}{
+		{fmt.Sprintf("/api/v1/content/%d/releases?season=1", movie.ID), http.StatusBadRequest},
+		{fmt.Sprintf("/api/v1/content/%d/releases?season=x", movie.ID), http.StatusBadRequest},
+		{"/api/v1/content/999/releases", http.StatusNotFound},
 	}
-	require.NoError(t, store.AddContent(series))
-
-	deps := ServerDeps{
-		Library:   store,
-		Downloads: download.NewStore(db),
-		History:   importer.NewHistoryStore(db),
-		Manager:   mockManager,
-		Bus:       bus,
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		assert.Equal(t, tt.code, w.Code, tt.url)
 	}
-	srv, err := NewWithDeps(deps, Config{})
-	require.NoError(t, err)
+}
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+func TestPinRelease(t *testing.T) {
+	movie := &library.Content{Type: library.ContentTypeMovie, Title: "Dune", Year: 2021,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	mux, mockSearcher, _ := setupReleasePreview(t, movie)
+	mockSearcher.EXPECT().Search(gomock.Any(), gomock.Any(), "hd").Return(&search.Result{Releases: []*search.Release{
+		{Title: "Dune.2021.1080p.BluRay-A", GUID: "g1", Indexer: "nzbgeek", DownloadURL: "http://nzb/1"},
+	}}, nil)
 
-	// Grab with overrides (ignoring the release name parsing)
-	body := fmt.Sprintf(`{
-		"content_id": %d,
-		"download_url": "http://example.com/nzb",
-		"title": "The.Mandalorian.2160p.WEB-DL.DDP5.1.Atmos.HDR.x265",
-		"indexer": "NZBgeek",
-		"season": 2,
-		"episodes": [1, 2, 3]
-	}`, series.ID)
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	base := fmt.Sprintf("/api/v1/content/%d", movie.ID)
 	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, base+"/releases", nil))
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
 
-	mux.ServeHTTP(w, req)
+	// Pin a previewed release by GUID
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, base+"/pin", strings.NewReader(`{"guid": "g1"}`)))
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	var resp contentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.PinnedRelease)
+	assert.Equal(t, "http://nzb/1", resp.PinnedRelease.DownloadURL)
+	assert.Equal(t, "Dune.2021.1080p.BluRay-A", resp.PinnedRelease.Title)
+	assert.Equal(t, "nzbgeek", resp.PinnedRelease.Indexer)
 
-	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, base, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = contentResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.PinnedRelease)
+	assert.Equal(t, "g1", resp.PinnedRelease.GUID)
 
-	// Verify the event uses overrides
-	select {
-	case evt := <-eventCh:
-		grabEvt, ok := evt.(*events.GrabRequested)
-		require.True(t, ok, "expected GrabRequested event")
-		assert.NotNil(t, grabEvt.Season)
-		assert.Equal(t, 2, *grabEvt.Season)
-		assert.Len(t, grabEvt.EpisodeIDs, 3)
-		assert.False(t, grabEvt.IsCompleteSeason)
-	default:
-		t.Fatal("expected event to be published")
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"guid": "unknown"}`, http.StatusNotFound},
+		{`{"download_url": "http://nzb/2"}`, http.StatusBadRequest},
+		{`{"download_url": "http://nzb/2", "title": "Dune.2021.720p", "protocol": "ftp"}`, http.StatusBadRequest},
+		{`{"download_url": "http://nzb/2", "title": "Dune.2021.720p", "season": 1}`, http.StatusBadRequest},
+		{`{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, base+"/pin", strings.NewReader(tt.body)))
+		assert.Equal(t, tt.code, w.Code, tt.body)
 	}
 
-	// Verify episodes were created
-	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &series.ID})
-	require.NoError(t, err)
-	assert.Len(t, episodes, 3)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, base+"/pin", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, base+"/pin", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
-func TestGrab_MovieIgnoresEpisodeDetection(t *testing.T) {
-	db := setupTestDB(t)
-	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
-
-	// Create event bus
-	bus := events.NewBus(nil, nil)
-	defer bus.Close()
-
-	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+func TestPinRelease_SeriesNeedsSeason(t *testing.T) {
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Severance", Year: 2022,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	mux, _, _ := setupReleasePreview(t, series)
+	url := fmt.Sprintf("/api/v1/content/%d/pin", series.ID)
 
-	// Add movie content
-	store := library.NewStore(db)
-	movie := &library.Content{
-		Type:           library.ContentTypeMovie,
-		Title:          "Inception",
-		Year:           2010,
-		Status:         library.StatusWanted,
-		QualityProfile: "hd",
-		RootPath:       "/movies",
-	}
-	require.NoError(t, store.AddContent(movie))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"download_url": "http://nzb/s1", "title": "Severance.S01.1080p"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	deps := ServerDeps{
-		Library:   store,
-		Downloads: download.NewStore(db),
-		History:   importer.NewHistoryStore(db),
-		Manager:   mockManager,
-		Bus:       bus,
-	}
-	srv, err := NewWithDeps(deps, Config{})
-	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"download_url": "http://nzb/s1", "title": "Severance.S01.1080p", "season": 1}`)))
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	var resp contentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.PinnedRelease)
+	assert.Equal(t, 1, *resp.PinnedRelease.Season)
+}
 
+func TestGetMetrics(t *testing.T) {
+	srv := New(testutil.OpenTestDB(t), Config{})
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	// Grab movie (should not trigger episode detection)
-	body := fmt.Sprintf(`{
-		"content_id": %d,
-		"download_url": "http://example.com/nzb",
-		"title": "Inception.2010.1080p.BluRay.x264-DEMAND",
-		"indexer": "NZBgeek"
-	}`, movie.ID)
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
 
-	mux.ServeHTTP(w, req)
+	metrics := requestlog.NewMetrics()
+	metrics.Observe("GET /api/v1/content", http.StatusOK, 10*time.Millisecond)
+	srv.deps.Metrics = metrics
 
-	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `arrgo_http_requests_total{route="GET /api/v1/content",code="2xx"} 1`)
+}
 
-	// Verify the event has no episode info
-	select {
-	case evt := <-eventCh:
-		grabEvt, ok := evt.(*events.GrabRequested)
-		require.True(t, ok, "expected GrabRequested event")
-		assert.Nil(t, grabEvt.Season)
-		assert.Nil(t, grabEvt.EpisodeID)
-		assert.Empty(t, grabEvt.EpisodeIDs)
-		assert.False(t, grabEvt.IsCompleteSeason)
-	default:
-		t.Fatal("expected event to be published")
+func TestParseExternalID(t *testing.T) {
+	tests := []struct {
+		q      string
+		source string
+		id     int64
+		ok     bool
+	}{
+		{"tmdb:603", "tmdb", 603, true},
+		{"TVDB:81189", "tvdb", 81189, true},
+		{"imdb:tt0133093", "", 0, false},
+		{"tmdb:abc", "", 0, false},
+		{"tmdb:-1", "", 0, false},
+		{"the matrix", "", 0, false},
+	}
+	for _, tt := range tests {
+		source, id, ok := parseExternalID(tt.q)
+		assert.Equal(t, tt.ok, ok, tt.q)
+		assert.Equal(t, tt.source, source, tt.q)
+		assert.Equal(t, tt.id, id, tt.q)
 	}
 }
 
-func TestGrab_MultiEpisodeRelease(t *testing.T) {
-	db := setupTestDB(t)
-	mockManager := mocks.NewMockDownloadManager(gomock.NewController(t))
-
-	// Create event bus
-	bus := events.NewBus(nil, nil)
-	defer bus.Close()
-
-	eventCh := bus.Subscribe(events.EventGrabRequested, 10)
+func setupLookup(t *testing.T) (*http.ServeMux, *library.Store, *mocks.MockTMDBService, *mocks.MockTVDBService) {
+	t.Helper()
+	db := testutil.OpenTestDB(t)
+	ctrl := gomock.NewController(t)
+	mockTMDB := mocks.NewMockTMDBService(ctrl)
+	mockTVDB := mocks.NewMockTVDBService(ctrl)
 
-	// Add series content
 	store := library.NewStore(db)
-	series := &library.Content{
-		Type:           library.ContentTypeSeries,
-		Title:          "House of the Dragon",
-		Year:           2022,
-		Status:         library.StatusWanted,
-		QualityProfile: "uhd",
-		RootPath:       "/tv",
-	}
-	require.NoError(t, store.AddContent(series))
-
-	deps := ServerDeps{
+	srv, err := NewWithDeps(ServerDeps{
 		Library:   store,
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-		Manager:   mockManager,
-		Bus:       bus,
-	}
-	srv, err := NewWithDeps(deps, Config{})
+	}, Config{})
 	require.NoError(t, err)
+	srv.SetTMDB(mockTMDB)
+	srv.SetTVDB(mockTVDB)
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
+	return mux, store, mockTMDB, mockTVDB
+}
 
-	// Grab multi-episode release (e.g., S01E01-E03)
-	body := fmt.Sprintf(`{
-		"content_id": %d,
-		"download_url": "http://example.com/nzb",
-		"title": "House.of.the.Dragon.S01E01-E03.2160p.HMAX.WEB-DL.x265",
-		"indexer": "NZBgeek"
-	}`, series.ID)
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/grab", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+func doLookup(t *testing.T, mux *http.ServeMux, query string) (int, lookupResponse) {
+	t.Helper()
 	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/lookup?"+query, nil))
+	var resp lookupResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp
+}
 
-	mux.ServeHTTP(w, req)
+func TestLookup_MovieSearch(t *testing.T) {
+	mux, store, mockTMDB, _ := setupLookup(t)
 
-	assert.Equal(t, http.StatusAccepted, w.Code, "response body: %s", w.Body.String())
+	tracked := int64(603)
+	require.NoError(t, store.AddContent(&library.Content{
+		Type: library.ContentTypeMovie, TMDBID: &tracked, Title: "The Matrix", Year: 1999,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/movies",
+	}))
 
-	// Verify the event
-	select {
-	case evt := <-eventCh:
-		grabEvt, ok := evt.(*events.GrabRequested)
-		require.True(t, ok, "expected GrabRequested event")
-		assert.NotNil(t, grabEvt.Season)
-		assert.Equal(t, 1, *grabEvt.Season)
-		assert.Len(t, grabEvt.EpisodeIDs, 3, "should have 3 episode IDs for E01-E03")
-		assert.Nil(t, grabEvt.EpisodeID, "EpisodeID should be nil for multi-episode")
-		assert.False(t, grabEvt.IsCompleteSeason)
-	default:
-		t.Fatal("expected event to be published")
-	}
+	mockTMDB.EXPECT().SearchMovies(gomock.Any(), "the matrix").Return([]tmdb.Movie{
+		{ID: 603, Title: "The Matrix", ReleaseDate: "1999-03-31", Overview: "Set in the 22nd century..."},
+		{ID: 604, Title: "The Matrix Reloaded", ReleaseDate: "2003-05-15"},
+	}, nil)
 
-	// Verify episodes were created
-	episodes, _, err := store.ListEpisodes(library.EpisodeFilter{ContentID: &series.ID})
-	require.NoError(t, err)
-	assert.Len(t, episodes, 3)
+	code, resp := doLookup(t, mux, "type=movie&q=the+matrix")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, "movie", resp.Results[0].Type)
+	assert.Equal(t, int64(603), *resp.Results[0].TMDBID)
+	assert.Equal(t, 1999, resp.Results[0].Year)
+	assert.Equal(t, "Set in the 22nd century...", resp.Results[0].Overview)
+	require.NotNil(t, resp.Results[0].ContentID, "tracked movie should report its library ID")
+	assert.Nil(t, resp.Results[1].ContentID)
 }
 
-func TestTVDBSearch_Success(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestLookup_ByExternalID(t *testing.T) {
+	mux, _, mockTMDB, mockTVDB := setupLookup(t)
+
+	mockTMDB.EXPECT().GetMovie(gomock.Any(), int64(603)).Return(&tmdb.Movie{ID: 603, Title: "The Matrix", ReleaseDate: "1999-03-31"}, nil)
+	code, resp := doLookup(t, mux, "q=tmdb:603")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, "movie", resp.Results[0].Type, "type is implied by the ID source")
+	assert.Equal(t, "The Matrix", resp.Results[0].Title)
+
+	mockTVDB.EXPECT().GetSeries(gomock.Any(), 81189).Return(&tvdb.Series{ID: 81189, Name: "Breaking Bad", Year: 2008}, nil)
+	code, resp = doLookup(t, mux, "type=series&q=tvdb:81189")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, int64(81189), *resp.Results[0].TVDBID)
+	assert.Equal(t, 2008, resp.Results[0].Year)
+
+	mockTMDB.EXPECT().GetMovie(gomock.Any(), int64(999999)).Return(nil, tmdb.ErrNotFound)
+	code, resp = doLookup(t, mux, "q=tmdb:999999")
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, resp.Results)
+}
 
-	db := setupTestDB(t)
-	mockTVDB := mocks.NewMockTVDBService(ctrl)
+func TestLookup_InvalidParams(t *testing.T) {
+	mux, _, _, _ := setupLookup(t)
 
-	// Set up expectation for search
-	mockTVDB.EXPECT().
-		Search(gomock.Any(), "breaking bad").
-		Return([]tvdb.SearchResult{
-			{
-				ID:       81189,
-				Name:     "Breaking Bad",
-				Year:     2008,
-				Status:   "Ended",
-				Overview: "A high school chemistry teacher diagnosed with inoperable lung cancer...",
-				Network:  "AMC",
-			},
-		}, nil)
+	code, _ := doLookup(t, mux, "type=movie")
+	assert.Equal(t, http.StatusBadRequest, code, "missing query")
+	code, _ = doLookup(t, mux, "q=the+matrix")
+	assert.Equal(t, http.StatusBadRequest, code, "title lookups need a type")
+	code, _ = doLookup(t, mux, "type=series&q=tmdb:603")
+	assert.Equal(t, http.StatusBadRequest, code, "series are not looked up by TMDB ID")
 
-	deps := ServerDeps{
+	// Providers are optional
+	db := testutil.OpenTestDB(t)
+	srv, err := NewWithDeps(ServerDeps{
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
-	}
-	srv, err := NewWithDeps(deps, Config{})
+	}, Config{})
 	require.NoError(t, err)
-	srv.SetTVDB(mockTVDB)
+	bare := http.NewServeMux()
+	srv.RegisterRoutes(bare)
+	code, _ = doLookup(t, bare, "type=movie&q=the+matrix")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+func TestCollection_ListAndAddMissing(t *testing.T) {
+	mux, store, mockTMDB, _ := setupLookup(t)
+
+	matrixID, collectionID := int64(603), int64(2344)
+	matrix := &library.Content{Type: library.ContentTypeMovie, TMDBID: &matrixID, Title: "The Matrix", Year: 1999,
+		Status: library.StatusAvailable, QualityProfile: "uhd", RootPath: "/media/films",
+		CollectionID: &collectionID, CollectionName: "The Matrix Collection"}
+	require.NoError(t, store.AddContent(matrix))
+	// Tracked without a TMDB ID: only duplicate detection on add finds it
+	revolutions := &library.Content{Type: library.ContentTypeMovie, Title: "The Matrix Revolutions", Year: 2003,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(revolutions))
+	require.NoError(t, store.AddExclusion(&library.Exclusion{Source: library.ExclusionTMDB, ExternalID: 624860, Title: "The Matrix Resurrections"}))
+
+	mockTMDB.EXPECT().GetCollection(gomock.Any(), collectionID).Return(&tmdb.Collection{
+		ID: collectionID, Name: "The Matrix Collection",
+		Parts: []tmdb.Movie{
+			{ID: 999001, Title: "The Matrix 5"},
+			{ID: 604, Title: "The Matrix Reloaded", ReleaseDate: "2003-05-15"},
+			{ID: 603, Title: "The Matrix", ReleaseDate: "1999-03-31"},
+			{ID: 605, Title: "The Matrix Revolutions", ReleaseDate: "2003-11-05"},
+			{ID: 624860, Title: "The Matrix Resurrections", ReleaseDate: "2021-12-16"},
+		},
+	}, nil).AnyTimes()
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/tvdb/search?q=breaking+bad", nil)
+	path := fmt.Sprintf("/api/v1/content/%d/collection", matrix.ID)
 	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list collectionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, "The Matrix Collection", list.Name)
+	require.Len(t, list.Members, 5)
+	titles := make([]string, 0, len(list.Members))
+	for _, m := range list.Members {
+		titles = append(titles, m.Title)
+	}
+	assert.Equal(t, []string{"The Matrix", "The Matrix Reloaded", "The Matrix Revolutions", "The Matrix Resurrections", "The Matrix 5"}, titles,
+		"members are by year, unreleased last")
+	assert.True(t, list.Members[0].Tracked)
+	assert.Equal(t, matrix.ID, list.Members[0].ContentID)
+	assert.False(t, list.Members[1].Tracked)
+	assert.Equal(t, 4, list.Untracked)
+
+	// Listing adds nothing
+	all, _, err := store.ListContent(library.ContentFilter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	addMissing := func(body string) collectionAddResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path+"/add-missing", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp collectionAddResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
 
-	mux.ServeHTTP(w, req)
+	// Only the chosen members are added
+	resp := addMissing(`{"tmdb_ids":[604]}`)
+	require.Len(t, resp.Added, 1)
+	assert.Empty(t, resp.Skipped)
+	reloaded, err := store.GetContent(resp.Added[0].ContentID)
+	require.NoError(t, err)
+	assert.Equal(t, "The Matrix Reloaded", reloaded.Title)
+	assert.Equal(t, 2003, reloaded.Year)
+	assert.Equal(t, int64(604), *reloaded.TMDBID)
+	assert.Equal(t, library.StatusWanted, reloaded.Status)
+	assert.Equal(t, "uhd", reloaded.QualityProfile, "source movie's profile")
+	assert.Equal(t, "/media/films", reloaded.RootPath, "source movie's root")
+
+	resp = addMissing("")
+	require.Len(t, resp.Added, 1)
+	assert.Equal(t, "The Matrix 5", resp.Added[0].Title)
+	require.Len(t, resp.Skipped, 2)
+	assert.Equal(t, "The Matrix Revolutions", resp.Skipped[0].Title)
+	assert.Equal(t, "already exists", resp.Skipped[0].Reason)
+	assert.Equal(t, "excluded", resp.Skipped[1].Reason)
+
+	all, _, err = store.ListContent(library.ContentFilter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+}
 
-	assert.Equal(t, http.StatusOK, w.Code)
+func TestCollection_Errors(t *testing.T) {
+	mux, store, mockTMDB, _ := setupLookup(t)
 
-	var results []tvdb.SearchResult
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
-	assert.Len(t, results, 1)
-	assert.Equal(t, 81189, results[0].ID)
-	assert.Equal(t, "Breaking Bad", results[0].Name)
-	assert.Equal(t, 2008, results[0].Year)
+	get := func(id int64) int {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/content/%d/collection", id), nil))
+		return w.Code
+	}
+
+	// A movie without a stored collection is looked up on TMDB
+	heatID := int64(949)
+	heat := &library.Content{Type: library.ContentTypeMovie, TMDBID: &heatID, Title: "Heat", Year: 1995,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(heat))
+	mockTMDB.EXPECT().GetMovie(gomock.Any(), heatID).Return(&tmdb.Movie{ID: heatID, Title: "Heat"}, nil)
+	assert.Equal(t, http.StatusNotFound, get(heat.ID), "not in a collection")
+
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Show", Year: 2020,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, store.AddContent(series))
+	assert.Equal(t, http.StatusBadRequest, get(series.ID))
+	assert.Equal(t, http.StatusNotFound, get(9999))
 }
 
-func TestTVDBSearch_MissingQuery(t *testing.T) {
-	db := setupTestDB(t)
+func TestPublicSummary(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{PublicStatus: true})
 
-	deps := ServerDeps{
-		Library:   library.NewStore(db),
-		Downloads: download.NewStore(db),
-		History:   importer.NewHistoryStore(db),
+	movie := &library.Content{Type: library.ContentTypeMovie, Title: "Public Movie", Year: 2024,
+		Status: library.StatusAvailable, QualityProfile: "hd", RootPath: "/srv/media/movies"}
+	require.NoError(t, srv.deps.Library.AddContent(movie))
+	require.NoError(t, srv.deps.Library.AddFile(&library.File{
+		ContentID: movie.ID, Path: "/srv/media/movies/Public Movie (2024)/Public Movie (2024).mkv",
+		SizeBytes: 1000, Quality: "1080p", Source: "https://indexer.example.com/get/123",
+	}))
+	series := &library.Content{Type: library.ContentTypeSeries, Title: "Public Show", Year: 2023,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/srv/media/tv"}
+	require.NoError(t, srv.deps.Library.AddContent(series))
+	ep := &library.Episode{ContentID: series.ID, Season: 2, Episode: 5, Status: library.StatusAvailable}
+	require.NoError(t, srv.deps.Library.AddEpisode(ep))
+	require.NoError(t, srv.deps.Library.AddFile(&library.File{
+		ContentID: series.ID, EpisodeID: &ep.ID, Path: `C:\media\tv\Public Show\S02E05.mkv`, Quality: "720p",
+	}))
+
+	for _, dl := range []*download.Download{
+		{ContentID: movie.ID, Client: download.ClientSABnzbd, ClientID: "a", Status: download.StatusDownloading,
+			ReleaseName: "Public.Movie.2024.1080p", Indexer: "secret-indexer", Progress: 50, Size: 3000},
+		{ContentID: series.ID, Client: download.ClientSABnzbd, ClientID: "b", Status: download.StatusQueued,
+			ReleaseName: "Public.Show.S02E06", Indexer: "secret-indexer", Size: 1000},
+		{ContentID: series.ID, Client: download.ClientSABnzbd, ClientID: "c", Status: download.StatusImported,
+			ReleaseName: "Public.Show.S02E05", Indexer: "secret-indexer", Progress: 100, Size: 1000},
+	} {
+		require.NoError(t, srv.deps.Downloads.Add(dl))
+		require.NoError(t, srv.deps.Downloads.UpdateProgress(dl.ID, dl.Progress, 0, 0, dl.Size))
 	}
-	srv, err := NewWithDeps(deps, Config{})
-	require.NoError(t, err)
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/tvdb/search", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/public/summary", nil)
 	w := httptest.NewRecorder()
-
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, http.StatusOK, w.Code, "response: %s", w.Body.String())
+	assert.Equal(t, "public, max-age=30", w.Header().Get("Cache-Control"))
 
-	var resp errorResponse
+	// Nothing path-like, URL-like, or indexer-related may leak
+	body := w.Body.String()
+	for _, leak := range []string{"/", `\`, "srv", "media", ".mkv", "http", "indexer", "Public.Movie"} {
+		assert.NotContains(t, body, leak)
+	}
+
+	var resp publicSummaryResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Contains(t, resp.Error, "query parameter is required")
+	require.Len(t, resp.RecentlyAdded, 2)
+	assert.Equal(t, publicImportResponse{Type: "series", Title: "Public Show", Year: 2023, Quality: "720p", Season: 2, Episode: 5}, resp.RecentlyAdded[0])
+	assert.Equal(t, publicImportResponse{Type: "movie", Title: "Public Movie", Year: 2024, Quality: "1080p"}, resp.RecentlyAdded[1])
+	assert.Equal(t, 2, resp.Downloads.Active, "imported downloads are not active")
+	assert.InDelta(t, 37.5, resp.Downloads.Progress, 0.01, "progress is weighted by size")
+	assert.Equal(t, publicLibraryResponse{Movies: 1, Series: 1, Episodes: 1}, resp.Library)
 }
 
-func TestTVDBSearch_NotConfigured(t *testing.T) {
-	db := setupTestDB(t)
-
-	deps := ServerDeps{
-		Library:   library.NewStore(db),
-		Downloads: download.NewStore(db),
-		History:   importer.NewHistoryStore(db),
-	}
-	srv, err := NewWithDeps(deps, Config{})
-	require.NoError(t, err)
-	// Note: Not calling SetTVDB, so tvdbSvc remains nil
+func TestPublicSummary_Disabled(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	srv := New(db, Config{})
 
 	mux := http.NewServeMux()
 	srv.RegisterRoutes(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/tvdb/search?q=test", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/public/summary", nil)
 	w := httptest.NewRecorder()
-
 	mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
-
-	var resp errorResponse
-	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Contains(t, resp.Error, "TVDB not configured")
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
-func TestTVDBSearch_SearchError(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	db := setupTestDB(t)
-	mockTVDB := mocks.NewMockTVDBService(ctrl)
+func TestSnapshot_ExportImport(t *testing.T) {
+	src := New(testutil.OpenTestDB(t), Config{})
+	lib := src.deps.Library
+	matrixID, breakingBadID := int64(603), int64(81189)
+	movie := &library.Content{Type: library.ContentTypeMovie, TMDBID: &matrixID, Title: "The Matrix", Year: 1999,
+		Status: library.StatusAvailable, QualityProfile: "uhd", RootPath: "/movies"}
+	require.NoError(t, lib.AddContent(movie))
+	require.NoError(t, lib.AddFile(&library.File{ContentID: movie.ID, Path: "/movies/The Matrix (1999)/The Matrix (1999).mkv",
+		SizeBytes: 1000, Quality: "2160p"}))
+	series := &library.Content{Type: library.ContentTypeSeries, TVDBID: &breakingBadID, Title: "Breaking Bad", Year: 2008,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, lib.AddContent(series))
+	require.NoError(t, lib.AddEpisode(&library.Episode{ContentID: series.ID, Season: 1, Episode: 1, Title: "Pilot",
+		Status: library.StatusWanted, QualityProfile: "uhd"}))
 
-	// Set up expectation for search failure
-	mockTVDB.EXPECT().
-		Search(gomock.Any(), "test").
-		Return(nil, fmt.Errorf("TVDB API error"))
+	w := httptest.NewRecorder()
+	src.exportLibrary(w, httptest.NewRequest(http.MethodGet, "/api/v1/export", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	snapshot := w.Body.String()
 
-	deps := ServerDeps{
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+	progress := bus.Subscribe(events.EventSnapshotProgressed, 10)
+	db := testutil.OpenTestDB(t)
+	dst, err := NewWithDeps(ServerDeps{
 		Library:   library.NewStore(db),
 		Downloads: download.NewStore(db),
 		History:   importer.NewHistoryStore(db),
+		Bus:       bus,
+	}, Config{})
+	require.NoError(t, err)
+
+	restore := func(query string) (*httptest.ResponseRecorder, snapshotImportResponse) {
+		w := httptest.NewRecorder()
+		dst.importSnapshot(w, httptest.NewRequest(http.MethodPost, "/api/v1/import/snapshot"+query, strings.NewReader(snapshot)))
+		var resp snapshotImportResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w, resp
 	}
-	srv, err := NewWithDeps(deps, Config{})
+
+	w, resp := restore("")
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	assert.Equal(t, "skip-existing", resp.Mode)
+	assert.Equal(t, 2, resp.Total)
+	assert.Equal(t, 2, resp.Created)
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, "The Matrix", resp.Items[0].Title)
+	assert.Equal(t, "created", resp.Items[0].Result)
+
+	select {
+	case e := <-progress:
+		evt, ok := e.(*events.SnapshotProgressed)
+		require.True(t, ok)
+		assert.Equal(t, 2, evt.Processed)
+		assert.True(t, evt.Done)
+	case <-time.After(time.Second):
+		t.Fatal("SnapshotProgressed not published")
+	}
+
+	restored, err := dst.deps.Library.GetByTitleYear("Breaking Bad", 2008)
 	require.NoError(t, err)
-	srv.SetTVDB(mockTVDB)
+	require.NotNil(t, restored)
+	episodes, _, err := dst.deps.Library.ListEpisodes(library.EpisodeFilter{ContentID: &restored.ID})
+	require.NoError(t, err)
+	require.Len(t, episodes, 1)
+	assert.Equal(t, "uhd", episodes[0].QualityProfile)
 
-	mux := http.NewServeMux()
-	srv.RegisterRoutes(mux)
+	// Restoring again skips or overwrites what is already there
+	_, resp = restore("?mode=skip-existing")
+	assert.Equal(t, 2, resp.Skipped)
+	_, resp = restore("?mode=overwrite")
+	assert.Equal(t, 2, resp.Updated)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/tvdb/search?q=test", nil)
-	w := httptest.NewRecorder()
+	w, _ = restore("?mode=merge")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
 
-	mux.ServeHTTP(w, req)
+func TestImportSnapshot_Invalid(t *testing.T) {
+	srv := New(testutil.OpenTestDB(t), Config{})
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.importSnapshot(w, httptest.NewRequest(http.MethodPost, "/api/v1/import/snapshot", strings.NewReader(body)))
+		return w
+	}
 
-	var resp errorResponse
+	w := post(`{"version": 99, "content": []}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "UNSUPPORTED_VERSION")
+
+	w = post(`{"version": 1, "content": [{"type": "movie", "title": "Broken"`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_SNAPSHOT")
+
+	// Invalid items fail on their own without stopping the import
+	w = post(`{"version": 1, "content": [
+		{"type": "movie", "title": "Good", "year": 2020, "status": "wanted", "quality_profile": "hd", "root_path": "/movies"},
+		{"type": "book", "title": "Bad", "year": 2020, "status": "wanted"}
+	]}`)
+	require.Equal(t, http.StatusOK, w.Code, "response body: %s", w.Body.String())
+	var resp snapshotImportResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-	assert.Contains(t, resp.Error, "TVDB API error")
+	assert.Equal(t, 1, resp.Created)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Equal(t, "failed", resp.Items[1].Result)
+	assert.NotEmpty(t, resp.Items[1].Error)
 }
@@ -0,0 +1,239 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/vmunix/arrgo/internal/importer"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// importArrLibrary imports the movies of a Radarr database or the series of a
+// Sonarr database, with their episodes and files.
+func (s *Server) importArrLibrary(w http.ResponseWriter, req libraryImportRequest) {
+	if req.Path == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PATH", "path is required")
+		return
+	}
+	path := filepath.Clean(req.Path)
+	if !filepath.IsAbs(path) {
+		writeError(w, http.StatusBadRequest, "INVALID_PATH", "path must be absolute")
+		return
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		writeError(w, http.StatusBadRequest, "INVALID_PATH", "not a file: "+path)
+		return
+	}
+	if req.QualityOverride != "" && !s.knownProfile(req.QualityOverride) {
+		writeError(w, http.StatusBadRequest, "INVALID_PROFILE", "unknown quality profile: "+req.QualityOverride)
+		return
+	}
+	for from, to := range req.QualityMap {
+		if !s.knownProfile(to) {
+			writeError(w, http.StatusBadRequest, "INVALID_PROFILE", fmt.Sprintf("quality_map %q: unknown quality profile: %s", from, to))
+			return
+		}
+	}
+
+	read := importer.ReadRadarr
+	if req.Source == "sonarr" {
+		read = importer.ReadSonarr
+	}
+	items, err := read(path)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "INVALID_DATABASE", err.Error())
+		return
+	}
+
+	resp := s.processArrImport(items, req.Source+"-import", req.QualityMap, req.QualityOverride, req.DryRun)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// processArrImport processes movies or series read from a Radarr or Sonarr
+// database. Content already tracked, by external ID or by title and year, is
+// skipped.
+func (s *Server) processArrImport(items []*importer.ArrItem, source string, qualityMap map[string]string, qualityOverride string, dryRun bool) libraryImportResponse {
+	resp := newLibraryImportResponse()
+
+	for _, item := range items {
+		importItem := libraryImportItem{
+			Title:   item.Title,
+			Year:    item.Year,
+			Type:    string(item.Type),
+			Path:    item.Path,
+			Quality: arrImportProfile(item, qualityMap, qualityOverride),
+		}
+		if item.Type == library.ContentTypeSeries {
+			importItem.Episodes = len(item.Episodes)
+		}
+
+		if existing := s.trackedArrContent(item); existing != nil {
+			importItem.ContentID = existing.ID
+			importItem.Reason = "already tracked"
+			resp.Skipped = append(resp.Skipped, importItem)
+			continue
+		}
+
+		if !dryRun {
+			contentID, err := s.createArrContent(item, source, importItem.Quality)
+			if err != nil {
+				importItem.Error = err.Error()
+				resp.Errors = append(resp.Errors, importItem)
+				continue
+			}
+			importItem.ContentID = contentID
+		}
+
+		resp.Imported = append(resp.Imported, importItem)
+	}
+
+	resp.Summary.Imported = len(resp.Imported)
+	resp.Summary.Skipped = len(resp.Skipped)
+	resp.Summary.Errors = len(resp.Errors)
+
+	return resp
+}
+
+// arrImportProfile returns the quality profile for an imported item: the
+// override, else its Radarr or Sonarr profile mapped by ID or name, else the
+// one matching the resolution of its first file, else "hd".
+func arrImportProfile(item *importer.ArrItem, qualityMap map[string]string, qualityOverride string) string {
+	if qualityOverride != "" {
+		return qualityOverride
+	}
+	if profile, ok := qualityMap[strconv.FormatInt(item.ProfileID, 10)]; ok {
+		return profile
+	}
+	if profile, ok := qualityMap[item.ProfileName]; ok && item.ProfileName != "" {
+		return profile
+	}
+	if f := item.FirstFile(); f != nil {
+		return mapResolutionToProfile(f.Resolution)
+	}
+	return "hd"
+}
+
+// trackedArrContent returns the content already tracked for an item, matched
+// by TMDB or TVDB ID, else by title and year; nil if there is none.
+func (s *Server) trackedArrContent(item *importer.ArrItem) *library.Content {
+	contentType := item.Type
+	if item.TMDBID != nil || item.TVDBID != nil {
+		existing, _, _ := s.deps.Library.ListContent(library.ContentFilter{
+			Type:   &contentType,
+			TMDBID: item.TMDBID,
+			TVDBID: item.TVDBID,
+			Limit:  1,
+		})
+		if len(existing) > 0 {
+			return existing[0]
+		}
+	}
+	title := item.Title
+	year := item.Year
+	existing, _, _ := s.deps.Library.ListContent(library.ContentFilter{
+		Type:  &contentType,
+		Title: &title,
+		Year:  &year,
+		Limit: 1,
+	})
+	if len(existing) > 0 {
+		return existing[0]
+	}
+	return nil
+}
+
+// createArrContent creates content, episode and file records for a movie or
+// series from a Radarr or Sonarr database in one transaction, so a failure
+// leaves nothing behind. Monitoring carries over: unmonitored content and
+// episodes are not searched for, and missing monitored ones are wanted.
+func (s *Server) createArrContent(item *importer.ArrItem, source, qualityProfile string) (int64, error) {
+	tx, err := s.deps.Library.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	content := &library.Content{
+		Type:           item.Type,
+		TMDBID:         item.TMDBID,
+		TVDBID:         item.TVDBID,
+		Title:          item.Title,
+		Year:           item.Year,
+		Status:         arrStatus(item.Monitored, item.HasFiles()),
+		QualityProfile: qualityProfile,
+		RootPath:       filepath.Dir(item.Path),
+		Daily:          item.Daily,
+	}
+	if err := tx.AddContent(content); err != nil {
+		return 0, fmt.Errorf("create content: %w", err)
+	}
+
+	for _, f := range item.Files {
+		if err := tx.AddFile(arrLibraryFile(content.ID, nil, f, source)); err != nil {
+			return 0, fmt.Errorf("create file %s: %w", f.Path, err)
+		}
+	}
+
+	// A multi-episode file is recorded once, on its first episode
+	recorded := make(map[*importer.ArrFile]bool)
+	for _, e := range item.Episodes {
+		ep := &library.Episode{
+			ContentID: content.ID,
+			Season:    e.Season,
+			Episode:   e.Episode,
+			Title:     e.Title,
+			Status:    arrStatus(item.Monitored && e.Monitored, e.File != nil),
+			AirDate:   e.AirDate,
+		}
+		if err := tx.AddEpisode(ep); err != nil {
+			return 0, fmt.Errorf("create episode S%02dE%02d: %w", e.Season, e.Episode, err)
+		}
+		if e.File == nil || recorded[e.File] {
+			continue
+		}
+		recorded[e.File] = true
+		if err := tx.AddFile(arrLibraryFile(content.ID, &ep.ID, e.File, source)); err != nil {
+			return 0, fmt.Errorf("create file %s: %w", e.File.Path, err)
+		}
+	}
+	if len(item.Seasons) > 0 {
+		if err := tx.SetSeasonMonitoring(content.ID, item.Seasons); err != nil {
+			return 0, fmt.Errorf("set season monitoring: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return content.ID, nil
+}
+
+// arrStatus returns the status of imported content or an episode:
+// unmonitored if it was not monitored, else available if it has a file, else
+// wanted.
+func arrStatus(monitored, hasFile bool) library.ContentStatus {
+	switch {
+	case !monitored:
+		return library.StatusUnmonitored
+	case hasFile:
+		return library.StatusAvailable
+	default:
+		return library.StatusWanted
+	}
+}
+
+// arrLibraryFile returns the library record of a Radarr or Sonarr file.
+func arrLibraryFile(contentID int64, episodeID *int64, f *importer.ArrFile, source string) *library.File {
+	return &library.File{
+		ContentID:    contentID,
+		EpisodeID:    episodeID,
+		Path:         f.Path,
+		SizeBytes:    f.Size,
+		Quality:      f.Resolution.String(),
+		Source:       source,
+		ReleaseGroup: f.Group,
+	}
+}
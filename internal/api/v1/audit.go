@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/events"
+)
+
+// listAudit handles GET /api/v1/audit.
+// Lists state-changing API requests, newest first. Filters: method, path
+// (prefix), key (API key fingerprint), key_name, failed=true|false (status 400 or
+// above), since and until (RFC3339).
+func (s *Server) listAudit(w http.ResponseWriter, r *http.Request) {
+	if s.deps.EventLog == nil {
+		writeError(w, http.StatusServiceUnavailable, "NO_EVENT_LOG", "Event log not configured")
+		return
+	}
+	page, ok := parseEventFilter(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	filter := events.AuditFilter{
+		Method:     q.Get("method"),
+		PathPrefix: q.Get("path"),
+		KeyPrint:   q.Get("key"),
+		KeyName:    q.Get("key_name"),
+		Since:      page.Since,
+		Until:      page.Until,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+	}
+	switch failed := q.Get("failed"); failed {
+	case "":
+	case queryTrue, "false":
+		isFailed := failed == queryTrue
+		filter.Failed = &isFailed
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_FILTER", "failed must be true or false")
+		return
+	}
+
+	raw, total, err := s.deps.EventLog.ListAudit(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
+		return
+	}
+
+	registry := events.DefaultRegistry()
+	items := make([]auditResponse, 0, len(raw))
+	for _, e := range raw {
+		decoded, err := registry.Unmarshal(e)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
+			return
+		}
+		audit, ok := decoded.(*events.APIAudit)
+		if !ok {
+			continue
+		}
+		items = append(items, auditResponse{
+			ID:         e.ID,
+			OccurredAt: e.OccurredAt.Format(time.RFC3339),
+			Method:     audit.Method,
+			Path:       audit.Path,
+			Route:      audit.Route,
+			Params:     audit.Params,
+			Query:      audit.Query,
+			Body:       audit.Body,
+			Remote:     audit.Remote,
+			KeyPrint:   audit.KeyPrint,
+			KeyName:    audit.KeyName,
+			Status:     audit.Status,
+			DurationMS: audit.DurationMS,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, listAuditResponse{
+		Items:  items,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/importer"
+)
+
+// defaultOrphanMinAge is how long an orphan must be unchanged when
+// Config.OrphanMinAge is unset.
+const defaultOrphanMinAge = 7 * 24 * time.Hour
+
+// removeJunk removes a canceled download's release directory if the client
+// left only junk in it, and publishes what was removed as JunkRemoved.
+func (s *Server) removeJunk(ctx context.Context, dl *download.Download) {
+	sourcePath := download.SourcePath(s.cfg.DownloadRoot, dl)
+	if !download.UnderRoot(s.cfg.DownloadRoot, sourcePath) {
+		return
+	}
+	removed, err := importer.RemoveJunk(sourcePath, s.cfg.Junk)
+	if err != nil || len(removed) == 0 || s.deps.Bus == nil {
+		return
+	}
+	_ = s.deps.Bus.Publish(ctx, &events.JunkRemoved{
+		BaseEvent:  events.NewBaseEvent(events.EventJunkRemoved, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		SourcePath: sourcePath,
+		Removed:    removed,
+		Trigger:    "cancel",
+	})
+}
+
+// findOrphans lists the orphans of the download root, writing an error
+// response if they can't be listed.
+func (s *Server) findOrphans(w http.ResponseWriter) ([]download.Orphan, time.Duration, bool) {
+	if s.cfg.DownloadRoot == "" {
+		writeError(w, http.StatusServiceUnavailable, "NO_DOWNLOAD_ROOT", "No download client has a local path configured")
+		return nil, 0, false
+	}
+	minAge := s.cfg.OrphanMinAge
+	if minAge <= 0 {
+		minAge = defaultOrphanMinAge
+	}
+
+	downloads, _, err := s.deps.Downloads.List(download.Filter{Active: true})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return nil, 0, false
+	}
+	var categories []string
+	for _, c := range s.cfg.Categories {
+		categories = append(categories, c.Default, c.Movie, c.Series)
+	}
+
+	orphans, err := download.FindOrphans(s.cfg.DownloadRoot, categories, downloads, minAge, time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "SCAN_ERROR", err.Error())
+		return nil, 0, false
+	}
+	return orphans, minAge, true
+}
+
+// listOrphans handles GET /api/v1/downloads/orphans.
+// Lists the entries of the download root that no active download refers to
+// and that haven't changed for downloads.orphan_min_age_days. Nothing is
+// removed; see cleanOrphans.
+func (s *Server) listOrphans(w http.ResponseWriter, _ *http.Request) {
+	orphans, minAge, ok := s.findOrphans(w)
+	if !ok {
+		return
+	}
+
+	resp := listOrphansResponse{Items: make([]orphanResponse, 0, len(orphans)), MinAgeDays: int(minAge / (24 * time.Hour))}
+	for _, o := range orphans {
+		resp.Items = append(resp.Items, orphanResponse{Path: o.Path, SizeBytes: o.Size, ModifiedAt: o.Modified.UTC().Format(time.RFC3339)})
+		resp.TotalBytes += o.Size
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// cleanOrphans handles POST /api/v1/downloads/orphans/clean.
+// Removes the orphans of the download root, found afresh so nothing that
+// became active since they were listed is removed. With "paths", only those
+// orphans are removed; paths that aren't orphans (anymore) are skipped.
+func (s *Server) cleanOrphans(w http.ResponseWriter, r *http.Request) {
+	var req cleanOrphansRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+
+	orphans, _, ok := s.findOrphans(w)
+	if !ok {
+		return
+	}
+	byPath := make(map[string]download.Orphan, len(orphans))
+	for _, o := range orphans {
+		byPath[o.Path] = o
+	}
+	paths := req.Paths
+	if paths == nil {
+		for _, o := range orphans {
+			paths = append(paths, o.Path)
+		}
+	}
+
+	resp := cleanOrphansResponse{Removed: []string{}, Skipped: []string{}}
+	for _, path := range paths {
+		o, isOrphan := byPath[path]
+		if !isOrphan || !download.UnderRoot(s.cfg.DownloadRoot, path) {
+			resp.Skipped = append(resp.Skipped, path)
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			resp.Skipped = append(resp.Skipped, path)
+			continue
+		}
+		resp.Removed = append(resp.Removed, path)
+		resp.FreedBytes += o.Size
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
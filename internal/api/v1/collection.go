@@ -0,0 +1,214 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/vmunix/arrgo/internal/importer"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/tmdb"
+)
+
+// getCollection handles GET /api/v1/content/{id}/collection.
+// Lists the movies of the TMDB collection a movie belongs to, each marked
+// tracked if the library has it by TMDB ID. Nothing is added.
+func (s *Server) getCollection(w http.ResponseWriter, r *http.Request) {
+	c, collection, ok := s.contentCollection(w, r)
+	if !ok {
+		return
+	}
+	resp := collectionResponse{
+		ID:        collection.ID,
+		Name:      collection.Name,
+		ContentID: c.ID,
+		Members:   s.collectionMembers(collection),
+	}
+	for _, m := range resp.Members {
+		if !m.Tracked {
+			resp.Untracked++
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// addMissingCollection handles POST /api/v1/content/{id}/collection/add-missing.
+// Adds the untracked movies of a movie's TMDB collection as wanted content
+// with its quality profile and root path, or only those listed in tmdb_ids.
+// Members go through the same checks as POST /content: excluded ones and
+// duplicates by title and year are skipped with the reason, and movies Plex
+// already has are adopted.
+func (s *Server) addMissingCollection(w http.ResponseWriter, r *http.Request) {
+	var req collectionAddRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+	source, collection, ok := s.contentCollection(w, r)
+	if !ok {
+		return
+	}
+
+	resp := collectionAddResponse{
+		ID:      collection.ID,
+		Name:    collection.Name,
+		Added:   []collectionMember{},
+		Skipped: []collectionMember{},
+	}
+	for _, m := range s.collectionMembers(collection) {
+		if m.Tracked || (len(req.TMDBIDs) > 0 && !slices.Contains(req.TMDBIDs, m.TMDBID)) {
+			continue
+		}
+		reason, err := s.addCollectionMember(r.Context(), source, &m)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+		if reason != "" {
+			m.Reason = reason
+			resp.Skipped = append(resp.Skipped, m)
+			continue
+		}
+		resp.Added = append(resp.Added, m)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// contentCollection loads the movie of the request and its TMDB collection,
+// writing an error and returning false if either is unavailable. A movie
+// whose collection was never stored is looked up on TMDB.
+func (s *Server) contentCollection(w http.ResponseWriter, r *http.Request) (*library.Content, *tmdb.Collection, bool) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return nil, nil, false
+	}
+	c, err := s.deps.Library.GetContent(id)
+	if errors.Is(err, library.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+		return nil, nil, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return nil, nil, false
+	}
+	if c.Type != library.ContentTypeMovie {
+		writeError(w, http.StatusBadRequest, "INVALID_TYPE", "collections only apply to movies")
+		return nil, nil, false
+	}
+	if s.tmdbSvc == nil {
+		writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "TMDB not configured")
+		return nil, nil, false
+	}
+
+	ctx := r.Context()
+	collectionID := c.CollectionID
+	if collectionID == nil && c.TMDBID != nil {
+		movie, err := s.tmdbSvc.GetMovie(ctx, *c.TMDBID)
+		if err != nil && !errors.Is(err, tmdb.ErrNotFound) {
+			writeError(w, http.StatusBadGateway, "LOOKUP_ERROR", err.Error())
+			return nil, nil, false
+		}
+		if movie != nil && movie.Collection != nil {
+			collectionID = &movie.Collection.ID
+		}
+	}
+	if collectionID == nil {
+		writeError(w, http.StatusNotFound, "NO_COLLECTION", "movie is not in a TMDB collection")
+		return nil, nil, false
+	}
+
+	collection, err := s.tmdbSvc.GetCollection(ctx, *collectionID)
+	if errors.Is(err, tmdb.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "NO_COLLECTION", "TMDB collection not found")
+		return nil, nil, false
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "LOOKUP_ERROR", err.Error())
+		return nil, nil, false
+	}
+	return c, collection, true
+}
+
+// collectionMembers describes the movies of a collection by year, with
+// unreleased ones last, each with the library ID of the movie if tracked.
+func (s *Server) collectionMembers(collection *tmdb.Collection) []collectionMember {
+	members := make([]collectionMember, 0, len(collection.Parts))
+	for i := range collection.Parts {
+		part := &collection.Parts[i]
+		m := collectionMember{
+			TMDBID:      part.ID,
+			Title:       part.Title,
+			Year:        part.Year(),
+			ReleaseDate: part.ReleaseDate,
+		}
+		movieType := library.ContentTypeMovie
+		tmdbID := part.ID
+		tracked, _, err := s.deps.Library.ListContent(library.ContentFilter{Type: &movieType, TMDBID: &tmdbID, Limit: 1})
+		if err == nil && len(tracked) > 0 {
+			m.Tracked = true
+			m.ContentID = tracked[0].ID
+		}
+		members = append(members, m)
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		a, b := members[i].Year, members[j].Year
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		return a < b
+	})
+	return members
+}
+
+// addCollectionMember adds a collection member as wanted content with the
+// source movie's quality profile and root path, setting its content ID. It
+// returns why the member was skipped, or "" if it was added.
+func (s *Server) addCollectionMember(ctx context.Context, source *library.Content, m *collectionMember) (string, error) {
+	tmdbID := m.TMDBID
+	c := &library.Content{
+		Type:           library.ContentTypeMovie,
+		TMDBID:         &tmdbID,
+		Title:          m.Title,
+		Year:           m.Year,
+		Status:         library.StatusWanted,
+		QualityProfile: source.QualityProfile,
+		RootPath:       source.RootPath,
+	}
+
+	// Metadata is best effort, as when adding content
+	if s.deps.Metadata != nil {
+		_, _ = s.deps.Metadata.Apply(ctx, c)
+	}
+
+	if _, err := s.deps.Library.ContentExclusion(c); err == nil {
+		return "excluded", nil
+	} else if !errors.Is(err, library.ErrNotFound) {
+		return "", err
+	}
+
+	// A movie Plex already has is available with Plex's file
+	existing := s.findExistingMovie(ctx, c)
+	if existing != "" {
+		c.Status = library.StatusAvailable
+	}
+
+	if err := s.deps.Library.AddContent(c); err != nil {
+		if errors.Is(err, library.ErrDuplicate) {
+			return "already exists", nil
+		}
+		return "", err
+	}
+	if existing != "" {
+		if err := s.deps.Library.AddFile(importer.ExistingMovieFile(c.ID, existing)); err != nil {
+			return "", err
+		}
+	}
+	m.ContentID = c.ID
+	s.publishContentAdded(ctx, c)
+	return "", nil
+}
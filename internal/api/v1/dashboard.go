@@ -0,0 +1,222 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/importer"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// Dashboard section sizes.
+const (
+	dashboardRecentLimit   = 10
+	dashboardUpcomingLimit = 10
+)
+
+// Problem checks reported in DashboardProblem.Check.
+const (
+	checkFailedDownloads = "failed_downloads"
+	checkStuckDownloads  = "stuck_downloads"
+	checkMissingFiles    = "missing_files"
+	checkNotInPlex       = "not_in_plex"
+	checkPlexUnreachable = "plex_unreachable"
+	checkPartialImports  = "partial_imports"
+)
+
+// dashboardActivity fills the dashboard's recent, upcoming, and problems
+// sections. Each section is built from a few batched queries; a section whose
+// query fails is left empty. Plex is asked for its library listing once per
+// section, never per item, and only if checkPlex is set.
+func (s *Server) dashboardActivity(ctx context.Context, resp *DashboardResponse, checkPlex bool) {
+	resp.Recent = []DashboardImport{}
+	resp.Upcoming = []DashboardEpisode{}
+	resp.Problems = []DashboardProblem{}
+
+	importedStatus := download.StatusImported
+	imported, _, _ := s.deps.Downloads.List(download.Filter{Status: &importedStatus})
+	var history []*importer.HistoryEntry
+	if s.deps.History != nil {
+		event := importer.EventImported
+		history, _, _ = s.deps.History.List(importer.HistoryFilter{Event: &event, Limit: dashboardRecentLimit})
+	}
+
+	// One query for the content of both recent imports and imported downloads
+	var contentIDs []int64
+	for _, h := range history {
+		contentIDs = append(contentIDs, h.ContentID)
+	}
+	for _, dl := range imported {
+		contentIDs = append(contentIDs, dl.ContentID)
+	}
+	content := make(map[int64]*library.Content)
+	if len(contentIDs) > 0 {
+		list, _, err := s.deps.Library.ListContent(library.ContentFilter{IDs: uniqueIDs(contentIDs)})
+		if err == nil {
+			for _, c := range list {
+				content[c.ID] = c
+			}
+		}
+	}
+
+	for _, h := range history {
+		c, ok := content[h.ContentID]
+		if !ok {
+			continue
+		}
+		var data struct {
+			Quality string `json:"quality"`
+			Season  int    `json:"season"`
+			Episode int    `json:"episode"`
+		}
+		_ = json.Unmarshal([]byte(h.Data), &data)
+		title := c.Title
+		switch {
+		case data.Season > 0 || data.Episode > 0:
+			title = fmt.Sprintf("%s S%02dE%02d", c.Title, data.Season, data.Episode)
+		case c.Year > 0:
+			title = fmt.Sprintf("%s (%d)", c.Title, c.Year)
+		}
+		resp.Recent = append(resp.Recent, DashboardImport{
+			ContentID:  h.ContentID,
+			EpisodeID:  h.EpisodeID,
+			Title:      title,
+			Quality:    data.Quality,
+			ImportedAt: h.CreatedAt,
+		})
+	}
+
+	if upcoming, err := s.deps.Library.ListUpcoming(dashboardUpcomingLimit); err == nil {
+		for _, item := range upcoming {
+			if item.EpisodeID == nil || item.AirDate == nil {
+				continue
+			}
+			resp.Upcoming = append(resp.Upcoming, DashboardEpisode{
+				ContentID:    item.ContentID,
+				EpisodeID:    *item.EpisodeID,
+				Series:       item.Title,
+				Season:       item.Season,
+				Episode:      item.Episode,
+				EpisodeTitle: item.EpisodeTitle,
+				AirDate:      *item.AirDate,
+			})
+		}
+	}
+
+	addProblem := func(check string, count int, message, fix string) {
+		if count > 0 {
+			resp.Problems = append(resp.Problems, DashboardProblem{Check: check, Count: count, Message: message, Fix: fix})
+		}
+	}
+	addProblem(checkFailedDownloads, resp.Downloads.Failed,
+		fmt.Sprintf("%d failed downloads", resp.Downloads.Failed), "arrgo downloads -s failed")
+	addProblem(checkPartialImports, resp.Downloads.PartiallyImported,
+		fmt.Sprintf("%d season packs have episodes that failed to import", resp.Downloads.PartiallyImported), "arrgo downloads -s partially_imported")
+	addProblem(checkStuckDownloads, resp.Stuck.Count,
+		fmt.Sprintf("%d downloads stuck (%s)", resp.Stuck.Count, stuckBreakdown(resp.Stuck.ByStatus)), "arrgo status --verify")
+
+	missing := s.countMissingFiles(imported)
+	addProblem(checkMissingFiles, missing,
+		fmt.Sprintf("%d imported downloads have files missing from the library", missing), "arrgo status --verify --fix --reimport")
+
+	if !checkPlex || s.deps.Plex == nil {
+		return
+	}
+	inPlex, err := s.plexLibraryKeys(ctx)
+	if err != nil {
+		addProblem(checkPlexUnreachable, 1, "Plex library could not be listed: "+err.Error(), "arrgo status --verify")
+		return
+	}
+	resp.PlexChecked = true
+	notInPlex := 0
+	for _, dl := range imported {
+		if c, ok := content[dl.ContentID]; ok && !inPlex[plexKey(c.Title, c.Year)] {
+			notInPlex++
+		}
+	}
+	addProblem(checkNotInPlex, notInPlex,
+		fmt.Sprintf("%d imported downloads not found in Plex", notInPlex), "arrgo plex scan")
+}
+
+// countMissingFiles returns how many imported downloads have a library file
+// missing from disk, loading the files of all their content in one query.
+func (s *Server) countMissingFiles(imported []*download.Download) int {
+	if len(imported) == 0 {
+		return 0
+	}
+	contentIDs := make([]int64, 0, len(imported))
+	for _, dl := range imported {
+		contentIDs = append(contentIDs, dl.ContentID)
+	}
+	files, _, err := s.deps.Library.ListFiles(library.FileFilter{ContentIDs: uniqueIDs(contentIDs)})
+	if err != nil {
+		return 0
+	}
+	byContent := make(map[int64][]*library.File)
+	for _, f := range files {
+		byContent[f.ContentID] = append(byContent[f.ContentID], f)
+	}
+
+	count := 0
+	for _, dl := range imported {
+		if len(s.missingDownloadFiles(dl, byContent[dl.ContentID])) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// plexLibraryKeys returns the plexKey of every movie and show in Plex,
+// listing each movie and show section once.
+func (s *Server) plexLibraryKeys(ctx context.Context) (map[string]bool, error) {
+	items, err := s.plexLibraryItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(items))
+	for _, item := range items {
+		keys[plexKey(item.Title, item.Year)] = true
+	}
+	return keys, nil
+}
+
+// plexLibraryItems returns every movie and show in Plex, listing each movie
+// and show section once.
+func (s *Server) plexLibraryItems(ctx context.Context) ([]importer.PlexItem, error) {
+	sections, err := s.deps.Plex.GetSections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var all []importer.PlexItem
+	for _, section := range sections {
+		if section.Type != "movie" && section.Type != "show" {
+			continue
+		}
+		items, err := s.deps.Plex.ListLibraryItems(ctx, section.Key)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// plexKey identifies a title in Plex case-insensitively by title and year.
+func plexKey(title string, year int) string {
+	return fmt.Sprintf("%s|%d", strings.ToLower(title), year)
+}
+
+// stuckBreakdown describes stuck download counts in status order,
+// e.g. "2 queued, 1 importing".
+func stuckBreakdown(byStatus map[string]int) string {
+	var parts []string
+	for _, status := range []download.Status{download.StatusQueued, download.StatusDownloading, download.StatusCompleted, download.StatusImporting} {
+		if n := byStatus[string(status)]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
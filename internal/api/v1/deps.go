@@ -2,13 +2,20 @@ package v1
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"io"
+	"time"
 
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
 	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/metadata"
+	"github.com/vmunix/arrgo/internal/profile"
 	"github.com/vmunix/arrgo/internal/search"
+	"github.com/vmunix/arrgo/internal/tmdb"
+	"github.com/vmunix/arrgo/pkg/newznab"
 	"github.com/vmunix/arrgo/pkg/tvdb"
 )
 
@@ -24,8 +31,12 @@ type Searcher interface {
 // Note: Grab is handled via the event bus (GrabRequested event).
 type DownloadManager interface {
 	Cancel(ctx context.Context, downloadID int64, deleteFiles bool) error
-	Client() download.Downloader
+	Clients() []download.ClientInfo
+	ClientFor(name download.Client) (download.Downloader, error)
+	Route(downloadURL string, protocol download.Protocol) (download.Client, download.Downloader, error)
+	Status(ctx context.Context, d *download.Download) (*download.ClientStatus, error)
 	GetActive(ctx context.Context) ([]*download.ActiveDownload, error)
+	SetTorrentOptions(ctx context.Context, d *download.Download, opts download.TorrentOptions) error
 }
 
 // PlexClient defines the interface for Plex media server operations.
@@ -39,28 +50,72 @@ type PlexClient interface {
 	ScanPath(ctx context.Context, filePath string) error
 	RefreshLibrary(ctx context.Context, sectionKey string) error
 	HasMovie(ctx context.Context, title string, year int) (bool, error)
+	FindMovieItem(ctx context.Context, title string, year int) (*importer.PlexItem, error)
+	GetItem(ctx context.Context, ratingKey string) (*importer.PlexItem, error)
 	TranslateToLocal(path string) string
+	PathMappings() []importer.PathMapping
 }
 
 // FileImporter defines the interface for file import operations.
 type FileImporter interface {
 	Import(ctx context.Context, downloadID int64, downloadPath string) (*importer.ImportResult, error)
+	ImportWithOptions(ctx context.Context, downloadID int64, downloadPath string, opts importer.ImportOptions) (*importer.ImportResult, error)
 	ImportSeasonPack(ctx context.Context, downloadID int64, downloadPath string) (*importer.SeasonPackResult, error)
+	PreviewRename(contentID int64) ([]importer.FileRename, error)
+	Rename(ctx context.Context, contentID int64) (*importer.RenameResult, error)
 }
 
 // IndexerAPI represents an indexer that can be queried.
 type IndexerAPI interface {
 	Name() string
 	URL() string
+	Priority() int                  // Lower is preferred on score ties
+	Categories() []string           // Content types searched; empty means all
 	Caps(ctx context.Context) error // Simple connectivity test
+	KeyUsage() []newznab.KeyUsage   // Today's usage of each API key
 }
 
 // TVDBService defines the interface for TVDB metadata operations.
 type TVDBService interface {
 	Search(ctx context.Context, query string) ([]tvdb.SearchResult, error)
+	GetSeries(ctx context.Context, tvdbID int) (*tvdb.Series, error)
 	GetEpisodes(ctx context.Context, tvdbID int) ([]tvdb.Episode, error)
 }
 
+// TMDBService defines the interface for TMDB movie and collection lookups.
+type TMDBService interface {
+	SearchMovies(ctx context.Context, query string) ([]tmdb.Movie, error)
+	GetMovie(ctx context.Context, tmdbID int64) (*tmdb.Movie, error)
+	GetCollection(ctx context.Context, collectionID int64) (*tmdb.Collection, error)
+}
+
+// MetadataRefresher fetches display metadata (overview, poster, runtime, genres) for content.
+type MetadataRefresher interface {
+	// Apply sets metadata on c without saving it; false if no provider applies.
+	Apply(ctx context.Context, c *library.Content) (bool, error)
+	// Refresh fetches and saves metadata for existing content; false if no provider applies.
+	Refresh(ctx context.Context, c *library.Content) (bool, error)
+}
+
+// EpisodeSyncer re-syncs a series' episodes, status, and aliases from TVDB.
+type EpisodeSyncer interface {
+	Sync(ctx context.Context, c *library.Content) (*metadata.EpisodeSyncResult, error)
+}
+
+// SpeedLimiter applies download speed limits (the bandwidth scheduler).
+type SpeedLimiter interface {
+	// Override sets a limit in bytes per second (0 = unlimited) until the given time.
+	Override(ctx context.Context, bytesPerSec int64, until time.Time) error
+	// Current returns the limit currently applied to the download client.
+	Current() download.SpeedLimitState
+}
+
+// MetricsWriter exposes collected HTTP request metrics.
+type MetricsWriter interface {
+	// WritePrometheus writes metrics in the Prometheus text exposition format.
+	WritePrometheus(w io.Writer) error
+}
+
 // ServerDeps contains all dependencies for the API server.
 // Required dependencies must be non-nil; optional dependencies may be nil.
 type ServerDeps struct {
@@ -74,9 +129,17 @@ type ServerDeps struct {
 	Manager  DownloadManager
 	Plex     PlexClient
 	Importer FileImporter
-	Bus      *events.Bus      // Optional: for event-driven mode
-	EventLog *events.EventLog // Optional: for event audit log
-	Indexers []IndexerAPI     // Optional: configured indexers
+	Bus      *events.Bus       // Optional: for event-driven mode
+	EventLog *events.EventLog  // Optional: for event audit log
+	Indexers []IndexerAPI      // Optional: configured indexers
+	Metadata MetadataRefresher // Optional: TMDB/TVDB metadata
+	Episodes EpisodeSyncer     // Optional: TVDB episode sync
+	Speed    SpeedLimiter      // Optional: download speed limits
+	Metrics  MetricsWriter     // Optional: HTTP request metrics
+	Profiles *profile.Store    // Optional: quality profiles (nil: any profile is accepted)
+	Samples  *download.Samples // Optional: download progress samples
+	Uploads  *download.Uploads // Optional: uploaded release files (nil: POST /grab/upload is unavailable)
+	DB       *sql.DB           // Optional: database probed by GET /health
 }
 
 // Validate checks that all required dependencies are provided.
@@ -1,52 +1,185 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
 )
 
+// maxEventsLimit caps the page size of event listings.
+const maxEventsLimit = 1000
+
+// listEvents handles GET /api/v1/events.
+// Filters: entity_type, entity_id, event_type (repeatable), since and until (RFC3339).
+// With enrich=true each event includes a title describing its entity.
 func (s *Server) listEvents(w http.ResponseWriter, r *http.Request) {
-	limit := queryInt(r, "limit", 50)
-	offset := queryInt(r, "offset", 0)
+	filter, ok := parseEventFilter(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	filter.EntityType = q.Get("entity_type")
+	if idStr := q.Get("entity_id"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ID", "entity_id must be an integer")
+			return
+		}
+		filter.EntityID = &id
+	}
+
+	s.writeEvents(w, r, filter)
+}
+
+// listContentEvents handles GET /api/v1/content/{id}/events.
+// Accepts the same filters as listEvents apart from the entity.
+func (s *Server) listContentEvents(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	filter, ok := parseEventFilter(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := s.deps.Library.GetContent(id); err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	filter.EntityType = events.EntityContent
+	filter.EntityID = &id
+	s.writeEvents(w, r, filter)
+}
+
+// parseEventFilter reads pagination, event_type, since, and until from the
+// query string. On invalid input it writes a 400 and returns false.
+func parseEventFilter(w http.ResponseWriter, r *http.Request) (events.EventFilter, bool) {
+	filter := events.EventFilter{
+		Limit:  queryInt(r, "limit", 50),
+		Offset: queryInt(r, "offset", 0),
+	}
 
 	// Validate pagination parameters
-	if limit < 0 || offset < 0 {
+	if filter.Limit < 0 || filter.Offset < 0 {
 		writeError(w, http.StatusBadRequest, "INVALID_PAGINATION", "limit and offset must be non-negative")
-		return
+		return filter, false
 	}
-	const maxLimit = 1000
-	if limit > maxLimit {
-		limit = maxLimit
+	if filter.Limit > maxEventsLimit {
+		filter.Limit = maxEventsLimit
 	}
 
+	q := r.URL.Query()
+	for _, t := range q["event_type"] {
+		if t != "" {
+			filter.EventTypes = append(filter.EventTypes, t)
+		}
+	}
+
+	for _, bound := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		val := q.Get(bound.name)
+		if val == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_TIME", fmt.Sprintf("%s must be an RFC3339 time", bound.name))
+			return filter, false
+		}
+		*bound.dst = &t
+	}
+
+	return filter, true
+}
+
+// writeEvents lists events matching filter and writes them as a page,
+// describing their entities if the request has enrich=true.
+func (s *Server) writeEvents(w http.ResponseWriter, r *http.Request, filter events.EventFilter) {
 	if s.deps.EventLog == nil {
 		writeError(w, http.StatusServiceUnavailable, "NO_EVENT_LOG", "Event log not configured")
 		return
 	}
 
-	events, total, err := s.deps.EventLog.Recent(limit, offset)
+	evts, total, err := s.deps.EventLog.List(filter)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
 		return
 	}
 
-	resp := listEventsResponse{
-		Items:  make([]EventResponse, len(events)),
+	items := eventsToResponse(evts)
+	if r.URL.Query().Get("enrich") == queryTrue {
+		if err := s.describeEntities(items); err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, listEventsResponse{
+		Items:  items,
 		Total:  total,
-		Limit:  limit,
-		Offset: offset,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
+
+// replayEvent handles POST /api/v1/events/{id}/replay.
+// Delivers a logged event to its subscribers again for manual recovery, e.g. a
+// grab the download handler never acted on. Handlers skip work already done,
+// such as a release that is already downloading.
+func (s *Server) replayEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
 	}
-	for i, e := range events {
-		resp.Items[i] = EventResponse{
-			ID:         e.ID,
-			EventType:  e.EventType,
-			EntityType: e.EntityType,
-			EntityID:   e.EntityID,
-			OccurredAt: e.OccurredAt.Format(time.RFC3339),
+	if s.deps.Bus == nil {
+		writeError(w, http.StatusServiceUnavailable, "NO_EVENT_BUS", "event bus not configured")
+		return
+	}
+	if s.deps.EventLog == nil {
+		writeError(w, http.StatusServiceUnavailable, "NO_EVENT_LOG", "Event log not configured")
+		return
+	}
+
+	if _, err := s.deps.Bus.Replay(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, events.ErrEventNotFound):
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Event not found")
+		case errors.Is(err, events.ErrUnknownEventType):
+			writeError(w, http.StatusBadRequest, "NOT_REPLAYABLE", err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
 		}
+		return
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	raw, err := s.deps.EventLog.Get(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, eventsToResponse([]events.RawEvent{*raw})[0])
 }
 
 func (s *Server) listDownloadEvents(w http.ResponseWriter, r *http.Request) {
@@ -67,27 +200,140 @@ func (s *Server) listDownloadEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	events, err := s.deps.EventLog.ForEntity("download", id)
+	evts, err := s.deps.EventLog.ForEntity("download", id)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
 		return
 	}
 
+	items := eventsToResponse(evts)
+	if r.URL.Query().Get("enrich") == queryTrue {
+		if err := s.describeEntities(items); err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+	}
+
 	resp := listEventsResponse{
-		Items:  make([]EventResponse, len(events)),
-		Total:  len(events),
-		Limit:  len(events),
+		Items:  items,
+		Total:  len(evts),
+		Limit:  len(evts),
 		Offset: 0,
 	}
-	for i, e := range events {
-		resp.Items[i] = EventResponse{
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// describeEntities sets Title on each event to a description of its entity:
+// the content title and year, the series title and episode number, or the
+// content of a download. Entities are loaded in one query per type; events
+// whose entity was deleted are left without a title.
+func (s *Server) describeEntities(items []EventResponse) error {
+	var contentIDs, episodeIDs, downloadIDs []int64
+	for _, e := range items {
+		switch e.EntityType {
+		case events.EntityContent:
+			contentIDs = append(contentIDs, e.EntityID)
+		case events.EntityEpisode:
+			episodeIDs = append(episodeIDs, e.EntityID)
+		case events.EntityDownload:
+			downloadIDs = append(downloadIDs, e.EntityID)
+		}
+	}
+
+	downloads := make(map[int64]*download.Download)
+	if len(downloadIDs) > 0 {
+		list, _, err := s.deps.Downloads.List(download.Filter{IDs: uniqueIDs(downloadIDs)})
+		if err != nil {
+			return err
+		}
+		for _, d := range list {
+			downloads[d.ID] = d
+			contentIDs = append(contentIDs, d.ContentID)
+		}
+	}
+
+	episodes := make(map[int64]*library.Episode)
+	if len(episodeIDs) > 0 {
+		list, _, err := s.deps.Library.ListEpisodes(library.EpisodeFilter{IDs: uniqueIDs(episodeIDs)})
+		if err != nil {
+			return err
+		}
+		for _, ep := range list {
+			episodes[ep.ID] = ep
+			contentIDs = append(contentIDs, ep.ContentID)
+		}
+	}
+
+	content := make(map[int64]*library.Content)
+	if len(contentIDs) > 0 {
+		list, _, err := s.deps.Library.ListContent(library.ContentFilter{IDs: uniqueIDs(contentIDs)})
+		if err != nil {
+			return err
+		}
+		for _, c := range list {
+			content[c.ID] = c
+		}
+	}
+
+	label := func(contentID int64) string {
+		c, ok := content[contentID]
+		if !ok {
+			return ""
+		}
+		if c.Year == 0 {
+			return c.Title
+		}
+		return fmt.Sprintf("%s (%d)", c.Title, c.Year)
+	}
+
+	for i, e := range items {
+		switch e.EntityType {
+		case events.EntityContent:
+			items[i].Title = label(e.EntityID)
+		case events.EntityEpisode:
+			if ep, ok := episodes[e.EntityID]; ok {
+				if c, ok := content[ep.ContentID]; ok {
+					items[i].Title = fmt.Sprintf("%s S%02dE%02d", c.Title, ep.Season, ep.Episode)
+				}
+			}
+		case events.EntityDownload:
+			d, ok := downloads[e.EntityID]
+			if !ok {
+				continue
+			}
+			title := label(d.ContentID)
+			if title == "" {
+				title = d.ReleaseName
+			} else if d.Season != nil {
+				title = fmt.Sprintf("%s S%02d", title, *d.Season)
+			}
+			items[i].Title = title
+		}
+	}
+	return nil
+}
+
+// uniqueIDs returns ids sorted and without duplicates.
+func uniqueIDs(ids []int64) []int64 {
+	return slices.Compact(slices.Sorted(slices.Values(ids)))
+}
+
+func eventsToResponse(evts []events.RawEvent) []EventResponse {
+	items := make([]EventResponse, len(evts))
+	for i, e := range evts {
+		items[i] = EventResponse{
 			ID:         e.ID,
 			EventType:  e.EventType,
 			EntityType: e.EntityType,
 			EntityID:   e.EntityID,
 			OccurredAt: e.OccurredAt.Format(time.RFC3339),
 		}
+		if e.ProcessedAt != nil {
+			items[i].ProcessedAt = e.ProcessedAt.Format(time.RFC3339)
+		} else {
+			items[i].Pending = true
+		}
 	}
-
-	writeJSON(w, http.StatusOK, resp)
+	return items
 }
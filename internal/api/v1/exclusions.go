@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// listExclusions handles GET /api/v1/exclusions.
+func (s *Server) listExclusions(w http.ResponseWriter, _ *http.Request) {
+	exclusions, err := s.deps.Library.ListExclusions()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	resp := listExclusionsResponse{
+		Items: make([]exclusionResponse, len(exclusions)),
+		Total: len(exclusions),
+	}
+	for i, e := range exclusions {
+		resp.Items[i] = exclusionToResponse(e)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// addExclusion handles POST /api/v1/exclusions.
+// Excludes a TMDB (movie) or TVDB (series) ID from being added to the library.
+func (s *Server) addExclusion(w http.ResponseWriter, r *http.Request) {
+	var req addExclusionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+	source := library.ExclusionSource(req.Source)
+	if source != library.ExclusionTMDB && source != library.ExclusionTVDB {
+		writeError(w, http.StatusBadRequest, "INVALID_SOURCE", "source must be 'tmdb' or 'tvdb'")
+		return
+	}
+	if req.ExternalID <= 0 {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "external_id is required")
+		return
+	}
+
+	e := &library.Exclusion{
+		Source:     source,
+		ExternalID: req.ExternalID,
+		Title:      req.Title,
+		Year:       req.Year,
+		Reason:     req.Reason,
+	}
+	if err := s.deps.Library.AddExclusion(e); err != nil {
+		if errors.Is(err, library.ErrDuplicate) {
+			writeError(w, http.StatusConflict, "DUPLICATE", fmt.Sprintf("%s:%d is already excluded", source, req.ExternalID))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, exclusionToResponse(e))
+}
+
+// deleteExclusion handles DELETE /api/v1/exclusions/{id}.
+// The external ID can be added to the library again.
+func (s *Server) deleteExclusion(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+	if err := s.deps.Library.DeleteExclusion(id); err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Exclusion not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkExcluded writes an EXCLUDED error and returns true if content may not
+// be added because its external ID is excluded.
+func (s *Server) checkExcluded(w http.ResponseWriter, c *library.Content) bool {
+	e, err := s.deps.Library.ContentExclusion(c)
+	if errors.Is(err, library.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return true
+	}
+	msg := fmt.Sprintf("%s:%d is excluded", e.Source, e.ExternalID)
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	writeError(w, http.StatusConflict, "EXCLUDED", msg+"; delete the exclusion to add it")
+	return true
+}
+
+func exclusionToResponse(e *library.Exclusion) exclusionResponse {
+	return exclusionResponse{
+		ID:         e.ID,
+		Source:     string(e.Source),
+		ExternalID: e.ExternalID,
+		Title:      e.Title,
+		Year:       e.Year,
+		Reason:     e.Reason,
+		CreatedAt:  e.CreatedAt,
+	}
+}
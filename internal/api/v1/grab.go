@@ -0,0 +1,407 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+)
+
+// grabError is a grab request failure with its HTTP status and error code.
+type grabError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *grabError) Error() string { return e.message }
+
+// grabTarget is the part of a series a release covers.
+type grabTarget struct {
+	Season           *int  // nil for movies
+	Episodes         []int // Episode numbers; empty for movies and season packs
+	IsCompleteSeason bool
+	AirDate          *time.Time // Daily releases: the episode is matched by air date
+}
+
+// resolveGrabTarget determines the season and episodes a release covers.
+// Movies have no target. For series the release title is parsed, with the
+// request's season and episode overrides taking precedence (see
+// release.ResolveSeriesTarget).
+func resolveGrabTarget(content *library.Content, req *grabRequest) (*grabTarget, error) {
+	target := &grabTarget{}
+	if content.Type != library.ContentTypeSeries {
+		return target, nil
+	}
+
+	resolved, err := release.ResolveSeriesTarget(req.Title, content.Daily, req.Season, req.Episodes)
+	if err != nil {
+		return nil, &grabError{http.StatusBadRequest, "INVALID_RELEASE", err.Error()}
+	}
+	if resolved.AirDate != nil {
+		target.AirDate = resolved.AirDate
+		return target, nil
+	}
+	target.Season = &resolved.Season
+	target.Episodes = resolved.Episodes
+	target.IsCompleteSeason = resolved.IsCompleteSeason
+	return target, nil
+}
+
+// grabPlan is a grab request resolved against the library.
+type grabPlan struct {
+	content     *library.Content
+	target      *grabTarget
+	episodeIDs  []int64 // Episode records for target.Episodes (only existing ones in a dry run)
+	newEpisodes []int   // Dry run: episode numbers without a record that a grab would create
+}
+
+// planGrab resolves a validated grab request against its content: works out
+// the season and episodes, and finds or creates the episode records.
+// A dry run creates nothing and reports missing episodes in newEpisodes instead.
+func (s *Server) planGrab(content *library.Content, req *grabRequest, dryRun bool) (*grabPlan, error) {
+	target, err := resolveGrabTarget(content, req)
+	if err != nil {
+		return nil, err
+	}
+	plan := &grabPlan{content: content, target: target}
+	if target.AirDate != nil {
+		return s.planDailyGrab(plan, dryRun)
+	}
+	if len(target.Episodes) == 0 {
+		return plan, nil
+	}
+
+	if !dryRun {
+		eps, err := s.deps.Library.FindOrCreateEpisodes(content.ID, *target.Season, target.Episodes)
+		if err != nil {
+			return nil, &grabError{http.StatusInternalServerError, "DB_ERROR", err.Error()}
+		}
+		for _, ep := range eps {
+			plan.episodeIDs = append(plan.episodeIDs, ep.ID)
+		}
+		return plan, nil
+	}
+
+	existing, _, err := s.deps.Library.ListEpisodes(library.EpisodeFilter{ContentID: &content.ID, Season: target.Season})
+	if err != nil {
+		return nil, &grabError{http.StatusInternalServerError, "DB_ERROR", err.Error()}
+	}
+	byNumber := make(map[int]int64, len(existing))
+	for _, ep := range existing {
+		byNumber[ep.Episode] = ep.ID
+	}
+	for _, num := range target.Episodes {
+		if id, ok := byNumber[num]; ok {
+			plan.episodeIDs = append(plan.episodeIDs, id)
+		} else {
+			plan.newEpisodes = append(plan.newEpisodes, num)
+		}
+	}
+	return plan, nil
+}
+
+// planDailyGrab resolves a dated release to the episode that aired that day,
+// creating it if needed. A dry run creates nothing and leaves the episode unset.
+func (s *Server) planDailyGrab(plan *grabPlan, dryRun bool) (*grabPlan, error) {
+	airDate := *plan.target.AirDate
+
+	var ep *library.Episode
+	var err error
+	if dryRun {
+		ep, err = s.deps.Library.FindEpisodeByDate(plan.content.ID, airDate)
+	} else {
+		ep, _, err = s.deps.Library.FindOrCreateEpisodeByDate(plan.content.ID, airDate)
+	}
+	if err != nil {
+		return nil, &grabError{http.StatusInternalServerError, "DB_ERROR", err.Error()}
+	}
+	if ep == nil {
+		// A grab would create the episode in the season keyed by year
+		season := airDate.Year()
+		plan.target.Season = &season
+		return plan, nil
+	}
+
+	plan.target.Season = &ep.Season
+	plan.target.Episodes = []int{ep.Episode}
+	plan.episodeIDs = []int64{ep.ID}
+	return plan, nil
+}
+
+// event builds the GrabRequested event for the plan.
+func (p *grabPlan) event(req *grabRequest) *events.GrabRequested {
+	event := &events.GrabRequested{
+		BaseEvent:          events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:          p.content.ID,
+		DownloadURL:        req.DownloadURL,
+		ReleaseName:        req.Title,
+		Indexer:            req.Indexer,
+		GUID:               req.GUID,
+		Protocol:           req.Protocol,
+		Season:             p.target.Season,
+		IsCompleteSeason:   p.target.IsCompleteSeason,
+		EpisodeIDs:         p.episodeIDs,
+		Sequential:         req.Sequential,
+		FirstLastPiecePrio: req.FirstLastPiecePrio,
+	}
+	switch {
+	case p.content.Type != library.ContentTypeSeries:
+		// For movies, preserve legacy EpisodeID if provided (shouldn't happen, but handle gracefully)
+		event.EpisodeID = req.EpisodeID
+	case len(event.EpisodeIDs) == 1:
+		// Backward compatibility: set EpisodeID for single-episode grabs
+		event.EpisodeID = &event.EpisodeIDs[0]
+	}
+	return event
+}
+
+// grab handles POST /api/v1/grab.
+// With ?dry_run=true the resolved plan is returned instead of grabbing.
+func (s *Server) grab(w http.ResponseWriter, r *http.Request) {
+	var req grabRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+
+	// Validate required fields
+	if req.ContentID == 0 {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "content_id is required")
+		return
+	}
+	if req.DownloadURL == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "download_url is required")
+		return
+	}
+	if download.IsUploadURL(req.DownloadURL) {
+		writeError(w, http.StatusBadRequest, "INVALID_RELEASE", "download_url can't be a local file; upload it with POST /api/v1/grab/upload")
+		return
+	}
+	if req.Title == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "title is required")
+		return
+	}
+	switch download.Protocol(req.Protocol) {
+	case "", download.ProtocolUsenet, download.ProtocolTorrent:
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_PROTOCOL", "protocol must be usenet or torrent")
+		return
+	}
+
+	s.grabRelease(w, r, &req)
+}
+
+// maxUploadSize bounds a release file posted to POST /grab/upload. NZBs of
+// large season packs run to tens of megabytes.
+const maxUploadSize = 64 << 20
+
+// grabUploadedFile handles POST /api/v1/grab/upload: a grab of a release file
+// rather than a URL, for NZBs from outside the configured indexers. The
+// multipart form has content_id, title (default: the file name), and
+// optionally indexer, season and episodes (comma-separated) fields, with the
+// file in an "nzb" part, or a "torrent" part for torrent clients.
+// The file is kept until the download client has taken it.
+// With ?dry_run=true the resolved plan is returned instead of grabbing.
+func (s *Server) grabUploadedFile(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Uploads == nil {
+		writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "uploads not configured")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "expected a multipart form: "+err.Error())
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	contentID, err := strconv.ParseInt(r.FormValue("content_id"), 10, 64)
+	if err != nil || contentID <= 0 {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "content_id is required")
+		return
+	}
+
+	protocol := download.ProtocolUsenet
+	file, header, err := r.FormFile("nzb")
+	if errors.Is(err, http.ErrMissingFile) {
+		protocol = download.ProtocolTorrent
+		file, header, err = r.FormFile("torrent")
+	}
+	if errors.Is(err, http.ErrMissingFile) {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "an nzb or torrent file is required")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	if protocol == download.ProtocolUsenet {
+		if err := download.ValidateNZB(data); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_NZB", err.Error())
+			return
+		}
+	}
+
+	req := &grabRequest{
+		ContentID: contentID,
+		Title:     r.FormValue("title"),
+		Indexer:   r.FormValue("indexer"),
+		Protocol:  string(protocol),
+		upload:    &uploadedFile{name: header.Filename, data: data},
+	}
+	if req.Title == "" {
+		req.Title = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename))
+	}
+	if req.Indexer == "" {
+		req.Indexer = "upload"
+	}
+	if v := r.FormValue("season"); v != "" {
+		season, err := strconv.Atoi(v)
+		if err != nil || season < 0 {
+			writeError(w, http.StatusBadRequest, "INVALID_SEASON", "season must be a non-negative number")
+			return
+		}
+		req.Season = &season
+	}
+	if v := r.FormValue("episodes"); v != "" {
+		for _, part := range strings.Split(v, ",") {
+			ep, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || ep <= 0 {
+				writeError(w, http.StatusBadRequest, "INVALID_EPISODE", "episodes must be comma-separated positive numbers")
+				return
+			}
+			req.Episodes = append(req.Episodes, ep)
+		}
+	}
+
+	s.grabRelease(w, r, req)
+}
+
+// grabRelease requests a grab of a validated release via the event bus.
+// Series releases are resolved to a season pack or specific episodes.
+// With ?dry_run=true nothing is written or published; the plan is returned with a 200.
+func (s *Server) grabRelease(w http.ResponseWriter, r *http.Request, req *grabRequest) {
+	dryRun := r.URL.Query().Get("dry_run") == queryTrue
+
+	content, err := s.deps.Library.GetContent(req.ContentID)
+	if err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	// Require event bus for grab operations
+	if !dryRun && s.deps.Bus == nil {
+		writeError(w, http.StatusServiceUnavailable, "NO_DOWNLOAD_CLIENT", "download client not configured")
+		return
+	}
+
+	plan, err := s.planGrab(content, req, dryRun)
+	if err != nil {
+		var ge *grabError
+		if errors.As(err, &ge) {
+			writeError(w, ge.status, ge.code, ge.message)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	if dryRun {
+		writeJSON(w, http.StatusOK, s.grabPlanToResponse(plan, req))
+		return
+	}
+
+	// Fail fast when no client handles the release, e.g. a torrent with only SABnzbd configured
+	if s.deps.Manager != nil {
+		if _, _, err := s.deps.Manager.Route(req.DownloadURL, download.Protocol(req.Protocol)); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "NO_DOWNLOAD_CLIENT", err.Error())
+			return
+		}
+	}
+
+	if req.upload != nil {
+		uploadURL, err := s.deps.Uploads.Save(req.upload.name, req.upload.data)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "UPLOAD_ERROR", err.Error())
+			return
+		}
+		req.DownloadURL = uploadURL
+	}
+
+	if err := s.deps.Bus.Publish(r.Context(), plan.event(req)); err != nil {
+		if req.upload != nil {
+			_ = s.deps.Uploads.Remove(req.DownloadURL)
+		}
+		writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "accepted"})
+}
+
+// grabPlanToResponse describes what a grab would send to the download client.
+func (s *Server) grabPlanToResponse(plan *grabPlan, req *grabRequest) grabPlanResponse {
+	resp := grabPlanResponse{
+		DryRun:           true,
+		ContentID:        plan.content.ID,
+		ContentType:      string(plan.content.Type),
+		ReleaseName:      req.Title,
+		Indexer:          req.Indexer,
+		GUID:             req.GUID,
+		DownloadURL:      req.DownloadURL,
+		Season:           plan.target.Season,
+		Episodes:         plan.target.Episodes,
+		EpisodeIDs:       plan.episodeIDs,
+		NewEpisodes:      plan.newEpisodes,
+		IsCompleteSeason: plan.target.IsCompleteSeason,
+		Protocol:         string(grabProtocol(req)),
+	}
+	if plan.target.AirDate != nil {
+		resp.AirDate = plan.target.AirDate.Format(time.DateOnly)
+	}
+
+	// Same routing and category selection as the download handler
+	if s.deps.Manager != nil {
+		client, _, err := s.deps.Manager.Route(req.DownloadURL, download.Protocol(req.Protocol))
+		if err != nil {
+			resp.ClientError = err.Error()
+		} else {
+			resp.Client = string(client)
+			resp.Category = s.cfg.Categories[client].For(string(plan.content.Type))
+		}
+	}
+
+	if req.GUID != "" {
+		if blocked, err := s.deps.Downloads.IsBlocked(plan.content.ID, req.GUID); err == nil {
+			resp.Blocklisted = blocked
+		}
+	}
+	return resp
+}
+
+// grabProtocol returns the request's protocol, inferring it from the URL if unset.
+func grabProtocol(req *grabRequest) download.Protocol {
+	if req.Protocol != "" {
+		return download.Protocol(req.Protocol)
+	}
+	return download.ProtocolForURL(req.DownloadURL)
+}
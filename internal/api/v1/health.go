@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/database"
+)
+
+const (
+	// healthCacheTTL is how long readiness check results are reused, so a
+	// probe every few seconds doesn't query the indexers every time.
+	healthCacheTTL = 30 * time.Second
+	// healthCheckTimeout bounds each external readiness check.
+	healthCheckTimeout = 5 * time.Second
+	// healthPingTimeout bounds the liveness database ping.
+	healthPingTimeout = 2 * time.Second
+)
+
+// Health check outcomes.
+const (
+	healthOK      = "ok"
+	healthFailed  = "failed"
+	healthSkipped = "skipped" // Not configured
+)
+
+// healthCheckResponse is the outcome of one health check.
+type healthCheckResponse struct {
+	Name       string    `json:"name"`
+	Status     string    `json:"status"` // ok, failed, or skipped
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// healthResponse is the response for GET /health.
+type healthResponse struct {
+	Status string                `json:"status"` // ok or unavailable
+	Ready  bool                  `json:"ready"`  // True if readiness was checked
+	Checks []healthCheckResponse `json:"checks"`
+}
+
+// healthCache holds the last readiness checks until they expire.
+type healthCache struct {
+	mu      sync.Mutex
+	checks  []healthCheckResponse
+	expires time.Time
+}
+
+// getHealth handles GET /api/v1/health.
+// Liveness (the default) only pings the database and makes no external calls.
+// With ready=true, readiness also checks that the database is writable, that
+// at least one indexer answers a caps request, and that every configured
+// download client answers; these results are cached for healthCacheTTL.
+// Responds 200 when every check passes or is skipped, 503 otherwise.
+func (s *Server) getHealth(w http.ResponseWriter, r *http.Request) {
+	ready := r.URL.Query().Get("ready") == queryTrue
+
+	checks := []healthCheckResponse{s.checkDatabasePing(r.Context())}
+	if ready {
+		checks = append(checks, s.readinessChecks(r.Context())...)
+	}
+
+	resp := healthResponse{Status: healthOK, Ready: ready, Checks: checks}
+	status := http.StatusOK
+	for _, c := range checks {
+		if c.Status == healthFailed {
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		}
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, resp)
+}
+
+// checkDatabasePing is the liveness check.
+func (s *Server) checkDatabasePing(ctx context.Context) healthCheckResponse {
+	return runHealthCheck("database", func() error {
+		if s.deps.DB == nil {
+			return errHealthSkipped
+		}
+		ctx, cancel := context.WithTimeout(ctx, healthPingTimeout)
+		defer cancel()
+		return s.deps.DB.PingContext(ctx)
+	})
+}
+
+// readinessChecks returns the cached readiness checks, running them again
+// once they expire.
+func (s *Server) readinessChecks(ctx context.Context) []healthCheckResponse {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+
+	if s.health.checks != nil && time.Now().Before(s.health.expires) {
+		return s.health.checks
+	}
+
+	// Checks run on their own timeout so a probe that gives up early doesn't
+	// cache failures that weren't the dependencies' fault
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), healthCheckTimeout)
+	defer cancel()
+
+	checks := []healthCheckResponse{
+		runHealthCheck("database_writable", func() error {
+			if s.deps.DB == nil {
+				return errHealthSkipped
+			}
+			return database.CheckWritable(ctx, s.deps.DB)
+		}),
+		runHealthCheck("indexers", func() error { return s.checkIndexers(ctx) }),
+	}
+	checks = append(checks, s.checkDownloadClients(ctx)...)
+
+	s.health.checks = checks
+	s.health.expires = time.Now().Add(healthCacheTTL)
+	return checks
+}
+
+// checkIndexers succeeds if any indexer answers a caps request. The indexers
+// are queried at once and the first success ends the check.
+func (s *Server) checkIndexers(ctx context.Context) error {
+	if len(s.deps.Indexers) == 0 {
+		return errHealthSkipped
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(s.deps.Indexers))
+	for _, idx := range s.deps.Indexers {
+		go func() {
+			if err := idx.Caps(ctx); err != nil {
+				errs <- fmt.Errorf("%s: %w", idx.Name(), err)
+				return
+			}
+			errs <- nil
+		}()
+	}
+	var failures []error
+	for range s.deps.Indexers {
+		err := <-errs
+		if err == nil {
+			return nil
+		}
+		failures = append(failures, err)
+	}
+	return fmt.Errorf("no indexer reachable: %w", errors.Join(failures...))
+}
+
+// checkDownloadClients checks each configured download client by listing
+// its downloads.
+func (s *Server) checkDownloadClients(ctx context.Context) []healthCheckResponse {
+	if s.deps.Manager == nil {
+		return []healthCheckResponse{runHealthCheck("download_clients", func() error { return errHealthSkipped })}
+	}
+	var checks []healthCheckResponse
+	for _, info := range s.deps.Manager.Clients() {
+		checks = append(checks, runHealthCheck("download_client:"+string(info.Name), func() error {
+			client, err := s.deps.Manager.ClientFor(info.Name)
+			if err != nil {
+				return err
+			}
+			_, err = client.List(ctx)
+			return err
+		}))
+	}
+	return checks
+}
+
+// errHealthSkipped marks a check whose dependency is not configured.
+var errHealthSkipped = errors.New("not configured")
+
+// runHealthCheck runs check and times it.
+func runHealthCheck(name string, check func() error) healthCheckResponse {
+	start := time.Now()
+	err := check()
+	c := healthCheckResponse{
+		Name:       name,
+		Status:     healthOK,
+		DurationMs: time.Since(start).Milliseconds(),
+		CheckedAt:  start,
+	}
+	switch {
+	case errors.Is(err, errHealthSkipped):
+		c.Status = healthSkipped
+	case err != nil:
+		c.Status = healthFailed
+		c.Error = err.Error()
+	}
+	return c
+}
@@ -26,8 +26,10 @@ import (
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/profile"
 	"github.com/vmunix/arrgo/internal/search"
 	searchmocks "github.com/vmunix/arrgo/internal/search/mocks"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
 )
 
 // testEnv holds all components needed for integration tests.
@@ -119,20 +121,14 @@ func setupIntegrationTest(t *testing.T) *testEnv {
 
 	// Create download store and manager
 	downloadStore := download.NewStore(db)
-	manager := download.NewManager(sabnzbdClient, downloadStore, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	manager := download.NewManager(downloadStore, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	manager.AddClient(download.ClientSABnzbd, download.ProtocolUsenet, sabnzbdClient)
 	env.manager = manager
 
-	// Build quality profiles map for API (resolution names for display)
-	qualityProfileNames := make(map[string][]string)
-	for name, p := range profiles {
-		qualityProfileNames[name] = p.Resolution
-	}
-
 	// Create API server
 	cfg := Config{
-		MovieRoot:       "/movies",
-		SeriesRoot:      "/tv",
-		QualityProfiles: qualityProfileNames,
+		MovieRoot:  "/movies",
+		SeriesRoot: "/tv",
 	}
 	deps := ServerDeps{
 		Library:   library.NewStore(db),
@@ -140,6 +136,7 @@ func setupIntegrationTest(t *testing.T) *testEnv {
 		History:   importer.NewHistoryStore(db),
 		Searcher:  searcher,
 		Manager:   manager,
+		Profiles:  profile.NewConfigStore(profiles),
 	}
 	srv, err := NewWithDeps(deps, cfg)
 	require.NoError(t, err, "create server")
@@ -195,28 +192,17 @@ func mockRelease(title string, size int64, indexer string) search.Release {
 
 func insertTestContent(t *testing.T, db *sql.DB, contentType, title string, year int) int64 {
 	t.Helper()
-	result, err := db.Exec(`
-		INSERT INTO content (type, title, year, status, quality_profile, root_path)
-		VALUES (?, ?, ?, 'wanted', 'hd', '/movies')`,
-		contentType, title, year,
-	)
-	require.NoError(t, err, "insert content")
-	id, err := result.LastInsertId()
-	require.NoError(t, err, "get last insert id")
-	return id
+	if contentType == string(library.ContentTypeSeries) {
+		return fixtures.NewSeries(title, year).Insert(t, db).ID
+	}
+	return fixtures.NewMovie(title, year).Insert(t, db).ID
 }
 
 func insertTestDownload(t *testing.T, db *sql.DB, contentID int64, clientID, status string) int64 {
 	t.Helper()
-	result, err := db.Exec(`
-		INSERT INTO downloads (content_id, client, client_id, status, release_name, indexer, added_at, last_transition_at)
-		VALUES (?, 'sabnzbd', ?, ?, 'Test.Release', 'TestIndexer', datetime('now'), datetime('now'))`,
-		contentID, clientID, status,
-	)
-	require.NoError(t, err, "insert download")
-	id, err := result.LastInsertId()
-	require.NoError(t, err, "get last insert id")
-	return id
+	return fixtures.NewDownload().ForContent(contentID).WithStatus(download.Status(status)).
+		WithClient(download.ClientSABnzbd, clientID).WithReleaseName("Test.Release").
+		Insert(t, db).ID
 }
 
 func queryDownload(t *testing.T, db *sql.DB, contentID int64) *download.Download {
@@ -267,12 +253,10 @@ func TestIntegration_SearchAndGrab(t *testing.T) {
 
 	// 4. After content creation, insert download record directly
 	// (Grab API requires event bus; this simulates what DownloadHandler does)
-	_, err := env.db.Exec(`
-		INSERT INTO downloads (content_id, client, client_id, status, release_name, indexer, added_at, last_transition_at)
-		VALUES (?, 'sabnzbd', ?, 'queued', ?, ?, datetime('now'), datetime('now'))`,
-		content.ID, "SABnzbd_nzo_abc123", searchResult.Releases[0].Title, searchResult.Releases[0].Indexer,
-	)
-	require.NoError(t, err, "insert download")
+	fixtures.NewDownload().ForContent(content.ID).
+		WithClient(download.ClientSABnzbd, "SABnzbd_nzo_abc123").
+		WithReleaseName(searchResult.Releases[0].Title).WithIndexer(searchResult.Releases[0].Indexer).
+		Insert(t, env.db)
 
 	// 5. Query download and verify indexer matches
 	dl := queryDownload(t, env.db, content.ID)
@@ -476,7 +460,8 @@ func TestIntegration_FullLifecycle(t *testing.T) {
 	searcher := search.NewSearcher(mockIndexer, scorer, logger)
 	sabnzbdClient := download.NewSABnzbdClient(sabnzbd.URL, "test-api-key", "arrgo", nil)
 	downloadStore := download.NewStore(db)
-	manager := download.NewManager(sabnzbdClient, downloadStore, logger)
+	manager := download.NewManager(downloadStore, logger)
+	manager.AddClient(download.ClientSABnzbd, download.ProtocolUsenet, sabnzbdClient)
 
 	// Create importer
 	importerCfg := importer.Config{MovieRoot: movieRoot, SeriesRoot: seriesRoot}
@@ -484,9 +469,8 @@ func TestIntegration_FullLifecycle(t *testing.T) {
 
 	// Create API server
 	cfg := Config{
-		MovieRoot:       movieRoot,
-		SeriesRoot:      seriesRoot,
-		QualityProfiles: map[string][]string{"hd": {"1080p", "720p"}},
+		MovieRoot:  movieRoot,
+		SeriesRoot: seriesRoot,
 	}
 	deps := ServerDeps{
 		Library:   library.NewStore(db),
@@ -495,6 +479,7 @@ func TestIntegration_FullLifecycle(t *testing.T) {
 		Searcher:  searcher,
 		Manager:   manager,
 		Importer:  imp,
+		Profiles:  profile.NewConfigStore(profiles),
 	}
 	srv, err := NewWithDeps(deps, cfg)
 	require.NoError(t, err, "create server")
@@ -541,14 +526,10 @@ func TestIntegration_FullLifecycle(t *testing.T) {
 	require.NoError(t, os.WriteFile(videoPath, make([]byte, 1000), 0644))
 
 	// Insert download record directly (simulating what DownloadHandler does)
-	result, err := db.Exec(`
-		INSERT INTO downloads (content_id, client, client_id, status, release_name, indexer, added_at, last_transition_at)
-		VALUES (?, 'sabnzbd', ?, ?, ?, 'nzbgeek', datetime('now'), datetime('now'))`,
-		content.ID, "SABnzbd_nzo_blade", download.StatusCompleted, releaseName,
-	)
-	require.NoError(t, err, "insert download")
-	downloadID, err := result.LastInsertId()
-	require.NoError(t, err, "get download id")
+	downloadID := fixtures.NewDownload().ForContent(content.ID).WithStatus(download.StatusCompleted).
+		WithClient(download.ClientSABnzbd, "SABnzbd_nzo_blade").
+		WithReleaseName(releaseName).WithIndexer("nzbgeek").
+		Insert(t, db).ID
 
 	dl := queryDownload(t, db, content.ID)
 	assert.Equal(t, download.StatusCompleted, dl.Status, "status after setup")
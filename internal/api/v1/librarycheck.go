@@ -0,0 +1,358 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/importer"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// Library check sizes.
+const (
+	syncLibraryCheckLimit = 50  // Default items checked by GET /library/check
+	maxSyncLibraryCheck   = 200 // Most items GET /library/check checks; POST checks all
+	libraryCheckBatchSize = 100 // Items checked and recorded per batch by a background check
+)
+
+// checkLibrary handles GET /api/v1/library/check.
+// Checks a page of content (limit, default 50, at most 200) synchronously,
+// for spot checks; POST /api/v1/library/check checks the whole library.
+func (s *Server) checkLibrary(w http.ResponseWriter, r *http.Request) {
+	filter := libraryCheckFilter(r)
+	filter.Limit = min(queryInt(r, "limit", syncLibraryCheckLimit), maxSyncLibraryCheck)
+	filter.Offset = queryInt(r, "offset", 0)
+
+	contents, total, err := s.deps.Library.ListContent(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	resp := libraryCheckResponse{
+		Items: make([]libraryCheckItem, 0, len(contents)),
+		Total: total,
+	}
+	if len(contents) == 0 {
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	checker := s.newLibraryChecker(r.Context())
+	items, err := checker.check(contents)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	for _, item := range items {
+		if len(item.Issues) > 0 {
+			resp.WithIssues++
+		} else {
+			resp.Healthy++
+		}
+		resp.Items = append(resp.Items, libraryCheckItemToResponse(item))
+	}
+	if checker.plexErr != nil {
+		resp.PlexError = checker.plexErr.Error()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// startLibraryCheck handles POST /api/v1/library/check.
+// Starts checking all content matching the type and status filters in the
+// background and returns the job; poll GET /api/v1/library/check/{id} for
+// progress and results. Only one check runs at a time.
+func (s *Server) startLibraryCheck(w http.ResponseWriter, r *http.Request) {
+	filter := libraryCheckFilter(r)
+
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+	if s.checkRunning {
+		writeError(w, http.StatusConflict, "CHECK_RUNNING", "A library check is already running")
+		return
+	}
+
+	contents, _, err := s.deps.Library.ListContent(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	job := &library.CheckJob{Filter: describeCheckFilter(filter), Total: len(contents)}
+	if err := s.deps.Library.CreateCheckJob(job); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	parent := s.baseCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	s.checkRunning = true
+	resp := libraryCheckJobToResponse(job, nil)
+	go s.runLibraryCheck(parent, job, contents)
+
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// runLibraryCheck lists the Plex library, then checks contents in batches for
+// a background job, recording each batch as it completes. The job fails if
+// ctx is canceled.
+func (s *Server) runLibraryCheck(ctx context.Context, job *library.CheckJob, contents []*library.Content) {
+	defer func() {
+		s.checkMu.Lock()
+		s.checkRunning = false
+		s.checkMu.Unlock()
+	}()
+
+	checker := s.newLibraryChecker(ctx)
+	if checker.plexErr != nil {
+		job.PlexError = checker.plexErr.Error()
+	}
+	var jobErr error
+	for start := 0; start < len(contents); start += libraryCheckBatchSize {
+		if err := ctx.Err(); err != nil {
+			jobErr = err
+			break
+		}
+		batch := contents[start:min(start+libraryCheckBatchSize, len(contents))]
+		items, err := checker.check(batch)
+		if err == nil {
+			err = s.deps.Library.AddCheckItems(job, items)
+		}
+		if err != nil {
+			jobErr = err
+			break
+		}
+	}
+	_ = s.deps.Library.FinishCheckJob(job, jobErr)
+}
+
+// getLibraryCheck handles GET /api/v1/library/check/{id}.
+// Returns a check job's progress and the items checked so far; issues_only
+// leaves out healthy items and limit/offset page them (default: all).
+func (s *Server) getLibraryCheck(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+	job, err := s.deps.Library.GetCheckJob(id)
+	s.writeLibraryCheck(w, r, job, err)
+}
+
+// getLatestLibraryCheck handles GET /api/v1/library/check/latest.
+// Returns the most recent check job like GET /api/v1/library/check/{id}.
+func (s *Server) getLatestLibraryCheck(w http.ResponseWriter, r *http.Request) {
+	job, err := s.deps.Library.LatestCheckJob()
+	s.writeLibraryCheck(w, r, job, err)
+}
+
+func (s *Server) writeLibraryCheck(w http.ResponseWriter, r *http.Request, job *library.CheckJob, err error) {
+	if errors.Is(err, library.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Library check not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	items, err := s.deps.Library.ListCheckItems(job.ID, r.URL.Query().Get("issues_only") == queryTrue,
+		queryInt(r, "limit", 0), queryInt(r, "offset", 0))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, libraryCheckJobToResponse(job, items))
+}
+
+// libraryCheckFilter returns the content filter from a check request's
+// type and status parameters.
+func libraryCheckFilter(r *http.Request) library.ContentFilter {
+	var filter library.ContentFilter
+	if typeStr := queryString(r, "type"); typeStr != nil {
+		t := library.ContentType(*typeStr)
+		filter.Type = &t
+	}
+	if statusStr := queryString(r, "status"); statusStr != nil {
+		st := library.ContentStatus(*statusStr)
+		filter.Status = &st
+	}
+	return filter
+}
+
+// describeCheckFilter returns the filter of a check job as recorded on it,
+// e.g. "type=movie status=available".
+func describeCheckFilter(f library.ContentFilter) string {
+	var parts []string
+	if f.Type != nil {
+		parts = append(parts, "type="+string(*f.Type))
+	}
+	if f.Status != nil {
+		parts = append(parts, "status="+string(*f.Status))
+	}
+	return strings.Join(parts, " ")
+}
+
+// libraryChecker checks content against its files on disk and against Plex.
+// The Plex library is listed once when the checker is created and matched in
+// memory, rather than searched for every item.
+type libraryChecker struct {
+	store      *library.Store
+	missing    func(*library.File) bool     // Whether a file is gone from disk
+	plex       map[string]importer.PlexItem // plexKey to the item as Plex has it; nil if Plex is not checked
+	plexByKey  map[string]string            // Rating key to the title as Plex has it
+	plexByYear map[int][]string             // Plex titles by year, for approximate matches
+	plexErr    error                        // Why the Plex library could not be listed
+}
+
+// newLibraryChecker lists the Plex library, if Plex is configured, and
+// returns a checker. If Plex cannot be listed, items are checked without it
+// and plexErr says why.
+func (s *Server) newLibraryChecker(ctx context.Context) *libraryChecker {
+	c := &libraryChecker{store: s.deps.Library, missing: s.fileMissing}
+	if s.deps.Plex == nil {
+		return c
+	}
+	items, err := s.plexLibraryItems(ctx)
+	if err != nil {
+		c.plexErr = fmt.Errorf("list Plex library: %w", err)
+		return c
+	}
+	c.plex = make(map[string]importer.PlexItem, len(items))
+	c.plexByKey = make(map[string]string, len(items))
+	c.plexByYear = make(map[int][]string)
+	for _, item := range items {
+		c.plex[plexKey(item.Title, item.Year)] = item
+		c.plexByKey[item.RatingKey] = item.Title
+		c.plexByYear[item.Year] = append(c.plexByYear[item.Year], item.Title)
+	}
+	return c
+}
+
+// check checks a batch of content, listing the files of the whole batch at once.
+func (c *libraryChecker) check(contents []*library.Content) ([]*library.CheckItem, error) {
+	ids := make([]int64, len(contents))
+	for i, content := range contents {
+		ids[i] = content.ID
+	}
+	files, _, err := c.store.ListFiles(library.FileFilter{ContentIDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	byContent := make(map[int64][]*library.File)
+	for _, f := range files {
+		byContent[f.ContentID] = append(byContent[f.ContentID], f)
+	}
+
+	items := make([]*library.CheckItem, 0, len(contents))
+	for _, content := range contents {
+		items = append(items, c.checkContent(content, byContent[content.ID]))
+	}
+	return items, nil
+}
+
+func (c *libraryChecker) checkContent(content *library.Content, files []*library.File) *library.CheckItem {
+	item := &library.CheckItem{
+		ContentID: content.ID,
+		Type:      content.Type,
+		Title:     content.Title,
+		Year:      content.Year,
+		Status:    content.Status,
+	}
+
+	for _, f := range files {
+		item.Files = append(item.Files, f.Path)
+		if c.missing(f) {
+			item.FileMissing = append(item.FileMissing, f.Path)
+			item.Issues = append(item.Issues, "File missing: "+f.Path)
+		}
+	}
+
+	// Check content status vs file presence
+	if content.Status == library.StatusAvailable && len(files) == 0 {
+		item.Issues = append(item.Issues, "Status is 'available' but no files in database")
+	}
+	if content.Status == library.StatusWanted && len(files) > 0 {
+		item.Issues = append(item.Issues, "Status is 'wanted' but has files")
+	}
+
+	// Plex movie and show sections don't list audiobooks
+	if c.plex == nil || content.Type == library.ContentTypeAudiobook {
+		return item
+	}
+	// The stored rating key identifies the item whatever its title in Plex;
+	// an exact title match stores its key for next time
+	if title, ok := c.plexByKey[content.PlexKey]; ok && content.PlexKey != "" {
+		item.InPlex = true
+		item.PlexTitle = title
+	} else if match, ok := c.plex[plexKey(content.Title, content.Year)]; ok {
+		item.InPlex = true
+		item.PlexTitle = match.Title
+		if match.RatingKey != "" && match.RatingKey != content.PlexKey {
+			_ = c.store.SetPlexKey(content.ID, match.RatingKey)
+		}
+	} else {
+		// Approximate match: same year, one title containing the other
+		want := strings.ToLower(content.Title)
+		for _, title := range c.plexByYear[content.Year] {
+			have := strings.ToLower(title)
+			if strings.Contains(have, want) || strings.Contains(want, have) {
+				item.InPlex = true
+				item.PlexTitle = title + " (year match)"
+				break
+			}
+		}
+	}
+
+	// Check Plex consistency
+	if content.Status == library.StatusAvailable && !item.InPlex {
+		item.Issues = append(item.Issues, "Status is 'available' but not found in Plex")
+	}
+	return item
+}
+
+func libraryCheckItemToResponse(item *library.CheckItem) libraryCheckItem {
+	return libraryCheckItem{
+		ID:          item.ContentID,
+		Type:        string(item.Type),
+		Title:       item.Title,
+		Year:        item.Year,
+		Status:      string(item.Status),
+		FileCount:   len(item.Files),
+		Files:       item.Files,
+		FileExists:  len(item.Files) > 0 && len(item.FileMissing) == 0,
+		FileMissing: item.FileMissing,
+		InPlex:      item.InPlex,
+		PlexTitle:   item.PlexTitle,
+		Issues:      item.Issues,
+	}
+}
+
+func libraryCheckJobToResponse(job *library.CheckJob, items []*library.CheckItem) libraryCheckJobResponse {
+	resp := libraryCheckJobResponse{
+		ID:         job.ID,
+		Status:     string(job.Status),
+		Filter:     job.Filter,
+		Total:      job.Total,
+		Checked:    job.Checked,
+		Healthy:    job.Healthy,
+		WithIssues: job.WithIssues,
+		Error:      job.Error,
+		PlexError:  job.PlexError,
+		StartedAt:  job.StartedAt.UTC().Format(time.RFC3339),
+		Items:      make([]libraryCheckItem, 0, len(items)),
+	}
+	if job.FinishedAt != nil {
+		resp.FinishedAt = job.FinishedAt.UTC().Format(time.RFC3339)
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, libraryCheckItemToResponse(item))
+	}
+	return resp
+}
@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/tmdb"
+	"github.com/vmunix/arrgo/pkg/tvdb"
+)
+
+// lookupLimit caps the number of matches returned by a title lookup.
+const lookupLimit = 10
+
+// parseExternalID parses an explicit "tmdb:603" or "tvdb:81189" lookup query.
+// Returns the source ("tmdb" or "tvdb") and ID, or ok=false for a title query.
+func parseExternalID(q string) (source string, id int64, ok bool) {
+	source, rest, found := strings.Cut(strings.ToLower(strings.TrimSpace(q)), ":")
+	if !found || (source != "tmdb" && source != "tvdb") {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil || id <= 0 {
+		return "", 0, false
+	}
+	return source, id, true
+}
+
+// lookup handles GET /api/v1/lookup?type=movie|series&q=query.
+// Finds movies on TMDB or series on TVDB by title, or by an explicit
+// tmdb:ID / tvdb:ID query, so content can be added without knowing its ID.
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_QUERY", "query parameter is required")
+		return
+	}
+	contentType := library.ContentType(r.URL.Query().Get("type"))
+
+	source, id, byID := parseExternalID(q)
+	if byID {
+		switch {
+		case contentType == "":
+			contentType = library.ContentTypeMovie
+			if source == "tvdb" {
+				contentType = library.ContentTypeSeries
+			}
+		case (contentType == library.ContentTypeMovie) != (source == "tmdb"):
+			writeError(w, http.StatusBadRequest, "INVALID_QUERY", "movies are looked up by tmdb: IDs and series by tvdb: IDs")
+			return
+		}
+	}
+
+	var results []lookupResult
+	var err error
+	switch contentType {
+	case library.ContentTypeMovie:
+		if s.tmdbSvc == nil {
+			writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "TMDB not configured")
+			return
+		}
+		results, err = s.lookupMovies(r.Context(), q, id, byID)
+	case library.ContentTypeSeries:
+		if s.tvdbSvc == nil {
+			writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "TVDB not configured")
+			return
+		}
+		results, err = s.lookupSeries(r.Context(), q, int(id), byID)
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_TYPE", "type must be 'movie' or 'series'")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "LOOKUP_ERROR", err.Error())
+		return
+	}
+
+	if len(results) > lookupLimit {
+		results = results[:lookupLimit]
+	}
+	for i := range results {
+		results[i].ContentID = s.existingContentID(&results[i])
+	}
+	writeJSON(w, http.StatusOK, lookupResponse{Results: results})
+}
+
+// lookupMovies searches TMDB by title, or fetches a single movie by ID.
+func (s *Server) lookupMovies(ctx context.Context, q string, id int64, byID bool) ([]lookupResult, error) {
+	var movies []tmdb.Movie
+	if byID {
+		movie, err := s.tmdbSvc.GetMovie(ctx, id)
+		if errors.Is(err, tmdb.ErrNotFound) {
+			return []lookupResult{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		movies = []tmdb.Movie{*movie}
+	} else {
+		var err error
+		if movies, err = s.tmdbSvc.SearchMovies(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]lookupResult, 0, len(movies))
+	for i := range movies {
+		tmdbID := movies[i].ID
+		results = append(results, lookupResult{
+			Type:     string(library.ContentTypeMovie),
+			TMDBID:   &tmdbID,
+			Title:    movies[i].Title,
+			Year:     movies[i].Year(),
+			Overview: movies[i].Overview,
+		})
+	}
+	return results, nil
+}
+
+// lookupSeries searches TVDB by title, or fetches a single series by ID.
+func (s *Server) lookupSeries(ctx context.Context, q string, id int, byID bool) ([]lookupResult, error) {
+	var found []tvdb.SearchResult
+	if byID {
+		series, err := s.tvdbSvc.GetSeries(ctx, id)
+		if errors.Is(err, tvdb.ErrNotFound) {
+			return []lookupResult{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = []tvdb.SearchResult{{ID: series.ID, Name: series.Name, Year: series.Year, Overview: series.Overview}}
+	} else {
+		var err error
+		if found, err = s.tvdbSvc.Search(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]lookupResult, 0, len(found))
+	for _, sr := range found {
+		tvdbID := int64(sr.ID)
+		results = append(results, lookupResult{
+			Type:     string(library.ContentTypeSeries),
+			TVDBID:   &tvdbID,
+			Title:    sr.Name,
+			Year:     sr.Year,
+			Overview: sr.Overview,
+		})
+	}
+	return results, nil
+}
+
+// existingContentID returns the library ID of a lookup result already being tracked, if any.
+func (s *Server) existingContentID(res *lookupResult) *int64 {
+	filter := library.ContentFilter{TMDBID: res.TMDBID, TVDBID: res.TVDBID, Limit: 1}
+	items, _, err := s.deps.Library.ListContent(filter)
+	if err != nil || len(items) == 0 {
+		return nil
+	}
+	return &items[0].ID
+}
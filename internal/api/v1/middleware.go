@@ -1,6 +1,10 @@
 package v1
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/vmunix/arrgo/internal/api/auth"
+)
 
 // requireSearcher wraps a handler and returns 503 if searcher is not configured.
 func (s *Server) requireSearcher(next http.HandlerFunc) http.HandlerFunc {
@@ -45,3 +49,26 @@ func (s *Server) requireImporter(next http.HandlerFunc) http.HandlerFunc {
 		next(w, r)
 	}
 }
+
+// requireScope wraps a destructive handler and returns 403 unless the
+// request's API key grants scope.
+func requireScope(scope auth.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !hasScope(w, r, scope) {
+			return
+		}
+		next(w, r)
+	}
+}
+
+// hasScope reports whether the request's API key grants scope, writing the
+// 403 if not. Requests that didn't authenticate with a key, as when no keys
+// are configured, have every scope.
+func hasScope(w http.ResponseWriter, r *http.Request, scope auth.Scope) bool {
+	key, ok := auth.FromContext(r.Context())
+	if !ok || key.Scope.Allows(scope) {
+		return true
+	}
+	auth.Forbidden(w, key, scope)
+	return false
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/vmunix/arrgo/internal/api/v1 (interfaces: Searcher,DownloadManager,PlexClient,FileImporter,TVDBService)
+// Source: github.com/vmunix/arrgo/internal/api/v1 (interfaces: Searcher,DownloadManager,PlexClient,FileImporter,TVDBService,TMDBService,MetadataRefresher,EpisodeSyncer,SpeedLimiter)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mocks/mocks.go -package=mocks github.com/vmunix/arrgo/internal/api/v1 Searcher,DownloadManager,PlexClient,FileImporter,TVDBService
+//	mockgen -destination=mocks/mocks.go -package=mocks github.com/vmunix/arrgo/internal/api/v1 Searcher,DownloadManager,PlexClient,FileImporter,TVDBService,TMDBService,MetadataRefresher,EpisodeSyncer,SpeedLimiter
 //
 
 // Package mocks is a generated GoMock package.
@@ -12,10 +12,14 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	download "github.com/vmunix/arrgo/internal/download"
 	importer "github.com/vmunix/arrgo/internal/importer"
+	library "github.com/vmunix/arrgo/internal/library"
+	metadata "github.com/vmunix/arrgo/internal/metadata"
 	search "github.com/vmunix/arrgo/internal/search"
+	tmdb "github.com/vmunix/arrgo/internal/tmdb"
 	tvdb "github.com/vmunix/arrgo/pkg/tvdb"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -97,18 +101,33 @@ func (mr *MockDownloadManagerMockRecorder) Cancel(ctx, downloadID, deleteFiles a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockDownloadManager)(nil).Cancel), ctx, downloadID, deleteFiles)
 }
 
-// Client mocks base method.
-func (m *MockDownloadManager) Client() download.Downloader {
+// ClientFor mocks base method.
+func (m *MockDownloadManager) ClientFor(name download.Client) (download.Downloader, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Client")
+	ret := m.ctrl.Call(m, "ClientFor", name)
 	ret0, _ := ret[0].(download.Downloader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientFor indicates an expected call of ClientFor.
+func (mr *MockDownloadManagerMockRecorder) ClientFor(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientFor", reflect.TypeOf((*MockDownloadManager)(nil).ClientFor), name)
+}
+
+// Clients mocks base method.
+func (m *MockDownloadManager) Clients() []download.ClientInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clients")
+	ret0, _ := ret[0].([]download.ClientInfo)
 	return ret0
 }
 
-// Client indicates an expected call of Client.
-func (mr *MockDownloadManagerMockRecorder) Client() *gomock.Call {
+// Clients indicates an expected call of Clients.
+func (mr *MockDownloadManagerMockRecorder) Clients() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Client", reflect.TypeOf((*MockDownloadManager)(nil).Client))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clients", reflect.TypeOf((*MockDownloadManager)(nil).Clients))
 }
 
 // GetActive mocks base method.
@@ -126,6 +145,51 @@ func (mr *MockDownloadManagerMockRecorder) GetActive(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActive", reflect.TypeOf((*MockDownloadManager)(nil).GetActive), ctx)
 }
 
+// Route mocks base method.
+func (m *MockDownloadManager) Route(downloadURL string, protocol download.Protocol) (download.Client, download.Downloader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Route", downloadURL, protocol)
+	ret0, _ := ret[0].(download.Client)
+	ret1, _ := ret[1].(download.Downloader)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Route indicates an expected call of Route.
+func (mr *MockDownloadManagerMockRecorder) Route(downloadURL, protocol any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Route", reflect.TypeOf((*MockDownloadManager)(nil).Route), downloadURL, protocol)
+}
+
+// SetTorrentOptions mocks base method.
+func (m *MockDownloadManager) SetTorrentOptions(ctx context.Context, d *download.Download, opts download.TorrentOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTorrentOptions", ctx, d, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTorrentOptions indicates an expected call of SetTorrentOptions.
+func (mr *MockDownloadManagerMockRecorder) SetTorrentOptions(ctx, d, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTorrentOptions", reflect.TypeOf((*MockDownloadManager)(nil).SetTorrentOptions), ctx, d, opts)
+}
+
+// Status mocks base method.
+func (m *MockDownloadManager) Status(ctx context.Context, d *download.Download) (*download.ClientStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx, d)
+	ret0, _ := ret[0].(*download.ClientStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockDownloadManagerMockRecorder) Status(ctx, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockDownloadManager)(nil).Status), ctx, d)
+}
+
 // MockPlexClient is a mock of PlexClient interface.
 type MockPlexClient struct {
 	ctrl     *gomock.Controller
@@ -150,6 +214,21 @@ func (m *MockPlexClient) EXPECT() *MockPlexClientMockRecorder {
 	return m.recorder
 }
 
+// FindMovieItem mocks base method.
+func (m *MockPlexClient) FindMovieItem(ctx context.Context, title string, year int) (*importer.PlexItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMovieItem", ctx, title, year)
+	ret0, _ := ret[0].(*importer.PlexItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMovieItem indicates an expected call of FindMovieItem.
+func (mr *MockPlexClientMockRecorder) FindMovieItem(ctx, title, year any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMovieItem", reflect.TypeOf((*MockPlexClient)(nil).FindMovieItem), ctx, title, year)
+}
+
 // FindSectionByName mocks base method.
 func (m *MockPlexClient) FindSectionByName(ctx context.Context, name string) (*importer.Section, error) {
 	m.ctrl.T.Helper()
@@ -180,6 +259,21 @@ func (mr *MockPlexClientMockRecorder) GetIdentity(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentity", reflect.TypeOf((*MockPlexClient)(nil).GetIdentity), ctx)
 }
 
+// GetItem mocks base method.
+func (m *MockPlexClient) GetItem(ctx context.Context, ratingKey string) (*importer.PlexItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, ratingKey)
+	ret0, _ := ret[0].(*importer.PlexItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockPlexClientMockRecorder) GetItem(ctx, ratingKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockPlexClient)(nil).GetItem), ctx, ratingKey)
+}
+
 // GetLibraryCount mocks base method.
 func (m *MockPlexClient) GetLibraryCount(ctx context.Context, sectionKey string) (int, error) {
 	m.ctrl.T.Helper()
@@ -240,6 +334,20 @@ func (mr *MockPlexClientMockRecorder) ListLibraryItems(ctx, sectionKey any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLibraryItems", reflect.TypeOf((*MockPlexClient)(nil).ListLibraryItems), ctx, sectionKey)
 }
 
+// PathMappings mocks base method.
+func (m *MockPlexClient) PathMappings() []importer.PathMapping {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PathMappings")
+	ret0, _ := ret[0].([]importer.PathMapping)
+	return ret0
+}
+
+// PathMappings indicates an expected call of PathMappings.
+func (mr *MockPlexClientMockRecorder) PathMappings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PathMappings", reflect.TypeOf((*MockPlexClient)(nil).PathMappings))
+}
+
 // RefreshLibrary mocks base method.
 func (m *MockPlexClient) RefreshLibrary(ctx context.Context, sectionKey string) error {
 	m.ctrl.T.Helper()
@@ -351,6 +459,51 @@ func (mr *MockFileImporterMockRecorder) ImportSeasonPack(ctx, downloadID, downlo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSeasonPack", reflect.TypeOf((*MockFileImporter)(nil).ImportSeasonPack), ctx, downloadID, downloadPath)
 }
 
+// ImportWithOptions mocks base method.
+func (m *MockFileImporter) ImportWithOptions(ctx context.Context, downloadID int64, downloadPath string, opts importer.ImportOptions) (*importer.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportWithOptions", ctx, downloadID, downloadPath, opts)
+	ret0, _ := ret[0].(*importer.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportWithOptions indicates an expected call of ImportWithOptions.
+func (mr *MockFileImporterMockRecorder) ImportWithOptions(ctx, downloadID, downloadPath, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportWithOptions", reflect.TypeOf((*MockFileImporter)(nil).ImportWithOptions), ctx, downloadID, downloadPath, opts)
+}
+
+// PreviewRename mocks base method.
+func (m *MockFileImporter) PreviewRename(contentID int64) ([]importer.FileRename, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewRename", contentID)
+	ret0, _ := ret[0].([]importer.FileRename)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewRename indicates an expected call of PreviewRename.
+func (mr *MockFileImporterMockRecorder) PreviewRename(contentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewRename", reflect.TypeOf((*MockFileImporter)(nil).PreviewRename), contentID)
+}
+
+// Rename mocks base method.
+func (m *MockFileImporter) Rename(ctx context.Context, contentID int64) (*importer.RenameResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, contentID)
+	ret0, _ := ret[0].(*importer.RenameResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockFileImporterMockRecorder) Rename(ctx, contentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockFileImporter)(nil).Rename), ctx, contentID)
+}
+
 // MockTVDBService is a mock of TVDBService interface.
 type MockTVDBService struct {
 	ctrl     *gomock.Controller
@@ -390,6 +543,21 @@ func (mr *MockTVDBServiceMockRecorder) GetEpisodes(ctx, tvdbID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpisodes", reflect.TypeOf((*MockTVDBService)(nil).GetEpisodes), ctx, tvdbID)
 }
 
+// GetSeries mocks base method.
+func (m *MockTVDBService) GetSeries(ctx context.Context, tvdbID int) (*tvdb.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeries", ctx, tvdbID)
+	ret0, _ := ret[0].(*tvdb.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeries indicates an expected call of GetSeries.
+func (mr *MockTVDBServiceMockRecorder) GetSeries(ctx, tvdbID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeries", reflect.TypeOf((*MockTVDBService)(nil).GetSeries), ctx, tvdbID)
+}
+
 // Search mocks base method.
 func (m *MockTVDBService) Search(ctx context.Context, query string) ([]tvdb.SearchResult, error) {
 	m.ctrl.T.Helper()
@@ -404,3 +572,217 @@ func (mr *MockTVDBServiceMockRecorder) Search(ctx, query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockTVDBService)(nil).Search), ctx, query)
 }
+
+// MockTMDBService is a mock of TMDBService interface.
+type MockTMDBService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTMDBServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTMDBServiceMockRecorder is the mock recorder for MockTMDBService.
+type MockTMDBServiceMockRecorder struct {
+	mock *MockTMDBService
+}
+
+// NewMockTMDBService creates a new mock instance.
+func NewMockTMDBService(ctrl *gomock.Controller) *MockTMDBService {
+	mock := &MockTMDBService{ctrl: ctrl}
+	mock.recorder = &MockTMDBServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTMDBService) EXPECT() *MockTMDBServiceMockRecorder {
+	return m.recorder
+}
+
+// GetCollection mocks base method.
+func (m *MockTMDBService) GetCollection(ctx context.Context, collectionID int64) (*tmdb.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollection", ctx, collectionID)
+	ret0, _ := ret[0].(*tmdb.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollection indicates an expected call of GetCollection.
+func (mr *MockTMDBServiceMockRecorder) GetCollection(ctx, collectionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollection", reflect.TypeOf((*MockTMDBService)(nil).GetCollection), ctx, collectionID)
+}
+
+// GetMovie mocks base method.
+func (m *MockTMDBService) GetMovie(ctx context.Context, tmdbID int64) (*tmdb.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovie", ctx, tmdbID)
+	ret0, _ := ret[0].(*tmdb.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovie indicates an expected call of GetMovie.
+func (mr *MockTMDBServiceMockRecorder) GetMovie(ctx, tmdbID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovie", reflect.TypeOf((*MockTMDBService)(nil).GetMovie), ctx, tmdbID)
+}
+
+// SearchMovies mocks base method.
+func (m *MockTMDBService) SearchMovies(ctx context.Context, query string) ([]tmdb.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchMovies", ctx, query)
+	ret0, _ := ret[0].([]tmdb.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchMovies indicates an expected call of SearchMovies.
+func (mr *MockTMDBServiceMockRecorder) SearchMovies(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMovies", reflect.TypeOf((*MockTMDBService)(nil).SearchMovies), ctx, query)
+}
+
+// MockMetadataRefresher is a mock of MetadataRefresher interface.
+type MockMetadataRefresher struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetadataRefresherMockRecorder
+	isgomock struct{}
+}
+
+// MockMetadataRefresherMockRecorder is the mock recorder for MockMetadataRefresher.
+type MockMetadataRefresherMockRecorder struct {
+	mock *MockMetadataRefresher
+}
+
+// NewMockMetadataRefresher creates a new mock instance.
+func NewMockMetadataRefresher(ctrl *gomock.Controller) *MockMetadataRefresher {
+	mock := &MockMetadataRefresher{ctrl: ctrl}
+	mock.recorder = &MockMetadataRefresherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetadataRefresher) EXPECT() *MockMetadataRefresherMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockMetadataRefresher) Apply(ctx context.Context, c *library.Content) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, c)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockMetadataRefresherMockRecorder) Apply(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockMetadataRefresher)(nil).Apply), ctx, c)
+}
+
+// Refresh mocks base method.
+func (m *MockMetadataRefresher) Refresh(ctx context.Context, c *library.Content) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, c)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockMetadataRefresherMockRecorder) Refresh(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockMetadataRefresher)(nil).Refresh), ctx, c)
+}
+
+// MockEpisodeSyncer is a mock of EpisodeSyncer interface.
+type MockEpisodeSyncer struct {
+	ctrl     *gomock.Controller
+	recorder *MockEpisodeSyncerMockRecorder
+	isgomock struct{}
+}
+
+// MockEpisodeSyncerMockRecorder is the mock recorder for MockEpisodeSyncer.
+type MockEpisodeSyncerMockRecorder struct {
+	mock *MockEpisodeSyncer
+}
+
+// NewMockEpisodeSyncer creates a new mock instance.
+func NewMockEpisodeSyncer(ctrl *gomock.Controller) *MockEpisodeSyncer {
+	mock := &MockEpisodeSyncer{ctrl: ctrl}
+	mock.recorder = &MockEpisodeSyncerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEpisodeSyncer) EXPECT() *MockEpisodeSyncerMockRecorder {
+	return m.recorder
+}
+
+// Sync mocks base method.
+func (m *MockEpisodeSyncer) Sync(ctx context.Context, c *library.Content) (*metadata.EpisodeSyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, c)
+	ret0, _ := ret[0].(*metadata.EpisodeSyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockEpisodeSyncerMockRecorder) Sync(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockEpisodeSyncer)(nil).Sync), ctx, c)
+}
+
+// MockSpeedLimiter is a mock of SpeedLimiter interface.
+type MockSpeedLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpeedLimiterMockRecorder
+	isgomock struct{}
+}
+
+// MockSpeedLimiterMockRecorder is the mock recorder for MockSpeedLimiter.
+type MockSpeedLimiterMockRecorder struct {
+	mock *MockSpeedLimiter
+}
+
+// NewMockSpeedLimiter creates a new mock instance.
+func NewMockSpeedLimiter(ctrl *gomock.Controller) *MockSpeedLimiter {
+	mock := &MockSpeedLimiter{ctrl: ctrl}
+	mock.recorder = &MockSpeedLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpeedLimiter) EXPECT() *MockSpeedLimiterMockRecorder {
+	return m.recorder
+}
+
+// Current mocks base method.
+func (m *MockSpeedLimiter) Current() download.SpeedLimitState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Current")
+	ret0, _ := ret[0].(download.SpeedLimitState)
+	return ret0
+}
+
+// Current indicates an expected call of Current.
+func (mr *MockSpeedLimiterMockRecorder) Current() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Current", reflect.TypeOf((*MockSpeedLimiter)(nil).Current))
+}
+
+// Override mocks base method.
+func (m *MockSpeedLimiter) Override(ctx context.Context, bytesPerSec int64, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Override", ctx, bytesPerSec, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Override indicates an expected call of Override.
+func (mr *MockSpeedLimiterMockRecorder) Override(ctx, bytesPerSec, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Override", reflect.TypeOf((*MockSpeedLimiter)(nil).Override), ctx, bytesPerSec, until)
+}
@@ -0,0 +1,301 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/vmunix/arrgo/internal/importer"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// plexMissingSection is a Plex section compared by GET /plex/missing, with
+// its locations translated to local paths.
+type plexMissingSection struct {
+	section   importer.Section
+	locations []string
+	files     []*library.File // Library files under its locations
+}
+
+// getPlexMissing handles GET /api/v1/plex/missing.
+// Compares library files with the movie and show sections of Plex: files
+// arrgo has that Plex does not list, and Plex items arrgo does not track.
+// Each section holding library files is listed once, never searched per
+// item. Movies match by file path; Plex lists shows without their episode
+// files, so an episode file counts as in Plex when its show folder is.
+// With content_id only that item's files are checked and untracked Plex
+// items are not reported, for a quick check after an import.
+func (s *Server) getPlexMissing(w http.ResponseWriter, r *http.Request) {
+	filter := library.FileFilter{}
+	var scoped *library.Content
+	if v := r.URL.Query().Get("content_id"); v != "" {
+		contentID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_CONTENT_ID", "content_id must be an integer")
+			return
+		}
+		scoped, err = s.deps.Library.GetContent(contentID)
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "content not found")
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+		filter.ContentID = &contentID
+	}
+
+	files, _, err := s.deps.Library.ListFiles(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	all, err := s.deps.Plex.GetSections(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "PLEX_ERROR", err.Error())
+		return
+	}
+	var sections []*plexMissingSection
+	for _, sec := range all {
+		if sec.Type != "movie" && sec.Type != "show" {
+			continue
+		}
+		if scoped != nil && sec.Type != plexSectionType(scoped.Type) {
+			continue
+		}
+		ps := &plexMissingSection{section: sec}
+		for _, loc := range sec.Locations {
+			ps.locations = append(ps.locations, filepath.Clean(s.deps.Plex.TranslateToLocal(loc.Path)))
+		}
+		sections = append(sections, ps)
+	}
+
+	resp := plexMissingResponse{
+		Sections:  []plexMissingSectionResponse{},
+		Missing:   []plexMissingFile{},
+		Untracked: []plexUntrackedItem{},
+	}
+	var missing []*library.File
+	fixes := make(map[int64][]string)
+	for _, f := range files {
+		if sec := sectionForFile(sections, f.Path); sec != nil {
+			sec.files = append(sec.files, f)
+			continue
+		}
+		missing = append(missing, f)
+		fixes[f.ID] = []string{fmt.Sprintf("add %s to a Plex library", filepath.Dir(f.Path))}
+	}
+
+	// Tracked file paths and every folder above them, to find untracked items
+	tracked := make(map[string]bool, len(files))
+	for _, f := range files {
+		for path := filepath.Clean(f.Path); !tracked[path]; path = filepath.Dir(path) {
+			tracked[path] = true
+		}
+	}
+	for _, sec := range sections {
+		// Sections holding none of the files checked are not listed
+		if len(sec.files) == 0 && scoped != nil {
+			continue
+		}
+		secResp := plexMissingSectionResponse{
+			Key:       sec.section.Key,
+			Name:      sec.section.Title,
+			Type:      sec.section.Type,
+			Locations: sec.locations,
+			Files:     len(sec.files),
+		}
+		items, err := s.deps.Plex.ListLibraryItems(ctx, sec.section.Key)
+		if err != nil {
+			secResp.Error = err.Error()
+			resp.Sections = append(resp.Sections, secResp)
+			continue
+		}
+
+		inPlex, folders := s.plexLocalPaths(items)
+		for _, f := range sec.files {
+			path := filepath.Clean(f.Path)
+			if inPlex[path] || inFolder(path, folders) {
+				continue
+			}
+			secResp.Missing++
+			missing = append(missing, f)
+			fixes[f.ID] = []string{fmt.Sprintf("arrgo plex scan %q", sec.section.Title)}
+		}
+		if scoped == nil {
+			resp.Untracked = append(resp.Untracked, s.untrackedPlexItems(sec.section, items, tracked)...)
+		}
+		resp.Sections = append(resp.Sections, secResp)
+	}
+
+	resp.Missing = s.plexMissingFiles(missing, fixes, sections)
+	resp.Summary.Files = len(files)
+	resp.Summary.Missing = len(resp.Missing)
+	resp.Summary.Untracked = len(resp.Untracked)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// plexSectionType returns the type of the Plex sections holding content of a type.
+func plexSectionType(t library.ContentType) string {
+	if t == library.ContentTypeSeries {
+		return "show"
+	}
+	return "movie"
+}
+
+// sectionForFile returns the section whose location holds path, or nil.
+func sectionForFile(sections []*plexMissingSection, path string) *plexMissingSection {
+	for _, sec := range sections {
+		if underAny(path, sec.locations) {
+			return sec
+		}
+	}
+	return nil
+}
+
+// underAny reports whether path is in one of dirs or below it.
+func underAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// inFolder reports whether path is below one of folders.
+func inFolder(path string, folders map[string]bool) bool {
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if folders[dir] {
+			return true
+		}
+		if dir == filepath.Dir(dir) {
+			return false
+		}
+	}
+}
+
+// plexLocalPaths returns the local paths of the files of items and of the
+// folders of shows.
+func (s *Server) plexLocalPaths(items []importer.PlexItem) (files, folders map[string]bool) {
+	files = make(map[string]bool, len(items))
+	folders = make(map[string]bool)
+	for _, item := range items {
+		if item.FilePath != "" {
+			files[filepath.Clean(s.deps.Plex.TranslateToLocal(item.FilePath))] = true
+		}
+		for _, loc := range item.Locations {
+			folders[filepath.Clean(s.deps.Plex.TranslateToLocal(loc))] = true
+		}
+	}
+	return files, folders
+}
+
+// untrackedPlexItems returns the items of a section arrgo has no file of: a
+// movie whose file is not tracked, or a show with no tracked file in its
+// folders. tracked holds the tracked file paths and the folders above them.
+// Items Plex lists without a path cannot be compared and are left out.
+func (s *Server) untrackedPlexItems(sec importer.Section, items []importer.PlexItem, tracked map[string]bool) []plexUntrackedItem {
+	var out []plexUntrackedItem
+	for _, item := range items {
+		var path string
+		switch {
+		case item.FilePath != "":
+			path = filepath.Clean(s.deps.Plex.TranslateToLocal(item.FilePath))
+			if tracked[path] {
+				continue
+			}
+		case len(item.Locations) > 0:
+			var found bool
+			for _, loc := range item.Locations {
+				folder := filepath.Clean(s.deps.Plex.TranslateToLocal(loc))
+				found = found || tracked[folder]
+				if path == "" {
+					path = folder
+				}
+			}
+			if found {
+				continue
+			}
+		default:
+			continue
+		}
+		out = append(out, plexUntrackedItem{
+			Section:   sec.Title,
+			RatingKey: item.RatingKey,
+			Title:     item.Title,
+			Year:      item.Year,
+			Type:      item.Type,
+			Path:      path,
+			Fix:       fmt.Sprintf("arrgo library import --from-plex %q", sec.Title),
+		})
+	}
+	return out
+}
+
+// plexMissingFiles describes files missing from Plex with the content they
+// belong to, loaded in one query, and the state of each file on disk. A file
+// Plex cannot read is more likely a permissions problem than a missed scan.
+func (s *Server) plexMissingFiles(files []*library.File, fixes map[int64][]string, sections []*plexMissingSection) []plexMissingFile {
+	out := make([]plexMissingFile, 0, len(files))
+	if len(files) == 0 {
+		return out
+	}
+	ids := make([]int64, 0, len(files))
+	for _, f := range files {
+		ids = append(ids, f.ContentID)
+	}
+	content := make(map[int64]*library.Content)
+	if list, _, err := s.deps.Library.ListContent(library.ContentFilter{IDs: uniqueIDs(ids)}); err == nil {
+		for _, c := range list {
+			content[c.ID] = c
+		}
+	}
+
+	for _, f := range files {
+		item := plexMissingFile{
+			FileID:    f.ID,
+			ContentID: f.ContentID,
+			EpisodeID: f.EpisodeID,
+			Path:      f.Path,
+			Fixes:     fixes[f.ID],
+		}
+		if c := content[f.ContentID]; c != nil {
+			item.Title = c.Title
+			item.Year = c.Year
+		}
+		if sec := sectionForFile(sections, filepath.Clean(f.Path)); sec != nil {
+			item.Section = sec.section.Title
+		}
+
+		info, err := os.Stat(f.Path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			item.Error = "file not found on disk"
+			item.Fixes = []string{"arrgo library check"}
+		case err != nil:
+			item.Error = err.Error()
+			item.Fixes = append([]string{"check the permissions of " + filepath.Dir(f.Path)}, item.Fixes...)
+		default:
+			item.Mode = info.Mode().Perm().String()
+			if st, ok := info.Sys().(*syscall.Stat_t); ok {
+				item.Owner = fmt.Sprintf("%d:%d", st.Uid, st.Gid)
+			}
+			// Plex rarely runs as the file's owner; it reads through the other bits
+			if info.Mode().Perm()&0o004 == 0 {
+				item.Fixes = append([]string{fmt.Sprintf("check permissions: %s is %s, owned by %s", f.Path, item.Mode, item.Owner)}, item.Fixes...)
+			}
+		}
+		out = append(out, item)
+	}
+	return out
+}
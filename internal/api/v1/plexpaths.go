@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vmunix/arrgo/internal/importer"
+)
+
+const (
+	defaultPathSample = 20  // Items sampled per library when validating path mappings
+	maxPathSample     = 200 // Upper bound on the per-library sample
+	// maxPathMissRatio is the share of sampled paths that may fail to resolve
+	// before a Plex library import is refused.
+	maxPathMissRatio = 0.5
+)
+
+// listPathMappings handles GET /api/v1/plex/path-mappings.
+func (s *Server) listPathMappings(w http.ResponseWriter, r *http.Request) {
+	resp := pathMappingsResponse{Mappings: []pathMapping{}}
+	for _, m := range s.deps.Plex.PathMappings() {
+		resp.Mappings = append(resp.Mappings, pathMapping{Remote: m.Remote, Local: m.Local})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// validatePathMappings handles POST /api/v1/plex/path-mappings/validate.
+// It samples items from each Plex library, translates their file paths, and
+// reports how many exist locally under each mapping.
+func (s *Server) validatePathMappings(w http.ResponseWriter, r *http.Request) {
+	var req pathMappingValidateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+			return
+		}
+	}
+	if req.Sample < 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_SAMPLE", "sample must be non-negative")
+		return
+	}
+	sample := req.Sample
+	if sample == 0 {
+		sample = defaultPathSample
+	}
+	sample = min(sample, maxPathSample)
+
+	ctx := r.Context()
+	sections, err := s.deps.Plex.GetSections(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "PLEX_ERROR", err.Error())
+		return
+	}
+	if len(req.Libraries) > 0 {
+		var selected []importer.Section
+		for _, name := range req.Libraries {
+			var found bool
+			for _, sec := range sections {
+				if strings.EqualFold(sec.Title, name) {
+					selected = append(selected, sec)
+					found = true
+					break
+				}
+			}
+			if !found {
+				writeError(w, http.StatusBadRequest, "LIBRARY_NOT_FOUND", fmt.Sprintf("library %q not found", name))
+				return
+			}
+		}
+		sections = selected
+	}
+
+	resp := pathMappingValidateResponse{Libraries: []pathSampleLibrary{}}
+	var paths []string
+	for _, sec := range sections {
+		lib := pathSampleLibrary{Name: sec.Title}
+		items, err := s.deps.Plex.ListLibraryItems(ctx, sec.Key)
+		if err != nil {
+			lib.Error = err.Error()
+		} else {
+			sampled := importer.SamplePaths(items, sample)
+			lib.Sampled = len(sampled)
+			paths = append(paths, sampled...)
+		}
+		resp.Libraries = append(resp.Libraries, lib)
+	}
+
+	check := importer.CheckPaths(paths, s.deps.Plex.PathMappings(), s.deps.Plex.TranslateToLocal)
+	resp.Sampled = check.Sampled
+	resp.Resolved = check.Resolved
+	resp.Mappings = pathMappingChecks(check)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// pathMappingChecks converts per-mapping results to their response form.
+func pathMappingChecks(check *importer.PathCheck) []pathMappingCheck {
+	out := make([]pathMappingCheck, len(check.Mappings))
+	for i, m := range check.Mappings {
+		out[i] = pathMappingCheck{
+			Remote: m.Remote,
+			Local:  m.Local,
+			Hits:   m.Hits,
+			Misses: m.Misses,
+		}
+		for _, f := range m.Failing {
+			out[i].Failing = append(out[i].Failing, pathFailingPath{PlexPath: f.PlexPath, LocalPath: f.LocalPath, Error: f.Error})
+		}
+	}
+	return out
+}
+
+// checkImportPaths samples the file paths of items and returns an error
+// describing the problem when most of them do not exist locally, which
+// almost always means the Plex path mapping is wrong.
+func (s *Server) checkImportPaths(items []importer.PlexItem) error {
+	check := importer.CheckPaths(importer.SamplePaths(items, defaultPathSample), s.deps.Plex.PathMappings(), s.deps.Plex.TranslateToLocal)
+	if check.MissRatio() <= maxPathMissRatio {
+		return nil
+	}
+
+	msg := fmt.Sprintf("%d of %d sampled Plex paths do not exist locally", check.Sampled-check.Resolved, check.Sampled)
+	for _, m := range check.Mappings {
+		if len(m.Failing) > 0 {
+			f := m.Failing[0]
+			msg += fmt.Sprintf(" (e.g. %s -> %s)", f.PlexPath, f.LocalPath)
+			break
+		}
+	}
+	if len(s.deps.Plex.PathMappings()) == 0 {
+		msg += "; set notifications.plex.remote_path and local_path if Plex sees files under a different path"
+	} else {
+		msg += "; check notifications.plex.remote_path and local_path"
+	}
+	return fmt.Errorf("%s, or POST /api/v1/plex/path-mappings/validate for details", msg)
+}
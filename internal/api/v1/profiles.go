@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/profile"
+)
+
+// profileNamePattern limits profile names to what reads well in config,
+// URLs, and the compat API.
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// listProfiles handles GET /api/v1/profiles.
+func (s *Server) listProfiles(w http.ResponseWriter, _ *http.Request) {
+	if s.deps.Profiles == nil {
+		writeJSON(w, http.StatusOK, listProfilesResponse{Profiles: []profileResponse{}, ReadOnly: true})
+		return
+	}
+	profiles, err := s.deps.Profiles.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	resp := listProfilesResponse{
+		Profiles: make([]profileResponse, len(profiles)),
+		ReadOnly: s.deps.Profiles.ReadOnly(),
+	}
+	for i, p := range profiles {
+		resp.Profiles[i] = profileToResponse(p)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getProfile handles GET /api/v1/profiles/{name}.
+func (s *Server) getProfile(w http.ResponseWriter, r *http.Request) {
+	if !s.requireProfiles(w) {
+		return
+	}
+	p, err := s.deps.Profiles.Get(r.PathValue("name"))
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, profileToResponse(p))
+}
+
+// createProfile handles POST /api/v1/profiles.
+func (s *Server) createProfile(w http.ResponseWriter, r *http.Request) {
+	if !s.requireProfiles(w) {
+		return
+	}
+	var req profileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "name is required")
+		return
+	}
+	if !profileNamePattern.MatchString(req.Name) {
+		writeError(w, http.StatusBadRequest, "INVALID_PROFILE", "name must be up to 64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+		return
+	}
+	if !checkProfileSettings(w, req.Settings) {
+		return
+	}
+
+	p := &profile.Profile{Name: req.Name, Settings: req.Settings}
+	if err := s.deps.Profiles.Create(p); err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, profileToResponse(p))
+}
+
+// updateProfile handles PUT /api/v1/profiles/{name}.
+// Replaces the profile's settings; the next search uses them.
+func (s *Server) updateProfile(w http.ResponseWriter, r *http.Request) {
+	if !s.requireProfiles(w) {
+		return
+	}
+	name := r.PathValue("name")
+	var req profileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+	if req.Name != "" && req.Name != name {
+		writeError(w, http.StatusBadRequest, "INVALID_PROFILE", "profiles can't be renamed; create a new profile and move content to it")
+		return
+	}
+	if !checkProfileSettings(w, req.Settings) {
+		return
+	}
+
+	p := &profile.Profile{Name: name, Settings: req.Settings}
+	if err := s.deps.Profiles.Update(p); err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, profileToResponse(p))
+}
+
+// deleteProfile handles DELETE /api/v1/profiles/{name}.
+// Refuses with 409 while content or episodes use the profile.
+func (s *Server) deleteProfile(w http.ResponseWriter, r *http.Request) {
+	if !s.requireProfiles(w) {
+		return
+	}
+	if err := s.deps.Profiles.Delete(r.PathValue("name")); err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireProfiles writes an error and returns false if no profile store is
+// configured.
+func (s *Server) requireProfiles(w http.ResponseWriter) bool {
+	if s.deps.Profiles == nil {
+		writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "quality profiles not configured")
+		return false
+	}
+	return true
+}
+
+// checkProfileSettings writes an error and returns false if the settings
+// are invalid.
+func checkProfileSettings(w http.ResponseWriter, settings config.QualityProfile) bool {
+	issues := config.ValidateQualityProfile("settings", settings)
+	if len(issues) == 0 {
+		return true
+	}
+	msgs := make([]string, len(issues))
+	for i, issue := range issues {
+		msgs[i] = issue.String()
+	}
+	writeError(w, http.StatusBadRequest, "INVALID_PROFILE", strings.Join(msgs, "; "))
+	return false
+}
+
+// writeProfileError writes the response for a profile store error.
+func writeProfileError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, profile.ErrNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Quality profile not found")
+	case errors.Is(err, profile.ErrDuplicate):
+		writeError(w, http.StatusConflict, "DUPLICATE", "Quality profile already exists")
+	case errors.Is(err, profile.ErrInUse):
+		writeError(w, http.StatusConflict, "PROFILE_IN_USE", fmt.Sprintf("%v; move the content to another profile first", err))
+	case errors.Is(err, profile.ErrReadOnly):
+		writeError(w, http.StatusConflict, "READ_ONLY", "Quality profiles come from config (quality.legacy_profiles); edit config.toml instead")
+	default:
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+	}
+}
+
+func profileToResponse(p *profile.Profile) profileResponse {
+	resp := profileResponse{
+		ID:       p.ID,
+		Name:     p.Name,
+		Accept:   p.Settings.Resolution,
+		Cutoff:   p.Cutoff(),
+		Settings: p.Settings,
+	}
+	if resp.Accept == nil {
+		resp.Accept = []string{}
+	}
+	if !p.CreatedAt.IsZero() {
+		resp.CreatedAt = &p.CreatedAt
+		resp.UpdatedAt = &p.UpdatedAt
+	}
+	return resp
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// PublicPathPrefix is the path prefix of endpoints that are served without
+// authentication. Anything under it must be safe to expose: no file paths,
+// URLs, or indexer names.
+const PublicPathPrefix = "/api/v1/public/"
+
+const (
+	defaultPublicRecent = 10 // Recently added items in the public summary
+	maxPublicRecent     = 50
+	// publicCacheControl lets a reverse proxy cache the public summary briefly.
+	publicCacheControl = "public, max-age=30"
+)
+
+// publicSummary handles GET /api/v1/public/summary.
+// It is only registered when the public status page is enabled and is built
+// from display fields only, so it is safe to serve without an API key.
+func (s *Server) publicSummary(w http.ResponseWriter, r *http.Request) {
+	limit := queryInt(r, "limit", defaultPublicRecent)
+	if limit <= 0 || limit > maxPublicRecent {
+		limit = defaultPublicRecent
+	}
+
+	recent, err := s.deps.Library.ListRecentImports(limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	unfinished, _, err := s.deps.Downloads.List(download.Filter{Active: true})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	var active []*download.Download
+	for _, dl := range unfinished {
+		if dl.Status == download.StatusQueued || dl.Status == download.StatusDownloading {
+			active = append(active, dl)
+		}
+	}
+
+	resp := publicSummaryResponse{
+		RecentlyAdded: make([]publicImportResponse, len(recent)),
+		Downloads: publicDownloadsResponse{
+			Active:   len(active),
+			Progress: aggregateProgress(active),
+		},
+	}
+	for i, ri := range recent {
+		resp.RecentlyAdded[i] = publicImportResponse{
+			Type:    string(ri.Type),
+			Title:   ri.Title,
+			Year:    ri.Year,
+			Quality: ri.Quality,
+			Season:  ri.Season,
+			Episode: ri.Episode,
+		}
+	}
+
+	movieType := library.ContentTypeMovie
+	seriesType := library.ContentTypeSeries
+	available := library.StatusAvailable
+	if _, resp.Library.Movies, err = s.deps.Library.ListContent(library.ContentFilter{Type: &movieType, Limit: 1}); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	if _, resp.Library.Series, err = s.deps.Library.ListContent(library.ContentFilter{Type: &seriesType, Limit: 1}); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	if _, resp.Library.Episodes, err = s.deps.Library.ListEpisodes(library.EpisodeFilter{Status: &available, Limit: 1}); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	w.Header().Set("Cache-Control", publicCacheControl)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// aggregateProgress returns the combined progress of downloads (0-100), weighted
+// by size when every download's size is known.
+func aggregateProgress(downloads []*download.Download) float64 {
+	if len(downloads) == 0 {
+		return 0
+	}
+	var total, done, sum float64
+	sized := true
+	for _, dl := range downloads {
+		sum += dl.Progress
+		if dl.Size <= 0 {
+			sized = false
+			continue
+		}
+		total += float64(dl.Size)
+		done += float64(dl.Size) * dl.Progress / 100
+	}
+	if sized {
+		return done / total * 100
+	}
+	return sum / float64(len(downloads))
+}
@@ -0,0 +1,323 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/search"
+)
+
+// releasePreviewTTL is how long a previewed release can be grabbed by GUID.
+const releasePreviewTTL = 30 * time.Minute
+
+// previewKey identifies a previewed release.
+type previewKey struct {
+	contentID int64
+	guid      string
+}
+
+type previewEntry struct {
+	release *search.Release
+	season  *int
+	episode *int
+	expires time.Time
+}
+
+// previewCache holds releases returned by GET /content/{id}/releases so a
+// later POST grabs exactly the release the user saw, without searching again.
+type previewCache struct {
+	mu      sync.Mutex
+	entries map[previewKey]previewEntry
+	ttl     time.Duration
+}
+
+func newPreviewCache(ttl time.Duration) *previewCache {
+	return &previewCache{
+		entries: make(map[previewKey]previewEntry),
+		ttl:     ttl,
+	}
+}
+
+// set replaces the cached preview for a content item.
+func (c *previewCache) set(contentID int64, releases []*search.Release, season, episode *int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if k.contentID == contentID || now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	for _, rel := range releases {
+		if rel.GUID == "" {
+			continue
+		}
+		c.entries[previewKey{contentID, rel.GUID}] = previewEntry{
+			release: rel,
+			season:  season,
+			episode: episode,
+			expires: now.Add(c.ttl),
+		}
+	}
+}
+
+func (c *previewCache) get(contentID int64, guid string) (previewEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[previewKey{contentID, guid}]
+	if !ok || time.Now().After(e.expires) {
+		return previewEntry{}, false
+	}
+	return e, true
+}
+
+// previewReleases handles GET /api/v1/content/{id}/releases.
+// Searches with the query and quality profile arrgo itself would use for the
+// content, returning scored and rejected releases without grabbing anything.
+func (s *Server) previewReleases(w http.ResponseWriter, r *http.Request) {
+	content, ok := s.releaseContent(w, r)
+	if !ok {
+		return
+	}
+
+	season, err := queryOptionalInt(r, "season")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_SEASON", err.Error())
+		return
+	}
+	episode, err := queryOptionalInt(r, "episode")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_EPISODE", err.Error())
+		return
+	}
+	if content.Type == library.ContentTypeMovie && (season != nil || episode != nil) {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "season and episode only apply to series")
+		return
+	}
+	if episode != nil && season == nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "episode requires season")
+		return
+	}
+
+	protocol, err := queryProtocol(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PROTOCOL", err.Error())
+		return
+	}
+
+	q := search.ContentQuery(content, season, episode)
+	q.Protocol = protocol
+	profile := content.QualityProfile
+	if profile == "" {
+		profile = "hd"
+	}
+
+	result, err := s.deps.Searcher.Search(r.Context(), q, profile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "SEARCH_ERROR", err.Error())
+		return
+	}
+	s.previews.set(content.ID, result.Releases, season, episode)
+
+	writeJSON(w, http.StatusOK, releasePreviewResponse{
+		ContentID:      content.ID,
+		Query:          q.Text,
+		Profile:        profile,
+		searchResponse: searchResultToResponse(result),
+	})
+}
+
+// grabPreviewedRelease handles POST /api/v1/content/{id}/releases.
+// Grabs a release from the content's last preview by GUID.
+func (s *Server) grabPreviewedRelease(w http.ResponseWriter, r *http.Request) {
+	content, ok := s.releaseContent(w, r)
+	if !ok {
+		return
+	}
+
+	var req grabPreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+	if req.GUID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "guid is required")
+		return
+	}
+
+	entry, ok := s.previews.get(content.ID, req.GUID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "PREVIEW_NOT_FOUND",
+			"release not in a recent preview; run GET /api/v1/content/{id}/releases first")
+		return
+	}
+
+	grab := &grabRequest{
+		ContentID:   content.ID,
+		DownloadURL: entry.release.DownloadURL,
+		Title:       entry.release.Title,
+		Indexer:     entry.release.Indexer,
+		GUID:        entry.release.GUID,
+		Protocol:    string(entry.release.Protocol),
+		Season:      entry.season,
+	}
+	if entry.episode != nil {
+		grab.Episodes = []int{*entry.episode}
+	}
+	s.grabRelease(w, r, grab)
+}
+
+// pinRelease handles POST /api/v1/content/{id}/pin.
+// Pins a release for the content, replacing any earlier pin: the wanted
+// search, download retries and compat searches grab it directly instead of
+// scoring search results, until it imports. A pin whose grab or download
+// fails is dropped and the content is searched for as usual. The release is
+// given by GUID from the content's last preview, or by download URL and
+// title. Series pin a season pack, so they need a season.
+func (s *Server) pinRelease(w http.ResponseWriter, r *http.Request) {
+	content, ok := s.releaseContent(w, r)
+	if !ok {
+		return
+	}
+
+	var req pinReleaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", err.Error())
+		return
+	}
+	pin := &library.PinnedRelease{
+		GUID:        req.GUID,
+		DownloadURL: req.DownloadURL,
+		Title:       req.Title,
+		Indexer:     req.Indexer,
+		Protocol:    req.Protocol,
+		Season:      req.Season,
+	}
+	if req.DownloadURL == "" && req.GUID != "" {
+		entry, ok := s.previews.get(content.ID, req.GUID)
+		if !ok {
+			writeError(w, http.StatusNotFound, "PREVIEW_NOT_FOUND",
+				"release not in a recent preview; run GET /api/v1/content/{id}/releases first or give download_url and title")
+			return
+		}
+		pin.DownloadURL = entry.release.DownloadURL
+		pin.Title = entry.release.Title
+		pin.Indexer = entry.release.Indexer
+		pin.Protocol = string(entry.release.Protocol)
+		if pin.Season == nil {
+			pin.Season = entry.season
+		}
+	}
+
+	if pin.DownloadURL == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "guid or download_url is required")
+		return
+	}
+	if download.IsUploadURL(pin.DownloadURL) {
+		writeError(w, http.StatusBadRequest, "INVALID_RELEASE", "download_url can't be a local file")
+		return
+	}
+	if pin.Title == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "title is required")
+		return
+	}
+	switch download.Protocol(pin.Protocol) {
+	case "", download.ProtocolUsenet, download.ProtocolTorrent:
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_PROTOCOL", "protocol must be usenet or torrent")
+		return
+	}
+	switch {
+	case content.Type == library.ContentTypeSeries && pin.Season == nil:
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "season is required for series")
+		return
+	case content.Type != library.ContentTypeSeries && pin.Season != nil:
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "season only applies to series")
+		return
+	}
+
+	if err := s.deps.Library.PinRelease(content.ID, pin); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+	content.Pin = pin
+
+	var stats *library.SeriesStats
+	if content.Type == library.ContentTypeSeries {
+		stats, _ = s.deps.Library.GetSeriesStats(content.ID)
+	}
+	writeJSON(w, http.StatusOK, s.contentResponse(content, stats))
+}
+
+// unpinRelease handles DELETE /api/v1/content/{id}/pin.
+func (s *Server) unpinRelease(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+
+	if err := s.deps.Library.UnpinRelease(id); err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "No pinned release")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// queryOptionalInt parses an optional non-negative integer query parameter.
+// Returns nil if the parameter is absent.
+func queryOptionalInt(r *http.Request, name string) (*int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return &n, nil
+}
+
+// queryProtocol parses the optional protocol query parameter, "usenet" or
+// "torrent". Returns "" if the parameter is absent.
+func queryProtocol(r *http.Request) (download.Protocol, error) {
+	switch p := download.Protocol(r.URL.Query().Get("protocol")); p {
+	case "", download.ProtocolUsenet, download.ProtocolTorrent:
+		return p, nil
+	default:
+		return "", fmt.Errorf("protocol must be usenet or torrent; got %q", p)
+	}
+}
+
+// releaseContent loads the content named by the path ID, writing an error response on failure.
+func (s *Server) releaseContent(w http.ResponseWriter, r *http.Request) (*library.Content, bool) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return nil, false
+	}
+	content, err := s.deps.Library.GetContent(id)
+	if err != nil {
+		if errors.Is(err, library.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Content not found")
+			return nil, false
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return nil, false
+	}
+	return content, true
+}
@@ -0,0 +1,337 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/search"
+)
+
+// Retry strategies for POST /downloads/{id}/retry.
+const (
+	retrySame     = "same"     // Search by content title and grab the best release
+	retryEpisodes = "episodes" // Season packs: grab each missing episode of the season
+	retryAuto     = "auto"     // Season packs: another pack scoring at least min_score, else episodes
+
+	// retryPinned is reported instead when the release pinned for the content is grabbed
+	retryPinned = "pinned"
+)
+
+// retryEpisodeConcurrency caps the episode searches a retry runs at once.
+const retryEpisodeConcurrency = 4
+
+// retryDownload handles POST /api/v1/downloads/{id}/retry.
+// Searches again for a failed download and grabs what it finds, by the
+// strategy query parameter: "same" (default), "episodes" or "auto". The
+// episode strategies apply to season packs; auto on any other download is
+// same. A release pinned for the content (for series, the download's season)
+// is grabbed instead of searching, unless it is the release that failed.
+func (s *Server) retryDownload(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", err.Error())
+		return
+	}
+	strategy := r.URL.Query().Get("strategy")
+	switch strategy {
+	case "":
+		strategy = retrySame
+	case retrySame, retryEpisodes, retryAuto:
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_STRATEGY", "strategy must be 'same', 'episodes' or 'auto'")
+		return
+	}
+	minScore := queryInt(r, "min_score", 0)
+
+	// Get the failed download
+	dl, err := s.deps.Downloads.Get(id)
+	if err != nil {
+		if errors.Is(err, download.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Download not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	// Only allow retry on failed downloads
+	if dl.Status != download.StatusFailed {
+		writeError(w, http.StatusBadRequest, "INVALID_STATE",
+			fmt.Sprintf("Can only retry failed downloads, current status: %s", dl.Status))
+		return
+	}
+
+	// Require event bus for retry operations (grabs go through event bus)
+	if s.deps.Bus == nil {
+		writeError(w, http.StatusServiceUnavailable, "NO_EVENT_BUS", "event bus not configured")
+		return
+	}
+
+	// Get content to search for
+	content, err := s.deps.Library.GetContent(dl.ContentID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "CONTENT_ERROR", err.Error())
+		return
+	}
+
+	pack := content.Type == library.ContentTypeSeries && dl.Season != nil && dl.IsCompleteSeason
+	if !pack {
+		if strategy == retryEpisodes {
+			writeError(w, http.StatusBadRequest, "INVALID_STRATEGY", "the episodes strategy only applies to season packs")
+			return
+		}
+		strategy = retrySame
+	}
+	profile := content.QualityProfile
+	if profile == "" {
+		profile = "hd"
+	}
+
+	resp := retryResponse{Strategy: strategy, PreviousError: dl.LastError}
+	var grabs []*events.GrabRequested
+	if grab := retryPinnedGrab(dl, content); grab != nil {
+		resp.Strategy = retryPinned
+		strategy = retryPinned
+		grabs = append(grabs, grab)
+	}
+	switch strategy {
+	case retrySame:
+		grab, err := s.retrySameGrab(r.Context(), dl, content, profile)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "SEARCH_ERROR", err.Error())
+			return
+		}
+		if grab != nil {
+			grabs = append(grabs, grab)
+		}
+	case retryAuto:
+		grab, err := s.retryPackGrab(r.Context(), dl, content, profile, minScore)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "SEARCH_ERROR", err.Error())
+			return
+		}
+		if grab != nil {
+			resp.Strategy = search.StrategySeasonPack
+			grabs = append(grabs, grab)
+			break
+		}
+		resp.Strategy = retryEpisodes
+		fallthrough
+	case retryEpisodes:
+		episodeGrabs, warnings, err := s.retryEpisodeGrabs(r.Context(), dl, content, profile)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+		grabs = append(grabs, episodeGrabs...)
+		resp.Warnings = warnings
+	}
+	if len(grabs) == 0 {
+		writeError(w, http.StatusNotFound, "NO_RESULTS", "No releases found")
+		return
+	}
+
+	resp.Grabs = make([]retryGrabResponse, 0, len(grabs))
+	for _, grab := range grabs {
+		if err := s.deps.Bus.Publish(r.Context(), grab); err != nil {
+			writeError(w, http.StatusInternalServerError, "EVENT_ERROR", err.Error())
+			return
+		}
+		resp.Grabs = append(resp.Grabs, retryGrabToResponse(grab))
+	}
+	resp.ReleaseName = grabs[0].ReleaseName
+	resp.Message = "Retry queued"
+	if len(grabs) > 1 {
+		resp.Message = fmt.Sprintf("Retry queued: %d grabs", len(grabs))
+	}
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// retryPinnedGrab returns a grab of the release pinned for the failed
+// download's content, or nil if there is none for its season or it is the
+// release that failed.
+func retryPinnedGrab(dl *download.Download, content *library.Content) *events.GrabRequested {
+	var season *int
+	if content.Type == library.ContentTypeSeries {
+		season = dl.Season
+	}
+	pin := content.Pin
+	if pin == nil || !pin.ForSeason(season) || pin.Matches(dl.GUID, dl.ReleaseName) {
+		return nil
+	}
+	return search.PinnedGrab(content.ID, pin)
+}
+
+// retrySameGrab searches again by content title and returns a grab of the
+// best release, or nil if nothing but the failed release was found.
+func (s *Server) retrySameGrab(ctx context.Context, dl *download.Download, content *library.Content, profile string) (*events.GrabRequested, error) {
+	result, err := s.deps.Searcher.Search(ctx, search.ContentQuery(content, nil, nil), profile)
+	if err != nil {
+		return nil, err
+	}
+	candidates := retryCandidates(dl, result)
+	if len(candidates.Releases) == 0 {
+		return nil, nil
+	}
+	best := candidates.Releases[0]
+	return &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   dl.ContentID,
+		EpisodeID:   dl.EpisodeID,
+		DownloadURL: best.DownloadURL,
+		ReleaseName: best.Title,
+		Indexer:     best.Indexer,
+		GUID:        best.GUID,
+		Decision:    search.NewGrabDecision(candidates, best, profile),
+		Alternates:  search.GrabAlternates(candidates, best),
+	}, nil
+}
+
+// retryPackGrab searches for another pack of the failed pack's season and
+// returns a grab of the best one scoring at least minScore, or nil if none does.
+func (s *Server) retryPackGrab(ctx context.Context, dl *download.Download, content *library.Content, profile string, minScore int) (*events.GrabRequested, error) {
+	season := *dl.Season
+	q := search.ContentQuery(content, &season, nil)
+	q.SeasonEpisodes = len(dl.EpisodeIDs)
+	result, err := s.deps.Searcher.Search(ctx, q, profile)
+	if err != nil {
+		return nil, err
+	}
+	candidates := retryCandidates(dl, result)
+	if len(candidates.Releases) == 0 || candidates.Releases[0].Score < minScore {
+		return nil, nil
+	}
+	best := candidates.Releases[0]
+	decision := search.NewGrabDecision(candidates, best, profile)
+	decision.Strategy = search.StrategySeasonPack
+	decision.StrategyReason = fmt.Sprintf("retry of failed pack: best pack scored %d, at least %d", best.Score, minScore)
+	return &events.GrabRequested{
+		BaseEvent:        events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:        dl.ContentID,
+		Season:           &season,
+		IsCompleteSeason: true,
+		DownloadURL:      best.DownloadURL,
+		ReleaseName:      best.Title,
+		Indexer:          best.Indexer,
+		GUID:             best.GUID,
+		Protocol:         string(best.Protocol),
+		Decision:         decision,
+		Alternates:       search.GrabAlternates(candidates, best),
+	}, nil
+}
+
+// retryEpisodeGrabs searches for each missing episode of the failed pack's
+// season, retryEpisodeConcurrency at a time, and returns a grab of the best
+// release for each one found, in episode order. Episodes that failed to
+// search or found nothing are reported as warnings.
+func (s *Server) retryEpisodeGrabs(ctx context.Context, dl *download.Download, content *library.Content, profile string) ([]*events.GrabRequested, []string, error) {
+	season := *dl.Season
+	episodes, _, err := s.deps.Library.ListEpisodes(library.EpisodeFilter{ContentID: &content.ID, Season: &season})
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	var missing []*library.Episode
+	for _, ep := range episodes {
+		if ep.Status == library.StatusWanted && (ep.AirDate == nil || !ep.AirDate.After(now)) {
+			missing = append(missing, ep)
+		}
+	}
+	reason := fmt.Sprintf("retry of failed pack: %d episodes of season %d missing", len(missing), season)
+
+	grabs := make([]*events.GrabRequested, len(missing))
+	warnings := make([]string, len(missing))
+	sem := make(chan struct{}, retryEpisodeConcurrency)
+	var wg sync.WaitGroup
+	for i, ep := range missing {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			label := fmt.Sprintf("S%02dE%02d", season, ep.Episode)
+			episode := ep.Episode
+			epProfile, _ := ep.EffectiveProfile(profile)
+			result, err := s.deps.Searcher.Search(ctx, search.ContentQuery(content, &season, &episode), epProfile)
+			if err != nil {
+				warnings[i] = fmt.Sprintf("%s: search failed: %v", label, err)
+				return
+			}
+			candidates := retryCandidates(dl, result)
+			if len(candidates.Releases) == 0 {
+				warnings[i] = label + ": no releases found"
+				return
+			}
+			best := candidates.Releases[0]
+			decision := search.NewGrabDecision(candidates, best, epProfile)
+			decision.Strategy, decision.StrategyReason = search.StrategyEpisodes, reason
+			grabs[i] = &events.GrabRequested{
+				BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+				ContentID:   dl.ContentID,
+				EpisodeID:   &ep.ID,
+				EpisodeIDs:  []int64{ep.ID},
+				Season:      &season,
+				DownloadURL: best.DownloadURL,
+				ReleaseName: best.Title,
+				Indexer:     best.Indexer,
+				GUID:        best.GUID,
+				Protocol:    string(best.Protocol),
+				Decision:    decision,
+				Alternates:  search.GrabAlternates(candidates, best),
+			}
+		}()
+	}
+	wg.Wait()
+
+	var found []*events.GrabRequested
+	var notes []string
+	for i := range missing {
+		if grabs[i] != nil {
+			found = append(found, grabs[i])
+		}
+		if warnings[i] != "" {
+			notes = append(notes, warnings[i])
+		}
+	}
+	return found, notes, nil
+}
+
+// retryCandidates returns the search results worth grabbing for a retry,
+// best first: never the release that just failed, since a failure such as
+// missing articles would only repeat with the same NZB. Other previously
+// failed releases are filtered by the searcher's blocklist. Results after
+// the best are fallbacks if the client rejects it.
+func retryCandidates(dl *download.Download, result *search.Result) *search.Result {
+	candidates := &search.Result{}
+	for _, rel := range result.Releases {
+		if dl.GUID != "" && rel.GUID == dl.GUID {
+			continue
+		}
+		if rel.Title == dl.ReleaseName {
+			continue
+		}
+		candidates.Releases = append(candidates.Releases, rel)
+	}
+	return candidates
+}
+
+func retryGrabToResponse(grab *events.GrabRequested) retryGrabResponse {
+	resp := retryGrabResponse{
+		ReleaseName: grab.ReleaseName,
+		Indexer:     grab.Indexer,
+		Season:      grab.Season,
+		EpisodeIDs:  grab.EpisodeIDs,
+	}
+	if grab.Decision != nil {
+		resp.Score = grab.Decision.Chosen.Score
+	}
+	return resp
+}
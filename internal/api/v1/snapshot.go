@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// Snapshot import modes.
+const (
+	snapshotSkipExisting = "skip-existing" // Leave content already in the library as is
+	snapshotOverwrite    = "overwrite"     // Replace content already in the library from the snapshot
+)
+
+// snapshotBatchSize is how many snapshot items are restored per transaction.
+const snapshotBatchSize = 100
+
+// exportLibrary handles GET /api/v1/export.
+// Streams the library as a JSON snapshot; see library.Store.WriteSnapshot.
+func (s *Server) exportLibrary(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="arrgo-library.json"`)
+	// The status is sent with the first item, so a failure part way through
+	// can only end the response; the truncated document fails to parse.
+	_, _ = s.deps.Library.WriteSnapshot(w)
+}
+
+// importSnapshot handles POST /api/v1/import/snapshot?mode=skip-existing|overwrite.
+// Restores a snapshot from GET /api/v1/export, decoding it as it is read and
+// committing every snapshotBatchSize items, with a progress event per batch.
+// Content already in the library is skipped or overwritten depending on mode.
+// A malformed snapshot stops the import; batches committed before it are kept.
+func (s *Server) importSnapshot(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = snapshotSkipExisting
+	}
+	if mode != snapshotSkipExisting && mode != snapshotOverwrite {
+		writeError(w, http.StatusBadRequest, "INVALID_MODE",
+			fmt.Sprintf("invalid mode %q (want %s or %s)", mode, snapshotSkipExisting, snapshotOverwrite))
+		return
+	}
+
+	resp := snapshotImportResponse{Mode: mode, Items: []snapshotItemResult{}}
+	var tx *library.Tx
+	pending := 0
+	commit := func(done bool) error {
+		if tx != nil {
+			err := tx.Commit()
+			tx = nil
+			if err != nil {
+				return fmt.Errorf("commit transaction: %w", err)
+			}
+		}
+		pending = 0
+		if s.deps.Bus != nil {
+			_ = s.deps.Bus.Publish(r.Context(), &events.SnapshotProgressed{
+				BaseEvent: events.NewBaseEvent(events.EventSnapshotProgressed, events.EntityLibrary, 0),
+				Mode:      mode,
+				Processed: resp.Total,
+				Created:   resp.Created,
+				Updated:   resp.Updated,
+				Skipped:   resp.Skipped,
+				Failed:    resp.Failed,
+				Done:      done,
+			})
+		}
+		return nil
+	}
+
+	var dbErr error // Set when the import stopped on the database rather than the snapshot
+	err := library.ReadSnapshot(r.Body, func(item *library.SnapshotItem) error {
+		if tx == nil {
+			if tx, dbErr = s.deps.Library.Begin(); dbErr != nil {
+				return dbErr
+			}
+		}
+		result := snapshotItemResult{Type: string(item.Type), Title: item.Title, Year: item.Year}
+		id, action, err := tx.RestoreSnapshotItem(item, mode == snapshotOverwrite)
+		resp.Total++
+		switch {
+		case err != nil:
+			result.Result, result.Error = "failed", err.Error()
+			resp.Failed++
+		case action == library.SnapshotCreated:
+			resp.Created++
+		case action == library.SnapshotUpdated:
+			resp.Updated++
+		default:
+			resp.Skipped++
+		}
+		if err == nil {
+			result.ContentID, result.Result = id, string(action)
+		}
+		resp.Items = append(resp.Items, result)
+
+		if pending++; pending == snapshotBatchSize {
+			dbErr = commit(false)
+			return dbErr
+		}
+		return nil
+	})
+	if err != nil {
+		if tx != nil {
+			_ = tx.Rollback()
+		}
+		if dbErr != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+		code := "INVALID_SNAPSHOT"
+		if errors.Is(err, library.ErrSnapshotVersion) {
+			code = "UNSUPPORTED_VERSION"
+		}
+		writeError(w, http.StatusBadRequest, code,
+			fmt.Sprintf("%v (%d items committed before the error)", err, resp.Total-pending))
+		return
+	}
+	if err := commit(true); err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
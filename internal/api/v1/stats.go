@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// Default and maximum number of items in the library stats' largest listing.
+const (
+	defaultLargestItems = 10
+	maxLargestItems     = 100
+)
+
+// storageGroupsToResponse converts storage groups to their API representation.
+func storageGroupsToResponse(groups []library.StorageGroup) []storageGroupResponse {
+	resp := make([]storageGroupResponse, len(groups))
+	for i, g := range groups {
+		resp[i] = storageGroupResponse{
+			Name:         g.Key,
+			FileCount:    g.Files,
+			SizeBytes:    g.SizeBytes,
+			AverageBytes: averageSize(g.SizeBytes, g.Files),
+		}
+	}
+	return resp
+}
+
+// averageSize returns size divided by count, or 0 if there is nothing to count.
+func averageSize(size int64, count int) int64 {
+	if count == 0 {
+		return 0
+	}
+	return size / int64(count)
+}
+
+// libraryStats handles GET /api/v1/library/stats.
+// Totals file sizes by content type and quality and lists the largest items;
+// ?largest=N sets how many (default 10, max 100, 0 omits the listing).
+func (s *Server) libraryStats(w http.ResponseWriter, r *http.Request) {
+	largestN := queryInt(r, "largest", defaultLargestItems)
+	if largestN < 0 {
+		largestN = 0
+	}
+	if largestN > maxLargestItems {
+		largestN = maxLargestItems
+	}
+
+	stats, err := s.deps.Library.StorageStats()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+		return
+	}
+
+	resp := libraryStatsResponse{
+		FileCount:    stats.Files,
+		SizeBytes:    stats.SizeBytes,
+		AverageBytes: averageSize(stats.SizeBytes, stats.Files),
+		ByType:       storageGroupsToResponse(stats.ByType),
+		ByQuality:    storageGroupsToResponse(stats.ByQuality),
+		Largest:      []largestContentResponse{},
+	}
+
+	if largestN > 0 {
+		largest, err := s.deps.Library.LargestContent(largestN)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "DB_ERROR", err.Error())
+			return
+		}
+		for _, c := range largest {
+			resp.Largest = append(resp.Largest, largestContentResponse{
+				ID:         c.ID,
+				Type:       string(c.Type),
+				Title:      c.Title,
+				Year:       c.Year,
+				SizeOnDisk: c.SizeOnDisk,
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -1,7 +1,14 @@
 // internal/api/v1/types.go
 package v1
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/pkg/release/scoring"
+)
 
 // contentResponse is the API representation of content.
 type contentResponse struct {
@@ -16,8 +23,49 @@ type contentResponse struct {
 	RootPath       string    `json:"root_path"`
 	AddedAt        time.Time `json:"added_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	// Movies: release stage required before searching, and the TMDB release date if known
+	MinimumAvailability string     `json:"minimum_availability"`
+	ReleaseDate         *time.Time `json:"release_date,omitempty"`
+	// Display metadata; empty if no metadata provider is configured
+	Overview  string   `json:"overview"`
+	PosterURL string   `json:"poster_url"`
+	Runtime   int      `json:"runtime"` // minutes
+	Genres    []string `json:"genres"`
+	// Total size of the content's files in bytes
+	SizeOnDisk int64 `json:"size_on_disk"`
+	// Whether the files on disk meet the cutoff of the quality profile, so
+	// wanted searches no longer look for a better release
+	QualityMet bool `json:"quality_met"`
 	// Series-only fields
+	Daily        bool                  `json:"daily,omitempty"` // Episodes are released and matched by air date
 	EpisodeStats *episodeStatsResponse `json:"episode_stats,omitempty"`
+	SeasonFolder *bool                 `json:"season_folder,omitempty"` // Omitted when inherited
+	// Naming template overriding the library's; omitted when inherited
+	NamingTemplate string `json:"naming_template,omitempty"`
+	// Audiobooks: the author, which names their folder
+	Author string `json:"author,omitempty"`
+	// Follow-up suggestions after an update (e.g. files to rename); empty otherwise
+	Warnings []string `json:"warnings,omitempty"`
+	// On add: the file Plex already had, adopted instead of searching for the movie
+	ExistingFile string `json:"existing_file,omitempty"`
+	// Release grabbed by searches instead of scoring results; omitted if none
+	PinnedRelease *pinnedReleaseResponse `json:"pinned_release,omitempty"`
+	// Movies: the TMDB collection they belong to; omitted if none
+	CollectionID   *int64 `json:"collection_id,omitempty"`
+	CollectionName string `json:"collection_name,omitempty"`
+	// Rating key of the content's Plex item once found; omitted until then
+	PlexKey string `json:"plex_key,omitempty"`
+}
+
+// pinnedReleaseResponse is the release pinned for a content item.
+type pinnedReleaseResponse struct {
+	GUID        string    `json:"guid,omitempty"`
+	DownloadURL string    `json:"download_url"`
+	Title       string    `json:"title"`
+	Indexer     string    `json:"indexer,omitempty"`
+	Protocol    string    `json:"protocol,omitempty"`
+	Season      *int      `json:"season,omitempty"` // Series: the season the release is a pack of
+	PinnedAt    time.Time `json:"pinned_at"`
 }
 
 // seasonStatsResponse contains statistics for a single season.
@@ -43,6 +91,39 @@ type listContentResponse struct {
 	Offset int               `json:"offset"`
 }
 
+// wantedItemResponse is a movie or episode listed by GET /wanted.
+type wantedItemResponse struct {
+	ContentID      int64      `json:"content_id"`
+	Type           string     `json:"type"`
+	Title          string     `json:"title"`
+	Year           int        `json:"year"`
+	EpisodeID      *int64     `json:"episode_id,omitempty"`
+	Season         *int       `json:"season,omitempty"`
+	Episode        *int       `json:"episode,omitempty"`
+	EpisodeTitle   string     `json:"episode_title,omitempty"`
+	AirDate        *time.Time `json:"air_date,omitempty"`
+	AddedAt        time.Time  `json:"added_at"`
+	QualityProfile string     `json:"quality_profile"`
+	CurrentQuality string     `json:"current_quality,omitempty"` // Best quality on disk; empty if missing
+	WantedQuality  string     `json:"wanted_quality,omitempty"`  // Best resolution the profile accepts
+	Reason         string     `json:"reason"`                    // "missing" or "cutoff_unmet"
+	Abandoned      bool       `json:"abandoned,omitempty"`       // Content was abandoned (only with include_abandoned)
+}
+
+// wantedSectionResponse is one section of GET /wanted.
+type wantedSectionResponse struct {
+	Items []wantedItemResponse `json:"items"`
+	Total int                  `json:"total"`
+}
+
+// wantedResponse is the response for GET /wanted.
+type wantedResponse struct {
+	Missing     wantedSectionResponse `json:"missing"`
+	CutoffUnmet wantedSectionResponse `json:"cutoff_unmet"`
+	Limit       int                   `json:"limit"`
+	Offset      int                   `json:"offset"`
+}
+
 // addContentRequest is the request body for POST /content.
 type addContentRequest struct {
 	Type           string `json:"type"`
@@ -52,12 +133,64 @@ type addContentRequest struct {
 	Year           int    `json:"year"`
 	QualityProfile string `json:"quality_profile"`
 	RootPath       string `json:"root_path,omitempty"`
+	Daily          bool   `json:"daily,omitempty"`  // Series released by air date
+	Author         string `json:"author,omitempty"` // Audiobooks only
+}
+
+// lookupResult is a TMDB movie or TVDB series match for GET /lookup.
+type lookupResult struct {
+	Type      string `json:"type"`
+	TMDBID    *int64 `json:"tmdb_id,omitempty"`
+	TVDBID    *int64 `json:"tvdb_id,omitempty"`
+	Title     string `json:"title"`
+	Year      int    `json:"year"`
+	Overview  string `json:"overview,omitempty"`
+	ContentID *int64 `json:"content_id,omitempty"` // Set if already in the library
+}
+
+// lookupResponse is the response for GET /lookup.
+type lookupResponse struct {
+	Results []lookupResult `json:"results"`
 }
 
 // updateContentRequest is the request body for PUT /content/:id.
 type updateContentRequest struct {
-	Status         *string `json:"status,omitempty"`
-	QualityProfile *string `json:"quality_profile,omitempty"`
+	Title               *string `json:"title,omitempty"`
+	Year                *int    `json:"year,omitempty"`
+	TMDBID              *int64  `json:"tmdb_id,omitempty"` // Movies only
+	TVDBID              *int64  `json:"tvdb_id,omitempty"` // Series only
+	RootPath            *string `json:"root_path,omitempty"`
+	RefreshMetadata     bool    `json:"refresh_metadata,omitempty"` // Refetch metadata (always done when an external ID changes)
+	Status              *string `json:"status,omitempty"`
+	Reason              string  `json:"reason,omitempty"` // Why content is abandoned; recorded in history
+	QualityProfile      *string `json:"quality_profile,omitempty"`
+	MinimumAvailability *string `json:"minimum_availability,omitempty"` // announced, inCinemas or released
+	Daily               *bool   `json:"daily,omitempty"`                // Series released by air date
+	// Naming overrides: null season_folder and "" naming_template inherit the library's
+	SeasonFolder   nullableBool `json:"season_folder"` // Series only
+	NamingTemplate *string      `json:"naming_template,omitempty"`
+	Author         *string      `json:"author,omitempty"` // Audiobooks only
+}
+
+// nullableBool is a request field that can be left out, set to a boolean, or
+// set to null to clear it.
+type nullableBool struct {
+	Set   bool  // The field was present
+	Value *bool // nil for null
+}
+
+func (b *nullableBool) UnmarshalJSON(data []byte) error {
+	b.Set = true
+	if string(data) == "null" {
+		b.Value = nil
+		return nil
+	}
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	b.Value = &v
+	return nil
 }
 
 // episodeResponse is the API representation of an episode.
@@ -69,6 +202,10 @@ type episodeResponse struct {
 	Title     string     `json:"title"`
 	Status    string     `json:"status"`
 	AirDate   *time.Time `json:"air_date,omitempty"`
+
+	// Effective profile, inherited from the series unless overridden
+	QualityProfile   string `json:"quality_profile"`
+	ProfileInherited bool   `json:"quality_profile_inherited"`
 }
 
 // listEpisodesResponse is the response for GET /content/:id/episodes.
@@ -77,9 +214,50 @@ type listEpisodesResponse struct {
 	Total int               `json:"total"`
 }
 
+// seasonSummaryResponse summarizes one season of a series.
+type seasonSummaryResponse struct {
+	Season         int        `json:"season"`
+	EpisodeCount   int        `json:"episode_count"`
+	AvailableCount int        `json:"available_count"`
+	SizeBytes      int64      `json:"size_bytes"`
+	LastAirDate    *time.Time `json:"last_air_date,omitempty"`
+	Complete       bool       `json:"complete"` // Every episode is available
+}
+
+// listSeasonsResponse is the response for GET /content/:id/seasons.
+type listSeasonsResponse struct {
+	Items []seasonSummaryResponse `json:"items"`
+	Total int                     `json:"total"`
+}
+
+// monitorEpisodesRequest is the request body for PUT /content/:id/episodes/monitor.
+// Exactly one of Seasons, EpisodeIDs, or FutureOnly must be set.
+// With QualityProfile set, only the selected episodes' profile override changes
+// ("" restores the series profile) and monitoring is left alone.
+type monitorEpisodesRequest struct {
+	Seasons        []int   `json:"seasons,omitempty"`
+	EpisodeIDs     []int64 `json:"episode_ids,omitempty"`
+	FutureOnly     bool    `json:"future_only,omitempty"`
+	QualityProfile *string `json:"quality_profile,omitempty"`
+}
+
+// seasonChangeResponse reports how many episodes changed status in a season.
+type seasonChangeResponse struct {
+	Season  int `json:"season"`
+	Changed int `json:"changed"`
+}
+
+// monitorEpisodesResponse is the response for PUT /content/:id/episodes/monitor.
+type monitorEpisodesResponse struct {
+	ContentID int64                  `json:"content_id"`
+	Changed   int                    `json:"changed"`
+	Seasons   []seasonChangeResponse `json:"seasons"`
+}
+
 // updateEpisodeRequest is the request body for PUT /episodes/:id.
 type updateEpisodeRequest struct {
-	Status *string `json:"status,omitempty"`
+	Status         *string `json:"status,omitempty"`
+	QualityProfile *string `json:"quality_profile,omitempty"` // "" inherits the series profile
 }
 
 // releaseResponse is the API representation of a search result.
@@ -92,12 +270,81 @@ type releaseResponse struct {
 	PublishDate time.Time `json:"publish_date"`
 	Quality     string    `json:"quality,omitempty"`
 	Score       int       `json:"score"`
+	Protocol    string    `json:"protocol"`
+	Seeders     int       `json:"seeders,omitempty"` // Torrents only
+	Peers       int       `json:"peers,omitempty"`   // Torrents only
+	// ScoreBreakdown shows how Score was earned
+	ScoreBreakdown scoring.Breakdown `json:"score_breakdown"`
+}
+
+// grabDecisionResponse is the score decision behind an automatic grab.
+type grabDecisionResponse struct {
+	DownloadID  int64     `json:"download_id"`
+	ReleaseName string    `json:"release_name"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	Summary     string    `json:"summary"`
+	events.GrabDecision
+}
+
+// rejectedReleaseResponse is a release filtered out by the profile's keyword or language lists.
+type rejectedReleaseResponse struct {
+	Title     string   `json:"title"`
+	Indexer   string   `json:"indexer"`
+	Reason    string   `json:"reason"`
+	Languages []string `json:"languages,omitempty"` // Detected audio languages, for language rejections
 }
 
 // searchResponse is the response for POST /search.
 type searchResponse struct {
-	Releases []releaseResponse `json:"releases"`
-	Errors   []string          `json:"errors,omitempty"`
+	Releases   []releaseResponse         `json:"releases"`
+	Rejected   []rejectedReleaseResponse `json:"rejected"`
+	Deferred   []deferredReleaseResponse `json:"deferred,omitempty"` // Too new to grab automatically, best first
+	Errors     []searchErrorResponse     `json:"errors,omitempty"`
+	Cached     bool                      `json:"cached"`
+	AgeSeconds int64                     `json:"age_seconds,omitempty"` // Age of cached indexer results
+	// Deprecated: errors as plain strings, as before errors became objects;
+	// removed in the next release
+	ErrorMessages []string `json:"error_messages,omitempty"`
+}
+
+// deferredReleaseResponse is a usenet release younger than the minimum age.
+// It can still be grabbed by hand with POST /grab.
+type deferredReleaseResponse struct {
+	releaseResponse
+	DeferredUntil    time.Time `json:"deferred_until"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+}
+
+// searchErrorResponse is an indexer that failed or was skipped in a search.
+type searchErrorResponse struct {
+	Indexer           string `json:"indexer,omitempty"`
+	Code              int    `json:"code,omitempty"` // Newznab error code, or 429 for HTTP 429
+	Message           string `json:"message"`
+	RetryAfterSeconds int64  `json:"retry_after_seconds,omitempty"` // Until the indexer is searched again
+}
+
+// releasePreviewResponse is the response for GET /content/{id}/releases.
+type releasePreviewResponse struct {
+	ContentID int64  `json:"content_id"`
+	Query     string `json:"query"`
+	Profile   string `json:"profile"`
+	searchResponse
+}
+
+// grabPreviewRequest is the request body for POST /content/{id}/releases.
+type grabPreviewRequest struct {
+	GUID string `json:"guid"`
+}
+
+// pinReleaseRequest is the request body for POST /content/{id}/pin. A GUID
+// from the content's last release preview fills in the other fields.
+type pinReleaseRequest struct {
+	GUID        string `json:"guid"`
+	DownloadURL string `json:"download_url"`
+	Title       string `json:"title"`
+	Indexer     string `json:"indexer"`
+	Protocol    string `json:"protocol"`
+	Season      *int   `json:"season"` // Required for series
 }
 
 // grabRequest is the request body for POST /grab.
@@ -106,9 +353,45 @@ type grabRequest struct {
 	DownloadURL string `json:"download_url"`
 	Title       string `json:"title"`
 	Indexer     string `json:"indexer"`
+	GUID        string `json:"guid,omitempty"`       // Indexer release GUID (enables blocklisting)
 	EpisodeID   *int64 `json:"episode_id,omitempty"` // Deprecated: use Season/Episodes
 	Season      *int   `json:"season,omitempty"`     // Override: season number
 	Episodes    []int  `json:"episodes,omitempty"`   // Override: episode numbers
+	Protocol    string `json:"protocol,omitempty"`   // "usenet" or "torrent"; inferred from download_url if empty
+	// Torrent options; omitted uses the configured default
+	Sequential         *bool `json:"sequential,omitempty"`
+	FirstLastPiecePrio *bool `json:"first_last_piece_prio,omitempty"`
+
+	upload *uploadedFile // Set by POST /grab/upload; saved as DownloadURL when the grab is sent
+}
+
+// uploadedFile is a release file posted to POST /grab/upload.
+type uploadedFile struct {
+	name string
+	data []byte
+}
+
+// grabPlanResponse is the response for POST /grab?dry_run=true.
+// It describes what a grab would send to the download client.
+type grabPlanResponse struct {
+	DryRun           bool    `json:"dry_run"`
+	ContentID        int64   `json:"content_id"`
+	ContentType      string  `json:"content_type"`
+	ReleaseName      string  `json:"release_name"`
+	Indexer          string  `json:"indexer,omitempty"`
+	GUID             string  `json:"guid,omitempty"`
+	DownloadURL      string  `json:"download_url"`
+	Season           *int    `json:"season,omitempty"`
+	Episodes         []int   `json:"episodes,omitempty"`
+	EpisodeIDs       []int64 `json:"episode_ids,omitempty"`  // Existing episode records
+	NewEpisodes      []int   `json:"new_episodes,omitempty"` // Episodes a grab would create
+	IsCompleteSeason bool    `json:"is_complete_season"`
+	AirDate          string  `json:"air_date,omitempty"` // Daily releases: YYYY-MM-DD the episode aired
+	Protocol         string  `json:"protocol"`
+	Client           string  `json:"client,omitempty"`
+	Category         string  `json:"category,omitempty"`
+	ClientError      string  `json:"client_error,omitempty"` // Why no client would accept the release
+	Blocklisted      bool    `json:"blocklisted"`
 }
 
 // downloadResponse is the API representation of a download.
@@ -122,14 +405,66 @@ type downloadResponse struct {
 	ClientID         string     `json:"client_id"`
 	Status           string     `json:"status"`
 	ReleaseName      string     `json:"release_name"`
+	ReleaseGroup     string     `json:"release_group,omitempty"`
 	Indexer          string     `json:"indexer"`
+	GUID             string     `json:"guid,omitempty"`
+	Category         string     `json:"category,omitempty"`
+	LastError        string     `json:"last_error,omitempty"` // Why the download failed
 	AddedAt          time.Time  `json:"added_at"`
 	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	// Place in the import queue, from 1, while a completed download waits for an import worker
+	ImportPosition int `json:"import_position,omitempty"`
 	// Live status from download client (only present for active downloads)
 	Progress *float64 `json:"progress,omitempty"` // 0-100
 	Size     *int64   `json:"size,omitempty"`     // bytes
 	Speed    *int64   `json:"speed,omitempty"`    // bytes/sec
 	ETA      *string  `json:"eta,omitempty"`      // human readable
+	// ETA from the rate of progress over recent polls; steadier than the client's
+	SmoothedETA *string `json:"smoothed_eta,omitempty"`
+	// Per-episode import outcome of a season pack (GET /downloads/{id} only)
+	EpisodeResults []episodeResultResponse `json:"episode_results,omitempty"`
+	// Options applied by a torrent client; absent for clients without them
+	TorrentOptions *torrentOptionsResponse `json:"torrent_options,omitempty"`
+}
+
+// torrentOptionsResponse is a download's torrent options.
+type torrentOptionsResponse struct {
+	Sequential         bool `json:"sequential"`
+	FirstLastPiecePrio bool `json:"first_last_piece_prio"`
+}
+
+// torrentOptionsRequest is the request body for POST /downloads/{id}/options.
+// Omitted options keep their current value.
+type torrentOptionsRequest struct {
+	Sequential         *bool `json:"sequential"`
+	FirstLastPiecePrio *bool `json:"first_last_piece_prio"`
+}
+
+// downloadSampleResponse is a download's progress at one poll.
+type downloadSampleResponse struct {
+	At       time.Time `json:"at"`
+	Progress float64   `json:"progress"` // 0-100
+	Speed    int64     `json:"speed"`    // bytes/sec
+	Size     int64     `json:"size"`     // bytes
+}
+
+// downloadSamplesResponse is the response for GET /downloads/{id}/samples.
+type downloadSamplesResponse struct {
+	DownloadID  int64                    `json:"download_id"`
+	Samples     []downloadSampleResponse `json:"samples"` // Oldest first; empty once the download is done
+	SmoothedETA *string                  `json:"smoothed_eta,omitempty"`
+}
+
+// episodeResultResponse is the import outcome of one file of a season pack.
+type episodeResultResponse struct {
+	SourceFile string    `json:"source_file"`
+	EpisodeID  *int64    `json:"episode_id,omitempty"` // Absent if the file matched no episode
+	Season     int       `json:"season"`
+	Episode    int       `json:"episode"`
+	Success    bool      `json:"success"`
+	FilePath   string    `json:"file_path,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // listDownloadsResponse is the response for GET /downloads.
@@ -140,6 +475,68 @@ type listDownloadsResponse struct {
 	Offset int                `json:"offset"`
 }
 
+// blocklistResponse is the API representation of a blocklisted release.
+type blocklistResponse struct {
+	ContentID   int64     `json:"content_id"`
+	GUID        string    `json:"guid"`
+	ReleaseName string    `json:"release_name"`
+	Indexer     string    `json:"indexer"`
+	Reason      string    `json:"reason,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// listBlocklistResponse is the response for GET /content/{id}/blocklist.
+type listBlocklistResponse struct {
+	Items []blocklistResponse `json:"items"`
+	Total int                 `json:"total"`
+}
+
+// exclusionResponse is the API representation of an excluded external ID.
+type exclusionResponse struct {
+	ID         int64     `json:"id"`
+	Source     string    `json:"source"` // tmdb or tvdb
+	ExternalID int64     `json:"external_id"`
+	Title      string    `json:"title,omitempty"`
+	Year       int       `json:"year,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// listExclusionsResponse is the response for GET /exclusions.
+type listExclusionsResponse struct {
+	Items []exclusionResponse `json:"items"`
+	Total int                 `json:"total"`
+}
+
+// addExclusionRequest is the request body for POST /exclusions.
+type addExclusionRequest struct {
+	Source     string `json:"source"`
+	ExternalID int64  `json:"external_id"`
+	Title      string `json:"title,omitempty"`
+	Year       int    `json:"year,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// aliasResponse is the API representation of a content alias.
+type aliasResponse struct {
+	ID        int64     `json:"id"`
+	ContentID int64     `json:"content_id"`
+	Alias     string    `json:"alias"`
+	Source    string    `json:"source"` // "tvdb" or "manual"
+	AddedAt   time.Time `json:"added_at"`
+}
+
+// listAliasesResponse is the response for GET /content/{id}/aliases.
+type listAliasesResponse struct {
+	Items []aliasResponse `json:"items"`
+	Total int             `json:"total"`
+}
+
+// addAliasRequest is the request body for POST /content/{id}/aliases.
+type addAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
 // historyResponse is the API representation of a history entry.
 type historyResponse struct {
 	ID        int64     `json:"id"`
@@ -160,14 +557,48 @@ type listHistoryResponse struct {
 
 // fileResponse is the API representation of a file.
 type fileResponse struct {
-	ID        int64     `json:"id"`
-	ContentID int64     `json:"content_id"`
-	EpisodeID *int64    `json:"episode_id,omitempty"`
-	Path      string    `json:"path"`
-	SizeBytes int64     `json:"size_bytes"`
-	Quality   string    `json:"quality"`
-	Source    string    `json:"source"`
-	AddedAt   time.Time `json:"added_at"`
+	ID           int64      `json:"id"`
+	ContentID    int64      `json:"content_id"`
+	EpisodeID    *int64     `json:"episode_id,omitempty"`
+	Season       *int       `json:"season,omitempty"`  // Of the episode, to save looking it up
+	Episode      *int       `json:"episode,omitempty"` // First episode of a multi-episode file
+	Path         string     `json:"path"`
+	SizeBytes    int64      `json:"size_bytes"`
+	Quality      string     `json:"quality"`
+	Source       string     `json:"source"`
+	Checksum     string     `json:"checksum,omitempty"` // SHA-256 at import (omitted if not verified)
+	ReleaseGroup string     `json:"release_group,omitempty"`
+	AddedAt      time.Time  `json:"added_at"`
+	Missing      bool       `json:"missing"`              // Gone from disk, found by the background reconcile
+	MissingAt    *time.Time `json:"missing_at,omitempty"` // When it was found missing
+	Kind         string     `json:"kind"`                 // media or subtitle
+}
+
+// File verification outcomes.
+const (
+	fileVerifyOK       = "ok"
+	fileVerifyMismatch = "mismatch"
+	fileVerifyMissing  = "missing"
+)
+
+// syncEpisodesResponse is the response for POST /content/{id}/sync-episodes.
+type syncEpisodesResponse struct {
+	ContentID  int64 `json:"content_id"`
+	TVDBID     int64 `json:"tvdb_id"`
+	Total      int   `json:"total"`                 // Episodes listed by TVDB (specials excluded)
+	Inserted   int   `json:"inserted"`              // New episodes added as wanted
+	Updated    int   `json:"updated"`               // Existing episodes whose title or air date changed
+	Aliases    int   `json:"aliases"`               // TVDB aliases added
+	NewSeasons []int `json:"new_seasons,omitempty"` // Seasons the library did not have before
+}
+
+// verifyFileResponse is the response for POST /files/{id}/verify.
+type verifyFileResponse struct {
+	ID       int64  `json:"id"`
+	Path     string `json:"path"`
+	Status   string `json:"status"`           // ok, mismatch, or missing
+	Expected string `json:"expected"`         // Checksum recorded at import
+	Actual   string `json:"actual,omitempty"` // Checksum now (empty if missing)
 }
 
 // listFilesResponse is the response for GET /files.
@@ -180,19 +611,47 @@ type listFilesResponse struct {
 
 // statusResponse is the response for GET /status.
 type statusResponse struct {
-	Status  string `json:"status"`
-	Version string `json:"version,omitempty"`
+	Status     string              `json:"status"`
+	Version    string              `json:"version,omitempty"`
+	SpeedLimit *speedLimitResponse `json:"speed_limit,omitempty"` // Nil without a download client
+}
+
+// speedLimitResponse is the download speed limit currently applied.
+type speedLimitResponse struct {
+	Limit     int64      `json:"limit"`   // Bytes per second; 0 = unlimited
+	Display   string     `json:"display"` // e.g. "5.0 MB/s" or "unlimited"
+	Source    string     `json:"source"`  // none, schedule, or override
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// setSpeedLimitRequest is the request body for PUT /downloads/speed-limit.
+type setSpeedLimitRequest struct {
+	Limit    string `json:"limit"`    // e.g. "5MB", "500K", "unlimited"
+	Duration string `json:"duration"` // How long the override lasts, e.g. "2h"
 }
 
 // profileResponse is the API representation of a quality profile.
 type profileResponse struct {
-	Name   string   `json:"name"`
-	Accept []string `json:"accept"`
+	ID        int64                 `json:"id"`
+	Name      string                `json:"name"`
+	Accept    []string              `json:"accept"`           // Accepted resolutions
+	Cutoff    string                `json:"cutoff,omitempty"` // Best accepted resolution
+	Settings  config.QualityProfile `json:"settings"`
+	CreatedAt *time.Time            `json:"created_at,omitempty"` // Unset for profiles from config
+	UpdatedAt *time.Time            `json:"updated_at,omitempty"`
 }
 
 // listProfilesResponse is the response for GET /profiles.
 type listProfilesResponse struct {
 	Profiles []profileResponse `json:"profiles"`
+	ReadOnly bool              `json:"read_only"` // Profiles come from config and can't be edited
+}
+
+// profileRequest is the request body for POST /profiles and PUT /profiles/{name}.
+// Settings use the same keys as [quality.profiles.NAME] in config.toml.
+type profileRequest struct {
+	Name     string                `json:"name"` // POST only; profiles can't be renamed
+	Settings config.QualityProfile `json:"settings"`
 }
 
 // plexStatusResponse is the response for GET /plex/status.
@@ -223,21 +682,79 @@ type importRequest struct {
 	Path    string `json:"path,omitempty"`
 	Title   string `json:"title,omitempty"`
 	Year    int    `json:"year,omitempty"`
-	Type    string `json:"type,omitempty"`    // "movie" or "series"
+	Type    string `json:"type,omitempty"`    // "movie", "series" or "audiobook"
 	Quality string `json:"quality,omitempty"` // "1080p", "2160p", etc.
 	Season  *int   `json:"season,omitempty"`  // For series
 	Episode *int   `json:"episode,omitempty"` // For series
+	Author  string `json:"author,omitempty"`  // For audiobooks added by the import
+	// AllowDowngrade imports over a higher-quality file already in the library
+	AllowDowngrade bool `json:"allow_downgrade,omitempty"`
 }
 
 // importResponse is the response for POST /import.
 type importResponse struct {
 	FileID       int64  `json:"file_id,omitempty"`
 	ContentID    int64  `json:"content_id"`
+	EpisodeID    *int64 `json:"episode_id,omitempty"` // Single episode imports
 	SourcePath   string `json:"source_path"`
 	DestPath     string `json:"dest_path,omitempty"`
 	SizeBytes    int64  `json:"size_bytes"`
 	PlexNotified bool   `json:"plex_notified"`
-	EpisodeCount int    `json:"episode_count,omitempty"` // For season pack imports
+	// The Plex scan waits to be sent with those of other imports
+	PlexScheduled bool `json:"plex_scheduled,omitempty"`
+	EpisodeCount  int  `json:"episode_count,omitempty"` // For season pack imports
+	// Season pack imports: the download status after the import and each episode's outcome
+	Status         string                       `json:"status,omitempty"`
+	EpisodeResults []events.EpisodeImportResult `json:"episode_results,omitempty"`
+}
+
+// plexMissingResponse is the response for GET /plex/missing.
+type plexMissingResponse struct {
+	Sections  []plexMissingSectionResponse `json:"sections"`
+	Missing   []plexMissingFile            `json:"missing"`   // Files arrgo has that Plex does not list
+	Untracked []plexUntrackedItem          `json:"untracked"` // Plex items arrgo has no file of
+	Summary   struct {
+		Files     int `json:"files"` // Library files checked
+		Missing   int `json:"missing"`
+		Untracked int `json:"untracked"`
+	} `json:"summary"`
+}
+
+// plexMissingSectionResponse is a Plex section compared with the library.
+type plexMissingSectionResponse struct {
+	Key       string   `json:"key"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Locations []string `json:"locations"` // Local paths of its folders
+	Files     int      `json:"files"`     // Library files under its folders
+	Missing   int      `json:"missing"`
+	Error     string   `json:"error,omitempty"` // The section could not be listed
+}
+
+// plexMissingFile is a library file Plex does not list.
+type plexMissingFile struct {
+	FileID    int64    `json:"file_id"`
+	ContentID int64    `json:"content_id"`
+	EpisodeID *int64   `json:"episode_id,omitempty"`
+	Title     string   `json:"title"`
+	Year      int      `json:"year"`
+	Path      string   `json:"path"`
+	Section   string   `json:"section,omitempty"` // Empty if no Plex library holds its folder
+	Mode      string   `json:"mode,omitempty"`    // Permission bits, e.g. "-rw-r-----"
+	Owner     string   `json:"owner,omitempty"`   // uid:gid
+	Error     string   `json:"error,omitempty"`   // Why the file could not be read
+	Fixes     []string `json:"fixes"`             // Suggested fixes, most likely first
+}
+
+// plexUntrackedItem is a Plex item arrgo has no file of.
+type plexUntrackedItem struct {
+	Section   string `json:"section"`
+	RatingKey string `json:"rating_key"`
+	Title     string `json:"title"`
+	Year      int    `json:"year"`
+	Type      string `json:"type"`
+	Path      string `json:"path"` // Local path of its file, or of its folder for a show
+	Fix       string `json:"fix"`
 }
 
 // plexScanRequest is the request body for POST /plex/scan.
@@ -268,6 +785,55 @@ type plexListResponse struct {
 	Total   int                `json:"total"`
 }
 
+// pathMapping is a Plex path prefix and the local prefix it maps to.
+type pathMapping struct {
+	Remote string `json:"remote"` // Path prefix as seen by Plex
+	Local  string `json:"local"`  // Corresponding local path prefix
+}
+
+// pathMappingsResponse is the response for GET /plex/path-mappings.
+type pathMappingsResponse struct {
+	Mappings []pathMapping `json:"mappings"`
+}
+
+// pathMappingValidateRequest is the request body for POST /plex/path-mappings/validate.
+type pathMappingValidateRequest struct {
+	Sample    int      `json:"sample,omitempty"`    // Items sampled per library (default: 20)
+	Libraries []string `json:"libraries,omitempty"` // Empty = all libraries
+}
+
+// pathMappingValidateResponse is the response for POST /plex/path-mappings/validate.
+type pathMappingValidateResponse struct {
+	Sampled   int                 `json:"sampled"`
+	Resolved  int                 `json:"resolved"`
+	Libraries []pathSampleLibrary `json:"libraries"`
+	Mappings  []pathMappingCheck  `json:"mappings"`
+}
+
+// pathSampleLibrary is the number of paths sampled from one Plex library.
+type pathSampleLibrary struct {
+	Name    string `json:"name"`
+	Sampled int    `json:"sampled"`
+	Error   string `json:"error,omitempty"`
+}
+
+// pathMappingCheck counts sampled paths under one mapping. Remote and
+// Local are empty for paths that no mapping covers.
+type pathMappingCheck struct {
+	Remote  string            `json:"remote"`
+	Local   string            `json:"local"`
+	Hits    int               `json:"hits"`
+	Misses  int               `json:"misses"`
+	Failing []pathFailingPath `json:"failing,omitempty"` // Up to 5 examples
+}
+
+// pathFailingPath is a sampled Plex path that does not exist locally.
+type pathFailingPath struct {
+	PlexPath  string `json:"plex_path"`
+	LocalPath string `json:"local_path"`
+	Error     string `json:"error"`
+}
+
 // plexSearchResponse is the response for GET /plex/search.
 type plexSearchResponse struct {
 	Query string             `json:"query"`
@@ -297,6 +863,24 @@ type libraryCheckResponse struct {
 	Total      int                `json:"total"`
 	Healthy    int                `json:"healthy"`
 	WithIssues int                `json:"with_issues"`
+	PlexError  string             `json:"plex_error,omitempty"` // Plex was not checked
+}
+
+// libraryCheckJobResponse is a background library check and the items it
+// has checked so far.
+type libraryCheckJobResponse struct {
+	ID         int64              `json:"id"`
+	Status     string             `json:"status"` // running, completed, failed
+	Filter     string             `json:"filter,omitempty"`
+	Total      int                `json:"total"`
+	Checked    int                `json:"checked"`
+	Healthy    int                `json:"healthy"`
+	WithIssues int                `json:"with_issues"`
+	Error      string             `json:"error,omitempty"`
+	PlexError  string             `json:"plex_error,omitempty"` // Plex was not checked
+	StartedAt  string             `json:"started_at"`
+	FinishedAt string             `json:"finished_at,omitempty"`
+	Items      []libraryCheckItem `json:"items"`
 }
 
 // EventResponse represents an event in API responses.
@@ -306,6 +890,11 @@ type EventResponse struct {
 	EntityType string `json:"entity_type"`
 	EntityID   int64  `json:"entity_id"`
 	OccurredAt string `json:"occurred_at"`
+	Title      string `json:"title,omitempty"` // Entity description; only with enrich=true
+	// ProcessedAt is when the event was delivered; outbox events not yet
+	// delivered are Pending instead.
+	ProcessedAt string `json:"processed_at,omitempty"`
+	Pending     bool   `json:"pending,omitempty"`
 }
 
 // listEventsResponse is the response for GET /events.
@@ -316,19 +905,100 @@ type listEventsResponse struct {
 	Offset int             `json:"offset"`
 }
 
+// auditResponse is one state-changing API request in GET /audit.
+type auditResponse struct {
+	ID         int64             `json:"id"`
+	OccurredAt string            `json:"occurred_at"`
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Route      string            `json:"route,omitempty"`
+	Params     map[string]string `json:"params,omitempty"`
+	Query      map[string]string `json:"query,omitempty"`
+	Body       map[string]any    `json:"body,omitempty"` // IDs and flags only
+	Remote     string            `json:"remote"`
+	KeyPrint   string            `json:"key_fingerprint,omitempty"`
+	KeyName    string            `json:"key_name,omitempty"`
+	Status     int               `json:"status"`
+	DurationMS int64             `json:"duration_ms"`
+}
+
+// listAuditResponse is the response for GET /audit.
+type listAuditResponse struct {
+	Items  []auditResponse `json:"items"`
+	Total  int             `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+// orphanResponse is one entry of the download root in GET /downloads/orphans.
+type orphanResponse struct {
+	Path       string `json:"path"`
+	SizeBytes  int64  `json:"size_bytes"`
+	ModifiedAt string `json:"modified_at"` // Newest change anywhere in the entry
+}
+
+// listOrphansResponse is the response for GET /downloads/orphans.
+type listOrphansResponse struct {
+	Items      []orphanResponse `json:"items"`
+	TotalBytes int64            `json:"total_bytes"`
+	MinAgeDays int              `json:"min_age_days"`
+}
+
+// cleanOrphansRequest is the request for POST /downloads/orphans/clean.
+type cleanOrphansRequest struct {
+	Paths []string `json:"paths,omitempty"` // Orphans to remove; all if omitted
+}
+
+// cleanOrphansResponse is the response for POST /downloads/orphans/clean.
+type cleanOrphansResponse struct {
+	Removed    []string `json:"removed"`
+	Skipped    []string `json:"skipped"` // Not orphans, or failed to remove
+	FreedBytes int64    `json:"freed_bytes"`
+}
+
 // retryResponse is the response for POST /downloads/{id}/retry.
 type retryResponse struct {
-	ReleaseName string `json:"release_name"`
-	Message     string `json:"message"`
+	ReleaseName   string `json:"release_name"` // The first grab's release
+	Message       string `json:"message"`
+	PreviousError string `json:"previous_error,omitempty"` // Why the retried download failed
+	// Strategy applied: same, season_pack or episodes (auto resolves to one of the latter)
+	Strategy string              `json:"strategy"`
+	Grabs    []retryGrabResponse `json:"grabs"`              // Every grab queued
+	Warnings []string            `json:"warnings,omitempty"` // Episodes searched without a grab
+}
+
+// retryGrabResponse is a grab queued by a retry.
+type retryGrabResponse struct {
+	ReleaseName string  `json:"release_name"`
+	Indexer     string  `json:"indexer"`
+	Score       int     `json:"score"`
+	Season      *int    `json:"season,omitempty"`
+	EpisodeIDs  []int64 `json:"episode_ids,omitempty"` // Episodes strategy: the episode grabbed for
 }
 
 // indexerResponse is the API representation of an indexer's status.
 type indexerResponse struct {
-	Name       string `json:"name"`
-	URL        string `json:"url"`
-	Status     string `json:"status,omitempty"`
-	Error      string `json:"error,omitempty"`
-	ResponseMs int64  `json:"response_ms,omitempty"`
+	Name       string               `json:"name"`
+	URL        string               `json:"url"`
+	Priority   int                  `json:"priority"`
+	Categories []string             `json:"categories"` // Empty means all content types
+	Status     string               `json:"status,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	ResponseMs int64                `json:"response_ms,omitempty"`
+	Keys       []indexerKeyResponse `json:"keys"` // Today's usage of each API key
+}
+
+// indexerKeyResponse is the usage of one indexer API key today. Limits are
+// as last reported by the indexer and omitted if it never reported them.
+type indexerKeyResponse struct {
+	Key         string `json:"key"` // Masked to its last 4 characters
+	Requests    int    `json:"requests"`
+	Grabs       int    `json:"grabs"`
+	Exhausted   bool   `json:"exhausted"`
+	APICurrent  int    `json:"api_current,omitempty"`
+	APIMax      int    `json:"api_max,omitempty"`
+	GrabCurrent int    `json:"grab_current,omitempty"`
+	GrabMax     int    `json:"grab_max,omitempty"`
 }
 
 // listIndexersResponse is the response for GET /indexers.
@@ -336,13 +1006,21 @@ type listIndexersResponse struct {
 	Indexers []indexerResponse `json:"indexers"`
 }
 
+// ClientConnection reports the state of one configured download client.
+type ClientConnection struct {
+	Name      string `json:"name"`
+	Protocol  string `json:"protocol"` // usenet or torrent
+	Connected bool   `json:"connected"`
+	Error     string `json:"error,omitempty"`
+}
+
 // DashboardResponse is the response for GET /dashboard with aggregated stats.
 type DashboardResponse struct {
 	Version     string `json:"version"`
 	Connections struct {
-		Server  bool `json:"server"`
-		Plex    bool `json:"plex"`
-		SABnzbd bool `json:"sabnzbd"`
+		Server  bool               `json:"server"`
+		Plex    bool               `json:"plex"`
+		Clients []ClientConnection `json:"clients"` // One per configured download client
 	} `json:"connections"`
 	Downloads struct {
 		Queued      int `json:"queued"`
@@ -352,23 +1030,96 @@ type DashboardResponse struct {
 		Imported    int `json:"imported"`
 		Cleaned     int `json:"cleaned"`
 		Failed      int `json:"failed"`
+		// Season packs with episodes that failed to import
+		PartiallyImported int `json:"partially_imported"`
+		// Completed downloads waiting for an import worker
+		ImportQueued int `json:"import_queued"`
 	} `json:"downloads"`
 	Stuck struct {
-		Count     int   `json:"count"`
+		Count int `json:"count"`
+		// Shortest of the per-status thresholds
 		Threshold int64 `json:"threshold_minutes"`
+		// Stuck downloads per status; statuses without any are absent
+		ByStatus map[string]int `json:"by_status"`
+		// Threshold of each status, in minutes
+		Thresholds map[string]int64 `json:"thresholds_minutes"`
 	} `json:"stuck"`
 	Library struct {
-		Movies int `json:"movies"`
-		Series int `json:"series"`
+		Movies    int   `json:"movies"`
+		Series    int   `json:"series"`
+		SizeBytes int64 `json:"size_bytes"` // Total size of the library's files
 	} `json:"library"`
+	Recent   []DashboardImport  `json:"recent"`   // Latest imports, newest first
+	Upcoming []DashboardEpisode `json:"upcoming"` // Next episodes to air, soonest first
+	Problems []DashboardProblem `json:"problems"` // Empty when every check passes
+	// PlexChecked is false if Plex isn't configured or skip_plex was requested
+	PlexChecked bool `json:"plex_checked"`
+}
+
+// DashboardImport is a recently imported movie or episode.
+type DashboardImport struct {
+	ContentID  int64     `json:"content_id"`
+	EpisodeID  *int64    `json:"episode_id,omitempty"`
+	Title      string    `json:"title"` // "Title (Year)" or "Title S01E02"
+	Quality    string    `json:"quality,omitempty"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// DashboardEpisode is an episode of a monitored series that has yet to air.
+type DashboardEpisode struct {
+	ContentID    int64     `json:"content_id"`
+	EpisodeID    int64     `json:"episode_id"`
+	Series       string    `json:"series"`
+	Season       int       `json:"season"`
+	Episode      int       `json:"episode"`
+	EpisodeTitle string    `json:"episode_title,omitempty"`
+	AirDate      time.Time `json:"air_date"`
+}
+
+// DashboardProblem counts the items failing one of the dashboard's checks,
+// a cheap summary of what GET /verify reports item by item.
+type DashboardProblem struct {
+	Check   string `json:"check"` // e.g. "failed_downloads", "not_in_plex"
+	Count   int    `json:"count"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"` // Command that shows or resolves the problem
+}
+
+// storageGroupResponse is the size of one group of library files.
+type storageGroupResponse struct {
+	Name         string `json:"name"` // Content type or quality
+	FileCount    int    `json:"file_count"`
+	SizeBytes    int64  `json:"size_bytes"`
+	AverageBytes int64  `json:"average_size_bytes"`
+}
+
+// largestContentResponse is a content item in the library stats' largest listing.
+type largestContentResponse struct {
+	ID         int64  `json:"id"`
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Year       int    `json:"year"`
+	SizeOnDisk int64  `json:"size_on_disk"`
+}
+
+// libraryStatsResponse is the response for GET /library/stats.
+type libraryStatsResponse struct {
+	FileCount    int                      `json:"file_count"`
+	SizeBytes    int64                    `json:"size_bytes"`
+	AverageBytes int64                    `json:"average_size_bytes"`
+	ByType       []storageGroupResponse   `json:"by_type"`
+	ByQuality    []storageGroupResponse   `json:"by_quality"`
+	Largest      []largestContentResponse `json:"largest"`
 }
 
 // libraryImportRequest is the request body for POST /library/import.
 type libraryImportRequest struct {
-	Source          string `json:"source"`                     // "plex"
-	Library         string `json:"library"`                    // Plex library name
-	QualityOverride string `json:"quality_override,omitempty"` // Override parsed quality
-	DryRun          bool   `json:"dry_run,omitempty"`
+	Source          string            `json:"source"`                     // "plex", "filesystem", "radarr" or "sonarr"
+	Library         string            `json:"library,omitempty"`          // Plex library name (plex)
+	Path            string            `json:"path,omitempty"`             // Directory to scan (filesystem) or database file (radarr, sonarr)
+	QualityMap      map[string]string `json:"quality_map,omitempty"`      // Radarr or Sonarr quality profile ID or name to profile (radarr, sonarr)
+	QualityOverride string            `json:"quality_override,omitempty"` // Override parsed quality
+	DryRun          bool              `json:"dry_run,omitempty"`
 }
 
 // libraryImportItem represents a single imported or skipped item.
@@ -378,18 +1129,140 @@ type libraryImportItem struct {
 	Type      string `json:"type"`
 	Quality   string `json:"quality,omitempty"`
 	ContentID int64  `json:"content_id,omitempty"`
-	Reason    string `json:"reason,omitempty"` // for skipped items
-	Error     string `json:"error,omitempty"`  // for errored items
+	Path      string `json:"path,omitempty"`     // Scanned folder or file (filesystem)
+	Episodes  int    `json:"episodes,omitempty"` // Episode files found (filesystem series)
+	Reason    string `json:"reason,omitempty"`   // for skipped and needs-review items
+	Error     string `json:"error,omitempty"`    // for errored items
+}
+
+// libraryRenameRequest is the request body for POST /library/rename.
+type libraryRenameRequest struct {
+	ContentID int64 `json:"content_id"`
+	DryRun    bool  `json:"dry_run,omitempty"`
+}
+
+// libraryRenameFile is the outcome for a single file.
+type libraryRenameFile struct {
+	FileID    int64  `json:"file_id"`
+	EpisodeID *int64 `json:"episode_id,omitempty"`
+	Kind      string `json:"kind"` // media or subtitle
+	OldPath   string `json:"old_path"`
+	NewPath   string `json:"new_path,omitempty"`
+	Status    string `json:"status"`          // unchanged, pending, renamed, conflict, failed
+	Error     string `json:"error,omitempty"` // for conflict and failed
+}
+
+// libraryRenameResponse is the response for POST /library/rename.
+type libraryRenameResponse struct {
+	ContentID    int64               `json:"content_id"`
+	DryRun       bool                `json:"dry_run"`
+	Files        []libraryRenameFile `json:"files"`
+	PlexNotified bool                `json:"plex_notified"`
+	Summary      struct {
+		Renamed   int `json:"renamed"` // would be renamed when dry_run
+		Unchanged int `json:"unchanged"`
+		Failed    int `json:"failed"` // conflicts and failures
+	} `json:"summary"`
 }
 
 // libraryImportResponse is the response for POST /library/import.
 type libraryImportResponse struct {
-	Imported []libraryImportItem `json:"imported"`
-	Skipped  []libraryImportItem `json:"skipped"`
-	Errors   []libraryImportItem `json:"errors"`
-	Summary  struct {
-		Imported int `json:"imported"`
-		Skipped  int `json:"skipped"`
-		Errors   int `json:"errors"`
+	Imported    []libraryImportItem `json:"imported"`
+	Skipped     []libraryImportItem `json:"skipped"`
+	Errors      []libraryImportItem `json:"errors"`
+	NeedsReview []libraryImportItem `json:"needs_review"` // Could not be identified reliably (filesystem)
+	Summary     struct {
+		Imported    int `json:"imported"`
+		Skipped     int `json:"skipped"`
+		Errors      int `json:"errors"`
+		NeedsReview int `json:"needs_review"`
 	} `json:"summary"`
 }
+
+// publicSummaryResponse is the response for GET /api/v1/public/summary.
+// It is served without authentication, so it must never carry file paths,
+// URLs, or indexer names.
+type publicSummaryResponse struct {
+	RecentlyAdded []publicImportResponse  `json:"recently_added"`
+	Downloads     publicDownloadsResponse `json:"downloads"`
+	Library       publicLibraryResponse   `json:"library"`
+}
+
+// publicImportResponse is a recently imported title.
+type publicImportResponse struct {
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Year    int    `json:"year,omitempty"`
+	Quality string `json:"quality,omitempty"`
+	Season  int    `json:"season,omitempty"`
+	Episode int    `json:"episode,omitempty"`
+}
+
+// publicDownloadsResponse summarizes queued and downloading downloads.
+type publicDownloadsResponse struct {
+	Active   int     `json:"active"`
+	Progress float64 `json:"progress"` // Combined progress, 0-100
+}
+
+// publicLibraryResponse holds library totals.
+type publicLibraryResponse struct {
+	Movies   int `json:"movies"`
+	Series   int `json:"series"`
+	Episodes int `json:"episodes"` // Available episodes
+}
+
+// snapshotImportResponse is the response for POST /import/snapshot.
+type snapshotImportResponse struct {
+	Mode    string               `json:"mode"` // "skip-existing" or "overwrite"
+	Total   int                  `json:"total"`
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Skipped int                  `json:"skipped"`
+	Failed  int                  `json:"failed"`
+	Items   []snapshotItemResult `json:"items"`
+}
+
+// snapshotItemResult is the outcome of restoring one snapshot item.
+type snapshotItemResult struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Year      int    `json:"year"`
+	ContentID int64  `json:"content_id,omitempty"`
+	Result    string `json:"result"` // created, updated, skipped, or failed
+	Error     string `json:"error,omitempty"`
+}
+
+// collectionResponse is the response for GET /content/{id}/collection: the
+// TMDB collection a movie belongs to and which of its movies are tracked.
+type collectionResponse struct {
+	ID        int64              `json:"id"`   // TMDB collection ID
+	Name      string             `json:"name"` // e.g. "The Matrix Collection"
+	ContentID int64              `json:"content_id"`
+	Members   []collectionMember `json:"members"` // By year; unreleased movies last
+	Untracked int                `json:"untracked"`
+}
+
+// collectionMember is a movie of a TMDB collection.
+type collectionMember struct {
+	TMDBID      int64  `json:"tmdb_id"`
+	Title       string `json:"title"`
+	Year        int    `json:"year,omitempty"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	Tracked     bool   `json:"tracked"`
+	ContentID   int64  `json:"content_id,omitempty"` // Library ID if tracked or added
+	Reason      string `json:"reason,omitempty"`     // for skipped members
+}
+
+// collectionAddRequest is the optional request body for
+// POST /content/{id}/collection/add-missing.
+type collectionAddRequest struct {
+	TMDBIDs []int64 `json:"tmdb_ids,omitempty"` // Only these members; empty adds every untracked one
+}
+
+// collectionAddResponse is the response for POST /content/{id}/collection/add-missing.
+type collectionAddResponse struct {
+	ID      int64              `json:"id"`
+	Name    string             `json:"name"`
+	Added   []collectionMember `json:"added"`
+	Skipped []collectionMember `json:"skipped"`
+}
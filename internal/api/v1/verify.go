@@ -2,14 +2,44 @@ package v1
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/importer"
+	"github.com/vmunix/arrgo/internal/library"
 )
 
+// Fix actions reported in AppliedFix.Action.
+const (
+	fixMarkCompleted = "mark_completed" // Client finished the download; arrgo still had it downloading
+	fixMarkFailed    = "mark_failed"    // Download is gone from the client
+	fixReimport      = "reimport"       // Imported file is missing; import again from the download
+)
+
+// AppliedFix describes a reconciliation applied by GET /verify?fix=true.
+type AppliedFix struct {
+	DownloadID int64  `json:"download_id"`
+	Action     string `json:"action"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+}
+
+// verifyFix is the reconciliation that resolves a problem, if one is safe to apply.
+type verifyFix struct {
+	action     string
+	to         download.Status
+	reason     string
+	sourcePath string  // For completed: where the download's files are
+	staleFiles []int64 // For reimport: library file records whose files are missing
+}
+
 // VerifyProblem describes a problem found during verification.
 type VerifyProblem struct {
 	DownloadID int64    `json:"download_id"`
@@ -20,21 +50,31 @@ type VerifyProblem struct {
 	Checks     []string `json:"checks"`
 	Likely     string   `json:"likely_cause"`
 	Fixes      []string `json:"suggested_fixes"`
+
+	fix *verifyFix // nil if the problem can't be fixed automatically
 }
 
 // VerifyResponse is the response for GET /verify.
 type VerifyResponse struct {
 	Connections struct {
-		Plex    bool   `json:"plex"`
-		PlexErr string `json:"plex_error,omitempty"`
-		SABnzbd bool   `json:"sabnzbd"`
-		SABErr  string `json:"sabnzbd_error,omitempty"`
+		Plex    bool               `json:"plex"`
+		PlexErr string             `json:"plex_error,omitempty"`
+		Clients []ClientConnection `json:"clients"` // One per configured download client
 	} `json:"connections"`
-	Checked  int             `json:"checked"`
-	Passed   int             `json:"passed"`
-	Problems []VerifyProblem `json:"problems"`
+	Checked      int             `json:"checked"`
+	Passed       int             `json:"passed"`
+	Problems     []VerifyProblem `json:"problems"`
+	DryRun       bool            `json:"dry_run"`       // True unless fix=true was requested
+	AppliedFixes []AppliedFix    `json:"applied_fixes"` // Reconciliations applied in fix mode
 }
 
+// verify handles GET /api/v1/verify.
+// With fix=true, safe reconciliations are applied: downloads the client reports
+// completed are marked completed, downloads missing from the client for longer
+// than the downloading stuck threshold are marked failed, and with reimport=true imported
+// downloads whose library file is missing are reset to completed and imported again.
+// Each applied fix is published as a DownloadReconciled event.
+
 func (s *Server) verify(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -49,8 +89,14 @@ func (s *Server) verify(w http.ResponseWriter, r *http.Request) {
 		}
 		filterID = &id
 	}
+	fix := r.URL.Query().Get("fix") == queryTrue
+	reimport := r.URL.Query().Get("reimport") == queryTrue
+	if fix && s.deps.Bus == nil {
+		writeError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "fix mode requires the event bus")
+		return
+	}
 
-	resp := VerifyResponse{}
+	resp := VerifyResponse{DryRun: !fix, AppliedFixes: []AppliedFix{}}
 
 	// Test connections
 	if s.deps.Plex != nil {
@@ -60,11 +106,19 @@ func (s *Server) verify(w http.ResponseWriter, r *http.Request) {
 			resp.Connections.PlexErr = err.Error()
 		}
 	}
+	resp.Connections.Clients = []ClientConnection{}
 	if s.deps.Manager != nil {
-		_, err := s.deps.Manager.Client().List(ctx)
-		resp.Connections.SABnzbd = err == nil
-		if err != nil {
-			resp.Connections.SABErr = err.Error()
+		for _, info := range s.deps.Manager.Clients() {
+			conn := ClientConnection{Name: string(info.Name), Protocol: string(info.Protocol)}
+			client, err := s.deps.Manager.ClientFor(info.Name)
+			if err == nil {
+				_, err = client.List(ctx)
+			}
+			conn.Connected = err == nil
+			if err != nil {
+				conn.Error = err.Error()
+			}
+			resp.Connections.Clients = append(resp.Connections.Clients, conn)
 		}
 	}
 
@@ -99,11 +153,19 @@ func (s *Server) verify(w http.ResponseWriter, r *http.Request) {
 
 	for _, dl := range downloads {
 		problem := s.verifyDownload(ctx, dl)
-		if problem != nil {
-			resp.Problems = append(resp.Problems, *problem)
-		} else {
+		if problem == nil {
 			resp.Passed++
+			continue
+		}
+		if fix && problem.fix != nil && (problem.fix.action != fixReimport || reimport) {
+			applied, err := s.applyFix(ctx, dl, problem.fix)
+			if err != nil {
+				problem.Checks = append(problem.Checks, "Fix "+problem.fix.action+": "+err.Error())
+			} else {
+				resp.AppliedFixes = append(resp.AppliedFixes, *applied)
+			}
 		}
+		resp.Problems = append(resp.Problems, *problem)
 	}
 
 	writeJSON(w, http.StatusOK, resp)
@@ -120,30 +182,56 @@ func (s *Server) verifyDownload(ctx context.Context, dl *download.Download) *Ver
 	if !dl.LastTransitionAt.IsZero() {
 		since = time.Since(dl.LastTransitionAt).Round(time.Minute).String()
 	}
+	threshold, hasThreshold := s.stuckThresholds()[dl.Status]
+	stuck := hasThreshold && !dl.LastTransitionAt.IsZero() && time.Since(dl.LastTransitionAt) > threshold
 
 	switch dl.Status {
 	case download.StatusDownloading:
-		// Check if actually in SABnzbd
+		// Check if actually in the download client
 		if s.deps.Manager != nil {
-			status, err := s.deps.Manager.Client().Status(ctx, dl.ClientID)
+			status, err := s.deps.Manager.Status(ctx, dl)
 			if err != nil || status == nil {
-				return &VerifyProblem{
+				problem := &VerifyProblem{
 					DownloadID: dl.ID,
 					Status:     string(dl.Status),
 					Title:      title,
 					Since:      since,
-					Issue:      "Not found in SABnzbd queue",
-					Checks:     []string{"SABnzbd queue: not found"},
-					Likely:     "Download was canceled or SABnzbd cleared it",
+					Issue:      "Not found in " + string(dl.Client) + " queue",
+					Checks:     []string{string(dl.Client) + " queue: not found"},
+					Likely:     "Download was canceled or " + string(dl.Client) + " cleared it",
 					Fixes:      []string{"arrgo retry " + strconv.FormatInt(dl.ID, 10), "arrgo skip " + strconv.FormatInt(dl.ID, 10)},
 				}
+				// Only a definite "not found" is safe to act on; the client may just be unreachable
+				if (err == nil || errors.Is(err, download.ErrDownloadNotFound)) && stuck {
+					problem.fix = &verifyFix{action: fixMarkFailed, to: download.StatusFailed, reason: "download missing from " + string(dl.Client)}
+				}
+				return problem
+			}
+			if status.Status == download.StatusCompleted {
+				problem := &VerifyProblem{
+					DownloadID: dl.ID,
+					Status:     string(dl.Status),
+					Title:      title,
+					Since:      since,
+					Issue:      "Completed in " + string(dl.Client) + " but still downloading in arrgo",
+					Checks:     []string{string(dl.Client) + " status: completed"},
+					Likely:     "Completion was missed while arrgo was stopped or the client was unreachable",
+					Fixes:      []string{"arrgo status --verify --fix"},
+				}
+				problem.fix = &verifyFix{
+					action:     fixMarkCompleted,
+					to:         download.StatusCompleted,
+					reason:     "completed in " + string(dl.Client),
+					sourcePath: s.verifySourcePath(dl, status),
+				}
+				return problem
 			}
 		}
 
 	case download.StatusCompleted:
 		// Check if source file exists
 		if s.deps.Manager != nil {
-			status, _ := s.deps.Manager.Client().Status(ctx, dl.ClientID)
+			status, _ := s.deps.Manager.Status(ctx, dl)
 			if status != nil && status.Path != "" {
 				if _, err := os.Stat(status.Path); os.IsNotExist(err) {
 					return &VerifyProblem{
@@ -161,21 +249,55 @@ func (s *Server) verifyDownload(ctx context.Context, dl *download.Download) *Ver
 		}
 
 	case download.StatusImported:
-		// Check if in Plex
-		if s.deps.Plex != nil && content != nil {
-			found, _ := s.deps.Plex.HasMovie(ctx, content.Title, content.Year)
-			if !found {
-				return &VerifyProblem{
-					DownloadID: dl.ID,
-					Status:     string(dl.Status),
-					Title:      title,
-					Since:      since,
-					Issue:      "Not found in Plex library",
-					Checks:     []string{"Plex search for '" + content.Title + "': not found"},
-					Likely:     "Plex hasn't scanned yet",
-					Fixes:      []string{"arrgo plex scan", "Wait for automatic scan"},
+		// Check that the imported files are still on disk
+		if missing, err := s.missingLibraryFiles(dl); err == nil && len(missing) > 0 {
+			problem := &VerifyProblem{
+				DownloadID: dl.ID,
+				Status:     string(dl.Status),
+				Title:      title,
+				Since:      since,
+				Issue:      "Imported file missing from library",
+				Likely:     "File was deleted or moved after import",
+				Fixes:      []string{"arrgo status --verify --fix --reimport"},
+			}
+			staleFiles := make([]int64, 0, len(missing))
+			for _, f := range missing {
+				problem.Checks = append(problem.Checks, "File at "+f.Path+": missing")
+				staleFiles = append(staleFiles, f.ID)
+			}
+			// Re-import needs the downloaded files, which remain until cleanup
+			var status *download.ClientStatus
+			if s.deps.Manager != nil {
+				status, _ = s.deps.Manager.Status(ctx, dl)
+			}
+			if source := s.verifySourcePath(dl, status); source != "" {
+				if _, err := os.Stat(source); err == nil {
+					problem.fix = &verifyFix{
+						action:     fixReimport,
+						to:         download.StatusCompleted,
+						reason:     "imported file missing",
+						sourcePath: source,
+						staleFiles: staleFiles,
+					}
+				} else {
+					problem.Checks = append(problem.Checks, "Source at "+source+": missing")
 				}
 			}
+			return problem
+		}
+
+		// Check if in Plex
+		if s.deps.Plex != nil && content != nil && !s.plexHasContent(ctx, content) {
+			return &VerifyProblem{
+				DownloadID: dl.ID,
+				Status:     string(dl.Status),
+				Title:      title,
+				Since:      since,
+				Issue:      "Not found in Plex library",
+				Checks:     []string{"Plex search for '" + content.Title + "': not found"},
+				Likely:     "Plex hasn't scanned yet",
+				Fixes:      []string{"arrgo plex scan", "Wait for automatic scan"},
+			}
 		}
 
 	case download.StatusFailed:
@@ -192,5 +314,154 @@ func (s *Server) verifyDownload(ctx context.Context, dl *download.Download) *Ver
 		}
 	}
 
+	if stuck {
+		return &VerifyProblem{
+			DownloadID: dl.ID,
+			Status:     string(dl.Status),
+			Title:      title,
+			Since:      since,
+			Issue:      "Stuck in " + string(dl.Status),
+			Checks:     []string{fmt.Sprintf("Time in %s: %s (threshold %s)", dl.Status, since, threshold)},
+			Likely:     stuckCause(dl.Status),
+			Fixes:      []string{"arrgo downloads retry " + strconv.FormatInt(dl.ID, 10), "arrgo downloads cancel " + strconv.FormatInt(dl.ID, 10)},
+		}
+	}
+
 	return nil
 }
+
+// stuckCause is the likely cause of a download stuck in status.
+func stuckCause(status download.Status) string {
+	switch status {
+	case download.StatusQueued:
+		return "The download client's queue is paused or full"
+	case download.StatusDownloading:
+		return "The release has no seeders or is missing articles"
+	default:
+		return "The import failed without being recorded, or arrgo was stopped mid-import"
+	}
+}
+
+// plexHasContent reports whether Plex has the content. The item of a stored
+// rating key is fetched directly; without one, or if Plex no longer has that
+// item, Plex is searched for its title or one of its aliases and the key of
+// the match is stored for next time.
+func (s *Server) plexHasContent(ctx context.Context, content *library.Content) bool {
+	if content.PlexKey != "" {
+		_, err := s.deps.Plex.GetItem(ctx, content.PlexKey)
+		if err == nil {
+			return true
+		}
+		if !errors.Is(err, importer.ErrPlexItemNotFound) {
+			return false
+		}
+	}
+
+	titles, err := s.deps.Library.Titles(content)
+	if err != nil {
+		titles = []string{content.Title}
+	}
+	for _, title := range titles {
+		item, _ := s.deps.Plex.FindMovieItem(ctx, title, content.Year)
+		if item == nil {
+			continue
+		}
+		// Storing the key is best effort; the next check searches again
+		if item.RatingKey != content.PlexKey {
+			_ = s.deps.Library.SetPlexKey(content.ID, item.RatingKey)
+		}
+		return true
+	}
+	if content.PlexKey != "" {
+		_ = s.deps.Library.SetPlexKey(content.ID, "")
+	}
+	return false
+}
+
+// verifySourcePath returns where a download's files are: under the configured
+// download root if set, otherwise the path reported by the client.
+// Returns "" if neither is known.
+func (s *Server) verifySourcePath(dl *download.Download, status *download.ClientStatus) string {
+	if s.cfg.DownloadRoot != "" {
+		return download.SourcePath(s.cfg.DownloadRoot, dl)
+	}
+	if status != nil {
+		return status.Path
+	}
+	return ""
+}
+
+// missingLibraryFiles returns the library files imported for a download that no longer exist on disk.
+// For episode downloads only the download's episodes are checked.
+func (s *Server) missingLibraryFiles(dl *download.Download) ([]*library.File, error) {
+	files, _, err := s.deps.Library.ListFiles(library.FileFilter{ContentID: &dl.ContentID})
+	if err != nil {
+		return nil, err
+	}
+	return s.missingDownloadFiles(dl, files), nil
+}
+
+// missingDownloadFiles returns the files among files (those of the download's
+// content) that were imported for the download and no longer exist on disk.
+func (s *Server) missingDownloadFiles(dl *download.Download, files []*library.File) []*library.File {
+	episodes := make(map[int64]bool, len(dl.EpisodeIDs))
+	for _, id := range dl.EpisodeIDs {
+		episodes[id] = true
+	}
+
+	var missing []*library.File
+	for _, f := range files {
+		if len(episodes) > 0 && (f.EpisodeID == nil || !episodes[*f.EpisodeID]) {
+			continue
+		}
+		if s.fileMissing(f) {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
+
+// applyFix transitions a download as described by fix and publishes a
+// DownloadReconciled event, followed by the event that drives the pipeline on:
+// DownloadCompleted to import, or DownloadFailed.
+func (s *Server) applyFix(ctx context.Context, dl *download.Download, fix *verifyFix) (*AppliedFix, error) {
+	from := dl.Status
+	for _, id := range fix.staleFiles {
+		if err := s.deps.Library.DeleteFile(id); err != nil {
+			return nil, fmt.Errorf("delete file record %d: %w", id, err)
+		}
+	}
+	if err := s.deps.Downloads.Transition(dl, fix.to); err != nil {
+		return nil, err
+	}
+
+	_ = s.deps.Bus.Publish(ctx, &events.DownloadReconciled{
+		BaseEvent:  events.NewBaseEvent(events.EventDownloadReconciled, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		FromStatus: string(from),
+		ToStatus:   string(fix.to),
+		Reason:     fix.reason,
+	})
+	switch fix.to {
+	case download.StatusCompleted:
+		_ = s.deps.Bus.Publish(ctx, &events.DownloadCompleted{
+			BaseEvent:  events.NewBaseEvent(events.EventDownloadCompleted, events.EntityDownload, dl.ID),
+			DownloadID: dl.ID,
+			SourcePath: fix.sourcePath,
+		})
+	case download.StatusFailed:
+		_ = s.deps.Bus.Publish(ctx, &events.DownloadFailed{
+			BaseEvent:  events.NewBaseEvent(events.EventDownloadFailed, events.EntityDownload, dl.ID),
+			DownloadID: dl.ID,
+			Reason:     fix.reason,
+		})
+	}
+
+	return &AppliedFix{
+		DownloadID: dl.ID,
+		Action:     fix.action,
+		FromStatus: string(from),
+		ToStatus:   string(fix.to),
+		Reason:     fix.reason,
+	}, nil
+}
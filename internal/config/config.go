@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
 )
 
 // Config is the root configuration structure.
@@ -17,7 +21,10 @@ type Config struct {
 	Libraries     LibrariesConfig     `toml:"libraries"`
 	Quality       QualityConfig       `toml:"quality"`
 	Indexers      IndexersConfig      `toml:"indexers"`
+	Search        SearchConfig        `toml:"search"`
 	Downloaders   DownloadersConfig   `toml:"downloaders"`
+	Downloads     DownloadsConfig     `toml:"downloads"`
+	Bandwidth     BandwidthConfig     `toml:"bandwidth"`
 	Notifications NotificationsConfig `toml:"notifications"`
 	Overseerr     OverseerrConfig     `toml:"overseerr"`
 	Compat        CompatConfig        `toml:"compat"`
@@ -28,38 +35,159 @@ type Config struct {
 }
 
 type ServerConfig struct {
-	Host     string `toml:"host"`
-	Port     int    `toml:"port"`
-	LogLevel string `toml:"log_level"`
+	Host                 string        `toml:"host"`
+	Port                 int           `toml:"port"`
+	LogLevel             string        `toml:"log_level"`
+	SlowRequestThreshold time.Duration `toml:"slow_request_threshold"` // Requests slower than this log at WARN (default: 2s)
+	PublicStatus         bool          `toml:"public_status"`          // Serve GET /api/v1/public/summary without authentication
+	EventOutbox          *bool         `toml:"event_outbox"`           // Persist handler events before delivery so they survive restarts (default: true)
+	// Named keys for the native API; without any, it is served unauthenticated
+	APIKeys map[string]*APIKeyConfig `toml:"api_keys"`
+}
+
+// API key scopes for [server.api_keys.<name>].
+const (
+	APIKeyScopeRead  = "read"  // GET requests only
+	APIKeyScopeWrite = "write" // Everything but deleting files, pruning, renaming and canceling downloads
+	APIKeyScopeAdmin = "admin" // Everything
+)
+
+// APIKeyConfig configures a named API key.
+type APIKeyConfig struct {
+	Scope  string   `toml:"scope"`  // read, write or admin
+	Values []string `toml:"values"` // One value, or two while rotating to a new one
+}
+
+// ShouldUseEventOutbox returns whether events that handlers act on are
+// delivered through the event log outbox. Defaults to true if not explicitly configured.
+func (c *ServerConfig) ShouldUseEventOutbox() bool {
+	if c.EventOutbox == nil {
+		return true // default
+	}
+	return *c.EventOutbox
 }
 
 type DatabaseConfig struct {
-	Path string `toml:"path"`
+	Path        string `toml:"path"`
+	AutoMigrate *bool  `toml:"auto_migrate"` // Apply pending schema migrations at startup (default: true)
+}
+
+// ShouldAutoMigrate returns whether arrgod applies pending migrations at startup.
+// Defaults to true if not explicitly configured.
+func (c *DatabaseConfig) ShouldAutoMigrate() bool {
+	if c.AutoMigrate == nil {
+		return true // default
+	}
+	return *c.AutoMigrate
 }
 
 type LibrariesConfig struct {
-	Movies LibraryConfig `toml:"movies"`
-	Series LibraryConfig `toml:"series"`
+	Movies     LibraryConfig          `toml:"movies"`
+	Series     LibraryConfig          `toml:"series"`
+	Audiobooks AudiobookLibraryConfig `toml:"audiobooks"`
 }
 
 type LibraryConfig struct {
-	Root   string `toml:"root"`
-	Naming string `toml:"naming"`
+	Root         string   `toml:"root"`
+	Roots        []string `toml:"roots"`         // Additional root folders for new content
+	RootStrategy string   `toml:"root_strategy"` // first_with_space (default), most_free_space, or round_robin
+	Naming       string   `toml:"naming"`
+}
+
+// AudiobookLibraryConfig is the audiobook library. Audiobooks can only be
+// added while it is enabled, so setups without it see no change.
+type AudiobookLibraryConfig struct {
+	LibraryConfig
+	Enabled bool `toml:"enabled"`
+}
+
+// Paths returns root followed by roots, skipping empty and duplicate entries.
+func (l *LibraryConfig) Paths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, p := range append([]string{l.Root}, l.Roots...) {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths
 }
 
 type QualityConfig struct {
-	Default  string                    `toml:"default"`
-	Profiles map[string]QualityProfile `toml:"profiles"`
+	Default    string                    `toml:"default"`
+	MinSeeders int                       `toml:"min_seeders"` // Minimum seeders for torrent releases (0 = no minimum)
+	Profiles   map[string]QualityProfile `toml:"profiles"`
+
+	// LegacyProfiles serves Profiles read-only from config instead of the
+	// database, which they otherwise only seed on first run.
+	LegacyProfiles bool `toml:"legacy_profiles"`
 }
 
+// QualityProfile defines which releases a profile accepts and how it ranks
+// them. Profiles are stored as JSON in the database, using the same keys as
+// the TOML.
 type QualityProfile struct {
-	Resolution  []string `toml:"resolution"`
-	Sources     []string `toml:"sources"`
-	Codecs      []string `toml:"codecs"`
-	HDR         []string `toml:"hdr"`
-	Audio       []string `toml:"audio"`
-	PreferRemux bool     `toml:"prefer_remux"`
-	Reject      []string `toml:"reject"`
+	Resolution  []string `toml:"resolution" json:"resolution,omitempty"`
+	Sources     []string `toml:"sources" json:"sources,omitempty"`
+	Codecs      []string `toml:"codecs" json:"codecs,omitempty"`
+	HDR         []string `toml:"hdr" json:"hdr,omitempty"`
+	Audio       []string `toml:"audio" json:"audio,omitempty"`
+	PreferRemux bool     `toml:"prefer_remux" json:"prefer_remux,omitempty"`
+	Reject      []string `toml:"reject" json:"reject,omitempty"`
+
+	// Keyword lists matched case-insensitively against whole words of the release title
+	Required  []string           `toml:"required" json:"required,omitempty"`   // Release must contain every keyword
+	Preferred []PreferredKeyword `toml:"preferred" json:"preferred,omitempty"` // Score bonus per matching keyword
+	Forbidden []string           `toml:"forbidden" json:"forbidden,omitempty"` // Release must contain none of these
+
+	// Audio languages, most preferred first. Releases carrying none of them are
+	// rejected; empty accepts any language. Unlabeled releases count as english.
+	Languages        []string `toml:"languages" json:"languages,omitempty"`
+	ExcludeLanguages []string `toml:"exclude_languages" json:"exclude_languages,omitempty"` // Don't count toward a release; one left with no language is rejected
+	MultiLanguages   []string `toml:"multi_languages" json:"multi_languages,omitempty"`     // Languages a MULTi release is assumed to carry (default: english, french)
+
+	// Release groups matched case-insensitively. PreferredGroups are tiers,
+	// best first: each tier scores less than the one before it.
+	PreferredGroups [][]string `toml:"preferred_groups" json:"preferred_groups,omitempty"`
+	BannedGroups    []string   `toml:"banned_groups" json:"banned_groups,omitempty"` // Releases by these groups are rejected
+
+	MinSeeders int `toml:"min_seeders" json:"min_seeders,omitempty"` // Overrides quality.min_seeders when set
+
+	// PreferredProtocol ("usenet" or "torrent") wins ties between equally scored
+	// releases; empty has no preference
+	PreferredProtocol string `toml:"preferred_protocol" json:"preferred_protocol,omitempty"`
+
+	// Release size bounds in MB (0 = no bound); season packs are measured per episode
+	MinSizeMB int `toml:"min_size_mb" json:"min_size_mb,omitempty"`
+	MaxSizeMB int `toml:"max_size_mb" json:"max_size_mb,omitempty"`
+
+	// Cutoff is the quality at which content is done: an accepted resolution,
+	// optionally followed by one of sources ("1080p webdl"). Setting it also
+	// searches for content whose file is below it. Empty means the best
+	// accepted resolution, and content with a file is not searched again.
+	Cutoff string `toml:"cutoff" json:"cutoff,omitempty"`
+}
+
+// CutoffQuality returns the profile's cutoff: Cutoff if set, else the best
+// resolution it accepts, or "" if it accepts none that rank.
+func (p QualityProfile) CutoffQuality() string {
+	if cutoff := strings.TrimSpace(p.Cutoff); cutoff != "" {
+		return strings.ToLower(cutoff)
+	}
+	cutoff := ""
+	for _, res := range p.Resolution {
+		if library.QualityRank(res) > library.QualityRank(cutoff) {
+			cutoff = res
+		}
+	}
+	return cutoff
+}
+
+// PreferredKeyword is a release title keyword that adds Weight to the score when present.
+type PreferredKeyword struct {
+	Keyword string `toml:"keyword" json:"keyword"`
+	Weight  int    `toml:"weight" json:"weight"`
 }
 
 // IndexersConfig is a map of indexer name to config.
@@ -67,28 +195,145 @@ type QualityProfile struct {
 type IndexersConfig map[string]*NewznabConfig
 
 type NewznabConfig struct {
-	URL    string `toml:"url"`
-	APIKey string `toml:"api_key"`
+	URL        string   `toml:"url"`
+	APIKey     string   `toml:"api_key"`
+	APIKeys    []string `toml:"api_keys"`    // More keys, rotated to when the indexer reports a key's daily limit reached
+	Priority   int      `toml:"priority"`    // Lower is preferred on score ties (1-50, default: 25)
+	Categories []string `toml:"categories"`  // Content types to search: "movie", "series", "audiobook" (default: all)
+	DailyLimit int      `toml:"daily_limit"` // API calls allowed per UTC day; the indexer is skipped beyond it (0 = unlimited)
+
+	MinimumAgeMinutes int `toml:"minimum_age_minutes"` // Overrides search.minimum_age_minutes when set
+}
+
+// SearchConfig controls caching of indexer results.
+type SearchConfig struct {
+	CacheTTL        time.Duration `toml:"cache_ttl"`         // How long indexer results are reused (default: 15m; negative disables the cache)
+	CacheMaxEntries int           `toml:"cache_max_entries"` // Least recently used results are evicted beyond this (default: 500)
+	CacheFile       string        `toml:"cache_file"`        // Keep cached results and indexer usage across restarts (optional)
+
+	// Fraction of a season's episodes that must be missing for a season search
+	// to look for season packs; below it the missing episodes are searched one
+	// by one (default: 0.5; negative always searches packs)
+	SeasonPackMinMissing float64 `toml:"season_pack_min_missing"`
+
+	// Usenet releases younger than this are not grabbed automatically; new
+	// posts are often incomplete or taken down (0 = no minimum)
+	MinimumAgeMinutes int `toml:"minimum_age_minutes"`
+
+	// Recent sync: the indexers' newest TV releases are checked for wanted
+	// episodes aired within the window and grabbed at once
+	RecentInterval time.Duration `toml:"recent_interval"` // Time between syncs (default: 15m; negative disables)
+	RecentWindow   time.Duration `toml:"recent_window"`   // How far back episodes count as recent (default: 72h)
 }
 
 type DownloadersConfig struct {
-	SABnzbd     *SABnzbdConfig     `toml:"sabnzbd"`
-	QBittorrent *QBittorrentConfig `toml:"qbittorrent"`
+	SABnzbd     *SABnzbdConfig                   `toml:"sabnzbd"`
+	QBittorrent *QBittorrentConfig               `toml:"qbittorrent"`
+	Clients     map[string]*DownloadClientConfig `toml:"clients"` // Named clients for multi-client setups
+	Retry       RetryConfig                      `toml:"retry"`
+	// Alternate releases tried when a client rejects a grab (default: 3; negative disables)
+	GrabFallbacks int `toml:"grab_fallbacks"`
+	// How long live download statuses are reused between API requests (default: 3s; negative disables)
+	StatusCacheTTL time.Duration `toml:"status_cache_ttl"`
+	// Progress samples kept per active download for speed charts and smoothed ETAs (default: 120; negative disables)
+	ProgressSamples int `toml:"progress_samples"`
+}
+
+// DownloadsConfig tunes how arrgo tracks downloads.
+// Maps are keyed by status: queued, downloading, completed, importing.
+// Statuses left out use the defaults.
+type DownloadsConfig struct {
+	// How long a download may stay in a status before it's reported as stuck
+	// (default: queued 2h, downloading 6h, completed 30m, importing 30m)
+	StuckThresholds map[string]time.Duration `toml:"stuck_thresholds"`
+	// How long before a download.stuck event is emitted; must exceed the stuck threshold
+	// (default: queued 12h, downloading 24h, completed 2h, importing 2h)
+	StuckAlertThresholds map[string]time.Duration `toml:"stuck_alert_thresholds"`
+	// Name globs of leftovers that don't keep a release directory from being
+	// removed after import or cancel; a matching directory goes as a whole
+	// (default: *.nfo, *.sfv, *.srr, *.srs, *.par2, *.nzb, *.url, sample, proof; empty disables)
+	JunkPatterns []string `toml:"junk_patterns"`
+	// .txt files up to this size are junk too (default: 64; negative: none are)
+	JunkTextMaxKB int `toml:"junk_text_max_kb"`
+	// Days an entry of the download root must be unchanged to be listed as an orphan (default: 7)
+	OrphanMinAgeDays int `toml:"orphan_min_age_days"`
+}
+
+// OrphanMinAge returns how long an unreferenced download root entry must be
+// unchanged before it's listed as an orphan.
+func (c DownloadsConfig) OrphanMinAge() time.Duration {
+	days := c.OrphanMinAgeDays
+	if days <= 0 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Thresholds returns the stuck and alert thresholds per status, with the
+// configured values over the defaults.
+func (c DownloadsConfig) Thresholds() (stuck, alert map[download.Status]time.Duration) {
+	stuck = download.DefaultStuckThresholds()
+	for status, d := range c.StuckThresholds {
+		stuck[download.Status(status)] = d
+	}
+	alert = download.DefaultStuckAlertThresholds()
+	for status, d := range c.StuckAlertThresholds {
+		alert[download.Status(status)] = d
+	}
+	return stuck, alert
+}
+
+// RetryConfig controls retries of transient download client errors.
+// Zero values use the defaults.
+type RetryConfig struct {
+	MaxAttempts  int           `toml:"max_attempts"`  // Total attempts per call; 1 disables retries (default: 4)
+	InitialDelay time.Duration `toml:"initial_delay"` // Delay before the first retry, doubled after each (default: 2s)
+	MaxDelay     time.Duration `toml:"max_delay"`     // Upper bound on the delay between attempts (default: 30s)
+}
+
+// Download client types for [downloaders.clients.<name>].
+const (
+	ClientTypeSABnzbd = "sabnzbd"
+)
+
+// DownloadClientConfig configures a named download client.
+// Type selects the client implementation; the remaining keys are that client's settings.
+type DownloadClientConfig struct {
+	Type string `toml:"type"` // "sabnzbd"
+	SABnzbdConfig
+}
+
+// All returns every configured download client by name: the [downloaders.clients.<name>]
+// sections plus the legacy [downloaders.sabnzbd] section, named "sabnzbd".
+func (d *DownloadersConfig) All() map[string]*DownloadClientConfig {
+	clients := make(map[string]*DownloadClientConfig, len(d.Clients)+1)
+	if d.SABnzbd != nil {
+		clients[ClientTypeSABnzbd] = &DownloadClientConfig{Type: ClientTypeSABnzbd, SABnzbdConfig: *d.SABnzbd}
+	}
+	for name, c := range d.Clients {
+		clients[name] = c
+	}
+	return clients
 }
 
 type SABnzbdConfig struct {
-	URL          string        `toml:"url"`
-	APIKey       string        `toml:"api_key"`
-	Category     string        `toml:"category"`
-	RemotePath   string        `toml:"remote_path"`   // Path prefix as seen by SABnzbd (e.g., /data/usenet)
-	LocalPath    string        `toml:"local_path"`    // Corresponding path on this machine (e.g., /srv/data/usenet)
-	PollInterval time.Duration `toml:"poll_interval"` // How often to poll for status (default: 5s)
+	URL            string        `toml:"url"`
+	APIKey         string        `toml:"api_key"`
+	Category       string        `toml:"category"`
+	MovieCategory  string        `toml:"movie_category"`  // Category for movie grabs (default: category)
+	SeriesCategory string        `toml:"series_category"` // Category for series grabs (default: category)
+	RemotePath     string        `toml:"remote_path"`     // Path prefix as seen by SABnzbd (e.g., /data/usenet)
+	LocalPath      string        `toml:"local_path"`      // Corresponding path on this machine (e.g., /srv/data/usenet)
+	PollInterval   time.Duration `toml:"poll_interval"`   // How often to poll for status (default: 5s)
 }
 
 type QBittorrentConfig struct {
 	URL      string `toml:"url"`
 	Username string `toml:"username"`
 	Password string `toml:"password"`
+	// Defaults for grabs that don't choose; POST /api/v1/downloads/{id}/options changes them per download
+	SequentialDownload bool `toml:"sequential_download"` // Download pieces in order, to play while downloading
+	FirstLastPiecePrio bool `toml:"first_last_piece_prio"`
 }
 
 type NotificationsConfig struct {
@@ -102,6 +347,22 @@ type PlexConfig struct {
 	RemotePath   string        `toml:"remote_path"`   // Path prefix as seen by Plex (e.g., /data/media)
 	LocalPath    string        `toml:"local_path"`    // Corresponding path on this machine (e.g., /srv/data/media)
 	PollInterval time.Duration `toml:"poll_interval"` // How often to poll for library updates (default: 60s)
+	// Scans after imports are held back and sent together, at most once per
+	// library section per window
+	ScanWindow   time.Duration `toml:"scan_window"`    // Default: 30s; negative sends each scan at once
+	ScanMaxPaths int           `toml:"scan_max_paths"` // Directories scanned one by one; more refresh the whole section (default: 5)
+	// Movies added that Plex already has are marked available with Plex's file instead of searched for (default: true)
+	AdoptExisting *bool `toml:"adopt_existing"`
+}
+
+// ShouldAdoptExisting returns whether adding a movie Plex already has adopts
+// Plex's file instead of searching for a new download.
+// Defaults to true if not explicitly configured.
+func (c *PlexConfig) ShouldAdoptExisting() bool {
+	if c.AdoptExisting == nil {
+		return true // default
+	}
+	return *c.AdoptExisting
 }
 
 type OverseerrConfig struct {
@@ -134,8 +395,44 @@ type AnthropicConfig struct {
 	Model  string `toml:"model"`
 }
 
+// BandwidthConfig limits download client speed by time of day.
+type BandwidthConfig struct {
+	Schedule []BandwidthWindowConfig `toml:"schedule"`
+}
+
+// BandwidthWindowConfig is one schedule entry, e.g. from="08:00", to="23:00", limit="5MB".
+// Windows may span midnight (from="22:00", to="06:00"). The first matching window wins.
+type BandwidthWindowConfig struct {
+	From  string `toml:"from"`
+	To    string `toml:"to"`
+	Limit string `toml:"limit"`
+}
+
 type ImporterConfig struct {
-	CleanupSource *bool `toml:"cleanup_source"`
+	CleanupSource *bool         `toml:"cleanup_source"`
+	WatchDir      string        `toml:"watch_dir"`      // Drop folder for manual imports (optional)
+	WatchInterval time.Duration `toml:"watch_interval"` // How often to scan watch_dir (default: 1m)
+	AutoAdd       bool          `toml:"auto_add"`       // Add unmatched titles from watch_dir to the library
+	// Hooks are executables run with ARRGO_* environment variables describing the event
+	PostImportHook string        `toml:"post_import_hook"` // Run after each imported file; failures are recorded, not fatal
+	PreCleanupHook string        `toml:"pre_cleanup_hook"` // Run before source cleanup; a non-zero exit blocks it
+	HookTimeout    time.Duration `toml:"hook_timeout"`     // Kill hooks that run longer (default: 5m)
+	VerifyChecksum bool          `toml:"verify_checksum"`  // Checksum each copy against its source before source cleanup
+	MinPartRatio   float64       `toml:"min_part_ratio"`   // Movie videos smaller than this fraction of the largest are skipped (default: 0.3)
+
+	// Subtitle sidecars of imported videos are copied beside them, named with
+	// their language (movie.en.srt) for media servers to pick up
+	Subtitles         *bool    `toml:"subtitles"`          // Default: true
+	SubtitleLanguages []string `toml:"subtitle_languages"` // Languages to copy (en, eng, English); empty copies all
+
+	Concurrency           int `toml:"concurrency"`             // Completed downloads imported at once; the rest wait in order (default: 2)
+	SeasonPackConcurrency int `toml:"season_pack_concurrency"` // Episodes of a season pack copied at once, per import (default: 3)
+
+	// Library files deleted outside arrgo are noticed by checking a batch of
+	// files on disk at each interval
+	ReconcileInterval time.Duration `toml:"reconcile_interval"` // Time between batches (default: 1m; negative disables)
+	ReconcileBatch    int           `toml:"reconcile_batch"`    // Files checked per batch (default: 100)
+	ResearchMissing   bool          `toml:"research_missing"`   // Search again for movies and episodes wanted after their file went missing
 }
 
 type TMDBConfig struct {
@@ -155,26 +452,48 @@ func (c *ImporterConfig) ShouldCleanupSource() bool {
 	return *c.CleanupSource
 }
 
+// ShouldImportSubtitles returns whether to copy subtitle sidecars on import.
+// Defaults to true if not explicitly configured.
+func (c *ImporterConfig) ShouldImportSubtitles() bool {
+	if c.Subtitles == nil {
+		return true
+	}
+	return *c.Subtitles
+}
+
 // Load reads, parses, and validates the configuration file.
+// Warnings do not fail the load; use Check to see them.
 func Load(path string) (*Config, error) {
-	cfg, missing, err := load(path)
+	cfg, configErr, err := Check(path)
 	if err != nil {
 		return nil, err
 	}
-
-	// Build Error if any issues
-	configErr := &Error{Path: path, Missing: missing}
-
-	// Run validation
-	configErr.Errors = cfg.Validate()
-
 	if configErr.HasErrors() {
 		return nil, configErr
 	}
-
 	return cfg, nil
 }
 
+// Check reads and parses the configuration file and reports every problem
+// found: unresolved environment variables, unknown keys, and validation
+// errors and warnings, each with the line it refers to where possible.
+// err is only set when the file cannot be read or parsed; the returned
+// *Error is never nil and may hold warnings even when HasErrors is false.
+func Check(path string) (*Config, *Error, error) {
+	cfg, src, err := load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configErr := &Error{Path: path, Missing: src.missing}
+	configErr.add(unknownKeys(src.meta, src.lines)...)
+	for _, issue := range cfg.Validate() {
+		issue.Line = src.lines.lookup(issue.Key)
+		configErr.add(issue)
+	}
+	return cfg, configErr, nil
+}
+
 // LoadWithoutValidation reads and parses the config without validation.
 // Useful for init commands or debugging.
 func LoadWithoutValidation(path string) (*Config, error) {
@@ -182,8 +501,15 @@ func LoadWithoutValidation(path string) (*Config, error) {
 	return cfg, err
 }
 
-// load is the internal loader that returns config, missing vars, and parse error.
-func load(path string) (*Config, []string, error) {
+// source describes the file a config was loaded from.
+type source struct {
+	missing []string      // Unresolved environment variables
+	meta    toml.MetaData // Decoder metadata, used to find unknown keys
+	lines   keyLines      // Line of each key in the file
+}
+
+// load is the internal loader that returns config, its source, and parse error.
+func load(path string) (*Config, *source, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, nil, fmt.Errorf("reading config: %w", err)
@@ -193,7 +519,8 @@ func load(path string) (*Config, []string, error) {
 	content, missing := substituteEnvVars(string(data))
 
 	var cfg Config
-	if _, err := toml.Decode(content, &cfg); err != nil {
+	md, err := toml.Decode(content, &cfg)
+	if err != nil {
 		return nil, nil, fmt.Errorf("parsing config: %w", err)
 	}
 
@@ -207,11 +534,20 @@ func load(path string) (*Config, []string, error) {
 	if cfg.Server.LogLevel == "" {
 		cfg.Server.LogLevel = "info"
 	}
+	if cfg.Server.SlowRequestThreshold == 0 {
+		cfg.Server.SlowRequestThreshold = 2 * time.Second
+	}
 	if cfg.Database.Path == "" {
 		cfg.Database.Path = "./data/arrgo.db"
 	}
+	// The first of roots is the primary root when root is not set
+	for _, lib := range []*LibraryConfig{&cfg.Libraries.Movies, &cfg.Libraries.Series, &cfg.Libraries.Audiobooks.LibraryConfig} {
+		if lib.Root == "" && len(lib.Roots) > 0 {
+			lib.Root = lib.Roots[0]
+		}
+	}
 
-	return &cfg, missing, nil
+	return &cfg, &source{missing: missing, meta: md, lines: indexKeyLines(string(data))}, nil
 }
 
 // substituteEnvVars replaces ${VAR}, ${VAR:-default}, ${VAR:?error} patterns.
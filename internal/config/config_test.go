@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -204,3 +205,125 @@ port = 8484
 	// Default should be true
 	assert.True(t, cfg.Importer.ShouldCleanupSource(), "CleanupSource should default to true")
 }
+
+func TestConfig_PlexAdoptExisting(t *testing.T) {
+	cfg, err := parseTestConfig(t, `
+[notifications.plex]
+url = "http://localhost:32400"
+token = "abc"
+`)
+	require.NoError(t, err)
+	assert.True(t, cfg.Notifications.Plex.ShouldAdoptExisting(), "AdoptExisting should default to true")
+
+	cfg, err = parseTestConfig(t, `
+[notifications.plex]
+url = "http://localhost:32400"
+token = "abc"
+adopt_existing = false
+`)
+	require.NoError(t, err)
+	assert.False(t, cfg.Notifications.Plex.ShouldAdoptExisting())
+}
+
+func TestConfig_DatabaseAutoMigrate(t *testing.T) {
+	cfg, err := parseTestConfig(t, `
+[server]
+port = 8484
+`)
+	require.NoError(t, err)
+	assert.True(t, cfg.Database.ShouldAutoMigrate(), "AutoMigrate should default to true")
+
+	cfg, err = parseTestConfig(t, `
+[database]
+auto_migrate = false
+`)
+	require.NoError(t, err)
+	assert.False(t, cfg.Database.ShouldAutoMigrate())
+}
+
+func TestConfig_EventOutbox(t *testing.T) {
+	cfg, err := parseTestConfig(t, `
+[server]
+port = 8484
+`)
+	require.NoError(t, err)
+	assert.True(t, cfg.Server.ShouldUseEventOutbox(), "EventOutbox should default to true")
+
+	cfg, err = parseTestConfig(t, `
+[server]
+event_outbox = false
+`)
+	require.NoError(t, err)
+	assert.False(t, cfg.Server.ShouldUseEventOutbox())
+}
+
+func TestConfig_SlowRequestThreshold(t *testing.T) {
+	cfg, err := parseTestConfig(t, `
+[server]
+port = 8484
+`)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, cfg.Server.SlowRequestThreshold, "should default to 2s")
+
+	cfg, err = parseTestConfig(t, `
+[server]
+slow_request_threshold = "500ms"
+`)
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, cfg.Server.SlowRequestThreshold)
+}
+
+func TestConfig_QualityProfileKeywords(t *testing.T) {
+	content := `
+[quality.profiles.hd]
+resolution = ["1080p"]
+required = ["x265"]
+forbidden = ["CAM", "HDTS"]
+preferred = [
+  { keyword = "Atmos", weight = 15 },
+  { keyword = "FLUX", weight = 10 },
+]
+`
+	cfg, err := parseTestConfig(t, content)
+	require.NoError(t, err)
+
+	hd := cfg.Quality.Profiles["hd"]
+	assert.Equal(t, []string{"x265"}, hd.Required)
+	assert.Equal(t, []string{"CAM", "HDTS"}, hd.Forbidden)
+	assert.Equal(t, []PreferredKeyword{{Keyword: "Atmos", Weight: 15}, {Keyword: "FLUX", Weight: 10}}, hd.Preferred)
+}
+
+func TestConfig_DownloadClients(t *testing.T) {
+	content := `
+[downloaders.sabnzbd]
+url = "http://localhost:8085"
+api_key = "legacy-key"
+category = "arrgo"
+
+[downloaders.clients.sab-4k]
+type = "sabnzbd"
+url = "http://nas:8080"
+api_key = "4k-key"
+movie_category = "movies-4k"
+poll_interval = "10s"
+`
+	cfg, err := parseTestConfig(t, content)
+	require.NoError(t, err)
+
+	all := cfg.Downloaders.All()
+	require.Len(t, all, 2)
+
+	legacy := all["sabnzbd"]
+	require.NotNil(t, legacy)
+	assert.Equal(t, ClientTypeSABnzbd, legacy.Type)
+	assert.Equal(t, "legacy-key", legacy.APIKey)
+	assert.Equal(t, "arrgo", legacy.Category)
+
+	named := all["sab-4k"]
+	require.NotNil(t, named)
+	assert.Equal(t, ClientTypeSABnzbd, named.Type)
+	assert.Equal(t, "http://nas:8080", named.URL)
+	assert.Equal(t, "4k-key", named.APIKey)
+	assert.Equal(t, "movies-4k", named.MovieCategory)
+	assert.Equal(t, 10*time.Second, named.PollInterval)
+}
@@ -6,11 +6,54 @@ import (
 	"strings"
 )
 
+// Severity classifies a configuration issue.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // Config cannot be used as-is
+	SeverityWarning Severity = "warning" // Config is usable but probably not what was intended
+)
+
+// Issue is a single configuration problem tied to a TOML key path.
+type Issue struct {
+	Key      string   // TOML key path, e.g. "indexers.nzbgeek.url"
+	Line     int      // 1-based line in the config file (0 if unknown)
+	Severity Severity // Error or warning
+	Message  string   // Human-readable description
+}
+
+func (i Issue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s (line %d): %s", i.Key, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Key, i.Message)
+}
+
+func errorf(key, format string, args ...any) Issue {
+	return Issue{Key: key, Severity: SeverityError, Message: fmt.Sprintf(format, args...)}
+}
+
+func warnf(key, format string, args ...any) Issue {
+	return Issue{Key: key, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)}
+}
+
 // Error aggregates configuration errors.
 type Error struct {
-	Path    string   // Config file path
-	Missing []string // Unresolved environment variables
-	Errors  []string // Validation errors
+	Path     string   // Config file path
+	Missing  []string // Unresolved environment variables
+	Errors   []Issue  // Validation errors
+	Warnings []Issue  // Non-fatal issues (unknown keys, missing directories)
+}
+
+// add files an issue under Errors or Warnings according to its severity.
+func (e *Error) add(issues ...Issue) {
+	for _, i := range issues {
+		if i.Severity == SeverityWarning {
+			e.Warnings = append(e.Warnings, i)
+		} else {
+			e.Errors = append(e.Errors, i)
+		}
+	}
 }
 
 func (e *Error) Error() string {
@@ -35,6 +78,7 @@ func (e *Error) Error() string {
 }
 
 // HasErrors returns true if there are any errors.
+// Warnings alone do not count.
 func (e *Error) HasErrors() bool {
 	return len(e.Missing) > 0 || len(e.Errors) > 0
 }
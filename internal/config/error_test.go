@@ -27,7 +27,7 @@ func TestError_Error_MissingVars(t *testing.T) {
 func TestError_Error_ValidationErrors(t *testing.T) {
 	e := &Error{
 		Path:   "/etc/arrgo/config.toml",
-		Errors: []string{"server.port: must be 1-65535", "quality.default: not defined"},
+		Errors: []Issue{errorf("server.port", "must be 1-65535"), errorf("quality.default", "not defined")},
 	}
 	got := e.Error()
 	assert.Contains(t, got, "validation failed")
@@ -38,9 +38,19 @@ func TestError_Error_Both(t *testing.T) {
 	e := &Error{
 		Path:    "/etc/arrgo/config.toml",
 		Missing: []string{"API_KEY"},
-		Errors:  []string{"server.port: invalid"},
+		Errors:  []Issue{errorf("server.port", "invalid")},
 	}
 	got := e.Error()
 	assert.Contains(t, got, "missing environment variables")
 	assert.Contains(t, got, "validation failed")
 }
+
+func TestError_WarningsOnly(t *testing.T) {
+	e := &Error{Path: "/etc/arrgo/config.toml"}
+	e.add(warnf("quality.profiles", "unused"), Issue{Key: "qualit_profile", Line: 3, Severity: SeverityWarning, Message: "unknown key (typo?)"})
+
+	assert.False(t, e.HasErrors(), "warnings alone are not errors")
+	assert.Empty(t, e.Error())
+	assert.Len(t, e.Warnings, 2)
+	assert.Equal(t, "qualit_profile (line 3): unknown key (typo?)", e.Warnings[1].String())
+}
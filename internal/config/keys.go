@@ -0,0 +1,118 @@
+// internal/config/keys.go
+package config
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+var (
+	tableHeaderPattern = regexp.MustCompile(`^\[\[?\s*([^\[\]]+?)\s*\]\]?\s*(?:#.*)?$`)
+	keyValuePattern    = regexp.MustCompile(`^([A-Za-z0-9_\-."' ]+?)\s*=`)
+	keyIndexPattern    = regexp.MustCompile(`\[\d+\]`)
+)
+
+// keyLines maps dotted key paths to the 1-based line where they first appear.
+// The TOML decoder does not expose key positions, so this is a line-oriented
+// scan: it understands table headers and key = value lines, and skips the
+// bodies of multi-line strings. Good enough to point at a typo.
+type keyLines map[string]int
+
+func indexKeyLines(content string) keyLines {
+	lines := make(keyLines)
+	table := ""
+	inMultiline := ""
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+
+		if inMultiline != "" {
+			if strings.Count(line, inMultiline)%2 == 1 {
+				inMultiline = ""
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if m := tableHeaderPattern.FindStringSubmatch(line); m != nil {
+			table = normalizeKey(m[1])
+			lines.add(table, i+1)
+			continue
+		}
+
+		if m := keyValuePattern.FindStringSubmatch(line); m != nil {
+			key := normalizeKey(m[1])
+			if table != "" {
+				key = table + "." + key
+			}
+			lines.add(key, i+1)
+
+			for _, delim := range []string{`"""`, `'''`} {
+				if strings.Count(line, delim)%2 == 1 {
+					inMultiline = delim
+				}
+			}
+		}
+	}
+	return lines
+}
+
+func (k keyLines) add(key string, line int) {
+	if _, ok := k[key]; !ok {
+		k[key] = line
+	}
+}
+
+// lookup returns the line of key, falling back to its closest parent that
+// appears in the file (a required key that is missing points at its table).
+// Array indexes such as "schedule[1]" are ignored. Returns 0 if nothing matches.
+func (k keyLines) lookup(key string) int {
+	key = keyIndexPattern.ReplaceAllString(key, "")
+	for key != "" {
+		if line, ok := k[key]; ok {
+			return line
+		}
+		dot := strings.LastIndex(key, ".")
+		if dot < 0 {
+			break
+		}
+		key = key[:dot]
+	}
+	return 0
+}
+
+// normalizeKey strips quotes and whitespace around dotted key segments.
+func normalizeKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// unknownKeys reports keys present in the file that do not map to any
+// config field, which is almost always a typo.
+func unknownKeys(md toml.MetaData, lines keyLines) []Issue {
+	undecoded := md.Undecoded()
+	seen := make(map[string]bool, len(undecoded))
+	for _, key := range undecoded {
+		seen[strings.Join(key, ".")] = true
+	}
+
+	var issues []Issue
+	for _, key := range undecoded {
+		// An unknown table makes everything under it unknown; report the table only
+		if len(key) > 1 && seen[strings.Join(key[:len(key)-1], ".")] {
+			continue
+		}
+		path := strings.Join(key, ".")
+		issue := warnf(path, "unknown key (typo?)")
+		issue.Line = lines.lookup(path)
+		issues = append(issues, issue)
+	}
+	return issues
+}
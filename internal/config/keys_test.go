@@ -0,0 +1,31 @@
+// internal/config/keys_test.go
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyLines_Lookup(t *testing.T) {
+	lines := indexKeyLines(`# comment
+[server]
+port = 8484
+
+[indexers."nzb geek"]
+url = "https://example.com"
+description = """
+fake = "not a key"
+"""
+
+[[bandwidth.schedule]]
+from = "08:00"
+`)
+
+	assert.Equal(t, 3, lines.lookup("server.port"))
+	assert.Equal(t, 6, lines.lookup("indexers.nzb geek.url"))
+	assert.Equal(t, 5, lines.lookup("indexers.nzb geek.api_key"), "missing key falls back to its table")
+	assert.NotContains(t, lines, "indexers.nzb geek.fake", "multi-line string bodies are skipped")
+	assert.Equal(t, 12, lines.lookup("bandwidth.schedule[0].from"))
+	assert.Equal(t, 0, lines.lookup("quality.default"))
+}
@@ -129,3 +129,103 @@ api_key = "test-key"
 	require.NoError(t, err)
 	assert.Equal(t, "localhost", cfg.Server.Host)
 }
+
+func TestCheck_UnknownKeysAndLines(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.toml")
+	content := `[server]
+port = 8080
+
+[libraries.movies]
+root = "` + tmp + `"
+qualit_profile = "hd"
+
+[indexers.nzbgeek]
+url = "https://api.nzbgeek.info"
+
+[notifcations.plex]
+url = "http://localhost:32400"
+`
+	require.NoError(t, os.WriteFile(cfgPath, []byte(content), 0644))
+
+	cfg, configErr, err := Check(cfgPath)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	require.Len(t, configErr.Warnings, 2, "got %v", configErr.Warnings)
+	assert.Equal(t, Issue{Key: "libraries.movies.qualit_profile", Line: 6, Severity: SeverityWarning, Message: "unknown key (typo?)"}, configErr.Warnings[0])
+	assert.Equal(t, "notifcations.plex", configErr.Warnings[1].Key, "an unknown table is reported once")
+	assert.Equal(t, 11, configErr.Warnings[1].Line)
+
+	require.Len(t, configErr.Errors, 1)
+	assert.Equal(t, "indexers.nzbgeek.api_key", configErr.Errors[0].Key)
+	assert.Equal(t, 8, configErr.Errors[0].Line, "missing key points at its table")
+}
+
+func TestLoad_WarningsDoNotFail(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.toml")
+	content := `
+[libraries.movies]
+root = "/nonexistent/path/12345"
+naming_typo = "x"
+
+[indexers.nzbgeek]
+url = "https://api.nzbgeek.info"
+api_key = "test-key"
+`
+	require.NoError(t, os.WriteFile(cfgPath, []byte(content), 0644))
+
+	cfg, err := Load(cfgPath)
+	require.NoError(t, err)
+	assert.Equal(t, "/nonexistent/path/12345", cfg.Libraries.Movies.Root)
+}
+
+func TestLoad_RootsWithoutRoot(t *testing.T) {
+	tmp := t.TempDir()
+	disk1, disk2 := t.TempDir(), t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.toml")
+	content := `
+[libraries.movies]
+roots = ["` + disk1 + `", "` + disk2 + `"]
+root_strategy = "round_robin"
+
+[indexers.nzbgeek]
+url = "https://api.nzbgeek.info"
+api_key = "test-key"
+`
+	require.NoError(t, os.WriteFile(cfgPath, []byte(content), 0644))
+
+	cfg, err := Load(cfgPath)
+	require.NoError(t, err)
+	assert.Equal(t, disk1, cfg.Libraries.Movies.Root, "first of roots becomes the primary root")
+	assert.Equal(t, []string{disk1, disk2}, cfg.Libraries.Movies.Paths())
+	assert.Equal(t, "round_robin", cfg.Libraries.Movies.RootStrategy)
+}
+
+func TestLoad_Audiobooks(t *testing.T) {
+	tmp := t.TempDir()
+	movies, books := t.TempDir(), t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.toml")
+	content := `
+[libraries.movies]
+root = "` + movies + `"
+
+[libraries.audiobooks]
+enabled = true
+roots = ["` + books + `"]
+naming = "{author}/{title}{part}.{ext}"
+
+[indexers.nzbgeek]
+url = "https://api.nzbgeek.info"
+api_key = "test-key"
+categories = ["movie", "audiobook"]
+`
+	require.NoError(t, os.WriteFile(cfgPath, []byte(content), 0644))
+
+	cfg, err := Load(cfgPath)
+	require.NoError(t, err)
+	assert.True(t, cfg.Libraries.Audiobooks.Enabled)
+	assert.Equal(t, books, cfg.Libraries.Audiobooks.Root, "first of roots becomes the primary root")
+	assert.Equal(t, "{author}/{title}{part}.{ext}", cfg.Libraries.Audiobooks.Naming)
+}
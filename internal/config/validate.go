@@ -4,6 +4,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/importer"
+	"github.com/vmunix/arrgo/internal/library"
 )
 
 var validLogLevels = map[string]bool{
@@ -14,72 +23,440 @@ var validAIProviders = map[string]bool{
 	"ollama": true, "anthropic": true,
 }
 
-// Validate checks the configuration for errors.
-// Returns a slice of error messages (empty if valid).
-func (c *Config) Validate() []string {
-	var errs []string
+var validIndexerCategories = map[string]bool{
+	"movie": true, "series": true, "audiobook": true,
+}
+
+// stuckStatuses are the download statuses with stuck thresholds.
+var stuckStatuses = []download.Status{
+	download.StatusQueued, download.StatusDownloading, download.StatusCompleted, download.StatusImporting,
+}
+
+// Validate checks the configuration for errors and warnings.
+// Returns the issues found (empty if valid); it does not know about
+// the config file, so issues carry no line numbers.
+func (c *Config) Validate() []Issue {
+	var issues []Issue
 
 	// At least one library required
-	if c.Libraries.Movies.Root == "" && c.Libraries.Series.Root == "" {
-		errs = append(errs, "libraries: at least one library (movies or series) must be configured")
+	if len(c.Libraries.Movies.Paths()) == 0 && len(c.Libraries.Series.Paths()) == 0 {
+		issues = append(issues, errorf("libraries", "at least one library (movies or series) must be configured"))
+	}
+	if c.Libraries.Audiobooks.Enabled && len(c.Libraries.Audiobooks.Paths()) == 0 {
+		issues = append(issues, errorf("libraries.audiobooks.root", "required when audiobooks are enabled"))
 	}
 
 	// Server validation
 	if c.Server.Port != 0 && (c.Server.Port < 1 || c.Server.Port > 65535) {
-		errs = append(errs, fmt.Sprintf("server.port: must be between 1 and 65535, got %d", c.Server.Port))
+		issues = append(issues, errorf("server.port", "must be between 1 and 65535, got %d", c.Server.Port))
 	}
 	if !validLogLevels[c.Server.LogLevel] {
-		errs = append(errs, fmt.Sprintf("server.log_level: must be one of debug, info, warn, error; got %q", c.Server.LogLevel))
+		issues = append(issues, errorf("server.log_level", "must be one of debug, info, warn, error; got %q", c.Server.LogLevel))
 	}
+	issues = append(issues, validateAPIKeys(c.Server.APIKeys)...)
 
 	// Quality validation
 	if c.Quality.Default != "" && len(c.Quality.Profiles) > 0 {
 		if _, ok := c.Quality.Profiles[c.Quality.Default]; !ok {
-			errs = append(errs, fmt.Sprintf("quality.default: profile %q not defined", c.Quality.Default))
+			issues = append(issues, errorf("quality.default", "profile %q not defined", c.Quality.Default))
 		}
 	}
+	if c.Quality.MinSeeders < 0 {
+		issues = append(issues, errorf("quality.min_seeders", "must not be negative"))
+	}
+	for name, p := range c.Quality.Profiles {
+		issues = append(issues, ValidateQualityProfile("quality.profiles."+name, p)...)
+	}
 
 	// Indexers validation
 	if len(c.Indexers) == 0 {
-		errs = append(errs, "indexers: at least one indexer must be configured")
+		issues = append(issues, errorf("indexers", "at least one indexer must be configured"))
 	}
 	for name, indexer := range c.Indexers {
 		if indexer.URL == "" {
-			errs = append(errs, fmt.Sprintf("indexers.%s.url: required", name))
+			issues = append(issues, errorf(fmt.Sprintf("indexers.%s.url", name), "required"))
+		}
+		if indexer.APIKey == "" && len(indexer.APIKeys) == 0 {
+			issues = append(issues, errorf(fmt.Sprintf("indexers.%s.api_key", name), "required"))
+		}
+		if indexer.Priority < 0 || indexer.Priority > 50 {
+			issues = append(issues, errorf(fmt.Sprintf("indexers.%s.priority", name), "must be between 1 and 50; got %d", indexer.Priority))
+		}
+		for _, cat := range indexer.Categories {
+			if !validIndexerCategories[cat] {
+				issues = append(issues, errorf(fmt.Sprintf("indexers.%s.categories", name), "must be one of movie, series, audiobook; got %q", cat))
+			}
 		}
-		if indexer.APIKey == "" {
-			errs = append(errs, fmt.Sprintf("indexers.%s.api_key: required", name))
+		if indexer.DailyLimit < 0 {
+			issues = append(issues, errorf(fmt.Sprintf("indexers.%s.daily_limit", name), "must not be negative"))
 		}
+		if indexer.MinimumAgeMinutes < 0 {
+			issues = append(issues, errorf(fmt.Sprintf("indexers.%s.minimum_age_minutes", name), "must not be negative"))
+		}
+	}
+	if c.Search.CacheMaxEntries < 0 {
+		issues = append(issues, errorf("search.cache_max_entries", "must not be negative"))
+	}
+	if c.Search.MinimumAgeMinutes < 0 {
+		issues = append(issues, errorf("search.minimum_age_minutes", "must not be negative"))
+	}
+	if c.Search.SeasonPackMinMissing > 1 {
+		issues = append(issues, errorf("search.season_pack_min_missing", "must be at most 1; got %g", c.Search.SeasonPackMinMissing))
 	}
 
 	// SABnzbd validation
 	if c.Downloaders.SABnzbd != nil {
 		if c.Downloaders.SABnzbd.URL == "" {
-			errs = append(errs, "downloaders.sabnzbd.url: required when sabnzbd is configured")
+			issues = append(issues, errorf("downloaders.sabnzbd.url", "required when sabnzbd is configured"))
 		}
 		if c.Downloaders.SABnzbd.APIKey == "" {
-			errs = append(errs, "downloaders.sabnzbd.api_key: required when sabnzbd is configured")
+			issues = append(issues, errorf("downloaders.sabnzbd.api_key", "required when sabnzbd is configured"))
+		}
+	}
+
+	// Named download clients validation
+	for name, client := range c.Downloaders.Clients {
+		switch {
+		case name == "manual":
+			issues = append(issues, errorf("downloaders.clients.manual", "name is reserved for manual imports"))
+		case name == ClientTypeSABnzbd && c.Downloaders.SABnzbd != nil:
+			issues = append(issues, errorf("downloaders.clients.sabnzbd", "conflicts with [downloaders.sabnzbd]; rename one of them"))
+		}
+		if client.Type != ClientTypeSABnzbd {
+			issues = append(issues, errorf(fmt.Sprintf("downloaders.clients.%s.type", name), "must be sabnzbd; got %q", client.Type))
+		}
+		if client.URL == "" {
+			issues = append(issues, errorf(fmt.Sprintf("downloaders.clients.%s.url", name), "required"))
+		}
+		if client.APIKey == "" {
+			issues = append(issues, errorf(fmt.Sprintf("downloaders.clients.%s.api_key", name), "required"))
+		}
+	}
+
+	// Download client retry validation
+	if c.Downloaders.Retry.MaxAttempts < 0 {
+		issues = append(issues, errorf("downloaders.retry.max_attempts", "must not be negative; got %d", c.Downloaders.Retry.MaxAttempts))
+	}
+	if c.Downloaders.Retry.InitialDelay < 0 {
+		issues = append(issues, errorf("downloaders.retry.initial_delay", "must not be negative; got %s", c.Downloaders.Retry.InitialDelay))
+	}
+	if c.Downloaders.Retry.MaxDelay < 0 {
+		issues = append(issues, errorf("downloaders.retry.max_delay", "must not be negative; got %s", c.Downloaders.Retry.MaxDelay))
+	}
+
+	// Stuck download thresholds validation
+	issues = append(issues, validateStuckThresholds("downloads.stuck_thresholds", c.Downloads.StuckThresholds)...)
+	issues = append(issues, validateStuckThresholds("downloads.stuck_alert_thresholds", c.Downloads.StuckAlertThresholds)...)
+	stuck, alert := c.Downloads.Thresholds()
+	for _, status := range stuckStatuses {
+		if alert[status] > 0 && alert[status] <= stuck[status] {
+			issues = append(issues, errorf("downloads.stuck_alert_thresholds."+string(status),
+				"must be longer than the stuck threshold of %s; got %s", stuck[status], alert[status]))
+		}
+	}
+
+	for i, pattern := range c.Downloads.JunkPatterns {
+		if _, err := filepath.Match(pattern, ""); err != nil || pattern == "" || strings.ContainsRune(pattern, '/') {
+			issues = append(issues, errorf(fmt.Sprintf("downloads.junk_patterns[%d]", i), "must be a file or directory name glob; got %q", pattern))
+		}
+	}
+	if c.Downloads.OrphanMinAgeDays < 0 {
+		issues = append(issues, errorf("downloads.orphan_min_age_days", "must not be negative"))
+	}
+
+	// Bandwidth schedule validation
+	for i, w := range c.Bandwidth.Schedule {
+		if _, err := download.ParseBandwidthWindow(w.From, w.To, w.Limit); err != nil {
+			issues = append(issues, errorf(fmt.Sprintf("bandwidth.schedule[%d]", i), "%v", err))
 		}
 	}
 
 	// AI validation
 	if c.AI.Enabled {
 		if !validAIProviders[c.AI.Provider] {
-			errs = append(errs, fmt.Sprintf("ai.provider: must be one of ollama, anthropic; got %q", c.AI.Provider))
+			issues = append(issues, errorf("ai.provider", "must be one of ollama, anthropic; got %q", c.AI.Provider))
+		}
+		if c.AI.Provider == "anthropic" && (c.AI.Anthropic == nil || c.AI.Anthropic.APIKey == "") {
+			issues = append(issues, errorf("ai.anthropic.api_key", "required when ai.provider is anthropic"))
 		}
 	}
 
-	// Library path warnings (non-fatal)
-	if c.Libraries.Movies.Root != "" {
-		if _, err := os.Stat(c.Libraries.Movies.Root); os.IsNotExist(err) {
-			errs = append(errs, fmt.Sprintf("libraries.movies.root: warning: directory %q does not exist", c.Libraries.Movies.Root))
+	// Integrations: required fields once a section is configured
+	if c.Downloaders.QBittorrent != nil && c.Downloaders.QBittorrent.URL == "" {
+		issues = append(issues, errorf("downloaders.qbittorrent.url", "required when qbittorrent is configured"))
+	}
+	if c.Notifications.Plex != nil {
+		if c.Notifications.Plex.URL == "" {
+			issues = append(issues, errorf("notifications.plex.url", "required when plex is configured"))
+		}
+		if c.Notifications.Plex.ScanMaxPaths < 0 {
+			issues = append(issues, errorf("notifications.plex.scan_max_paths", "must not be negative; got %d", c.Notifications.Plex.ScanMaxPaths))
+		}
+		if c.Notifications.Plex.Token == "" {
+			issues = append(issues, errorf("notifications.plex.token", "required when plex is configured"))
 		}
 	}
-	if c.Libraries.Series.Root != "" {
-		if _, err := os.Stat(c.Libraries.Series.Root); os.IsNotExist(err) {
-			errs = append(errs, fmt.Sprintf("libraries.series.root: warning: directory %q does not exist", c.Libraries.Series.Root))
+	if c.Overseerr.Enabled {
+		if c.Overseerr.URL == "" {
+			issues = append(issues, errorf("overseerr.url", "required when overseerr is enabled"))
+		}
+		if c.Overseerr.APIKey == "" {
+			issues = append(issues, errorf("overseerr.api_key", "required when overseerr is enabled"))
 		}
 	}
+	if (c.Compat.Radarr || c.Compat.Sonarr) && c.Compat.APIKey == "" {
+		issues = append(issues, warnf("compat.api_key", "not set; the radarr/sonarr API will accept any key"))
+	}
 
-	return errs
+	// Library roots must be writable directories; a missing one is only a
+	// warning since it may be a mount that is not up yet.
+	type keyedLibrary struct {
+		key string
+		cfg LibraryConfig
+	}
+	libraries := []keyedLibrary{
+		{"libraries.movies", c.Libraries.Movies},
+		{"libraries.series", c.Libraries.Series},
+	}
+	if c.Libraries.Audiobooks.Enabled {
+		libraries = append(libraries, keyedLibrary{"libraries.audiobooks", c.Libraries.Audiobooks.LibraryConfig})
+	}
+	for _, lib := range libraries {
+		issues = append(issues, checkDir(lib.key+".root", lib.cfg.Root)...)
+		for i, root := range lib.cfg.Roots {
+			if root != lib.cfg.Root {
+				issues = append(issues, checkDir(fmt.Sprintf("%s.roots[%d]", lib.key, i), root)...)
+			}
+		}
+		if _, ok := library.ParseRootStrategy(lib.cfg.RootStrategy); !ok {
+			issues = append(issues, errorf(lib.key+".root_strategy",
+				"must be one of first_with_space, most_free_space, round_robin; got %q", lib.cfg.RootStrategy))
+		}
+	}
+	issues = append(issues, checkDir("importer.watch_dir", c.Importer.WatchDir)...)
+
+	// Hooks
+	issues = append(issues, checkExecutable("importer.post_import_hook", c.Importer.PostImportHook)...)
+	issues = append(issues, checkExecutable("importer.pre_cleanup_hook", c.Importer.PreCleanupHook)...)
+	if c.Importer.HookTimeout < 0 {
+		issues = append(issues, errorf("importer.hook_timeout", "must not be negative; got %s", c.Importer.HookTimeout))
+	}
+	if c.Importer.MinPartRatio < 0 || c.Importer.MinPartRatio >= 1 {
+		issues = append(issues, errorf("importer.min_part_ratio", "must be at least 0 and below 1; got %g", c.Importer.MinPartRatio))
+	}
+	if c.Importer.Concurrency < 0 {
+		issues = append(issues, errorf("importer.concurrency", "must not be negative; got %d", c.Importer.Concurrency))
+	}
+	if c.Importer.SeasonPackConcurrency < 0 {
+		issues = append(issues, errorf("importer.season_pack_concurrency", "must not be negative; got %d", c.Importer.SeasonPackConcurrency))
+	}
+	for _, lang := range c.Importer.SubtitleLanguages {
+		if _, ok := importer.LanguageCode(lang); !ok {
+			issues = append(issues, errorf("importer.subtitle_languages", "unknown language %q", lang))
+		}
+	}
+
+	return issues
+}
+
+// checkExecutable reports whether path is an executable file. A missing file
+// is only a warning, like a missing directory. An empty path is not checked.
+func checkExecutable(key, path string) []Issue {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return []Issue{warnf(key, "%q does not exist", path)}
+	case err != nil:
+		return []Issue{warnf(key, "cannot access %q: %v", path, err)}
+	case info.IsDir():
+		return []Issue{errorf(key, "%q is a directory", path)}
+	case info.Mode().Perm()&0o111 == 0:
+		return []Issue{errorf(key, "%q is not executable", path)}
+	}
+	return nil
+}
+
+// checkDir reports whether dir exists, is a directory, and is writable.
+// An empty dir is not checked.
+func checkDir(key, dir string) []Issue {
+	if dir == "" {
+		return nil
+	}
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		return []Issue{warnf(key, "directory %q does not exist", dir)}
+	case err != nil:
+		return []Issue{warnf(key, "cannot access %q: %v", dir, err)}
+	case !info.IsDir():
+		return []Issue{errorf(key, "%q is not a directory", dir)}
+	}
+
+	f, err := os.CreateTemp(dir, ".arrgo-check-*")
+	if err != nil {
+		return []Issue{warnf(key, "directory %q is not writable", dir)}
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	return nil
+}
+
+// ValidateQualityProfile checks a quality profile's settings. key prefixes
+// the issue keys, e.g. "quality.profiles.hd".
+func ValidateQualityProfile(key string, p QualityProfile) []Issue {
+	var issues []Issue
+	if p.MinSeeders < 0 {
+		issues = append(issues, errorf(key+".min_seeders", "must not be negative"))
+	}
+	if p.MinSizeMB < 0 {
+		issues = append(issues, errorf(key+".min_size_mb", "must not be negative"))
+	}
+	switch p.PreferredProtocol {
+	case "", "usenet", "torrent":
+	default:
+		issues = append(issues, errorf(key+".preferred_protocol", "must be usenet or torrent; got %q", p.PreferredProtocol))
+	}
+	if p.MaxSizeMB < 0 {
+		issues = append(issues, errorf(key+".max_size_mb", "must not be negative"))
+	}
+	if p.MaxSizeMB > 0 && p.MinSizeMB > p.MaxSizeMB {
+		issues = append(issues, errorf(key+".min_size_mb", "must not exceed max_size_mb (%d)", p.MaxSizeMB))
+	}
+	for i, kw := range p.Required {
+		if strings.TrimSpace(kw) == "" {
+			issues = append(issues, errorf(fmt.Sprintf("%s.required[%d]", key, i), "keyword must not be empty"))
+		}
+	}
+	for i, kw := range p.Forbidden {
+		if strings.TrimSpace(kw) == "" {
+			issues = append(issues, errorf(fmt.Sprintf("%s.forbidden[%d]", key, i), "keyword must not be empty"))
+		}
+	}
+	for i, pk := range p.Preferred {
+		if strings.TrimSpace(pk.Keyword) == "" {
+			issues = append(issues, errorf(fmt.Sprintf("%s.preferred[%d].keyword", key, i), "required"))
+		}
+	}
+	for i, tier := range p.PreferredGroups {
+		for j, g := range tier {
+			field := fmt.Sprintf("%s.preferred_groups[%d][%d]", key, i, j)
+			if strings.TrimSpace(g) == "" {
+				issues = append(issues, errorf(field, "group must not be empty"))
+			} else if slices.ContainsFunc(p.BannedGroups, func(b string) bool { return strings.EqualFold(b, g) }) {
+				issues = append(issues, errorf(field, "%q is also in banned_groups", g))
+			}
+		}
+	}
+	for i, g := range p.BannedGroups {
+		if strings.TrimSpace(g) == "" {
+			issues = append(issues, errorf(fmt.Sprintf("%s.banned_groups[%d]", key, i), "group must not be empty"))
+		}
+	}
+	if strings.TrimSpace(p.Cutoff) != "" {
+		fields := strings.Fields(p.Cutoff)
+		res, source := fields[0], ""
+		if len(fields) > 1 {
+			source = fields[1]
+		}
+		switch {
+		case len(fields) > 2:
+			issues = append(issues, errorf(key+".cutoff", "must be a resolution and an optional source, e.g. \"1080p webdl\"; got %q", p.Cutoff))
+		case len(p.Resolution) > 0 && !slices.ContainsFunc(p.Resolution, func(r string) bool { return strings.EqualFold(r, res) }):
+			issues = append(issues, errorf(key+".cutoff", "resolution %q is not one of resolution %v", res, p.Resolution))
+		case len(p.Resolution) == 0 && library.QualityRank(res) == 0:
+			issues = append(issues, errorf(key+".cutoff", "unknown resolution %q", res))
+		case source != "" && !slices.ContainsFunc(p.Sources, func(s string) bool { return strings.EqualFold(s, source) }):
+			issues = append(issues, errorf(key+".cutoff", "source %q is not one of sources %v", source, p.Sources))
+		}
+	}
+	for i, lang := range p.ExcludeLanguages {
+		if slices.ContainsFunc(p.Languages, func(l string) bool { return strings.EqualFold(l, lang) }) {
+			issues = append(issues, errorf(fmt.Sprintf("%s.exclude_languages[%d]", key, i), "%q is also in languages", lang))
+		}
+	}
+	return issues
+}
+
+// ValidateProfiles checks that every quality profile referenced by library
+// content is defined in config. inUse maps profile name to the number of
+// content items using it. Undefined profiles are warnings: the content stays
+// in the library but cannot be searched until the profile is added or the
+// content is moved to another one.
+func (c *Config) ValidateProfiles(inUse map[string]int) []Issue {
+	return ValidateProfilesDefined(c.Quality.Profiles, inUse)
+}
+
+// ValidateProfilesDefined is ValidateProfiles against profiles defined
+// elsewhere, such as the database.
+func ValidateProfilesDefined(defined map[string]QualityProfile, inUse map[string]int) []Issue {
+	names := make([]string, 0, len(inUse))
+	for name := range inUse {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []Issue
+	for _, name := range names {
+		if _, ok := defined[name]; !ok {
+			issues = append(issues, warnf("quality.profiles", "profile %q is used by %d content item(s) but not defined", name, inUse[name]))
+		}
+	}
+	return issues
+}
+
+// validateStuckThresholds checks the keys and values of a per-status threshold map.
+// validateAPIKeys checks [server.api_keys]: each key needs a known scope and
+// one or two values, and no value may belong to two keys.
+func validateAPIKeys(keys map[string]*APIKeyConfig) []Issue {
+	var issues []Issue
+	owner := make(map[string]string)
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key := keys[name]
+		prefix := "server.api_keys." + name
+		switch key.Scope {
+		case APIKeyScopeRead, APIKeyScopeWrite, APIKeyScopeAdmin:
+		default:
+			issues = append(issues, errorf(prefix+".scope", "must be one of read, write, admin; got %q", key.Scope))
+		}
+		switch {
+		case len(key.Values) == 0:
+			issues = append(issues, errorf(prefix+".values", "required"))
+		case len(key.Values) > 2:
+			issues = append(issues, errorf(prefix+".values", "at most 2 values, the current one and its replacement; got %d", len(key.Values)))
+		}
+		for _, v := range key.Values {
+			if v == "" {
+				issues = append(issues, errorf(prefix+".values", "must not be empty"))
+				continue
+			}
+			if other, ok := owner[v]; ok {
+				issues = append(issues, errorf(prefix+".values", "value also belongs to key %q", other))
+				continue
+			}
+			owner[v] = name
+		}
+	}
+	return issues
+}
+
+func validateStuckThresholds(key string, thresholds map[string]time.Duration) []Issue {
+	var issues []Issue
+	for status, d := range thresholds {
+		if !slices.Contains(stuckStatuses, download.Status(status)) {
+			issues = append(issues, errorf(key+"."+status, "must be one of queued, downloading, completed, importing"))
+			continue
+		}
+		if d <= 0 {
+			issues = append(issues, errorf(key+"."+status, "must be positive; got %s", d))
+		}
+	}
+	return issues
 }
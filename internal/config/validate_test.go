@@ -3,10 +3,15 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmunix/arrgo/internal/download"
 )
 
 func TestValidate_MinimalValid(t *testing.T) {
@@ -49,6 +54,24 @@ func TestValidate_InvalidLogLevel(t *testing.T) {
 	assert.True(t, containsError(errs, "log_level"), "expected log_level error, got %v", errs)
 }
 
+func TestValidate_APIKeys(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{APIKeys: map[string]*APIKeyConfig{
+			"automation": {Scope: "admin", Values: []string{"new", "old"}},
+			"dashboard":  {Scope: "viewer", Values: []string{"a", "b", "c"}},
+			"widget":     {Scope: "read", Values: []string{"old"}},
+			"empty":      {Scope: "write"},
+		}},
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+	}
+	errs := cfg.Validate()
+	assert.False(t, containsError(errs, "server.api_keys.automation"), "expected automation to be valid, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "dashboard.scope", "viewer"), "expected scope error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "dashboard.values", "at most 2"), "expected too many values error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "widget.values", "automation"), "expected shared value error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "empty.values", "required"), "expected missing values error, got %v", errs)
+}
+
 func TestValidate_IndexerMissingAPIKey(t *testing.T) {
 	cfg := &Config{
 		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
@@ -60,6 +83,87 @@ func TestValidate_IndexerMissingAPIKey(t *testing.T) {
 	assert.True(t, containsErrorBoth(errs, "nzbgeek", "api_key"), "expected indexer api_key error, got %v", errs)
 }
 
+func TestValidate_IndexerInvalidCategory(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Indexers: IndexersConfig{
+			"nzbgeek": &NewznabConfig{URL: "https://api.nzbgeek.info", APIKey: "key", Categories: []string{"tv"}},
+		},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "nzbgeek", "categories"), "expected indexer categories error, got %v", errs)
+}
+
+func TestValidate_AudiobooksRequireRoot(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{
+			Movies:     LibraryConfig{Root: "/tmp"},
+			Audiobooks: AudiobookLibraryConfig{Enabled: true},
+		},
+		Indexers: IndexersConfig{"nzbgeek": &NewznabConfig{URL: "https://api.nzbgeek.info", APIKey: "key"}},
+	}
+	assert.True(t, containsError(cfg.Validate(), "libraries.audiobooks.root"))
+
+	cfg.Libraries.Audiobooks.Enabled = false
+	assert.Empty(t, cfg.Validate(), "a disabled audiobook library needs no root")
+}
+
+func TestValidate_IndexerPriorityOutOfRange(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Indexers: IndexersConfig{
+			"nzbgeek": &NewznabConfig{URL: "https://api.nzbgeek.info", APIKey: "key", Priority: 99},
+		},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "nzbgeek", "priority"), "expected indexer priority error, got %v", errs)
+}
+
+func TestValidate_IndexerNegativeDailyLimit(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Indexers: IndexersConfig{
+			"nzbgeek": &NewznabConfig{URL: "https://api.nzbgeek.info", APIKey: "key", DailyLimit: -1},
+		},
+		Search: SearchConfig{CacheMaxEntries: -1},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "nzbgeek", "daily_limit"), "expected indexer daily_limit error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "search", "cache_max_entries"), "expected cache_max_entries error, got %v", errs)
+}
+
+func TestValidate_NegativeMinimumAge(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Indexers: IndexersConfig{
+			"nzbgeek": &NewznabConfig{URL: "https://api.nzbgeek.info", APIKey: "key", MinimumAgeMinutes: -5},
+		},
+		Search: SearchConfig{MinimumAgeMinutes: -1},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "nzbgeek", "minimum_age_minutes"), "expected indexer minimum_age_minutes error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "search", "minimum_age_minutes"), "expected search minimum_age_minutes error, got %v", errs)
+}
+
+func TestValidate_SeasonPackSizes(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Indexers: IndexersConfig{
+			"nzbgeek": &NewznabConfig{URL: "https://api.nzbgeek.info", APIKey: "key"},
+		},
+		Quality: QualityConfig{Profiles: map[string]QualityProfile{
+			"hd":  {MinSizeMB: 4000, MaxSizeMB: 2000},
+			"uhd": {MaxSizeMB: -1, PreferredProtocol: "nzb"},
+		}},
+		Search: SearchConfig{SeasonPackMinMissing: 1.5},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "hd", "min_size_mb"), "expected min_size_mb error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "uhd", "max_size_mb"), "expected max_size_mb error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "uhd", "preferred_protocol"), "expected preferred_protocol error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "search", "season_pack_min_missing"), "expected season_pack_min_missing error, got %v", errs)
+}
+
 func TestValidate_NoIndexers(t *testing.T) {
 	cfg := &Config{
 		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
@@ -81,6 +185,169 @@ func TestValidate_QualityDefaultNotDefined(t *testing.T) {
 	assert.True(t, containsErrorBoth(errs, "quality.default", "ultra"), "expected quality.default error, got %v", errs)
 }
 
+func TestValidate_QualityEmptyKeyword(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Quality: QualityConfig{
+			Profiles: map[string]QualityProfile{"hd": {
+				Forbidden: []string{"CAM", " "},
+				Preferred: []PreferredKeyword{{Keyword: "", Weight: 10}},
+			}},
+		},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.hd.forbidden[1]", "empty"), "expected forbidden keyword error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.hd.preferred[0].keyword", "required"), "expected preferred keyword error, got %v", errs)
+}
+
+func TestValidate_QualityLanguageConflict(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Quality: QualityConfig{
+			Profiles: map[string]QualityProfile{"hd": {
+				Languages:        []string{"english", "German"},
+				ExcludeLanguages: []string{"german"},
+			}},
+		},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.hd.exclude_languages[0]", "also in languages"), "expected language conflict error, got %v", errs)
+}
+
+func TestValidate_QualityGroups(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Quality: QualityConfig{
+			Profiles: map[string]QualityProfile{"hd": {
+				PreferredGroups: [][]string{{"FraMeSToR"}, {"FLUX", "yify"}},
+				BannedGroups:    []string{"YIFY", " "},
+			}},
+		},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.hd.preferred_groups[1][1]", "also in banned_groups"), "expected group conflict error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.hd.banned_groups[1]", "empty"), "expected empty group error, got %v", errs)
+}
+
+func TestValidate_QualityCutoff(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Quality: QualityConfig{
+			Profiles: map[string]QualityProfile{
+				"hd":    {Resolution: []string{"720p", "1080p"}, Sources: []string{"bluray", "webdl"}, Cutoff: "1080p WEBDL"},
+				"res":   {Resolution: []string{"720p", "1080p"}, Cutoff: "2160p"},
+				"src":   {Resolution: []string{"1080p"}, Sources: []string{"bluray"}, Cutoff: "1080p hdtv"},
+				"any":   {Cutoff: "ultra"},
+				"words": {Cutoff: "1080p web dl"},
+			},
+		},
+	}
+	errs := cfg.Validate()
+	assert.Nil(t, findIssue(errs, "quality.profiles.hd.cutoff"), "got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.res.cutoff", `"2160p" is not one of resolution`), "got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.src.cutoff", `"hdtv" is not one of sources`), "got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.any.cutoff", "unknown resolution"), "got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.words.cutoff", "optional source"), "got %v", errs)
+}
+
+func TestValidate_QualityMinSeeders(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Quality: QualityConfig{
+			MinSeeders: -1,
+			Profiles:   map[string]QualityProfile{"hd": {MinSeeders: -5}},
+		},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "quality.min_seeders", "negative"), "expected min_seeders error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "quality.profiles.hd.min_seeders", "negative"), "expected profile min_seeders error, got %v", errs)
+}
+
+func TestValidate_BandwidthSchedule(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Bandwidth: BandwidthConfig{Schedule: []BandwidthWindowConfig{
+			{From: "08:00", To: "23:00", Limit: "5MB"},
+			{From: "8am", To: "23:00", Limit: "5MB"},
+			{From: "23:00", To: "08:00", Limit: "fast"},
+		}},
+	}
+	errs := cfg.Validate()
+	assert.False(t, containsError(errs, "bandwidth.schedule[0]"), "expected valid window, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "bandwidth.schedule[1]", "time of day"), "expected time error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "bandwidth.schedule[2]", "speed limit"), "expected limit error, got %v", errs)
+}
+
+func TestValidate_DownloadRetry(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Downloaders: DownloadersConfig{Retry: RetryConfig{
+			MaxAttempts:  -1,
+			InitialDelay: 2 * time.Second,
+			MaxDelay:     -time.Second,
+		}},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsError(errs, "downloaders.retry.max_attempts"), "expected max_attempts error, got %v", errs)
+	assert.False(t, containsError(errs, "downloaders.retry.initial_delay"), "expected valid initial_delay, got %v", errs)
+	assert.True(t, containsError(errs, "downloaders.retry.max_delay"), "expected max_delay error, got %v", errs)
+}
+
+func TestValidate_StuckThresholds(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Downloads: DownloadsConfig{
+			StuckThresholds: map[string]time.Duration{
+				"queued":      time.Hour,
+				"downloading": 30 * time.Hour,
+				"importing":   -time.Minute,
+				"seeding":     time.Hour,
+			},
+			StuckAlertThresholds: map[string]time.Duration{"queued": 3 * time.Hour},
+		},
+	}
+	errs := cfg.Validate()
+	assert.False(t, containsError(errs, "downloads.stuck_thresholds.queued"), "expected valid queued, got %v", errs)
+	assert.True(t, containsError(errs, "downloads.stuck_thresholds.importing"), "expected importing error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "downloads.stuck_thresholds.seeding", "must be one of"), "expected status error, got %v", errs)
+	assert.False(t, containsError(errs, "downloads.stuck_alert_thresholds.queued"), "expected valid alert, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "downloads.stuck_alert_thresholds.downloading", "longer than"), "default alert below configured threshold, got %v", errs)
+
+	stuck, alert := cfg.Downloads.Thresholds()
+	assert.Equal(t, time.Hour, stuck[download.StatusQueued])
+	assert.Equal(t, 30*time.Minute, stuck[download.StatusCompleted], "defaults fill unset statuses")
+	assert.Equal(t, 3*time.Hour, alert[download.StatusQueued])
+}
+
+func TestValidate_JunkAndOrphans(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Downloads: DownloadsConfig{
+			JunkPatterns:     []string{"*.nfo", "[", "sample/*"},
+			OrphanMinAgeDays: -1,
+		},
+	}
+	errs := cfg.Validate()
+	assert.False(t, containsError(errs, "downloads.junk_patterns[0]"), "expected valid pattern, got %v", errs)
+	assert.True(t, containsError(errs, "downloads.junk_patterns[1]"), "expected bad glob error, got %v", errs)
+	assert.True(t, containsError(errs, "downloads.junk_patterns[2]"), "expected path error, got %v", errs)
+	assert.True(t, containsError(errs, "downloads.orphan_min_age_days"), "expected orphan age error, got %v", errs)
+	assert.Equal(t, 7*24*time.Hour, DownloadsConfig{}.OrphanMinAge())
+}
+
+func TestValidate_SubtitleLanguages(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Importer:  ImporterConfig{SubtitleLanguages: []string{"en", "French", "klingon"}},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsError(errs, "importer.subtitle_languages"), "expected language error, got %v", errs)
+
+	cfg.Importer.SubtitleLanguages = []string{"en", "French"}
+	assert.False(t, containsError(cfg.Validate(), "importer.subtitle_languages"))
+	assert.True(t, cfg.Importer.ShouldImportSubtitles())
+}
+
 func TestValidate_AIProviderInvalid(t *testing.T) {
 	cfg := &Config{
 		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
@@ -97,7 +364,10 @@ func TestValidate_LibraryRootWarning(t *testing.T) {
 		},
 	}
 	errs := cfg.Validate()
-	assert.True(t, containsErrorBoth(errs, "warning", "does not exist"), "expected warning for nonexistent path, got %v", errs)
+	issue := findIssue(errs, "libraries.movies.root")
+	require.NotNil(t, issue, "expected warning for nonexistent path, got %v", errs)
+	assert.Equal(t, SeverityWarning, issue.Severity)
+	assert.Contains(t, issue.Message, "does not exist")
 }
 
 func TestValidate_LibraryRootExists(t *testing.T) {
@@ -111,6 +381,80 @@ func TestValidate_LibraryRootExists(t *testing.T) {
 	assert.False(t, containsError(errs, tmp), "unexpected error for existing path: %v", errs)
 }
 
+func TestValidate_LibraryRoots(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := &Config{
+		Libraries: LibrariesConfig{
+			Movies: LibraryConfig{Root: tmp, Roots: []string{tmp, "/nonexistent/path/12345"}, RootStrategy: "most_free_space"},
+			Series: LibraryConfig{Root: tmp, RootStrategy: "random"},
+		},
+	}
+	errs := cfg.Validate()
+
+	issue := findIssue(errs, "libraries.movies.roots[1]")
+	require.NotNil(t, issue, "expected warning for missing extra root, got %v", errs)
+	assert.Equal(t, SeverityWarning, issue.Severity)
+	assert.Nil(t, findIssue(errs, "libraries.movies.roots[0]"), "root repeated in roots is checked once")
+	assert.Nil(t, findIssue(errs, "libraries.movies.root_strategy"))
+
+	issue = findIssue(errs, "libraries.series.root_strategy")
+	require.NotNil(t, issue, "expected error for unknown strategy, got %v", errs)
+	assert.Equal(t, SeverityError, issue.Severity)
+}
+
+func TestLibraryConfig_Paths(t *testing.T) {
+	lib := LibraryConfig{Root: "/movies", Roots: []string{"/movies", "", "/movies2"}}
+	assert.Equal(t, []string{"/movies", "/movies2"}, lib.Paths())
+	assert.Empty(t, (&LibraryConfig{}).Paths())
+}
+
+func TestValidate_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "transcode.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"), 0o755))
+	notExec := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(notExec, []byte("x"), 0o644))
+
+	cfg := &Config{Importer: ImporterConfig{PostImportHook: script}}
+	assert.Nil(t, findIssue(cfg.Validate(), "importer.post_import_hook"))
+
+	cfg = &Config{Importer: ImporterConfig{
+		PostImportHook: notExec,
+		PreCleanupHook: filepath.Join(dir, "missing.sh"),
+		HookTimeout:    -time.Second,
+		MinPartRatio:   1.5,
+		Concurrency:    -1,
+
+		SeasonPackConcurrency: -1,
+	}}
+	issues := cfg.Validate()
+
+	issue := findIssue(issues, "importer.post_import_hook")
+	require.NotNil(t, issue, "got %v", issues)
+	assert.Equal(t, SeverityError, issue.Severity)
+	assert.Contains(t, issue.Message, "not executable")
+
+	issue = findIssue(issues, "importer.pre_cleanup_hook")
+	require.NotNil(t, issue, "got %v", issues)
+	assert.Equal(t, SeverityWarning, issue.Severity)
+
+	issue = findIssue(issues, "importer.hook_timeout")
+	require.NotNil(t, issue, "got %v", issues)
+	assert.Equal(t, SeverityError, issue.Severity)
+
+	issue = findIssue(issues, "importer.min_part_ratio")
+	require.NotNil(t, issue, "got %v", issues)
+	assert.Equal(t, SeverityError, issue.Severity)
+
+	issue = findIssue(issues, "importer.concurrency")
+	require.NotNil(t, issue, "got %v", issues)
+	assert.Equal(t, SeverityError, issue.Severity)
+
+	issue = findIssue(issues, "importer.season_pack_concurrency")
+	require.NotNil(t, issue, "got %v", issues)
+	assert.Equal(t, SeverityError, issue.Severity)
+}
+
 func TestValidate_SABnzbdMissingURL(t *testing.T) {
 	cfg := &Config{
 		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: os.TempDir()}},
@@ -122,21 +466,92 @@ func TestValidate_SABnzbdMissingURL(t *testing.T) {
 	assert.True(t, containsErrorBoth(errs, "sabnzbd", "url"), "expected sabnzbd url error, got %v", errs)
 }
 
+func TestValidate_DownloadClients(t *testing.T) {
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: "/tmp"}},
+		Downloaders: DownloadersConfig{
+			SABnzbd: &SABnzbdConfig{URL: "http://localhost:8085", APIKey: "key"},
+			Clients: map[string]*DownloadClientConfig{
+				"sabnzbd": {Type: "sabnzbd", SABnzbdConfig: SABnzbdConfig{URL: "http://b", APIKey: "k"}},
+				"manual":  {Type: "sabnzbd", SABnzbdConfig: SABnzbdConfig{URL: "http://c", APIKey: "k"}},
+				"torrent": {Type: "transmission"},
+			},
+		},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsErrorBoth(errs, "downloaders.clients.sabnzbd", "conflicts"), "expected conflict error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "downloaders.clients.manual", "reserved"), "expected reserved name error, got %v", errs)
+	assert.True(t, containsErrorBoth(errs, "downloaders.clients.torrent.type", "transmission"), "expected type error, got %v", errs)
+	assert.True(t, containsError(errs, "downloaders.clients.torrent.url: required"), "expected url error, got %v", errs)
+	assert.True(t, containsError(errs, "downloaders.clients.torrent.api_key: required"), "expected api_key error, got %v", errs)
+}
+
+func TestValidate_LibraryRootNotDirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "movies")
+	require.NoError(t, os.WriteFile(file, nil, 0644))
+	cfg := &Config{
+		Libraries: LibrariesConfig{Movies: LibraryConfig{Root: file}},
+	}
+	issue := findIssue(cfg.Validate(), "libraries.movies.root")
+	require.NotNil(t, issue)
+	assert.Equal(t, SeverityError, issue.Severity)
+	assert.Contains(t, issue.Message, "not a directory")
+}
+
+func TestValidate_Integrations(t *testing.T) {
+	cfg := &Config{
+		Libraries:     LibrariesConfig{Movies: LibraryConfig{Root: t.TempDir()}},
+		Notifications: NotificationsConfig{Plex: &PlexConfig{URL: "http://localhost:32400"}},
+		Overseerr:     OverseerrConfig{Enabled: true},
+		AI:            AIConfig{Enabled: true, Provider: "anthropic"},
+		Compat:        CompatConfig{Radarr: true},
+	}
+	errs := cfg.Validate()
+	assert.True(t, containsError(errs, "notifications.plex.token: required"), "expected plex token error, got %v", errs)
+	assert.False(t, containsError(errs, "notifications.plex.url"), "unexpected plex url error: %v", errs)
+	assert.True(t, containsError(errs, "overseerr.url: required"), "expected overseerr url error, got %v", errs)
+	assert.True(t, containsError(errs, "overseerr.api_key: required"), "expected overseerr api_key error, got %v", errs)
+	assert.True(t, containsError(errs, "ai.anthropic.api_key: required"), "expected anthropic key error, got %v", errs)
+
+	compat := findIssue(errs, "compat.api_key")
+	require.NotNil(t, compat)
+	assert.Equal(t, SeverityWarning, compat.Severity)
+}
+
+func TestValidateProfiles(t *testing.T) {
+	cfg := &Config{
+		Quality: QualityConfig{Profiles: map[string]QualityProfile{"hd": {}}},
+	}
+	issues := cfg.ValidateProfiles(map[string]int{"hd": 10, "uhd": 2})
+	require.Len(t, issues, 1)
+	assert.Equal(t, SeverityWarning, issues[0].Severity)
+	assert.Contains(t, issues[0].Message, `"uhd" is used by 2`)
+}
+
 // Helper functions to check for errors containing specific strings
-func containsError(errs []string, substr string) bool {
+func containsError(errs []Issue, substr string) bool {
 	for _, e := range errs {
-		if strings.Contains(e, substr) {
+		if strings.Contains(e.String(), substr) {
 			return true
 		}
 	}
 	return false
 }
 
-func containsErrorBoth(errs []string, substr1, substr2 string) bool {
+func containsErrorBoth(errs []Issue, substr1, substr2 string) bool {
 	for _, e := range errs {
-		if strings.Contains(e, substr1) && strings.Contains(e, substr2) {
+		if strings.Contains(e.String(), substr1) && strings.Contains(e.String(), substr2) {
 			return true
 		}
 	}
 	return false
 }
+
+func findIssue(issues []Issue, key string) *Issue {
+	for i := range issues {
+		if issues[i].Key == key {
+			return &issues[i]
+		}
+	}
+	return nil
+}
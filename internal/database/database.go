@@ -0,0 +1,71 @@
+// Package database opens the SQLite database shared by the stores.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	_ "modernc.org/sqlite" // SQLite driver
+)
+
+// BusyTimeout is how long a connection waits for a lock before failing with
+// "database is locked", in milliseconds.
+const BusyTimeout = 5000
+
+// DSN returns the connection string for the SQLite database at path.
+//
+// The pragmas are applied to every pooled connection, not just the first:
+//   - journal_mode=WAL lets readers proceed while a write is in progress
+//   - busy_timeout waits for locks instead of failing immediately
+//   - synchronous=NORMAL is durable under WAL and avoids an fsync per commit
+//
+// _txlock=immediate makes every transaction take the write lock at BEGIN.
+// This is the single-writer guard: read-modify-write transactions queue on
+// busy_timeout rather than failing when a deferred read lock can't be upgraded.
+func DSN(path string) string {
+	q := url.Values{}
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", BusyTimeout))
+	q.Add("_pragma", "synchronous(NORMAL)")
+	q.Set("_txlock", "immediate")
+	return path + "?" + q.Encode()
+}
+
+// Open opens the SQLite database at path with the settings described in DSN
+// and verifies the connection.
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", DSN(path))
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connect db: %w", err)
+	}
+	return db, nil
+}
+
+// CheckWritable commits a transaction that rewrites the database's user
+// version unchanged, so it fails when the database file or its directory
+// cannot be written, e.g. a read-only mount or a full disk.
+func CheckWritable(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var v int
+	if err := tx.QueryRowContext(ctx, "PRAGMA user_version").Scan(&v); err != nil {
+		return fmt.Errorf("read user version: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", v)); err != nil {
+		return fmt.Errorf("write user version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_AppliesPragmasToEveryConnection(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "arrgo.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	// Hold one connection so the checks below run on a second one
+	held, err := db.Conn(t.Context())
+	require.NoError(t, err)
+	defer func() { _ = held.Close() }()
+
+	var mode string
+	require.NoError(t, db.QueryRow("PRAGMA journal_mode").Scan(&mode))
+	assert.Equal(t, "wal", mode)
+
+	var timeout int
+	require.NoError(t, db.QueryRow("PRAGMA busy_timeout").Scan(&timeout))
+	assert.Equal(t, BusyTimeout, timeout)
+
+	var synchronous int
+	require.NoError(t, db.QueryRow("PRAGMA synchronous").Scan(&synchronous))
+	assert.Equal(t, 1, synchronous, "synchronous should be NORMAL")
+}
+
+func TestCheckWritable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arrgo.db")
+	db, err := Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, CheckWritable(t.Context(), db))
+
+	ro, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ro.Close() })
+	assert.Error(t, CheckWritable(t.Context(), ro), "read-only database")
+}
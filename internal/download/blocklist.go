@@ -0,0 +1,103 @@
+package download
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BlocklistEntry is a release that must not be grabbed again for a content item.
+// Entries are added when a download fails or its import fails, so the same
+// broken release is not picked up by later searches or retries.
+type BlocklistEntry struct {
+	ID          int64
+	ContentID   int64
+	GUID        string
+	ReleaseName string
+	Indexer     string
+	Reason      string
+	CreatedAt   time.Time
+}
+
+// Block adds a release to the content's blocklist.
+// This method is idempotent: blocking an already blocked GUID returns the existing entry.
+func (s *Store) Block(e *BlocklistEntry) error {
+	if e.GUID == "" {
+		return errors.New("block release: guid is required")
+	}
+
+	now := time.Now()
+	_, err := s.db.Exec(`
+		INSERT INTO blocklist (content_id, guid, release_name, indexer, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(content_id, guid) DO NOTHING`,
+		e.ContentID, e.GUID, e.ReleaseName, e.Indexer, e.Reason, now,
+	)
+	if err != nil {
+		return fmt.Errorf("block release %s for content %d: %w", e.GUID, e.ContentID, err)
+	}
+
+	err = s.db.QueryRow(`SELECT id, created_at FROM blocklist WHERE content_id = ? AND guid = ?`,
+		e.ContentID, e.GUID,
+	).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("get blocklist entry %s for content %d: %w", e.GUID, e.ContentID, err)
+	}
+	return nil
+}
+
+// IsBlocked reports whether a release GUID is blocklisted for the content.
+func (s *Store) IsBlocked(contentID int64, guid string) (bool, error) {
+	if guid == "" {
+		return false, nil
+	}
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM blocklist WHERE content_id = ? AND guid = ?`,
+		contentID, guid,
+	).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("check blocklist %s for content %d: %w", guid, contentID, err)
+	}
+	return n > 0, nil
+}
+
+// ListBlocked returns the blocklist entries for a content item, newest first.
+func (s *Store) ListBlocked(contentID int64) ([]*BlocklistEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT id, content_id, guid, release_name, indexer, reason, created_at
+		FROM blocklist WHERE content_id = ? ORDER BY created_at DESC, id DESC`, contentID)
+	if err != nil {
+		return nil, fmt.Errorf("list blocklist for content %d: %w", contentID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []*BlocklistEntry
+	for rows.Next() {
+		e := &BlocklistEntry{}
+		if err := rows.Scan(&e.ID, &e.ContentID, &e.GUID, &e.ReleaseName, &e.Indexer, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan blocklist entry: %w", err)
+		}
+		results = append(results, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate blocklist: %w", err)
+	}
+	return results, nil
+}
+
+// Unblock removes a release from the content's blocklist.
+// Returns ErrNotFound if the GUID is not blocklisted for the content.
+func (s *Store) Unblock(contentID int64, guid string) error {
+	result, err := s.db.Exec(`DELETE FROM blocklist WHERE content_id = ? AND guid = ?`, contentID, guid)
+	if err != nil {
+		return fmt.Errorf("unblock release %s for content %d: %w", guid, contentID, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("unblock release %s for content %d: %w", guid, contentID, ErrNotFound)
+	}
+	return nil
+}
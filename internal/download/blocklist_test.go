@@ -0,0 +1,99 @@
+package download
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func TestStore_Block(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Fight Club")
+
+	e := &BlocklistEntry{
+		ContentID:   contentID,
+		GUID:        "nzbgeek-abc123",
+		ReleaseName: "Fight.Club.1999.1080p.BluRay.x264",
+		Indexer:     "nzbgeek",
+		Reason:      "Unpacking failed",
+	}
+	require.NoError(t, store.Block(e))
+	assert.NotZero(t, e.ID)
+
+	blocked, err := store.IsBlocked(contentID, "nzbgeek-abc123")
+	require.NoError(t, err)
+	assert.True(t, blocked)
+
+	// Blocklist is per-content
+	otherID := insertTestContent(t, db, "Se7en")
+	blocked, err = store.IsBlocked(otherID, "nzbgeek-abc123")
+	require.NoError(t, err)
+	assert.False(t, blocked)
+}
+
+func TestStore_Block_Idempotent(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Fight Club")
+
+	e1 := &BlocklistEntry{ContentID: contentID, GUID: "abc", Reason: "first"}
+	require.NoError(t, store.Block(e1))
+
+	e2 := &BlocklistEntry{ContentID: contentID, GUID: "abc", Reason: "second"}
+	require.NoError(t, store.Block(e2))
+	assert.Equal(t, e1.ID, e2.ID, "should return existing entry")
+
+	entries, err := store.ListBlocked(contentID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "first", entries[0].Reason)
+}
+
+func TestStore_Block_RequiresGUID(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Fight Club")
+
+	err := store.Block(&BlocklistEntry{ContentID: contentID})
+	require.Error(t, err)
+}
+
+func TestStore_Unblock(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Fight Club")
+
+	require.NoError(t, store.Block(&BlocklistEntry{ContentID: contentID, GUID: "abc"}))
+	require.NoError(t, store.Unblock(contentID, "abc"))
+
+	blocked, err := store.IsBlocked(contentID, "abc")
+	require.NoError(t, err)
+	assert.False(t, blocked)
+
+	err = store.Unblock(contentID, "abc")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_Add_PersistsGUID(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Fight Club")
+
+	d := &Download{
+		ContentID:   contentID,
+		Client:      ClientSABnzbd,
+		ClientID:    "SABnzbd_nzo_abc123",
+		Status:      StatusQueued,
+		ReleaseName: "Fight.Club.1999.1080p.BluRay.x264",
+		Indexer:     "nzbgeek",
+		GUID:        "nzbgeek-abc123",
+	}
+	require.NoError(t, store.Add(d))
+
+	got, err := store.Get(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "nzbgeek-abc123", got.GUID)
+}
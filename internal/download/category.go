@@ -0,0 +1,61 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Categories maps content types to download client categories.
+// Empty Movie or Series values fall back to Default.
+type Categories struct {
+	Default string
+	Movie   string
+	Series  string
+}
+
+// For returns the category for a content type ("movie" or "series").
+func (c Categories) For(contentType string) string {
+	switch contentType {
+	case "movie":
+		if c.Movie != "" {
+			return c.Movie
+		}
+	case "series":
+		if c.Series != "" {
+			return c.Series
+		}
+	}
+	return c.Default
+}
+
+// SourcePath returns where a completed download is expected under root.
+// SABnzbd places completed jobs in a per-category subdirectory when the
+// category has its own folder, so root/category/release is tried first,
+// falling back to root/release.
+func SourcePath(root string, d *Download) string {
+	flat := filepath.Join(root, d.ReleaseName)
+	if d.Category == "" {
+		return flat
+	}
+	nested := filepath.Join(root, d.Category, d.ReleaseName)
+	if _, err := os.Stat(nested); err == nil {
+		return nested
+	}
+	return flat
+}
+
+// UnderRoot reports whether path is inside root, after resolving both to
+// clean absolute paths. Neither root itself nor /downloads-other is under
+// /downloads, so deleting a path under root never deletes root.
+func UnderRoot(root, path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(absPath, absRoot+string(filepath.Separator))
+}
@@ -0,0 +1,42 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategories_For(t *testing.T) {
+	cats := Categories{Default: "arrgo", Movie: "arrgo-movies", Series: "arrgo-tv"}
+	assert.Equal(t, "arrgo-movies", cats.For("movie"))
+	assert.Equal(t, "arrgo-tv", cats.For("series"))
+	assert.Equal(t, "arrgo", cats.For(""))
+
+	// Unset per-type categories fall back to the default
+	cats = Categories{Default: "arrgo"}
+	assert.Equal(t, "arrgo", cats.For("movie"))
+	assert.Equal(t, "arrgo", cats.For("series"))
+}
+
+func TestSourcePath(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "arrgo-movies", "Movie.2024.1080p"), 0o755))
+
+	tests := []struct {
+		name string
+		d    *Download
+		want string
+	}{
+		{"no category", &Download{ReleaseName: "Movie.2024.1080p"}, filepath.Join(root, "Movie.2024.1080p")},
+		{"category subdirectory", &Download{ReleaseName: "Movie.2024.1080p", Category: "arrgo-movies"}, filepath.Join(root, "arrgo-movies", "Movie.2024.1080p")},
+		{"category without subdirectory", &Download{ReleaseName: "Show.S01E01", Category: "arrgo-tv"}, filepath.Join(root, "Show.S01E01")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SourcePath(root, tt.d))
+		})
+	}
+}
@@ -8,9 +8,13 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/vmunix/arrgo/pkg/release"
 )
 
-// Client is a download client (SABnzbd, qBittorrent, etc.)
+// Client names the download client that handled a download. With multiple
+// clients configured this is the client's configured name; the legacy single
+// SABnzbd setup uses ClientSABnzbd.
 type Client string
 
 const (
@@ -31,6 +35,10 @@ const (
 	StatusImported    Status = "imported"
 	StatusCleaned     Status = "cleaned"
 	StatusSkipped     Status = "skipped" // Duplicate detected, import skipped
+
+	// StatusPartiallyImported is a season pack where some episodes failed to
+	// import. The source is kept so the failed episodes can be reimported.
+	StatusPartiallyImported Status = "partially_imported"
 )
 
 // Download represents an active or recent download.
@@ -45,7 +53,11 @@ type Download struct {
 	ClientID         string // ID in the download client
 	Status           Status
 	ReleaseName      string
+	ReleaseGroup     string // Parsed from ReleaseName by Add unless set (empty if none)
 	Indexer          string
+	GUID             string // Release GUID from the indexer (empty if unknown)
+	Category         string // Download client category (empty for the client default)
+	LastError        string // Why the download failed (empty unless it failed)
 	AddedAt          time.Time
 	CompletedAt      *time.Time
 	LastTransitionAt time.Time
@@ -58,15 +70,58 @@ type Download struct {
 
 // Filter specifies criteria for listing downloads.
 type Filter struct {
+	IDs       []int64 // Only these download IDs; empty means any
 	ContentID *int64
 	EpisodeID *int64
 	Status    *Status
 	Client    *Client
 	Active    bool // If true, exclude terminal states (cleaned, failed)
+	Sort      Sort // Default: ID
+	Desc      bool // Reverse the sort
 	Limit     int  // Maximum number of results (0 = unlimited)
 	Offset    int  // Number of results to skip
 }
 
+// Sort orders listed downloads. Downloads that sort equal are ordered by ID,
+// so pages of a sorted list neither repeat nor skip downloads.
+type Sort string
+
+const (
+	SortAddedAt     Sort = "added_at"
+	SortCompletedAt Sort = "completed_at" // Downloads not completed sort first, or last if Desc
+	SortStatus      Sort = "status"
+)
+
+// sortColumns maps each sort to the column it orders by. Only these reach the
+// query, so a sort from a request cannot name another column.
+var sortColumns = map[Sort]string{
+	SortAddedAt:     "added_at",
+	SortCompletedAt: "completed_at",
+	SortStatus:      "status",
+}
+
+// ValidSort reports whether s is a sort List accepts.
+func ValidSort(s Sort) bool {
+	_, ok := sortColumns[s]
+	return ok
+}
+
+// orderBy returns the ORDER BY clause for the filter's sort.
+func (f Filter) orderBy() (string, error) {
+	dir := "ASC"
+	if f.Desc {
+		dir = "DESC"
+	}
+	if f.Sort == "" {
+		return " ORDER BY id " + dir, nil
+	}
+	column, ok := sortColumns[f.Sort]
+	if !ok {
+		return "", fmt.Errorf("unknown download sort %q", f.Sort)
+	}
+	return " ORDER BY " + column + " " + dir + ", id " + dir, nil
+}
+
 // ClientStatus is the status from a download client.
 type ClientStatus struct {
 	ID       string
@@ -77,6 +132,7 @@ type ClientStatus struct {
 	Speed    int64 // bytes/sec
 	ETA      time.Duration
 	Path     string // Completed download path
+	Error    string // Why the client failed the download (empty unless failed)
 }
 
 // Downloader sends items to download clients.
@@ -111,6 +167,10 @@ func NewStore(db *sql.DB) *Store {
 // This method is idempotent: if a download with the same content_id and release_name
 // already exists, it returns the existing record's ID instead of creating a duplicate.
 func (s *Store) Add(d *Download) error {
+	if d.ReleaseGroup == "" {
+		d.ReleaseGroup = release.ParseGroup(d.ReleaseName)
+	}
+
 	// Check for existing download with same content_id and release_name
 	var existingID int64
 	var existingAddedAt time.Time
@@ -128,14 +188,32 @@ func (s *Store) Add(d *Download) error {
 			return fmt.Errorf("get existing status: %w", err)
 		}
 
+		if existingStatus == StatusFailed && d.Status == StatusFailed {
+			// Failed again before reaching the client: keep it failed with the new error
+			now := time.Now()
+			_, updateErr := s.db.Exec(`
+				UPDATE downloads
+				SET client = ?, client_id = ?, last_error = ?, last_transition_at = ?
+				WHERE id = ?`,
+				d.Client, d.ClientID, d.LastError, now, existingID,
+			)
+			if updateErr != nil {
+				return fmt.Errorf("update existing download: %w", updateErr)
+			}
+			d.ID = existingID
+			d.AddedAt = existingAddedAt
+			d.LastTransitionAt = now
+			return nil
+		}
+
 		if existingStatus == StatusFailed {
 			// Retry scenario: update with new client info and reset status to queued
 			now := time.Now()
 			_, updateErr := s.db.Exec(`
 				UPDATE downloads
-				SET client_id = ?, status = ?, last_transition_at = ?
+				SET client = ?, client_id = ?, category = ?, status = ?, last_error = '', last_transition_at = ?
 				WHERE id = ?`,
-				d.ClientID, StatusQueued, now, existingID,
+				d.Client, d.ClientID, d.Category, StatusQueued, now, existingID,
 			)
 			if updateErr != nil {
 				return fmt.Errorf("update existing download: %w", updateErr)
@@ -159,9 +237,9 @@ func (s *Store) Add(d *Download) error {
 	// No existing record, insert new one
 	now := time.Now()
 	result, err := s.db.Exec(`
-		INSERT INTO downloads (content_id, episode_id, client, client_id, status, release_name, indexer, added_at, completed_at, last_transition_at, season, is_complete_season)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		d.ContentID, d.EpisodeID, d.Client, d.ClientID, d.Status, d.ReleaseName, d.Indexer, now, d.CompletedAt, now, d.Season, d.IsCompleteSeason,
+		INSERT INTO downloads (content_id, episode_id, client, client_id, status, release_name, release_group, indexer, guid, category, last_error, added_at, completed_at, last_transition_at, season, is_complete_season)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.ContentID, d.EpisodeID, d.Client, d.ClientID, d.Status, d.ReleaseName, d.ReleaseGroup, d.Indexer, d.GUID, d.Category, d.LastError, now, d.CompletedAt, now, d.Season, d.IsCompleteSeason,
 	)
 	if err != nil {
 		return fmt.Errorf("insert download: %w", err)
@@ -183,9 +261,9 @@ func (s *Store) Add(d *Download) error {
 func (s *Store) Get(id int64) (*Download, error) {
 	d := &Download{}
 	err := s.db.QueryRow(`
-		SELECT id, content_id, episode_id, client, client_id, status, release_name, indexer, added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds, size_bytes
+		SELECT id, content_id, episode_id, client, client_id, status, release_name, release_group, indexer, added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds, size_bytes, guid, category, last_error
 		FROM downloads WHERE id = ?`, id,
-	).Scan(&d.ID, &d.ContentID, &d.EpisodeID, &d.Client, &d.ClientID, &d.Status, &d.ReleaseName, &d.Indexer, &d.AddedAt, &d.CompletedAt, &d.LastTransitionAt, &d.Season, &d.IsCompleteSeason, &d.Progress, &d.Speed, &d.ETASeconds, &d.Size)
+	).Scan(&d.ID, &d.ContentID, &d.EpisodeID, &d.Client, &d.ClientID, &d.Status, &d.ReleaseName, &d.ReleaseGroup, &d.Indexer, &d.AddedAt, &d.CompletedAt, &d.LastTransitionAt, &d.Season, &d.IsCompleteSeason, &d.Progress, &d.Speed, &d.ETASeconds, &d.Size, &d.GUID, &d.Category, &d.LastError)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("get download %d: %w", id, ErrNotFound)
@@ -208,9 +286,9 @@ func (s *Store) Get(id int64) (*Download, error) {
 func (s *Store) GetByClientID(client Client, clientID string) (*Download, error) {
 	d := &Download{}
 	err := s.db.QueryRow(`
-		SELECT id, content_id, episode_id, client, client_id, status, release_name, indexer, added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds, size_bytes
+		SELECT id, content_id, episode_id, client, client_id, status, release_name, release_group, indexer, added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds, size_bytes, guid, category, last_error
 		FROM downloads WHERE client = ? AND client_id = ?`, client, clientID,
-	).Scan(&d.ID, &d.ContentID, &d.EpisodeID, &d.Client, &d.ClientID, &d.Status, &d.ReleaseName, &d.Indexer, &d.AddedAt, &d.CompletedAt, &d.LastTransitionAt, &d.Season, &d.IsCompleteSeason, &d.Progress, &d.Speed, &d.ETASeconds, &d.Size)
+	).Scan(&d.ID, &d.ContentID, &d.EpisodeID, &d.Client, &d.ClientID, &d.Status, &d.ReleaseName, &d.ReleaseGroup, &d.Indexer, &d.AddedAt, &d.CompletedAt, &d.LastTransitionAt, &d.Season, &d.IsCompleteSeason, &d.Progress, &d.Speed, &d.ETASeconds, &d.Size, &d.GUID, &d.Category, &d.LastError)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("get download by client %s/%s: %w", client, clientID, ErrNotFound)
@@ -251,12 +329,53 @@ func (s *Store) Update(d *Download) error {
 }
 
 // Transition changes a download's status with validation and event emission.
+// The status is re-read and swapped in one compare-and-swap, so concurrent
+// callers holding stale copies of d cannot both apply the same transition;
+// the loser gets ErrInvalidTransition against the status stored in the
+// database.
 func (s *Store) Transition(d *Download, to Status) error {
 	if !d.Status.CanTransitionTo(to) {
 		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, d.Status, to)
 	}
 
-	from := d.Status
+	var from Status
+	err := s.db.QueryRow("SELECT status FROM downloads WHERE id = ?", d.ID).Scan(&from)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("transition download %d: %w", d.ID, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("get download %d status: %w", d.ID, err)
+	}
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+
+	won, err := s.swapStatus(d, from, to)
+	if err != nil {
+		return err
+	}
+	if !won {
+		return fmt.Errorf("%w: %s -> %s: status changed concurrently", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// Claim moves a download from the status in d to another in one
+// compare-and-swap: it wins only if the stored status is still d.Status.
+// Callers that must not both act on a download, such as the import pipeline
+// and the import API taking a completed download, claim it before doing any
+// work. Returns false, with no error, if another caller changed the status
+// first or the download is gone.
+func (s *Store) Claim(d *Download, to Status) (bool, error) {
+	if !d.Status.CanTransitionTo(to) {
+		return false, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, d.Status, to)
+	}
+	return s.swapStatus(d, d.Status, to)
+}
+
+// swapStatus sets a download's status to to if it is still from, updating d
+// and emitting the transition event. Reports whether it did.
+func (s *Store) swapStatus(d *Download, from, to Status) (bool, error) {
 	now := time.Now()
 
 	// Set completed_at for terminal and completion states
@@ -267,19 +386,18 @@ func (s *Store) Transition(d *Download, to Status) error {
 
 	result, err := s.db.Exec(`
 		UPDATE downloads SET status = ?, last_transition_at = ?, completed_at = COALESCE(?, completed_at)
-		WHERE id = ?`,
-		to, now, completedAt, d.ID,
+		WHERE id = ? AND status = ?`,
+		to, now, completedAt, d.ID, from,
 	)
 	if err != nil {
-		return fmt.Errorf("update download %d: %w", d.ID, err)
+		return false, fmt.Errorf("update download %d: %w", d.ID, err)
 	}
-
 	rows, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("rows affected: %w", err)
+		return false, fmt.Errorf("rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("transition download %d: %w", d.ID, ErrNotFound)
+		return false, nil
 	}
 
 	d.Status = to
@@ -299,7 +417,7 @@ func (s *Store) Transition(d *Download, to Status) error {
 		h(event)
 	}
 
-	return nil
+	return true, nil
 }
 
 // List returns downloads matching the specified filter.
@@ -307,9 +425,15 @@ func (s *Store) Transition(d *Download, to Status) error {
 // Returns the matching downloads and total count (before pagination).
 func (s *Store) List(f Filter) ([]*Download, int, error) {
 	// Pre-allocate with capacity for potential filter conditions
-	conditions := make([]string, 0, 5)
-	args := make([]any, 0, 6)
+	conditions := make([]string, 0, 6)
+	args := make([]any, 0, 6+len(f.IDs))
 
+	if len(f.IDs) > 0 {
+		conditions = append(conditions, "id IN ("+strings.TrimSuffix(strings.Repeat("?,", len(f.IDs)), ",")+")")
+		for _, id := range f.IDs {
+			args = append(args, id)
+		}
+	}
 	if f.ContentID != nil {
 		conditions = append(conditions, "content_id = ?")
 		args = append(args, *f.ContentID)
@@ -335,6 +459,10 @@ func (s *Store) List(f Filter) ([]*Download, int, error) {
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
+	orderBy, err := f.orderBy()
+	if err != nil {
+		return nil, 0, fmt.Errorf("list downloads: %w", err)
+	}
 
 	// Get total count first
 	// G202: False positive - whereClause contains only "col = ?" conditions,
@@ -346,9 +474,10 @@ func (s *Store) List(f Filter) ([]*Download, int, error) {
 	}
 
 	// G202: False positive - whereClause contains only "col = ?" conditions,
-	// actual values are passed via args parameter (parameterized query).
-	query := "SELECT id, content_id, episode_id, client, client_id, status, release_name, indexer, added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds, size_bytes FROM downloads " + //nolint:gosec
-		whereClause + " ORDER BY id"
+	// actual values are passed via args parameter (parameterized query), and
+	// orderBy only columns from sortColumns.
+	query := "SELECT id, content_id, episode_id, client, client_id, status, release_name, release_group, indexer, added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds, size_bytes, guid, category, last_error FROM downloads " + //nolint:gosec
+		whereClause + orderBy
 
 	// Add LIMIT/OFFSET if specified
 	if f.Limit > 0 {
@@ -364,7 +493,7 @@ func (s *Store) List(f Filter) ([]*Download, int, error) {
 	var results []*Download
 	for rows.Next() {
 		d := &Download{}
-		if err := rows.Scan(&d.ID, &d.ContentID, &d.EpisodeID, &d.Client, &d.ClientID, &d.Status, &d.ReleaseName, &d.Indexer, &d.AddedAt, &d.CompletedAt, &d.LastTransitionAt, &d.Season, &d.IsCompleteSeason, &d.Progress, &d.Speed, &d.ETASeconds, &d.Size); err != nil {
+		if err := rows.Scan(&d.ID, &d.ContentID, &d.EpisodeID, &d.Client, &d.ClientID, &d.Status, &d.ReleaseName, &d.ReleaseGroup, &d.Indexer, &d.AddedAt, &d.CompletedAt, &d.LastTransitionAt, &d.Season, &d.IsCompleteSeason, &d.Progress, &d.Speed, &d.ETASeconds, &d.Size, &d.GUID, &d.Category, &d.LastError); err != nil {
 			return nil, 0, fmt.Errorf("scan download: %w", err)
 		}
 		// Note: EpisodeIDs not loaded for List() performance - use Get() for full details
@@ -491,7 +620,7 @@ func (s *Store) ListStuck(thresholds map[Status]time.Duration) ([]*Download, err
 	// actual values are passed via args parameter (parameterized query).
 	whereClause := strings.Join(conditions, " OR ")
 	//nolint:gosec // G201: whereClause is built from hardcoded conditions, not user input
-	query := fmt.Sprintf(`SELECT id, content_id, episode_id, client, client_id, status, release_name, indexer, added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds, size_bytes
+	query := fmt.Sprintf(`SELECT id, content_id, episode_id, client, client_id, status, release_name, release_group, indexer, added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds, size_bytes, guid, category, last_error
 		FROM downloads WHERE %s ORDER BY last_transition_at`, whereClause)
 
 	rows, err := s.db.Query(query, args...)
@@ -503,7 +632,7 @@ func (s *Store) ListStuck(thresholds map[Status]time.Duration) ([]*Download, err
 	var results []*Download
 	for rows.Next() {
 		d := &Download{}
-		if err := rows.Scan(&d.ID, &d.ContentID, &d.EpisodeID, &d.Client, &d.ClientID, &d.Status, &d.ReleaseName, &d.Indexer, &d.AddedAt, &d.CompletedAt, &d.LastTransitionAt, &d.Season, &d.IsCompleteSeason, &d.Progress, &d.Speed, &d.ETASeconds, &d.Size); err != nil {
+		if err := rows.Scan(&d.ID, &d.ContentID, &d.EpisodeID, &d.Client, &d.ClientID, &d.Status, &d.ReleaseName, &d.ReleaseGroup, &d.Indexer, &d.AddedAt, &d.CompletedAt, &d.LastTransitionAt, &d.Season, &d.IsCompleteSeason, &d.Progress, &d.Speed, &d.ETASeconds, &d.Size, &d.GUID, &d.Category, &d.LastError); err != nil {
 			return nil, fmt.Errorf("scan download: %w", err)
 		}
 		// Note: EpisodeIDs not loaded for ListStuck() performance - use Get() for full details
@@ -525,3 +654,68 @@ func (s *Store) UpdateProgress(id int64, progress float64, speed, etaSeconds, si
 	}
 	return nil
 }
+
+// SetLastError records why a download failed.
+func (s *Store) SetLastError(id int64, msg string) error {
+	result, err := s.db.Exec(`UPDATE downloads SET last_error = ? WHERE id = ?`, msg, id)
+	if err != nil {
+		return fmt.Errorf("set last error for download %d: %w", id, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("set last error for download %d: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// SetReleaseName records the name the download client gave a download's job,
+// which is where its files complete. The release group parsed at grab time is kept.
+func (s *Store) SetReleaseName(id int64, name string) error {
+	result, err := s.db.Exec(`UPDATE downloads SET release_name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		return fmt.Errorf("set release name for download %d: %w", id, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("set release name for download %d: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// BackfillReleaseGroups parses the release group of downloads recorded
+// before groups were stored. Returns how many downloads got a group.
+func (s *Store) BackfillReleaseGroups() (int, error) {
+	rows, err := s.db.Query("SELECT id, release_name FROM downloads WHERE release_group = ''")
+	if err != nil {
+		return 0, fmt.Errorf("list downloads to backfill: %w", err)
+	}
+	groups := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("scan download: %w", err)
+		}
+		if group := release.ParseGroup(name); group != "" {
+			groups[id] = group
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate downloads: %w", err)
+	}
+
+	for id, group := range groups {
+		if _, err := s.db.Exec("UPDATE downloads SET release_group = ? WHERE id = ?", group, id); err != nil {
+			return 0, fmt.Errorf("backfill download %d: %w", id, err)
+		}
+	}
+	return len(groups), nil
+}
@@ -0,0 +1,79 @@
+package download
+
+import (
+	"fmt"
+	"time"
+)
+
+// EpisodeResult is the recorded outcome of importing one file of a season
+// pack. Results are kept per source file so a reimport can skip files that
+// were already imported and retry only the ones that failed.
+type EpisodeResult struct {
+	DownloadID int64
+	SourceFile string // Path of the video relative to the download folder
+	EpisodeID  *int64 // Nil if the file could not be matched to an episode
+	Season     int
+	Episode    int
+	Success    bool
+	FilePath   string // Library path (empty if failed)
+	Error      string // Why the import failed (empty if success)
+	UpdatedAt  time.Time
+}
+
+// SetEpisodeResults replaces the recorded episode outcomes of a download.
+func (s *Store) SetEpisodeResults(downloadID int64, results []*EpisodeResult) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec("DELETE FROM download_episode_results WHERE download_id = ?", downloadID); err != nil {
+		return fmt.Errorf("delete episode results for download %d: %w", downloadID, err)
+	}
+
+	now := time.Now()
+	for _, r := range results {
+		if _, err := tx.Exec(`
+			INSERT INTO download_episode_results
+				(download_id, source_file, episode_id, season, episode, success, file_path, error, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			downloadID, r.SourceFile, r.EpisodeID, r.Season, r.Episode, r.Success, r.FilePath, r.Error, now,
+		); err != nil {
+			return fmt.Errorf("insert episode result %s for download %d: %w", r.SourceFile, downloadID, err)
+		}
+		r.DownloadID = downloadID
+		r.UpdatedAt = now
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// EpisodeResults returns the recorded episode outcomes of a download, ordered
+// by season and episode. Downloads that are not season packs have none.
+func (s *Store) EpisodeResults(downloadID int64) ([]*EpisodeResult, error) {
+	rows, err := s.db.Query(`
+		SELECT download_id, source_file, episode_id, season, episode, success, file_path, error, updated_at
+		FROM download_episode_results WHERE download_id = ?
+		ORDER BY season, episode, source_file`, downloadID)
+	if err != nil {
+		return nil, fmt.Errorf("list episode results for download %d: %w", downloadID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []*EpisodeResult
+	for rows.Next() {
+		r := &EpisodeResult{}
+		if err := rows.Scan(&r.DownloadID, &r.SourceFile, &r.EpisodeID, &r.Season, &r.Episode, &r.Success, &r.FilePath, &r.Error, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan episode result: %w", err)
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate episode results: %w", err)
+	}
+	return results, nil
+}
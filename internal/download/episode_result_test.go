@@ -0,0 +1,54 @@
+package download
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func TestStore_SetEpisodeResults(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Breaking Bad")
+
+	d := &Download{
+		ContentID:   contentID,
+		Client:      ClientSABnzbd,
+		ClientID:    "SABnzbd_nzo_pack",
+		Status:      StatusImporting,
+		ReleaseName: "Breaking.Bad.S01.1080p.BluRay.x264",
+	}
+	require.NoError(t, store.Add(d))
+
+	results := []*EpisodeResult{
+		{SourceFile: "Breaking.Bad.S01E02.mkv", Season: 1, Episode: 2, Error: "copy file: disk full"},
+		{SourceFile: "Breaking.Bad.S01E01.mkv", Season: 1, Episode: 1, Success: true, FilePath: "/tv/Breaking Bad/S01E01.mkv"},
+	}
+	require.NoError(t, store.SetEpisodeResults(d.ID, results))
+
+	got, err := store.EpisodeResults(d.ID)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, 1, got[0].Episode, "ordered by season and episode")
+	assert.True(t, got[0].Success)
+	assert.Equal(t, "/tv/Breaking Bad/S01E01.mkv", got[0].FilePath)
+	assert.Nil(t, got[0].EpisodeID)
+	assert.Equal(t, "copy file: disk full", got[1].Error)
+	assert.False(t, got[1].UpdatedAt.IsZero())
+
+	// Recording again replaces the previous outcome
+	require.NoError(t, store.SetEpisodeResults(d.ID, []*EpisodeResult{
+		{SourceFile: "Breaking.Bad.S01E02.mkv", Season: 1, Episode: 2, Success: true, FilePath: "/tv/Breaking Bad/S01E02.mkv"},
+	}))
+	got, err = store.EpisodeResults(d.ID)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.True(t, got[0].Success)
+
+	// Other downloads have none
+	got, err = store.EpisodeResults(d.ID + 1)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -1,6 +1,10 @@
 package download
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // Sentinel errors for the download package.
 var (
@@ -18,4 +22,44 @@ var (
 
 	// ErrInvalidTransition is returned when an invalid state transition is attempted.
 	ErrInvalidTransition = errors.New("invalid state transition")
+
+	// ErrUnknownClient is returned when a download names a client that isn't configured.
+	ErrUnknownClient = errors.New("unknown download client")
+
+	// ErrNoClient is returned when no configured client handles a release's protocol.
+	ErrNoClient = errors.New("no download client for protocol")
+
+	// ErrUnsupported is returned when the download client does not support an operation.
+	ErrUnsupported = errors.New("operation not supported by download client")
 )
+
+// StatusError is returned when a download client responds with an unexpected HTTP status.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status: %d", e.StatusCode)
+}
+
+// missingArticlePhrases appear in client failure messages when the usenet
+// servers no longer have all of a release's articles.
+var missingArticlePhrases = []string{
+	"missing articles",
+	"cannot be completed",
+	"not enough repair blocks",
+	"articles were missing",
+}
+
+// IsMissingArticles reports whether a client failure reason means the
+// release is incomplete on the servers. Grabbing the same NZB again would
+// fail the same way, so the next-best release should be tried instead.
+func IsMissingArticles(reason string) bool {
+	lower := strings.ToLower(reason)
+	for _, phrase := range missingArticlePhrases {
+		if strings.Contains(lower, phrase) {
+			return true
+		}
+	}
+	return false
+}
@@ -20,3 +20,11 @@ func TestErrors(t *testing.T) {
 		assert.NotEmpty(t, err.Error(), "error %v should have a message", err)
 	}
 }
+
+func TestIsMissingArticles(t *testing.T) {
+	assert.True(t, IsMissingArticles("Aborted, cannot be completed - https://sabnzbd.org/not-complete"))
+	assert.True(t, IsMissingArticles("Repair: Repair failed, not enough repair blocks (12 short)"))
+	assert.True(t, IsMissingArticles("Download failed - Missing articles"))
+	assert.False(t, IsMissingArticles("Unpacking failed, write error or disk is full?"))
+	assert.False(t, IsMissingArticles(""))
+}
@@ -0,0 +1,86 @@
+package download
+
+import (
+	"fmt"
+	"time"
+)
+
+// QueuedImport is a completed download waiting for an import worker.
+type QueuedImport struct {
+	DownloadID int64
+	SourcePath string // Where the client reported the files; empty if unknown
+	QueuedAt   time.Time
+}
+
+// QueueImport adds a download to the back of the import queue. Queuing a
+// download already queued keeps its place, and updates its source path if
+// sourcePath is not empty.
+func (s *Store) QueueImport(downloadID int64, sourcePath string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO import_queue (download_id, position, source_path, queued_at)
+		VALUES (?, (SELECT COALESCE(MAX(position), 0) + 1 FROM import_queue), ?, ?)
+		ON CONFLICT(download_id) DO UPDATE SET
+			source_path = CASE WHEN excluded.source_path = '' THEN source_path ELSE excluded.source_path END`,
+		downloadID, sourcePath, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("queue import of download %d: %w", downloadID, err)
+	}
+	return nil
+}
+
+// PrioritizeImport moves a queued download to the front of the import queue.
+// Returns ErrNotFound if the download is not queued.
+func (s *Store) PrioritizeImport(downloadID int64) error {
+	result, err := s.db.Exec(`
+		UPDATE import_queue SET position = (SELECT MIN(position) - 1 FROM import_queue)
+		WHERE download_id = ?`, downloadID)
+	if err != nil {
+		return fmt.Errorf("prioritize import of download %d: %w", downloadID, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("prioritize import of download %d: %w", downloadID, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("prioritize import of download %d: %w", downloadID, ErrNotFound)
+	}
+	return nil
+}
+
+// DequeueImport removes a download from the import queue. Removing a download
+// that is not queued is not an error.
+func (s *Store) DequeueImport(downloadID int64) error {
+	if _, err := s.db.Exec("DELETE FROM import_queue WHERE download_id = ?", downloadID); err != nil {
+		return fmt.Errorf("dequeue import of download %d: %w", downloadID, err)
+	}
+	return nil
+}
+
+// ImportQueue returns the queued imports in the order they will run. Entries
+// whose download is no longer completed, such as one being imported, are left
+// out.
+func (s *Store) ImportQueue() ([]QueuedImport, error) {
+	rows, err := s.db.Query(`
+		SELECT q.download_id, q.source_path, q.queued_at
+		FROM import_queue q JOIN downloads d ON d.id = q.download_id
+		WHERE d.status = ?
+		ORDER BY q.position, q.download_id`, StatusCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("list import queue: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var queue []QueuedImport
+	for rows.Next() {
+		var q QueuedImport
+		if err := rows.Scan(&q.DownloadID, &q.SourcePath, &q.QueuedAt); err != nil {
+			return nil, fmt.Errorf("scan queued import: %w", err)
+		}
+		queue = append(queue, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate import queue: %w", err)
+	}
+	return queue, nil
+}
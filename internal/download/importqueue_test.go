@@ -0,0 +1,67 @@
+package download
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func TestStore_ImportQueue(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	var ids []int64
+	for _, title := range []string{"Fight Club", "Se7en", "Zodiac"} {
+		d := &Download{
+			ContentID:   insertTestContent(t, db, title),
+			Client:      ClientSABnzbd,
+			ClientID:    "nzo_" + title,
+			Status:      StatusCompleted,
+			ReleaseName: title + ".1080p",
+			Indexer:     "nzbgeek",
+		}
+		require.NoError(t, store.Add(d))
+		require.NoError(t, store.QueueImport(d.ID, "/downloads/"+title))
+		ids = append(ids, d.ID)
+	}
+
+	queue, err := store.ImportQueue()
+	require.NoError(t, err)
+	require.Len(t, queue, 3)
+	assert.Equal(t, ids[0], queue[0].DownloadID, "first queued imports first")
+	assert.Equal(t, "/downloads/Fight Club", queue[0].SourcePath)
+	assert.False(t, queue[0].QueuedAt.IsZero())
+
+	// Queuing again keeps the place, and an unknown path keeps the known one
+	require.NoError(t, store.QueueImport(ids[0], ""))
+	require.NoError(t, store.QueueImport(ids[1], "/downloads/moved"))
+	queue, err = store.ImportQueue()
+	require.NoError(t, err)
+	require.Len(t, queue, 3)
+	assert.Equal(t, ids[0], queue[0].DownloadID)
+	assert.Equal(t, "/downloads/Fight Club", queue[0].SourcePath)
+	assert.Equal(t, "/downloads/moved", queue[1].SourcePath)
+
+	require.NoError(t, store.PrioritizeImport(ids[2]))
+	queue, err = store.ImportQueue()
+	require.NoError(t, err)
+	assert.Equal(t, ids[2], queue[0].DownloadID, "prioritized import goes first")
+
+	// Downloads no longer completed are not waiting
+	d, err := store.Get(ids[1])
+	require.NoError(t, err)
+	require.NoError(t, store.Transition(d, StatusImporting))
+	queue, err = store.ImportQueue()
+	require.NoError(t, err)
+	assert.Len(t, queue, 2)
+
+	require.NoError(t, store.DequeueImport(ids[2]))
+	require.NoError(t, store.DequeueImport(ids[2]), "dequeue is idempotent")
+	require.ErrorIs(t, store.PrioritizeImport(ids[2]), ErrNotFound)
+	queue, err = store.ImportQueue()
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, ids[0], queue[0].DownloadID)
+}
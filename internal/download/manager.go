@@ -2,48 +2,252 @@ package download
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"strings"
+	"time"
 )
 
+// Protocol is the transfer protocol of a release.
+type Protocol string
+
+const (
+	ProtocolUsenet  Protocol = "usenet"
+	ProtocolTorrent Protocol = "torrent"
+)
+
+// ProtocolForURL infers a release's protocol from its download URL.
+// Magnet links and .torrent files are torrents; everything else is treated as an NZB.
+func ProtocolForURL(downloadURL string) Protocol {
+	u, err := url.Parse(downloadURL)
+	if err != nil {
+		return ProtocolUsenet
+	}
+	if strings.EqualFold(u.Scheme, "magnet") || strings.HasSuffix(strings.ToLower(u.Path), ".torrent") {
+		return ProtocolTorrent
+	}
+	return ProtocolUsenet
+}
+
+// ClientInfo describes a download client registered with the Manager.
+type ClientInfo struct {
+	Name     Client
+	Protocol Protocol
+}
+
 // ActiveDownload combines database record with live client status.
 type ActiveDownload struct {
 	Download *Download
 	Live     *ClientStatus
+	LiveAt   time.Time // When Live was fetched; up to the status cache TTL ago
 }
 
 // Manager provides download client operations for API endpoints.
+// It holds one or more named clients and dispatches to the client
+// recorded on each download. New grabs are routed by protocol.
 // Note: Grab and status polling are handled by the event-driven architecture
 // (DownloadHandler and SABnzbd adapter). Manager is retained for:
 // - Cancel: removing downloads from client and database
-// - Client: accessing the download client for live status queries
+// - Status: querying a download's client for live status
 // - GetActive: listing active downloads with live status
+// - Route: choosing the client for a new grab
+// - Add: sending a grab to a client, retrying transient errors
+// - SetSpeedLimit: passing speed limits through to clients that support them
+//
+// Live statuses from Status and GetActive are cached for a few seconds and
+// shared between concurrent callers, so polling API consumers don't each
+// query the clients.
 type Manager struct {
-	client Downloader
-	store  *Store
-	log    *slog.Logger
+	clients  map[Client]Downloader
+	infos    []ClientInfo        // Registration order
+	routes   map[Protocol]Client // First client registered per protocol
+	retry    RetryPolicy
+	store    *Store
+	log      *slog.Logger
+	statuses statusCache
+	uploads  *Uploads
 }
 
-// NewManager creates a new download manager.
-func NewManager(client Downloader, store *Store, log *slog.Logger) *Manager {
+// NewManager creates a download manager with no clients; register them with AddClient.
+func NewManager(store *Store, log *slog.Logger) *Manager {
 	return &Manager{
-		client: client,
-		store:  store,
-		log:    log,
+		clients:  make(map[Client]Downloader),
+		routes:   make(map[Protocol]Client),
+		retry:    DefaultRetryPolicy(),
+		store:    store,
+		log:      log,
+		statuses: statusCache{ttl: DefaultStatusCacheTTL},
+	}
+}
+
+// AddClient registers a named client. The first client added for a protocol
+// receives new grabs of that protocol.
+func (m *Manager) AddClient(name Client, protocol Protocol, client Downloader) {
+	if _, exists := m.clients[name]; !exists {
+		m.infos = append(m.infos, ClientInfo{Name: name, Protocol: protocol})
+	}
+	m.clients[name] = client
+	if _, ok := m.routes[protocol]; !ok {
+		m.routes[protocol] = name
+	}
+}
+
+// SetRetryPolicy sets how Add and Status retry transient client errors.
+func (m *Manager) SetRetryPolicy(p RetryPolicy) {
+	m.retry = p
+}
+
+// SetStatusCacheTTL sets how long live statuses are reused; 0 disables the cache.
+func (m *Manager) SetStatusCacheTTL(ttl time.Duration) {
+	m.statuses.setTTL(ttl)
+}
+
+// SetUploads sets where uploaded release files are kept. Without it, Add
+// refuses upload URLs.
+func (m *Manager) SetUploads(u *Uploads) {
+	m.uploads = u
+}
+
+// Clients returns the registered clients in registration order.
+func (m *Manager) Clients() []ClientInfo {
+	return append([]ClientInfo(nil), m.infos...)
+}
+
+// ClientFor returns the client with the given name.
+func (m *Manager) ClientFor(name Client) (Downloader, error) {
+	client, ok := m.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownClient, name)
+	}
+	return client, nil
+}
+
+// Route returns the client that should receive a release, chosen by its protocol.
+// An empty protocol is inferred from the download URL; indexers that proxy
+// torrent downloads (e.g. Jackett) need the protocol passed explicitly.
+func (m *Manager) Route(downloadURL string, protocol Protocol) (Client, Downloader, error) {
+	if protocol == "" {
+		protocol = ProtocolForURL(downloadURL)
+	}
+	name, ok := m.routes[protocol]
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s (configured clients: %s)", ErrNoClient, protocol, m.describeClients())
+	}
+	return name, m.clients[name], nil
+}
+
+// describeClients lists the registered clients and their protocols for error messages.
+func (m *Manager) describeClients() string {
+	if len(m.infos) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(m.infos))
+	for i, info := range m.infos {
+		parts[i] = fmt.Sprintf("%s (%s)", info.Name, info.Protocol)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Add sends a release to the named client, retrying transient errors per the
+// retry policy. onRetry, if non-nil, is called before each retry.
+// An upload URL (see Uploads) sends the file itself, which the client must
+// support (FileAdder); the upload is removed once the client accepts it.
+// Returns the client's ID for the download.
+func (m *Manager) Add(ctx context.Context, name Client, downloadURL, category string, onRetry func(RetryAttempt)) (string, error) {
+	client, err := m.ClientFor(name)
+	if err != nil {
+		return "", err
+	}
+	add := func() (string, error) { return client.Add(ctx, downloadURL, category) }
+	if IsUploadURL(downloadURL) {
+		if add, err = m.addUpload(ctx, name, client, downloadURL, category); err != nil {
+			return "", err
+		}
+	}
+
+	var clientID string
+	err = m.retry.Do(ctx, func() error {
+		var addErr error
+		clientID, addErr = add()
+		return addErr
+	}, func(a RetryAttempt) {
+		if m.log != nil {
+			m.log.Warn("download client add failed, retrying",
+				"client", name, "attempt", a.Attempt, "max_attempts", a.MaxAttempts, "retry_in", a.Delay, "error", a.Err)
+		}
+		if onRetry != nil {
+			onRetry(a)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	m.statuses.invalidate()
+	if IsUploadURL(downloadURL) {
+		if err := m.uploads.Remove(downloadURL); err != nil && m.log != nil {
+			m.log.Warn("failed to remove upload", "error", err)
+		}
+	}
+	return clientID, nil
+}
+
+// addUpload reads a saved upload and returns the call that sends it to client.
+func (m *Manager) addUpload(ctx context.Context, name Client, client Downloader, uploadURL, category string) (func() (string, error), error) {
+	adder, ok := client.(FileAdder)
+	if !ok {
+		return nil, fmt.Errorf("%s can't take uploaded files: %w", name, ErrUnsupported)
+	}
+	if m.uploads == nil {
+		return nil, fmt.Errorf("uploads not configured: %w", ErrUnsupported)
+	}
+	filename, data, err := m.uploads.Read(uploadURL)
+	if err != nil {
+		return nil, err
 	}
+	return func() (string, error) { return adder.AddFile(ctx, filename, data, category) }, nil
 }
 
-// Cancel removes a download from the client and database.
+// Status returns live status for a download from the client that handled it,
+// retrying transient errors per the retry policy. The status may be cached.
+func (m *Manager) Status(ctx context.Context, d *Download) (*ClientStatus, error) {
+	client, err := m.ClientFor(d.Client)
+	if err != nil {
+		return nil, err
+	}
+	status, _, err := m.statuses.get(ctx, statusKey{d.Client, d.ClientID}, func(ctx context.Context) (*ClientStatus, error) {
+		var status *ClientStatus
+		err := m.retry.Do(ctx, func() error {
+			var statusErr error
+			status, statusErr = client.Status(ctx, d.ClientID)
+			return statusErr
+		}, nil)
+		return status, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Cancel removes a download from its client and the database.
 func (m *Manager) Cancel(ctx context.Context, downloadID int64, deleteFiles bool) error {
 	d, err := m.store.Get(downloadID)
 	if err != nil {
 		return fmt.Errorf("get download: %w", err)
 	}
 
-	// Remove from client (best effort - may already be gone)
-	_ = m.client.Remove(ctx, d.ClientID, deleteFiles)
+	// Remove from client (best effort - may already be gone, or a manual import with no client)
+	if client, err := m.ClientFor(d.Client); err == nil {
+		_ = client.Remove(ctx, d.ClientID, deleteFiles)
+	} else if d.Client != ClientManual {
+		m.log.Warn("download client not configured, removing record only",
+			"download_id", downloadID, "client", d.Client)
+	}
 
 	// Remove from database
+	m.statuses.invalidate()
 	if err := m.store.Delete(downloadID); err != nil {
 		return fmt.Errorf("delete download: %w", err)
 	}
@@ -51,12 +255,31 @@ func (m *Manager) Cancel(ctx context.Context, downloadID int64, deleteFiles bool
 	return nil
 }
 
-// Client returns the underlying download client.
-func (m *Manager) Client() Downloader {
-	return m.client
+// SetSpeedLimit caps each client's total download rate in bytes per second; 0 removes the limit.
+// Clients without speed limit support are skipped. Returns ErrUnsupported if no client supports it.
+func (m *Manager) SetSpeedLimit(ctx context.Context, bytesPerSec int64) error {
+	var errs []error
+	supported := false
+	for _, info := range m.infos {
+		limiter, ok := m.clients[info.Name].(SpeedLimiter)
+		if !ok {
+			continue
+		}
+		supported = true
+		if err := limiter.SetSpeedLimit(ctx, bytesPerSec); err != nil {
+			errs = append(errs, fmt.Errorf("set speed limit on %s: %w", info.Name, err))
+		}
+	}
+	if !supported {
+		return ErrUnsupported
+	}
+	return errors.Join(errs...)
 }
 
-// GetActive returns active downloads with live status from the client.
+// GetActive returns active downloads with live status from their clients.
+// Each client is asked once without retries so listings don't stall while a
+// client is unreachable; such downloads are returned without live status.
+// Live statuses may be cached; LiveAt tells how old each is.
 func (m *Manager) GetActive(ctx context.Context) ([]*ActiveDownload, error) {
 	downloads, _, err := m.store.List(Filter{Active: true})
 	if err != nil {
@@ -65,13 +288,20 @@ func (m *Manager) GetActive(ctx context.Context) ([]*ActiveDownload, error) {
 
 	results := make([]*ActiveDownload, 0, len(downloads))
 	for _, d := range downloads {
-		live, err := m.client.Status(ctx, d.ClientID)
+		client, err := m.ClientFor(d.Client)
+		if err != nil {
+			results = append(results, &ActiveDownload{Download: d})
+			continue
+		}
+		live, liveAt, err := m.statuses.get(ctx, statusKey{d.Client, d.ClientID}, func(ctx context.Context) (*ClientStatus, error) {
+			return client.Status(ctx, d.ClientID)
+		})
 		if err != nil {
 			// Include download without live status
 			results = append(results, &ActiveDownload{Download: d})
 			continue
 		}
-		results = append(results, &ActiveDownload{Download: d, Live: live})
+		results = append(results, &ActiveDownload{Download: d, Live: live, LiveAt: liveAt})
 	}
 
 	return results, nil
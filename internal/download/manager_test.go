@@ -2,10 +2,10 @@ package download_test
 
 import (
 	"context"
-	"database/sql"
-	_ "embed"
 	"io"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,48 +13,30 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/download/mocks"
+	"github.com/vmunix/arrgo/internal/testutil"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
 	"go.uber.org/mock/gomock"
 	_ "modernc.org/sqlite"
 )
 
-//go:embed testdata/schema.sql
-var testSchema string
-
 // testLogger returns a discard logger for tests.
 func testLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, nil))
 }
 
-func setupTestDB(t *testing.T) *sql.DB {
-	t.Helper()
-	db, err := sql.Open("sqlite", ":memory:?_foreign_keys=on")
-	require.NoError(t, err)
-	t.Cleanup(func() { _ = db.Close() })
-
-	_, err = db.Exec(testSchema)
-	require.NoError(t, err)
-	return db
-}
-
-// insertTestContent inserts a test content row and returns its ID.
-func insertTestContent(t *testing.T, db *sql.DB) int64 {
-	t.Helper()
-	result, err := db.Exec(`
-		INSERT INTO content (type, title, year, status, quality_profile, root_path)
-		VALUES ('movie', 'Test Movie', 2000, 'wanted', 'hd', '/movies')`,
-	)
-	require.NoError(t, err)
-	id, err := result.LastInsertId()
-	require.NoError(t, err)
-	return id
+// newTestManager returns a manager with client registered as the SABnzbd usenet client.
+func newTestManager(client download.Downloader, store *download.Store) *download.Manager {
+	mgr := download.NewManager(store, testLogger())
+	mgr.AddClient(download.ClientSABnzbd, download.ProtocolUsenet, client)
+	return mgr
 }
 
 func TestManager_Cancel(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 
 	d := &download.Download{
 		ContentID:   contentID,
@@ -70,7 +52,7 @@ func TestManager_Cancel(t *testing.T) {
 		Remove(gomock.Any(), "nzo_abc123", false).
 		Return(nil)
 
-	mgr := download.NewManager(client, store, testLogger())
+	mgr := newTestManager(client, store)
 
 	err := mgr.Cancel(context.Background(), d.ID, false)
 	require.NoError(t, err)
@@ -83,13 +65,13 @@ func TestManager_Cancel(t *testing.T) {
 func TestManager_Cancel_NotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
 
 	client := mocks.NewMockDownloader(ctrl)
 	// No expectations - Remove should not be called
 
-	mgr := download.NewManager(client, store, testLogger())
+	mgr := newTestManager(client, store)
 
 	err := mgr.Cancel(context.Background(), 9999, false)
 	require.ErrorIs(t, err, download.ErrNotFound)
@@ -98,9 +80,9 @@ func TestManager_Cancel_NotFound(t *testing.T) {
 func TestManager_Cancel_ClientError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 
 	d := &download.Download{
 		ContentID:   contentID,
@@ -117,7 +99,7 @@ func TestManager_Cancel_ClientError(t *testing.T) {
 		Remove(gomock.Any(), "nzo_abc123", false).
 		Return(download.ErrClientUnavailable)
 
-	mgr := download.NewManager(client, store, testLogger())
+	mgr := newTestManager(client, store)
 
 	err := mgr.Cancel(context.Background(), d.ID, false)
 	require.NoError(t, err, "Cancel should succeed despite client error")
@@ -130,9 +112,9 @@ func TestManager_Cancel_ClientError(t *testing.T) {
 func TestManager_GetActive(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 
 	d := &download.Download{
 		ContentID:   contentID,
@@ -154,7 +136,7 @@ func TestManager_GetActive(t *testing.T) {
 			ETA:      5 * time.Minute,
 		}, nil)
 
-	mgr := download.NewManager(client, store, testLogger())
+	mgr := newTestManager(client, store)
 
 	active, err := mgr.GetActive(context.Background())
 	require.NoError(t, err)
@@ -168,9 +150,9 @@ func TestManager_GetActive(t *testing.T) {
 func TestManager_GetActive_ClientError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 
 	d := &download.Download{
 		ContentID:   contentID,
@@ -187,7 +169,7 @@ func TestManager_GetActive_ClientError(t *testing.T) {
 		Status(gomock.Any(), "nzo_abc123").
 		Return(nil, download.ErrClientUnavailable)
 
-	mgr := download.NewManager(client, store, testLogger())
+	mgr := newTestManager(client, store)
 
 	active, err := mgr.GetActive(context.Background())
 	require.NoError(t, err)
@@ -200,9 +182,9 @@ func TestManager_GetActive_ClientError(t *testing.T) {
 func TestManager_GetActive_ExcludesTerminal(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 
 	// Add active download
 	d1 := &download.Download{
@@ -251,7 +233,7 @@ func TestManager_GetActive_ExcludesTerminal(t *testing.T) {
 			Progress: 100,
 		}, nil)
 
-	mgr := download.NewManager(client, store, testLogger())
+	mgr := newTestManager(client, store)
 
 	active, err := mgr.GetActive(context.Background())
 	require.NoError(t, err)
@@ -264,14 +246,86 @@ func TestManager_GetActive_ExcludesTerminal(t *testing.T) {
 	}
 }
 
+func TestManager_GetActive_SharesClientCalls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	db := testutil.OpenTestDB(t)
+	store := download.NewStore(db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
+	d := fixtures.NewDownload().ForContent(contentID).WithStatus(download.StatusDownloading).
+		WithClient(download.ClientSABnzbd, "nzo_abc123").Insert(t, db)
+
+	// The client answers slowly, so the callers overlap
+	release := make(chan struct{})
+	var calls atomic.Int32
+	client := mocks.NewMockDownloader(ctrl)
+	client.EXPECT().Status(gomock.Any(), "nzo_abc123").DoAndReturn(
+		func(context.Context, string) (*download.ClientStatus, error) {
+			calls.Add(1)
+			<-release
+			return &download.ClientStatus{ID: "nzo_abc123", Status: download.StatusDownloading, Progress: 50}, nil
+		}).AnyTimes()
+	client.EXPECT().Remove(gomock.Any(), gomock.Any(), false).Return(nil).AnyTimes()
+
+	mgr := newTestManager(client, store)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([][]*download.ActiveDownload, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			active, err := mgr.GetActive(context.Background())
+			assert.NoError(t, err)
+			results[i] = active
+		}()
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "concurrent callers share one client call")
+	for _, active := range results {
+		require.Len(t, active, 1)
+		require.NotNil(t, active[0].Live)
+		assert.InDelta(t, 50, active[0].Live.Progress, 0.001)
+		assert.False(t, active[0].LiveAt.IsZero())
+	}
+
+	// Fresh statuses are reused, also by Status
+	_, err := mgr.GetActive(context.Background())
+	require.NoError(t, err)
+	_, err = mgr.Status(context.Background(), d)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Removing a download drops the cache
+	other := fixtures.NewDownload().ForContent(contentID).WithStatus(download.StatusQueued).
+		WithClient(download.ClientSABnzbd, "nzo_other").WithReleaseName("Other.Release").Insert(t, db)
+	require.NoError(t, mgr.Cancel(context.Background(), other.ID, false))
+	_, err = mgr.Status(context.Background(), d)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// Without the cache every call reaches the client
+	mgr.SetStatusCacheTTL(0)
+	_, err = mgr.Status(context.Background(), d)
+	require.NoError(t, err)
+	_, err = mgr.Status(context.Background(), d)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
 // --- Cancel State Tests ---
 
 func TestManager_Cancel_FromQueued(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 
 	d := &download.Download{
 		ContentID:   contentID,
@@ -287,7 +341,7 @@ func TestManager_Cancel_FromQueued(t *testing.T) {
 		Remove(gomock.Any(), "nzo_queued", false).
 		Return(nil)
 
-	mgr := download.NewManager(client, store, testLogger())
+	mgr := newTestManager(client, store)
 
 	err := mgr.Cancel(context.Background(), d.ID, false)
 	require.NoError(t, err)
@@ -300,9 +354,9 @@ func TestManager_Cancel_FromQueued(t *testing.T) {
 func TestManager_Cancel_FromDownloading(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 
 	d := &download.Download{
 		ContentID:   contentID,
@@ -318,7 +372,7 @@ func TestManager_Cancel_FromDownloading(t *testing.T) {
 		Remove(gomock.Any(), "nzo_downloading", false).
 		Return(nil)
 
-	mgr := download.NewManager(client, store, testLogger())
+	mgr := newTestManager(client, store)
 
 	err := mgr.Cancel(context.Background(), d.ID, false)
 	require.NoError(t, err)
@@ -331,9 +385,9 @@ func TestManager_Cancel_FromDownloading(t *testing.T) {
 func TestManager_Cancel_FromCompleted_WithDeleteFiles(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := download.NewStore(db)
-	contentID := insertTestContent(t, db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
 
 	d := &download.Download{
 		ContentID:   contentID,
@@ -350,7 +404,7 @@ func TestManager_Cancel_FromCompleted_WithDeleteFiles(t *testing.T) {
 		Remove(gomock.Any(), "nzo_completed", true).
 		Return(nil)
 
-	mgr := download.NewManager(client, store, testLogger())
+	mgr := newTestManager(client, store)
 
 	// Cancel with deleteFiles=true
 	err := mgr.Cancel(context.Background(), d.ID, true)
@@ -360,3 +414,185 @@ func TestManager_Cancel_FromCompleted_WithDeleteFiles(t *testing.T) {
 	_, err = store.Get(d.ID)
 	require.ErrorIs(t, err, download.ErrNotFound)
 }
+
+func TestManager_SetSpeedLimit_Unsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := download.NewStore(testutil.OpenTestDB(t))
+	mgr := newTestManager(mocks.NewMockDownloader(ctrl), store)
+
+	err := mgr.SetSpeedLimit(context.Background(), 1024)
+	assert.ErrorIs(t, err, download.ErrUnsupported)
+}
+
+func TestProtocolForURL(t *testing.T) {
+	assert.Equal(t, download.ProtocolUsenet, download.ProtocolForURL("https://indexer.example/api?t=get&id=abc"))
+	assert.Equal(t, download.ProtocolUsenet, download.ProtocolForURL("https://indexer.example/getnzb/abc.nzb"))
+	assert.Equal(t, download.ProtocolTorrent, download.ProtocolForURL("magnet:?xt=urn:btih:abc"))
+	assert.Equal(t, download.ProtocolTorrent, download.ProtocolForURL("https://tracker.example/dl/Movie.2024.torrent"))
+}
+
+func TestManager_Add_RetriesTransientErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockDownloader(ctrl)
+	mgr := newTestManager(client, nil)
+	mgr.SetRetryPolicy(download.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+
+	gomock.InOrder(
+		client.EXPECT().Add(gomock.Any(), "http://example.com/nzb", "movies").Return("", download.ErrClientUnavailable),
+		client.EXPECT().Add(gomock.Any(), "http://example.com/nzb", "movies").Return("", &download.StatusError{StatusCode: 503}),
+		client.EXPECT().Add(gomock.Any(), "http://example.com/nzb", "movies").Return("nzo_1", nil),
+	)
+
+	var attempts []int
+	clientID, err := mgr.Add(context.Background(), download.ClientSABnzbd, "http://example.com/nzb", "movies",
+		func(a download.RetryAttempt) { attempts = append(attempts, a.Attempt) })
+	require.NoError(t, err)
+	assert.Equal(t, "nzo_1", clientID)
+	assert.Equal(t, []int{1, 2}, attempts)
+}
+
+func TestManager_Add_PermanentError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockDownloader(ctrl)
+	mgr := newTestManager(client, nil)
+	mgr.SetRetryPolicy(download.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+
+	client.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any()).Return("", download.ErrInvalidAPIKey).Times(1)
+
+	_, err := mgr.Add(context.Background(), download.ClientSABnzbd, "http://example.com/nzb", "", nil)
+	require.ErrorIs(t, err, download.ErrInvalidAPIKey)
+}
+
+func TestManager_Add_UnknownClient(t *testing.T) {
+	mgr := download.NewManager(nil, testLogger())
+
+	_, err := mgr.Add(context.Background(), "missing", "http://example.com/nzb", "", nil)
+	require.ErrorIs(t, err, download.ErrUnknownClient)
+}
+
+func TestManager_Route(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := download.NewStore(testutil.OpenTestDB(t))
+	sab := mocks.NewMockDownloader(ctrl)
+	sab2 := mocks.NewMockDownloader(ctrl)
+	qbit := mocks.NewMockDownloader(ctrl)
+
+	mgr := download.NewManager(store, testLogger())
+	mgr.AddClient("sab", download.ProtocolUsenet, sab)
+	mgr.AddClient("sab-backup", download.ProtocolUsenet, sab2)
+
+	name, client, err := mgr.Route("https://indexer.example/api?t=get&id=abc", "")
+	require.NoError(t, err)
+	assert.Equal(t, download.Client("sab"), name, "first usenet client receives grabs")
+	assert.Same(t, sab, client)
+
+	_, _, err = mgr.Route("magnet:?xt=urn:btih:abc", "")
+	require.ErrorIs(t, err, download.ErrNoClient)
+	assert.EqualError(t, err, "no download client for protocol: torrent (configured clients: sab (usenet), sab-backup (usenet))")
+
+	// Torznab proxy URLs don't look like torrents; the protocol is passed explicitly
+	_, _, err = mgr.Route("http://jackett:9117/dl/tracker/?path=abc", download.ProtocolTorrent)
+	require.ErrorIs(t, err, download.ErrNoClient)
+
+	mgr.AddClient("qbit", download.ProtocolTorrent, qbit)
+	name, client, err = mgr.Route("magnet:?xt=urn:btih:abc", "")
+	require.NoError(t, err)
+	assert.Equal(t, download.Client("qbit"), name)
+	assert.Same(t, qbit, client)
+
+	name, _, err = mgr.Route("http://jackett:9117/dl/tracker/?path=abc", download.ProtocolTorrent)
+	require.NoError(t, err)
+	assert.Equal(t, download.Client("qbit"), name)
+
+	assert.Equal(t, []download.ClientInfo{
+		{Name: "sab", Protocol: download.ProtocolUsenet},
+		{Name: "sab-backup", Protocol: download.ProtocolUsenet},
+		{Name: "qbit", Protocol: download.ProtocolTorrent},
+	}, mgr.Clients())
+}
+
+func TestManager_DispatchesByClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	store := download.NewStore(db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
+
+	sab := mocks.NewMockDownloader(ctrl)
+	qbit := mocks.NewMockDownloader(ctrl)
+	mgr := download.NewManager(store, testLogger())
+	mgr.AddClient("sab", download.ProtocolUsenet, sab)
+	mgr.AddClient("qbit", download.ProtocolTorrent, qbit)
+
+	nzb := &download.Download{ContentID: contentID, Client: "sab", ClientID: "nzo_1", Status: download.StatusDownloading, ReleaseName: "A"}
+	torrent := &download.Download{ContentID: contentID, Client: "qbit", ClientID: "hash1", Status: download.StatusDownloading, ReleaseName: "B"}
+	orphan := &download.Download{ContentID: contentID, Client: "removed", ClientID: "x", Status: download.StatusDownloading, ReleaseName: "C"}
+	require.NoError(t, store.Add(nzb))
+	require.NoError(t, store.Add(torrent))
+	require.NoError(t, store.Add(orphan))
+
+	sab.EXPECT().Status(gomock.Any(), "nzo_1").Return(&download.ClientStatus{ID: "nzo_1", Progress: 10}, nil)
+	qbit.EXPECT().Status(gomock.Any(), "hash1").Return(&download.ClientStatus{ID: "hash1", Progress: 20}, nil)
+
+	active, err := mgr.GetActive(context.Background())
+	require.NoError(t, err)
+	require.Len(t, active, 3)
+	live := make(map[int64]*download.ClientStatus)
+	for _, a := range active {
+		live[a.Download.ID] = a.Live
+	}
+	require.NotNil(t, live[nzb.ID])
+	assert.Equal(t, "nzo_1", live[nzb.ID].ID)
+	require.NotNil(t, live[torrent.ID])
+	assert.Equal(t, "hash1", live[torrent.ID].ID)
+	assert.Nil(t, live[orphan.ID], "unknown client has no live status")
+
+	_, err = mgr.Status(context.Background(), orphan)
+	require.ErrorIs(t, err, download.ErrUnknownClient)
+
+	qbit.EXPECT().Remove(gomock.Any(), "hash1", true).Return(nil)
+	require.NoError(t, mgr.Cancel(context.Background(), torrent.ID, true))
+	require.NoError(t, mgr.Cancel(context.Background(), orphan.ID, false), "orphaned record is still removed")
+}
+
+// fileClient is a downloader that takes uploaded files.
+type fileClient struct {
+	*mocks.MockDownloader
+	filename string
+	data     []byte
+}
+
+func (c *fileClient) AddFile(_ context.Context, filename string, data []byte, _ string) (string, error) {
+	c.filename, c.data = filename, data
+	return "nzo_file", nil
+}
+
+func TestManager_Add_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := &fileClient{MockDownloader: mocks.NewMockDownloader(ctrl)}
+	mgr := newTestManager(client, nil)
+	uploads := download.NewUploads(t.TempDir())
+	mgr.SetUploads(uploads)
+
+	uploadURL, err := uploads.Save("Movie.2024.nzb", []byte("<nzb/>"))
+	require.NoError(t, err)
+	clientID, err := mgr.Add(context.Background(), download.ClientSABnzbd, uploadURL, "movies", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "nzo_file", clientID)
+	assert.Equal(t, "Movie.2024.nzb", client.filename)
+	assert.Equal(t, []byte("<nzb/>"), client.data)
+
+	_, _, err = uploads.Read(uploadURL)
+	require.Error(t, err, "upload removed once the client has it")
+}
+
+func TestManager_Add_UploadUnsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mgr := newTestManager(mocks.NewMockDownloader(ctrl), nil)
+	uploads := download.NewUploads(t.TempDir())
+	mgr.SetUploads(uploads)
+
+	uploadURL, err := uploads.Save("Movie.2024.nzb", []byte("<nzb/>"))
+	require.NoError(t, err)
+	_, err = mgr.Add(context.Background(), download.ClientSABnzbd, uploadURL, "movies", nil)
+	require.ErrorIs(t, err, download.ErrUnsupported)
+}
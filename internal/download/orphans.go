@@ -0,0 +1,102 @@
+package download
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Orphan is an entry of the download root that no download refers to.
+type Orphan struct {
+	Path     string
+	Size     int64
+	Modified time.Time // Newest modification anywhere in the entry
+}
+
+// FindOrphans lists the entries of root that no non-terminal download refers
+// to and that haven't changed for minAge. Entries named after a category are
+// searched one level down, where clients put a category's jobs. Hidden
+// entries are skipped. Orphans are ordered by path.
+func FindOrphans(root string, categories []string, downloads []*Download, minAge time.Duration, now time.Time) ([]Orphan, error) {
+	referenced := make(map[string]bool)
+	for _, d := range downloads {
+		if !d.Status.IsTerminal() {
+			referenced[d.ReleaseName] = true
+		}
+	}
+	isCategory := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		if c != "" {
+			isCategory[c] = true
+		}
+	}
+
+	var orphans []Orphan
+	var scan func(dir string, nested bool) error
+	scan = func(dir string, nested bool) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", dir, err)
+		}
+		for _, e := range entries {
+			name := e.Name()
+			path := filepath.Join(dir, name)
+			switch {
+			case strings.HasPrefix(name, "."), referenced[name]:
+				continue
+			case !nested && e.IsDir() && isCategory[name]:
+				if err := scan(path, true); err != nil {
+					return err
+				}
+				continue
+			}
+			orphan, err := statTree(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // Removed while scanning
+			}
+			if err != nil {
+				return err
+			}
+			if now.Sub(orphan.Modified) >= minAge {
+				orphans = append(orphans, orphan)
+			}
+		}
+		return nil
+	}
+	if err := scan(root, false); err != nil {
+		return nil, err
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].Path < orphans[j].Path })
+	return orphans, nil
+}
+
+// statTree sums the size of the files under path and finds the newest
+// modification time.
+func statTree(path string) (Orphan, error) {
+	o := Orphan{Path: path}
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			o.Size += info.Size()
+		}
+		if info.ModTime().After(o.Modified) {
+			o.Modified = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return Orphan{}, fmt.Errorf("stat %s: %w", path, err)
+	}
+	return o, nil
+}
@@ -0,0 +1,47 @@
+package download_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/download"
+)
+
+func TestFindOrphans(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	mkdir := func(rel string, mtime time.Time) string {
+		path := filepath.Join(root, rel)
+		require.NoError(t, os.MkdirAll(path, 0755))
+		file := filepath.Join(path, "file.mkv")
+		require.NoError(t, os.WriteFile(file, []byte("12345"), 0644))
+		require.NoError(t, os.Chtimes(file, mtime, mtime))
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+		return path
+	}
+
+	orphan := mkdir("Old.Release.2020", old)
+	mkdir("Active.Release.2024", old)
+	mkdir("Recent.Release.2024", time.Now())
+	mkdir(".incomplete", old)
+	nested := mkdir("arrgo-movies/Nested.Release.2019", old)
+	mkdir("arrgo-movies/Cleaned.Release.2018", old)
+	require.NoError(t, os.Chtimes(filepath.Join(root, "arrgo-movies"), old, old))
+
+	downloads := []*download.Download{
+		{ReleaseName: "Active.Release.2024", Status: download.StatusDownloading},
+		{ReleaseName: "Cleaned.Release.2018", Status: download.StatusImported},
+		{ReleaseName: "Old.Release.2020", Status: download.StatusCleaned},
+	}
+
+	orphans, err := download.FindOrphans(root, []string{"arrgo-movies"}, downloads, 7*24*time.Hour, time.Now())
+	require.NoError(t, err)
+	require.Len(t, orphans, 2, "got %+v", orphans)
+	assert.Equal(t, orphan, orphans[0].Path, "a cleaned download doesn't keep its directory")
+	assert.Equal(t, int64(5), orphans[0].Size)
+	assert.Equal(t, nested, orphans[1].Path)
+}
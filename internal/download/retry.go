@@ -0,0 +1,93 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how download client calls are retried after transient errors.
+type RetryPolicy struct {
+	MaxAttempts  int           // Total attempts including the first; 1 disables retries
+	InitialDelay time.Duration // Delay before the first retry; doubled for each later retry
+	MaxDelay     time.Duration // Upper bound on the delay between attempts
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured:
+// up to 4 attempts, waiting about 2s, 4s and 8s between them.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  4,
+		InitialDelay: 2 * time.Second,
+		MaxDelay:     30 * time.Second,
+	}
+}
+
+// RetryAttempt describes a failed attempt that is about to be retried.
+type RetryAttempt struct {
+	Attempt     int           // Attempt that failed, starting at 1
+	MaxAttempts int           // Total attempts allowed by the policy
+	Err         error         // Error returned by the failed attempt
+	Delay       time.Duration // Wait before the next attempt
+}
+
+// IsTransient reports whether err is a download client error worth retrying:
+// the client could not be reached, timed out, or answered with a 5xx or 429.
+// Errors such as a rejected API key or an invalid NZB are permanent.
+func IsTransient(err error) bool {
+	if errors.Is(err, ErrClientUnavailable) {
+		return true
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// Do calls fn until it succeeds, fails with a permanent error, the attempts run
+// out, or ctx is done, and returns the last error. onRetry, if non-nil, is called
+// before waiting for each retry.
+func (p RetryPolicy) Do(ctx context.Context, fn func() error, onRetry func(RetryAttempt)) error {
+	maxAttempts := max(p.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxAttempts || !IsTransient(err) {
+			return err
+		}
+
+		delay := p.delay(attempt)
+		if onRetry != nil {
+			onRetry(RetryAttempt{Attempt: attempt, MaxAttempts: maxAttempts, Err: err, Delay: delay})
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns the wait after the given failed attempt: exponential backoff
+// capped at MaxDelay, with jitter so that retries from concurrent grabs spread out.
+// The result is between half and all of the backoff.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.InitialDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || backoff < p.MaxDelay); i++ {
+		backoff *= 2
+	}
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + rand.N(backoff-half+1)
+}
@@ -0,0 +1,131 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetry retries without noticeable waits.
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unavailable", fmt.Errorf("%w: connection refused", ErrClientUnavailable), true},
+		{"server error", &StatusError{StatusCode: 503}, true},
+		{"rate limited", fmt.Errorf("add: %w", &StatusError{StatusCode: 429}), true},
+		{"not found", &StatusError{StatusCode: 404}, false},
+		{"timeout", &net.DNSError{Err: "timeout", IsTimeout: true}, true},
+		{"invalid api key", ErrInvalidAPIKey, false},
+		{"other", errors.New("invalid nzb"), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransient(tt.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Do_SucceedsAfterTransientErrors(t *testing.T) {
+	calls := 0
+	var retries []RetryAttempt
+	err := fastRetry.Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return ErrClientUnavailable
+		}
+		return nil
+	}, func(a RetryAttempt) { retries = append(retries, a) })
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	require.Len(t, retries, 2)
+	assert.Equal(t, 1, retries[0].Attempt)
+	assert.Equal(t, 2, retries[1].Attempt)
+	assert.Equal(t, 3, retries[1].MaxAttempts)
+	assert.ErrorIs(t, retries[0].Err, ErrClientUnavailable)
+}
+
+func TestRetryPolicy_Do_PermanentErrorFailsFast(t *testing.T) {
+	calls := 0
+	err := fastRetry.Do(context.Background(), func() error {
+		calls++
+		return ErrInvalidAPIKey
+	}, nil)
+
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicy_Do_Exhausted(t *testing.T) {
+	calls := 0
+	err := fastRetry.Do(context.Background(), func() error {
+		calls++
+		return &StatusError{StatusCode: 502}
+	}, nil)
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, 502, statusErr.StatusCode)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryPolicy_Do_SingleAttempt(t *testing.T) {
+	calls := 0
+	err := RetryPolicy{MaxAttempts: 1}.Do(context.Background(), func() error {
+		calls++
+		return ErrClientUnavailable
+	}, nil)
+
+	require.ErrorIs(t, err, ErrClientUnavailable)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicy_Do_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := RetryPolicy{MaxAttempts: 5, InitialDelay: time.Hour}
+
+	calls := 0
+	err := policy.Do(ctx, func() error {
+		calls++
+		return ErrClientUnavailable
+	}, func(RetryAttempt) { cancel() })
+
+	require.ErrorIs(t, err, ErrClientUnavailable)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, InitialDelay: 2 * time.Second, MaxDelay: 30 * time.Second}
+	tests := []struct {
+		attempt int
+		backoff time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{4, 16 * time.Second},
+		{5, 30 * time.Second},
+		{60, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			d := p.delay(tt.attempt)
+			assert.GreaterOrEqual(t, d, tt.backoff/2, "attempt %d", tt.attempt)
+			assert.LessOrEqual(t, d, tt.backoff, "attempt %d", tt.attempt)
+		}
+	}
+
+	assert.Zero(t, RetryPolicy{}.delay(1))
+}
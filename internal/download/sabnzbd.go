@@ -1,10 +1,12 @@
 package download
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -21,20 +23,37 @@ type SABnzbdClient struct {
 	log        *slog.Logger
 }
 
+// DefaultSABnzbdTimeout bounds each SABnzbd API request when no timeout is given.
+const DefaultSABnzbdTimeout = 30 * time.Second
+
+// SABnzbdOption configures a SABnzbdClient.
+type SABnzbdOption func(*SABnzbdClient)
+
+// WithSABnzbdTimeout sets the timeout for each request to SABnzbd.
+func WithSABnzbdTimeout(d time.Duration) SABnzbdOption {
+	return func(c *SABnzbdClient) {
+		c.httpClient.Timeout = d
+	}
+}
+
 // NewSABnzbdClient creates a new SABnzbd client.
-func NewSABnzbdClient(baseURL, apiKey, category string, log *slog.Logger) *SABnzbdClient {
+func NewSABnzbdClient(baseURL, apiKey, category string, log *slog.Logger, opts ...SABnzbdOption) *SABnzbdClient {
 	if log == nil {
 		log = slog.Default()
 	}
-	return &SABnzbdClient{
+	c := &SABnzbdClient{
 		baseURL:  strings.TrimSuffix(baseURL, "/"),
 		apiKey:   apiKey,
 		category: category,
 		log:      log.With("component", "sabnzbd"),
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultSABnzbdTimeout,
 		},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Add sends an NZB URL to SABnzbd.
@@ -53,7 +72,11 @@ func (c *SABnzbdClient) Add(ctx context.Context, nzbURL, category string) (strin
 	if err := c.doRequest(ctx, "addurl", params, &resp); err != nil {
 		return "", err
 	}
+	return c.addedID(resp)
+}
 
+// addedID returns the job ID from an addurl or addfile response.
+func (c *SABnzbdClient) addedID(resp addResponse) (string, error) {
 	if !resp.Status {
 		if isAPIKeyError(resp.Error) {
 			return "", ErrInvalidAPIKey
@@ -69,6 +92,43 @@ func (c *SABnzbdClient) Add(ctx context.Context, nzbURL, category string) (strin
 	return resp.NzoIDs[0], nil
 }
 
+// AddFile uploads an NZB file to SABnzbd.
+func (c *SABnzbdClient) AddFile(ctx context.Context, filename string, data []byte, category string) (string, error) {
+	c.log.Debug("uploading nzb", "filename", filename, "category", category)
+
+	params := url.Values{
+		"apikey": {c.apiKey},
+		"output": {"json"},
+		"mode":   {"addfile"},
+		"cat":    {category},
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("name", filename)
+	if err != nil {
+		return "", fmt.Errorf("create form: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("create form: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("create form: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api?"+params.Encode(), &body)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	var resp addResponse
+	if err := c.do(req, "addfile", &resp); err != nil {
+		return "", err
+	}
+	return c.addedID(resp)
+}
+
 // Status gets the status of a download.
 func (c *SABnzbdClient) Status(ctx context.Context, clientID string) (*ClientStatus, error) {
 	// Check queue first
@@ -143,6 +203,34 @@ func (c *SABnzbdClient) Remove(ctx context.Context, clientID string, deleteFiles
 	return nil
 }
 
+// SetSpeedLimit sets SABnzbd's global download speed limit.
+// SABnzbd takes the value in KB/s with a "K" suffix; 100 (percent) removes the limit.
+func (c *SABnzbdClient) SetSpeedLimit(ctx context.Context, bytesPerSec int64) error {
+	value := "100"
+	if bytesPerSec > 0 {
+		value = fmt.Sprintf("%dK", max(bytesPerSec/1024, 1))
+	}
+	c.log.Debug("setting speed limit", "value", value)
+
+	params := url.Values{
+		"apikey": {c.apiKey},
+		"output": {"json"},
+		"mode":   {"config"},
+		"name":   {"speedlimit"},
+		"value":  {value},
+	}
+
+	var resp statusResponse
+	if err := c.doRequest(ctx, "config/speedlimit", params, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Status {
+		return fmt.Errorf("sabnzbd set speed limit failed")
+	}
+	return nil
+}
+
 // getQueue fetches the current download queue.
 func (c *SABnzbdClient) getQueue(ctx context.Context) ([]*ClientStatus, error) {
 	params := url.Values{
@@ -209,6 +297,7 @@ func (c *SABnzbdClient) getHistory(ctx context.Context) ([]*ClientStatus, error)
 			Progress: 100,
 			Size:     slot.Bytes,
 			Path:     slot.Storage,
+			Error:    historyFailure(slot),
 		})
 	}
 
@@ -217,14 +306,18 @@ func (c *SABnzbdClient) getHistory(ctx context.Context) ([]*ClientStatus, error)
 
 // doRequest performs an HTTP request to the SABnzbd API.
 func (c *SABnzbdClient) doRequest(ctx context.Context, mode string, params url.Values, result any) error {
-	start := time.Now()
 	reqURL := c.baseURL + "/api?" + params.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
+	return c.do(req, mode, result)
+}
 
+// do sends a request to the SABnzbd API and decodes its JSON response.
+func (c *SABnzbdClient) do(req *http.Request, mode string, result any) error {
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		c.log.Debug("api request failed", "mode", mode, "error", err)
@@ -234,7 +327,7 @@ func (c *SABnzbdClient) doRequest(ctx context.Context, mode string, params url.V
 
 	if resp.StatusCode != http.StatusOK {
 		c.log.Debug("api unexpected status", "mode", mode, "status", resp.StatusCode)
-		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		return &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
@@ -281,11 +374,18 @@ type historyResponse struct {
 }
 
 type historySlot struct {
-	NzoID   string `json:"nzo_id"`
-	Name    string `json:"name"`
-	Status  string `json:"status"`
-	Bytes   int64  `json:"bytes"`
-	Storage string `json:"storage"`
+	NzoID       string     `json:"nzo_id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Bytes       int64      `json:"bytes"`
+	Storage     string     `json:"storage"`
+	FailMessage string     `json:"fail_message"`
+	StageLog    []stageLog `json:"stage_log"`
+}
+
+type stageLog struct {
+	Name    string   `json:"name"`
+	Actions []string `json:"actions"`
 }
 
 // mapQueueStatus maps SABnzbd queue status to our Status type.
@@ -312,6 +412,26 @@ func mapHistoryStatus(sabStatus string) Status {
 	}
 }
 
+// historyFailure returns why SABnzbd failed a job: its fail_message, or else
+// the last stage log action that mentions a failure. Empty unless failed.
+func historyFailure(slot historySlot) string {
+	if slot.Status != "Failed" {
+		return ""
+	}
+	if msg := strings.TrimSpace(slot.FailMessage); msg != "" {
+		return msg
+	}
+	for i := len(slot.StageLog) - 1; i >= 0; i-- {
+		actions := slot.StageLog[i].Actions
+		for j := len(actions) - 1; j >= 0; j-- {
+			if strings.Contains(strings.ToLower(actions[j]), "fail") {
+				return slot.StageLog[i].Name + ": " + strings.TrimSpace(actions[j])
+			}
+		}
+	}
+	return ""
+}
+
 // isAPIKeyError checks if the error message indicates an invalid API key.
 func isAPIKeyError(errMsg string) bool {
 	lower := strings.ToLower(errMsg)
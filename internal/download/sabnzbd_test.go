@@ -3,9 +3,11 @@ package download
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,6 +49,53 @@ func TestSABnzbdClient_Add(t *testing.T) {
 	assert.Equal(t, "nzo_abc123", id)
 }
 
+func TestSABnzbdClient_AddFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "addfile", r.URL.Query().Get("mode"))
+		assert.Equal(t, "test-key", r.URL.Query().Get("apikey"))
+		assert.Equal(t, "movies", r.URL.Query().Get("cat"))
+
+		file, header, err := r.FormFile("name")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer func() { _ = file.Close() }()
+		data, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		assert.Equal(t, "Movie.2024.nzb", header.Filename)
+		assert.Equal(t, "<nzb/>", string(data))
+
+		writeJSON(t, w, map[string]any{"status": true, "nzo_ids": []string{"nzo_file1"}})
+	}))
+	defer server.Close()
+
+	client := NewSABnzbdClient(server.URL, "test-key", "", nil)
+	id, err := client.AddFile(context.Background(), "Movie.2024.nzb", []byte("<nzb/>"), "movies")
+	require.NoError(t, err)
+	assert.Equal(t, "nzo_file1", id)
+}
+
+func TestSABnzbdClient_Timeout(t *testing.T) {
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never respond
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	defer server.Close()
+	defer close(stop)
+
+	client := NewSABnzbdClient(server.URL, "test-key", "movies", nil, WithSABnzbdTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.Status(context.Background(), "SABnzbd_nzo_abc123")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second, "request should give up after the configured timeout")
+}
+
 func TestSABnzbdClient_Add_InvalidKey(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := map[string]any{
@@ -229,10 +278,21 @@ func TestSABnzbdClient_List(t *testing.T) {
 							"storage": "/downloads/complete/Completed.Movie.2024",
 						},
 						{
-							"nzo_id": "nzo_fail1",
-							"name":   "Failed.Movie.2024",
+							"nzo_id":       "nzo_fail1",
+							"name":         "Failed.Movie.2024",
+							"status":       "Failed",
+							"bytes":        0,
+							"fail_message": "Aborted, cannot be completed - https://sabnzbd.org/not-complete",
+						},
+						{
+							"nzo_id": "nzo_fail2",
+							"name":   "Unpack.Movie.2024",
 							"status": "Failed",
 							"bytes":  0,
+							"stage_log": []map[string]any{
+								{"name": "Repair", "actions": []string{"[Unpack.Movie.2024] Quick Check OK"}},
+								{"name": "Unpack", "actions": []string{"[Unpack.Movie.2024] Unpacking failed, CRC error"}},
+							},
 						},
 					},
 				},
@@ -246,7 +306,7 @@ func TestSABnzbdClient_List(t *testing.T) {
 	client := NewSABnzbdClient(server.URL, "test-key", "", nil)
 	list, err := client.List(context.Background())
 	require.NoError(t, err)
-	require.Len(t, list, 4)
+	require.Len(t, list, 5)
 
 	// Check queue items come first
 	assert.Equal(t, "nzo_queue1", list[0].ID)
@@ -257,8 +317,13 @@ func TestSABnzbdClient_List(t *testing.T) {
 	// Check history items
 	assert.Equal(t, "nzo_done1", list[2].ID)
 	assert.Equal(t, StatusCompleted, list[2].Status)
+	assert.Empty(t, list[2].Error)
 	assert.Equal(t, "nzo_fail1", list[3].ID)
 	assert.Equal(t, StatusFailed, list[3].Status)
+	assert.Equal(t, "Aborted, cannot be completed - https://sabnzbd.org/not-complete", list[3].Error)
+
+	// Without a fail_message, the failing stage log action is used
+	assert.Equal(t, "Unpack: [Unpack.Movie.2024] Unpacking failed, CRC error", list[4].Error)
 }
 
 func TestSABnzbdClient_List_QueuePositionOverridesStatus(t *testing.T) {
@@ -338,3 +403,29 @@ func TestSABnzbdClient_Remove(t *testing.T) {
 	err := client.Remove(context.Background(), "nzo_abc123", false)
 	require.NoError(t, err)
 }
+
+func TestSABnzbdClient_SetSpeedLimit(t *testing.T) {
+	var values []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "config", r.URL.Query().Get("mode"))
+		assert.Equal(t, "speedlimit", r.URL.Query().Get("name"))
+		values = append(values, r.URL.Query().Get("value"))
+		writeJSON(t, w, map[string]any{"status": true})
+	}))
+	defer server.Close()
+
+	client := NewSABnzbdClient(server.URL, "test-key", "", nil)
+	require.NoError(t, client.SetSpeedLimit(context.Background(), 5<<20))
+	require.NoError(t, client.SetSpeedLimit(context.Background(), 0))
+	assert.Equal(t, []string{"5120K", "100"}, values)
+}
+
+func TestSABnzbdClient_SetSpeedLimit_Failed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"status": false})
+	}))
+	defer server.Close()
+
+	client := NewSABnzbdClient(server.URL, "test-key", "", nil)
+	assert.Error(t, client.SetSpeedLimit(context.Background(), 1024))
+}
@@ -0,0 +1,159 @@
+package download
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultSampleLimit is how many progress samples are kept per download.
+const DefaultSampleLimit = 120
+
+const (
+	// etaWindow is how far back samples count toward the smoothed ETA.
+	etaWindow = 10 * time.Minute
+	// minETASamples is how many samples in the window a smoothed ETA needs.
+	minETASamples = 3
+	// sampleMaxAge is how long samples of a download no longer polled are
+	// kept, for downloads that leave the poll without a terminal status,
+	// such as canceled ones.
+	sampleMaxAge = 30 * time.Minute
+)
+
+// Sample is a download's progress at one poll.
+type Sample struct {
+	At       time.Time
+	Progress float64 // 0-100
+	Speed    int64   // bytes/sec, as reported by the client
+	Size     int64   // bytes
+}
+
+// Samples keeps the most recent progress samples of active downloads in
+// memory, for charts and for an ETA smoothed over recent progress. Clients'
+// own ETAs follow the instantaneous speed and swing with it.
+// Safe for concurrent use.
+type Samples struct {
+	mu    sync.Mutex
+	limit int
+	rings map[int64]*sampleRing
+}
+
+// sampleRing is a bounded ring of samples, oldest overwritten first.
+type sampleRing struct {
+	buf  []Sample
+	next int // Where the next sample goes
+	full bool
+}
+
+// NewSamples creates a sample store keeping up to limit samples per
+// download (DefaultSampleLimit if limit is 0 or less).
+func NewSamples(limit int) *Samples {
+	if limit <= 0 {
+		limit = DefaultSampleLimit
+	}
+	return &Samples{limit: limit, rings: make(map[int64]*sampleRing)}
+}
+
+// Record adds a sample for a download, dropping its oldest sample once the
+// limit is reached. Downloads with no sample for sampleMaxAge are forgotten.
+func (s *Samples) Record(downloadID int64, sample Sample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, ring := range s.rings {
+		if id != downloadID && sample.At.Sub(ring.last().At) > sampleMaxAge {
+			delete(s.rings, id)
+		}
+	}
+	ring, ok := s.rings[downloadID]
+	if !ok {
+		ring = &sampleRing{buf: make([]Sample, s.limit)}
+		s.rings[downloadID] = ring
+	}
+	ring.buf[ring.next] = sample
+	ring.next = (ring.next + 1) % len(ring.buf)
+	if ring.next == 0 {
+		ring.full = true
+	}
+}
+
+// Get returns a download's samples, oldest first; nil if it has none.
+func (s *Samples) Get(downloadID int64) []Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ring, ok := s.rings[downloadID]
+	if !ok {
+		return nil
+	}
+	return ring.samples()
+}
+
+// Drop forgets a download's samples, once it has reached a terminal status.
+func (s *Samples) Drop(downloadID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.rings, downloadID)
+}
+
+// ETA returns the time left for a download at its average rate of progress
+// over the last etaWindow, fitted by least squares so a brief burst or stall
+// moves it little. ok is false without minETASamples in the window or when
+// progress isn't advancing.
+func (s *Samples) ETA(downloadID int64) (eta time.Duration, ok bool) {
+	return SmoothedETA(s.Get(downloadID))
+}
+
+// SmoothedETA is ETA for samples ordered oldest first.
+func SmoothedETA(samples []Sample) (time.Duration, bool) {
+	if len(samples) == 0 {
+		return 0, false
+	}
+	latest := samples[len(samples)-1]
+	if latest.Progress >= 100 {
+		return 0, true
+	}
+	var window []Sample
+	for _, sample := range samples {
+		if latest.At.Sub(sample.At) <= etaWindow {
+			window = append(window, sample)
+		}
+	}
+	if len(window) < minETASamples {
+		return 0, false
+	}
+
+	// Slope of progress over time in percent per second, with times taken
+	// relative to the first sample to keep the sums small
+	var sumT, sumP, sumTT, sumTP float64
+	for _, sample := range window {
+		t := sample.At.Sub(window[0].At).Seconds()
+		sumT += t
+		sumP += sample.Progress
+		sumTT += t * t
+		sumTP += t * sample.Progress
+	}
+	n := float64(len(window))
+	denom := n*sumTT - sumT*sumT
+	if denom <= 0 {
+		return 0, false
+	}
+	slope := (n*sumTP - sumT*sumP) / denom
+	if slope <= 0 {
+		return 0, false
+	}
+	seconds := (100 - latest.Progress) / slope
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second), true
+}
+
+func (r *sampleRing) last() Sample {
+	return r.buf[(r.next-1+len(r.buf))%len(r.buf)]
+}
+
+func (r *sampleRing) samples() []Sample {
+	if !r.full {
+		return append([]Sample(nil), r.buf[:r.next]...)
+	}
+	out := make([]Sample, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
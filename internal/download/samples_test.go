@@ -0,0 +1,95 @@
+package download
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamples_Ring(t *testing.T) {
+	samples := NewSamples(3)
+	start := time.Now()
+	for i := range 5 {
+		samples.Record(1, Sample{At: start.Add(time.Duration(i) * time.Second), Progress: float64(i * 10)})
+	}
+
+	got := samples.Get(1)
+	require.Len(t, got, 3, "bounded to the limit")
+	assert.InDelta(t, 20.0, got[0].Progress, 0.001, "oldest kept first")
+	assert.InDelta(t, 40.0, got[2].Progress, 0.001)
+	assert.Nil(t, samples.Get(2))
+
+	samples.Drop(1)
+	assert.Nil(t, samples.Get(1))
+}
+
+func TestSamples_ForgetsStaleDownloads(t *testing.T) {
+	samples := NewSamples(0)
+	start := time.Now()
+	samples.Record(1, Sample{At: start, Progress: 10})
+	samples.Record(2, Sample{At: start.Add(sampleMaxAge + time.Minute), Progress: 10})
+
+	assert.Nil(t, samples.Get(1), "download no longer polled is forgotten")
+	assert.Len(t, samples.Get(2), 1)
+}
+
+func TestSmoothedETA(t *testing.T) {
+	start := time.Now()
+	steady := func(n int, step time.Duration, perStep float64) []Sample {
+		var out []Sample
+		for i := range n {
+			out = append(out, Sample{At: start.Add(time.Duration(i) * step), Progress: float64(i) * perStep})
+		}
+		return out
+	}
+
+	t.Run("steady progress", func(t *testing.T) {
+		// 1% per 10s, at 9% after 90s: 91% left takes 910s
+		eta, ok := SmoothedETA(steady(10, 10*time.Second, 1))
+		require.True(t, ok)
+		assert.Equal(t, 910*time.Second, eta)
+	})
+
+	t.Run("burst moves it little", func(t *testing.T) {
+		samples := steady(10, 10*time.Second, 1)
+		samples[9].Progress = 12 // Last poll jumped 4%
+		eta, ok := SmoothedETA(samples)
+		require.True(t, ok)
+		assert.Greater(t, eta, 500*time.Second, "one fast poll doesn't collapse the ETA")
+	})
+
+	t.Run("only recent samples count", func(t *testing.T) {
+		// Slow for an hour, then 1% per 10s for the last 10 minutes
+		var samples []Sample
+		for i := range 6 {
+			samples = append(samples, Sample{At: start.Add(time.Duration(i) * 10 * time.Minute), Progress: float64(i)})
+		}
+		last := samples[len(samples)-1]
+		for i := 1; i <= 60; i++ {
+			samples = append(samples, Sample{At: last.At.Add(time.Duration(i) * 10 * time.Second), Progress: last.Progress + float64(i)})
+		}
+		eta, ok := SmoothedETA(samples)
+		require.True(t, ok)
+		assert.Equal(t, 350*time.Second, eta)
+	})
+
+	t.Run("not enough samples", func(t *testing.T) {
+		_, ok := SmoothedETA(steady(2, 10*time.Second, 1))
+		assert.False(t, ok)
+		_, ok = SmoothedETA(nil)
+		assert.False(t, ok)
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		_, ok := SmoothedETA(steady(5, 10*time.Second, 0))
+		assert.False(t, ok)
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		eta, ok := SmoothedETA([]Sample{{At: start, Progress: 100}})
+		require.True(t, ok)
+		assert.Zero(t, eta)
+	})
+}
@@ -0,0 +1,236 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpeedLimiter is implemented by download clients that support a global speed limit.
+type SpeedLimiter interface {
+	// SetSpeedLimit caps the total download rate in bytes per second. 0 removes the limit.
+	SetSpeedLimit(ctx context.Context, bytesPerSec int64) error
+}
+
+// ParseSpeedLimit parses a speed such as "5MB", "500K" or "1.5 MB/s" into bytes per second.
+// Units are binary (1K = 1024 bytes); a bare number is bytes. "", "0" and "unlimited"
+// mean no limit.
+func ParseSpeedLimit(limit string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(limit))
+	s = strings.TrimSuffix(s, "/S")
+	if s == "" || s == "0" || s == "UNLIMITED" {
+		return 0, nil
+	}
+
+	multiplier := 1.0
+	for _, u := range []struct {
+		suffix string
+		mult   float64
+	}{
+		{"GB", 1 << 30}, {"G", 1 << 30},
+		{"MB", 1 << 20}, {"M", 1 << 20},
+		{"KB", 1 << 10}, {"K", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSuffix(s, u.suffix)
+			multiplier = u.mult
+			break
+		}
+	}
+
+	val, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || val < 0 {
+		return 0, fmt.Errorf("invalid speed limit %q", limit)
+	}
+	return int64(val * multiplier), nil
+}
+
+// FormatSpeedLimit formats bytes per second for display: 5242880 -> "5.0 MB/s", 0 -> "unlimited".
+func FormatSpeedLimit(bytesPerSec int64) string {
+	switch {
+	case bytesPerSec <= 0:
+		return "unlimited"
+	case bytesPerSec >= 1<<20:
+		return fmt.Sprintf("%.1f MB/s", float64(bytesPerSec)/(1<<20))
+	case bytesPerSec >= 1<<10:
+		return fmt.Sprintf("%.0f KB/s", float64(bytesPerSec)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B/s", bytesPerSec)
+	}
+}
+
+// BandwidthWindow limits download speed between two times of day.
+type BandwidthWindow struct {
+	From  time.Duration // Offset from midnight
+	To    time.Duration // Offset from midnight; before From for windows spanning midnight
+	Limit int64         // Bytes per second
+}
+
+// ParseBandwidthWindow parses a window such as from "08:00", to "23:00", limit "5MB".
+func ParseBandwidthWindow(from, to, limit string) (BandwidthWindow, error) {
+	var w BandwidthWindow
+	var err error
+	if w.From, err = parseTimeOfDay(from); err != nil {
+		return w, err
+	}
+	if w.To, err = parseTimeOfDay(to); err != nil {
+		return w, err
+	}
+	if w.Limit, err = ParseSpeedLimit(limit); err != nil {
+		return w, err
+	}
+	return w, nil
+}
+
+// parseTimeOfDay parses "HH:MM" into an offset from midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (want HH:MM)", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether the window covers the time of day of t.
+func (w BandwidthWindow) Contains(t time.Time) bool {
+	y, m, d := t.Date()
+	offset := t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+	if w.From <= w.To {
+		return offset >= w.From && offset < w.To
+	}
+	return offset >= w.From || offset < w.To
+}
+
+// Speed limit sources reported by SpeedLimitState.
+const (
+	SpeedSourceNone     = "none"
+	SpeedSourceSchedule = "schedule"
+	SpeedSourceOverride = "override"
+)
+
+// SpeedLimitState describes the speed limit currently applied to the download client.
+type SpeedLimitState struct {
+	Limit   int64      // Bytes per second; 0 = unlimited
+	Source  string     // SpeedSourceNone, SpeedSourceSchedule or SpeedSourceOverride
+	Expires *time.Time // When an override ends; nil otherwise
+}
+
+// SpeedScheduler applies the bandwidth schedule to the download client,
+// letting an ad-hoc override take precedence until it expires.
+type SpeedScheduler struct {
+	limiter SpeedLimiter
+	windows []BandwidthWindow
+	log     *slog.Logger
+	now     func() time.Time
+
+	mu       sync.Mutex
+	override *SpeedLimitState
+	applied  *SpeedLimitState // nil until the first successful apply
+}
+
+// NewSpeedScheduler creates a scheduler. The first window containing the current
+// time sets the limit; outside all windows the client is unlimited.
+func NewSpeedScheduler(limiter SpeedLimiter, windows []BandwidthWindow, log *slog.Logger) *SpeedScheduler {
+	return &SpeedScheduler{
+		limiter: limiter,
+		windows: windows,
+		log:     log,
+		now:     time.Now,
+	}
+}
+
+// desired returns the limit that should be in effect now. Caller must hold mu.
+func (s *SpeedScheduler) desired(now time.Time) SpeedLimitState {
+	if s.override != nil {
+		if s.override.Expires == nil || now.Before(*s.override.Expires) {
+			return *s.override
+		}
+		s.override = nil
+	}
+	for _, w := range s.windows {
+		if w.Contains(now) {
+			return SpeedLimitState{Limit: w.Limit, Source: SpeedSourceSchedule}
+		}
+	}
+	return SpeedLimitState{Source: SpeedSourceNone}
+}
+
+// Apply sends the limit that should be in effect now to the client.
+// The client is only called when the limit changes. Until a schedule window or
+// override first applies, the client's own limit is left alone.
+func (s *SpeedScheduler) Apply(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.applyLocked(ctx)
+}
+
+func (s *SpeedScheduler) applyLocked(ctx context.Context) error {
+	want := s.desired(s.now())
+	if s.applied == nil && want.Source == SpeedSourceNone {
+		return nil
+	}
+	if s.applied == nil || s.applied.Limit != want.Limit {
+		if err := s.limiter.SetSpeedLimit(ctx, want.Limit); err != nil {
+			return err
+		}
+		s.log.Info("download speed limit applied", "limit", FormatSpeedLimit(want.Limit), "source", want.Source)
+	}
+	s.applied = &want
+	return nil
+}
+
+// Override sets a limit that takes precedence over the schedule until the given time.
+// If the client rejects the limit, the override is discarded and the error returned.
+func (s *SpeedScheduler) Override(ctx context.Context, bytesPerSec int64, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev := s.override
+	s.override = &SpeedLimitState{Limit: bytesPerSec, Source: SpeedSourceOverride, Expires: &until}
+	if err := s.applyLocked(ctx); err != nil {
+		s.override = prev
+		return err
+	}
+	return nil
+}
+
+// Current returns the limit last applied to the client by the scheduler.
+func (s *SpeedScheduler) Current() SpeedLimitState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.applied == nil {
+		return SpeedLimitState{Source: SpeedSourceNone}
+	}
+	return *s.applied
+}
+
+// Run applies the schedule every interval until the context is canceled.
+// Returns nil without running if the client doesn't support speed limits.
+func (s *SpeedScheduler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Apply(ctx); err != nil {
+			if errors.Is(err, ErrUnsupported) {
+				s.log.Warn("download client does not support speed limits; bandwidth schedule disabled")
+				return nil
+			}
+			if ctx.Err() == nil {
+				s.log.Warn("apply speed limit failed", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
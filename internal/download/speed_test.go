@@ -0,0 +1,173 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpeedLimit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"", 0},
+		{"unlimited", 0},
+		{"0", 0},
+		{"5MB", 5 << 20},
+		{"5 MB/s", 5 << 20},
+		{"500K", 500 << 10},
+		{"1.5M", 3 << 19},
+		{"1G", 1 << 30},
+		{"2048", 2048},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSpeedLimit(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, bad := range []string{"fast", "-5MB", "MB"} {
+		_, err := ParseSpeedLimit(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestFormatSpeedLimit(t *testing.T) {
+	assert.Equal(t, "unlimited", FormatSpeedLimit(0))
+	assert.Equal(t, "5.0 MB/s", FormatSpeedLimit(5<<20))
+	assert.Equal(t, "500 KB/s", FormatSpeedLimit(500<<10))
+	assert.Equal(t, "100 B/s", FormatSpeedLimit(100))
+}
+
+func TestBandwidthWindow_Contains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 6, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	day, err := ParseBandwidthWindow("08:00", "23:00", "5MB")
+	require.NoError(t, err)
+	assert.False(t, day.Contains(at(7, 59)))
+	assert.True(t, day.Contains(at(8, 0)))
+	assert.True(t, day.Contains(at(22, 59)))
+	assert.False(t, day.Contains(at(23, 0)))
+
+	night, err := ParseBandwidthWindow("22:00", "06:00", "1MB")
+	require.NoError(t, err)
+	assert.True(t, night.Contains(at(23, 30)))
+	assert.True(t, night.Contains(at(2, 0)))
+	assert.False(t, night.Contains(at(6, 0)))
+	assert.False(t, night.Contains(at(12, 0)))
+
+	_, err = ParseBandwidthWindow("8am", "23:00", "5MB")
+	assert.Error(t, err)
+}
+
+// fakeLimiter records the limits it is asked to apply.
+type fakeLimiter struct {
+	calls []int64
+	err   error
+}
+
+func (f *fakeLimiter) SetSpeedLimit(_ context.Context, bytesPerSec int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.calls = append(f.calls, bytesPerSec)
+	return nil
+}
+
+func newTestScheduler(limiter SpeedLimiter, now *time.Time, windows ...BandwidthWindow) *SpeedScheduler {
+	s := NewSpeedScheduler(limiter, windows, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestSpeedScheduler_Schedule(t *testing.T) {
+	window, err := ParseBandwidthWindow("08:00", "23:00", "5MB")
+	require.NoError(t, err)
+	limiter := &fakeLimiter{}
+	now := time.Date(2024, 6, 1, 7, 0, 0, 0, time.Local)
+	s := newTestScheduler(limiter, &now, window)
+	ctx := context.Background()
+
+	// Outside the window before anything was applied: client left alone
+	require.NoError(t, s.Apply(ctx))
+	assert.Empty(t, limiter.calls)
+	assert.Equal(t, SpeedSourceNone, s.Current().Source)
+
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, s.Apply(ctx))
+	require.NoError(t, s.Apply(ctx)) // unchanged, no second call
+	assert.Equal(t, []int64{5 << 20}, limiter.calls)
+	assert.Equal(t, SpeedLimitState{Limit: 5 << 20, Source: SpeedSourceSchedule}, s.Current())
+
+	now = now.Add(15 * time.Hour) // 00:00
+	require.NoError(t, s.Apply(ctx))
+	assert.Equal(t, []int64{5 << 20, 0}, limiter.calls)
+	assert.Equal(t, SpeedSourceNone, s.Current().Source)
+}
+
+func TestSpeedScheduler_OverrideExpires(t *testing.T) {
+	window, err := ParseBandwidthWindow("08:00", "23:00", "5MB")
+	require.NoError(t, err)
+	limiter := &fakeLimiter{}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	s := newTestScheduler(limiter, &now, window)
+	ctx := context.Background()
+
+	until := now.Add(time.Hour)
+	require.NoError(t, s.Override(ctx, 1<<20, until))
+	cur := s.Current()
+	assert.Equal(t, int64(1<<20), cur.Limit)
+	assert.Equal(t, SpeedSourceOverride, cur.Source)
+	require.NotNil(t, cur.Expires)
+	assert.Equal(t, until, *cur.Expires)
+
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, s.Apply(ctx))
+	assert.Equal(t, SpeedSourceOverride, s.Current().Source)
+
+	now = now.Add(time.Hour)
+	require.NoError(t, s.Apply(ctx))
+	assert.Equal(t, SpeedLimitState{Limit: 5 << 20, Source: SpeedSourceSchedule}, s.Current())
+	assert.Equal(t, []int64{1 << 20, 5 << 20}, limiter.calls)
+}
+
+func TestSpeedScheduler_OverrideRejected(t *testing.T) {
+	limiter := &fakeLimiter{err: ErrUnsupported}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	s := newTestScheduler(limiter, &now)
+
+	err := s.Override(context.Background(), 1<<20, now.Add(time.Hour))
+	require.ErrorIs(t, err, ErrUnsupported)
+	assert.Equal(t, SpeedSourceNone, s.Current().Source)
+}
+
+func TestSpeedScheduler_RunStopsWhenUnsupported(t *testing.T) {
+	window, err := ParseBandwidthWindow("00:00", "23:59", "5MB")
+	require.NoError(t, err)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	s := newTestScheduler(&fakeLimiter{err: ErrUnsupported}, &now, window)
+
+	err = s.Run(context.Background(), time.Hour)
+	assert.NoError(t, err)
+}
+
+func TestSpeedScheduler_RunCanceled(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	s := newTestScheduler(&fakeLimiter{err: errors.New("boom")}, &now)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := s.Run(ctx, time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -15,15 +15,19 @@ type TransitionHandler func(event TransitionEvent)
 
 // validTransitions defines allowed state transitions.
 // Key is the "from" status, value is list of valid "to" statuses.
+// imported -> completed re-imports a download whose library file went missing.
 var validTransitions = map[Status][]Status{
 	StatusQueued:      {StatusDownloading, StatusCompleted, StatusFailed}, // completed: can skip downloading if fast
 	StatusDownloading: {StatusCompleted, StatusFailed},
-	StatusCompleted:   {StatusImporting, StatusSkipped, StatusFailed}, // skipped: duplicate detected
-	StatusImporting:   {StatusImported, StatusFailed},
-	StatusImported:    {StatusCleaned, StatusFailed},
+	StatusCompleted:   {StatusImporting, StatusSkipped, StatusFailed},                           // skipped: duplicate detected
+	StatusImporting:   {StatusImported, StatusPartiallyImported, StatusFailed, StatusCompleted}, // completed: interrupted import, retry
+	StatusImported:    {StatusCleaned, StatusFailed, StatusCompleted},
 	StatusCleaned:     {},             // terminal - no transitions out
 	StatusSkipped:     {},             // terminal - duplicate was detected
 	StatusFailed:      {StatusQueued}, // allow retry
+
+	// A season pack with failed episodes; importing reimports them
+	StatusPartiallyImported: {StatusImporting, StatusFailed},
 }
 
 // CanTransitionTo returns true if transitioning from s to target is valid.
@@ -21,9 +21,13 @@ func TestCanTransitionTo_ValidTransitions(t *testing.T) {
 		{StatusCompleted, StatusFailed},
 		{StatusImporting, StatusImported},
 		{StatusImporting, StatusFailed},
+		{StatusImporting, StatusCompleted}, // interrupted, retry
+		{StatusImporting, StatusPartiallyImported},
+		{StatusPartiallyImported, StatusImporting}, // reimport failed episodes
 		{StatusImported, StatusCleaned},
 		{StatusImported, StatusFailed},
-		{StatusFailed, StatusQueued}, // retry
+		{StatusImported, StatusCompleted}, // re-import
+		{StatusFailed, StatusQueued},      // retry
 	}
 
 	for _, tt := range tests {
@@ -47,12 +51,12 @@ func TestCanTransitionTo_InvalidTransitions(t *testing.T) {
 		{StatusCompleted, StatusCleaned},    // skip importing+imported
 		{StatusCompleted, StatusImported},   // skip importing
 		{StatusImporting, StatusQueued},     // backwards
-		{StatusImporting, StatusCompleted},  // backwards
 		{StatusImported, StatusQueued},      // backwards
-		{StatusImported, StatusCompleted},   // backwards
 		{StatusImported, StatusImporting},   // backwards
 		{StatusCleaned, StatusQueued},       // terminal
 		{StatusCleaned, StatusFailed},       // terminal
+
+		{StatusPartiallyImported, StatusCleaned}, // source kept for reimport
 	}
 
 	for _, tt := range tests {
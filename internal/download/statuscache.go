@@ -0,0 +1,119 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultStatusCacheTTL is how long the Manager reuses a live client status.
+const DefaultStatusCacheTTL = 3 * time.Second
+
+// statusCache shares live download statuses between callers for a short
+// time, so API requests polling the queue don't each query the client.
+// Concurrent lookups of the same download share one client call. Errors are
+// not cached.
+type statusCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration // 0 disables caching
+	gen     uint64        // Bumped by invalidate; fetches started before are not stored
+	entries map[statusKey]cachedStatus
+	calls   singleflight.Group
+}
+
+type statusKey struct {
+	client   Client
+	clientID string
+}
+
+type cachedStatus struct {
+	status    *ClientStatus
+	fetchedAt time.Time
+}
+
+// get returns the status of a download and when it was fetched, calling fetch
+// unless a fresh status is cached or another caller is already fetching it.
+// fetch runs without ctx's cancellation, since other callers may share it;
+// get itself returns when ctx is done.
+func (c *statusCache) get(ctx context.Context, key statusKey, fetch func(context.Context) (*ClientStatus, error)) (*ClientStatus, time.Time, error) {
+	c.mu.Lock()
+	ttl, gen := c.ttl, c.gen
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ttl <= 0 {
+		status, err := fetch(ctx)
+		return status, time.Now(), err
+	}
+	if ok && time.Since(e.fetchedAt) < ttl {
+		return copyStatus(e.status), e.fetchedAt, nil
+	}
+
+	ch := c.calls.DoChan(fmt.Sprintf("%d/%s/%s", gen, key.client, key.clientID), func() (any, error) {
+		status, err := fetch(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		e := cachedStatus{status: status, fetchedAt: time.Now()}
+		c.store(gen, key, e)
+		return e, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, time.Time{}, res.Err
+		}
+		e := res.Val.(cachedStatus)
+		return copyStatus(e.status), e.fetchedAt, nil
+	case <-ctx.Done():
+		return nil, time.Time{}, ctx.Err()
+	}
+}
+
+// store caches a fetched status unless the cache was invalidated since the
+// fetch started, dropping expired entries.
+func (c *statusCache) store(gen uint64, key statusKey, e cachedStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen {
+		return
+	}
+	if c.entries == nil {
+		c.entries = make(map[statusKey]cachedStatus)
+	}
+	for k, old := range c.entries {
+		if time.Since(old.fetchedAt) >= c.ttl {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = e
+}
+
+// invalidate drops every cached status, for when downloads are added or removed.
+func (c *statusCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.entries = nil
+}
+
+// setTTL sets how long statuses are reused; 0 or less disables the cache.
+func (c *statusCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = max(ttl, 0)
+	c.gen++
+	c.entries = nil
+}
+
+// copyStatus returns a copy of status so callers sharing a cached status
+// can't change it for each other.
+func copyStatus(status *ClientStatus) *ClientStatus {
+	if status == nil {
+		return nil
+	}
+	s := *status
+	return &s
+}
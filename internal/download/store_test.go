@@ -1,16 +1,22 @@
 package download
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/database"
+	"github.com/vmunix/arrgo/internal/testutil"
 )
 
 func TestStore_Add(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -34,7 +40,7 @@ func TestStore_Add(t *testing.T) {
 }
 
 func TestStore_Add_Idempotent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -65,8 +71,80 @@ func TestStore_Add_Idempotent(t *testing.T) {
 	assert.Equal(t, firstID, d2.ID, "idempotent Add should return same ID")
 }
 
+func TestStore_Add_FailedGrabLastError(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Fight Club")
+
+	// The client never accepted the grab
+	d := &Download{
+		ContentID:   contentID,
+		Client:      ClientSABnzbd,
+		Status:      StatusFailed,
+		ReleaseName: "Fight.Club.1999.1080p.BluRay.x264",
+		LastError:   "download client unavailable",
+	}
+	require.NoError(t, store.Add(d))
+
+	got, err := store.Get(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, got.Status)
+	assert.Equal(t, "download client unavailable", got.LastError)
+
+	// Failing again keeps the record failed with the latest error
+	again := &Download{ContentID: contentID, Client: ClientSABnzbd, Status: StatusFailed, ReleaseName: d.ReleaseName, LastError: "unexpected status: 503"}
+	require.NoError(t, store.Add(again))
+	assert.Equal(t, d.ID, again.ID)
+	got, err = store.Get(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, got.Status)
+	assert.Equal(t, "unexpected status: 503", got.LastError)
+
+	// A successful re-grab queues it and clears the error
+	regrab := &Download{ContentID: contentID, Client: ClientSABnzbd, ClientID: "nzo_1", Status: StatusQueued, ReleaseName: d.ReleaseName}
+	require.NoError(t, store.Add(regrab))
+	got, err = store.Get(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, got.Status)
+	assert.Equal(t, "nzo_1", got.ClientID)
+	assert.Empty(t, got.LastError)
+}
+
+func TestStore_SetLastError(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Fight Club")
+
+	d := &Download{ContentID: contentID, Client: ClientSABnzbd, ClientID: "nzo_1", Status: StatusDownloading, ReleaseName: "Fight.Club.1999.1080p.BluRay.x264"}
+	require.NoError(t, store.Add(d))
+	require.NoError(t, store.SetLastError(d.ID, "Aborted, cannot be completed"))
+
+	got, err := store.Get(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Aborted, cannot be completed", got.LastError)
+
+	require.ErrorIs(t, store.SetLastError(9999, "x"), ErrNotFound)
+}
+
+func TestStore_SetReleaseName(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Fight Club")
+
+	d := &Download{ContentID: contentID, Client: ClientSABnzbd, ClientID: "nzo_1", Status: StatusDownloading, ReleaseName: "Fight.Club.1999.1080p.BluRay.x264-GRP"}
+	require.NoError(t, store.Add(d))
+	require.NoError(t, store.SetReleaseName(d.ID, "Fight.Club.1999.1080p.BluRay.x264-GRP.1"))
+
+	got, err := store.Get(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Fight.Club.1999.1080p.BluRay.x264-GRP.1", got.ReleaseName)
+	assert.Equal(t, "GRP", got.ReleaseGroup, "group parsed at grab time is kept")
+
+	require.ErrorIs(t, store.SetReleaseName(9999, "x"), ErrNotFound)
+}
+
 func TestStore_Add_DifferentReleaseName(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -97,7 +175,7 @@ func TestStore_Add_DifferentReleaseName(t *testing.T) {
 }
 
 func TestStore_Get(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -106,10 +184,12 @@ func TestStore_Get(t *testing.T) {
 		Client:      ClientSABnzbd,
 		ClientID:    "SABnzbd_nzo_abc123",
 		Status:      StatusDownloading,
-		ReleaseName: "Fight.Club.1999.1080p.BluRay.x264",
+		ReleaseName: "Fight.Club.1999.1080p.BluRay.x264-AMIABLE",
 		Indexer:     "nzbgeek",
+		Category:    "arrgo-movies",
 	}
 	require.NoError(t, store.Add(original))
+	assert.Equal(t, "AMIABLE", original.ReleaseGroup, "group parsed from the release name")
 
 	retrieved, err := store.Get(original.ID)
 	require.NoError(t, err)
@@ -121,11 +201,13 @@ func TestStore_Get(t *testing.T) {
 	assert.Equal(t, original.ClientID, retrieved.ClientID)
 	assert.Equal(t, original.Status, retrieved.Status)
 	assert.Equal(t, original.ReleaseName, retrieved.ReleaseName)
+	assert.Equal(t, "AMIABLE", retrieved.ReleaseGroup)
 	assert.Equal(t, original.Indexer, retrieved.Indexer)
+	assert.Equal(t, original.Category, retrieved.Category)
 }
 
 func TestStore_Get_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	_, err := store.Get(9999)
@@ -133,7 +215,7 @@ func TestStore_Get_NotFound(t *testing.T) {
 }
 
 func TestStore_GetByClientID(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -153,7 +235,7 @@ func TestStore_GetByClientID(t *testing.T) {
 }
 
 func TestStore_GetByClientID_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	_, err := store.GetByClientID(ClientSABnzbd, "nonexistent")
@@ -161,7 +243,7 @@ func TestStore_GetByClientID_NotFound(t *testing.T) {
 }
 
 func TestStore_Update(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -191,7 +273,7 @@ func TestStore_Update(t *testing.T) {
 }
 
 func TestStore_Update_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	d := &Download{
@@ -208,7 +290,7 @@ func TestStore_Update_NotFound(t *testing.T) {
 }
 
 func TestStore_List_All(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -231,7 +313,7 @@ func TestStore_List_All(t *testing.T) {
 }
 
 func TestStore_List_Active(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -263,7 +345,7 @@ func TestStore_List_Active(t *testing.T) {
 }
 
 func TestStore_List_FilterByContentID(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID1 := insertTestContent(t, db, "Fight Club")
 	contentID2 := insertTestContent(t, db, "Pulp Fiction")
@@ -284,8 +366,26 @@ func TestStore_List_FilterByContentID(t *testing.T) {
 	assert.Equal(t, contentID1, results[0].ContentID)
 }
 
+func TestStore_List_FilterByIDs(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Fight Club")
+
+	d1 := &Download{ContentID: contentID, Client: ClientSABnzbd, ClientID: "nzo_1", Status: StatusQueued, ReleaseName: "release1", Indexer: "idx1"}
+	d2 := &Download{ContentID: contentID, Client: ClientSABnzbd, ClientID: "nzo_2", Status: StatusQueued, ReleaseName: "release2", Indexer: "idx2"}
+	require.NoError(t, store.Add(d1))
+	require.NoError(t, store.Add(d2))
+
+	results, total, err := store.List(Filter{IDs: []int64{d2.ID}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, total)
+	require.Len(t, results, 1)
+	assert.Equal(t, "release2", results[0].ReleaseName)
+}
+
 func TestStore_List_FilterByStatus(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -307,7 +407,7 @@ func TestStore_List_FilterByStatus(t *testing.T) {
 }
 
 func TestStore_List_FilterByClient(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -329,7 +429,7 @@ func TestStore_List_FilterByClient(t *testing.T) {
 }
 
 func TestStore_Delete(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -352,7 +452,7 @@ func TestStore_Delete(t *testing.T) {
 }
 
 func TestStore_Delete_Idempotent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Delete non-existent should not error
@@ -361,7 +461,7 @@ func TestStore_Delete_Idempotent(t *testing.T) {
 }
 
 func TestStore_Add_WithEpisodeID(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series content
@@ -398,7 +498,7 @@ func TestStore_Add_WithEpisodeID(t *testing.T) {
 }
 
 func TestStore_List_FilterByEpisodeID(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series content
@@ -432,7 +532,7 @@ func TestStore_List_FilterByEpisodeID(t *testing.T) {
 }
 
 func TestStore_LastTransitionAt(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -475,7 +575,7 @@ func TestStore_LastTransitionAt(t *testing.T) {
 }
 
 func TestStore_Transition(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Fight Club")
 
@@ -512,8 +612,81 @@ func TestStore_Transition(t *testing.T) {
 	assert.Error(t, err, "should reject invalid transition downloading->cleaned")
 }
 
+func TestStore_Transition_Concurrent(t *testing.T) {
+	// File-backed so goroutines use separate pooled connections, as in production
+	db, err := database.Open(filepath.Join(t.TempDir(), "arrgo.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	testutil.ApplySchema(t, db)
+
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Concurrent Movie")
+
+	var mu sync.Mutex
+	transitions := make(map[int64][]Status)
+	store.OnTransition(func(e TransitionEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		transitions[e.DownloadID] = append(transitions[e.DownloadID], e.To)
+	})
+
+	const numDownloads = 5
+	const workers = 8
+	lifecycle := []Status{StatusDownloading, StatusCompleted, StatusImporting, StatusImported, StatusCleaned}
+
+	ids := make([]int64, numDownloads)
+	for i := range ids {
+		d := &Download{
+			ContentID:   contentID,
+			Client:      ClientManual,
+			ClientID:    fmt.Sprintf("concurrent-%d", i),
+			Status:      StatusQueued,
+			ReleaseName: "Concurrent.Movie",
+			Indexer:     "manual",
+		}
+		require.NoError(t, store.Add(d))
+		ids[i] = d.ID
+	}
+
+	// Every worker walks every download through the full lifecycle using fresh copies.
+	// Each step must be applied exactly once; losers see ErrInvalidTransition.
+	errCh := make(chan error, numDownloads*workers)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, to := range lifecycle {
+					d, err := store.Get(id)
+					if err != nil {
+						errCh <- err
+						return
+					}
+					if err := store.Transition(d, to); err != nil && !errors.Is(err, ErrInvalidTransition) {
+						errCh <- err
+						return
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, id := range ids {
+		assert.Equal(t, lifecycle, transitions[id], "download %d should record each transition once, in order", id)
+		got, err := store.Get(id)
+		require.NoError(t, err)
+		assert.Equal(t, StatusCleaned, got.Status)
+	}
+}
+
 func TestStore_ListStuck(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create content records for foreign key constraints
@@ -578,8 +751,62 @@ func TestStore_ListStuck(t *testing.T) {
 	assert.Equal(t, d1.ID, stuck[0].ID)
 }
 
+func TestStore_Claim(t *testing.T) {
+	// File-backed so goroutines use separate pooled connections, as in production
+	db, err := database.Open(filepath.Join(t.TempDir(), "arrgo.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	testutil.ApplySchema(t, db)
+
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Claimed Movie")
+	d := &Download{
+		ContentID:   contentID,
+		Client:      ClientManual,
+		ClientID:    "claim",
+		Status:      StatusCompleted,
+		ReleaseName: "Claimed.Movie",
+		Indexer:     "manual",
+	}
+	require.NoError(t, store.Add(d))
+
+	// Every claimant read the download as completed; exactly one takes it
+	const claimants = 8
+	var wins atomic.Int32
+	var wg sync.WaitGroup
+	for range claimants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mine := *d
+			won, err := store.Claim(&mine, StatusImporting)
+			assert.NoError(t, err)
+			if won {
+				wins.Add(1)
+				assert.Equal(t, StatusImporting, mine.Status)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), wins.Load())
+
+	got, err := store.Get(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusImporting, got.Status)
+
+	// A stale copy loses without error and is left unchanged
+	won, err := store.Claim(d, StatusSkipped)
+	require.NoError(t, err)
+	assert.False(t, won)
+	assert.Equal(t, StatusCompleted, d.Status)
+
+	// Claims are still validated against the transition table
+	_, err = store.Claim(got, StatusCleaned)
+	require.ErrorIs(t, err, ErrInvalidTransition)
+}
+
 func TestStore_Transition_FailedToQueued(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Retry Movie")
 
@@ -610,7 +837,7 @@ func TestStore_Transition_FailedToQueued(t *testing.T) {
 }
 
 func TestStore_List_Pagination(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Pagination Test")
 
@@ -665,8 +892,57 @@ func TestStore_List_Pagination(t *testing.T) {
 	assert.Equal(t, 5, total)
 }
 
+func TestStore_List_Sort(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	contentID := insertTestContent(t, db, "Sort Test")
+
+	// Added in the same instant, so added_at pages depend on the ID tie-break
+	addedAt := time.Now().Add(-time.Hour)
+	statuses := []Status{StatusQueued, StatusFailed, StatusQueued, StatusCompleted}
+	var ids []int64
+	for i, status := range statuses {
+		d := &Download{
+			ContentID:   contentID,
+			Client:      ClientSABnzbd,
+			ClientID:    fmt.Sprintf("nzo_%d", i),
+			Status:      status,
+			ReleaseName: fmt.Sprintf("release%d", i),
+			Indexer:     "idx",
+		}
+		require.NoError(t, store.Add(d))
+		_, err := db.Exec("UPDATE downloads SET added_at = ? WHERE id = ?", addedAt, d.ID)
+		require.NoError(t, err)
+		ids = append(ids, d.ID)
+	}
+
+	listIDs := func(f Filter) []int64 {
+		t.Helper()
+		results, _, err := store.List(f)
+		require.NoError(t, err)
+		var got []int64
+		for _, d := range results {
+			got = append(got, d.ID)
+		}
+		return got
+	}
+
+	var paged []int64
+	for offset := 0; offset < 4; offset += 2 {
+		paged = append(paged, listIDs(Filter{Sort: SortAddedAt, Desc: true, Limit: 2, Offset: offset})...)
+	}
+	assert.Equal(t, []int64{ids[3], ids[2], ids[1], ids[0]}, paged, "equal times newest ID first across pages")
+
+	assert.Equal(t, []int64{ids[3], ids[1], ids[0], ids[2]}, listIDs(Filter{Sort: SortStatus}),
+		"completed, failed, then queued by ID")
+
+	_, _, err := store.List(Filter{Sort: "id; DROP TABLE downloads"})
+	require.Error(t, err)
+	assert.False(t, ValidSort("release_name"))
+}
+
 func TestStore_CountByStatus(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	contentID := insertTestContent(t, db, "Count Test")
 
@@ -695,7 +971,7 @@ func TestStore_CountByStatus(t *testing.T) {
 }
 
 func TestStore_CountByStatus_Empty(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	counts, err := store.CountByStatus()
@@ -704,7 +980,7 @@ func TestStore_CountByStatus_Empty(t *testing.T) {
 }
 
 func TestStore_AddWithEpisodeIDs(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series content
@@ -746,7 +1022,7 @@ func TestStore_AddWithEpisodeIDs(t *testing.T) {
 }
 
 func TestStore_AddWithSeasonPack(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series content
@@ -800,7 +1076,7 @@ func TestStore_AddWithSeasonPack(t *testing.T) {
 }
 
 func TestStore_SetEpisodeIDs_Replace(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series content
@@ -843,7 +1119,7 @@ func TestStore_SetEpisodeIDs_Replace(t *testing.T) {
 }
 
 func TestStore_List_SeasonPackFields(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series content
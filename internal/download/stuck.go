@@ -0,0 +1,50 @@
+package download
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultStuckThresholds returns how long a download may stay in each active
+// status before it counts as stuck. Queued and downloading allow for slow
+// connections and large releases; a completed download should be imported
+// within minutes.
+func DefaultStuckThresholds() map[Status]time.Duration {
+	return map[Status]time.Duration{
+		StatusQueued:      2 * time.Hour,
+		StatusDownloading: 6 * time.Hour,
+		StatusCompleted:   30 * time.Minute,
+		StatusImporting:   30 * time.Minute,
+	}
+}
+
+// DefaultStuckAlertThresholds returns how long a download may stay in each
+// active status before a download.stuck alert is raised for it.
+func DefaultStuckAlertThresholds() map[Status]time.Duration {
+	return map[Status]time.Duration{
+		StatusQueued:      12 * time.Hour,
+		StatusDownloading: 24 * time.Hour,
+		StatusCompleted:   2 * time.Hour,
+		StatusImporting:   2 * time.Hour,
+	}
+}
+
+// MarkStuckAlerted records that a stuck alert was raised for a download in
+// status. Reports false if one already had been, so each download is alerted
+// at most once.
+func (s *Store) MarkStuckAlerted(downloadID int64, status Status) (bool, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO download_stuck_alerts (download_id, status, alerted_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(download_id) DO NOTHING`,
+		downloadID, status, time.Now(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("mark download %d stuck alerted: %w", downloadID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("mark download %d stuck alerted: %w", downloadID, err)
+	}
+	return n > 0, nil
+}
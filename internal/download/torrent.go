@@ -0,0 +1,93 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TorrentOptions are per-download settings of torrent clients.
+type TorrentOptions struct {
+	// Sequential downloads pieces in order, so a player can start on a
+	// partially downloaded file.
+	Sequential bool
+	// FirstLastPiecePrio fetches the first and last pieces of each file
+	// first, which players read to open a file.
+	FirstLastPiecePrio bool
+}
+
+// TorrentOptioner is implemented by download clients with per-torrent
+// options. Clients without them, such as SABnzbd, ignore the options of a
+// grab.
+type TorrentOptioner interface {
+	// SetTorrentOptions applies options to a download in the client.
+	SetTorrentOptions(ctx context.Context, clientID string, opts TorrentOptions) error
+}
+
+// SetTorrentOptions applies options to a download in its client and records
+// them. Returns ErrUnsupported if the client has no torrent options.
+func (m *Manager) SetTorrentOptions(ctx context.Context, d *Download, opts TorrentOptions) error {
+	client, err := m.ClientFor(d.Client)
+	if err != nil {
+		return err
+	}
+	optioner, ok := client.(TorrentOptioner)
+	if !ok {
+		return fmt.Errorf("%s has no torrent options: %w", d.Client, ErrUnsupported)
+	}
+	if err := optioner.SetTorrentOptions(ctx, d.ClientID, opts); err != nil {
+		return fmt.Errorf("set torrent options on %s: %w", d.Client, err)
+	}
+	m.statuses.invalidate()
+	return m.store.SetTorrentOptions(d.ID, opts)
+}
+
+// SetTorrentOptions records the torrent options applied to a download.
+func (s *Store) SetTorrentOptions(downloadID int64, opts TorrentOptions) error {
+	_, err := s.db.Exec(`
+		INSERT INTO download_torrent_options (download_id, sequential, first_last_piece_prio)
+		VALUES (?, ?, ?)
+		ON CONFLICT(download_id) DO UPDATE SET
+			sequential = excluded.sequential,
+			first_last_piece_prio = excluded.first_last_piece_prio`,
+		downloadID, opts.Sequential, opts.FirstLastPiecePrio,
+	)
+	if err != nil {
+		return fmt.Errorf("set torrent options for download %d: %w", downloadID, err)
+	}
+	return nil
+}
+
+// TorrentOptions returns the recorded torrent options of the given downloads.
+// Downloads whose client has no torrent options are absent from the map.
+func (s *Store) TorrentOptions(downloadIDs ...int64) (map[int64]TorrentOptions, error) {
+	result := make(map[int64]TorrentOptions)
+	if len(downloadIDs) == 0 {
+		return result, nil
+	}
+	args := make([]any, len(downloadIDs))
+	for i, id := range downloadIDs {
+		args[i] = id
+	}
+	rows, err := s.db.Query(`
+		SELECT download_id, sequential, first_last_piece_prio
+		FROM download_torrent_options
+		WHERE download_id IN (?`+strings.Repeat(", ?", len(downloadIDs)-1)+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query torrent options: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var id int64
+		var opts TorrentOptions
+		if err := rows.Scan(&id, &opts.Sequential, &opts.FirstLastPiecePrio); err != nil {
+			return nil, fmt.Errorf("scan torrent options: %w", err)
+		}
+		result[id] = opts
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate torrent options: %w", err)
+	}
+	return result, nil
+}
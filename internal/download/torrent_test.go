@@ -0,0 +1,60 @@
+package download_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/download/mocks"
+	"github.com/vmunix/arrgo/internal/testutil"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
+	"go.uber.org/mock/gomock"
+)
+
+// torrentClient is a downloader with per-torrent options.
+type torrentClient struct {
+	*mocks.MockDownloader
+	set map[string]download.TorrentOptions
+	err error
+}
+
+func (c *torrentClient) SetTorrentOptions(_ context.Context, clientID string, opts download.TorrentOptions) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.set[clientID] = opts
+	return nil
+}
+
+func TestManager_SetTorrentOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := testutil.OpenTestDB(t)
+	store := download.NewStore(db)
+	contentID := fixtures.NewMovie("Test Movie", 2000).Insert(t, db).ID
+
+	qbit := &torrentClient{MockDownloader: mocks.NewMockDownloader(ctrl), set: make(map[string]download.TorrentOptions)}
+	mgr := newTestManager(mocks.NewMockDownloader(ctrl), store)
+	mgr.AddClient(download.ClientQBittorrent, download.ProtocolTorrent, qbit)
+
+	torrent := &download.Download{ContentID: contentID, Client: download.ClientQBittorrent, ClientID: "hash1", Status: download.StatusDownloading, ReleaseName: "Test.Movie.2160p"}
+	usenet := &download.Download{ContentID: contentID, Client: download.ClientSABnzbd, ClientID: "nzo_1", Status: download.StatusDownloading, ReleaseName: "Test.Movie.1080p"}
+	require.NoError(t, store.Add(torrent))
+	require.NoError(t, store.Add(usenet))
+
+	opts := download.TorrentOptions{Sequential: true, FirstLastPiecePrio: true}
+	require.NoError(t, mgr.SetTorrentOptions(context.Background(), torrent, opts))
+	assert.Equal(t, opts, qbit.set["hash1"])
+
+	err := mgr.SetTorrentOptions(context.Background(), usenet, opts)
+	require.ErrorIs(t, err, download.ErrUnsupported, "SABnzbd has no torrent options")
+
+	qbit.err = errors.New("connection refused")
+	require.Error(t, mgr.SetTorrentOptions(context.Background(), torrent, download.TorrentOptions{}))
+
+	recorded, err := store.TorrentOptions(torrent.ID, usenet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]download.TorrentOptions{torrent.ID: opts}, recorded, "failed changes aren't recorded")
+}
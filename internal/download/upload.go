@@ -0,0 +1,149 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileAdder is implemented by download clients that accept a release file
+// itself rather than a URL to fetch it from.
+type FileAdder interface {
+	// AddFile sends a release file's contents to the download client.
+	AddFile(ctx context.Context, filename string, data []byte, category string) (clientID string, err error)
+}
+
+// ErrInvalidNZB is returned for an uploaded file that isn't an NZB.
+var ErrInvalidNZB = errors.New("invalid nzb")
+
+// Uploads keeps uploaded release files until a download client has taken
+// them. A saved upload is referred to by a file:// URL, so it travels the
+// grab pipeline like any other download URL; Manager.Add reads it back and
+// removes it once the client has accepted it.
+type Uploads struct {
+	dir string
+}
+
+// NewUploads creates an upload store keeping files under dir.
+func NewUploads(dir string) *Uploads {
+	return &Uploads{dir: dir}
+}
+
+// Save stores an uploaded file and returns the URL to grab it by.
+// Each upload gets its own directory, so the file keeps its name, which
+// clients use to name the job.
+func (u *Uploads) Save(filename string, data []byte) (string, error) {
+	name := filepath.Base(filepath.Clean("/" + filename))
+	if name == "/" || name == "." {
+		return "", fmt.Errorf("save upload: invalid file name %q", filename)
+	}
+	var token [8]byte
+	if _, err := rand.Read(token[:]); err != nil {
+		return "", fmt.Errorf("save upload: %w", err)
+	}
+	dir, err := filepath.Abs(filepath.Join(u.dir, hex.EncodeToString(token[:])))
+	if err != nil {
+		return "", fmt.Errorf("save upload: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("save upload: %w", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o640); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("save upload: %w", err)
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String(), nil
+}
+
+// Read returns the name and contents of a saved upload.
+func (u *Uploads) Read(uploadURL string) (string, []byte, error) {
+	path, err := u.path(uploadURL)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("read upload: %w", err)
+	}
+	return filepath.Base(path), data, nil
+}
+
+// Remove deletes a saved upload.
+func (u *Uploads) Remove(uploadURL string) error {
+	path, err := u.path(uploadURL)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("remove upload: %w", err)
+	}
+	return nil
+}
+
+// path returns the file a URL from Save refers to. URLs outside the upload
+// directory are refused, so a grab can't send arbitrary local files.
+func (u *Uploads) path(uploadURL string) (string, error) {
+	parsed, err := url.Parse(uploadURL)
+	if err != nil || parsed.Scheme != "file" {
+		return "", fmt.Errorf("not an upload URL: %q", uploadURL)
+	}
+	root, err := filepath.Abs(u.dir)
+	if err != nil {
+		return "", fmt.Errorf("resolve upload dir: %w", err)
+	}
+	path := filepath.Clean(filepath.FromSlash(parsed.Path))
+	if filepath.Dir(filepath.Dir(path)) != root {
+		return "", fmt.Errorf("upload %q is outside %s", uploadURL, root)
+	}
+	return path, nil
+}
+
+// IsUploadURL reports whether a download URL refers to a saved upload.
+func IsUploadURL(downloadURL string) bool {
+	return strings.HasPrefix(strings.ToLower(downloadURL), "file:")
+}
+
+// ValidateNZB checks that data is a well-formed NZB document: XML with an
+// <nzb> root listing at least one file.
+func ValidateNZB(data []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	// NZBs are commonly ISO-8859-1; their content doesn't matter here
+	dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	depth, files := 0, 0
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidNZB, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 && t.Name.Local != "nzb" {
+				return fmt.Errorf("%w: root element is <%s>, want <nzb>", ErrInvalidNZB, t.Name.Local)
+			}
+			if depth == 1 && t.Name.Local == "file" {
+				files++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	if files == 0 {
+		return fmt.Errorf("%w: no files listed", ErrInvalidNZB)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNZB = `<?xml version="1.0" encoding="iso-8859-1" ?>
+<!DOCTYPE nzb PUBLIC "-//newzBin//DTD NZB 1.1//EN" "http://www.newzbin.com/DTD/nzb/nzb-1.1.dtd">
+<nzb xmlns="http://www.newzbin.com/DTD/2003/nzb">
+  <file poster="poster@example.com" date="1700000000" subject="Movie.2024.1080p [1/1] - &quot;movie.mkv&quot; yEnc">
+    <groups><group>alt.binaries.test</group></groups>
+    <segments><segment bytes="1000" number="1">abc@example.com</segment></segments>
+  </file>
+</nzb>`
+
+func TestValidateNZB(t *testing.T) {
+	require.NoError(t, ValidateNZB([]byte(testNZB)))
+
+	for name, data := range map[string]string{
+		"empty":      "",
+		"not xml":    "d8:announce",
+		"truncated":  testNZB[:len(testNZB)/2],
+		"wrong root": `<rss><file/></rss>`,
+		"no files":   `<nzb></nzb>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, ValidateNZB([]byte(data)), ErrInvalidNZB)
+		})
+	}
+}
+
+func TestUploads(t *testing.T) {
+	dir := t.TempDir()
+	uploads := NewUploads(dir)
+
+	uploadURL, err := uploads.Save("../Movie.2024.nzb", []byte(testNZB))
+	require.NoError(t, err)
+	assert.True(t, IsUploadURL(uploadURL))
+
+	name, data, err := uploads.Read(uploadURL)
+	require.NoError(t, err)
+	assert.Equal(t, "Movie.2024.nzb", name, "directories stripped from the name")
+	assert.Equal(t, testNZB, string(data))
+
+	require.NoError(t, uploads.Remove(uploadURL))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Only files saved under the upload directory can be read
+	outside := filepath.Join(t.TempDir(), "x", "secret.nzb")
+	_, _, err = uploads.Read("file://" + filepath.ToSlash(outside))
+	require.Error(t, err)
+	_, _, err = uploads.Read("file://" + filepath.ToSlash(filepath.Join(dir, "..", "x", "secret.nzb")))
+	require.Error(t, err)
+	_, _, err = uploads.Read("http://example.com/movie.nzb")
+	require.Error(t, err)
+}
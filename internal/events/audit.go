@@ -0,0 +1,74 @@
+package events
+
+import "time"
+
+// APIAudit records an API request that changes state, whatever its outcome,
+// so failed destructive attempts are visible as well as successful ones.
+type APIAudit struct {
+	BaseEvent
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Route      string            `json:"route,omitempty"`  // ServeMux pattern; empty if none matched
+	Params     map[string]string `json:"params,omitempty"` // Path wildcards, e.g. the {id}
+	Query      map[string]string `json:"query,omitempty"`  // API keys redacted
+	Body       map[string]any    `json:"body,omitempty"`   // IDs and flags from a JSON body, not the full body
+	Remote     string            `json:"remote"`           // Source IP
+	KeyPrint   string            `json:"key_fingerprint,omitempty"`
+	KeyName    string            `json:"key_name,omitempty"` // Name of the authenticated API key
+	Status     int               `json:"status"`
+	DurationMS int64             `json:"duration_ms"`
+}
+
+// AuditFilter selects API audit entries for ListAudit. Zero fields match everything.
+type AuditFilter struct {
+	Method     string
+	PathPrefix string
+	KeyPrint   string
+	KeyName    string
+	Failed     *bool      // Status 400 or above, or below
+	Since      *time.Time // occurred_at >= Since
+	Until      *time.Time // occurred_at < Until
+	Limit      int        // Maximum number of results (0 = unlimited)
+	Offset     int        // Number of results to skip
+}
+
+// ListAudit returns API audit entries matching the filter, newest first,
+// along with the total count before pagination.
+func (l *EventLog) ListAudit(f AuditFilter) ([]RawEvent, int, error) {
+	conditions := []string{"event_type = ?"}
+	args := []any{EventAPIAudit}
+
+	if f.Method != "" {
+		conditions = append(conditions, "json_extract(payload, '$.method') = ?")
+		args = append(args, f.Method)
+	}
+	if f.PathPrefix != "" {
+		conditions = append(conditions, "substr(json_extract(payload, '$.path'), 1, ?) = ?")
+		args = append(args, len(f.PathPrefix), f.PathPrefix)
+	}
+	if f.KeyPrint != "" {
+		conditions = append(conditions, "json_extract(payload, '$.key_fingerprint') = ?")
+		args = append(args, f.KeyPrint)
+	}
+	if f.KeyName != "" {
+		conditions = append(conditions, "json_extract(payload, '$.key_name') = ?")
+		args = append(args, f.KeyName)
+	}
+	if f.Failed != nil {
+		if *f.Failed {
+			conditions = append(conditions, "json_extract(payload, '$.status') >= 400")
+		} else {
+			conditions = append(conditions, "json_extract(payload, '$.status') < 400")
+		}
+	}
+	if f.Since != nil {
+		conditions = append(conditions, "occurred_at >= ?")
+		args = append(args, f.Since.Local())
+	}
+	if f.Until != nil {
+		conditions = append(conditions, "occurred_at < ?")
+		args = append(args, f.Until.Local())
+	}
+
+	return l.list(conditions, args, f.Limit, f.Offset)
+}
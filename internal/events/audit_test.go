@@ -0,0 +1,52 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventLog_ListAudit(t *testing.T) {
+	db := setupTestDB(t)
+	log := NewEventLog(db)
+
+	audit := func(method, path string, status int, key string) {
+		t.Helper()
+		_, err := log.Append(&APIAudit{
+			BaseEvent: NewBaseEvent(EventAPIAudit, EntityAPI, 0),
+			Method:    method,
+			Path:      path,
+			Status:    status,
+			KeyPrint:  key,
+		})
+		require.NoError(t, err)
+	}
+	audit("DELETE", "/api/v1/content/1", 204, "")
+	audit("DELETE", "/api/v1/files/2", 500, "")
+	audit("POST", "/api/v3/movie", 201, "abcd")
+	_, err := log.Append(&testEvent{BaseEvent: NewBaseEvent("test.created", "test", 1)})
+	require.NoError(t, err)
+
+	paths := func(f AuditFilter) []string {
+		t.Helper()
+		raw, total, err := log.ListAudit(f)
+		require.NoError(t, err)
+		assert.Len(t, raw, total)
+		var out []string
+		for _, r := range raw {
+			e, err := DefaultRegistry().Unmarshal(r)
+			require.NoError(t, err)
+			out = append(out, e.(*APIAudit).Path)
+		}
+		return out
+	}
+
+	failed, succeeded := true, false
+	assert.Equal(t, []string{"/api/v3/movie", "/api/v1/files/2", "/api/v1/content/1"}, paths(AuditFilter{}), "newest first, audit entries only")
+	assert.Equal(t, []string{"/api/v1/files/2", "/api/v1/content/1"}, paths(AuditFilter{Method: "DELETE"}))
+	assert.Equal(t, []string{"/api/v1/content/1"}, paths(AuditFilter{PathPrefix: "/api/v1/content"}))
+	assert.Equal(t, []string{"/api/v1/files/2"}, paths(AuditFilter{Failed: &failed}))
+	assert.Len(t, paths(AuditFilter{Failed: &succeeded}), 2)
+	assert.Equal(t, []string{"/api/v3/movie"}, paths(AuditFilter{KeyPrint: "abcd"}))
+}
@@ -14,6 +14,10 @@ type Bus struct {
 	log         *EventLog               // SQLite persistence (may be nil)
 	logger      *slog.Logger
 	closed      bool
+
+	// Outbox mode (see EnableOutbox)
+	registry *Registry     // nil unless durable events go through the outbox
+	wake     chan struct{} // Signals the dispatcher that events are pending
 }
 
 // NewBus creates a new event bus.
@@ -26,17 +30,45 @@ func NewBus(log *EventLog, logger *slog.Logger) *Bus {
 		subscribers: make(map[string][]chan Event),
 		log:         log,
 		logger:      logger,
+		wake:        make(chan struct{}, 1),
 	}
 }
 
 // Publish sends an event to all subscribers and optionally persists it.
+// In outbox mode durable events are only persisted here and delivered by
+// RunOutbox, so they are not lost if arrgod stops before a handler sees them.
 func (b *Bus) Publish(ctx context.Context, e Event) error {
 	b.mu.RLock()
-	if b.closed {
-		b.mu.RUnlock()
+	closed := b.closed
+	durable := b.registry != nil && b.registry.Durable(e.EventType())
+	b.mu.RUnlock()
+	if closed {
 		return nil
 	}
 
+	if durable {
+		_, err := b.log.AppendPending(e)
+		if err == nil {
+			b.notifyOutbox()
+			return nil
+		}
+		// Deliver directly rather than lose the event
+		b.logger.Error("failed to queue event in outbox", "type", e.EventType(), "error", err)
+	} else if b.log != nil {
+		if _, err := b.log.Append(e); err != nil {
+			b.logger.Error("failed to persist event", "type", e.EventType(), "error", err)
+			// Continue - event delivery is more important than persistence
+		}
+	}
+
+	b.deliver(e)
+	return nil
+}
+
+// deliver hands an event to its subscribers without blocking,
+// dropping it for subscribers whose channel is full.
+func (b *Bus) deliver(e Event) {
+	b.mu.RLock()
 	// Get subscribers for this event type
 	subs := make([]chan Event, len(b.subscribers[e.EventType()]))
 	copy(subs, b.subscribers[e.EventType()])
@@ -46,14 +78,6 @@ func (b *Bus) Publish(ctx context.Context, e Event) error {
 	copy(allSubs, b.allSubs)
 	b.mu.RUnlock()
 
-	// Persist event
-	if b.log != nil {
-		if _, err := b.log.Append(e); err != nil {
-			b.logger.Error("failed to persist event", "type", e.EventType(), "error", err)
-			// Continue - event delivery is more important than persistence
-		}
-	}
-
 	// Deliver to type-specific subscribers (non-blocking)
 	for _, ch := range subs {
 		select {
@@ -75,8 +99,6 @@ func (b *Bus) Publish(ctx context.Context, e Event) error {
 				"type", e.EventType())
 		}
 	}
-
-	return nil
 }
 
 // Subscribe returns a channel for events of a specific type.
@@ -1,30 +1,54 @@
 // internal/events/download.go
 package events
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmunix/arrgo/pkg/release/scoring"
+)
+
 // Entity types
 const (
 	EntityDownload = "download"
 	EntityContent  = "content"
 	EntityEpisode  = "episode"
+	EntityLibrary  = "library" // The library as a whole (entity ID 0)
+	EntityAPI      = "api"     // An API request (entity ID 0)
 )
 
 // Event type constants
 const (
 	EventGrabRequested        = "grab.requested"
 	EventGrabSkipped          = "grab.skipped"
+	EventGrabRetrying         = "grab.retrying"
+	EventGrabFallback         = "grab.fallback"
+	EventPinnedGrabFailed     = "grab.pinned_failed"
 	EventDownloadCreated      = "download.created"
 	EventDownloadProgressed   = "download.progressed"
 	EventDownloadCompleted    = "download.completed"
 	EventDownloadFailed       = "download.failed"
+	EventDownloadReconciled   = "download.reconciled"
+	EventDownloadStuck        = "download.stuck"
+	EventDownloadRenamed      = "download.renamed"
 	EventImportStarted        = "import.started"
+	EventImportProgress       = "import.progress"
 	EventImportCompleted      = "import.completed"
 	EventImportFailed         = "import.failed"
 	EventImportSkipped        = "import.skipped"
 	EventCleanupStarted       = "cleanup.started"
 	EventCleanupCompleted     = "cleanup.completed"
+	EventJunkRemoved          = "cleanup.junk_removed"
+	EventHookFailed           = "hook.failed"
 	EventContentAdded         = "content.added"
 	EventContentStatusChanged = "content.status.changed"
+	EventContentUpdated       = "content.updated"
+	EventContentDeleted       = "content.deleted"
+	EventEpisodesSynced       = "episodes.synced"
+	EventSnapshotProgressed   = "snapshot.progressed"
+	EventFileMissing          = "file.missing"
 	EventPlexItemDetected     = "plex.item.detected"
+	EventAPIAudit             = "api.audit"
 )
 
 // GrabRequested is emitted when a user/API requests a download.
@@ -38,6 +62,93 @@ type GrabRequested struct {
 	DownloadURL      string  `json:"download_url"`
 	ReleaseName      string  `json:"release_name"`
 	Indexer          string  `json:"indexer"`
+	GUID             string  `json:"guid,omitempty"`     // Indexer release GUID (for blocklisting)
+	Protocol         string  `json:"protocol,omitempty"` // "usenet" or "torrent"; inferred from DownloadURL if empty
+	// Torrent options; nil uses the configured default. Clients without
+	// per-torrent options ignore them.
+	Sequential         *bool `json:"sequential,omitempty"`
+	FirstLastPiecePrio *bool `json:"first_last_piece_prio,omitempty"`
+	// Decision records why an automatic search chose this release; nil for manual grabs.
+	Decision *GrabDecision `json:"decision,omitempty"`
+	// Alternates are the next best releases, best first, tried in order when the
+	// download client rejects this one.
+	Alternates []GrabAlternate `json:"alternates,omitempty"`
+	// Pinned marks a grab of the release pinned for the content; if it fails
+	// the pin is dropped and the content is searched for as usual.
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// GrabAlternate is a fallback release for a grab.
+type GrabAlternate struct {
+	DownloadURL string `json:"download_url"`
+	ReleaseName string `json:"release_name"`
+	Indexer     string `json:"indexer"`
+	GUID        string `json:"guid,omitempty"`
+	Protocol    string `json:"protocol,omitempty"`
+}
+
+// GrabDecision records how a release was chosen from search results.
+type GrabDecision struct {
+	Profile    string          `json:"profile"`
+	Chosen     ScoredRelease   `json:"chosen"`
+	RunnersUp  []ScoredRelease `json:"runners_up,omitempty"` // Next best releases, best first
+	Candidates int             `json:"candidates"`           // Releases that passed the profile
+	// Season searches record whether a season pack or single episodes were
+	// searched for, and why; empty for other searches.
+	Strategy       string `json:"strategy,omitempty"`
+	StrategyReason string `json:"strategy_reason,omitempty"`
+}
+
+// ScoredRelease is a search result with the breakdown of its score.
+type ScoredRelease struct {
+	Title     string            `json:"title"`
+	Indexer   string            `json:"indexer"`
+	Group     string            `json:"group,omitempty"` // Release group; empty if it has none
+	GUID      string            `json:"guid,omitempty"`
+	SizeBytes int64             `json:"size_bytes"`
+	Score     int               `json:"score"`
+	Breakdown scoring.Breakdown `json:"breakdown"`
+}
+
+// Summary describes the decision in one line, e.g.
+// "score 105 (resolution 80, source 10, keywords 15) in profile hd; best of 4, runner-up 90".
+func (d *GrabDecision) Summary() string {
+	b := d.Chosen.Breakdown
+	var parts []string
+	for _, p := range []struct {
+		name   string
+		points int
+	}{
+		{"resolution", b.Resolution},
+		{"source", b.Source},
+		{"codec", b.Codec},
+		{"hdr", b.HDR},
+		{"audio", b.Audio},
+		{"remux", b.Remux},
+		{"language", b.Language},
+		{"group", b.Group},
+		{"keywords", b.Keywords},
+	} {
+		if p.points != 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", p.name, p.points))
+		}
+	}
+
+	summary := fmt.Sprintf("score %d", d.Chosen.Score)
+	if len(parts) > 0 {
+		summary += " (" + strings.Join(parts, ", ") + ")"
+	}
+	summary += " in profile " + d.Profile
+	if d.Candidates > 1 {
+		summary += fmt.Sprintf("; best of %d", d.Candidates)
+	}
+	if len(d.RunnersUp) > 0 {
+		summary += fmt.Sprintf(", runner-up %d", d.RunnersUp[0].Score)
+	}
+	if d.StrategyReason != "" {
+		summary += "; " + d.StrategyReason
+	}
+	return summary
 }
 
 // DownloadCreated is emitted when a download record is created.
@@ -51,6 +162,7 @@ type DownloadCreated struct {
 	IsCompleteSeason bool    `json:"is_complete_season,omitempty"` // True if grabbing complete season
 	ClientID         string  `json:"client_id"`                    // SABnzbd nzo_id
 	ReleaseName      string  `json:"release_name"`
+	Decision         string  `json:"decision,omitempty"` // GrabDecision summary for automatic grabs
 }
 
 // DownloadProgressed is emitted periodically with download progress.
@@ -78,6 +190,38 @@ type DownloadFailed struct {
 	Retryable  bool   `json:"retryable"`
 }
 
+// DownloadReconciled is emitted when verification corrects a download's status
+// to match the download client or the filesystem.
+type DownloadReconciled struct {
+	BaseEvent
+	DownloadID int64  `json:"download_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+}
+
+// DownloadStuck is emitted once per download when it stays in a status longer
+// than that status's alert threshold.
+type DownloadStuck struct {
+	BaseEvent
+	DownloadID       int64  `json:"download_id"`
+	ContentID        int64  `json:"content_id"`
+	ReleaseName      string `json:"release_name"`
+	Status           string `json:"status"`
+	StuckMinutes     int64  `json:"stuck_minutes"`     // Time in the status so far
+	ThresholdMinutes int64  `json:"threshold_minutes"` // Alert threshold of the status
+}
+
+// DownloadRenamed is emitted when the download client reports a job name
+// other than the recorded release name, as when SABnzbd appends ".1" to a
+// duplicate or the job is renamed in its UI. The download now carries NewName.
+type DownloadRenamed struct {
+	BaseEvent
+	DownloadID int64  `json:"download_id"`
+	OldName    string `json:"old_name"`
+	NewName    string `json:"new_name"`
+}
+
 // GrabSkipped is emitted when a grab is skipped due to existing quality.
 type GrabSkipped struct {
 	BaseEvent
@@ -85,7 +229,43 @@ type GrabSkipped struct {
 	ReleaseName     string `json:"release_name"`
 	ReleaseQuality  string `json:"release_quality"`  // e.g., "1080p"
 	ExistingQuality string `json:"existing_quality"` // e.g., "2160p"
-	Reason          string `json:"reason"`           // "existing_quality_equal_or_better"
+	Reason          string `json:"reason"`           // "existing_quality_equal_or_better" or "blocklisted"
+}
+
+// GrabRetrying is emitted when sending a grab to the download client failed
+// with a transient error and will be retried.
+type GrabRetrying struct {
+	BaseEvent
+	ContentID   int64  `json:"content_id"`
+	ReleaseName string `json:"release_name"`
+	Client      string `json:"client"`       // Configured download client name
+	Attempt     int    `json:"attempt"`      // Attempt that failed, starting at 1
+	MaxAttempts int    `json:"max_attempts"` // Total attempts allowed
+	Error       string `json:"error"`
+	RetryInMs   int64  `json:"retry_in_ms"` // Delay before the next attempt
+}
+
+// GrabFallback is emitted when the download client rejected a release and the
+// grab moves on to the next alternate.
+type GrabFallback struct {
+	BaseEvent
+	ContentID     int64  `json:"content_id"`
+	FailedRelease string `json:"failed_release"`
+	Error         string `json:"error"`
+	NextRelease   string `json:"next_release"`
+	Attempt       int    `json:"attempt"`      // Attempt that failed, starting at 1
+	MaxAttempts   int    `json:"max_attempts"` // Releases that will be tried in total
+}
+
+// PinnedGrabFailed is emitted when the release pinned for a content item
+// could not be grabbed or downloaded. The pin has been dropped, so the
+// content is searched for as usual.
+type PinnedGrabFailed struct {
+	BaseEvent
+	ContentID   int64  `json:"content_id"`
+	Season      *int   `json:"season,omitempty"` // Series: the season the pin was for
+	ReleaseName string `json:"release_name"`
+	Reason      string `json:"reason"`
 }
 
 // ImportSkipped is emitted when an import is skipped due to existing quality.
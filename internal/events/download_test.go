@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/pkg/release/scoring"
 )
 
 func TestGrabRequested_JSON(t *testing.T) {
@@ -70,3 +71,24 @@ func TestDownloadProgressed_JSON(t *testing.T) {
 	assert.Equal(t, int64(10485760), decoded.Speed)
 	assert.Equal(t, 300, decoded.ETA)
 }
+
+func TestGrabDecision_Summary(t *testing.T) {
+	d := &GrabDecision{
+		Profile: "hd",
+		Chosen: ScoredRelease{
+			Title:     "Movie.2024.1080p.BluRay.x264-FLUX",
+			Group:     "FLUX",
+			Score:     120,
+			Breakdown: scoring.Breakdown{Resolution: 80, Source: 10, Group: 15, Keywords: 15, Total: 120},
+		},
+		RunnersUp:  []ScoredRelease{{Title: "Movie.2024.1080p.WEB-DL", Score: 90}},
+		Candidates: 4,
+	}
+	assert.Equal(t, "score 120 (resolution 80, source 10, group 15, keywords 15) in profile hd; best of 4, runner-up 90", d.Summary())
+
+	single := &GrabDecision{Profile: "any", Chosen: ScoredRelease{Score: 40, Breakdown: scoring.Breakdown{Resolution: 40, Total: 40}}, Candidates: 1}
+	assert.Equal(t, "score 40 (resolution 40) in profile any", single.Summary())
+
+	single.Strategy, single.StrategyReason = "episodes", "single episodes: 1 of 10 episodes missing, below 50%"
+	assert.Equal(t, "score 40 (resolution 40) in profile any; single episodes: 1 of 10 episodes missing, below 50%", single.Summary())
+}
@@ -8,14 +8,32 @@ type ImportStarted struct {
 	SourcePath string `json:"source_path"`
 }
 
+// ImportProgress is emitted as the episodes of a season pack are copied: when
+// each starts, every few seconds while it copies, and when it is done.
+type ImportProgress struct {
+	BaseEvent
+	DownloadID  int64  `json:"download_id"`
+	ContentID   int64  `json:"content_id"`
+	SourceFile  string `json:"source_file"` // Video path relative to the download folder
+	Season      int    `json:"season,omitempty"`
+	Episode     int    `json:"episode,omitempty"`
+	BytesCopied int64  `json:"bytes_copied"`
+	TotalBytes  int64  `json:"total_bytes"`
+	Done        bool   `json:"done,omitempty"`  // The episode's import finished, successfully or not
+	Error       string `json:"error,omitempty"` // Why the episode failed; empty on success or while copying
+}
+
 // EpisodeImportResult tracks the outcome of importing a single episode.
 type EpisodeImportResult struct {
-	EpisodeID int64  `json:"episode_id"`
-	Season    int    `json:"season"`
-	Episode   int    `json:"episode"`
-	Success   bool   `json:"success"`
-	FilePath  string `json:"file_path,omitempty"` // Empty if failed
-	Error     string `json:"error,omitempty"`     // Empty if success
+	EpisodeID       int64  `json:"episode_id"`
+	FileID          int64  `json:"file_id,omitempty"` // Library file record; empty if failed
+	Season          int    `json:"season"`
+	Episode         int    `json:"episode"`
+	Success         bool   `json:"success"`
+	SourceFile      string `json:"source_file,omitempty"`      // Video path relative to the download folder
+	FilePath        string `json:"file_path,omitempty"`        // Empty if failed
+	Error           string `json:"error,omitempty"`            // Empty if success
+	AlreadyImported bool   `json:"already_imported,omitempty"` // Imported by an earlier attempt, skipped on reimport
 }
 
 // ImportCompleted is emitted when import succeeds.
@@ -60,6 +78,21 @@ type ImportFailed struct {
 	Reason     string `json:"reason"`
 }
 
+// HookFailed is emitted when a hook script fails to run, times out, or exits
+// non-zero. A failed post-import hook does not fail the import; a failed
+// pre-cleanup hook blocks the cleanup.
+type HookFailed struct {
+	BaseEvent
+	DownloadID int64  `json:"download_id"`
+	ContentID  int64  `json:"content_id"`
+	Hook       string `json:"hook"` // "post_import" or "pre_cleanup"
+	Path       string `json:"path"`
+	ExitCode   int    `json:"exit_code"` // -1 if the script did not exit on its own
+	Error      string `json:"error"`
+	Stderr     string `json:"stderr,omitempty"`
+	Blocked    bool   `json:"blocked,omitempty"` // True if the hook blocked cleanup
+}
+
 // CleanupStarted is emitted when source cleanup begins.
 type CleanupStarted struct {
 	BaseEvent
@@ -72,3 +105,13 @@ type CleanupCompleted struct {
 	BaseEvent
 	DownloadID int64 `json:"download_id"`
 }
+
+// JunkRemoved is emitted when a download's release directory held only junk,
+// such as .nfo files and par2 sets, and was removed.
+type JunkRemoved struct {
+	BaseEvent
+	DownloadID int64    `json:"download_id"`
+	SourcePath string   `json:"source_path"`
+	Removed    []string `json:"removed"` // Files and directories removed, the release directory last
+	Trigger    string   `json:"trigger"` // "import" or "cancel"
+}
@@ -19,6 +19,67 @@ type ContentStatusChanged struct {
 	NewStatus string `json:"new_status"`
 }
 
+// ContentUpdated is emitted when content is edited, listing the fields that changed.
+type ContentUpdated struct {
+	BaseEvent
+	ContentID int64         `json:"content_id"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// FieldChange records the old and new value of an edited field.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ContentDeleted is emitted when content is removed from the library.
+type ContentDeleted struct {
+	BaseEvent
+	ContentID         int64    `json:"content_id"`
+	ContentType       string   `json:"content_type"` // "movie" or "series"
+	Title             string   `json:"title"`
+	Year              int      `json:"year"`
+	DeletedFiles      []string `json:"deleted_files,omitempty"`      // Paths removed from disk
+	CanceledDownloads []int64  `json:"canceled_downloads,omitempty"` // Download IDs canceled
+	Excluded          bool     `json:"excluded,omitempty"`           // External ID added to the exclusions
+}
+
+// EpisodesSynced is emitted when a TVDB episode sync adds or changes episodes of a series.
+type EpisodesSynced struct {
+	BaseEvent
+	ContentID  int64  `json:"content_id"`
+	Title      string `json:"title"`
+	Total      int    `json:"total"`                 // Episodes listed by TVDB (specials excluded)
+	Inserted   int    `json:"inserted"`              // New episodes added as wanted
+	Updated    int    `json:"updated"`               // Existing episodes whose title or air date changed
+	NewSeasons []int  `json:"new_seasons,omitempty"` // Seasons the library did not have before
+}
+
+// SnapshotProgressed is emitted as a library snapshot is restored, after
+// each batch of items is committed. Done is set on the last one.
+type SnapshotProgressed struct {
+	BaseEvent
+	Mode      string `json:"mode"`      // "skip-existing" or "overwrite"
+	Processed int    `json:"processed"` // Items read from the snapshot so far
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Skipped   int    `json:"skipped"`
+	Failed    int    `json:"failed"`
+	Done      bool   `json:"done"`
+}
+
+// FileMissing is emitted when a library file is found gone from disk, e.g.
+// deleted outside arrgo. The file record is kept, marked missing.
+type FileMissing struct {
+	BaseEvent
+	FileID    int64  `json:"file_id"`
+	ContentID int64  `json:"content_id"`
+	EpisodeID *int64 `json:"episode_id,omitempty"`
+	Path      string `json:"path"`
+	Wanted    bool   `json:"wanted"` // No other file covers the movie or episode, so it is wanted again
+}
+
 // PlexItemDetected is emitted when Plex finds our imported file.
 type PlexItemDetected struct {
 	BaseEvent
@@ -31,6 +31,29 @@ func TestContentAdded_JSON(t *testing.T) {
 	assert.Equal(t, "The Matrix", decoded.Title)
 }
 
+func TestContentDeleted_JSON(t *testing.T) {
+	e := &ContentDeleted{
+		BaseEvent:         NewBaseEvent(EventContentDeleted, EntityContent, 42),
+		ContentID:         42,
+		ContentType:       "movie",
+		Title:             "The Matrix",
+		Year:              1999,
+		DeletedFiles:      []string{"/movies/The Matrix (1999)/The Matrix (1999).mkv"},
+		CanceledDownloads: []int64{7},
+	}
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	var decoded ContentDeleted
+	err = json.Unmarshal(data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), decoded.ContentID)
+	assert.Equal(t, e.DeletedFiles, decoded.DeletedFiles)
+	assert.Equal(t, []int64{7}, decoded.CanceledDownloads)
+}
+
 func TestPlexItemDetected_JSON(t *testing.T) {
 	e := &PlexItemDetected{
 		BaseEvent: NewBaseEvent(EventPlexItemDetected, EntityContent, 42),
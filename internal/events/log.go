@@ -3,7 +3,9 @@ package events
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -17,17 +19,46 @@ func NewEventLog(db *sql.DB) *EventLog {
 	return &EventLog{db: db}
 }
 
-// Append persists an event and returns its ID.
+// ErrEventNotFound is returned when an event is not in the log.
+var ErrEventNotFound = errors.New("event not found")
+
+// execer runs a statement on a database or transaction.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// Append persists an event that is delivered directly and returns its ID.
 func (l *EventLog) Append(e Event) (int64, error) {
+	return appendEvent(l.db, e, false)
+}
+
+// AppendPending persists an event for the outbox dispatcher to deliver and
+// returns its ID. The event stays pending until MarkProcessed.
+func (l *EventLog) AppendPending(e Event) (int64, error) {
+	return appendEvent(l.db, e, true)
+}
+
+// AppendPendingTx is AppendPending within tx, so the event is only queued for
+// delivery if the state change it announces is committed.
+func (l *EventLog) AppendPendingTx(tx *sql.Tx, e Event) (int64, error) {
+	return appendEvent(tx, e, true)
+}
+
+func appendEvent(db execer, e Event, pending bool) (int64, error) {
 	payload, err := json.Marshal(e)
 	if err != nil {
 		return 0, fmt.Errorf("marshal event: %w", err)
 	}
 
-	result, err := l.db.Exec(`
-		INSERT INTO events (event_type, entity_type, entity_id, payload, occurred_at)
-		VALUES (?, ?, ?, ?, ?)`,
-		e.EventType(), e.EntityType(), e.EntityID(), string(payload), e.OccurredAt(),
+	var processedAt *time.Time
+	if !pending {
+		now := time.Now()
+		processedAt = &now
+	}
+	result, err := db.Exec(`
+		INSERT INTO events (event_type, entity_type, entity_id, payload, occurred_at, processed_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		e.EventType(), e.EntityType(), e.EntityID(), string(payload), e.OccurredAt(), processedAt,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("insert event: %w", err)
@@ -45,12 +76,65 @@ type RawEvent struct {
 	Payload    string
 	OccurredAt time.Time
 	CreatedAt  time.Time
+	// ProcessedAt is when the event was delivered to subscribers; nil while
+	// pending in the outbox.
+	ProcessedAt *time.Time
+}
+
+const eventColumns = "id, event_type, entity_type, entity_id, payload, occurred_at, created_at, processed_at"
+
+// Get returns the event with the given ID, or ErrEventNotFound.
+func (l *EventLog) Get(id int64) (*RawEvent, error) {
+	rows, err := l.db.Query(`SELECT `+eventColumns+` FROM events WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query event: %w", err)
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrEventNotFound
+	}
+	return &events[0], nil
+}
+
+// Pending returns up to limit events awaiting delivery, oldest first.
+func (l *EventLog) Pending(limit int) ([]RawEvent, error) {
+	rows, err := l.db.Query(`
+		SELECT `+eventColumns+`
+		FROM events
+		WHERE processed_at IS NULL
+		ORDER BY id ASC
+		LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query pending events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// MarkProcessed records that an event has been delivered to its subscribers.
+func (l *EventLog) MarkProcessed(id int64) error {
+	result, err := l.db.Exec(`UPDATE events SET processed_at = ? WHERE id = ?`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("mark event %d processed: %w", id, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("mark event %d processed: %w", id, ErrEventNotFound)
+	}
+	return nil
 }
 
 // Since returns all events since the given time.
 func (l *EventLog) Since(t time.Time) ([]RawEvent, error) {
 	rows, err := l.db.Query(`
-		SELECT id, event_type, entity_type, entity_id, payload, occurred_at, created_at
+		SELECT `+eventColumns+`
 		FROM events
 		WHERE occurred_at >= ?
 		ORDER BY id ASC`,
@@ -67,7 +151,7 @@ func (l *EventLog) Since(t time.Time) ([]RawEvent, error) {
 // ForEntity returns all events for a specific entity.
 func (l *EventLog) ForEntity(entityType string, entityID int64) ([]RawEvent, error) {
 	rows, err := l.db.Query(`
-		SELECT id, event_type, entity_type, entity_id, payload, occurred_at, created_at
+		SELECT `+eventColumns+`
 		FROM events
 		WHERE entity_type = ? AND entity_id = ?
 		ORDER BY id ASC`,
@@ -81,22 +165,73 @@ func (l *EventLog) ForEntity(entityType string, entityID int64) ([]RawEvent, err
 	return scanEvents(rows)
 }
 
-// Recent returns the last N events in reverse chronological order with pagination.
-// Returns events, total count, and any error.
-func (l *EventLog) Recent(limit, offset int) ([]RawEvent, int, error) {
-	// Get total count
+// EventFilter selects events for List. Zero fields match everything.
+type EventFilter struct {
+	EntityType string
+	EntityID   *int64
+	EventTypes []string   // Matches any of these types
+	Since      *time.Time // occurred_at >= Since
+	Until      *time.Time // occurred_at < Until
+	Limit      int        // Maximum number of results (0 = unlimited)
+	Offset     int        // Number of results to skip
+}
+
+// List returns events matching the filter in reverse chronological order,
+// along with the total count before pagination.
+func (l *EventLog) List(f EventFilter) ([]RawEvent, int, error) {
+	var conditions []string
+	var args []any
+
+	if f.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, f.EntityType)
+	}
+	if f.EntityID != nil {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, *f.EntityID)
+	}
+	if len(f.EventTypes) > 0 {
+		conditions = append(conditions, "event_type IN (?"+strings.Repeat(", ?", len(f.EventTypes)-1)+")")
+		for _, t := range f.EventTypes {
+			args = append(args, t)
+		}
+	}
+	// occurred_at is stored in local time (see NewBaseEvent), so bounds must
+	// be too for the comparison to hold.
+	if f.Since != nil {
+		conditions = append(conditions, "occurred_at >= ?")
+		args = append(args, f.Since.Local())
+	}
+	if f.Until != nil {
+		conditions = append(conditions, "occurred_at < ?")
+		args = append(args, f.Until.Local())
+	}
+
+	return l.list(conditions, args, f.Limit, f.Offset)
+}
+
+// list returns events matching all conditions, newest first, and their
+// total count before pagination.
+func (l *EventLog) list(conditions []string, args []any, limit, offset int) ([]RawEvent, int, error) {
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// G202: False positive - whereClause contains only placeholder conditions,
+	// actual values are passed via args parameter (parameterized query).
 	var total int
-	if err := l.db.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&total); err != nil {
+	if err := l.db.QueryRow("SELECT COUNT(*) FROM events "+whereClause, args...).Scan(&total); err != nil { //nolint:gosec
 		return nil, 0, fmt.Errorf("count events: %w", err)
 	}
 
-	rows, err := l.db.Query(`
-		SELECT id, event_type, entity_type, entity_id, payload, occurred_at, created_at
-		FROM events
-		ORDER BY id DESC
-		LIMIT ? OFFSET ?`,
-		limit, offset,
-	)
+	query := "SELECT " + eventColumns + " FROM events " + //nolint:gosec
+		whereClause + " ORDER BY id DESC"
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	}
+
+	rows, err := l.db.Query(query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("query events: %w", err)
 	}
@@ -109,6 +244,12 @@ func (l *EventLog) Recent(limit, offset int) ([]RawEvent, int, error) {
 	return events, total, nil
 }
 
+// Recent returns the last N events in reverse chronological order with pagination.
+// Returns events, total count, and any error.
+func (l *EventLog) Recent(limit, offset int) ([]RawEvent, int, error) {
+	return l.List(EventFilter{Limit: limit, Offset: offset})
+}
+
 // Prune removes events older than the given duration.
 func (l *EventLog) Prune(olderThan time.Duration) (int64, error) {
 	cutoff := time.Now().Add(-olderThan)
@@ -123,9 +264,13 @@ func scanEvents(rows *sql.Rows) ([]RawEvent, error) {
 	var events []RawEvent
 	for rows.Next() {
 		var e RawEvent
-		if err := rows.Scan(&e.ID, &e.EventType, &e.EntityType, &e.EntityID, &e.Payload, &e.OccurredAt, &e.CreatedAt); err != nil {
+		var processedAt sql.NullTime
+		if err := rows.Scan(&e.ID, &e.EventType, &e.EntityType, &e.EntityID, &e.Payload, &e.OccurredAt, &e.CreatedAt, &processedAt); err != nil {
 			return nil, fmt.Errorf("scan event: %w", err)
 		}
+		if processedAt.Valid {
+			e.ProcessedAt = &processedAt.Time
+		}
 		events = append(events, e)
 	}
 	return events, rows.Err()
@@ -25,10 +25,11 @@ func setupTestDB(t *testing.T) *sql.DB {
 			entity_id INTEGER NOT NULL,
 			payload TEXT NOT NULL,
 			occurred_at TIMESTAMP NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			processed_at TIMESTAMP
 		);
-		CREATE INDEX idx_events_type ON events(event_type);
-		CREATE INDEX idx_events_entity ON events(entity_type, entity_id);
+		CREATE INDEX idx_events_type_occurred ON events(event_type, occurred_at);
+		CREATE INDEX idx_events_entity_occurred ON events(entity_type, entity_id, occurred_at);
 		CREATE INDEX idx_events_occurred ON events(occurred_at);
 	`)
 	require.NoError(t, err)
@@ -195,6 +196,106 @@ func TestEventLog_Recent_Pagination(t *testing.T) {
 	assert.Equal(t, int64(2), events[1].EntityID)
 }
 
+func TestEventLog_List_Filter(t *testing.T) {
+	db := setupTestDB(t)
+	log := NewEventLog(db)
+
+	now := time.Now()
+	for _, e := range []struct {
+		eventType  string
+		entityType string
+		entityID   int64
+		at         time.Time
+	}{
+		{EventContentAdded, EntityContent, 1, now.Add(-72 * time.Hour)},
+		{EventContentStatusChanged, EntityContent, 1, now.Add(-2 * time.Hour)},
+		{EventGrabSkipped, EntityContent, 1, now.Add(-time.Hour)},
+		{EventContentAdded, EntityContent, 2, now.Add(-time.Hour)},
+		{EventDownloadCreated, EntityDownload, 1, now},
+	} {
+		_, err := db.Exec(`
+			INSERT INTO events (event_type, entity_type, entity_id, payload, occurred_at)
+			VALUES (?, ?, ?, '{}', ?)`,
+			e.eventType, e.entityType, e.entityID, e.at,
+		)
+		require.NoError(t, err)
+	}
+
+	contentID := int64(1)
+	events, total, err := log.List(EventFilter{EntityType: EntityContent, EntityID: &contentID})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, events, 3)
+	assert.Equal(t, EventGrabSkipped, events[0].EventType, "newest first")
+
+	events, total, err = log.List(EventFilter{EventTypes: []string{EventContentAdded, EventDownloadCreated}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, events, 3)
+
+	// Bounds in another zone compare by instant
+	since := now.Add(-3 * time.Hour).In(time.FixedZone("UTC+5", 5*60*60))
+	until := now.Add(-30 * time.Minute).In(time.FixedZone("UTC-7", -7*60*60))
+	events, total, err = log.List(EventFilter{EntityType: EntityContent, Since: &since, Until: &until})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, events, 3)
+
+	events, total, err = log.List(EventFilter{EntityType: EntityContent, Since: &since, Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(1), events[0].EntityID)
+	assert.Equal(t, EventGrabSkipped, events[0].EventType)
+}
+
+func TestEventLog_Pending(t *testing.T) {
+	db := setupTestDB(t)
+	log := NewEventLog(db)
+
+	delivered, err := log.Append(&testEvent{BaseEvent: NewBaseEvent("test.info", "test", 1)})
+	require.NoError(t, err)
+	first, err := log.AppendPending(&testEvent{BaseEvent: NewBaseEvent("test.durable", "test", 1)})
+	require.NoError(t, err)
+	second, err := log.AppendPending(&testEvent{BaseEvent: NewBaseEvent("test.durable", "test", 2)})
+	require.NoError(t, err)
+
+	pending, err := log.Pending(10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2, "directly delivered events are not pending")
+	assert.Equal(t, first, pending[0].ID, "oldest first")
+	assert.Nil(t, pending[0].ProcessedAt)
+
+	require.NoError(t, log.MarkProcessed(first))
+	pending, err = log.Pending(10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, second, pending[0].ID)
+
+	raw, err := log.Get(delivered)
+	require.NoError(t, err)
+	assert.NotNil(t, raw.ProcessedAt)
+
+	_, err = log.Get(999)
+	require.ErrorIs(t, err, ErrEventNotFound)
+	require.ErrorIs(t, log.MarkProcessed(999), ErrEventNotFound)
+}
+
+func TestEventLog_AppendPendingTx(t *testing.T) {
+	db := setupTestDB(t)
+	log := NewEventLog(db)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = log.AppendPendingTx(tx, &testEvent{BaseEvent: NewBaseEvent("test.durable", "test", 1)})
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	pending, err := log.Pending(10)
+	require.NoError(t, err)
+	assert.Empty(t, pending, "rolled back with the transaction")
+}
+
 // testEvent is a concrete event type for testing
 type testEvent struct {
 	BaseEvent
@@ -0,0 +1,186 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoOutbox is returned by PublishTx when the bus is not in outbox mode.
+var ErrNoOutbox = errors.New("event outbox not enabled")
+
+const (
+	outboxBatchSize    = 100         // Pending events loaded per query
+	outboxPollInterval = time.Second // Catches events queued by PublishTx and resumes after startup
+)
+
+// EnableOutbox switches the bus to outbox mode. Events of the types reg marks
+// durable are written to the event log as pending by Publish and delivered by
+// RunOutbox, which marks them processed; events still pending when arrgod
+// stops are delivered after the next start. Informational events keep the
+// direct path. Has no effect without an event log.
+// Call before events are published.
+func (b *Bus) EnableOutbox(reg *Registry) {
+	if b.log == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.registry = reg
+}
+
+// PublishTx queues an event for delivery within tx, so it is only delivered if
+// the state change it announces is committed. The dispatcher picks it up on
+// its next poll after the commit. Returns ErrNoOutbox unless in outbox mode.
+func (b *Bus) PublishTx(tx *sql.Tx, e Event) error {
+	b.mu.RLock()
+	enabled := b.registry != nil
+	b.mu.RUnlock()
+	if !enabled {
+		return ErrNoOutbox
+	}
+	if _, err := b.log.AppendPendingTx(tx, e); err != nil {
+		return err
+	}
+	b.notifyOutbox()
+	return nil
+}
+
+// notifyOutbox wakes the dispatcher if it is not already due to run.
+func (b *Bus) notifyOutbox() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// RunOutbox delivers pending events until ctx is canceled. Each is handed to
+// every subscriber of its type, waiting for room in their channels, and then
+// marked processed; handlers may therefore see an event again if arrgod stops
+// part way through and must tolerate duplicates. The first pass runs one poll
+// interval after start, once subscribers started alongside have subscribed,
+// and resumes events left pending by the previous run.
+func (b *Bus) RunOutbox(ctx context.Context) error {
+	b.mu.RLock()
+	enabled := b.registry != nil
+	b.mu.RUnlock()
+	if !enabled {
+		return ErrNoOutbox
+	}
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	started := false
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			started = true
+		case <-b.wake:
+			if !started {
+				continue // The first tick covers it
+			}
+		}
+		b.dispatchPending(ctx)
+	}
+}
+
+// dispatchPending delivers pending events in order until none are left.
+func (b *Bus) dispatchPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		pending, err := b.log.Pending(outboxBatchSize)
+		if err != nil {
+			b.logger.Error("failed to load pending events", "error", err)
+			return
+		}
+		for _, raw := range pending {
+			if !b.dispatch(ctx, raw) {
+				return
+			}
+		}
+		if len(pending) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+// dispatch delivers one pending event and marks it processed.
+// Returns false if delivery was interrupted and the event is still pending.
+func (b *Bus) dispatch(ctx context.Context, raw RawEvent) bool {
+	e, err := b.registry.Unmarshal(raw)
+	if err != nil {
+		b.logger.Error("dropping undecodable event", "id", raw.ID, "type", raw.EventType, "error", err)
+	} else if !b.deliverWait(ctx, e) {
+		return false
+	}
+	if err := b.log.MarkProcessed(raw.ID); err != nil {
+		b.logger.Error("failed to mark event processed", "id", raw.ID, "type", raw.EventType, "error", err)
+	}
+	return true
+}
+
+// deliverWait hands an event to every subscriber of its type, waiting for
+// room in their channels, and to all-event subscribers without blocking.
+// Returns false if ctx is canceled or the bus closed first.
+func (b *Bus) deliverWait(ctx context.Context, e Event) bool {
+	// Holding the read lock keeps Close from closing a channel mid-send
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return false
+	}
+
+	for _, ch := range b.subscribers[e.EventType()] {
+		select {
+		case ch <- e:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	for _, ch := range b.allSubs {
+		select {
+		case ch <- e:
+		default:
+			b.logger.Warn("all-subscriber channel full, dropping event", "type", e.EventType())
+		}
+	}
+	return true
+}
+
+// Replay delivers a logged event to its subscribers again and marks it
+// processed, to recover from a handler that failed to act on it. Returns
+// ErrEventNotFound if the event is not in the log.
+func (b *Bus) Replay(ctx context.Context, id int64) (Event, error) {
+	if b.log == nil {
+		return nil, errors.New("event log not configured")
+	}
+	raw, err := b.log.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	reg := b.registry
+	b.mu.RUnlock()
+	if reg == nil {
+		reg = DefaultRegistry()
+	}
+	e, err := reg.Unmarshal(*raw)
+	if err != nil {
+		return nil, err
+	}
+	if !b.deliverWait(ctx, e) {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("replay event %d: %w", id, err)
+		}
+		return nil, fmt.Errorf("replay event %d: event bus closed", id)
+	}
+	if err := b.log.MarkProcessed(id); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,130 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outboxRegistry registers test.durable as durable and test.info as informational.
+func outboxRegistry() *Registry {
+	r := NewRegistry()
+	r.RegisterDurable("test.durable", func() Event { return &testEvent{} })
+	r.Register("test.info", func() Event { return &testEvent{} })
+	return r
+}
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for event")
+		return nil
+	}
+}
+
+func TestBus_Outbox(t *testing.T) {
+	db := setupTestDB(t)
+	log := NewEventLog(db)
+
+	// Left pending by a previous run that stopped before delivering it
+	resumedID, err := log.AppendPending(&testEvent{BaseEvent: NewBaseEvent("test.durable", "test", 1), Message: "resumed"})
+	require.NoError(t, err)
+
+	bus := NewBus(log, nil)
+	defer bus.Close()
+	bus.EnableOutbox(outboxRegistry())
+	durable := bus.Subscribe("test.durable", 10)
+	info := bus.Subscribe("test.info", 10)
+
+	// Informational events are delivered directly and logged as processed
+	require.NoError(t, bus.Publish(context.Background(), &testEvent{BaseEvent: NewBaseEvent("test.info", "test", 2)}))
+	assert.Equal(t, int64(2), receive(t, info).EntityID())
+
+	// Durable events wait for the dispatcher
+	require.NoError(t, bus.Publish(context.Background(), &testEvent{BaseEvent: NewBaseEvent("test.durable", "test", 3), Message: "published"}))
+	assert.Empty(t, durable)
+	pending, err := log.Pending(10)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bus.RunOutbox(ctx) }()
+
+	first := receive(t, durable).(*testEvent)
+	second := receive(t, durable).(*testEvent)
+	assert.Equal(t, "resumed", first.Message, "pending events are delivered in order")
+	assert.Equal(t, "published", second.Message)
+
+	// Events published while running are delivered without waiting for a poll
+	require.NoError(t, bus.Publish(context.Background(), &testEvent{BaseEvent: NewBaseEvent("test.durable", "test", 4)}))
+	assert.Equal(t, int64(4), receive(t, durable).EntityID())
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	pending, err = log.Pending(10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	raw, err := log.Get(resumedID)
+	require.NoError(t, err)
+	assert.NotNil(t, raw.ProcessedAt)
+}
+
+func TestBus_PublishTx(t *testing.T) {
+	db := setupTestDB(t)
+	bus := NewBus(NewEventLog(db), nil)
+	defer bus.Close()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.ErrorIs(t, bus.PublishTx(tx, &testEvent{BaseEvent: NewBaseEvent("test.durable", "test", 1)}), ErrNoOutbox)
+	require.NoError(t, tx.Rollback())
+
+	bus.EnableOutbox(outboxRegistry())
+	tx, err = db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, bus.PublishTx(tx, &testEvent{BaseEvent: NewBaseEvent("test.durable", "test", 1)}))
+	require.NoError(t, tx.Commit())
+
+	pending, err := bus.log.Pending(10)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
+
+func TestBus_Replay(t *testing.T) {
+	db := setupTestDB(t)
+	log := NewEventLog(db)
+	bus := NewBus(log, nil)
+	defer bus.Close()
+	ch := bus.Subscribe(EventGrabRequested, 10)
+
+	grab := &GrabRequested{BaseEvent: NewBaseEvent(EventGrabRequested, EntityDownload, 0), ContentID: 7, ReleaseName: "Movie.2024.1080p"}
+	require.NoError(t, bus.Publish(context.Background(), grab))
+	receive(t, ch)
+
+	raw, _, err := log.List(EventFilter{EventTypes: []string{EventGrabRequested}})
+	require.NoError(t, err)
+	require.Len(t, raw, 1)
+
+	e, err := bus.Replay(context.Background(), raw[0].ID)
+	require.NoError(t, err)
+	replayed, ok := receive(t, ch).(*GrabRequested)
+	require.True(t, ok)
+	assert.Equal(t, int64(7), replayed.ContentID)
+	assert.Equal(t, e, Event(replayed))
+
+	_, err = bus.Replay(context.Background(), 999)
+	require.ErrorIs(t, err, ErrEventNotFound)
+
+	id, err := log.Append(&testEvent{BaseEvent: NewBaseEvent("test.unknown", "test", 1)})
+	require.NoError(t, err)
+	_, err = bus.Replay(context.Background(), id)
+	require.ErrorIs(t, err, ErrUnknownEventType)
+}
@@ -3,21 +3,27 @@ package events
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
+// ErrUnknownEventType is returned when unmarshaling an unregistered event type.
+var ErrUnknownEventType = errors.New("unknown event type")
+
 // EventFactory creates a new zero-value event of a specific type.
 type EventFactory func() Event
 
 // Registry maps event types to their factories for deserialization.
 type Registry struct {
 	factories map[string]EventFactory
+	durable   map[string]bool // Event types delivered through the outbox
 }
 
 // NewRegistry creates a new event registry.
 func NewRegistry() *Registry {
 	return &Registry{
 		factories: make(map[string]EventFactory),
+		durable:   make(map[string]bool),
 	}
 }
 
@@ -26,11 +32,23 @@ func (r *Registry) Register(eventType string, factory EventFactory) {
 	r.factories[eventType] = factory
 }
 
+// RegisterDurable adds an event type whose delivery must survive a restart.
+// A bus in outbox mode persists these before delivering them; see Bus.EnableOutbox.
+func (r *Registry) RegisterDurable(eventType string, factory EventFactory) {
+	r.Register(eventType, factory)
+	r.durable[eventType] = true
+}
+
+// Durable reports whether events of the type were registered as durable.
+func (r *Registry) Durable(eventType string) bool {
+	return r.durable[eventType]
+}
+
 // Unmarshal deserializes a raw event into its concrete type.
 func (r *Registry) Unmarshal(raw RawEvent) (Event, error) {
 	factory, ok := r.factories[raw.EventType]
 	if !ok {
-		return nil, fmt.Errorf("unknown event type: %s", raw.EventType)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, raw.EventType)
 	}
 
 	event := factory()
@@ -42,31 +60,48 @@ func (r *Registry) Unmarshal(raw RawEvent) (Event, error) {
 }
 
 // DefaultRegistry returns a registry with all standard event types registered.
+// Events that handlers act on are durable; the rest are informational.
 func DefaultRegistry() *Registry {
 	r := NewRegistry()
 
 	// Download events
-	r.Register(EventGrabRequested, func() Event { return &GrabRequested{} })
+	r.RegisterDurable(EventGrabRequested, func() Event { return &GrabRequested{} })
+	r.Register(EventGrabRetrying, func() Event { return &GrabRetrying{} })
+	r.Register(EventGrabFallback, func() Event { return &GrabFallback{} })
+	r.RegisterDurable(EventPinnedGrabFailed, func() Event { return &PinnedGrabFailed{} })
 	r.Register(EventDownloadCreated, func() Event { return &DownloadCreated{} })
 	r.Register(EventDownloadProgressed, func() Event { return &DownloadProgressed{} })
-	r.Register(EventDownloadCompleted, func() Event { return &DownloadCompleted{} })
-	r.Register(EventDownloadFailed, func() Event { return &DownloadFailed{} })
+	r.RegisterDurable(EventDownloadCompleted, func() Event { return &DownloadCompleted{} })
+	r.RegisterDurable(EventDownloadFailed, func() Event { return &DownloadFailed{} })
+	r.Register(EventDownloadReconciled, func() Event { return &DownloadReconciled{} })
+	r.RegisterDurable(EventDownloadStuck, func() Event { return &DownloadStuck{} })
+	r.Register(EventDownloadRenamed, func() Event { return &DownloadRenamed{} })
 
 	// Import events
 	r.Register(EventImportStarted, func() Event { return &ImportStarted{} })
-	r.Register(EventImportCompleted, func() Event { return &ImportCompleted{} })
-	r.Register(EventImportFailed, func() Event { return &ImportFailed{} })
+	r.Register(EventImportProgress, func() Event { return &ImportProgress{} })
+	r.RegisterDurable(EventImportCompleted, func() Event { return &ImportCompleted{} })
+	r.RegisterDurable(EventImportFailed, func() Event { return &ImportFailed{} })
+	r.RegisterDurable(EventImportSkipped, func() Event { return &ImportSkipped{} })
 
 	// Cleanup events
 	r.Register(EventCleanupStarted, func() Event { return &CleanupStarted{} })
 	r.Register(EventCleanupCompleted, func() Event { return &CleanupCompleted{} })
+	r.Register(EventJunkRemoved, func() Event { return &JunkRemoved{} })
+	r.Register(EventHookFailed, func() Event { return &HookFailed{} })
 
 	// Library events
 	r.Register(EventContentAdded, func() Event { return &ContentAdded{} })
 	r.Register(EventContentStatusChanged, func() Event { return &ContentStatusChanged{} })
+	r.Register(EventContentUpdated, func() Event { return &ContentUpdated{} })
+	r.Register(EventContentDeleted, func() Event { return &ContentDeleted{} })
+	r.Register(EventEpisodesSynced, func() Event { return &EpisodesSynced{} })
+	r.Register(EventSnapshotProgressed, func() Event { return &SnapshotProgressed{} })
+	r.Register(EventFileMissing, func() Event { return &FileMissing{} })
+	r.Register(EventAPIAudit, func() Event { return &APIAudit{} })
 
 	// Plex events
-	r.Register(EventPlexItemDetected, func() Event { return &PlexItemDetected{} })
+	r.RegisterDurable(EventPlexItemDetected, func() Event { return &PlexItemDetected{} })
 
 	return r
 }
@@ -67,13 +67,23 @@ func TestDefaultRegistry(t *testing.T) {
 		EventDownloadProgressed,
 		EventDownloadCompleted,
 		EventDownloadFailed,
+		EventDownloadReconciled,
+		EventDownloadStuck,
+		EventDownloadRenamed,
 		EventImportStarted,
 		EventImportCompleted,
 		EventImportFailed,
+		EventImportSkipped,
 		EventCleanupStarted,
 		EventCleanupCompleted,
+		EventJunkRemoved,
 		EventContentAdded,
 		EventContentStatusChanged,
+		EventContentUpdated,
+		EventContentDeleted,
+		EventEpisodesSynced,
+		EventSnapshotProgressed,
+		EventFileMissing,
 		EventPlexItemDetected,
 	}
 
@@ -6,17 +6,21 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
-	"strings"
+	"strconv"
 	"sync"
 
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/importer"
 )
 
 // CleanupConfig configures the cleanup handler.
 type CleanupConfig struct {
 	DownloadRoot string
 	Enabled      bool
+	PreCleanup   *importer.Hook // Run before deleting source files; a non-zero exit blocks cleanup (optional)
+	// Leftovers that don't hold up removing a release directory after import (zero: none)
+	Junk importer.JunkRules
 }
 
 // pendingCleanup tracks downloads awaiting Plex verification.
@@ -24,6 +28,7 @@ type pendingCleanup struct {
 	DownloadID  int64
 	ContentID   int64
 	ReleaseName string
+	Category    string
 }
 
 // CleanupHandler cleans up source files after Plex verification.
@@ -137,7 +142,10 @@ func (h *CleanupHandler) handleImportSkipped(ctx context.Context, e *events.Impo
 		"reason", e.Reason)
 
 	// Perform cleanup immediately (Plex already has content)
-	sourcePath := filepath.Join(h.config.DownloadRoot, dl.ReleaseName)
+	sourcePath := download.SourcePath(h.config.DownloadRoot, dl)
+	if !h.runPreCleanupHook(ctx, e.DownloadID, e.ContentID, dl.ReleaseName, sourcePath) {
+		return
+	}
 
 	// Emit CleanupStarted event
 	if err := h.Bus().Publish(ctx, &events.CleanupStarted{
@@ -174,7 +182,17 @@ func (h *CleanupHandler) handleImportSkipped(ctx context.Context, e *events.Impo
 }
 
 // handleImportCompleted tracks pending cleanup for the imported content.
-func (h *CleanupHandler) handleImportCompleted(_ context.Context, e *events.ImportCompleted) {
+// A partially imported season pack is not cleaned up: its source is needed to
+// reimport the episodes that failed.
+func (h *CleanupHandler) handleImportCompleted(ctx context.Context, e *events.ImportCompleted) {
+	if !e.AllSucceeded() {
+		h.Logger().Info("partial import, keeping source for reimport",
+			"download_id", e.DownloadID,
+			"episodes_success", e.SuccessCount(),
+			"episodes_total", len(e.EpisodeResults))
+		return
+	}
+
 	// Get download to retrieve release name
 	dl, err := h.store.Get(e.DownloadID)
 	if err != nil {
@@ -190,6 +208,7 @@ func (h *CleanupHandler) handleImportCompleted(_ context.Context, e *events.Impo
 		DownloadID:  e.DownloadID,
 		ContentID:   e.ContentID,
 		ReleaseName: dl.ReleaseName,
+		Category:    dl.Category,
 	}
 	h.mu.Unlock()
 
@@ -197,6 +216,42 @@ func (h *CleanupHandler) handleImportCompleted(_ context.Context, e *events.Impo
 		"download_id", e.DownloadID,
 		"content_id", e.ContentID,
 		"release_name", dl.ReleaseName)
+
+	// A release directory the import emptied of media goes now
+	if h.config.Enabled && len(h.config.Junk.Patterns) > 0 {
+		h.removeJunk(ctx, dl)
+	}
+}
+
+// removeJunk removes the download's release directory if only junk is left in
+// it, and publishes what was removed as JunkRemoved.
+func (h *CleanupHandler) removeJunk(ctx context.Context, dl *download.Download) {
+	sourcePath := download.SourcePath(h.config.DownloadRoot, dl)
+	if !h.underRoot(sourcePath) {
+		return
+	}
+	removed, err := importer.RemoveJunk(sourcePath, h.config.Junk)
+	if err != nil {
+		h.Logger().Error("junk cleanup failed", "download_id", dl.ID, "source_path", sourcePath, "error", err)
+		return
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	h.Logger().Info("removed junk-only release directory",
+		"download_id", dl.ID,
+		"source_path", sourcePath,
+		"removed", len(removed))
+	if err := h.Bus().Publish(ctx, &events.JunkRemoved{
+		BaseEvent:  events.NewBaseEvent(events.EventJunkRemoved, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		SourcePath: sourcePath,
+		Removed:    removed,
+		Trigger:    "import",
+	}); err != nil {
+		h.Logger().Error("failed to publish JunkRemoved event", "error", err)
+	}
 }
 
 // handlePlexDetected performs cleanup if pending cleanup exists for the content.
@@ -225,7 +280,13 @@ func (h *CleanupHandler) handlePlexDetected(ctx context.Context, e *events.PlexI
 		"release_name", pending.ReleaseName)
 
 	// Perform cleanup
-	sourcePath := filepath.Join(h.config.DownloadRoot, pending.ReleaseName)
+	sourcePath := download.SourcePath(h.config.DownloadRoot, &download.Download{
+		ReleaseName: pending.ReleaseName,
+		Category:    pending.Category,
+	})
+	if !h.runPreCleanupHook(ctx, pending.DownloadID, pending.ContentID, pending.ReleaseName, sourcePath) {
+		return
+	}
 
 	// Emit CleanupStarted event
 	if err := h.Bus().Publish(ctx, &events.CleanupStarted{
@@ -283,35 +344,75 @@ func (h *CleanupHandler) handlePlexDetected(ctx context.Context, e *events.PlexI
 		"source_path", sourcePath)
 }
 
+// runPreCleanupHook runs the pre-cleanup hook, if configured, and reports
+// whether cleanup may proceed. A failing hook blocks cleanup and leaves the
+// download in its current state.
+func (h *CleanupHandler) runPreCleanupHook(ctx context.Context, downloadID, contentID int64, releaseName, sourcePath string) bool {
+	if h.config.PreCleanup == nil {
+		return true
+	}
+
+	result := h.config.PreCleanup.Run(ctx, map[string]string{
+		"ARRGO_DOWNLOAD_ID":  strconv.FormatInt(downloadID, 10),
+		"ARRGO_CONTENT_ID":   strconv.FormatInt(contentID, 10),
+		"ARRGO_RELEASE_NAME": releaseName,
+		"ARRGO_SOURCE_PATH":  sourcePath,
+	})
+	if !result.Failed() {
+		h.Logger().Debug("pre-cleanup hook passed",
+			"download_id", downloadID,
+			"duration_ms", result.Duration.Milliseconds())
+		return true
+	}
+
+	h.Logger().Warn("pre-cleanup hook blocked cleanup",
+		"download_id", downloadID,
+		"source_path", sourcePath,
+		"exit_code", result.ExitCode,
+		"error", result.Err,
+		"stderr", result.Stderr)
+
+	if err := h.Bus().Publish(ctx, &events.HookFailed{
+		BaseEvent:  events.NewBaseEvent(events.EventHookFailed, events.EntityDownload, downloadID),
+		DownloadID: downloadID,
+		ContentID:  contentID,
+		Hook:       result.Hook,
+		Path:       result.Path,
+		ExitCode:   result.ExitCode,
+		Error:      result.Err.Error(),
+		Stderr:     result.Stderr,
+		Blocked:    true,
+	}); err != nil {
+		h.Logger().Error("failed to publish HookFailed event", "error", err)
+	}
+	return false
+}
+
 // ErrPathOutsideRoot is returned when cleanup path is outside download root.
 var ErrPathOutsideRoot = os.ErrPermission
 
+// underRoot reports whether path is under DownloadRoot, logging a refusal if not.
+func (h *CleanupHandler) underRoot(path string) bool {
+	if download.UnderRoot(h.config.DownloadRoot, path) {
+		return true
+	}
+	h.Logger().Warn("refusing to delete path outside download root",
+		"source_path", path,
+		"download_root", h.config.DownloadRoot)
+	return false
+}
+
 // cleanupSource safely deletes files under DownloadRoot.
 func (h *CleanupHandler) cleanupSource(sourcePath string) error {
-	// Resolve absolute paths for safety check
-	absSource, err := filepath.Abs(sourcePath)
-	if err != nil {
-		return err
+	// Ensure source path is under download root (prevent path traversal)
+	if !h.underRoot(sourcePath) {
+		return ErrPathOutsideRoot
 	}
-
-	absRoot, err := filepath.Abs(h.config.DownloadRoot)
+	absSource, err := filepath.Abs(sourcePath)
 	if err != nil {
 		return err
 	}
 
-	// Ensure source path is under download root (prevent path traversal)
-	// Use filepath.Clean to normalize paths before comparison
-	cleanSource := filepath.Clean(absSource)
-	cleanRoot := filepath.Clean(absRoot)
-
-	// Source must be under root (add separator to prevent /downloads matching /downloads-other)
-	if !strings.HasPrefix(cleanSource, cleanRoot+string(filepath.Separator)) && cleanSource != cleanRoot {
-		h.Logger().Warn("refusing to delete path outside download root",
-			"source_path", sourcePath,
-			"download_root", h.config.DownloadRoot)
-		return ErrPathOutsideRoot
-	}
-
 	// Check if path exists
 	info, err := os.Stat(absSource)
 	if os.IsNotExist(err) {
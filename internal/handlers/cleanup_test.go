@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/importer"
 	_ "modernc.org/sqlite"
 )
 
@@ -32,6 +33,7 @@ func setupCleanupTestDB(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL,
 			release_name TEXT NOT NULL,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT NOT NULL,
 			added_at TIMESTAMP NOT NULL,
 			completed_at TIMESTAMP,
@@ -41,7 +43,10 @@ func setupCleanupTestDB(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
 		);
 		CREATE TABLE download_episodes (
 			download_id INTEGER NOT NULL,
@@ -246,6 +251,72 @@ func TestCleanupHandler_Disabled(t *testing.T) {
 	assert.NoError(t, err, "release directory should still exist")
 }
 
+func TestCleanupHandler_RemovesJunkAfterImport(t *testing.T) {
+	db := setupCleanupTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	downloadRoot := filepath.Join(t.TempDir(), "downloads")
+
+	// The import moved the video away, leaving scene info and a repair set
+	releaseDir := filepath.Join(downloadRoot, "Test.Movie.2024.1080p")
+	require.NoError(t, os.MkdirAll(releaseDir, 0755))
+	for _, name := range []string{"test.movie.nfo", "test.movie.par2", "test.movie.vol00+01.par2"} {
+		require.NoError(t, os.WriteFile(filepath.Join(releaseDir, name), []byte("junk"), 0644))
+	}
+	// Another release still holds its video
+	keptDir := filepath.Join(downloadRoot, "Other.Movie.2024.1080p")
+	require.NoError(t, os.MkdirAll(keptDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(keptDir, "other.movie.mkv"), []byte("video"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(keptDir, "other.movie.nfo"), []byte("junk"), 0644))
+
+	dl := &download.Download{ContentID: 42, Client: download.ClientSABnzbd, ClientID: "sab-123",
+		Status: download.StatusImported, ReleaseName: "Test.Movie.2024.1080p", Indexer: "nzbgeek"}
+	require.NoError(t, store.Add(dl))
+	other := &download.Download{ContentID: 43, Client: download.ClientSABnzbd, ClientID: "sab-456",
+		Status: download.StatusImported, ReleaseName: "Other.Movie.2024.1080p", Indexer: "nzbgeek"}
+	require.NoError(t, store.Add(other))
+
+	handler := NewCleanupHandler(bus, store, CleanupConfig{
+		DownloadRoot: downloadRoot,
+		Enabled:      true,
+		Junk:         importer.DefaultJunkRules(),
+	}, nil)
+	junk := bus.Subscribe(events.EventJunkRemoved, 10)
+
+	ctx := context.Background()
+	for _, d := range []*download.Download{dl, other} {
+		handler.handleImportCompleted(ctx, &events.ImportCompleted{
+			BaseEvent:  events.NewBaseEvent(events.EventImportCompleted, events.EntityDownload, d.ID),
+			DownloadID: d.ID,
+			ContentID:  d.ContentID,
+		})
+	}
+
+	select {
+	case e := <-junk:
+		removed := e.(*events.JunkRemoved)
+		assert.Equal(t, dl.ID, removed.DownloadID)
+		assert.Equal(t, "import", removed.Trigger)
+		assert.Equal(t, []string{
+			filepath.Join(releaseDir, "test.movie.nfo"),
+			filepath.Join(releaseDir, "test.movie.par2"),
+			filepath.Join(releaseDir, "test.movie.vol00+01.par2"),
+			releaseDir,
+		}, removed.Removed)
+	case <-time.After(time.Second):
+		t.Fatal("expected JunkRemoved event")
+	}
+	assert.NoDirExists(t, releaseDir)
+	assert.FileExists(t, filepath.Join(keptDir, "other.movie.nfo"), "a release with video is left for Plex-verified cleanup")
+	select {
+	case e := <-junk:
+		t.Fatalf("unexpected JunkRemoved: %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 func TestCleanupHandler_SafetyRefusesOutsideRoot(t *testing.T) {
 	db := setupCleanupTestDB(t)
 	bus := events.NewBus(nil, nil)
@@ -608,6 +679,77 @@ func TestCleanupHandler_ImportSkipped_ImmediateCleanup(t *testing.T) {
 	assert.True(t, os.IsNotExist(err), "release directory should be deleted")
 }
 
+func TestCleanupHandler_PreCleanupHookBlocks(t *testing.T) {
+	db := setupCleanupTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+
+	dl := &download.Download{
+		ContentID:   42,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "sab-123",
+		Status:      download.StatusSkipped,
+		ReleaseName: "Test.Movie.2024.1080p.WEB-DL",
+		Indexer:     "nzbgeek",
+	}
+	require.NoError(t, store.Add(dl))
+
+	downloadRoot := t.TempDir()
+	releaseDir := filepath.Join(downloadRoot, dl.ReleaseName)
+	require.NoError(t, os.MkdirAll(releaseDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(releaseDir, "movie.mkv"), []byte("test"), 0644))
+
+	// Hook refuses while the source is still being seeded
+	script := filepath.Join(t.TempDir(), "pre-cleanup.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"still seeding $ARRGO_SOURCE_PATH\" >&2\nexit 1\n"), 0o755))
+
+	config := CleanupConfig{
+		DownloadRoot: downloadRoot,
+		Enabled:      true,
+		PreCleanup:   importer.NewHook(importer.HookPreCleanup, script, time.Minute),
+	}
+	handler := NewCleanupHandler(bus, store, config, nil)
+
+	hookFailed := bus.Subscribe(events.EventHookFailed, 10)
+	completed := bus.Subscribe(events.EventCleanupCompleted, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.ImportSkipped{
+		BaseEvent:  events.NewBaseEvent(events.EventImportSkipped, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		ContentID:  42,
+		SourcePath: releaseDir,
+	}))
+
+	select {
+	case e := <-hookFailed:
+		hf := e.(*events.HookFailed)
+		assert.Equal(t, dl.ID, hf.DownloadID)
+		assert.Equal(t, importer.HookPreCleanup, hf.Hook)
+		assert.Equal(t, 1, hf.ExitCode)
+		assert.True(t, hf.Blocked)
+		assert.Equal(t, "still seeding "+releaseDir+"\n", hf.Stderr)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for HookFailed event")
+	}
+
+	select {
+	case <-completed:
+		t.Fatal("cleanup should have been blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_, err := os.Stat(releaseDir)
+	assert.NoError(t, err, "release directory should be kept")
+}
+
 func TestCleanupHandler_ImportSkipped_Disabled(t *testing.T) {
 	db := setupCleanupTestDB(t)
 	bus := events.NewBus(nil, nil)
@@ -806,3 +948,35 @@ func TestCleanupHandler_ReconcileOnStartup_NoImportedDownloads(t *testing.T) {
 
 	assert.Equal(t, 0, count, "expected empty pending map")
 }
+
+func TestCleanupHandler_PartialImportKeepsSource(t *testing.T) {
+	db := setupCleanupTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	dl := &download.Download{
+		ContentID:   42,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "sab-123",
+		Status:      download.StatusPartiallyImported,
+		ReleaseName: "Test.Show.S01.1080p",
+		Indexer:     "nzbgeek",
+	}
+	require.NoError(t, store.Add(dl))
+
+	handler := NewCleanupHandler(bus, store, CleanupConfig{DownloadRoot: t.TempDir(), Enabled: true}, nil)
+	handler.handleImportCompleted(context.Background(), &events.ImportCompleted{
+		BaseEvent:  events.NewBaseEvent(events.EventImportCompleted, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		ContentID:  42,
+		EpisodeResults: []events.EpisodeImportResult{
+			{EpisodeID: 1, Season: 1, Episode: 1, Success: true},
+			{EpisodeID: 2, Season: 1, Episode: 2, Error: "copy file: disk full"},
+		},
+	})
+
+	handler.mu.RLock()
+	defer handler.mu.RUnlock()
+	assert.Empty(t, handler.pending, "partial import should not be cleaned up")
+}
@@ -3,32 +3,86 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log/slog"
+	"slices"
 
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
 	"github.com/vmunix/arrgo/pkg/release"
 )
 
+// ClientRouter picks the download client for a release and sends it there.
+type ClientRouter interface {
+	Route(downloadURL string, protocol download.Protocol) (download.Client, download.Downloader, error)
+	Add(ctx context.Context, name download.Client, downloadURL, category string, onRetry func(download.RetryAttempt)) (string, error)
+	SetTorrentOptions(ctx context.Context, d *download.Download, opts download.TorrentOptions) error
+}
+
+// GrabURLs rewrites a release download URL before it is sent to a client,
+// e.g. to use the indexer API key with the most grabs left.
+type GrabURLs interface {
+	GrabURL(indexer, downloadURL string) string
+}
+
+// DefaultMaxFallbacks is how many alternate releases a grab tries by default
+// when the download client rejects the chosen one.
+const DefaultMaxFallbacks = 3
+
 // DownloadHandler manages download lifecycle.
 type DownloadHandler struct {
 	*BaseHandler
-	store   *download.Store
-	library *library.Store
-	client  download.Downloader
+	store        *download.Store
+	library      *library.Store
+	clients      ClientRouter
+	history      *importer.HistoryStore // nil if grab history is not recorded
+	categories   map[download.Client]download.Categories
+	maxFallbacks int
+	grabURLs     GrabURLs // nil if download URLs are sent as found
+	torrentOpts  download.TorrentOptions
 }
 
 // NewDownloadHandler creates a new download handler.
-func NewDownloadHandler(bus *events.Bus, store *download.Store, lib *library.Store, client download.Downloader, logger *slog.Logger) *DownloadHandler {
+func NewDownloadHandler(bus *events.Bus, store *download.Store, lib *library.Store, clients ClientRouter, logger *slog.Logger) *DownloadHandler {
 	return &DownloadHandler{
-		BaseHandler: NewBaseHandler(bus, logger),
-		store:       store,
-		library:     lib,
-		client:      client,
+		BaseHandler:  NewBaseHandler(bus, logger),
+		store:        store,
+		library:      lib,
+		clients:      clients,
+		categories:   make(map[download.Client]download.Categories),
+		maxFallbacks: DefaultMaxFallbacks,
 	}
 }
 
+// SetHistory configures the history store used to record grabs and failures.
+func (h *DownloadHandler) SetHistory(history *importer.HistoryStore) {
+	h.history = history
+}
+
+// SetGrabURLs configures the rewriting of download URLs before they are sent.
+func (h *DownloadHandler) SetGrabURLs(g GrabURLs) {
+	h.grabURLs = g
+}
+
+// SetTorrentDefaults sets the torrent options of grabs that don't choose them.
+func (h *DownloadHandler) SetTorrentDefaults(opts download.TorrentOptions) {
+	h.torrentOpts = opts
+}
+
+// SetMaxFallbacks sets how many alternate releases a grab tries after the
+// download client rejects the chosen one. Zero disables fallbacks.
+func (h *DownloadHandler) SetMaxFallbacks(n int) {
+	h.maxFallbacks = max(n, 0)
+}
+
+// SetCategories configures the categories a download client uses per content type.
+func (h *DownloadHandler) SetCategories(client download.Client, categories download.Categories) {
+	h.categories[client] = categories
+}
+
 // Name returns the handler name.
 func (h *DownloadHandler) Name() string {
 	return "download"
@@ -37,6 +91,8 @@ func (h *DownloadHandler) Name() string {
 // Start begins processing events.
 func (h *DownloadHandler) Start(ctx context.Context) error {
 	grabs := h.Bus().Subscribe(events.EventGrabRequested, 100)
+	downloadFailures := h.Bus().Subscribe(events.EventDownloadFailed, 100)
+	importFailures := h.Bus().Subscribe(events.EventImportFailed, 100)
 
 	for {
 		select {
@@ -45,21 +101,84 @@ func (h *DownloadHandler) Start(ctx context.Context) error {
 				return nil // Channel closed
 			}
 			h.handleGrabRequested(ctx, e.(*events.GrabRequested))
+		case e := <-downloadFailures:
+			if e == nil {
+				return nil
+			}
+			df := e.(*events.DownloadFailed)
+			h.blockFailedRelease(ctx, df.DownloadID, df.Reason)
+		case e := <-importFailures:
+			if e == nil {
+				return nil
+			}
+			imf := e.(*events.ImportFailed)
+			h.blockFailedRelease(ctx, imf.DownloadID, imf.Reason)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
+// isDownloading reports whether the release has an active download for the content.
+// Lookup errors are logged and treated as not downloading.
+func (h *DownloadHandler) isDownloading(contentID int64, guid string) bool {
+	active, _, err := h.store.List(download.Filter{ContentID: &contentID, Active: true})
+	if err != nil {
+		h.Logger().Warn("failed to check active downloads", "content_id", contentID, "error", err)
+		return false
+	}
+	for _, dl := range active {
+		if dl.GUID == guid {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *DownloadHandler) handleGrabRequested(ctx context.Context, e *events.GrabRequested) {
 	h.Logger().Info("processing grab request",
 		"content_id", e.ContentID,
 		"release", e.ReleaseName,
 		"indexer", e.Indexer,
+		"guid", e.GUID,
 		"episode_ids", e.EpisodeIDs,
 		"season", e.Season,
 		"is_complete_season", e.IsCompleteSeason)
 
+	// Never re-grab a release that previously failed for this content
+	if e.GUID != "" && e.ContentID > 0 {
+		blocked, err := h.store.IsBlocked(e.ContentID, e.GUID)
+		if err != nil {
+			h.Logger().Warn("failed to check blocklist", "error", err)
+		} else if blocked {
+			h.Logger().Warn("skipping grab, release is blocklisted",
+				"content_id", e.ContentID,
+				"guid", e.GUID,
+				"release", e.ReleaseName)
+			if err := h.Bus().Publish(ctx, &events.GrabSkipped{
+				BaseEvent:   events.NewBaseEvent(events.EventGrabSkipped, events.EntityContent, e.ContentID),
+				ContentID:   e.ContentID,
+				ReleaseName: e.ReleaseName,
+				Reason:      "blocklisted",
+			}); err != nil {
+				h.Logger().Error("failed to publish GrabSkipped event", "error", err)
+			}
+			if e.Pinned {
+				h.dropFailedPin(ctx, e.ContentID, e.GUID, e.ReleaseName, "blocklisted")
+			}
+			return
+		}
+
+		// A grab delivered again by the outbox or replayed must not send the release twice
+		if h.isDownloading(e.ContentID, e.GUID) {
+			h.Logger().Warn("skipping grab, release already downloading",
+				"content_id", e.ContentID,
+				"guid", e.GUID,
+				"release", e.ReleaseName)
+			return
+		}
+	}
+
 	// Check for existing files before grabbing (duplicate prevention)
 	if e.ContentID > 0 && h.library != nil {
 		// Build filter - for season packs, only compare against files from the same season
@@ -68,6 +187,16 @@ func (h *DownloadHandler) handleGrabRequested(ctx context.Context, e *events.Gra
 			filter.Season = e.Season
 		}
 		files, _, err := h.library.ListFiles(filter)
+		// Episode grabs only compare against files of the same episodes
+		episodeIDs := e.EpisodeIDs
+		if len(episodeIDs) == 0 && e.EpisodeID != nil {
+			episodeIDs = []int64{*e.EpisodeID}
+		}
+		if err == nil && !e.IsCompleteSeason && len(episodeIDs) > 0 {
+			files = slices.DeleteFunc(files, func(f *library.File) bool {
+				return f.EpisodeID == nil || !slices.Contains(episodeIDs, *f.EpisodeID)
+			})
+		}
 		if err != nil {
 			h.Logger().Warn("failed to check existing files", "error", err)
 			// Continue with grab on error - better to grab than miss content
@@ -106,19 +235,57 @@ func (h *DownloadHandler) handleGrabRequested(ctx context.Context, e *events.Gra
 		}
 	}
 
-	// Send to download client
-	clientID, err := h.client.Add(ctx, e.DownloadURL, "")
-	if err != nil {
-		h.Logger().Error("failed to add download", "error", err)
-		if pubErr := h.Bus().Publish(ctx, &events.DownloadFailed{
-			BaseEvent:  events.NewBaseEvent(events.EventDownloadFailed, events.EntityDownload, 0),
-			DownloadID: 0,
-			Reason:     err.Error(),
-			Retryable:  true,
+	// Try the chosen release, then its alternates, until a client accepts one
+	candidates := h.grabCandidates(e)
+	var (
+		clientName download.Client
+		category   string
+		clientID   string
+	)
+	for i, c := range candidates {
+		var err error
+		clientName, category, clientID, err = h.sendGrab(ctx, c)
+		if err == nil {
+			e = c
+			break
+		}
+
+		if i == len(candidates)-1 {
+			if c.Pinned {
+				h.dropFailedPin(ctx, c.ContentID, c.GUID, c.ReleaseName, err.Error())
+			}
+			if clientName == "" {
+				if pubErr := h.Bus().Publish(ctx, &events.DownloadFailed{
+					BaseEvent:  events.NewBaseEvent(events.EventDownloadFailed, events.EntityDownload, 0),
+					DownloadID: 0,
+					Reason:     err.Error(),
+					Retryable:  true,
+				}); pubErr != nil {
+					h.Logger().Error("failed to publish DownloadFailed event", "error", pubErr)
+				}
+				return
+			}
+			h.recordFailedGrab(ctx, c, clientName, category, err)
+			return
+		}
+
+		next := candidates[i+1]
+		h.Logger().Warn("grab rejected, trying next release",
+			"content_id", e.ContentID,
+			"release", c.ReleaseName,
+			"next_release", next.ReleaseName,
+			"error", err)
+		if pubErr := h.Bus().Publish(ctx, &events.GrabFallback{
+			BaseEvent:     events.NewBaseEvent(events.EventGrabFallback, events.EntityContent, e.ContentID),
+			ContentID:     e.ContentID,
+			FailedRelease: c.ReleaseName,
+			Error:         err.Error(),
+			NextRelease:   next.ReleaseName,
+			Attempt:       i + 1,
+			MaxAttempts:   len(candidates),
 		}); pubErr != nil {
-			h.Logger().Error("failed to publish DownloadFailed event", "error", pubErr)
+			h.Logger().Error("failed to publish GrabFallback event", "error", pubErr)
 		}
-		return
 	}
 
 	// Create DB record
@@ -126,11 +293,13 @@ func (h *DownloadHandler) handleGrabRequested(ctx context.Context, e *events.Gra
 		ContentID:        e.ContentID,
 		Season:           e.Season,
 		IsCompleteSeason: e.IsCompleteSeason,
-		Client:           download.ClientSABnzbd,
+		Client:           clientName,
 		ClientID:         clientID,
 		Status:           download.StatusQueued,
 		ReleaseName:      e.ReleaseName,
 		Indexer:          e.Indexer,
+		GUID:             e.GUID,
+		Category:         category,
 	}
 
 	// Backward compat: set EpisodeID if single episode
@@ -152,6 +321,15 @@ func (h *DownloadHandler) handleGrabRequested(ctx context.Context, e *events.Gra
 		}
 	}
 
+	h.applyTorrentOptions(ctx, dl, e)
+	h.recordHistory(dl, importer.EventGrabbed, "")
+
+	var decision string
+	if e.Decision != nil {
+		h.recordDecision(dl, e.Decision)
+		decision = e.Decision.Summary()
+	}
+
 	// Emit success event with new fields
 	if err := h.Bus().Publish(ctx, &events.DownloadCreated{
 		BaseEvent:        events.NewBaseEvent(events.EventDownloadCreated, events.EntityDownload, dl.ID),
@@ -163,12 +341,270 @@ func (h *DownloadHandler) handleGrabRequested(ctx context.Context, e *events.Gra
 		IsCompleteSeason: e.IsCompleteSeason,
 		ClientID:         clientID,
 		ReleaseName:      e.ReleaseName,
+		Decision:         decision,
 	}); err != nil {
 		h.Logger().Error("failed to publish DownloadCreated event", "error", err)
 	}
 
 	h.Logger().Info("download created",
 		"download_id", dl.ID,
+		"client", clientName,
 		"client_id", clientID,
 		"episode_ids", e.EpisodeIDs)
 }
+
+// applyTorrentOptions sets the grab's torrent options, or the defaults, on a
+// new download. Clients without torrent options are left alone.
+func (h *DownloadHandler) applyTorrentOptions(ctx context.Context, dl *download.Download, e *events.GrabRequested) {
+	opts := h.torrentOpts
+	if e.Sequential != nil {
+		opts.Sequential = *e.Sequential
+	}
+	if e.FirstLastPiecePrio != nil {
+		opts.FirstLastPiecePrio = *e.FirstLastPiecePrio
+	}
+	if err := h.clients.SetTorrentOptions(ctx, dl, opts); err != nil && !errors.Is(err, download.ErrUnsupported) {
+		h.Logger().Warn("failed to set torrent options", "download_id", dl.ID, "client", dl.Client, "error", err)
+	}
+}
+
+// grabCandidates returns the grab followed by one grab per alternate release,
+// skipping blocklisted alternates, up to the fallback limit. Alternates carry
+// no decision since the search chose a different release.
+func (h *DownloadHandler) grabCandidates(e *events.GrabRequested) []*events.GrabRequested {
+	candidates := []*events.GrabRequested{e}
+	for _, alt := range e.Alternates {
+		if len(candidates) > h.maxFallbacks {
+			break
+		}
+		if alt.GUID != "" && e.ContentID > 0 {
+			blocked, err := h.store.IsBlocked(e.ContentID, alt.GUID)
+			if err != nil {
+				h.Logger().Warn("failed to check blocklist", "error", err)
+			} else if blocked {
+				continue
+			}
+		}
+		c := *e
+		c.DownloadURL = alt.DownloadURL
+		c.ReleaseName = alt.ReleaseName
+		c.Indexer = alt.Indexer
+		c.GUID = alt.GUID
+		c.Protocol = alt.Protocol
+		c.Decision = nil
+		c.Alternates = nil
+		candidates = append(candidates, &c)
+	}
+	return candidates
+}
+
+// sendGrab sends a release to the client for its protocol, under the category for
+// its content type. Returns an empty client name if no client handles the release.
+func (h *DownloadHandler) sendGrab(ctx context.Context, e *events.GrabRequested) (download.Client, string, string, error) {
+	clientName, _, err := h.clients.Route(e.DownloadURL, download.Protocol(e.Protocol))
+	if err != nil {
+		h.Logger().Error("failed to route download", "error", err)
+		return "", "", "", err
+	}
+	category := h.categoryFor(clientName, e.ContentID)
+	downloadURL := e.DownloadURL
+	if h.grabURLs != nil {
+		downloadURL = h.grabURLs.GrabURL(e.Indexer, downloadURL)
+	}
+	clientID, err := h.clients.Add(ctx, clientName, downloadURL, category, func(a download.RetryAttempt) {
+		if pubErr := h.Bus().Publish(ctx, &events.GrabRetrying{
+			BaseEvent:   events.NewBaseEvent(events.EventGrabRetrying, events.EntityContent, e.ContentID),
+			ContentID:   e.ContentID,
+			ReleaseName: e.ReleaseName,
+			Client:      string(clientName),
+			Attempt:     a.Attempt,
+			MaxAttempts: a.MaxAttempts,
+			Error:       a.Err.Error(),
+			RetryInMs:   a.Delay.Milliseconds(),
+		}); pubErr != nil {
+			h.Logger().Error("failed to publish GrabRetrying event", "error", pubErr)
+		}
+	})
+	if err != nil {
+		h.Logger().Error("failed to add download", "client", clientName, "error", err)
+		return clientName, category, "", err
+	}
+	return clientName, category, clientID, nil
+}
+
+// recordFailedGrab saves a failed download for a grab the client never accepted,
+// so the failure and its error are visible, then publishes DownloadFailed for it.
+func (h *DownloadHandler) recordFailedGrab(ctx context.Context, e *events.GrabRequested, clientName download.Client, category string, addErr error) {
+	dl := &download.Download{
+		ContentID:        e.ContentID,
+		EpisodeID:        e.EpisodeID,
+		Season:           e.Season,
+		IsCompleteSeason: e.IsCompleteSeason,
+		Client:           clientName,
+		Status:           download.StatusFailed,
+		ReleaseName:      e.ReleaseName,
+		Indexer:          e.Indexer,
+		GUID:             e.GUID,
+		Category:         category,
+		LastError:        addErr.Error(),
+	}
+	if dl.EpisodeID == nil && len(e.EpisodeIDs) == 1 {
+		dl.EpisodeID = &e.EpisodeIDs[0]
+	}
+	if err := h.store.Add(dl); err != nil {
+		h.Logger().Error("failed to save failed download", "error", err)
+	} else if len(e.EpisodeIDs) > 0 {
+		if err := h.store.SetEpisodeIDs(dl.ID, e.EpisodeIDs); err != nil {
+			h.Logger().Error("failed to set episode IDs", "error", err)
+		}
+	}
+
+	if err := h.Bus().Publish(ctx, &events.DownloadFailed{
+		BaseEvent:  events.NewBaseEvent(events.EventDownloadFailed, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		Reason:     addErr.Error(),
+		Retryable:  download.IsTransient(addErr),
+	}); err != nil {
+		h.Logger().Error("failed to publish DownloadFailed event", "error", err)
+	}
+}
+
+// blockFailedRelease adds a failed download's release to its content's blocklist
+// so later searches and retries don't grab the same broken release again. A
+// release pinned for the content is unpinned.
+func (h *DownloadHandler) blockFailedRelease(ctx context.Context, downloadID int64, reason string) {
+	if downloadID == 0 {
+		return // Client rejected the grab before a record was created
+	}
+
+	dl, err := h.store.Get(downloadID)
+	if err != nil {
+		h.Logger().Warn("failed to get failed download", "download_id", downloadID, "error", err)
+		return
+	}
+
+	h.recordHistory(dl, importer.EventFailed, reason)
+	h.dropFailedPin(ctx, dl.ContentID, dl.GUID, dl.ReleaseName, reason)
+
+	// A grab the client never accepted says nothing about the release itself
+	if dl.GUID == "" || dl.ClientID == "" {
+		return
+	}
+	if err := h.store.Block(&download.BlocklistEntry{
+		ContentID:   dl.ContentID,
+		GUID:        dl.GUID,
+		ReleaseName: dl.ReleaseName,
+		Indexer:     dl.Indexer,
+		Reason:      reason,
+	}); err != nil {
+		h.Logger().Error("failed to blocklist release", "download_id", downloadID, "error", err)
+		return
+	}
+
+	h.Logger().Info("release blocklisted",
+		"download_id", downloadID,
+		"content_id", dl.ContentID,
+		"guid", dl.GUID,
+		"release", dl.ReleaseName)
+}
+
+// dropFailedPin unpins a release that failed to grab or download, if it is
+// still the one pinned for the content, and publishes PinnedGrabFailed so the
+// content is searched for as usual.
+func (h *DownloadHandler) dropFailedPin(ctx context.Context, contentID int64, guid, title, reason string) {
+	if h.library == nil || contentID == 0 {
+		return
+	}
+	content, err := h.library.GetContent(contentID)
+	if err != nil {
+		h.Logger().Warn("failed to check pinned release", "content_id", contentID, "error", err)
+		return
+	}
+	pin := content.Pin
+	if pin == nil || !pin.Matches(guid, title) {
+		return
+	}
+	if err := h.library.UnpinRelease(contentID); err != nil {
+		if !errors.Is(err, library.ErrNotFound) {
+			h.Logger().Error("failed to unpin release", "content_id", contentID, "error", err)
+		}
+		return
+	}
+
+	h.Logger().Warn("pinned release failed, unpinned",
+		"content_id", contentID,
+		"release", title,
+		"reason", reason)
+	if err := h.Bus().Publish(ctx, &events.PinnedGrabFailed{
+		BaseEvent:   events.NewBaseEvent(events.EventPinnedGrabFailed, events.EntityContent, contentID),
+		ContentID:   contentID,
+		Season:      pin.Season,
+		ReleaseName: title,
+		Reason:      reason,
+	}); err != nil {
+		h.Logger().Error("failed to publish PinnedGrabFailed event", "error", err)
+	}
+}
+
+// recordHistory adds a history entry for a download (best effort).
+func (h *DownloadHandler) recordHistory(dl *download.Download, event, reason string) {
+	if h.history == nil {
+		return
+	}
+
+	data := map[string]any{
+		"download_id":  dl.ID,
+		"release_name": dl.ReleaseName,
+		"indexer":      dl.Indexer,
+		"guid":         dl.GUID,
+	}
+	if reason != "" {
+		data["reason"] = reason
+	}
+	historyData, _ := json.Marshal(data)
+	if err := h.history.Add(&importer.HistoryEntry{
+		ContentID: dl.ContentID,
+		EpisodeID: dl.EpisodeID,
+		Event:     event,
+		Data:      string(historyData),
+	}); err != nil {
+		h.Logger().Warn("failed to record history", "download_id", dl.ID, "event", event, "error", err)
+	}
+}
+
+// recordDecision adds a grab_decision history entry for a download (best effort).
+func (h *DownloadHandler) recordDecision(dl *download.Download, decision *events.GrabDecision) {
+	if h.history == nil {
+		return
+	}
+
+	historyData, _ := json.Marshal(struct {
+		DownloadID  int64  `json:"download_id"`
+		ReleaseName string `json:"release_name"`
+		*events.GrabDecision
+	}{dl.ID, dl.ReleaseName, decision})
+	if err := h.history.Add(&importer.HistoryEntry{
+		ContentID: dl.ContentID,
+		EpisodeID: dl.EpisodeID,
+		Event:     importer.EventGrabDecision,
+		Data:      string(historyData),
+	}); err != nil {
+		h.Logger().Warn("failed to record grab decision", "download_id", dl.ID, "error", err)
+	}
+}
+
+// categoryFor returns the client's category for the content's type.
+func (h *DownloadHandler) categoryFor(client download.Client, contentID int64) string {
+	categories := h.categories[client]
+	if contentID == 0 || h.library == nil {
+		return categories.Default
+	}
+	content, err := h.library.GetContent(contentID)
+	if err != nil {
+		h.Logger().Warn("failed to get content for category, using default",
+			"content_id", contentID,
+			"error", err)
+		return categories.Default
+	}
+	return categories.For(string(content.Type))
+}
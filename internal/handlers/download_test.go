@@ -4,6 +4,8 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,7 +13,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/search"
+	"github.com/vmunix/arrgo/pkg/release/scoring"
 	_ "modernc.org/sqlite"
 )
 
@@ -30,6 +35,7 @@ func setupDownloadTestDB(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL,
 			release_name TEXT NOT NULL,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT NOT NULL,
 			added_at TIMESTAMP NOT NULL,
 			completed_at TIMESTAMP,
@@ -39,12 +45,33 @@ func setupDownloadTestDB(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
 		);
 		CREATE TABLE download_episodes (
 			download_id INTEGER NOT NULL,
 			episode_id  INTEGER NOT NULL,
 			PRIMARY KEY (download_id, episode_id)
+		);
+		CREATE TABLE blocklist (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			content_id INTEGER NOT NULL,
+			guid TEXT NOT NULL,
+			release_name TEXT NOT NULL DEFAULT '',
+			indexer TEXT NOT NULL DEFAULT '',
+			reason TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(content_id, guid)
+		);
+		CREATE TABLE history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			content_id INTEGER NOT NULL,
+			episode_id INTEGER,
+			event TEXT NOT NULL,
+			data TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
 	require.NoError(t, err)
@@ -53,15 +80,21 @@ func setupDownloadTestDB(t *testing.T) *sql.DB {
 
 // mockDownloader is a test implementation
 type mockDownloader struct {
-	addCalled   bool
-	lastURL     string
-	returnID    string
-	returnError error
+	addCalled    bool
+	lastURL      string
+	lastCategory string
+	returnID     string
+	returnError  error
+	urlErrors    map[string]error // Errors for specific URLs, checked before returnError
 }
 
 func (m *mockDownloader) Add(ctx context.Context, url, category string) (string, error) {
 	m.addCalled = true
 	m.lastURL = url
+	m.lastCategory = category
+	if err := m.urlErrors[url]; err != nil {
+		return "", err
+	}
 	if m.returnError != nil {
 		return "", m.returnError
 	}
@@ -80,6 +113,13 @@ func (m *mockDownloader) Remove(ctx context.Context, clientID string, deleteFile
 	return nil
 }
 
+// singleClient routes every grab to client, registered as the SABnzbd client.
+func singleClient(client download.Downloader) *download.Manager {
+	m := download.NewManager(nil, nil)
+	m.AddClient(download.ClientSABnzbd, download.ProtocolUsenet, client)
+	return m
+}
+
 func TestDownloadHandler_GrabRequested(t *testing.T) {
 	db := setupDownloadTestDB(t)
 	bus := events.NewBus(nil, nil)
@@ -88,7 +128,7 @@ func TestDownloadHandler_GrabRequested(t *testing.T) {
 	store := download.NewStore(db)
 	client := &mockDownloader{returnID: "sab-123"}
 
-	handler := NewDownloadHandler(bus, store, nil, client, nil)
+	handler := NewDownloadHandler(bus, store, nil, singleClient(client), nil)
 
 	// Subscribe to DownloadCreated before starting
 	created := bus.Subscribe(events.EventDownloadCreated, 10)
@@ -130,6 +170,162 @@ func TestDownloadHandler_GrabRequested(t *testing.T) {
 	assert.Equal(t, "https://example.com/test.nzb", client.lastURL)
 }
 
+// grabURLFunc adapts a function to GrabURLs.
+type grabURLFunc func(indexer, downloadURL string) string
+
+func (f grabURLFunc) GrabURL(indexer, downloadURL string) string { return f(indexer, downloadURL) }
+
+func TestDownloadHandler_GrabRewritesURL(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	client := &mockDownloader{returnID: "sab-123"}
+	handler := NewDownloadHandler(bus, download.NewStore(db), nil, singleClient(client), nil)
+	var gotIndexer string
+	handler.SetGrabURLs(grabURLFunc(func(indexer, downloadURL string) string {
+		gotIndexer = indexer
+		return strings.Replace(downloadURL, "apikey=first", "apikey=second", 1)
+	}))
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "https://api.nzbgeek.info/api?t=get&id=abc&apikey=first",
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "nzbgeek",
+	}))
+
+	select {
+	case <-created:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadCreated event")
+	}
+	assert.Equal(t, "nzbgeek", gotIndexer)
+	assert.Equal(t, "https://api.nzbgeek.info/api?t=get&id=abc&apikey=second", client.lastURL, "the client gets the rewritten URL")
+}
+
+func TestDownloadHandler_GrabRoutesByProtocol(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	sab := &mockDownloader{returnID: "nzo_1"}
+	qbit := &mockDownloader{returnID: "abc123hash"}
+	clients := download.NewManager(nil, nil)
+	clients.AddClient("sab", download.ProtocolUsenet, sab)
+	clients.AddClient("qbit", download.ProtocolTorrent, qbit)
+
+	handler := NewDownloadHandler(bus, store, nil, clients, nil)
+	handler.SetCategories("qbit", download.Categories{Default: "arrgo-torrents"})
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "magnet:?xt=urn:btih:abc123hash",
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "tracker",
+	}))
+
+	var downloadID int64
+	select {
+	case e := <-created:
+		downloadID = e.(*events.DownloadCreated).DownloadID
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadCreated event")
+	}
+
+	assert.False(t, sab.addCalled, "usenet client should not receive a magnet link")
+	assert.True(t, qbit.addCalled)
+	assert.Equal(t, "arrgo-torrents", qbit.lastCategory)
+
+	dl, err := store.Get(downloadID)
+	require.NoError(t, err)
+	assert.Equal(t, download.Client("qbit"), dl.Client)
+	assert.Equal(t, "abc123hash", dl.ClientID)
+}
+
+func TestDownloadHandler_GrabRoutesByEventProtocol(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	sab := &mockDownloader{returnID: "nzo_1"}
+	qbit := &mockDownloader{returnID: "abc123hash"}
+	clients := download.NewManager(nil, nil)
+	clients.AddClient("sab", download.ProtocolUsenet, sab)
+	clients.AddClient("qbit", download.ProtocolTorrent, qbit)
+
+	handler := NewDownloadHandler(bus, download.NewStore(db), nil, clients, nil)
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+
+	// Torznab proxy links don't look like torrents; the event's protocol decides
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "http://jackett:9117/dl/tracker/?path=abc",
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "jackett",
+		Protocol:    "torrent",
+	}))
+
+	select {
+	case <-created:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadCreated event")
+	}
+	assert.False(t, sab.addCalled)
+	assert.True(t, qbit.addCalled)
+}
+
+func TestDownloadHandler_GrabNoClientForProtocol(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	client := &mockDownloader{returnID: "nzo_1"}
+	handler := NewDownloadHandler(bus, download.NewStore(db), nil, singleClient(client), nil)
+	failed := bus.Subscribe(events.EventDownloadFailed, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "magnet:?xt=urn:btih:abc123hash",
+		ReleaseName: "Test.Movie.2024.1080p",
+	}))
+
+	select {
+	case e := <-failed:
+		assert.Contains(t, e.(*events.DownloadFailed).Reason, download.ErrNoClient.Error())
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadFailed event")
+	}
+	assert.False(t, client.addCalled)
+}
+
 // setupDownloadTestDBWithLibrary creates a test DB with both download and library schemas.
 func setupDownloadTestDBWithLibrary(t *testing.T) *sql.DB {
 	db, err := sql.Open("sqlite", ":memory:")
@@ -145,6 +341,7 @@ func setupDownloadTestDBWithLibrary(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL,
 			release_name TEXT NOT NULL,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT NOT NULL,
 			added_at TIMESTAMP NOT NULL,
 			completed_at TIMESTAMP,
@@ -154,7 +351,10 @@ func setupDownloadTestDBWithLibrary(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
 		);
 		CREATE TABLE download_episodes (
 			download_id INTEGER NOT NULL,
@@ -164,11 +364,47 @@ func setupDownloadTestDBWithLibrary(t *testing.T) *sql.DB {
 		CREATE TABLE content (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			type TEXT NOT NULL,
+			tmdb_id INTEGER,
+			tvdb_id INTEGER,
 			title TEXT NOT NULL,
 			year INTEGER,
 			status TEXT NOT NULL DEFAULT 'wanted',
 			quality_profile TEXT NOT NULL DEFAULT 'hd',
-			root_path TEXT NOT NULL
+			root_path TEXT NOT NULL,
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			overview TEXT NOT NULL DEFAULT '',
+			poster_url TEXT NOT NULL DEFAULT '',
+			runtime INTEGER NOT NULL DEFAULT 0,
+			genres TEXT NOT NULL DEFAULT '[]',
+			metadata_updated_at TIMESTAMP,
+			minimum_availability TEXT NOT NULL DEFAULT 'announced',
+			release_date TIMESTAMP,
+			normalized_title TEXT,
+			daily INTEGER NOT NULL DEFAULT 0,
+			series_status TEXT,
+			season_folder INTEGER,
+			naming_template TEXT,
+			author TEXT NOT NULL DEFAULT '',
+			pinned_guid TEXT,
+			pinned_download_url TEXT,
+			pinned_title TEXT,
+			pinned_indexer TEXT,
+			pinned_protocol TEXT,
+			pinned_season INTEGER,
+			pinned_at TIMESTAMP,
+			collection_id INTEGER,
+			collection_name TEXT NOT NULL DEFAULT '',
+			plex_key TEXT NOT NULL DEFAULT ''
+		);
+		CREATE TABLE content_aliases (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			content_id INTEGER NOT NULL,
+			alias TEXT NOT NULL,
+			normalized_alias TEXT NOT NULL,
+			source TEXT NOT NULL,
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (content_id, normalized_alias)
 		);
 		CREATE TABLE files (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -178,7 +414,12 @@ func setupDownloadTestDBWithLibrary(t *testing.T) *sql.DB {
 			size_bytes INTEGER,
 			quality TEXT,
 			source TEXT,
-			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			checksum TEXT,
+			part_number INTEGER,
+			release_group TEXT NOT NULL DEFAULT '',
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			missing_at TIMESTAMP,
+			kind TEXT NOT NULL DEFAULT 'media'
 		);
 	`)
 	require.NoError(t, err)
@@ -200,7 +441,7 @@ func TestDownloadHandler_GrabSkipped_ExistingQualityEqual(t *testing.T) {
 	libraryStore := library.NewStore(db)
 	client := &mockDownloader{returnID: "sab-123"}
 
-	handler := NewDownloadHandler(bus, downloadStore, libraryStore, client, nil)
+	handler := NewDownloadHandler(bus, downloadStore, libraryStore, singleClient(client), nil)
 
 	// Subscribe to events
 	skipped := bus.Subscribe(events.EventGrabSkipped, 10)
@@ -255,7 +496,7 @@ func TestDownloadHandler_GrabSkipped_ExistingQualityBetter(t *testing.T) {
 	libraryStore := library.NewStore(db)
 	client := &mockDownloader{returnID: "sab-123"}
 
-	handler := NewDownloadHandler(bus, downloadStore, libraryStore, client, nil)
+	handler := NewDownloadHandler(bus, downloadStore, libraryStore, singleClient(client), nil)
 
 	skipped := bus.Subscribe(events.EventGrabSkipped, 10)
 	created := bus.Subscribe(events.EventDownloadCreated, 10)
@@ -306,7 +547,7 @@ func TestDownloadHandler_GrabProceeds_QualityUpgrade(t *testing.T) {
 	libraryStore := library.NewStore(db)
 	client := &mockDownloader{returnID: "sab-123"}
 
-	handler := NewDownloadHandler(bus, downloadStore, libraryStore, client, nil)
+	handler := NewDownloadHandler(bus, downloadStore, libraryStore, singleClient(client), nil)
 
 	skipped := bus.Subscribe(events.EventGrabSkipped, 10)
 	created := bus.Subscribe(events.EventDownloadCreated, 10)
@@ -354,7 +595,7 @@ func TestDownloadHandler_GrabProceeds_NoExistingFiles(t *testing.T) {
 	libraryStore := library.NewStore(db)
 	client := &mockDownloader{returnID: "sab-123"}
 
-	handler := NewDownloadHandler(bus, downloadStore, libraryStore, client, nil)
+	handler := NewDownloadHandler(bus, downloadStore, libraryStore, singleClient(client), nil)
 
 	created := bus.Subscribe(events.EventDownloadCreated, 10)
 
@@ -384,6 +625,101 @@ func TestDownloadHandler_GrabProceeds_NoExistingFiles(t *testing.T) {
 	assert.True(t, client.addCalled)
 }
 
+func TestDownloadHandler_GrabProceeds_OtherEpisodeFiles(t *testing.T) {
+	db := setupDownloadTestDBWithLibrary(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	// The series has episode 1 in 1080p; episode 2 is still missing
+	_, err := db.Exec(`INSERT INTO content (id, type, title, year, root_path) VALUES (42, 'series', 'Test Show', 2024, '/tv')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO files (content_id, episode_id, path, quality, size_bytes, source) VALUES (42, 1, '/tv/Test Show/S01E01.mkv', '1080p', 2000000000, 'webdl')`)
+	require.NoError(t, err)
+
+	downloadStore := download.NewStore(db)
+	libraryStore := library.NewStore(db)
+	client := &mockDownloader{returnID: "sab-123"}
+
+	handler := NewDownloadHandler(bus, downloadStore, libraryStore, singleClient(client), nil)
+
+	skipped := bus.Subscribe(events.EventGrabSkipped, 10)
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	season := 1
+	grab := &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		Season:      &season,
+		EpisodeIDs:  []int64{2},
+		DownloadURL: "https://example.com/test.nzb",
+		ReleaseName: "Test.Show.S01E02.1080p.WEB-DL",
+		Indexer:     "nzbgeek",
+	}
+	require.NoError(t, bus.Publish(ctx, grab))
+
+	// Episode 1's file does not make episode 2 a duplicate
+	select {
+	case <-created:
+	case <-skipped:
+		t.Fatal("should not compare against files of other episodes")
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+
+	assert.True(t, client.addCalled)
+}
+
+func TestDownloadHandler_GrabUsesContentTypeCategory(t *testing.T) {
+	db := setupDownloadTestDBWithLibrary(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	_, err := db.Exec(`INSERT INTO content (id, type, title, year, root_path) VALUES (42, 'series', 'Test Show', 2024, '/tv')`)
+	require.NoError(t, err)
+
+	downloadStore := download.NewStore(db)
+	client := &mockDownloader{returnID: "sab-123"}
+
+	handler := NewDownloadHandler(bus, downloadStore, library.NewStore(db), singleClient(client), nil)
+	handler.SetCategories(download.ClientSABnzbd, download.Categories{Default: "arrgo", Movie: "arrgo-movies", Series: "arrgo-tv"})
+
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "https://example.com/test.nzb",
+		ReleaseName: "Test.Show.S01E01.1080p",
+		Indexer:     "nzbgeek",
+	}))
+
+	var downloadID int64
+	select {
+	case e := <-created:
+		downloadID = e.(*events.DownloadCreated).DownloadID
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadCreated event")
+	}
+
+	assert.Equal(t, "arrgo-tv", client.lastCategory)
+
+	dl, err := downloadStore.Get(downloadID)
+	require.NoError(t, err)
+	assert.Equal(t, "arrgo-tv", dl.Category)
+}
+
 // setupDownloadTestDBWithEpisodes creates a test DB with download, library, and episode schemas.
 func setupDownloadTestDBWithEpisodes(t *testing.T) *sql.DB {
 	db, err := sql.Open("sqlite", ":memory:")
@@ -399,6 +735,7 @@ func setupDownloadTestDBWithEpisodes(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL,
 			release_name TEXT NOT NULL,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT NOT NULL,
 			added_at TIMESTAMP NOT NULL,
 			completed_at TIMESTAMP,
@@ -408,7 +745,10 @@ func setupDownloadTestDBWithEpisodes(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
 		);
 		CREATE TABLE download_episodes (
 			download_id INTEGER NOT NULL,
@@ -424,6 +764,15 @@ func setupDownloadTestDBWithEpisodes(t *testing.T) *sql.DB {
 			quality_profile TEXT NOT NULL DEFAULT 'hd',
 			root_path TEXT NOT NULL
 		);
+		CREATE TABLE content_aliases (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			content_id INTEGER NOT NULL,
+			alias TEXT NOT NULL,
+			normalized_alias TEXT NOT NULL,
+			source TEXT NOT NULL,
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (content_id, normalized_alias)
+		);
 		CREATE TABLE episodes (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			content_id INTEGER NOT NULL REFERENCES content(id) ON DELETE CASCADE,
@@ -432,6 +781,7 @@ func setupDownloadTestDBWithEpisodes(t *testing.T) *sql.DB {
 			title TEXT,
 			status TEXT NOT NULL DEFAULT 'wanted',
 			air_date DATE,
+			quality_profile TEXT,
 			UNIQUE(content_id, season, episode)
 		);
 		CREATE TABLE files (
@@ -442,7 +792,12 @@ func setupDownloadTestDBWithEpisodes(t *testing.T) *sql.DB {
 			size_bytes INTEGER,
 			quality TEXT,
 			source TEXT,
-			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			checksum TEXT,
+			part_number INTEGER,
+			release_group TEXT NOT NULL DEFAULT '',
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			missing_at TIMESTAMP,
+			kind TEXT NOT NULL DEFAULT 'media'
 		);
 	`)
 	require.NoError(t, err)
@@ -467,7 +822,7 @@ func TestDownloadHandler_GrabWithEpisodeIDs(t *testing.T) {
 	downloadStore := download.NewStore(db)
 	client := &mockDownloader{returnID: "sab-456"}
 
-	handler := NewDownloadHandler(bus, downloadStore, nil, client, nil)
+	handler := NewDownloadHandler(bus, downloadStore, nil, singleClient(client), nil)
 
 	created := bus.Subscribe(events.EventDownloadCreated, 10)
 
@@ -534,7 +889,7 @@ func TestDownloadHandler_GrabSeasonPack(t *testing.T) {
 	downloadStore := download.NewStore(db)
 	client := &mockDownloader{returnID: "sab-789"}
 
-	handler := NewDownloadHandler(bus, downloadStore, nil, client, nil)
+	handler := NewDownloadHandler(bus, downloadStore, nil, singleClient(client), nil)
 
 	created := bus.Subscribe(events.EventDownloadCreated, 10)
 
@@ -597,7 +952,7 @@ func TestDownloadHandler_GrabSingleEpisodeBackwardCompat(t *testing.T) {
 	downloadStore := download.NewStore(db)
 	client := &mockDownloader{returnID: "sab-111"}
 
-	handler := NewDownloadHandler(bus, downloadStore, nil, client, nil)
+	handler := NewDownloadHandler(bus, downloadStore, nil, singleClient(client), nil)
 
 	created := bus.Subscribe(events.EventDownloadCreated, 10)
 
@@ -638,3 +993,436 @@ func TestDownloadHandler_GrabSingleEpisodeBackwardCompat(t *testing.T) {
 	assert.True(t, episodeID.Valid)
 	assert.Equal(t, int64(101), episodeID.Int64)
 }
+
+func TestDownloadHandler_GrabSkipped_Blocklisted(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	require.NoError(t, store.Block(&download.BlocklistEntry{ContentID: 42, GUID: "broken-guid"}))
+	client := &mockDownloader{returnID: "sab-123"}
+
+	handler := NewDownloadHandler(bus, store, nil, singleClient(client), nil)
+
+	skipped := bus.Subscribe(events.EventGrabSkipped, 10)
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "https://example.com/test.nzb",
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "nzbgeek",
+		GUID:        "broken-guid",
+	}))
+
+	select {
+	case e := <-skipped:
+		gs := e.(*events.GrabSkipped)
+		assert.Equal(t, int64(42), gs.ContentID)
+		assert.Equal(t, "blocklisted", gs.Reason)
+	case <-created:
+		t.Fatal("should not create download for blocklisted release")
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+
+	assert.False(t, client.addCalled, "download client should not be called for blocklisted release")
+}
+
+func TestDownloadHandler_GrabSkipped_AlreadyDownloading(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	client := &mockDownloader{returnID: "sab-123"}
+	handler := NewDownloadHandler(bus, store, nil, singleClient(client), nil)
+
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	// The same grab delivered twice, e.g. replayed from the event log
+	grab := &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "https://example.com/test.nzb",
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "nzbgeek",
+		GUID:        "guid-123",
+	}
+	require.NoError(t, bus.Publish(ctx, grab))
+	select {
+	case <-created:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadCreated")
+	}
+
+	client.addCalled = false
+	require.NoError(t, bus.Publish(ctx, grab))
+	select {
+	case <-created:
+		t.Fatal("should not download a release that is already downloading")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.False(t, client.addCalled, "download client should not be called again")
+
+	downloads, _, err := store.List(download.Filter{})
+	require.NoError(t, err)
+	assert.Len(t, downloads, 1)
+}
+
+func TestDownloadHandler_FailedDownloadBlocklistsGUID(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	history := importer.NewHistoryStore(db)
+	client := &mockDownloader{returnID: "sab-123"}
+
+	handler := NewDownloadHandler(bus, store, nil, singleClient(client), nil)
+	handler.SetHistory(history)
+
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "https://example.com/test.nzb",
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "nzbgeek",
+		GUID:        "guid-123",
+	}))
+
+	var downloadID int64
+	select {
+	case e := <-created:
+		downloadID = e.(*events.DownloadCreated).DownloadID
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadCreated event")
+	}
+
+	dl, err := store.Get(downloadID)
+	require.NoError(t, err)
+	assert.Equal(t, "guid-123", dl.GUID)
+
+	require.NoError(t, bus.Publish(ctx, &events.ImportFailed{
+		BaseEvent:  events.NewBaseEvent(events.EventImportFailed, events.EntityDownload, downloadID),
+		DownloadID: downloadID,
+		Reason:     "no video file found",
+	}))
+
+	require.Eventually(t, func() bool {
+		blocked, err := store.IsBlocked(42, "guid-123")
+		return err == nil && blocked
+	}, time.Second, 10*time.Millisecond)
+
+	entries, _, err := history.List(importer.HistoryFilter{})
+	require.NoError(t, err)
+	recorded := make([]string, 0, len(entries))
+	for _, h := range entries {
+		assert.Contains(t, h.Data, "guid-123")
+		recorded = append(recorded, h.Event)
+	}
+	assert.ElementsMatch(t, []string{importer.EventGrabbed, importer.EventFailed}, recorded)
+}
+
+func TestDownloadHandler_RecordsGrabDecision(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	history := importer.NewHistoryStore(db)
+	client := &mockDownloader{returnID: "sab-123"}
+
+	handler := NewDownloadHandler(bus, store, nil, singleClient(client), nil)
+	handler.SetHistory(history)
+
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	decision := &events.GrabDecision{
+		Profile: "hd",
+		Chosen: events.ScoredRelease{
+			Title:     "Test.Movie.2024.1080p.BluRay",
+			Score:     90,
+			Breakdown: scoring.Breakdown{Resolution: 80, Source: 10, Total: 90},
+		},
+		RunnersUp:  []events.ScoredRelease{{Title: "Test.Movie.2024.720p", Score: 60}},
+		Candidates: 2,
+	}
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "https://example.com/test.nzb",
+		ReleaseName: "Test.Movie.2024.1080p.BluRay",
+		Indexer:     "nzbgeek",
+		Decision:    decision,
+	}))
+
+	var dc *events.DownloadCreated
+	select {
+	case e := <-created:
+		dc = e.(*events.DownloadCreated)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadCreated event")
+	}
+	assert.Equal(t, decision.Summary(), dc.Decision)
+
+	entry, err := history.LatestForDownload(importer.EventGrabDecision, dc.DownloadID)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+
+	var recorded struct {
+		DownloadID int64 `json:"download_id"`
+		events.GrabDecision
+	}
+	require.NoError(t, json.Unmarshal([]byte(entry.Data), &recorded))
+	assert.Equal(t, dc.DownloadID, recorded.DownloadID)
+	assert.Equal(t, *decision, recorded.GrabDecision)
+}
+
+func TestDownloadHandler_GrabRetriesExhausted(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	history := importer.NewHistoryStore(db)
+	client := &mockDownloader{returnError: &download.StatusError{StatusCode: 503}}
+	clients := singleClient(client)
+	clients.SetRetryPolicy(download.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+
+	handler := NewDownloadHandler(bus, store, nil, clients, nil)
+	handler.SetHistory(history)
+
+	retrying := bus.Subscribe(events.EventGrabRetrying, 10)
+	failed := bus.Subscribe(events.EventDownloadFailed, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "https://example.com/test.nzb",
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "nzbgeek",
+		GUID:        "guid-123",
+	}))
+
+	var df *events.DownloadFailed
+	select {
+	case e := <-failed:
+		df = e.(*events.DownloadFailed)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadFailed event")
+	}
+	assert.True(t, df.Retryable)
+	assert.Contains(t, df.Reason, "503")
+	require.NotZero(t, df.DownloadID)
+
+	require.Len(t, retrying, 2)
+	first := (<-retrying).(*events.GrabRetrying)
+	assert.Equal(t, 1, first.Attempt)
+	assert.Equal(t, 3, first.MaxAttempts)
+	assert.Equal(t, "sabnzbd", first.Client)
+	assert.Equal(t, "Test.Movie.2024.1080p", first.ReleaseName)
+
+	dl, err := store.Get(df.DownloadID)
+	require.NoError(t, err)
+	assert.Equal(t, download.StatusFailed, dl.Status)
+	assert.Empty(t, dl.ClientID)
+	assert.Equal(t, "unexpected status: 503", dl.LastError)
+
+	// The failure is recorded, but the release never reached the client so it isn't blocklisted
+	require.Eventually(t, func() bool {
+		entries, _, err := history.List(importer.HistoryFilter{})
+		return err == nil && len(entries) == 1 && entries[0].Event == importer.EventFailed
+	}, time.Second, 10*time.Millisecond)
+	blocked, err := store.IsBlocked(42, "guid-123")
+	require.NoError(t, err)
+	assert.False(t, blocked)
+}
+
+func TestDownloadHandler_GrabFallsBackToAlternate(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	require.NoError(t, store.Block(&download.BlocklistEntry{ContentID: 42, GUID: "guid-blocked", ReleaseName: "Blocked"}))
+	notFound := &download.StatusError{StatusCode: 404}
+	client := &mockDownloader{returnID: "SABnzbd_nzo_ok", urlErrors: map[string]error{
+		"https://example.com/best.nzb":   notFound,
+		"https://example.com/second.nzb": notFound,
+	}}
+
+	handler := NewDownloadHandler(bus, store, nil, singleClient(client), nil)
+
+	fallbacks := bus.Subscribe(events.EventGrabFallback, 10)
+	created := bus.Subscribe(events.EventDownloadCreated, 10)
+	failed := bus.Subscribe(events.EventDownloadFailed, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "https://example.com/best.nzb",
+		ReleaseName: "Best.Release",
+		GUID:        "guid-best",
+		Alternates: []events.GrabAlternate{
+			{DownloadURL: "https://example.com/blocked.nzb", ReleaseName: "Blocked", GUID: "guid-blocked"},
+			{DownloadURL: "https://example.com/second.nzb", ReleaseName: "Second.Release", GUID: "guid-second"},
+			{DownloadURL: "https://example.com/third.nzb", ReleaseName: "Third.Release", Indexer: "nzbgeek", GUID: "guid-third"},
+		},
+	}))
+
+	var dc *events.DownloadCreated
+	select {
+	case e := <-created:
+		dc = e.(*events.DownloadCreated)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadCreated event")
+	}
+	assert.Equal(t, "Third.Release", dc.ReleaseName)
+
+	// One fallback per rejected release; the blocklisted alternate is never tried
+	require.Len(t, fallbacks, 2)
+	first := (<-fallbacks).(*events.GrabFallback)
+	assert.Equal(t, "Best.Release", first.FailedRelease)
+	assert.Equal(t, "Second.Release", first.NextRelease)
+	assert.Equal(t, 1, first.Attempt)
+	assert.Equal(t, 3, first.MaxAttempts)
+	assert.Contains(t, first.Error, "404")
+	second := (<-fallbacks).(*events.GrabFallback)
+	assert.Equal(t, "Third.Release", second.NextRelease)
+	assert.Empty(t, failed, "no failure while an alternate succeeds")
+
+	dl, err := store.Get(dc.DownloadID)
+	require.NoError(t, err)
+	assert.Equal(t, "guid-third", dl.GUID)
+	assert.Equal(t, "nzbgeek", dl.Indexer)
+	_, total, err := store.List(download.Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total, "rejected releases leave no records")
+}
+
+func TestDownloadHandler_GrabFallbacksExhausted(t *testing.T) {
+	db := setupDownloadTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	client := &mockDownloader{returnError: &download.StatusError{StatusCode: 404}}
+
+	handler := NewDownloadHandler(bus, store, nil, singleClient(client), nil)
+	handler.SetMaxFallbacks(1)
+
+	fallbacks := bus.Subscribe(events.EventGrabFallback, 10)
+	failed := bus.Subscribe(events.EventDownloadFailed, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   42,
+		DownloadURL: "https://example.com/best.nzb",
+		ReleaseName: "Best.Release",
+		Alternates: []events.GrabAlternate{
+			{DownloadURL: "https://example.com/second.nzb", ReleaseName: "Second.Release"},
+			{DownloadURL: "https://example.com/third.nzb", ReleaseName: "Third.Release"},
+		},
+	}))
+
+	var df *events.DownloadFailed
+	select {
+	case e := <-failed:
+		df = e.(*events.DownloadFailed)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for DownloadFailed event")
+	}
+	assert.False(t, df.Retryable)
+	assert.Len(t, fallbacks, 1, "only one alternate is allowed")
+
+	dl, err := store.Get(df.DownloadID)
+	require.NoError(t, err)
+	assert.Equal(t, "Second.Release", dl.ReleaseName, "the last release tried is recorded as failed")
+	assert.Equal(t, "https://example.com/second.nzb", client.lastURL)
+}
+
+func TestDownloadHandler_PinnedGrabFailedUnpins(t *testing.T) {
+	db := setupDownloadTestDBWithLibrary(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	_, err := db.Exec(`INSERT INTO content (id, type, title, year, root_path) VALUES (42, 'movie', 'Test Movie', 2024, '/movies')`)
+	require.NoError(t, err)
+	libraryStore := library.NewStore(db)
+	pin := &library.PinnedRelease{GUID: "pinned", DownloadURL: "https://example.com/dead.nzb", Title: "Test.Movie.2024.2160p"}
+	require.NoError(t, libraryStore.PinRelease(42, pin))
+
+	client := &mockDownloader{returnError: &download.StatusError{StatusCode: 404}}
+	handler := NewDownloadHandler(bus, download.NewStore(db), libraryStore, singleClient(client), nil)
+
+	pinFailures := bus.Subscribe(events.EventPinnedGrabFailed, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = handler.Start(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, search.PinnedGrab(42, pin)))
+
+	select {
+	case e := <-pinFailures:
+		failed := e.(*events.PinnedGrabFailed)
+		assert.Equal(t, int64(42), failed.ContentID)
+		assert.Equal(t, "Test.Movie.2024.2160p", failed.ReleaseName)
+		assert.NotEmpty(t, failed.Reason)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for PinnedGrabFailed event")
+	}
+
+	remaining, err := libraryStore.PinnedRelease(42)
+	require.NoError(t, err)
+	assert.Nil(t, remaining, "a pin that failed to grab is dropped")
+}
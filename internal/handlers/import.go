@@ -3,7 +3,9 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"os"
 	"sync"
 
 	"github.com/vmunix/arrgo/internal/download"
@@ -19,58 +21,226 @@ type FileImporter interface {
 	ImportSeasonPack(ctx context.Context, downloadID int64, path string) (*importer.SeasonPackResult, error)
 }
 
+// DefaultImportConcurrency is how many imports run at once unless configured.
+const DefaultImportConcurrency = 2
+
 // ImportHandler handles file import when downloads complete.
+//
+// Completed downloads go into the import queue kept by the download store,
+// which survives restarts, and a few workers import them in queue order, so a
+// batch of completions does not copy every release to disk at once.
 type ImportHandler struct {
 	*BaseHandler
-	store    *download.Store
-	library  *library.Store
-	importer FileImporter
+	store        *download.Store
+	library      *library.Store
+	importer     FileImporter
+	downloadRoot string // Scanned for downloads missing from their reported path (optional)
+	concurrency  int
+	wake         chan struct{} // Tells an idle worker the queue may have work
 
-	// Per-download lock to prevent concurrent imports
-	importing sync.Map // map[int64]bool
+	// In-flight imports run on importCtx rather than the handler's context,
+	// so stopping the handler gives them a grace period (see Drain).
+	importCtx context.Context
+	abort     context.CancelFunc
+	mu        sync.Mutex
+	importing map[int64]bool // Downloads claimed by a worker
+	draining  bool
+	inflight  sync.WaitGroup
 }
 
 // NewImportHandler creates a new import handler.
 func NewImportHandler(bus *events.Bus, store *download.Store, lib *library.Store, imp FileImporter, logger *slog.Logger) *ImportHandler {
+	importCtx, abort := context.WithCancel(context.Background())
 	return &ImportHandler{
 		BaseHandler: NewBaseHandler(bus, logger),
 		store:       store,
 		library:     lib,
 		importer:    imp,
+		concurrency: DefaultImportConcurrency,
+		wake:        make(chan struct{}, 1),
+		importCtx:   importCtx,
+		abort:       abort,
+		importing:   make(map[int64]bool),
+	}
+}
+
+// SetConcurrency sets how many imports run at once. Call before Start.
+func (h *ImportHandler) SetConcurrency(n int) {
+	if n > 0 {
+		h.concurrency = n
 	}
 }
 
+// SetDownloadRoot sets the directory scanned for a completed download whose
+// reported path does not exist, such as an obfuscated release.
+func (h *ImportHandler) SetDownloadRoot(root string) {
+	h.downloadRoot = root
+}
+
 // Name returns the handler name.
 func (h *ImportHandler) Name() string {
 	return "import"
 }
 
-// Start begins processing events.
+// Start begins processing events. Completed downloads are queued for the
+// import workers, which first resume the queue left by a previous run.
 func (h *ImportHandler) Start(ctx context.Context) error {
 	completed := h.Bus().Subscribe(events.EventDownloadCompleted, 100)
 
+	for range h.concurrency {
+		go h.worker(ctx)
+	}
+	h.notify()
+
 	for {
 		select {
 		case e := <-completed:
 			if e == nil {
 				return nil // Channel closed
 			}
-			// Process in goroutine to not block other events
-			go h.handleDownloadCompleted(ctx, e.(*events.DownloadCompleted))
+			h.enqueue(ctx, e.(*events.DownloadCompleted))
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
-func (h *ImportHandler) handleDownloadCompleted(ctx context.Context, e *events.DownloadCompleted) {
-	// Acquire per-download lock (prevents concurrent imports)
-	if _, loaded := h.importing.LoadOrStore(e.DownloadID, true); loaded {
-		h.Logger().Warn("import already in progress", "download_id", e.DownloadID)
+// enqueue adds a completed download to the import queue. A download that is
+// already queued keeps its place, so a repeated event does not import twice.
+func (h *ImportHandler) enqueue(ctx context.Context, e *events.DownloadCompleted) {
+	dl, err := h.store.Get(e.DownloadID)
+	if err != nil {
+		h.Logger().Error("failed to get download", "download_id", e.DownloadID, "error", err)
+		h.publishImportFailed(ctx, e.DownloadID, err.Error())
 		return
 	}
-	defer h.importing.Delete(e.DownloadID)
+	if dl.Status != download.StatusCompleted {
+		h.Logger().Warn("download not completed, import not queued", "download_id", e.DownloadID, "status", dl.Status)
+		return
+	}
+	if err := h.store.QueueImport(e.DownloadID, e.SourcePath); err != nil {
+		h.Logger().Error("failed to queue import", "download_id", e.DownloadID, "error", err)
+		h.publishImportFailed(ctx, e.DownloadID, err.Error())
+		return
+	}
+	h.Logger().Info("import queued", "download_id", e.DownloadID, "path", e.SourcePath)
+	h.notify()
+}
 
+// notify wakes an idle worker, if one is not already due to look at the queue.
+func (h *ImportHandler) notify() {
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+}
+
+// worker imports queued downloads one at a time until ctx is done.
+func (h *ImportHandler) worker(ctx context.Context) {
+	for {
+		q, ok := h.claim()
+		if !ok {
+			select {
+			case <-h.wake:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		h.handleDownloadCompleted(h.importCtx, &events.DownloadCompleted{
+			DownloadID: q.DownloadID,
+			SourcePath: q.SourcePath,
+		})
+		h.release(q.DownloadID)
+	}
+}
+
+// claim takes the first queued download no other worker is importing. It
+// returns false if there is none or the handler is draining; queued imports
+// then wait for the next start.
+func (h *ImportHandler) claim() (download.QueuedImport, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.draining {
+		return download.QueuedImport{}, false
+	}
+	queue, err := h.store.ImportQueue()
+	if err != nil {
+		h.Logger().Error("failed to list import queue", "error", err)
+		return download.QueuedImport{}, false
+	}
+	for i, q := range queue {
+		if h.importing[q.DownloadID] {
+			continue
+		}
+		h.importing[q.DownloadID] = true
+		h.inflight.Add(1)
+		if i < len(queue)-1 {
+			h.notify() // More waiting: let another idle worker take one
+		}
+		return q, true
+	}
+	return download.QueuedImport{}, false
+}
+
+// release ends a worker's import of a download and removes it from the queue,
+// unless Drain interrupted it: it then stays queued, ahead of later arrivals.
+func (h *ImportHandler) release(downloadID int64) {
+	if h.importCtx.Err() == nil {
+		if err := h.store.DequeueImport(downloadID); err != nil {
+			h.Logger().Error("failed to dequeue import", "download_id", downloadID, "error", err)
+		}
+	}
+	h.mu.Lock()
+	delete(h.importing, downloadID)
+	h.mu.Unlock()
+	h.inflight.Done()
+}
+
+// Drain stops accepting new imports and waits for in-flight ones to finish.
+// Imports still running when ctx is done are canceled: the importer removes
+// their partial files and their downloads return to completed, so they can
+// be imported again after a restart. Returns ctx.Err() if any were canceled.
+func (h *ImportHandler) Drain(ctx context.Context) error {
+	h.mu.Lock()
+	h.draining = true
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+	h.Logger().Warn("canceling in-flight imports", "error", ctx.Err())
+	h.abort()
+	<-done
+	return ctx.Err()
+}
+
+// importFailed reports a failed import. An import canceled by Drain is not a
+// failure of the release: the download goes back to completed instead.
+func (h *ImportHandler) importFailed(ctx context.Context, dl *download.Download, err error) {
+	if h.importCtx.Err() == nil || !errors.Is(err, context.Canceled) {
+		h.publishImportFailed(ctx, dl.ID, err.Error())
+		return
+	}
+	if err := h.store.Transition(dl, download.StatusCompleted); err != nil {
+		h.Logger().Error("failed to return interrupted import to completed", "download_id", dl.ID, "error", err)
+		return
+	}
+	h.Logger().Warn("import interrupted by shutdown, will retry", "download_id", dl.ID)
+}
+
+// handleDownloadCompleted imports a completed download. Workers call it for
+// one download at a time (see claim). The download is claimed in the store
+// before any work, so a download the import API took first is skipped.
+func (h *ImportHandler) handleDownloadCompleted(ctx context.Context, e *events.DownloadCompleted) {
 	// Get download from store to retrieve ContentID for events
 	dl, err := h.store.Get(e.DownloadID)
 	if err != nil {
@@ -78,6 +248,10 @@ func (h *ImportHandler) handleDownloadCompleted(ctx context.Context, e *events.D
 		h.publishImportFailed(ctx, e.DownloadID, err.Error())
 		return
 	}
+	if dl.Status != download.StatusCompleted {
+		h.Logger().Debug("download no longer completed, skipping import", "download_id", e.DownloadID, "status", dl.Status)
+		return
+	}
 
 	// Check for existing files before importing (duplicate prevention)
 	// Must happen before transitioning to importing, since completed→skipped is valid but importing→skipped is not
@@ -107,8 +281,12 @@ func (h *ImportHandler) handleDownloadCompleted(ctx context.Context, e *events.D
 					"release", dl.ReleaseName)
 
 				// Transition to skipped status (from completed state)
-				if err := h.store.Transition(dl, download.StatusSkipped); err != nil {
+				won, err := h.store.Claim(dl, download.StatusSkipped)
+				if err != nil {
 					h.Logger().Error("failed to transition to skipped", "download_id", e.DownloadID, "error", err)
+				} else if !won {
+					h.Logger().Debug("download claimed by another import, skipping", "download_id", e.DownloadID)
+					return
 				}
 
 				// Emit ImportSkipped event
@@ -134,18 +312,30 @@ func (h *ImportHandler) handleDownloadCompleted(ctx context.Context, e *events.D
 		}
 	}
 
-	// Transition to importing status
-	if err := h.store.Transition(dl, download.StatusImporting); err != nil {
+	// Claim the download; the loser of a race with the import API does nothing
+	won, err := h.store.Claim(dl, download.StatusImporting)
+	if err != nil {
 		h.Logger().Error("failed to transition to importing", "download_id", e.DownloadID, "error", err)
 		h.publishImportFailed(ctx, e.DownloadID, err.Error())
 		return
 	}
+	if !won {
+		h.Logger().Debug("download claimed by another import, skipping", "download_id", e.DownloadID)
+		return
+	}
+
+	sourcePath, err := h.locateSource(dl, e.SourcePath)
+	if err != nil {
+		h.Logger().Error("download files not found", "download_id", e.DownloadID, "path", e.SourcePath, "error", err)
+		h.importFailed(ctx, dl, err)
+		return
+	}
 
 	// Emit ImportStarted event
 	if err := h.Bus().Publish(ctx, &events.ImportStarted{
 		BaseEvent:  events.NewBaseEvent(events.EventImportStarted, events.EntityDownload, e.DownloadID),
 		DownloadID: e.DownloadID,
-		SourcePath: e.SourcePath,
+		SourcePath: sourcePath,
 	}); err != nil {
 		h.Logger().Error("failed to publish ImportStarted event", "error", err)
 	}
@@ -153,15 +343,37 @@ func (h *ImportHandler) handleDownloadCompleted(ctx context.Context, e *events.D
 	h.Logger().Info("starting import",
 		"download_id", e.DownloadID,
 		"content_id", dl.ContentID,
-		"path", e.SourcePath,
+		"path", sourcePath,
 		"is_complete_season", dl.IsCompleteSeason)
 
 	// Route to appropriate import handler based on download type
 	if dl.IsCompleteSeason {
-		h.handleSeasonPackImport(ctx, dl, e.SourcePath)
+		h.handleSeasonPackImport(ctx, dl, sourcePath)
 	} else {
-		h.handleSingleFileImport(ctx, dl, e.SourcePath)
+		h.handleSingleFileImport(ctx, dl, sourcePath)
+	}
+}
+
+// locateSource returns where a completed download's files are: the path the
+// client reported, or if that is missing, the expected path under the download
+// root or a confident match found by scanning it (see importer.LocateDownload).
+func (h *ImportHandler) locateSource(dl *download.Download, reported string) (string, error) {
+	if _, err := os.Stat(reported); err == nil || h.library == nil || h.downloadRoot == "" {
+		return reported, nil
+	}
+	content, err := h.library.GetContent(dl.ContentID)
+	if err != nil {
+		return "", err
+	}
+	path, err := importer.LocateDownload(h.downloadRoot, dl, content, download.SourcePath(h.downloadRoot, dl))
+	if err != nil {
+		return "", err
 	}
+	h.Logger().Info("located download away from reported path",
+		"download_id", dl.ID,
+		"reported", reported,
+		"path", path)
+	return path, nil
 }
 
 // handleSingleFileImport handles import of a single-file download (movie or single episode).
@@ -170,7 +382,7 @@ func (h *ImportHandler) handleSingleFileImport(ctx context.Context, dl *download
 	result, err := h.importer.Import(ctx, dl.ID, sourcePath)
 	if err != nil {
 		h.Logger().Error("import failed", "download_id", dl.ID, "error", err)
-		h.publishImportFailed(ctx, dl.ID, err.Error())
+		h.importFailed(ctx, dl, err)
 		return
 	}
 
@@ -179,6 +391,7 @@ func (h *ImportHandler) handleSingleFileImport(ctx context.Context, dl *download
 		h.Logger().Error("failed to transition to imported", "download_id", dl.ID, "error", err)
 		// Don't return - the import succeeded, just log the transition failure
 	}
+	h.unpinImported(dl)
 
 	// Emit ImportCompleted event
 	if err := h.Bus().Publish(ctx, &events.ImportCompleted{
@@ -192,6 +405,8 @@ func (h *ImportHandler) handleSingleFileImport(ctx context.Context, dl *download
 		h.Logger().Error("failed to publish ImportCompleted event", "error", err)
 	}
 
+	h.publishHookFailed(ctx, dl, result.Hook)
+
 	h.Logger().Info("import completed",
 		"download_id", dl.ID,
 		"content_id", dl.ContentID,
@@ -205,30 +420,39 @@ func (h *ImportHandler) handleSeasonPackImport(ctx context.Context, dl *download
 	result, err := h.importer.ImportSeasonPack(ctx, dl.ID, sourcePath)
 	if err != nil {
 		h.Logger().Error("season pack import failed", "download_id", dl.ID, "error", err)
-		h.publishImportFailed(ctx, dl.ID, err.Error())
+		h.importFailed(ctx, dl, err)
 		return
 	}
 
-	// Transition to imported status
-	if err := h.store.Transition(dl, download.StatusImported); err != nil {
-		h.Logger().Error("failed to transition to imported", "download_id", dl.ID, "error", err)
+	// Transition to imported, or partially imported if any episode failed;
+	// the failed episodes can then be reimported through the API
+	status := result.DownloadStatus()
+	if err := h.store.Transition(dl, status); err != nil {
+		h.Logger().Error("failed to transition after import", "download_id", dl.ID, "status", status, "error", err)
 		// Don't return - the import succeeded, just log the transition failure
 	}
+	if result.SuccessCount() > 0 {
+		h.unpinImported(dl)
+	}
 
 	// Convert importer results to event results
 	episodeResults := make([]events.EpisodeImportResult, 0, len(result.Episodes))
 	for _, ep := range result.Episodes {
 		epResult := events.EpisodeImportResult{
-			EpisodeID: ep.EpisodeID,
-			Season:    ep.Season,
-			Episode:   ep.Episode,
-			Success:   ep.Success,
-			FilePath:  ep.FilePath,
+			EpisodeID:       ep.EpisodeID,
+			FileID:          ep.FileID,
+			Season:          ep.Season,
+			Episode:         ep.Episode,
+			Success:         ep.Success,
+			SourceFile:      ep.SourceFile,
+			FilePath:        ep.FilePath,
+			AlreadyImported: ep.AlreadyImported,
 		}
 		if ep.Error != nil {
 			epResult.Error = ep.Error.Error()
 		}
 		episodeResults = append(episodeResults, epResult)
+		h.publishHookFailed(ctx, dl, ep.Hook)
 	}
 
 	// Emit ImportCompleted event with episode results
@@ -244,12 +468,54 @@ func (h *ImportHandler) handleSeasonPackImport(ctx context.Context, dl *download
 
 	h.Logger().Info("season pack import completed",
 		"download_id", dl.ID,
+		"status", status,
 		"content_id", dl.ContentID,
 		"episodes_total", len(result.Episodes),
 		"episodes_success", result.SuccessCount(),
 		"total_size", result.TotalSize)
 }
 
+// unpinImported unpins the release pinned for a download's content once the
+// download, being that release, has imported (best effort).
+func (h *ImportHandler) unpinImported(dl *download.Download) {
+	if h.library == nil {
+		return
+	}
+	content, err := h.library.GetContent(dl.ContentID)
+	if err != nil {
+		h.Logger().Warn("failed to check pinned release", "content_id", dl.ContentID, "error", err)
+		return
+	}
+	if content.Pin == nil || !content.Pin.Matches(dl.GUID, dl.ReleaseName) {
+		return
+	}
+	if err := h.library.UnpinRelease(dl.ContentID); err != nil && !errors.Is(err, library.ErrNotFound) {
+		h.Logger().Warn("failed to unpin imported release", "content_id", dl.ContentID, "error", err)
+		return
+	}
+	h.Logger().Info("pinned release imported, unpinned", "content_id", dl.ContentID, "release", dl.ReleaseName)
+}
+
+// publishHookFailed emits HookFailed if the post-import hook ran and failed.
+// The import itself stands.
+func (h *ImportHandler) publishHookFailed(ctx context.Context, dl *download.Download, hook *importer.HookResult) {
+	if hook == nil || !hook.Failed() {
+		return
+	}
+	if err := h.Bus().Publish(ctx, &events.HookFailed{
+		BaseEvent:  events.NewBaseEvent(events.EventHookFailed, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		ContentID:  dl.ContentID,
+		Hook:       hook.Hook,
+		Path:       hook.Path,
+		ExitCode:   hook.ExitCode,
+		Error:      hook.Err.Error(),
+		Stderr:     hook.Stderr,
+	}); err != nil {
+		h.Logger().Error("failed to publish HookFailed event", "error", err)
+	}
+}
+
 func (h *ImportHandler) publishImportFailed(ctx context.Context, downloadID int64, reason string) {
 	if err := h.Bus().Publish(ctx, &events.ImportFailed{
 		BaseEvent:  events.NewBaseEvent(events.EventImportFailed, events.EntityDownload, downloadID),
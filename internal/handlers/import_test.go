@@ -5,8 +5,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -35,6 +39,7 @@ func setupImportTestDB(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL,
 			release_name TEXT NOT NULL,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT NOT NULL,
 			added_at TIMESTAMP NOT NULL,
 			completed_at TIMESTAMP,
@@ -44,12 +49,21 @@ func setupImportTestDB(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
 		);
 		CREATE TABLE download_episodes (
 			download_id INTEGER NOT NULL,
 			episode_id  INTEGER NOT NULL,
 			PRIMARY KEY (download_id, episode_id)
+		);
+		CREATE TABLE import_queue (
+			download_id INTEGER PRIMARY KEY,
+			position INTEGER NOT NULL,
+			source_path TEXT NOT NULL DEFAULT '',
+			queued_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
 	require.NoError(t, err)
@@ -63,7 +77,9 @@ type mockImporter struct {
 	callCount    int
 	lastID       int64
 	lastPath     string
+	imported     []int64 // Download IDs in the order imported
 	returnResult *importer.ImportResult
+	returnPack   *importer.SeasonPackResult // Season pack result (default: empty pack)
 	returnError  error
 	delay        time.Duration // Artificial delay for concurrency tests
 }
@@ -80,6 +96,7 @@ func (m *mockImporter) Import(ctx context.Context, downloadID int64, path string
 	m.callCount++
 	m.lastID = downloadID
 	m.lastPath = path
+	m.imported = append(m.imported, downloadID)
 
 	if m.returnError != nil {
 		return nil, m.returnError
@@ -99,10 +116,14 @@ func (m *mockImporter) ImportSeasonPack(ctx context.Context, downloadID int64, p
 	m.callCount++
 	m.lastID = downloadID
 	m.lastPath = path
+	m.imported = append(m.imported, downloadID)
 
 	if m.returnError != nil {
 		return nil, m.returnError
 	}
+	if m.returnPack != nil {
+		return m.returnPack, nil
+	}
 	return &importer.SeasonPackResult{
 		TotalSize: 10000000000,
 		Episodes:  []importer.EpisodeResult{},
@@ -115,6 +136,12 @@ func (m *mockImporter) getCallCount() int {
 	return m.callCount
 }
 
+func (m *mockImporter) getImported() []int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]int64(nil), m.imported...)
+}
+
 func TestImportHandler_Name(t *testing.T) {
 	bus := events.NewBus(nil, nil)
 	defer bus.Close()
@@ -204,6 +231,59 @@ func TestImportHandler_DownloadCompleted(t *testing.T) {
 	assert.Equal(t, "/downloads/Test.Movie.2024.1080p", imp.lastPath)
 }
 
+func TestImportHandler_SeasonPackPartiallyImported(t *testing.T) {
+	db := setupImportTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	season := 1
+	dl := &download.Download{
+		ContentID:        42,
+		Season:           &season,
+		IsCompleteSeason: true,
+		Client:           download.ClientSABnzbd,
+		ClientID:         "sab-123",
+		Status:           download.StatusCompleted,
+		ReleaseName:      "Test.Show.S01.1080p",
+		Indexer:          "nzbgeek",
+	}
+	require.NoError(t, store.Add(dl))
+
+	imp := &mockImporter{
+		returnPack: &importer.SeasonPackResult{
+			TotalSize: 2000,
+			Episodes: []importer.EpisodeResult{
+				{EpisodeID: 1, Season: 1, Episode: 1, Success: true, SourceFile: "Test.Show.S01E01.mkv", FilePath: "/tv/Test Show/S01E01.mkv"},
+				{SourceFile: "Test.Show.Bonus.mkv", Error: errors.New("match file to season: no match")},
+			},
+		},
+	}
+	handler := NewImportHandler(bus, store, nil, imp, nil)
+	completed := bus.Subscribe(events.EventImportCompleted, 10)
+
+	handler.handleDownloadCompleted(context.Background(), &events.DownloadCompleted{
+		BaseEvent:  events.NewBaseEvent(events.EventDownloadCompleted, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		SourcePath: "/downloads/Test.Show.S01.1080p",
+	})
+
+	select {
+	case e := <-completed:
+		ic := e.(*events.ImportCompleted)
+		assert.False(t, ic.AllSucceeded())
+		require.Len(t, ic.EpisodeResults, 2)
+		assert.Equal(t, "Test.Show.S01E01.mkv", ic.EpisodeResults[0].SourceFile)
+		assert.Equal(t, "match file to season: no match", ic.EpisodeResults[1].Error)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for ImportCompleted event")
+	}
+
+	updated, err := store.Get(dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, download.StatusPartiallyImported, updated.Status)
+}
+
 func TestImportHandler_ImportFailed(t *testing.T) {
 	db := setupImportTestDB(t)
 	bus := events.NewBus(nil, nil)
@@ -500,6 +580,7 @@ func setupImportTestDBWithLibrary(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL,
 			release_name TEXT NOT NULL,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT NOT NULL,
 			added_at TIMESTAMP NOT NULL,
 			completed_at TIMESTAMP,
@@ -509,13 +590,22 @@ func setupImportTestDBWithLibrary(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
 		);
 		CREATE TABLE download_episodes (
 			download_id INTEGER NOT NULL,
 			episode_id  INTEGER NOT NULL,
 			PRIMARY KEY (download_id, episode_id)
 		);
+		CREATE TABLE import_queue (
+			download_id INTEGER PRIMARY KEY,
+			position INTEGER NOT NULL,
+			source_path TEXT NOT NULL DEFAULT '',
+			queued_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
 		CREATE TABLE content (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			type TEXT NOT NULL,
@@ -533,7 +623,12 @@ func setupImportTestDBWithLibrary(t *testing.T) *sql.DB {
 			size_bytes INTEGER,
 			quality TEXT,
 			source TEXT,
-			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			checksum TEXT,
+			part_number INTEGER,
+			release_group TEXT NOT NULL DEFAULT '',
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			missing_at TIMESTAMP,
+			kind TEXT NOT NULL DEFAULT 'media'
 		);
 	`)
 	require.NoError(t, err)
@@ -683,3 +778,236 @@ func TestImportHandler_ImportProceeds_QualityUpgrade(t *testing.T) {
 
 	assert.True(t, imp.importCalled, "importer should be called for upgrade")
 }
+
+// slowCopyImporter imports with the real file copy from a source that
+// trickles in, like a stalled network filesystem.
+type slowCopyImporter struct {
+	src, dst string
+}
+
+func (m *slowCopyImporter) Import(ctx context.Context, _ int64, _ string) (*importer.ImportResult, error) {
+	size, err := importer.CopyFileContext(ctx, m.src, m.dst)
+	if err != nil {
+		return nil, err
+	}
+	return &importer.ImportResult{DestPath: m.dst, SizeBytes: size}, nil
+}
+
+func (m *slowCopyImporter) ImportSeasonPack(context.Context, int64, string) (*importer.SeasonPackResult, error) {
+	return nil, errors.New("not a season pack")
+}
+
+// slowSource returns a FIFO that receives a small chunk every few
+// milliseconds until the reader goes away or the test ends.
+func slowSource(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "movie.mkv")
+	require.NoError(t, syscall.Mkfifo(path, 0o600))
+
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+	go func() {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		chunk := make([]byte, 4096)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+			if _, err := f.Write(chunk); err != nil {
+				return // Reader closed
+			}
+		}
+	}()
+	return path
+}
+
+func TestImportHandler_DrainCancelsSlowImport(t *testing.T) {
+	db := setupImportTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	dl := &download.Download{
+		ContentID:   42,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "sab-123",
+		Status:      download.StatusCompleted,
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "nzbgeek",
+	}
+	require.NoError(t, store.Add(dl))
+
+	dst := filepath.Join(t.TempDir(), "Test Movie (2024)", "Test Movie (2024) - 1080p.mkv")
+	handler := NewImportHandler(bus, store, nil, &slowCopyImporter{src: slowSource(t), dst: dst}, nil)
+	failed := bus.Subscribe(events.EventImportFailed, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		_ = handler.Start(ctx)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.DownloadCompleted{
+		BaseEvent:  events.NewBaseEvent(events.EventDownloadCompleted, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		SourcePath: "/downloads/Test.Movie.2024.1080p",
+	}))
+
+	// Wait until the copy is under way
+	require.Eventually(t, func() bool {
+		info, err := os.Stat(dst)
+		return err == nil && info.Size() > 0
+	}, 2*time.Second, 5*time.Millisecond, "copy should start")
+
+	// Shut down: stop the handler, then give the import a short grace period
+	cancel()
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer drainCancel()
+	require.ErrorIs(t, handler.Drain(drainCtx), context.DeadlineExceeded)
+
+	_, err := os.Stat(dst)
+	assert.True(t, os.IsNotExist(err), "partial destination file should be removed")
+
+	got, err := store.Get(dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, download.StatusCompleted, got.Status, "download should be ready to import again")
+	queue, err := store.ImportQueue()
+	require.NoError(t, err)
+	require.Len(t, queue, 1, "interrupted import should stay queued for the next start")
+	assert.Equal(t, "/downloads/Test.Movie.2024.1080p", queue[0].SourcePath)
+
+	select {
+	case e := <-failed:
+		t.Fatalf("interrupted import must not be reported as failed: %+v", e)
+	default:
+	}
+}
+
+func TestImportHandler_DrainWaitsForImport(t *testing.T) {
+	db := setupImportTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	dl := &download.Download{
+		ContentID:   42,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "sab-123",
+		Status:      download.StatusCompleted,
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "nzbgeek",
+	}
+	require.NoError(t, store.Add(dl))
+
+	imp := &mockImporter{
+		delay:        100 * time.Millisecond,
+		returnResult: &importer.ImportResult{DestPath: "/movies/Test Movie (2024)/Test Movie (2024) - 1080p.mkv"},
+	}
+	handler := NewImportHandler(bus, store, nil, imp, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		_ = handler.Start(ctx)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, bus.Publish(ctx, &events.DownloadCompleted{
+		BaseEvent:  events.NewBaseEvent(events.EventDownloadCompleted, events.EntityDownload, dl.ID),
+		DownloadID: dl.ID,
+		SourcePath: "/downloads/Test.Movie.2024.1080p",
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	// The handler stops, but the import finishes within the grace period
+	cancel()
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer drainCancel()
+	require.NoError(t, handler.Drain(drainCtx))
+
+	assert.Equal(t, 1, imp.getCallCount())
+	got, err := store.Get(dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, download.StatusImported, got.Status)
+}
+
+func TestImportHandler_ImportsQueueInOrder(t *testing.T) {
+	db := setupImportTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	var ids []int64
+	for i := range 3 {
+		dl := &download.Download{
+			ContentID:   int64(42 + i),
+			Client:      download.ClientSABnzbd,
+			ClientID:    fmt.Sprintf("sab-%d", i),
+			Status:      download.StatusCompleted,
+			ReleaseName: fmt.Sprintf("Test.Movie.%d.1080p", i),
+			Indexer:     "nzbgeek",
+		}
+		require.NoError(t, store.Add(dl))
+		ids = append(ids, dl.ID)
+	}
+
+	// Queued by a previous run; the last one was moved to the front
+	for _, id := range ids[:2] {
+		require.NoError(t, store.QueueImport(id, fmt.Sprintf("/downloads/%d", id)))
+	}
+	require.NoError(t, store.QueueImport(ids[2], "/downloads/last"))
+	require.NoError(t, store.PrioritizeImport(ids[2]))
+
+	imp := &mockImporter{
+		delay:        20 * time.Millisecond,
+		returnResult: &importer.ImportResult{DestPath: "/movies/Test Movie (2024)/Test Movie (2024) - 1080p.mkv"},
+	}
+	handler := NewImportHandler(bus, store, nil, imp, nil)
+	handler.SetConcurrency(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = handler.Start(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return imp.getCallCount() == 3
+	}, 2*time.Second, 10*time.Millisecond, "queued imports should resume on start")
+	assert.Equal(t, []int64{ids[2], ids[0], ids[1]}, imp.getImported(), "one at a time, in queue order")
+
+	require.Eventually(t, func() bool {
+		queue, err := store.ImportQueue()
+		return err == nil && len(queue) == 0
+	}, time.Second, 10*time.Millisecond, "imported downloads should leave the queue")
+}
+
+func TestImportHandler_QueuesOnlyCompletedDownloads(t *testing.T) {
+	db := setupImportTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	store := download.NewStore(db)
+	dl := &download.Download{
+		ContentID:   42,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "sab-123",
+		Status:      download.StatusImported,
+		ReleaseName: "Test.Movie.2024.1080p",
+		Indexer:     "nzbgeek",
+	}
+	require.NoError(t, store.Add(dl))
+
+	imp := &mockImporter{}
+	handler := NewImportHandler(bus, store, nil, imp, nil)
+	handler.enqueue(context.Background(), &events.DownloadCompleted{DownloadID: dl.ID})
+
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM import_queue").Scan(&n))
+	assert.Zero(t, n, "a late event for an imported download should not queue it again")
+}
@@ -4,12 +4,19 @@ package handlers_test
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/adapters/sabnzbd"
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
 	"github.com/vmunix/arrgo/internal/handlers"
@@ -33,6 +40,7 @@ func setupIntegrationDB(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL,
 			release_name TEXT NOT NULL,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT NOT NULL,
 			added_at TIMESTAMP NOT NULL,
 			completed_at TIMESTAMP,
@@ -42,13 +50,22 @@ func setupIntegrationDB(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
 		);
 		CREATE TABLE download_episodes (
 			download_id INTEGER NOT NULL,
 			episode_id  INTEGER NOT NULL,
 			PRIMARY KEY (download_id, episode_id)
 		);
+		CREATE TABLE import_queue (
+			download_id INTEGER PRIMARY KEY,
+			position INTEGER NOT NULL,
+			source_path TEXT NOT NULL DEFAULT '',
+			queued_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
 		CREATE TABLE events (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			event_type TEXT NOT NULL,
@@ -56,7 +73,8 @@ func setupIntegrationDB(t *testing.T) *sql.DB {
 			entity_id INTEGER NOT NULL,
 			payload TEXT NOT NULL,
 			occurred_at TIMESTAMP NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			processed_at TIMESTAMP
 		);
 	`)
 	require.NoError(t, err)
@@ -80,6 +98,7 @@ func setupIntegrationDBWithContent(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL,
 			release_name TEXT NOT NULL,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT NOT NULL,
 			added_at TIMESTAMP NOT NULL,
 			completed_at TIMESTAMP,
@@ -89,13 +108,22 @@ func setupIntegrationDBWithContent(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
 		);
 		CREATE TABLE download_episodes (
 			download_id INTEGER NOT NULL,
 			episode_id  INTEGER NOT NULL,
 			PRIMARY KEY (download_id, episode_id)
 		);
+		CREATE TABLE import_queue (
+			download_id INTEGER PRIMARY KEY,
+			position INTEGER NOT NULL,
+			source_path TEXT NOT NULL DEFAULT '',
+			queued_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
 		CREATE TABLE events (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			event_type TEXT NOT NULL,
@@ -103,7 +131,8 @@ func setupIntegrationDBWithContent(t *testing.T) *sql.DB {
 			entity_id INTEGER NOT NULL,
 			payload TEXT NOT NULL,
 			occurred_at TIMESTAMP NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			processed_at TIMESTAMP
 		);
 		CREATE TABLE content (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -116,7 +145,39 @@ func setupIntegrationDBWithContent(t *testing.T) *sql.DB {
 			quality_profile TEXT NOT NULL DEFAULT 'hd',
 			root_path TEXT NOT NULL,
 			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			overview TEXT NOT NULL DEFAULT '',
+			poster_url TEXT NOT NULL DEFAULT '',
+			runtime INTEGER NOT NULL DEFAULT 0,
+			genres TEXT NOT NULL DEFAULT '[]',
+			metadata_updated_at TIMESTAMP,
+			minimum_availability TEXT NOT NULL DEFAULT 'announced',
+			release_date TIMESTAMP,
+			normalized_title TEXT,
+			daily INTEGER NOT NULL DEFAULT 0,
+			series_status TEXT,
+			season_folder INTEGER,
+			naming_template TEXT,
+			author TEXT NOT NULL DEFAULT '',
+			pinned_guid TEXT,
+			pinned_download_url TEXT,
+			pinned_title TEXT,
+			pinned_indexer TEXT,
+			pinned_protocol TEXT,
+			pinned_season INTEGER,
+			pinned_at TIMESTAMP,
+			collection_id INTEGER,
+			collection_name TEXT NOT NULL DEFAULT '',
+			plex_key TEXT NOT NULL DEFAULT ''
+		);
+		CREATE TABLE content_aliases (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			content_id INTEGER NOT NULL,
+			alias TEXT NOT NULL,
+			normalized_alias TEXT NOT NULL,
+			source TEXT NOT NULL,
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (content_id, normalized_alias)
 		);
 		CREATE TABLE episodes (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -126,6 +187,7 @@ func setupIntegrationDBWithContent(t *testing.T) *sql.DB {
 			title TEXT,
 			status TEXT NOT NULL DEFAULT 'wanted',
 			air_date DATE,
+			quality_profile TEXT,
 			UNIQUE(content_id, season, episode)
 		);
 		CREATE TABLE files (
@@ -136,7 +198,12 @@ func setupIntegrationDBWithContent(t *testing.T) *sql.DB {
 			size_bytes INTEGER,
 			quality TEXT,
 			source TEXT,
-			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			checksum TEXT,
+			part_number INTEGER,
+			release_group TEXT NOT NULL DEFAULT '',
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			missing_at TIMESTAMP,
+			kind TEXT NOT NULL DEFAULT 'media'
 		);
 	`)
 	require.NoError(t, err)
@@ -183,6 +250,13 @@ func (m *integrationImporter) ImportSeasonPack(_ context.Context, _ int64, _ str
 	}, nil
 }
 
+// routeAll routes every grab to client, registered as the SABnzbd client.
+func routeAll(client download.Downloader) *download.Manager {
+	m := download.NewManager(nil, nil)
+	m.AddClient(download.ClientSABnzbd, download.ProtocolUsenet, client)
+	return m
+}
+
 // TestIntegration_GrabToImport tests the full event-driven flow:
 // GrabRequested -> DownloadHandler -> DownloadCreated
 // DownloadCompleted -> ImportHandler -> ImportCompleted
@@ -197,7 +271,7 @@ func TestIntegration_GrabToImport(t *testing.T) {
 	imp := &integrationImporter{}
 
 	// Create handlers
-	downloadHandler := handlers.NewDownloadHandler(bus, store, nil, client, nil)
+	downloadHandler := handlers.NewDownloadHandler(bus, store, nil, routeAll(client), nil)
 	importHandler := handlers.NewImportHandler(bus, store, nil, imp, nil)
 
 	// Subscribe to events we want to track
@@ -303,7 +377,7 @@ func TestIntegration_GrabFailure(t *testing.T) {
 	store := download.NewStore(db)
 	client := &failingDownloader{err: assert.AnError}
 
-	downloadHandler := handlers.NewDownloadHandler(bus, store, nil, client, nil)
+	downloadHandler := handlers.NewDownloadHandler(bus, store, nil, routeAll(client), nil)
 
 	// Subscribe to failure event
 	failed := bus.Subscribe(events.EventDownloadFailed, 10)
@@ -329,15 +403,17 @@ func TestIntegration_GrabFailure(t *testing.T) {
 	select {
 	case e := <-failed:
 		df := e.(*events.DownloadFailed)
-		assert.True(t, df.Retryable)
+		assert.False(t, df.Retryable, "client rejected the grab with a permanent error")
 	case <-time.After(2 * time.Second):
 		t.Fatal("timeout waiting for DownloadFailed event")
 	}
 
-	// Verify no download was created
+	// Verify a failed download records the client's error
 	downloads, _, err := store.List(download.Filter{})
 	require.NoError(t, err)
-	assert.Empty(t, downloads, "no download should be created on failure")
+	require.Len(t, downloads, 1)
+	assert.Equal(t, download.StatusFailed, downloads[0].Status)
+	assert.Equal(t, assert.AnError.Error(), downloads[0].LastError)
 }
 
 // failingDownloader always returns an error.
@@ -515,7 +591,7 @@ func TestIntegration_SeasonPackGrabAndImport(t *testing.T) {
 	require.NoError(t, lib.AddContent(content))
 
 	// Create download handler
-	dlHandler := handlers.NewDownloadHandler(bus, dlStore, lib, client, logger)
+	dlHandler := handlers.NewDownloadHandler(bus, dlStore, lib, routeAll(client), logger)
 
 	// Create a mock importer for season packs
 	imp := &integrationImporter{}
@@ -612,3 +688,133 @@ func TestIntegration_SeasonPackGrabAndImport(t *testing.T) {
 	assert.Contains(t, eventTypes, events.EventImportStarted)
 	assert.Contains(t, eventTypes, events.EventImportCompleted)
 }
+
+// pathImporter is a mock file importer that records the source path it was given.
+type pathImporter struct {
+	integrationImporter
+	mu   sync.Mutex
+	path string
+}
+
+func (m *pathImporter) Import(ctx context.Context, downloadID int64, path string) (*importer.ImportResult, error) {
+	m.mu.Lock()
+	m.path = path
+	m.mu.Unlock()
+	return m.integrationImporter.Import(ctx, downloadID, path)
+}
+
+// TestIntegration_SABnzbdDeduplicatedJob follows a grab whose job SABnzbd
+// renamed with a ".1" suffix, because a job of the same name existed, through
+// to import: the download takes the job's name and is imported from where the
+// job completed, whether or not SABnzbd reports its storage path.
+func TestIntegration_SABnzbdDeduplicatedJob(t *testing.T) {
+	const (
+		grabbed = "Test.Movie.2024.1080p.WEB-DL-GRP"
+		job     = grabbed + ".1"
+	)
+
+	for _, tc := range []struct {
+		name    string
+		storage func(remote string) string // Storage path SABnzbd reports
+	}{
+		{"storage path", func(remote string) string { return filepath.Join(remote, job) }},
+		{"no storage path", func(string) string { return "" }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(root, grabbed), 0o755), "the job SABnzbd deduplicated against")
+			require.NoError(t, os.MkdirAll(filepath.Join(root, job), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(root, job, "movie.mkv"), []byte("video"), 0o644))
+
+			const remote = "/sab/complete"
+			sab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var resp any
+				switch r.URL.Query().Get("mode") {
+				case "addurl":
+					resp = map[string]any{"status": true, "nzo_ids": []string{"SABnzbd_nzo_dup"}}
+				case "queue":
+					resp = map[string]any{"queue": map[string]any{"slots": []any{}}}
+				case "history":
+					resp = map[string]any{"history": map[string]any{"slots": []any{map[string]any{
+						"nzo_id":  "SABnzbd_nzo_dup",
+						"name":    job,
+						"status":  "Completed",
+						"storage": tc.storage(remote),
+					}}}}
+				}
+				_ = json.NewEncoder(w).Encode(resp)
+			}))
+			defer sab.Close()
+
+			db := setupIntegrationDBWithContent(t)
+			eventLog := events.NewEventLog(db)
+			bus := events.NewBus(eventLog, nil)
+			defer bus.Close()
+
+			dlStore := download.NewStore(db)
+			lib := library.NewStore(db)
+			content := &library.Content{Type: library.ContentTypeMovie, Title: "Test Movie", Year: 2024,
+				Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+			require.NoError(t, lib.AddContent(content))
+
+			client := download.NewSABnzbdClient(sab.URL, "key", "", slog.Default())
+			imp := &pathImporter{}
+			dlHandler := handlers.NewDownloadHandler(bus, dlStore, lib, routeAll(client), nil)
+			impHandler := handlers.NewImportHandler(bus, dlStore, lib, imp, nil)
+			impHandler.SetDownloadRoot(root)
+			adapter := sabnzbd.New(bus, client, dlStore, sabnzbd.Config{
+				Interval:   10 * time.Millisecond,
+				RemotePath: remote,
+				LocalPath:  root,
+			}, nil)
+
+			createdCh := bus.Subscribe(events.EventDownloadCreated, 10)
+			renamedCh := bus.Subscribe(events.EventDownloadRenamed, 10)
+			completedCh := bus.Subscribe(events.EventImportCompleted, 10)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() { _ = dlHandler.Start(ctx) }()
+			go func() { _ = impHandler.Start(ctx) }()
+			time.Sleep(50 * time.Millisecond)
+
+			require.NoError(t, bus.Publish(ctx, &events.GrabRequested{
+				BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+				ContentID:   content.ID,
+				DownloadURL: "https://example.com/test.nzb",
+				ReleaseName: grabbed,
+				Indexer:     "test",
+			}))
+			select {
+			case <-createdCh:
+			case <-time.After(2 * time.Second):
+				t.Fatal("timeout waiting for DownloadCreated")
+			}
+			go func() { _ = adapter.Start(ctx) }()
+
+			select {
+			case e := <-renamedCh:
+				renamed := e.(*events.DownloadRenamed)
+				assert.Equal(t, grabbed, renamed.OldName)
+				assert.Equal(t, job, renamed.NewName)
+			case <-time.After(2 * time.Second):
+				t.Fatal("timeout waiting for DownloadRenamed")
+			}
+			select {
+			case <-completedCh:
+			case <-time.After(2 * time.Second):
+				t.Fatal("timeout waiting for ImportCompleted")
+			}
+
+			imp.mu.Lock()
+			assert.Equal(t, filepath.Join(root, job), imp.path, "imported from the deduplicated job, not the grab title")
+			imp.mu.Unlock()
+
+			downloads, _, err := dlStore.List(download.Filter{ContentID: &content.ID})
+			require.NoError(t, err)
+			require.Len(t, downloads, 1)
+			assert.Equal(t, job, downloads[0].ReleaseName)
+			assert.Equal(t, "GRP", downloads[0].ReleaseGroup)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// internal/handlers/stuck.go
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+)
+
+// stuckCheckInterval is how often the stuck handler looks for stuck downloads.
+const stuckCheckInterval = time.Minute
+
+// StuckHandler emits DownloadStuck for downloads that stay in a status past
+// its alert threshold, at most once per download.
+type StuckHandler struct {
+	*BaseHandler
+	store      *download.Store
+	thresholds map[download.Status]time.Duration
+	interval   time.Duration
+}
+
+// NewStuckHandler creates a new stuck handler. A nil thresholds map uses
+// download.DefaultStuckAlertThresholds.
+func NewStuckHandler(bus *events.Bus, store *download.Store, thresholds map[download.Status]time.Duration, logger *slog.Logger) *StuckHandler {
+	if thresholds == nil {
+		thresholds = download.DefaultStuckAlertThresholds()
+	}
+	return &StuckHandler{
+		BaseHandler: NewBaseHandler(bus, logger),
+		store:       store,
+		thresholds:  thresholds,
+		interval:    stuckCheckInterval,
+	}
+}
+
+// Name returns the handler name.
+func (h *StuckHandler) Name() string {
+	return "stuck"
+}
+
+// Start checks for stuck downloads on startup and then periodically.
+func (h *StuckHandler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		h.check(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// check emits DownloadStuck for each download past its alert threshold that
+// hasn't been alerted yet.
+func (h *StuckHandler) check(ctx context.Context) {
+	stuck, err := h.store.ListStuck(h.thresholds)
+	if err != nil {
+		h.Logger().Error("failed to list stuck downloads", "error", err)
+		return
+	}
+	for _, dl := range stuck {
+		first, err := h.store.MarkStuckAlerted(dl.ID, dl.Status)
+		if err != nil {
+			h.Logger().Error("failed to record stuck alert", "download_id", dl.ID, "error", err)
+			continue
+		}
+		if !first {
+			continue
+		}
+
+		stuckFor := time.Since(dl.LastTransitionAt)
+		h.Logger().Warn("download stuck",
+			"download_id", dl.ID,
+			"status", dl.Status,
+			"release_name", dl.ReleaseName,
+			"stuck_for", stuckFor.Round(time.Minute))
+		if err := h.Bus().Publish(ctx, &events.DownloadStuck{
+			BaseEvent:        events.NewBaseEvent(events.EventDownloadStuck, events.EntityDownload, dl.ID),
+			DownloadID:       dl.ID,
+			ContentID:        dl.ContentID,
+			ReleaseName:      dl.ReleaseName,
+			Status:           string(dl.Status),
+			StuckMinutes:     int64(stuckFor / time.Minute),
+			ThresholdMinutes: int64(h.thresholds[dl.Status] / time.Minute),
+		}); err != nil {
+			h.Logger().Error("failed to publish DownloadStuck", "download_id", dl.ID, "error", err)
+		}
+	}
+}
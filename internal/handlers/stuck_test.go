@@ -0,0 +1,62 @@
+// internal/handlers/stuck_test.go
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/testutil"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
+)
+
+func TestStuckHandler_Name(t *testing.T) {
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+
+	handler := NewStuckHandler(bus, nil, nil, nil)
+	assert.Equal(t, "stuck", handler.Name())
+}
+
+func TestStuckHandler_AlertsOnce(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	bus := events.NewBus(nil, nil)
+	defer bus.Close()
+	store := download.NewStore(db)
+	contentID := fixtures.NewMovie("Stuck Movie", 2024).Insert(t, db).ID
+
+	stuck := &download.Download{ContentID: contentID, Client: download.ClientSABnzbd, ClientID: "nzo_stuck",
+		Status: download.StatusQueued, ReleaseName: "Stuck.Movie.2024.1080p"}
+	require.NoError(t, store.Add(stuck))
+	fresh := &download.Download{ContentID: contentID, Client: download.ClientSABnzbd, ClientID: "nzo_fresh",
+		Status: download.StatusQueued, ReleaseName: "Fresh.Movie.2024.1080p"}
+	require.NoError(t, store.Add(fresh))
+	_, err := db.Exec("UPDATE downloads SET last_transition_at = ? WHERE id = ?", time.Now().Add(-3*time.Hour), stuck.ID)
+	require.NoError(t, err)
+
+	alerts := bus.Subscribe(events.EventDownloadStuck, 10)
+	handler := NewStuckHandler(bus, store, map[download.Status]time.Duration{download.StatusQueued: 2 * time.Hour}, nil)
+	handler.check(context.Background())
+	handler.check(context.Background())
+
+	select {
+	case e := <-alerts:
+		alert := e.(*events.DownloadStuck)
+		assert.Equal(t, stuck.ID, alert.DownloadID)
+		assert.Equal(t, contentID, alert.ContentID)
+		assert.Equal(t, "queued", alert.Status)
+		assert.Equal(t, int64(120), alert.ThresholdMinutes)
+		assert.GreaterOrEqual(t, alert.StuckMinutes, int64(180))
+	case <-time.After(time.Second):
+		t.Fatal("expected DownloadStuck event")
+	}
+	select {
+	case e := <-alerts:
+		t.Fatalf("unexpected second alert: %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,464 @@
+package importer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+	_ "modernc.org/sqlite" // SQLite driver
+)
+
+// ErrNotArrDatabase is returned when a database lacks the tables of a Radarr
+// or Sonarr database.
+var ErrNotArrDatabase = errors.New("not a Radarr or Sonarr database")
+
+// ArrItem is a movie or series read from a Radarr or Sonarr database.
+type ArrItem struct {
+	Type        library.ContentType
+	Title       string
+	Year        int
+	TMDBID      *int64 // Movies
+	TVDBID      *int64 // Series
+	Path        string // Folder of the movie or series
+	Monitored   bool
+	Daily       bool // Sonarr daily series
+	ProfileID   int64
+	ProfileName string       // Name of the Radarr or Sonarr quality profile; empty if unknown
+	Seasons     map[int]bool // Series: whether each season is monitored; nil if not recorded
+	Files       []*ArrFile   // Movies: the movie file, if any
+	Episodes    []*ArrEpisode
+}
+
+// ArrEpisode is an episode of a series read from a Sonarr database.
+type ArrEpisode struct {
+	Season    int
+	Episode   int
+	Title     string
+	AirDate   *time.Time // UTC date; nil if unknown
+	Monitored bool
+	File      *ArrFile // nil if the episode has no file; shared by the episodes of a multi-episode file
+}
+
+// ArrFile is a media file recorded by Radarr or Sonarr.
+type ArrFile struct {
+	ID         int64 // Id in the Radarr or Sonarr database
+	Path       string
+	Size       int64
+	Resolution release.Resolution
+	Group      string // Release group; empty if unknown
+}
+
+// ReadRadarr reads the movies of a Radarr database with their files and
+// quality profiles. The database is opened read-only.
+//
+// Radarr v3 and later are read: movie titles, years and TMDB IDs are taken
+// from the MovieMetadata table when movies refer to one (v4+), else from the
+// Movies table itself, and optional columns missing from other versions are
+// left empty.
+func ReadRadarr(path string) ([]*ArrItem, error) {
+	db, err := openArrDB(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	movies, err := arrColumns(db, "Movies")
+	if err != nil {
+		return nil, err
+	}
+	if len(movies) == 0 {
+		return nil, fmt.Errorf("%s: %w: no Movies table", path, ErrNotArrDatabase)
+	}
+	profiles, err := arrProfiles(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Metadata moved to its own table in Radarr v4
+	meta, from := "m", "Movies m"
+	if movies["moviemetadataid"] {
+		metadata, err := arrColumns(db, "MovieMetadata")
+		if err != nil {
+			return nil, err
+		}
+		if len(metadata) > 0 {
+			meta, from = "mm", "Movies m JOIN MovieMetadata mm ON mm.Id = m.MovieMetadataId"
+			movies = metadata
+		}
+	}
+	query := fmt.Sprintf(`SELECT m.Id, %[1]s.Title, %[2]s, %[3]s, m.Path, m.Monitored, %[4]s FROM %[5]s ORDER BY m.Id`,
+		meta,
+		arrColumn(movies, meta, "0", "Year"),
+		arrColumn(movies, meta, "NULL", "TmdbId"),
+		arrProfileColumn(db, "Movies", "m"),
+		from)
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("read movies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []*ArrItem
+	byID := make(map[int64]*ArrItem)
+	for rows.Next() {
+		var (
+			id        int64
+			item      = &ArrItem{Type: library.ContentTypeMovie}
+			tmdbID    sql.NullInt64
+			profileID sql.NullInt64
+		)
+		if err := rows.Scan(&id, &item.Title, &item.Year, &tmdbID, &item.Path, &item.Monitored, &profileID); err != nil {
+			return nil, fmt.Errorf("scan movie: %w", err)
+		}
+		if tmdbID.Valid && tmdbID.Int64 > 0 {
+			item.TMDBID = &tmdbID.Int64
+		}
+		item.ProfileID = profileID.Int64
+		item.ProfileName = profiles[profileID.Int64]
+		items = append(items, item)
+		byID[id] = item
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read movies: %w", err)
+	}
+
+	files, err := arrFiles(db, "MovieFiles", "MovieId")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if item := byID[f.ownerID]; item != nil {
+			f.resolve(item.Path)
+			item.Files = append(item.Files, f.ArrFile)
+		}
+	}
+	return items, nil
+}
+
+// ReadSonarr reads the series of a Sonarr database with their episodes,
+// episode files and quality profiles. The database is opened read-only.
+//
+// Sonarr v3 and later are read; optional columns missing from other versions
+// are left empty. Every episode of a multi-episode file shares its ArrFile.
+func ReadSonarr(path string) ([]*ArrItem, error) {
+	db, err := openArrDB(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	series, err := arrColumns(db, "Series")
+	if err != nil {
+		return nil, err
+	}
+	episodes, err := arrColumns(db, "Episodes")
+	if err != nil {
+		return nil, err
+	}
+	if len(series) == 0 || len(episodes) == 0 {
+		return nil, fmt.Errorf("%s: %w: no Series or Episodes table", path, ErrNotArrDatabase)
+	}
+	profiles, err := arrProfiles(db)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`SELECT s.Id, s.Title, %s, %s, s.Path, s.Monitored, %s, %s, %s FROM Series s ORDER BY s.Id`,
+		arrColumn(series, "s", "0", "Year"),
+		arrColumn(series, "s", "NULL", "TvdbId"),
+		arrProfileColumn(db, "Series", "s"),
+		arrColumn(series, "s", "''", "SeriesType"),
+		arrColumn(series, "s", "''", "Seasons"))
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("read series: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []*ArrItem
+	byID := make(map[int64]*ArrItem)
+	for rows.Next() {
+		var (
+			id         int64
+			item       = &ArrItem{Type: library.ContentTypeSeries}
+			tvdbID     sql.NullInt64
+			profileID  sql.NullInt64
+			seriesType sql.NullString
+			seasons    sql.NullString
+		)
+		if err := rows.Scan(&id, &item.Title, &item.Year, &tvdbID, &item.Path, &item.Monitored, &profileID, &seriesType, &seasons); err != nil {
+			return nil, fmt.Errorf("scan series: %w", err)
+		}
+		if tvdbID.Valid && tvdbID.Int64 > 0 {
+			item.TVDBID = &tvdbID.Int64
+		}
+		item.ProfileID = profileID.Int64
+		item.ProfileName = profiles[profileID.Int64]
+		// SeriesType is 1 for daily series; some versions stored the name
+		item.Daily = seriesType.String == "1" || strings.EqualFold(seriesType.String, "daily")
+		item.Seasons = arrSeasons(seasons.String)
+		items = append(items, item)
+		byID[id] = item
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read series: %w", err)
+	}
+
+	files, err := arrFiles(db, "EpisodeFiles", "SeriesId")
+	if err != nil {
+		return nil, err
+	}
+	fileByID := make(map[int64]*ArrFile, len(files))
+	for _, f := range files {
+		if item := byID[f.ownerID]; item != nil {
+			f.resolve(item.Path)
+			fileByID[f.ID] = f.ArrFile
+		}
+	}
+
+	query = fmt.Sprintf(`SELECT SeriesId, SeasonNumber, EpisodeNumber, %s, %s, Monitored, %s FROM Episodes ORDER BY SeriesId, SeasonNumber, EpisodeNumber`,
+		arrColumn(episodes, "", "''", "Title"),
+		arrColumn(episodes, "", "NULL", "AirDate", "AirDateUtc"),
+		arrColumn(episodes, "", "0", "EpisodeFileId"))
+	epRows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("read episodes: %w", err)
+	}
+	defer func() { _ = epRows.Close() }()
+	for epRows.Next() {
+		var (
+			seriesID int64
+			ep       = &ArrEpisode{}
+			title    sql.NullString
+			airDate  sql.NullString
+			fileID   sql.NullInt64
+		)
+		if err := epRows.Scan(&seriesID, &ep.Season, &ep.Episode, &title, &airDate, &ep.Monitored, &fileID); err != nil {
+			return nil, fmt.Errorf("scan episode: %w", err)
+		}
+		item := byID[seriesID]
+		if item == nil {
+			continue
+		}
+		ep.Title = title.String
+		ep.AirDate = arrDate(airDate.String)
+		ep.File = fileByID[fileID.Int64]
+		item.Episodes = append(item.Episodes, ep)
+	}
+	if err := epRows.Err(); err != nil {
+		return nil, fmt.Errorf("read episodes: %w", err)
+	}
+	return items, nil
+}
+
+// openArrDB opens a Radarr or Sonarr database read-only, so importing from it
+// can never change it, even while Radarr or Sonarr still runs.
+func openArrDB(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	q := url.Values{}
+	q.Set("mode", "ro")
+	q.Add("_pragma", "query_only(1)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return db, nil
+}
+
+// arrColumns returns the columns of a table, lowercased; empty if the table
+// does not exist.
+func arrColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("read %s columns: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("read %s columns: %w", table, err)
+		}
+		cols[strings.ToLower(name)] = true
+	}
+	return cols, rows.Err()
+}
+
+// arrColumn returns the first of names that cols has, qualified by alias, or
+// fallback if it has none. Radarr and Sonarr renamed columns across versions.
+func arrColumn(cols map[string]bool, alias, fallback string, names ...string) string {
+	for _, name := range names {
+		if cols[strings.ToLower(name)] {
+			if alias == "" {
+				return name
+			}
+			return alias + "." + name
+		}
+	}
+	return fallback
+}
+
+// arrProfileColumn returns the quality profile column of a table: ProfileId
+// before Radarr v4 and Sonarr v3, QualityProfileId since.
+func arrProfileColumn(db *sql.DB, table, alias string) string {
+	cols, err := arrColumns(db, table)
+	if err != nil {
+		return "NULL"
+	}
+	return arrColumn(cols, alias, "NULL", "QualityProfileId", "ProfileId")
+}
+
+// arrProfiles returns the names of the quality profiles by ID, from the
+// QualityProfiles table or, in older versions, Profiles.
+func arrProfiles(db *sql.DB) (map[int64]string, error) {
+	names := make(map[int64]string)
+	for _, table := range []string{"QualityProfiles", "Profiles"} {
+		cols, err := arrColumns(db, table)
+		if err != nil {
+			return nil, err
+		}
+		if !cols["id"] || !cols["name"] {
+			continue
+		}
+		rows, err := db.Query("SELECT Id, Name FROM " + table)
+		if err != nil {
+			return nil, fmt.Errorf("read quality profiles: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var id int64
+			var name string
+			if err := rows.Scan(&id, &name); err != nil {
+				return nil, fmt.Errorf("read quality profiles: %w", err)
+			}
+			names[id] = name
+		}
+		return names, rows.Err()
+	}
+	return names, nil
+}
+
+// arrFileRow is a file row with the ID of the movie or series it belongs to.
+type arrFileRow struct {
+	*ArrFile
+	ownerID   int64
+	sceneName string
+}
+
+// resolve makes the file's path absolute under its movie or series folder and
+// reads its resolution, and release group if not recorded, from the scene
+// name or file name.
+func (f *arrFileRow) resolve(folder string) {
+	if !filepath.IsAbs(f.Path) {
+		f.Path = filepath.Join(folder, f.Path)
+	}
+	name := f.sceneName
+	if name == "" {
+		name = filepath.Base(f.Path)
+	}
+	parsed := release.Parse(name)
+	f.Resolution = parsed.Resolution
+	if f.Group == "" {
+		f.Group = parsed.Group
+	}
+}
+
+// arrFiles reads the files of a MovieFiles or EpisodeFiles table.
+func arrFiles(db *sql.DB, table, ownerColumn string) ([]*arrFileRow, error) {
+	cols, err := arrColumns(db, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) == 0 {
+		return nil, nil
+	}
+	query := fmt.Sprintf("SELECT Id, %s, %s, %s, %s, %s FROM %s ORDER BY Id",
+		ownerColumn,
+		arrColumn(cols, "", "''", "RelativePath", "Path"),
+		arrColumn(cols, "", "0", "Size"),
+		arrColumn(cols, "", "''", "SceneName"),
+		arrColumn(cols, "", "''", "ReleaseGroup"),
+		table)
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var files []*arrFileRow
+	for rows.Next() {
+		f := &arrFileRow{ArrFile: &ArrFile{}}
+		var sceneName, group sql.NullString
+		if err := rows.Scan(&f.ID, &f.ownerID, &f.Path, &f.Size, &sceneName, &group); err != nil {
+			return nil, fmt.Errorf("read %s: %w", table, err)
+		}
+		f.sceneName, f.Group = sceneName.String, group.String
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
+// arrSeasons decodes the Seasons JSON of a Sonarr series into whether each
+// season is monitored. Returns nil if it is empty or unreadable.
+func arrSeasons(raw string) map[int]bool {
+	var seasons []struct {
+		SeasonNumber int  `json:"seasonNumber"`
+		Monitored    bool `json:"monitored"`
+	}
+	if raw == "" || json.Unmarshal([]byte(raw), &seasons) != nil || len(seasons) == 0 {
+		return nil
+	}
+	monitored := make(map[int]bool, len(seasons))
+	for _, s := range seasons {
+		monitored[s.SeasonNumber] = s.Monitored
+	}
+	return monitored
+}
+
+// arrDate parses the date of an AirDate ("2008-01-20") or AirDateUtc
+// ("2008-01-20 03:00:00Z") column. Returns nil if it is empty or unreadable.
+func arrDate(s string) *time.Time {
+	if len(s) < len("2006-01-02") {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", s[:len("2006-01-02")])
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// FirstFile returns the movie file or the first episode file of an item; nil
+// if it has none.
+func (item *ArrItem) FirstFile() *ArrFile {
+	if len(item.Files) > 0 {
+		return item.Files[0]
+	}
+	for _, ep := range item.Episodes {
+		if ep.File != nil {
+			return ep.File
+		}
+	}
+	return nil
+}
+
+// HasFiles reports whether the item has a movie file or any episode file.
+func (item *ArrItem) HasFiles() bool {
+	return item.FirstFile() != nil
+}
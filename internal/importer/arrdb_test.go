@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+)
+
+// arrFixture returns the path of a testdata database.
+func arrFixture(name string) string {
+	return filepath.Join("testdata", name+".db")
+}
+
+func TestReadRadarr(t *testing.T) {
+	items, err := ReadRadarr(arrFixture("radarr_v4"))
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	matrix := items[0]
+	assert.Equal(t, library.ContentTypeMovie, matrix.Type)
+	assert.Equal(t, "The Matrix", matrix.Title)
+	assert.Equal(t, 1999, matrix.Year)
+	require.NotNil(t, matrix.TMDBID)
+	assert.Equal(t, int64(603), *matrix.TMDBID)
+	assert.True(t, matrix.Monitored)
+	assert.Equal(t, int64(4), matrix.ProfileID)
+	assert.Equal(t, "HD-1080p", matrix.ProfileName)
+	require.Len(t, matrix.Files, 1)
+	assert.Equal(t, "/movies/The Matrix (1999)/The.Matrix.1999.1080p.BluRay.x264-GRP.mkv", matrix.Files[0].Path)
+	assert.Equal(t, int64(8000000000), matrix.Files[0].Size)
+	assert.Equal(t, release.Resolution1080p, matrix.Files[0].Resolution)
+	assert.Equal(t, "GRP", matrix.Files[0].Group)
+
+	inception := items[1]
+	assert.Equal(t, "Ultra-HD", inception.ProfileName)
+	assert.Empty(t, inception.Files)
+
+	// Resolution and group come from the scene name over the renamed file
+	interstellar := items[2]
+	assert.False(t, interstellar.Monitored)
+	require.Len(t, interstellar.Files, 1)
+	assert.Equal(t, release.Resolution2160p, interstellar.Files[0].Resolution)
+	assert.Equal(t, "TERMiNAL", interstellar.Files[0].Group)
+}
+
+func TestReadRadarr_V3(t *testing.T) {
+	items, err := ReadRadarr(arrFixture("radarr_v3"))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Heat", items[0].Title)
+	assert.Equal(t, 1995, items[0].Year)
+	require.NotNil(t, items[0].TMDBID)
+	assert.Equal(t, int64(949), *items[0].TMDBID)
+	assert.Equal(t, "HD-720p", items[0].ProfileName)
+	require.Len(t, items[0].Files, 1)
+	assert.Equal(t, release.Resolution720p, items[0].Files[0].Resolution)
+	assert.Equal(t, "SiNNERS", items[0].Files[0].Group)
+}
+
+func TestReadSonarr(t *testing.T) {
+	items, err := ReadSonarr(arrFixture("sonarr_v4"))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	bb := items[0]
+	assert.Equal(t, library.ContentTypeSeries, bb.Type)
+	assert.Equal(t, "Breaking Bad", bb.Title)
+	require.NotNil(t, bb.TVDBID)
+	assert.Equal(t, int64(81189), *bb.TVDBID)
+	assert.Equal(t, "HD-1080p", bb.ProfileName)
+	assert.False(t, bb.Daily)
+	assert.Equal(t, map[int]bool{1: true, 2: false}, bb.Seasons)
+	require.Len(t, bb.Episodes, 5)
+
+	pilot := bb.Episodes[0]
+	assert.Equal(t, 1, pilot.Season)
+	assert.Equal(t, 1, pilot.Episode)
+	assert.Equal(t, "Pilot", pilot.Title)
+	require.NotNil(t, pilot.AirDate)
+	assert.Equal(t, "2008-01-20", pilot.AirDate.Format("2006-01-02"))
+	require.NotNil(t, pilot.File)
+	assert.Equal(t, "/tv/Breaking Bad/Season 01/Breaking.Bad.S01E01.1080p.BluRay.x264-DEMAND.mkv", pilot.File.Path)
+	assert.Equal(t, release.Resolution1080p, pilot.File.Resolution)
+
+	// A multi-episode file is shared by its episodes
+	require.NotNil(t, bb.Episodes[1].File)
+	assert.Same(t, bb.Episodes[1].File, bb.Episodes[2].File)
+	assert.Equal(t, release.Resolution720p, bb.Episodes[1].File.Resolution)
+	assert.Equal(t, "CTU", bb.Episodes[1].File.Group)
+
+	assert.Nil(t, bb.Episodes[3].File, "EpisodeFileId 0 means no file")
+	assert.False(t, bb.Episodes[4].Monitored)
+
+	daily := items[1]
+	assert.True(t, daily.Daily)
+	assert.Equal(t, "SD", daily.ProfileName)
+	assert.Nil(t, daily.Seasons)
+	require.Len(t, daily.Episodes, 1)
+	assert.Equal(t, 2024, daily.Episodes[0].Season)
+}
+
+func TestReadArr_WrongDatabase(t *testing.T) {
+	radarr := arrFixture("radarr_v4")
+	_, err := ReadSonarr(radarr)
+	require.ErrorIs(t, err, ErrNotArrDatabase)
+
+	_, err = ReadRadarr(arrFixture("sonarr_v4"))
+	require.ErrorIs(t, err, ErrNotArrDatabase)
+
+	_, err = ReadRadarr(filepath.Join(t.TempDir(), "missing.db"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReadRadarr_ReadOnly(t *testing.T) {
+	path := arrFixture("radarr_v4")
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	_, err = ReadRadarr(path)
+	require.NoError(t, err)
+
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
@@ -2,17 +2,76 @@
 package importer
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 )
 
 // CopyFile copies a file from src to dst.
 // Creates destination directory if it doesn't exist.
 // Returns ErrDestinationExists if dst already exists.
 func CopyFile(src, dst string) (int64, error) {
+	return CopyFileContext(context.Background(), src, dst)
+}
+
+// CopyFileContext is CopyFile that stops when ctx is done. A copy that fails
+// or is canceled removes the partial destination file. The destination size
+// is checked against the source after the copy is synced.
+func CopyFileContext(ctx context.Context, src, dst string) (int64, error) {
+	return copyFile(ctx, src, dst, nil)
+}
+
+// CopyFileVerified is CopyFileContext that also checksums the copy. The
+// source is hashed as it is copied, then the synced destination is read back
+// and hashed; a mismatch removes the destination and returns ErrVerifyFailed.
+// Returns the size and the hex SHA-256 of the file.
+func CopyFileVerified(ctx context.Context, src, dst string) (int64, string, error) {
+	h := sha256.New()
+	size, err := copyFile(ctx, src, dst, h)
+	if err != nil {
+		return 0, "", err
+	}
+	want := hex.EncodeToString(h.Sum(nil))
+
+	got, err := FileChecksum(ctx, dst)
+	if err != nil {
+		_ = os.Remove(dst)
+		return 0, "", fmt.Errorf("%w: hash destination: %w", ErrVerifyFailed, err)
+	}
+	if got != want {
+		_ = os.Remove(dst)
+		return 0, "", fmt.Errorf("%w: checksum mismatch: source %s, destination %s", ErrVerifyFailed, want, got)
+	}
+	return size, want, nil
+}
+
+// FileChecksum returns the hex SHA-256 of the file at path, the same digest
+// CopyFileVerified records. Stops when ctx is done.
+func FileChecksum(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, &contextReader{ctx: ctx, r: f}); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyFile copies src to dst, writing every chunk to h as well when h is
+// non-nil so the source is hashed without a second read.
+func copyFile(ctx context.Context, src, dst string, h hash.Hash) (int64, error) {
 	// Check if destination exists
 	if _, err := os.Stat(dst); err == nil {
 		return 0, ErrDestinationExists
@@ -29,6 +88,10 @@ func CopyFile(src, dst string) (int64, error) {
 		return 0, fmt.Errorf("%w: open source: %w", ErrCopyFailed, err)
 	}
 	defer func() { _ = srcFile.Close() }()
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("%w: stat source: %w", ErrCopyFailed, err)
+	}
 
 	// Create destination
 	dstFile, err := os.Create(dst)
@@ -37,22 +100,155 @@ func CopyFile(src, dst string) (int64, error) {
 	}
 	defer func() { _ = dstFile.Close() }()
 
+	var w io.Writer = dstFile
+	if h != nil {
+		hw := newHashingWriter(dstFile, h)
+		defer hw.close()
+		w = hw
+	}
+
 	// Copy content
-	size, err := io.Copy(dstFile, srcFile)
+	var r io.Reader = &contextReader{ctx: ctx, r: srcFile}
+	if progress, ok := ctx.Value(copyProgressKey{}).(func(int64)); ok {
+		r = &progressReader{r: r, progress: progress}
+	}
+	size, err := io.CopyBuffer(w, r, make([]byte, copyBufferSize))
 	if err != nil {
 		// Clean up partial file on error
+		_ = dstFile.Close()
 		_ = os.Remove(dst)
 		return 0, fmt.Errorf("%w: copy content: %w", ErrCopyFailed, err)
 	}
 
 	// Sync to disk
 	if err := dstFile.Sync(); err != nil {
+		_ = dstFile.Close()
+		_ = os.Remove(dst)
 		return 0, fmt.Errorf("%w: sync: %w", ErrCopyFailed, err)
 	}
 
+	// A short copy or a destination truncated on a full disk shows up as a size difference
+	dstInfo, err := dstFile.Stat()
+	if err != nil {
+		_ = dstFile.Close()
+		_ = os.Remove(dst)
+		return 0, fmt.Errorf("%w: stat destination: %w", ErrVerifyFailed, err)
+	}
+	if size != srcInfo.Size() || dstInfo.Size() != srcInfo.Size() {
+		_ = dstFile.Close()
+		_ = os.Remove(dst)
+		return 0, fmt.Errorf("%w: size mismatch: source %d bytes, copied %d, destination %d",
+			ErrVerifyFailed, srcInfo.Size(), size, dstInfo.Size())
+	}
+
 	return size, nil
 }
 
+// copyBufferSize is the chunk size for copies. Larger chunks let hashing
+// overlap more of each write.
+const copyBufferSize = 1 << 20
+
+// hashingWriter writes each chunk to w while a goroutine feeds the same chunk
+// to h, so hashing overlaps the write instead of following it.
+type hashingWriter struct {
+	w     io.Writer
+	h     hash.Hash
+	chunk chan []byte
+	done  chan struct{}
+}
+
+func newHashingWriter(w io.Writer, h hash.Hash) *hashingWriter {
+	hw := &hashingWriter{w: w, h: h, chunk: make(chan []byte), done: make(chan struct{})}
+	go func() {
+		for p := range hw.chunk {
+			_, _ = hw.h.Write(p) // hash.Hash writes never fail
+			hw.done <- struct{}{}
+		}
+	}()
+	return hw
+}
+
+// Write returns once both the write and the hash of p are done, so the
+// caller may reuse p.
+func (hw *hashingWriter) Write(p []byte) (int, error) {
+	hw.chunk <- p
+	n, err := hw.w.Write(p)
+	<-hw.done
+	return n, err
+}
+
+// close stops the hashing goroutine.
+func (hw *hashingWriter) close() {
+	close(hw.chunk)
+}
+
+// contextReader fails reads once ctx is done, so a long copy can be
+// interrupted between chunks.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// copyProgressKey is the context key of the func copies under the context
+// report the bytes copied so far to (see withCopyProgress).
+type copyProgressKey struct{}
+
+// withCopyProgress returns a context under which file copies call progress
+// with the bytes copied so far after each read.
+func withCopyProgress(ctx context.Context, progress func(copied int64)) context.Context {
+	return context.WithValue(ctx, copyProgressKey{}, progress)
+}
+
+// progressReader reports the bytes read so far after each read.
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	progress func(int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.read += int64(n)
+		p.progress(p.read)
+	}
+	return n, err
+}
+
+// MoveFile moves a file from src to dst.
+// Creates destination directory if it doesn't exist. Falls back to copy and
+// delete when src and dst are on different filesystems.
+func MoveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("rename: %w", err)
+	}
+
+	// Cross-device move
+	if _, err := CopyFile(src, dst); err != nil {
+		return err
+	}
+	if err := os.Remove(src); err != nil {
+		_ = os.Remove(dst)
+		return fmt.Errorf("remove source: %w", err)
+	}
+	return nil
+}
+
 // FindLargestVideo finds the largest video file in a directory tree.
 // Returns ErrNoVideoFile if no video files are found.
 // Skips files with "sample" in the name.
@@ -2,6 +2,9 @@
 package importer
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path/filepath"
 	"testing"
@@ -69,6 +72,113 @@ func TestCopyFile_SourceNotFound(t *testing.T) {
 	assert.Error(t, err, "expected error for missing source")
 }
 
+func TestCopyFileContext_CanceledRemovesPartial(t *testing.T) {
+	srcPath := filepath.Join(t.TempDir(), "test.mkv")
+	require.NoError(t, os.WriteFile(srcPath, []byte("content"), 0644), "create source")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dstPath := filepath.Join(t.TempDir(), "copied.mkv")
+	_, err := CopyFileContext(ctx, srcPath, dstPath)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, ErrCopyFailed)
+
+	_, statErr := os.Stat(dstPath)
+	assert.True(t, os.IsNotExist(statErr), "partial destination should be removed")
+}
+
+func TestCopyFileVerified(t *testing.T) {
+	srcPath := filepath.Join(t.TempDir(), "test.mkv")
+	content := []byte("test video content")
+	require.NoError(t, os.WriteFile(srcPath, content, 0644), "create source")
+	sum := sha256.Sum256(content)
+
+	dstPath := filepath.Join(t.TempDir(), "copied.mkv")
+	size, checksum, err := CopyFileVerified(context.Background(), srcPath, dstPath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), size)
+	assert.Equal(t, hex.EncodeToString(sum[:]), checksum)
+
+	got, err := FileChecksum(context.Background(), dstPath)
+	require.NoError(t, err)
+	assert.Equal(t, checksum, got, "FileChecksum should match the recorded checksum")
+}
+
+func TestFileChecksum_DetectsChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.mkv")
+	require.NoError(t, os.WriteFile(path, []byte("original"), 0644))
+	before, err := FileChecksum(context.Background(), path)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("origina1"), 0644))
+	after, err := FileChecksum(context.Background(), path)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+}
+
+func TestFileChecksum_NotFound(t *testing.T) {
+	_, err := FileChecksum(context.Background(), filepath.Join(t.TempDir(), "missing.mkv"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// benchmarkCopy copies a 64 MiB file per iteration with the given copy function.
+func benchmarkCopy(b *testing.B, copyFn func(ctx context.Context, src, dst string) error) {
+	const size = 64 << 20
+	srcPath := filepath.Join(b.TempDir(), "src.mkv")
+	require.NoError(b, os.WriteFile(srcPath, make([]byte, size), 0644))
+	dstDir := b.TempDir()
+
+	b.SetBytes(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dstPath := filepath.Join(dstDir, "dst.mkv")
+		if err := copyFn(context.Background(), srcPath, dstPath); err != nil {
+			b.Fatal(err)
+		}
+		b.StopTimer()
+		_ = os.Remove(dstPath)
+		b.StartTimer()
+	}
+}
+
+func BenchmarkCopyFileContext(b *testing.B) {
+	benchmarkCopy(b, func(ctx context.Context, src, dst string) error {
+		_, err := CopyFileContext(ctx, src, dst)
+		return err
+	})
+}
+
+func BenchmarkCopyFileVerified(b *testing.B) {
+	benchmarkCopy(b, func(ctx context.Context, src, dst string) error {
+		_, _, err := CopyFileVerified(ctx, src, dst)
+		return err
+	})
+}
+
+func TestMoveFile(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	srcPath := filepath.Join(srcDir, "test.mkv")
+	require.NoError(t, os.WriteFile(srcPath, []byte("content"), 0644), "create source")
+
+	dstPath := filepath.Join(dstDir, "nested", "moved.mkv")
+	require.NoError(t, MoveFile(srcPath, dstPath), "MoveFile")
+
+	_, err := os.Stat(srcPath)
+	assert.True(t, os.IsNotExist(err), "source should be gone")
+	got, err := os.ReadFile(dstPath)
+	require.NoError(t, err, "read dest")
+	assert.Equal(t, "content", string(got))
+}
+
+func TestMoveFile_SourceNotFound(t *testing.T) {
+	dstDir := t.TempDir()
+	err := MoveFile("/nonexistent/file.mkv", filepath.Join(dstDir, "out.mkv"))
+	assert.Error(t, err, "expected error for missing source")
+}
+
 func TestFindLargestVideo(t *testing.T) {
 	dir := t.TempDir()
 
@@ -3,6 +3,7 @@ package importer
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/vmunix/arrgo/internal/library"
 	"github.com/vmunix/arrgo/pkg/release"
@@ -15,8 +16,19 @@ type FileMatch struct {
 }
 
 // MatchFileToEpisode finds the episode that matches a filename.
+// Dated files without SxxExx ("Show.2024.03.14.mkv") match the episode that aired that day.
 // Returns error if no match is found.
 func MatchFileToEpisode(filename string, episodes []*library.Episode) (*library.Episode, error) {
+	if airDate, ok := MatchFileToAirDate(filename, false); ok {
+		day := airDate.Format(time.DateOnly)
+		for _, ep := range episodes {
+			if ep.AirDate != nil && ep.AirDate.UTC().Format(time.DateOnly) == day {
+				return ep, nil
+			}
+		}
+		return nil, fmt.Errorf("no matching episode for air date %s in %s", day, filename)
+	}
+
 	info := release.Parse(filepath.Base(filename))
 
 	if info.Season == 0 || len(info.Episodes) == 0 {
@@ -68,3 +80,14 @@ func MatchFileToSeason(filename string) (int, int, error) {
 
 	return info.Season, info.Episodes[0], nil
 }
+
+// MatchFileToAirDate returns the air date of a dated episode file of a daily series.
+// For other series a date is only used when the file has no season to go by.
+func MatchFileToAirDate(filename string, daily bool) (time.Time, bool) {
+	info := release.Parse(filepath.Base(filename))
+	airDate, ok := info.AirDate()
+	if !ok || (!daily && info.Season != 0) {
+		return time.Time{}, false
+	}
+	return airDate, true
+}
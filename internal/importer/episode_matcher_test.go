@@ -2,6 +2,7 @@ package importer
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -9,10 +10,12 @@ import (
 )
 
 func TestMatchFileToEpisode(t *testing.T) {
+	aired := time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)
 	episodes := []*library.Episode{
 		{ID: 1, Season: 1, Episode: 1},
 		{ID: 2, Season: 1, Episode: 2},
 		{ID: 3, Season: 1, Episode: 3},
+		{ID: 4, Season: 2024, Episode: 45, AirDate: &aired},
 	}
 
 	tests := []struct {
@@ -46,6 +49,16 @@ func TestMatchFileToEpisode(t *testing.T) {
 			filename: "random_file.mkv",
 			wantErr:  true,
 		},
+		{
+			name:     "air date",
+			filename: "The.Daily.Show.2024.03.14.Guest.Name.1080p.WEB.mkv",
+			wantID:   4,
+		},
+		{
+			name:     "unknown air date",
+			filename: "The.Daily.Show.2024.03.15.1080p.WEB.mkv",
+			wantErr:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -132,3 +145,12 @@ func TestMatchFileToSeason(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchFileToAirDate(t *testing.T) {
+	date, ok := MatchFileToAirDate("/downloads/The.Daily.Show.2024.03.14.1080p.WEB.mkv", false)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC), date)
+
+	_, ok = MatchFileToAirDate("Show.S01E02.1080p.mkv", true)
+	assert.False(t, ok, "no date")
+}
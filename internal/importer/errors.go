@@ -13,15 +13,49 @@ var (
 	// ErrNoVideoFile indicates no video file was found in the download.
 	ErrNoVideoFile = errors.New("no video file found in download")
 
+	// ErrNoAudioFile indicates no audio file was found in an audiobook download.
+	ErrNoAudioFile = errors.New("no audio file found in download")
+
 	// ErrCopyFailed indicates the file copy operation failed.
 	ErrCopyFailed = errors.New("failed to copy file")
 
+	// ErrVerifyFailed indicates a copied file does not match its source.
+	ErrVerifyFailed = errors.New("copy verification failed")
+
 	// ErrDestinationExists indicates the destination file already exists.
 	ErrDestinationExists = errors.New("destination file already exists")
 
+	// ErrImportWouldDowngrade indicates the library already has a
+	// higher-quality file for the content or episode being imported.
+	ErrImportWouldDowngrade = errors.New("import would downgrade existing file")
+
+	// ErrInvalidTemplate indicates a naming template uses unknown placeholders.
+	ErrInvalidTemplate = errors.New("invalid naming template")
+
 	// ErrPathTraversal indicates a path traversal attack was detected.
 	ErrPathTraversal = errors.New("path traversal detected")
 
 	// ErrEpisodeNotSpecified indicates a series download is missing the episode ID.
 	ErrEpisodeNotSpecified = errors.New("episode not specified for series download")
+
+	// ErrUnsupportedExtension indicates a watched file is not a video file.
+	ErrUnsupportedExtension = errors.New("unsupported file extension")
+
+	// ErrNoTitleMatch indicates a watched file matched no library content.
+	ErrNoTitleMatch = errors.New("no matching title in library")
+
+	// ErrAmbiguousMatch indicates a watched file matched more than one library item.
+	ErrAmbiguousMatch = errors.New("ambiguous title match")
+
+	// ErrNeedsReview indicates a download holds several full-size videos that
+	// are not parts of one movie, so the file to import cannot be chosen safely.
+	ErrNeedsReview = errors.New("needs review, several candidate video files")
+
+	// ErrNoConfidentMatch indicates a completed download's files were not at the
+	// expected path and no directory under the download root clearly matched it.
+	ErrNoConfidentMatch = errors.New("obfuscated release, no confident match")
+
+	// ErrPlexItemNotFound indicates Plex has no item with a rating key, such
+	// as one deleted or matched again since the key was stored.
+	ErrPlexItemNotFound = errors.New("plex item not found")
 )
@@ -21,6 +21,9 @@ func TestErrors(t *testing.T) {
 		ErrCopyFailed,
 		ErrDestinationExists,
 		ErrPathTraversal,
+		ErrUnsupportedExtension,
+		ErrNoTitleMatch,
+		ErrAmbiguousMatch,
 	}
 	for _, err := range errs {
 		assert.NotEmpty(t, err.Error(), "error %v should have a message", err)
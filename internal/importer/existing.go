@@ -0,0 +1,33 @@
+// internal/importer/existing.go
+package importer
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+)
+
+// SourcePlexExisting is the source of a file record adopted from Plex when
+// a movie Plex already has is added to the library.
+const SourcePlexExisting = "plex-existing"
+
+// ExistingMovieFile returns the file record for a movie file that is already
+// in the media library rather than imported by arrgo. The size is read from
+// disk when the file is reachable and the quality and release group are
+// parsed from its name.
+func ExistingMovieFile(contentID int64, path string) *library.File {
+	parsed := release.Parse(filepath.Base(path))
+	f := &library.File{
+		ContentID:    contentID,
+		Path:         path,
+		Quality:      parsed.Resolution.String(),
+		Source:       SourcePlexExisting,
+		ReleaseGroup: parsed.Group,
+	}
+	if info, err := os.Stat(path); err == nil {
+		f.SizeBytes = info.Size()
+	}
+	return f
+}
@@ -0,0 +1,29 @@
+// internal/importer/existing_test.go
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExistingMovieFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Test Movie (2024) 2160p.mkv")
+	require.NoError(t, os.WriteFile(path, make([]byte, 1234), 0644))
+
+	f := ExistingMovieFile(7, path)
+	assert.Equal(t, int64(7), f.ContentID)
+	assert.Equal(t, path, f.Path)
+	assert.Equal(t, int64(1234), f.SizeBytes)
+	assert.Equal(t, "2160p", f.Quality)
+	assert.Equal(t, SourcePlexExisting, f.Source)
+}
+
+func TestExistingMovieFile_Unreachable(t *testing.T) {
+	f := ExistingMovieFile(7, "/nonexistent/Test Movie (2024) 1080p.mkv")
+	assert.Equal(t, int64(0), f.SizeBytes, "size is unknown when the file can't be read")
+	assert.Equal(t, "1080p", f.Quality)
+}
@@ -3,8 +3,12 @@ package importer
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -40,3 +44,147 @@ func FindAllVideos(root string) ([]string, error) {
 
 	return videos, nil
 }
+
+// DefaultMinPartRatio is the size, relative to the largest video in a
+// download, below which a video is not considered part of the movie.
+const DefaultMinPartRatio = 0.3
+
+// VideoPart is a video file selected for a movie import.
+type VideoPart struct {
+	Path string
+	Size int64
+	Part int // Part number of a multi-part movie (CD1 = 1); 0 for a single file
+}
+
+// partPattern matches multi-part markers such as CD1, cd.2, DVD1, Part 2,
+// pt3, or Disc1 in a file name.
+var partPattern = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:cd|dvd|disc|disk|part|pt)[ ._-]?(\d{1,2})(?:[^a-z0-9]|$)`)
+
+// PartNumber returns the part number marked in a file name (2 for
+// "Movie.CD2.avi"), or 0 if the name has no part marker.
+func PartNumber(path string) int {
+	name := filepath.Base(path)
+	m := partPattern.FindStringSubmatch(strings.TrimSuffix(name, filepath.Ext(name)))
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// SelectMovieFiles picks the video files of a movie download. Samples,
+// trailers, and extras are skipped, as is any video smaller than minRatio of
+// the largest (DefaultMinPartRatio if minRatio is 0). One remaining video is
+// returned alone; several are returned in part order when each carries a
+// distinct part marker (CD1/CD2, part1/part2). Otherwise the download is
+// ambiguous and ErrNeedsReview lists the candidates rather than guessing.
+// Returns ErrNoVideoFile if no video files are found.
+func SelectMovieFiles(dir string, minRatio float64) ([]VideoPart, error) {
+	if minRatio <= 0 {
+		minRatio = DefaultMinPartRatio
+	}
+
+	var videos []VideoPart
+	var largest int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Keep walking past inaccessible subdirectories, like FindLargestVideo
+			return nil //nolint:nilerr // Skip errors intentionally
+		}
+		if d.IsDir() {
+			// Sample/ and Extras/ folders are skipped however large their videos
+			if path != dir && extraDirs[strings.ToLower(d.Name())] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !IsVideoFile(path) || isExtraFile(d.Name()) {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return nil //nolint:nilerr // Removed since the directory was read
+		}
+		videos = append(videos, VideoPart{Path: path, Size: fi.Size()})
+		largest = max(largest, fi.Size())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk directory: %w", err)
+	}
+	if len(videos) == 0 {
+		return nil, ErrNoVideoFile
+	}
+
+	minSize := int64(float64(largest) * minRatio)
+	videos = slices.DeleteFunc(videos, func(v VideoPart) bool { return v.Size < minSize })
+	if len(videos) == 1 {
+		return videos, nil
+	}
+
+	seen := make(map[int]bool)
+	multiPart := true
+	for i := range videos {
+		videos[i].Part = PartNumber(videos[i].Path)
+		if videos[i].Part == 0 || seen[videos[i].Part] {
+			multiPart = false
+		}
+		seen[videos[i].Part] = true
+	}
+	if !multiPart {
+		names := make([]string, 0, len(videos))
+		for _, v := range videos {
+			rel, _ := filepath.Rel(dir, v.Path)
+			names = append(names, rel)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("%w: %s", ErrNeedsReview, strings.Join(names, ", "))
+	}
+	slices.SortFunc(videos, func(a, b VideoPart) int { return a.Part - b.Part })
+	return videos, nil
+}
+
+// SelectAudiobookFiles picks the audio files of an audiobook download, in
+// file name order, which is chapter order for the usual "01 - ..." naming.
+// Several files are numbered as parts from 1; a single file is part 0.
+// Samples are skipped. Unlike movies, files are not weighed against each
+// other: every chapter belongs to the book, however short.
+// Returns ErrNoAudioFile if no audio files are found.
+func SelectAudiobookFiles(dir string) ([]VideoPart, error) {
+	var files []VideoPart
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // Skip errors intentionally
+		}
+		if d.IsDir() {
+			if path != dir && extraDirs[strings.ToLower(d.Name())] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !IsAudioFile(path) || strings.Contains(strings.ToLower(d.Name()), "sample") {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return nil //nolint:nilerr // Removed since the directory was read
+		}
+		files = append(files, VideoPart{Path: path, Size: fi.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk directory: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, ErrNoAudioFile
+	}
+	if len(files) == 1 {
+		return files, nil
+	}
+
+	// WalkDir visits files in lexical order, disc folders included
+	for i := range files {
+		files[i].Part = i + 1
+	}
+	return files, nil
+}
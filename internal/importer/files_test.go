@@ -0,0 +1,109 @@
+// internal/importer/files_test.go
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"Movie.1999.CD1.avi", 1},
+		{"movie-cd2.avi", 2},
+		{"Movie 1999 Part 2.mkv", 2},
+		{"movie.part1.mkv", 1},
+		{"Movie.DVD2.mkv", 2},
+		{"movie_pt3.mp4", 3},
+		{"Movie.Disc.1.mkv", 1},
+		{"Movie.1999.1080p.mkv", 0},
+		{"Partisan.2015.mkv", 0},
+		{"CDX.2020.mkv", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PartNumber(filepath.Join("/downloads", tt.name)))
+		})
+	}
+}
+
+func TestSelectMovieFiles_Ratio(t *testing.T) {
+	dir := t.TempDir()
+	for name, size := range map[string]int{
+		"movie.mkv":            1000,
+		"movie-trailer.mkv":    900, // Extra by name
+		"Extras/interview.mkv": 800, // Extra by folder
+		"bonus.clip.mkv":       250, // Under the ratio
+		"readme.txt":           50,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	}
+
+	videos, err := SelectMovieFiles(dir, 0)
+	require.NoError(t, err)
+	require.Len(t, videos, 1)
+	assert.Equal(t, filepath.Join(dir, "movie.mkv"), videos[0].Path)
+	assert.Zero(t, videos[0].Part)
+
+	// A lower ratio lets the small clip through, which is ambiguous
+	_, err = SelectMovieFiles(dir, 0.2)
+	require.ErrorIs(t, err, ErrNeedsReview)
+	assert.Contains(t, err.Error(), "bonus.clip.mkv, movie.mkv")
+}
+
+func TestSelectMovieFiles_DuplicateParts(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "movie.cd1.avi"), make([]byte, 700), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "movie.1080p.cd1.avi"), make([]byte, 700), 0644))
+
+	_, err := SelectMovieFiles(dir, 0)
+	assert.ErrorIs(t, err, ErrNeedsReview, "two files claiming part 1 are not one movie")
+}
+
+func TestSelectMovieFiles_NoVideos(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Sample"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Sample", "movie.mkv"), make([]byte, 100), 0644))
+
+	_, err := SelectMovieFiles(dir, 0)
+	assert.ErrorIs(t, err, ErrNoVideoFile)
+}
+
+func TestSelectAudiobookFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"Book/02 - Chapter Two.mp3",
+		"Book/01 - Chapter One.mp3",
+		"Book/sample.mp3",
+		"cover.jpg",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, make([]byte, 100), 0644))
+	}
+
+	parts, err := SelectAudiobookFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, parts, 2)
+	assert.Equal(t, filepath.Join(dir, "Book", "01 - Chapter One.mp3"), parts[0].Path)
+	assert.Equal(t, 1, parts[0].Part, "numbered in name order")
+	assert.Equal(t, 2, parts[1].Part)
+
+	single := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(single, "book.m4b"), make([]byte, 100), 0644))
+	parts, err = SelectAudiobookFiles(single)
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	assert.Zero(t, parts[0].Part, "a single file has no part")
+
+	_, err = SelectAudiobookFiles(t.TempDir())
+	assert.ErrorIs(t, err, ErrNoAudioFile)
+}
@@ -3,6 +3,7 @@ package importer
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -15,6 +16,15 @@ const (
 	EventDeleted  = "deleted"
 	EventUpgraded = "upgraded"
 	EventFailed   = "failed"
+
+	// EventGrabDecision records the score breakdown behind an automatic grab.
+	EventGrabDecision = "grab_decision"
+
+	// EventAbandoned records why an operator gave up on wanted content.
+	EventAbandoned = "abandoned"
+
+	// EventHook records the outcome and output of a hook script.
+	EventHook = "hook"
 )
 
 // HistoryEntry represents a history record.
@@ -34,6 +44,8 @@ type HistoryFilter struct {
 	Event     *string
 	Limit     int // Maximum number of results (0 = unlimited)
 	Offset    int // Number of results to skip
+
+	Since *time.Time // Only entries created at or after this time
 }
 
 // HistoryStore persists history records.
@@ -87,6 +99,12 @@ func (s *HistoryStore) List(f HistoryFilter) ([]*HistoryEntry, int, error) {
 		conditions = append(conditions, "event = ?")
 		args = append(args, *f.Event)
 	}
+	// created_at is stored in local time (see Add), so the bound must be too
+	// for the comparison to hold.
+	if f.Since != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, f.Since.Local())
+	}
 
 	whereClause := ""
 	if len(conditions) > 0 {
@@ -132,3 +150,23 @@ func (s *HistoryStore) List(f HistoryFilter) ([]*HistoryEntry, int, error) {
 
 	return results, total, nil
 }
+
+// LatestForDownload returns the most recent entry of the given event type whose
+// data has the download's "download_id". Returns nil if there is none.
+func (s *HistoryStore) LatestForDownload(event string, downloadID int64) (*HistoryEntry, error) {
+	h := &HistoryEntry{}
+	err := s.db.QueryRow(`
+		SELECT id, content_id, episode_id, event, data, created_at
+		FROM history
+		WHERE event = ? AND json_extract(data, '$.download_id') = ?
+		ORDER BY created_at DESC, id DESC LIMIT 1`,
+		event, downloadID,
+	).Scan(&h.ID, &h.ContentID, &h.EpisodeID, &h.Event, &h.Data, &h.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get history for download: %w", err)
+	}
+	return h, nil
+}
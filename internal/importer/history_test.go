@@ -7,10 +7,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
 )
 
 func TestHistoryStore_Add(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewHistoryStore(db)
 	contentID := insertTestContent(t, db)
 
@@ -27,7 +28,7 @@ func TestHistoryStore_Add(t *testing.T) {
 }
 
 func TestHistoryStore_List(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewHistoryStore(db)
 	contentID := insertTestContent(t, db)
 
@@ -66,7 +67,7 @@ func TestHistoryStore_List(t *testing.T) {
 }
 
 func TestHistoryStore_List_OrderByRecent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewHistoryStore(db)
 	contentID := insertTestContent(t, db)
 
@@ -85,3 +86,24 @@ func TestHistoryStore_List_OrderByRecent(t *testing.T) {
 			"entries should be ordered by most recent first")
 	}
 }
+
+func TestHistoryStore_LatestForDownload(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewHistoryStore(db)
+	contentID := insertTestContent(t, db)
+
+	require.NoError(t, store.Add(&HistoryEntry{ContentID: contentID, Event: EventGrabDecision, Data: `{"download_id": 1, "n": 1}`}))
+	require.NoError(t, store.Add(&HistoryEntry{ContentID: contentID, Event: EventGrabDecision, Data: `{"download_id": 2}`}))
+	require.NoError(t, store.Add(&HistoryEntry{ContentID: contentID, Event: EventGrabbed, Data: `{"download_id": 1}`}))
+	require.NoError(t, store.Add(&HistoryEntry{ContentID: contentID, Event: EventGrabDecision, Data: `{"download_id": 1, "n": 2}`}))
+
+	got, err := store.LatestForDownload(EventGrabDecision, 1)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, EventGrabDecision, got.Event)
+	assert.JSONEq(t, `{"download_id": 1, "n": 2}`, got.Data)
+
+	got, err = store.LatestForDownload(EventGrabDecision, 3)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
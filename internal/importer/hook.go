@@ -0,0 +1,160 @@
+// internal/importer/hook.go
+package importer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+)
+
+// DefaultHookTimeout bounds a hook script when no timeout is configured.
+const DefaultHookTimeout = 5 * time.Minute
+
+// maxHookOutput caps how much of a hook's stdout and stderr is kept.
+const maxHookOutput = 16 * 1024
+
+// Hook names, passed to scripts as ARRGO_EVENT.
+const (
+	HookPostImport = "post_import"
+	HookPreCleanup = "pre_cleanup"
+)
+
+// Hook is a user executable run at a point in the import pipeline.
+// Details of the event are passed as ARRGO_* environment variables.
+type Hook struct {
+	Name    string        // HookPostImport or HookPreCleanup
+	Path    string        // Executable to run
+	Timeout time.Duration // Kill the script after this long (default: 5m)
+}
+
+// NewHook returns a hook running path, or nil if path is empty.
+func NewHook(name, path string, timeout time.Duration) *Hook {
+	if path == "" {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	return &Hook{Name: name, Path: path, Timeout: timeout}
+}
+
+// HookResult is the outcome of running a hook.
+type HookResult struct {
+	Hook     string
+	Path     string
+	ExitCode int // -1 if the script did not exit on its own
+	Stdout   string
+	Stderr   string
+	Duration time.Duration
+	Err      error // nil if the script exited zero
+}
+
+// Failed reports whether the script failed to run, timed out, or exited non-zero.
+func (r *HookResult) Failed() bool {
+	return r.Err != nil
+}
+
+// historyData returns the result as a history entry payload.
+func (r *HookResult) historyData() map[string]any {
+	data := map[string]any{
+		"hook":        r.Hook,
+		"path":        r.Path,
+		"exit_code":   r.ExitCode,
+		"duration_ms": r.Duration.Milliseconds(),
+	}
+	if r.Stdout != "" {
+		data["stdout"] = r.Stdout
+	}
+	if r.Stderr != "" {
+		data["stderr"] = r.Stderr
+	}
+	if r.Err != nil {
+		data["error"] = r.Err.Error()
+	}
+	return data
+}
+
+// Run executes the hook with env added to the process environment and
+// ARRGO_EVENT set to the hook name. It waits for the script to exit or
+// the timeout to pass; a non-zero exit is reported in the result's Err.
+func (h *Hook) Run(ctx context.Context, env map[string]string) *HookResult {
+	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.Path) //nolint:gosec // path comes from the operator's config
+	cmd.Env = append(os.Environ(), "ARRGO_EVENT="+h.Name)
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd.Env = append(cmd.Env, k+"="+env[k])
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &limitedBuffer{buf: &stdout, max: maxHookOutput}
+	cmd.Stderr = &limitedBuffer{buf: &stderr, max: maxHookOutput}
+	// Don't wait on children that inherited the output pipes after a kill
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &HookResult{
+		Hook:     h.Name,
+		Path:     h.Path,
+		ExitCode: -1,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+	}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Err = fmt.Errorf("%s hook timed out after %s", h.Name, h.Timeout)
+	case errors.As(err, &exitErr):
+		result.Err = fmt.Errorf("%s hook exited with status %d", h.Name, result.ExitCode)
+	default:
+		result.Err = fmt.Errorf("run %s hook: %w", h.Name, err)
+	}
+	return result
+}
+
+// log records a hook result at info, or warn if it failed.
+func (r *HookResult) log(log *slog.Logger, attrs ...any) {
+	attrs = append(attrs, "hook", r.Hook, "exit_code", r.ExitCode, "duration_ms", r.Duration.Milliseconds())
+	if r.Err != nil {
+		log.Warn("hook failed", append(attrs, "error", r.Err, "stderr", r.Stderr)...)
+		return
+	}
+	log.Info("hook completed", attrs...)
+}
+
+// limitedBuffer keeps the first max bytes written and discards the rest,
+// so a chatty script cannot exhaust memory.
+type limitedBuffer struct {
+	buf *bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); room > 0 {
+		if len(p) > room {
+			b.buf.Write(p[:room])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,82 @@
+// internal/importer/hook_test.go
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeHookScript writes an executable shell script and returns its path.
+func writeHookScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755))
+	return path
+}
+
+func TestNewHook(t *testing.T) {
+	assert.Nil(t, NewHook(HookPostImport, "", time.Minute))
+
+	h := NewHook(HookPostImport, "/usr/local/bin/transcode", 0)
+	require.NotNil(t, h)
+	assert.Equal(t, DefaultHookTimeout, h.Timeout)
+}
+
+func TestHook_Run_Success(t *testing.T) {
+	script := writeHookScript(t, `echo "$ARRGO_EVENT $ARRGO_CONTENT_TITLE"; echo warn >&2`)
+	h := NewHook(HookPostImport, script, time.Minute)
+
+	result := h.Run(context.Background(), map[string]string{"ARRGO_CONTENT_TITLE": "The Matrix"})
+	require.NoError(t, result.Err)
+	assert.False(t, result.Failed())
+	assert.Equal(t, 0, result.ExitCode)
+	assert.Equal(t, "post_import The Matrix\n", result.Stdout)
+	assert.Equal(t, "warn\n", result.Stderr)
+}
+
+func TestHook_Run_NonZeroExit(t *testing.T) {
+	script := writeHookScript(t, `echo "disk full" >&2; exit 3`)
+	h := NewHook(HookPreCleanup, script, time.Minute)
+
+	result := h.Run(context.Background(), nil)
+	require.True(t, result.Failed())
+	assert.Equal(t, 3, result.ExitCode)
+	assert.Equal(t, "disk full\n", result.Stderr)
+	assert.Contains(t, result.Err.Error(), "exited with status 3")
+}
+
+func TestHook_Run_Timeout(t *testing.T) {
+	script := writeHookScript(t, `sleep 10`)
+	h := NewHook(HookPostImport, script, 100*time.Millisecond)
+
+	start := time.Now()
+	result := h.Run(context.Background(), nil)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	require.True(t, result.Failed())
+	assert.Contains(t, result.Err.Error(), "timed out")
+}
+
+func TestHook_Run_NotFound(t *testing.T) {
+	h := NewHook(HookPostImport, filepath.Join(t.TempDir(), "missing.sh"), time.Minute)
+
+	result := h.Run(context.Background(), nil)
+	require.True(t, result.Failed())
+	assert.Equal(t, -1, result.ExitCode)
+}
+
+func TestHook_Run_OutputCapped(t *testing.T) {
+	script := writeHookScript(t, `i=0; while [ $i -lt 4000 ]; do echo "0123456789"; i=$((i+1)); done`)
+	h := NewHook(HookPostImport, script, time.Minute)
+
+	result := h.Run(context.Background(), nil)
+	require.NoError(t, result.Err)
+	assert.Len(t, result.Stdout, maxHookOutput)
+	assert.True(t, strings.HasPrefix(result.Stdout, "0123456789\n"))
+}
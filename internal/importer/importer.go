@@ -10,35 +10,72 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
 )
 
 // Importer processes completed downloads.
 type Importer struct {
-	downloads   *download.Store
-	library     *library.Store
-	history     *HistoryStore
-	renamer     *Renamer
-	mediaServer MediaServer // nil if not configured
-	movieRoot   string
-	seriesRoot  string
-	log         *slog.Logger
+	downloads    *download.Store
+	library      *library.Store
+	history      *HistoryStore
+	renamer      *Renamer
+	mediaServer  MediaServer    // nil if not configured
+	scans        *ScanScheduler // nil sends each scan at once
+	movieRoot    string         // Root for content whose root path is not configured
+	seriesRoot   string         // Root for content whose root path is not configured
+	movieRoots   []string       // Every configured movie root, including movieRoot
+	seriesRoots  []string       // Every configured series root, including seriesRoot
+	bookRoot     string         // Root for audiobooks whose root path is not configured
+	bookRoots    []string       // Every configured audiobook root, including bookRoot
+	watchDir     string
+	autoAdd      bool
+	verify       bool            // Checksum each copy against its source
+	minRatio     float64         // Smallest size, relative to the largest video, of a movie part
+	subtitles    bool            // Copy subtitle sidecars beside imported videos
+	subLanguages map[string]bool // Languages of the subtitles to copy; empty means all
+	postImport   *Hook           // nil if not configured
+	packWorkers  int             // Episodes of a season pack imported at once
+	bus          Publisher       // nil publishes no progress events
+	log          *slog.Logger
 }
 
 // Config for the importer.
 type Config struct {
-	MovieRoot      string
-	SeriesRoot     string
-	MovieTemplate  string
-	SeriesTemplate string
-	PlexURL        string
-	PlexToken      string
-	PlexLocalPath  string // Local path prefix (e.g., /srv/data/media)
-	PlexRemotePath string // Plex's path prefix (e.g., /data/media)
+	MovieRoot         string
+	SeriesRoot        string
+	MovieRoots        []string // Additional movie roots; content stored under one is imported there
+	SeriesRoots       []string // Additional series roots; content stored under one is imported there
+	MovieTemplate     string
+	SeriesTemplate    string
+	PlexURL           string
+	PlexToken         string
+	PlexLocalPath     string        // Local path prefix (e.g., /srv/data/media)
+	PlexRemotePath    string        // Plex's path prefix (e.g., /data/media)
+	PlexScanWindow    time.Duration // Scans of a section within this window are sent together (default: 30s; negative sends each at once)
+	PlexScanMaxPaths  int           // Directories of a section scanned one by one; beyond it the section is refreshed whole (default: 5)
+	WatchDir          string        // Drop folder scanned for manual imports (empty = disabled)
+	WatchAutoAdd      bool          // Add unmatched titles to the library when importing from WatchDir
+	PostImportHook    string        // Executable run after each imported file (empty = disabled)
+	HookTimeout       time.Duration // Bound on a hook run (default: 5m)
+	VerifyChecksum    bool          // Hash source and destination and fail the import on mismatch
+	MinPartRatio      float64       // Videos smaller than this fraction of the largest are not movie parts (default: 0.3)
+	Subtitles         bool          // Copy subtitle sidecars of imported videos beside them
+	SubtitleLanguages []string      // ISO 639-1 codes of the subtitles to copy; empty means all, including unlabeled ones
+
+	SeasonPackConcurrency int // Episodes of a season pack imported at once (default: DefaultSeasonPackConcurrency)
+
+	// Audiobook library; roots are empty unless it is enabled
+	AudiobookRoot     string
+	AudiobookRoots    []string
+	AudiobookTemplate string // Default: DefaultAudiobookTemplate
 }
 
 // New creates a new importer.
@@ -52,32 +89,130 @@ func New(db *sql.DB, cfg Config, log *slog.Logger) *Importer {
 		}
 	}
 
+	var scans *ScanScheduler
+	if mediaServer != nil && cfg.PlexScanWindow >= 0 {
+		scans = NewScanScheduler(mediaServer, cfg.PlexScanWindow, cfg.PlexScanMaxPaths, log.With("component", "plexscan"))
+	}
+
+	subLanguages := make(map[string]bool, len(cfg.SubtitleLanguages))
+	for _, lang := range cfg.SubtitleLanguages {
+		if code, ok := LanguageCode(lang); ok {
+			subLanguages[code] = true
+		}
+	}
+
+	packWorkers := cfg.SeasonPackConcurrency
+	if packWorkers <= 0 {
+		packWorkers = DefaultSeasonPackConcurrency
+	}
+
+	renamer := NewRenamer(cfg.MovieTemplate, cfg.SeriesTemplate)
+	renamer.SetAudiobookTemplate(cfg.AudiobookTemplate)
+
 	return &Importer{
-		downloads:   download.NewStore(db),
-		library:     library.NewStore(db),
-		history:     NewHistoryStore(db),
-		renamer:     NewRenamer(cfg.MovieTemplate, cfg.SeriesTemplate),
-		mediaServer: mediaServer,
-		movieRoot:   cfg.MovieRoot,
-		seriesRoot:  cfg.SeriesRoot,
-		log:         log,
+		downloads:    download.NewStore(db),
+		library:      library.NewStore(db),
+		history:      NewHistoryStore(db),
+		renamer:      renamer,
+		mediaServer:  mediaServer,
+		scans:        scans,
+		movieRoot:    cfg.MovieRoot,
+		seriesRoot:   cfg.SeriesRoot,
+		movieRoots:   append([]string{cfg.MovieRoot}, cfg.MovieRoots...),
+		seriesRoots:  append([]string{cfg.SeriesRoot}, cfg.SeriesRoots...),
+		bookRoot:     cfg.AudiobookRoot,
+		bookRoots:    append([]string{cfg.AudiobookRoot}, cfg.AudiobookRoots...),
+		watchDir:     cfg.WatchDir,
+		autoAdd:      cfg.WatchAutoAdd,
+		verify:       cfg.VerifyChecksum,
+		minRatio:     cfg.MinPartRatio,
+		subtitles:    cfg.Subtitles,
+		subLanguages: subLanguages,
+		postImport:   NewHook(HookPostImport, cfg.PostImportHook, cfg.HookTimeout),
+		packWorkers:  packWorkers,
+		log:          log,
 	}
 }
 
+// rootFor returns the library root to import content into: its stored root
+// path when that is a configured root, otherwise the root for its type.
+// Paths outside the configured roots are never written to.
+func (i *Importer) rootFor(content *library.Content) string {
+	root, roots := i.movieRoot, i.movieRoots
+	switch content.Type {
+	case library.ContentTypeSeries:
+		root, roots = i.seriesRoot, i.seriesRoots
+	case library.ContentTypeAudiobook:
+		root, roots = i.bookRoot, i.bookRoots
+	}
+	if content.RootPath != "" {
+		stored := filepath.Clean(content.RootPath)
+		for _, r := range roots {
+			if r != "" && filepath.Clean(r) == stored {
+				return r
+			}
+		}
+	}
+	return root
+}
+
+// copyChecked copies src to dst, checksumming the copy when verification is
+// enabled. Sizes are always compared. Returns the size and the checksum
+// (empty when verification is off).
+func (i *Importer) copyChecked(ctx context.Context, src, dst string) (int64, string, error) {
+	if i.verify {
+		return CopyFileVerified(ctx, src, dst)
+	}
+	size, err := CopyFileContext(ctx, src, dst)
+	return size, "", err
+}
+
+// sameContents reports whether an existing dst of the same size as src holds
+// the same data, for resuming an interrupted import. Without verification
+// the size match is taken as enough. Returns the checksum when one was computed.
+func (i *Importer) sameContents(ctx context.Context, src, dst string) (bool, string) {
+	if !i.verify {
+		return true, ""
+	}
+	srcSum, err := FileChecksum(ctx, src)
+	if err != nil {
+		i.log.Warn("failed to hash source file", "src", src, "error", err)
+		return false, ""
+	}
+	dstSum, err := FileChecksum(ctx, dst)
+	if err != nil {
+		i.log.Warn("failed to hash existing file", "dest", dst, "error", err)
+		return false, ""
+	}
+	return srcSum == dstSum, srcSum
+}
+
 // ImportResult is the result of an import operation.
 type ImportResult struct {
-	FileID       int64
-	SourcePath   string
-	DestPath     string
-	SizeBytes    int64
-	Quality      string
-	PlexNotified bool
-	PlexError    error
+	FileID        int64 // First part for a multi-part movie
+	SourcePath    string
+	DestPath      string
+	Parts         []string // Destination of every part of a multi-part movie (empty for a single file)
+	SizeBytes     int64    // Total of all parts
+	Quality       string
+	Checksum      string   // Hex SHA-256 of the copy (empty if verification is off)
+	Subtitles     []string // Destination of every subtitle copied beside the import
+	PlexNotified  bool     // The scan was sent, or scheduled
+	PlexScheduled bool     // The scan waits to be sent with others (see ScanScheduler)
+	PlexError     error
+	Hook          *HookResult // Post-import hook outcome (nil if no hook configured)
 }
 
-// Import processes a completed download.
-// It orchestrates three phases: prepare, execute, and notify.
+// Import processes a completed download with the default options.
 func (i *Importer) Import(ctx context.Context, downloadID int64, downloadPath string) (*ImportResult, error) {
+	return i.ImportWithOptions(ctx, downloadID, downloadPath, ImportOptions{})
+}
+
+// ImportWithOptions processes a completed download.
+// It orchestrates three phases: prepare, execute, and notify, then runs the
+// post-import hook if one is configured. An import that would downgrade an
+// existing file fails with ErrImportWouldDowngrade unless opts allow it.
+func (i *Importer) ImportWithOptions(ctx context.Context, downloadID int64, downloadPath string, opts ImportOptions) (*ImportResult, error) {
 	i.log.Info("import started", "download_id", downloadID, "path", downloadPath)
 
 	// Phase 1: Prepare - validate download, find video, build paths
@@ -85,9 +220,12 @@ func (i *Importer) Import(ctx context.Context, downloadID int64, downloadPath st
 	if err != nil {
 		return nil, err
 	}
+	if err := i.checkExisting(job, opts); err != nil {
+		return nil, err
+	}
 
 	// Phase 2: Execute - copy file, update database, record history
-	result, err := i.executeImport(job)
+	result, err := i.executeImport(ctx, job)
 	if err != nil {
 		return nil, err
 	}
@@ -95,12 +233,25 @@ func (i *Importer) Import(ctx context.Context, downloadID int64, downloadPath st
 	// Phase 3: Notify - trigger media server scan (best effort)
 	i.notifyMediaServer(ctx, job, result)
 
-	i.log.Info("import complete", "download_id", downloadID, "dest", job.DestPath, "quality", job.Quality)
+	// A failing hook is recorded but does not fail the import. Each part of a
+	// multi-part movie is its own file to the hook; the first failure is kept.
+	if len(job.Parts) == 0 {
+		result.Hook = i.runPostImportHook(ctx, job.Download, job.Content, job.Episode, job.SourcePath, job.DestPath, job.Quality, result.SizeBytes)
+	}
+	for _, part := range job.Parts {
+		hook := i.runPostImportHook(ctx, job.Download, job.Content, job.Episode, part.SourcePath, part.DestPath, job.Quality, part.SizeBytes)
+		if result.Hook == nil || (hook != nil && hook.Failed() && !result.Hook.Failed()) {
+			result.Hook = hook
+		}
+	}
+
+	i.log.Info("import complete", "download_id", downloadID, "dest", job.DestPath, "quality", job.Quality, "parts", len(job.Parts))
 	return result, nil
 }
 
 // prepareImport validates the download and prepares an import job.
-// It verifies the download is ready, finds the video file, and builds paths.
+// It verifies the download is ready, finds the video file (every part of a
+// multi-part movie), and builds paths.
 func (i *Importer) prepareImport(downloadID int64, downloadPath string) (*ImportJob, error) {
 	// Get download record
 	dl, err := i.downloads.Get(downloadID)
@@ -122,40 +273,64 @@ func (i *Importer) prepareImport(downloadID int64, downloadPath string) (*Import
 		return nil, fmt.Errorf("get content: %w", err)
 	}
 
-	// Find largest video file
-	srcPath, _, err := FindLargestVideo(downloadPath)
-	if err != nil {
-		return nil, err
-	}
-	i.log.Debug("found video", "path", srcPath)
-
 	// Extract quality from release name
 	quality := extractQuality(dl.ReleaseName)
-
-	// Build destination path
-	ext := strings.TrimPrefix(filepath.Ext(srcPath), ".")
-	var relPath string
-	var root string
-	var episode *library.Episode
+	root := i.rootFor(content)
 
 	if content.Type == library.ContentTypeMovie {
-		relPath = i.renamer.MoviePath(content.Title, content.Year, quality, ext)
-		root = i.movieRoot
-	} else {
-		// Series: require episode to be specified
-		if dl.EpisodeID == nil {
-			return nil, ErrEpisodeNotSpecified
+		videos, err := SelectMovieFiles(downloadPath, i.minRatio)
+		if err != nil {
+			return nil, err
 		}
+		var parts []ImportPart
+		for _, v := range videos {
+			ext := strings.TrimPrefix(filepath.Ext(v.Path), ".")
+			destPath := filepath.Join(root, i.renamer.For(content).MoviePartPath(content.Title, content.Year, quality, ext, v.Part))
+			// Validate path is within root (security check)
+			if err := ValidatePath(destPath, root); err != nil {
+				return nil, err
+			}
+			parts = append(parts, ImportPart{Part: v.Part, SourcePath: v.Path, DestPath: destPath, SizeBytes: v.Size})
+		}
+		i.log.Debug("found video", "path", parts[0].SourcePath, "parts", len(parts))
 
-		episode, err = i.library.GetEpisode(*dl.EpisodeID)
-		if err != nil {
-			return nil, fmt.Errorf("get episode: %w", err)
+		job := &ImportJob{
+			Download:   dl,
+			Content:    content,
+			SourcePath: parts[0].SourcePath,
+			DestPath:   parts[0].DestPath,
+			Quality:    quality,
+			RootPath:   root,
+		}
+		if len(parts) > 1 {
+			job.Parts = parts
 		}
+		return job, nil
+	}
 
-		relPath = i.renamer.EpisodePath(content.Title, episode.Season, episode.Episode, quality, ext)
-		root = i.seriesRoot
+	if content.Type == library.ContentTypeAudiobook {
+		return i.prepareAudiobook(dl, content, downloadPath, quality, root)
 	}
 
+	// Series: require episode to be specified
+	if dl.EpisodeID == nil {
+		return nil, ErrEpisodeNotSpecified
+	}
+	episode, err := i.library.GetEpisode(*dl.EpisodeID)
+	if err != nil {
+		return nil, fmt.Errorf("get episode: %w", err)
+	}
+
+	// Find largest video file
+	srcPath, _, err := FindLargestVideo(downloadPath)
+	if err != nil {
+		return nil, err
+	}
+	i.log.Debug("found video", "path", srcPath)
+
+	// Build destination path
+	ext := strings.TrimPrefix(filepath.Ext(srcPath), ".")
+	relPath := i.renamer.For(content).EpisodePath(content.Title, episode.Season, episode.Episode, quality, ext)
 	destPath := filepath.Join(root, relPath)
 
 	// Validate path is within root (security check)
@@ -174,15 +349,95 @@ func (i *Importer) prepareImport(downloadID int64, downloadPath string) (*Import
 	}, nil
 }
 
-// executeImport copies the file and updates the database.
-// It handles the file copy, database transaction, and history recording.
-func (i *Importer) executeImport(job *ImportJob) (*ImportResult, error) {
-	// Copy file
-	size, err := CopyFile(job.SourcePath, job.DestPath)
+// prepareAudiobook prepares the import of an audiobook: every audio file of
+// the download, each recorded as a part, under the author's folder.
+func (i *Importer) prepareAudiobook(dl *download.Download, content *library.Content, downloadPath, quality, root string) (*ImportJob, error) {
+	if root == "" {
+		return nil, errors.New("import audiobook: no audiobook library configured")
+	}
+	files, err := SelectAudiobookFiles(downloadPath)
 	if err != nil {
 		return nil, err
 	}
-	i.log.Debug("file copied", "src", job.SourcePath, "dest", job.DestPath, "size_bytes", size)
+	renamer := i.renamer.For(content)
+	parts := make([]ImportPart, 0, len(files))
+	for _, f := range files {
+		ext := strings.TrimPrefix(filepath.Ext(f.Path), ".")
+		destPath := filepath.Join(root, renamer.AudiobookPath(content.Author, content.Title, content.Year, ext, f.Part))
+		// Validate path is within root (security check)
+		if err := ValidatePath(destPath, root); err != nil {
+			return nil, err
+		}
+		parts = append(parts, ImportPart{Part: f.Part, SourcePath: f.Path, DestPath: destPath, SizeBytes: f.Size})
+	}
+	i.log.Debug("found audio", "path", parts[0].SourcePath, "files", len(parts))
+
+	job := &ImportJob{
+		Download:   dl,
+		Content:    content,
+		SourcePath: parts[0].SourcePath,
+		DestPath:   parts[0].DestPath,
+		Quality:    quality,
+		RootPath:   root,
+	}
+	if len(parts) > 1 {
+		job.Parts = parts
+	}
+	return job, nil
+}
+
+// executeImport copies the file and updates the database.
+// It handles the file copy, database transaction, and history recording.
+// Each part of a multi-part movie is copied and recorded as its own file.
+func (i *Importer) executeImport(ctx context.Context, job *ImportJob) (*ImportResult, error) {
+	parts := job.Parts
+	if len(parts) == 0 {
+		parts = []ImportPart{{SourcePath: job.SourcePath, DestPath: job.DestPath}}
+	}
+
+	// A part written over a replaced file is copied beside it first
+	replaced := make(map[string]bool, len(job.Replaces))
+	for _, f := range job.Replaces {
+		replaced[f.Path] = true
+	}
+	copyPaths := make([]string, len(parts))
+	for n, part := range parts {
+		copyPaths[n] = part.DestPath
+		if replaced[part.DestPath] {
+			copyPaths[n] = part.DestPath + stagingSuffix
+		}
+	}
+
+	// Copy files; a part that fails removes the parts already copied
+	var total int64
+	checksums := make([]string, len(parts))
+	for n := range parts {
+		size, checksum, err := i.copyChecked(ctx, parts[n].SourcePath, copyPaths[n])
+		if err != nil {
+			for _, done := range copyPaths[:n] {
+				_ = os.Remove(done)
+			}
+			return nil, err
+		}
+		parts[n].SizeBytes = size
+		checksums[n] = checksum
+		total += size
+		i.log.Debug("file copied", "src", parts[n].SourcePath, "dest", parts[n].DestPath, "size_bytes", size, "checksum", checksum)
+	}
+
+	// Subtitles are copied beside each part; they don't fail the import
+	var subtitles []*library.File
+	if job.Content.Type != library.ContentTypeAudiobook {
+		for _, part := range parts {
+			subtitles = append(subtitles, i.copySubtitles(ctx, part.SourcePath, &library.File{
+				ContentID:    job.Content.ID,
+				EpisodeID:    job.Download.EpisodeID,
+				Path:         part.DestPath,
+				Source:       job.Download.Indexer,
+				ReleaseGroup: releaseGroup(job.Download, part.SourcePath),
+			})...)
+		}
+	}
 
 	// Update database in transaction
 	tx, err := i.library.Begin()
@@ -191,18 +446,35 @@ func (i *Importer) executeImport(job *ImportJob) (*ImportResult, error) {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	// Insert file record
-	file := &library.File{
-		ContentID: job.Content.ID,
-		EpisodeID: job.Download.EpisodeID,
-		Path:      job.DestPath,
-		SizeBytes: size,
-		Quality:   job.Quality,
-		Source:    job.Download.Indexer,
+	// Replaced records go first so their paths are free
+	for _, f := range job.Replaces {
+		if err := tx.DeleteFile(f.ID); err != nil {
+			return nil, fmt.Errorf("delete replaced file: %w", err)
+		}
 	}
-	if err := tx.AddFile(file); err != nil {
-		return nil, fmt.Errorf("add file: %w", err)
+
+	// Insert file records
+	var firstID int64
+	for n, part := range parts {
+		file := &library.File{
+			ContentID:    job.Content.ID,
+			EpisodeID:    job.Download.EpisodeID,
+			Path:         part.DestPath,
+			SizeBytes:    part.SizeBytes,
+			Quality:      job.Quality,
+			Source:       job.Download.Indexer,
+			Checksum:     checksums[n],
+			PartNumber:   part.Part,
+			ReleaseGroup: releaseGroup(job.Download, part.SourcePath),
+		}
+		if err := tx.AddFile(file); err != nil {
+			return nil, fmt.Errorf("add file: %w", err)
+		}
+		if n == 0 {
+			firstID = file.ID
+		}
 	}
+	i.addSubtitles(tx, subtitles)
 
 	// Update status: content for movies, episode for series
 	if job.Episode != nil {
@@ -222,6 +494,22 @@ func (i *Importer) executeImport(job *ImportJob) (*ImportResult, error) {
 		return nil, fmt.Errorf("commit: %w", err)
 	}
 
+	// Move staged copies over the files they replace, then remove the rest
+	written := make(map[string]bool, len(parts)+len(subtitles))
+	for _, sub := range subtitles {
+		written[sub.Path] = true
+	}
+	for n, part := range parts {
+		written[part.DestPath] = true
+		if copyPaths[n] == part.DestPath {
+			continue
+		}
+		if err := os.Rename(copyPaths[n], part.DestPath); err != nil {
+			i.log.Warn("failed to move replacement into place", "src", copyPaths[n], "dest", part.DestPath, "error", err)
+		}
+	}
+	removed := i.removeReplaced(job.Replaces, written)
+
 	// Update download status (separate from library transaction)
 	job.Download.Status = download.StatusImported
 	now := time.Now()
@@ -230,19 +518,50 @@ func (i *Importer) executeImport(job *ImportJob) (*ImportResult, error) {
 		i.log.Warn("update download status failed", "download_id", job.Download.ID, "error", err)
 	}
 
+	result := &ImportResult{
+		FileID:     firstID,
+		SourcePath: job.SourcePath,
+		DestPath:   job.DestPath,
+		SizeBytes:  total,
+		Quality:    job.Quality,
+		Checksum:   checksums[0],
+	}
+
 	// Add history entry
 	historyMap := map[string]any{
 		"source_path":  job.SourcePath,
 		"dest_path":    job.DestPath,
-		"size_bytes":   size,
+		"size_bytes":   total,
 		"quality":      job.Quality,
 		"indexer":      job.Download.Indexer,
 		"release_name": job.Download.ReleaseName,
+		"download_id":  job.Download.ID,
 	}
 	if job.Episode != nil {
 		historyMap["season"] = job.Episode.Season
 		historyMap["episode"] = job.Episode.Episode
 	}
+	if len(job.Parts) > 0 {
+		for _, part := range job.Parts {
+			result.Parts = append(result.Parts, part.DestPath)
+		}
+		historyMap["parts"] = result.Parts
+	}
+	if checksums[0] != "" {
+		historyMap["checksum"] = checksums[0]
+	}
+	if len(subtitles) > 0 {
+		result.Subtitles = filePaths(subtitles)
+		historyMap["subtitles"] = result.Subtitles
+	}
+	if len(job.Replaces) > 0 {
+		replacedPaths := make([]string, 0, len(job.Replaces))
+		for _, f := range job.Replaces {
+			replacedPaths = append(replacedPaths, f.Path)
+		}
+		historyMap["replaced"] = replacedPaths
+		i.log.Info("replaced existing files", "content_id", job.Content.ID, "replaced", len(replacedPaths), "removed", len(removed))
+	}
 	historyData, _ := json.Marshal(historyMap)
 	_ = i.history.Add(&HistoryEntry{
 		ContentID: job.Content.ID,
@@ -251,13 +570,7 @@ func (i *Importer) executeImport(job *ImportJob) (*ImportResult, error) {
 		Data:      string(historyData),
 	})
 
-	return &ImportResult{
-		FileID:     file.ID,
-		SourcePath: job.SourcePath,
-		DestPath:   job.DestPath,
-		SizeBytes:  size,
-		Quality:    job.Quality,
-	}, nil
+	return result, nil
 }
 
 // notifyMediaServer triggers a scan of the imported file path.
@@ -267,13 +580,78 @@ func (i *Importer) notifyMediaServer(ctx context.Context, job *ImportJob, result
 		return
 	}
 
-	if err := i.mediaServer.ScanPath(ctx, job.DestPath); err != nil {
+	scheduled, err := i.scanPath(ctx, job.DestPath)
+	if err != nil {
 		result.PlexError = err
 		i.log.Warn("plex notification failed", "error", err)
 	} else {
 		result.PlexNotified = true
-		i.log.Debug("plex notified", "path", job.DestPath)
+		result.PlexScheduled = scheduled
+		i.log.Debug("plex notified", "path", job.DestPath, "scheduled", scheduled)
+	}
+}
+
+// scanPath asks the media server to scan the directory containing path,
+// through the scan scheduler when there is one. Reports whether the scan was
+// scheduled rather than sent.
+func (i *Importer) scanPath(ctx context.Context, path string) (bool, error) {
+	if i.scans != nil {
+		return i.scans.Schedule(ctx, path)
+	}
+	return false, i.mediaServer.ScanPath(ctx, path)
+}
+
+// RunScans sends scheduled media server scans until the context is canceled;
+// while it isn't running, scans are sent at once. Returns immediately if
+// there is no media server or scans aren't scheduled.
+func (i *Importer) RunScans(ctx context.Context) error {
+	if i.scans == nil {
+		return nil
 	}
+	return i.scans.Run(ctx)
+}
+
+// runPostImportHook runs the post-import hook for one imported file and
+// records its outcome in history. Returns nil if no hook is configured.
+func (i *Importer) runPostImportHook(ctx context.Context, dl *download.Download, content *library.Content, episode *library.Episode, srcPath, destPath, quality string, size int64) *HookResult {
+	if i.postImport == nil {
+		return nil
+	}
+
+	env := map[string]string{
+		"ARRGO_CONTENT_ID":    strconv.FormatInt(content.ID, 10),
+		"ARRGO_CONTENT_TYPE":  string(content.Type),
+		"ARRGO_CONTENT_TITLE": content.Title,
+		"ARRGO_CONTENT_YEAR":  strconv.Itoa(content.Year),
+		"ARRGO_DOWNLOAD_ID":   strconv.FormatInt(dl.ID, 10),
+		"ARRGO_RELEASE_NAME":  dl.ReleaseName,
+		"ARRGO_INDEXER":       dl.Indexer,
+		"ARRGO_SOURCE_PATH":   srcPath,
+		"ARRGO_FILE_PATH":     destPath,
+		"ARRGO_QUALITY":       quality,
+		"ARRGO_SIZE_BYTES":    strconv.FormatInt(size, 10),
+	}
+	var episodeID *int64
+	if episode != nil {
+		episodeID = &episode.ID
+		env["ARRGO_EPISODE_ID"] = strconv.FormatInt(episode.ID, 10)
+		env["ARRGO_SEASON"] = strconv.Itoa(episode.Season)
+		env["ARRGO_EPISODE"] = strconv.Itoa(episode.Episode)
+	}
+
+	result := i.postImport.Run(ctx, env)
+	result.log(i.log, "download_id", dl.ID, "path", destPath)
+
+	historyData, _ := json.Marshal(result.historyData())
+	if err := i.history.Add(&HistoryEntry{
+		ContentID: content.ID,
+		EpisodeID: episodeID,
+		Event:     EventHook,
+		Data:      string(historyData),
+	}); err != nil {
+		i.log.Warn("failed to record hook history", "download_id", dl.ID, "error", err)
+	}
+	return result
 }
 
 // extractQuality extracts resolution from a release name.
@@ -293,23 +671,40 @@ func extractQuality(releaseName string) string {
 	}
 }
 
+// releaseGroup returns the group of the release a file came from: the
+// download's, else one parsed from the file name, since some releases name
+// their group only on the files inside.
+func releaseGroup(dl *download.Download, srcPath string) string {
+	if dl.ReleaseGroup != "" {
+		return dl.ReleaseGroup
+	}
+	return release.ParseGroup(filepath.Base(srcPath))
+}
+
 // EpisodeResult represents the outcome of importing a single episode.
 type EpisodeResult struct {
-	EpisodeID int64
-	Season    int
-	Episode   int
-	Success   bool
-	FilePath  string // Destination path (empty if failed)
-	SizeBytes int64
-	Error     error // nil if success
+	EpisodeID  int64
+	FileID     int64 // Library file record (0 if failed)
+	Season     int
+	Episode    int
+	Success    bool
+	SourceFile string // Video path relative to the download folder
+	FilePath   string // Destination path (empty if failed)
+	SizeBytes  int64
+	Error      error       // nil if success
+	Hook       *HookResult // Post-import hook outcome (nil if failed or no hook configured)
+	// AlreadyImported is set when an earlier import of the download imported
+	// the file and its file record still exists, so it was not imported again
+	AlreadyImported bool
 }
 
 // SeasonPackResult is the result of importing a season pack.
 type SeasonPackResult struct {
-	TotalSize    int64           // Total bytes imported
-	Episodes     []EpisodeResult // Per-episode outcomes
-	PlexNotified bool
-	PlexError    error
+	TotalSize     int64           // Total bytes imported
+	Episodes      []EpisodeResult // Per-episode outcomes
+	PlexNotified  bool            // The scan was sent, or scheduled
+	PlexScheduled bool            // The scan waits to be sent with others
+	PlexError     error
 }
 
 // SuccessCount returns the number of successfully imported episodes.
@@ -323,8 +718,22 @@ func (r *SeasonPackResult) SuccessCount() int {
 	return count
 }
 
+// DownloadStatus returns the status of the download after the import:
+// imported if every episode succeeded, otherwise partially imported.
+func (r *SeasonPackResult) DownloadStatus() download.Status {
+	if r.SuccessCount() < len(r.Episodes) {
+		return download.StatusPartiallyImported
+	}
+	return download.StatusImported
+}
+
 // ImportSeasonPack processes a season pack download with multiple video files.
-// It matches each video file to an episode and imports them.
+// It matches each video file to an episode and imports them. The outcome of
+// each file is recorded on the download; files an earlier import of the
+// download succeeded on are skipped while their file record exists, so
+// running it again on a partially imported download retries only the
+// episodes that failed. Files are imported a few at a time, each on its own:
+// one failing does not stop the others.
 func (i *Importer) ImportSeasonPack(ctx context.Context, downloadID int64, downloadPath string) (*SeasonPackResult, error) {
 	i.log.Info("season pack import started", "download_id", downloadID, "path", downloadPath)
 
@@ -371,25 +780,68 @@ func (i *Importer) ImportSeasonPack(ctx context.Context, downloadID int64, downl
 		Episodes: make([]EpisodeResult, 0, len(videos)),
 	}
 
-	// Process each video file
-	for _, srcPath := range videos {
-		epResult := i.importEpisodeFile(ctx, dl, content, srcPath, quality)
-		result.Episodes = append(result.Episodes, epResult)
-		if epResult.Success {
-			result.TotalSize += epResult.SizeBytes
+	previous := i.previousResults(downloadID)
+
+	// Import the files on a bounded pool of workers, each into its own slot
+	episodes := make([]EpisodeResult, len(videos))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(i.packWorkers, 1), len(videos)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range work {
+				episodes[n] = i.importPackFile(ctx, dl, content, downloadPath, videos[n], quality, previous)
+			}
+		}()
+	}
+feed:
+	for n := range videos {
+		select {
+		case work <- n:
+		case <-ctx.Done():
+			break feed
 		}
 	}
+	close(work)
+	wg.Wait()
+
+	// Stop on cancellation; a rerun skips episodes already copied
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("season pack import interrupted: %w", err)
+	}
+
+	for _, ep := range episodes {
+		result.Episodes = append(result.Episodes, ep)
+		if ep.Success {
+			result.TotalSize += ep.SizeBytes
+		}
+	}
+	sort.SliceStable(result.Episodes, func(a, b int) bool {
+		ea, eb := result.Episodes[a], result.Episodes[b]
+		if ea.Season != eb.Season {
+			return ea.Season < eb.Season
+		}
+		if ea.Episode != eb.Episode {
+			return ea.Episode < eb.Episode
+		}
+		return ea.SourceFile < eb.SourceFile
+	})
+
+	i.recordResults(downloadID, result.Episodes)
 
 	// Notify media server once for the series folder (best effort)
 	if i.mediaServer != nil {
 		// Scan the series root folder
-		seriesPath := filepath.Join(i.seriesRoot, SanitizeFilename(content.Title))
-		if err := i.mediaServer.ScanPath(ctx, seriesPath); err != nil {
+		seriesPath := filepath.Join(i.rootFor(content), SanitizeFilename(content.Title))
+		scheduled, err := i.scanPath(ctx, seriesPath)
+		if err != nil {
 			result.PlexError = err
 			i.log.Warn("plex notification failed", "error", err)
 		} else {
 			result.PlexNotified = true
-			i.log.Debug("plex notified", "path", seriesPath)
+			result.PlexScheduled = scheduled
+			i.log.Debug("plex notified", "path", seriesPath, "scheduled", scheduled)
 		}
 	}
 
@@ -402,29 +854,119 @@ func (i *Importer) ImportSeasonPack(ctx context.Context, downloadID int64, downl
 	return result, nil
 }
 
-// importEpisodeFile imports a single episode file from a season pack.
-func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, content *library.Content, srcPath, quality string) EpisodeResult {
-	// Parse filename to get season/episode
-	season, epNum, err := MatchFileToSeason(srcPath)
+// previousResults returns the successful episode outcomes recorded by an
+// earlier import of the download, keyed by source file.
+func (i *Importer) previousResults(downloadID int64) map[string]*download.EpisodeResult {
+	results, err := i.downloads.EpisodeResults(downloadID)
 	if err != nil {
-		i.log.Warn("failed to match file to season", "path", srcPath, "error", err)
-		return EpisodeResult{
-			Season:  0,
-			Episode: 0,
-			Success: false,
-			Error:   fmt.Errorf("match file to season: %w", err),
+		i.log.Warn("failed to load previous episode results", "download_id", downloadID, "error", err)
+		return nil
+	}
+	previous := make(map[string]*download.EpisodeResult, len(results))
+	for _, r := range results {
+		if r.Success && r.EpisodeID != nil {
+			previous[r.SourceFile] = r
 		}
 	}
+	return previous
+}
 
-	// Find or create episode record
-	episode, created, err := i.library.FindOrCreateEpisode(content.ID, season, epNum)
+// fileRecord returns the ID of the file a previous import recorded for the
+// episode, or 0 if the library no longer has it.
+func (i *Importer) fileRecord(r *download.EpisodeResult) int64 {
+	files, _, err := i.library.ListFiles(library.FileFilter{EpisodeID: r.EpisodeID})
 	if err != nil {
-		i.log.Warn("failed to find/create episode", "season", season, "episode", epNum, "error", err)
-		return EpisodeResult{
-			Season:  season,
-			Episode: epNum,
-			Success: false,
-			Error:   fmt.Errorf("find/create episode: %w", err),
+		return 0
+	}
+	for _, f := range files {
+		if f.Path == r.FilePath {
+			return f.ID
+		}
+	}
+	return 0
+}
+
+// existingFileID returns the ID of the episode's file record at path, or 0.
+func existingFileID(tx *library.Tx, episodeID int64, path string) int64 {
+	files, _, err := tx.ListFiles(library.FileFilter{EpisodeID: &episodeID})
+	if err != nil {
+		return 0
+	}
+	for _, f := range files {
+		if f.Path == path {
+			return f.ID
+		}
+	}
+	return 0
+}
+
+// recordResults stores the outcome of each file of a season pack on the
+// download (best effort).
+func (i *Importer) recordResults(downloadID int64, episodes []EpisodeResult) {
+	results := make([]*download.EpisodeResult, 0, len(episodes))
+	for _, ep := range episodes {
+		r := &download.EpisodeResult{
+			SourceFile: ep.SourceFile,
+			Season:     ep.Season,
+			Episode:    ep.Episode,
+			Success:    ep.Success,
+			FilePath:   ep.FilePath,
+		}
+		if ep.EpisodeID != 0 {
+			r.EpisodeID = &ep.EpisodeID
+		}
+		if ep.Error != nil {
+			r.Error = ep.Error.Error()
+		}
+		results = append(results, r)
+	}
+	if err := i.downloads.SetEpisodeResults(downloadID, results); err != nil {
+		i.log.Warn("failed to record episode results", "download_id", downloadID, "error", err)
+	}
+}
+
+// importEpisodeFile imports a single episode file from a season pack.
+func (i *Importer) importEpisodeFile(ctx context.Context, dl *download.Download, content *library.Content, srcPath, quality string) EpisodeResult {
+	var (
+		season, epNum int
+		episode       *library.Episode
+		created       bool
+		err           error
+	)
+	if airDate, ok := MatchFileToAirDate(srcPath, content.Daily); ok {
+		// Daily shows are matched by air date instead of SxxExx
+		episode, created, err = i.library.FindOrCreateEpisodeByDate(content.ID, airDate)
+		if err != nil {
+			i.log.Warn("failed to find/create episode", "air_date", airDate.Format(time.DateOnly), "error", err)
+			return EpisodeResult{
+				Success: false,
+				Error:   fmt.Errorf("find/create episode: %w", err),
+			}
+		}
+		season, epNum = episode.Season, episode.Episode
+	} else {
+		// Parse filename to get season/episode
+		season, epNum, err = MatchFileToSeason(srcPath)
+		if err != nil {
+			i.log.Warn("failed to match file to season", "path", srcPath, "error", err)
+			return EpisodeResult{
+				Season:  0,
+				Episode: 0,
+				Success: false,
+				Error:   fmt.Errorf("match file to season: %w", err),
+			}
+		}
+
+		// Find or create episode record
+		episode, created, err = i.library.FindOrCreateEpisode(content.ID, season, epNum)
+		if err != nil {
+			i.log.Warn("failed to find/create episode", "season", season, "episode", epNum, "error", err)
+			return EpisodeResult{
+				Season:  season,
+				Episode: epNum,
+				Success: false,
+				Error:   fmt.Errorf("find/create episode: %w", err),
+			}
 		}
 	}
 	if created {
@@ -433,11 +975,12 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 
 	// Build destination path
 	ext := strings.TrimPrefix(filepath.Ext(srcPath), ".")
-	relPath := i.renamer.EpisodePath(content.Title, season, epNum, quality, ext)
-	destPath := filepath.Join(i.seriesRoot, relPath)
+	relPath := i.renamer.For(content).EpisodePath(content.Title, season, epNum, quality, ext)
+	root := i.rootFor(content)
+	destPath := filepath.Join(root, relPath)
 
 	// Validate path is within root (security check)
-	if err := ValidatePath(destPath, i.seriesRoot); err != nil {
+	if err := ValidatePath(destPath, root); err != nil {
 		i.log.Warn("path validation failed", "path", destPath, "error", err)
 		return EpisodeResult{
 			EpisodeID: episode.ID,
@@ -449,7 +992,10 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 	}
 
 	// Check if destination already exists (for resumable imports)
-	var size int64
+	var (
+		size     int64
+		checksum string
+	)
 	srcInfo, err := os.Stat(srcPath)
 	if err != nil {
 		i.log.Warn("failed to stat source file", "src", srcPath, "error", err)
@@ -463,15 +1009,19 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 	}
 
 	if destInfo, err := os.Stat(destPath); err == nil {
-		// Destination exists - check if it matches source size
-		if destInfo.Size() == srcInfo.Size() {
+		// Destination exists - check if it matches source
+		same := destInfo.Size() == srcInfo.Size()
+		if same {
+			same, checksum = i.sameContents(ctx, srcPath, destPath)
+		}
+		if same {
 			// File already imported, skip copy
 			size = destInfo.Size()
 			i.log.Info("episode file already exists, skipping copy",
 				"dest", destPath, "size", size, "season", season, "episode", epNum)
 		} else {
-			// Size mismatch - file is incomplete, remove and re-copy
-			i.log.Warn("destination file exists with wrong size, removing",
+			// Size or checksum mismatch - file is incomplete, remove and re-copy
+			i.log.Warn("destination file exists but does not match source, removing",
 				"dest", destPath, "expected", srcInfo.Size(), "actual", destInfo.Size())
 			if err := os.Remove(destPath); err != nil {
 				i.log.Warn("failed to remove incomplete file", "dest", destPath, "error", err)
@@ -484,7 +1034,7 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 				}
 			}
 			// Now copy the file
-			size, err = CopyFile(srcPath, destPath)
+			size, checksum, err = i.copyChecked(ctx, srcPath, destPath)
 			if err != nil {
 				i.log.Warn("failed to copy file", "src", srcPath, "dest", destPath, "error", err)
 				return EpisodeResult{
@@ -499,7 +1049,7 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 		}
 	} else {
 		// Destination doesn't exist - copy the file
-		size, err = CopyFile(srcPath, destPath)
+		size, checksum, err = i.copyChecked(ctx, srcPath, destPath)
 		if err != nil {
 			i.log.Warn("failed to copy file", "src", srcPath, "dest", destPath, "error", err)
 			return EpisodeResult{
@@ -513,6 +1063,14 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 		i.log.Debug("copied episode file", "src", srcPath, "dest", destPath, "size", size)
 	}
 
+	subtitles := i.copySubtitles(ctx, srcPath, &library.File{
+		ContentID:    content.ID,
+		EpisodeID:    &episode.ID,
+		Path:         destPath,
+		Source:       dl.Indexer,
+		ReleaseGroup: releaseGroup(dl, srcPath),
+	})
+
 	// Update database in transaction
 	tx, err := i.library.Begin()
 	if err != nil {
@@ -529,17 +1087,20 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 
 	// Insert file record (skip if already exists from previous import attempt)
 	file := &library.File{
-		ContentID: content.ID,
-		EpisodeID: &episode.ID,
-		Path:      destPath,
-		SizeBytes: size,
-		Quality:   quality,
-		Source:    dl.Indexer,
+		ContentID:    content.ID,
+		EpisodeID:    &episode.ID,
+		Path:         destPath,
+		SizeBytes:    size,
+		Quality:      quality,
+		Source:       dl.Indexer,
+		Checksum:     checksum,
+		ReleaseGroup: releaseGroup(dl, srcPath),
 	}
 	if err := tx.AddFile(file); err != nil {
 		if errors.Is(err, library.ErrDuplicate) {
 			// File record already exists - this is fine for resumable imports
 			i.log.Debug("file record already exists, skipping insert", "path", destPath)
+			file.ID = existingFileID(tx, episode.ID, destPath)
 		} else {
 			i.log.Warn("failed to add file record", "error", err)
 			return EpisodeResult{
@@ -551,6 +1112,7 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 			}
 		}
 	}
+	i.addSubtitles(tx, subtitles)
 
 	// Update episode status to available
 	episode.Status = library.StatusAvailable
@@ -584,9 +1146,16 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 		"quality":      quality,
 		"indexer":      dl.Indexer,
 		"release_name": dl.ReleaseName,
+		"download_id":  dl.ID,
 		"season":       season,
 		"episode":      epNum,
 	}
+	if checksum != "" {
+		historyMap["checksum"] = checksum
+	}
+	if len(subtitles) > 0 {
+		historyMap["subtitles"] = filePaths(subtitles)
+	}
 	historyData, _ := json.Marshal(historyMap)
 	_ = i.history.Add(&HistoryEntry{
 		ContentID: content.ID,
@@ -597,10 +1166,12 @@ func (i *Importer) importEpisodeFile(_ context.Context, dl *download.Download, c
 
 	return EpisodeResult{
 		EpisodeID: episode.ID,
+		FileID:    file.ID,
 		Season:    season,
 		Episode:   epNum,
 		Success:   true,
 		FilePath:  destPath,
 		SizeBytes: size,
+		Hook:      i.runPostImportHook(ctx, dl, content, episode, srcPath, destPath, quality, size),
 	}
 }
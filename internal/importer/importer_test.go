@@ -4,17 +4,23 @@ package importer
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
 	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/testutil"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
 )
 
 // testLogger returns a discard logger for tests.
@@ -25,7 +31,7 @@ func testLogger() *slog.Logger {
 func setupTestImporter(t *testing.T) (*Importer, *sql.DB, string, string) {
 	t.Helper()
 
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	downloadDir := t.TempDir()
 	movieRoot := t.TempDir()
 
@@ -45,14 +51,9 @@ func setupTestImporter(t *testing.T) (*Importer, *sql.DB, string, string) {
 
 func createTestDownload(t *testing.T, db *sql.DB, contentID int64, status download.Status) int64 {
 	t.Helper()
-	result, err := db.Exec(`
-		INSERT INTO downloads (content_id, client, client_id, status, release_name, indexer, added_at, last_transition_at)
-		VALUES (?, 'sabnzbd', 'nzo_test', ?, 'Test.Movie.2024.1080p.BluRay', 'TestIndexer', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
-		contentID, status,
-	)
-	require.NoError(t, err, "create download")
-	id, _ := result.LastInsertId()
-	return id
+	return fixtures.NewDownload().ForContent(contentID).WithStatus(status).
+		WithClient(download.ClientSABnzbd, "nzo_test").WithReleaseName("Test.Movie.2024.1080p.BluRay").
+		Insert(t, db).ID
 }
 
 func TestImporter_Import_Movie(t *testing.T) {
@@ -105,6 +106,230 @@ func TestImporter_Import_Movie(t *testing.T) {
 	assert.Equal(t, result.DestPath, filePath)
 }
 
+func TestImporter_Import_VerifyChecksum(t *testing.T) {
+	imp, db, downloadDir, _ := setupTestImporter(t)
+	imp.verify = true
+
+	contentID := insertTestContent(t, db)
+	downloadID := createTestDownload(t, db, contentID, download.StatusCompleted)
+
+	downloadPath := filepath.Join(downloadDir, "Test.Movie.2024.1080p.BluRay")
+	require.NoError(t, os.MkdirAll(downloadPath, 0755), "create download dir")
+	videoPath := filepath.Join(downloadPath, "test.movie.mkv")
+	require.NoError(t, os.WriteFile(videoPath, []byte("test video content"), 0644), "create video")
+
+	result, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err, "Import")
+
+	want, err := FileChecksum(context.Background(), videoPath)
+	require.NoError(t, err)
+	assert.Equal(t, want, result.Checksum)
+
+	var stored string
+	require.NoError(t, db.QueryRow("SELECT checksum FROM files WHERE id = ?", result.FileID).Scan(&stored), "query checksum")
+	assert.Equal(t, want, stored, "checksum should be stored on the file record")
+}
+
+func TestImporter_Import_ReleaseGroup(t *testing.T) {
+	// The download's group wins; a release without one takes the file's
+	tests := []struct{ name, release, file, want string }{
+		{"from release", "Test.Movie.2024.1080p.BluRay.x264-AMIABLE", "amiable-test.movie.mkv", "AMIABLE"},
+		{"from file", "Test.Movie.2024.1080p.WEB-DL", "Test.Movie.2024.1080p.WEB-DL.x264-FLUX.mkv", "FLUX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imp, db, downloadDir, _ := setupTestImporter(t)
+			contentID := insertTestContent(t, db)
+			downloadID := fixtures.NewDownload().ForContent(contentID).WithStatus(download.StatusCompleted).
+				WithReleaseName(tt.release).Insert(t, db).ID
+
+			downloadPath := filepath.Join(downloadDir, tt.release)
+			require.NoError(t, os.MkdirAll(downloadPath, 0755), "create download dir")
+			require.NoError(t, os.WriteFile(filepath.Join(downloadPath, tt.file), make([]byte, 1000), 0644), "create video")
+
+			result, err := imp.Import(context.Background(), downloadID, downloadPath)
+			require.NoError(t, err, "Import")
+
+			f, err := imp.library.GetFile(result.FileID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.ReleaseGroup)
+		})
+	}
+}
+
+func TestImporter_Import_MultiPart(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+
+	contentID := insertTestContent(t, db)
+	downloadID := createTestDownload(t, db, contentID, download.StatusCompleted)
+
+	// Classic CD1/CD2 layout with a sample folder
+	downloadPath := filepath.Join(downloadDir, "Test.Movie.2024.1080p.BluRay")
+	for name, size := range map[string]int{
+		"test.movie.cd1.avi":        900,
+		"test.movie.cd2.avi":        800,
+		"Sample/test.movie.cd1.avi": 100,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(downloadPath, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(downloadPath, name), make([]byte, size), 0644))
+	}
+
+	result, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err, "Import")
+
+	dir := filepath.Join(movieRoot, "Test Movie (2024)")
+	want := []string{
+		filepath.Join(dir, "Test Movie (2024) - 1080p - cd1.avi"),
+		filepath.Join(dir, "Test Movie (2024) - 1080p - cd2.avi"),
+	}
+	assert.Equal(t, want, result.Parts)
+	assert.Equal(t, want[0], result.DestPath)
+	assert.Equal(t, int64(1700), result.SizeBytes, "size covers every part")
+
+	files, _, err := imp.library.ListFiles(library.FileFilter{ContentID: &contentID})
+	require.NoError(t, err)
+	require.Len(t, files, 2, "one file record per part")
+	for n, f := range files {
+		assert.Equal(t, want[n], f.Path)
+		assert.Equal(t, n+1, f.PartNumber)
+		_, err := os.Stat(f.Path)
+		assert.NoError(t, err, "part copied")
+	}
+
+	entries, _, _ := imp.history.List(HistoryFilter{ContentID: &contentID})
+	require.Len(t, entries, 1, "one history entry for the movie")
+	assert.Contains(t, entries[0].Data, "cd2.avi")
+}
+
+func TestImporter_Import_Audiobook(t *testing.T) {
+	imp, db, downloadDir, _ := setupTestImporter(t)
+	imp.bookRoot = t.TempDir()
+
+	content := fixtures.NewAudiobook("Andy Weir", "Project Hail Mary", 2021).WithRootPath(imp.bookRoot).Insert(t, db)
+	downloadID := createTestDownload(t, db, content.ID, download.StatusCompleted)
+
+	downloadPath := filepath.Join(downloadDir, "Andy.Weir-Project.Hail.Mary")
+	require.NoError(t, os.MkdirAll(downloadPath, 0755))
+	for _, name := range []string{"Part 1.mp3", "Part 2.mp3", "cover.jpg"} {
+		require.NoError(t, os.WriteFile(filepath.Join(downloadPath, name), make([]byte, 500), 0644))
+	}
+
+	result, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err, "Import")
+
+	dir := filepath.Join(imp.bookRoot, "Andy Weir", "Project Hail Mary")
+	want := []string{
+		filepath.Join(dir, "Project Hail Mary - 01.mp3"),
+		filepath.Join(dir, "Project Hail Mary - 02.mp3"),
+	}
+	assert.Equal(t, want, result.Parts)
+	assert.Equal(t, int64(1000), result.SizeBytes)
+
+	files, _, err := imp.library.ListFiles(library.FileFilter{ContentID: &content.ID})
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "one file record per audio file")
+}
+
+func TestImporter_Import_AudiobookWithoutRoot(t *testing.T) {
+	imp, db, downloadDir, _ := setupTestImporter(t)
+
+	content := fixtures.NewAudiobook("Andy Weir", "Artemis", 2017).Insert(t, db)
+	downloadID := createTestDownload(t, db, content.ID, download.StatusCompleted)
+	downloadPath := filepath.Join(downloadDir, "Artemis")
+	require.NoError(t, os.MkdirAll(downloadPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(downloadPath, "Artemis.m4b"), make([]byte, 500), 0644))
+
+	_, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.Error(t, err, "audiobooks aren't imported without an audiobook library")
+}
+
+func TestImporter_Import_OversizedSample(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+
+	contentID := insertTestContent(t, db)
+	downloadID := createTestDownload(t, db, contentID, download.StatusCompleted)
+
+	// The sample is large enough to pass the size ratio and not named "sample"
+	downloadPath := filepath.Join(downloadDir, "Test.Movie.2024.1080p.BluRay")
+	require.NoError(t, os.MkdirAll(filepath.Join(downloadPath, "Sample"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(downloadPath, "Sample", "test.movie.mkv"), make([]byte, 800), 0644))
+	videoPath := filepath.Join(downloadPath, "test.movie.mkv")
+	require.NoError(t, os.WriteFile(videoPath, make([]byte, 1000), 0644))
+
+	result, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err, "Import")
+	assert.Equal(t, videoPath, result.SourcePath)
+	assert.Equal(t, filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 1080p.mkv"), result.DestPath)
+	assert.Empty(t, result.Parts)
+
+	var part sql.NullInt64
+	require.NoError(t, db.QueryRow("SELECT part_number FROM files WHERE id = ?", result.FileID).Scan(&part))
+	assert.False(t, part.Valid, "single file has no part number")
+}
+
+func TestImporter_Import_NeedsReview(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+
+	contentID := insertTestContent(t, db)
+	downloadID := createTestDownload(t, db, contentID, download.StatusCompleted)
+
+	// Two full-size videos without part markers: a guess could import the wrong cut
+	downloadPath := filepath.Join(downloadDir, "Test.Movie.2024.1080p.BluRay")
+	require.NoError(t, os.MkdirAll(downloadPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(downloadPath, "test.movie.theatrical.mkv"), make([]byte, 900), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(downloadPath, "test.movie.directors.cut.mkv"), make([]byte, 1000), 0644))
+
+	_, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.ErrorIs(t, err, ErrNeedsReview)
+	assert.Contains(t, err.Error(), "test.movie.directors.cut.mkv, test.movie.theatrical.mkv", "candidates are listed")
+
+	entries, err := os.ReadDir(movieRoot)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing is imported")
+}
+
+func TestImporter_Import_PostImportHook(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+
+	envFile := filepath.Join(t.TempDir(), "env")
+	script := writeHookScript(t, `env | grep ^ARRGO_ | sort > `+envFile+`; echo transcoded; exit 1`)
+	imp.postImport = NewHook(HookPostImport, script, time.Minute)
+
+	contentID := insertTestContent(t, db)
+	downloadID := createTestDownload(t, db, contentID, download.StatusCompleted)
+	downloadPath := filepath.Join(downloadDir, "Test.Movie.2024.1080p.BluRay")
+	require.NoError(t, os.MkdirAll(downloadPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(downloadPath, "test.movie.mkv"), make([]byte, 1000), 0644))
+
+	// A failing hook does not fail the import
+	result, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err)
+	require.NotNil(t, result.Hook)
+	assert.True(t, result.Hook.Failed())
+	assert.Equal(t, 1, result.Hook.ExitCode)
+	assert.Equal(t, filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 1080p.mkv"), result.DestPath)
+
+	env, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(env), "ARRGO_EVENT=post_import\n")
+	assert.Contains(t, string(env), "ARRGO_CONTENT_TITLE=Test Movie\n")
+	assert.Contains(t, string(env), "ARRGO_FILE_PATH="+result.DestPath+"\n")
+	assert.Contains(t, string(env), "ARRGO_QUALITY=1080p\n")
+	assert.Contains(t, string(env), fmt.Sprintf("ARRGO_DOWNLOAD_ID=%d\n", downloadID))
+
+	// The hook's outcome and output are kept in history
+	hookEvent := EventHook
+	entries, _, err := imp.history.List(HistoryFilter{ContentID: &contentID, Event: &hookEvent})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	var data map[string]any
+	require.NoError(t, json.Unmarshal([]byte(entries[0].Data), &data))
+	assert.Equal(t, "post_import", data["hook"])
+	assert.InDelta(t, 1, data["exit_code"], 0)
+	assert.Equal(t, "transcoded\n", data["stdout"])
+	assert.Contains(t, data["error"], "exited with status 1")
+}
+
 func TestImporter_Import_DownloadNotFound(t *testing.T) {
 	imp, _, _, _ := setupTestImporter(t)
 
@@ -150,10 +375,7 @@ func TestImporter_Import_PathTraversal(t *testing.T) {
 		imp, db, downloadDir, movieRoot := setupTestImporter(t)
 
 		// Create content with malicious title - sanitization should handle it
-		result, _ := db.Exec(`
-			INSERT INTO content (type, title, year, status, quality_profile, root_path)
-			VALUES ('movie', '../../../etc/passwd', 2024, 'wanted', 'hd', '/movies')`)
-		contentID, _ := result.LastInsertId()
+		contentID := fixtures.NewMovie("../../../etc/passwd", 2024).Insert(t, db).ID
 
 		downloadID := createTestDownload(t, db, contentID, download.StatusCompleted)
 
@@ -195,43 +417,176 @@ func TestImporter_Import_DestinationExists(t *testing.T) {
 	assert.ErrorIs(t, err, ErrDestinationExists)
 }
 
+// setupExistingFile writes a library file on disk and records it.
+func setupExistingFile(t *testing.T, db *sql.DB, contentID int64, path, quality, data string) *library.File {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "create existing dir")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644), "create existing file")
+	return fixtures.NewFile(contentID, path).WithQuality(quality).WithSize(int64(len(data))).Insert(t, db)
+}
+
+// setupMovieDownload creates a completed movie download of one video.
+func setupMovieDownload(t *testing.T, db *sql.DB, downloadDir string, contentID int64, releaseName string) (int64, string) {
+	t.Helper()
+	id := fixtures.NewDownload().ForContent(contentID).WithStatus(download.StatusCompleted).
+		WithClient(download.ClientSABnzbd, "nzo_test").WithReleaseName(releaseName).
+		Insert(t, db).ID
+	downloadPath := filepath.Join(downloadDir, releaseName)
+	require.NoError(t, os.MkdirAll(downloadPath, 0755), "create download dir")
+	require.NoError(t, os.WriteFile(filepath.Join(downloadPath, "movie.mkv"), []byte("new"), 0644), "create video")
+	return id, downloadPath
+}
+
+func TestImporter_Import_DowngradeBlocked(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+	contentID := insertTestContent(t, db)
+	existing := setupExistingFile(t, db, contentID,
+		filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 2160p.mkv"), "2160p", "old")
+	downloadID, downloadPath := setupMovieDownload(t, db, downloadDir, contentID, "Test.Movie.2024.1080p.BluRay")
+
+	_, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.ErrorIs(t, err, ErrImportWouldDowngrade)
+	assert.Contains(t, err.Error(), "2160p")
+	_, statErr := os.Stat(filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 1080p.mkv"))
+	assert.True(t, os.IsNotExist(statErr), "nothing copied")
+
+	result, err := imp.ImportWithOptions(context.Background(), downloadID, downloadPath, ImportOptions{AllowDowngrade: true})
+	require.NoError(t, err, "allow_downgrade overrides")
+	files, _, err := imp.library.ListFiles(library.FileFilter{ContentID: &contentID})
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "a downgrade keeps the better file")
+	assert.FileExists(t, existing.Path)
+	assert.FileExists(t, result.DestPath)
+}
+
+func TestImporter_Import_DowngradeBySource(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+	contentID := insertTestContent(t, db)
+	setupExistingFile(t, db, contentID,
+		filepath.Join(movieRoot, "Test Movie (2024)", "Test.Movie.2024.1080p.BluRay.mkv"), "1080p", "old")
+	downloadID, downloadPath := setupMovieDownload(t, db, downloadDir, contentID, "Test.Movie.2024.1080p.HDTV")
+
+	_, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.ErrorIs(t, err, ErrImportWouldDowngrade, "HDTV is below BluRay at the same resolution")
+}
+
+func TestImporter_Import_EqualQualityReplaces(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+	contentID := insertTestContent(t, db)
+	dest := filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 1080p.mkv")
+	sameName := setupExistingFile(t, db, contentID, dest, "1080p", "old")
+	otherName := setupExistingFile(t, db, contentID,
+		filepath.Join(movieRoot, "Test Movie (2024)", "Test.Movie.2024.1080p.mkv"), "1080p", "older")
+	downloadID, downloadPath := setupMovieDownload(t, db, downloadDir, contentID, "Test.Movie.2024.1080p.BluRay")
+
+	result, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err, "Import")
+	assert.Equal(t, dest, result.DestPath)
+
+	data, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data), "replacement written over the old file")
+	assert.NoFileExists(t, dest+stagingSuffix)
+	assert.NoFileExists(t, otherName.Path, "replaced file removed")
+
+	files, _, err := imp.library.ListFiles(library.FileFilter{ContentID: &contentID})
+	require.NoError(t, err)
+	require.Len(t, files, 1, "a replacement is not a duplicate")
+	assert.Equal(t, result.FileID, files[0].ID)
+	assert.NotEqual(t, sameName.ID, files[0].ID)
+
+	entries, _, _ := imp.history.List(HistoryFilter{ContentID: &contentID})
+	require.Len(t, entries, 1)
+	assert.Contains(t, entries[0].Data, "Test.Movie.2024.1080p.mkv")
+}
+
+func TestImporter_Import_Subtitles(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+	imp.subtitles = true
+	imp.subLanguages = map[string]bool{"en": true, "fr": true}
+	contentID := insertTestContent(t, db)
+	dir := filepath.Join(movieRoot, "Test Movie (2024)")
+	dest := filepath.Join(dir, "Test Movie (2024) - 1080p.mkv")
+	setupExistingFile(t, db, contentID, dest, "1080p", "old")
+	oldSub := filepath.Join(dir, "Test Movie (2024) - 1080p.de.srt")
+	require.NoError(t, os.WriteFile(oldSub, []byte("alt"), 0644))
+	fixtures.NewFile(contentID, oldSub).Insert(t, db)
+	_, err := db.Exec("UPDATE files SET kind = 'subtitle' WHERE path = ?", oldSub)
+	require.NoError(t, err)
+
+	downloadID, downloadPath := setupMovieDownload(t, db, downloadDir, contentID, "Test.Movie.2024.1080p.BluRay")
+	subs := filepath.Join(downloadPath, "Subs")
+	require.NoError(t, os.MkdirAll(subs, 0755))
+	for name, data := range map[string]string{
+		"movie.en.srt":          "english",
+		"2_English.srt":         "english again",
+		"3_English.SDH.srt":     "english sdh",
+		"4_French.forced.ass":   "french forced",
+		"5_Spanish.srt":         "spanish",
+		"Test.Movie.nfo":        "info",
+		"Test.Movie.Sample.mkv": "sample",
+	} {
+		dir := subs
+		if strings.HasPrefix(name, "movie.") || strings.HasPrefix(name, "Test.") {
+			dir = downloadPath
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+
+	result, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err, "Import")
+	base := strings.TrimSuffix(dest, ".mkv")
+	assert.Equal(t, []string{base + ".en.srt", base + ".en.sdh.srt", base + ".fr.forced.ass"}, result.Subtitles,
+		"in source path order, the first of a language wins, other languages are skipped")
+	data, err := os.ReadFile(base + ".en.srt")
+	require.NoError(t, err)
+	assert.Equal(t, "english again", string(data))
+	assert.NoFileExists(t, base+".es.srt")
+	assert.NoFileExists(t, oldSub, "subtitles of the replaced file go with it")
+
+	files, _, err := imp.library.ListFiles(library.FileFilter{ContentID: &contentID, Kinds: []library.FileKind{library.FileKindSubtitle}})
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	media, _, err := imp.library.ListFiles(library.FileFilter{ContentID: &contentID})
+	require.NoError(t, err)
+	require.Len(t, media, 1, "subtitles aren't media files")
+}
+
+func TestImporter_Import_Upgrade(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+	contentID := insertTestContent(t, db)
+	existing := setupExistingFile(t, db, contentID,
+		filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 720p.mkv"), "720p", "old")
+	downloadID, downloadPath := setupMovieDownload(t, db, downloadDir, contentID, "Test.Movie.2024.1080p.BluRay")
+
+	result, err := imp.Import(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err, "Import")
+	assert.FileExists(t, result.DestPath)
+	assert.FileExists(t, existing.Path, "upgrades leave older files to the upgrade flow")
+
+	files, _, err := imp.library.ListFiles(library.FileFilter{ContentID: &contentID})
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
+
 // Helper to create series content
 func insertTestSeries(t *testing.T, db *sql.DB, title string) int64 {
 	t.Helper()
-	result, err := db.Exec(`
-		INSERT INTO content (type, title, year, status, quality_profile, root_path)
-		VALUES ('series', ?, 2024, 'wanted', 'hd', '/tv')`,
-		title,
-	)
-	require.NoError(t, err, "insert test series")
-	id, _ := result.LastInsertId()
-	return id
+	return fixtures.NewSeries(title, 2024).Insert(t, db).ID
 }
 
 // Helper to create episode
 func insertTestEpisode(t *testing.T, db *sql.DB, contentID int64, season, episode int) int64 {
 	t.Helper()
-	result, err := db.Exec(`
-		INSERT INTO episodes (content_id, season, episode, title, status)
-		VALUES (?, ?, ?, 'Test Episode', 'wanted')`,
-		contentID, season, episode,
-	)
-	require.NoError(t, err, "insert test episode")
-	id, _ := result.LastInsertId()
-	return id
+	return fixtures.NewEpisode(contentID, season, episode).WithTitle("Test Episode").Insert(t, db).ID
 }
 
 // Helper to create download with episode ID
 func createTestEpisodeDownload(t *testing.T, db *sql.DB, contentID, episodeID int64, status download.Status) int64 {
 	t.Helper()
-	result, err := db.Exec(`
-		INSERT INTO downloads (content_id, episode_id, client, client_id, status, release_name, indexer, added_at, last_transition_at)
-		VALUES (?, ?, 'sabnzbd', 'nzo_test', ?, 'Test.Show.S01E05.1080p.WEB', 'TestIndexer', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
-		contentID, episodeID, status,
-	)
-	require.NoError(t, err, "create episode download")
-	id, _ := result.LastInsertId()
-	return id
+	return fixtures.NewDownload().ForContent(contentID).ForEpisodes(episodeID).WithStatus(status).
+		WithClient(download.ClientSABnzbd, "nzo_test").WithReleaseName("Test.Show.S01E05.1080p.WEB").
+		Insert(t, db).ID
 }
 
 func TestImporter_Import_Episode(t *testing.T) {
@@ -293,20 +648,15 @@ func TestImporter_Import_Episode_NoEpisodeID(t *testing.T) {
 	seriesID := insertTestSeries(t, db, "Test Show")
 
 	// Create download WITHOUT episode ID
-	result, err := db.Exec(`
-		INSERT INTO downloads (content_id, client, client_id, status, release_name, indexer, added_at, last_transition_at)
-		VALUES (?, 'sabnzbd', 'nzo_test', 'completed', 'Test.Show.S01E05.1080p', 'Indexer', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
-		seriesID,
-	)
-	require.NoError(t, err, "create download")
-	downloadID, _ := result.LastInsertId()
+	downloadID := fixtures.NewDownload().ForContent(seriesID).WithStatus(download.StatusCompleted).
+		WithReleaseName("Test.Show.S01E05.1080p").Insert(t, db).ID
 
 	// Create download directory with video
 	downloadPath := filepath.Join(downloadDir, "download")
 	require.NoError(t, os.MkdirAll(downloadPath, 0755), "create download dir")
 	require.NoError(t, os.WriteFile(filepath.Join(downloadPath, "episode.mkv"), make([]byte, 100), 0644), "create video")
 
-	_, err = imp.Import(context.Background(), downloadID, downloadPath)
+	_, err := imp.Import(context.Background(), downloadID, downloadPath)
 	assert.ErrorIs(t, err, ErrEpisodeNotSpecified)
 }
 
@@ -338,3 +688,202 @@ func TestImporter_Import_Episode_EpisodeNotFound(t *testing.T) {
 	require.Error(t, err, "expected error for non-existent episode")
 	assert.Contains(t, err.Error(), "get episode", "expected 'get episode' error")
 }
+
+func TestImporter_Import_MultipleRoots(t *testing.T) {
+	imp, db, downloadDir, movieRoot := setupTestImporter(t)
+	movieRoot2 := t.TempDir()
+	imp.movieRoots = []string{movieRoot, movieRoot2}
+
+	tests := []struct {
+		name     string
+		rootPath string
+		wantRoot string
+	}{
+		{"stored on second root", movieRoot2, movieRoot2},
+		{"stored on primary root", movieRoot, movieRoot},
+		{"stored root not configured", "/somewhere/else", movieRoot},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title := fmt.Sprintf("Movie %d", i)
+			contentID := fixtures.NewMovie(title, 2024).WithRootPath(tt.rootPath).Insert(t, db).ID
+			downloadID := createTestDownload(t, db, contentID, download.StatusCompleted)
+
+			downloadPath := filepath.Join(downloadDir, title)
+			require.NoError(t, os.MkdirAll(downloadPath, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(downloadPath, "movie.mkv"), make([]byte, 1000), 0644))
+
+			importResult, err := imp.Import(context.Background(), downloadID, downloadPath)
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(tt.wantRoot, title+" (2024)", title+" (2024) - 1080p.mkv"), importResult.DestPath)
+			assert.FileExists(t, importResult.DestPath)
+		})
+	}
+}
+
+func TestImporter_ImportSeasonPack_StoredRoot(t *testing.T) {
+	imp, db, downloadDir, _ := setupTestImporter(t)
+	seriesRoot2 := t.TempDir()
+	imp.seriesRoots = []string{imp.seriesRoot, seriesRoot2}
+
+	contentID := fixtures.NewSeries("Test Show", 2024).WithRootPath(seriesRoot2).Insert(t, db).ID
+	downloadID := createTestDownload(t, db, contentID, download.StatusCompleted)
+
+	downloadPath := filepath.Join(downloadDir, "Test.Show.S01.1080p")
+	require.NoError(t, os.MkdirAll(downloadPath, 0755))
+	for _, name := range []string{"Test.Show.S01E01.mkv", "Test.Show.S01E02.mkv"} {
+		require.NoError(t, os.WriteFile(filepath.Join(downloadPath, name), make([]byte, 1000), 0644))
+	}
+
+	packResult, err := imp.ImportSeasonPack(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err)
+	require.Len(t, packResult.Episodes, 2)
+	for _, ep := range packResult.Episodes {
+		require.True(t, ep.Success, "episode %d: %v", ep.Episode, ep.Error)
+		assert.True(t, strings.HasPrefix(ep.FilePath, seriesRoot2), "FilePath %q should be under %q", ep.FilePath, seriesRoot2)
+	}
+}
+
+func TestImporter_ImportSeasonPack_PartialThenReimport(t *testing.T) {
+	imp, db, downloadDir, _ := setupTestImporter(t)
+
+	contentID := fixtures.NewSeries("Test Show", 2024).WithRootPath(imp.seriesRoot).Insert(t, db).ID
+	downloadID := createTestDownload(t, db, contentID, download.StatusImporting)
+
+	downloadPath := filepath.Join(downloadDir, "Test.Show.S01.1080p")
+	require.NoError(t, os.MkdirAll(downloadPath, 0755))
+	for _, name := range []string{"Test.Show.S01E01.mkv", "Test.Show.S01E02.mkv", "Test.Show.Bonus.mkv"} {
+		require.NoError(t, os.WriteFile(filepath.Join(downloadPath, name), make([]byte, 1000), 0644))
+	}
+
+	packResult, err := imp.ImportSeasonPack(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err)
+	require.Len(t, packResult.Episodes, 3)
+	assert.Equal(t, 2, packResult.SuccessCount())
+	assert.Equal(t, download.StatusPartiallyImported, packResult.DownloadStatus())
+
+	// The outcome of each file is recorded on the download
+	recorded, err := imp.downloads.EpisodeResults(downloadID)
+	require.NoError(t, err)
+	require.Len(t, recorded, 3)
+	assert.Equal(t, "Test.Show.Bonus.mkv", recorded[0].SourceFile)
+	assert.False(t, recorded[0].Success)
+	assert.NotEmpty(t, recorded[0].Error)
+	assert.True(t, recorded[1].Success)
+	assert.True(t, recorded[2].Success)
+
+	// Fix the misnamed file and import again: only it is imported
+	require.NoError(t, os.Rename(filepath.Join(downloadPath, "Test.Show.Bonus.mkv"),
+		filepath.Join(downloadPath, "Test.Show.S01E03.mkv")))
+	packResult, err = imp.ImportSeasonPack(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err)
+	require.Len(t, packResult.Episodes, 3)
+	assert.Equal(t, download.StatusImported, packResult.DownloadStatus())
+	for _, ep := range packResult.Episodes {
+		require.True(t, ep.Success, "episode %d: %v", ep.Episode, ep.Error)
+		assert.Equal(t, ep.Episode != 3, ep.AlreadyImported, "episode %d", ep.Episode)
+	}
+	assert.Equal(t, int64(1000), packResult.TotalSize)
+
+	var available int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM episodes WHERE content_id = ? AND status = 'available'`, contentID).Scan(&available))
+	assert.Equal(t, 3, available)
+}
+
+func TestImporter_ImportSeasonPack_EpisodeFiles(t *testing.T) {
+	imp, db, downloadDir, _ := setupTestImporter(t)
+
+	contentID := fixtures.NewSeries("Test Show", 2024).WithRootPath(imp.seriesRoot).Insert(t, db).ID
+	downloadID := createTestDownload(t, db, contentID, download.StatusImporting)
+
+	downloadPath := filepath.Join(downloadDir, "Test.Show.S01.1080p")
+	require.NoError(t, os.MkdirAll(downloadPath, 0755))
+	for _, name := range []string{"Test.Show.S01E01E02.mkv", "Test.Show.S01E03.mkv", "Test.Show.Bonus.mkv"} {
+		require.NoError(t, os.WriteFile(filepath.Join(downloadPath, name), make([]byte, 1000), 0644))
+	}
+
+	packResult, err := imp.ImportSeasonPack(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err)
+	require.Len(t, packResult.Episodes, 3)
+	assert.Zero(t, packResult.Episodes[0].FileID, "the unmatched file has no record")
+
+	// Every file record is linked to an episode; the multi-episode file to its first
+	files, _, err := imp.library.ListFiles(library.FileFilter{ContentID: &contentID})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	byID := make(map[int64]*library.File)
+	for _, f := range files {
+		require.NotNil(t, f.EpisodeID, f.Path)
+		byID[f.ID] = f
+	}
+	for _, ep := range packResult.Episodes[1:] {
+		require.True(t, ep.Success, "episode %d: %v", ep.Episode, ep.Error)
+		f := byID[ep.FileID]
+		require.NotNil(t, f, "episode %d file %d", ep.Episode, ep.FileID)
+		assert.Equal(t, ep.EpisodeID, *f.EpisodeID)
+		assert.Equal(t, ep.FilePath, f.Path)
+	}
+	assert.Equal(t, []int{1, 3}, []int{packResult.Episodes[1].Episode, packResult.Episodes[2].Episode})
+
+	// Files imported by an earlier attempt keep their file IDs
+	again, err := imp.ImportSeasonPack(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err)
+	for n, ep := range again.Episodes[1:] {
+		assert.True(t, ep.AlreadyImported)
+		assert.Equal(t, packResult.Episodes[n+1].FileID, ep.FileID)
+	}
+}
+
+func TestImporter_ImportSeasonPack_Concurrent(t *testing.T) {
+	imp, db, downloadDir, _ := setupTestImporter(t)
+	db.SetMaxOpenConns(1) // Each :memory: connection is its own database
+	imp.packWorkers = 3
+	bus := &recordingBus{}
+	imp.SetEvents(bus)
+
+	contentID := fixtures.NewSeries("Test Show", 2024).WithRootPath(imp.seriesRoot).Insert(t, db).ID
+	downloadID := createTestDownload(t, db, contentID, download.StatusCompleted)
+
+	downloadPath := filepath.Join(downloadDir, "Test.Show.S01.1080p")
+	require.NoError(t, os.MkdirAll(downloadPath, 0755))
+	names := []string{"Test.Show.S01E05.mkv", "Test.Show.S01E02.mkv", "Test.Show.Bonus.mkv", "Test.Show.S01E04.mkv", "Test.Show.S01E01.mkv", "Test.Show.S01E03.mkv"}
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(downloadPath, name), make([]byte, 1000), 0644))
+	}
+
+	packResult, err := imp.ImportSeasonPack(context.Background(), downloadID, downloadPath)
+	require.NoError(t, err)
+	require.Len(t, packResult.Episodes, 6)
+	assert.Equal(t, 5, packResult.SuccessCount())
+	assert.Equal(t, int64(5000), packResult.TotalSize)
+
+	// The unmatched file fails alone; results are in episode order
+	assert.Equal(t, "Test.Show.Bonus.mkv", packResult.Episodes[0].SourceFile)
+	assert.False(t, packResult.Episodes[0].Success)
+	for n, ep := range packResult.Episodes[1:] {
+		require.True(t, ep.Success, "episode %d: %v", ep.Episode, ep.Error)
+		assert.Equal(t, n+1, ep.Episode)
+	}
+
+	// Each file reports its start and its outcome
+	started := make(map[string]bool)
+	done := make(map[string]*events.ImportProgress)
+	for _, e := range bus.events {
+		p, ok := e.(*events.ImportProgress)
+		require.True(t, ok, "unexpected event %s", e.EventType())
+		assert.Equal(t, downloadID, p.DownloadID)
+		assert.Equal(t, int64(1000), p.TotalBytes)
+		if p.Done {
+			done[p.SourceFile] = p
+		} else {
+			started[p.SourceFile] = true
+		}
+	}
+	require.Len(t, done, 6)
+	assert.Len(t, started, 6)
+	assert.NotEmpty(t, done["Test.Show.Bonus.mkv"].Error)
+	ok := done["Test.Show.S01E03.mkv"]
+	assert.Empty(t, ok.Error)
+	assert.Equal(t, 3, ok.Episode)
+	assert.Equal(t, int64(1000), ok.BytesCopied)
+}
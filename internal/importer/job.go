@@ -28,4 +28,27 @@ type ImportJob struct {
 
 	// RootPath is the library root directory.
 	RootPath string
+
+	// Parts lists every file of a multi-part movie in part order; the first
+	// is also in SourcePath and DestPath. Empty for a single-file import.
+	Parts []ImportPart
+
+	// Replaces lists existing files of equal quality that the import
+	// replaces; their records and files are removed once it is recorded.
+	Replaces []*library.File
+}
+
+// ImportPart is one file of a multi-part movie import.
+type ImportPart struct {
+	// Part is the part number (CD1 = 1).
+	Part int
+
+	// SourcePath is the path to the part's video file.
+	SourcePath string
+
+	// DestPath is the full destination path for the part.
+	DestPath string
+
+	// SizeBytes is the size of the part's video file.
+	SizeBytes int64
 }
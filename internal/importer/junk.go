@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultJunkPatterns are the names left behind in release directories that
+// are safe to delete once the media is gone: scene info, checksums, repair
+// sets, and sample and proof directories.
+func DefaultJunkPatterns() []string {
+	return []string{"*.nfo", "*.sfv", "*.srr", "*.srs", "*.par2", "*.nzb", "*.url", "sample", "proof"}
+}
+
+// DefaultJunkTextMaxSize is the largest .txt file counted as junk by default.
+const DefaultJunkTextMaxSize = 64 << 10
+
+// JunkRules decide which leftovers in a release directory are junk.
+type JunkRules struct {
+	// Patterns are globs matched against lowercase file and directory names.
+	// A matching directory is junk with everything in it.
+	Patterns []string
+	// TextMaxSize is the largest .txt file that is junk (0: none are).
+	TextMaxSize int64
+}
+
+// DefaultJunkRules returns the default junk rules.
+func DefaultJunkRules() JunkRules {
+	return JunkRules{Patterns: DefaultJunkPatterns(), TextMaxSize: DefaultJunkTextMaxSize}
+}
+
+// errNotJunk stops a walk at the first file that isn't junk.
+var errNotJunk = errors.New("not junk")
+
+// matches reports whether a file or directory name matches a junk pattern.
+func (r JunkRules) matches(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range r.Patterns {
+		if ok, _ := filepath.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isJunkFile reports whether a file outside junk directories is junk.
+// Video and audio files never are.
+func (r JunkRules) isJunkFile(path string, size int64) bool {
+	if IsVideoFile(path) || IsAudioFile(path) {
+		return false
+	}
+	if r.matches(filepath.Base(path)) {
+		return true
+	}
+	return strings.EqualFold(filepath.Ext(path), ".txt") && size <= r.TextMaxSize
+}
+
+// RemoveJunk removes dir if everything left in it is junk, and returns the
+// files and directories removed, dir last. A dir holding anything else, such
+// as a video outside a sample directory, is left alone and nil is returned,
+// as it is if dir doesn't exist or isn't a directory.
+func RemoveJunk(dir string, rules JunkRules) ([]string, error) {
+	info, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, nil
+	}
+
+	var removed []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if d.IsDir() {
+			if rules.matches(d.Name()) {
+				// Junk directory: everything in it goes, including sample videos
+				if err := filepath.WalkDir(path, func(p string, _ fs.DirEntry, err error) error {
+					if err != nil {
+						return err
+					}
+					removed = append(removed, p)
+					return nil
+				}); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+			removed = append(removed, path)
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || !rules.isJunkFile(path, info.Size()) {
+			return errNotJunk
+		}
+		removed = append(removed, path)
+		return nil
+	})
+	if errors.Is(err, errNotJunk) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", dir, err)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("remove %s: %w", dir, err)
+	}
+	return append(removed, dir), nil
+}
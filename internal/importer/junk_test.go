@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeJunkTree(t *testing.T, dir string, files map[string]int) {
+	t.Helper()
+	for name, size := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644))
+	}
+}
+
+func TestRemoveJunk(t *testing.T) {
+	rules := DefaultJunkRules()
+
+	t.Run("only junk", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "Movie.2024.1080p")
+		writeJunkTree(t, dir, map[string]int{
+			"movie.nfo":                 10,
+			"movie.SFV":                 10,
+			"movie.vol00+01.par2":       10,
+			"readme.txt":                100,
+			"Sample/movie-sample.mkv":   1000,
+			"Subs/empty-dir-leftover/x": 0,
+		})
+		require.NoError(t, os.Remove(filepath.Join(dir, "Subs/empty-dir-leftover/x")))
+
+		removed, err := RemoveJunk(dir, rules)
+		require.NoError(t, err)
+		assert.NoDirExists(t, dir)
+		assert.Contains(t, removed, filepath.Join(dir, "movie.nfo"))
+		assert.Contains(t, removed, filepath.Join(dir, "Sample", "movie-sample.mkv"), "sample videos are junk")
+		assert.Contains(t, removed, filepath.Join(dir, "Subs", "empty-dir-leftover"))
+		assert.Equal(t, dir, removed[len(removed)-1])
+	})
+
+	t.Run("video kept", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "Movie.2024.1080p")
+		writeJunkTree(t, dir, map[string]int{"movie.nfo": 10, "movie.mkv": 1000})
+
+		removed, err := RemoveJunk(dir, rules)
+		require.NoError(t, err)
+		assert.Nil(t, removed)
+		assert.FileExists(t, filepath.Join(dir, "movie.nfo"), "nothing is removed")
+	})
+
+	t.Run("large text kept", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "Movie.2024.1080p")
+		writeJunkTree(t, dir, map[string]int{"notes.txt": 200})
+
+		removed, err := RemoveJunk(dir, JunkRules{Patterns: rules.Patterns, TextMaxSize: 100})
+		require.NoError(t, err)
+		assert.Nil(t, removed)
+		assert.DirExists(t, dir)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		removed, err := RemoveJunk(filepath.Join(t.TempDir(), "gone"), rules)
+		require.NoError(t, err)
+		assert.Nil(t, removed)
+	})
+}
@@ -0,0 +1,126 @@
+// internal/importer/locate.go
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+)
+
+const (
+	// locateSizeTolerance is how far a candidate's video size may be from the
+	// grab's size, as a fraction of the grab's size.
+	locateSizeTolerance = 0.1
+	// locateClockSlack allows for clock skew and clients that keep the original
+	// modification time when moving a completed job.
+	locateClockSlack = time.Hour
+)
+
+// LocateDownload returns where a completed download's files are. The first of
+// paths that exists wins; list the path reported by the download client, which
+// follows jobs it renamed, then the expected path. If none exist, as when an obfuscated NZB unpacks into a random
+// folder name, root and its category subdirectory are scanned for a directory
+// modified since the grab that holds a video whose parsed title matches the
+// content and, when the grab's size is known, whose size is within 10% of it.
+// Exactly one directory must qualify, otherwise ErrNoConfidentMatch is returned.
+func LocateDownload(root string, dl *download.Download, content *library.Content, paths ...string) (string, error) {
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	if root == "" {
+		return "", fmt.Errorf("%w: download root not configured", ErrNoConfidentMatch)
+	}
+
+	dirs := []string{root}
+	if dl.Category != "" {
+		dirs = append(dirs, filepath.Join(root, dl.Category))
+	}
+	since := dl.AddedAt.Add(-locateClockSlack)
+
+	var matches []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || (dl.Category != "" && dir == root && entry.Name() == dl.Category) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || info.ModTime().Before(since) {
+				continue
+			}
+			candidate := filepath.Join(dir, entry.Name())
+			if matchesDownload(candidate, dl, content) {
+				matches = append(matches, candidate)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return "", fmt.Errorf("%w: nothing under %s matches %q", ErrNoConfidentMatch, root, content.Title)
+	default:
+		return "", fmt.Errorf("%w: %d directories match %q", ErrNoConfidentMatch, len(matches), content.Title)
+	}
+}
+
+// matchesDownload reports whether dir holds the download: its name or one of its
+// videos parses to the content's title, and its videos add up to the grab's size.
+func matchesDownload(dir string, dl *download.Download, content *library.Content) bool {
+	videos, err := FindAllVideos(dir)
+	if err != nil || len(videos) == 0 {
+		return false
+	}
+
+	var size int64
+	for _, v := range videos {
+		if info, err := os.Stat(v); err == nil {
+			size += info.Size()
+		}
+	}
+	if dl.Size > 0 {
+		diff := float64(size - dl.Size)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > float64(dl.Size)*locateSizeTolerance {
+			return false
+		}
+	}
+
+	names := []string{filepath.Base(dir)}
+	for _, v := range videos {
+		names = append(names, filepath.Base(v))
+	}
+	for _, name := range names {
+		if titleMatchesContent(release.Parse(name), content) {
+			return true
+		}
+	}
+	return false
+}
+
+// titleMatchesContent reports whether a parsed release confidently names the content.
+func titleMatchesContent(info *release.Info, content *library.Content) bool {
+	if info.Title == "" {
+		return false
+	}
+	if info.Year > 0 && content.Year > 0 && info.Year != content.Year {
+		return false
+	}
+	return release.MatchTitle(info.Title, []string{content.Title}).Confidence == release.ConfidenceHigh
+}
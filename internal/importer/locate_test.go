@@ -0,0 +1,113 @@
+// internal/importer/locate_test.go
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// writeRelease creates dir/name holding a video of the given size.
+func writeRelease(t *testing.T, dir, name, video string, size int) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(path, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, video), make([]byte, size), 0644))
+	return path
+}
+
+func TestLocateDownload(t *testing.T) {
+	content := &library.Content{Title: "The Matrix", Year: 1999}
+	newDownload := func() *download.Download {
+		return &download.Download{
+			ReleaseName: "The.Matrix.1999.1080p.BluRay.x264-GROUP",
+			Category:    "movies",
+			Size:        1000,
+			AddedAt:     time.Now(),
+		}
+	}
+
+	t.Run("expected path exists", func(t *testing.T) {
+		root := t.TempDir()
+		expected := writeRelease(t, root, "The.Matrix.1999.1080p.BluRay.x264-GROUP", "movie.mkv", 1000)
+
+		got, err := LocateDownload(root, newDownload(), content, "", expected)
+		require.NoError(t, err)
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("obfuscated folder found by video name and size", func(t *testing.T) {
+		root := t.TempDir()
+		writeRelease(t, root, "unrelated", "Other.Movie.2010.1080p.mkv", 1000)
+		want := writeRelease(t, filepath.Join(root, "movies"), "a8f3c9e1d2b7", "The.Matrix.1999.1080p.BluRay.x264-GROUP.mkv", 950)
+
+		got, err := LocateDownload(root, newDownload(), content, filepath.Join(root, "movies", "The.Matrix.1999.1080p.BluRay.x264-GROUP"))
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("renamed folder found by folder name", func(t *testing.T) {
+		root := t.TempDir()
+		want := writeRelease(t, root, "The Matrix (1999)", "abc123.mkv", 1000)
+
+		got, err := LocateDownload(root, newDownload(), content)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("size mismatch", func(t *testing.T) {
+		root := t.TempDir()
+		writeRelease(t, root, "a8f3c9e1d2b7", "The.Matrix.1999.1080p.BluRay.x264-GROUP.mkv", 500)
+
+		_, err := LocateDownload(root, newDownload(), content)
+		assert.ErrorIs(t, err, ErrNoConfidentMatch)
+	})
+
+	t.Run("wrong year", func(t *testing.T) {
+		root := t.TempDir()
+		writeRelease(t, root, "a8f3c9e1d2b7", "The.Matrix.2021.1080p.BluRay.x264-GROUP.mkv", 1000)
+
+		_, err := LocateDownload(root, newDownload(), content)
+		assert.ErrorIs(t, err, ErrNoConfidentMatch)
+	})
+
+	t.Run("no title match", func(t *testing.T) {
+		root := t.TempDir()
+		writeRelease(t, root, "a8f3c9e1d2b7", "f0e1d2c3b4a5.mkv", 1000)
+
+		_, err := LocateDownload(root, newDownload(), content)
+		assert.ErrorIs(t, err, ErrNoConfidentMatch)
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		root := t.TempDir()
+		writeRelease(t, root, "a8f3c9e1d2b7", "The.Matrix.1999.1080p.BluRay.x264-GROUP.mkv", 1000)
+		writeRelease(t, root, "0d9e8f7a6b5c", "The.Matrix.1999.720p.WEB-DL-OTHER.mkv", 1000)
+
+		_, err := LocateDownload(root, newDownload(), content)
+		assert.ErrorIs(t, err, ErrNoConfidentMatch)
+		assert.Contains(t, err.Error(), "2 directories match")
+	})
+
+	t.Run("folders older than the grab are ignored", func(t *testing.T) {
+		root := t.TempDir()
+		old := writeRelease(t, root, "a8f3c9e1d2b7", "The.Matrix.1999.1080p.BluRay.x264-GROUP.mkv", 1000)
+		stale := time.Now().Add(-48 * time.Hour)
+		require.NoError(t, os.Chtimes(old, stale, stale))
+
+		_, err := LocateDownload(root, newDownload(), content)
+		assert.ErrorIs(t, err, ErrNoConfidentMatch)
+	})
+
+	t.Run("no download root", func(t *testing.T) {
+		_, err := LocateDownload("", newDownload(), content, "/nonexistent/path")
+		assert.ErrorIs(t, err, ErrNoConfidentMatch)
+	})
+}
@@ -0,0 +1,259 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// RenameStatus is the outcome of renaming a single library file.
+type RenameStatus string
+
+const (
+	RenameUnchanged RenameStatus = "unchanged" // Path already matches the template
+	RenamePending   RenameStatus = "pending"   // Would be renamed (preview only)
+	RenameRenamed   RenameStatus = "renamed"   // Moved on disk and updated in the database
+	RenameConflict  RenameStatus = "conflict"  // Target path is already taken
+	RenameFailed    RenameStatus = "failed"    // Could not compute the path or move the file
+)
+
+// FileRename describes how a library file maps onto the current naming template.
+type FileRename struct {
+	FileID    int64
+	EpisodeID *int64
+	Kind      library.FileKind // Subtitles follow the media file listed before them
+	OldPath   string
+	NewPath   string // Empty if the new path could not be computed
+	Status    RenameStatus
+	Error     error // Set for conflict and failed
+}
+
+// RenameResult is the result of renaming all files of a content item.
+type RenameResult struct {
+	ContentID    int64
+	Files        []FileRename
+	PlexNotified bool
+	PlexError    error
+}
+
+// RenamedCount returns the number of files that were moved.
+func (r *RenameResult) RenamedCount() int {
+	count := 0
+	for _, f := range r.Files {
+		if f.Status == RenameRenamed {
+			count++
+		}
+	}
+	return count
+}
+
+// PreviewRename computes the path each existing file of a content item would
+// have under the current naming templates, without touching the disk.
+// Files whose target is taken (on disk or by another file in the same batch)
+// are reported as conflicts. Subtitles named after a media file keep their
+// language suffix and follow it.
+func (i *Importer) PreviewRename(contentID int64) ([]FileRename, error) {
+	content, err := i.library.GetContent(contentID)
+	if err != nil {
+		return nil, fmt.Errorf("get content: %w", err)
+	}
+
+	files, _, err := i.library.ListFiles(library.FileFilter{ContentID: &contentID})
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	subtitles, _, err := i.library.ListFiles(library.FileFilter{ContentID: &contentID, Kinds: []library.FileKind{library.FileKindSubtitle}})
+	if err != nil {
+		return nil, fmt.Errorf("list subtitles: %w", err)
+	}
+
+	plans := make([]FileRename, 0, len(files)+len(subtitles))
+	targets := make(map[string]bool, len(files)+len(subtitles))
+	plan := func(f *library.File, newPath string) FileRename {
+		p := FileRename{FileID: f.ID, EpisodeID: f.EpisodeID, Kind: f.Kind, OldPath: f.Path, NewPath: newPath}
+		switch {
+		case newPath == f.Path:
+			p.Status = RenameUnchanged
+		case targets[newPath]:
+			p.Status = RenameConflict
+			p.Error = fmt.Errorf("%w: %s is the target of another file", ErrDestinationExists, newPath)
+		case pathTaken(f.Path, newPath):
+			p.Status = RenameConflict
+			p.Error = fmt.Errorf("%w: %s", ErrDestinationExists, newPath)
+		default:
+			p.Status = RenamePending
+		}
+		targets[newPath] = true
+		return p
+	}
+	for _, f := range files {
+		newPath, err := i.renamePath(content, f)
+		if err != nil {
+			plans = append(plans, FileRename{FileID: f.ID, EpisodeID: f.EpisodeID, Kind: f.Kind, OldPath: f.Path, Status: RenameFailed, Error: err})
+			continue
+		}
+		plans = append(plans, plan(f, newPath))
+
+		oldStem := strings.TrimSuffix(f.Path, filepath.Ext(f.Path))
+		newStem := strings.TrimSuffix(newPath, filepath.Ext(newPath))
+		for _, sub := range subtitlesOf(f, subtitles) {
+			plans = append(plans, plan(sub, newStem+strings.TrimPrefix(sub.Path, oldStem)))
+		}
+	}
+
+	return plans, nil
+}
+
+// Rename moves the files of a content item to the paths given by the current
+// naming templates and updates their database records. Each file is handled
+// independently: a conflict or failure is reported in its FileRename and the
+// remaining files are still processed.
+func (i *Importer) Rename(ctx context.Context, contentID int64) (*RenameResult, error) {
+	plans, err := i.PreviewRename(contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RenameResult{ContentID: contentID, Files: plans}
+	var scanPaths []string
+	mediaMoved := false // Whether the media file the following subtitles are named after is in place
+	for idx := range result.Files {
+		plan := &result.Files[idx]
+		if plan.Kind == library.FileKindMedia {
+			mediaMoved = plan.Status == RenamePending || plan.Status == RenameUnchanged
+		} else if plan.Status == RenamePending && !mediaMoved {
+			plan.Status = RenameFailed
+			plan.Error = errors.New("its media file was not renamed")
+			continue
+		}
+		if plan.Status != RenamePending {
+			continue
+		}
+		if err := i.renameFile(plan); err != nil {
+			plan.Error = err
+			if errors.Is(err, ErrDestinationExists) {
+				plan.Status = RenameConflict
+			} else {
+				plan.Status = RenameFailed
+			}
+			i.log.Warn("rename failed", "file_id", plan.FileID, "old", plan.OldPath, "new", plan.NewPath, "error", err)
+			if plan.Kind == library.FileKindMedia {
+				mediaMoved = false
+			}
+			continue
+		}
+		plan.Status = RenameRenamed
+		scanPaths = append(scanPaths, plan.OldPath, plan.NewPath)
+		i.log.Debug("file renamed", "file_id", plan.FileID, "old", plan.OldPath, "new", plan.NewPath)
+	}
+
+	// Rescan old and new locations so Plex drops stale entries (best effort)
+	if i.mediaServer != nil && len(scanPaths) > 0 {
+		scanned := make(map[string]bool)
+		for _, p := range scanPaths {
+			dir := filepath.Dir(p)
+			if scanned[dir] {
+				continue
+			}
+			scanned[dir] = true
+			if _, err := i.scanPath(ctx, p); err != nil {
+				result.PlexError = err
+				i.log.Warn("plex notification failed", "path", p, "error", err)
+			}
+		}
+		result.PlexNotified = result.PlexError == nil
+	}
+
+	i.log.Info("rename complete", "content_id", contentID, "files", len(result.Files), "renamed", result.RenamedCount())
+	return result, nil
+}
+
+// renamePath computes the templated path for an existing file.
+func (i *Importer) renamePath(content *library.Content, f *library.File) (string, error) {
+	ext := strings.TrimPrefix(filepath.Ext(f.Path), ".")
+	quality := f.Quality
+	if quality == "" {
+		quality = "unknown"
+	}
+
+	var relPath, root string
+	switch content.Type {
+	case library.ContentTypeMovie:
+		relPath = i.renamer.For(content).MoviePath(content.Title, content.Year, quality, ext)
+		root = i.rootFor(content)
+	case library.ContentTypeAudiobook:
+		relPath = i.renamer.For(content).AudiobookPath(content.Author, content.Title, content.Year, ext, f.PartNumber)
+		root = i.rootFor(content)
+	default:
+		if f.EpisodeID == nil {
+			return "", ErrEpisodeNotSpecified
+		}
+		episode, err := i.library.GetEpisode(*f.EpisodeID)
+		if err != nil {
+			return "", fmt.Errorf("get episode: %w", err)
+		}
+		relPath = i.renamer.For(content).EpisodePath(content.Title, episode.Season, episode.Episode, quality, ext)
+		root = i.rootFor(content)
+	}
+
+	destPath := filepath.Join(root, relPath)
+	if err := ValidatePath(destPath, root); err != nil {
+		return "", err
+	}
+	return destPath, nil
+}
+
+// renameFile moves a single file and points its database record at the new path.
+// If the database update fails the file is moved back.
+func (i *Importer) renameFile(plan *FileRename) error {
+	if pathTaken(plan.OldPath, plan.NewPath) {
+		return ErrDestinationExists
+	}
+
+	if err := MoveFile(plan.OldPath, plan.NewPath); err != nil {
+		return err
+	}
+
+	f, err := i.library.GetFile(plan.FileID)
+	if err == nil {
+		f.Path = plan.NewPath
+		err = i.library.UpdateFile(f)
+	}
+	if err != nil {
+		if rbErr := MoveFile(plan.NewPath, plan.OldPath); rbErr != nil {
+			i.log.Error("rename rollback failed", "file_id", plan.FileID, "path", plan.NewPath, "error", rbErr)
+		}
+		return fmt.Errorf("update file: %w", err)
+	}
+
+	removeEmptyDir(filepath.Dir(plan.OldPath))
+	return nil
+}
+
+// pathTaken reports whether dst exists and is a different file than src.
+// A case-only rename on a case-insensitive filesystem is not a conflict.
+func pathTaken(src, dst string) bool {
+	dstInfo, err := os.Stat(dst)
+	if err != nil {
+		return false
+	}
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(srcInfo, dstInfo)
+}
+
+// removeEmptyDir removes dir if it no longer contains anything.
+func removeEmptyDir(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) > 0 {
+		return
+	}
+	_ = os.Remove(dir)
+}
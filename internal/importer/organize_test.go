@@ -0,0 +1,176 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
+)
+
+// addTestFile writes a file on disk and records it in the library.
+func addTestFile(t *testing.T, imp *Importer, contentID int64, episodeID *int64, path, quality string) int64 {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("video"), 0644))
+	f := &library.File{ContentID: contentID, EpisodeID: episodeID, Path: path, SizeBytes: 5, Quality: quality, Source: "test"}
+	require.NoError(t, imp.library.AddFile(f))
+	return f.ID
+}
+
+func TestImporter_PreviewRename(t *testing.T) {
+	imp, db, _, movieRoot := setupTestImporter(t)
+	contentID := insertTestContent(t, db)
+
+	oldPath := filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 1080p.mkv")
+	fileID := addTestFile(t, imp, contentID, nil, oldPath, "1080p")
+
+	// Unchanged under the default template
+	plans, err := imp.PreviewRename(contentID)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Equal(t, RenameUnchanged, plans[0].Status)
+
+	// New template puts the file in a different place
+	imp.renamer = NewRenamer("{title} [{year}]/{title} [{quality}].{ext}", "")
+	plans, err = imp.PreviewRename(contentID)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Equal(t, fileID, plans[0].FileID)
+	assert.Equal(t, RenamePending, plans[0].Status)
+	assert.Equal(t, filepath.Join(movieRoot, "Test Movie [2024]", "Test Movie [1080p].mkv"), plans[0].NewPath)
+
+	// Preview does not touch the disk
+	_, err = os.Stat(oldPath)
+	assert.NoError(t, err)
+}
+
+func TestImporter_PreviewRename_Conflicts(t *testing.T) {
+	imp, db, _, movieRoot := setupTestImporter(t)
+	contentID := insertTestContent(t, db)
+	imp.renamer = NewRenamer("{title}/{title}.{ext}", "")
+
+	// Two files map to the same target
+	addTestFile(t, imp, contentID, nil, filepath.Join(movieRoot, "a", "one.mkv"), "1080p")
+	addTestFile(t, imp, contentID, nil, filepath.Join(movieRoot, "b", "two.mkv"), "720p")
+
+	plans, err := imp.PreviewRename(contentID)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+	assert.Equal(t, RenamePending, plans[0].Status)
+	assert.Equal(t, RenameConflict, plans[1].Status)
+	assert.ErrorIs(t, plans[1].Error, ErrDestinationExists)
+
+	// Target already exists on disk
+	target := filepath.Join(movieRoot, "Test Movie", "Test Movie.mkv")
+	require.NoError(t, os.MkdirAll(filepath.Dir(target), 0755))
+	require.NoError(t, os.WriteFile(target, []byte("other"), 0644))
+
+	plans, err = imp.PreviewRename(contentID)
+	require.NoError(t, err)
+	assert.Equal(t, RenameConflict, plans[0].Status)
+}
+
+func TestImporter_Rename_Movie(t *testing.T) {
+	imp, db, _, movieRoot := setupTestImporter(t)
+	contentID := insertTestContent(t, db)
+
+	oldPath := filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 1080p.mkv")
+	fileID := addTestFile(t, imp, contentID, nil, oldPath, "1080p")
+
+	imp.renamer = NewRenamer("{title} [{year}]/{title} [{quality}].{ext}", "")
+	result, err := imp.Rename(context.Background(), contentID)
+	require.NoError(t, err)
+	require.Len(t, result.Files, 1)
+	assert.Equal(t, RenameRenamed, result.Files[0].Status)
+	assert.Equal(t, 1, result.RenamedCount())
+
+	newPath := filepath.Join(movieRoot, "Test Movie [2024]", "Test Movie [1080p].mkv")
+	_, err = os.Stat(newPath)
+	require.NoError(t, err, "file should be at new path")
+	_, err = os.Stat(filepath.Dir(oldPath))
+	assert.True(t, os.IsNotExist(err), "empty old directory should be removed")
+
+	f, err := imp.library.GetFile(fileID)
+	require.NoError(t, err)
+	assert.Equal(t, newPath, f.Path)
+}
+
+func TestImporter_Rename_Subtitles(t *testing.T) {
+	imp, db, _, movieRoot := setupTestImporter(t)
+	contentID := insertTestContent(t, db)
+
+	oldPath := filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 1080p.mkv")
+	addTestFile(t, imp, contentID, nil, oldPath, "1080p")
+	subPath := filepath.Join(movieRoot, "Test Movie (2024)", "Test Movie (2024) - 1080p.en.forced.srt")
+	require.NoError(t, os.WriteFile(subPath, []byte("sub"), 0644))
+	sub := &library.File{ContentID: contentID, Path: subPath, SizeBytes: 3, Kind: library.FileKindSubtitle}
+	require.NoError(t, imp.library.AddFile(sub))
+
+	imp.renamer = NewRenamer("{title} [{year}]/{title} [{quality}].{ext}", "")
+	result, err := imp.Rename(context.Background(), contentID)
+	require.NoError(t, err)
+	require.Len(t, result.Files, 2)
+	assert.Equal(t, library.FileKindSubtitle, result.Files[1].Kind)
+	assert.Equal(t, 2, result.RenamedCount())
+
+	newSub := filepath.Join(movieRoot, "Test Movie [2024]", "Test Movie [1080p].en.forced.srt")
+	assert.FileExists(t, newSub, "the subtitle follows its video and keeps its language")
+	f, err := imp.library.GetFile(sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, newSub, f.Path)
+	assert.NoDirExists(t, filepath.Dir(oldPath))
+}
+
+func TestImporter_Rename_PartialFailure(t *testing.T) {
+	imp, db, _, _ := setupTestImporter(t)
+	seriesID := insertTestSeries(t, db, "Test Show")
+	ep1 := insertTestEpisode(t, db, seriesID, 1, 1)
+	ep2 := insertTestEpisode(t, db, seriesID, 1, 2)
+
+	oldDir := filepath.Join(imp.seriesRoot, "old")
+	path1 := filepath.Join(oldDir, "ep1.mkv")
+	path2 := filepath.Join(oldDir, "ep2.mkv")
+	addTestFile(t, imp, seriesID, &ep1, path1, "1080p")
+	file2 := addTestFile(t, imp, seriesID, &ep2, path2, "1080p")
+
+	// First file is gone from disk; second must still be renamed
+	require.NoError(t, os.Remove(path1))
+
+	result, err := imp.Rename(context.Background(), seriesID)
+	require.NoError(t, err)
+	require.Len(t, result.Files, 2)
+	assert.Equal(t, RenameFailed, result.Files[0].Status)
+	assert.Error(t, result.Files[0].Error)
+	assert.Equal(t, RenameRenamed, result.Files[1].Status)
+
+	want := filepath.Join(imp.seriesRoot, "Test Show", "Season 01", "Test Show - S01E02 - 1080p.mkv")
+	f, err := imp.library.GetFile(file2)
+	require.NoError(t, err)
+	assert.Equal(t, want, f.Path)
+}
+
+func TestImporter_Rename_NamingOverrides(t *testing.T) {
+	imp, db, _, _ := setupTestImporter(t)
+	series := fixtures.NewSeries("Frieren", 2023).WithRootPath(imp.seriesRoot).
+		WithSeasonFolder(false).WithNamingTemplate("{title}/{title} - {episode:03} [{quality}].{ext}").
+		Insert(t, db)
+	episodeID := insertTestEpisode(t, db, series.ID, 1, 7)
+
+	oldPath := filepath.Join(imp.seriesRoot, "Frieren", "Season 01", "Frieren - S01E07 - 1080p.mkv")
+	addTestFile(t, imp, series.ID, &episodeID, oldPath, "1080p")
+
+	result, err := imp.Rename(context.Background(), series.ID)
+	require.NoError(t, err)
+	require.Len(t, result.Files, 1)
+	assert.Equal(t, RenameRenamed, result.Files[0].Status)
+	newPath := filepath.Join(imp.seriesRoot, "Frieren", "Frieren - 007 [1080p].mkv")
+	assert.Equal(t, newPath, result.Files[0].NewPath)
+	_, err = os.Stat(newPath)
+	require.NoError(t, err)
+	assert.NoDirExists(t, filepath.Dir(oldPath), "emptied season folder removed")
+}
@@ -0,0 +1,127 @@
+// internal/importer/pathmap.go
+package importer
+
+import (
+	"os"
+	"strings"
+)
+
+// maxFailingExamples caps the failing paths kept per mapping.
+const maxFailingExamples = 5
+
+// PathMapping translates a path prefix as seen by Plex or a download client
+// to the same location on this machine.
+type PathMapping struct {
+	Remote string // Path prefix as seen by Plex or the download client
+	Local  string // Corresponding local path prefix
+}
+
+// ToLocal converts a remote path under the mapping to the local path.
+// Other paths, and all paths for an incomplete mapping, are returned unchanged.
+func (m PathMapping) ToLocal(path string) string {
+	if m.Remote == "" || m.Local == "" || !strings.HasPrefix(path, m.Remote) {
+		return path
+	}
+	return m.Local + path[len(m.Remote):]
+}
+
+// PathMappings returns the configured path mappings (empty if none).
+func (c *PlexClient) PathMappings() []PathMapping {
+	if c.localPath == "" || c.remotePath == "" {
+		return nil
+	}
+	return []PathMapping{{Remote: c.remotePath, Local: c.localPath}}
+}
+
+// PathCheck is the outcome of resolving Plex file paths on this machine.
+type PathCheck struct {
+	Sampled  int
+	Resolved int
+	Mappings []MappingCheck // One per mapping, then one for paths no mapping covers
+}
+
+// MappingCheck counts the sampled paths that fell under one mapping.
+type MappingCheck struct {
+	PathMapping               // Zero for paths no mapping covers
+	Hits        int           // Paths that exist locally after translation
+	Misses      int           // Paths that do not
+	Failing     []FailingPath // Up to 5 misses, as examples
+}
+
+// FailingPath is a sampled path that did not resolve locally.
+type FailingPath struct {
+	PlexPath  string
+	LocalPath string
+	Error     string
+}
+
+// MissRatio returns the fraction of sampled paths that did not resolve,
+// or 0 if nothing was sampled.
+func (c *PathCheck) MissRatio() float64 {
+	if c.Sampled == 0 {
+		return 0
+	}
+	return float64(c.Sampled-c.Resolved) / float64(c.Sampled)
+}
+
+// CheckPaths translates each Plex path with translate, stats the result, and
+// counts hits and misses against the mapping whose remote prefix covers it.
+// Paths outside every mapping are counted in a final unmapped entry, which
+// is omitted when nothing falls in it.
+func CheckPaths(paths []string, mappings []PathMapping, translate func(string) string) *PathCheck {
+	check := &PathCheck{Mappings: make([]MappingCheck, len(mappings)+1)}
+	for i, m := range mappings {
+		check.Mappings[i].PathMapping = m
+	}
+	unmapped := len(mappings)
+
+	for _, plexPath := range paths {
+		if plexPath == "" {
+			continue
+		}
+		idx := unmapped
+		for i, m := range mappings {
+			if strings.HasPrefix(plexPath, m.Remote) {
+				idx = i
+				break
+			}
+		}
+
+		mc := &check.Mappings[idx]
+		localPath := translate(plexPath)
+		check.Sampled++
+		if _, err := os.Stat(localPath); err != nil {
+			mc.Misses++
+			if len(mc.Failing) < maxFailingExamples {
+				mc.Failing = append(mc.Failing, FailingPath{PlexPath: plexPath, LocalPath: localPath, Error: err.Error()})
+			}
+			continue
+		}
+		mc.Hits++
+		check.Resolved++
+	}
+
+	if check.Mappings[unmapped].Hits+check.Mappings[unmapped].Misses == 0 {
+		check.Mappings = check.Mappings[:unmapped]
+	}
+	return check
+}
+
+// SamplePaths returns the file paths of up to n items spread evenly through
+// items, skipping items without a file (such as shows).
+func SamplePaths(items []PlexItem, n int) []string {
+	var paths []string
+	for _, item := range items {
+		if item.FilePath != "" {
+			paths = append(paths, item.FilePath)
+		}
+	}
+	if n <= 0 || len(paths) <= n {
+		return paths
+	}
+	sample := make([]string, n)
+	for i := range sample {
+		sample[i] = paths[i*len(paths)/n]
+	}
+	return sample
+}
@@ -0,0 +1,79 @@
+// internal/importer/pathmap_test.go
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlexClient_PathMappings(t *testing.T) {
+	assert.Empty(t, NewPlexClient("http://plex", "token", nil).PathMappings())
+
+	c := NewPlexClientWithPathMapping("http://plex", "token", "/srv/media", "/data/media", nil)
+	assert.Equal(t, []PathMapping{{Remote: "/data/media", Local: "/srv/media"}}, c.PathMappings())
+}
+
+func TestCheckPaths(t *testing.T) {
+	local := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(local, "movies"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(local, "movies", "a.mkv"), []byte("x"), 0644))
+
+	c := NewPlexClientWithPathMapping("http://plex", "token", local, "/data", nil)
+	check := CheckPaths([]string{
+		"/data/movies/a.mkv",
+		"/data/movies/b.mkv",
+		"",
+		"/mnt/other/c.mkv",
+	}, c.PathMappings(), c.TranslateToLocal)
+
+	assert.Equal(t, 3, check.Sampled)
+	assert.Equal(t, 1, check.Resolved)
+	assert.InDelta(t, 2.0/3, check.MissRatio(), 0.001)
+
+	require.Len(t, check.Mappings, 2)
+	assert.Equal(t, "/data", check.Mappings[0].Remote)
+	assert.Equal(t, 1, check.Mappings[0].Hits)
+	assert.Equal(t, 1, check.Mappings[0].Misses)
+	require.Len(t, check.Mappings[0].Failing, 1)
+	assert.Equal(t, filepath.Join(local, "movies", "b.mkv"), check.Mappings[0].Failing[0].LocalPath)
+
+	assert.Empty(t, check.Mappings[1].Remote, "unmapped paths are reported last")
+	assert.Equal(t, 1, check.Mappings[1].Misses)
+}
+
+func TestCheckPaths_OmitsEmptyUnmapped(t *testing.T) {
+	check := CheckPaths([]string{"/data/a.mkv"}, []PathMapping{{Remote: "/data", Local: "/srv"}}, func(p string) string {
+		return "/srv" + strings.TrimPrefix(p, "/data")
+	})
+	require.Len(t, check.Mappings, 1)
+	assert.Len(t, check.Mappings[0].Failing, 1)
+	assert.Zero(t, (&PathCheck{}).MissRatio())
+}
+
+func TestCheckPaths_CapsFailingExamples(t *testing.T) {
+	var paths []string
+	for range 8 {
+		paths = append(paths, "/nonexistent/path/12345.mkv")
+	}
+	check := CheckPaths(paths, nil, func(p string) string { return p })
+	require.Len(t, check.Mappings, 1)
+	assert.Equal(t, 8, check.Mappings[0].Misses)
+	assert.Len(t, check.Mappings[0].Failing, maxFailingExamples)
+}
+
+func TestSamplePaths(t *testing.T) {
+	var items []PlexItem
+	for i := range 10 {
+		items = append(items, PlexItem{FilePath: string(rune('a' + i))})
+	}
+	items = append(items, PlexItem{Title: "Show", Type: "show"})
+
+	assert.Len(t, SamplePaths(items, 0), 10)
+	assert.Len(t, SamplePaths(items, 20), 10, "shows without files are skipped")
+	assert.Equal(t, []string{"a", "c", "e", "g", "i"}, SamplePaths(items, 5), "sample is spread evenly")
+}
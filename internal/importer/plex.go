@@ -24,31 +24,41 @@ type PlexClient struct {
 	log        *slog.Logger
 }
 
-// NewPlexClient creates a new Plex client.
-func NewPlexClient(baseURL, token string, log *slog.Logger) *PlexClient {
-	return &PlexClient{
-		baseURL: strings.TrimSuffix(baseURL, "/"),
-		token:   token,
-		log:     plexLogger(log),
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+// DefaultPlexTimeout bounds each Plex API request when no timeout is given.
+const DefaultPlexTimeout = 30 * time.Second
+
+// PlexOption configures a PlexClient.
+type PlexOption func(*PlexClient)
+
+// WithPlexTimeout sets the timeout for each request to Plex.
+func WithPlexTimeout(d time.Duration) PlexOption {
+	return func(c *PlexClient) {
+		c.httpClient.Timeout = d
 	}
 }
 
+// NewPlexClient creates a new Plex client.
+func NewPlexClient(baseURL, token string, log *slog.Logger, opts ...PlexOption) *PlexClient {
+	return NewPlexClientWithPathMapping(baseURL, token, "", "", log, opts...)
+}
+
 // NewPlexClientWithPathMapping creates a new Plex client with path translation.
 // localPath is the path on this machine, remotePath is how Plex sees it.
-func NewPlexClientWithPathMapping(baseURL, token, localPath, remotePath string, log *slog.Logger) *PlexClient {
-	return &PlexClient{
+func NewPlexClientWithPathMapping(baseURL, token, localPath, remotePath string, log *slog.Logger, opts ...PlexOption) *PlexClient {
+	c := &PlexClient{
 		baseURL:    strings.TrimSuffix(baseURL, "/"),
 		token:      token,
 		localPath:  localPath,
 		remotePath: remotePath,
 		log:        plexLogger(log),
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultPlexTimeout,
 		},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func plexLogger(log *slog.Logger) *slog.Logger {
@@ -163,37 +173,39 @@ func (c *PlexClient) FindSectionByName(ctx context.Context, name string) (*Secti
 	return nil, nil
 }
 
-// ScanPath triggers a partial scan of the directory containing the given file path.
-func (c *PlexClient) ScanPath(ctx context.Context, filePath string) error {
+// SectionForPath returns the key of the library section whose locations
+// contain the given local path.
+func (c *PlexClient) SectionForPath(ctx context.Context, filePath string) (string, error) {
 	// Translate local path to Plex's path (for Docker path mapping)
 	remotePath := c.translateToRemote(filePath)
 	remoteDir := filepath.Dir(remotePath)
 
-	if c.log != nil {
-		c.log.Debug("scanning path", "local", filePath, "remote", remotePath)
-	}
-
-	// Find the section that contains this path
 	sections, err := c.GetSections(ctx)
 	if err != nil {
-		return fmt.Errorf("get sections: %w", err)
+		return "", fmt.Errorf("get sections: %w", err)
 	}
-
-	var sectionKey string
 	for _, section := range sections {
 		for _, loc := range section.Locations {
 			if strings.HasPrefix(remoteDir, loc.Path) || strings.HasPrefix(remotePath, loc.Path) {
-				sectionKey = section.Key
-				break
+				return section.Key, nil
 			}
 		}
-		if sectionKey != "" {
-			break
-		}
 	}
+	return "", fmt.Errorf("no library section found for path: %s (translated: %s)", filePath, remotePath)
+}
 
-	if sectionKey == "" {
-		return fmt.Errorf("no library section found for path: %s (translated: %s)", filePath, remotePath)
+// ScanPath triggers a partial scan of the directory containing the given file path.
+func (c *PlexClient) ScanPath(ctx context.Context, filePath string) error {
+	remoteDir := filepath.Dir(c.translateToRemote(filePath))
+
+	if c.log != nil {
+		c.log.Debug("scanning path", "local", filePath, "remote", remoteDir)
+	}
+
+	// Find the section that contains this path
+	sectionKey, err := c.SectionForPath(ctx, filePath)
+	if err != nil {
+		return err
 	}
 
 	// Trigger partial scan using the remote path
@@ -291,6 +303,7 @@ type PlexItem struct {
 	Type      string // movie, show
 	AddedAt   int64
 	FilePath  string
+	Locations []string // Folders of a show (empty for movies)
 }
 
 // plexItemXML is the XML representation of a Plex item.
@@ -305,6 +318,7 @@ type plexItemXML struct {
 			File string `xml:"file,attr"`
 		} `xml:"Part"`
 	} `xml:"Media"`
+	Locations []Location `xml:"Location"`
 }
 
 // libraryItemsResponse is the XML response from /library/sections/{key}/all.
@@ -388,6 +402,9 @@ func (c *PlexClient) ListLibraryItems(ctx context.Context, sectionKey string) ([
 			AddedAt:   item.AddedAt,
 			FilePath:  filePath,
 		}
+		for _, loc := range item.Locations {
+			items[i].Locations = append(items[i].Locations, loc.Path)
+		}
 	}
 
 	return items, nil
@@ -449,6 +466,61 @@ func (c *PlexClient) Search(ctx context.Context, query string) ([]PlexItem, erro
 	return items, nil
 }
 
+// GetItem fetches the item with a rating key directly, with no search.
+// Returns ErrPlexItemNotFound if Plex no longer has it.
+func (c *PlexClient) GetItem(ctx context.Context, ratingKey string) (*PlexItem, error) {
+	reqURL := fmt.Sprintf("%s/library/metadata/%s", c.baseURL, url.PathEscape(ratingKey))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-Plex-Token", c.token)
+	req.Header.Set("Accept", "application/xml")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrPlexItemNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	var result searchResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	var item plexItemXML
+	switch {
+	case len(result.Videos) > 0:
+		item = result.Videos[0]
+	case len(result.Directories) > 0:
+		item = result.Directories[0]
+	default:
+		return nil, ErrPlexItemNotFound
+	}
+
+	found := &PlexItem{
+		RatingKey: item.RatingKey,
+		Title:     item.Title,
+		Year:      item.Year,
+		Type:      item.Type,
+		AddedAt:   item.AddedAt,
+	}
+	if len(item.Media) > 0 && len(item.Media[0].Part) > 0 {
+		found.FilePath = item.Media[0].Part[0].File
+	}
+	for _, loc := range item.Locations {
+		found.Locations = append(found.Locations, loc.Path)
+	}
+	return found, nil
+}
+
 // HasMovie checks if Plex has a movie with the given title and year.
 func (c *PlexClient) HasMovie(ctx context.Context, title string, year int) (bool, error) {
 	// Use FindMovie which has year tolerance and fuzzy title matching
@@ -464,6 +536,16 @@ func (c *PlexClient) HasContent(ctx context.Context, title string, year int) (bo
 
 // FindMovie searches for a movie in Plex with fuzzy title matching and year tolerance.
 // Returns (found, ratingKey, error). The ratingKey is Plex's unique identifier.
+func (c *PlexClient) FindMovie(ctx context.Context, title string, year int) (bool, string, error) {
+	item, err := c.FindMovieItem(ctx, title, year)
+	if err != nil || item == nil {
+		return false, "", err
+	}
+	return true, item.RatingKey, nil
+}
+
+// FindMovieItem searches for a movie in Plex with fuzzy title matching and
+// year tolerance. Returns the matched item, or nil if Plex doesn't have it.
 //
 // Matching strategy:
 //  1. Exact title match (case-insensitive) with exact year
@@ -473,10 +555,10 @@ func (c *PlexClient) HasContent(ctx context.Context, title string, year int) (bo
 // This handles common mismatches:
 //   - Year off by one (release year vs theatrical year)
 //   - Title includes year ("Blade Runner 2049" vs "Blade Runner" + year=2049)
-func (c *PlexClient) FindMovie(ctx context.Context, title string, year int) (bool, string, error) {
+func (c *PlexClient) FindMovieItem(ctx context.Context, title string, year int) (*PlexItem, error) {
 	movies, err := c.searchMovies(ctx, title)
 	if err != nil {
-		return false, "", err
+		return nil, err
 	}
 
 	// If no results, try fallback searches with individual words.
@@ -484,12 +566,12 @@ func (c *PlexClient) FindMovie(ctx context.Context, title string, year int) (boo
 	if len(movies) == 0 {
 		movies, err = c.fallbackSearch(ctx, title)
 		if err != nil {
-			return false, "", err
+			return nil, err
 		}
 	}
 
 	if len(movies) == 0 {
-		return false, "", nil
+		return nil, nil
 	}
 
 	// Normalize search title once for all comparisons
@@ -497,17 +579,17 @@ func (c *PlexClient) FindMovie(ctx context.Context, title string, year int) (boo
 
 	// Strategy 1: Normalized title match with exact year
 	// Uses normalization to handle punctuation differences like "Dr." vs "Dr"
-	for _, item := range movies {
+	for i, item := range movies {
 		if item.Year == year && normalizeForMatch(item.Title) == normalizedSearch {
-			return true, item.RatingKey, nil
+			return &movies[i], nil
 		}
 	}
 
 	// Strategy 2: Normalized title match with ±1 year tolerance
-	for _, item := range movies {
+	for i, item := range movies {
 		yearDiff := item.Year - year
 		if yearDiff >= -1 && yearDiff <= 1 && normalizeForMatch(item.Title) == normalizedSearch {
-			return true, item.RatingKey, nil
+			return &movies[i], nil
 		}
 	}
 
@@ -515,7 +597,7 @@ func (c *PlexClient) FindMovie(ctx context.Context, title string, year int) (boo
 	// e.g., searching for "Blade Runner" year=2049 should match "Blade Runner 2049"
 	// Only applies when the Plex title contains the search year.
 
-	for _, item := range movies {
+	for i, item := range movies {
 		// Only consider items where the Plex title contains the year we're looking for
 		// This handles "Blade Runner 2049" matching search for "Blade Runner" year=2049
 		if containsYear(item.Title, year) {
@@ -523,12 +605,12 @@ func (c *PlexClient) FindMovie(ctx context.Context, title string, year int) (boo
 			plexTitleWithoutYear := removeYear(item.Title, year)
 			similarity := jaroWinkler(normalizedSearch, normalizeForMatch(plexTitleWithoutYear))
 			if similarity >= 0.85 {
-				return true, item.RatingKey, nil
+				return &movies[i], nil
 			}
 		}
 	}
 
-	return false, "", nil
+	return nil, nil
 }
 
 // searchMovies searches Plex and filters to movies only.
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,6 +44,26 @@ func TestPlexClient_GetSections(t *testing.T) {
 	assert.Equal(t, "/movies", sections[0].Locations[0].Path)
 }
 
+func TestPlexClient_Timeout(t *testing.T) {
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never respond
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	defer server.Close()
+	defer close(stop)
+
+	client := NewPlexClient(server.URL, "test-token", nil, WithPlexTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.GetSections(context.Background())
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second, "request should give up after the configured timeout")
+}
+
 func TestPlexClient_ScanPath(t *testing.T) {
 	scanCalled := false
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -168,6 +189,32 @@ func TestPlexClient_GetLibraryCount(t *testing.T) {
 	assert.Equal(t, 42, count)
 }
 
+func TestPlexClient_ListLibraryItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/library/sections/2/all", r.URL.Path)
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<?xml version="1.0"?>
+<MediaContainer>
+  <Video ratingKey="10" title="Heat" year="1995" type="movie">
+    <Media><Part file="/data/movies/Heat (1995)/Heat.mkv"/></Media>
+  </Video>
+  <Directory ratingKey="20" title="The Wire" year="2002" type="show">
+    <Location path="/data/tv/The Wire"/>
+  </Directory>
+</MediaContainer>`)
+	}))
+	defer server.Close()
+
+	client := NewPlexClient(server.URL, "test-token", nil)
+	items, err := client.ListLibraryItems(context.Background(), "2")
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "/data/movies/Heat (1995)/Heat.mkv", items[0].FilePath)
+	assert.Empty(t, items[0].Locations)
+	assert.Empty(t, items[1].FilePath)
+	assert.Equal(t, []string{"/data/tv/The Wire"}, items[1].Locations)
+}
+
 func TestPlexClient_HasMovie(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == plexSearchPath && strings.Contains(r.URL.RawQuery, "Test+Movie") {
@@ -297,6 +344,78 @@ func TestPlexClient_FindMovie_ReturnsRatingKey(t *testing.T) {
 	assert.Equal(t, "99999", key, "should return the Plex ratingKey")
 }
 
+func TestPlexClient_FindMovieItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == plexSearchPath {
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprint(w, `<?xml version="1.0"?>
+<MediaContainer>
+  <Video ratingKey="99999" title="Test Movie" year="2023" type="movie">
+    <Media><Part file="/data/media/movies/Test Movie (2023)/Test Movie (2023) 1080p.mkv"/></Media>
+  </Video>
+</MediaContainer>`)
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	client := NewPlexClient(server.URL, "test-token", nil)
+
+	// Year off by one still matches
+	item, err := client.FindMovieItem(context.Background(), "Test Movie", 2024)
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	assert.Equal(t, "99999", item.RatingKey)
+	assert.Equal(t, "/data/media/movies/Test Movie (2023)/Test Movie (2023) 1080p.mkv", item.FilePath)
+
+	item, err = client.FindMovieItem(context.Background(), "Other Movie", 2024)
+	require.NoError(t, err)
+	assert.Nil(t, item)
+}
+
+func TestPlexClient_GetItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-token", r.Header.Get("X-Plex-Token"))
+		switch r.URL.Path {
+		case "/library/metadata/99999":
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprint(w, `<?xml version="1.0"?>
+<MediaContainer>
+  <Video ratingKey="99999" title="Test Movie" year="2023" type="movie">
+    <Media><Part file="/data/media/movies/Test Movie (2023)/Test Movie (2023) 1080p.mkv"/></Media>
+  </Video>
+</MediaContainer>`)
+		case "/library/metadata/500":
+			fmt.Fprint(w, `<?xml version="1.0"?>
+<MediaContainer>
+  <Directory ratingKey="500" title="Test Show" year="2020" type="show">
+    <Location path="/data/media/tv/Test Show"/>
+  </Directory>
+</MediaContainer>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewPlexClient(server.URL, "test-token", nil)
+
+	item, err := client.GetItem(context.Background(), "99999")
+	require.NoError(t, err)
+	assert.Equal(t, "Test Movie", item.Title)
+	assert.Equal(t, 2023, item.Year)
+	assert.Equal(t, "/data/media/movies/Test Movie (2023)/Test Movie (2023) 1080p.mkv", item.FilePath)
+
+	item, err = client.GetItem(context.Background(), "500")
+	require.NoError(t, err)
+	assert.Equal(t, "show", item.Type)
+	assert.Equal(t, []string{"/data/media/tv/Test Show"}, item.Locations)
+
+	_, err = client.GetItem(context.Background(), "404")
+	require.ErrorIs(t, err, ErrPlexItemNotFound)
+}
+
 func TestPlexClient_TranslateToLocal(t *testing.T) {
 	client := NewPlexClientWithPathMapping(
 		"http://plex:32400",
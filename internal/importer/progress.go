@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// DefaultSeasonPackConcurrency is the number of season pack episodes imported
+// at once when none is configured.
+const DefaultSeasonPackConcurrency = 3
+
+// importProgressInterval is the least time between two ImportProgress events
+// of an episode while it copies.
+const importProgressInterval = 5 * time.Second
+
+// SetEvents makes the importer publish ImportProgress events of season packs
+// to p; nil publishes none.
+func (i *Importer) SetEvents(p Publisher) {
+	i.bus = p
+}
+
+// importPackFile imports one video of a season pack, or reports it imported
+// when an earlier import of the download already did. Progress is published
+// as it copies.
+func (i *Importer) importPackFile(ctx context.Context, dl *download.Download, content *library.Content, downloadPath, srcPath, quality string, previous map[string]*download.EpisodeResult) EpisodeResult {
+	sourceFile, err := filepath.Rel(downloadPath, srcPath)
+	if err != nil {
+		sourceFile = filepath.Base(srcPath)
+	}
+	if prev := previous[sourceFile]; prev != nil {
+		if fileID := i.fileRecord(prev); fileID != 0 {
+			return EpisodeResult{
+				EpisodeID:       *prev.EpisodeID,
+				FileID:          fileID,
+				Season:          prev.Season,
+				Episode:         prev.Episode,
+				Success:         true,
+				SourceFile:      sourceFile,
+				FilePath:        prev.FilePath,
+				AlreadyImported: true,
+			}
+		}
+	}
+
+	progress := i.newPackProgress(ctx, dl, sourceFile, srcPath)
+	if progress != nil {
+		ctx = withCopyProgress(ctx, progress.copied)
+	}
+	epResult := i.importEpisodeFile(ctx, dl, content, srcPath, quality)
+	epResult.SourceFile = sourceFile
+	progress.done(epResult)
+	return epResult
+}
+
+// packProgress publishes the ImportProgress events of one season pack file.
+// Its methods do nothing on a nil packProgress.
+type packProgress struct {
+	i     *Importer
+	ctx   context.Context
+	event events.ImportProgress // Template of the events published
+	last  time.Time             // When the last event was published
+}
+
+// newPackProgress publishes the start of the import of a season pack file and
+// returns its progress, or nil when the importer publishes no events.
+func (i *Importer) newPackProgress(ctx context.Context, dl *download.Download, sourceFile, srcPath string) *packProgress {
+	if i.bus == nil {
+		return nil
+	}
+	p := &packProgress{
+		i:   i,
+		ctx: ctx,
+		event: events.ImportProgress{
+			DownloadID: dl.ID,
+			ContentID:  dl.ContentID,
+			SourceFile: sourceFile,
+		},
+	}
+	// Best effort: daily episodes are only matched on import
+	if season, episode, err := MatchFileToSeason(srcPath); err == nil {
+		p.event.Season, p.event.Episode = season, episode
+	}
+	if info, err := os.Stat(srcPath); err == nil {
+		p.event.TotalBytes = info.Size()
+	}
+	p.publish(p.event)
+	return p
+}
+
+// copied publishes the bytes copied so far, at most once per
+// importProgressInterval.
+func (p *packProgress) copied(n int64) {
+	if time.Since(p.last) < importProgressInterval {
+		return
+	}
+	e := p.event
+	e.BytesCopied = n
+	p.publish(e)
+}
+
+// done publishes the outcome of the file's import.
+func (p *packProgress) done(r EpisodeResult) {
+	if p == nil {
+		return
+	}
+	e := p.event
+	e.Done = true
+	if r.Season != 0 || r.Episode != 0 {
+		e.Season, e.Episode = r.Season, r.Episode
+	}
+	if r.Success {
+		e.BytesCopied = e.TotalBytes
+	} else if r.Error != nil {
+		e.Error = r.Error.Error()
+	}
+	p.publish(e)
+}
+
+func (p *packProgress) publish(e events.ImportProgress) {
+	p.last = time.Now()
+	e.BaseEvent = events.NewBaseEvent(events.EventImportProgress, events.EntityDownload, e.DownloadID)
+	if err := p.i.bus.Publish(p.ctx, &e); err != nil {
+		p.i.log.Warn("failed to publish import progress", "download_id", e.DownloadID, "file", e.SourceFile, "error", err)
+	}
+}
@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// DefaultReconcileBatch is how many files a reconcile pass checks.
+const DefaultReconcileBatch = 100
+
+// Publisher publishes events.
+// Satisfied by *events.Bus.
+type Publisher interface {
+	Publish(ctx context.Context, e events.Event) error
+}
+
+// ReconcileResult counts what a reconcile pass found.
+type ReconcileResult struct {
+	Checked  int
+	Missing  int // Files newly found missing
+	Returned int // Files marked missing that are back on disk
+	Wanted   int // Movies and episodes wanted again as a result
+}
+
+// Reconciler notices library files deleted outside arrgo. Each pass stats
+// the next batch of file records, in ID order, wrapping around at the end,
+// so a large library is covered gradually without a burst of disk access.
+//
+// A missing file is marked missing rather than deleted, its movie or episode
+// goes back to wanted if no other file covers it, and a FileMissing event is
+// published. Files under a root folder that is itself gone are skipped, so
+// an unmounted disk doesn't mark the whole library missing.
+type Reconciler struct {
+	library  *library.Store
+	bus      Publisher                                  // nil: no events
+	research func(ctx context.Context, contentID int64) // nil: no search for content wanted again
+	batch    int
+	log      *slog.Logger
+	next     int // Offset of the next batch
+}
+
+// NewReconciler creates a reconciler checking batch files per pass
+// (DefaultReconcileBatch if batch is 0 or less).
+func NewReconciler(lib *library.Store, bus Publisher, batch int, log *slog.Logger) *Reconciler {
+	if batch <= 0 {
+		batch = DefaultReconcileBatch
+	}
+	return &Reconciler{library: lib, bus: bus, batch: batch, log: log}
+}
+
+// SetResearch sets a search run for each content item with a movie or
+// episode wanted again after its file went missing.
+func (r *Reconciler) SetResearch(fn func(ctx context.Context, contentID int64)) {
+	r.research = fn
+}
+
+// Run reconciles a batch of files every interval until the context is canceled.
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		result, err := r.Reconcile(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.log.Warn("file reconcile failed", "error", err)
+		} else if result.Missing > 0 || result.Returned > 0 {
+			r.log.Info("files reconciled", "missing", result.Missing, "returned", result.Returned, "wanted", result.Wanted)
+		}
+	}
+}
+
+// Reconcile checks the next batch of files.
+func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileResult, error) {
+	result := &ReconcileResult{}
+	files, total, err := r.library.ListFiles(library.FileFilter{Limit: r.batch, Offset: r.next})
+	if err != nil {
+		return result, fmt.Errorf("list files: %w", err)
+	}
+	r.next += len(files)
+	if r.next >= total {
+		r.next = 0
+	}
+
+	roots := make(map[int64]bool) // Content ID to whether its root folder exists
+	var wanted []int64
+	for _, f := range files {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Checked++
+
+		_, statErr := os.Stat(f.Path)
+		switch {
+		case statErr == nil && f.MissingAt != nil:
+			if err := r.library.MarkFilePresent(f); err != nil {
+				return result, err
+			}
+			result.Returned++
+		case errors.Is(statErr, os.ErrNotExist) && f.MissingAt == nil:
+			if !r.rootExists(roots, f.ContentID) {
+				continue
+			}
+			isWanted, err := r.library.MarkFileMissing(f)
+			if err != nil {
+				return result, err
+			}
+			result.Missing++
+			r.log.Warn("library file missing", "file_id", f.ID, "content_id", f.ContentID, "path", f.Path)
+			if isWanted {
+				result.Wanted++
+				wanted = append(wanted, f.ContentID)
+			}
+			r.publish(ctx, f, isWanted)
+		}
+	}
+
+	if r.research != nil {
+		searched := make(map[int64]bool)
+		for _, id := range wanted {
+			if !searched[id] {
+				searched[id] = true
+				r.research(ctx, id)
+			}
+		}
+	}
+	return result, nil
+}
+
+// rootExists reports whether the root folder of the content exists,
+// caching the answer per content for the pass.
+func (r *Reconciler) rootExists(roots map[int64]bool, contentID int64) bool {
+	if exists, ok := roots[contentID]; ok {
+		return exists
+	}
+	exists := false
+	if content, err := r.library.GetContent(contentID); err == nil {
+		_, statErr := os.Stat(content.RootPath)
+		exists = content.RootPath == "" || statErr == nil
+		if !exists {
+			r.log.Warn("root folder missing, skipping its files", "content_id", contentID, "root", content.RootPath)
+		}
+	}
+	roots[contentID] = exists
+	return exists
+}
+
+func (r *Reconciler) publish(ctx context.Context, f *library.File, wanted bool) {
+	if r.bus == nil {
+		return
+	}
+	if err := r.bus.Publish(ctx, &events.FileMissing{
+		BaseEvent: events.NewBaseEvent(events.EventFileMissing, events.EntityContent, f.ContentID),
+		FileID:    f.ID,
+		ContentID: f.ContentID,
+		EpisodeID: f.EpisodeID,
+		Path:      f.Path,
+		Wanted:    wanted,
+	}); err != nil {
+		r.log.Error("failed to publish FileMissing", "file_id", f.ID, "error", err)
+	}
+}
@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/testutil"
+	"github.com/vmunix/arrgo/internal/testutil/fixtures"
+)
+
+// recordingBus keeps published events.
+type recordingBus struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (b *recordingBus) Publish(_ context.Context, e events.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, e)
+	return nil
+}
+
+func TestReconciler(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := library.NewStore(db)
+	root := t.TempDir()
+
+	movie := fixtures.NewMovie("Dune", 2021).WithStatus(library.StatusAvailable).WithRootPath(root).Insert(t, db)
+	kept := fixtures.NewMovie("Arrival", 2016).WithStatus(library.StatusAvailable).WithRootPath(root).Insert(t, db)
+	unmounted := fixtures.NewMovie("Heat", 1995).WithStatus(library.StatusAvailable).WithRootPath(filepath.Join(root, "gone")).Insert(t, db)
+
+	addFile := func(contentID int64, name string, create bool) *library.File {
+		path := filepath.Join(root, name)
+		if create {
+			require.NoError(t, os.WriteFile(path, []byte("video"), 0o644))
+		}
+		f := &library.File{ContentID: contentID, Path: path, Quality: "1080p"}
+		require.NoError(t, store.AddFile(f))
+		return f
+	}
+	deleted := addFile(movie.ID, "dune.mkv", true)
+	addFile(kept.ID, "arrival.mkv", true)
+	addFile(unmounted.ID, "gone/heat.mkv", false)
+	require.NoError(t, os.Remove(deleted.Path))
+
+	bus := &recordingBus{}
+	var researched []int64
+	r := NewReconciler(store, bus, 2, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	r.SetResearch(func(_ context.Context, contentID int64) { researched = append(researched, contentID) })
+
+	// Batches of two: the third file waits for the next pass
+	result, err := r.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &ReconcileResult{Checked: 2, Missing: 1, Wanted: 1}, result)
+	result, err = r.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Checked)
+	assert.Zero(t, result.Missing, "files under a missing root folder are skipped")
+
+	f, err := store.GetFile(deleted.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, f.MissingAt, "record kept, marked missing")
+	got, err := store.GetContent(movie.ID)
+	require.NoError(t, err)
+	assert.Equal(t, library.StatusWanted, got.Status)
+	got, err = store.GetContent(unmounted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, library.StatusAvailable, got.Status)
+
+	require.Len(t, bus.events, 1)
+	missing, ok := bus.events[0].(*events.FileMissing)
+	require.True(t, ok)
+	assert.Equal(t, deleted.ID, missing.FileID)
+	assert.Equal(t, deleted.Path, missing.Path)
+	assert.True(t, missing.Wanted)
+	assert.Equal(t, []int64{movie.ID}, researched)
+
+	// Wrapped around: a file put back is noticed
+	require.NoError(t, os.WriteFile(deleted.Path, []byte("video"), 0o644))
+	result, err = r.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Returned)
+	got, err = store.GetContent(movie.ID)
+	require.NoError(t, err)
+	assert.Equal(t, library.StatusAvailable, got.Status)
+}
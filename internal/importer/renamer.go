@@ -4,19 +4,28 @@ package importer
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+
+	"github.com/vmunix/arrgo/internal/library"
 )
 
 // Default naming templates.
 const (
-	DefaultMovieTemplate  = "{title} ({year})/{title} ({year}) - {quality}.{ext}"
-	DefaultSeriesTemplate = "{title}/Season {season:02}/{title} - S{season:02}E{episode:02} - {quality}.{ext}"
+	DefaultMovieTemplate     = "{title} ({year})/{title} ({year}) - {quality}.{ext}"
+	DefaultSeriesTemplate    = "{title}/Season {season:02}/{title} - S{season:02}E{episode:02} - {quality}.{ext}"
+	DefaultAudiobookTemplate = "{author}/{title}/{title}{part}.{ext}"
 )
 
+// unknownAuthor is the author folder of audiobooks added without an author.
+const unknownAuthor = "Unknown Author"
+
 // Renamer applies naming templates to generate file paths.
 type Renamer struct {
-	movieTemplate  string
-	seriesTemplate string
+	movieTemplate     string
+	seriesTemplate    string
+	audiobookTemplate string
 }
 
 // NewRenamer creates a new Renamer with the given templates.
@@ -29,21 +38,171 @@ func NewRenamer(movieTemplate, seriesTemplate string) *Renamer {
 		seriesTemplate = DefaultSeriesTemplate
 	}
 	return &Renamer{
-		movieTemplate:  movieTemplate,
-		seriesTemplate: seriesTemplate,
+		movieTemplate:     movieTemplate,
+		seriesTemplate:    seriesTemplate,
+		audiobookTemplate: DefaultAudiobookTemplate,
+	}
+}
+
+// SetAudiobookTemplate sets the audiobook naming template.
+// An empty string keeps the default.
+func (r *Renamer) SetAudiobookTemplate(template string) {
+	if template != "" {
+		r.audiobookTemplate = template
+	}
+}
+
+// For returns a renamer that applies the naming overrides of a content item:
+// its own naming template, then for series whether to keep season folders.
+// Returns r itself if the content overrides nothing.
+func (r *Renamer) For(c *library.Content) *Renamer {
+	if c.NamingTemplate == "" && (c.SeasonFolder == nil || c.Type != library.ContentTypeSeries) {
+		return r
+	}
+	out := *r
+	switch c.Type {
+	case library.ContentTypeMovie:
+		if c.NamingTemplate != "" {
+			out.movieTemplate = c.NamingTemplate
+		}
+		return &out
+	case library.ContentTypeAudiobook:
+		if c.NamingTemplate != "" {
+			out.audiobookTemplate = c.NamingTemplate
+		}
+		return &out
 	}
+	if c.NamingTemplate != "" {
+		out.seriesTemplate = c.NamingTemplate
+	}
+	if c.SeasonFolder != nil {
+		out.seriesTemplate = withSeasonFolder(out.seriesTemplate, *c.SeasonFolder)
+	}
+	return &out
+}
+
+// seasonFolderDir is the season directory added to series templates without one.
+const seasonFolderDir = "Season {season:02}"
+
+// withSeasonFolder adds or removes the season directory of a series template.
+// A season directory is any directory of the template that uses {season}.
+func withSeasonFolder(template string, seasonFolder bool) string {
+	parts := strings.Split(template, "/")
+	dirs, file := parts[:len(parts)-1], parts[len(parts)-1]
+	hasSeason := func(dir string) bool { return strings.Contains(dir, "{season}") || strings.Contains(dir, "{season:") }
+
+	kept := make([]string, 0, len(parts))
+	found := false
+	for _, dir := range dirs {
+		if hasSeason(dir) {
+			found = true
+			if !seasonFolder {
+				continue
+			}
+		}
+		kept = append(kept, dir)
+	}
+	if seasonFolder && !found {
+		kept = append(kept, seasonFolderDir)
+	}
+	return strings.Join(append(kept, file), "/")
+}
+
+// Placeholders each kind of naming template may use.
+var (
+	moviePlaceholders     = []string{"title", "year", "quality", "ext", "part"}
+	seriesPlaceholders    = []string{"title", "season", "episode", "quality", "ext"}
+	audiobookPlaceholders = []string{"author", "title", "year", "ext", "part"}
+)
+
+// ValidateTemplate checks that a naming template for the given content type
+// uses only known placeholders. The error lists the valid ones.
+func ValidateTemplate(template string, contentType library.ContentType) error {
+	valid := seriesPlaceholders
+	switch contentType {
+	case library.ContentTypeMovie:
+		valid = moviePlaceholders
+	case library.ContentTypeAudiobook:
+		valid = audiobookPlaceholders
+	}
+	var unknown []string
+	for _, m := range formatPattern.FindAllStringSubmatch(template, -1) {
+		if !slices.Contains(valid, m[1]) && !slices.Contains(unknown, m[0]) {
+			unknown = append(unknown, m[0])
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	names := make([]string, len(valid))
+	for i, name := range valid {
+		names[i] = "{" + name + "}"
+	}
+	return fmt.Errorf("%w: unknown placeholder %s (valid: %s)",
+		ErrInvalidTemplate, strings.Join(unknown, ", "), strings.Join(names, ", "))
 }
 
 // MoviePath generates the relative path for a movie file.
 func (r *Renamer) MoviePath(title string, year int, quality, ext string) string {
+	return r.MoviePartPath(title, year, quality, ext, 0)
+}
+
+// MoviePartPath generates the relative path for one part of a multi-part
+// movie. The template's {part} placeholder becomes " - cd1", " - cd2", and so
+// on, the stacking suffix Plex recognizes, and is empty for a single file
+// (part 0). Templates without {part} get the suffix before the extension.
+func (r *Renamer) MoviePartPath(title string, year int, quality, ext string, part int) string {
 	title = SanitizeFilename(title)
+	suffix := ""
+	if part > 0 {
+		suffix = fmt.Sprintf(" - cd%d", part)
+	}
 	vars := map[string]any{
 		"title":   title,
 		"year":    year,
 		"quality": quality,
 		"ext":     ext,
+		"part":    suffix,
+	}
+	return applyPartTemplate(r.movieTemplate, vars, ext, suffix)
+}
+
+// applyPartTemplate applies a template with a {part} placeholder. Templates
+// without one get a non-empty part suffix before the extension, so the
+// files of one item don't overwrite each other.
+func applyPartTemplate(template string, vars map[string]any, ext, suffix string) string {
+	path := applyTemplate(template, vars)
+	if suffix == "" || strings.Contains(template, "{part}") {
+		return path
+	}
+	if base, ok := strings.CutSuffix(path, "."+ext); ok {
+		return base + suffix + "." + ext
+	}
+	return path + suffix
+}
+
+// AudiobookPath generates the relative path for one file of an audiobook.
+// The template's {part} placeholder becomes " - 01", " - 02", and so on for
+// a book in several files, and is empty for a single file (part 0); templates
+// without {part} get the suffix before the extension. Audiobooks without an
+// author go under "Unknown Author".
+func (r *Renamer) AudiobookPath(author, title string, year int, ext string, part int) string {
+	author = SanitizeFilename(author)
+	if author == "" {
+		author = unknownAuthor
+	}
+	suffix := ""
+	if part > 0 {
+		suffix = fmt.Sprintf(" - %02d", part)
+	}
+	vars := map[string]any{
+		"author": author,
+		"title":  SanitizeFilename(title),
+		"year":   year,
+		"ext":    ext,
+		"part":   suffix,
 	}
-	return applyTemplate(r.movieTemplate, vars)
+	return applyPartTemplate(r.audiobookTemplate, vars, ext, suffix)
 }
 
 // EpisodePath generates the relative path for an episode file.
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/library"
 )
 
 func TestRenamer_MoviePath(t *testing.T) {
@@ -44,6 +46,31 @@ func TestRenamer_MoviePath(t *testing.T) {
 	}
 }
 
+func TestRenamer_MoviePartPath(t *testing.T) {
+	r := NewRenamer("", "")
+	assert.Equal(t, "The Matrix (1999)/The Matrix (1999) - 1080p - cd2.avi",
+		r.MoviePartPath("The Matrix", 1999, "1080p", "avi", 2), "suffix goes before the extension")
+	assert.Equal(t, r.MoviePath("The Matrix", 1999, "1080p", "avi"),
+		r.MoviePartPath("The Matrix", 1999, "1080p", "avi", 0), "part 0 is a single file")
+
+	r = NewRenamer("{title} ({year}){part} [{quality}].{ext}", "")
+	assert.Equal(t, "The Matrix (1999) - cd1 [1080p].avi", r.MoviePartPath("The Matrix", 1999, "1080p", "avi", 1))
+	assert.Equal(t, "The Matrix (1999) [1080p].avi", r.MoviePath("The Matrix", 1999, "1080p", "avi"))
+}
+
+func TestRenamer_AudiobookPath(t *testing.T) {
+	r := NewRenamer("", "")
+	assert.Equal(t, "Andy Weir/Project Hail Mary/Project Hail Mary.m4b",
+		r.AudiobookPath("Andy Weir", "Project Hail Mary", 2021, "m4b", 0))
+	assert.Equal(t, "Andy Weir/Project Hail Mary/Project Hail Mary - 03.mp3",
+		r.AudiobookPath("Andy Weir", "Project Hail Mary", 2021, "mp3", 3))
+	assert.Equal(t, "Unknown Author/Dune/Dune.m4b", r.AudiobookPath("", "Dune", 1965, "m4b", 0))
+
+	r.SetAudiobookTemplate("{author}/{title} ({year}).{ext}")
+	assert.Equal(t, "Frank Herbert/Dune (1965) - 02.mp3",
+		r.AudiobookPath("Frank Herbert", "Dune", 1965, "mp3", 2), "suffix goes before the extension")
+}
+
 func TestRenamer_EpisodePath(t *testing.T) {
 	r := NewRenamer("", "") // Use defaults
 
@@ -137,3 +164,61 @@ func TestApplyTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestRenamer_For(t *testing.T) {
+	r := NewRenamer("", "")
+	noFolders, folders := false, true
+
+	movie := &library.Content{Type: library.ContentTypeMovie}
+	assert.Same(t, r, r.For(movie), "no overrides")
+	movie.NamingTemplate = "{title}.{ext}"
+	assert.Equal(t, "Movie.mkv", r.For(movie).MoviePath("Movie", 2024, "1080p", "mkv"))
+
+	series := &library.Content{Type: library.ContentTypeSeries, SeasonFolder: &noFolders}
+	assert.Equal(t, "Show/Show - S01E05 - 1080p.mkv", r.For(series).EpisodePath("Show", 1, 5, "1080p", "mkv"),
+		"season folder dropped from the library template")
+
+	series.NamingTemplate = "{title}/{title} - {episode:03}.{ext}"
+	series.SeasonFolder = nil
+	assert.Equal(t, "Show/Show - 005.mkv", r.For(series).EpisodePath("Show", 1, 5, "1080p", "mkv"))
+	series.SeasonFolder = &folders
+	assert.Equal(t, "Show/Season 01/Show - 005.mkv", r.For(series).EpisodePath("Show", 1, 5, "1080p", "mkv"),
+		"season folder added to a template without one")
+
+	assert.Equal(t, DefaultSeriesTemplate, r.seriesTemplate, "overrides leave the renamer alone")
+}
+
+func TestWithSeasonFolder(t *testing.T) {
+	tests := []struct {
+		template     string
+		seasonFolder bool
+		want         string
+	}{
+		{DefaultSeriesTemplate, false, "{title}/{title} - S{season:02}E{episode:02} - {quality}.{ext}"},
+		{DefaultSeriesTemplate, true, DefaultSeriesTemplate},
+		{"{title}/S{season}E{episode}.{ext}", true, "{title}/Season {season:02}/S{season}E{episode}.{ext}"},
+		{"{title}/S{season}E{episode}.{ext}", false, "{title}/S{season}E{episode}.{ext}"},
+		{"{title}/Series {season}/Part {season}/{episode}.{ext}", false, "{title}/{episode}.{ext}"},
+		{"{title} - {episode}.{ext}", true, "Season {season:02}/{title} - {episode}.{ext}"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, withSeasonFolder(tt.template, tt.seasonFolder), "%s (season folder %v)", tt.template, tt.seasonFolder)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	require.NoError(t, ValidateTemplate(DefaultMovieTemplate, library.ContentTypeMovie))
+	require.NoError(t, ValidateTemplate(DefaultSeriesTemplate, library.ContentTypeSeries))
+	require.NoError(t, ValidateTemplate("{title} - {part}.{ext}", library.ContentTypeMovie))
+	require.NoError(t, ValidateTemplate(DefaultAudiobookTemplate, library.ContentTypeAudiobook))
+	require.ErrorIs(t, ValidateTemplate("{author}/{season}.{ext}", library.ContentTypeAudiobook), ErrInvalidTemplate)
+
+	err := ValidateTemplate("{title} - {part}.{ext}", library.ContentTypeSeries)
+	require.ErrorIs(t, err, ErrInvalidTemplate)
+	assert.Contains(t, err.Error(), "unknown placeholder {part}")
+	assert.Contains(t, err.Error(), "{title}, {season}, {episode}, {quality}, {ext}")
+
+	err = ValidateTemplate("{title}/{absolute:03} {name} {absolute}.{ext}", library.ContentTypeSeries)
+	require.ErrorIs(t, err, ErrInvalidTemplate)
+	assert.Contains(t, err.Error(), "{absolute:03}, {name}, {absolute}")
+}
@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+	"github.com/vmunix/arrgo/pkg/release/scoring"
+)
+
+// stagingSuffix is appended to a copy that will replace an existing file, so
+// the old file stays in place until the import is recorded.
+const stagingSuffix = ".arrgo-import"
+
+// ImportOptions change how a download is imported.
+type ImportOptions struct {
+	// AllowDowngrade imports even when the library already has a
+	// higher-quality file for the content or episode.
+	AllowDowngrade bool
+}
+
+// compareQuality compares two files' qualities by resolution, then by
+// source when both are known. Returns a negative number when a is worse than
+// b, zero when they are equal, and a positive number when a is better.
+func compareQuality(aQuality string, aSource release.Source, bQuality string, bSource release.Source) int {
+	if d := library.QualityRank(aQuality) - library.QualityRank(bQuality); d != 0 {
+		return d
+	}
+	if aSource == release.SourceUnknown || bSource == release.SourceUnknown {
+		return 0
+	}
+	return scoring.SourceRank(aSource) - scoring.SourceRank(bSource)
+}
+
+// checkExisting compares the job against the files the library already has
+// for its content or episode. A worse import fails with
+// ErrImportWouldDowngrade unless opts allow it; an equal one replaces the
+// existing files. A better import leaves them alone.
+func (i *Importer) checkExisting(job *ImportJob, opts ImportOptions) error {
+	present := false
+	filter := library.FileFilter{ContentID: &job.Content.ID, Missing: &present}
+	if job.Episode != nil {
+		filter.EpisodeID = &job.Episode.ID
+	}
+	files, _, err := i.library.ListFiles(filter)
+	if err != nil {
+		return fmt.Errorf("list existing files: %w", err)
+	}
+
+	source := release.Parse(job.Download.ReleaseName).Source
+	for _, f := range files {
+		if job.Episode == nil && f.EpisodeID != nil {
+			continue
+		}
+		existing := release.Parse(filepath.Base(f.Path)).Source
+		switch cmp := compareQuality(job.Quality, source, f.Quality, existing); {
+		case cmp < 0 && !opts.AllowDowngrade:
+			return fmt.Errorf("%w: %s has %s, import is %s", ErrImportWouldDowngrade, f.Path, describeQuality(f.Quality, existing), describeQuality(job.Quality, source))
+		case cmp == 0:
+			job.Replaces = append(job.Replaces, f)
+		}
+	}
+	if len(job.Replaces) == 0 {
+		return nil
+	}
+
+	// Subtitles named after a replaced file go with it
+	filter.Kinds = []library.FileKind{library.FileKindSubtitle}
+	subtitles, _, err := i.library.ListFiles(filter)
+	if err != nil {
+		return fmt.Errorf("list existing subtitles: %w", err)
+	}
+	media := job.Replaces
+	for _, f := range media {
+		job.Replaces = append(job.Replaces, subtitlesOf(f, subtitles)...)
+	}
+	return nil
+}
+
+// describeQuality formats a quality and source for error messages.
+func describeQuality(quality string, source release.Source) string {
+	if quality == "" {
+		quality = "unknown quality"
+	}
+	if source == release.SourceUnknown {
+		return quality
+	}
+	return quality + " " + source.String()
+}
+
+// removeReplaced deletes the files of replaced records from disk, except
+// those the import wrote over.
+func (i *Importer) removeReplaced(replaced []*library.File, written map[string]bool) []string {
+	var removed []string
+	for _, f := range replaced {
+		if written[f.Path] {
+			continue
+		}
+		if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+			i.log.Warn("failed to remove replaced file", "path", f.Path, "error", err)
+			continue
+		}
+		removed = append(removed, f.Path)
+	}
+	return removed
+}
@@ -78,3 +78,20 @@ func IsVideoFile(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
 	return VideoExtensions[ext]
 }
+
+// AudioExtensions is the list of recognized audiobook file extensions.
+var AudioExtensions = map[string]bool{
+	".aac":  true,
+	".flac": true,
+	".m4a":  true,
+	".m4b":  true,
+	".mp3":  true,
+	".ogg":  true,
+	".opus": true,
+}
+
+// IsAudioFile checks if a path is an audiobook file.
+func IsAudioFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return AudioExtensions[ext]
+}
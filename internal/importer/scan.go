@@ -0,0 +1,240 @@
+// internal/importer/scan.go
+package importer
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+)
+
+// extraDirs are folder names that hold bonus material rather than the main video.
+// Names follow Plex's local extras conventions.
+var extraDirs = map[string]bool{
+	"behind the scenes": true,
+	"bonus":             true,
+	"deleted scenes":    true,
+	"extras":            true,
+	"featurettes":       true,
+	"interviews":        true,
+	"other":             true,
+	"sample":            true,
+	"samples":           true,
+	"scenes":            true,
+	"shorts":            true,
+	"trailers":          true,
+}
+
+// extraSuffixes mark an extra by file name, e.g. "Movie (2020)-trailer.mkv".
+var extraSuffixes = []string{
+	"-behindthescenes", "-deleted", "-featurette", "-interview",
+	"-other", "-sample", "-scene", "-short", "-trailer",
+}
+
+// Reasons a scanned item needs review instead of being imported.
+const (
+	ReviewNoTitle       = "could not parse title"
+	ReviewNoYear        = "no year in folder or file name"
+	ReviewMultipleFiles = "multiple video files of similar size"
+)
+
+// ScannedItem is a movie or series found by ScanDirectory.
+type ScannedItem struct {
+	Type   library.ContentType
+	Title  string
+	Year   int
+	Path   string        // Top-level folder or file under the scanned directory
+	Files  []ScannedFile // The movie file, or one file per episode
+	Review string        // Why the item could not be identified reliably; empty if it was
+}
+
+// ScannedFile is a video file belonging to a ScannedItem.
+type ScannedFile struct {
+	Path       string
+	Size       int64
+	Season     int // Series only
+	Episode    int // Series only; the first episode of multi-episode files
+	Resolution release.Resolution
+	Group      string // Release group parsed from the file name; empty if none
+}
+
+// ScanDirectory identifies the movies and series in a directory tree without
+// touching the library.
+//
+// Each top-level folder is one item, named by its folder ("The Matrix (1999)") or,
+// failing that, by its video files; video files directly in root are one item each.
+// An item whose files have SxxExx numbers is a series, otherwise a movie, whose
+// largest video file is the main file. Samples and extras are excluded by folder
+// and file name, and for movies by being much smaller than the main file.
+// Items that cannot be identified are returned with Review set.
+func ScanDirectory(root string) ([]*ScannedItem, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("scan directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("scan directory: %s is not a directory", root)
+	}
+
+	groups := make(map[string][]ScannedFile)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil //nolint:nilerr // Skip unreadable entries and keep scanning
+		}
+		name := d.Name()
+		if path != root && strings.HasPrefix(name, ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && extraDirs[strings.ToLower(name)] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !IsVideoFile(path) || isExtraFile(name) {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return nil //nolint:nilerr // Removed since the directory was read
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		top := strings.SplitN(rel, string(filepath.Separator), 2)[0]
+		parsed := release.Parse(strings.TrimSuffix(name, filepath.Ext(name)))
+		file := ScannedFile{Path: path, Size: fi.Size(), Resolution: parsed.Resolution, Group: parsed.Group}
+		if parsed.Season > 0 && parsed.Episode > 0 {
+			file.Season = parsed.Season
+			file.Episode = parsed.Episode
+		}
+		key := filepath.Join(root, top)
+		groups[key] = append(groups[key], file)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk directory: %w", err)
+	}
+
+	items := make([]*ScannedItem, 0, len(groups))
+	for path, files := range groups {
+		items = append(items, scanItem(path, files))
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Path < items[j].Path })
+	return items, nil
+}
+
+// scanItem identifies the content for one top-level folder or file.
+func scanItem(path string, files []ScannedFile) *ScannedItem {
+	item := &ScannedItem{Type: library.ContentTypeMovie, Path: path}
+
+	var episodes []ScannedFile
+	for _, f := range files {
+		if f.Episode > 0 {
+			episodes = append(episodes, f)
+		}
+	}
+
+	var named ScannedFile
+	if len(episodes) > 0 {
+		item.Type = library.ContentTypeSeries
+		item.Files = uniqueEpisodes(episodes)
+		named = item.Files[0]
+	} else {
+		sort.Slice(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+		item.Files = files[:1]
+		named = files[0]
+		for _, f := range files[1:] {
+			if f.Size*2 >= named.Size {
+				item.Review = ReviewMultipleFiles
+				item.Files = files
+				break
+			}
+		}
+	}
+
+	// A folder names its content better than the files in it
+	if named.Path != path {
+		item.Title, item.Year = scanTitle(filepath.Base(path))
+	}
+	if item.Title == "" || item.Year == 0 {
+		base := filepath.Base(named.Path)
+		title, year := scanTitle(strings.TrimSuffix(base, filepath.Ext(base)))
+		if item.Title == "" {
+			item.Title = title
+		}
+		if item.Year == 0 {
+			item.Year = year
+		}
+	}
+
+	switch {
+	case item.Review != "":
+	case item.Title == "":
+		item.Review = ReviewNoTitle
+	case item.Year == 0:
+		item.Review = ReviewNoYear
+	}
+	return item
+}
+
+// uniqueEpisodes keeps the largest file for each episode, ordered by season and episode.
+func uniqueEpisodes(files []ScannedFile) []ScannedFile {
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Season != files[j].Season {
+			return files[i].Season < files[j].Season
+		}
+		if files[i].Episode != files[j].Episode {
+			return files[i].Episode < files[j].Episode
+		}
+		return files[i].Size > files[j].Size
+	})
+	unique := files[:0]
+	for _, f := range files {
+		if n := len(unique); n > 0 && unique[n-1].Season == f.Season && unique[n-1].Episode == f.Episode {
+			continue
+		}
+		unique = append(unique, f)
+	}
+	return unique
+}
+
+// scanTitle returns the title and year in a folder or file name.
+// Names without release markers ("Breaking Bad") are used as the title as-is.
+func scanTitle(name string) (string, int) {
+	info := release.Parse(name)
+	title := info.Title
+	if title == "" && info.Season == 0 && info.Year == 0 && info.Resolution == release.ResolutionUnknown {
+		title = strings.NewReplacer(".", " ", "_", " ").Replace(name)
+	}
+	// The parser stops at the year, leaving "Title (" or "Title -"
+	title = strings.TrimRight(strings.TrimSpace(title), " -([{.")
+	return strings.TrimSpace(title), info.Year
+}
+
+// isExtraFile reports whether a video file name marks a sample or extra.
+func isExtraFile(name string) bool {
+	base := strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))
+	if strings.Contains(base, "sample") {
+		return true
+	}
+	for _, suffix := range extraSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+// internal/importer/scan_test.go
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+)
+
+// writeScanFile creates a file of the given size under root, creating parent folders.
+func writeScanFile(t *testing.T, root, rel string, size int) string {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	return path
+}
+
+func TestScanDirectory_Movies(t *testing.T) {
+	root := t.TempDir()
+	main := writeScanFile(t, root, "The Matrix (1999)/The.Matrix.1999.1080p.BluRay.x264.mkv", 1000)
+	writeScanFile(t, root, "The Matrix (1999)/The.Matrix.1999.1080p.BluRay.x264.sample.mkv", 900)
+	writeScanFile(t, root, "The Matrix (1999)/Featurettes/Making Of.mkv", 900)
+	writeScanFile(t, root, "The Matrix (1999)/The Matrix (1999)-trailer.mp4", 900)
+	writeScanFile(t, root, "The Matrix (1999)/bonus-clip.mkv", 100)
+	writeScanFile(t, root, "The Matrix (1999)/The.Matrix.1999.1080p.BluRay.x264.nfo", 10)
+	writeScanFile(t, root, "Heat.1995.2160p.WEB-DL.mkv", 500)
+
+	items, err := ScanDirectory(root)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	heat := items[0]
+	assert.Equal(t, library.ContentTypeMovie, heat.Type)
+	assert.Equal(t, "Heat", heat.Title)
+	assert.Equal(t, 1995, heat.Year)
+	assert.Empty(t, heat.Review)
+	require.Len(t, heat.Files, 1)
+	assert.Equal(t, release.Resolution2160p, heat.Files[0].Resolution)
+
+	matrix := items[1]
+	assert.Equal(t, library.ContentTypeMovie, matrix.Type)
+	assert.Equal(t, "The Matrix", matrix.Title)
+	assert.Equal(t, 1999, matrix.Year)
+	assert.Equal(t, filepath.Join(root, "The Matrix (1999)"), matrix.Path)
+	assert.Empty(t, matrix.Review)
+	require.Len(t, matrix.Files, 1, "samples, extras and small files are excluded")
+	assert.Equal(t, main, matrix.Files[0].Path)
+	assert.Equal(t, int64(1000), matrix.Files[0].Size)
+	assert.Equal(t, release.Resolution1080p, matrix.Files[0].Resolution)
+}
+
+func TestScanDirectory_Series(t *testing.T) {
+	root := t.TempDir()
+	writeScanFile(t, root, "Breaking Bad (2008)/Season 01/Breaking.Bad.S01E02.720p.mkv", 200)
+	writeScanFile(t, root, "Breaking Bad (2008)/Season 01/Breaking.Bad.S01E01.720p.mkv", 200)
+	writeScanFile(t, root, "Breaking Bad (2008)/Season 01/Breaking.Bad.S01E01.480p.mkv", 100)
+	writeScanFile(t, root, "Breaking Bad (2008)/Season 02/Breaking Bad - S02E01 - Seven Thirty-Seven.mkv", 200)
+	writeScanFile(t, root, "Breaking Bad (2008)/Extras/Breaking.Bad.S00E01.Pilot.Commentary.mkv", 200)
+
+	items, err := ScanDirectory(root)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	series := items[0]
+	assert.Equal(t, library.ContentTypeSeries, series.Type)
+	assert.Equal(t, "Breaking Bad", series.Title)
+	assert.Equal(t, 2008, series.Year)
+	assert.Empty(t, series.Review)
+	require.Len(t, series.Files, 3)
+
+	got := make([][2]int, len(series.Files))
+	for i, f := range series.Files {
+		got[i] = [2]int{f.Season, f.Episode}
+	}
+	assert.Equal(t, [][2]int{{1, 1}, {1, 2}, {2, 1}}, got)
+	assert.Equal(t, release.Resolution720p, series.Files[0].Resolution, "largest copy of a duplicate episode is kept")
+}
+
+func TestScanDirectory_NeedsReview(t *testing.T) {
+	root := t.TempDir()
+	writeScanFile(t, root, "Heat/Heat.mkv", 100)
+	writeScanFile(t, root, "The Office/The.Office.S01E01.mkv", 100)
+	writeScanFile(t, root, "Kill Bill (2003)/Kill.Bill.2003.Part1.mkv", 100)
+	writeScanFile(t, root, "Kill Bill (2003)/Kill.Bill.2003.Part2.mkv", 90)
+	writeScanFile(t, root, "1080p.mkv", 100)
+
+	items, err := ScanDirectory(root)
+	require.NoError(t, err)
+	require.Len(t, items, 4)
+
+	byPath := make(map[string]*ScannedItem, len(items))
+	for _, item := range items {
+		byPath[filepath.Base(item.Path)] = item
+	}
+
+	assert.Equal(t, ReviewNoTitle, byPath["1080p.mkv"].Review)
+	assert.Equal(t, ReviewNoYear, byPath["Heat"].Review)
+	assert.Equal(t, "Heat", byPath["Heat"].Title)
+	assert.Equal(t, ReviewNoYear, byPath["The Office"].Review)
+	assert.Equal(t, library.ContentTypeSeries, byPath["The Office"].Type)
+	assert.Equal(t, ReviewMultipleFiles, byPath["Kill Bill (2003)"].Review)
+	assert.Len(t, byPath["Kill Bill (2003)"].Files, 2)
+}
+
+func TestScanDirectory_NotADirectory(t *testing.T) {
+	file := writeScanFile(t, t.TempDir(), "movie.mkv", 10)
+
+	_, err := ScanDirectory(file)
+	require.Error(t, err)
+
+	_, err = ScanDirectory(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultScanWindow is how long scans of a library section are held back to
+// be sent together.
+const DefaultScanWindow = 30 * time.Second
+
+// DefaultScanMaxPaths is the most directories of a section scanned one by
+// one; a section with more to scan is refreshed whole.
+const DefaultScanMaxPaths = 5
+
+// shutdownScanTimeout bounds sending the scans still pending at shutdown.
+const shutdownScanTimeout = 10 * time.Second
+
+// sectionLocator is implemented by media servers whose libraries are split
+// into sections, such as Plex.
+type sectionLocator interface {
+	// SectionForPath returns the key of the library section containing path.
+	SectionForPath(ctx context.Context, path string) (string, error)
+}
+
+// ScanScheduler coalesces media server scans after imports. Scans of a
+// library section requested within a window are sent together when it ends:
+// a scan of each directory when few are involved, else one refresh of the
+// whole section. A season pack imported file by file thus sends one scan
+// instead of one per episode, which the server would queue behind each other.
+type ScanScheduler struct {
+	server   MediaServer
+	window   time.Duration
+	maxPaths int
+	log      *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	pending map[string]*pendingScan // By section key; "" if the server has no sections
+	wake    chan struct{}
+}
+
+// pendingScan is the scans of a section waiting to be sent.
+type pendingScan struct {
+	due   time.Time
+	paths map[string]string // Directory to the path requested in it
+}
+
+// NewScanScheduler creates a scheduler sending the scans of each section at
+// most once per window (DefaultScanWindow if 0 or less) and refreshing a
+// section whole beyond maxPaths directories (DefaultScanMaxPaths if 0 or less).
+func NewScanScheduler(server MediaServer, window time.Duration, maxPaths int, log *slog.Logger) *ScanScheduler {
+	if window <= 0 {
+		window = DefaultScanWindow
+	}
+	if maxPaths <= 0 {
+		maxPaths = DefaultScanMaxPaths
+	}
+	return &ScanScheduler{
+		server:   server,
+		window:   window,
+		maxPaths: maxPaths,
+		log:      log,
+		pending:  make(map[string]*pendingScan),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Schedule requests a scan of the directory containing path. While Run is
+// running the scan is held back to the end of its section's window and
+// scheduled is true; otherwise it is sent at once.
+func (s *ScanScheduler) Schedule(ctx context.Context, path string) (scheduled bool, err error) {
+	s.mu.Lock()
+	running := s.running
+	s.mu.Unlock()
+	if !running {
+		return false, s.server.ScanPath(ctx, path)
+	}
+
+	var section string
+	if locator, ok := s.server.(sectionLocator); ok {
+		if section, err = locator.SectionForPath(ctx, path); err != nil {
+			return false, err
+		}
+	}
+
+	s.mu.Lock()
+	if !s.running {
+		// Run stopped while the section was looked up
+		s.mu.Unlock()
+		return false, s.server.ScanPath(ctx, path)
+	}
+	p := s.pending[section]
+	if p == nil {
+		p = &pendingScan{due: time.Now().Add(s.window), paths: make(map[string]string)}
+		s.pending[section] = p
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	p.paths[filepath.Dir(path)] = path
+	s.mu.Unlock()
+	return true, nil
+}
+
+// Run sends scheduled scans as they fall due until the context is canceled,
+// then sends those still pending.
+func (s *ScanScheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	s.running = true
+	s.mu.Unlock()
+
+	timer := time.NewTimer(s.window)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.running = false
+			s.mu.Unlock()
+			sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownScanTimeout)
+			s.sendDue(sendCtx, true)
+			cancel()
+			return ctx.Err()
+		case <-s.wake:
+		case <-timer.C:
+		}
+		timer.Reset(s.sendDue(ctx, false))
+	}
+}
+
+// sendDue sends the scans of every section whose window has ended, or of all
+// sections, and returns the time until the next section falls due.
+func (s *ScanScheduler) sendDue(ctx context.Context, all bool) time.Duration {
+	now := time.Now()
+	next := s.window
+	due := make(map[string]*pendingScan)
+
+	s.mu.Lock()
+	for section, p := range s.pending {
+		if all || !p.due.After(now) {
+			due[section] = p
+			delete(s.pending, section)
+		} else if wait := p.due.Sub(now); wait < next {
+			next = wait
+		}
+	}
+	s.mu.Unlock()
+
+	for section, p := range due {
+		s.send(ctx, section, p)
+	}
+	return next
+}
+
+// send scans each directory of a section, or refreshes the section whole
+// when there are more than maxPaths. Failures are logged.
+func (s *ScanScheduler) send(ctx context.Context, section string, p *pendingScan) {
+	if section != "" && len(p.paths) > s.maxPaths {
+		if err := s.server.RefreshLibrary(ctx, section); err != nil {
+			s.log.Warn("media server refresh failed", "section", section, "error", err)
+			return
+		}
+		s.log.Debug("media server section refreshed", "section", section, "directories", len(p.paths))
+		return
+	}
+
+	dirs := make([]string, 0, len(p.paths))
+	for dir := range p.paths {
+		dirs = append(dirs, dir)
+	}
+	slices.Sort(dirs)
+	for _, dir := range dirs {
+		if err := s.server.ScanPath(ctx, p.paths[dir]); err != nil {
+			s.log.Warn("media server scan failed", "path", p.paths[dir], "error", err)
+			continue
+		}
+		s.log.Debug("media server scan sent", "section", section, "path", dir)
+	}
+}
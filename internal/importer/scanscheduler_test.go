@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMediaServer records scans; its sections are the top-level directories.
+type fakeMediaServer struct {
+	mu        sync.Mutex
+	scans     []string
+	refreshes []string
+}
+
+func (f *fakeMediaServer) HasContent(context.Context, string, int) (bool, error) { return false, nil }
+
+func (f *fakeMediaServer) SectionForPath(_ context.Context, path string) (string, error) {
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")[0], nil
+}
+
+func (f *fakeMediaServer) ScanPath(_ context.Context, path string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.scans = append(f.scans, filepath.Dir(path))
+	return nil
+}
+
+func (f *fakeMediaServer) RefreshLibrary(_ context.Context, section string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.refreshes = append(f.refreshes, section)
+	return nil
+}
+
+func (f *fakeMediaServer) calls() ([]string, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.scans...), append([]string(nil), f.refreshes...)
+}
+
+func TestScanScheduler(t *testing.T) {
+	server := &fakeMediaServer{}
+	s := NewScanScheduler(server, 50*time.Millisecond, 2, testLogger())
+
+	// Not running: scans go out at once
+	scheduled, err := s.Schedule(context.Background(), "/tv/Show/Season 01/e1.mkv")
+	require.NoError(t, err)
+	assert.False(t, scheduled)
+	scans, _ := server.calls()
+	assert.Equal(t, []string{"/tv/Show/Season 01"}, scans)
+	server.scans = nil
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.running
+	}, time.Second, time.Millisecond)
+
+	// A season pack's worth of imports in one directory: one scan
+	for e := 1; e <= 20; e++ {
+		scheduled, err := s.Schedule(context.Background(), fmt.Sprintf("/tv/Show/Season 01/e%d.mkv", e))
+		require.NoError(t, err)
+		assert.True(t, scheduled)
+	}
+	// Imports across more directories than maxPaths: one refresh of the section
+	for _, title := range []string{"A", "B", "C"} {
+		_, err := s.Schedule(context.Background(), "/movies/"+title+"/"+title+".mkv")
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool {
+		scans, refreshes := server.calls()
+		return len(scans) > 0 && len(refreshes) > 0
+	}, time.Second, 5*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	scans, refreshes := server.calls()
+	assert.Equal(t, []string{"/tv/Show/Season 01"}, scans, "rapid imports produce one scan")
+	assert.Equal(t, []string{"movies"}, refreshes)
+
+	// Pending scans are sent at shutdown
+	_, err = s.Schedule(context.Background(), "/tv/Other/e1.mkv")
+	require.NoError(t, err)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	scans, _ = server.calls()
+	assert.Equal(t, []string{"/tv/Show/Season 01", "/tv/Other"}, scans)
+}
@@ -0,0 +1,309 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// subtitleExts are the extensions of subtitle sidecars.
+var subtitleExts = map[string]bool{
+	".srt": true,
+	".ass": true,
+	".ssa": true,
+	".vtt": true,
+	".sub": true,
+	".idx": true,
+}
+
+// IsSubtitleFile checks if a file is a subtitle sidecar based on extension.
+func IsSubtitleFile(path string) bool {
+	return subtitleExts[strings.ToLower(filepath.Ext(path))]
+}
+
+// subtitleFolders are the names of folders releases keep subtitles in.
+var subtitleFolders = []string{"subs", "subtitles"}
+
+// languageNames lists the names and codes by which subtitle file names give
+// their language, keyed by ISO 639-1 code.
+var languageNames = map[string][]string{
+	"ar": {"ara", "arabic"},
+	"cs": {"cze", "ces", "czech"},
+	"da": {"dan", "danish"},
+	"de": {"ger", "deu", "german"},
+	"el": {"gre", "ell", "greek"},
+	"en": {"eng", "english"},
+	"es": {"spa", "spanish"},
+	"fi": {"fin", "finnish"},
+	"fr": {"fre", "fra", "french"},
+	"he": {"heb", "hebrew"},
+	"hu": {"hun", "hungarian"},
+	"it": {"ita", "italian"},
+	"ja": {"jpn", "japanese"},
+	"ko": {"kor", "korean"},
+	"nl": {"dut", "nld", "dutch"},
+	"no": {"nor", "nob", "norwegian"},
+	"pl": {"pol", "polish"},
+	"pt": {"por", "portuguese"},
+	"ro": {"rum", "ron", "romanian"},
+	"ru": {"rus", "russian"},
+	"sv": {"swe", "swedish"},
+	"tr": {"tur", "turkish"},
+	"zh": {"chi", "zho", "chinese"},
+}
+
+// languageCodes maps every code and name in languageNames to its ISO 639-1 code.
+var languageCodes = func() map[string]string {
+	codes := make(map[string]string)
+	for code, names := range languageNames {
+		codes[code] = code
+		for _, name := range names {
+			codes[name] = code
+		}
+	}
+	return codes
+}()
+
+// LanguageCode returns the ISO 639-1 code of a language given by code
+// (en, eng) or English name (English). Reports false for unknown languages.
+func LanguageCode(s string) (string, bool) {
+	code, ok := languageCodes[strings.ToLower(strings.TrimSpace(s))]
+	return code, ok
+}
+
+// Subtitle is a subtitle sidecar found next to a video.
+type Subtitle struct {
+	Path     string
+	Language string // ISO 639-1 code; empty if the file name gives none
+	Forced   bool   // Only translates foreign dialogue and signs
+	SDH      bool   // Describes sounds for the deaf and hard of hearing
+}
+
+// FindSubtitles returns the subtitle sidecars of a video, sorted by path:
+// subtitle files beside it or in a Subs folder beside it that are named after
+// it, and those in a folder of Subs named after it. When the video is the only
+// one in its directory besides samples and extras, every subtitle beside it or
+// directly in Subs is its own.
+func FindSubtitles(videoPath string) ([]Subtitle, error) {
+	dir := filepath.Dir(videoPath)
+	base := strings.TrimSuffix(filepath.Base(videoPath), filepath.Ext(videoPath))
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", dir, err)
+	}
+	videos := 0
+	for _, e := range entries {
+		if !e.IsDir() && IsVideoFile(e.Name()) && !isExtraFile(e.Name()) {
+			videos++
+		}
+	}
+
+	// own reports whether a subtitle file beside the video or in its Subs
+	// folder is the video's, and returns the hints in its name
+	own := func(name string) (string, bool) {
+		if hints, ok := subtitleHints(name, base); ok {
+			return hints, true
+		}
+		if videos == 1 && IsSubtitleFile(name) {
+			return strings.TrimSuffix(name, filepath.Ext(name)), true
+		}
+		return "", false
+	}
+
+	var subs []Subtitle
+	for _, e := range entries {
+		if !e.IsDir() {
+			if hints, ok := own(e.Name()); ok {
+				subs = append(subs, parseSubtitle(filepath.Join(dir, e.Name()), hints))
+			}
+			continue
+		}
+		if !slices.Contains(subtitleFolders, strings.ToLower(e.Name())) {
+			continue
+		}
+
+		folder := filepath.Join(dir, e.Name())
+		inner, err := os.ReadDir(folder)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", folder, err)
+		}
+		for _, f := range inner {
+			path := filepath.Join(folder, f.Name())
+			switch {
+			case f.IsDir() && strings.EqualFold(f.Name(), base):
+				found, err := folderSubtitles(path)
+				if err != nil {
+					return nil, err
+				}
+				subs = append(subs, found...)
+			case f.IsDir():
+			default:
+				if hints, ok := own(f.Name()); ok {
+					subs = append(subs, parseSubtitle(path, hints))
+				}
+			}
+		}
+	}
+	slices.SortFunc(subs, func(a, b Subtitle) int { return strings.Compare(a.Path, b.Path) })
+	return subs, nil
+}
+
+// folderSubtitles returns every subtitle file directly in a folder named after a video.
+func folderSubtitles(dir string) ([]Subtitle, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", dir, err)
+	}
+	var subs []Subtitle
+	for _, e := range entries {
+		if !e.IsDir() && IsSubtitleFile(e.Name()) {
+			subs = append(subs, parseSubtitle(filepath.Join(dir, e.Name()), strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))))
+		}
+	}
+	return subs, nil
+}
+
+// subtitleHints reports whether name is a subtitle file named after the video
+// base, and returns what follows the base (such as ".en.forced").
+func subtitleHints(name, base string) (string, bool) {
+	if !IsSubtitleFile(name) {
+		return "", false
+	}
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	if len(stem) < len(base) || !strings.EqualFold(stem[:len(base)], base) {
+		return "", false
+	}
+	rest := stem[len(base):]
+	if rest != "" && !strings.ContainsRune("._- ", rune(rest[0])) {
+		return "", false
+	}
+	return rest, true
+}
+
+// parseSubtitle reads the flags and language of a subtitle from the last
+// words of hints: flags in any order, preceded by the language, as in
+// "2_English" or ".en.forced". Other words, such as a release name, are
+// ignored.
+func parseSubtitle(path, hints string) Subtitle {
+	sub := Subtitle{Path: path}
+	words := strings.FieldsFunc(strings.ToLower(hints), func(r rune) bool {
+		return r == '.' || r == '_' || r == '-' || r == ' ' || r == '[' || r == ']' || r == '(' || r == ')'
+	})
+	for i := len(words) - 1; i >= 0; i-- {
+		switch words[i] {
+		case "forced":
+			sub.Forced = true
+		case "sdh", "cc", "hi":
+			sub.SDH = true
+		default:
+			sub.Language = languageCodes[words[i]]
+			return sub
+		}
+	}
+	return sub
+}
+
+// SubtitlePath returns where a subtitle of the video at videoDest goes: the
+// video's path with the language code, flags, and the subtitle's extension,
+// such as "Movie (2020).en.forced.srt".
+func SubtitlePath(videoDest string, sub Subtitle) string {
+	name := strings.TrimSuffix(videoDest, filepath.Ext(videoDest))
+	if sub.Language != "" {
+		name += "." + sub.Language
+	}
+	if sub.Forced {
+		name += ".forced"
+	}
+	if sub.SDH {
+		name += ".sdh"
+	}
+	return name + strings.ToLower(filepath.Ext(sub.Path))
+}
+
+// copySubtitles copies the subtitle sidecars of media imported from src,
+// whose record is media, beside it in the configured languages, and returns
+// records for them. Existing subtitles at the same paths are overwritten. A
+// subtitle that fails to copy is logged and skipped; it never fails an import.
+func (i *Importer) copySubtitles(ctx context.Context, src string, media *library.File) []*library.File {
+	if !i.subtitles {
+		return nil
+	}
+	found, err := FindSubtitles(src)
+	if err != nil {
+		i.log.Warn("failed to look for subtitles", "src", src, "error", err)
+		return nil
+	}
+
+	var files []*library.File
+	taken := make(map[string]bool)
+	for _, sub := range found {
+		if len(i.subLanguages) > 0 && !i.subLanguages[sub.Language] {
+			continue
+		}
+		dest := SubtitlePath(media.Path, sub)
+		if taken[dest] {
+			i.log.Debug("skipping subtitle with the same language as another", "src", sub.Path, "dest", dest)
+			continue
+		}
+		taken[dest] = true
+
+		if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+			i.log.Warn("failed to replace subtitle", "dest", dest, "error", err)
+			continue
+		}
+		size, err := CopyFileContext(ctx, sub.Path, dest)
+		if err != nil {
+			i.log.Warn("failed to copy subtitle", "src", sub.Path, "dest", dest, "error", err)
+			continue
+		}
+		files = append(files, &library.File{
+			ContentID:    media.ContentID,
+			EpisodeID:    media.EpisodeID,
+			Path:         dest,
+			SizeBytes:    size,
+			Source:       media.Source,
+			ReleaseGroup: media.ReleaseGroup,
+			Kind:         library.FileKindSubtitle,
+		})
+		i.log.Debug("copied subtitle", "src", sub.Path, "dest", dest, "language", sub.Language)
+	}
+	return files
+}
+
+// addSubtitles records copied subtitles. A subtitle already recorded at its
+// path, as when an import is resumed, keeps its record.
+func (i *Importer) addSubtitles(tx *library.Tx, files []*library.File) {
+	for _, f := range files {
+		if err := tx.AddFile(f); err != nil && !errors.Is(err, library.ErrDuplicate) {
+			i.log.Warn("failed to add subtitle record", "path", f.Path, "error", err)
+		}
+	}
+}
+
+// subtitlesOf returns the recorded subtitles among files named after a media file.
+func subtitlesOf(media *library.File, subtitles []*library.File) []*library.File {
+	stem := strings.TrimSuffix(media.Path, filepath.Ext(media.Path)) + "."
+	var own []*library.File
+	for _, f := range subtitles {
+		if strings.HasPrefix(f.Path, stem) {
+			own = append(own, f)
+		}
+	}
+	return own
+}
+
+// filePaths returns the paths of files.
+func filePaths(files []*library.File) []string {
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
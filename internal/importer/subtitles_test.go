@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSubtitles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"Show.S01E01.mkv",
+		"Show.S01E02.mkv",
+		"Show.S01E01.en.srt",
+		"Show.S01E01.forced.srt",
+		"Show.S01E010.srt", // Another episode's prefix
+		"Show.S01E02.Eng.srt",
+		"Subs/Show.S01E01/2_English.srt",
+		"Subs/Show.S01E01/3_Spanish.SDH.srt",
+		"Subs/Show.S01E02/2_English.srt",
+		"Subs/Show.S01E01.fre.ass",
+		"Subs/English.srt", // Not named after a video of several
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+
+	subs, err := FindSubtitles(filepath.Join(dir, "Show.S01E01.mkv"))
+	require.NoError(t, err)
+	assert.Equal(t, []Subtitle{
+		{Path: filepath.Join(dir, "Show.S01E01.en.srt"), Language: "en"},
+		{Path: filepath.Join(dir, "Show.S01E01.forced.srt"), Forced: true},
+		{Path: filepath.Join(dir, "Subs/Show.S01E01.fre.ass"), Language: "fr"},
+		{Path: filepath.Join(dir, "Subs/Show.S01E01/2_English.srt"), Language: "en"},
+		{Path: filepath.Join(dir, "Subs/Show.S01E01/3_Spanish.SDH.srt"), Language: "es", SDH: true},
+	}, subs)
+
+	// The only video of a release owns every subtitle beside it or in Subs
+	movie := t.TempDir()
+	for _, name := range []string{"It.2017.1080p.mkv", "It.2017.1080p.Sample.mkv", "It.2017.srt", "Subs/English.srt"} {
+		path := filepath.Join(movie, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+	subs, err = FindSubtitles(filepath.Join(movie, "It.2017.1080p.mkv"))
+	require.NoError(t, err)
+	assert.Equal(t, []Subtitle{
+		{Path: filepath.Join(movie, "It.2017.srt")}, // "It" in a title is no language
+		{Path: filepath.Join(movie, "Subs/English.srt"), Language: "en"},
+	}, subs)
+}
+
+func TestSubtitlePath(t *testing.T) {
+	dest := "/movies/Dune (2021)/Dune (2021) - 2160p.mkv"
+	assert.Equal(t, "/movies/Dune (2021)/Dune (2021) - 2160p.en.srt", SubtitlePath(dest, Subtitle{Path: "a/2_English.SRT", Language: "en"}))
+	assert.Equal(t, "/movies/Dune (2021)/Dune (2021) - 2160p.fr.forced.sdh.ass", SubtitlePath(dest, Subtitle{Path: "b.ass", Language: "fr", Forced: true, SDH: true}))
+	assert.Equal(t, "/movies/Dune (2021)/Dune (2021) - 2160p.srt", SubtitlePath(dest, Subtitle{Path: "c.srt"}))
+}
+
+func TestLanguageCode(t *testing.T) {
+	for _, s := range []string{"en", "eng", "English", " EN "} {
+		code, ok := LanguageCode(s)
+		assert.True(t, ok, s)
+		assert.Equal(t, "en", code, s)
+	}
+	_, ok := LanguageCode("klingon")
+	assert.False(t, ok)
+}
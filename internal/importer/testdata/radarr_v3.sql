@@ -0,0 +1,9 @@
+-- Trimmed Radarr v3 schema: metadata on Movies, profiles in Profiles.
+-- Regenerate the fixture with: sqlite3 radarr_v3.db < radarr_v3.sql
+CREATE TABLE Profiles (Id INTEGER PRIMARY KEY, Name TEXT NOT NULL, Cutoff INTEGER, Items TEXT);
+CREATE TABLE Movies (Id INTEGER PRIMARY KEY, Title TEXT NOT NULL, Year INTEGER, TmdbId INTEGER NOT NULL, Path TEXT NOT NULL, Monitored INTEGER NOT NULL, ProfileId INTEGER NOT NULL, MovieFileId INTEGER);
+CREATE TABLE MovieFiles (Id INTEGER PRIMARY KEY, MovieId INTEGER NOT NULL, RelativePath TEXT, Size INTEGER NOT NULL, SceneName TEXT, Quality TEXT);
+
+INSERT INTO Profiles VALUES (1, 'HD-720p', 4, '[]');
+INSERT INTO Movies VALUES (7, 'Heat', 1995, 949, '/movies/Heat (1995)', 1, 1, 70);
+INSERT INTO MovieFiles VALUES (70, 7, 'Heat.1995.720p.BluRay.x264-SiNNERS.mkv', 5000000000, NULL, '{}');
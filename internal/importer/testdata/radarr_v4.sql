@@ -0,0 +1,19 @@
+-- Trimmed Radarr v4 schema: movie metadata lives in MovieMetadata.
+-- Regenerate the fixture with: sqlite3 radarr_v4.db < radarr_v4.sql
+CREATE TABLE QualityProfiles (Id INTEGER PRIMARY KEY, Name TEXT NOT NULL, Cutoff INTEGER, Items TEXT);
+CREATE TABLE MovieMetadata (Id INTEGER PRIMARY KEY, TmdbId INTEGER NOT NULL, ImdbId TEXT, Title TEXT NOT NULL, Year INTEGER, Overview TEXT);
+CREATE TABLE Movies (Id INTEGER PRIMARY KEY, Path TEXT NOT NULL, Monitored INTEGER NOT NULL, QualityProfileId INTEGER NOT NULL, MovieFileId INTEGER, MovieMetadataId INTEGER NOT NULL, Added DATETIME);
+CREATE TABLE MovieFiles (Id INTEGER PRIMARY KEY, MovieId INTEGER NOT NULL, RelativePath TEXT, Size INTEGER NOT NULL, SceneName TEXT, ReleaseGroup TEXT, Quality TEXT);
+
+INSERT INTO QualityProfiles VALUES (1, 'Any', 1, '[]'), (4, 'HD-1080p', 7, '[]'), (5, 'Ultra-HD', 19, '[]');
+INSERT INTO MovieMetadata VALUES
+  (10, 603, 'tt0133093', 'The Matrix', 1999, ''),
+  (11, 27205, 'tt1375666', 'Inception', 2010, ''),
+  (12, 157336, 'tt0816692', 'Interstellar', 2014, '');
+INSERT INTO Movies VALUES
+  (1, '/movies/The Matrix (1999)', 1, 4, 100, 10, '2021-01-01'),
+  (2, '/movies/Inception (2010)', 1, 5, NULL, 11, '2021-01-01'),
+  (3, '/movies/Interstellar (2014)', 0, 1, 101, 12, '2021-01-01');
+INSERT INTO MovieFiles VALUES
+  (100, 1, 'The.Matrix.1999.1080p.BluRay.x264-GRP.mkv', 8000000000, 'The.Matrix.1999.1080p.BluRay.x264-GRP', 'GRP', '{}'),
+  (101, 3, 'Interstellar (2014).mkv', 30000000000, 'Interstellar.2014.2160p.UHD.BluRay.x265-TERMiNAL', NULL, '{}');
@@ -0,0 +1,22 @@
+-- Trimmed Sonarr v4 schema.
+-- Regenerate the fixture with: sqlite3 sonarr_v4.db < sonarr_v4.sql
+CREATE TABLE QualityProfiles (Id INTEGER PRIMARY KEY, Name TEXT NOT NULL, Cutoff INTEGER, Items TEXT);
+CREATE TABLE Series (Id INTEGER PRIMARY KEY, TvdbId INTEGER NOT NULL, Title TEXT NOT NULL, Year INTEGER, Path TEXT NOT NULL, Monitored INTEGER NOT NULL, QualityProfileId INTEGER NOT NULL, SeriesType INTEGER NOT NULL, Seasons TEXT);
+CREATE TABLE Episodes (Id INTEGER PRIMARY KEY, SeriesId INTEGER NOT NULL, SeasonNumber INTEGER NOT NULL, EpisodeNumber INTEGER NOT NULL, Title TEXT, AirDate TEXT, AirDateUtc DATETIME, Monitored INTEGER NOT NULL, EpisodeFileId INTEGER);
+CREATE TABLE EpisodeFiles (Id INTEGER PRIMARY KEY, SeriesId INTEGER NOT NULL, SeasonNumber INTEGER NOT NULL, RelativePath TEXT, Size INTEGER NOT NULL, SceneName TEXT, ReleaseGroup TEXT, Quality TEXT);
+
+INSERT INTO QualityProfiles VALUES (4, 'HD-1080p', 7, '[]'), (6, 'SD', 1, '[]');
+INSERT INTO Series VALUES
+  (1, 81189, 'Breaking Bad', 2008, '/tv/Breaking Bad', 1, 4, 0,
+   '[{"seasonNumber":1,"monitored":true},{"seasonNumber":2,"monitored":false}]'),
+  (2, 71256, 'The Daily Show', 1996, '/tv/The Daily Show', 1, 6, 1, NULL);
+INSERT INTO Episodes VALUES
+  (1, 1, 1, 1, 'Pilot', '2008-01-20', '2008-01-21 02:00:00Z', 1, 10),
+  (2, 1, 1, 2, 'Cat''s in the Bag...', '2008-01-27', '2008-01-28 02:00:00Z', 1, 11),
+  (3, 1, 1, 3, '...And the Bag''s in the River', '2008-02-10', '2008-02-11 02:00:00Z', 1, 11),
+  (4, 1, 1, 4, 'Cancer Man', '2008-02-17', '2008-02-18 02:00:00Z', 1, 0),
+  (5, 1, 2, 1, 'Seven Thirty-Seven', '2009-03-08', '2009-03-09 02:00:00Z', 0, NULL),
+  (6, 2, 2024, 1, 'Guest', '2024-01-15', NULL, 1, NULL);
+INSERT INTO EpisodeFiles VALUES
+  (10, 1, 1, 'Season 01/Breaking.Bad.S01E01.1080p.BluRay.x264-DEMAND.mkv', 2000000000, NULL, 'DEMAND', '{}'),
+  (11, 1, 1, 'Season 01/Breaking.Bad.S01E02-E03.720p.HDTV.x264-CTU.mkv', 1500000000, 'Breaking.Bad.S01E02-E03.720p.HDTV.x264-CTU', NULL, '{}');
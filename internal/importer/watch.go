@@ -0,0 +1,262 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+)
+
+// rejectedDir is the watch directory subfolder that receives files which could not be imported.
+const rejectedDir = "rejected"
+
+// WatchedImport describes a file imported from the watch directory.
+type WatchedImport struct {
+	DownloadID int64
+	ContentID  int64
+	EpisodeID  *int64
+	Result     *ImportResult
+}
+
+// Watcher imports video files dropped into the importer's watch directory.
+//
+// Each scan looks at the top level of the directory. A file is only processed once
+// its size is unchanged since the previous scan, so files still being written are skipped.
+// Files are matched to library content by parsed title and year, imported through the
+// normal Import flow and removed from the watch directory. Files that cannot be imported
+// are moved to rejected/ with a .txt sidecar explaining why.
+type Watcher struct {
+	importer *Importer
+	onImport func(context.Context, *WatchedImport) // optional
+	sizes    map[string]int64                      // file size at the previous scan, by path
+}
+
+// NewWatcher creates a watcher for the importer's configured watch directory.
+// onImport, if non-nil, is called after each successful import.
+func NewWatcher(imp *Importer, onImport func(context.Context, *WatchedImport)) *Watcher {
+	return &Watcher{
+		importer: imp,
+		onImport: onImport,
+		sizes:    make(map[string]int64),
+	}
+}
+
+// Run scans the watch directory every interval until the context is canceled.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Scan(ctx); err != nil && ctx.Err() == nil {
+			w.importer.log.Warn("watch directory scan failed", "dir", w.importer.watchDir, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan processes files in the watch directory whose size has been stable since the last scan.
+// Returns the number of files imported.
+func (w *Watcher) Scan(ctx context.Context) (int, error) {
+	dir := w.importer.watchDir
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("read watch dir: %w", err)
+	}
+
+	seen := make(map[string]bool, len(entries))
+	imported := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return imported, ctx.Err()
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Removed since ReadDir
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		seen[path] = true
+		if prev, ok := w.sizes[path]; !ok || prev != info.Size() {
+			w.sizes[path] = info.Size()
+			continue
+		}
+		delete(w.sizes, path)
+
+		if err := w.importFile(ctx, path); err != nil {
+			w.importer.log.Warn("watched file rejected", "path", path, "error", err)
+			if rerr := w.reject(path, err); rerr != nil {
+				w.importer.log.Error("reject watched file failed", "path", path, "error", rerr)
+			}
+			continue
+		}
+		imported++
+	}
+
+	// Forget files that disappeared between scans
+	for path := range w.sizes {
+		if !seen[path] {
+			delete(w.sizes, path)
+		}
+	}
+	return imported, nil
+}
+
+// importFile matches a watched file to library content and imports it.
+func (w *Watcher) importFile(ctx context.Context, path string) error {
+	if !IsVideoFile(path) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedExtension, filepath.Ext(path))
+	}
+
+	releaseName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	info := release.Parse(releaseName)
+
+	content, err := w.matchContent(info)
+	if err != nil {
+		return err
+	}
+
+	var episodeID *int64
+	if content.Type == library.ContentTypeSeries {
+		var ep *library.Episode
+		if airDate, ok := MatchFileToAirDate(path, content.Daily); ok {
+			ep, _, err = w.importer.library.FindOrCreateEpisodeByDate(content.ID, airDate)
+		} else {
+			if info.Season == 0 || info.Episode == 0 {
+				return ErrEpisodeNotSpecified
+			}
+			ep, _, err = w.importer.library.FindOrCreateEpisode(content.ID, info.Season, info.Episode)
+		}
+		if err != nil {
+			return fmt.Errorf("find episode: %w", err)
+		}
+		episodeID = &ep.ID
+	}
+
+	// Create download record for audit trail
+	now := time.Now()
+	dl := &download.Download{
+		ContentID:   content.ID,
+		EpisodeID:   episodeID,
+		Client:      download.ClientManual,
+		ClientID:    fmt.Sprintf("manual-%d", now.UnixNano()),
+		Status:      download.StatusCompleted,
+		ReleaseName: releaseName,
+		Indexer:     "watch",
+		CompletedAt: &now,
+	}
+	if err := w.importer.downloads.Add(dl); err != nil {
+		return fmt.Errorf("add download: %w", err)
+	}
+
+	result, err := w.importer.Import(ctx, dl.ID, path)
+	if err != nil {
+		return err
+	}
+
+	// The file now lives in the library; drop it so it isn't picked up again
+	if err := os.Remove(path); err != nil {
+		w.importer.log.Warn("remove watched file failed", "path", path, "error", err)
+	}
+
+	if w.onImport != nil {
+		w.onImport(ctx, &WatchedImport{
+			DownloadID: dl.ID,
+			ContentID:  content.ID,
+			EpisodeID:  episodeID,
+			Result:     result,
+		})
+	}
+	return nil
+}
+
+// matchContent finds the library content for a parsed release.
+// Releases with an episode number match series, others match movies.
+// The year is only compared when the release has one.
+// If nothing matches and auto-add is enabled, new content is created.
+func (w *Watcher) matchContent(info *release.Info) (*library.Content, error) {
+	if info.Title == "" {
+		return nil, fmt.Errorf("%w: could not parse title", ErrNoTitleMatch)
+	}
+
+	// Dated releases are episodes of daily shows
+	contentType := library.ContentTypeMovie
+	if info.Episode > 0 || info.DailyDate != "" {
+		contentType = library.ContentTypeSeries
+	}
+
+	filter := library.ContentFilter{Type: &contentType}
+	if info.Year > 0 {
+		filter.Year = &info.Year
+	}
+	candidates, _, err := w.importer.library.ListContent(filter)
+	if err != nil {
+		return nil, fmt.Errorf("list content: %w", err)
+	}
+
+	title := release.CleanTitle(info.Title)
+	var matches []*library.Content
+	for _, c := range candidates {
+		if release.CleanTitle(c.Title) == title {
+			matches = append(matches, c)
+		}
+	}
+
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("%w: %q matches %d %s entries", ErrAmbiguousMatch, info.Title, len(matches), contentType)
+	case !w.importer.autoAdd:
+		return nil, fmt.Errorf("%w: %s %q (%d)", ErrNoTitleMatch, contentType, info.Title, info.Year)
+	}
+
+	rootPath := w.importer.movieRoot
+	if contentType == library.ContentTypeSeries {
+		rootPath = w.importer.seriesRoot
+	}
+	content := &library.Content{
+		Type:           contentType,
+		Title:          info.Title,
+		Year:           info.Year,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       rootPath,
+		Daily:          info.DailyDate != "" && info.Episode == 0,
+	}
+	if err := w.importer.library.AddContent(content); err != nil {
+		return nil, fmt.Errorf("add content: %w", err)
+	}
+	w.importer.log.Info("added content from watch directory", "content_id", content.ID, "title", content.Title, "year", content.Year)
+	return content, nil
+}
+
+// reject moves a file to the rejected subfolder and writes a sidecar with the reason.
+func (w *Watcher) reject(path string, reason error) error {
+	dir := filepath.Join(w.importer.watchDir, rejectedDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create rejected dir: %w", err)
+	}
+
+	dest := filepath.Join(dir, filepath.Base(path))
+	if err := os.Rename(path, dest); err != nil {
+		return fmt.Errorf("move to rejected: %w", err)
+	}
+	if err := os.WriteFile(dest+".txt", []byte(reason.Error()+"\n"), 0644); err != nil {
+		return fmt.Errorf("write reject reason: %w", err)
+	}
+	return nil
+}
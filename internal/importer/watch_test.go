@@ -0,0 +1,179 @@
+// internal/importer/watch_test.go
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+func setupTestWatcher(t *testing.T, autoAdd bool) (*Watcher, *Importer, *sql.DB, *[]*WatchedImport) {
+	t.Helper()
+	imp, db, _, _ := setupTestImporter(t)
+	imp.watchDir = t.TempDir()
+	imp.autoAdd = autoAdd
+
+	var imported []*WatchedImport
+	w := NewWatcher(imp, func(_ context.Context, wi *WatchedImport) {
+		imported = append(imported, wi)
+	})
+	return w, imp, db, &imported
+}
+
+func writeWatchedFile(t *testing.T, dir, name string, size int) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	return path
+}
+
+// scanTwice runs two scans so files with a stable size are processed.
+func scanTwice(t *testing.T, w *Watcher) int {
+	t.Helper()
+	n, err := w.Scan(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, n, "first scan should only record sizes")
+	n, err = w.Scan(context.Background())
+	require.NoError(t, err)
+	return n
+}
+
+func assertRejected(t *testing.T, dir, name, reason string) {
+	t.Helper()
+	assert.NoFileExists(t, filepath.Join(dir, name))
+	assert.FileExists(t, filepath.Join(dir, rejectedDir, name))
+	data, err := os.ReadFile(filepath.Join(dir, rejectedDir, name+".txt"))
+	require.NoError(t, err, "read sidecar")
+	assert.Contains(t, string(data), reason)
+}
+
+func TestWatcher_ImportsMatchedMovie(t *testing.T) {
+	w, imp, db, imported := setupTestWatcher(t, false)
+	dir := imp.watchDir
+	contentID := insertTestContent(t, db)
+	path := writeWatchedFile(t, dir, "Test.Movie.2024.1080p.BluRay.mkv", 1000)
+
+	n := scanTwice(t, w)
+	assert.Equal(t, 1, n)
+	assert.NoFileExists(t, path, "imported file should be removed from watch dir")
+
+	require.Len(t, *imported, 1)
+	wi := (*imported)[0]
+	assert.Equal(t, contentID, wi.ContentID)
+	assert.Nil(t, wi.EpisodeID)
+	assert.FileExists(t, wi.Result.DestPath)
+	assert.Equal(t, "1080p", wi.Result.Quality)
+
+	content, err := imp.library.GetContent(contentID)
+	require.NoError(t, err)
+	assert.Equal(t, library.StatusAvailable, content.Status)
+}
+
+func TestWatcher_SkipsGrowingFile(t *testing.T) {
+	w, imp, db, imported := setupTestWatcher(t, false)
+	dir := imp.watchDir
+	insertTestContent(t, db)
+	writeWatchedFile(t, dir, "Test.Movie.2024.1080p.BluRay.mkv", 1000)
+
+	n, err := w.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// Still being written
+	writeWatchedFile(t, dir, "Test.Movie.2024.1080p.BluRay.mkv", 2000)
+	n, err = w.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Empty(t, *imported)
+
+	n, err = w.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestWatcher_RejectsNoMatch(t *testing.T) {
+	w, imp, _, imported := setupTestWatcher(t, false)
+	dir := imp.watchDir
+	writeWatchedFile(t, dir, "Unknown.Film.2019.720p.WEB-DL.mkv", 1000)
+
+	assert.Zero(t, scanTwice(t, w))
+	assert.Empty(t, *imported)
+	assertRejected(t, dir, "Unknown.Film.2019.720p.WEB-DL.mkv", ErrNoTitleMatch.Error())
+}
+
+func TestWatcher_RejectsAmbiguousMatch(t *testing.T) {
+	w, imp, db, _ := setupTestWatcher(t, false)
+	dir := imp.watchDir
+	insertTestContent(t, db)
+	// Only databases from before the unique title index can hold a duplicate,
+	// so it is inserted around the store, without a normalized title
+	_, err := db.Exec(`
+		INSERT INTO content (type, title, year, status, quality_profile, root_path)
+		VALUES ('movie', 'Test Movie', 2024, 'wanted', 'hd', '/movies')`)
+	require.NoError(t, err)
+	writeWatchedFile(t, dir, "Test.Movie.2024.1080p.BluRay.mkv", 1000)
+
+	assert.Zero(t, scanTwice(t, w))
+	assertRejected(t, dir, "Test.Movie.2024.1080p.BluRay.mkv", ErrAmbiguousMatch.Error())
+}
+
+func TestWatcher_RejectsUnsupportedExtension(t *testing.T) {
+	w, imp, _, _ := setupTestWatcher(t, true)
+	dir := imp.watchDir
+	writeWatchedFile(t, dir, "Test.Movie.2024.1080p.BluRay.nfo", 100)
+
+	assert.Zero(t, scanTwice(t, w))
+	assertRejected(t, dir, "Test.Movie.2024.1080p.BluRay.nfo", ErrUnsupportedExtension.Error())
+}
+
+func TestWatcher_AutoAddSeriesEpisode(t *testing.T) {
+	w, imp, _, imported := setupTestWatcher(t, true)
+	dir := imp.watchDir
+	writeWatchedFile(t, dir, "Some.Show.S02E05.720p.HDTV.mkv", 1000)
+
+	assert.Equal(t, 1, scanTwice(t, w))
+	require.Len(t, *imported, 1)
+	wi := (*imported)[0]
+	require.NotNil(t, wi.EpisodeID)
+
+	content, err := imp.library.GetContent(wi.ContentID)
+	require.NoError(t, err)
+	assert.Equal(t, library.ContentTypeSeries, content.Type)
+	assert.Equal(t, "Some Show", content.Title)
+
+	ep, err := imp.library.GetEpisode(*wi.EpisodeID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ep.Season)
+	assert.Equal(t, 5, ep.Episode)
+	assert.Equal(t, library.StatusAvailable, ep.Status)
+}
+
+func TestWatcher_AutoAddDailyEpisode(t *testing.T) {
+	w, imp, _, imported := setupTestWatcher(t, true)
+	dir := imp.watchDir
+	writeWatchedFile(t, dir, "The.Daily.Show.2024.03.14.Guest.Name.1080p.WEB.mkv", 1000)
+
+	assert.Equal(t, 1, scanTwice(t, w))
+	require.Len(t, *imported, 1)
+	wi := (*imported)[0]
+	require.NotNil(t, wi.EpisodeID)
+
+	content, err := imp.library.GetContent(wi.ContentID)
+	require.NoError(t, err)
+	assert.Equal(t, library.ContentTypeSeries, content.Type)
+	assert.Equal(t, "The Daily Show", content.Title)
+	assert.True(t, content.Daily)
+
+	ep, err := imp.library.GetEpisode(*wi.EpisodeID)
+	require.NoError(t, err)
+	assert.Equal(t, 2024, ep.Season)
+	require.NotNil(t, ep.AirDate)
+	assert.Equal(t, "2024-03-14", ep.AirDate.Format(time.DateOnly))
+}
@@ -0,0 +1,177 @@
+package library
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AliasSource records where an alias came from.
+type AliasSource string
+
+const (
+	AliasSourceTVDB   AliasSource = "tvdb"   // Replaced on every series sync
+	AliasSourceManual AliasSource = "manual" // Added by the user; kept across syncs
+)
+
+// Alias is an alternate title for content, such as "La Casa de Papel" for "Money Heist".
+type Alias struct {
+	ID        int64
+	ContentID int64
+	Alias     string
+	Source    AliasSource
+	AddedAt   time.Time
+}
+
+// ListAliases returns the aliases of a content item, oldest first.
+func (s *Store) ListAliases(contentID int64) ([]*Alias, error) {
+	rows, err := s.db.Query(`
+		SELECT id, content_id, alias, source, added_at
+		FROM content_aliases WHERE content_id = ? ORDER BY id`, contentID)
+	if err != nil {
+		return nil, fmt.Errorf("list aliases: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var aliases []*Alias
+	for rows.Next() {
+		a := &Alias{}
+		if err := rows.Scan(&a.ID, &a.ContentID, &a.Alias, &a.Source, &a.AddedAt); err != nil {
+			return nil, fmt.Errorf("scan alias: %w", err)
+		}
+		aliases = append(aliases, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// Titles returns the content's title followed by its aliases.
+func (s *Store) Titles(c *Content) ([]string, error) {
+	aliases, err := s.ListAliases(c.ID)
+	if err != nil {
+		return nil, err
+	}
+	titles := make([]string, 0, len(aliases)+1)
+	titles = append(titles, c.Title)
+	for _, a := range aliases {
+		titles = append(titles, a.Alias)
+	}
+	return titles, nil
+}
+
+// AddAlias adds an alias to a content item and sets ID and AddedAt on it.
+// Returns ErrNotFound if the content does not exist, and ErrDuplicate if the
+// alias normalizes to the content's title or to one of its existing aliases.
+// An alias that is empty after normalization returns ErrConstraint.
+func (s *Store) AddAlias(a *Alias) error {
+	a.Alias = strings.TrimSpace(a.Alias)
+	normalized := normalizeTitle(a.Alias)
+	if normalized == "" {
+		return fmt.Errorf("add alias: empty alias: %w", ErrConstraint)
+	}
+
+	var title string
+	if err := s.db.QueryRow("SELECT title FROM content WHERE id = ?", a.ContentID).Scan(&title); err != nil {
+		return fmt.Errorf("add alias: content %d: %w", a.ContentID, mapSQLiteError(err))
+	}
+	if normalizeTitle(title) == normalized {
+		return fmt.Errorf("add alias: %q is the content's title: %w", a.Alias, ErrDuplicate)
+	}
+
+	now := time.Now()
+	result, err := s.db.Exec(`
+		INSERT INTO content_aliases (content_id, alias, normalized_alias, source, added_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		a.ContentID, a.Alias, normalized, a.Source, now)
+	if err != nil {
+		return fmt.Errorf("add alias: %w", mapSQLiteError(err))
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	a.ID = id
+	a.AddedAt = now
+	return nil
+}
+
+// ReplaceAliases sets the aliases a source provides for a content item,
+// removing ones it no longer lists. Aliases from other sources are kept, and
+// names matching the title or an alias already stored are skipped.
+// Returns the number of aliases added.
+func (s *Store) ReplaceAliases(contentID int64, source AliasSource, names []string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var title string
+	if err := tx.QueryRow("SELECT title FROM content WHERE id = ?", contentID).Scan(&title); err != nil {
+		return 0, fmt.Errorf("replace aliases: content %d: %w", contentID, mapSQLiteError(err))
+	}
+	if _, err := tx.Exec("DELETE FROM content_aliases WHERE content_id = ? AND source = ?", contentID, source); err != nil {
+		return 0, fmt.Errorf("delete aliases: %w", err)
+	}
+
+	seen := map[string]bool{normalizeTitle(title): true}
+	now := time.Now()
+	added := 0
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		normalized := normalizeTitle(name)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result, err := tx.Exec(`
+			INSERT OR IGNORE INTO content_aliases (content_id, alias, normalized_alias, source, added_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			contentID, name, normalized, source, now)
+		if err != nil {
+			return 0, fmt.Errorf("insert alias: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return added, nil
+}
+
+// DeleteAlias removes an alias from a content item.
+// Returns ErrNotFound if the content has no such alias.
+func (s *Store) DeleteAlias(contentID, aliasID int64) error {
+	result, err := s.db.Exec("DELETE FROM content_aliases WHERE id = ? AND content_id = ?", aliasID, contentID)
+	if err != nil {
+		return fmt.Errorf("delete alias %d: %w", aliasID, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("delete alias %d: %w", aliasID, ErrNotFound)
+	}
+	return nil
+}
+
+// aliasOwner returns the ID of content of the given type and year with an alias
+// that normalizes to title, or 0 if there is none.
+func aliasOwner(q querier, contentType ContentType, title string, year int) (int64, error) {
+	var id int64
+	err := q.QueryRow(`
+		SELECT c.id FROM content c
+		JOIN content_aliases a ON a.content_id = c.id
+		WHERE c.type = ? AND c.year = ? AND a.normalized_alias = ?
+		LIMIT 1`, contentType, year, normalizeTitle(title)).Scan(&id)
+	if err != nil {
+		if errors.Is(mapSQLiteError(err), ErrNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("check aliases: %w", err)
+	}
+	return id, nil
+}
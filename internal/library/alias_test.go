@@ -0,0 +1,129 @@
+package library
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func addSeries(t *testing.T, store *Store, title string, year int) *Content {
+	t.Helper()
+	c := &Content{Type: ContentTypeSeries, Title: title, Year: year, Status: StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, store.AddContent(c))
+	return c
+}
+
+func aliasNames(aliases []*Alias) []string {
+	names := make([]string, len(aliases))
+	for i, a := range aliases {
+		names[i] = a.Alias
+	}
+	return names
+}
+
+func TestStore_AddAlias(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	c := addSeries(t, store, "Money Heist", 2017)
+
+	a := &Alias{ContentID: c.ID, Alias: " La Casa de Papel ", Source: AliasSourceManual}
+	require.NoError(t, store.AddAlias(a))
+	assert.NotZero(t, a.ID)
+	assert.Equal(t, "La Casa de Papel", a.Alias)
+
+	// Same alias with different punctuation or case
+	err := store.AddAlias(&Alias{ContentID: c.ID, Alias: "la casa de papel!", Source: AliasSourceManual})
+	require.ErrorIs(t, err, ErrDuplicate)
+
+	// The title itself is not an alias
+	err = store.AddAlias(&Alias{ContentID: c.ID, Alias: "money-heist", Source: AliasSourceManual})
+	require.ErrorIs(t, err, ErrDuplicate)
+
+	err = store.AddAlias(&Alias{ContentID: c.ID, Alias: " ?! ", Source: AliasSourceManual})
+	require.ErrorIs(t, err, ErrConstraint)
+
+	err = store.AddAlias(&Alias{ContentID: 999, Alias: "Other", Source: AliasSourceManual})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	aliases, err := store.ListAliases(c.ID)
+	require.NoError(t, err)
+	require.Len(t, aliases, 1)
+	assert.Equal(t, AliasSourceManual, aliases[0].Source)
+
+	titles, err := store.Titles(c)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Money Heist", "La Casa de Papel"}, titles)
+
+	require.NoError(t, store.DeleteAlias(c.ID, a.ID))
+	require.ErrorIs(t, store.DeleteAlias(c.ID, a.ID), ErrNotFound)
+}
+
+func TestStore_ReplaceAliases(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	c := addSeries(t, store, "Money Heist", 2017)
+	require.NoError(t, store.AddAlias(&Alias{ContentID: c.ID, Alias: "Heist", Source: AliasSourceManual}))
+
+	added, err := store.ReplaceAliases(c.ID, AliasSourceTVDB, []string{"La Casa de Papel", "la casa de papel", "Money Heist", "Haus des Geldes", ""})
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+
+	aliases, err := store.ListAliases(c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Heist", "La Casa de Papel", "Haus des Geldes"}, aliasNames(aliases))
+
+	// A later sync drops aliases TVDB no longer lists but keeps manual ones
+	added, err = store.ReplaceAliases(c.ID, AliasSourceTVDB, []string{"La Casa de Papel", "heist"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	aliases, err = store.ListAliases(c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Heist", "La Casa de Papel"}, aliasNames(aliases))
+
+	_, err = store.ReplaceAliases(999, AliasSourceTVDB, []string{"x"})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_UpdateContent_RetitleToAlias(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	heist := addSeries(t, store, "Money Heist", 2017)
+	require.NoError(t, store.AddAlias(&Alias{ContentID: heist.ID, Alias: "La Casa de Papel", Source: AliasSourceManual}))
+	other := addSeries(t, store, "Casa de Papel", 2017)
+
+	other.Title = "La Casa de Papel"
+	require.ErrorIs(t, store.UpdateContent(other), ErrDuplicate)
+
+	// Retitling content to its own alias is allowed
+	heist.Title = "La Casa de Papel"
+	require.NoError(t, store.UpdateContent(heist))
+}
+
+func TestStore_TitleLookupMatchesAliases(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	c := addSeries(t, store, "Money Heist", 2017)
+	_, err := store.ReplaceAliases(c.ID, AliasSourceTVDB, []string{"La Casa de Papel"})
+	require.NoError(t, err)
+
+	got, err := store.GetByTitleYear("La Casa de Papel", 2017)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, c.ID, got.ID)
+
+	got, err = store.GetByTitleYear("La Casa de Papel", 2020)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// Adding the alias as new content of the same type and year is a duplicate
+	err = store.AddContent(&Content{Type: ContentTypeSeries, Title: "La Casa De Papel", Year: 2017, Status: StatusWanted, QualityProfile: "hd", RootPath: "/tv"})
+	require.ErrorIs(t, err, ErrDuplicate)
+
+	// A movie of the same name is distinct content
+	require.NoError(t, store.AddContent(&Content{Type: ContentTypeMovie, Title: "La Casa de Papel", Year: 2017, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}))
+
+	// Deleting the content removes its aliases
+	require.NoError(t, store.DeleteContent(c.ID))
+	aliases, err := store.ListAliases(c.ID)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+}
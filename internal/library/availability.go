@@ -0,0 +1,61 @@
+package library
+
+import (
+	"strings"
+	"time"
+)
+
+// MinimumAvailability is the release stage a movie must reach before it is searched for.
+type MinimumAvailability string
+
+const (
+	AvailabilityAnnounced MinimumAvailability = "announced" // Search as soon as the movie is added
+	AvailabilityInCinemas MinimumAvailability = "inCinemas" // Search from the release date
+	AvailabilityReleased  MinimumAvailability = "released"  // Search once a home release is expected
+)
+
+// releasedDelay is how long after the release date a movie is assumed to be
+// released for home viewing. TMDB's primary release date is usually theatrical;
+// Radarr falls back to the same delay when no physical or digital date is known.
+const releasedDelay = 90 * 24 * time.Hour
+
+// ParseMinimumAvailability parses an availability, accepting Radarr's values
+// case-insensitively. "tba" maps to announced and "preDB" to released.
+// Returns false for unknown values.
+func ParseMinimumAvailability(s string) (MinimumAvailability, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "announced", "tba":
+		return AvailabilityAnnounced, true
+	case "incinemas":
+		return AvailabilityInCinemas, true
+	case "released", "predb":
+		return AvailabilityReleased, true
+	default:
+		return "", false
+	}
+}
+
+// AvailableAt returns when the movie reaches its minimum availability.
+// Returns nil if it is searchable regardless of date: series, movies wanted
+// once announced, and movies without a known release date.
+func (c *Content) AvailableAt() *time.Time {
+	if c.Type != ContentTypeMovie || c.ReleaseDate == nil {
+		return nil
+	}
+	var at time.Time
+	switch c.MinimumAvailability {
+	case AvailabilityInCinemas:
+		at = *c.ReleaseDate
+	case AvailabilityReleased:
+		at = c.ReleaseDate.Add(releasedDelay)
+	default:
+		return nil
+	}
+	return &at
+}
+
+// IsAvailable reports whether the content has reached its minimum availability at now.
+func (c *Content) IsAvailable(now time.Time) bool {
+	at := c.AvailableAt()
+	return at == nil || !now.Before(*at)
+}
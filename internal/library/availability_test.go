@@ -0,0 +1,55 @@
+package library
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMinimumAvailability(t *testing.T) {
+	tests := []struct {
+		input string
+		want  MinimumAvailability
+		ok    bool
+	}{
+		{"announced", AvailabilityAnnounced, true},
+		{"tba", AvailabilityAnnounced, true},
+		{"inCinemas", AvailabilityInCinemas, true},
+		{"incinemas", AvailabilityInCinemas, true},
+		{"released", AvailabilityReleased, true},
+		{"preDB", AvailabilityReleased, true},
+		{"", "", false},
+		{"soon", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseMinimumAvailability(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestContent_IsAvailable(t *testing.T) {
+	released := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		content      Content
+		availableAt  *time.Time
+		availableNow bool // at 2024-07-01
+	}{
+		{"announced", Content{Type: ContentTypeMovie, MinimumAvailability: AvailabilityAnnounced, ReleaseDate: &released}, nil, true},
+		{"no release date", Content{Type: ContentTypeMovie, MinimumAvailability: AvailabilityReleased}, nil, true},
+		{"series", Content{Type: ContentTypeSeries, MinimumAvailability: AvailabilityReleased, ReleaseDate: &released}, nil, true},
+		{"in cinemas", Content{Type: ContentTypeMovie, MinimumAvailability: AvailabilityInCinemas, ReleaseDate: &released}, &released, true},
+		{"released", Content{Type: ContentTypeMovie, MinimumAvailability: AvailabilityReleased, ReleaseDate: &released}, ptr(released.Add(releasedDelay)), false},
+	}
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.availableAt, tt.content.AvailableAt())
+			assert.Equal(t, tt.availableNow, tt.content.IsAvailable(now))
+		})
+	}
+}
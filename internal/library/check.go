@@ -0,0 +1,247 @@
+package library
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CheckJobStatus is the state of a library check job.
+type CheckJobStatus string
+
+const (
+	CheckJobRunning   CheckJobStatus = "running"
+	CheckJobCompleted CheckJobStatus = "completed"
+	CheckJobFailed    CheckJobStatus = "failed"
+)
+
+// checkJobsKept is how many library check jobs are kept; starting a job
+// deletes the oldest beyond it along with their items.
+const checkJobsKept = 5
+
+// CheckJob is a library check running in the background, or its outcome.
+// Checked, Healthy and WithIssues count the items recorded so far.
+type CheckJob struct {
+	ID         int64
+	Status     CheckJobStatus
+	Filter     string // The content checked, e.g. "type=movie"; empty for the whole library
+	Total      int    // Content items to check
+	Checked    int
+	Healthy    int
+	WithIssues int
+	Error      string // Why the job failed
+	PlexError  string // Why Plex could not be checked, known once finished; the job ran without it
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// CheckItem is the outcome of checking one content item.
+type CheckItem struct {
+	JobID       int64
+	ContentID   int64
+	Type        ContentType
+	Title       string
+	Year        int
+	Status      ContentStatus
+	Files       []string // Paths of the content's file records
+	FileMissing []string // Paths of files not found on disk
+	InPlex      bool
+	PlexTitle   string
+	Issues      []string
+}
+
+// CreateCheckJob records a new running check job and sets ID and StartedAt
+// on it. Jobs older than the most recent few are deleted.
+func (s *Store) CreateCheckJob(j *CheckJob) error {
+	j.Status = CheckJobRunning
+	j.StartedAt = time.Now()
+	result, err := s.db.Exec(`
+		INSERT INTO library_check_jobs (status, filter, total, started_at)
+		VALUES (?, ?, ?, ?)`,
+		j.Status, j.Filter, j.Total, j.StartedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert check job: %w", err)
+	}
+	j.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get check job id: %w", err)
+	}
+
+	if _, err := s.db.Exec(`
+		DELETE FROM library_check_jobs
+		WHERE id NOT IN (SELECT id FROM library_check_jobs ORDER BY id DESC LIMIT ?)`, checkJobsKept); err != nil {
+		return fmt.Errorf("prune check jobs: %w", err)
+	}
+	return nil
+}
+
+// AddCheckItems records checked items for a job and advances its progress
+// counters, on j as well as in the database.
+func (s *Store) AddCheckItems(j *CheckJob, items []*CheckItem) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	checked, healthy, withIssues := j.Checked, j.Healthy, j.WithIssues
+	for _, item := range items {
+		files, _ := json.Marshal(nonNil(item.Files))
+		missing, _ := json.Marshal(nonNil(item.FileMissing))
+		issues, _ := json.Marshal(nonNil(item.Issues))
+		if _, err := tx.Exec(`
+			INSERT OR REPLACE INTO library_check_items
+				(job_id, content_id, type, title, year, status, files, file_missing, in_plex, plex_title, issues)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			j.ID, item.ContentID, item.Type, item.Title, item.Year, item.Status,
+			string(files), string(missing), item.InPlex, item.PlexTitle, string(issues),
+		); err != nil {
+			return fmt.Errorf("insert check item for content %d: %w", item.ContentID, err)
+		}
+		item.JobID = j.ID
+		checked++
+		if len(item.Issues) > 0 {
+			withIssues++
+		} else {
+			healthy++
+		}
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE library_check_jobs SET checked = ?, healthy = ?, with_issues = ? WHERE id = ?`,
+		checked, healthy, withIssues, j.ID,
+	); err != nil {
+		return fmt.Errorf("update check job %d: %w", j.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	j.Checked, j.Healthy, j.WithIssues = checked, healthy, withIssues
+	return nil
+}
+
+// FinishCheckJob marks a job completed, or failed with jobErr, and records
+// its PlexError.
+func (s *Store) FinishCheckJob(j *CheckJob, jobErr error) error {
+	j.Status = CheckJobCompleted
+	if jobErr != nil {
+		j.Status = CheckJobFailed
+		j.Error = jobErr.Error()
+	}
+	now := time.Now()
+	j.FinishedAt = &now
+	if _, err := s.db.Exec(`
+		UPDATE library_check_jobs SET status = ?, error = ?, plex_error = ?, finished_at = ? WHERE id = ?`,
+		j.Status, j.Error, j.PlexError, now, j.ID,
+	); err != nil {
+		return fmt.Errorf("finish check job %d: %w", j.ID, err)
+	}
+	return nil
+}
+
+// AbandonCheckJobs marks jobs left running by a previous process as failed
+// and returns how many there were. Call it at startup, before jobs start.
+func (s *Store) AbandonCheckJobs() (int, error) {
+	result, err := s.db.Exec(`
+		UPDATE library_check_jobs SET status = ?, error = 'interrupted by restart', finished_at = ?
+		WHERE status = ?`,
+		CheckJobFailed, time.Now(), CheckJobRunning,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("abandon check jobs: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("abandon check jobs: %w", err)
+	}
+	return int(n), nil
+}
+
+// GetCheckJob returns a check job by ID, or ErrNotFound.
+func (s *Store) GetCheckJob(id int64) (*CheckJob, error) {
+	return s.scanCheckJob(s.db.QueryRow(checkJobSelect+" WHERE id = ?", id))
+}
+
+// LatestCheckJob returns the most recently started check job, or ErrNotFound
+// if the library was never checked.
+func (s *Store) LatestCheckJob() (*CheckJob, error) {
+	return s.scanCheckJob(s.db.QueryRow(checkJobSelect + " ORDER BY id DESC LIMIT 1"))
+}
+
+const checkJobSelect = `
+	SELECT id, status, filter, total, checked, healthy, with_issues, error, plex_error, started_at, finished_at
+	FROM library_check_jobs`
+
+func (s *Store) scanCheckJob(row *sql.Row) (*CheckJob, error) {
+	j := &CheckJob{}
+	var finishedAt sql.NullTime
+	err := row.Scan(&j.ID, &j.Status, &j.Filter, &j.Total, &j.Checked, &j.Healthy, &j.WithIssues,
+		&j.Error, &j.PlexError, &j.StartedAt, &finishedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get check job: %w", err)
+	}
+	if finishedAt.Valid {
+		j.FinishedAt = &finishedAt.Time
+	}
+	return j, nil
+}
+
+// ListCheckItems returns the items a job has checked, in content ID order.
+// With issuesOnly, healthy items are left out. A limit of 0 means no limit.
+func (s *Store) ListCheckItems(jobID int64, issuesOnly bool, limit, offset int) ([]*CheckItem, error) {
+	query := `
+		SELECT job_id, content_id, type, title, year, status, files, file_missing, in_plex, plex_title, issues
+		FROM library_check_items WHERE job_id = ?`
+	if issuesOnly {
+		query += " AND issues != '[]'"
+	}
+	query += " ORDER BY content_id"
+	args := []any{jobID}
+	if limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list check items for job %d: %w", jobID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []*CheckItem
+	for rows.Next() {
+		item := &CheckItem{}
+		var files, missing, issues string
+		if err := rows.Scan(&item.JobID, &item.ContentID, &item.Type, &item.Title, &item.Year, &item.Status,
+			&files, &missing, &item.InPlex, &item.PlexTitle, &issues); err != nil {
+			return nil, fmt.Errorf("scan check item: %w", err)
+		}
+		for _, field := range []struct {
+			raw  string
+			dest *[]string
+		}{{files, &item.Files}, {missing, &item.FileMissing}, {issues, &item.Issues}} {
+			if err := json.Unmarshal([]byte(field.raw), field.dest); err != nil {
+				return nil, fmt.Errorf("decode check item for content %d: %w", item.ContentID, err)
+			}
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate check items: %w", err)
+	}
+	return items, nil
+}
+
+// nonNil returns s, or an empty slice if s is nil, so it encodes as [].
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package library
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func TestStore_CheckJob(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	_, err := store.LatestCheckJob()
+	require.ErrorIs(t, err, ErrNotFound)
+
+	job := &CheckJob{Filter: "type=movie", Total: 3}
+	require.NoError(t, store.CreateCheckJob(job))
+	assert.NotZero(t, job.ID)
+	assert.Equal(t, CheckJobRunning, job.Status)
+
+	require.NoError(t, store.AddCheckItems(job, []*CheckItem{
+		{ContentID: 2, Type: ContentTypeMovie, Title: "Healthy", Year: 2020, Status: StatusAvailable,
+			Files: []string{"/movies/Healthy.mkv"}, InPlex: true, PlexTitle: "Healthy"},
+		{ContentID: 1, Type: ContentTypeMovie, Title: "Broken", Year: 2021, Status: StatusAvailable,
+			Files: []string{"/movies/Broken.mkv"}, FileMissing: []string{"/movies/Broken.mkv"},
+			Issues: []string{"File missing: /movies/Broken.mkv"}},
+	}))
+	assert.Equal(t, 2, job.Checked)
+
+	// Progress is visible while the job runs
+	got, err := store.GetCheckJob(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, CheckJobRunning, got.Status)
+	assert.Equal(t, 3, got.Total)
+	assert.Equal(t, 2, got.Checked)
+	assert.Equal(t, 1, got.Healthy)
+	assert.Equal(t, 1, got.WithIssues)
+	assert.Nil(t, got.FinishedAt)
+
+	require.NoError(t, store.AddCheckItems(job, []*CheckItem{
+		{ContentID: 3, Type: ContentTypeMovie, Title: "Wanted", Year: 2022, Status: StatusWanted},
+	}))
+	require.NoError(t, store.FinishCheckJob(job, nil))
+
+	got, err = store.LatestCheckJob()
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, got.ID)
+	assert.Equal(t, CheckJobCompleted, got.Status)
+	assert.Equal(t, 3, got.Checked)
+	assert.Equal(t, 2, got.Healthy)
+	require.NotNil(t, got.FinishedAt)
+
+	items, err := store.ListCheckItems(job.ID, false, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, "Broken", items[0].Title)
+	assert.Equal(t, []string{"/movies/Broken.mkv"}, items[0].FileMissing)
+	assert.True(t, items[1].InPlex)
+	assert.Empty(t, items[2].Files)
+
+	items, err = store.ListCheckItems(job.ID, true, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, []string{"File missing: /movies/Broken.mkv"}, items[0].Issues)
+
+	items, err = store.ListCheckItems(job.ID, false, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Healthy", items[0].Title)
+
+	_, err = store.GetCheckJob(job.ID + 1)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_FinishCheckJob_Failed(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	job := &CheckJob{}
+	require.NoError(t, store.CreateCheckJob(job))
+	require.NoError(t, store.FinishCheckJob(job, errors.New("disk on fire")))
+
+	got, err := store.GetCheckJob(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, CheckJobFailed, got.Status)
+	assert.Equal(t, "disk on fire", got.Error)
+}
+
+func TestStore_AbandonCheckJobs(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	done := &CheckJob{}
+	require.NoError(t, store.CreateCheckJob(done))
+	require.NoError(t, store.FinishCheckJob(done, nil))
+	running := &CheckJob{}
+	require.NoError(t, store.CreateCheckJob(running))
+
+	n, err := store.AbandonCheckJobs()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	got, err := store.GetCheckJob(running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, CheckJobFailed, got.Status)
+	assert.Equal(t, "interrupted by restart", got.Error)
+	got, err = store.GetCheckJob(done.ID)
+	require.NoError(t, err)
+	assert.Equal(t, CheckJobCompleted, got.Status)
+}
+
+func TestStore_CreateCheckJob_PrunesOldJobs(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	first := &CheckJob{}
+	require.NoError(t, store.CreateCheckJob(first))
+	require.NoError(t, store.AddCheckItems(first, []*CheckItem{{ContentID: 1, Type: ContentTypeMovie, Title: "Old", Status: StatusWanted}}))
+	for range checkJobsKept {
+		require.NoError(t, store.CreateCheckJob(&CheckJob{}))
+	}
+
+	_, err := store.GetCheckJob(first.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+	items, err := store.ListCheckItems(first.ID, false, 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
@@ -2,12 +2,65 @@ package library
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 )
 
+// contentColumns lists the content columns read by scanContent, in order.
+// Select them FROM contentFrom, which joins in the size of each item's files.
+const contentColumns = `id, type, tmdb_id, tvdb_id, title, year, status, quality_profile, root_path, added_at, updated_at,
+	overview, poster_url, runtime, genres, metadata_updated_at, minimum_availability, release_date, daily,
+	COALESCE(series_status, ''), season_folder, COALESCE(naming_template, ''), author, COALESCE(sizes.size_bytes, 0),
+	COALESCE(pinned_guid, ''), pinned_download_url, COALESCE(pinned_title, ''), COALESCE(pinned_indexer, ''),
+	COALESCE(pinned_protocol, ''), pinned_season, pinned_at, collection_id, collection_name, plex_key`
+
+// contentFrom is the content table joined with the total size of each item's files.
+const contentFrom = `content LEFT JOIN (
+	SELECT content_id, SUM(size_bytes) AS size_bytes FROM files GROUP BY content_id
+) sizes ON sizes.content_id = content.id`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanContent reads a content row selected with contentColumns.
+func scanContent(row rowScanner) (*Content, error) {
+	c := &Content{}
+	var genres string
+	var pin PinnedRelease
+	var pinURL sql.NullString
+	var pinnedAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.Type, &c.TMDBID, &c.TVDBID, &c.Title, &c.Year, &c.Status, &c.QualityProfile, &c.RootPath, &c.AddedAt, &c.UpdatedAt,
+		&c.Overview, &c.PosterURL, &c.Runtime, &genres, &c.MetadataUpdatedAt, &c.MinimumAvailability, &c.ReleaseDate, &c.Daily,
+		&c.SeriesStatus, &c.SeasonFolder, &c.NamingTemplate, &c.Author, &c.SizeOnDisk,
+		&pin.GUID, &pinURL, &pin.Title, &pin.Indexer, &pin.Protocol, &pin.Season, &pinnedAt, &c.CollectionID, &c.CollectionName, &c.PlexKey); err != nil {
+		return nil, err
+	}
+	if pinURL.Valid {
+		pin.DownloadURL, pin.PinnedAt = pinURL.String, pinnedAt.Time
+		c.Pin = &pin
+	}
+	if genres != "" {
+		if err := json.Unmarshal([]byte(genres), &c.Genres); err != nil {
+			return nil, fmt.Errorf("decode genres: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// encodeGenres serializes genres for the genres JSON column.
+func encodeGenres(genres []string) string {
+	if len(genres) == 0 {
+		return "[]"
+	}
+	data, _ := json.Marshal(genres)
+	return string(data)
+}
+
 // mapSQLiteError converts SQLite errors to custom error types.
 func mapSQLiteError(err error) error {
 	if err == nil {
@@ -30,11 +83,27 @@ func mapSQLiteError(err error) error {
 }
 
 func addContent(q querier, c *Content) error {
+	if c.MinimumAvailability == "" {
+		c.MinimumAvailability = AvailabilityAnnounced
+	}
+	owner, err := aliasOwner(q, c.Type, c.Title, c.Year)
+	if err != nil {
+		return err
+	}
+	if owner != 0 {
+		return fmt.Errorf("insert content: %q is an alias of content %d: %w", c.Title, owner, ErrDuplicate)
+	}
+
 	now := time.Now()
 	result, err := q.Exec(`
-		INSERT INTO content (type, tmdb_id, tvdb_id, title, year, status, quality_profile, root_path, added_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		INSERT INTO content (type, tmdb_id, tvdb_id, title, year, status, quality_profile, root_path, added_at, updated_at,
+			overview, poster_url, runtime, genres, metadata_updated_at, minimum_availability, release_date, normalized_title, daily, series_status,
+			season_folder, naming_template, author, collection_id, collection_name, plex_key)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, ?, ?)`,
 		c.Type, c.TMDBID, c.TVDBID, c.Title, c.Year, c.Status, c.QualityProfile, c.RootPath, now, now,
+		c.Overview, c.PosterURL, c.Runtime, encodeGenres(c.Genres), c.MetadataUpdatedAt, c.MinimumAvailability, c.ReleaseDate,
+		normalizeTitle(c.Title), c.Daily, c.SeriesStatus, c.SeasonFolder, c.NamingTemplate, c.Author,
+		c.CollectionID, c.CollectionName, c.PlexKey,
 	)
 	if err != nil {
 		return fmt.Errorf("insert content: %w", mapSQLiteError(err))
@@ -51,17 +120,15 @@ func addContent(q querier, c *Content) error {
 
 // AddContent inserts a new content item into the database.
 // Sets ID, AddedAt, and UpdatedAt on the struct.
+// Returns ErrDuplicate if content of the same type and year has a title or alias
+// that differs only by case, punctuation or whitespace.
 func (s *Store) AddContent(c *Content) error { return addContent(s.db, c) }
 
 // AddContent inserts a new content item within a transaction.
 func (t *Tx) AddContent(c *Content) error { return addContent(t.tx, c) }
 
 func getContent(q querier, id int64) (*Content, error) {
-	c := &Content{}
-	err := q.QueryRow(`
-		SELECT id, type, tmdb_id, tvdb_id, title, year, status, quality_profile, root_path, added_at, updated_at
-		FROM content WHERE id = ?`, id,
-	).Scan(&c.ID, &c.Type, &c.TMDBID, &c.TVDBID, &c.Title, &c.Year, &c.Status, &c.QualityProfile, &c.RootPath, &c.AddedAt, &c.UpdatedAt)
+	c, err := scanContent(q.QueryRow("SELECT "+contentColumns+" FROM "+contentFrom+" WHERE id = ?", id))
 	if err != nil {
 		return nil, fmt.Errorf("get content %d: %w", id, mapSQLiteError(err))
 	}
@@ -75,8 +142,8 @@ func (s *Store) GetContent(id int64) (*Content, error) { return getContent(s.db,
 // GetContent retrieves a content item by ID within a transaction.
 func (t *Tx) GetContent(id int64) (*Content, error) { return getContent(t.tx, id) }
 
-// GetByTitleYear finds content by title and year.
-// Returns nil, nil if not found.
+// GetByTitleYear finds content by title or alias and year, ignoring differences
+// in case, punctuation and whitespace. Returns nil, nil if not found.
 func (s *Store) GetByTitleYear(title string, year int) (*Content, error) {
 	contents, _, err := s.ListContent(ContentFilter{Title: &title, Year: &year, Limit: 1})
 	if err != nil {
@@ -92,6 +159,12 @@ func listContent(q querier, f ContentFilter) ([]*Content, int, error) {
 	var conditions []string
 	var args []any
 
+	if len(f.IDs) > 0 {
+		conditions = append(conditions, "id IN ("+placeholders(len(f.IDs))+")")
+		for _, id := range f.IDs {
+			args = append(args, id)
+		}
+	}
 	if f.Type != nil {
 		conditions = append(conditions, "type = ?")
 		args = append(args, *f.Type)
@@ -113,25 +186,35 @@ func listContent(q querier, f ContentFilter) ([]*Content, int, error) {
 		args = append(args, *f.TVDBID)
 	}
 	if f.Title != nil {
-		conditions = append(conditions, "title = ?")
-		args = append(args, *f.Title)
+		conditions = append(conditions, "(normalized_title = ? OR id IN (SELECT content_id FROM content_aliases WHERE normalized_alias = ?))")
+		normalized := normalizeTitle(*f.Title)
+		args = append(args, normalized, normalized)
 	}
 	if f.Year != nil {
 		conditions = append(conditions, "year = ?")
 		args = append(args, *f.Year)
 	}
+	if f.MetadataBefore != nil {
+		conditions = append(conditions, "(metadata_updated_at IS NULL OR metadata_updated_at < ?)")
+		args = append(args, *f.MetadataBefore)
+	}
 
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
+	orderBy, err := f.orderBy()
+	if err != nil {
+		return nil, 0, fmt.Errorf("list content: %w", err)
+	}
+
 	var total int
 	if err := q.QueryRow("SELECT COUNT(*) FROM content "+whereClause, args...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("count content: %w", err)
 	}
 
-	query := "SELECT id, type, tmdb_id, tvdb_id, title, year, status, quality_profile, root_path, added_at, updated_at FROM content " + whereClause + " ORDER BY id"
+	query := "SELECT " + contentColumns + " FROM " + contentFrom + " " + whereClause + orderBy
 	if f.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT %d OFFSET %d", f.Limit, f.Offset)
 	}
@@ -144,8 +227,8 @@ func listContent(q querier, f ContentFilter) ([]*Content, int, error) {
 
 	var results []*Content
 	for rows.Next() {
-		c := &Content{}
-		if err := rows.Scan(&c.ID, &c.Type, &c.TMDBID, &c.TVDBID, &c.Title, &c.Year, &c.Status, &c.QualityProfile, &c.RootPath, &c.AddedAt, &c.UpdatedAt); err != nil {
+		c, err := scanContent(rows)
+		if err != nil {
 			return nil, 0, fmt.Errorf("scan content: %w", err)
 		}
 		results = append(results, c)
@@ -165,11 +248,27 @@ func (s *Store) ListContent(f ContentFilter) ([]*Content, int, error) { return l
 func (t *Tx) ListContent(f ContentFilter) ([]*Content, int, error) { return listContent(t.tx, f) }
 
 func updateContent(q querier, c *Content) error {
+	if c.MinimumAvailability == "" {
+		c.MinimumAvailability = AvailabilityAnnounced
+	}
+	owner, err := aliasOwner(q, c.Type, c.Title, c.Year)
+	if err != nil {
+		return err
+	}
+	if owner != 0 && owner != c.ID {
+		return fmt.Errorf("update content %d: %q is an alias of content %d: %w", c.ID, c.Title, owner, ErrDuplicate)
+	}
+
 	now := time.Now()
+	// Rows left without a normalized title by the migration 015 backfill are known
+	// duplicates; they keep NULL until retitled so other updates don't fail.
 	result, err := q.Exec(`
-		UPDATE content SET type = ?, tmdb_id = ?, tvdb_id = ?, title = ?, year = ?, status = ?, quality_profile = ?, root_path = ?, updated_at = ?
+		UPDATE content SET type = ?, tmdb_id = ?, tvdb_id = ?, title = ?, year = ?, status = ?, quality_profile = ?, root_path = ?,
+			minimum_availability = ?, daily = ?, season_folder = ?, naming_template = NULLIF(?, ''), author = ?, updated_at = ?,
+			normalized_title = CASE WHEN normalized_title IS NULL AND title = ? THEN NULL ELSE ? END
 		WHERE id = ?`,
-		c.Type, c.TMDBID, c.TVDBID, c.Title, c.Year, c.Status, c.QualityProfile, c.RootPath, now, c.ID,
+		c.Type, c.TMDBID, c.TVDBID, c.Title, c.Year, c.Status, c.QualityProfile, c.RootPath, c.MinimumAvailability, c.Daily,
+		c.SeasonFolder, c.NamingTemplate, c.Author, now, c.Title, normalizeTitle(c.Title), c.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("update content %d: %w", c.ID, mapSQLiteError(err))
@@ -185,14 +284,112 @@ func updateContent(q querier, c *Content) error {
 	return nil
 }
 
+// SetPlexKey stores the rating key of the content's Plex item; empty clears it.
+// Returns ErrNotFound if the content does not exist.
+func (s *Store) SetPlexKey(contentID int64, key string) error {
+	result, err := s.db.Exec(`UPDATE content SET plex_key = ? WHERE id = ?`, key, contentID)
+	if err != nil {
+		return fmt.Errorf("set plex key for content %d: %w", contentID, mapSQLiteError(err))
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("set plex key for content %d: %w", contentID, ErrNotFound)
+	}
+	return nil
+}
+
 // UpdateContent updates an existing content item.
+// Display metadata is not written; use UpdateContentMetadata.
 // Sets UpdatedAt on the struct.
-// Returns ErrNotFound if the content does not exist.
+// Returns ErrNotFound if the content does not exist, and ErrDuplicate if
+// other content of the same type and year has a matching title or alias.
 func (s *Store) UpdateContent(c *Content) error { return updateContent(s.db, c) }
 
 // UpdateContent updates an existing content item within a transaction.
 func (t *Tx) UpdateContent(c *Content) error { return updateContent(t.tx, c) }
 
+func updateContentMetadata(q querier, c *Content) error {
+	now := time.Now()
+	result, err := q.Exec(`
+		UPDATE content SET overview = ?, poster_url = ?, runtime = ?, genres = ?, release_date = ?, metadata_updated_at = ?,
+			collection_id = ?, collection_name = ?
+		WHERE id = ?`,
+		c.Overview, c.PosterURL, c.Runtime, encodeGenres(c.Genres), c.ReleaseDate, now,
+		c.CollectionID, c.CollectionName, c.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update content metadata %d: %w", c.ID, mapSQLiteError(err))
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("update content metadata %d: %w", c.ID, ErrNotFound)
+	}
+	c.MetadataUpdatedAt = &now
+	return nil
+}
+
+// UpdateContentMetadata writes the display metadata (overview, poster, runtime, genres),
+// release date and collection of a content item and sets MetadataUpdatedAt.
+// Returns ErrNotFound if the content does not exist.
+func (s *Store) UpdateContentMetadata(c *Content) error { return updateContentMetadata(s.db, c) }
+
+// UpdateContentMetadata writes the display metadata of a content item within a transaction.
+func (t *Tx) UpdateContentMetadata(c *Content) error { return updateContentMetadata(t.tx, c) }
+
+// SeriesStatusContinuing is the TVDB status of a series still airing.
+const SeriesStatusContinuing = "continuing"
+
+// UpdateSeriesStatus stores the TVDB status of a series, lowercased.
+// Returns ErrNotFound if the content does not exist.
+func (s *Store) UpdateSeriesStatus(id int64, status string) error {
+	result, err := s.db.Exec("UPDATE content SET series_status = NULLIF(?, '') WHERE id = ?", strings.ToLower(status), id)
+	if err != nil {
+		return fmt.Errorf("update series status %d: %w", id, mapSQLiteError(err))
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("update series status %d: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// ListContinuingSeries returns series with a TVDB ID whose TVDB status is
+// continuing or not yet known, skipping abandoned and unmonitored ones.
+// These are the series whose episode lists can still grow.
+func (s *Store) ListContinuingSeries() ([]*Content, error) {
+	rows, err := s.db.Query("SELECT "+contentColumns+" FROM "+contentFrom+`
+		WHERE type = ? AND tvdb_id IS NOT NULL AND status NOT IN (?, ?)
+			AND (series_status IS NULL OR series_status = ?)
+		ORDER BY id`,
+		ContentTypeSeries, StatusAbandoned, StatusUnmonitored, SeriesStatusContinuing)
+	if err != nil {
+		return nil, fmt.Errorf("list continuing series: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []*Content
+	for rows.Next() {
+		c, err := scanContent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan content: %w", err)
+		}
+		results = append(results, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate content: %w", err)
+	}
+	return results, nil
+}
+
 func deleteContent(q querier, id int64) error {
 	_, err := q.Exec("DELETE FROM content WHERE id = ?", id)
 	if err != nil {
@@ -207,3 +404,23 @@ func (s *Store) DeleteContent(id int64) error { return deleteContent(s.db, id) }
 
 // DeleteContent removes a content item by ID within a transaction.
 func (t *Tx) DeleteContent(id int64) error { return deleteContent(t.tx, id) }
+
+// QualityProfileCounts returns the number of content items using each quality profile.
+func (s *Store) QualityProfileCounts() (map[string]int, error) {
+	rows, err := s.db.Query("SELECT quality_profile, COUNT(*) FROM content GROUP BY quality_profile")
+	if err != nil {
+		return nil, fmt.Errorf("count quality profiles: %w", mapSQLiteError(err))
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var n int
+		if err := rows.Scan(&name, &n); err != nil {
+			return nil, fmt.Errorf("scan quality profile count: %w", err)
+		}
+		counts[name] = n
+	}
+	return counts, rows.Err()
+}
@@ -2,14 +2,19 @@ package library
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 )
 
+// episodeColumns lists the columns scanned into an Episode, in scan order.
+const episodeColumns = `id, content_id, season, episode, title, status, air_date, COALESCE(quality_profile, '')`
+
 func addEpisode(q querier, e *Episode) error {
 	result, err := q.Exec(`
-		INSERT INTO episodes (content_id, season, episode, title, status, air_date)
-		VALUES (?, ?, ?, ?, ?, ?)`,
-		e.ContentID, e.Season, e.Episode, e.Title, e.Status, e.AirDate,
+		INSERT INTO episodes (content_id, season, episode, title, status, air_date, quality_profile)
+		VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''))`,
+		e.ContentID, e.Season, e.Episode, e.Title, e.Status, e.AirDate, e.QualityProfile,
 	)
 	if err != nil {
 		return fmt.Errorf("insert episode: %w", mapSQLiteError(err))
@@ -32,9 +37,9 @@ func (t *Tx) AddEpisode(e *Episode) error { return addEpisode(t.tx, e) }
 func getEpisode(q querier, id int64) (*Episode, error) {
 	e := &Episode{}
 	err := q.QueryRow(`
-		SELECT id, content_id, season, episode, title, status, air_date
+		SELECT `+episodeColumns+`
 		FROM episodes WHERE id = ?`, id,
-	).Scan(&e.ID, &e.ContentID, &e.Season, &e.Episode, &e.Title, &e.Status, &e.AirDate)
+	).Scan(&e.ID, &e.ContentID, &e.Season, &e.Episode, &e.Title, &e.Status, &e.AirDate, &e.QualityProfile)
 	if err != nil {
 		return nil, fmt.Errorf("get episode %d: %w", id, mapSQLiteError(err))
 	}
@@ -52,6 +57,12 @@ func listEpisodes(q querier, f EpisodeFilter) ([]*Episode, int, error) {
 	var conditions []string
 	var args []any
 
+	if len(f.IDs) > 0 {
+		conditions = append(conditions, "id IN ("+placeholders(len(f.IDs))+")")
+		for _, id := range f.IDs {
+			args = append(args, id)
+		}
+	}
 	if f.ContentID != nil {
 		conditions = append(conditions, "content_id = ?")
 		args = append(args, *f.ContentID)
@@ -75,7 +86,7 @@ func listEpisodes(q querier, f EpisodeFilter) ([]*Episode, int, error) {
 		return nil, 0, fmt.Errorf("count episodes: %w", err)
 	}
 
-	query := "SELECT id, content_id, season, episode, title, status, air_date FROM episodes " + whereClause + " ORDER BY season, episode"
+	query := "SELECT " + episodeColumns + " FROM episodes " + whereClause + " ORDER BY season, episode"
 	if f.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT %d OFFSET %d", f.Limit, f.Offset)
 	}
@@ -89,7 +100,7 @@ func listEpisodes(q querier, f EpisodeFilter) ([]*Episode, int, error) {
 	var results []*Episode
 	for rows.Next() {
 		e := &Episode{}
-		if err := rows.Scan(&e.ID, &e.ContentID, &e.Season, &e.Episode, &e.Title, &e.Status, &e.AirDate); err != nil {
+		if err := rows.Scan(&e.ID, &e.ContentID, &e.Season, &e.Episode, &e.Title, &e.Status, &e.AirDate, &e.QualityProfile); err != nil {
 			return nil, 0, fmt.Errorf("scan episode: %w", err)
 		}
 		results = append(results, e)
@@ -110,9 +121,10 @@ func (t *Tx) ListEpisodes(f EpisodeFilter) ([]*Episode, int, error) { return lis
 
 func updateEpisode(q querier, e *Episode) error {
 	result, err := q.Exec(`
-		UPDATE episodes SET content_id = ?, season = ?, episode = ?, title = ?, status = ?, air_date = ?
+		UPDATE episodes SET content_id = ?, season = ?, episode = ?, title = ?, status = ?, air_date = ?,
+			quality_profile = NULLIF(?, '')
 		WHERE id = ?`,
-		e.ContentID, e.Season, e.Episode, e.Title, e.Status, e.AirDate, e.ID,
+		e.ContentID, e.Season, e.Episode, e.Title, e.Status, e.AirDate, e.QualityProfile, e.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("update episode %d: %w", e.ID, mapSQLiteError(err))
@@ -149,11 +161,9 @@ func (s *Store) DeleteEpisode(id int64) error { return deleteEpisode(s.db, id) }
 // DeleteEpisode removes an episode by ID within a transaction.
 func (t *Tx) DeleteEpisode(id int64) error { return deleteEpisode(t.tx, id) }
 
-// FindOrCreateEpisode finds an existing episode or creates a new one.
-// Returns (episode, created, error) where created is true if a new episode was created.
-func (s *Store) FindOrCreateEpisode(contentID int64, season, episode int) (*Episode, bool, error) {
+func findOrCreateEpisode(q querier, contentID int64, season, episode int) (*Episode, bool, error) {
 	// Try to find existing - query by contentID and season
-	eps, _, err := s.ListEpisodes(EpisodeFilter{
+	eps, _, err := listEpisodes(q, EpisodeFilter{
 		ContentID: &contentID,
 		Season:    &season,
 	})
@@ -174,20 +184,18 @@ func (s *Store) FindOrCreateEpisode(contentID int64, season, episode int) (*Epis
 		Episode:   episode,
 		Status:    StatusWanted,
 	}
-	if err := s.AddEpisode(ep); err != nil {
+	if err := addEpisode(q, ep); err != nil {
 		return nil, false, fmt.Errorf("add episode: %w", err)
 	}
 
 	return ep, true, nil
 }
 
-// FindOrCreateEpisodes finds or creates multiple episodes for a season.
-// Returns the episodes in the same order as the input episode numbers.
-func (s *Store) FindOrCreateEpisodes(contentID int64, season int, episodeNums []int) ([]*Episode, error) {
+func findOrCreateEpisodes(q querier, contentID int64, season int, episodeNums []int) ([]*Episode, error) {
 	result := make([]*Episode, 0, len(episodeNums))
 
 	for _, epNum := range episodeNums {
-		ep, _, err := s.FindOrCreateEpisode(contentID, season, epNum)
+		ep, _, err := findOrCreateEpisode(q, contentID, season, epNum)
 		if err != nil {
 			return nil, err
 		}
@@ -197,11 +205,145 @@ func (s *Store) FindOrCreateEpisodes(contentID int64, season int, episodeNums []
 	return result, nil
 }
 
+// FindOrCreateEpisode finds an existing episode or creates a new one.
+// The lookup and insert run in one transaction so concurrent callers can't both create it.
+// Returns (episode, created, error) where created is true if a new episode was created.
+func (s *Store) FindOrCreateEpisode(contentID int64, season, episode int) (*Episode, bool, error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	ep, created, err := tx.FindOrCreateEpisode(contentID, season, episode)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return ep, created, nil
+}
+
+// FindOrCreateEpisode finds an existing episode or creates a new one within a transaction.
+func (t *Tx) FindOrCreateEpisode(contentID int64, season, episode int) (*Episode, bool, error) {
+	return findOrCreateEpisode(t.tx, contentID, season, episode)
+}
+
+// FindOrCreateEpisodes finds or creates multiple episodes for a season in one transaction.
+// Returns the episodes in the same order as the input episode numbers.
+func (s *Store) FindOrCreateEpisodes(contentID int64, season int, episodeNums []int) ([]*Episode, error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	eps, err := tx.FindOrCreateEpisodes(contentID, season, episodeNums)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return eps, nil
+}
+
+// FindOrCreateEpisodes finds or creates multiple episodes for a season within a transaction.
+func (t *Tx) FindOrCreateEpisodes(contentID int64, season int, episodeNums []int) ([]*Episode, error) {
+	return findOrCreateEpisodes(t.tx, contentID, season, episodeNums)
+}
+
+func findEpisodeByDate(q querier, contentID int64, airDate time.Time) (*Episode, error) {
+	eps, _, err := listEpisodes(q, EpisodeFilter{ContentID: &contentID})
+	if err != nil {
+		return nil, fmt.Errorf("list episodes: %w", err)
+	}
+	day := airDate.Format(time.DateOnly)
+	for _, ep := range eps {
+		if ep.AirDate != nil && ep.AirDate.UTC().Format(time.DateOnly) == day {
+			return ep, nil
+		}
+	}
+	return nil, nil
+}
+
+func findOrCreateEpisodeByDate(q querier, contentID int64, airDate time.Time) (*Episode, bool, error) {
+	ep, err := findEpisodeByDate(q, contentID, airDate)
+	if err != nil || ep != nil {
+		return ep, false, err
+	}
+
+	// Not found: create it in a season keyed by year, after the season's last episode
+	season := airDate.Year()
+	eps, _, err := listEpisodes(q, EpisodeFilter{ContentID: &contentID, Season: &season})
+	if err != nil {
+		return nil, false, fmt.Errorf("list episodes: %w", err)
+	}
+	next := 1
+	for _, e := range eps {
+		next = max(next, e.Episode+1)
+	}
+
+	date := time.Date(airDate.Year(), airDate.Month(), airDate.Day(), 0, 0, 0, 0, time.UTC)
+	ep = &Episode{
+		ContentID: contentID,
+		Season:    season,
+		Episode:   next,
+		Status:    StatusWanted,
+		AirDate:   &date,
+	}
+	if err := addEpisode(q, ep); err != nil {
+		return nil, false, fmt.Errorf("add episode: %w", err)
+	}
+	return ep, true, nil
+}
+
+// FindEpisodeByDate returns the episode of a series that aired on the given day.
+// Returns nil, nil if there is none.
+func (s *Store) FindEpisodeByDate(contentID int64, airDate time.Time) (*Episode, error) {
+	return findEpisodeByDate(s.db, contentID, airDate)
+}
+
+// FindOrCreateEpisodeByDate finds the episode of a daily series that aired on the
+// given day. If there is none, it is created in a season numbered by the year,
+// after the last episode of that season.
+// Returns (episode, created, error) where created is true if a new episode was created.
+func (s *Store) FindOrCreateEpisodeByDate(contentID int64, airDate time.Time) (*Episode, bool, error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	ep, created, err := tx.FindOrCreateEpisodeByDate(contentID, airDate)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return ep, created, nil
+}
+
+// FindOrCreateEpisodeByDate finds or creates the episode that aired on a day within a transaction.
+func (t *Tx) FindOrCreateEpisodeByDate(contentID int64, airDate time.Time) (*Episode, bool, error) {
+	return findOrCreateEpisodeByDate(t.tx, contentID, airDate)
+}
+
 // SeasonStats contains statistics for a single season.
 type SeasonStats struct {
-	Season    int
-	Total     int
-	Available int
+	Season      int
+	Total       int
+	Available   int
+	Files       int        // Episodes with a file on disk (missing files don't count)
+	SizeBytes   int64      // Total size of the season's episode files on disk
+	LastAirDate *time.Time // Newest episode air date; nil if none is known
+}
+
+// Complete reports whether every episode of the season is available.
+func (ss SeasonStats) Complete() bool {
+	return ss.Total > 0 && ss.Available == ss.Total
 }
 
 // SeriesStats contains statistics about a series.
@@ -230,33 +372,92 @@ func (s *Store) GetSeriesStats(contentID int64) (*SeriesStats, error) {
 		return nil, fmt.Errorf("get series stats: %w", err)
 	}
 
-	// Get per-season breakdown
-	rows, err := s.db.Query(`
-		SELECT
-			season,
-			COUNT(*) as total,
-			COALESCE(SUM(CASE WHEN status = 'available' THEN 1 ELSE 0 END), 0) as available
-		FROM episodes
-		WHERE content_id = ?
-		GROUP BY season
-		ORDER BY season`, contentID)
+	seasons, err := s.seasonStats([]int64{contentID})
 	if err != nil {
-		return nil, fmt.Errorf("get season stats: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
+	stats.Seasons = seasons[contentID]
 
-	for rows.Next() {
-		var ss SeasonStats
-		if err := rows.Scan(&ss.Season, &ss.Total, &ss.Available); err != nil {
-			return nil, fmt.Errorf("scan season stats: %w", err)
+	return stats, nil
+}
+
+// BulkUpdateEpisodeStatus sets monitoring for all episodes of a series in one transaction.
+// Selected episodes become wanted and all other episodes become unmonitored.
+// Available episodes are never changed. Returns the number of changed episodes per season.
+// Returns ErrNotFound if an explicit episode ID does not belong to the series.
+func (s *Store) BulkUpdateEpisodeStatus(contentID int64, sel EpisodeSelection) (map[int]int, error) {
+	return s.bulkUpdateEpisodes(contentID, sel, func(e *Episode, selected bool) bool {
+		if e.Status == StatusAvailable {
+			return false
+		}
+		status := StatusUnmonitored
+		if selected {
+			status = StatusWanted
+		}
+		if e.Status == status {
+			return false
 		}
-		stats.Seasons = append(stats.Seasons, ss)
+		e.Status = status
+		return true
+	})
+}
+
+// BulkSetEpisodeProfile sets the quality profile override on the selected episodes
+// of a series in one transaction. An empty profile makes them inherit the series'
+// profile again. Monitoring is not changed. Returns the number of changed episodes
+// per season. Returns ErrNotFound if an explicit episode ID does not belong to the series.
+func (s *Store) BulkSetEpisodeProfile(contentID int64, sel EpisodeSelection, profile string) (map[int]int, error) {
+	return s.bulkUpdateEpisodes(contentID, sel, func(e *Episode, selected bool) bool {
+		if !selected || e.QualityProfile == profile {
+			return false
+		}
+		e.QualityProfile = profile
+		return true
+	})
+}
+
+// bulkUpdateEpisodes applies update to every episode of a series in one transaction,
+// saving the episodes it reports as changed. Returns the changed count per season.
+func (s *Store) bulkUpdateEpisodes(contentID int64, sel EpisodeSelection, update func(e *Episode, selected bool) bool) (map[int]int, error) {
+	if err := sel.validate(); err != nil {
+		return nil, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate season stats: %w", err)
+
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, err
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	return stats, nil
+	episodes, _, err := tx.ListEpisodes(EpisodeFilter{ContentID: &contentID})
+	if err != nil {
+		return nil, err
+	}
+
+	// Explicit IDs must all belong to this series
+	for _, id := range sel.EpisodeIDs {
+		if !slices.ContainsFunc(episodes, func(e *Episode) bool { return e.ID == id }) {
+			return nil, fmt.Errorf("episode %d in content %d: %w", id, contentID, ErrNotFound)
+		}
+	}
+
+	now := time.Now()
+	changed := make(map[int]int)
+	for _, e := range episodes {
+		if !update(e, sel.matches(e, now)) {
+			continue
+		}
+		if err := tx.UpdateEpisode(e); err != nil {
+			return nil, err
+		}
+		changed[e.Season]++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return changed, nil
 }
 
 // BulkAddEpisodes inserts multiple episodes efficiently.
@@ -274,8 +475,8 @@ func (s *Store) BulkAddEpisodes(episodes []*Episode) (int, error) {
 	defer func() { _ = tx.Rollback() }()
 
 	stmt, err := tx.Prepare(`
-		INSERT OR IGNORE INTO episodes (content_id, season, episode, title, status, air_date)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT OR IGNORE INTO episodes (content_id, season, episode, title, status, air_date, quality_profile)
+		VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''))
 	`)
 	if err != nil {
 		return 0, fmt.Errorf("prepare statement: %w", err)
@@ -284,7 +485,7 @@ func (s *Store) BulkAddEpisodes(episodes []*Episode) (int, error) {
 
 	inserted := 0
 	for _, e := range episodes {
-		result, err := stmt.Exec(e.ContentID, e.Season, e.Episode, e.Title, e.Status, e.AirDate)
+		result, err := stmt.Exec(e.ContentID, e.Season, e.Episode, e.Title, e.Status, e.AirDate, e.QualityProfile)
 		if err != nil {
 			return inserted, fmt.Errorf("insert episode S%02dE%02d: %w", e.Season, e.Episode, err)
 		}
@@ -300,6 +501,65 @@ func (s *Store) BulkAddEpisodes(episodes []*Episode) (int, error) {
 	return inserted, nil
 }
 
+// SyncEpisodes reconciles a series' episodes with an external episode list:
+// new episodes are inserted as given, and existing ones (by season and
+// episode) take the list's title and air date where those are set and differ.
+// Status and quality profile of existing episodes are never changed.
+// Returns the counts of inserted and updated episodes.
+func (s *Store) SyncEpisodes(episodes []*Episode) (inserted, updated int, err error) {
+	if len(episodes) == 0 {
+		return 0, 0, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	insert, err := tx.Prepare(`
+		INSERT OR IGNORE INTO episodes (content_id, season, episode, title, status, air_date, quality_profile)
+		VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''))
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("prepare insert: %w", err)
+	}
+	defer func() { _ = insert.Close() }()
+
+	update, err := tx.Prepare(`
+		UPDATE episodes SET title = COALESCE(NULLIF(?1, ''), title), air_date = COALESCE(?2, air_date)
+		WHERE content_id = ?3 AND season = ?4 AND episode = ?5
+			AND (title IS NOT COALESCE(NULLIF(?1, ''), title) OR air_date IS NOT COALESCE(?2, air_date))
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("prepare update: %w", err)
+	}
+	defer func() { _ = update.Close() }()
+
+	for _, e := range episodes {
+		result, err := insert.Exec(e.ContentID, e.Season, e.Episode, e.Title, e.Status, e.AirDate, e.QualityProfile)
+		if err != nil {
+			return 0, 0, fmt.Errorf("insert episode S%02dE%02d: %w", e.Season, e.Episode, err)
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			inserted++
+			continue
+		}
+		result, err = update.Exec(e.Title, e.AirDate, e.ContentID, e.Season, e.Episode)
+		if err != nil {
+			return 0, 0, fmt.Errorf("update episode S%02dE%02d: %w", e.Season, e.Episode, err)
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			updated++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return inserted, updated, nil
+}
+
 // GetSeriesStatsBatch returns episode statistics for multiple series.
 // Returns a map from content ID to stats.
 func (s *Store) GetSeriesStatsBatch(contentIDs []int64) (map[int64]*SeriesStats, error) {
@@ -344,35 +604,67 @@ func (s *Store) GetSeriesStatsBatch(contentIDs []int64) (map[int64]*SeriesStats,
 		return nil, fmt.Errorf("iterate series stats: %w", err)
 	}
 
-	// Get per-season breakdown for each series
-	seasonRows, err := s.db.Query(fmt.Sprintf(`
+	seasons, err := s.seasonStats(contentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for contentID, stats := range result {
+		stats.Seasons = seasons[contentID]
+	}
+
+	return result, nil
+}
+
+// seasonStats returns per-season statistics for the given series, ordered by season.
+// File sizes are summed per episode first so episodes with several files are counted once;
+// files marked missing are left out.
+func (s *Store) seasonStats(contentIDs []int64) (map[int64][]SeasonStats, error) {
+	placeholders := make([]string, len(contentIDs))
+	args := make([]any, len(contentIDs))
+	for i, id := range contentIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	// The bare e.air_date takes its value from the row with MAX(e.air_date),
+	// which keeps the column's DATE type for scanning (MAX alone returns text).
+	rows, err := s.db.Query(fmt.Sprintf(`
 		SELECT
-			content_id,
-			season,
+			e.content_id,
+			e.season,
 			COUNT(*) as total,
-			COALESCE(SUM(CASE WHEN status = 'available' THEN 1 ELSE 0 END), 0) as available
-		FROM episodes
-		WHERE content_id IN (%s)
-		GROUP BY content_id, season
-		ORDER BY content_id, season`, inClause), args...)
+			COALESCE(SUM(CASE WHEN e.status = 'available' THEN 1 ELSE 0 END), 0) as available,
+			COUNT(f.episode_id) as files,
+			COALESCE(SUM(f.size_bytes), 0) as size_bytes,
+			MAX(e.air_date),
+			e.air_date
+		FROM episodes e
+		LEFT JOIN (
+			SELECT episode_id, SUM(size_bytes) as size_bytes
+			FROM files
+			WHERE episode_id IS NOT NULL AND missing_at IS NULL AND kind = 'media'
+			GROUP BY episode_id
+		) f ON f.episode_id = e.id
+		WHERE e.content_id IN (%s)
+		GROUP BY e.content_id, e.season
+		ORDER BY e.content_id, e.season`, strings.Join(placeholders, ",")), args...)
 	if err != nil {
-		return nil, fmt.Errorf("get season stats batch: %w", err)
+		return nil, fmt.Errorf("get season stats: %w", err)
 	}
-	defer seasonRows.Close()
+	defer rows.Close()
 
-	for seasonRows.Next() {
+	result := make(map[int64][]SeasonStats)
+	for rows.Next() {
 		var contentID int64
 		var ss SeasonStats
-		if err := seasonRows.Scan(&contentID, &ss.Season, &ss.Total, &ss.Available); err != nil {
+		var maxAirDate any
+		if err := rows.Scan(&contentID, &ss.Season, &ss.Total, &ss.Available, &ss.Files, &ss.SizeBytes, &maxAirDate, &ss.LastAirDate); err != nil {
 			return nil, fmt.Errorf("scan season stats: %w", err)
 		}
-		if stats, ok := result[contentID]; ok {
-			stats.Seasons = append(stats.Seasons, ss)
-		}
+		result[contentID] = append(result[contentID], ss)
 	}
-	if err := seasonRows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate season stats: %w", err)
 	}
-
 	return result, nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
 )
 
 // createTestSeries creates a series Content for episode tests
@@ -25,7 +26,7 @@ func createTestSeries(t *testing.T, store *Store) *Content {
 }
 
 func TestStore_AddEpisode(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -46,7 +47,7 @@ func TestStore_AddEpisode(t *testing.T) {
 }
 
 func TestStore_AddEpisode_Duplicate(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -74,7 +75,7 @@ func TestStore_AddEpisode_Duplicate(t *testing.T) {
 }
 
 func TestStore_GetEpisode(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -104,7 +105,7 @@ func TestStore_GetEpisode(t *testing.T) {
 }
 
 func TestStore_GetEpisode_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	_, err := store.GetEpisode(9999)
@@ -112,7 +113,7 @@ func TestStore_GetEpisode_NotFound(t *testing.T) {
 }
 
 func TestStore_ListEpisodes_FilterByContentID(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create two series
@@ -145,7 +146,7 @@ func TestStore_ListEpisodes_FilterByContentID(t *testing.T) {
 }
 
 func TestStore_ListEpisodes_FilterBySeason(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -167,7 +168,7 @@ func TestStore_ListEpisodes_FilterBySeason(t *testing.T) {
 }
 
 func TestStore_ListEpisodes_Pagination(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -202,7 +203,7 @@ func TestStore_ListEpisodes_Pagination(t *testing.T) {
 }
 
 func TestStore_UpdateEpisode(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -230,7 +231,7 @@ func TestStore_UpdateEpisode(t *testing.T) {
 }
 
 func TestStore_UpdateEpisode_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -248,7 +249,7 @@ func TestStore_UpdateEpisode_NotFound(t *testing.T) {
 }
 
 func TestStore_DeleteEpisode(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -270,7 +271,7 @@ func TestStore_DeleteEpisode(t *testing.T) {
 }
 
 func TestStore_DeleteEpisode_Idempotent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Delete non-existent should not error
@@ -278,7 +279,7 @@ func TestStore_DeleteEpisode_Idempotent(t *testing.T) {
 }
 
 func TestTx_AddEpisode(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -312,7 +313,7 @@ func TestTx_AddEpisode(t *testing.T) {
 }
 
 func TestTx_Rollback_Episode(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -340,7 +341,7 @@ func TestTx_Rollback_Episode(t *testing.T) {
 }
 
 func TestStore_FindOrCreateEpisode(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create test series
@@ -371,7 +372,7 @@ func TestStore_FindOrCreateEpisode(t *testing.T) {
 }
 
 func TestStore_FindOrCreateEpisodes(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	content := &Content{
@@ -402,8 +403,62 @@ func TestStore_FindOrCreateEpisodes(t *testing.T) {
 	assert.Equal(t, episodes[0].ID, episodes2[0].ID)
 }
 
+func TestStore_FindOrCreateEpisodeByDate(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	content := &Content{
+		Type:           ContentTypeSeries,
+		Title:          "The Daily Show",
+		Year:           1996,
+		Status:         StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+		Daily:          true,
+	}
+	require.NoError(t, store.AddContent(content))
+
+	aired := time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)
+	known := &Episode{ContentID: content.ID, Season: 29, Episode: 30, Status: StatusWanted, AirDate: &aired}
+	require.NoError(t, store.AddEpisode(known))
+
+	// An episode with a matching air date is found regardless of its numbering
+	ep, created, err := store.FindOrCreateEpisodeByDate(content.ID, aired)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, known.ID, ep.ID)
+
+	// Unknown dates are created in a season keyed by year
+	date := time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)
+	ep, created, err = store.FindOrCreateEpisodeByDate(content.ID, date)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, 2024, ep.Season)
+	assert.Equal(t, 1, ep.Episode)
+	require.NotNil(t, ep.AirDate)
+	assert.True(t, date.Equal(*ep.AirDate))
+
+	next, created, err := store.FindOrCreateEpisodeByDate(content.ID, date.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, 2, next.Episode)
+
+	again, created, err := store.FindOrCreateEpisodeByDate(content.ID, date)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, ep.ID, again.ID)
+
+	missing, err := store.FindEpisodeByDate(content.ID, date.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	got, err := store.GetContent(content.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Daily)
+}
+
 func TestStore_GetSeriesStats(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create test series
@@ -447,7 +502,7 @@ func TestStore_GetSeriesStats(t *testing.T) {
 }
 
 func TestStore_GetSeriesStats_AllAvailable(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	series := &Content{
@@ -471,8 +526,48 @@ func TestStore_GetSeriesStats_AllAvailable(t *testing.T) {
 	assert.Equal(t, 1, stats.SeasonCount)
 }
 
+func TestStore_GetSeriesStats_SeasonSizesAndAirDates(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	series := &Content{Type: ContentTypeSeries, Title: "Sized Series", Year: 2024, Status: StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, store.AddContent(series))
+
+	aired1 := time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)
+	aired2 := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	ep1 := &Episode{ContentID: series.ID, Season: 1, Episode: 1, Status: StatusAvailable, AirDate: &aired1}
+	ep2 := &Episode{ContentID: series.ID, Season: 1, Episode: 2, Status: StatusAvailable, AirDate: &aired2}
+	ep3 := &Episode{ContentID: series.ID, Season: 2, Episode: 1, Status: StatusWanted}
+	for _, ep := range []*Episode{ep1, ep2, ep3} {
+		require.NoError(t, store.AddEpisode(ep))
+	}
+	// Episode 1 has two files (e.g. a proper alongside the original); both count toward size
+	require.NoError(t, store.AddFile(&File{ContentID: series.ID, EpisodeID: &ep1.ID, Path: "/tv/s01e01.mkv", SizeBytes: 1000}))
+	require.NoError(t, store.AddFile(&File{ContentID: series.ID, EpisodeID: &ep1.ID, Path: "/tv/s01e01.proper.mkv", SizeBytes: 500}))
+	require.NoError(t, store.AddFile(&File{ContentID: series.ID, EpisodeID: &ep2.ID, Path: "/tv/s01e02.mkv", SizeBytes: 2000}))
+
+	stats, err := store.GetSeriesStats(series.ID)
+	require.NoError(t, err)
+	require.Len(t, stats.Seasons, 2)
+
+	s1 := stats.Seasons[0]
+	assert.Equal(t, 1, s1.Season)
+	assert.Equal(t, 2, s1.Total, "multiple files per episode must not inflate counts")
+	assert.Equal(t, 2, s1.Available)
+	assert.Equal(t, int64(3500), s1.SizeBytes)
+	require.NotNil(t, s1.LastAirDate)
+	assert.True(t, aired2.Equal(*s1.LastAirDate))
+	assert.True(t, s1.Complete())
+
+	s2 := stats.Seasons[1]
+	assert.Equal(t, 2, s2.Season)
+	assert.Equal(t, int64(0), s2.SizeBytes)
+	assert.Nil(t, s2.LastAirDate)
+	assert.False(t, s2.Complete())
+}
+
 func TestStore_GetSeriesStatsBatch(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create two series
@@ -528,7 +623,7 @@ func TestStore_GetSeriesStatsBatch(t *testing.T) {
 }
 
 func TestStore_GetSeriesStatsBatch_EmptyInput(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Empty input should return empty map
@@ -538,7 +633,7 @@ func TestStore_GetSeriesStatsBatch_EmptyInput(t *testing.T) {
 }
 
 func TestStore_GetSeriesStatsBatch_NoEpisodes(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series with no episodes
@@ -559,7 +654,7 @@ func TestStore_GetSeriesStatsBatch_NoEpisodes(t *testing.T) {
 }
 
 func TestStore_BulkAddEpisodes(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create test series
@@ -619,7 +714,7 @@ func TestStore_BulkAddEpisodes(t *testing.T) {
 }
 
 func TestStore_BulkAddEpisodes_Empty(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Empty slice should return 0, nil
@@ -628,8 +723,97 @@ func TestStore_BulkAddEpisodes_Empty(t *testing.T) {
 	assert.Equal(t, 0, inserted)
 }
 
+func TestStore_SyncEpisodes(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	series := &Content{
+		Type:           ContentTypeSeries,
+		Title:          "Sync Test Show",
+		Year:           2024,
+		Status:         StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}
+	require.NoError(t, store.AddContent(series))
+
+	airDate1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.AddEpisode(&Episode{ContentID: series.ID, Season: 1, Episode: 1, Title: "TBA", Status: StatusAvailable, AirDate: &airDate1}))
+	require.NoError(t, store.AddEpisode(&Episode{ContentID: series.ID, Season: 1, Episode: 2, Title: "Episode Two", Status: StatusWanted}))
+
+	airDate2 := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	airDate3 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	inserted, updated, err := store.SyncEpisodes([]*Episode{
+		{ContentID: series.ID, Season: 1, Episode: 1, Title: "Pilot", Status: StatusWanted, AirDate: &airDate1},
+		{ContentID: series.ID, Season: 1, Episode: 2, Title: "", Status: StatusWanted, AirDate: &airDate2},
+		{ContentID: series.ID, Season: 2, Episode: 1, Title: "New Season", Status: StatusWanted, AirDate: &airDate3},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, inserted, "S02E01 is new")
+	assert.Equal(t, 2, updated, "S01E01 title and S01E02 air date changed")
+
+	episodes, _, err := store.ListEpisodes(EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	require.Len(t, episodes, 3)
+	assert.Equal(t, "Pilot", episodes[0].Title)
+	assert.Equal(t, StatusAvailable, episodes[0].Status, "status of existing episodes is kept")
+	assert.Equal(t, "Episode Two", episodes[1].Title, "empty title does not overwrite")
+	require.NotNil(t, episodes[1].AirDate)
+	assert.True(t, airDate2.Equal(*episodes[1].AirDate))
+	assert.Equal(t, StatusWanted, episodes[2].Status)
+
+	// Syncing the same list again changes nothing
+	inserted, updated, err = store.SyncEpisodes([]*Episode{
+		{ContentID: series.ID, Season: 1, Episode: 1, Title: "Pilot", Status: StatusWanted, AirDate: &airDate1},
+		{ContentID: series.ID, Season: 2, Episode: 1, Title: "New Season", Status: StatusWanted, AirDate: &airDate3},
+	})
+	require.NoError(t, err)
+	assert.Zero(t, inserted)
+	assert.Zero(t, updated)
+}
+
+func TestStore_ListContinuingSeries(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	add := func(title string, tvdbID *int64, status ContentStatus, seriesStatus string) *Content {
+		c := &Content{
+			Type:           ContentTypeSeries,
+			TVDBID:         tvdbID,
+			Title:          title,
+			Year:           2024,
+			Status:         status,
+			QualityProfile: "hd",
+			RootPath:       "/tv",
+			SeriesStatus:   seriesStatus,
+		}
+		require.NoError(t, store.AddContent(c))
+		return c
+	}
+	id := func(n int64) *int64 { return &n }
+
+	continuing := add("Continuing", id(1), StatusAvailable, "continuing")
+	unknown := add("Never Synced", id(2), StatusWanted, "")
+	ended := add("Ended", id(3), StatusAvailable, "")
+	require.NoError(t, store.UpdateSeriesStatus(ended.ID, "Ended"))
+	add("No TVDB ID", nil, StatusWanted, "")
+	add("Abandoned", id(4), StatusAbandoned, "continuing")
+
+	got, err := store.ListContinuingSeries()
+	require.NoError(t, err)
+	ids := make([]int64, 0, len(got))
+	for _, c := range got {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []int64{continuing.ID, unknown.ID}, ids)
+
+	reloaded, err := store.GetContent(ended.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ended", reloaded.SeriesStatus, "status is stored lowercased")
+}
+
 func TestStore_BulkAddEpisodes_PartialDuplicates(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create test series
@@ -667,3 +851,146 @@ func TestStore_BulkAddEpisodes_PartialDuplicates(t *testing.T) {
 	// Verify original was not modified
 	assert.Equal(t, "Existing", results[0].Title, "original episode title should be preserved")
 }
+
+func TestStore_BulkUpdateEpisodeStatus_Seasons(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	series := createTestSeries(t, store)
+
+	for season := 1; season <= 3; season++ {
+		for ep := 1; ep <= 2; ep++ {
+			require.NoError(t, store.AddEpisode(&Episode{ContentID: series.ID, Season: season, Episode: ep, Status: StatusWanted}))
+		}
+	}
+	// Available episodes are never touched
+	require.NoError(t, store.AddEpisode(&Episode{ContentID: series.ID, Season: 1, Episode: 3, Status: StatusAvailable}))
+
+	changed, err := store.BulkUpdateEpisodeStatus(series.ID, EpisodeSelection{Seasons: []int{2, 3}})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 2}, changed, "only season 1 wanted episodes should change")
+
+	episodes, _, err := store.ListEpisodes(EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	for _, e := range episodes {
+		switch {
+		case e.Season == 1 && e.Episode == 3:
+			assert.Equal(t, StatusAvailable, e.Status)
+		case e.Season == 1:
+			assert.Equal(t, StatusUnmonitored, e.Status)
+		default:
+			assert.Equal(t, StatusWanted, e.Status)
+		}
+	}
+
+	// Re-monitoring season 1 flips it back and unmonitors 2 and 3
+	changed, err = store.BulkUpdateEpisodeStatus(series.ID, EpisodeSelection{Seasons: []int{1}})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 2, 2: 2, 3: 2}, changed)
+}
+
+func TestStore_BulkUpdateEpisodeStatus_EpisodeIDs(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	series := createTestSeries(t, store)
+
+	ep1 := &Episode{ContentID: series.ID, Season: 1, Episode: 1, Status: StatusWanted}
+	ep2 := &Episode{ContentID: series.ID, Season: 1, Episode: 2, Status: StatusWanted}
+	require.NoError(t, store.AddEpisode(ep1))
+	require.NoError(t, store.AddEpisode(ep2))
+
+	changed, err := store.BulkUpdateEpisodeStatus(series.ID, EpisodeSelection{EpisodeIDs: []int64{ep2.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 1}, changed)
+
+	got, err := store.GetEpisode(ep1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUnmonitored, got.Status)
+}
+
+func TestStore_BulkUpdateEpisodeStatus_FutureOnly(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	series := createTestSeries(t, store)
+
+	past := time.Now().AddDate(-1, 0, 0)
+	future := time.Now().AddDate(0, 1, 0)
+	aired := &Episode{ContentID: series.ID, Season: 1, Episode: 1, Status: StatusWanted, AirDate: &past}
+	upcoming := &Episode{ContentID: series.ID, Season: 1, Episode: 2, Status: StatusUnmonitored, AirDate: &future}
+	tba := &Episode{ContentID: series.ID, Season: 2, Episode: 1, Status: StatusUnmonitored}
+	require.NoError(t, store.AddEpisode(aired))
+	require.NoError(t, store.AddEpisode(upcoming))
+	require.NoError(t, store.AddEpisode(tba))
+
+	changed, err := store.BulkUpdateEpisodeStatus(series.ID, EpisodeSelection{FutureOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 2, 2: 1}, changed)
+
+	got, err := store.GetEpisode(aired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUnmonitored, got.Status)
+	got, err = store.GetEpisode(tba.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusWanted, got.Status)
+}
+
+func TestStore_BulkUpdateEpisodeStatus_Errors(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	series := createTestSeries(t, store)
+
+	ep := &Episode{ContentID: series.ID, Season: 1, Episode: 1, Status: StatusWanted}
+	require.NoError(t, store.AddEpisode(ep))
+
+	_, err := store.BulkUpdateEpisodeStatus(series.ID, EpisodeSelection{})
+	require.Error(t, err, "no selector")
+
+	_, err = store.BulkUpdateEpisodeStatus(series.ID, EpisodeSelection{Seasons: []int{1}, FutureOnly: true})
+	require.Error(t, err, "multiple selectors")
+
+	_, err = store.BulkUpdateEpisodeStatus(series.ID, EpisodeSelection{EpisodeIDs: []int64{ep.ID, 9999}})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// Nothing changed on error
+	got, err := store.GetEpisode(ep.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusWanted, got.Status)
+}
+
+func TestStore_BulkSetEpisodeProfile(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	series := createTestSeries(t, store)
+
+	for season := 1; season <= 2; season++ {
+		for ep := 1; ep <= 2; ep++ {
+			require.NoError(t, store.AddEpisode(&Episode{ContentID: series.ID, Season: season, Episode: ep, Status: StatusWanted}))
+		}
+	}
+
+	changed, err := store.BulkSetEpisodeProfile(series.ID, EpisodeSelection{Seasons: []int{1}}, "uhd")
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 2}, changed)
+
+	episodes, _, err := store.ListEpisodes(EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	for _, e := range episodes {
+		profile, inherited := e.EffectiveProfile("hd")
+		if e.Season == 1 {
+			assert.Equal(t, "uhd", profile)
+			assert.False(t, inherited)
+		} else {
+			assert.Equal(t, "hd", profile)
+			assert.True(t, inherited)
+		}
+		assert.Equal(t, StatusWanted, e.Status, "monitoring is unchanged")
+	}
+
+	// Clearing the override restores inheritance
+	changed, err = store.BulkSetEpisodeProfile(series.ID, EpisodeSelection{Seasons: []int{1, 2}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 2}, changed)
+
+	ep, err := store.GetEpisode(episodes[0].ID)
+	require.NoError(t, err)
+	assert.Empty(t, ep.QualityProfile)
+}
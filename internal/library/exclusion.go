@@ -0,0 +1,132 @@
+package library
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExclusionSource is the metadata provider an excluded ID belongs to.
+type ExclusionSource string
+
+const (
+	ExclusionTMDB ExclusionSource = "tmdb"
+	ExclusionTVDB ExclusionSource = "tvdb"
+)
+
+// Exclusion is an external ID that must never be added to the library, such
+// as content deliberately deleted that a request tool keeps asking for.
+// Title and Year describe the excluded content for display only.
+type Exclusion struct {
+	ID         int64
+	Source     ExclusionSource
+	ExternalID int64
+	Title      string
+	Year       int
+	Reason     string
+	CreatedAt  time.Time
+}
+
+// ExclusionKey returns the external ID content is excluded by: TMDB for
+// movies and TVDB for series. ok is false if the content lacks that ID.
+func ExclusionKey(c *Content) (source ExclusionSource, id int64, ok bool) {
+	if c.Type == ContentTypeSeries {
+		if c.TVDBID == nil {
+			return "", 0, false
+		}
+		return ExclusionTVDB, *c.TVDBID, true
+	}
+	if c.TMDBID == nil {
+		return "", 0, false
+	}
+	return ExclusionTMDB, *c.TMDBID, true
+}
+
+func addExclusion(q querier, e *Exclusion) error {
+	now := time.Now()
+	result, err := q.Exec(`
+		INSERT INTO exclusions (source, external_id, title, year, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		e.Source, e.ExternalID, e.Title, e.Year, e.Reason, now)
+	if err != nil {
+		return fmt.Errorf("add exclusion %s:%d: %w", e.Source, e.ExternalID, mapSQLiteError(err))
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	e.ID = id
+	e.CreatedAt = now
+	return nil
+}
+
+// AddExclusion excludes an external ID and sets ID and CreatedAt on e.
+// Returns ErrDuplicate if the ID is already excluded, and ErrConstraint for
+// an unknown source.
+func (s *Store) AddExclusion(e *Exclusion) error { return addExclusion(s.db, e) }
+
+// AddExclusion excludes an external ID within a transaction.
+func (t *Tx) AddExclusion(e *Exclusion) error { return addExclusion(t.tx, e) }
+
+// ListExclusions returns all exclusions, newest first.
+func (s *Store) ListExclusions() ([]*Exclusion, error) {
+	rows, err := s.db.Query(`
+		SELECT id, source, external_id, title, year, reason, created_at
+		FROM exclusions ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("list exclusions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var exclusions []*Exclusion
+	for rows.Next() {
+		e := &Exclusion{}
+		if err := rows.Scan(&e.ID, &e.Source, &e.ExternalID, &e.Title, &e.Year, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan exclusion: %w", err)
+		}
+		exclusions = append(exclusions, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate exclusions: %w", err)
+	}
+	return exclusions, nil
+}
+
+// FindExclusion returns the exclusion of an external ID, or ErrNotFound if
+// the ID is not excluded.
+func (s *Store) FindExclusion(source ExclusionSource, externalID int64) (*Exclusion, error) {
+	e := &Exclusion{}
+	err := s.db.QueryRow(`
+		SELECT id, source, external_id, title, year, reason, created_at
+		FROM exclusions WHERE source = ? AND external_id = ?`, source, externalID,
+	).Scan(&e.ID, &e.Source, &e.ExternalID, &e.Title, &e.Year, &e.Reason, &e.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("find exclusion %s:%d: %w", source, externalID, mapSQLiteError(err))
+	}
+	return e, nil
+}
+
+// ContentExclusion returns the exclusion that keeps content out of the
+// library, or ErrNotFound if it may be added. See ExclusionKey.
+func (s *Store) ContentExclusion(c *Content) (*Exclusion, error) {
+	source, id, ok := ExclusionKey(c)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s.FindExclusion(source, id)
+}
+
+// DeleteExclusion removes an exclusion by ID, or returns ErrNotFound.
+func (s *Store) DeleteExclusion(id int64) error {
+	result, err := s.db.Exec("DELETE FROM exclusions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete exclusion %d: %w", id, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete exclusion %d: %w", id, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("delete exclusion %d: %w", id, ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package library
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func TestStore_Exclusions(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	e := &Exclusion{Source: ExclusionTMDB, ExternalID: 603, Title: "The Matrix", Year: 1999, Reason: "seen it"}
+	require.NoError(t, store.AddExclusion(e))
+	assert.NotZero(t, e.ID)
+	assert.False(t, e.CreatedAt.IsZero())
+
+	// One exclusion per external ID
+	err := store.AddExclusion(&Exclusion{Source: ExclusionTMDB, ExternalID: 603})
+	require.ErrorIs(t, err, ErrDuplicate)
+	// The same number from another provider is a different ID
+	require.NoError(t, store.AddExclusion(&Exclusion{Source: ExclusionTVDB, ExternalID: 603}))
+	err = store.AddExclusion(&Exclusion{Source: "imdb", ExternalID: 1})
+	require.ErrorIs(t, err, ErrConstraint)
+
+	got, err := store.FindExclusion(ExclusionTMDB, 603)
+	require.NoError(t, err)
+	assert.Equal(t, "seen it", got.Reason)
+	_, err = store.FindExclusion(ExclusionTMDB, 604)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	list, err := store.ListExclusions()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, ExclusionTVDB, list[0].Source, "newest first")
+
+	require.NoError(t, store.DeleteExclusion(e.ID))
+	require.ErrorIs(t, store.DeleteExclusion(e.ID), ErrNotFound)
+	_, err = store.FindExclusion(ExclusionTMDB, 603)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_ContentExclusion(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	require.NoError(t, store.AddExclusion(&Exclusion{Source: ExclusionTMDB, ExternalID: 603}))
+	require.NoError(t, store.AddExclusion(&Exclusion{Source: ExclusionTVDB, ExternalID: 81189}))
+
+	movie := &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(603))}
+	_, err := store.ContentExclusion(movie)
+	require.NoError(t, err)
+
+	// Series are excluded by TVDB ID, movies by TMDB ID
+	series := &Content{Type: ContentTypeSeries, TMDBID: ptr(int64(603))}
+	_, err = store.ContentExclusion(series)
+	require.ErrorIs(t, err, ErrNotFound)
+	series.TVDBID = ptr(int64(81189))
+	_, err = store.ContentExclusion(series)
+	require.NoError(t, err)
+
+	_, err = store.ContentExclusion(&Content{Type: ContentTypeMovie})
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -7,11 +7,15 @@ import (
 )
 
 func addFile(q querier, f *File) error {
+	// A file imported again where one went missing replaces the old record
+	if _, err := q.Exec("DELETE FROM files WHERE path = ? AND missing_at IS NOT NULL", f.Path); err != nil {
+		return fmt.Errorf("replace missing file: %w", err)
+	}
 	now := time.Now()
 	result, err := q.Exec(`
-		INSERT INTO files (content_id, episode_id, path, size_bytes, quality, source, added_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		f.ContentID, f.EpisodeID, f.Path, f.SizeBytes, f.Quality, f.Source, now,
+		INSERT INTO files (content_id, episode_id, path, size_bytes, quality, source, checksum, part_number, release_group, added_at, kind)
+		VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, 0), ?, ?, ?)`,
+		f.ContentID, f.EpisodeID, f.Path, f.SizeBytes, f.Quality, f.Source, f.Checksum, f.PartNumber, f.ReleaseGroup, now, f.kind(),
 	)
 	if err != nil {
 		return fmt.Errorf("insert file: %w", mapSQLiteError(err))
@@ -22,11 +26,12 @@ func addFile(q querier, f *File) error {
 	}
 	f.ID = id
 	f.AddedAt = now
+	f.Kind = f.kind()
 	return nil
 }
 
-// AddFile inserts a new file into the database.
-// Sets ID and AddedAt on the struct.
+// AddFile inserts a new file into the database, replacing the record of a
+// missing file at the same path. Sets ID and AddedAt on the struct.
 func (s *Store) AddFile(f *File) error { return addFile(s.db, f) }
 
 // AddFile inserts a new file within a transaction.
@@ -35,9 +40,9 @@ func (t *Tx) AddFile(f *File) error { return addFile(t.tx, f) }
 func getFile(q querier, id int64) (*File, error) {
 	f := &File{}
 	err := q.QueryRow(`
-		SELECT id, content_id, episode_id, path, size_bytes, quality, source, added_at
+		SELECT id, content_id, episode_id, path, size_bytes, quality, source, COALESCE(checksum, ''), COALESCE(part_number, 0), release_group, added_at, missing_at, kind
 		FROM files WHERE id = ?`, id,
-	).Scan(&f.ID, &f.ContentID, &f.EpisodeID, &f.Path, &f.SizeBytes, &f.Quality, &f.Source, &f.AddedAt)
+	).Scan(&f.ID, &f.ContentID, &f.EpisodeID, &f.Path, &f.SizeBytes, &f.Quality, &f.Source, &f.Checksum, &f.PartNumber, &f.ReleaseGroup, &f.AddedAt, &f.MissingAt, &f.Kind)
 	if err != nil {
 		return nil, fmt.Errorf("get file %d: %w", id, mapSQLiteError(err))
 	}
@@ -68,6 +73,12 @@ func listFiles(q querier, f FileFilter) ([]*File, int, error) {
 		conditions = append(conditions, filePrefix+"content_id = ?")
 		args = append(args, *f.ContentID)
 	}
+	if len(f.ContentIDs) > 0 {
+		conditions = append(conditions, filePrefix+"content_id IN ("+placeholders(len(f.ContentIDs))+")")
+		for _, id := range f.ContentIDs {
+			args = append(args, id)
+		}
+	}
 	if f.EpisodeID != nil {
 		conditions = append(conditions, filePrefix+"episode_id = ?")
 		args = append(args, *f.EpisodeID)
@@ -80,12 +91,24 @@ func listFiles(q querier, f FileFilter) ([]*File, int, error) {
 		conditions = append(conditions, filePrefix+"quality = ?")
 		args = append(args, *f.Quality)
 	}
-
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	if f.Missing != nil {
+		if *f.Missing {
+			conditions = append(conditions, filePrefix+"missing_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, filePrefix+"missing_at IS NULL")
+		}
+	}
+	kinds := f.Kinds
+	if len(kinds) == 0 {
+		kinds = []FileKind{FileKindMedia}
+	}
+	conditions = append(conditions, filePrefix+"kind IN ("+placeholders(len(kinds))+")")
+	for _, k := range kinds {
+		args = append(args, k)
 	}
 
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
 	// Build FROM clause with optional join
 	fromClause := "files"
 	if needsJoin {
@@ -97,9 +120,9 @@ func listFiles(q querier, f FileFilter) ([]*File, int, error) {
 		return nil, 0, fmt.Errorf("count files: %w", err)
 	}
 
-	selectCols := "id, content_id, episode_id, path, size_bytes, quality, source, added_at"
+	selectCols := "id, content_id, episode_id, path, size_bytes, quality, source, COALESCE(checksum, ''), COALESCE(part_number, 0), release_group, added_at, missing_at, kind"
 	if needsJoin {
-		selectCols = "f.id, f.content_id, f.episode_id, f.path, f.size_bytes, f.quality, f.source, f.added_at"
+		selectCols = "f.id, f.content_id, f.episode_id, f.path, f.size_bytes, f.quality, f.source, COALESCE(f.checksum, ''), COALESCE(f.part_number, 0), f.release_group, f.added_at, f.missing_at, f.kind"
 	}
 	query := "SELECT " + selectCols + " FROM " + fromClause + " " + whereClause + " ORDER BY " + filePrefix + "id"
 	if f.Limit > 0 {
@@ -115,7 +138,7 @@ func listFiles(q querier, f FileFilter) ([]*File, int, error) {
 	var results []*File
 	for rows.Next() {
 		file := &File{}
-		if err := rows.Scan(&file.ID, &file.ContentID, &file.EpisodeID, &file.Path, &file.SizeBytes, &file.Quality, &file.Source, &file.AddedAt); err != nil {
+		if err := rows.Scan(&file.ID, &file.ContentID, &file.EpisodeID, &file.Path, &file.SizeBytes, &file.Quality, &file.Source, &file.Checksum, &file.PartNumber, &file.ReleaseGroup, &file.AddedAt, &file.MissingAt, &file.Kind); err != nil {
 			return nil, 0, fmt.Errorf("scan file: %w", err)
 		}
 		results = append(results, file)
@@ -134,11 +157,41 @@ func (s *Store) ListFiles(f FileFilter) ([]*File, int, error) { return listFiles
 // ListFiles returns files matching the filter within a transaction.
 func (t *Tx) ListFiles(f FileFilter) ([]*File, int, error) { return listFiles(t.tx, f) }
 
+// ListRecentImports returns the content of the most recently added files, newest first.
+// Only display details are returned, never file paths.
+func (s *Store) ListRecentImports(limit int) ([]*RecentImport, error) {
+	rows, err := s.db.Query(`
+		SELECT c.type, c.title, c.year, COALESCE(f.quality, ''), COALESCE(e.season, 0), COALESCE(e.episode, 0), f.added_at
+		FROM files f
+		JOIN content c ON c.id = f.content_id
+		LEFT JOIN episodes e ON e.id = f.episode_id
+		WHERE f.kind = 'media'
+		ORDER BY f.added_at DESC, f.id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list recent imports: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []*RecentImport
+	for rows.Next() {
+		ri := &RecentImport{}
+		if err := rows.Scan(&ri.Type, &ri.Title, &ri.Year, &ri.Quality, &ri.Season, &ri.Episode, &ri.AddedAt); err != nil {
+			return nil, fmt.Errorf("scan recent import: %w", err)
+		}
+		results = append(results, ri)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recent imports: %w", err)
+	}
+	return results, nil
+}
+
 func updateFile(q querier, f *File) error {
 	result, err := q.Exec(`
-		UPDATE files SET content_id = ?, episode_id = ?, path = ?, size_bytes = ?, quality = ?, source = ?
+		UPDATE files SET content_id = ?, episode_id = ?, path = ?, size_bytes = ?, quality = ?, source = ?, checksum = NULLIF(?, ''), part_number = NULLIF(?, 0), release_group = ?, kind = ?
 		WHERE id = ?`,
-		f.ContentID, f.EpisodeID, f.Path, f.SizeBytes, f.Quality, f.Source, f.ID,
+		f.ContentID, f.EpisodeID, f.Path, f.SizeBytes, f.Quality, f.Source, f.Checksum, f.PartNumber, f.ReleaseGroup, f.kind(), f.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("update file %d: %w", f.ID, mapSQLiteError(err))
@@ -174,3 +227,89 @@ func (s *Store) DeleteFile(id int64) error { return deleteFile(s.db, id) }
 
 // DeleteFile removes a file by ID within a transaction.
 func (t *Tx) DeleteFile(id int64) error { return deleteFile(t.tx, id) }
+
+// MarkFileMissing records that a file is gone from disk. The record is kept
+// for its history. If no other present media file covers the file's movie or
+// episode, an available one goes back to wanted; wanted reports whether it
+// did. Missing subtitles change no status. Marking a file already missing
+// changes nothing.
+func (s *Store) MarkFileMissing(f *File) (wanted bool, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now()
+	result, err := tx.Exec("UPDATE files SET missing_at = ? WHERE id = ? AND missing_at IS NULL", now, f.ID)
+	if err != nil {
+		return false, fmt.Errorf("mark file %d missing: %w", f.ID, err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return false, fmt.Errorf("mark file %d missing: %w", f.ID, err)
+	} else if n == 0 {
+		return false, nil
+	}
+	if f.kind() != FileKindMedia {
+		if err := tx.Commit(); err != nil {
+			return false, fmt.Errorf("commit transaction: %w", err)
+		}
+		f.MissingAt = &now
+		return false, nil
+	}
+
+	if f.EpisodeID != nil {
+		result, err = tx.Exec(`
+			UPDATE episodes SET status = 'wanted' WHERE id = ? AND status = 'available'
+			AND NOT EXISTS (SELECT 1 FROM files WHERE episode_id = ? AND missing_at IS NULL AND kind = 'media')`,
+			*f.EpisodeID, *f.EpisodeID)
+	} else {
+		result, err = tx.Exec(`
+			UPDATE content SET status = 'wanted', updated_at = ? WHERE id = ? AND type = 'movie' AND status = 'available'
+			AND NOT EXISTS (SELECT 1 FROM files WHERE content_id = ? AND missing_at IS NULL AND kind = 'media')`,
+			now, f.ContentID, f.ContentID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("mark file %d missing: %w", f.ID, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("mark file %d missing: %w", f.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+	f.MissingAt = &now
+	return n > 0, nil
+}
+
+// MarkFilePresent clears a file's missing mark once it is back on disk, and
+// makes the movie or episode of a media file available again if it went back
+// to wanted.
+func (s *Store) MarkFilePresent(f *File) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec("UPDATE files SET missing_at = NULL WHERE id = ?", f.ID); err != nil {
+		return fmt.Errorf("mark file %d present: %w", f.ID, err)
+	}
+	switch {
+	case f.kind() != FileKindMedia:
+	case f.EpisodeID != nil:
+		_, err = tx.Exec("UPDATE episodes SET status = 'available' WHERE id = ? AND status = 'wanted'", *f.EpisodeID)
+	default:
+		_, err = tx.Exec("UPDATE content SET status = 'available', updated_at = ? WHERE id = ? AND type = 'movie' AND status = 'wanted'",
+			time.Now(), f.ContentID)
+	}
+	if err != nil {
+		return fmt.Errorf("mark file %d present: %w", f.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	f.MissingAt = nil
+	return nil
+}
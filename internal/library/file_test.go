@@ -1,11 +1,13 @@
 package library
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
 )
 
 // createTestMovie creates a movie Content for file tests
@@ -25,7 +27,7 @@ func createTestMovie(t *testing.T, store *Store) *Content {
 }
 
 func TestStore_AddFile(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -50,7 +52,7 @@ func TestStore_AddFile(t *testing.T) {
 }
 
 func TestStore_AddFile_DuplicatePath(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -78,7 +80,7 @@ func TestStore_AddFile_DuplicatePath(t *testing.T) {
 }
 
 func TestStore_GetFile(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -102,10 +104,90 @@ func TestStore_GetFile(t *testing.T) {
 	assert.Equal(t, original.SizeBytes, retrieved.SizeBytes)
 	assert.Equal(t, original.Quality, retrieved.Quality)
 	assert.Equal(t, original.Source, retrieved.Source)
+	assert.Empty(t, retrieved.Checksum, "unverified file has no checksum")
+}
+
+func TestStore_GetFile_Checksum(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	movie := createTestMovie(t, store)
+
+	f := &File{
+		ContentID: movie.ID,
+		Path:      "/movies/Fight Club (1999)/Fight.Club.1999.1080p.BluRay.mkv",
+		SizeBytes: 1024,
+		Checksum:  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	}
+	require.NoError(t, store.AddFile(f))
+
+	retrieved, err := store.GetFile(f.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.Checksum, retrieved.Checksum)
+
+	files, _, err := store.ListFiles(FileFilter{ContentID: &movie.ID})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, f.Checksum, files[0].Checksum)
+}
+
+func TestStore_GetFile_PartNumber(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	movie := createTestMovie(t, store)
+
+	for part := 1; part <= 2; part++ {
+		require.NoError(t, store.AddFile(&File{
+			ContentID:  movie.ID,
+			Path:       fmt.Sprintf("/movies/Fight Club (1999)/Fight Club (1999) - cd%d.avi", part),
+			SizeBytes:  700 << 20,
+			PartNumber: part,
+		}))
+	}
+
+	files, _, err := store.ListFiles(FileFilter{ContentID: &movie.ID})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, 1, files[0].PartNumber)
+	assert.Equal(t, 2, files[1].PartNumber)
+
+	retrieved, err := store.GetFile(files[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, retrieved.PartNumber)
+
+	retrieved.PartNumber = 0
+	require.NoError(t, store.UpdateFile(retrieved))
+	retrieved, err = store.GetFile(retrieved.ID)
+	require.NoError(t, err)
+	assert.Zero(t, retrieved.PartNumber, "part number cleared")
+}
+
+func TestStore_GetFile_ReleaseGroup(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	movie := createTestMovie(t, store)
+
+	f := &File{
+		ContentID:    movie.ID,
+		Path:         "/movies/Fight Club (1999)/Fight Club (1999).mkv",
+		SizeBytes:    1024,
+		ReleaseGroup: "AMIABLE",
+	}
+	require.NoError(t, store.AddFile(f))
+
+	retrieved, err := store.GetFile(f.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "AMIABLE", retrieved.ReleaseGroup)
+
+	retrieved.ReleaseGroup = "FraMeSToR"
+	require.NoError(t, store.UpdateFile(retrieved))
+	files, _, err := store.ListFiles(FileFilter{ContentID: &movie.ID})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "FraMeSToR", files[0].ReleaseGroup)
 }
 
 func TestStore_GetFile_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	_, err := store.GetFile(9999)
@@ -113,7 +195,7 @@ func TestStore_GetFile_NotFound(t *testing.T) {
 }
 
 func TestStore_ListFiles_FilterByContentID(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create two movies
@@ -143,10 +225,18 @@ func TestStore_ListFiles_FilterByContentID(t *testing.T) {
 	for _, f := range results {
 		assert.Equal(t, movie1.ID, f.ContentID, "file ContentID should match")
 	}
+
+	// Filter by several content IDs
+	_, total, err = store.ListFiles(FileFilter{ContentIDs: []int64{movie1.ID, movie2.ID}})
+	require.NoError(t, err, "ListFiles should succeed")
+	assert.Equal(t, 3, total)
+	_, total, err = store.ListFiles(FileFilter{ContentIDs: []int64{movie2.ID}})
+	require.NoError(t, err, "ListFiles should succeed")
+	assert.Equal(t, 1, total)
 }
 
 func TestStore_ListFiles_Pagination(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -179,7 +269,7 @@ func TestStore_ListFiles_Pagination(t *testing.T) {
 }
 
 func TestStore_UpdateFile(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -209,7 +299,7 @@ func TestStore_UpdateFile(t *testing.T) {
 }
 
 func TestStore_UpdateFile_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -225,7 +315,7 @@ func TestStore_UpdateFile_NotFound(t *testing.T) {
 }
 
 func TestStore_DeleteFile(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -247,7 +337,7 @@ func TestStore_DeleteFile(t *testing.T) {
 }
 
 func TestStore_DeleteFile_Idempotent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Delete non-existent should not error
@@ -255,7 +345,7 @@ func TestStore_DeleteFile_Idempotent(t *testing.T) {
 }
 
 func TestStore_AddFile_ForEpisode(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -291,7 +381,7 @@ func TestStore_AddFile_ForEpisode(t *testing.T) {
 }
 
 func TestStore_ListFiles_FilterByEpisodeID(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -316,7 +406,7 @@ func TestStore_ListFiles_FilterByEpisodeID(t *testing.T) {
 }
 
 func TestStore_ListFiles_FilterByQuality(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -338,7 +428,7 @@ func TestStore_ListFiles_FilterByQuality(t *testing.T) {
 }
 
 func TestStore_ListFiles_FilterBySeason(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	series := createTestSeries(t, store)
 
@@ -386,7 +476,7 @@ func TestStore_ListFiles_FilterBySeason(t *testing.T) {
 }
 
 func TestTx_AddFile(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -420,7 +510,7 @@ func TestTx_AddFile(t *testing.T) {
 }
 
 func TestTx_Rollback_File(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 	movie := createTestMovie(t, store)
 
@@ -446,3 +536,110 @@ func TestTx_Rollback_File(t *testing.T) {
 	_, err = store.GetFile(id)
 	assert.ErrorIs(t, err, ErrNotFound, "GetFile after rollback should return ErrNotFound")
 }
+
+func TestStore_MarkFileMissing(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+
+	// One of two files of an episode: the other still covers it
+	files, _, err := store.ListFiles(FileFilter{EpisodeID: ptr(ids["have_episode"])})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	wanted, err := store.MarkFileMissing(files[0])
+	require.NoError(t, err)
+	assert.False(t, wanted)
+	assert.NotNil(t, files[0].MissingAt)
+
+	// Both gone: the episode is wanted again
+	wanted, err = store.MarkFileMissing(files[1])
+	require.NoError(t, err)
+	assert.True(t, wanted)
+	ep, err := store.GetEpisode(ids["have_episode"])
+	require.NoError(t, err)
+	assert.Equal(t, StatusWanted, ep.Status)
+
+	// Marking again changes nothing
+	wanted, err = store.MarkFileMissing(files[1])
+	require.NoError(t, err)
+	assert.False(t, wanted)
+
+	missing := true
+	list, total, err := store.ListFiles(FileFilter{Missing: &missing})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.NotNil(t, list[0].MissingAt)
+
+	// Back on disk: available again
+	require.NoError(t, store.MarkFilePresent(files[1]))
+	assert.Nil(t, files[1].MissingAt)
+	ep, err = store.GetEpisode(ids["have_episode"])
+	require.NoError(t, err)
+	assert.Equal(t, StatusAvailable, ep.Status)
+}
+
+func TestStore_MarkFileMissing_Movie(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+	files, _, err := store.ListFiles(FileFilter{ContentID: ptr(ids["low_movie"])})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	wanted, err := store.MarkFileMissing(files[0])
+	require.NoError(t, err)
+	assert.True(t, wanted)
+	movie, err := store.GetContent(ids["low_movie"])
+	require.NoError(t, err)
+	assert.Equal(t, StatusWanted, movie.Status)
+
+	// A missing file's record is kept but doesn't count as a file
+	items, _, err := store.ListMissing(WantedFilter{ContentID: ptr(ids["low_movie"])})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, ids["low_movie"], items[0].ContentID)
+
+	// Importing the file again replaces the record
+	f := &File{ContentID: ids["low_movie"], Path: files[0].Path, Quality: "1080p"}
+	require.NoError(t, store.AddFile(f))
+	assert.NotEqual(t, files[0].ID, f.ID)
+	_, err = store.GetFile(files[0].ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_SubtitleFiles(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+	files, _, err := store.ListFiles(FileFilter{ContentID: ptr(ids["low_movie"])})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, FileKindMedia, files[0].Kind)
+
+	sub := &File{ContentID: ids["low_movie"], Path: "/movies/Low/Low.en.srt", SizeBytes: 100, Kind: FileKindSubtitle}
+	require.NoError(t, store.AddFile(sub))
+
+	// Listing is of media files unless subtitles are asked for
+	files, total, err := store.ListFiles(FileFilter{ContentID: ptr(ids["low_movie"])})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, FileKindMedia, files[0].Kind)
+	files, _, err = store.ListFiles(FileFilter{ContentID: ptr(ids["low_movie"]), Kinds: []FileKind{FileKindSubtitle}})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, sub.ID, files[0].ID)
+	got, err := store.GetFile(sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, FileKindSubtitle, got.Kind)
+
+	// A subtitle doesn't make its movie available
+	media, _, err := store.ListFiles(FileFilter{ContentID: ptr(ids["low_movie"])})
+	require.NoError(t, err)
+	wanted, err := store.MarkFileMissing(media[0])
+	require.NoError(t, err)
+	assert.True(t, wanted, "the subtitle doesn't cover the movie")
+
+	// Nor does its going missing make it wanted
+	require.NoError(t, store.MarkFilePresent(media[0]))
+	wanted, err = store.MarkFileMissing(sub)
+	require.NoError(t, err)
+	assert.False(t, wanted)
+	assert.NotNil(t, sub.MissingAt)
+	movie, err := store.GetContent(ids["low_movie"])
+	require.NoError(t, err)
+	assert.Equal(t, StatusAvailable, movie.Status)
+}
@@ -1,21 +1,76 @@
 // Package library manages content tracking (movies, series, episodes, files).
 package library
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
 // ContentFilter specifies criteria for listing content.
 type ContentFilter struct {
+	IDs            []int64 // Only these content IDs; empty means any
 	Type           *ContentType
 	Status         *ContentStatus
 	QualityProfile *string
 	TMDBID         *int64
 	TVDBID         *int64
-	Title          *string
+	Title          *string // Matches the title or an alias, ignoring case, punctuation and whitespace
 	Year           *int
-	Limit          int // 0 = no limit
+	MetadataBefore *time.Time  // Metadata never fetched or last fetched before this time
+	Sort           ContentSort // Default: ID
+	Desc           bool        // Reverse the sort
+	Limit          int         // 0 = no limit
 	Offset         int
 }
 
+// ContentSort orders listed content. Items that sort equal are ordered by ID,
+// so pages of a sorted list neither repeat nor skip items.
+type ContentSort string
+
+const (
+	ContentSortAddedAt   ContentSort = "added_at"
+	ContentSortUpdatedAt ContentSort = "updated_at"
+	ContentSortTitle     ContentSort = "title" // Ignoring case
+	ContentSortYear      ContentSort = "year"
+)
+
+// contentSortColumns maps each sort to the expression it orders by. Only
+// these reach the query, so a sort from a request cannot name another column.
+var contentSortColumns = map[ContentSort]string{
+	ContentSortAddedAt:   "added_at",
+	ContentSortUpdatedAt: "updated_at",
+	ContentSortTitle:     "title COLLATE NOCASE",
+	ContentSortYear:      "year",
+}
+
+// ValidContentSort reports whether s is a sort ListContent accepts.
+func ValidContentSort(s ContentSort) bool {
+	_, ok := contentSortColumns[s]
+	return ok
+}
+
+// orderBy returns the ORDER BY clause for the filter's sort.
+func (f ContentFilter) orderBy() (string, error) {
+	dir := "ASC"
+	if f.Desc {
+		dir = "DESC"
+	}
+	if f.Sort == "" {
+		return " ORDER BY id " + dir, nil
+	}
+	column, ok := contentSortColumns[f.Sort]
+	if !ok {
+		return "", fmt.Errorf("unknown content sort %q", f.Sort)
+	}
+	return " ORDER BY " + column + " " + dir + ", id " + dir, nil
+}
+
 // EpisodeFilter specifies criteria for listing episodes.
 type EpisodeFilter struct {
+	IDs       []int64 // Only these episode IDs; empty means any
 	ContentID *int64
 	Season    *int
 	Status    *ContentStatus
@@ -25,10 +80,56 @@ type EpisodeFilter struct {
 
 // FileFilter specifies criteria for listing files.
 type FileFilter struct {
-	ContentID *int64
-	EpisodeID *int64
-	Season    *int // Filter by episode season (requires join with episodes table)
-	Quality   *string
-	Limit     int
-	Offset    int
+	ContentID  *int64
+	ContentIDs []int64 // Only files of these content items; empty means any
+	EpisodeID  *int64
+	Season     *int // Filter by episode season (requires join with episodes table)
+	Quality    *string
+	Missing    *bool      // Only files missing from disk (true) or present (false)
+	Kinds      []FileKind // Only files of these kinds; empty means media files
+	Limit      int
+	Offset     int
+}
+
+// EpisodeSelection chooses episodes of a series for a bulk update.
+// Set exactly one of Seasons, EpisodeIDs, or FutureOnly.
+type EpisodeSelection struct {
+	Seasons    []int
+	EpisodeIDs []int64
+	FutureOnly bool // Episodes that have not aired yet (or have no air date)
+}
+
+// validate checks that exactly one selector is set.
+func (sel EpisodeSelection) validate() error {
+	selectors := 0
+	if len(sel.Seasons) > 0 {
+		selectors++
+	}
+	if len(sel.EpisodeIDs) > 0 {
+		selectors++
+	}
+	if sel.FutureOnly {
+		selectors++
+	}
+	if selectors != 1 {
+		return errors.New("bulk update episodes: exactly one of seasons, episode IDs, or future only must be set")
+	}
+	return nil
+}
+
+// matches reports whether the selection includes the episode.
+func (sel EpisodeSelection) matches(e *Episode, now time.Time) bool {
+	switch {
+	case len(sel.Seasons) > 0:
+		return slices.Contains(sel.Seasons, e.Season)
+	case len(sel.EpisodeIDs) > 0:
+		return slices.Contains(sel.EpisodeIDs, e.ID)
+	default:
+		return e.AirDate == nil || e.AirDate.After(now)
+	}
+}
+
+// placeholders returns n comma-separated SQL placeholders for an IN clause.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
 }
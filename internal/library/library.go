@@ -5,12 +5,15 @@ import (
 	"time"
 )
 
-// ContentType distinguishes movies from series.
+// ContentType distinguishes movies, series, and audiobooks.
 type ContentType string
 
 const (
 	ContentTypeMovie  ContentType = "movie"
 	ContentTypeSeries ContentType = "series"
+	// Audiobooks are single items like movies, named by author and title.
+	// Only added when the audiobook library is enabled.
+	ContentTypeAudiobook ContentType = "audiobook"
 )
 
 // ContentStatus tracks the state of content.
@@ -20,9 +23,19 @@ const (
 	StatusWanted      ContentStatus = "wanted"
 	StatusAvailable   ContentStatus = "available"
 	StatusUnmonitored ContentStatus = "unmonitored"
+	StatusAbandoned   ContentStatus = "abandoned" // Given up on: never searched, hidden from wanted (content only)
 )
 
-// Content represents a movie or series.
+// Valid reports whether s is a known content status.
+func (s ContentStatus) Valid() bool {
+	switch s {
+	case StatusWanted, StatusAvailable, StatusUnmonitored, StatusAbandoned:
+		return true
+	}
+	return false
+}
+
+// Content represents a movie, series, or audiobook.
 type Content struct {
 	ID             int64
 	Type           ContentType
@@ -35,6 +48,48 @@ type Content struct {
 	RootPath       string
 	AddedAt        time.Time
 	UpdatedAt      time.Time
+
+	// Movies are not searched for until the release date reaches this stage; empty means announced
+	MinimumAvailability MinimumAvailability
+	ReleaseDate         *time.Time // TMDB release date (UTC) for movies; nil if unknown
+
+	// Daily series release by air date instead of SxxExx; their episodes are matched by air date
+	Daily bool
+
+	// SeriesStatus is the lowercased TVDB status ("continuing", "ended", ...) stored
+	// by episode syncs; empty if never synced. Continuing series are re-synced.
+	SeriesStatus string
+
+	// Naming overrides; nil and empty inherit the library's naming settings.
+	// SeasonFolder keeps or drops the season directory of the series template.
+	SeasonFolder   *bool
+	NamingTemplate string
+
+	// Author of an audiobook, its top folder in the library; empty for movies and series
+	Author string
+
+	// Display metadata from TMDB (movies) or TVDB (series); empty if no provider is configured
+	Overview          string
+	PosterURL         string
+	Runtime           int // minutes
+	Genres            []string
+	MetadataUpdatedAt *time.Time // nil if metadata was never fetched
+
+	// TMDB collection of a movie (e.g. a franchise), stored with its metadata;
+	// nil and empty if it belongs to none or metadata was never fetched
+	CollectionID   *int64
+	CollectionName string
+
+	// Rating key of the content's Plex item, so verification fetches it
+	// directly; empty until Plex was found to have it. Set with SetPlexKey.
+	PlexKey string
+
+	// SizeOnDisk is the total size of the content's files in bytes; read-only
+	SizeOnDisk int64
+
+	// Pin is the release pinned for the content, grabbed by searches instead of
+	// scoring results; nil if none. Read-only: set with PinRelease.
+	Pin *PinnedRelease
 }
 
 // Episode represents a single episode of a series.
@@ -46,16 +101,68 @@ type Episode struct {
 	Title     string
 	Status    ContentStatus
 	AirDate   *time.Time
+
+	// QualityProfile overrides the series' profile for this episode; empty inherits it
+	QualityProfile string
+}
+
+// EffectiveProfile returns the quality profile used when searching for the episode
+// and whether it is inherited from seriesProfile.
+func (e *Episode) EffectiveProfile(seriesProfile string) (string, bool) {
+	if e.QualityProfile == "" {
+		return seriesProfile, true
+	}
+	return e.QualityProfile, false
+}
+
+// Season records whether a season of a series is monitored.
+// Seasons without a record are treated as monitored.
+type Season struct {
+	ContentID int64
+	Season    int
+	Monitored bool
 }
 
+// FileKind is what a file record holds.
+type FileKind string
+
+const (
+	FileKindMedia    FileKind = "media"    // The video or audio of a movie, episode, or audiobook
+	FileKindSubtitle FileKind = "subtitle" // A subtitle sidecar imported with a media file
+)
+
 // File represents a media file on disk.
 type File struct {
-	ID        int64
-	ContentID int64
-	EpisodeID *int64 // nil for movies
-	Path      string
-	SizeBytes int64
-	Quality   string
-	Source    string
-	AddedAt   time.Time
+	ID           int64
+	ContentID    int64
+	EpisodeID    *int64 // nil for movies
+	Path         string
+	SizeBytes    int64
+	Quality      string
+	Source       string
+	Checksum     string // Hex SHA-256 recorded at import (empty if not verified)
+	PartNumber   int    // Part of a multi-part movie (CD1 = 1); 0 for a single file
+	ReleaseGroup string // Group of the release the file came from; empty if unknown
+	AddedAt      time.Time
+	MissingAt    *time.Time // When the file was found missing from disk; nil while present
+	Kind         FileKind   // Empty is stored as FileKindMedia
+}
+
+// kind returns the file's kind, FileKindMedia if unset.
+func (f *File) kind() FileKind {
+	if f.Kind == "" {
+		return FileKindMedia
+	}
+	return f.Kind
+}
+
+// RecentImport describes a recently imported file by its content, without its path.
+type RecentImport struct {
+	Type    ContentType
+	Title   string
+	Year    int
+	Quality string
+	Season  int // 0 for movies
+	Episode int // 0 for movies
+	AddedAt time.Time
 }
@@ -0,0 +1,93 @@
+package library
+
+import (
+	"fmt"
+	"time"
+)
+
+// PinnedRelease is a release chosen by hand for a content item, for when
+// automatic selection keeps picking a bad one. Searches for the content grab
+// it directly until it imports or its grab fails.
+type PinnedRelease struct {
+	GUID        string // Indexer release GUID; empty if unknown
+	DownloadURL string
+	Title       string
+	Indexer     string
+	Protocol    string // "usenet" or "torrent"; empty to infer from DownloadURL
+	Season      *int   // Series: the season the release is a pack of; nil otherwise
+	PinnedAt    time.Time
+}
+
+// ForSeason reports whether the pin is the release for season, which is nil
+// for movies and audiobooks.
+func (p *PinnedRelease) ForSeason(season *int) bool {
+	if p.Season == nil || season == nil {
+		return p.Season == nil && season == nil
+	}
+	return *p.Season == *season
+}
+
+// Matches reports whether a release is the pinned one: by GUID when both
+// have one, else by title.
+func (p *PinnedRelease) Matches(guid, title string) bool {
+	if p.GUID != "" && guid != "" {
+		return p.GUID == guid
+	}
+	return p.Title == title
+}
+
+// PinRelease pins a release for a content item, replacing any earlier pin,
+// and sets PinnedAt. Returns ErrNotFound if the content does not exist.
+func (s *Store) PinRelease(contentID int64, p *PinnedRelease) error {
+	now := time.Now()
+	result, err := s.db.Exec(`
+		UPDATE content SET pinned_guid = NULLIF(?, ''), pinned_download_url = ?, pinned_title = ?,
+			pinned_indexer = NULLIF(?, ''), pinned_protocol = NULLIF(?, ''), pinned_season = ?, pinned_at = ?
+		WHERE id = ?`,
+		p.GUID, p.DownloadURL, p.Title, p.Indexer, p.Protocol, p.Season, now, contentID,
+	)
+	if err != nil {
+		return fmt.Errorf("pin release for content %d: %w", contentID, mapSQLiteError(err))
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("pin release for content %d: %w", contentID, ErrNotFound)
+	}
+	p.PinnedAt = now
+	return nil
+}
+
+// UnpinRelease removes the pinned release of a content item. Returns
+// ErrNotFound if the content has no pin.
+func (s *Store) UnpinRelease(contentID int64) error {
+	result, err := s.db.Exec(`
+		UPDATE content SET pinned_guid = NULL, pinned_download_url = NULL, pinned_title = NULL,
+			pinned_indexer = NULL, pinned_protocol = NULL, pinned_season = NULL, pinned_at = NULL
+		WHERE id = ? AND pinned_download_url IS NOT NULL`,
+		contentID,
+	)
+	if err != nil {
+		return fmt.Errorf("unpin release for content %d: %w", contentID, mapSQLiteError(err))
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("unpin release for content %d: %w", contentID, ErrNotFound)
+	}
+	return nil
+}
+
+// PinnedRelease returns the release pinned for a content item, or nil if it
+// has none. Returns ErrNotFound if the content does not exist.
+func (s *Store) PinnedRelease(contentID int64) (*PinnedRelease, error) {
+	c, err := s.GetContent(contentID)
+	if err != nil {
+		return nil, err
+	}
+	return c.Pin, nil
+}
@@ -0,0 +1,51 @@
+package library
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func TestStore_PinRelease(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	c := addSeries(t, store, "The Wire", 2002)
+
+	pin, err := store.PinnedRelease(c.ID)
+	require.NoError(t, err)
+	assert.Nil(t, pin)
+
+	p := &PinnedRelease{GUID: "g1", DownloadURL: "http://nzb/1", Title: "The.Wire.S02.1080p.BluRay-GRP", Indexer: "nzbgeek", Season: ptr(2)}
+	require.NoError(t, store.PinRelease(c.ID, p))
+	assert.False(t, p.PinnedAt.IsZero())
+
+	got, err := store.GetContent(c.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.Pin)
+	assert.Equal(t, "g1", got.Pin.GUID)
+	assert.Equal(t, "http://nzb/1", got.Pin.DownloadURL)
+	assert.Equal(t, "nzbgeek", got.Pin.Indexer)
+	assert.Empty(t, got.Pin.Protocol)
+	assert.Equal(t, ptr(2), got.Pin.Season)
+	assert.True(t, got.Pin.ForSeason(ptr(2)))
+	assert.False(t, got.Pin.ForSeason(ptr(1)))
+	assert.False(t, got.Pin.ForSeason(nil))
+	assert.True(t, got.Pin.Matches("g1", "Other.Title"))
+	assert.False(t, got.Pin.Matches("g2", "The.Wire.S02.1080p.BluRay-GRP"))
+
+	// Updating the content keeps the pin
+	got.QualityProfile = "uhd"
+	require.NoError(t, store.UpdateContent(got))
+	pin, err = store.PinnedRelease(c.ID)
+	require.NoError(t, err)
+	require.NotNil(t, pin)
+
+	require.NoError(t, store.UnpinRelease(c.ID))
+	require.ErrorIs(t, store.UnpinRelease(c.ID), ErrNotFound)
+	pin, err = store.PinnedRelease(c.ID)
+	require.NoError(t, err)
+	assert.Nil(t, pin)
+
+	require.ErrorIs(t, store.PinRelease(999, p), ErrNotFound)
+}
@@ -0,0 +1,133 @@
+package library
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// RootStrategy decides which root folder new content is added under.
+type RootStrategy string
+
+const (
+	RootFirstWithSpace RootStrategy = "first_with_space" // First root with at least MinRootFreeSpace free
+	RootMostFreeSpace  RootStrategy = "most_free_space"  // Root with the most free space
+	RootRoundRobin     RootStrategy = "round_robin"      // Each root in turn
+)
+
+// MinRootFreeSpace is the free space a root needs before first_with_space
+// passes over it.
+const MinRootFreeSpace = 10 << 30 // 10 GiB
+
+// ParseRootStrategy parses a strategy name; empty means first_with_space.
+// Returns false for unknown values.
+func ParseRootStrategy(s string) (RootStrategy, bool) {
+	switch RootStrategy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", RootFirstWithSpace:
+		return RootFirstWithSpace, true
+	case RootMostFreeSpace:
+		return RootMostFreeSpace, true
+	case RootRoundRobin:
+		return RootRoundRobin, true
+	}
+	return "", false
+}
+
+// RootFolders is the set of root folders for one content type and the
+// strategy for choosing between them. It is safe for concurrent use.
+type RootFolders struct {
+	paths     []string
+	strategy  RootStrategy
+	freeSpace func(path string) uint64
+
+	mu   sync.Mutex
+	next int // Next root for round_robin
+}
+
+// NewRootFolders returns the root folders in paths, skipping empty and
+// duplicate entries. An unknown strategy falls back to first_with_space.
+func NewRootFolders(paths []string, strategy string) *RootFolders {
+	s, ok := ParseRootStrategy(strategy)
+	if !ok {
+		s = RootFirstWithSpace
+	}
+	r := &RootFolders{strategy: s, freeSpace: FreeSpace}
+	for _, p := range paths {
+		if p != "" && !r.Contains(p) {
+			r.paths = append(r.paths, p)
+		}
+	}
+	return r
+}
+
+// Paths returns the root folders in configured order.
+func (r *RootFolders) Paths() []string {
+	if r == nil {
+		return nil
+	}
+	return r.paths
+}
+
+// Contains reports whether path is one of the root folders.
+func (r *RootFolders) Contains(path string) bool {
+	if r == nil || path == "" {
+		return false
+	}
+	path = filepath.Clean(path)
+	for _, p := range r.paths {
+		if filepath.Clean(p) == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Select picks the root folder for new content. It returns the first root
+// when free space cannot be read, and "" when there are no roots.
+func (r *RootFolders) Select() string {
+	if r == nil || len(r.paths) == 0 {
+		return ""
+	}
+	if len(r.paths) == 1 {
+		return r.paths[0]
+	}
+
+	switch r.strategy {
+	case RootRoundRobin:
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		p := r.paths[r.next%len(r.paths)]
+		r.next++
+		return p
+	case RootMostFreeSpace:
+		best, bestFree := r.paths[0], r.freeSpace(r.paths[0])
+		for _, p := range r.paths[1:] {
+			if free := r.freeSpace(p); free > bestFree {
+				best, bestFree = p, free
+			}
+		}
+		return best
+	default:
+		for _, p := range r.paths {
+			if r.freeSpace(p) >= MinRootFreeSpace {
+				return p
+			}
+		}
+		// Every root is nearly full; keep filling the first
+		return r.paths[0]
+	}
+}
+
+// FreeSpace returns the free space in bytes at path, or 0 if it cannot be read.
+func FreeSpace(path string) uint64 {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0
+	}
+	// Bsize is always positive, safe to convert
+	if stat.Bsize < 0 {
+		return 0
+	}
+	return stat.Bavail * uint64(stat.Bsize) //nolint:gosec // Bsize checked above
+}
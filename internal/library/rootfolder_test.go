@@ -0,0 +1,89 @@
+package library
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRootStrategy(t *testing.T) {
+	tests := []struct {
+		input string
+		want  RootStrategy
+		ok    bool
+	}{
+		{"", RootFirstWithSpace, true},
+		{"first_with_space", RootFirstWithSpace, true},
+		{"most_free_space", RootMostFreeSpace, true},
+		{"Round_Robin", RootRoundRobin, true},
+		{"random", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseRootStrategy(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// newTestRootFolders returns root folders reporting the given free space per path.
+func newTestRootFolders(strategy string, free map[string]uint64, paths ...string) *RootFolders {
+	r := NewRootFolders(paths, strategy)
+	r.freeSpace = func(path string) uint64 { return free[path] }
+	return r
+}
+
+func TestRootFolders_Select(t *testing.T) {
+	free := map[string]uint64{
+		"/movies":  1 << 30, // Nearly full
+		"/movies2": 500 << 30,
+		"/movies3": 900 << 30,
+	}
+
+	t.Run("first with space skips full roots", func(t *testing.T) {
+		r := newTestRootFolders("first_with_space", free, "/movies", "/movies2", "/movies3")
+		assert.Equal(t, "/movies2", r.Select())
+	})
+
+	t.Run("first with space falls back to first when all are full", func(t *testing.T) {
+		r := newTestRootFolders("", map[string]uint64{}, "/movies", "/movies2")
+		assert.Equal(t, "/movies", r.Select())
+	})
+
+	t.Run("most free space", func(t *testing.T) {
+		r := newTestRootFolders("most_free_space", free, "/movies", "/movies2", "/movies3")
+		assert.Equal(t, "/movies3", r.Select())
+	})
+
+	t.Run("round robin", func(t *testing.T) {
+		r := newTestRootFolders("round_robin", free, "/movies", "/movies2")
+		assert.Equal(t, "/movies", r.Select())
+		assert.Equal(t, "/movies2", r.Select())
+		assert.Equal(t, "/movies", r.Select())
+	})
+
+	t.Run("single root", func(t *testing.T) {
+		r := newTestRootFolders("most_free_space", free, "/movies")
+		assert.Equal(t, "/movies", r.Select())
+	})
+
+	t.Run("no roots", func(t *testing.T) {
+		assert.Empty(t, NewRootFolders(nil, "").Select())
+		var r *RootFolders
+		assert.Empty(t, r.Select())
+		assert.Empty(t, r.Paths())
+	})
+}
+
+func TestNewRootFolders_SkipsEmptyAndDuplicates(t *testing.T) {
+	r := NewRootFolders([]string{"/movies", "", "/movies2", "/movies/"}, "")
+	assert.Equal(t, []string{"/movies", "/movies2"}, r.Paths())
+	assert.True(t, r.Contains("/movies2/"))
+	assert.False(t, r.Contains("/tv"))
+}
+
+func TestFreeSpace(t *testing.T) {
+	assert.NotZero(t, FreeSpace(t.TempDir()))
+	assert.Zero(t, FreeSpace("/nonexistent/path/12345"))
+}
@@ -0,0 +1,137 @@
+package library
+
+import (
+	"fmt"
+	"sort"
+)
+
+func setSeasonMonitoring(q querier, contentID int64, seasons map[int]bool) error {
+	for season, monitored := range seasons {
+		_, err := q.Exec(`
+			INSERT INTO seasons (content_id, season, monitored) VALUES (?, ?, ?)
+			ON CONFLICT(content_id, season) DO UPDATE SET monitored = excluded.monitored`,
+			contentID, season, monitored,
+		)
+		if err != nil {
+			return fmt.Errorf("set season %d monitoring: %w", season, mapSQLiteError(err))
+		}
+	}
+	return nil
+}
+
+// SetSeasonMonitoring records the monitored flag for each given season of a series.
+// Seasons not in the map keep their current record.
+func (s *Store) SetSeasonMonitoring(contentID int64, seasons map[int]bool) error {
+	tx, err := s.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := tx.SetSeasonMonitoring(contentID, seasons); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetSeasonMonitoring records season monitoring within a transaction.
+func (t *Tx) SetSeasonMonitoring(contentID int64, seasons map[int]bool) error {
+	return setSeasonMonitoring(t.tx, contentID, seasons)
+}
+
+func listSeasons(q querier, contentID int64) ([]*Season, error) {
+	rows, err := q.Query(`
+		SELECT content_id, season, monitored FROM seasons
+		WHERE content_id = ? ORDER BY season`, contentID)
+	if err != nil {
+		return nil, fmt.Errorf("list seasons: %w", err)
+	}
+	defer rows.Close()
+
+	var seasons []*Season
+	for rows.Next() {
+		ss := &Season{}
+		if err := rows.Scan(&ss.ContentID, &ss.Season, &ss.Monitored); err != nil {
+			return nil, fmt.Errorf("scan season: %w", err)
+		}
+		seasons = append(seasons, ss)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate seasons: %w", err)
+	}
+	return seasons, nil
+}
+
+// ListSeasons returns the recorded season monitoring for a series, ordered by season.
+// Returns an empty slice if no seasons were recorded.
+func (s *Store) ListSeasons(contentID int64) ([]*Season, error) { return listSeasons(s.db, contentID) }
+
+// ListSeasons returns recorded season monitoring within a transaction.
+func (t *Tx) ListSeasons(contentID int64) ([]*Season, error) { return listSeasons(t.tx, contentID) }
+
+// MonitoredSeasons returns the numbers of the recorded seasons that are monitored, ascending.
+func (s *Store) MonitoredSeasons(contentID int64) ([]int, error) {
+	seasons, err := s.ListSeasons(contentID)
+	if err != nil {
+		return nil, err
+	}
+	var monitored []int
+	for _, ss := range seasons {
+		if ss.Monitored {
+			monitored = append(monitored, ss.Season)
+		}
+	}
+	sort.Ints(monitored)
+	return monitored, nil
+}
+
+// ApplySeasonMonitoring marks wanted episodes in unmonitored seasons as unmonitored,
+// so episodes synced after a season request are not searched for.
+// Available episodes are never changed. Returns the number of changed episodes.
+func (s *Store) ApplySeasonMonitoring(contentID int64) (int, error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	seasons, err := tx.ListSeasons(contentID)
+	if err != nil {
+		return 0, err
+	}
+	unmonitored := make(map[int]bool)
+	for _, ss := range seasons {
+		if !ss.Monitored {
+			unmonitored[ss.Season] = true
+		}
+	}
+	if len(unmonitored) == 0 {
+		return 0, nil
+	}
+
+	wanted := StatusWanted
+	episodes, _, err := tx.ListEpisodes(EpisodeFilter{ContentID: &contentID, Status: &wanted})
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, e := range episodes {
+		if !unmonitored[e.Season] {
+			continue
+		}
+		e.Status = StatusUnmonitored
+		if err := tx.UpdateEpisode(e); err != nil {
+			return 0, err
+		}
+		changed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return changed, nil
+}
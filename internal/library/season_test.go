@@ -0,0 +1,73 @@
+package library
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func TestStore_SetSeasonMonitoring(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	series := createTestSeries(t, store)
+
+	seasons, err := store.ListSeasons(series.ID)
+	require.NoError(t, err)
+	assert.Empty(t, seasons)
+
+	require.NoError(t, store.SetSeasonMonitoring(series.ID, map[int]bool{1: false, 2: false, 3: true}))
+	// Updates existing records and leaves unlisted seasons alone
+	require.NoError(t, store.SetSeasonMonitoring(series.ID, map[int]bool{2: true}))
+
+	seasons, err = store.ListSeasons(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []*Season{
+		{ContentID: series.ID, Season: 1, Monitored: false},
+		{ContentID: series.ID, Season: 2, Monitored: true},
+		{ContentID: series.ID, Season: 3, Monitored: true},
+	}, seasons)
+
+	monitored, err := store.MonitoredSeasons(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, monitored)
+}
+
+func TestStore_ApplySeasonMonitoring(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	series := createTestSeries(t, store)
+
+	for _, e := range []*Episode{
+		{ContentID: series.ID, Season: 1, Episode: 1, Status: StatusWanted},
+		{ContentID: series.ID, Season: 1, Episode: 2, Status: StatusAvailable},
+		{ContentID: series.ID, Season: 2, Episode: 1, Status: StatusWanted},
+		{ContentID: series.ID, Season: 3, Episode: 1, Status: StatusWanted},
+	} {
+		require.NoError(t, store.AddEpisode(e))
+	}
+
+	// Nothing recorded: all seasons stay as they are
+	changed, err := store.ApplySeasonMonitoring(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, changed)
+
+	require.NoError(t, store.SetSeasonMonitoring(series.ID, map[int]bool{1: false, 2: true}))
+	changed, err = store.ApplySeasonMonitoring(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	episodes, _, err := store.ListEpisodes(EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	statuses := make(map[[2]int]ContentStatus)
+	for _, e := range episodes {
+		statuses[[2]int{e.Season, e.Episode}] = e.Status
+	}
+	assert.Equal(t, map[[2]int]ContentStatus{
+		{1, 1}: StatusUnmonitored,
+		{1, 2}: StatusAvailable,
+		{2, 1}: StatusWanted,
+		{3, 1}: StatusWanted, // No record: treated as monitored
+	}, statuses)
+}
@@ -0,0 +1,476 @@
+package library
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format written by
+// WriteSnapshot. ReadSnapshot rejects other versions.
+const SnapshotVersion = 1
+
+// snapshotPageSize is how many content items WriteSnapshot loads per query.
+const snapshotPageSize = 500
+
+// ErrSnapshotVersion is returned by ReadSnapshot for a snapshot written in an
+// unsupported format version.
+var ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+// SnapshotItem is a content item in a library snapshot, with its seasons,
+// episodes, and files. Items refer to each other by external ID or title and
+// year rather than database ID, so a snapshot can be restored into any
+// database. Display metadata is not included; it is refetched on refresh.
+type SnapshotItem struct {
+	Type                ContentType         `json:"type"`
+	TMDBID              *int64              `json:"tmdb_id,omitempty"`
+	TVDBID              *int64              `json:"tvdb_id,omitempty"`
+	Title               string              `json:"title"`
+	Year                int                 `json:"year"`
+	Status              ContentStatus       `json:"status"`
+	QualityProfile      string              `json:"quality_profile"`
+	RootPath            string              `json:"root_path"`
+	MinimumAvailability MinimumAvailability `json:"minimum_availability,omitempty"`
+	ReleaseDate         *time.Time          `json:"release_date,omitempty"`
+	Daily               bool                `json:"daily,omitempty"`
+	SeasonFolder        *bool               `json:"season_folder,omitempty"`
+	NamingTemplate      string              `json:"naming_template,omitempty"`
+	Author              string              `json:"author,omitempty"`
+	AddedAt             time.Time           `json:"added_at"`
+	Seasons             []SnapshotSeason    `json:"seasons,omitempty"`
+	Episodes            []SnapshotEpisode   `json:"episodes,omitempty"`
+	Files               []SnapshotFile      `json:"files,omitempty"`
+}
+
+// SnapshotSeason is the monitoring record of a season in a snapshot.
+type SnapshotSeason struct {
+	Season    int  `json:"season"`
+	Monitored bool `json:"monitored"`
+}
+
+// SnapshotEpisode is an episode in a snapshot.
+type SnapshotEpisode struct {
+	Season         int           `json:"season"`
+	Episode        int           `json:"episode"`
+	Title          string        `json:"title,omitempty"`
+	Status         ContentStatus `json:"status"`
+	AirDate        *time.Time    `json:"air_date,omitempty"`
+	QualityProfile string        `json:"quality_profile,omitempty"` // Overrides the series' profile
+}
+
+// SnapshotFile is a file in a snapshot. Episode files name their episode by
+// season and episode number.
+type SnapshotFile struct {
+	Season     *int   `json:"season,omitempty"`
+	Episode    *int   `json:"episode,omitempty"`
+	Path       string `json:"path"`
+	SizeBytes  int64  `json:"size_bytes"`
+	Quality    string `json:"quality,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Checksum   string `json:"checksum,omitempty"`
+	PartNumber int    `json:"part_number,omitempty"`
+	Kind       string `json:"kind,omitempty"` // Omitted for media files
+}
+
+// snapshotHeader is the part of a snapshot document before its content.
+type snapshotHeader struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// WriteSnapshot writes the whole library to w as a versioned JSON document:
+//
+//	{"version": 1, "exported_at": "...", "content": [...]}
+//
+// Items are encoded as they are read, a page of content at a time with the
+// seasons, episodes, and files of the page loaded in one query each, so the
+// library is never held in memory. Returns the number of items written.
+func (s *Store) WriteSnapshot(w io.Writer) (int, error) {
+	header, err := json.Marshal(snapshotHeader{Version: SnapshotVersion, ExportedAt: time.Now().UTC()})
+	if err != nil {
+		return 0, fmt.Errorf("encode snapshot header: %w", err)
+	}
+	// Splice the content array into the header object
+	if _, err := fmt.Fprintf(w, "%s,\"content\":[\n", header[:len(header)-1]); err != nil {
+		return 0, err
+	}
+
+	enc := json.NewEncoder(w)
+	written := 0
+	for offset := 0; ; offset += snapshotPageSize {
+		page, _, err := listContent(s.db, ContentFilter{Limit: snapshotPageSize, Offset: offset})
+		if err != nil {
+			return written, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		items, err := s.snapshotItems(page)
+		if err != nil {
+			return written, err
+		}
+		for _, item := range items {
+			if written > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return written, err
+				}
+			}
+			if err := enc.Encode(item); err != nil {
+				return written, fmt.Errorf("encode %s: %w", item.Title, err)
+			}
+			written++
+		}
+		if len(page) < snapshotPageSize {
+			break
+		}
+	}
+
+	if _, err := io.WriteString(w, "]}\n"); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+// snapshotItems converts a page of content to snapshot items, loading the
+// seasons, episodes, and files of the whole page at once.
+func (s *Store) snapshotItems(page []*Content) ([]*SnapshotItem, error) {
+	ids := make([]int64, len(page))
+	args := make([]any, len(page))
+	items := make(map[int64]*SnapshotItem, len(page))
+	result := make([]*SnapshotItem, len(page))
+	for i, c := range page {
+		ids[i], args[i] = c.ID, c.ID
+		result[i] = &SnapshotItem{
+			Type:                c.Type,
+			TMDBID:              c.TMDBID,
+			TVDBID:              c.TVDBID,
+			Title:               c.Title,
+			Year:                c.Year,
+			Status:              c.Status,
+			QualityProfile:      c.QualityProfile,
+			RootPath:            c.RootPath,
+			MinimumAvailability: c.MinimumAvailability,
+			ReleaseDate:         c.ReleaseDate,
+			Daily:               c.Daily,
+			SeasonFolder:        c.SeasonFolder,
+			NamingTemplate:      c.NamingTemplate,
+			Author:              c.Author,
+			AddedAt:             c.AddedAt,
+		}
+		items[c.ID] = result[i]
+	}
+	in := "(" + placeholders(len(ids)) + ")"
+
+	rows, err := s.db.Query("SELECT content_id, season, monitored FROM seasons WHERE content_id IN "+in+" ORDER BY content_id, season", args...)
+	if err != nil {
+		return nil, fmt.Errorf("list seasons: %w", err)
+	}
+	for rows.Next() {
+		var contentID int64
+		var ss SnapshotSeason
+		if err := rows.Scan(&contentID, &ss.Season, &ss.Monitored); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan season: %w", err)
+		}
+		items[contentID].Seasons = append(items[contentID].Seasons, ss)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate seasons: %w", err)
+	}
+
+	// Episode numbers by ID, for naming the episodes of files
+	type episodeKey struct{ season, episode int }
+	episodes := make(map[int64]episodeKey)
+	rows, err = s.db.Query("SELECT "+episodeColumns+" FROM episodes WHERE content_id IN "+in+" ORDER BY content_id, season, episode", args...)
+	if err != nil {
+		return nil, fmt.Errorf("list episodes: %w", err)
+	}
+	for rows.Next() {
+		e := &Episode{}
+		if err := rows.Scan(&e.ID, &e.ContentID, &e.Season, &e.Episode, &e.Title, &e.Status, &e.AirDate, &e.QualityProfile); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan episode: %w", err)
+		}
+		episodes[e.ID] = episodeKey{e.Season, e.Episode}
+		items[e.ContentID].Episodes = append(items[e.ContentID].Episodes, SnapshotEpisode{
+			Season:         e.Season,
+			Episode:        e.Episode,
+			Title:          e.Title,
+			Status:         e.Status,
+			AirDate:        e.AirDate,
+			QualityProfile: e.QualityProfile,
+		})
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate episodes: %w", err)
+	}
+
+	files, _, err := listFiles(s.db, FileFilter{ContentIDs: ids, Kinds: []FileKind{FileKindMedia, FileKindSubtitle}})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		sf := SnapshotFile{
+			Path:       f.Path,
+			SizeBytes:  f.SizeBytes,
+			Quality:    f.Quality,
+			Source:     f.Source,
+			Checksum:   f.Checksum,
+			PartNumber: f.PartNumber,
+		}
+		if f.Kind != FileKindMedia {
+			sf.Kind = string(f.Kind)
+		}
+		if f.EpisodeID != nil {
+			if key, ok := episodes[*f.EpisodeID]; ok {
+				sf.Season, sf.Episode = &key.season, &key.episode
+			}
+		}
+		items[f.ContentID].Files = append(items[f.ContentID].Files, sf)
+	}
+	return result, nil
+}
+
+// ReadSnapshot decodes a snapshot written by WriteSnapshot from r, calling fn
+// for each content item as it is decoded, so the document is never held in
+// memory. Returns ErrSnapshotVersion for a version other than SnapshotVersion;
+// the version must come before the content, as WriteSnapshot writes it.
+func ReadSnapshot(r io.Reader, fn func(*SnapshotItem) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	version := 0
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("read snapshot: %w", err)
+		}
+		switch tok {
+		case "version":
+			if err := dec.Decode(&version); err != nil {
+				return fmt.Errorf("read snapshot version: %w", err)
+			}
+			if version != SnapshotVersion {
+				return fmt.Errorf("%w: %d (want %d)", ErrSnapshotVersion, version, SnapshotVersion)
+			}
+		case "content":
+			if version == 0 {
+				return fmt.Errorf("%w: version missing before content", ErrSnapshotVersion)
+			}
+			if err := expectDelim(dec, '['); err != nil {
+				return err
+			}
+			for dec.More() {
+				var item SnapshotItem
+				if err := dec.Decode(&item); err != nil {
+					return fmt.Errorf("read snapshot item: %w", err)
+				}
+				if err := fn(&item); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		default:
+			// Skip fields this version doesn't use, such as exported_at
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("read snapshot: %w", err)
+			}
+		}
+	}
+	if version == 0 {
+		return fmt.Errorf("%w: version missing", ErrSnapshotVersion)
+	}
+	return expectDelim(dec, '}')
+}
+
+// expectDelim reads the next JSON token and checks it is the delimiter want.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+	if tok != want {
+		return fmt.Errorf("read snapshot: expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// SnapshotAction is what restoring a snapshot item did.
+type SnapshotAction string
+
+const (
+	SnapshotCreated SnapshotAction = "created" // Content was not in the library and was added
+	SnapshotUpdated SnapshotAction = "updated" // Existing content was overwritten from the snapshot
+	SnapshotSkipped SnapshotAction = "skipped" // Existing content was left as is
+)
+
+// RestoreSnapshotItem adds a snapshot item to the library, or, if content
+// with the same external ID (or type, title, and year) exists, overwrites it
+// when overwrite is set and skips it otherwise. Overwriting updates the
+// content and the snapshot's seasons, episodes, and files; records missing
+// from the snapshot are kept. Returns the content ID and what was done.
+// The item is restored under a savepoint, so a failed item leaves nothing
+// behind and the transaction can go on with the next.
+func (t *Tx) RestoreSnapshotItem(item *SnapshotItem, overwrite bool) (int64, SnapshotAction, error) {
+	if _, err := t.tx.Exec("SAVEPOINT snapshot_item"); err != nil {
+		return 0, "", fmt.Errorf("savepoint: %w", err)
+	}
+	id, action, err := restoreSnapshotItem(t.tx, item, overwrite)
+	if err != nil {
+		_, _ = t.tx.Exec("ROLLBACK TO snapshot_item")
+		_, _ = t.tx.Exec("RELEASE snapshot_item")
+		return 0, "", err
+	}
+	if _, err := t.tx.Exec("RELEASE snapshot_item"); err != nil {
+		return 0, "", fmt.Errorf("release savepoint: %w", err)
+	}
+	return id, action, nil
+}
+
+func restoreSnapshotItem(q querier, item *SnapshotItem, overwrite bool) (int64, SnapshotAction, error) {
+	if item.Type != ContentTypeMovie && item.Type != ContentTypeSeries && item.Type != ContentTypeAudiobook {
+		return 0, "", fmt.Errorf("restore %q: invalid type %q: %w", item.Title, item.Type, ErrConstraint)
+	}
+	if item.Title == "" {
+		return 0, "", fmt.Errorf("restore: missing title: %w", ErrConstraint)
+	}
+	if !item.Status.Valid() {
+		return 0, "", fmt.Errorf("restore %q: invalid status %q: %w", item.Title, item.Status, ErrConstraint)
+	}
+	for _, sf := range item.Files {
+		if kind := FileKind(sf.Kind); kind != "" && kind != FileKindMedia && kind != FileKindSubtitle {
+			return 0, "", fmt.Errorf("restore %q: file %s: invalid kind %q: %w", item.Title, sf.Path, sf.Kind, ErrConstraint)
+		}
+	}
+
+	c, err := findSnapshotContent(q, item)
+	if err != nil {
+		return 0, "", err
+	}
+	action := SnapshotCreated
+	switch {
+	case c != nil && !overwrite:
+		return c.ID, SnapshotSkipped, nil
+	case c != nil:
+		action = SnapshotUpdated
+	default:
+		c = &Content{}
+	}
+	c.Type, c.TMDBID, c.TVDBID = item.Type, item.TMDBID, item.TVDBID
+	c.Title, c.Year, c.Status = item.Title, item.Year, item.Status
+	c.QualityProfile, c.RootPath = item.QualityProfile, item.RootPath
+	c.MinimumAvailability, c.ReleaseDate, c.Daily = item.MinimumAvailability, item.ReleaseDate, item.Daily
+	c.SeasonFolder, c.NamingTemplate, c.Author = item.SeasonFolder, item.NamingTemplate, item.Author
+	if action == SnapshotCreated {
+		err = addContent(q, c)
+	} else {
+		err = updateContent(q, c)
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	// Neither add nor update writes these as given
+	addedAt := item.AddedAt
+	if addedAt.IsZero() {
+		addedAt = c.AddedAt
+	}
+	if _, err := q.Exec("UPDATE content SET added_at = ?, release_date = ? WHERE id = ?", addedAt, item.ReleaseDate, c.ID); err != nil {
+		return 0, "", fmt.Errorf("restore %q dates: %w", item.Title, mapSQLiteError(err))
+	}
+
+	if len(item.Seasons) > 0 {
+		seasons := make(map[int]bool, len(item.Seasons))
+		for _, ss := range item.Seasons {
+			seasons[ss.Season] = ss.Monitored
+		}
+		if err := setSeasonMonitoring(q, c.ID, seasons); err != nil {
+			return 0, "", err
+		}
+	}
+
+	episodeIDs := make(map[[2]int]int64, len(item.Episodes))
+	for _, se := range item.Episodes {
+		e, _, err := findOrCreateEpisode(q, c.ID, se.Season, se.Episode)
+		if err != nil {
+			return 0, "", err
+		}
+		e.Title, e.Status, e.AirDate, e.QualityProfile = se.Title, se.Status, se.AirDate, se.QualityProfile
+		if err := updateEpisode(q, e); err != nil {
+			return 0, "", err
+		}
+		episodeIDs[[2]int{se.Season, se.Episode}] = e.ID
+	}
+
+	for _, sf := range item.Files {
+		f := &File{
+			ContentID:  c.ID,
+			Path:       sf.Path,
+			SizeBytes:  sf.SizeBytes,
+			Quality:    sf.Quality,
+			Source:     sf.Source,
+			Checksum:   sf.Checksum,
+			PartNumber: sf.PartNumber,
+			Kind:       FileKind(sf.Kind),
+		}
+		if sf.Season != nil && sf.Episode != nil {
+			id, ok := episodeIDs[[2]int{*sf.Season, *sf.Episode}]
+			if !ok {
+				e, _, err := findOrCreateEpisode(q, c.ID, *sf.Season, *sf.Episode)
+				if err != nil {
+					return 0, "", err
+				}
+				id = e.ID
+			}
+			f.EpisodeID = &id
+		}
+		if action == SnapshotUpdated {
+			err := q.QueryRow("SELECT id FROM files WHERE path = ?", f.Path).Scan(&f.ID)
+			if err == nil {
+				if err := updateFile(q, f); err != nil {
+					return 0, "", err
+				}
+				continue
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return 0, "", fmt.Errorf("find file %s: %w", f.Path, err)
+			}
+		}
+		if err := addFile(q, f); err != nil {
+			return 0, "", fmt.Errorf("restore %q file %s: %w", item.Title, f.Path, err)
+		}
+	}
+	return c.ID, action, nil
+}
+
+// findSnapshotContent returns the content a snapshot item is for: the item
+// with its TMDB ID (movies) or TVDB ID (series), else its type, title, and
+// year. Returns nil if there is none.
+func findSnapshotContent(q querier, item *SnapshotItem) (*Content, error) {
+	filters := make([]ContentFilter, 0, 2)
+	if item.Type == ContentTypeMovie && item.TMDBID != nil {
+		filters = append(filters, ContentFilter{Type: &item.Type, TMDBID: item.TMDBID, Limit: 1})
+	}
+	if item.Type == ContentTypeSeries && item.TVDBID != nil {
+		filters = append(filters, ContentFilter{Type: &item.Type, TVDBID: item.TVDBID, Limit: 1})
+	}
+	filters = append(filters, ContentFilter{Type: &item.Type, Title: &item.Title, Year: &item.Year, Limit: 1})
+	for _, f := range filters {
+		found, _, err := listContent(q, f)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) > 0 {
+			return found[0], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,194 @@
+package library
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+// seedSnapshotLibrary adds a movie with a file and a series with a season
+// record, two episodes, and an episode file.
+func seedSnapshotLibrary(t *testing.T, store *Store) (movie, series *Content) {
+	t.Helper()
+	movie = &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(603)), Title: "The Matrix", Year: 1999,
+		Status: StatusAvailable, QualityProfile: "uhd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(movie))
+	require.NoError(t, store.AddFile(&File{ContentID: movie.ID, Path: "/movies/The Matrix (1999)/The Matrix (1999).mkv",
+		SizeBytes: 1000, Quality: "2160p", Source: "nzbgeek", Checksum: "abc"}))
+
+	series = &Content{Type: ContentTypeSeries, TVDBID: ptr(int64(81189)), Title: "Breaking Bad", Year: 2008,
+		Status: StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, store.AddContent(series))
+	require.NoError(t, store.SetSeasonMonitoring(series.ID, map[int]bool{1: true, 2: false}))
+	airDate := time.Date(2008, 1, 20, 0, 0, 0, 0, time.UTC)
+	ep1 := &Episode{ContentID: series.ID, Season: 1, Episode: 1, Title: "Pilot", Status: StatusAvailable, AirDate: &airDate}
+	require.NoError(t, store.AddEpisode(ep1))
+	require.NoError(t, store.AddEpisode(&Episode{ContentID: series.ID, Season: 1, Episode: 2, Title: "Cat's in the Bag",
+		Status: StatusWanted, QualityProfile: "uhd"}))
+	require.NoError(t, store.AddFile(&File{ContentID: series.ID, EpisodeID: &ep1.ID, Path: "/tv/Breaking Bad/S01E01.mkv",
+		SizeBytes: 500, Quality: "1080p"}))
+	return movie, series
+}
+
+func TestStore_WriteSnapshot(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	seedSnapshotLibrary(t, store)
+
+	var buf bytes.Buffer
+	n, err := store.WriteSnapshot(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	var doc struct {
+		Version int             `json:"version"`
+		Content []*SnapshotItem `json:"content"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc), "snapshot must be valid JSON: %s", buf.String())
+	assert.Equal(t, SnapshotVersion, doc.Version)
+	require.Len(t, doc.Content, 2)
+
+	movie := doc.Content[0]
+	assert.Equal(t, "The Matrix", movie.Title)
+	assert.Equal(t, "uhd", movie.QualityProfile)
+	require.Len(t, movie.Files, 1)
+	assert.Nil(t, movie.Files[0].Season)
+	assert.Equal(t, "abc", movie.Files[0].Checksum)
+
+	series := doc.Content[1]
+	assert.Equal(t, []SnapshotSeason{{Season: 1, Monitored: true}, {Season: 2, Monitored: false}}, series.Seasons)
+	require.Len(t, series.Episodes, 2)
+	assert.Equal(t, "uhd", series.Episodes[1].QualityProfile)
+	require.Len(t, series.Files, 1)
+	assert.Equal(t, 1, *series.Files[0].Season)
+	assert.Equal(t, 1, *series.Files[0].Episode)
+}
+
+func TestStore_WriteSnapshot_Empty(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	var buf bytes.Buffer
+	n, err := store.WriteSnapshot(&buf)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	var items []*SnapshotItem
+	require.NoError(t, ReadSnapshot(&buf, func(item *SnapshotItem) error {
+		items = append(items, item)
+		return nil
+	}))
+	assert.Empty(t, items)
+}
+
+func TestReadSnapshot_Version(t *testing.T) {
+	noop := func(*SnapshotItem) error { return nil }
+
+	err := ReadSnapshot(strings.NewReader(`{"version": 2, "content": []}`), noop)
+	require.ErrorIs(t, err, ErrSnapshotVersion)
+
+	err = ReadSnapshot(strings.NewReader(`{"content": []}`), noop)
+	require.ErrorIs(t, err, ErrSnapshotVersion)
+
+	err = ReadSnapshot(strings.NewReader(`[]`), noop)
+	require.Error(t, err)
+}
+
+func TestTx_RestoreSnapshotItem_RoundTrip(t *testing.T) {
+	src := NewStore(testutil.OpenTestDB(t))
+	seedSnapshotLibrary(t, src)
+	var buf bytes.Buffer
+	_, err := src.WriteSnapshot(&buf)
+	require.NoError(t, err)
+
+	dst := NewStore(testutil.OpenTestDB(t))
+	tx, err := dst.Begin()
+	require.NoError(t, err)
+	var actions []SnapshotAction
+	require.NoError(t, ReadSnapshot(&buf, func(item *SnapshotItem) error {
+		_, action, err := tx.RestoreSnapshotItem(item, false)
+		actions = append(actions, action)
+		return err
+	}))
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, []SnapshotAction{SnapshotCreated, SnapshotCreated}, actions)
+
+	series, err := dst.GetByTitleYear("Breaking Bad", 2008)
+	require.NoError(t, err)
+	require.NotNil(t, series)
+	assert.Equal(t, int64(81189), *series.TVDBID)
+	monitored, err := dst.MonitoredSeasons(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, monitored)
+	episodes, _, err := dst.ListEpisodes(EpisodeFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	require.Len(t, episodes, 2)
+	assert.Equal(t, "uhd", episodes[1].QualityProfile)
+	files, _, err := dst.ListFiles(FileFilter{ContentID: &series.ID})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, episodes[0].ID, *files[0].EpisodeID)
+}
+
+func TestTx_RestoreSnapshotItem_Existing(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	movie, _ := seedSnapshotLibrary(t, store)
+
+	item := &SnapshotItem{Type: ContentTypeMovie, TMDBID: ptr(int64(603)), Title: "The Matrix", Year: 1999,
+		Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies2",
+		Files: []SnapshotFile{{Path: "/movies/The Matrix (1999)/The Matrix (1999).mkv", SizeBytes: 2000, Quality: "1080p"}}}
+
+	tx, err := store.Begin()
+	require.NoError(t, err)
+	id, action, err := tx.RestoreSnapshotItem(item, false)
+	require.NoError(t, err)
+	assert.Equal(t, movie.ID, id)
+	assert.Equal(t, SnapshotSkipped, action)
+
+	id, action, err = tx.RestoreSnapshotItem(item, true)
+	require.NoError(t, err)
+	assert.Equal(t, movie.ID, id)
+	assert.Equal(t, SnapshotUpdated, action)
+	require.NoError(t, tx.Commit())
+
+	got, err := store.GetContent(movie.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hd", got.QualityProfile)
+	assert.Equal(t, "/movies2", got.RootPath)
+	files, _, err := store.ListFiles(FileFilter{ContentID: &movie.ID})
+	require.NoError(t, err)
+	require.Len(t, files, 1, "a file with the same path is updated, not added")
+	assert.Equal(t, int64(2000), files[0].SizeBytes)
+}
+
+func TestTx_RestoreSnapshotItem_FailureLeavesNothing(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	movie, _ := seedSnapshotLibrary(t, store)
+
+	// The file path belongs to another movie, so the insert fails after the content was added
+	item := &SnapshotItem{Type: ContentTypeMovie, Title: "Other Movie", Year: 2001, Status: StatusWanted,
+		QualityProfile: "hd", RootPath: "/movies",
+		Files: []SnapshotFile{{Path: "/movies/The Matrix (1999)/The Matrix (1999).mkv"}}}
+
+	tx, err := store.Begin()
+	require.NoError(t, err)
+	_, _, err = tx.RestoreSnapshotItem(item, false)
+	require.ErrorIs(t, err, ErrDuplicate)
+	_, _, err = tx.RestoreSnapshotItem(&SnapshotItem{Type: ContentTypeMovie, Title: "Next Movie", Year: 2002,
+		Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}, false)
+	require.NoError(t, err, "the transaction goes on after a failed item")
+	require.NoError(t, tx.Commit())
+
+	other, err := store.GetByTitleYear("Other Movie", 2001)
+	require.NoError(t, err)
+	assert.Nil(t, other)
+	next, err := store.GetByTitleYear("Next Movie", 2002)
+	require.NoError(t, err)
+	assert.NotNil(t, next)
+	_, err = store.GetContent(movie.ID)
+	require.NoError(t, err)
+}
@@ -7,11 +7,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
 )
 
 // TestSQLiteCompat_NullHandling verifies NULL handling works correctly.
 func TestSQLiteCompat_NullHandling(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create content with nil optional fields
@@ -36,7 +37,7 @@ func TestSQLiteCompat_NullHandling(t *testing.T) {
 
 // TestSQLiteCompat_TypeAffinity verifies type coercion works.
 func TestSQLiteCompat_TypeAffinity(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Store with int64 ID
@@ -63,7 +64,7 @@ func TestSQLiteCompat_TypeAffinity(t *testing.T) {
 // we limit to a single connection to ensure all goroutines share
 // the same database state.
 func TestSQLiteCompat_ConcurrentWrites(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	// With in-memory SQLite, multiple connections create separate databases.
 	// Limit to 1 connection so all goroutines share the same database.
 	db.SetMaxOpenConns(1)
@@ -110,7 +111,7 @@ func TestSQLiteCompat_ConcurrentWrites(t *testing.T) {
 
 // TestSQLiteCompat_ConstraintErrors verifies error mapping works.
 func TestSQLiteCompat_ConstraintErrors(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Add a series with an episode
@@ -150,7 +151,7 @@ func TestSQLiteCompat_ConstraintErrors(t *testing.T) {
 
 // TestSQLiteCompat_TransactionIsolation verifies transactions work correctly.
 func TestSQLiteCompat_TransactionIsolation(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Start transaction
@@ -186,7 +187,7 @@ func TestSQLiteCompat_TransactionIsolation(t *testing.T) {
 
 // TestSQLiteCompat_TransactionRollback verifies rollback works correctly.
 func TestSQLiteCompat_TransactionRollback(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Start transaction
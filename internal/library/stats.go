@@ -0,0 +1,90 @@
+package library
+
+import "fmt"
+
+// StorageGroup is the number and total size of the files in one group,
+// such as all movie files or all 2160p files.
+type StorageGroup struct {
+	Key       string
+	Files     int
+	SizeBytes int64
+}
+
+// StorageStats summarizes the size of the library's files.
+type StorageStats struct {
+	Files     int
+	SizeBytes int64
+	ByType    []StorageGroup // Keyed by content type, largest first
+	ByQuality []StorageGroup // Keyed by file quality ("unknown" if not recorded), largest first
+}
+
+// StorageStats totals file sizes across the library, by content type and by quality.
+func (s *Store) StorageStats() (*StorageStats, error) {
+	stats := &StorageStats{}
+	if err := s.db.QueryRow("SELECT COUNT(*), COALESCE(SUM(size_bytes), 0) FROM files WHERE kind = 'media'").Scan(&stats.Files, &stats.SizeBytes); err != nil {
+		return nil, fmt.Errorf("total file size: %w", err)
+	}
+
+	var err error
+	stats.ByType, err = s.storageGroups(`
+		SELECT c.type, COUNT(*), COALESCE(SUM(f.size_bytes), 0)
+		FROM files f JOIN content c ON c.id = f.content_id
+		WHERE f.kind = 'media'
+		GROUP BY c.type`)
+	if err != nil {
+		return nil, fmt.Errorf("file size by type: %w", err)
+	}
+	stats.ByQuality, err = s.storageGroups(`
+		SELECT COALESCE(NULLIF(quality, ''), 'unknown') AS q, COUNT(*), COALESCE(SUM(size_bytes), 0)
+		FROM files
+		WHERE kind = 'media'
+		GROUP BY q`)
+	if err != nil {
+		return nil, fmt.Errorf("file size by quality: %w", err)
+	}
+	return stats, nil
+}
+
+// storageGroups runs a query selecting key, file count and total size, and
+// returns the groups largest first.
+func (s *Store) storageGroups(query string) ([]StorageGroup, error) {
+	rows, err := s.db.Query(query + " ORDER BY 3 DESC, 1")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	groups := []StorageGroup{}
+	for rows.Next() {
+		var g StorageGroup
+		if err := rows.Scan(&g.Key, &g.Files, &g.SizeBytes); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// LargestContent returns the content items using the most disk space, largest first.
+// Items without files are not included.
+func (s *Store) LargestContent(limit int) ([]*Content, error) {
+	rows, err := s.db.Query("SELECT "+contentColumns+" FROM "+contentFrom+
+		" WHERE sizes.size_bytes > 0 ORDER BY sizes.size_bytes DESC, id LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("list largest content: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var contents []*Content
+	for rows.Next() {
+		c, err := scanContent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan content: %w", err)
+		}
+		contents = append(contents, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate content: %w", err)
+	}
+	return contents, nil
+}
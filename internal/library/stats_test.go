@@ -0,0 +1,67 @@
+package library
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func TestStore_StorageStats(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	stats, err := store.StorageStats()
+	require.NoError(t, err)
+	assert.Zero(t, stats.Files)
+	assert.Empty(t, stats.ByType)
+	assert.Empty(t, stats.ByQuality)
+
+	movie := &Content{Type: ContentTypeMovie, Title: "Dune", Year: 2021, Status: StatusAvailable, QualityProfile: "uhd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(movie))
+	series := &Content{Type: ContentTypeSeries, Title: "Severance", Year: 2022, Status: StatusAvailable, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, store.AddContent(series))
+	empty := &Content{Type: ContentTypeMovie, Title: "Arrival", Year: 2016, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(empty))
+
+	require.NoError(t, store.AddFile(&File{ContentID: movie.ID, Path: "/movies/dune.mkv", SizeBytes: 60_000, Quality: "2160p"}))
+	require.NoError(t, store.AddFile(&File{ContentID: series.ID, Path: "/tv/s01e01.mkv", SizeBytes: 2_000, Quality: "1080p"}))
+	require.NoError(t, store.AddFile(&File{ContentID: series.ID, Path: "/tv/s01e02.mkv", SizeBytes: 3_000, Quality: "1080p"}))
+	require.NoError(t, store.AddFile(&File{ContentID: series.ID, Path: "/tv/s01e03.mkv", SizeBytes: 1_000}))
+
+	stats, err = store.StorageStats()
+	require.NoError(t, err)
+	assert.Equal(t, 4, stats.Files)
+	assert.Equal(t, int64(66_000), stats.SizeBytes)
+	assert.Equal(t, []StorageGroup{
+		{Key: "movie", Files: 1, SizeBytes: 60_000},
+		{Key: "series", Files: 3, SizeBytes: 6_000},
+	}, stats.ByType)
+	assert.Equal(t, []StorageGroup{
+		{Key: "2160p", Files: 1, SizeBytes: 60_000},
+		{Key: "1080p", Files: 2, SizeBytes: 5_000},
+		{Key: "unknown", Files: 1, SizeBytes: 1_000},
+	}, stats.ByQuality)
+
+	// Size on disk is read with the content
+	got, err := store.GetContent(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6_000), got.SizeOnDisk)
+	contents, _, err := store.ListContent(ContentFilter{})
+	require.NoError(t, err)
+	sizes := map[int64]int64{}
+	for _, c := range contents {
+		sizes[c.ID] = c.SizeOnDisk
+	}
+	assert.Equal(t, map[int64]int64{movie.ID: 60_000, series.ID: 6_000, empty.ID: 0}, sizes)
+
+	largest, err := store.LargestContent(10)
+	require.NoError(t, err)
+	require.Len(t, largest, 2)
+	assert.Equal(t, movie.ID, largest[0].ID)
+	assert.Equal(t, series.ID, largest[1].ID)
+
+	largest, err = store.LargestContent(1)
+	require.NoError(t, err)
+	require.Len(t, largest, 1)
+}
@@ -6,10 +6,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
 )
 
 func TestStore_AddContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	c := &Content{
@@ -37,7 +38,7 @@ func TestStore_AddContent(t *testing.T) {
 }
 
 func TestStore_AddContent_Series(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	c := &Content{
@@ -55,7 +56,7 @@ func TestStore_AddContent_Series(t *testing.T) {
 }
 
 func TestStore_GetContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	original := &Content{
@@ -85,7 +86,7 @@ func TestStore_GetContent(t *testing.T) {
 }
 
 func TestStore_GetContent_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	_, err := store.GetContent(9999)
@@ -93,7 +94,7 @@ func TestStore_GetContent_NotFound(t *testing.T) {
 }
 
 func TestStore_ListContent_All(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Add multiple content items
@@ -117,7 +118,7 @@ func TestStore_ListContent_All(t *testing.T) {
 }
 
 func TestStore_ListContent_FilterByType(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Add movies and series
@@ -138,7 +139,7 @@ func TestStore_ListContent_FilterByType(t *testing.T) {
 }
 
 func TestStore_ListContent_FilterByStatus(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	wanted := &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(550)), Title: "Fight Club", Year: 1999, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
@@ -157,7 +158,7 @@ func TestStore_ListContent_FilterByStatus(t *testing.T) {
 }
 
 func TestStore_ListContent_FilterByTMDBID(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	c1 := &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(550)), Title: "Fight Club", Year: 1999, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
@@ -174,8 +175,28 @@ func TestStore_ListContent_FilterByTMDBID(t *testing.T) {
 	assert.Equal(t, "Fight Club", results[0].Title)
 }
 
+func TestStore_ListContent_FilterByIDs(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	c1 := &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(550)), Title: "Fight Club", Year: 1999, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	c2 := &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(680)), Title: "Pulp Fiction", Year: 1994, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	c3 := &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(13)), Title: "Forrest Gump", Year: 1994, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	for _, c := range []*Content{c1, c2, c3} {
+		require.NoError(t, store.AddContent(c), "AddContent should succeed")
+	}
+
+	results, total, err := store.ListContent(ContentFilter{IDs: []int64{c1.ID, c3.ID, 999}})
+	require.NoError(t, err, "ListContent should succeed")
+
+	assert.Equal(t, 2, total)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Fight Club", results[0].Title)
+	assert.Equal(t, "Forrest Gump", results[1].Title)
+}
+
 func TestStore_ListContent_Pagination(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Add 5 items
@@ -210,8 +231,50 @@ func TestStore_ListContent_Pagination(t *testing.T) {
 	assert.NotEqual(t, results[0].ID, results2[0].ID, "pagination should return different items")
 }
 
+func TestStore_ListContent_Sort(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	// Three share a year, so year pages depend on the ID tie-break
+	for i, m := range []struct {
+		title string
+		year  int
+	}{{"b movie", 2001}, {"A Movie", 2001}, {"C Movie", 2001}, {"d movie", 1999}} {
+		c := &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(i + 1)), Title: m.title, Year: m.year,
+			Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+		require.NoError(t, store.AddContent(c))
+	}
+
+	titles := func(f ContentFilter) []string {
+		t.Helper()
+		items, _, err := store.ListContent(f)
+		require.NoError(t, err)
+		var got []string
+		for _, c := range items {
+			got = append(got, c.Title)
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"A Movie", "b movie", "C Movie", "d movie"}, titles(ContentFilter{Sort: ContentSortTitle}), "ignoring case")
+	assert.Equal(t, []string{"d movie", "C Movie", "b movie", "A Movie"}, titles(ContentFilter{Sort: ContentSortTitle, Desc: true}))
+
+	// Pages of equal years neither repeat nor skip items
+	var paged []string
+	for offset := 0; offset < 4; offset++ {
+		paged = append(paged, titles(ContentFilter{Sort: ContentSortYear, Desc: true, Limit: 1, Offset: offset})...)
+	}
+	assert.Equal(t, []string{"C Movie", "A Movie", "b movie", "d movie"}, paged, "equal years newest ID first")
+	assert.Equal(t, paged, titles(ContentFilter{Sort: ContentSortYear, Desc: true}))
+
+	_, _, err := store.ListContent(ContentFilter{Sort: "title; DROP TABLE content"})
+	require.Error(t, err)
+	assert.False(t, ValidContentSort("title; DROP TABLE content"))
+	assert.True(t, ValidContentSort(ContentSortUpdatedAt))
+}
+
 func TestStore_UpdateContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	c := &Content{
@@ -247,8 +310,36 @@ func TestStore_UpdateContent(t *testing.T) {
 	assert.Equal(t, "4k", retrieved.QualityProfile)
 }
 
+func TestStore_UpdateContent_NamingOverrides(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	c := &Content{Type: ContentTypeSeries, Title: "Frieren", Year: 2023, Status: StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, store.AddContent(c))
+	got, err := store.GetContent(c.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.SeasonFolder, "inherits by default")
+	assert.Empty(t, got.NamingTemplate)
+
+	c.SeasonFolder = ptr(false)
+	c.NamingTemplate = "{title}/{title} - {episode:03}.{ext}"
+	require.NoError(t, store.UpdateContent(c))
+	got, err = store.GetContent(c.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.SeasonFolder)
+	assert.False(t, *got.SeasonFolder)
+	assert.Equal(t, "{title}/{title} - {episode:03}.{ext}", got.NamingTemplate)
+
+	// Clearing inherits again
+	c.SeasonFolder, c.NamingTemplate = nil, ""
+	require.NoError(t, store.UpdateContent(c))
+	got, err = store.GetContent(c.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.SeasonFolder)
+	assert.Empty(t, got.NamingTemplate)
+}
+
 func TestStore_UpdateContent_NotFound(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	c := &Content{
@@ -265,8 +356,32 @@ func TestStore_UpdateContent_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, ErrNotFound, "UpdateContent should return ErrNotFound")
 }
 
+func TestStore_SetPlexKey(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	c := &Content{Type: ContentTypeMovie, Title: "Heat", Year: 1995, Status: StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(c))
+
+	got, err := store.GetContent(c.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.PlexKey)
+
+	require.NoError(t, store.SetPlexKey(c.ID, "12345"))
+	got, err = store.GetContent(c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "12345", got.PlexKey)
+
+	require.NoError(t, store.SetPlexKey(c.ID, ""))
+	got, err = store.GetContent(c.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.PlexKey)
+
+	require.ErrorIs(t, store.SetPlexKey(9999, "1"), ErrNotFound)
+}
+
 func TestStore_DeleteContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	c := &Content{
@@ -289,7 +404,7 @@ func TestStore_DeleteContent(t *testing.T) {
 }
 
 func TestStore_DeleteContent_Idempotent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Delete non-existent should not error
@@ -297,7 +412,7 @@ func TestStore_DeleteContent_Idempotent(t *testing.T) {
 }
 
 func TestTx_AddContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	tx, err := store.Begin()
@@ -332,7 +447,7 @@ func TestTx_AddContent(t *testing.T) {
 }
 
 func TestTx_Rollback(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	tx, err := store.Begin()
@@ -361,7 +476,7 @@ func TestTx_Rollback(t *testing.T) {
 }
 
 func TestTx_ListContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Add initial content
@@ -385,7 +500,7 @@ func TestTx_ListContent(t *testing.T) {
 }
 
 func TestTx_UpdateContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	c := &Content{
@@ -415,7 +530,7 @@ func TestTx_UpdateContent(t *testing.T) {
 }
 
 func TestTx_DeleteContent(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	c := &Content{
@@ -442,7 +557,7 @@ func TestTx_DeleteContent(t *testing.T) {
 }
 
 func TestStore_GetByTitleYear(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Add a movie
@@ -460,3 +575,23 @@ func TestStore_GetByTitleYear(t *testing.T) {
 	require.NoError(t, err, "GetByTitleYear should succeed")
 	assert.Nil(t, notFound, "expected nil for nonexistent content")
 }
+
+func TestStore_QualityProfileCounts(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	for i, profile := range []string{"hd", "hd", "uhd"} {
+		require.NoError(t, store.AddContent(&Content{
+			Type:           ContentTypeMovie,
+			Title:          "Movie",
+			Year:           2000 + i,
+			Status:         StatusWanted,
+			QualityProfile: profile,
+			RootPath:       "/movies",
+		}))
+	}
+
+	counts, err := store.QualityProfileCounts()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"hd": 2, "uhd": 1}, counts)
+}
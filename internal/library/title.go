@@ -0,0 +1,86 @@
+package library
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// normalizeTitle returns the form of a title used to detect duplicate content:
+// lowercased, apostrophes dropped, other punctuation treated as spaces and
+// whitespace collapsed. "Se7en " and "se7en" normalize to "se7en", and
+// "Spider-Man: No Way Home" to "spider man no way home".
+func normalizeTitle(title string) string {
+	s := strings.Map(func(r rune) rune {
+		switch {
+		case r == '\'' || r == '’':
+			return -1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		default:
+			return ' '
+		}
+	}, title)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// DuplicateContent is a content row whose normalized title, type and year
+// match an earlier row.
+type DuplicateContent struct {
+	ID          int64
+	DuplicateOf int64
+	Type        ContentType
+	Title       string
+	Year        int
+}
+
+// BackfillNormalizedTitles sets normalized_title on rows that lack it, oldest first.
+// A row that would duplicate an earlier row is left NULL and returned rather than
+// failing, so databases created before the unique index keep working until the
+// duplicates are merged or deleted.
+func (s *Store) BackfillNormalizedTitles() ([]DuplicateContent, error) {
+	type pending struct {
+		id          int64
+		contentType ContentType
+		title       string
+		year        int
+	}
+
+	rows, err := s.db.Query("SELECT id, type, title, year FROM content WHERE normalized_title IS NULL ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("list content to backfill: %w", err)
+	}
+	var todo []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.contentType, &p.title, &p.year); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan content: %w", err)
+		}
+		todo = append(todo, p)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate content: %w", err)
+	}
+
+	var dups []DuplicateContent
+	for _, p := range todo {
+		normalized := normalizeTitle(p.title)
+		_, err := s.db.Exec("UPDATE content SET normalized_title = ? WHERE id = ?", normalized, p.id)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(mapSQLiteError(err), ErrDuplicate) {
+			return dups, fmt.Errorf("backfill content %d: %w", p.id, err)
+		}
+		var firstID int64
+		if err := s.db.QueryRow("SELECT id FROM content WHERE type = ? AND normalized_title = ? AND year = ?",
+			p.contentType, normalized, p.year).Scan(&firstID); err != nil {
+			return dups, fmt.Errorf("find duplicate of content %d: %w", p.id, err)
+		}
+		dups = append(dups, DuplicateContent{ID: p.id, DuplicateOf: firstID, Type: p.contentType, Title: p.title, Year: p.year})
+	}
+	return dups, nil
+}
@@ -0,0 +1,100 @@
+package library
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Se7en", "se7en"},
+		{"Se7en ", "se7en"},
+		{"  THE   Matrix ", "the matrix"},
+		{"Spider-Man: No Way Home", "spider man no way home"},
+		{"Ocean's Eleven", "oceans eleven"},
+		{"Ocean’s Eleven", "oceans eleven"},
+		{"WALL·E", "wall e"},
+		{"Amélie", "amélie"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeTitle(tt.title))
+		})
+	}
+}
+
+func TestStore_AddContent_DuplicateNormalizedTitle(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	require.NoError(t, store.AddContent(&Content{Type: ContentTypeMovie, Title: "Se7en", Year: 1995, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}))
+
+	err := store.AddContent(&Content{Type: ContentTypeMovie, Title: "se7en ", Year: 1995, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"})
+	require.ErrorIs(t, err, ErrDuplicate)
+
+	// Same title with a different year or type is distinct content
+	require.NoError(t, store.AddContent(&Content{Type: ContentTypeMovie, Title: "Se7en", Year: 2025, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}))
+	require.NoError(t, store.AddContent(&Content{Type: ContentTypeSeries, Title: "Se7en", Year: 1995, Status: StatusWanted, QualityProfile: "hd", RootPath: "/tv"}))
+}
+
+func TestStore_UpdateContent_RetitleToDuplicate(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	require.NoError(t, store.AddContent(&Content{Type: ContentTypeMovie, Title: "Alien", Year: 1979, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}))
+	other := &Content{Type: ContentTypeMovie, Title: "Aliens", Year: 1979, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(other))
+
+	other.Title = "ALIEN."
+	require.ErrorIs(t, store.UpdateContent(other), ErrDuplicate)
+}
+
+func TestStore_GetByTitleYear_Normalized(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+
+	movie := &Content{Type: ContentTypeMovie, Title: "Spider-Man: No Way Home", Year: 2021, Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(movie))
+
+	found, err := store.GetByTitleYear("spider man  no way home", 2021)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, movie.ID, found.ID)
+	assert.Equal(t, "Spider-Man: No Way Home", found.Title, "stored title is unchanged")
+}
+
+func TestStore_BackfillNormalizedTitles(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	// Rows as they exist before migration 015: no normalized title
+	for _, title := range []string{"Se7en", "The Matrix", "se7en "} {
+		_, err := db.Exec(`INSERT INTO content (type, title, year, status, quality_profile, root_path) VALUES ('movie', ?, 1995, 'wanted', 'hd', '/movies')`, title)
+		require.NoError(t, err)
+	}
+
+	dups, err := store.BackfillNormalizedTitles()
+	require.NoError(t, err)
+	require.Len(t, dups, 1)
+	assert.Equal(t, DuplicateContent{ID: 3, DuplicateOf: 1, Type: ContentTypeMovie, Title: "se7en ", Year: 1995}, dups[0])
+
+	var normalized *string
+	require.NoError(t, db.QueryRow("SELECT normalized_title FROM content WHERE id = 2").Scan(&normalized))
+	require.NotNil(t, normalized)
+	assert.Equal(t, "the matrix", *normalized)
+
+	// The reported duplicate stays updatable until it is retitled
+	dup, err := store.GetContent(3)
+	require.NoError(t, err)
+	dup.Status = StatusUnmonitored
+	require.NoError(t, store.UpdateContent(dup))
+
+	// Running again finds the same duplicate and nothing else
+	dups, err = store.BackfillNormalizedTitles()
+	require.NoError(t, err)
+	assert.Len(t, dups, 1)
+}
@@ -6,10 +6,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
 )
 
 func TestTx_Commit(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	tx, err := store.Begin()
@@ -27,7 +28,7 @@ func TestTx_Commit(t *testing.T) {
 }
 
 func TestTx_Rollback_Comprehensive(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	tx, err := store.Begin()
@@ -45,7 +46,7 @@ func TestTx_Rollback_Comprehensive(t *testing.T) {
 }
 
 func TestTx_MultipleOperations(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	tx, err := store.Begin()
@@ -70,7 +71,7 @@ func TestTx_MultipleOperations(t *testing.T) {
 }
 
 func TestTx_CascadeDelete(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series with episode and file
@@ -94,7 +95,7 @@ func TestTx_CascadeDelete(t *testing.T) {
 }
 
 func TestTx_CascadeDelete_InTransaction(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series with episode and file outside transaction
@@ -123,7 +124,7 @@ func TestTx_CascadeDelete_InTransaction(t *testing.T) {
 }
 
 func TestTx_CascadeDelete_Rollback(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series with episode and file
@@ -157,7 +158,7 @@ func TestTx_CascadeDelete_Rollback(t *testing.T) {
 }
 
 func TestTx_EpisodeDelete_CascadeFiles(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series with episode and file
@@ -185,7 +186,7 @@ func TestTx_EpisodeDelete_CascadeFiles(t *testing.T) {
 }
 
 func TestTx_MultipleEpisodesAndFiles(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create series with multiple episodes and files in a transaction
@@ -239,7 +240,7 @@ func TestTx_MultipleEpisodesAndFiles(t *testing.T) {
 }
 
 func TestTx_MovieWithFile_CascadeDelete(t *testing.T) {
-	db := setupTestDB(t)
+	db := testutil.OpenTestDB(t)
 	store := NewStore(db)
 
 	// Create movie with file
@@ -0,0 +1,266 @@
+package library
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WantedSort orders wanted items.
+type WantedSort string
+
+const (
+	WantedSortAddedAt WantedSort = "added_at" // Most recently added content first
+	WantedSortAirDate WantedSort = "air_date" // Most recently aired first; movies use added_at
+)
+
+// WantedFilter specifies criteria for listing wanted items.
+type WantedFilter struct {
+	Type             *ContentType
+	ContentID        *int64     // Only this content (ListMissing)
+	AiredSince       *time.Time // Only episodes aired at or after this time, no movies or audiobooks (ListMissing)
+	Sort             WantedSort // Default: added_at
+	Limit            int        // 0 = no limit
+	Offset           int
+	IncludeAbandoned bool // Also list abandoned content
+}
+
+// wantedStatuses returns the SQL list of content statuses that still want files.
+func (f WantedFilter) wantedStatuses() string {
+	if f.IncludeAbandoned {
+		return "('wanted', 'abandoned')"
+	}
+	return "('wanted')"
+}
+
+// excludedStatuses returns the SQL list of content statuses the filter leaves out.
+func (f WantedFilter) excludedStatuses() string {
+	if f.IncludeAbandoned {
+		return "('unmonitored')"
+	}
+	return "('unmonitored', 'abandoned')"
+}
+
+// WantedItem is a movie, episode, or audiobook the library still wants:
+// either missing a file or holding one below its quality cutoff.
+type WantedItem struct {
+	ContentID      int64
+	Type           ContentType
+	Status         ContentStatus
+	Title          string
+	Year           int
+	Author         string // Audiobooks only
+	QualityProfile string // episode override if set, otherwise the series profile
+	AddedAt        time.Time
+	// Episode fields (nil/zero for movies)
+	EpisodeID    *int64
+	Season       int
+	Episode      int
+	EpisodeTitle string
+	AirDate      *time.Time
+	// Best quality on disk; empty for missing items
+	Quality string
+}
+
+// QualityRank returns a numeric rank for a file or profile quality.
+// Higher is better: 2160p=4, 1080p=3, 720p=2, 480p=1, unknown=0.
+func QualityRank(quality string) int {
+	switch strings.ToLower(quality) {
+	case "2160p", "4k", "uhd":
+		return 4
+	case "1080p", "fhd":
+		return 3
+	case "720p", "hd":
+		return 2
+	case "480p", "sd":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Column lists shared by the wanted queries.
+// Movies and audiobooks select NULL/zero placeholders for the episode columns.
+const (
+	wantedMovieColumns   = `c.id, c.type, c.status, c.title, c.year, c.author, c.quality_profile, c.added_at, NULL AS episode_id, 0 AS season, 0 AS episode, '' AS episode_title, NULL AS air_date`
+	wantedEpisodeColumns = `c.id, c.type, c.status, c.title, c.year, c.author, COALESCE(e.quality_profile, c.quality_profile) AS quality_profile, c.added_at, e.id AS episode_id, e.season, e.episode, e.title AS episode_title, e.air_date`
+)
+
+// wantedOrder returns the ORDER BY clause for a wanted query over the shared columns.
+func wantedOrder(sort WantedSort) string {
+	if sort == WantedSortAirDate {
+		return " ORDER BY COALESCE(air_date, added_at) DESC, id, season, episode"
+	}
+	return " ORDER BY added_at DESC, id, season, episode"
+}
+
+// wantedUnion combines the movie and episode halves of a wanted query,
+// dropping the half excluded by the type filter. The movie half also
+// covers audiobooks, which are wanted as a whole like movies.
+func wantedUnion(f WantedFilter, movies, episodes string) string {
+	switch {
+	case f.Type != nil && *f.Type == ContentTypeSeries:
+		return episodes
+	case f.Type != nil:
+		return movies
+	default:
+		return movies + " UNION ALL " + episodes
+	}
+}
+
+// itemTypes returns the SQL list of content types the movie half of a
+// missing query lists.
+func (f WantedFilter) itemTypes() string {
+	switch {
+	case f.Type != nil && *f.Type == ContentTypeMovie:
+		return "('movie')"
+	case f.Type != nil && *f.Type == ContentTypeAudiobook:
+		return "('audiobook')"
+	}
+	return "('movie', 'audiobook')"
+}
+
+// ListMissing returns wanted movies and audiobooks and aired wanted episodes
+// that have no file on disk; files marked missing don't count.
+// Movies that have not reached their minimum availability are excluded (see Content.IsAvailable),
+// as are episodes of unmonitored series and episodes without an air date.
+// Abandoned content is excluded unless f.IncludeAbandoned is set.
+// Returns (results, totalCount, error).
+func (s *Store) ListMissing(f WantedFilter) ([]*WantedItem, int, error) {
+	movies := `SELECT ` + wantedMovieColumns + `
+		FROM content c
+		WHERE c.type IN ` + f.itemTypes() + ` AND c.status IN ` + f.wantedStatuses() + `
+			AND (c.release_date IS NULL OR c.minimum_availability NOT IN ('inCinemas', 'released')
+				OR (c.minimum_availability = 'inCinemas' AND c.release_date <= ?)
+				OR (c.minimum_availability = 'released' AND c.release_date <= ?))
+			AND NOT EXISTS (SELECT 1 FROM files f WHERE f.content_id = c.id AND f.missing_at IS NULL AND f.kind = 'media')`
+	episodes := `SELECT ` + wantedEpisodeColumns + `
+		FROM episodes e
+		JOIN content c ON c.id = e.content_id
+		WHERE c.type = 'series' AND c.status NOT IN ` + f.excludedStatuses() + `
+			AND e.status = 'wanted'
+			AND e.air_date IS NOT NULL AND e.air_date <= ?
+			AND NOT EXISTS (SELECT 1 FROM files f WHERE f.episode_id = e.id AND f.missing_at IS NULL AND f.kind = 'media')`
+
+	if f.AiredSince != nil {
+		series := ContentTypeSeries
+		f.Type = &series
+		episodes += " AND e.air_date >= ?"
+	}
+	now := time.Now().UTC()
+	var args []any
+	if f.Type == nil || *f.Type != ContentTypeSeries {
+		args = append(args, now, now.Add(-releasedDelay))
+	}
+	if f.Type == nil || *f.Type == ContentTypeSeries {
+		args = append(args, now)
+	}
+	if f.AiredSince != nil {
+		args = append(args, f.AiredSince.UTC())
+	}
+	union := "(" + wantedUnion(f, movies, episodes) + ")"
+	if f.ContentID != nil {
+		union += " WHERE id = ?"
+		args = append(args, *f.ContentID)
+	}
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM "+union, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count missing: %w", err)
+	}
+
+	query := "SELECT * FROM " + union + wantedOrder(f.Sort)
+	if f.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", f.Limit, f.Offset)
+	}
+
+	items, err := s.queryWanted(query, false, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list missing: %w", err)
+	}
+	return items, total, nil
+}
+
+// ListUpcoming returns up to limit episodes of monitored series airing today
+// or later, soonest first. Unmonitored episodes are excluded.
+func (s *Store) ListUpcoming(limit int) ([]*WantedItem, error) {
+	query := `SELECT ` + wantedEpisodeColumns + `
+		FROM episodes e
+		JOIN content c ON c.id = e.content_id
+		WHERE c.type = 'series' AND c.status NOT IN ` + WantedFilter{}.excludedStatuses() + `
+			AND e.status != 'unmonitored'
+			AND e.air_date >= ?
+		ORDER BY e.air_date, c.id, e.season, e.episode`
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	items, err := s.queryWanted(query, false, today)
+	if err != nil {
+		return nil, fmt.Errorf("list upcoming: %w", err)
+	}
+	return items, nil
+}
+
+// ListWithFiles returns movies and episodes that have at least one file,
+// with Quality set to the best quality on disk. Unmonitored content is excluded,
+// as is abandoned content unless f.IncludeAbandoned is set. Audiobooks have
+// no resolution to upgrade and are never listed.
+// Results are not paginated: callers compare Quality against the quality
+// profile to find items below cutoff, then paginate.
+func (s *Store) ListWithFiles(f WantedFilter) ([]*WantedItem, error) {
+	movies := `SELECT ` + wantedMovieColumns + `, GROUP_CONCAT(COALESCE(f.quality, ''), ',') AS qualities
+		FROM content c
+		JOIN files f ON f.content_id = c.id AND f.missing_at IS NULL AND f.kind = 'media'
+		WHERE c.type = 'movie' AND c.status NOT IN ` + f.excludedStatuses() + `
+		GROUP BY c.id`
+	episodes := `SELECT ` + wantedEpisodeColumns + `, GROUP_CONCAT(COALESCE(f.quality, ''), ',') AS qualities
+		FROM episodes e
+		JOIN content c ON c.id = e.content_id
+		JOIN files f ON f.episode_id = e.id AND f.missing_at IS NULL AND f.kind = 'media'
+		WHERE c.type = 'series' AND c.status NOT IN ` + f.excludedStatuses() + ` AND e.status != 'unmonitored'
+		GROUP BY e.id`
+
+	query := "SELECT * FROM (" + wantedUnion(f, movies, episodes) + ")" + wantedOrder(f.Sort)
+	items, err := s.queryWanted(query, true)
+	if err != nil {
+		return nil, fmt.Errorf("list content with files: %w", err)
+	}
+	return items, nil
+}
+
+// queryWanted runs a wanted query and scans the shared columns.
+// If withQualities is set, a trailing comma-separated qualities column is
+// reduced to the best quality.
+func (s *Store) queryWanted(query string, withQualities bool, args ...any) ([]*WantedItem, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []*WantedItem
+	for rows.Next() {
+		item := &WantedItem{}
+		dest := []any{&item.ContentID, &item.Type, &item.Status, &item.Title, &item.Year, &item.Author, &item.QualityProfile, &item.AddedAt,
+			&item.EpisodeID, &item.Season, &item.Episode, &item.EpisodeTitle, &item.AirDate}
+		var qualities string
+		if withQualities {
+			dest = append(dest, &qualities)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan wanted item: %w", err)
+		}
+		for _, q := range strings.Split(qualities, ",") {
+			if item.Quality == "" || QualityRank(q) > QualityRank(item.Quality) {
+				item.Quality = q
+			}
+		}
+		results = append(results, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate wanted items: %w", err)
+	}
+	return results, nil
+}
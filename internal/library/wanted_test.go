@@ -0,0 +1,254 @@
+package library
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+// setupWantedLibrary creates a movie without a file, a movie with a 720p file,
+// and a series with one aired missing episode, one aired episode with a file,
+// and one unaired episode.
+func setupWantedLibrary(t *testing.T) (*Store, map[string]int64) {
+	t.Helper()
+	store := NewStore(testutil.OpenTestDB(t))
+	ids := make(map[string]int64)
+
+	missingMovie := &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(1)), Title: "Missing Movie", Year: 2020,
+		Status: StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(missingMovie))
+	ids["missing_movie"] = missingMovie.ID
+
+	lowMovie := &Content{Type: ContentTypeMovie, TMDBID: ptr(int64(2)), Title: "Low Movie", Year: 2021,
+		Status: StatusAvailable, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, store.AddContent(lowMovie))
+	ids["low_movie"] = lowMovie.ID
+	require.NoError(t, store.AddFile(&File{ContentID: lowMovie.ID, Path: "/movies/low-720p.mkv", Quality: "720p"}))
+
+	series := &Content{Type: ContentTypeSeries, TVDBID: ptr(int64(3)), Title: "Show", Year: 2022,
+		Status: StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, store.AddContent(series))
+	ids["series"] = series.ID
+
+	aired := time.Now().UTC().AddDate(0, 0, -7)
+	future := time.Now().UTC().AddDate(0, 1, 0)
+	missingEp := &Episode{ContentID: series.ID, Season: 1, Episode: 1, Title: "Pilot", Status: StatusWanted, AirDate: &aired}
+	require.NoError(t, store.AddEpisode(missingEp))
+	ids["missing_episode"] = missingEp.ID
+
+	haveEp := &Episode{ContentID: series.ID, Season: 1, Episode: 2, Title: "Second", Status: StatusAvailable, AirDate: &aired}
+	require.NoError(t, store.AddEpisode(haveEp))
+	ids["have_episode"] = haveEp.ID
+	require.NoError(t, store.AddFile(&File{ContentID: series.ID, EpisodeID: &haveEp.ID, Path: "/tv/s01e02-480p.mkv", Quality: "480p"}))
+	require.NoError(t, store.AddFile(&File{ContentID: series.ID, EpisodeID: &haveEp.ID, Path: "/tv/s01e02-1080p.mkv", Quality: "1080p"}))
+
+	require.NoError(t, store.AddEpisode(&Episode{ContentID: series.ID, Season: 1, Episode: 3, Title: "Unaired", Status: StatusWanted, AirDate: &future}))
+
+	return store, ids
+}
+
+func TestStore_ListMissing(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+
+	items, total, err := store.ListMissing(WantedFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, items, 2)
+
+	byContent := make(map[int64]*WantedItem)
+	for _, item := range items {
+		byContent[item.ContentID] = item
+	}
+	require.Contains(t, byContent, ids["missing_movie"])
+	assert.Nil(t, byContent[ids["missing_movie"]].EpisodeID)
+
+	ep := byContent[ids["series"]]
+	require.NotNil(t, ep)
+	require.NotNil(t, ep.EpisodeID)
+	assert.Equal(t, ids["missing_episode"], *ep.EpisodeID)
+	assert.Equal(t, "Pilot", ep.EpisodeTitle)
+	assert.NotNil(t, ep.AirDate)
+}
+
+func TestStore_ListMissing_EpisodeProfileOverride(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+
+	ep, err := store.GetEpisode(ids["missing_episode"])
+	require.NoError(t, err)
+	ep.QualityProfile = "uhd"
+	require.NoError(t, store.UpdateEpisode(ep))
+
+	series := ContentTypeSeries
+	items, _, err := store.ListMissing(WantedFilter{Type: &series})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "uhd", items[0].QualityProfile, "episode override replaces the series profile")
+}
+
+func TestStore_ListMissing_AiredSince(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+
+	recent := time.Now().UTC().AddDate(0, 0, -1)
+	recentEp := &Episode{ContentID: ids["series"], Season: 1, Episode: 4, Title: "Recent", Status: StatusWanted, AirDate: &recent}
+	require.NoError(t, store.AddEpisode(recentEp))
+
+	since := time.Now().AddDate(0, 0, -3)
+	items, total, err := store.ListMissing(WantedFilter{AiredSince: &since})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total, "movies and episodes aired before the window are left out")
+	require.Len(t, items, 1)
+	assert.Equal(t, recentEp.ID, *items[0].EpisodeID)
+}
+
+func TestStore_ListMissing_Abandoned(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+
+	for _, key := range []string{"missing_movie", "series"} {
+		c, err := store.GetContent(ids[key])
+		require.NoError(t, err)
+		c.Status = StatusAbandoned
+		require.NoError(t, store.UpdateContent(c))
+	}
+
+	items, total, err := store.ListMissing(WantedFilter{})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, items)
+
+	withFiles, err := store.ListWithFiles(WantedFilter{})
+	require.NoError(t, err)
+	require.Len(t, withFiles, 1, "abandoned series drops out of cutoff checks")
+	assert.Equal(t, ids["low_movie"], withFiles[0].ContentID)
+
+	items, total, err = store.ListMissing(WantedFilter{IncludeAbandoned: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	for _, item := range items {
+		assert.Equal(t, StatusAbandoned, item.Status)
+	}
+}
+
+func TestStore_ListMissing_MinimumAvailability(t *testing.T) {
+	store := NewStore(testutil.OpenTestDB(t))
+	now := time.Now().UTC()
+	lastMonth := now.AddDate(0, -1, 0)
+	lastYear := now.AddDate(-1, 0, 0)
+	nextMonth := now.AddDate(0, 1, 0)
+
+	add := func(title string, availability MinimumAvailability, released *time.Time) int64 {
+		c := &Content{Type: ContentTypeMovie, Title: title, Year: 2024, Status: StatusWanted, QualityProfile: "hd",
+			RootPath: "/movies", MinimumAvailability: availability, ReleaseDate: released}
+		require.NoError(t, store.AddContent(c))
+		return c.ID
+	}
+	unknownDate := add("Unknown Date", AvailabilityReleased, nil)
+	announced := add("Announced", AvailabilityAnnounced, &nextMonth)
+	inCinemas := add("In Cinemas", AvailabilityInCinemas, &lastMonth)
+	add("Upcoming", AvailabilityInCinemas, &nextMonth)
+	add("Not Yet Released", AvailabilityReleased, &lastMonth)
+	released := add("Released", AvailabilityReleased, &lastYear)
+
+	movieType := ContentTypeMovie
+	items, total, err := store.ListMissing(WantedFilter{Type: &movieType})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	var got []int64
+	for _, item := range items {
+		got = append(got, item.ContentID)
+	}
+	assert.ElementsMatch(t, []int64{unknownDate, announced, inCinemas, released}, got)
+}
+
+func TestStore_ListMissing_TypeFilterAndPagination(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+
+	movieType := ContentTypeMovie
+	items, total, err := store.ListMissing(WantedFilter{Type: &movieType})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, items, 1)
+	assert.Equal(t, ids["missing_movie"], items[0].ContentID)
+
+	items, total, err = store.ListMissing(WantedFilter{Sort: WantedSortAirDate, Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, items, 1)
+}
+
+func TestStore_ListMissing_Audiobooks(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+	book := &Content{Type: ContentTypeAudiobook, Title: "Dune", Year: 1965, Author: "Frank Herbert",
+		Status: StatusWanted, QualityProfile: "audiobook", RootPath: "/audiobooks"}
+	require.NoError(t, store.AddContent(book))
+
+	_, total, err := store.ListMissing(WantedFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total, "audiobooks are wanted like movies")
+
+	bookType := ContentTypeAudiobook
+	items, total, err := store.ListMissing(WantedFilter{Type: &bookType})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, items, 1)
+	assert.Equal(t, book.ID, items[0].ContentID)
+	assert.Equal(t, "Frank Herbert", items[0].Author)
+
+	movieType := ContentTypeMovie
+	items, _, err = store.ListMissing(WantedFilter{Type: &movieType})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, ids["missing_movie"], items[0].ContentID, "movies exclude audiobooks")
+}
+
+func TestStore_ListWithFiles(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+
+	items, err := store.ListWithFiles(WantedFilter{})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	byContent := make(map[int64]*WantedItem)
+	for _, item := range items {
+		byContent[item.ContentID] = item
+	}
+	assert.Equal(t, "720p", byContent[ids["low_movie"]].Quality)
+
+	// Best of the episode's files is reported
+	ep := byContent[ids["series"]]
+	require.NotNil(t, ep)
+	require.NotNil(t, ep.EpisodeID)
+	assert.Equal(t, ids["have_episode"], *ep.EpisodeID)
+	assert.Equal(t, "1080p", ep.Quality)
+}
+
+func TestStore_ListUpcoming(t *testing.T) {
+	store, ids := setupWantedLibrary(t)
+	seriesID := ids["series"]
+
+	soon := time.Now().UTC().AddDate(0, 0, 2)
+	require.NoError(t, store.AddEpisode(&Episode{ContentID: seriesID, Season: 2, Episode: 1, Title: "Soon", Status: StatusWanted, AirDate: &soon}))
+	require.NoError(t, store.AddEpisode(&Episode{ContentID: seriesID, Season: 2, Episode: 2, Title: "Skipped", Status: StatusUnmonitored, AirDate: &soon}))
+
+	items, err := store.ListUpcoming(10)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "Soon", items[0].EpisodeTitle, "soonest first")
+	assert.Equal(t, "Unaired", items[1].EpisodeTitle)
+	assert.Equal(t, "Show", items[0].Title)
+
+	items, err = store.ListUpcoming(1)
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	// Unmonitored series have no upcoming episodes
+	series, err := store.GetContent(seriesID)
+	require.NoError(t, err)
+	series.Status = StatusUnmonitored
+	require.NoError(t, store.UpdateContent(series))
+	items, err = store.ListUpcoming(10)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
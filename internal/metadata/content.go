@@ -0,0 +1,159 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/tmdb"
+	"github.com/vmunix/arrgo/pkg/tvdb"
+)
+
+// tmdbPosterSize is the TMDB image size stored as the poster URL.
+const tmdbPosterSize = "w500"
+
+// MovieProvider fetches movie metadata by TMDB ID.
+// Satisfied by *tmdb.Client.
+type MovieProvider interface {
+	GetMovie(ctx context.Context, tmdbID int64) (*tmdb.Movie, error)
+}
+
+// SeriesProvider fetches extended series metadata by TVDB ID.
+// Satisfied by *TVDBService.
+type SeriesProvider interface {
+	GetSeriesExtended(ctx context.Context, tvdbID int) (*tvdb.Series, error)
+}
+
+// ContentRefresher fills in display metadata (overview, poster, runtime, genres)
+// and movie release dates and collections for library content from TMDB (movies) or TVDB (series).
+// Either provider may be nil; content whose provider is not configured,
+// or that has no external ID, is left unchanged.
+type ContentRefresher struct {
+	library *library.Store
+	movies  MovieProvider
+	series  SeriesProvider
+	log     *slog.Logger
+}
+
+// NewContentRefresher creates a refresher. Pass nil for unconfigured providers.
+func NewContentRefresher(lib *library.Store, movies MovieProvider, series SeriesProvider, log *slog.Logger) *ContentRefresher {
+	return &ContentRefresher{
+		library: lib,
+		movies:  movies,
+		series:  series,
+		log:     log,
+	}
+}
+
+// Apply fetches metadata for the content and sets it on the struct without saving.
+// Use it before AddContent to store metadata with new content.
+// Returns false if no provider applies to the content.
+func (r *ContentRefresher) Apply(ctx context.Context, c *library.Content) (bool, error) {
+	switch c.Type {
+	case library.ContentTypeMovie:
+		if r.movies == nil || c.TMDBID == nil {
+			return false, nil
+		}
+		movie, err := r.movies.GetMovie(ctx, *c.TMDBID)
+		if err != nil {
+			return false, fmt.Errorf("get movie %d: %w", *c.TMDBID, err)
+		}
+		c.Overview = movie.Overview
+		c.PosterURL = movie.PosterURL(tmdbPosterSize)
+		c.Runtime = movie.Runtime
+		c.Genres = make([]string, 0, len(movie.Genres))
+		for _, g := range movie.Genres {
+			c.Genres = append(c.Genres, g.Name)
+		}
+		c.ReleaseDate = nil
+		if released, err := time.Parse(time.DateOnly, movie.ReleaseDate); err == nil {
+			c.ReleaseDate = &released
+		}
+		c.CollectionID, c.CollectionName = nil, ""
+		if movie.Collection != nil {
+			id := movie.Collection.ID
+			c.CollectionID, c.CollectionName = &id, movie.Collection.Name
+		}
+	case library.ContentTypeSeries:
+		if r.series == nil || c.TVDBID == nil {
+			return false, nil
+		}
+		series, err := r.series.GetSeriesExtended(ctx, int(*c.TVDBID))
+		if err != nil {
+			return false, fmt.Errorf("get series %d: %w", *c.TVDBID, err)
+		}
+		c.Overview = series.Overview
+		c.PosterURL = series.Image
+		c.Runtime = series.Runtime
+		c.Genres = series.Genres
+	default:
+		return false, nil
+	}
+
+	now := time.Now()
+	c.MetadataUpdatedAt = &now
+	return true, nil
+}
+
+// Refresh fetches metadata for existing content and saves it.
+// Returns false if no provider applies to the content.
+func (r *ContentRefresher) Refresh(ctx context.Context, c *library.Content) (bool, error) {
+	ok, err := r.Apply(ctx, c)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := r.library.UpdateContentMetadata(c); err != nil {
+		return false, fmt.Errorf("save metadata: %w", err)
+	}
+	return true, nil
+}
+
+// RefreshStale refreshes content whose metadata was never fetched or is older than maxAge.
+// Failures for individual items are logged and skipped.
+// Returns the number of items refreshed.
+func (r *ContentRefresher) RefreshStale(ctx context.Context, maxAge time.Duration) (int, error) {
+	cutoff := time.Now().Add(-maxAge)
+	contents, _, err := r.library.ListContent(library.ContentFilter{MetadataBefore: &cutoff})
+	if err != nil {
+		return 0, fmt.Errorf("list stale content: %w", err)
+	}
+
+	refreshed := 0
+	for _, c := range contents {
+		if ctx.Err() != nil {
+			return refreshed, ctx.Err()
+		}
+		ok, err := r.Refresh(ctx, c)
+		if err != nil {
+			r.log.Warn("metadata refresh failed", "content_id", c.ID, "title", c.Title, "error", err)
+			continue
+		}
+		if ok {
+			refreshed++
+		}
+	}
+	return refreshed, nil
+}
+
+// Run refreshes stale metadata every interval until the context is canceled.
+func (r *ContentRefresher) Run(ctx context.Context, interval, maxAge time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.RefreshStale(ctx, maxAge)
+		if err != nil && ctx.Err() == nil {
+			r.log.Warn("metadata refresh job failed", "error", err)
+		} else if n > 0 {
+			r.log.Info("metadata refreshed", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/testutil"
+	"github.com/vmunix/arrgo/internal/tmdb"
+	"github.com/vmunix/arrgo/pkg/tvdb"
+)
+
+func setupTestLibrary(t *testing.T) *library.Store {
+	t.Helper()
+	return library.NewStore(testutil.OpenTestDB(t))
+}
+
+type fakeMovies struct {
+	movie *tmdb.Movie
+	err   error
+	calls int
+}
+
+func (f *fakeMovies) GetMovie(_ context.Context, _ int64) (*tmdb.Movie, error) {
+	f.calls++
+	return f.movie, f.err
+}
+
+type fakeSeries struct {
+	series *tvdb.Series
+	err    error
+}
+
+func (f *fakeSeries) GetSeriesExtended(_ context.Context, _ int) (*tvdb.Series, error) {
+	return f.series, f.err
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestContentRefresher_Apply_Movie(t *testing.T) {
+	movies := &fakeMovies{movie: &tmdb.Movie{
+		Overview:    "A thief who steals secrets.",
+		PosterPath:  "/inception.jpg",
+		Runtime:     148,
+		Genres:      []tmdb.Genre{{ID: 28, Name: "Action"}, {ID: 878, Name: "Science Fiction"}},
+		ReleaseDate: "2010-07-15",
+	}}
+	r := NewContentRefresher(nil, movies, nil, discardLogger())
+
+	c := &library.Content{Type: library.ContentTypeMovie, TMDBID: int64Ptr(27205), Title: "Inception"}
+	ok, err := r.Apply(context.Background(), c)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "A thief who steals secrets.", c.Overview)
+	assert.Equal(t, "https://image.tmdb.org/t/p/w500/inception.jpg", c.PosterURL)
+	assert.Equal(t, 148, c.Runtime)
+	assert.Equal(t, []string{"Action", "Science Fiction"}, c.Genres)
+	require.NotNil(t, c.ReleaseDate)
+	assert.Equal(t, time.Date(2010, 7, 15, 0, 0, 0, 0, time.UTC), *c.ReleaseDate)
+	assert.NotNil(t, c.MetadataUpdatedAt)
+}
+
+func TestContentRefresher_Refresh_Collection(t *testing.T) {
+	lib := setupTestLibrary(t)
+	movies := &fakeMovies{movie: &tmdb.Movie{
+		Title:      "The Matrix",
+		Collection: &tmdb.CollectionRef{ID: 2344, Name: "The Matrix Collection"},
+	}}
+	r := NewContentRefresher(lib, movies, nil, discardLogger())
+
+	c := &library.Content{Type: library.ContentTypeMovie, TMDBID: int64Ptr(603), Title: "The Matrix", Year: 1999,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, lib.AddContent(c))
+	ok, err := r.Refresh(context.Background(), c)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	got, err := lib.GetContent(c.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.CollectionID)
+	assert.Equal(t, int64(2344), *got.CollectionID)
+	assert.Equal(t, "The Matrix Collection", got.CollectionName)
+
+	// A movie removed from its collection loses it on the next refresh
+	movies.movie.Collection = nil
+	_, err = r.Refresh(context.Background(), got)
+	require.NoError(t, err)
+	got, err = lib.GetContent(c.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.CollectionID)
+	assert.Empty(t, got.CollectionName)
+}
+
+func TestContentRefresher_Apply_Series(t *testing.T) {
+	series := &fakeSeries{series: &tvdb.Series{
+		Overview: "A chemistry teacher turns to crime.",
+		Image:    "https://artworks.thetvdb.com/bb.jpg",
+		Runtime:  47,
+		Genres:   []string{"Drama", "Crime"},
+	}}
+	r := NewContentRefresher(nil, nil, series, discardLogger())
+
+	c := &library.Content{Type: library.ContentTypeSeries, TVDBID: int64Ptr(81189), Title: "Breaking Bad"}
+	ok, err := r.Apply(context.Background(), c)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "A chemistry teacher turns to crime.", c.Overview)
+	assert.Equal(t, "https://artworks.thetvdb.com/bb.jpg", c.PosterURL)
+	assert.Equal(t, 47, c.Runtime)
+	assert.Equal(t, []string{"Drama", "Crime"}, c.Genres)
+}
+
+func TestContentRefresher_Apply_NoProvider(t *testing.T) {
+	r := NewContentRefresher(nil, nil, nil, discardLogger())
+
+	c := &library.Content{Type: library.ContentTypeMovie, TMDBID: int64Ptr(27205)}
+	ok, err := r.Apply(context.Background(), c)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, c.Overview)
+	assert.Nil(t, c.MetadataUpdatedAt)
+}
+
+func TestContentRefresher_Apply_ProviderError(t *testing.T) {
+	movies := &fakeMovies{err: errors.New("tmdb down")}
+	r := NewContentRefresher(nil, movies, nil, discardLogger())
+
+	c := &library.Content{Type: library.ContentTypeMovie, TMDBID: int64Ptr(27205)}
+	ok, err := r.Apply(context.Background(), c)
+	require.Error(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, c.MetadataUpdatedAt)
+}
+
+func TestContentRefresher_RefreshStale(t *testing.T) {
+	lib := setupTestLibrary(t)
+	movies := &fakeMovies{movie: &tmdb.Movie{Overview: "Fresh overview", Runtime: 120}}
+	r := NewContentRefresher(lib, movies, nil, discardLogger())
+
+	stale := &library.Content{Type: library.ContentTypeMovie, TMDBID: int64Ptr(1), Title: "Stale", Year: 2020,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies"}
+	require.NoError(t, lib.AddContent(stale))
+
+	recent := time.Now()
+	fresh := &library.Content{Type: library.ContentTypeMovie, TMDBID: int64Ptr(2), Title: "Fresh", Year: 2021,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/movies", MetadataUpdatedAt: &recent}
+	require.NoError(t, lib.AddContent(fresh))
+
+	n, err := r.RefreshStale(context.Background(), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1, movies.calls)
+
+	got, err := lib.GetContent(stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Fresh overview", got.Overview)
+	assert.Equal(t, 120, got.Runtime)
+	assert.NotNil(t, got.MetadataUpdatedAt)
+
+	// Refreshed content is no longer stale
+	n, err = r.RefreshStale(context.Background(), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
@@ -0,0 +1,201 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/tvdb"
+)
+
+// EpisodeProvider fetches series status, aliases, and episode lists by TVDB ID.
+// Satisfied by *TVDBService.
+type EpisodeProvider interface {
+	GetSeries(ctx context.Context, tvdbID int) (*tvdb.Series, error)
+	GetEpisodes(ctx context.Context, tvdbID int) ([]tvdb.Episode, error)
+	// InvalidateSeries drops cached data so a sync sees the current episode list.
+	InvalidateSeries(ctx context.Context, tvdbID int) error
+}
+
+// EpisodeSyncResult reports what an episode sync changed.
+type EpisodeSyncResult struct {
+	Total      int   // Episodes listed by TVDB (specials excluded)
+	Inserted   int   // New episodes added as wanted
+	Updated    int   // Existing episodes whose title or air date changed
+	Aliases    int   // TVDB aliases added
+	NewSeasons []int // Seasons the library did not have before, ascending
+}
+
+// EpisodeSyncer keeps series episodes in step with TVDB: new episodes are added
+// as wanted, and changed titles and air dates are updated on existing ones.
+type EpisodeSyncer struct {
+	library  *library.Store
+	provider EpisodeProvider
+	bus      *events.Bus // Optional: publishes EpisodesSynced
+	log      *slog.Logger
+}
+
+// NewEpisodeSyncer creates a syncer. bus may be nil.
+func NewEpisodeSyncer(lib *library.Store, provider EpisodeProvider, bus *events.Bus, log *slog.Logger) *EpisodeSyncer {
+	return &EpisodeSyncer{
+		library:  lib,
+		provider: provider,
+		bus:      bus,
+		log:      log,
+	}
+}
+
+// Sync re-fetches a series' episodes from TVDB and saves the changes, along with
+// the series status and aliases. Publishes EpisodesSynced if any episode changed.
+func (s *EpisodeSyncer) Sync(ctx context.Context, c *library.Content) (*EpisodeSyncResult, error) {
+	if c.Type != library.ContentTypeSeries || c.TVDBID == nil {
+		return nil, fmt.Errorf("content %d is not a series with a TVDB ID", c.ID)
+	}
+	tvdbID := int(*c.TVDBID)
+
+	if err := s.provider.InvalidateSeries(ctx, tvdbID); err != nil {
+		s.log.Warn("invalidate series cache failed", "tvdb_id", tvdbID, "error", err)
+	}
+	episodes, err := s.provider.GetEpisodes(ctx, tvdbID)
+	if err != nil {
+		return nil, fmt.Errorf("get episodes: %w", err)
+	}
+
+	existing, _, err := s.library.ListEpisodes(library.EpisodeFilter{ContentID: &c.ID})
+	if err != nil {
+		return nil, fmt.Errorf("list episodes: %w", err)
+	}
+	known := make(map[int]bool)
+	for _, ep := range existing {
+		known[ep.Season] = true
+	}
+
+	result := &EpisodeSyncResult{}
+	newSeasons := make(map[int]bool)
+	libEpisodes := make([]*library.Episode, 0, len(episodes))
+	for _, ep := range episodes {
+		// Skip specials (season 0) and episodes without numbers
+		if ep.Season == 0 || ep.Episode == 0 {
+			continue
+		}
+		var airDate *time.Time
+		if !ep.AirDate.IsZero() {
+			airDate = &ep.AirDate
+		}
+		libEpisodes = append(libEpisodes, &library.Episode{
+			ContentID: c.ID,
+			Season:    ep.Season,
+			Episode:   ep.Episode,
+			Title:     ep.Name,
+			Status:    library.StatusWanted,
+			AirDate:   airDate,
+		})
+		if !known[ep.Season] {
+			newSeasons[ep.Season] = true
+		}
+	}
+	result.Total = len(libEpisodes)
+
+	result.Inserted, result.Updated, err = s.library.SyncEpisodes(libEpisodes)
+	if err != nil {
+		return nil, fmt.Errorf("save episodes: %w", err)
+	}
+	// Skip episodes in seasons that weren't requested
+	if _, err := s.library.ApplySeasonMonitoring(c.ID); err != nil {
+		return nil, fmt.Errorf("apply season monitoring: %w", err)
+	}
+	// A series with no episodes before this sync has no "new" seasons
+	if len(existing) > 0 {
+		for season := range newSeasons {
+			result.NewSeasons = append(result.NewSeasons, season)
+		}
+		sort.Ints(result.NewSeasons)
+	}
+
+	// Status and aliases are best effort: the episodes are already synced
+	if series, err := s.provider.GetSeries(ctx, tvdbID); err != nil {
+		s.log.Warn("get series failed", "tvdb_id", tvdbID, "error", err)
+	} else {
+		if err := s.library.UpdateSeriesStatus(c.ID, series.Status); err != nil {
+			s.log.Warn("save series status failed", "content_id", c.ID, "error", err)
+		}
+		if n, err := s.library.ReplaceAliases(c.ID, library.AliasSourceTVDB, series.Aliases); err != nil {
+			s.log.Warn("save aliases failed", "content_id", c.ID, "error", err)
+		} else {
+			result.Aliases = n
+		}
+	}
+
+	if s.bus != nil && result.Inserted+result.Updated > 0 {
+		_ = s.bus.Publish(ctx, &events.EpisodesSynced{
+			BaseEvent:  events.NewBaseEvent(events.EventEpisodesSynced, events.EntityContent, c.ID),
+			ContentID:  c.ID,
+			Title:      c.Title,
+			Total:      result.Total,
+			Inserted:   result.Inserted,
+			Updated:    result.Updated,
+			NewSeasons: result.NewSeasons,
+		})
+	}
+	return result, nil
+}
+
+// SyncContinuing syncs every continuing (or never synced) monitored series.
+// Failures for individual series are logged and skipped.
+// Returns the number of series whose episodes changed.
+func (s *EpisodeSyncer) SyncContinuing(ctx context.Context) (int, error) {
+	series, err := s.library.ListContinuingSeries()
+	if err != nil {
+		return 0, fmt.Errorf("list continuing series: %w", err)
+	}
+
+	changed := 0
+	for _, c := range series {
+		if ctx.Err() != nil {
+			return changed, ctx.Err()
+		}
+		result, err := s.Sync(ctx, c)
+		if err != nil {
+			s.log.Warn("episode sync failed", "content_id", c.ID, "title", c.Title, "error", err)
+			continue
+		}
+		if result.Inserted+result.Updated > 0 {
+			s.log.Info("episodes synced", "content_id", c.ID, "title", c.Title,
+				"inserted", result.Inserted, "updated", result.Updated, "new_seasons", result.NewSeasons)
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// Run syncs continuing series every interval until the context is canceled.
+// Each sync is delayed by a random amount up to jitter (the first one by jitter
+// alone) so restarts and long-running instances don't hit TVDB in lockstep.
+func (s *EpisodeSyncer) Run(ctx context.Context, interval, jitter time.Duration) error {
+	var wait time.Duration
+	for {
+		if jitter > 0 {
+			wait += rand.N(jitter)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		n, err := s.SyncContinuing(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.log.Warn("episode sync job failed", "error", err)
+		} else if n > 0 {
+			s.log.Info("episode sync finished", "changed_series", n)
+		}
+		wait = interval
+	}
+}
@@ -0,0 +1,122 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/tvdb"
+)
+
+type fakeEpisodes struct {
+	series      *tvdb.Series
+	episodes    []tvdb.Episode
+	err         error
+	invalidated int
+}
+
+func (f *fakeEpisodes) GetSeries(_ context.Context, _ int) (*tvdb.Series, error) {
+	return f.series, nil
+}
+
+func (f *fakeEpisodes) GetEpisodes(_ context.Context, _ int) ([]tvdb.Episode, error) {
+	return f.episodes, f.err
+}
+
+func (f *fakeEpisodes) InvalidateSeries(_ context.Context, _ int) error {
+	f.invalidated++
+	return nil
+}
+
+func addTestSeries(t *testing.T, lib *library.Store, tvdbID int64) *library.Content {
+	t.Helper()
+	c := &library.Content{Type: library.ContentTypeSeries, TVDBID: int64Ptr(tvdbID), Title: "Sync Show", Year: 2024,
+		Status: library.StatusWanted, QualityProfile: "hd", RootPath: "/tv"}
+	require.NoError(t, lib.AddContent(c))
+	return c
+}
+
+func TestEpisodeSyncer_Sync_NewSeason(t *testing.T) {
+	lib := setupTestLibrary(t)
+	c := addTestSeries(t, lib, 100)
+	require.NoError(t, lib.AddEpisode(&library.Episode{ContentID: c.ID, Season: 1, Episode: 1, Title: "TBA",
+		Status: library.StatusAvailable}))
+
+	aired := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	provider := &fakeEpisodes{
+		series: &tvdb.Series{Status: "Continuing", Aliases: []string{"Sync Show US"}},
+		episodes: []tvdb.Episode{
+			{Season: 0, Episode: 1, Name: "Special"},
+			{Season: 1, Episode: 1, Name: "Pilot"},
+			{Season: 2, Episode: 1, Name: "Return", AirDate: aired},
+			{Season: 2, Episode: 2, Name: "Fallout"},
+		},
+	}
+	bus := events.NewBus(nil, nil)
+	defer func() { _ = bus.Close() }()
+	synced := bus.Subscribe(events.EventEpisodesSynced, 1)
+
+	s := NewEpisodeSyncer(lib, provider, bus, discardLogger())
+	result, err := s.Sync(context.Background(), c)
+	require.NoError(t, err)
+	assert.Equal(t, 1, provider.invalidated)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 2, result.Inserted)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Aliases)
+	assert.Equal(t, []int{2}, result.NewSeasons)
+
+	got, err := lib.GetContent(c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, library.SeriesStatusContinuing, got.SeriesStatus)
+
+	select {
+	case e := <-synced:
+		evt, ok := e.(*events.EpisodesSynced)
+		require.True(t, ok)
+		assert.Equal(t, c.ID, evt.ContentID)
+		assert.Equal(t, 2, evt.Inserted)
+		assert.Equal(t, []int{2}, evt.NewSeasons)
+	case <-time.After(time.Second):
+		t.Fatal("EpisodesSynced not published")
+	}
+}
+
+func TestEpisodeSyncer_SyncContinuing(t *testing.T) {
+	lib := setupTestLibrary(t)
+	c := addTestSeries(t, lib, 100)
+	provider := &fakeEpisodes{
+		series:   &tvdb.Series{Status: "Ended"},
+		episodes: []tvdb.Episode{{Season: 1, Episode: 1, Name: "Pilot"}},
+	}
+	s := NewEpisodeSyncer(lib, provider, nil, discardLogger())
+
+	n, err := s.SyncContinuing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// Ended series are no longer synced
+	n, err = s.SyncContinuing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, 1, provider.invalidated)
+
+	episodes, _, err := lib.ListEpisodes(library.EpisodeFilter{ContentID: &c.ID})
+	require.NoError(t, err)
+	assert.Len(t, episodes, 1)
+}
+
+func TestEpisodeSyncer_Sync_ProviderError(t *testing.T) {
+	lib := setupTestLibrary(t)
+	c := addTestSeries(t, lib, 100)
+	s := NewEpisodeSyncer(lib, &fakeEpisodes{err: errors.New("tvdb down")}, nil, discardLogger())
+
+	_, err := s.Sync(context.Background(), c)
+	require.Error(t, err)
+}
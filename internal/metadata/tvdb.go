@@ -21,6 +21,7 @@ const (
 const (
 	keyPrefixSearch   = "tvdb:search:"
 	keyPrefixSeries   = "tvdb:series:"
+	keyPrefixExtended = "tvdb:series-extended:"
 	keyPrefixEpisodes = "tvdb:episodes:"
 )
 
@@ -136,6 +137,47 @@ func (s *TVDBService) GetSeries(ctx context.Context, tvdbID int) (*tvdb.Series,
 	return series, nil
 }
 
+// GetSeriesExtended fetches series metadata including poster, runtime,
+// and genres by TVDB ID (cached).
+func (s *TVDBService) GetSeriesExtended(ctx context.Context, tvdbID int) (*tvdb.Series, error) {
+	key := fmt.Sprintf("%s%d", keyPrefixExtended, tvdbID)
+
+	// Check cache first
+	if data, ok := s.cache.Get(ctx, key); ok {
+		var series tvdb.Series
+		if err := json.Unmarshal(data, &series); err == nil {
+			if s.log != nil {
+				s.log.Debug("cache hit for series extended", "tvdb_id", tvdbID, "name", series.Name)
+			}
+			return &series, nil
+		}
+		if s.log != nil {
+			s.log.Warn("failed to unmarshal cached series extended", "tvdb_id", tvdbID)
+		}
+	}
+
+	series, err := s.client.GetSeriesExtended(ctx, tvdbID)
+	if err != nil {
+		return nil, fmt.Errorf("get series extended: %w", err)
+	}
+
+	data, err := json.Marshal(series)
+	if err != nil {
+		if s.log != nil {
+			s.log.Warn("failed to marshal series extended for cache", "tvdb_id", tvdbID, "error", err)
+		}
+		return series, nil
+	}
+
+	if err := s.cache.Set(ctx, key, data, seriesTTL); err != nil {
+		if s.log != nil {
+			s.log.Warn("failed to cache series extended", "tvdb_id", tvdbID, "error", err)
+		}
+	}
+
+	return series, nil
+}
+
 // GetEpisodes fetches all episodes for a series (cached).
 func (s *TVDBService) GetEpisodes(ctx context.Context, tvdbID int) ([]tvdb.Episode, error) {
 	key := fmt.Sprintf("%s%d", keyPrefixEpisodes, tvdbID)
@@ -188,6 +230,7 @@ func (s *TVDBService) GetEpisodes(ctx context.Context, tvdbID int) ([]tvdb.Episo
 // This clears the series metadata and episodes cache entries.
 func (s *TVDBService) InvalidateSeries(ctx context.Context, tvdbID int) error {
 	seriesKey := fmt.Sprintf("%s%d", keyPrefixSeries, tvdbID)
+	extendedKey := fmt.Sprintf("%s%d", keyPrefixExtended, tvdbID)
 	episodesKey := fmt.Sprintf("%s%d", keyPrefixEpisodes, tvdbID)
 
 	var errs []error
@@ -196,6 +239,10 @@ func (s *TVDBService) InvalidateSeries(ctx context.Context, tvdbID int) error {
 		errs = append(errs, fmt.Errorf("delete series cache: %w", err))
 	}
 
+	if err := s.cache.Delete(ctx, extendedKey); err != nil {
+		errs = append(errs, fmt.Errorf("delete series extended cache: %w", err))
+	}
+
 	if err := s.cache.Delete(ctx, episodesKey); err != nil {
 		errs = append(errs, fmt.Errorf("delete episodes cache: %w", err))
 	}
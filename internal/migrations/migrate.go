@@ -0,0 +1,152 @@
+// Package migrations applies the embedded SQL schema migrations (see package
+// schema) and tracks which have applied to a database.
+package migrations
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/migrations/schema"
+)
+
+// ErrDatabaseTooNew is returned when the database has migrations this binary
+// does not know about, i.e. it was last opened by a newer arrgo.
+var ErrDatabaseTooNew = errors.New("database schema is newer than this binary")
+
+// Migration is one embedded schema change; see package schema.
+type Migration = schema.Migration
+
+// afterMigration holds data fixes that run once a migration's SQL has applied.
+var afterMigration = map[int]func(db *sql.DB, logger *slog.Logger) error{
+	15: backfillNormalizedTitles,
+	33: backfillReleaseGroups,
+}
+
+// All returns every embedded migration in version order.
+func All() ([]Migration, error) { return schema.All() }
+
+// Latest returns the highest embedded migration version.
+func Latest() int {
+	all, err := All()
+	if err != nil || len(all) == 0 {
+		return 0
+	}
+	return all[len(all)-1].Version
+}
+
+// CurrentVersion returns the database's schema version, or 0 for a database
+// that has never been migrated.
+func CurrentVersion(db *sql.DB) (int, error) {
+	var exists int
+	if err := db.QueryRow(
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'",
+	).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("check schema_migrations: %w", err)
+	}
+	if exists == 0 {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, fmt.Errorf("get schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Pending returns the migrations not yet applied to db, in order. It returns
+// ErrDatabaseTooNew if the database is ahead of the embedded migrations.
+func Pending(db *sql.DB) ([]Migration, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+	current, err := CurrentVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	if latest := Latest(); current > latest {
+		return nil, fmt.Errorf("%w: database is at version %d, this binary knows up to %d", ErrDatabaseTooNew, current, latest)
+	}
+
+	var pending []Migration
+	for _, m := range all {
+		if m.Version > current {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies the pending migrations in order and returns those applied.
+// Each migration's version is recorded in schema_migrations once it has
+// applied, so a failure leaves the database at the last good version.
+func Up(db *sql.DB, logger *slog.Logger) ([]Migration, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	pending, err := Pending(db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]Migration, 0, len(pending))
+	for _, m := range pending {
+		if err := apply(db, m, logger); err != nil {
+			return applied, fmt.Errorf("migrate %03d: %w", m.Version, err)
+		}
+		applied = append(applied, m)
+		logger.Info("applied migration", "version", m.Version, "name", m.Name)
+	}
+	return applied, nil
+}
+
+// apply runs one migration and its data fixes, then records its version.
+func apply(db *sql.DB, m Migration, logger *slog.Logger) error {
+	skipped, err := schema.Exec(db, m)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		logger.Debug("migration already applied", "version", m.Version, "error", skipped)
+	}
+	if after := afterMigration[m.Version]; after != nil {
+		if err := after(db, logger); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec("INSERT OR IGNORE INTO schema_migrations (version) VALUES (?)", m.Version); err != nil {
+		return fmt.Errorf("record version: %w", err)
+	}
+	return nil
+}
+
+// backfillNormalizedTitles fills in the titles added by migration 015 and
+// reports content that duplicates an earlier row.
+func backfillNormalizedTitles(db *sql.DB, logger *slog.Logger) error {
+	dups, err := library.NewStore(db).BackfillNormalizedTitles()
+	if err != nil {
+		return fmt.Errorf("backfill: %w", err)
+	}
+	for _, d := range dups {
+		logger.Warn("duplicate content found; merge or delete one of them",
+			"content_id", d.ID, "duplicate_of", d.DuplicateOf, "type", d.Type, "title", d.Title, "year", d.Year)
+	}
+	return nil
+}
+
+// backfillReleaseGroups fills in the release groups added by migration 033.
+func backfillReleaseGroups(db *sql.DB, logger *slog.Logger) error {
+	n, err := download.NewStore(db).BackfillReleaseGroups()
+	if err != nil {
+		return fmt.Errorf("backfill: %w", err)
+	}
+	if n > 0 {
+		logger.Info("backfilled download release groups", "downloads", n)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/database"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "arrgo.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// columns returns the column names of table.
+func columns(t *testing.T, db *sql.DB, table string) []string {
+	t.Helper()
+	rows, err := db.Query(fmt.Sprintf("SELECT name FROM pragma_table_info('%s')", table))
+	require.NoError(t, err)
+	defer rows.Close()
+	var cols []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		cols = append(cols, name)
+	}
+	return cols
+}
+
+func TestAll_Ordered(t *testing.T) {
+	all, err := All()
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+	assert.Equal(t, 1, all[0].Version)
+	assert.Equal(t, "initial", all[0].Name)
+	for i := 1; i < len(all); i++ {
+		assert.Greater(t, all[i].Version, all[i-1].Version)
+	}
+	assert.Equal(t, all[len(all)-1].Version, Latest())
+}
+
+func TestUp_FreshDatabase(t *testing.T) {
+	db := openTestDB(t)
+
+	version, err := CurrentVersion(db)
+	require.NoError(t, err)
+	assert.Zero(t, version)
+
+	all, err := All()
+	require.NoError(t, err)
+	applied, err := Up(db, testLogger())
+	require.NoError(t, err)
+	assert.Len(t, applied, len(all))
+
+	version, err = CurrentVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, Latest(), version)
+
+	// The schema matches what the stores expect
+	assert.Contains(t, columns(t, db, "downloads"), "last_error")
+	assert.Contains(t, columns(t, db, "content"), "normalized_title")
+	assert.Contains(t, columns(t, db, "content"), "daily")
+	assert.NotEmpty(t, columns(t, db, "seasons"))
+	assert.NotEmpty(t, columns(t, db, "blocklist"))
+	assert.NotEmpty(t, columns(t, db, "download_episode_results"))
+	assert.NotEmpty(t, columns(t, db, "library_check_items"))
+	assert.NotEmpty(t, columns(t, db, "exclusions"))
+	assert.Contains(t, columns(t, db, "files"), "release_group")
+	assert.NotEmpty(t, columns(t, db, "import_queue"))
+	assert.Contains(t, columns(t, db, "content"), "naming_template")
+	assert.Contains(t, columns(t, db, "quality_profiles"), "definition")
+	assert.Contains(t, columns(t, db, "files"), "missing_at")
+	assert.Contains(t, columns(t, db, "content"), "author")
+
+	// Nothing left to do
+	pending, err := Pending(db)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	applied, err = Up(db, testLogger())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestUp_LegacyDatabase(t *testing.T) {
+	db := openTestDB(t)
+
+	// A database whose progress columns (008) were added without the
+	// version being recorded
+	all, err := All()
+	require.NoError(t, err)
+	for _, m := range all {
+		if m.Version > 8 {
+			break
+		}
+		require.NoError(t, apply(db, m, testLogger()))
+	}
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 8")
+	require.NoError(t, err)
+	version, err := CurrentVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, 7, version)
+
+	_, err = Up(db, testLogger())
+	require.NoError(t, err)
+
+	version, err = CurrentVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, Latest(), version)
+	assert.Contains(t, columns(t, db, "downloads"), "size_bytes")
+}
+
+func TestUp_BackfillsReleaseGroups(t *testing.T) {
+	db := openTestDB(t)
+
+	all, err := All()
+	require.NoError(t, err)
+	for _, m := range all {
+		if m.Version >= 33 {
+			break
+		}
+		require.NoError(t, apply(db, m, testLogger()))
+	}
+	_, err = db.Exec(`INSERT INTO content (id, type, title, year, status, quality_profile, root_path)
+		VALUES (1, 'movie', 'Heat', 1995, 'wanted', 'hd', '/movies')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO downloads (content_id, client, client_id, status, release_name, indexer)
+		VALUES (1, 'sabnzbd', 'nzo_1', 'imported', 'Heat.1995.1080p.BluRay.x264-AMIABLE', 'test'),
+		       (1, 'sabnzbd', 'nzo_2', 'failed', 'Heat.1995.1080p.WEB-DL.x264', 'test')`)
+	require.NoError(t, err)
+
+	_, err = Up(db, testLogger())
+	require.NoError(t, err)
+
+	rows, err := db.Query("SELECT release_group FROM downloads ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+	var groups []string
+	for rows.Next() {
+		var g string
+		require.NoError(t, rows.Scan(&g))
+		groups = append(groups, g)
+	}
+	assert.Equal(t, []string{"AMIABLE", ""}, groups)
+}
+
+func TestPending_DatabaseTooNew(t *testing.T) {
+	db := openTestDB(t)
+	_, err := Up(db, testLogger())
+	require.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO schema_migrations (version) VALUES (?)", Latest()+1)
+	require.NoError(t, err)
+
+	_, err = Pending(db)
+	require.ErrorIs(t, err, ErrDatabaseTooNew)
+	_, err = Up(db, testLogger())
+	assert.ErrorIs(t, err, ErrDatabaseTooNew)
+}
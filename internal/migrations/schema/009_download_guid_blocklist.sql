@@ -0,0 +1,19 @@
+-- Migration 009: Persist release GUIDs and track blocklisted releases.
+-- The blocklist prevents re-grabbing a release that previously failed
+-- to download or import for the same content.
+
+CREATE TABLE IF NOT EXISTS blocklist (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_id   INTEGER NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    guid         TEXT NOT NULL,
+    release_name TEXT NOT NULL DEFAULT '',
+    indexer      TEXT NOT NULL DEFAULT '',
+    reason       TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(content_id, guid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blocklist_content ON blocklist(content_id);
+
+-- Release GUID from the indexer (empty for grabs without one)
+ALTER TABLE downloads ADD COLUMN guid TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- Migration 010: Store display metadata (overview, poster, runtime, genres) on content.
+-- Populated from TMDB (movies) or TVDB (series) when a provider is configured.
+
+ALTER TABLE content ADD COLUMN overview TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN poster_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN runtime INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE content ADD COLUMN genres TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE content ADD COLUMN metadata_updated_at TIMESTAMP;
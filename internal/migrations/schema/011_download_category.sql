@@ -0,0 +1,4 @@
+-- Migration 011: Record the download client category used for each download.
+-- SABnzbd stores completed jobs in a per-category subdirectory of the download root.
+
+ALTER TABLE downloads ADD COLUMN category TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,40 @@
+-- Migration 012: Allow any configured client name in downloads.client.
+-- With multiple download clients, each download records the name of the
+-- client that handled it, so the fixed CHECK list is dropped.
+-- SQLite doesn't support dropping a CHECK, so we recreate the table.
+
+CREATE TABLE downloads_new (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_id      INTEGER NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    episode_id      INTEGER REFERENCES episodes(id) ON DELETE CASCADE,
+    client          TEXT NOT NULL,
+    client_id       TEXT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'downloading', 'completed', 'importing', 'failed', 'imported', 'cleaned')),
+    release_name    TEXT,
+    indexer         TEXT,
+    added_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at    TIMESTAMP,
+    last_transition_at TIMESTAMP,
+    season          INTEGER,
+    is_complete_season INTEGER DEFAULT 0,
+    progress        REAL DEFAULT 0,
+    speed           INTEGER DEFAULT 0,
+    eta_seconds     INTEGER DEFAULT 0,
+    size_bytes      INTEGER DEFAULT 0,
+    guid            TEXT NOT NULL DEFAULT '',
+    category        TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO downloads_new (id, content_id, episode_id, client, client_id, status, release_name, indexer,
+    added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds,
+    size_bytes, guid, category)
+SELECT id, content_id, episode_id, client, client_id, status, release_name, indexer,
+    added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds,
+    size_bytes, guid, category
+FROM downloads;
+DROP TABLE downloads;
+ALTER TABLE downloads_new RENAME TO downloads;
+
+CREATE INDEX IF NOT EXISTS idx_downloads_content ON downloads(content_id);
+CREATE INDEX IF NOT EXISTS idx_downloads_status ON downloads(status);
+CREATE INDEX IF NOT EXISTS idx_downloads_client ON downloads(client, client_id);
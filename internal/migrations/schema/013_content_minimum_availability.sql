@@ -0,0 +1,6 @@
+-- Migration 013: Minimum availability for movies.
+-- A movie is not searched for until its TMDB release date reaches the requested stage
+-- ('announced', 'inCinemas' or 'released'). release_date is filled in with display metadata.
+
+ALTER TABLE content ADD COLUMN minimum_availability TEXT NOT NULL DEFAULT 'announced';
+ALTER TABLE content ADD COLUMN release_date TIMESTAMP;
@@ -0,0 +1,10 @@
+-- Migration 014: Per-season monitoring for series.
+-- Records which seasons were requested (e.g. by Overseerr) so searches target
+-- exactly those seasons and episodes synced later in unmonitored seasons are skipped.
+
+CREATE TABLE IF NOT EXISTS seasons (
+    content_id  INTEGER NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    season      INTEGER NOT NULL,
+    monitored   INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (content_id, season)
+);
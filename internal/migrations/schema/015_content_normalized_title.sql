@@ -0,0 +1,8 @@
+-- Migration 015: Normalized titles for duplicate detection.
+-- normalized_title is the title lowercased with punctuation and whitespace collapsed,
+-- so "Se7en" and "se7en " are the same content. The store maintains it and backfills
+-- existing rows after this migration; rows that duplicate an earlier row keep NULL
+-- (NULLs never conflict in a unique index) and are reported at startup.
+
+ALTER TABLE content ADD COLUMN normalized_title TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_content_normalized_title ON content(type, normalized_title, year);
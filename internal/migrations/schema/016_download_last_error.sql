@@ -0,0 +1,5 @@
+-- Migration 016: Record why a download failed.
+-- Set when a grab fails after the download client retries are exhausted,
+-- or when the client rejects the release outright.
+
+ALTER TABLE downloads ADD COLUMN last_error TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- Migration 018: Daily series.
+-- Daily shows release by air date ("Show.2024.03.14") instead of SxxExx,
+-- so their episodes are matched by air_date.
+
+ALTER TABLE content ADD COLUMN daily INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,65 @@
+-- Migration 019: 'abandoned' content status and history events.
+-- Wanted items that will never be found can be abandoned with a reason: they
+-- drop out of wanted searches and the reason is recorded in history.
+-- SQLite doesn't support altering a CHECK, so we recreate both tables.
+-- Foreign keys must be off so dropping content does not cascade.
+
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE content_new (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    type            TEXT NOT NULL CHECK (type IN ('movie', 'series')),
+    tmdb_id         INTEGER,
+    tvdb_id         INTEGER,
+    title           TEXT NOT NULL,
+    year            INTEGER,
+    status          TEXT NOT NULL DEFAULT 'wanted' CHECK (status IN ('wanted', 'available', 'unmonitored', 'abandoned')),
+    quality_profile TEXT NOT NULL DEFAULT 'hd',
+    root_path       TEXT NOT NULL,
+    added_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    overview        TEXT NOT NULL DEFAULT '',
+    poster_url      TEXT NOT NULL DEFAULT '',
+    runtime         INTEGER NOT NULL DEFAULT 0,
+    genres          TEXT NOT NULL DEFAULT '[]',
+    metadata_updated_at TIMESTAMP,
+    minimum_availability TEXT NOT NULL DEFAULT 'announced',
+    release_date    TIMESTAMP,
+    normalized_title TEXT,
+    daily           INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO content_new (id, type, tmdb_id, tvdb_id, title, year, status, quality_profile, root_path,
+    added_at, updated_at, overview, poster_url, runtime, genres, metadata_updated_at,
+    minimum_availability, release_date, normalized_title, daily)
+SELECT id, type, tmdb_id, tvdb_id, title, year, status, quality_profile, root_path,
+    added_at, updated_at, overview, poster_url, runtime, genres, metadata_updated_at,
+    minimum_availability, release_date, normalized_title, daily
+FROM content;
+DROP TABLE content;
+ALTER TABLE content_new RENAME TO content;
+
+CREATE INDEX IF NOT EXISTS idx_content_type ON content(type);
+CREATE INDEX IF NOT EXISTS idx_content_status ON content(status);
+CREATE INDEX IF NOT EXISTS idx_content_tmdb ON content(tmdb_id);
+CREATE INDEX IF NOT EXISTS idx_content_tvdb ON content(tvdb_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_content_normalized_title ON content(type, normalized_title, year);
+
+CREATE TABLE history_new (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_id      INTEGER NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    episode_id      INTEGER REFERENCES episodes(id) ON DELETE CASCADE,
+    event           TEXT NOT NULL CHECK (event IN ('grabbed', 'imported', 'deleted', 'upgraded', 'failed', 'grab_decision', 'abandoned')),
+    data            TEXT,  -- JSON blob for event-specific details
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO history_new (id, content_id, episode_id, event, data, created_at)
+SELECT id, content_id, episode_id, event, data, created_at
+FROM history;
+DROP TABLE history;
+ALTER TABLE history_new RENAME TO history;
+
+CREATE INDEX IF NOT EXISTS idx_history_content ON history(content_id);
+CREATE INDEX IF NOT EXISTS idx_history_event ON history(event);
+CREATE INDEX IF NOT EXISTS idx_history_created ON history(created_at);
@@ -0,0 +1,8 @@
+-- Migration 020: Index events for filtered listing.
+-- Per-entity and per-type queries are usually bounded by time, so the
+-- composite indexes replace the single-purpose ones they extend.
+
+DROP INDEX IF EXISTS idx_events_entity;
+DROP INDEX IF EXISTS idx_events_type;
+CREATE INDEX IF NOT EXISTS idx_events_entity_occurred ON events(entity_type, entity_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_events_type_occurred ON events(event_type, occurred_at);
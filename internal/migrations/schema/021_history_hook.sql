@@ -0,0 +1,22 @@
+-- Migration 021: Allow 'hook' history events.
+-- Records the exit status and output of post-import hook scripts.
+-- SQLite doesn't support altering a CHECK, so we recreate the table.
+
+CREATE TABLE history_new (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_id      INTEGER NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    episode_id      INTEGER REFERENCES episodes(id) ON DELETE CASCADE,
+    event           TEXT NOT NULL CHECK (event IN ('grabbed', 'imported', 'deleted', 'upgraded', 'failed', 'grab_decision', 'abandoned', 'hook')),
+    data            TEXT,  -- JSON blob for event-specific details
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO history_new (id, content_id, episode_id, event, data, created_at)
+SELECT id, content_id, episode_id, event, data, created_at
+FROM history;
+DROP TABLE history;
+ALTER TABLE history_new RENAME TO history;
+
+CREATE INDEX IF NOT EXISTS idx_history_content ON history(content_id);
+CREATE INDEX IF NOT EXISTS idx_history_event ON history(event);
+CREATE INDEX IF NOT EXISTS idx_history_created ON history(created_at);
@@ -0,0 +1,4 @@
+-- Migration 022: Per-episode quality profile override.
+-- NULL means the episode inherits its series' quality profile.
+
+ALTER TABLE episodes ADD COLUMN quality_profile TEXT;
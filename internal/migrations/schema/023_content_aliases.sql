@@ -0,0 +1,15 @@
+-- Migration 023: Alternate titles for content.
+-- Aliases come from TVDB when a series is synced, or are added by hand.
+-- normalized_alias uses the same normalization as content.normalized_title.
+
+CREATE TABLE IF NOT EXISTS content_aliases (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_id       INTEGER NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    alias            TEXT NOT NULL,
+    normalized_alias TEXT NOT NULL,
+    source           TEXT NOT NULL CHECK (source IN ('tvdb', 'manual')),
+    added_at         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (content_id, normalized_alias)
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_aliases_normalized ON content_aliases(normalized_alias);
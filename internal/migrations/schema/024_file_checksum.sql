@@ -0,0 +1,5 @@
+-- Migration 024: File checksums.
+-- SHA-256 of the file as imported, for detecting corruption later.
+-- NULL means the file was imported without checksum verification.
+
+ALTER TABLE files ADD COLUMN checksum TEXT;
@@ -0,0 +1,6 @@
+-- Migration 025: TVDB series status.
+-- Lowercased TVDB status ("continuing", "ended", "upcoming"), stored when
+-- episodes are synced. Continuing series are re-synced periodically so new
+-- seasons appear; NULL means the series was never synced with a status.
+
+ALTER TABLE content ADD COLUMN series_status TEXT;
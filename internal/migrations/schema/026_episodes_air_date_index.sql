@@ -0,0 +1,4 @@
+-- Migration 026: Index episodes by air date.
+-- The dashboard lists the next episodes to air across all series.
+
+CREATE INDEX IF NOT EXISTS idx_episodes_air_date ON episodes(air_date);
@@ -0,0 +1,5 @@
+-- Migration 027: Multi-part movie files.
+-- Part number of a file split across several files (CD1/CD2, part1/part2).
+-- NULL means the file holds the whole movie or episode.
+
+ALTER TABLE files ADD COLUMN part_number INTEGER;
@@ -0,0 +1,8 @@
+-- Migration 028: Event outbox.
+-- Durable events are written with processed_at NULL and delivered to
+-- subscribers by the outbox dispatcher, which resumes pending events after a
+-- restart. Events logged before the outbox existed were already delivered.
+
+ALTER TABLE events ADD COLUMN processed_at TIMESTAMP;
+UPDATE events SET processed_at = created_at;
+CREATE INDEX IF NOT EXISTS idx_events_pending ON events(id) WHERE processed_at IS NULL;
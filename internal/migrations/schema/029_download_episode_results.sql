@@ -0,0 +1,62 @@
+-- Migration 029: Partially imported season packs.
+-- A season pack where some episodes fail to import stays 'partially_imported'
+-- with the outcome of each file recorded, so the failed episodes can be
+-- reimported. The status CHECK also gains 'skipped', which the stores used
+-- before it was allowed here.
+-- SQLite doesn't support altering a CHECK, so we recreate the table.
+-- Foreign keys must be off so dropping downloads does not cascade.
+
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE downloads_new (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_id      INTEGER NOT NULL REFERENCES content(id) ON DELETE CASCADE,
+    episode_id      INTEGER REFERENCES episodes(id) ON DELETE CASCADE,
+    client          TEXT NOT NULL,
+    client_id       TEXT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'downloading', 'completed', 'importing', 'failed', 'imported', 'partially_imported', 'cleaned', 'skipped')),
+    release_name    TEXT,
+    indexer         TEXT,
+    added_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at    TIMESTAMP,
+    last_transition_at TIMESTAMP,
+    season          INTEGER,
+    is_complete_season INTEGER DEFAULT 0,
+    progress        REAL DEFAULT 0,
+    speed           INTEGER DEFAULT 0,
+    eta_seconds     INTEGER DEFAULT 0,
+    size_bytes      INTEGER DEFAULT 0,
+    guid            TEXT NOT NULL DEFAULT '',
+    category        TEXT NOT NULL DEFAULT '',
+    last_error      TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO downloads_new (id, content_id, episode_id, client, client_id, status, release_name, indexer,
+    added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds,
+    size_bytes, guid, category, last_error)
+SELECT id, content_id, episode_id, client, client_id, status, release_name, indexer,
+    added_at, completed_at, last_transition_at, season, is_complete_season, progress, speed, eta_seconds,
+    size_bytes, guid, category, last_error
+FROM downloads;
+DROP TABLE downloads;
+ALTER TABLE downloads_new RENAME TO downloads;
+
+CREATE INDEX IF NOT EXISTS idx_downloads_content ON downloads(content_id);
+CREATE INDEX IF NOT EXISTS idx_downloads_status ON downloads(status);
+CREATE INDEX IF NOT EXISTS idx_downloads_client ON downloads(client, client_id);
+
+PRAGMA foreign_keys = ON;
+
+-- Per-file outcome of a season pack import
+CREATE TABLE IF NOT EXISTS download_episode_results (
+    download_id INTEGER NOT NULL REFERENCES downloads(id) ON DELETE CASCADE,
+    source_file TEXT NOT NULL,
+    episode_id  INTEGER REFERENCES episodes(id) ON DELETE SET NULL,
+    season      INTEGER NOT NULL DEFAULT 0,
+    episode     INTEGER NOT NULL DEFAULT 0,
+    success     INTEGER NOT NULL DEFAULT 0,
+    file_path   TEXT NOT NULL DEFAULT '',
+    error       TEXT NOT NULL DEFAULT '',
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (download_id, source_file)
+);
@@ -0,0 +1,33 @@
+-- Migration 030: Library check jobs.
+-- A library check runs in the background and records each item it checks,
+-- so progress and partial results can be polled and the last scan viewed
+-- without running it again. Items keep the title of content deleted since.
+
+CREATE TABLE IF NOT EXISTS library_check_jobs (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    status      TEXT NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'completed', 'failed')),
+    filter      TEXT NOT NULL DEFAULT '',
+    total       INTEGER NOT NULL DEFAULT 0,
+    checked     INTEGER NOT NULL DEFAULT 0,
+    healthy     INTEGER NOT NULL DEFAULT 0,
+    with_issues INTEGER NOT NULL DEFAULT 0,
+    error       TEXT NOT NULL DEFAULT '',
+    plex_error  TEXT NOT NULL DEFAULT '',
+    started_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS library_check_items (
+    job_id       INTEGER NOT NULL REFERENCES library_check_jobs(id) ON DELETE CASCADE,
+    content_id   INTEGER NOT NULL,
+    type         TEXT NOT NULL,
+    title        TEXT NOT NULL,
+    year         INTEGER NOT NULL DEFAULT 0,
+    status       TEXT NOT NULL,
+    files        TEXT NOT NULL DEFAULT '[]',
+    file_missing TEXT NOT NULL DEFAULT '[]',
+    in_plex      INTEGER NOT NULL DEFAULT 0,
+    plex_title   TEXT NOT NULL DEFAULT '',
+    issues       TEXT NOT NULL DEFAULT '[]',
+    PRIMARY KEY (job_id, content_id)
+);
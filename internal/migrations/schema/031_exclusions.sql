@@ -0,0 +1,14 @@
+-- Migration 031: Exclusions.
+-- External IDs that must never be added to the library again, such as
+-- content deliberately deleted that Overseerr keeps requesting.
+
+CREATE TABLE IF NOT EXISTS exclusions (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    source      TEXT NOT NULL CHECK (source IN ('tmdb', 'tvdb')),
+    external_id INTEGER NOT NULL,
+    title       TEXT NOT NULL DEFAULT '',
+    year        INTEGER NOT NULL DEFAULT 0,
+    reason      TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(source, external_id)
+);
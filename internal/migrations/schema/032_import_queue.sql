@@ -0,0 +1,13 @@
+-- Migration 032: Import queue.
+-- Completed downloads waiting for an import worker. Imports run a few at a
+-- time in position order; new entries go after the last and an entry moved
+-- to the front goes before the first, so positions may be negative.
+
+CREATE TABLE IF NOT EXISTS import_queue (
+    download_id INTEGER PRIMARY KEY REFERENCES downloads(id) ON DELETE CASCADE,
+    position    INTEGER NOT NULL,
+    source_path TEXT NOT NULL DEFAULT '',
+    queued_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_queue_position ON import_queue(position);
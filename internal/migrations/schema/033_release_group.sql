@@ -0,0 +1,7 @@
+-- Migration 033: Release groups.
+-- The group that made a release, parsed from its name, so quality profiles
+-- can prefer or ban groups and users can see where a file came from.
+-- Existing downloads are backfilled from their release names.
+
+ALTER TABLE downloads ADD COLUMN release_group TEXT NOT NULL DEFAULT '';
+ALTER TABLE files ADD COLUMN release_group TEXT NOT NULL DEFAULT '';
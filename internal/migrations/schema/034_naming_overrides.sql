@@ -0,0 +1,6 @@
+-- Migration 034: Per-content naming overrides.
+-- A series can keep or drop season folders and use its own naming template,
+-- e.g. anime without season folders. NULL inherits the library settings.
+
+ALTER TABLE content ADD COLUMN season_folder INTEGER;
+ALTER TABLE content ADD COLUMN naming_template TEXT;
@@ -0,0 +1,15 @@
+-- Quality profile definitions move from config.toml into the database so they
+-- can be edited through the API. Each definition is the profile's settings as
+-- JSON, with the same keys as the [quality.profiles.NAME] TOML tables.
+-- arrgod seeds the table from config when it is empty. The table created by
+-- 001 was never read, so its placeholder rows are dropped.
+
+DROP TABLE IF EXISTS quality_profiles;
+
+CREATE TABLE quality_profiles (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        TEXT NOT NULL UNIQUE,
+    definition  TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
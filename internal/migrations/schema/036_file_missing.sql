@@ -0,0 +1,7 @@
+-- Migration 036: Missing files.
+-- Files deleted outside arrgo are marked missing by the background reconcile
+-- rather than deleted, so their history is kept and checks can read the flag
+-- instead of statting every file again.
+
+ALTER TABLE files ADD COLUMN missing_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_files_missing ON files(missing_at) WHERE missing_at IS NOT NULL;
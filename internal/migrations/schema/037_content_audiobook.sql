@@ -0,0 +1,52 @@
+-- Migration 037: Audiobook content.
+-- Audiobooks are single items like movies, named by author and title. They
+-- are only added when the audiobook library is enabled in config.
+-- SQLite doesn't support altering a CHECK, so we recreate the table.
+-- Foreign keys must be off so dropping content does not cascade.
+
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE content_new (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    type            TEXT NOT NULL CHECK (type IN ('movie', 'series', 'audiobook')),
+    tmdb_id         INTEGER,
+    tvdb_id         INTEGER,
+    title           TEXT NOT NULL,
+    year            INTEGER,
+    status          TEXT NOT NULL DEFAULT 'wanted' CHECK (status IN ('wanted', 'available', 'unmonitored', 'abandoned')),
+    quality_profile TEXT NOT NULL DEFAULT 'hd',
+    root_path       TEXT NOT NULL,
+    added_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    overview        TEXT NOT NULL DEFAULT '',
+    poster_url      TEXT NOT NULL DEFAULT '',
+    runtime         INTEGER NOT NULL DEFAULT 0,
+    genres          TEXT NOT NULL DEFAULT '[]',
+    metadata_updated_at TIMESTAMP,
+    minimum_availability TEXT NOT NULL DEFAULT 'announced',
+    release_date    TIMESTAMP,
+    normalized_title TEXT,
+    daily           INTEGER NOT NULL DEFAULT 0,
+    series_status   TEXT,
+    season_folder   INTEGER,
+    naming_template TEXT,
+    author          TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO content_new (id, type, tmdb_id, tvdb_id, title, year, status, quality_profile, root_path,
+    added_at, updated_at, overview, poster_url, runtime, genres, metadata_updated_at,
+    minimum_availability, release_date, normalized_title, daily, series_status, season_folder, naming_template)
+SELECT id, type, tmdb_id, tvdb_id, title, year, status, quality_profile, root_path,
+    added_at, updated_at, overview, poster_url, runtime, genres, metadata_updated_at,
+    minimum_availability, release_date, normalized_title, daily, series_status, season_folder, naming_template
+FROM content;
+DROP TABLE content;
+ALTER TABLE content_new RENAME TO content;
+
+CREATE INDEX IF NOT EXISTS idx_content_type ON content(type);
+CREATE INDEX IF NOT EXISTS idx_content_status ON content(status);
+CREATE INDEX IF NOT EXISTS idx_content_tmdb ON content(tmdb_id);
+CREATE INDEX IF NOT EXISTS idx_content_tvdb ON content(tvdb_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_content_normalized_title ON content(type, normalized_title, year);
+
+PRAGMA foreign_keys = ON;
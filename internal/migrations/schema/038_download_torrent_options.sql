@@ -0,0 +1,10 @@
+-- Migration 038: Torrent options of downloads.
+-- Sequential download and first/last piece priority as applied to a torrent
+-- client. Downloads sent to clients without per-torrent options, such as
+-- SABnzbd, have no row.
+
+CREATE TABLE IF NOT EXISTS download_torrent_options (
+    download_id           INTEGER PRIMARY KEY REFERENCES downloads(id) ON DELETE CASCADE,
+    sequential            INTEGER NOT NULL DEFAULT 0,
+    first_last_piece_prio INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,9 @@
+-- Migration 039: Stuck download alerts.
+-- A download that stays in one status past its alert threshold raises a
+-- download.stuck event once; the row records that it was sent.
+
+CREATE TABLE IF NOT EXISTS download_stuck_alerts (
+    download_id INTEGER PRIMARY KEY REFERENCES downloads(id) ON DELETE CASCADE,
+    status      TEXT NOT NULL,
+    alerted_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+-- Migration 040: File kinds.
+-- Subtitle sidecars imported with a video are recorded as files too, so they
+-- are tracked and deleted with their content. Only media files count towards
+-- a movie or episode being available.
+
+ALTER TABLE files ADD COLUMN kind TEXT NOT NULL DEFAULT 'media';
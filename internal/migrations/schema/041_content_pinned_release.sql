@@ -0,0 +1,12 @@
+-- Migration 041: Pinned releases.
+-- A release pinned for a content item is grabbed by searches for it instead
+-- of scoring search results, until it imports or its grab fails. Series pin a
+-- season pack, so the season is recorded too.
+
+ALTER TABLE content ADD COLUMN pinned_guid TEXT;
+ALTER TABLE content ADD COLUMN pinned_download_url TEXT;
+ALTER TABLE content ADD COLUMN pinned_title TEXT;
+ALTER TABLE content ADD COLUMN pinned_indexer TEXT;
+ALTER TABLE content ADD COLUMN pinned_protocol TEXT;
+ALTER TABLE content ADD COLUMN pinned_season INTEGER;
+ALTER TABLE content ADD COLUMN pinned_at TIMESTAMP;
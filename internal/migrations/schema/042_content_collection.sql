@@ -0,0 +1,6 @@
+-- Migration 042: TMDB collections.
+-- The collection a movie belongs to (e.g. a franchise), stored with its
+-- display metadata so the other movies in it can be offered.
+
+ALTER TABLE content ADD COLUMN collection_id INTEGER;
+ALTER TABLE content ADD COLUMN collection_name TEXT NOT NULL DEFAULT '';
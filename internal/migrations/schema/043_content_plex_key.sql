@@ -0,0 +1,5 @@
+-- Migration 043: Plex rating keys.
+-- The Plex item of content once found, so verification fetches it directly
+-- instead of searching by title. Filled in on the first successful search.
+
+ALTER TABLE content ADD COLUMN plex_key TEXT NOT NULL DEFAULT '';
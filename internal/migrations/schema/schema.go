@@ -0,0 +1,91 @@
+// Package schema embeds the SQL migration files and runs them. It has no
+// dependencies on the stores so that tests in any package can build the
+// schema from the migrations; package migrations records which have applied
+// to a real database.
+package schema
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// files holds the migrations, named NNN_description.sql. Version 004 is
+// unused (it was merged into 003).
+//
+//go:embed *.sql
+var files embed.FS
+
+// legacyVersion is the last migration that may find its changes already
+// applied: databases from before version tracking was reliable can have
+// those columns and tables without the version recorded.
+const legacyVersion = 11
+
+// Migration is one embedded schema change. Migrations are up-only and
+// applied in version order.
+type Migration struct {
+	Version int
+	Name    string // Description from the file name, e.g. "download_last_error"
+	SQL     string
+}
+
+// All returns every embedded migration in version order.
+func All() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	all := make([]Migration, 0, len(entries))
+	seen := make(map[int]string, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || path.Ext(name) != ".sql" {
+			continue
+		}
+		num, desc, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), "_")
+		version, err := strconv.Atoi(num)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must be NNN_description.sql", name)
+		}
+		if prev, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migration %s: version %d already used by %s", name, version, prev)
+		}
+		seen[version] = name
+
+		data, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+		all = append(all, Migration{Version: version, Name: desc, SQL: string(data)})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all, nil
+}
+
+// Exec runs a migration's SQL. It is not wrapped in a transaction: some
+// migrations toggle foreign_keys, which SQLite ignores inside one.
+//
+// A legacy migration whose change is already in the schema is not an error;
+// Exec returns that error as skipped instead, for logging.
+func Exec(db *sql.DB, m Migration) (skipped, err error) {
+	if _, err := db.Exec(m.SQL); err != nil {
+		if m.Version > legacyVersion || !alreadyApplied(err) {
+			return nil, err
+		}
+		return err, nil
+	}
+	return nil, nil
+}
+
+// alreadyApplied reports whether err means the migration's change is
+// already in the schema.
+func alreadyApplied(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "duplicate column") || strings.Contains(msg, "already exists")
+}
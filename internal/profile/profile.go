@@ -0,0 +1,43 @@
+// Package profile stores quality profile definitions.
+//
+// Profiles live in the database and are seeded from config.toml on first
+// run. A Store built with NewConfigStore serves config-defined profiles
+// read-only instead, for setups that keep profiles in config.
+package profile
+
+import (
+	"errors"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/config"
+)
+
+var (
+	// ErrNotFound indicates the profile doesn't exist.
+	ErrNotFound = errors.New("quality profile not found")
+
+	// ErrDuplicate indicates a profile with the same name already exists.
+	ErrDuplicate = errors.New("quality profile already exists")
+
+	// ErrInUse indicates content or episodes still reference the profile.
+	ErrInUse = errors.New("quality profile in use")
+
+	// ErrReadOnly indicates profiles come from config and can't be changed.
+	ErrReadOnly = errors.New("quality profiles are read-only")
+)
+
+// Profile is a named quality profile.
+type Profile struct {
+	ID        int64
+	Name      string
+	Settings  config.QualityProfile
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Cutoff returns the quality at which content of the profile is done: its
+// configured cutoff, else the best resolution it accepts, or "" if it
+// accepts none that rank.
+func (p *Profile) Cutoff() string {
+	return p.Settings.CutoffQuality()
+}
@@ -0,0 +1,326 @@
+package profile
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/config"
+)
+
+// Store provides access to quality profiles. Profiles are loaded from the
+// database on first use and kept in memory, since the scorer looks them up
+// for every release; changes made through the Store update both.
+type Store struct {
+	db *sql.DB // nil for a config-backed store
+
+	mu       sync.RWMutex
+	loaded   bool
+	profiles map[string]*Profile
+}
+
+// NewStore creates a store backed by the quality_profiles table.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// NewConfigStore creates a read-only store serving profiles from config.
+// IDs follow the sorted profile names, so they are stable across restarts
+// as long as the config doesn't change.
+func NewConfigStore(profiles map[string]config.QualityProfile) *Store {
+	s := &Store{loaded: true, profiles: make(map[string]*Profile, len(profiles))}
+	for i, name := range sortedNames(profiles) {
+		s.profiles[name] = &Profile{ID: int64(i + 1), Name: name, Settings: profiles[name]}
+	}
+	return s
+}
+
+// ReadOnly reports whether the store serves profiles from config.
+func (s *Store) ReadOnly() bool {
+	return s.db == nil
+}
+
+// Seed adds profiles to an empty table, in name order, and returns how many
+// were added. It does nothing once the table has any profile, so profiles
+// edited or deleted through the API are not brought back from config.
+func (s *Store) Seed(profiles map[string]config.QualityProfile) (int, error) {
+	if s.ReadOnly() {
+		return 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("seed quality profiles: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var n int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM quality_profiles").Scan(&n); err != nil {
+		return 0, fmt.Errorf("seed quality profiles: %w", err)
+	}
+	if n > 0 {
+		return 0, nil
+	}
+	now := time.Now()
+	for _, name := range sortedNames(profiles) {
+		def, err := json.Marshal(profiles[name])
+		if err != nil {
+			return 0, fmt.Errorf("seed quality profile %q: %w", name, err)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO quality_profiles (name, definition, created_at, updated_at)
+			VALUES (?, ?, ?, ?)`, name, string(def), now, now); err != nil {
+			return 0, fmt.Errorf("seed quality profile %q: %w", name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("seed quality profiles: %w", err)
+	}
+	s.loaded = false // Reload with the IDs just assigned
+	return len(profiles), nil
+}
+
+// List returns all profiles ordered by ID.
+func (s *Store) List() ([]*Profile, error) {
+	if err := s.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]*Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		cp := *p
+		list = append(list, &cp)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// Get returns a profile by name.
+func (s *Store) Get(name string) (*Profile, error) {
+	if err := s.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("get quality profile %q: %w", name, ErrNotFound)
+	}
+	cp := *p
+	return &cp, nil
+}
+
+// Profile returns a profile's settings, for the scorer. ok is false if the
+// profile doesn't exist or the profiles can't be loaded.
+func (s *Store) Profile(name string) (config.QualityProfile, bool) {
+	p, err := s.Get(name)
+	if err != nil {
+		return config.QualityProfile{}, false
+	}
+	return p.Settings, true
+}
+
+// Definitions returns every profile's settings by name.
+func (s *Store) Definitions() (map[string]config.QualityProfile, error) {
+	list, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	defs := make(map[string]config.QualityProfile, len(list))
+	for _, p := range list {
+		defs[p.Name] = p.Settings
+	}
+	return defs, nil
+}
+
+// Create adds a profile and sets its ID and timestamps.
+// Returns ErrDuplicate if the name is taken and ErrReadOnly for a
+// config-backed store.
+func (s *Store) Create(p *Profile) error {
+	if s.ReadOnly() {
+		return fmt.Errorf("create quality profile %q: %w", p.Name, ErrReadOnly)
+	}
+	if err := s.ensureLoaded(); err != nil {
+		return err
+	}
+	def, err := json.Marshal(p.Settings)
+	if err != nil {
+		return fmt.Errorf("create quality profile %q: %w", p.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	result, err := s.db.Exec(`
+		INSERT INTO quality_profiles (name, definition, created_at, updated_at)
+		VALUES (?, ?, ?, ?)`, p.Name, string(def), now, now)
+	if err != nil {
+		return fmt.Errorf("create quality profile %q: %w", p.Name, mapSQLiteError(err))
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	p.ID = id
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	cp := *p
+	s.profiles[p.Name] = &cp
+	return nil
+}
+
+// Update replaces the settings of the profile named p.Name and sets p's ID
+// and timestamps. Profiles can't be renamed, since content refers to them
+// by name. Returns ErrNotFound if there is no such profile and ErrReadOnly
+// for a config-backed store.
+func (s *Store) Update(p *Profile) error {
+	if s.ReadOnly() {
+		return fmt.Errorf("update quality profile %q: %w", p.Name, ErrReadOnly)
+	}
+	if err := s.ensureLoaded(); err != nil {
+		return err
+	}
+	def, err := json.Marshal(p.Settings)
+	if err != nil {
+		return fmt.Errorf("update quality profile %q: %w", p.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.profiles[p.Name]
+	if !ok {
+		return fmt.Errorf("update quality profile %q: %w", p.Name, ErrNotFound)
+	}
+	now := time.Now()
+	result, err := s.db.Exec("UPDATE quality_profiles SET definition = ?, updated_at = ? WHERE name = ?",
+		string(def), now, p.Name)
+	if err != nil {
+		return fmt.Errorf("update quality profile %q: %w", p.Name, err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("update quality profile %q: %w", p.Name, err)
+	} else if n == 0 {
+		return fmt.Errorf("update quality profile %q: %w", p.Name, ErrNotFound)
+	}
+	p.ID = old.ID
+	p.CreatedAt = old.CreatedAt
+	p.UpdatedAt = now
+	cp := *p
+	s.profiles[p.Name] = &cp
+	return nil
+}
+
+// Delete removes a profile. Returns ErrInUse if any content or episode
+// still uses it, ErrNotFound if there is no such profile, and ErrReadOnly
+// for a config-backed store.
+func (s *Store) Delete(name string) error {
+	if s.ReadOnly() {
+		return fmt.Errorf("delete quality profile %q: %w", name, ErrReadOnly)
+	}
+	if err := s.ensureLoaded(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The in-use check is part of the delete, so content added meanwhile
+	// can't be left on a deleted profile
+	result, err := s.db.Exec(`
+		DELETE FROM quality_profiles WHERE name = ?
+		AND NOT EXISTS (SELECT 1 FROM content WHERE quality_profile = ?)
+		AND NOT EXISTS (SELECT 1 FROM episodes WHERE quality_profile = ?)`, name, name, name)
+	if err != nil {
+		return fmt.Errorf("delete quality profile %q: %w", name, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete quality profile %q: %w", name, err)
+	}
+	if n == 0 {
+		if _, ok := s.profiles[name]; !ok {
+			return fmt.Errorf("delete quality profile %q: %w", name, ErrNotFound)
+		}
+		uses, err := s.uses(name)
+		if err != nil {
+			return fmt.Errorf("delete quality profile %q: %w", name, err)
+		}
+		return fmt.Errorf("delete quality profile %q: %w by %d content item(s) or episode(s)", name, ErrInUse, uses)
+	}
+	delete(s.profiles, name)
+	return nil
+}
+
+// uses counts the content and episodes using a profile.
+func (s *Store) uses(name string) (int, error) {
+	var n int
+	err := s.db.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM content WHERE quality_profile = ?)
+		     + (SELECT COUNT(*) FROM episodes WHERE quality_profile = ?)`, name, name).Scan(&n)
+	return n, err
+}
+
+// ensureLoaded reads the profiles from the database unless they are loaded.
+func (s *Store) ensureLoaded() error {
+	s.mu.RLock()
+	loaded := s.loaded
+	s.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loaded {
+		return nil
+	}
+	rows, err := s.db.Query("SELECT id, name, definition, created_at, updated_at FROM quality_profiles")
+	if err != nil {
+		return fmt.Errorf("load quality profiles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	profiles := make(map[string]*Profile)
+	for rows.Next() {
+		var p Profile
+		var def string
+		if err := rows.Scan(&p.ID, &p.Name, &def, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return fmt.Errorf("scan quality profile: %w", err)
+		}
+		if err := json.Unmarshal([]byte(def), &p.Settings); err != nil {
+			return fmt.Errorf("decode quality profile %q: %w", p.Name, err)
+		}
+		profiles[p.Name] = &p
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate quality profiles: %w", err)
+	}
+	s.profiles = profiles
+	s.loaded = true
+	return nil
+}
+
+func sortedNames(profiles map[string]config.QualityProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mapSQLiteError maps a unique constraint violation to ErrDuplicate.
+func mapSQLiteError(err error) error {
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicate
+	}
+	return err
+}
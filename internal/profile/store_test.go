@@ -0,0 +1,123 @@
+package profile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/testutil"
+)
+
+var testProfiles = map[string]config.QualityProfile{
+	"uhd": {Resolution: []string{"2160p", "1080p"}, MaxSizeMB: 80000},
+	"hd":  {Resolution: []string{"720p", "1080p"}, Preferred: []config.PreferredKeyword{{Keyword: "remux", Weight: 10}}},
+}
+
+func TestStore_Seed(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	n, err := store.Seed(testProfiles)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	list, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "hd", list[0].Name, "seeded in name order")
+	assert.Equal(t, int64(1), list[0].ID)
+	assert.Equal(t, "uhd", list[1].Name)
+	assert.Equal(t, int64(2), list[1].ID)
+	assert.Equal(t, testProfiles["hd"], list[0].Settings)
+
+	// A fresh store reads the same profiles back
+	hd, err := NewStore(db).Get("hd")
+	require.NoError(t, err)
+	assert.Equal(t, testProfiles["hd"], hd.Settings)
+	assert.Equal(t, "1080p", hd.Cutoff())
+
+	// Seeding again leaves the table alone, even after a delete
+	require.NoError(t, store.Delete("uhd"))
+	n, err = store.Seed(testProfiles)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	_, err = store.Get("uhd")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_CRUD(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+
+	p := &Profile{Name: "anime", Settings: config.QualityProfile{Resolution: []string{"1080p"}}}
+	require.NoError(t, store.Create(p))
+	assert.NotZero(t, p.ID)
+	assert.False(t, p.CreatedAt.IsZero())
+	require.ErrorIs(t, store.Create(&Profile{Name: "anime"}), ErrDuplicate)
+
+	settings, ok := store.Profile("anime")
+	require.True(t, ok)
+	assert.Equal(t, []string{"1080p"}, settings.Resolution)
+
+	update := &Profile{Name: "anime", Settings: config.QualityProfile{Resolution: []string{"720p"}, MinSizeMB: 100}}
+	require.NoError(t, store.Update(update))
+	assert.Equal(t, p.ID, update.ID)
+	settings, ok = store.Profile("anime")
+	require.True(t, ok)
+	assert.Equal(t, 100, settings.MinSizeMB)
+	require.ErrorIs(t, store.Update(&Profile{Name: "missing"}), ErrNotFound)
+
+	// Changes reach the database, not just the cache
+	defs, err := NewStore(db).Definitions()
+	require.NoError(t, err)
+	assert.Equal(t, update.Settings, defs["anime"])
+
+	require.NoError(t, store.Delete("anime"))
+	require.ErrorIs(t, store.Delete("anime"), ErrNotFound)
+	_, ok = store.Profile("anime")
+	assert.False(t, ok)
+}
+
+func TestStore_Delete_InUse(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	store := NewStore(db)
+	_, err := store.Seed(testProfiles)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO content (id, type, title, year, status, quality_profile, root_path)
+		VALUES (1, 'series', 'Frieren', 2023, 'wanted', 'hd', '/tv')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO episodes (content_id, season, episode, title, status, quality_profile)
+		VALUES (1, 1, 1, 'The Journey''s End', 'wanted', 'uhd')`)
+	require.NoError(t, err)
+
+	err = store.Delete("hd")
+	require.ErrorIs(t, err, ErrInUse)
+	assert.Contains(t, err.Error(), "1 content item(s)")
+	require.ErrorIs(t, store.Delete("uhd"), ErrInUse, "episode overrides count")
+
+	_, err = store.Get("hd")
+	require.NoError(t, err, "profile in use is kept")
+}
+
+func TestConfigStore(t *testing.T) {
+	store := NewConfigStore(testProfiles)
+	assert.True(t, store.ReadOnly())
+
+	list, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "hd", list[0].Name)
+	assert.Equal(t, int64(1), list[0].ID)
+
+	_, ok := store.Profile("uhd")
+	assert.True(t, ok)
+	n, err := store.Seed(testProfiles)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	require.ErrorIs(t, store.Create(&Profile{Name: "anime"}), ErrReadOnly)
+	require.ErrorIs(t, store.Update(&Profile{Name: "hd"}), ErrReadOnly)
+	require.ErrorIs(t, store.Delete("hd"), ErrReadOnly)
+}
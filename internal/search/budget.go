@@ -0,0 +1,93 @@
+package search
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget limits how many calls each indexer receives per day, so a
+// low-quota indexer is skipped rather than exhausted early. Days are
+// counted in UTC. A nil Budget allows every call.
+type Budget struct {
+	limits map[string]int // Calls allowed per day by indexer name; 0 or missing is unlimited
+	now    func() time.Time
+
+	mu    sync.Mutex
+	day   string         // UTC date the counts apply to
+	calls map[string]int // Calls made on day
+}
+
+// NewBudget creates a budget with the given daily limit per indexer.
+func NewBudget(limits map[string]int) *Budget {
+	return &Budget{limits: limits, now: time.Now, calls: make(map[string]int)}
+}
+
+// Take records a call to the indexer and reports whether it is within the
+// day's budget. A call over budget is not recorded.
+func (b *Budget) Take(indexer string) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	if limit := b.limits[indexer]; limit > 0 && b.calls[indexer] >= limit {
+		return false
+	}
+	b.calls[indexer]++
+	return true
+}
+
+// Limit returns the indexer's daily limit (0 if unlimited).
+func (b *Budget) Limit(indexer string) int {
+	if b == nil {
+		return 0
+	}
+	return b.limits[indexer]
+}
+
+// Usage returns the calls made to each indexer today.
+func (b *Budget) Usage() map[string]int {
+	if b == nil {
+		return nil
+	}
+	_, calls := b.snapshot()
+	return calls
+}
+
+// snapshot returns the current UTC day and a copy of its counts.
+func (b *Budget) snapshot() (string, map[string]int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	calls := make(map[string]int, len(b.calls))
+	for name, n := range b.calls {
+		calls[name] = n
+	}
+	return b.day, calls
+}
+
+// restore sets the counts for day, ignoring counts from an earlier day.
+func (b *Budget) restore(day string, calls map[string]int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	if day != b.day {
+		return
+	}
+	for name, n := range calls {
+		b.calls[name] = max(b.calls[name], n)
+	}
+}
+
+// rollover resets the counts when the UTC day changes; the caller holds the lock.
+func (b *Budget) rollover() {
+	today := b.now().UTC().Format(time.DateOnly)
+	if today != b.day {
+		b.day = today
+		b.calls = make(map[string]int)
+	}
+}
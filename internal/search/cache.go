@@ -0,0 +1,130 @@
+package search
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmunix/arrgo/pkg/release"
+)
+
+const (
+	// DefaultCacheTTL is how long indexer results are reused when no TTL is configured.
+	DefaultCacheTTL = 15 * time.Minute
+	// DefaultCacheEntries bounds the cache when no size is configured.
+	DefaultCacheEntries = 500
+)
+
+// ResultCache keeps recent indexer results so repeated searches, such as
+// bursts of identical lookups from Overseerr, do not spend indexer API calls.
+// Entries expire after the TTL; beyond the maximum size the least recently
+// used entry is evicted. It is safe for concurrent use.
+type ResultCache struct {
+	ttl time.Duration
+	max int
+	now func() time.Time
+
+	mu    sync.Mutex
+	order *list.List               // Front is most recently used
+	items map[string]*list.Element // Values are *cacheEntry
+}
+
+// cacheEntry is one cached indexer response.
+type cacheEntry struct {
+	Key      string    `json:"key"`
+	Releases []Release `json:"releases"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// NewResultCache creates a cache. A zero ttl or size uses the default.
+func NewResultCache(ttl time.Duration, maxEntries int) *ResultCache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	return &ResultCache{
+		ttl:   ttl,
+		max:   maxEntries,
+		now:   time.Now,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// cacheKey identifies the indexer request a query makes: its normalized
+// text and content type, which selects the Newznab categories. Profile,
+// season, and episode are applied after the indexers answer, so queries
+// differing only in those share an entry.
+func cacheKey(q Query) string {
+	return q.Type + "|" + strings.ToLower(release.NormalizeSearchQuery(q.Text))
+}
+
+// Get returns the releases cached for key and when they were stored.
+func (c *ResultCache) Get(key string) ([]Release, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if c.now().Sub(entry.StoredAt) >= c.ttl {
+		c.remove(el)
+		return nil, time.Time{}, false
+	}
+	c.order.MoveToFront(el)
+	return entry.Releases, entry.StoredAt, true
+}
+
+// Put stores releases for key, evicting the least recently used entry if full.
+func (c *ResultCache) Put(key string, releases []Release) {
+	c.put(&cacheEntry{Key: key, Releases: releases, StoredAt: c.now()})
+}
+
+func (c *ResultCache) put(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[entry.Key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[entry.Key] = c.order.PushFront(entry)
+	for c.order.Len() > c.max {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove drops an entry; the caller holds the lock.
+func (c *ResultCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*cacheEntry).Key)
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (c *ResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// entries returns the unexpired entries, least recently used first.
+func (c *ResultCache) entries() []*cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	out := make([]*cacheEntry, 0, c.order.Len())
+	for el := c.order.Back(); el != nil; el = el.Prev() {
+		entry := el.Value.(*cacheEntry)
+		if now.Sub(entry.StoredAt) < c.ttl {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a settable time source for cache and budget tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestCacheKey_NormalizesText(t *testing.T) {
+	assert.Equal(t, cacheKey(Query{Text: "The Matrix", Type: "movie"}), cacheKey(Query{Text: "the matrix", Type: "movie"}))
+	assert.Equal(t, cacheKey(Query{Text: "Matrix", Type: "movie"}), cacheKey(Query{Text: "Matrix", Type: "movie", Season: new(int)}))
+	assert.NotEqual(t, cacheKey(Query{Text: "Matrix", Type: "movie"}), cacheKey(Query{Text: "Matrix", Type: "series"}))
+}
+
+func TestResultCache_Expires(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	c := NewResultCache(10*time.Minute, 10)
+	c.now = clock.now
+
+	c.Put("k", []Release{{GUID: "1"}})
+	clock.advance(9 * time.Minute)
+	releases, storedAt, ok := c.Get("k")
+	require.True(t, ok)
+	assert.Len(t, releases, 1)
+	assert.Equal(t, clock.t.Add(-9*time.Minute), storedAt)
+
+	clock.advance(time.Minute)
+	_, _, ok = c.Get("k")
+	assert.False(t, ok)
+	assert.Zero(t, c.Len(), "expired entry should be evicted on read")
+}
+
+func TestResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewResultCache(time.Hour, 2)
+	c.Put("a", nil)
+	c.Put("b", nil)
+	_, _, _ = c.Get("a") // b is now least recently used
+	c.Put("c", nil)
+
+	_, _, ok := c.Get("b")
+	assert.False(t, ok)
+	_, _, ok = c.Get("a")
+	assert.True(t, ok)
+	_, _, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestBudget_DailyLimit(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)}
+	b := NewBudget(map[string]int{"capped": 2})
+	b.now = clock.now
+
+	assert.True(t, b.Take("capped"))
+	assert.True(t, b.Take("capped"))
+	assert.False(t, b.Take("capped"))
+	assert.True(t, b.Take("unlimited"))
+	assert.Equal(t, map[string]int{"capped": 2, "unlimited": 1}, b.Usage())
+
+	// Resets at UTC midnight
+	clock.advance(time.Hour)
+	assert.True(t, b.Take("capped"))
+	assert.Equal(t, map[string]int{"capped": 1}, b.Usage())
+
+	var nilBudget *Budget
+	assert.True(t, nilBudget.Take("capped"))
+}
+
+func TestState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.json")
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	cache := NewResultCache(10*time.Minute, 10)
+	cache.now = clock.now
+	budget := NewBudget(map[string]int{"capped": 5})
+	budget.now = clock.now
+
+	cache.Put("old", []Release{{GUID: "1"}})
+	clock.advance(8 * time.Minute)
+	cache.Put("new", []Release{{GUID: "2", Title: "Movie.2024.1080p"}})
+	budget.Take("capped")
+	budget.Take("capped")
+	require.NoError(t, SaveState(path, cache, budget))
+
+	// Restart five minutes later: "old" has expired
+	clock.advance(5 * time.Minute)
+	cache2 := NewResultCache(10*time.Minute, 10)
+	cache2.now = clock.now
+	budget2 := NewBudget(map[string]int{"capped": 5})
+	budget2.now = clock.now
+	require.NoError(t, LoadState(path, cache2, budget2))
+
+	_, _, ok := cache2.Get("old")
+	assert.False(t, ok)
+	releases, _, ok := cache2.Get("new")
+	require.True(t, ok)
+	assert.Equal(t, "Movie.2024.1080p", releases[0].Title)
+	assert.Equal(t, 2, budget2.Usage()["capped"])
+
+	// Usage from an earlier day is dropped
+	clock.advance(24 * time.Hour)
+	budget3 := NewBudget(nil)
+	budget3.now = clock.now
+	require.NoError(t, LoadState(path, nil, budget3))
+	assert.Empty(t, budget3.Usage())
+}
+
+func TestLoadState_MissingFile(t *testing.T) {
+	assert.NoError(t, LoadState(filepath.Join(t.TempDir(), "missing.json"), NewResultCache(0, 0), NewBudget(nil)))
+}
@@ -0,0 +1,63 @@
+package search
+
+import "github.com/vmunix/arrgo/internal/events"
+
+// MaxRunnersUp is how many competing releases a grab decision records.
+const MaxRunnersUp = 5
+
+// NewGrabDecision records why chosen was picked from the releases in result,
+// which must be ranked best first.
+func NewGrabDecision(result *Result, chosen *Release, profile string) *events.GrabDecision {
+	d := &events.GrabDecision{
+		Profile:    profile,
+		Chosen:     scoredRelease(chosen),
+		Candidates: len(result.Releases),
+	}
+	for _, r := range result.Releases {
+		if len(d.RunnersUp) == MaxRunnersUp {
+			break
+		}
+		if r == chosen {
+			continue
+		}
+		d.RunnersUp = append(d.RunnersUp, scoredRelease(r))
+	}
+	return d
+}
+
+func scoredRelease(r *Release) events.ScoredRelease {
+	sr := events.ScoredRelease{
+		Title:     r.Title,
+		Indexer:   r.Indexer,
+		GUID:      r.GUID,
+		SizeBytes: r.Size,
+		Score:     r.Score,
+		Breakdown: r.Breakdown,
+	}
+	if r.Quality != nil {
+		sr.Group = r.Quality.Group
+	}
+	return sr
+}
+
+// GrabAlternates returns the releases after chosen in the result, best first,
+// as fallbacks for the grab. At most MaxRunnersUp are returned.
+func GrabAlternates(result *Result, chosen *Release) []events.GrabAlternate {
+	var alternates []events.GrabAlternate
+	for _, r := range result.Releases {
+		if len(alternates) == MaxRunnersUp {
+			break
+		}
+		if r == chosen {
+			continue
+		}
+		alternates = append(alternates, events.GrabAlternate{
+			DownloadURL: r.DownloadURL,
+			ReleaseName: r.Title,
+			Indexer:     r.Indexer,
+			GUID:        r.GUID,
+			Protocol:    string(r.Protocol),
+		})
+	}
+	return alternates
+}
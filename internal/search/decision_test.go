@@ -0,0 +1,70 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/pkg/release"
+	"github.com/vmunix/arrgo/pkg/release/scoring"
+)
+
+func TestNewGrabDecision(t *testing.T) {
+	result := &Result{}
+	for i := range 8 {
+		score := 100 - i*10
+		result.Releases = append(result.Releases, &Release{
+			Title:     fmt.Sprintf("Movie.2024.%d", i),
+			Indexer:   "nzbgeek",
+			Size:      int64(i + 1),
+			Score:     score,
+			Breakdown: scoring.Breakdown{Resolution: score, Total: score},
+		})
+	}
+
+	d := NewGrabDecision(result, result.Releases[0], "hd")
+	assert.Equal(t, "hd", d.Profile)
+	assert.Equal(t, 8, d.Candidates)
+	assert.Equal(t, "Movie.2024.0", d.Chosen.Title)
+	assert.Equal(t, 100, d.Chosen.Breakdown.Total)
+	require.Len(t, d.RunnersUp, MaxRunnersUp)
+	assert.Equal(t, "Movie.2024.1", d.RunnersUp[0].Title)
+	assert.Equal(t, 90, d.RunnersUp[0].Score)
+
+	// A chosen release other than the best is left out of the runners-up
+	d = NewGrabDecision(result, result.Releases[1], "hd")
+	assert.Equal(t, "Movie.2024.1", d.Chosen.Title)
+	require.Len(t, d.RunnersUp, MaxRunnersUp)
+	assert.Equal(t, "Movie.2024.0", d.RunnersUp[0].Title)
+	assert.Equal(t, "Movie.2024.2", d.RunnersUp[1].Title)
+
+	// The chosen release's group is recorded when it was parsed
+	result.Releases[0].Quality = release.Parse("Movie.2024.1080p.BluRay.x264-FraMeSToR")
+	d = NewGrabDecision(result, result.Releases[0], "hd")
+	assert.Equal(t, "FraMeSToR", d.Chosen.Group)
+	assert.Empty(t, d.RunnersUp[0].Group, "unparsed releases have no group")
+}
+
+func TestGrabAlternates(t *testing.T) {
+	result := &Result{}
+	for i := range 8 {
+		result.Releases = append(result.Releases, &Release{
+			Title:       fmt.Sprintf("Movie.2024.%d", i),
+			Indexer:     "nzbgeek",
+			GUID:        fmt.Sprintf("guid-%d", i),
+			DownloadURL: fmt.Sprintf("https://example.com/%d.nzb", i),
+			Protocol:    download.ProtocolUsenet,
+		})
+	}
+
+	alternates := GrabAlternates(result, result.Releases[0])
+	require.Len(t, alternates, MaxRunnersUp)
+	assert.Equal(t, "Movie.2024.1", alternates[0].ReleaseName)
+	assert.Equal(t, "https://example.com/1.nzb", alternates[0].DownloadURL)
+	assert.Equal(t, "guid-1", alternates[0].GUID)
+	assert.Equal(t, "usenet", alternates[0].Protocol)
+
+	assert.Empty(t, GrabAlternates(&Result{Releases: result.Releases[:1]}, result.Releases[0]))
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"sync"
+	"time"
+)
+
+// deferrals remembers, by query and profile, the releases a wanted search
+// held back for the minimum age, so a later search can still grab one once it
+// comes of age even if the indexers no longer return it.
+type deferrals struct {
+	mu    sync.Mutex
+	byKey map[string][]*Deferred
+}
+
+func newDeferrals() *deferrals {
+	return &deferrals{byKey: make(map[string][]*Deferred)}
+}
+
+// recall merges the releases remembered for key into result: those now old
+// enough join the releases, the others stay deferred. The result's deferred
+// releases are then remembered in their place. Reports whether anything was
+// merged, in which case the result needs sorting again.
+func (d *deferrals) recall(key string, result *Result, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	seen := make(map[string]bool, len(result.Releases)+len(result.Deferred))
+	for _, r := range result.Releases {
+		seen[r.GUID] = true
+	}
+	for _, r := range result.Deferred {
+		seen[r.Release.GUID] = true
+	}
+
+	merged := false
+	for _, r := range d.byKey[key] {
+		if seen[r.Release.GUID] {
+			continue
+		}
+		merged = true
+		if r.Until.After(now) {
+			result.Deferred = append(result.Deferred, r)
+		} else {
+			result.Releases = append(result.Releases, r.Release)
+		}
+	}
+
+	if len(result.Deferred) == 0 {
+		delete(d.byKey, key)
+	} else {
+		d.byKey[key] = append([]*Deferred(nil), result.Deferred...)
+	}
+	return merged
+}
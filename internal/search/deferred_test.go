@@ -0,0 +1,39 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeferrals_Recall(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	d := newDeferrals()
+
+	// First search: two new posts are deferred and remembered
+	first := &Result{Deferred: []*Deferred{
+		{Release: &Release{GUID: "a"}, Until: now.Add(10 * time.Minute)},
+		{Release: &Release{GUID: "b"}, Until: now.Add(time.Hour)},
+	}}
+	assert.False(t, d.recall("k", first, now), "nothing remembered yet")
+
+	// Later the indexers return neither; "a" has come of age
+	later := &Result{Releases: []*Release{{GUID: "c"}}}
+	assert.True(t, d.recall("k", later, now.Add(15*time.Minute)))
+	require.Len(t, later.Releases, 2)
+	assert.Equal(t, "a", later.Releases[1].GUID, "matured release joins the candidates")
+	require.Len(t, later.Deferred, 1)
+	assert.Equal(t, "b", later.Deferred[0].Release.GUID, "still too new")
+
+	// Releases the indexers still return aren't added twice
+	again := &Result{Deferred: []*Deferred{{Release: &Release{GUID: "b"}, Until: now.Add(time.Hour)}}}
+	assert.False(t, d.recall("k", again, now.Add(20*time.Minute)))
+	assert.Len(t, again.Deferred, 1)
+
+	// Other queries are remembered apart; nothing left deferred forgets the key
+	assert.False(t, d.recall("other", &Result{}, now))
+	assert.True(t, d.recall("k", &Result{}, now.Add(2*time.Hour)))
+	assert.Empty(t, d.byKey)
+}
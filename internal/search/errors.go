@@ -1,7 +1,10 @@
 // Package search handles indexer queries and release matching.
 package search
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	// ErrProwlarrUnavailable indicates Prowlarr could not be reached.
@@ -13,4 +16,28 @@ var (
 	// ErrNoResults indicates no matching releases were found.
 	// This is informational, not a failure.
 	ErrNoResults = errors.New("no matching releases found")
+
+	// ErrBudgetExhausted indicates an indexer was skipped because its daily
+	// call budget is used up.
+	ErrBudgetExhausted = errors.New("daily indexer call budget exhausted")
+
+	// ErrIndexerBackoff indicates an indexer was skipped because it recently
+	// asked to be retried later or rejected the API key.
+	ErrIndexerBackoff = errors.New("indexer backing off")
 )
+
+// IndexerError is a failed or skipped search of one indexer.
+type IndexerError struct {
+	Indexer    string
+	Code       int           // Newznab error code (429 for HTTP 429); 0 for other failures
+	RetryAfter time.Duration // Until the indexer is searched again; 0 if it isn't backing off
+	Err        error
+}
+
+func (e *IndexerError) Error() string {
+	return e.Indexer + ": " + e.Err.Error()
+}
+
+func (e *IndexerError) Unwrap() error {
+	return e.Err
+}
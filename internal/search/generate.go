@@ -1,3 +1,3 @@
 package search
 
-//go:generate mockgen -destination=mocks/mocks.go -package=mocks github.com/vmunix/arrgo/internal/search IndexerAPI
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks github.com/vmunix/arrgo/internal/search IndexerAPI,Blocklist,Aliases
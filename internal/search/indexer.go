@@ -3,10 +3,13 @@ package search
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/pkg/newznab"
 	"github.com/vmunix/arrgo/pkg/release"
 )
@@ -14,15 +17,95 @@ import (
 // ErrNoIndexers is returned when no indexers are configured.
 var ErrNoIndexers = errors.New("no indexers configured")
 
+// accountBackoff is how long an indexer that rejected the API key or
+// account is skipped.
+const accountBackoff = time.Hour
+
+// DefaultIndexerPriority is used for indexers without a configured priority.
+// As in Prowlarr, priorities range from 1 (most preferred) to 50.
+const DefaultIndexerPriority = 25
+
+// Indexer is a Newznab client with its search preferences.
+type Indexer struct {
+	*newznab.Client
+	priority   int
+	categories []string
+}
+
+// NewIndexer wraps a client with a priority and the content types it is searched for.
+// A zero priority uses DefaultIndexerPriority; empty categories means all content types.
+func NewIndexer(client *newznab.Client, priority int, categories []string) *Indexer {
+	if priority == 0 {
+		priority = DefaultIndexerPriority
+	}
+	return &Indexer{Client: client, priority: priority, categories: categories}
+}
+
+// Priority returns the indexer priority (lower is preferred).
+func (i *Indexer) Priority() int {
+	return i.priority
+}
+
+// Categories returns the content types the indexer is searched for.
+// Empty means all content types.
+func (i *Indexer) Categories() []string {
+	return i.categories
+}
+
+// Covers reports whether the indexer should be searched for the content type.
+func (i *Indexer) Covers(contentType string) bool {
+	return len(i.categories) == 0 || contentType == "" || slices.Contains(i.categories, contentType)
+}
+
 // IndexerPool manages multiple Newznab indexers and searches them in parallel.
+// An indexer that asks to be retried later, runs out of calls for the day,
+// or rejects the API key is skipped until then; other failures, such as
+// timeouts, don't hold it back.
 type IndexerPool struct {
-	clients []*newznab.Client
+	clients []*Indexer
+	budget  *Budget // nil if calls are unlimited
 	log     *slog.Logger
+	now     func() time.Time
+
+	mu      sync.Mutex
+	backoff map[string]indexerBackoff // By indexer name
+}
+
+// indexerBackoff is when a backed-off indexer may be searched again, and
+// the error code that backed it off.
+type indexerBackoff struct {
+	until time.Time
+	code  int
+}
+
+// NewIndexerPool creates a pool from the given indexers.
+func NewIndexerPool(clients []*Indexer, log *slog.Logger) *IndexerPool {
+	return &IndexerPool{clients: clients, log: log, now: time.Now, backoff: make(map[string]indexerBackoff)}
 }
 
-// NewIndexerPool creates a pool from the given clients.
-func NewIndexerPool(clients []*newznab.Client, log *slog.Logger) *IndexerPool {
-	return &IndexerPool{clients: clients, log: log}
+// SetBudget limits the daily calls made to each indexer.
+func (p *IndexerPool) SetBudget(b *Budget) {
+	p.budget = b
+}
+
+// Priorities returns the priority of each indexer by name.
+func (p *IndexerPool) Priorities() map[string]int {
+	priorities := make(map[string]int, len(p.clients))
+	for _, c := range p.clients {
+		priorities[c.Name()] = c.Priority()
+	}
+	return priorities
+}
+
+// GrabURL returns a release download URL with the named indexer's API key
+// that has the most grabs left. URLs of unknown indexers are returned as-is.
+func (p *IndexerPool) GrabURL(indexer, downloadURL string) string {
+	for _, c := range p.clients {
+		if c.Name() == indexer {
+			return c.GrabURL(downloadURL)
+		}
+	}
+	return downloadURL
 }
 
 // Search queries all indexers in parallel and merges results.
@@ -30,43 +113,118 @@ func NewIndexerPool(clients []*newznab.Client, log *slog.Logger) *IndexerPool {
 func (p *IndexerPool) Search(ctx context.Context, q Query) ([]Release, []error) {
 	// Normalize query for better indexer matching (e.g., & → and)
 	searchText := release.NormalizeSearchQuery(q.Text)
-	p.log.Debug("search started", "query", searchText, "original", q.Text, "type", q.Type, "indexers", len(p.clients))
 	start := time.Now()
 
 	if len(p.clients) == 0 {
 		return nil, []error{ErrNoIndexers}
 	}
 
-	// Determine categories based on content type
-	var categories []int
-	switch q.Type {
+	clients, errs := p.available(q.Type)
+	p.log.Debug("search started", "query", searchText, "original", q.Text, "type", q.Type, "indexers", len(clients))
+
+	categories := contentCategories(q.Type)
+	allReleases, queryErrs := p.gather(ctx, clients, func(ctx context.Context, c *Indexer) ([]newznab.Release, error) {
+		return c.Search(ctx, searchText, categories)
+	})
+	errs = append(errs, queryErrs...)
+
+	p.log.Info("search complete", "query", searchText, "results", len(allReleases), "errors", len(errs), "duration_ms", time.Since(start).Milliseconds())
+	return allReleases, errs
+}
+
+// recentLimit is how many of the newest releases are listed per indexer.
+const recentLimit = 100
+
+// ListRecent lists the newest releases of a content type from all indexers
+// in parallel, newest first per indexer. Only series are listed: indexers
+// offer a feed of recent releases through tvsearch. Like searches, each
+// listing counts against an indexer's daily budget.
+func (p *IndexerPool) ListRecent(ctx context.Context, contentType string) ([]Release, []error) {
+	if len(p.clients) == 0 {
+		return nil, []error{ErrNoIndexers}
+	}
+	start := time.Now()
+
+	clients, errs := p.available(contentType)
+	categories := contentCategories(contentType)
+	releases, queryErrs := p.gather(ctx, clients, func(ctx context.Context, c *Indexer) ([]newznab.Release, error) {
+		return c.ListRecent(ctx, categories, recentLimit)
+	})
+	errs = append(errs, queryErrs...)
+
+	p.log.Debug("recent releases listed", "type", contentType, "indexers", len(clients), "results", len(releases), "errors", len(errs), "duration_ms", time.Since(start).Milliseconds())
+	return releases, errs
+}
+
+// available returns the indexers to query for a content type, skipping
+// those restricted to other content types, backing off, or out of calls for
+// the day; skipped indexers other than by type are reported as errors.
+func (p *IndexerPool) available(contentType string) ([]*Indexer, []error) {
+	clients := make([]*Indexer, 0, len(p.clients))
+	var errs []error
+	for _, c := range p.clients {
+		if !c.Covers(contentType) {
+			p.log.Debug("indexer skipped", "indexer", c.Name(), "type", contentType, "categories", c.Categories())
+			continue
+		}
+		if wait, code := p.backingOff(c.Name()); wait > 0 {
+			p.log.Debug("indexer skipped", "indexer", c.Name(), "reason", "backing off", "retry_after", wait)
+			errs = append(errs, &IndexerError{Indexer: c.Name(), Code: code, RetryAfter: wait, Err: ErrIndexerBackoff})
+			continue
+		}
+		if !p.budget.Take(c.Name()) {
+			p.log.Warn("indexer skipped", "indexer", c.Name(), "reason", "daily budget exhausted", "limit", p.budget.Limit(c.Name()))
+			errs = append(errs, &IndexerError{
+				Indexer:    c.Name(),
+				RetryAfter: untilNextDay(p.now()),
+				Err:        fmt.Errorf("%w (%d calls)", ErrBudgetExhausted, p.budget.Limit(c.Name())),
+			})
+			continue
+		}
+		clients = append(clients, c)
+	}
+	return clients, errs
+}
+
+// contentCategories returns the Newznab categories of a content type; nil
+// searches all categories.
+func contentCategories(contentType string) []int {
+	switch contentType {
 	case "movie":
-		categories = []int{2000, 2010, 2020, 2030, 2040, 2045, 2050}
+		return []int{2000, 2010, 2020, 2030, 2040, 2045, 2050}
 	case "series":
-		categories = []int{5000, 5010, 5020, 5030, 5040, 5045, 5050, 5070}
+		return []int{5000, 5010, 5020, 5030, 5040, 5045, 5050, 5070}
+	case "audiobook":
+		return []int{3030}
 	}
+	return nil
+}
 
+// gather queries the indexers in parallel with fetch and merges their
+// releases, wrapping each failure in an *IndexerError.
+func (p *IndexerPool) gather(ctx context.Context, clients []*Indexer, fetch func(context.Context, *Indexer) ([]newznab.Release, error)) ([]Release, []error) {
 	type result struct {
+		indexer  string
 		releases []newznab.Release
 		err      error
 	}
 
-	results := make(chan result, len(p.clients))
+	results := make(chan result, len(clients))
 	var wg sync.WaitGroup
 
 	// Query all indexers in parallel
-	for _, client := range p.clients {
+	for _, client := range clients {
 		wg.Add(1)
-		go func(c *newznab.Client) {
+		go func(c *Indexer) {
 			defer wg.Done()
 			indexerStart := time.Now()
-			releases, err := c.Search(ctx, searchText, categories)
+			releases, err := fetch(ctx, c)
 			if err != nil {
 				p.log.Warn("indexer failed", "indexer", c.Name(), "error", err, "duration_ms", time.Since(indexerStart).Milliseconds())
 			} else {
 				p.log.Debug("indexer returned", "indexer", c.Name(), "results", len(releases), "duration_ms", time.Since(indexerStart).Milliseconds())
 			}
-			results <- result{releases: releases, err: err}
+			results <- result{indexer: c.Name(), releases: releases, err: err}
 		}(client)
 	}
 
@@ -79,10 +237,9 @@ func (p *IndexerPool) Search(ctx context.Context, q Query) ([]Release, []error)
 	// Collect results
 	var allReleases []Release
 	var errs []error
-
 	for r := range results {
 		if r.err != nil {
-			errs = append(errs, r.err)
+			errs = append(errs, p.indexerError(r.indexer, r.err))
 			continue
 		}
 		for _, nr := range r.releases {
@@ -93,10 +250,69 @@ func (p *IndexerPool) Search(ctx context.Context, q Query) ([]Release, []error)
 				Size:        nr.Size,
 				PublishDate: nr.PublishDate,
 				Indexer:     nr.Indexer,
+				Protocol:    releaseProtocol(&nr),
+				Seeders:     nr.Seeders,
+				Peers:       nr.Peers,
+				InfoHash:    nr.InfoHash,
 			})
 		}
 	}
-
-	p.log.Info("search complete", "query", searchText, "results", len(allReleases), "errors", len(errs), "duration_ms", time.Since(start).Milliseconds())
 	return allReleases, errs
 }
+
+// backingOff returns how long the indexer is still skipped for and the
+// error code that backed it off; 0 if it isn't.
+func (p *IndexerPool) backingOff(indexer string) (time.Duration, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b, ok := p.backoff[indexer]
+	if !ok {
+		return 0, 0
+	}
+	wait := b.until.Sub(p.now())
+	if wait <= 0 {
+		delete(p.backoff, indexer)
+		return 0, 0
+	}
+	return wait.Round(time.Second), b.code
+}
+
+// indexerError wraps a failed search of an indexer, backing the indexer off
+// when retrying soon is pointless: for the delay it asked for, until the
+// UTC day ends once every API key is out of calls, or for accountBackoff
+// when it rejects the key or account.
+func (p *IndexerPool) indexerError(indexer string, err error) *IndexerError {
+	ie := &IndexerError{Indexer: indexer, Err: err}
+	var apiErr *newznab.APIError
+	if errors.As(err, &apiErr) {
+		ie.Code, ie.RetryAfter = apiErr.Code, apiErr.RetryAfter
+	}
+	switch {
+	case ie.RetryAfter > 0:
+	case errors.Is(err, newznab.ErrLimitReached):
+		ie.RetryAfter = untilNextDay(p.now())
+	case apiErr != nil && apiErr.AccountRejected():
+		ie.RetryAfter = accountBackoff
+	}
+	if ie.RetryAfter > 0 {
+		p.mu.Lock()
+		p.backoff[indexer] = indexerBackoff{until: p.now().Add(ie.RetryAfter), code: ie.Code}
+		p.mu.Unlock()
+	}
+	return ie
+}
+
+// untilNextDay returns the time left in the UTC day, when daily quotas reset.
+func untilNextDay(now time.Time) time.Duration {
+	now = now.UTC()
+	return now.Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now)
+}
+
+// releaseProtocol maps an indexer result's download type to a download protocol.
+func releaseProtocol(nr *newznab.Release) download.Protocol {
+	if nr.IsTorrent() {
+		return download.ProtocolTorrent
+	}
+	return download.ProtocolUsenet
+}
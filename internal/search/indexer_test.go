@@ -0,0 +1,214 @@
+package search_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/search"
+	"github.com/vmunix/arrgo/pkg/newznab"
+)
+
+// newznabServer returns a test indexer that serves one release and counts requests.
+func newznabServer(t *testing.T, title string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0"?>
+<rss version="2.0"><channel><item><title>%s</title><guid>%s</guid><link>http://example.com/nzb</link></item></channel></rss>`, title, title)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestIndexerPool_SkipsIndexersOutsideCategories(t *testing.T) {
+	generalSrv, generalHits := newznabServer(t, "Movie.2024.1080p.BluRay.x264-GEN")
+	tvSrv, tvHits := newznabServer(t, "Movie.2024.1080p.WEB-DL.x264-TV")
+
+	pool := search.NewIndexerPool([]*search.Indexer{
+		search.NewIndexer(newznab.NewClient("general", generalSrv.URL, "key", nil), 0, nil),
+		search.NewIndexer(newznab.NewClient("tvonly", tvSrv.URL, "key", nil), 0, []string{"series"}),
+	}, testLogger())
+
+	releases, errs := pool.Search(context.Background(), search.Query{Text: "Movie", Type: "movie"})
+	require.Empty(t, errs)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "general", releases[0].Indexer)
+	assert.Equal(t, int32(1), generalHits.Load())
+	assert.Equal(t, int32(0), tvHits.Load(), "movie search must not query the TV-only indexer")
+
+	// Series searches use both
+	releases, errs = pool.Search(context.Background(), search.Query{Text: "Movie", Type: "series"})
+	require.Empty(t, errs)
+	assert.Len(t, releases, 2)
+	assert.Equal(t, int32(1), tvHits.Load())
+}
+
+func TestIndexerPool_CarriesTorznabAttrs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<?xml version="1.0"?>
+<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed"><channel><item>
+<title>Movie.2024.1080p.BluRay.x264-GROUP</title><guid>t1</guid><link>magnet:?xt=urn:btih:abc</link>
+<torznab:attr name="seeders" value="12" /><torznab:attr name="peers" value="15" /><torznab:attr name="infohash" value="abc" />
+</item></channel></rss>`)
+	}))
+	t.Cleanup(srv.Close)
+	nzbSrv, _ := newznabServer(t, "Movie.2024.1080p.WEB-DL.x264-NZB")
+
+	pool := search.NewIndexerPool([]*search.Indexer{
+		search.NewIndexer(newznab.NewClient("jackett", srv.URL, "key", nil), 0, nil),
+		search.NewIndexer(newznab.NewClient("nzbgeek", nzbSrv.URL, "key", nil), 0, nil),
+	}, testLogger())
+
+	releases, errs := pool.Search(context.Background(), search.Query{Text: "Movie", Type: "movie"})
+	require.Empty(t, errs)
+	require.Len(t, releases, 2)
+	for _, rel := range releases {
+		switch rel.Indexer {
+		case "jackett":
+			assert.Equal(t, download.ProtocolTorrent, rel.Protocol)
+			assert.Equal(t, 12, rel.Seeders)
+			assert.Equal(t, 15, rel.Peers)
+			assert.Equal(t, "abc", rel.InfoHash)
+		case "nzbgeek":
+			assert.Equal(t, download.ProtocolUsenet, rel.Protocol)
+		}
+	}
+}
+
+func TestIndexer_Defaults(t *testing.T) {
+	idx := search.NewIndexer(newznab.NewClient("test", "http://localhost", "key", nil), 0, nil)
+	assert.Equal(t, search.DefaultIndexerPriority, idx.Priority())
+	assert.True(t, idx.Covers("movie"))
+	assert.True(t, idx.Covers("series"))
+
+	idx = search.NewIndexer(newznab.NewClient("test", "http://localhost", "key", nil), 5, []string{"movie"})
+	assert.Equal(t, 5, idx.Priority())
+	assert.True(t, idx.Covers("movie"))
+	assert.False(t, idx.Covers("series"))
+	assert.True(t, idx.Covers(""), "untyped searches go to every indexer")
+}
+
+func TestIndexerPool_SkipsIndexersOverBudget(t *testing.T) {
+	cappedSrv, cappedHits := newznabServer(t, "Movie.2024.1080p.BluRay.x264-CAP")
+	freeSrv, freeHits := newznabServer(t, "Movie.2024.1080p.WEB-DL.x264-FREE")
+
+	pool := search.NewIndexerPool([]*search.Indexer{
+		search.NewIndexer(newznab.NewClient("capped", cappedSrv.URL, "key", nil), 0, nil),
+		search.NewIndexer(newznab.NewClient("free", freeSrv.URL, "key", nil), 0, nil),
+	}, testLogger())
+	pool.SetBudget(search.NewBudget(map[string]int{"capped": 1}))
+
+	releases, errs := pool.Search(context.Background(), search.Query{Text: "Movie"})
+	require.Empty(t, errs)
+	assert.Len(t, releases, 2)
+
+	releases, errs = pool.Search(context.Background(), search.Query{Text: "Movie"})
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], search.ErrBudgetExhausted)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "free", releases[0].Indexer)
+	assert.Equal(t, int32(1), cappedHits.Load())
+	assert.Equal(t, int32(2), freeHits.Load())
+}
+
+func TestIndexerPool_ListRecent(t *testing.T) {
+	tvSrv, tvHits := newznabServer(t, "Show.S01E02.1080p.WEB-DL.x264-TV")
+	movieSrv, movieHits := newznabServer(t, "Movie.2024.1080p.BluRay.x264-MOV")
+	cappedSrv, cappedHits := newznabServer(t, "Show.S01E02.720p.HDTV.x264-CAP")
+
+	pool := search.NewIndexerPool([]*search.Indexer{
+		search.NewIndexer(newznab.NewClient("tv", tvSrv.URL, "key", nil), 0, []string{"series"}),
+		search.NewIndexer(newznab.NewClient("movies", movieSrv.URL, "key", nil), 0, []string{"movie"}),
+		search.NewIndexer(newznab.NewClient("capped", cappedSrv.URL, "key", nil), 0, nil),
+	}, testLogger())
+	pool.SetBudget(search.NewBudget(map[string]int{"capped": 1}))
+
+	releases, errs := pool.ListRecent(context.Background(), "series")
+	require.Empty(t, errs)
+	assert.Len(t, releases, 2)
+	assert.Equal(t, int32(1), tvHits.Load())
+	assert.Equal(t, int32(0), movieHits.Load(), "movie-only indexers have no TV feed")
+
+	// Listings count against the daily budget like searches
+	releases, errs = pool.ListRecent(context.Background(), "series")
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], search.ErrBudgetExhausted)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "tv", releases[0].Indexer)
+	assert.Equal(t, int32(1), cappedHits.Load())
+}
+
+func TestIndexerPool_GrabURL(t *testing.T) {
+	pool := search.NewIndexerPool([]*search.Indexer{
+		search.NewIndexer(newznab.NewClient("nzbgeek", "https://api.nzbgeek.info", "key-a", nil, newznab.WithAPIKeys("key-b")), 0, nil),
+	}, testLogger())
+
+	url := "https://api.nzbgeek.info/api?apikey=key-a&id=abc&t=get"
+	assert.Equal(t, url, pool.GrabURL("nzbgeek", url), "first usable key with no reported quota")
+	assert.Equal(t, url, pool.GrabURL("unknown", url), "other indexers' URLs are unchanged")
+}
+
+func TestIndexerPool_BacksOffOnTypedErrors(t *testing.T) {
+	var throttledHits, rejectedHits, brokenHits atomic.Int32
+	throttled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		throttledHits.Add(1)
+		w.Header().Set("Retry-After", "600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(throttled.Close)
+	rejected := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		rejectedHits.Add(1)
+		_, _ = fmt.Fprint(w, `<error code="101" description="Account suspended"/>`)
+	}))
+	t.Cleanup(rejected.Close)
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		brokenHits.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(broken.Close)
+
+	pool := search.NewIndexerPool([]*search.Indexer{
+		search.NewIndexer(newznab.NewClient("throttled", throttled.URL, "key", nil), 0, nil),
+		search.NewIndexer(newznab.NewClient("rejected", rejected.URL, "key", nil), 0, nil),
+		search.NewIndexer(newznab.NewClient("broken", broken.URL, "key", nil), 0, nil),
+	}, testLogger())
+
+	indexerErrors := func(errs []error) map[string]*search.IndexerError {
+		out := make(map[string]*search.IndexerError)
+		for _, err := range errs {
+			var ie *search.IndexerError
+			require.ErrorAs(t, err, &ie)
+			out[ie.Indexer] = ie
+		}
+		return out
+	}
+
+	_, errs := pool.Search(context.Background(), search.Query{Text: "Movie"})
+	byIndexer := indexerErrors(errs)
+	require.Len(t, byIndexer, 3)
+	assert.Equal(t, newznab.ErrorCodeTooManyRequests, byIndexer["throttled"].Code)
+	assert.Equal(t, 10*time.Minute, byIndexer["throttled"].RetryAfter)
+	assert.Equal(t, newznab.ErrorCodeAccountSuspended, byIndexer["rejected"].Code)
+	assert.Equal(t, time.Hour, byIndexer["rejected"].RetryAfter)
+	assert.Zero(t, byIndexer["broken"].RetryAfter, "an untyped failure doesn't back off")
+
+	_, errs = pool.Search(context.Background(), search.Query{Text: "Movie"})
+	byIndexer = indexerErrors(errs)
+	require.Len(t, byIndexer, 3)
+	assert.ErrorIs(t, byIndexer["throttled"], search.ErrIndexerBackoff)
+	assert.Equal(t, newznab.ErrorCodeTooManyRequests, byIndexer["throttled"].Code, "skip carries the original code")
+	assert.Positive(t, byIndexer["throttled"].RetryAfter)
+	assert.ErrorIs(t, byIndexer["rejected"], search.ErrIndexerBackoff)
+	assert.Equal(t, int32(1), throttledHits.Load())
+	assert.Equal(t, int32(1), rejectedHits.Load())
+	assert.Equal(t, int32(2), brokenHits.Load(), "failing indexer is still searched")
+}
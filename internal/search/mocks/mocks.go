@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/vmunix/arrgo/internal/search (interfaces: IndexerAPI)
+// Source: github.com/vmunix/arrgo/internal/search (interfaces: IndexerAPI,Blocklist,Aliases)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mocks/mocks.go -package=mocks github.com/vmunix/arrgo/internal/search IndexerAPI
+//	mockgen -destination=mocks/mocks.go -package=mocks github.com/vmunix/arrgo/internal/search IndexerAPI,Blocklist,Aliases
 //
 
 // Package mocks is a generated GoMock package.
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	library "github.com/vmunix/arrgo/internal/library"
 	search "github.com/vmunix/arrgo/internal/search"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -55,3 +56,81 @@ func (mr *MockIndexerAPIMockRecorder) Search(ctx, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockIndexerAPI)(nil).Search), ctx, q)
 }
+
+// MockBlocklist is a mock of Blocklist interface.
+type MockBlocklist struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlocklistMockRecorder
+	isgomock struct{}
+}
+
+// MockBlocklistMockRecorder is the mock recorder for MockBlocklist.
+type MockBlocklistMockRecorder struct {
+	mock *MockBlocklist
+}
+
+// NewMockBlocklist creates a new mock instance.
+func NewMockBlocklist(ctrl *gomock.Controller) *MockBlocklist {
+	mock := &MockBlocklist{ctrl: ctrl}
+	mock.recorder = &MockBlocklistMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlocklist) EXPECT() *MockBlocklistMockRecorder {
+	return m.recorder
+}
+
+// IsBlocked mocks base method.
+func (m *MockBlocklist) IsBlocked(contentID int64, guid string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBlocked", contentID, guid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBlocked indicates an expected call of IsBlocked.
+func (mr *MockBlocklistMockRecorder) IsBlocked(contentID, guid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBlocked", reflect.TypeOf((*MockBlocklist)(nil).IsBlocked), contentID, guid)
+}
+
+// MockAliases is a mock of Aliases interface.
+type MockAliases struct {
+	ctrl     *gomock.Controller
+	recorder *MockAliasesMockRecorder
+	isgomock struct{}
+}
+
+// MockAliasesMockRecorder is the mock recorder for MockAliases.
+type MockAliasesMockRecorder struct {
+	mock *MockAliases
+}
+
+// NewMockAliases creates a new mock instance.
+func NewMockAliases(ctrl *gomock.Controller) *MockAliases {
+	mock := &MockAliases{ctrl: ctrl}
+	mock.recorder = &MockAliasesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAliases) EXPECT() *MockAliasesMockRecorder {
+	return m.recorder
+}
+
+// ListAliases mocks base method.
+func (m *MockAliases) ListAliases(contentID int64) ([]*library.Alias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAliases", contentID)
+	ret0, _ := ret[0].([]*library.Alias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAliases indicates an expected call of ListAliases.
+func (mr *MockAliasesMockRecorder) ListAliases(contentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAliases", reflect.TypeOf((*MockAliases)(nil).ListAliases), contentID)
+}
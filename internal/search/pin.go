@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// PinReader reads the release pinned for a content item.
+// Satisfied by *library.Store.
+type PinReader interface {
+	PinnedRelease(contentID int64) (*library.PinnedRelease, error)
+}
+
+// PinnedGrab returns a grab of the release pinned for a content item: a
+// season pack for a series pin. It carries no decision or alternates since
+// nothing was scored; if it fails the pin is dropped instead.
+func PinnedGrab(contentID int64, pin *library.PinnedRelease) *events.GrabRequested {
+	grab := &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   contentID,
+		DownloadURL: pin.DownloadURL,
+		ReleaseName: pin.Title,
+		Indexer:     pin.Indexer,
+		GUID:        pin.GUID,
+		Protocol:    pin.Protocol,
+		Pinned:      true,
+	}
+	if pin.Season != nil {
+		season := *pin.Season
+		grab.Season = &season
+		grab.IsCompleteSeason = true
+	}
+	return grab
+}
+
+// SetPins makes the searcher grab the release pinned for content, if any,
+// instead of searching for it.
+func (w *WantedSearcher) SetPins(p PinReader) {
+	w.pins = p
+}
+
+// grabPinned requests a grab of the release pinned for a content item's
+// season (nil for movies and audiobooks), reporting whether there was one.
+func (w *WantedSearcher) grabPinned(ctx context.Context, contentID int64, season *int) bool {
+	if w.pins == nil {
+		return false
+	}
+	pin, err := w.pins.PinnedRelease(contentID)
+	if err != nil {
+		w.log.Warn("read pinned release failed", "content_id", contentID, "error", err)
+		return false
+	}
+	if pin == nil || !pin.ForSeason(season) {
+		return false
+	}
+	if err := w.bus.Publish(ctx, PinnedGrab(contentID, pin)); err != nil {
+		w.log.Error("failed to publish GrabRequested", "content_id", contentID, "error", err)
+		return false
+	}
+	w.log.Info("grabbing pinned release", "content_id", contentID, "release", pin.Title)
+	return true
+}
+
+// RunPinFallback searches for the content of each failed pinned grab
+// received from failures, whose pins have been dropped, until the context is
+// canceled or failures is closed.
+func (w *WantedSearcher) RunPinFallback(ctx context.Context, failures <-chan events.Event) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e, ok := <-failures:
+			if !ok {
+				return nil
+			}
+			failed, ok := e.(*events.PinnedGrabFailed)
+			if !ok {
+				continue
+			}
+			n, err := w.SearchContent(ctx, failed.ContentID)
+			if err != nil {
+				w.log.Warn("search after failed pinned grab failed", "content_id", failed.ContentID, "error", err)
+				continue
+			}
+			w.log.Info("searched after failed pinned grab", "content_id", failed.ContentID, "release", failed.ReleaseName, "grabs", n)
+		}
+	}
+}
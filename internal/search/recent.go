@@ -0,0 +1,348 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/pkg/release"
+)
+
+// StrategyRecent is the strategy recorded on grabs by RecentSync.
+const StrategyRecent = "recent"
+
+// DefaultRecentWindow is how far back RecentSync looks for aired episodes.
+const DefaultRecentWindow = 72 * time.Hour
+
+// RecentLister lists the newest releases of the indexers.
+// Satisfied by *IndexerPool.
+type RecentLister interface {
+	ListRecent(ctx context.Context, contentType string) ([]Release, []error)
+}
+
+// RecentSync keeps up with airing series the way an RSS sync does: rather
+// than searching for each missing episode, it lists the indexers' newest TV
+// releases and grabs those of wanted episodes aired within the window.
+// Releases match a series only by its exact title or one of its aliases,
+// since the feed holds every show; season packs are left to the wanted
+// search. Matches go through the searcher's filters as in a search, and
+// episodes with an active download are skipped.
+type RecentSync struct {
+	searcher  *Searcher
+	feed      RecentLister
+	library   MissingLister
+	downloads DownloadLister
+	bus       Publisher
+	window    time.Duration
+	log       *slog.Logger
+}
+
+// NewRecentSync creates a recent sync over episodes aired within window;
+// zero uses DefaultRecentWindow.
+func NewRecentSync(searcher *Searcher, feed RecentLister, lib MissingLister, downloads DownloadLister, bus Publisher, window time.Duration, log *slog.Logger) *RecentSync {
+	if window <= 0 {
+		window = DefaultRecentWindow
+	}
+	return &RecentSync{
+		searcher:  searcher,
+		feed:      feed,
+		library:   lib,
+		downloads: downloads,
+		bus:       bus,
+		window:    window,
+		log:       log,
+	}
+}
+
+// recentShow is a series with wanted episodes aired within the window.
+type recentShow struct {
+	contentID int64
+	title     string
+	names     map[string]bool                // Normalized title and aliases
+	episodes  map[[2]int]*library.WantedItem // By season and episode
+	airDates  map[string]*library.WantedItem // By air date, for daily series
+}
+
+// recentMatch is the releases found for the same wanted episodes.
+type recentMatch struct {
+	show     *recentShow
+	items    []*library.WantedItem
+	releases []Release
+}
+
+// Sync lists the indexers' newest TV releases once and requests a grab of the
+// best release for each wanted episode aired within the window that one
+// matches. The indexers are not queried when no such episode is wanted.
+// Returns the number of grabs requested.
+func (r *RecentSync) Sync(ctx context.Context) (int, error) {
+	since := time.Now().Add(-r.window)
+	items, _, err := r.library.ListMissing(library.WantedFilter{AiredSince: &since})
+	if err != nil {
+		return 0, fmt.Errorf("list recent episodes: %w", err)
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	releases, errs := r.feed.ListRecent(ctx, string(library.ContentTypeSeries))
+	for _, err := range errs {
+		r.log.Warn("recent releases unavailable", "error", err)
+	}
+	if len(releases) == 0 {
+		return 0, nil
+	}
+
+	shows := r.recentShows(items)
+	matches := make(map[string]*recentMatch)
+	for _, rel := range releases {
+		info := release.Parse(rel.Title)
+		if info.IsCompleteSeason {
+			continue
+		}
+		show := matchShow(shows, info)
+		if show == nil {
+			continue
+		}
+		wanted := show.wantedEpisodes(info)
+		if len(wanted) == 0 {
+			continue
+		}
+		key := matchKey(wanted)
+		m := matches[key]
+		if m == nil {
+			m = &recentMatch{show: show, items: wanted}
+			matches[key] = m
+		}
+		m.releases = append(m.releases, rel)
+	}
+
+	// Single episodes first, so a multi-episode release only wins episodes
+	// no release of their own was grabbed for
+	ordered := make([]*recentMatch, 0, len(matches))
+	for _, m := range matches {
+		ordered = append(ordered, m)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if len(a.items) != len(b.items) {
+			return len(a.items) < len(b.items)
+		}
+		return *a.items[0].EpisodeID < *b.items[0].EpisodeID
+	})
+
+	grabbed := 0
+	taken := make(map[int64]bool)
+	for _, m := range ordered {
+		if ctx.Err() != nil {
+			return grabbed, ctx.Err()
+		}
+		if slices.ContainsFunc(m.items, func(item *library.WantedItem) bool { return taken[*item.EpisodeID] }) {
+			continue
+		}
+		if r.grab(ctx, m) {
+			grabbed++
+			for _, item := range m.items {
+				taken[*item.EpisodeID] = true
+			}
+		}
+	}
+	return grabbed, nil
+}
+
+// grab filters a match's releases as a search for its first episode would and
+// requests a grab of the best, reporting whether it did.
+func (r *RecentSync) grab(ctx context.Context, m *recentMatch) bool {
+	first := m.items[0]
+	ids := make([]int64, 0, len(m.items))
+	for _, item := range m.items {
+		ids = append(ids, *item.EpisodeID)
+	}
+	if r.downloading(first.ContentID, first.Season, ids) {
+		return false
+	}
+
+	season, episode := first.Season, first.Episode
+	q := ContentQuery(&library.Content{ID: first.ContentID, Type: library.ContentTypeSeries, Title: m.show.title}, &season, &episode)
+	result := &Result{}
+	r.searcher.filter(q, m.releases, first.QualityProfile, time.Now(), result)
+	if len(result.Releases) == 0 {
+		return false
+	}
+
+	best := result.Releases[0]
+	decision := NewGrabDecision(result, best, first.QualityProfile)
+	decision.Strategy = StrategyRecent
+	decision.StrategyReason = fmt.Sprintf("recent release: aired within %s", r.window)
+	if err := r.bus.Publish(ctx, &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   first.ContentID,
+		EpisodeID:   &ids[0],
+		EpisodeIDs:  ids,
+		Season:      &season,
+		DownloadURL: best.DownloadURL,
+		ReleaseName: best.Title,
+		Indexer:     best.Indexer,
+		GUID:        best.GUID,
+		Protocol:    string(best.Protocol),
+		Decision:    decision,
+		Alternates:  GrabAlternates(result, best),
+	}); err != nil {
+		r.log.Error("failed to publish GrabRequested", "content_id", first.ContentID, "error", err)
+		return false
+	}
+	r.log.Info("grabbing recent release", "content_id", first.ContentID, "season", season, "episode", episode, "release", best.Title)
+	return true
+}
+
+// downloading reports whether an active download of the series covers one of
+// the episodes: a download of one of them, of their season's pack, or of the
+// whole series. Downloads that could not be checked count as covering them.
+func (r *RecentSync) downloading(contentID int64, season int, episodeIDs []int64) bool {
+	active, _, err := r.downloads.List(download.Filter{ContentID: &contentID, Active: true})
+	if err != nil {
+		r.log.Warn("check active downloads failed", "content_id", contentID, "error", err)
+		return true
+	}
+	for _, dl := range active {
+		switch {
+		case dl.IsCompleteSeason:
+			if dl.Season == nil || *dl.Season == season {
+				return true
+			}
+		case len(dl.EpisodeIDs) > 0:
+			if slices.ContainsFunc(dl.EpisodeIDs, func(id int64) bool { return slices.Contains(episodeIDs, id) }) {
+				return true
+			}
+		case dl.EpisodeID != nil:
+			if slices.Contains(episodeIDs, *dl.EpisodeID) {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// recentShows groups wanted episodes by series, with the names releases of
+// each series may carry.
+func (r *RecentSync) recentShows(items []*library.WantedItem) []*recentShow {
+	byID := make(map[int64]*recentShow)
+	var shows []*recentShow
+	for _, item := range items {
+		if item.EpisodeID == nil {
+			continue
+		}
+		show := byID[item.ContentID]
+		if show == nil {
+			show = &recentShow{
+				contentID: item.ContentID,
+				title:     item.Title,
+				names:     map[string]bool{normalizeTitle(item.Title): true},
+				episodes:  make(map[[2]int]*library.WantedItem),
+				airDates:  make(map[string]*library.WantedItem),
+			}
+			for _, alias := range r.searcher.aliasTitles(item.ContentID) {
+				show.names[normalizeTitle(alias)] = true
+			}
+			byID[item.ContentID] = show
+			shows = append(shows, show)
+		}
+		show.episodes[[2]int{item.Season, item.Episode}] = item
+		if item.AirDate != nil {
+			show.airDates[item.AirDate.UTC().Format(time.DateOnly)] = item
+		}
+	}
+	return shows
+}
+
+// matchShow returns the show a release is of, or nil. The release title, with
+// or without the year it carries, must equal the show's title or an alias
+// once normalized: the feed holds every show, so "Fear the Walking Dead" must
+// not pass for "The Walking Dead".
+func matchShow(shows []*recentShow, info *release.Info) *recentShow {
+	if info.Title == "" {
+		return nil
+	}
+	names := []string{normalizeTitle(info.Title)}
+	if info.Year > 0 {
+		names = append(names, normalizeTitle(info.Title+" "+strconv.Itoa(info.Year)))
+	}
+	var match *recentShow
+	for _, show := range shows {
+		if !slices.ContainsFunc(names, func(n string) bool { return show.names[n] }) {
+			continue
+		}
+		if match != nil {
+			return nil // Ambiguous
+		}
+		match = show
+	}
+	return match
+}
+
+// wantedEpisodes returns the show's wanted episodes a release carries, by air
+// date for daily releases; nil unless every episode it carries is wanted.
+func (s *recentShow) wantedEpisodes(info *release.Info) []*library.WantedItem {
+	if info.DailyDate != "" {
+		if item := s.airDates[info.DailyDate]; item != nil {
+			return []*library.WantedItem{item}
+		}
+		return nil
+	}
+	episodes := info.Episodes
+	if len(episodes) == 0 && info.Episode > 0 {
+		episodes = []int{info.Episode}
+	}
+	if info.Season == 0 || len(episodes) == 0 {
+		return nil
+	}
+	items := make([]*library.WantedItem, 0, len(episodes))
+	for _, ep := range episodes {
+		item := s.episodes[[2]int{info.Season, ep}]
+		if item == nil {
+			return nil
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// matchKey identifies the set of episodes a release carries.
+func matchKey(items []*library.WantedItem) string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, strconv.FormatInt(*item.EpisodeID, 10))
+	}
+	return strings.Join(ids, ",")
+}
+
+// Run syncs recent releases every interval until the context is canceled.
+// The first sync runs after one interval, not at startup.
+func (r *RecentSync) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		n, err := r.Sync(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.log.Warn("recent sync failed", "error", err)
+		} else if n > 0 {
+			r.log.Info("recent sync requested grabs", "count", n)
+		}
+	}
+}
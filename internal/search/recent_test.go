@@ -0,0 +1,176 @@
+package search_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/search"
+	"github.com/vmunix/arrgo/internal/search/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeFeed struct {
+	releases []search.Release
+	calls    int
+}
+
+func (f *fakeFeed) ListRecent(_ context.Context, contentType string) ([]search.Release, []error) {
+	f.calls++
+	if contentType != "series" {
+		return nil, nil
+	}
+	return f.releases, nil
+}
+
+type fakeActive []*download.Download
+
+func (f fakeActive) List(filter download.Filter) ([]*download.Download, int, error) {
+	var out []*download.Download
+	for _, d := range f {
+		if filter.ContentID == nil || d.ContentID == *filter.ContentID {
+			out = append(out, d)
+		}
+	}
+	return out, len(out), nil
+}
+
+func recentEpisode(contentID, episodeID int64, title string, season, episode int, aired time.Time) *library.WantedItem {
+	return &library.WantedItem{
+		ContentID:      contentID,
+		Type:           library.ContentTypeSeries,
+		Title:          title,
+		QualityProfile: "hd",
+		EpisodeID:      &episodeID,
+		Season:         season,
+		Episode:        episode,
+		AirDate:        &aired,
+	}
+}
+
+func newRecentSearcher(ctrl *gomock.Controller) *search.Searcher {
+	scorer := search.NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+	searcher := search.NewSearcher(mocks.NewMockIndexerAPI(ctrl), scorer, testLogger())
+	aliases := mocks.NewMockAliases(ctrl)
+	aliases.EXPECT().ListAliases(gomock.Any()).DoAndReturn(func(contentID int64) ([]*library.Alias, error) {
+		if contentID == 2 {
+			return []*library.Alias{{ContentID: 2, Alias: "La Casa de Papel"}}, nil
+		}
+		return nil, nil
+	}).AnyTimes()
+	searcher.SetAliases(aliases)
+	blocklist := mocks.NewMockBlocklist(ctrl)
+	blocklist.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).DoAndReturn(func(_ int64, guid string) (bool, error) {
+		return guid == "blocked", nil
+	}).AnyTimes()
+	searcher.SetBlocklist(blocklist)
+	return searcher
+}
+
+func TestRecentSync_Sync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	aired := time.Now().Add(-24 * time.Hour)
+
+	lib := &fakeMissing{items: []*library.WantedItem{
+		recentEpisode(1, 101, "The Walking Dead", 11, 5, aired),
+		recentEpisode(1, 102, "The Walking Dead", 11, 6, aired),
+		recentEpisode(2, 201, "Money Heist", 1, 1, aired),
+		recentEpisode(3, 301, "The Daily Show", aired.Year(), 40, aired),
+		recentEpisode(4, 401, "Severance", 2, 1, aired),
+		recentEpisode(4, 402, "Severance", 2, 2, aired),
+	}}
+	feed := &fakeFeed{releases: []search.Release{
+		{Title: "Fear.the.Walking.Dead.S11E05.1080p.WEB-DL.x264-GRP", GUID: "fear", Indexer: "nzbgeek"},
+		{Title: "The.Walking.Dead.S11E05.720p.WEB-DL.x264-GRP", GUID: "twd-720", Indexer: "nzbgeek"},
+		{Title: "The.Walking.Dead.S11E05.1080p.WEB-DL.x264-GRP", GUID: "twd", Indexer: "nzbgeek", DownloadURL: "http://nzb/twd"},
+		{Title: "The.Walking.Dead.S11E06.1080p.WEB-DL.x264-GRP", GUID: "blocked", Indexer: "nzbgeek"},
+		{Title: "The.Walking.Dead.S11E07.1080p.WEB-DL.x264-GRP", GUID: "unwanted", Indexer: "nzbgeek"},
+		{Title: "The.Walking.Dead.S11.1080p.WEB-DL.x264-GRP", GUID: "pack", Indexer: "nzbgeek"},
+		{Title: "La.Casa.de.Papel.S01E01.1080p.WEB-DL.x264-GRP", GUID: "alias", Indexer: "nzbgeek"},
+		{Title: "The.Daily.Show." + aired.Format("2006.01.02") + ".1080p.WEB-DL.x264-GRP", GUID: "daily", Indexer: "nzbgeek"},
+		{Title: "Severance.S02E01.1080p.WEB-DL.x264-GRP", GUID: "sev-1", Indexer: "nzbgeek"},
+		{Title: "Severance.S02E01E02.1080p.WEB-DL.x264-GRP", GUID: "sev-multi", Indexer: "nzbgeek"},
+	}}
+	bus := &fakePublisher{}
+	r := search.NewRecentSync(newRecentSearcher(ctrl), feed, lib, fakeActive{}, bus, 0, testLogger())
+
+	n, err := r.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, 1, feed.calls)
+
+	grabs := make(map[string]*events.GrabRequested)
+	for _, e := range bus.events {
+		grab, ok := e.(*events.GrabRequested)
+		require.True(t, ok)
+		grabs[grab.GUID] = grab
+	}
+	require.Len(t, grabs, 4)
+
+	twd := grabs["twd"]
+	require.NotNil(t, twd, "best quality of the episode's releases")
+	assert.Equal(t, int64(1), twd.ContentID)
+	assert.Equal(t, "http://nzb/twd", twd.DownloadURL)
+	require.NotNil(t, twd.Season)
+	assert.Equal(t, 11, *twd.Season)
+	assert.Equal(t, []int64{101}, twd.EpisodeIDs)
+	assert.Equal(t, search.StrategyRecent, twd.Decision.Strategy)
+
+	require.NotNil(t, grabs["alias"])
+	assert.Equal(t, []int64{201}, grabs["alias"].EpisodeIDs)
+	require.NotNil(t, grabs["daily"])
+	assert.Equal(t, []int64{301}, grabs["daily"].EpisodeIDs)
+
+	// The single episode wins; the multi-episode release would grab it twice
+	require.NotNil(t, grabs["sev-1"])
+	assert.Equal(t, []int64{401}, grabs["sev-1"].EpisodeIDs)
+}
+
+func TestRecentSync_Sync_NothingWanted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	feed := &fakeFeed{}
+	bus := &fakePublisher{}
+	r := search.NewRecentSync(newRecentSearcher(ctrl), feed, &fakeMissing{}, fakeActive{}, bus, time.Hour, testLogger())
+
+	n, err := r.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Zero(t, feed.calls, "indexers are not queried")
+	assert.Empty(t, bus.events)
+}
+
+func TestRecentSync_Sync_Downloading(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	aired := time.Now().Add(-time.Hour)
+	season := 1
+	episodeID := int64(11)
+
+	lib := &fakeMissing{items: []*library.WantedItem{
+		recentEpisode(1, 11, "Andor", 1, 1, aired),
+		recentEpisode(1, 12, "Andor", 1, 2, aired),
+		recentEpisode(2, 21, "Shogun", 1, 1, aired),
+	}}
+	feed := &fakeFeed{releases: []search.Release{
+		{Title: "Andor.S01E01.1080p.WEB-DL.x264-GRP", GUID: "a1", Indexer: "nzbgeek"},
+		{Title: "Andor.S01E02.1080p.WEB-DL.x264-GRP", GUID: "a2", Indexer: "nzbgeek"},
+		{Title: "Shogun.S01E01.1080p.WEB-DL.x264-GRP", GUID: "s1", Indexer: "nzbgeek"},
+	}}
+	active := fakeActive{
+		{ContentID: 1, Season: &season, EpisodeID: &episodeID, EpisodeIDs: []int64{episodeID}},
+		{ContentID: 2, Season: &season, IsCompleteSeason: true},
+	}
+	bus := &fakePublisher{}
+	r := search.NewRecentSync(newRecentSearcher(ctrl), feed, lib, active, bus, 0, testLogger())
+
+	n, err := r.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, bus.events, 1)
+	assert.Equal(t, "a2", bus.events[0].(*events.GrabRequested).GUID, "only the episode not downloading")
+}
@@ -1,57 +1,410 @@
 package search
 
 import (
+	"fmt"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
 	"github.com/vmunix/arrgo/pkg/release"
 	"github.com/vmunix/arrgo/pkg/release/scoring"
 )
 
 // Scorer scores releases against quality profiles.
 type Scorer struct {
-	profiles map[string]config.QualityProfile
+	profiles   ProfileSource
+	priorities map[string]int             // indexer name -> priority (lower is preferred)
+	minSeeders int                        // Default minimum seeders for torrents; profiles may override
+	protocols  map[download.Protocol]bool // Protocols with a download client; nil accepts any
+
+	minAge        time.Duration            // Default minimum age of usenet releases
+	indexerMinAge map[string]time.Duration // indexer name -> minimum age, overriding minAge
+}
+
+// ProfileSource looks up quality profiles by name.
+type ProfileSource interface {
+	Profile(name string) (config.QualityProfile, bool)
+}
+
+// staticProfiles is a fixed set of profiles, such as those from config.
+type staticProfiles map[string]config.QualityProfile
+
+func (p staticProfiles) Profile(name string) (config.QualityProfile, bool) {
+	profile, ok := p[name]
+	return profile, ok
 }
 
 // NewScorer creates a new Scorer from config profiles.
 func NewScorer(profiles map[string]config.QualityProfile) *Scorer {
+	return NewScorerFrom(staticProfiles(profiles))
+}
+
+// NewScorerFrom creates a new Scorer that looks profiles up in src each time
+// it scores, so edits to stored profiles apply from the next search.
+func NewScorerFrom(src ProfileSource) *Scorer {
 	return &Scorer{
-		profiles: profiles,
+		profiles: src,
+	}
+}
+
+// SetIndexerPriorities configures the indexer priorities used to break score ties.
+// Indexers missing from the map use DefaultIndexerPriority.
+func (s *Scorer) SetIndexerPriorities(priorities map[string]int) {
+	s.priorities = priorities
+}
+
+// SetMinSeeders configures the minimum seeders for torrent releases
+// in profiles that don't set their own.
+func (s *Scorer) SetMinSeeders(n int) {
+	s.minSeeders = n
+}
+
+// SetMinimumAge configures how old a usenet release must be before it may be
+// grabbed. Indexers in perIndexer use their own minimum instead.
+func (s *Scorer) SetMinimumAge(minAge time.Duration, perIndexer map[string]time.Duration) {
+	s.minAge = minAge
+	s.indexerMinAge = perIndexer
+}
+
+// DeferUntil returns when a usenet release reaches its indexer's minimum age,
+// or the zero time if it may be grabbed now. New posts are often incomplete
+// or taken down within minutes. Torrents and releases without a publish
+// date always pass.
+func (s *Scorer) DeferUntil(rel *Release, now time.Time) time.Time {
+	if rel.Protocol == download.ProtocolTorrent || rel.PublishDate.IsZero() {
+		return time.Time{}
+	}
+	minAge := s.minAge
+	if d, ok := s.indexerMinAge[rel.Indexer]; ok && d > 0 {
+		minAge = d
+	}
+	until := rel.PublishDate.Add(minAge)
+	if minAge <= 0 || !until.After(now) {
+		return time.Time{}
+	}
+	return until
+}
+
+// SetProtocols configures the protocols that have a download client. Releases
+// of other protocols are rejected, since grabbing them can only fail. Without
+// it, or with none, every protocol is accepted.
+func (s *Scorer) SetProtocols(protocols []download.Protocol) {
+	s.protocols = nil
+	if len(protocols) == 0 {
+		return
+	}
+	s.protocols = make(map[download.Protocol]bool, len(protocols))
+	for _, p := range protocols {
+		s.protocols[p] = true
+	}
+}
+
+// CheckProtocol returns a non-empty rejection reason if no download client
+// handles the release's protocol.
+func (s *Scorer) CheckProtocol(rel *Release) string {
+	if s.protocols == nil || s.protocols[rel.Protocol] {
+		return ""
+	}
+	return fmt.Sprintf("no %s download client configured", rel.Protocol)
+}
+
+// CheckSeeders returns a non-empty rejection reason if a torrent release has
+// fewer seeders than the profile requires. Usenet releases always pass.
+func (s *Scorer) CheckSeeders(rel *Release, profile string) string {
+	if rel.Protocol != download.ProtocolTorrent {
+		return ""
+	}
+	minSeeders := s.minSeeders
+	if p, ok := s.profiles.Profile(profile); ok && p.MinSeeders > 0 {
+		minSeeders = p.MinSeeders
+	}
+	if rel.Seeders < minSeeders {
+		return fmt.Sprintf("%d seeders, minimum %d", rel.Seeders, minSeeders)
+	}
+	return ""
+}
+
+// CheckSize returns a non-empty rejection reason if a release of size bytes
+// is outside the profile's size bounds. A season pack is measured per episode,
+// dividing its size by the episodes it carries. Unknown sizes always pass.
+func (s *Scorer) CheckSize(size int64, episodes int, profile string) string {
+	p, ok := s.profiles.Profile(profile)
+	if !ok || size <= 0 || (p.MinSizeMB <= 0 && p.MaxSizeMB <= 0) {
+		return ""
+	}
+	per := ""
+	if episodes > 1 {
+		size /= int64(episodes)
+		per = fmt.Sprintf(" per episode (%d episodes)", episodes)
+	}
+	mb := size / (1 << 20)
+	if p.MaxSizeMB > 0 && mb > int64(p.MaxSizeMB) {
+		return fmt.Sprintf("size %d MB%s, maximum %d MB", mb, per, p.MaxSizeMB)
+	}
+	if p.MinSizeMB > 0 && mb < int64(p.MinSizeMB) {
+		return fmt.Sprintf("size %d MB%s, minimum %d MB", mb, per, p.MinSizeMB)
+	}
+	return ""
+}
+
+// indexerPriority returns the configured priority for an indexer.
+func (s *Scorer) indexerPriority(name string) int {
+	if p, ok := s.priorities[name]; ok && p > 0 {
+		return p
 	}
+	return DefaultIndexerPriority
 }
 
-// Score returns the quality score for a release in the given profile.
-func (s *Scorer) Score(info release.Info, profile string) int {
-	p, ok := s.profiles[profile]
+// Better reports whether release a ranks ahead of release b in the profile.
+// Higher scores win; equal scores go to the profile's preferred protocol,
+// then to the higher-priority indexer.
+func (s *Scorer) Better(a, b *Release, profile string) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Protocol != b.Protocol {
+		if p, ok := s.profiles.Profile(profile); ok && p.PreferredProtocol != "" {
+			preferred := download.Protocol(p.PreferredProtocol)
+			if a.Protocol == preferred || b.Protocol == preferred {
+				return a.Protocol == preferred
+			}
+		}
+	}
+	return s.indexerPriority(a.Indexer) < s.indexerPriority(b.Indexer)
+}
+
+// MeetsCutoff reports whether a file of fileQuality meets the cutoff of the
+// profile, so its content is done (see CutoffMet). Any file meets the cutoff
+// of an unknown profile.
+func (s *Scorer) MeetsCutoff(fileQuality, profile string) bool {
+	p, ok := s.profiles.Profile(profile)
 	if !ok {
-		return 0
+		return fileQuality != ""
+	}
+	return CutoffMet(fileQuality, p)
+}
+
+// HasCutoff reports whether the profile sets a cutoff, so content whose file
+// is below it is searched for again.
+func (s *Scorer) HasCutoff(profile string) bool {
+	p, ok := s.profiles.Profile(profile)
+	return ok && strings.TrimSpace(p.Cutoff) != ""
+}
+
+// CutoffMet reports whether a file of fileQuality, a resolution optionally
+// followed by a source ("1080p webdl"), meets the cutoff of p. A higher
+// resolution than the cutoff's meets it; at the same resolution the file's
+// source must rank no lower in p.Sources, and a file of unknown source is
+// taken to meet it. No file meets a cutoff; any file meets a profile without one.
+func CutoffMet(fileQuality string, p config.QualityProfile) bool {
+	if fileQuality == "" {
+		return false
+	}
+	cutoff := p.CutoffQuality()
+	if cutoff == "" {
+		return true
+	}
+	res, source := splitQuality(fileQuality)
+	cutRes, cutSource := splitQuality(cutoff)
+	rank, cutRank := library.QualityRank(res), library.QualityRank(cutRes)
+	if rank != cutRank || cutSource == "" || source == "" {
+		return rank >= cutRank
+	}
+	i := slices.IndexFunc(p.Sources, func(s string) bool { return strings.EqualFold(s, source) })
+	return i >= 0 && i <= slices.IndexFunc(p.Sources, func(s string) bool { return strings.EqualFold(s, cutSource) })
+}
+
+// splitQuality splits a quality such as "1080p webdl" into its lowercased
+// resolution and source; source is empty if there is none.
+func splitQuality(quality string) (resolution, source string) {
+	fields := strings.Fields(strings.ToLower(quality))
+	if len(fields) > 0 {
+		resolution = fields[0]
+	}
+	if len(fields) > 1 {
+		source = fields[1]
+	}
+	return resolution, source
+}
+
+// Score returns the quality score for a release in the given profile, split by
+// the profile rule that earned each part. Keywords are scored by MatchKeywords.
+// Total is 0 if the profile rejects the release.
+func (s *Scorer) Score(info release.Info, profile string) scoring.Breakdown {
+	p, ok := s.profiles.Profile(profile)
+	if !ok {
+		return scoring.Breakdown{}
 	}
 
 	// Check reject list first
 	if scoring.MatchesRejectList(info, p.Reject) {
-		return 0
+		return scoring.Breakdown{}
 	}
 
 	// Check resolution requirement
 	baseScore := calculateBaseScore(info, p.Resolution)
 	if baseScore == 0 {
-		return 0
+		return scoring.Breakdown{}
 	}
 
-	score := baseScore
-
 	// Add bonuses for matching attributes
-	score += calculatePositionBonus(info.Source.String(), p.Sources, scoring.BonusSource)
-	score += calculatePositionBonus(info.Codec.String(), p.Codecs, scoring.BonusCodec)
-	score += calculateHDRBonus(info.HDR, p.HDR)
-	score += calculateAudioBonus(info.Audio, p.Audio)
+	b := scoring.Breakdown{
+		Resolution: baseScore,
+		Source:     calculatePositionBonus(info.Source.String(), p.Sources, scoring.BonusSource),
+		Codec:      calculatePositionBonus(info.Codec.String(), p.Codecs, scoring.BonusCodec),
+		HDR:        calculateHDRBonus(info.HDR, p.HDR),
+		Audio:      calculateAudioBonus(info.Audio, p.Audio),
+	}
 
 	// Remux bonus
 	if p.PreferRemux && info.IsRemux {
-		score += scoring.BonusRemux
+		b.Remux = scoring.BonusRemux
+	}
+
+	// Language bonus by the most preferred language the release carries
+	langs := releaseLanguages(info, p)
+	for _, pref := range p.Languages {
+		if slices.Contains(langs, strings.ToLower(pref)) {
+			b.Language = calculatePositionBonus(pref, p.Languages, scoring.BonusLanguage)
+			break
+		}
+	}
+
+	// Group bonus by the tier of preferred groups the release's group is in
+	b.Group = calculateGroupBonus(info.Group, p.PreferredGroups)
+
+	b.Total = b.Resolution + b.Source + b.Codec + b.HDR + b.Audio + b.Remux + b.Language + b.Group
+	return b
+}
+
+// CheckLanguage applies the profile's language lists to a release. It returns
+// the audio languages the release is taken to carry and a non-empty rejection
+// reason if, once excluded languages are dropped, none of them is wanted.
+func (s *Scorer) CheckLanguage(info release.Info, profile string) ([]string, string) {
+	p, ok := s.profiles.Profile(profile)
+	if !ok {
+		return nil, ""
+	}
+	langs := releaseLanguages(info, p)
+	if len(p.Languages) == 0 && len(p.ExcludeLanguages) == 0 {
+		return langs, ""
+	}
+
+	var kept []string
+	for _, lang := range langs {
+		if !containsFold(p.ExcludeLanguages, lang) {
+			kept = append(kept, lang)
+		}
+	}
+	if len(kept) == 0 {
+		return langs, fmt.Sprintf("language %s excluded", strings.Join(langs, ", "))
 	}
+	if len(p.Languages) == 0 {
+		return langs, ""
+	}
+	for _, lang := range kept {
+		if containsFold(p.Languages, lang) {
+			return langs, ""
+		}
+	}
+	return langs, fmt.Sprintf("language %s not wanted", strings.Join(kept, ", "))
+}
+
+// CheckGroup returns a non-empty rejection reason if the release's group is
+// banned by the profile. Releases without a group always pass.
+func (s *Scorer) CheckGroup(info release.Info, profile string) string {
+	p, ok := s.profiles.Profile(profile)
+	if !ok || info.Group == "" {
+		return ""
+	}
+	if containsFold(p.BannedGroups, info.Group) {
+		return fmt.Sprintf("banned group %q", info.Group)
+	}
+	return ""
+}
 
-	return score
+// releaseLanguages returns the audio languages a release is taken to carry:
+// those it names, plus the profile's MULTi set for MULTi releases.
+// An Info without languages (not from Parse) counts as English.
+func releaseLanguages(info release.Info, p config.QualityProfile) []string {
+	langs := slices.Clone(info.Languages)
+	if info.Multi {
+		multi := p.MultiLanguages
+		if len(multi) == 0 {
+			multi = scoring.DefaultMultiLanguages
+		}
+		for _, lang := range multi {
+			if lang = strings.ToLower(lang); !slices.Contains(langs, lang) {
+				langs = append(langs, lang)
+			}
+		}
+	}
+	if len(langs) == 0 {
+		langs = []string{release.LanguageEnglish}
+	}
+	return langs
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// MatchKeywords applies the profile's keyword lists to a release title.
+// Returns the summed weight of matching preferred keywords, or a non-empty
+// rejection reason if the title contains a forbidden keyword or lacks a required one.
+func (s *Scorer) MatchKeywords(title, profile string) (int, string) {
+	p, ok := s.profiles.Profile(profile)
+	if !ok {
+		return 0, ""
+	}
+
+	words := titleWords(title)
+	for _, kw := range p.Forbidden {
+		if containsKeyword(words, kw) {
+			return 0, fmt.Sprintf("forbidden keyword %q", kw)
+		}
+	}
+	for _, kw := range p.Required {
+		if !containsKeyword(words, kw) {
+			return 0, fmt.Sprintf("missing required keyword %q", kw)
+		}
+	}
+
+	bonus := 0
+	for _, pk := range p.Preferred {
+		if containsKeyword(words, pk.Keyword) {
+			bonus += pk.Weight
+		}
+	}
+	return bonus, ""
+}
+
+// titleWords splits a title into lowercase words on any non-alphanumeric character.
+// "Movie.2024.DTS-HD.MA-FLUX" -> [movie 2024 dts hd ma flux]
+func titleWords(title string) []string {
+	return strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsKeyword reports whether the keyword's words appear consecutively in words.
+// Matching whole words keeps "CAM" from matching "Camera".
+func containsKeyword(words []string, keyword string) bool {
+	kw := titleWords(keyword)
+	if len(kw) == 0 {
+		return false
+	}
+	for i := 0; i+len(kw) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(kw)], kw) {
+			return true
+		}
+	}
+	return false
 }
 
 // calculateBaseScore returns the base score for a resolution.
@@ -90,6 +443,25 @@ func calculatePositionBonus(value string, preferences []string, baseBonus int) i
 	return 0
 }
 
+// calculateGroupBonus calculates bonus for the preferred group tier a release group is in.
+func calculateGroupBonus(group string, tiers [][]string) int {
+	if group == "" {
+		return 0
+	}
+
+	for i, tier := range tiers {
+		if containsFold(tier, group) {
+			multiplier := 1.0 - 0.2*float64(i)
+			if multiplier < 0 {
+				multiplier = 0
+			}
+			return int(float64(scoring.BonusGroup) * multiplier)
+		}
+	}
+
+	return 0
+}
+
 // calculateHDRBonus calculates bonus for HDR format matching.
 func calculateHDRBonus(hdr release.HDRFormat, preferences []string) int {
 	if len(preferences) == 0 || hdr == release.HDRNone {
@@ -2,9 +2,11 @@ package search
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/pkg/release"
 	"github.com/vmunix/arrgo/pkg/release/scoring"
 )
@@ -30,7 +32,7 @@ func TestScorer_Score_BaseResolution(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			info := release.Info{Resolution: tt.resolution}
-			got := scorer.Score(info, "any")
+			got := scorer.Score(info, "any").Total
 			assert.Equal(t, tt.wantScore, got, "Score()")
 		})
 	}
@@ -57,7 +59,7 @@ func TestScorer_Score_ResolutionMustMatch(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			info := release.Info{Resolution: tt.resolution}
-			got := scorer.Score(info, "hd-only")
+			got := scorer.Score(info, "hd-only").Total
 			assert.Equal(t, tt.wantScore, got, "Score()")
 		})
 	}
@@ -96,7 +98,7 @@ func TestScorer_Score_SourceBonus(t *testing.T) {
 				Resolution: release.Resolution1080p,
 				Source:     tt.source,
 			}
-			got := scorer.Score(info, "hd")
+			got := scorer.Score(info, "hd").Total
 			want := baseScore + tt.wantBonus
 			assert.Equal(t, want, got, "Score() (base=%d + bonus=%d)", baseScore, tt.wantBonus)
 		})
@@ -133,7 +135,7 @@ func TestScorer_Score_CodecBonus(t *testing.T) {
 				Resolution: release.Resolution1080p,
 				Codec:      tt.codec,
 			}
-			got := scorer.Score(info, "hd")
+			got := scorer.Score(info, "hd").Total
 			want := baseScore + tt.wantBonus
 			assert.Equal(t, want, got, "Score() (base=%d + bonus=%d)", baseScore, tt.wantBonus)
 		})
@@ -173,7 +175,7 @@ func TestScorer_Score_HDRBonus(t *testing.T) {
 				Resolution: release.Resolution2160p,
 				HDR:        tt.hdr,
 			}
-			got := scorer.Score(info, "uhd")
+			got := scorer.Score(info, "uhd").Total
 			want := baseScore + tt.wantBonus
 			assert.Equal(t, want, got, "Score() (base=%d + bonus=%d)", baseScore, tt.wantBonus)
 		})
@@ -213,7 +215,7 @@ func TestScorer_Score_AudioBonus(t *testing.T) {
 				Resolution: release.Resolution1080p,
 				Audio:      tt.audio,
 			}
-			got := scorer.Score(info, "hd")
+			got := scorer.Score(info, "hd").Total
 			want := baseScore + tt.wantBonus
 			assert.Equal(t, want, got, "Score() (base=%d + bonus=%d)", baseScore, tt.wantBonus)
 		})
@@ -240,7 +242,7 @@ func TestScorer_Score_RemuxBonus(t *testing.T) {
 			Resolution: release.Resolution1080p,
 			IsRemux:    true,
 		}
-		got := scorer.Score(info, "remux-preferred")
+		got := scorer.Score(info, "remux-preferred").Total
 		want := baseScore + 20 // remux bonus
 		assert.Equal(t, want, got, "Score()")
 	})
@@ -250,7 +252,7 @@ func TestScorer_Score_RemuxBonus(t *testing.T) {
 			Resolution: release.Resolution1080p,
 			IsRemux:    false,
 		}
-		got := scorer.Score(info, "remux-preferred")
+		got := scorer.Score(info, "remux-preferred").Total
 		assert.Equal(t, baseScore, got, "Score()")
 	})
 
@@ -259,7 +261,7 @@ func TestScorer_Score_RemuxBonus(t *testing.T) {
 			Resolution: release.Resolution1080p,
 			IsRemux:    true,
 		}
-		got := scorer.Score(info, "no-remux-pref")
+		got := scorer.Score(info, "no-remux-pref").Total
 		assert.Equal(t, baseScore, got, "Score()")
 	})
 }
@@ -307,7 +309,7 @@ func TestScorer_Score_RejectList(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := scorer.Score(tt.info, "hd")
+			got := scorer.Score(tt.info, "hd").Total
 			if tt.reject {
 				assert.Equal(t, 0, got, "Score() should be 0 (rejected)")
 			} else {
@@ -317,6 +319,236 @@ func TestScorer_Score_RejectList(t *testing.T) {
 	}
 }
 
+func TestScorer_MatchKeywords(t *testing.T) {
+	profiles := map[string]config.QualityProfile{
+		"hd": {
+			Resolution: []string{"1080p", "720p"},
+			Forbidden:  []string{"CAM", "HDTS", "YIFY"},
+			Preferred: []config.PreferredKeyword{
+				{Keyword: "Atmos", Weight: 15},
+				{Keyword: "FLUX", Weight: 10},
+				{Keyword: "DTS-HD", Weight: 5},
+			},
+		},
+		"x265only": {
+			Required: []string{"x265"},
+		},
+	}
+	scorer := NewScorer(profiles)
+
+	tests := []struct {
+		name       string
+		title      string
+		profile    string
+		wantBonus  int
+		wantReason string
+	}{
+		{
+			name:    "plain release has no bonus",
+			title:   "Dune.Part.Two.2024.1080p.BluRay.x264-SPARKS",
+			profile: "hd",
+		},
+		{
+			name:      "preferred group bonus",
+			title:     "Dune.Part.Two.2024.1080p.AMZN.WEB-DL.DDP5.1.H.264-FLUX",
+			profile:   "hd",
+			wantBonus: 10,
+		},
+		{
+			name:      "preferred keywords add up",
+			title:     "Dune.Part.Two.2024.1080p.AMZN.WEB-DL.DDP5.1.Atmos.H.264-FLUX",
+			profile:   "hd",
+			wantBonus: 25,
+		},
+		{
+			name:      "multi-word keyword matches across separators",
+			title:     "Oppenheimer.2023.1080p.BluRay.DTS-HD.MA.5.1.x264-GROUP",
+			profile:   "hd",
+			wantBonus: 5,
+		},
+		{
+			name:      "case insensitive",
+			title:     "oppenheimer.2023.1080p.web.atmos-flux",
+			profile:   "hd",
+			wantBonus: 25,
+		},
+		{
+			name:       "forbidden source",
+			title:      "Deadpool.and.Wolverine.2024.1080p.HDTS.x264-SOMEGROUP",
+			profile:    "hd",
+			wantReason: `forbidden keyword "HDTS"`,
+		},
+		{
+			name:       "forbidden camrip",
+			title:      "Deadpool.and.Wolverine.2024.720p.CAM.x264",
+			profile:    "hd",
+			wantReason: `forbidden keyword "CAM"`,
+		},
+		{
+			name:       "forbidden group",
+			title:      "The.Matrix.1999.1080p.BluRay.x264-[YIFY]",
+			profile:    "hd",
+			wantReason: `forbidden keyword "YIFY"`,
+		},
+		{
+			name:    "forbidden keyword must be a whole word",
+			title:   "The.Cameraman.1928.1080p.BluRay.x264-GROUP",
+			profile: "hd",
+		},
+		{
+			name:    "required keyword present",
+			title:   "Severance.S02E01.1080p.ATVP.WEB-DL.DDP5.1.x265-GROUP",
+			profile: "x265only",
+		},
+		{
+			name:       "required keyword missing",
+			title:      "Severance.S02E01.1080p.ATVP.WEB-DL.DDP5.1.H.264-GROUP",
+			profile:    "x265only",
+			wantReason: `missing required keyword "x265"`,
+		},
+		{
+			name:    "unknown profile",
+			title:   "Movie.2024.CAM",
+			profile: "nope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bonus, reason := scorer.MatchKeywords(tt.title, tt.profile)
+			assert.Equal(t, tt.wantBonus, bonus)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestScorer_CheckLanguage(t *testing.T) {
+	profiles := map[string]config.QualityProfile{
+		"english": {Languages: []string{"english"}},
+		"french":  {Languages: []string{"French"}, MultiLanguages: []string{"english", "german"}},
+		"no-dubs": {ExcludeLanguages: []string{"german", "italian"}},
+		"any":     {},
+	}
+	scorer := NewScorer(profiles)
+
+	tests := []struct {
+		name       string
+		title      string
+		profile    string
+		wantLangs  []string
+		wantReason string
+	}{
+		{
+			name:      "unlabeled release is english",
+			title:     "Movie.2024.1080p.BluRay.x264-GRP",
+			profile:   "english",
+			wantLangs: []string{"english"},
+		},
+		{
+			name:       "german release not wanted",
+			title:      "Movie.2024.GERMAN.1080p.BluRay.x264-GRP",
+			profile:    "english",
+			wantLangs:  []string{"german"},
+			wantReason: "language german not wanted",
+		},
+		{
+			name:      "multi carries the default set",
+			title:     "Movie.2024.MULTi.1080p.BluRay.x264-GRP",
+			profile:   "english",
+			wantLangs: []string{"english", "french"},
+		},
+		{
+			name:       "multi set is configurable",
+			title:      "Movie.2024.MULTi.1080p.BluRay.x264-GRP",
+			profile:    "french",
+			wantLangs:  []string{"english", "german"},
+			wantReason: "language english, german not wanted",
+		},
+		{
+			name:      "named language in multi release",
+			title:     "Movie.2024.MULTi.VFF.1080p.BluRay.x264-GRP",
+			profile:   "french",
+			wantLangs: []string{"french", "english", "german"},
+		},
+		{
+			name:       "excluded language",
+			title:      "Movie.2024.iTALiAN.1080p.BluRay.x264-GRP",
+			profile:    "no-dubs",
+			wantLangs:  []string{"italian"},
+			wantReason: "language italian excluded",
+		},
+		{
+			name:      "dual audio keeps the other language",
+			title:     "Movie.2024.German.English.1080p.BluRay.x264-GRP",
+			profile:   "no-dubs",
+			wantLangs: []string{"german", "english"},
+		},
+		{
+			name:      "no language lists accept anything",
+			title:     "Movie.2024.GERMAN.1080p.BluRay.x264-GRP",
+			profile:   "any",
+			wantLangs: []string{"german"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			langs, reason := scorer.CheckLanguage(*release.Parse(tt.title), tt.profile)
+			assert.Equal(t, tt.wantLangs, langs)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestScorer_Score_LanguageBonus(t *testing.T) {
+	profiles := map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}, Languages: []string{"french", "english"}},
+	}
+	scorer := NewScorer(profiles)
+
+	french := scorer.Score(*release.Parse("Movie.2024.FRENCH.1080p.BluRay.x264-GRP"), "hd")
+	english := scorer.Score(*release.Parse("Movie.2024.1080p.BluRay.x264-GRP"), "hd")
+	multi := scorer.Score(*release.Parse("Movie.2024.MULTi.1080p.BluRay.x264-GRP"), "hd")
+
+	assert.Equal(t, scoring.BonusLanguage, french.Language)
+	assert.Equal(t, 8, english.Language, "second preference earns 80%")
+	assert.Equal(t, scoring.BonusLanguage, multi.Language, "MULTi carries french by default")
+	assert.Equal(t, french.Resolution+french.Language, french.Total)
+}
+
+func TestScorer_Score_GroupBonus(t *testing.T) {
+	profiles := map[string]config.QualityProfile{
+		"hd": {
+			Resolution:      []string{"1080p"},
+			PreferredGroups: [][]string{{"FraMeSToR", "BHDStudio"}, {"flux"}},
+		},
+	}
+	scorer := NewScorer(profiles)
+
+	top := scorer.Score(*release.Parse("Movie.2024.1080p.BluRay.x264-FraMeSToR"), "hd")
+	second := scorer.Score(*release.Parse("Movie.2024.1080p.WEB-DL.DDP5.1.H.264-FLUX"), "hd")
+	other := scorer.Score(*release.Parse("Movie.2024.1080p.BluRay.x264-GRP"), "hd")
+	none := scorer.Score(*release.Parse("Movie.2024.1080p.WEB-DL.x264"), "hd")
+
+	assert.Equal(t, scoring.BonusGroup, top.Group)
+	assert.Equal(t, 12, second.Group, "second tier earns 80%, matched case-insensitively")
+	assert.Zero(t, other.Group)
+	assert.Zero(t, none.Group, "releases without a group earn nothing")
+	assert.Equal(t, top.Resolution+top.Group, top.Total)
+}
+
+func TestScorer_CheckGroup(t *testing.T) {
+	profiles := map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}, BannedGroups: []string{"yify"}},
+	}
+	scorer := NewScorer(profiles)
+
+	assert.Equal(t, `banned group "YIFY"`, scorer.CheckGroup(*release.Parse("Movie.2024.1080p.BluRay.x264-YIFY"), "hd"))
+	assert.Empty(t, scorer.CheckGroup(*release.Parse("Movie.2024.1080p.BluRay.x264-GRP"), "hd"))
+	assert.Empty(t, scorer.CheckGroup(*release.Parse("Movie.2024.1080p.WEB-DL.x264"), "hd"), "no group passes")
+	assert.Empty(t, scorer.CheckGroup(*release.Parse("Movie.2024.1080p.BluRay.x264-YIFY"), "unknown"))
+}
+
 func TestScorer_Score_CombinedBonuses(t *testing.T) {
 	profiles := map[string]config.QualityProfile{
 		"uhd": {
@@ -340,7 +572,7 @@ func TestScorer_Score_CombinedBonuses(t *testing.T) {
 		IsRemux:    true,
 	}
 
-	got := scorer.Score(info, "uhd")
+	got := scorer.Score(info, "uhd").Total
 
 	// Calculate expected:
 	// Base: 100 (2160p)
@@ -355,6 +587,27 @@ func TestScorer_Score_CombinedBonuses(t *testing.T) {
 	assert.Equal(t, want, got, "Score()")
 }
 
+func TestScorer_Score_Breakdown(t *testing.T) {
+	profiles := map[string]config.QualityProfile{
+		"uhd": {
+			Resolution:  []string{"2160p"},
+			Sources:     []string{"webdl", "bluray"},
+			HDR:         []string{"dolby-vision"},
+			PreferRemux: true,
+		},
+	}
+	scorer := NewScorer(profiles)
+
+	got := scorer.Score(release.Info{
+		Resolution: release.Resolution2160p,
+		Source:     release.SourceBluRay,
+		HDR:        release.DolbyVision,
+	}, "uhd")
+
+	assert.Equal(t, scoring.Breakdown{Resolution: 100, Source: 8, HDR: 15, Total: 123}, got)
+	assert.Equal(t, scoring.Breakdown{}, scorer.Score(release.Info{Resolution: release.Resolution720p}, "uhd"), "rejected release has an empty breakdown")
+}
+
 func TestScorer_Score_PositionAdjustment(t *testing.T) {
 	// Test the position adjustment formula: bonus * (1 - 0.2 * position)
 	profiles := map[string]config.QualityProfile{
@@ -390,7 +643,7 @@ func TestScorer_Score_PositionAdjustment(t *testing.T) {
 				Resolution: release.Resolution1080p,
 				HDR:        tt.hdr,
 			}
-			got := scorer.Score(info, "test")
+			got := scorer.Score(info, "test").Total
 			want := baseScore + tt.wantBonus
 			assert.Equal(t, want, got, "Score() (bonus=%d at position %d)", tt.wantBonus, tt.position)
 		})
@@ -404,7 +657,7 @@ func TestScorer_Score_UnknownProfile(t *testing.T) {
 	scorer := NewScorer(profiles)
 
 	info := release.Info{Resolution: release.Resolution1080p}
-	got := scorer.Score(info, "nonexistent")
+	got := scorer.Score(info, "nonexistent").Total
 
 	assert.Equal(t, 0, got, "Score() for unknown profile")
 }
@@ -436,7 +689,7 @@ func TestScorer_Score_EmptyResolutionMeansAny(t *testing.T) {
 				Resolution: tt.resolution,
 				Source:     release.SourceBluRay,
 			}
-			got := scorer.Score(info, "any")
+			got := scorer.Score(info, "any").Total
 			want := tt.wantBase + 10 // bluray bonus
 			assert.Equal(t, want, got, "Score()")
 		})
@@ -458,7 +711,7 @@ func TestScorer_Score_EmptySourceMeansNoBonus(t *testing.T) {
 		Resolution: release.Resolution1080p,
 		Source:     release.SourceBluRay,
 	}
-	got := scorer.Score(info, "hd")
+	got := scorer.Score(info, "hd").Total
 	want := 80 // Just base score, no source bonus
 
 	assert.Equal(t, want, got, "Score() (no source bonus)")
@@ -478,8 +731,8 @@ func TestScorer_Score_MultipleProfiles(t *testing.T) {
 	info := release.Info{Resolution: release.Resolution1080p}
 
 	// Same release should score differently in different profiles
-	uhdScore := scorer.Score(info, "uhd")
-	hdScore := scorer.Score(info, "hd")
+	uhdScore := scorer.Score(info, "uhd").Total
+	hdScore := scorer.Score(info, "hd").Total
 
 	assert.Equal(t, 0, uhdScore, "1080p in uhd profile")
 	assert.Equal(t, 80, hdScore, "1080p in hd profile")
@@ -717,3 +970,93 @@ func TestCalculatePositionBonus(t *testing.T) {
 		})
 	}
 }
+
+func TestScorer_CheckSize(t *testing.T) {
+	const mb = 1 << 20
+	scorer := NewScorer(map[string]config.QualityProfile{
+		"hd":  {MinSizeMB: 500, MaxSizeMB: 4000},
+		"any": {},
+	})
+
+	tests := []struct {
+		name     string
+		size     int64
+		episodes int
+		profile  string
+		want     string
+	}{
+		{"within bounds", 2000 * mb, 1, "hd", ""},
+		{"too large", 6000 * mb, 1, "hd", "size 6000 MB, maximum 4000 MB"},
+		{"too small", 100 * mb, 1, "hd", "size 100 MB, minimum 500 MB"},
+		{"pack within bounds per episode", 30000 * mb, 10, "hd", ""},
+		{"pack too large per episode", 80000 * mb, 10, "hd", "size 8000 MB per episode (10 episodes), maximum 4000 MB"},
+		{"unknown size", 0, 1, "hd", ""},
+		{"no bounds", 90000 * mb, 1, "any", ""},
+		{"unknown profile", 90000 * mb, 1, "missing", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scorer.CheckSize(tt.size, tt.episodes, tt.profile))
+		})
+	}
+}
+
+func TestScorer_DeferUntil(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	scorer := NewScorer(nil)
+	scorer.SetMinimumAge(30*time.Minute, map[string]time.Duration{"slowidx": 2 * time.Hour})
+
+	tests := []struct {
+		name string
+		rel  Release
+		want time.Time
+	}{
+		{"new usenet post", Release{Indexer: "nzbgeek", PublishDate: now.Add(-10 * time.Minute)}, now.Add(20 * time.Minute)},
+		{"old enough", Release{Indexer: "nzbgeek", PublishDate: now.Add(-30 * time.Minute)}, time.Time{}},
+		{"indexer override", Release{Indexer: "slowidx", PublishDate: now.Add(-time.Hour)}, now.Add(time.Hour)},
+		{"torrent", Release{Indexer: "jackett", Protocol: download.ProtocolTorrent, PublishDate: now}, time.Time{}},
+		{"no publish date", Release{Indexer: "nzbgeek"}, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scorer.DeferUntil(&tt.rel, now))
+		})
+	}
+
+	scorer.SetMinimumAge(0, nil)
+	assert.True(t, scorer.DeferUntil(&Release{PublishDate: now}, now).IsZero(), "no minimum")
+}
+
+func TestCutoffMet(t *testing.T) {
+	p := config.QualityProfile{
+		Resolution: []string{"720p", "1080p", "2160p"},
+		Sources:    []string{"bluray", "webdl", "hdtv"},
+		Cutoff:     "1080p webdl",
+	}
+	tests := []struct {
+		quality string
+		want    bool
+	}{
+		{"", false},
+		{"720p", false},
+		{"1080p hdtv", false},
+		{"1080p webdl", true},
+		{"1080p BluRay", true},
+		{"1080p", true}, // Source unknown: the resolution decides
+		{"2160p hdtv", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CutoffMet(tt.quality, p), tt.quality)
+	}
+
+	// Without a cutoff the best accepted resolution is the cutoff
+	p.Cutoff = ""
+	assert.False(t, CutoffMet("1080p bluray", p))
+	assert.True(t, CutoffMet("2160p", p))
+
+	s := NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"720p", "1080p"}, Cutoff: "720p"}, "uhd": p})
+	assert.True(t, s.HasCutoff("hd"))
+	assert.False(t, s.HasCutoff("uhd"), "no explicit cutoff")
+	assert.True(t, s.MeetsCutoff("720p", "hd"))
+	assert.False(t, s.MeetsCutoff("480p", "hd"))
+}
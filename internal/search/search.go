@@ -3,13 +3,18 @@ package search
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"regexp"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
 	"github.com/vmunix/arrgo/pkg/release"
+	"github.com/vmunix/arrgo/pkg/release/scoring"
 )
 
 // sequelPattern matches sequel indicators in titles.
@@ -145,6 +150,20 @@ func titleMatches(queryTitle, releaseTitle string) bool {
 	return false
 }
 
+// titleMatchesAny checks if a release title matches the query title or one of
+// the content's aliases.
+func titleMatchesAny(queryTitle string, aliases []string, releaseTitle string) bool {
+	if titleMatches(queryTitle, releaseTitle) {
+		return true
+	}
+	for _, alias := range aliases {
+		if titleMatches(alias, releaseTitle) {
+			return true
+		}
+	}
+	return false
+}
+
 // hasSequelMismatch returns true if the release title has sequel indicators
 // that don't match the query. This helps rank:
 // - Original films higher when no sequel specified
@@ -181,25 +200,81 @@ type Release struct {
 	DownloadURL string
 	Size        int64
 	PublishDate time.Time
-	Quality     *release.Info // Parsed quality info
-	Score       int           // Match score (higher is better)
+	Quality     *release.Info     // Parsed quality info
+	Score       int               // Match score (higher is better)
+	Breakdown   scoring.Breakdown // How Score was earned
+
+	// Protocol is how the release downloads; Torznab results are torrents.
+	Protocol download.Protocol
+	Seeders  int    // Torrents only
+	Peers    int    // Torrents only
+	InfoHash string // Torrents only
 }
 
 // Query specifies what to search for.
 type Query struct {
 	ContentID int64  // If searching for known content
 	Text      string // Free text search
-	Type      string // "movie" or "series"
+	Type      string // "movie", "series", or "audiobook"
 	TMDBID    *int64
 	TVDBID    *int64
 	Season    *int
 	Episode   *int
+	Refresh   bool // Query the indexers even if results are cached
+	// Protocol keeps only releases of this protocol; empty keeps any
+	Protocol download.Protocol
+	// SeasonEpisodes is how many episodes a season pack for Season carries;
+	// when set, packs are held to the profile's size bounds per episode.
+	SeasonEpisodes int
+}
+
+// ContentQuery builds the query used to search for a library item.
+// Without a season the query is "Title Year"; for series a season
+// narrows it to "Title S01" (season packs) and an episode to "Title S01E02".
+// Audiobooks are searched as "Author Title": their releases seldom carry a year.
+func ContentQuery(c *library.Content, season, episode *int) Query {
+	q := Query{
+		ContentID: c.ID,
+		Text:      c.Title,
+		Type:      string(c.Type),
+	}
+	switch {
+	case c.Type == library.ContentTypeSeries && season != nil && episode != nil:
+		q.Text = fmt.Sprintf("%s S%02dE%02d", c.Title, *season, *episode)
+		q.Season, q.Episode = season, episode
+	case c.Type == library.ContentTypeSeries && season != nil:
+		q.Text = fmt.Sprintf("%s S%02d", c.Title, *season)
+		q.Season = season
+	case c.Type == library.ContentTypeAudiobook:
+		q.Text = strings.TrimSpace(c.Author + " " + c.Title)
+	case c.Year > 0:
+		q.Text = fmt.Sprintf("%s %d", c.Title, c.Year)
+	}
+	return q
+}
+
+// Rejection records a release filtered out by the profile's keyword or language lists.
+type Rejection struct {
+	Title     string
+	Indexer   string
+	Reason    string   // e.g. forbidden keyword "CAM"
+	Languages []string // Detected audio languages, set for language rejections
+}
+
+// Deferred is a usenet release held back until it reaches the minimum age.
+type Deferred struct {
+	Release *Release
+	Until   time.Time // When the release may be grabbed
 }
 
 // Result contains the results of a search operation.
 type Result struct {
 	Releases []*Release
-	Errors   []error
+	Rejected []*Rejection
+	Deferred []*Deferred // Acceptable releases too new to grab, best first
+	Errors   []error     // Failed and skipped indexers are *IndexerError
+	Cached   bool        // Indexer results came from the cache
+	CachedAt time.Time   // When the cached results were fetched
 }
 
 // IndexerAPI defines the interface for indexer operations.
@@ -208,11 +283,26 @@ type IndexerAPI interface {
 	Search(ctx context.Context, q Query) ([]Release, []error)
 }
 
+// Blocklist reports releases that must not be grabbed again for a content item.
+type Blocklist interface {
+	IsBlocked(contentID int64, guid string) (bool, error)
+}
+
+// Aliases lists the alternate titles of a content item.
+type Aliases interface {
+	ListAliases(contentID int64) ([]*library.Alias, error)
+}
+
 // Searcher orchestrates searches across indexers with quality scoring.
 type Searcher struct {
-	indexers IndexerAPI
-	scorer   *Scorer
-	log      *slog.Logger
+	indexers  IndexerAPI
+	scorer    *Scorer
+	blocklist Blocklist    // nil if blocklisted releases are not filtered
+	aliases   Aliases      // nil if releases must match the query title
+	cache     *ResultCache // nil if indexer results are not cached
+	log       *slog.Logger
+
+	seasonPackMinMissing float64 // See SetSeasonPackMinMissing
 }
 
 // NewSearcher creates a new Searcher with the given indexer pool and scorer.
@@ -224,42 +314,146 @@ func NewSearcher(indexers IndexerAPI, scorer *Scorer, log *slog.Logger) *Searche
 	}
 }
 
+// SetBlocklist configures the blocklist consulted for searches with a ContentID.
+func (s *Searcher) SetBlocklist(b Blocklist) {
+	s.blocklist = b
+}
+
+// SetAliases configures the alternate titles a release for a search with a
+// ContentID may carry instead of the query title.
+func (s *Searcher) SetAliases(a Aliases) {
+	s.aliases = a
+}
+
+// SetCache configures the cache consulted before querying the indexers.
+func (s *Searcher) SetCache(c *ResultCache) {
+	s.cache = c
+}
+
 // Search queries the indexers for releases matching the query,
 // parses quality information, scores against the profile,
-// filters out zero-score releases, and sorts by score descending.
+// filters out zero-score and blocklisted releases, and sorts by score descending.
+// Releases rejected by the profile's keyword or language lists or minimum seeders are reported in Result.Rejected.
+// Usenet releases younger than the minimum age are reported in Result.Deferred.
 func (s *Searcher) Search(ctx context.Context, q Query, profile string) (*Result, error) {
 	s.log.Info("search started", "query", q.Text, "type", q.Type, "profile", profile)
 
 	result := &Result{
 		Releases: make([]*Release, 0),
+		Rejected: make([]*Rejection, 0),
 		Errors:   make([]error, 0),
 	}
+	now := time.Now()
 
-	// Query the indexers
-	releases, errs := s.indexers.Search(ctx, q)
-	result.Errors = append(result.Errors, errs...)
+	// Query the indexers, or reuse a recent answer to the same request
+	releases, cached := s.indexerResults(ctx, q, result)
+	if cached {
+		s.log.Debug("search cache hit", "query", q.Text, "age", time.Since(result.CachedAt).Round(time.Second))
+	}
 
-	// Extract the query title for matching
+	s.filter(q, releases, profile, now, result)
+	return result, nil
+}
+
+// filter parses, scores and filters releases found for q as Search does,
+// adding those accepted, rejected and deferred to result, best first.
+func (s *Searcher) filter(q Query, releases []Release, profile string, now time.Time, result *Result) {
+	// Extract the query title for matching; aliases such as a localized name also match
 	queryTitle := extractQueryTitle(q.Text)
+	aliases := s.aliasTitles(q.ContentID)
 
 	// Process each release: parse, score, and filter
 	for _, rel := range releases {
+		// Skip releases that previously failed for this content
+		if s.isBlocked(q.ContentID, rel.GUID) {
+			continue
+		}
+
+		// Skip releases of other protocols than asked for
+		if q.Protocol != "" && rel.Protocol != q.Protocol {
+			continue
+		}
+
 		// Parse quality info from release name
 		info := release.Parse(rel.Title)
 
 		// Filter out releases with mismatched titles
 		// This prevents "Fear the Walking Dead" from matching "The Walking Dead"
-		if queryTitle != "" && info.Title != "" && !titleMatches(queryTitle, info.Title) {
+		if queryTitle != "" && info.Title != "" && !titleMatchesAny(queryTitle, aliases, info.Title) {
+			continue
+		}
+
+		// Apply required/forbidden keywords; preferred keywords add a bonus
+		bonus, reason := s.scorer.MatchKeywords(rel.Title, profile)
+		if reason != "" {
+			result.Rejected = append(result.Rejected, &Rejection{
+				Title:   rel.Title,
+				Indexer: rel.Indexer,
+				Reason:  reason,
+			})
+			continue
+		}
+
+		// Apply the profile's wanted and excluded languages
+		if langs, reason := s.scorer.CheckLanguage(*info, profile); reason != "" {
+			result.Rejected = append(result.Rejected, &Rejection{
+				Title:     rel.Title,
+				Indexer:   rel.Indexer,
+				Reason:    reason,
+				Languages: langs,
+			})
+			continue
+		}
+
+		// Skip releases by groups the profile bans
+		if reason := s.scorer.CheckGroup(*info, profile); reason != "" {
+			result.Rejected = append(result.Rejected, &Rejection{
+				Title:   rel.Title,
+				Indexer: rel.Indexer,
+				Reason:  reason,
+			})
+			continue
+		}
+
+		// Skip releases no download client can take
+		if reason := s.scorer.CheckProtocol(&rel); reason != "" {
+			result.Rejected = append(result.Rejected, &Rejection{
+				Title:   rel.Title,
+				Indexer: rel.Indexer,
+				Reason:  reason,
+			})
+			continue
+		}
+
+		// Skip poorly seeded torrents
+		if reason := s.scorer.CheckSeeders(&rel, profile); reason != "" {
+			result.Rejected = append(result.Rejected, &Rejection{
+				Title:   rel.Title,
+				Indexer: rel.Indexer,
+				Reason:  reason,
+			})
+			continue
+		}
+
+		// Skip releases outside the profile's size bounds
+		if reason := s.scorer.CheckSize(rel.Size, packEpisodes(q, info), profile); reason != "" {
+			result.Rejected = append(result.Rejected, &Rejection{
+				Title:   rel.Title,
+				Indexer: rel.Indexer,
+				Reason:  reason,
+			})
 			continue
 		}
 
 		// Score against the quality profile
-		score := s.scorer.Score(*info, profile)
+		breakdown := s.scorer.Score(*info, profile)
 
 		// Filter out releases with score 0
-		if score == 0 {
+		if breakdown.Total == 0 {
 			continue
 		}
+		breakdown.Keywords = bonus
+		breakdown.Total += bonus
 
 		// For series season requests: filter out individual episodes, prefer season packs
 		// When searching for a season (Season set, Episode not set), we want season packs
@@ -279,7 +473,8 @@ func (s *Searcher) Search(ctx context.Context, q Query, profile string) (*Result
 		// Use negative score to rank below non-sequels with same quality
 		// Note: Use rel.Title (raw) not info.Title (parsed) since parser strips sequel info
 		if hasSequelMismatch(q.Text, rel.Title) {
-			score = -score // Negative score, still included but ranked last
+			breakdown.SequelPenalty = true
+			breakdown.Total = -breakdown.Total // Negative score, still included but ranked last
 		}
 
 		// Create a copy with quality info and score
@@ -291,18 +486,112 @@ func (s *Searcher) Search(ctx context.Context, q Query, profile string) (*Result
 			Size:        rel.Size,
 			PublishDate: rel.PublishDate,
 			Quality:     info,
-			Score:       score,
+			Score:       breakdown.Total,
+			Breakdown:   breakdown,
+			Protocol:    rel.Protocol,
+			Seeders:     rel.Seeders,
+			Peers:       rel.Peers,
+			InfoHash:    rel.InfoHash,
+		}
+
+		// Hold back usenet posts younger than the minimum age
+		if until := s.scorer.DeferUntil(r, now); !until.IsZero() {
+			result.Deferred = append(result.Deferred, &Deferred{Release: r, Until: until})
+			continue
 		}
 
 		result.Releases = append(result.Releases, r)
 	}
 
-	s.log.Debug("scoring complete", "raw", len(releases), "filtered", len(result.Releases))
+	s.log.Debug("scoring complete", "raw", len(releases), "filtered", len(result.Releases), "rejected", len(result.Rejected), "deferred", len(result.Deferred))
 
-	// Sort by score descending (stable sort to preserve order for equal scores)
+	// Sort by score descending, preferring higher-priority indexers on ties
+	// (stable sort to preserve order when both are equal)
+	s.sortResult(result, profile)
+}
+
+// sortResult orders the result's releases and deferred releases best first
+// in the profile.
+func (s *Searcher) sortResult(result *Result, profile string) {
 	sort.SliceStable(result.Releases, func(i, j int) bool {
-		return result.Releases[i].Score > result.Releases[j].Score
+		return s.scorer.Better(result.Releases[i], result.Releases[j], profile)
+	})
+	sort.SliceStable(result.Deferred, func(i, j int) bool {
+		return s.scorer.Better(result.Deferred[i].Release, result.Deferred[j].Release, profile)
 	})
+}
 
-	return result, nil
+// packEpisodes returns how many episodes a release found by q carries for
+// size checks: q.SeasonEpisodes for a season pack in a season search, else 1.
+func packEpisodes(q Query, info *release.Info) int {
+	if q.SeasonEpisodes > 0 && q.Season != nil && q.Episode == nil && (info.Episode == 0 || info.IsCompleteSeason) {
+		return q.SeasonEpisodes
+	}
+	return 1
+}
+
+// indexerResults returns the indexers' releases for q, from the cache unless
+// q.Refresh is set, recording indexer errors and cache use in result. Answers
+// are cached only when no indexer failed; skipping an indexer over its daily
+// budget does not count as failing.
+func (s *Searcher) indexerResults(ctx context.Context, q Query, result *Result) ([]Release, bool) {
+	if s.cache == nil {
+		releases, errs := s.indexers.Search(ctx, q)
+		result.Errors = append(result.Errors, errs...)
+		return releases, false
+	}
+
+	key := cacheKey(q)
+	if !q.Refresh {
+		if releases, storedAt, ok := s.cache.Get(key); ok {
+			result.Cached, result.CachedAt = true, storedAt
+			return releases, true
+		}
+	}
+
+	releases, errs := s.indexers.Search(ctx, q)
+	result.Errors = append(result.Errors, errs...)
+	for _, err := range errs {
+		if !errors.Is(err, ErrBudgetExhausted) {
+			return releases, false
+		}
+	}
+	if len(errs) > 0 && len(releases) == 0 {
+		// Likely every indexer was skipped; don't remember an empty answer
+		return releases, false
+	}
+	s.cache.Put(key, releases)
+	return releases, false
+}
+
+// isBlocked reports whether a release is blocklisted for the content.
+// Lookup errors are logged and treated as not blocked.
+// aliasTitles returns the alternate titles of a content item, or nil if there
+// are none or they can't be read.
+func (s *Searcher) aliasTitles(contentID int64) []string {
+	if s.aliases == nil || contentID == 0 {
+		return nil
+	}
+	aliases, err := s.aliases.ListAliases(contentID)
+	if err != nil {
+		s.log.Warn("alias lookup failed", "content_id", contentID, "error", err)
+		return nil
+	}
+	titles := make([]string, 0, len(aliases))
+	for _, a := range aliases {
+		titles = append(titles, a.Alias)
+	}
+	return titles
+}
+
+func (s *Searcher) isBlocked(contentID int64, guid string) bool {
+	if s.blocklist == nil || contentID == 0 || guid == "" {
+		return false
+	}
+	blocked, err := s.blocklist.IsBlocked(contentID, guid)
+	if err != nil {
+		s.log.Warn("blocklist check failed", "content_id", contentID, "guid", guid, "error", err)
+		return false
+	}
+	return blocked
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
 	"github.com/vmunix/arrgo/internal/search"
 	"github.com/vmunix/arrgo/internal/search/mocks"
 	"github.com/vmunix/arrgo/pkg/release"
@@ -54,6 +56,201 @@ func TestSearcher_Search(t *testing.T) {
 	assert.Equal(t, "1", result.Releases[0].GUID)
 }
 
+func TestSearcher_Search_Keywords(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	profiles := map[string]config.QualityProfile{
+		"hd": {
+			Resolution: []string{"1080p"},
+			Forbidden:  []string{"HDTS"},
+			Preferred:  []config.PreferredKeyword{{Keyword: "Atmos", Weight: 50}},
+		},
+	}
+	scorer := search.NewScorer(profiles)
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{
+			{Title: "Movie.2024.1080p.BluRay.x264-GROUP", GUID: "1", Indexer: "nzbgeek"},
+			{Title: "Movie.2024.1080p.HDTS.x264-BAD", GUID: "2", Indexer: "drunken"},
+			{Title: "Movie.2024.1080p.WEB-DL.DDP5.1.Atmos.x264-WEB", GUID: "3", Indexer: "nzbgeek"},
+		}, nil)
+
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+	result, err := searcher.Search(context.Background(), search.Query{Text: "Movie"}, "hd")
+	require.NoError(t, err)
+
+	require.Len(t, result.Releases, 2)
+	assert.Equal(t, "3", result.Releases[0].GUID, "preferred keyword bonus should rank Atmos release first")
+
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, "Movie.2024.1080p.HDTS.x264-BAD", result.Rejected[0].Title)
+	assert.Equal(t, "drunken", result.Rejected[0].Indexer)
+	assert.Equal(t, `forbidden keyword "HDTS"`, result.Rejected[0].Reason)
+}
+
+func TestSearcher_Search_MinSeeders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	profiles := map[string]config.QualityProfile{
+		"hd":  {Resolution: []string{"1080p"}},
+		"uhd": {Resolution: []string{"1080p"}, MinSeeders: 20},
+	}
+	scorer := search.NewScorer(profiles)
+	scorer.SetMinSeeders(5)
+
+	releases := []search.Release{
+		{Title: "Movie.2024.1080p.BluRay.x264-NZB", GUID: "1", Indexer: "nzbgeek", Protocol: download.ProtocolUsenet},
+		{Title: "Movie.2024.1080p.BluRay.x264-WELL", GUID: "2", Indexer: "jackett", Protocol: download.ProtocolTorrent, Seeders: 10},
+		{Title: "Movie.2024.1080p.BluRay.x264-DEAD", GUID: "3", Indexer: "jackett", Protocol: download.ProtocolTorrent, Seeders: 1},
+	}
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().Search(gomock.Any(), gomock.Any()).Return(releases, nil).Times(2)
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+
+	// Global minimum
+	result, err := searcher.Search(context.Background(), search.Query{Text: "Movie"}, "hd")
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 2)
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, "Movie.2024.1080p.BluRay.x264-DEAD", result.Rejected[0].Title)
+	assert.Equal(t, "1 seeders, minimum 5", result.Rejected[0].Reason)
+
+	// Profile override
+	result, err = searcher.Search(context.Background(), search.Query{Text: "Movie"}, "uhd")
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 1)
+	assert.Equal(t, "1", result.Releases[0].GUID, "usenet releases ignore min seeders")
+	assert.Len(t, result.Rejected, 2)
+}
+
+func TestSearcher_Search_Protocol(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	profiles := map[string]config.QualityProfile{
+		"hd":      {Resolution: []string{"1080p"}},
+		"torrent": {Resolution: []string{"1080p"}, PreferredProtocol: "torrent"},
+	}
+	scorer := search.NewScorer(profiles)
+
+	// Equal scores; the usenet release's indexer has the better priority
+	releases := []search.Release{
+		{Title: "Movie.2024.1080p.BluRay.x264-NZB", GUID: "1", Indexer: "nzbgeek", Protocol: download.ProtocolUsenet},
+		{Title: "Movie.2024.1080p.BluRay.x264-TOR", GUID: "2", Indexer: "jackett", Protocol: download.ProtocolTorrent, Seeders: 10},
+	}
+	scorer.SetIndexerPriorities(map[string]int{"nzbgeek": 1, "jackett": 50})
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().Search(gomock.Any(), gomock.Any()).Return(releases, nil).AnyTimes()
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+
+	result, err := searcher.Search(context.Background(), search.Query{Text: "Movie"}, "hd")
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 2)
+	assert.Equal(t, "1", result.Releases[0].GUID, "no preference: indexer priority breaks the tie")
+
+	result, err = searcher.Search(context.Background(), search.Query{Text: "Movie"}, "torrent")
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 2)
+	assert.Equal(t, "2", result.Releases[0].GUID, "preferred protocol breaks the tie")
+
+	// Filtered by the query
+	result, err = searcher.Search(context.Background(), search.Query{Text: "Movie", Protocol: download.ProtocolTorrent}, "hd")
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 1)
+	assert.Equal(t, "2", result.Releases[0].GUID)
+	assert.Empty(t, result.Rejected)
+
+	// Rejected without a client for the protocol
+	scorer.SetProtocols([]download.Protocol{download.ProtocolUsenet})
+	result, err = searcher.Search(context.Background(), search.Query{Text: "Movie"}, "torrent")
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 1)
+	assert.Equal(t, "1", result.Releases[0].GUID)
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, "no torrent download client configured", result.Rejected[0].Reason)
+}
+
+func TestSearcher_Search_MinimumAge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	scorer := search.NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+	scorer.SetMinimumAge(time.Hour, nil)
+
+	now := time.Now()
+	releases := []search.Release{
+		{Title: "Movie.2024.1080p.BluRay.x264-OLD", GUID: "1", Indexer: "nzbgeek", Protocol: download.ProtocolUsenet, PublishDate: now.Add(-2 * time.Hour)},
+		{Title: "Movie.2024.1080p.BluRay.x264-NEW", GUID: "2", Indexer: "nzbgeek", Protocol: download.ProtocolUsenet, PublishDate: now.Add(-10 * time.Minute)},
+		{Title: "Movie.2024.1080p.BluRay.x264-TOR", GUID: "3", Indexer: "jackett", Protocol: download.ProtocolTorrent, PublishDate: now},
+		{Title: "Movie.2024.720p.BluRay.x264-SMALL", GUID: "4", Indexer: "nzbgeek", Protocol: download.ProtocolUsenet, PublishDate: now},
+	}
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().Search(gomock.Any(), gomock.Any()).Return(releases, nil)
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+
+	result, err := searcher.Search(context.Background(), search.Query{Text: "Movie"}, "hd")
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 2)
+	assert.ElementsMatch(t, []string{"1", "3"}, []string{result.Releases[0].GUID, result.Releases[1].GUID}, "torrents are never deferred")
+	require.Len(t, result.Deferred, 1, "releases the profile rejects aren't deferred")
+	assert.Equal(t, "2", result.Deferred[0].Release.GUID)
+	assert.WithinDuration(t, now.Add(50*time.Minute), result.Deferred[0].Until, time.Second)
+}
+
+func TestSearcher_Search_Languages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	profiles := map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}, Languages: []string{"english"}},
+	}
+	scorer := search.NewScorer(profiles)
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{
+			{Title: "Movie.2024.1080p.BluRay.x264-GROUP", GUID: "1", Indexer: "nzbgeek"},
+			{Title: "Movie.2024.GERMAN.DL.1080p.BluRay.x264-GER", GUID: "2", Indexer: "nzbgeek"},
+			{Title: "Movie.2024.MULTi.1080p.BluRay.x264-FR", GUID: "3", Indexer: "nzbgeek"},
+		}, nil)
+
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+	result, err := searcher.Search(context.Background(), search.Query{Text: "Movie"}, "hd")
+	require.NoError(t, err)
+
+	require.Len(t, result.Releases, 2, "MULTi releases carry english by default")
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, "Movie.2024.GERMAN.DL.1080p.BluRay.x264-GER", result.Rejected[0].Title)
+	assert.Equal(t, "language german not wanted", result.Rejected[0].Reason)
+	assert.Equal(t, []string{"german"}, result.Rejected[0].Languages)
+}
+
+func TestSearcher_Search_BannedGroups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	profiles := map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}, BannedGroups: []string{"YIFY"}},
+	}
+	scorer := search.NewScorer(profiles)
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{
+			{Title: "Movie.2024.1080p.BluRay.x264-GROUP", GUID: "1", Indexer: "nzbgeek"},
+			{Title: "Movie.2024.1080p.BluRay.x264-YIFY", GUID: "2", Indexer: "nzbgeek"},
+		}, nil)
+
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+	result, err := searcher.Search(context.Background(), search.Query{Text: "Movie"}, "hd")
+	require.NoError(t, err)
+
+	require.Len(t, result.Releases, 1)
+	assert.Equal(t, "1", result.Releases[0].GUID)
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, `banned group "YIFY"`, result.Rejected[0].Reason)
+}
+
 func TestSearcher_Search_ParsesQualityInfo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -222,6 +419,38 @@ func TestSearcher_Search_SortStability(t *testing.T) {
 	}
 }
 
+func TestSearcher_Search_IndexerPriorityTiebreak(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	profiles := map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p", "720p"}},
+	}
+	scorer := search.NewScorer(profiles)
+	scorer.SetIndexerPriorities(map[string]int{"preferred": 1, "fallback": 40})
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{
+			{Title: "Movie.2024.720p.BluRay.x264-AAA", GUID: "1", Indexer: "preferred"},
+			{Title: "Movie.2024.1080p.BluRay.x264-BBB", GUID: "2", Indexer: "fallback"},
+			{Title: "Movie.2024.1080p.BluRay.x264-CCC", GUID: "3", Indexer: "unset"},
+			{Title: "Movie.2024.1080p.BluRay.x264-DDD", GUID: "4", Indexer: "preferred"},
+		}, nil)
+
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+	result, err := searcher.Search(context.Background(), search.Query{Text: "Movie"}, "hd")
+
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 4)
+
+	// Equal 1080p scores are ordered by priority; priority never beats a higher score
+	expectedGUIDs := []string{"4", "3", "2", "1"}
+	for i, expected := range expectedGUIDs {
+		assert.Equal(t, expected, result.Releases[i].GUID, "Position %d: expected GUID %s", i, expected)
+	}
+}
+
 func TestSearcher_SequelPenalty(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -292,6 +521,38 @@ func TestSearcher_SeasonPackPreference(t *testing.T) {
 	assert.Equal(t, "s01pack", result.Releases[0].GUID, "Expected S01 season pack")
 }
 
+func TestSearcher_Search_SeasonPackSizePerEpisode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	const gb = 1 << 30
+	scorer := search.NewScorer(map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}, MaxSizeMB: 3000},
+	})
+	releases := []search.Release{
+		{Title: "Show.S01.1080p.WEB-DL.x264-SMALL", GUID: "small", Indexer: "test", Size: 20 * gb},
+		{Title: "Show.S01.1080p.BluRay.REMUX-HUGE", GUID: "huge", Indexer: "test", Size: 80 * gb},
+	}
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().Search(gomock.Any(), gomock.Any()).Return(releases, nil).Times(2)
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+
+	season := 1
+	query := search.Query{Text: "Show S01", Type: "series", Season: &season, SeasonEpisodes: 10}
+	result, err := searcher.Search(context.Background(), query, "hd")
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 1)
+	assert.Equal(t, "small", result.Releases[0].GUID, "2 GB per episode is within bounds")
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, "size 8192 MB per episode (10 episodes), maximum 3000 MB", result.Rejected[0].Reason)
+
+	// Without an episode count the whole pack is measured
+	query.SeasonEpisodes = 0
+	result, err = searcher.Search(context.Background(), query, "hd")
+	require.NoError(t, err)
+	assert.Empty(t, result.Releases)
+	assert.Len(t, result.Rejected, 2)
+}
+
 func TestSearcher_SeasonPackPreference_NoFilteringForEpisodeSearch(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -324,3 +585,171 @@ func TestSearcher_SeasonPackPreference_NoFilteringForEpisodeSearch(t *testing.T)
 	require.Len(t, result.Releases, 1, "Should return the episode when searching for specific episode")
 	assert.Equal(t, "ep4", result.Releases[0].GUID)
 }
+
+func TestSearcher_Search_FiltersBlocklisted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	profiles := map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}},
+	}
+	scorer := search.NewScorer(profiles)
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{
+			{Title: "Movie.2024.1080p.BluRay.x264-BROKEN", GUID: "bad", Indexer: "nzbgeek"},
+			{Title: "Movie.2024.1080p.WEB-DL.x264-GOOD", GUID: "good", Indexer: "nzbgeek"},
+		}, nil)
+
+	blocklist := mocks.NewMockBlocklist(ctrl)
+	blocklist.EXPECT().IsBlocked(int64(42), "bad").Return(true, nil)
+	blocklist.EXPECT().IsBlocked(int64(42), "good").Return(false, nil)
+
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+	searcher.SetBlocklist(blocklist)
+	result, err := searcher.Search(context.Background(), search.Query{ContentID: 42, Text: "Movie"}, "hd")
+
+	require.NoError(t, err)
+	require.Len(t, result.Releases, 1)
+	assert.Equal(t, "good", result.Releases[0].GUID)
+}
+
+func TestSearcher_Search_BlocklistIgnoredWithoutContentID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	profiles := map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}},
+	}
+	scorer := search.NewScorer(profiles)
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{
+			{Title: "Movie.2024.1080p.BluRay.x264-GROUP", GUID: "1", Indexer: "nzbgeek"},
+		}, nil)
+
+	// No IsBlocked calls expected for free-text searches
+	blocklist := mocks.NewMockBlocklist(ctrl)
+
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+	searcher.SetBlocklist(blocklist)
+	result, err := searcher.Search(context.Background(), search.Query{Text: "Movie"}, "hd")
+
+	require.NoError(t, err)
+	assert.Len(t, result.Releases, 1)
+}
+
+func TestSearcher_Search_MatchesAliases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	profiles := map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}},
+	}
+	scorer := search.NewScorer(profiles)
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{
+			{Title: "Money.Heist.S01E01.1080p.WEB-DL.x264-GROUP", GUID: "title", Indexer: "nzbgeek"},
+			{Title: "La.Casa.de.Papel.S01E01.1080p.WEB-DL.x264-GROUP", GUID: "alias", Indexer: "nzbgeek"},
+			{Title: "Berlin.S01E01.1080p.WEB-DL.x264-GROUP", GUID: "other", Indexer: "nzbgeek"},
+		}, nil)
+
+	aliases := mocks.NewMockAliases(ctrl)
+	aliases.EXPECT().ListAliases(int64(7)).Return([]*library.Alias{{ContentID: 7, Alias: "La Casa de Papel"}}, nil)
+
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+	searcher.SetAliases(aliases)
+	result, err := searcher.Search(context.Background(), search.Query{ContentID: 7, Text: "Money Heist S01E01", Type: "series"}, "hd")
+
+	require.NoError(t, err)
+	guids := make([]string, 0, len(result.Releases))
+	for _, r := range result.Releases {
+		guids = append(guids, r.GUID)
+	}
+	assert.ElementsMatch(t, []string{"title", "alias"}, guids)
+}
+
+func TestContentQuery(t *testing.T) {
+	season, episode := 2, 5
+	movie := &library.Content{ID: 1, Type: library.ContentTypeMovie, Title: "Dune", Year: 2021}
+	series := &library.Content{ID: 2, Type: library.ContentTypeSeries, Title: "Severance", Year: 2022}
+
+	q := search.ContentQuery(movie, &season, &episode)
+	assert.Equal(t, search.Query{ContentID: 1, Text: "Dune 2021", Type: "movie"}, q, "season is ignored for movies")
+
+	q = search.ContentQuery(series, nil, nil)
+	assert.Equal(t, "Severance 2022", q.Text)
+	assert.Nil(t, q.Season)
+
+	q = search.ContentQuery(series, &season, nil)
+	assert.Equal(t, "Severance S02", q.Text)
+	assert.Equal(t, &season, q.Season)
+	assert.Nil(t, q.Episode)
+
+	q = search.ContentQuery(series, &season, &episode)
+	assert.Equal(t, "Severance S02E05", q.Text)
+	assert.Equal(t, &episode, q.Episode)
+
+	book := &library.Content{ID: 3, Type: library.ContentTypeAudiobook, Title: "Dune", Year: 1965, Author: "Frank Herbert"}
+	q = search.ContentQuery(book, nil, nil)
+	assert.Equal(t, search.Query{ContentID: 3, Text: "Frank Herbert Dune", Type: "audiobook"}, q, "searched by author, not year")
+}
+
+func TestSearcher_Search_Cache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scorer := search.NewScorer(map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}},
+	})
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{{Title: "Movie.2024.1080p.BluRay.x264-GROUP", GUID: "1", Indexer: "nzbgeek"}}, nil).
+		Times(2)
+
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+	searcher.SetCache(search.NewResultCache(time.Hour, 10))
+
+	result, err := searcher.Search(context.Background(), search.Query{Text: "Movie", Type: "movie"}, "hd")
+	require.NoError(t, err)
+	assert.False(t, result.Cached)
+	require.Len(t, result.Releases, 1)
+
+	// Same query, different casing: served from the cache
+	result, err = searcher.Search(context.Background(), search.Query{Text: "movie", Type: "movie"}, "hd")
+	require.NoError(t, err)
+	assert.True(t, result.Cached)
+	assert.False(t, result.CachedAt.IsZero())
+	require.Len(t, result.Releases, 1)
+
+	// Refresh goes back to the indexers
+	result, err = searcher.Search(context.Background(), search.Query{Text: "Movie", Type: "movie", Refresh: true}, "hd")
+	require.NoError(t, err)
+	assert.False(t, result.Cached)
+}
+
+func TestSearcher_Search_CacheSkipsFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scorer := search.NewScorer(map[string]config.QualityProfile{
+		"hd": {Resolution: []string{"1080p"}},
+	})
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{{Title: "Movie.2024.1080p.BluRay.x264-GROUP", GUID: "1"}}, []error{errors.New("indexer unavailable")}).
+		Times(2)
+
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+	searcher.SetCache(search.NewResultCache(time.Hour, 10))
+
+	for range 2 {
+		result, err := searcher.Search(context.Background(), search.Query{Text: "Movie"}, "hd")
+		require.NoError(t, err)
+		assert.False(t, result.Cached, "partial results must not be cached")
+	}
+}
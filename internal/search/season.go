@@ -0,0 +1,177 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// Season search strategies, recorded on the grab decision.
+const (
+	StrategySeasonPack = "season_pack" // One release carrying the whole season
+	StrategyEpisodes   = "episodes"    // One release per missing episode
+)
+
+// DefaultSeasonPackMinMissing is the fraction of a season's episodes that must
+// be missing before a season search looks for season packs.
+const DefaultSeasonPackMinMissing = 0.5
+
+// SeasonPlan is how a season search looks for the season's missing episodes.
+type SeasonPlan struct {
+	Strategy string // StrategySeasonPack or StrategyEpisodes; empty when nothing is missing
+	Reason   string // Why the strategy was chosen, e.g. "single episodes: 1 of 10 episodes missing"
+	Aired    int    // Episodes aired so far, what a season pack is expected to carry; 0 if unknown
+	// Missing are the aired episodes still wanted, searched one by one for StrategyEpisodes
+	Missing []*library.Episode
+}
+
+// SetSeasonPackMinMissing configures the fraction of a season's episodes that
+// must be missing for PlanSeason to choose a season pack. Zero uses
+// DefaultSeasonPackMinMissing; a negative fraction always chooses packs.
+func (s *Searcher) SetSeasonPackMinMissing(f float64) {
+	s.seasonPackMinMissing = f
+}
+
+// PlanSeason decides how to search for a season given all of its episodes.
+// Episodes are missing when wanted and aired; one without an air date counts
+// as aired. When fewer than the configured fraction of the season is missing,
+// the missing episodes are searched individually rather than grabbing a large
+// pack for a few episodes, or a pack of a current season holding only the
+// episodes aired so far. An empty episode list searches for a season pack.
+func (s *Searcher) PlanSeason(episodes []*library.Episode) SeasonPlan {
+	if len(episodes) == 0 {
+		return SeasonPlan{Strategy: StrategySeasonPack, Reason: "season pack: episode list unknown"}
+	}
+
+	now := time.Now()
+	var plan SeasonPlan
+	for _, ep := range episodes {
+		if ep.AirDate != nil && ep.AirDate.After(now) {
+			continue
+		}
+		plan.Aired++
+		if ep.Status == library.StatusWanted {
+			plan.Missing = append(plan.Missing, ep)
+		}
+	}
+	if len(plan.Missing) == 0 {
+		return plan
+	}
+
+	minMissing := s.seasonPackMinMissing
+	if minMissing == 0 {
+		minMissing = DefaultSeasonPackMinMissing
+	}
+	counts := fmt.Sprintf("%d of %d episodes missing", len(plan.Missing), len(episodes))
+	if float64(len(plan.Missing)) < minMissing*float64(len(episodes)) {
+		plan.Strategy = StrategyEpisodes
+		plan.Reason = fmt.Sprintf("single episodes: %s, below %.0f%%", counts, minMissing*100)
+	} else {
+		plan.Strategy = StrategySeasonPack
+		plan.Reason = "season pack: " + counts
+	}
+	return plan
+}
+
+// SeasonProfile returns the quality profile for a season pack given the
+// season's episodes: their override when every episode shares one, otherwise
+// the series profile.
+func SeasonProfile(episodes []*library.Episode, seriesProfile string) string {
+	if len(episodes) == 0 {
+		return seriesProfile
+	}
+	override := episodes[0].QualityProfile
+	for _, ep := range episodes[1:] {
+		if ep.QualityProfile != override {
+			return seriesProfile
+		}
+	}
+	if override == "" {
+		return seriesProfile
+	}
+	return override
+}
+
+// SearchSeason searches for a season of a series as planned and returns a grab
+// request for the best release of each search that found one: a season pack,
+// or a release per missing episode. Episodes use their own profile override,
+// falling back to profile. Failed episode searches are logged and skipped;
+// the search stops early if ctx is canceled.
+func (s *Searcher) SearchSeason(ctx context.Context, contentID int64, title, profile string, season int, plan SeasonPlan) []*events.GrabRequested {
+	return s.searchSeason(ctx, contentID, title, profile, season, plan, s.Search)
+}
+
+// searchFunc runs a search; Searcher.Search or a wrapper of it.
+type searchFunc func(ctx context.Context, q Query, profile string) (*Result, error)
+
+// searchSeason is SearchSeason running each query through search.
+func (s *Searcher) searchSeason(ctx context.Context, contentID int64, title, profile string, season int, plan SeasonPlan, search searchFunc) []*events.GrabRequested {
+	if plan.Strategy == StrategySeasonPack {
+		q := Query{
+			ContentID:      contentID,
+			Text:           fmt.Sprintf("%s S%02d", title, season),
+			Type:           "series",
+			Season:         &season, // Signal we want season packs, not individual episodes
+			SeasonEpisodes: plan.Aired,
+		}
+		result, err := search(ctx, q, profile)
+		if err != nil || len(result.Releases) == 0 {
+			return nil
+		}
+		best := result.Releases[0]
+		grab := seasonGrab(contentID, season, result, best, profile, plan)
+		grab.IsCompleteSeason = true
+		return []*events.GrabRequested{grab}
+	}
+
+	var grabs []*events.GrabRequested
+	for _, ep := range plan.Missing {
+		if ctx.Err() != nil {
+			break
+		}
+		episode := ep.Episode
+		q := Query{
+			ContentID: contentID,
+			Text:      fmt.Sprintf("%s S%02dE%02d", title, season, episode),
+			Type:      "series",
+			Season:    &season,
+			Episode:   &episode,
+		}
+		epProfile, _ := ep.EffectiveProfile(profile)
+		result, err := search(ctx, q, epProfile)
+		if err != nil {
+			s.log.Warn("episode search failed", "content_id", contentID, "season", season, "episode", episode, "error", err)
+			continue
+		}
+		if len(result.Releases) == 0 {
+			continue
+		}
+		grab := seasonGrab(contentID, season, result, result.Releases[0], epProfile, plan)
+		grab.EpisodeID = &ep.ID
+		grab.EpisodeIDs = []int64{ep.ID}
+		grabs = append(grabs, grab)
+	}
+	return grabs
+}
+
+// seasonGrab builds the grab request for a release found by a season search,
+// noting the plan's strategy on the decision.
+func seasonGrab(contentID int64, season int, result *Result, best *Release, profile string, plan SeasonPlan) *events.GrabRequested {
+	decision := NewGrabDecision(result, best, profile)
+	decision.Strategy, decision.StrategyReason = plan.Strategy, plan.Reason
+	return &events.GrabRequested{
+		BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+		ContentID:   contentID,
+		Season:      &season,
+		DownloadURL: best.DownloadURL,
+		ReleaseName: best.Title,
+		Indexer:     best.Indexer,
+		GUID:        best.GUID,
+		Protocol:    string(best.Protocol),
+		Decision:    decision,
+		Alternates:  GrabAlternates(result, best),
+	}
+}
@@ -0,0 +1,139 @@
+package search_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/search"
+	"github.com/vmunix/arrgo/internal/search/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+// seasonEpisodes returns a season of n episodes; the first aired are
+// available or wanted as given, the rest air next week.
+func seasonEpisodes(n int, statuses ...library.ContentStatus) []*library.Episode {
+	past := time.Now().AddDate(0, -1, 0)
+	future := time.Now().AddDate(0, 0, 7)
+	episodes := make([]*library.Episode, n)
+	for i := range episodes {
+		ep := &library.Episode{ID: int64(i + 1), Season: 1, Episode: i + 1, Status: library.StatusWanted, AirDate: &future}
+		if i < len(statuses) {
+			ep.Status, ep.AirDate = statuses[i], &past
+		}
+		episodes[i] = ep
+	}
+	return episodes
+}
+
+func TestSearcher_PlanSeason(t *testing.T) {
+	const (
+		have = library.StatusAvailable
+		want = library.StatusWanted
+	)
+	searcher := search.NewSearcher(nil, search.NewScorer(nil), testLogger())
+
+	tests := []struct {
+		name         string
+		episodes     []*library.Episode
+		minMissing   float64
+		wantStrategy string
+		wantReason   string
+		wantMissing  int
+		wantAired    int
+	}{
+		{
+			name:         "no episode list",
+			wantStrategy: search.StrategySeasonPack,
+			wantReason:   "season pack: episode list unknown",
+		},
+		{
+			name:         "whole season missing",
+			episodes:     seasonEpisodes(4, want, want, want, want),
+			wantStrategy: search.StrategySeasonPack,
+			wantReason:   "season pack: 4 of 4 episodes missing",
+			wantMissing:  4,
+			wantAired:    4,
+		},
+		{
+			name:         "only the finale missing",
+			episodes:     seasonEpisodes(10, have, have, have, have, have, have, have, have, have, want),
+			wantStrategy: search.StrategyEpisodes,
+			wantReason:   "single episodes: 1 of 10 episodes missing, below 50%",
+			wantMissing:  1,
+			wantAired:    10,
+		},
+		{
+			name:         "current season partly aired",
+			episodes:     seasonEpisodes(10, want, want, want),
+			wantStrategy: search.StrategyEpisodes,
+			wantReason:   "single episodes: 3 of 10 episodes missing, below 50%",
+			wantMissing:  3,
+			wantAired:    3,
+		},
+		{
+			name:         "negative threshold always searches packs",
+			episodes:     seasonEpisodes(10, have, have, have, have, have, have, have, have, have, want),
+			minMissing:   -1,
+			wantStrategy: search.StrategySeasonPack,
+			wantReason:   "season pack: 1 of 10 episodes missing",
+			wantMissing:  1,
+			wantAired:    10,
+		},
+		{
+			name:        "nothing missing",
+			episodes:    seasonEpisodes(2, have, have),
+			wantAired:   2,
+			wantMissing: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher.SetSeasonPackMinMissing(tt.minMissing)
+			plan := searcher.PlanSeason(tt.episodes)
+			assert.Equal(t, tt.wantStrategy, plan.Strategy)
+			assert.Equal(t, tt.wantReason, plan.Reason)
+			assert.Len(t, plan.Missing, tt.wantMissing)
+			assert.Equal(t, tt.wantAired, plan.Aired)
+		})
+	}
+}
+
+func TestSearcher_SearchSeason_Episodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClient := mocks.NewMockIndexerAPI(ctrl)
+	mockClient.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query) ([]search.Release, []error) {
+			require.NotNil(t, q.Season)
+			require.NotNil(t, q.Episode, "single episodes are searched")
+			assert.Equal(t, "Show S01E10", q.Text)
+			return []search.Release{
+				{Title: "Show.S01E10.1080p.WEB-DL.x264-GRP", GUID: "e10", Indexer: "test", DownloadURL: "http://nzb/e10"},
+				{Title: "Show.S01.1080p.WEB-DL.x264-GRP", GUID: "pack", Indexer: "test", DownloadURL: "http://nzb/pack"},
+			}, nil
+		})
+	scorer := search.NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+	searcher := search.NewSearcher(mockClient, scorer, testLogger())
+
+	const have = library.StatusAvailable
+	episodes := seasonEpisodes(10, have, have, have, have, have, have, have, have, have, library.StatusWanted)
+	plan := searcher.PlanSeason(episodes)
+	require.Equal(t, search.StrategyEpisodes, plan.Strategy)
+
+	grabs := searcher.SearchSeason(context.Background(), 7, "Show", "hd", 1, plan)
+	require.Len(t, grabs, 1)
+	grab := grabs[0]
+	assert.Equal(t, int64(7), grab.ContentID)
+	assert.Equal(t, "e10", grab.GUID)
+	assert.False(t, grab.IsCompleteSeason)
+	assert.Equal(t, []int64{10}, grab.EpisodeIDs)
+	require.NotNil(t, grab.Decision)
+	assert.Equal(t, search.StrategyEpisodes, grab.Decision.Strategy)
+	assert.Equal(t, "single episodes: 1 of 10 episodes missing, below 50%", grab.Decision.StrategyReason)
+}
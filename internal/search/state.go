@@ -0,0 +1,81 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// state is the on-disk form of the result cache and indexer usage.
+type state struct {
+	Entries []*cacheEntry  `json:"entries,omitempty"` // Least recently used first
+	Day     string         `json:"day,omitempty"`     // UTC date of Calls
+	Calls   map[string]int `json:"calls,omitempty"`
+}
+
+// SaveState writes the unexpired cache entries and today's indexer usage to
+// path, so they survive a restart. Either argument may be nil.
+func SaveState(path string, cache *ResultCache, budget *Budget) error {
+	var st state
+	if cache != nil {
+		st.Entries = cache.entries()
+	}
+	if budget != nil {
+		st.Day, st.Calls = budget.snapshot()
+	}
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("encode search state: %w", err)
+	}
+	// Write then rename so a crash never leaves a truncated file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".search-state-*")
+	if err != nil {
+		return fmt.Errorf("save search state: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("save search state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("save search state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("save search state: %w", err)
+	}
+	return nil
+}
+
+// LoadState restores state written by SaveState. Expired entries and usage
+// from an earlier day are dropped. A missing file is not an error.
+func LoadState(path string, cache *ResultCache, budget *Budget) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load search state: %w", err)
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("decode search state: %w", err)
+	}
+	if cache != nil {
+		now := cache.now()
+		for _, entry := range st.Entries {
+			if entry != nil && now.Sub(entry.StoredAt) < cache.ttl {
+				cache.put(entry)
+			}
+		}
+	}
+	if budget != nil {
+		budget.restore(st.Day, st.Calls)
+	}
+	return nil
+}
@@ -0,0 +1,342 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// MissingLister lists wanted items that have no file.
+// Satisfied by *library.Store.
+type MissingLister interface {
+	ListMissing(f library.WantedFilter) ([]*library.WantedItem, int, error)
+}
+
+// DownloadLister lists download records.
+// Satisfied by *download.Store.
+type DownloadLister interface {
+	List(f download.Filter) ([]*download.Download, int, error)
+}
+
+// CutoffLister lists wanted movies and episodes that have a file, with the
+// best quality on disk. Satisfied by *library.Store.
+type CutoffLister interface {
+	ListWithFiles(f library.WantedFilter) ([]*library.WantedItem, error)
+}
+
+// SeriesLister reads series and their episodes.
+// Satisfied by *library.Store.
+type SeriesLister interface {
+	GetContent(id int64) (*library.Content, error)
+	ListEpisodes(f library.EpisodeFilter) ([]*library.Episode, int, error)
+}
+
+// Publisher publishes events.
+// Satisfied by *events.Bus.
+type Publisher interface {
+	Publish(ctx context.Context, e events.Event) error
+}
+
+// WantedSearcher periodically searches for missing movies and audiobooks and
+// requests a grab of the best release. Movies are only listed as missing once
+// they reach their minimum availability, so unreleased movies are picked up
+// when their date arrives.
+// With a series lister set, seasons with aired missing episodes are searched
+// too, as a season pack or episode by episode (see Searcher.PlanSeason).
+// Content with an active download is skipped. Releases held back for the
+// minimum age are remembered and grabbed by a later search once old enough.
+// With a pin reader set, content with a pinned release grabs it unsearched.
+// With a cutoff lister set, movies and episodes whose best file is below the
+// cutoff their profile sets are searched too, until a file meets it.
+type WantedSearcher struct {
+	searcher  *Searcher
+	library   MissingLister
+	series    SeriesLister // nil if series are not searched
+	pins      PinReader    // nil if pins are not read
+	withFiles CutoffLister // nil if content below cutoff is not searched
+	downloads DownloadLister
+	bus       Publisher
+	deferred  *deferrals
+	log       *slog.Logger
+}
+
+// NewWantedSearcher creates a wanted searcher.
+func NewWantedSearcher(searcher *Searcher, lib MissingLister, downloads DownloadLister, bus Publisher, log *slog.Logger) *WantedSearcher {
+	return &WantedSearcher{
+		searcher:  searcher,
+		library:   lib,
+		downloads: downloads,
+		bus:       bus,
+		deferred:  newDeferrals(),
+		log:       log,
+	}
+}
+
+// SetSeries enables searching for missing series episodes.
+func (w *WantedSearcher) SetSeries(l SeriesLister) {
+	w.series = l
+}
+
+// SetCutoffLister enables searching for movies and episodes whose best file is
+// below the cutoff set by their profile.
+func (w *WantedSearcher) SetCutoffLister(l CutoffLister) {
+	w.withFiles = l
+}
+
+// seasonKey identifies a season of a series.
+type seasonKey struct {
+	contentID int64
+	season    int
+}
+
+// SearchMissing searches once for every missing movie and audiobook and,
+// with a series lister set, every season with missing episodes.
+// Failures for individual items are logged and skipped.
+// Returns the number of grabs requested.
+func (w *WantedSearcher) SearchMissing(ctx context.Context) (int, error) {
+	return w.searchMissing(ctx, library.WantedFilter{})
+}
+
+// SearchContent searches once for a content item's missing movie or
+// audiobook or, with a series lister set, each of its seasons with missing
+// episodes.
+// Returns the number of grabs requested.
+func (w *WantedSearcher) SearchContent(ctx context.Context, contentID int64) (int, error) {
+	return w.searchMissing(ctx, library.WantedFilter{ContentID: &contentID})
+}
+
+func (w *WantedSearcher) searchMissing(ctx context.Context, filter library.WantedFilter) (int, error) {
+	items, _, err := w.library.ListMissing(filter)
+	if err != nil {
+		return 0, fmt.Errorf("list missing: %w", err)
+	}
+
+	grabbed := 0
+	searched := make(map[seasonKey]bool)
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return grabbed, ctx.Err()
+		}
+
+		if item.Type == library.ContentTypeSeries {
+			if w.series == nil {
+				continue
+			}
+			key := seasonKey{item.ContentID, item.Season}
+			if searched[key] {
+				continue
+			}
+			searched[key] = true
+		}
+
+		contentID := item.ContentID
+		if w.downloading(contentID) {
+			continue
+		}
+
+		if item.Type == library.ContentTypeSeries {
+			season := item.Season
+			if w.grabPinned(ctx, contentID, &season) {
+				grabbed++
+				continue
+			}
+			grabbed += w.searchSeason(ctx, contentID, item.Season)
+			continue
+		}
+		if w.grabPinned(ctx, contentID, nil) {
+			grabbed++
+			continue
+		}
+
+		q := ContentQuery(&library.Content{ID: contentID, Type: item.Type, Title: item.Title, Year: item.Year, Author: item.Author}, nil, nil)
+		result, err := w.search(ctx, q, item.QualityProfile)
+		if err != nil {
+			w.log.Warn("wanted search failed", "content_id", contentID, "title", item.Title, "error", err)
+			continue
+		}
+		if len(result.Releases) == 0 {
+			continue
+		}
+
+		best := result.Releases[0]
+		if err := w.bus.Publish(ctx, &events.GrabRequested{
+			BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+			ContentID:   contentID,
+			DownloadURL: best.DownloadURL,
+			ReleaseName: best.Title,
+			Indexer:     best.Indexer,
+			GUID:        best.GUID,
+			Protocol:    string(best.Protocol),
+			Decision:    NewGrabDecision(result, best, item.QualityProfile),
+			Alternates:  GrabAlternates(result, best),
+		}); err != nil {
+			w.log.Error("failed to publish GrabRequested", "content_id", contentID, "error", err)
+			continue
+		}
+		grabbed++
+	}
+	if w.withFiles != nil {
+		grabbed += w.searchCutoffUnmet(ctx, filter)
+	}
+	return grabbed, nil
+}
+
+// downloading reports whether content has an active download, or its
+// downloads could not be checked.
+func (w *WantedSearcher) downloading(contentID int64) bool {
+	active, _, err := w.downloads.List(download.Filter{ContentID: &contentID, Active: true, Limit: 1})
+	if err != nil {
+		w.log.Warn("check active downloads failed", "content_id", contentID, "error", err)
+		return true
+	}
+	return len(active) > 0
+}
+
+// searchCutoffUnmet searches for movies and episodes whose best file is below
+// the cutoff set by their profile, and requests a grab of the best release of
+// a higher resolution than the file. Content whose profile sets no cutoff is
+// left alone. Returns the number of grabs requested.
+func (w *WantedSearcher) searchCutoffUnmet(ctx context.Context, filter library.WantedFilter) int {
+	items, err := w.withFiles.ListWithFiles(filter)
+	if err != nil {
+		w.log.Warn("list content with files failed", "error", err)
+		return 0
+	}
+
+	scorer := w.searcher.scorer
+	grabbed := 0
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		if !scorer.HasCutoff(item.QualityProfile) || scorer.MeetsCutoff(item.Quality, item.QualityProfile) {
+			continue
+		}
+		if item.Type == library.ContentTypeSeries && w.series == nil {
+			continue
+		}
+		if w.downloading(item.ContentID) {
+			continue
+		}
+
+		var season, episode *int
+		if item.EpisodeID != nil {
+			season, episode = &item.Season, &item.Episode
+		}
+		q := ContentQuery(&library.Content{ID: item.ContentID, Type: item.Type, Title: item.Title, Year: item.Year}, season, episode)
+		result, err := w.search(ctx, q, item.QualityProfile)
+		if err != nil {
+			w.log.Warn("cutoff search failed", "content_id", item.ContentID, "title", item.Title, "error", err)
+			continue
+		}
+		var best *Release
+		for _, rel := range result.Releases {
+			if rel.Quality != nil && library.QualityRank(rel.Quality.Resolution.String()) > library.QualityRank(item.Quality) {
+				best = rel
+				break
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		grab := &events.GrabRequested{
+			BaseEvent:   events.NewBaseEvent(events.EventGrabRequested, events.EntityDownload, 0),
+			ContentID:   item.ContentID,
+			DownloadURL: best.DownloadURL,
+			ReleaseName: best.Title,
+			Indexer:     best.Indexer,
+			GUID:        best.GUID,
+			Protocol:    string(best.Protocol),
+			Decision:    NewGrabDecision(result, best, item.QualityProfile),
+			Alternates:  GrabAlternates(result, best),
+		}
+		if item.EpisodeID != nil {
+			grab.Season = season
+			grab.EpisodeID = item.EpisodeID
+			grab.EpisodeIDs = []int64{*item.EpisodeID}
+		}
+		if err := w.bus.Publish(ctx, grab); err != nil {
+			w.log.Error("failed to publish GrabRequested", "content_id", item.ContentID, "error", err)
+			continue
+		}
+		w.log.Info("grabbing release above file quality", "content_id", item.ContentID, "quality", item.Quality, "release", best.Title)
+		grabbed++
+	}
+	return grabbed
+}
+
+// search runs a wanted search for q, adding the releases deferred by earlier
+// searches for it that have since reached the minimum age.
+func (w *WantedSearcher) search(ctx context.Context, q Query, profile string) (*Result, error) {
+	result, err := w.searcher.Search(ctx, q, profile)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%d|%s|%s", q.ContentID, cacheKey(q), profile)
+	if w.deferred.recall(key, result, time.Now()) {
+		w.searcher.sortResult(result, profile)
+	}
+	if len(result.Deferred) > 0 {
+		w.log.Debug("releases deferred for minimum age", "query", q.Text, "deferred", len(result.Deferred),
+			"until", result.Deferred[0].Until.Format(time.RFC3339))
+	}
+	return result, nil
+}
+
+// searchSeason searches for the missing episodes of a season and requests
+// grabs for what is found. Returns the number of grabs requested.
+func (w *WantedSearcher) searchSeason(ctx context.Context, contentID int64, season int) int {
+	series, err := w.series.GetContent(contentID)
+	if err != nil {
+		w.log.Warn("get series failed", "content_id", contentID, "error", err)
+		return 0
+	}
+	episodes, _, err := w.series.ListEpisodes(library.EpisodeFilter{ContentID: &contentID, Season: &season})
+	if err != nil {
+		w.log.Warn("list episodes failed", "content_id", contentID, "season", season, "error", err)
+		return 0
+	}
+	plan := w.searcher.PlanSeason(episodes)
+	if plan.Strategy == "" {
+		return 0
+	}
+
+	grabbed := 0
+	profile := SeasonProfile(episodes, series.QualityProfile)
+	for _, grab := range w.searcher.searchSeason(ctx, contentID, series.Title, profile, season, plan, w.search) {
+		if err := w.bus.Publish(ctx, grab); err != nil {
+			w.log.Error("failed to publish GrabRequested", "content_id", contentID, "error", err)
+			continue
+		}
+		grabbed++
+	}
+	return grabbed
+}
+
+// Run searches for missing content every interval until the context is canceled.
+// The first search runs after one interval, not at startup.
+func (w *WantedSearcher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		n, err := w.SearchMissing(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.log.Warn("wanted search job failed", "error", err)
+		} else if n > 0 {
+			w.log.Info("wanted search requested grabs", "count", n)
+		}
+	}
+}
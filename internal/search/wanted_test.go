@@ -0,0 +1,271 @@
+package search_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/config"
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/events"
+	"github.com/vmunix/arrgo/internal/library"
+	"github.com/vmunix/arrgo/internal/search"
+	"github.com/vmunix/arrgo/internal/search/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeMissing struct {
+	items []*library.WantedItem
+}
+
+func (f *fakeMissing) ListMissing(_ library.WantedFilter) ([]*library.WantedItem, int, error) {
+	return f.items, len(f.items), nil
+}
+
+type fakeDownloads struct {
+	active map[int64]bool // content ID -> has active download
+}
+
+func (f *fakeDownloads) List(filter download.Filter) ([]*download.Download, int, error) {
+	if filter.ContentID != nil && f.active[*filter.ContentID] {
+		return []*download.Download{{ContentID: *filter.ContentID}}, 1, nil
+	}
+	return nil, 0, nil
+}
+
+type fakePublisher struct {
+	events []events.Event
+}
+
+func (f *fakePublisher) Publish(_ context.Context, e events.Event) error {
+	f.events = append(f.events, e)
+	return nil
+}
+
+func TestWantedSearcher_SearchMissing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	indexers := mocks.NewMockIndexerAPI(ctrl)
+	indexers.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query) ([]search.Release, []error) {
+			assert.Equal(t, int64(1), q.ContentID, "only the movie without an active download is searched")
+			assert.Equal(t, "Dune 2024", q.Text)
+			return []search.Release{
+				{Title: "Dune.2024.1080p.BluRay.x264-GROUP", GUID: "g1", Indexer: "nzbgeek", DownloadURL: "http://nzb/1"},
+			}, nil
+		})
+
+	scorer := search.NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+	searcher := search.NewSearcher(indexers, scorer, testLogger())
+
+	lib := &fakeMissing{items: []*library.WantedItem{
+		{ContentID: 1, Type: library.ContentTypeMovie, Title: "Dune", Year: 2024, QualityProfile: "hd"},
+		{ContentID: 2, Type: library.ContentTypeMovie, Title: "Busy", Year: 2024, QualityProfile: "hd"},
+	}}
+	bus := &fakePublisher{}
+	w := search.NewWantedSearcher(searcher, lib, &fakeDownloads{active: map[int64]bool{2: true}}, bus, testLogger())
+
+	n, err := w.SearchMissing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	require.Len(t, bus.events, 1)
+	grab, ok := bus.events[0].(*events.GrabRequested)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), grab.ContentID)
+	assert.Equal(t, "g1", grab.GUID)
+	assert.Equal(t, "http://nzb/1", grab.DownloadURL)
+}
+
+func TestWantedSearcher_SearchMissing_MinimumAge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	published := time.Now().Add(-5 * time.Minute)
+	indexers := mocks.NewMockIndexerAPI(ctrl)
+	indexers.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		Return([]search.Release{
+			{Title: "Dune.2024.1080p.BluRay.x264-GROUP", GUID: "g1", Indexer: "nzbgeek", DownloadURL: "http://nzb/1", PublishDate: published},
+		}, nil)
+	indexers.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	scorer := search.NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+	scorer.SetMinimumAge(time.Hour, nil)
+	searcher := search.NewSearcher(indexers, scorer, testLogger())
+
+	lib := &fakeMissing{items: []*library.WantedItem{
+		{ContentID: 1, Type: library.ContentTypeMovie, Title: "Dune", Year: 2024, QualityProfile: "hd"},
+	}}
+	bus := &fakePublisher{}
+	w := search.NewWantedSearcher(searcher, lib, &fakeDownloads{}, bus, testLogger())
+
+	n, err := w.SearchMissing(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n, "too new to grab")
+
+	// The indexer no longer returns it; it is remembered but still too new
+	n, err = w.SearchMissing(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Empty(t, bus.events)
+}
+
+type fakeSeries struct {
+	series   *library.Content
+	episodes []*library.Episode
+}
+
+func (f *fakeSeries) GetContent(_ int64) (*library.Content, error) {
+	return f.series, nil
+}
+
+func (f *fakeSeries) ListEpisodes(_ library.EpisodeFilter) ([]*library.Episode, int, error) {
+	return f.episodes, len(f.episodes), nil
+}
+
+func TestWantedSearcher_SearchMissing_Series(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// Most of the season is missing, so one season pack search covers both episodes
+	indexers := mocks.NewMockIndexerAPI(ctrl)
+	indexers.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query) ([]search.Release, []error) {
+			assert.Equal(t, "Show S01", q.Text)
+			assert.Nil(t, q.Episode)
+			assert.Equal(t, 3, q.SeasonEpisodes)
+			return []search.Release{
+				{Title: "Show.S01.1080p.WEB-DL.x264-GRP", GUID: "pack", Indexer: "test", DownloadURL: "http://nzb/pack"},
+			}, nil
+		})
+	scorer := search.NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+	searcher := search.NewSearcher(indexers, scorer, testLogger())
+
+	e1, e2 := int64(1), int64(2)
+	lib := &fakeMissing{items: []*library.WantedItem{
+		{ContentID: 5, Type: library.ContentTypeSeries, Title: "Show", QualityProfile: "hd", EpisodeID: &e1, Season: 1, Episode: 1},
+		{ContentID: 5, Type: library.ContentTypeSeries, Title: "Show", QualityProfile: "hd", EpisodeID: &e2, Season: 1, Episode: 2},
+	}}
+	series := &fakeSeries{
+		series:   &library.Content{ID: 5, Type: library.ContentTypeSeries, Title: "Show", QualityProfile: "hd"},
+		episodes: seasonEpisodes(3, library.StatusWanted, library.StatusWanted, library.StatusAvailable),
+	}
+	bus := &fakePublisher{}
+	w := search.NewWantedSearcher(searcher, lib, &fakeDownloads{}, bus, testLogger())
+	w.SetSeries(series)
+
+	n, err := w.SearchMissing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	require.Len(t, bus.events, 1)
+	grab, ok := bus.events[0].(*events.GrabRequested)
+	require.True(t, ok)
+	assert.Equal(t, "pack", grab.GUID)
+	assert.True(t, grab.IsCompleteSeason)
+	require.NotNil(t, grab.Season)
+	assert.Equal(t, 1, *grab.Season)
+	assert.Equal(t, search.StrategySeasonPack, grab.Decision.Strategy)
+}
+
+type fakePins map[int64]*library.PinnedRelease
+
+func (f fakePins) PinnedRelease(contentID int64) (*library.PinnedRelease, error) {
+	return f[contentID], nil
+}
+
+func TestWantedSearcher_SearchMissing_Pinned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// Only the movie without a pin is searched
+	indexers := mocks.NewMockIndexerAPI(ctrl)
+	indexers.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query) ([]search.Release, []error) {
+			assert.Equal(t, int64(2), q.ContentID)
+			return nil, nil
+		})
+	scorer := search.NewScorer(map[string]config.QualityProfile{"hd": {Resolution: []string{"1080p"}}})
+	searcher := search.NewSearcher(indexers, scorer, testLogger())
+
+	season := 1
+	lib := &fakeMissing{items: []*library.WantedItem{
+		{ContentID: 1, Type: library.ContentTypeMovie, Title: "Dune", Year: 2024, QualityProfile: "hd"},
+		{ContentID: 2, Type: library.ContentTypeMovie, Title: "Plain", Year: 2024, QualityProfile: "hd"},
+		{ContentID: 5, Type: library.ContentTypeSeries, Title: "Show", QualityProfile: "hd", Season: 1, Episode: 1},
+	}}
+	pins := fakePins{
+		1: {GUID: "pin", DownloadURL: "http://nzb/pin", Title: "Dune.2024.2160p.WEB-DL-GRP", Indexer: "manual"},
+		5: {DownloadURL: "http://nzb/s01", Title: "Show.S01.1080p.WEB-DL-GRP", Season: &season},
+	}
+	bus := &fakePublisher{}
+	w := search.NewWantedSearcher(searcher, lib, &fakeDownloads{}, bus, testLogger())
+	w.SetSeries(&fakeSeries{})
+	w.SetPins(pins)
+
+	n, err := w.SearchMissing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	require.Len(t, bus.events, 2)
+	movie := bus.events[0].(*events.GrabRequested)
+	assert.True(t, movie.Pinned)
+	assert.Equal(t, "pin", movie.GUID)
+	assert.Nil(t, movie.Decision, "pinned grabs are not scored")
+	pack := bus.events[1].(*events.GrabRequested)
+	assert.True(t, pack.Pinned)
+	assert.True(t, pack.IsCompleteSeason)
+	assert.Equal(t, &season, pack.Season)
+}
+
+type fakeWithFiles struct {
+	items []*library.WantedItem
+}
+
+func (f *fakeWithFiles) ListWithFiles(_ library.WantedFilter) ([]*library.WantedItem, error) {
+	return f.items, nil
+}
+
+func TestWantedSearcher_SearchMissing_CutoffUnmet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// Only the movie below its profile's cutoff is searched
+	indexers := mocks.NewMockIndexerAPI(ctrl)
+	indexers.EXPECT().
+		Search(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q search.Query) ([]search.Release, []error) {
+			assert.Equal(t, int64(1), q.ContentID)
+			return []search.Release{
+				{Title: "Dune.2024.720p.BluRay.x264-GROUP", GUID: "same", Indexer: "test", DownloadURL: "http://nzb/720"},
+				{Title: "Dune.2024.1080p.WEB-DL.x264-GROUP", GUID: "better", Indexer: "test", DownloadURL: "http://nzb/1080"},
+			}, nil
+		})
+	scorer := search.NewScorer(map[string]config.QualityProfile{
+		"hd":    {Resolution: []string{"720p", "1080p"}, Cutoff: "1080p"},
+		"plain": {Resolution: []string{"720p", "1080p"}},
+	})
+	searcher := search.NewSearcher(indexers, scorer, testLogger())
+
+	withFiles := &fakeWithFiles{items: []*library.WantedItem{
+		{ContentID: 1, Type: library.ContentTypeMovie, Title: "Dune", Year: 2024, QualityProfile: "hd", Quality: "720p"},
+		{ContentID: 2, Type: library.ContentTypeMovie, Title: "Done", Year: 2024, QualityProfile: "hd", Quality: "1080p"},
+		{ContentID: 3, Type: library.ContentTypeMovie, Title: "Plain", Year: 2024, QualityProfile: "plain", Quality: "720p"},
+	}}
+	bus := &fakePublisher{}
+	w := search.NewWantedSearcher(searcher, &fakeMissing{}, &fakeDownloads{}, bus, testLogger())
+	w.SetCutoffLister(withFiles)
+
+	n, err := w.SearchMissing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	require.Len(t, bus.events, 1)
+	grab, ok := bus.events[0].(*events.GrabRequested)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), grab.ContentID)
+	assert.Equal(t, "better", grab.GUID, "only a release above the file's quality is grabbed")
+	require.NotNil(t, grab.Decision)
+}
@@ -14,18 +14,38 @@ import (
 	"github.com/vmunix/arrgo/internal/download"
 	"github.com/vmunix/arrgo/internal/events"
 	"github.com/vmunix/arrgo/internal/handlers"
+	"github.com/vmunix/arrgo/internal/importer"
 	"github.com/vmunix/arrgo/internal/library"
 	"golang.org/x/sync/errgroup"
 )
 
 // Config for the event-driven server.
 type Config struct {
-	SABnzbdPollInterval time.Duration // How often to poll SABnzbd (default: 5s)
-	PlexPollInterval    time.Duration // How often to poll Plex (default: 60s)
-	DownloadRoot        string
-	DownloadRemotePath  string // Path prefix as seen by SABnzbd
-	DownloadLocalPath   string // Local path prefix
-	CleanupEnabled      bool
+	Clients          []ClientConfig // Download clients to poll; each must be registered with the Manager
+	PlexPollInterval time.Duration  // How often to poll Plex (default: 60s)
+	DownloadRoot     string
+	CleanupEnabled   bool
+	PreCleanupHook   *importer.Hook          // Run before deleting source files (optional)
+	GrabFallbacks    int                     // Alternate releases tried when a client rejects a grab (default: 3; negative disables)
+	GrabURLs         handlers.GrabURLs       // Picks the indexer API key for each grab (optional)
+	Outbox           bool                    // Deliver durable events through the event log outbox
+	Samples          *download.Samples       // Records download progress on each poll (optional)
+	TorrentDefaults  download.TorrentOptions // Torrent options of grabs that don't choose them
+	// How long a download may stay in each status before DownloadStuck is emitted (nil: defaults)
+	StuckAlertThresholds map[download.Status]time.Duration
+	// Leftovers that don't keep a release directory from being removed after import (zero: none)
+	Junk importer.JunkRules
+
+	ImportConcurrency int // Imports run at once (default: 2)
+}
+
+// ClientConfig configures polling and categories for one named download client.
+type ClientConfig struct {
+	Name         download.Client
+	PollInterval time.Duration // How often to poll the client (default: 5s)
+	RemotePath   string        // Path prefix as seen by the client
+	LocalPath    string        // Local path prefix
+	Categories   download.Categories
 }
 
 // Runner manages the event-driven components.
@@ -35,7 +55,7 @@ type Runner struct {
 	logger *slog.Logger
 
 	// Dependencies
-	downloader  download.Downloader
+	clients     *download.Manager
 	importer    handlers.FileImporter
 	plexChecker plex.Checker // Can be nil if Plex not configured
 
@@ -43,10 +63,13 @@ type Runner struct {
 	startOnce sync.Once
 	bus       *events.Bus
 	eventLog  *events.EventLog
+
+	mu      sync.Mutex
+	imports *handlers.ImportHandler // Set by Run
 }
 
 // NewRunner creates a new runner.
-func NewRunner(db *sql.DB, cfg Config, logger *slog.Logger, downloader download.Downloader, importer handlers.FileImporter, plexChecker plex.Checker) *Runner {
+func NewRunner(db *sql.DB, cfg Config, logger *slog.Logger, clients *download.Manager, importer handlers.FileImporter, plexChecker plex.Checker) *Runner {
 	if logger == nil {
 		logger = slog.Default()
 	}
@@ -54,7 +77,7 @@ func NewRunner(db *sql.DB, cfg Config, logger *slog.Logger, downloader download.
 		db:          db,
 		config:      cfg,
 		logger:      logger,
-		downloader:  downloader,
+		clients:     clients,
 		importer:    importer,
 		plexChecker: plexChecker,
 	}
@@ -67,6 +90,9 @@ func (r *Runner) Start() *events.Bus {
 	r.startOnce.Do(func() {
 		r.eventLog = events.NewEventLog(r.db)
 		r.bus = events.NewBus(r.eventLog, r.logger.With("component", "bus"))
+		if r.config.Outbox {
+			r.bus.EnableOutbox(events.DefaultRegistry())
+		}
 	})
 	return r.bus
 }
@@ -89,12 +115,59 @@ func (r *Runner) Run(ctx context.Context) error {
 	libraryStore := library.NewStore(r.db)
 
 	// Create handlers
-	downloadHandler := handlers.NewDownloadHandler(r.bus, downloadStore, libraryStore, r.downloader, r.logger.With("handler", "download"))
+	downloadHandler := handlers.NewDownloadHandler(r.bus, downloadStore, libraryStore, r.clients, r.logger.With("handler", "download"))
+	downloadHandler.SetHistory(importer.NewHistoryStore(r.db))
+	if r.config.GrabFallbacks != 0 {
+		downloadHandler.SetMaxFallbacks(r.config.GrabFallbacks)
+	}
+	if r.config.GrabURLs != nil {
+		downloadHandler.SetGrabURLs(r.config.GrabURLs)
+	}
+	for _, c := range r.config.Clients {
+		downloadHandler.SetCategories(c.Name, c.Categories)
+	}
+	downloadHandler.SetTorrentDefaults(r.config.TorrentDefaults)
 	importHandler := handlers.NewImportHandler(r.bus, downloadStore, libraryStore, r.importer, r.logger.With("handler", "import"))
+	importHandler.SetDownloadRoot(r.config.DownloadRoot)
+	importHandler.SetConcurrency(r.config.ImportConcurrency)
+	r.mu.Lock()
+	r.imports = importHandler
+	r.mu.Unlock()
 	cleanupHandler := handlers.NewCleanupHandler(r.bus, downloadStore, handlers.CleanupConfig{
 		DownloadRoot: r.config.DownloadRoot,
 		Enabled:      r.config.CleanupEnabled,
+		PreCleanup:   r.config.PreCleanupHook,
+		Junk:         r.config.Junk,
 	}, r.logger.With("handler", "cleanup"))
+	stuckHandler := handlers.NewStuckHandler(r.bus, downloadStore, r.config.StuckAlertThresholds, r.logger.With("handler", "stuck"))
+
+	// Create a polling adapter per download client
+	type clientAdapter struct {
+		*sabnzbd.Adapter
+		client   download.Client
+		interval time.Duration
+	}
+	adapters := make([]clientAdapter, 0, len(r.config.Clients))
+	for _, c := range r.config.Clients {
+		client, err := r.clients.ClientFor(c.Name)
+		if err != nil {
+			return err
+		}
+		interval := c.PollInterval
+		if interval <= 0 {
+			interval = 5 * time.Second
+		}
+		adapter := sabnzbd.New(r.bus, client, downloadStore, sabnzbd.Config{
+			Client:     c.Name,
+			Interval:   interval,
+			RemotePath: c.RemotePath,
+			LocalPath:  c.LocalPath,
+			Samples:    r.config.Samples,
+		}, r.logger.With("adapter", "sabnzbd", "client", c.Name))
+		adapters = append(adapters, clientAdapter{Adapter: adapter, client: c.Name, interval: interval})
+	}
+
+	r.recoverInterruptedImports(downloadStore)
 
 	// Use errgroup to manage component lifecycle
 	g, ctx := errgroup.WithContext(ctx)
@@ -112,20 +185,27 @@ func (r *Runner) Run(ctx context.Context) error {
 		r.logger.Info("starting cleanup handler")
 		return cleanupHandler.Start(ctx)
 	})
-
-	// Create adapters
-	sabnzbdAdapter := sabnzbd.New(r.bus, r.downloader, downloadStore, sabnzbd.Config{
-		Interval:   r.config.SABnzbdPollInterval,
-		RemotePath: r.config.DownloadRemotePath,
-		LocalPath:  r.config.DownloadLocalPath,
-	}, r.logger.With("adapter", "sabnzbd"))
-
-	// Start adapters
 	g.Go(func() error {
-		r.logger.Info("starting sabnzbd adapter", "interval", r.config.SABnzbdPollInterval)
-		return sabnzbdAdapter.Start(ctx)
+		r.logger.Info("starting stuck handler")
+		return stuckHandler.Start(ctx)
 	})
 
+	// Deliver durable events, resuming those pending from before a restart
+	if r.config.Outbox {
+		g.Go(func() error {
+			r.logger.Info("starting event outbox")
+			return r.bus.RunOutbox(ctx)
+		})
+	}
+
+	// Start a polling adapter per download client
+	for _, adapter := range adapters {
+		g.Go(func() error {
+			r.logger.Info("starting sabnzbd adapter", "client", adapter.client, "interval", adapter.interval)
+			return adapter.Start(ctx)
+		})
+	}
+
 	// Only start Plex adapter if configured
 	if r.plexChecker != nil {
 		plexAdapter := plex.New(r.bus, r.plexChecker, downloadStore, r.config.PlexPollInterval, r.logger.With("adapter", "plex"))
@@ -162,5 +242,46 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 	})
 
-	return g.Wait()
+	err := g.Wait()
+
+	// Keep the bus open until in-flight imports finish; Shutdown bounds the wait
+	_ = importHandler.Drain(context.Background())
+	return err
+}
+
+// Shutdown waits for in-flight imports after Run's context is canceled.
+// Imports still running when ctx is done are canceled and their downloads
+// returned to completed. Run returns once they have stopped.
+func (r *Runner) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	imports := r.imports
+	r.mu.Unlock()
+	if imports == nil {
+		return nil
+	}
+	return imports.Drain(ctx)
+}
+
+// recoverInterruptedImports returns downloads left in importing by a crash
+// to completed and puts them at the front of the import queue, so they are
+// imported again before later arrivals.
+func (r *Runner) recoverInterruptedImports(store *download.Store) {
+	status := download.StatusImporting
+	stuck, _, err := store.List(download.Filter{Status: &status})
+	if err != nil {
+		r.logger.Error("failed to list interrupted imports", "error", err)
+		return
+	}
+	for _, dl := range stuck {
+		if err := store.Transition(dl, download.StatusCompleted); err != nil {
+			r.logger.Error("failed to recover interrupted import", "download_id", dl.ID, "error", err)
+			continue
+		}
+		if err := store.QueueImport(dl.ID, ""); err != nil {
+			r.logger.Error("failed to queue interrupted import", "download_id", dl.ID, "error", err)
+		} else if err := store.PrioritizeImport(dl.ID); err != nil {
+			r.logger.Error("failed to prioritize interrupted import", "download_id", dl.ID, "error", err)
+		}
+		r.logger.Warn("recovered interrupted import", "download_id", dl.ID, "release", dl.ReleaseName)
+	}
 }
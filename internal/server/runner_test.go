@@ -60,7 +60,8 @@ func setupTestDB(t *testing.T) *sql.DB {
 			entity_id INTEGER NOT NULL,
 			payload TEXT NOT NULL,
 			occurred_at TIMESTAMP NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			processed_at TIMESTAMP
 		);
 		CREATE INDEX idx_events_type ON events(event_type);
 		CREATE INDEX idx_events_entity ON events(entity_type, entity_id);
@@ -79,6 +80,15 @@ func setupTestDB(t *testing.T) *sql.DB {
 			quality_profile TEXT NOT NULL DEFAULT 'hd',
 			root_path TEXT NOT NULL
 		);
+		CREATE TABLE content_aliases (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			content_id INTEGER NOT NULL,
+			alias TEXT NOT NULL,
+			normalized_alias TEXT NOT NULL,
+			source TEXT NOT NULL,
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (content_id, normalized_alias)
+		);
 
 		CREATE TABLE downloads (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -88,6 +98,7 @@ func setupTestDB(t *testing.T) *sql.DB {
 			client_id TEXT NOT NULL,
 			status TEXT NOT NULL DEFAULT 'queued',
 			release_name TEXT,
+			release_group TEXT NOT NULL DEFAULT '',
 			indexer TEXT,
 			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			completed_at TIMESTAMP,
@@ -97,7 +108,21 @@ func setupTestDB(t *testing.T) *sql.DB {
 			progress REAL DEFAULT 0,
 			speed INTEGER DEFAULT 0,
 			eta_seconds INTEGER DEFAULT 0,
-			size_bytes INTEGER DEFAULT 0
+			size_bytes INTEGER DEFAULT 0,
+			guid TEXT NOT NULL DEFAULT '',
+			category TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT ''
+		);
+		CREATE TABLE download_episodes (
+			download_id INTEGER NOT NULL,
+			episode_id  INTEGER NOT NULL,
+			PRIMARY KEY (download_id, episode_id)
+		);
+		CREATE TABLE import_queue (
+			download_id INTEGER PRIMARY KEY,
+			position INTEGER NOT NULL,
+			source_path TEXT NOT NULL DEFAULT '',
+			queued_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
 	`)
 	require.NoError(t, err)
@@ -105,18 +130,24 @@ func setupTestDB(t *testing.T) *sql.DB {
 	return db
 }
 
+// testClients returns a manager with a single mock SABnzbd client.
+func testClients() *download.Manager {
+	m := download.NewManager(nil, nil)
+	m.AddClient(download.ClientSABnzbd, download.ProtocolUsenet, &mockDownloader{})
+	return m
+}
+
 func TestRunner_StartsAndStops(t *testing.T) {
 	db := setupTestDB(t)
 
-	mockDownloader := &mockDownloader{}
 	mockImporter := &mockImporter{}
 
 	runner := NewRunner(db, Config{
-		SABnzbdPollInterval: 100 * time.Millisecond,
-		PlexPollInterval:    100 * time.Millisecond,
-		DownloadRoot:        "/tmp/downloads",
-		CleanupEnabled:      false,
-	}, nil, mockDownloader, mockImporter, nil)
+		Clients:          []ClientConfig{{Name: download.ClientSABnzbd, PollInterval: 100 * time.Millisecond}},
+		PlexPollInterval: 100 * time.Millisecond,
+		DownloadRoot:     "/tmp/downloads",
+		CleanupEnabled:   false,
+	}, nil, testClients(), mockImporter, nil)
 
 	// Start returns the bus
 	bus := runner.Start()
@@ -142,11 +173,50 @@ func TestRunner_StartsAndStops(t *testing.T) {
 	}
 }
 
+func TestRunner_RecoversInterruptedImports(t *testing.T) {
+	db := setupTestDB(t)
+	db.SetMaxOpenConns(1) // Each :memory: connection is its own database
+	store := download.NewStore(db)
+
+	dl := &download.Download{
+		ContentID:   1,
+		Client:      download.ClientSABnzbd,
+		ClientID:    "sab-1",
+		Status:      download.StatusImporting,
+		ReleaseName: "Movie.2024.1080p",
+		Indexer:     "nzbgeek",
+	}
+	require.NoError(t, store.Add(dl))
+
+	runner := NewRunner(db, Config{}, nil, testClients(), &mockImporter{}, nil)
+	runner.Start()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		var status string
+		err := db.QueryRow("SELECT status FROM downloads WHERE id = ?", dl.ID).Scan(&status)
+		return err == nil && download.Status(status) == download.StatusImported
+	}, 2*time.Second, 10*time.Millisecond, "interrupted import should be queued and imported again")
+
+	cancel()
+	require.NoError(t, runner.Shutdown(context.Background()))
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for runner to stop")
+	}
+}
+
 func TestNewRunner_DefaultLogger(t *testing.T) {
 	db := setupTestDB(t)
 
 	// Should not panic with nil logger
-	runner := NewRunner(db, Config{}, nil, &mockDownloader{}, &mockImporter{}, nil)
+	runner := NewRunner(db, Config{}, nil, testClients(), &mockImporter{}, nil)
 	require.NotNil(t, runner)
 	require.NotNil(t, runner.logger)
 }
@@ -155,15 +225,15 @@ func TestRunner_ConfigFields(t *testing.T) {
 	db := setupTestDB(t)
 
 	cfg := Config{
-		SABnzbdPollInterval: 5 * time.Second,
-		PlexPollInterval:    60 * time.Second,
-		DownloadRoot:        "/downloads",
-		CleanupEnabled:      true,
+		Clients:          []ClientConfig{{Name: download.ClientSABnzbd, PollInterval: 5 * time.Second}},
+		PlexPollInterval: 60 * time.Second,
+		DownloadRoot:     "/downloads",
+		CleanupEnabled:   true,
 	}
 
-	runner := NewRunner(db, cfg, nil, &mockDownloader{}, &mockImporter{}, nil)
+	runner := NewRunner(db, cfg, nil, testClients(), &mockImporter{}, nil)
 
-	require.Equal(t, cfg.SABnzbdPollInterval, runner.config.SABnzbdPollInterval)
+	require.Equal(t, cfg.Clients, runner.config.Clients)
 	require.Equal(t, cfg.PlexPollInterval, runner.config.PlexPollInterval)
 	require.Equal(t, cfg.DownloadRoot, runner.config.DownloadRoot)
 	require.True(t, runner.config.CleanupEnabled)
@@ -171,7 +241,7 @@ func TestRunner_ConfigFields(t *testing.T) {
 
 func TestRunner_RunWithoutStart(t *testing.T) {
 	db := setupTestDB(t)
-	runner := NewRunner(db, Config{}, nil, &mockDownloader{}, &mockImporter{}, nil)
+	runner := NewRunner(db, Config{}, nil, testClients(), &mockImporter{}, nil)
 
 	err := runner.Run(context.Background())
 	require.Error(t, err)
@@ -180,7 +250,7 @@ func TestRunner_RunWithoutStart(t *testing.T) {
 
 func TestRunner_StartIsIdempotent(t *testing.T) {
 	db := setupTestDB(t)
-	runner := NewRunner(db, Config{}, nil, &mockDownloader{}, &mockImporter{}, nil)
+	runner := NewRunner(db, Config{}, nil, testClients(), &mockImporter{}, nil)
 
 	// Calling Start() multiple times should return the same bus
 	bus1 := runner.Start()
@@ -194,7 +264,7 @@ func TestRunner_StartIsIdempotent(t *testing.T) {
 
 func TestRunner_StartIsConcurrentSafe(t *testing.T) {
 	db := setupTestDB(t)
-	runner := NewRunner(db, Config{}, nil, &mockDownloader{}, &mockImporter{}, nil)
+	runner := NewRunner(db, Config{}, nil, testClients(), &mockImporter{}, nil)
 
 	const goroutines = 10
 	results := make(chan *events.Bus, goroutines)
@@ -217,3 +287,14 @@ func TestRunner_StartIsConcurrentSafe(t *testing.T) {
 		}
 	}
 }
+
+func TestRunner_UnknownClient(t *testing.T) {
+	db := setupTestDB(t)
+	runner := NewRunner(db, Config{
+		Clients: []ClientConfig{{Name: "missing"}},
+	}, nil, testClients(), &mockImporter{}, nil)
+	runner.Start()
+
+	err := runner.Run(context.Background())
+	require.ErrorIs(t, err, download.ErrUnknownClient)
+}
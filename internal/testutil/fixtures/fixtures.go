@@ -0,0 +1,280 @@
+// Package fixtures builds test rows through the real stores, so fixtures keep
+// up with schema changes instead of hand-writing INSERTs:
+//
+//	movie := fixtures.NewMovie("The Matrix", 1999).WithStatus(library.StatusAvailable).Insert(t, db)
+//	dl := fixtures.NewDownload().ForContent(movie.ID).WithStatus(download.StatusCompleted).Insert(t, db)
+//
+// Builders default every required field; With methods override them. The
+// library and download packages cannot use it in their own tests, since it
+// imports them.
+package fixtures
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/download"
+	"github.com/vmunix/arrgo/internal/library"
+)
+
+// ContentBuilder builds a movie, series or audiobook.
+type ContentBuilder struct {
+	c library.Content
+}
+
+// NewMovie returns a builder for a wanted HD movie under /movies.
+func NewMovie(title string, year int) *ContentBuilder {
+	return &ContentBuilder{c: library.Content{
+		Type:           library.ContentTypeMovie,
+		Title:          title,
+		Year:           year,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/movies",
+	}}
+}
+
+// NewSeries returns a builder for a wanted HD series under /tv.
+func NewSeries(title string, year int) *ContentBuilder {
+	return &ContentBuilder{c: library.Content{
+		Type:           library.ContentTypeSeries,
+		Title:          title,
+		Year:           year,
+		Status:         library.StatusWanted,
+		QualityProfile: "hd",
+		RootPath:       "/tv",
+	}}
+}
+
+// NewAudiobook returns a builder for a wanted audiobook under /audiobooks.
+func NewAudiobook(author, title string, year int) *ContentBuilder {
+	return &ContentBuilder{c: library.Content{
+		Type:           library.ContentTypeAudiobook,
+		Title:          title,
+		Year:           year,
+		Author:         author,
+		Status:         library.StatusWanted,
+		QualityProfile: "audiobook",
+		RootPath:       "/audiobooks",
+	}}
+}
+
+// WithStatus sets the content status.
+func (b *ContentBuilder) WithStatus(status library.ContentStatus) *ContentBuilder {
+	b.c.Status = status
+	return b
+}
+
+// WithProfile sets the quality profile.
+func (b *ContentBuilder) WithProfile(profile string) *ContentBuilder {
+	b.c.QualityProfile = profile
+	return b
+}
+
+// WithRootPath sets the root folder.
+func (b *ContentBuilder) WithRootPath(root string) *ContentBuilder {
+	b.c.RootPath = root
+	return b
+}
+
+// WithTMDBID sets the TMDB ID.
+func (b *ContentBuilder) WithTMDBID(id int64) *ContentBuilder {
+	b.c.TMDBID = &id
+	return b
+}
+
+// WithTVDBID sets the TVDB ID.
+func (b *ContentBuilder) WithTVDBID(id int64) *ContentBuilder {
+	b.c.TVDBID = &id
+	return b
+}
+
+// WithSeasonFolder overrides whether the series' episodes go in season folders.
+func (b *ContentBuilder) WithSeasonFolder(seasonFolder bool) *ContentBuilder {
+	b.c.SeasonFolder = &seasonFolder
+	return b
+}
+
+// WithNamingTemplate overrides the library's naming template.
+func (b *ContentBuilder) WithNamingTemplate(template string) *ContentBuilder {
+	b.c.NamingTemplate = template
+	return b
+}
+
+// Insert adds the content to the library and returns it with its ID set.
+func (b *ContentBuilder) Insert(t testing.TB, db *sql.DB) *library.Content {
+	t.Helper()
+	c := b.c
+	require.NoError(t, library.NewStore(db).AddContent(&c), "insert %s %q", c.Type, c.Title)
+	return &c
+}
+
+// EpisodeBuilder builds an episode of a series.
+type EpisodeBuilder struct {
+	e library.Episode
+}
+
+// NewEpisode returns a builder for a wanted episode of a series.
+func NewEpisode(contentID int64, season, episode int) *EpisodeBuilder {
+	return &EpisodeBuilder{e: library.Episode{
+		ContentID: contentID,
+		Season:    season,
+		Episode:   episode,
+		Title:     fmt.Sprintf("Episode %d", episode),
+		Status:    library.StatusWanted,
+	}}
+}
+
+// WithStatus sets the episode status.
+func (b *EpisodeBuilder) WithStatus(status library.ContentStatus) *EpisodeBuilder {
+	b.e.Status = status
+	return b
+}
+
+// WithTitle sets the episode title.
+func (b *EpisodeBuilder) WithTitle(title string) *EpisodeBuilder {
+	b.e.Title = title
+	return b
+}
+
+// WithAirDate sets the air date.
+func (b *EpisodeBuilder) WithAirDate(airDate time.Time) *EpisodeBuilder {
+	b.e.AirDate = &airDate
+	return b
+}
+
+// Insert adds the episode and returns it with its ID set.
+func (b *EpisodeBuilder) Insert(t testing.TB, db *sql.DB) *library.Episode {
+	t.Helper()
+	e := b.e
+	require.NoError(t, library.NewStore(db).AddEpisode(&e), "insert episode S%02dE%02d", e.Season, e.Episode)
+	return &e
+}
+
+// FileBuilder builds a library file record.
+type FileBuilder struct {
+	f library.File
+}
+
+// NewFile returns a builder for a 1 GB 1080p file of content at path.
+func NewFile(contentID int64, path string) *FileBuilder {
+	return &FileBuilder{f: library.File{
+		ContentID: contentID,
+		Path:      path,
+		SizeBytes: 1 << 30,
+		Quality:   "1080p",
+	}}
+}
+
+// ForEpisode makes the file an episode's.
+func (b *FileBuilder) ForEpisode(episodeID int64) *FileBuilder {
+	b.f.EpisodeID = &episodeID
+	return b
+}
+
+// WithQuality sets the quality, e.g. "2160p".
+func (b *FileBuilder) WithQuality(quality string) *FileBuilder {
+	b.f.Quality = quality
+	return b
+}
+
+// WithSize sets the size in bytes.
+func (b *FileBuilder) WithSize(size int64) *FileBuilder {
+	b.f.SizeBytes = size
+	return b
+}
+
+// Insert adds the file record and returns it with its ID set.
+func (b *FileBuilder) Insert(t testing.TB, db *sql.DB) *library.File {
+	t.Helper()
+	f := b.f
+	require.NoError(t, library.NewStore(db).AddFile(&f), "insert file %s", f.Path)
+	return &f
+}
+
+// DownloadBuilder builds a download. ForContent is required.
+type DownloadBuilder struct {
+	d download.Download
+}
+
+// NewDownload returns a builder for a queued SABnzbd download. The release
+// name is derived from the content ID unless WithReleaseName sets one, since
+// downloads are unique by content and release name.
+func NewDownload() *DownloadBuilder {
+	return &DownloadBuilder{d: download.Download{
+		Client:  download.ClientSABnzbd,
+		Status:  download.StatusQueued,
+		Indexer: "test",
+	}}
+}
+
+// ForContent sets the content downloaded.
+func (b *DownloadBuilder) ForContent(contentID int64) *DownloadBuilder {
+	b.d.ContentID = contentID
+	return b
+}
+
+// ForEpisodes sets the episodes downloaded; the first is also recorded as
+// the download's EpisodeID.
+func (b *DownloadBuilder) ForEpisodes(episodeIDs ...int64) *DownloadBuilder {
+	b.d.EpisodeIDs = episodeIDs
+	if len(episodeIDs) > 0 {
+		b.d.EpisodeID = &episodeIDs[0]
+	}
+	return b
+}
+
+// ForSeason makes the download a complete season pack.
+func (b *DownloadBuilder) ForSeason(season int) *DownloadBuilder {
+	b.d.Season = &season
+	b.d.IsCompleteSeason = true
+	return b
+}
+
+// WithStatus sets the download status.
+func (b *DownloadBuilder) WithStatus(status download.Status) *DownloadBuilder {
+	b.d.Status = status
+	return b
+}
+
+// WithClient sets the download client and the ID in it.
+func (b *DownloadBuilder) WithClient(client download.Client, clientID string) *DownloadBuilder {
+	b.d.Client = client
+	b.d.ClientID = clientID
+	return b
+}
+
+// WithReleaseName sets the release name.
+func (b *DownloadBuilder) WithReleaseName(name string) *DownloadBuilder {
+	b.d.ReleaseName = name
+	return b
+}
+
+// WithIndexer sets the indexer the release came from.
+func (b *DownloadBuilder) WithIndexer(indexer string) *DownloadBuilder {
+	b.d.Indexer = indexer
+	return b
+}
+
+// Insert adds the download, and its episodes if any, and returns it with its
+// ID set.
+func (b *DownloadBuilder) Insert(t testing.TB, db *sql.DB) *download.Download {
+	t.Helper()
+	require.NotZero(t, b.d.ContentID, "download fixture needs ForContent")
+	d := b.d
+	if d.ReleaseName == "" {
+		d.ReleaseName = fmt.Sprintf("Test.Release.%d.1080p.WEB-DL", d.ContentID)
+	}
+	if d.ClientID == "" {
+		d.ClientID = "nzo_" + d.ReleaseName
+	}
+	store := download.NewStore(db)
+	require.NoError(t, store.Add(&d), "insert download %s", d.ReleaseName)
+	if len(d.EpisodeIDs) > 0 {
+		require.NoError(t, store.SetEpisodeIDs(d.ID, d.EpisodeIDs), "set download episodes")
+	}
+	return &d
+}
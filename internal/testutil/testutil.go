@@ -0,0 +1,41 @@
+// Package testutil provides the test database shared by the store and API
+// test suites. Fixture builders that insert through the stores are in
+// package fixtures.
+package testutil
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmunix/arrgo/internal/migrations/schema"
+	_ "modernc.org/sqlite" // SQLite driver
+)
+
+// OpenTestDB returns an in-memory database with the full schema, built from
+// the embedded migrations, and closes it when the test ends.
+func OpenTestDB(t testing.TB) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:?_foreign_keys=on")
+	require.NoError(t, err, "open test db")
+	t.Cleanup(func() { _ = db.Close() })
+	ApplySchema(t, db)
+	return db
+}
+
+// ApplySchema runs every embedded migration on an empty database, for tests
+// that need a file-backed database rather than OpenTestDB's.
+//
+// It runs the migration SQL directly rather than calling migrations.Up, which
+// imports the stores, so the library and download packages can use it in their
+// own tests. The data fixes Up runs after some migrations have nothing to fix
+// in an empty database.
+func ApplySchema(t testing.TB, db *sql.DB) {
+	t.Helper()
+	all, err := schema.All()
+	require.NoError(t, err, "read migrations")
+	for _, m := range all {
+		_, err := schema.Exec(db, m)
+		require.NoError(t, err, "apply migration %03d_%s", m.Version, m.Name)
+	}
+}
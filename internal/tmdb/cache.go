@@ -1,6 +1,7 @@
 package tmdb
 
 import (
+	"strings"
 	"sync"
 	"time"
 )
@@ -10,16 +11,34 @@ type cacheEntry struct {
 	expires time.Time
 }
 
+type collectionEntry struct {
+	collection *Collection
+	expires    time.Time
+}
+
+type searchEntry struct {
+	movies  []Movie
+	expires time.Time
+}
+
 type cache struct {
-	mu      sync.RWMutex
-	entries map[int64]cacheEntry
-	ttl     time.Duration
+	mu          sync.RWMutex
+	entries     map[int64]cacheEntry
+	collections map[int64]collectionEntry
+	searches    map[string]searchEntry // Keyed by lowercased, trimmed query
+	ttl         time.Duration
 }
 
+// searchCacheTTL is how long search results are reused. Searches are
+// cheaper to go stale than movie details but are repeated more often.
+const searchCacheTTL = time.Hour
+
 func newCache(ttl time.Duration) *cache {
 	return &cache{
-		entries: make(map[int64]cacheEntry),
-		ttl:     ttl,
+		entries:     make(map[int64]cacheEntry),
+		collections: make(map[int64]collectionEntry),
+		searches:    make(map[string]searchEntry),
+		ttl:         ttl,
 	}
 }
 
@@ -46,3 +65,56 @@ func (c *cache) set(tmdbID int64, movie *Movie) {
 		expires: time.Now().Add(c.ttl),
 	}
 }
+
+func (c *cache) getCollection(id int64) (*Collection, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.collections[id]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.collection, true
+}
+
+func (c *cache) setCollection(id int64, collection *Collection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.collections[id] = collectionEntry{
+		collection: collection,
+		expires:    time.Now().Add(c.ttl),
+	}
+}
+
+func searchKey(query string) string {
+	return strings.ToLower(strings.TrimSpace(query))
+}
+
+func (c *cache) getSearch(query string) ([]Movie, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.searches[searchKey(query)]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.movies, true
+}
+
+func (c *cache) setSearch(query string, movies []Movie) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop expired searches so free-text queries don't accumulate
+	now := time.Now()
+	for k, e := range c.searches {
+		if now.After(e.expires) {
+			delete(c.searches, k)
+		}
+	}
+	c.searches[searchKey(query)] = searchEntry{
+		movies:  movies,
+		expires: now.Add(min(c.ttl, searchCacheTTL)),
+	}
+}
@@ -7,13 +7,17 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"time"
 )
 
 const defaultBaseURL = "https://api.themoviedb.org"
 const defaultCacheTTL = 24 * time.Hour
 
-// ErrNotFound is returned when a movie doesn't exist in TMDB.
+// DefaultTimeout bounds each TMDB request when no timeout is given.
+const DefaultTimeout = 10 * time.Second
+
+// ErrNotFound is returned when a movie or collection doesn't exist in TMDB.
 var ErrNotFound = errors.New("movie not found")
 
 // Client is a TMDB API client.
@@ -49,6 +53,15 @@ func WithHTTPClient(hc *http.Client) Option {
 	}
 }
 
+// WithTimeout sets the timeout for each request to TMDB.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Timeout = d
+		c.httpClient = &hc
+	}
+}
+
 // WithLogger sets a logger for debug output.
 func WithLogger(log *slog.Logger) Option {
 	return func(c *Client) {
@@ -62,7 +75,7 @@ func NewClient(apiKey string, opts ...Option) *Client {
 		apiKey:  apiKey,
 		baseURL: defaultBaseURL,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: DefaultTimeout,
 		},
 		cache: newCache(defaultCacheTTL),
 	}
@@ -129,3 +142,104 @@ func (c *Client) GetMovie(ctx context.Context, tmdbID int64) (*Movie, error) {
 	c.cache.set(tmdbID, &movie)
 	return &movie, nil
 }
+
+// GetCollection fetches a collection and its movies by TMDB collection ID,
+// ordered as TMDB lists them. Returns ErrNotFound if it doesn't exist.
+func (c *Client) GetCollection(ctx context.Context, collectionID int64) (*Collection, error) {
+	if collection, ok := c.cache.getCollection(collectionID); ok {
+		if c.log != nil {
+			c.log.Debug("collection cache hit", "collection_id", collectionID, "name", collection.Name)
+		}
+		return collection, nil
+	}
+
+	start := time.Now()
+
+	url := fmt.Sprintf("%s/3/collection/%d?api_key=%s", c.baseURL, collectionID, c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if c.log != nil {
+			c.log.Debug("request failed", "collection_id", collectionID, "error", err)
+		}
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		if c.log != nil {
+			c.log.Debug("not found", "collection_id", collectionID)
+		}
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		if c.log != nil {
+			c.log.Debug("api error", "collection_id", collectionID, "status", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("TMDB API error: %s", resp.Status)
+	}
+
+	var collection Collection
+	if err := json.NewDecoder(resp.Body).Decode(&collection); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if c.log != nil {
+		c.log.Debug("fetched collection", "collection_id", collectionID, "name", collection.Name, "parts", len(collection.Parts), "duration_ms", time.Since(start).Milliseconds())
+	}
+	c.cache.setCollection(collectionID, &collection)
+	return &collection, nil
+}
+
+// SearchMovies searches TMDB for movies by title, most relevant first.
+// Search results omit details such as runtime and genres. Results are
+// cached for up to an hour per query.
+func (c *Client) SearchMovies(ctx context.Context, query string) ([]Movie, error) {
+	if movies, ok := c.cache.getSearch(query); ok {
+		if c.log != nil {
+			c.log.Debug("search cache hit", "query", query, "results", len(movies))
+		}
+		return movies, nil
+	}
+
+	start := time.Now()
+
+	params := url.Values{}
+	params.Set("api_key", c.apiKey)
+	params.Set("query", query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/3/search/movie?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if c.log != nil {
+			c.log.Debug("search failed", "query", query, "error", err)
+		}
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if c.log != nil {
+			c.log.Debug("api error", "query", query, "status", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("TMDB API error: %s", resp.Status)
+	}
+
+	var result searchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if c.log != nil {
+		c.log.Debug("searched movies", "query", query, "results", len(result.Results), "duration_ms", time.Since(start).Milliseconds())
+	}
+	c.cache.setSearch(query, result.Results)
+	return result.Results, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -43,6 +44,26 @@ func TestClient_GetMovie(t *testing.T) {
 	assert.Equal(t, 139, movie.Runtime)
 }
 
+func TestClient_Timeout(t *testing.T) {
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never respond
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	defer server.Close()
+	defer close(stop)
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.GetMovie(context.Background(), 550)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second, "request should give up after the configured timeout")
+}
+
 func TestClient_GetMovie_NotFound(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -78,3 +99,103 @@ func TestClient_GetMovie_Cached(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1, callCount, "should use cache, not call API again")
 }
+
+func TestClient_GetMovie_Collection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":603,"title":"The Matrix","belongs_to_collection":{"id":2344,"name":"The Matrix Collection","poster_path":"/x.jpg"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	movie, err := client.GetMovie(context.Background(), 603)
+	require.NoError(t, err)
+	require.NotNil(t, movie.Collection)
+	assert.Equal(t, int64(2344), movie.Collection.ID)
+	assert.Equal(t, "The Matrix Collection", movie.Collection.Name)
+}
+
+func TestClient_GetCollection(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.Equal(t, "/3/collection/2344", r.URL.Path)
+		assert.Equal(t, "test-key", r.URL.Query().Get("api_key"))
+		_, _ = w.Write([]byte(`{"id":2344,"name":"The Matrix Collection","parts":[
+			{"id":603,"title":"The Matrix","release_date":"1999-03-31"},
+			{"id":604,"title":"The Matrix Reloaded","release_date":"2003-05-15"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	collection, err := client.GetCollection(context.Background(), 2344)
+	require.NoError(t, err)
+	assert.Equal(t, "The Matrix Collection", collection.Name)
+	require.Len(t, collection.Parts, 2)
+	assert.Equal(t, int64(604), collection.Parts[1].ID)
+	assert.Equal(t, 2003, collection.Parts[1].Year())
+
+	_, err = client.GetCollection(context.Background(), 2344)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "repeat fetch should be served from cache")
+}
+
+func TestClient_GetCollection_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	_, err := client.GetCollection(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_SearchMovies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/3/search/movie", r.URL.Path)
+		assert.Equal(t, "test-key", r.URL.Query().Get("api_key"))
+		assert.Equal(t, "the matrix", r.URL.Query().Get("query"))
+		_, _ = w.Write([]byte(`{"page":1,"results":[
+			{"id":603,"title":"The Matrix","release_date":"1999-03-31","overview":"Set in the 22nd century..."},
+			{"id":604,"title":"The Matrix Reloaded","release_date":"2003-05-15"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	movies, err := client.SearchMovies(context.Background(), "the matrix")
+	require.NoError(t, err)
+	require.Len(t, movies, 2)
+	assert.Equal(t, int64(603), movies[0].ID)
+	assert.Equal(t, 1999, movies[0].Year())
+	assert.Equal(t, "Set in the 22nd century...", movies[0].Overview)
+}
+
+func TestClient_SearchMovies_Cached(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`{"page":1,"results":[{"id":603,"title":"The Matrix","release_date":"1999-03-31"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	_, err := client.SearchMovies(context.Background(), "the matrix")
+	require.NoError(t, err)
+	movies, err := client.SearchMovies(context.Background(), " The Matrix ")
+	require.NoError(t, err)
+	require.Len(t, movies, 1)
+	assert.Equal(t, int32(1), calls.Load(), "repeat search should be served from cache")
+}
+
+func TestClient_SearchMovies_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient("bad-key", WithBaseURL(server.URL))
+	_, err := client.SearchMovies(context.Background(), "the matrix")
+	assert.Error(t, err)
+}
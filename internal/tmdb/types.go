@@ -16,6 +16,25 @@ type Movie struct {
 	VoteCount    int     `json:"vote_count"`
 	Runtime      int     `json:"runtime"` // minutes
 	Genres       []Genre `json:"genres"`
+
+	// Collection the movie belongs to (e.g. a franchise); nil if none.
+	// Only set on movie details, not search results.
+	Collection *CollectionRef `json:"belongs_to_collection,omitempty"`
+}
+
+// CollectionRef identifies the collection a movie belongs to.
+type CollectionRef struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Collection is a TMDB collection: a set of related movies such as a
+// franchise. Parts carry search-level details, without runtime or genres.
+type Collection struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Overview string  `json:"overview"`
+	Parts    []Movie `json:"parts"`
 }
 
 // Genre represents a movie genre.
@@ -24,6 +43,11 @@ type Genre struct {
 	Name string `json:"name"`
 }
 
+// searchResponse is the TMDB movie search response.
+type searchResponse struct {
+	Results []Movie `json:"results"`
+}
+
 // Year extracts the year from ReleaseDate.
 func (m *Movie) Year() int {
 	if len(m.ReleaseDate) < 4 {
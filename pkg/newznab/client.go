@@ -1,13 +1,17 @@
-// Package newznab implements the Newznab usenet indexer API protocol.
+// Package newznab implements the Newznab usenet indexer API protocol,
+// including Torznab feeds (e.g. from Jackett or Prowlarr) that return torrents.
 package newznab
 
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -17,36 +21,84 @@ import (
 type Client struct {
 	name       string
 	baseURL    string
-	apiKey     string
+	keys       *keyRing
 	httpClient *http.Client
 	log        *slog.Logger
 }
 
+// DownloadType is the kind of file a release's download URL points at.
+type DownloadType string
+
+const (
+	DownloadNZB     DownloadType = "nzb"
+	DownloadTorrent DownloadType = "torrent" // .torrent file
+	DownloadMagnet  DownloadType = "magnet"  // magnet: link
+)
+
+// torrentMIMEType is the enclosure type Torznab feeds use for .torrent files.
+const torrentMIMEType = "application/x-bittorrent"
+
 // Release represents a search result from a Newznab indexer.
 type Release struct {
-	Title       string
-	GUID        string
-	DownloadURL string
-	Size        int64
-	PublishDate time.Time
-	Indexer     string
+	Title        string
+	GUID         string
+	DownloadURL  string
+	Size         int64
+	PublishDate  time.Time
+	Indexer      string
+	DownloadType DownloadType
+
+	// Torznab attributes; zero for NZB releases
+	Seeders  int
+	Peers    int
+	InfoHash string
+}
+
+// IsTorrent reports whether the release downloads via a torrent client.
+func (r *Release) IsTorrent() bool {
+	return r.DownloadType == DownloadTorrent || r.DownloadType == DownloadMagnet
+}
+
+// DefaultTimeout bounds each indexer request when no timeout is given.
+const DefaultTimeout = 30 * time.Second
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithTimeout sets the timeout for each request to the indexer.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = d
+	}
+}
+
+// WithAPIKeys adds API keys to rotate to when the indexer reports a key's
+// daily limit reached. Keys are tried in order after the client's own key.
+func WithAPIKeys(keys ...string) Option {
+	return func(c *Client) {
+		c.keys = newKeyRing(append(slices.Clone(c.keys.keys), keys...)...)
+	}
 }
 
 // NewClient creates a new Newznab client.
-func NewClient(name, baseURL, apiKey string, log *slog.Logger) *Client {
+func NewClient(name, baseURL, apiKey string, log *slog.Logger, opts ...Option) *Client {
 	var clientLog *slog.Logger
 	if log != nil {
 		clientLog = log.With("component", "newznab", "indexer", name)
 	}
-	return &Client{
+	c := &Client{
 		name:    name,
 		baseURL: strings.TrimSuffix(baseURL, "/"),
-		apiKey:  apiKey,
+		keys:    newKeyRing(apiKey),
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultTimeout,
 		},
 		log: clientLog,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Name returns the indexer name.
@@ -59,15 +111,48 @@ func (c *Client) URL() string {
 	return c.baseURL
 }
 
+// KeyUsage returns today's usage of each of the indexer's API keys.
+func (c *Client) KeyUsage() []KeyUsage {
+	return c.keys.snapshot()
+}
+
+// GrabURL returns a release download URL from this indexer (a t=get link)
+// with its API key replaced by the key with the most grabs left, counting
+// the grab. URLs that carry none of the client's keys are returned as-is.
+func (c *Client) GrabURL(downloadURL string) string {
+	u, err := url.Parse(downloadURL)
+	if err != nil {
+		return downloadURL
+	}
+	q := u.Query()
+	for name, values := range q {
+		if len(values) != 1 || !c.keys.has(values[0]) {
+			continue
+		}
+		key := c.keys.grabKey()
+		if key != values[0] && c.log != nil {
+			c.log.Debug("grab uses another api key", "key", maskKey(key))
+		}
+		q.Set(name, key)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	return downloadURL
+}
+
 // Caps performs a capabilities request to test connectivity.
 func (c *Client) Caps(ctx context.Context) error {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return err
 	}
+	_, key, err := c.keys.acquire()
+	if err != nil {
+		return err
+	}
 	q := u.Query()
 	q.Set("t", "caps")
-	q.Set("apikey", c.apiKey)
+	q.Set("apikey", key)
 	u.RawQuery = q.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
@@ -94,7 +179,15 @@ type rssResponse struct {
 }
 
 type rssChannel struct {
-	Items []rssItem `xml:"item"`
+	Items     []rssItem  `xml:"item"`
+	APILimits *apiLimits `xml:"http://www.newznab.com/DTD/2010/feeds/attributes/ apilimits"`
+}
+
+// errorResponse is the body of a Newznab API error.
+type errorResponse struct {
+	XMLName     xml.Name `xml:"error"`
+	Code        int      `xml:"code,attr"`
+	Description string   `xml:"description,attr"`
 }
 
 type rssItem struct {
@@ -105,11 +198,13 @@ type rssItem struct {
 	PubDate   string        `xml:"pubDate"`
 	Enclosure rssEnclosure  `xml:"enclosure"`
 	Attrs     []newznabAttr `xml:"http://www.newznab.com/DTD/2010/feeds/attributes/ attr"`
+	Torznab   []newznabAttr `xml:"http://torznab.com/schemas/2015/feed attr"`
 }
 
 type rssEnclosure struct {
 	URL    string `xml:"url,attr"`
 	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
 }
 
 type newznabAttr struct {
@@ -134,52 +229,77 @@ func (c *Client) SearchWithOffset(ctx context.Context, query string, categories
 
 	// Build query params
 	params := url.Values{}
-	params.Set("apikey", c.apiKey)
 	params.Set("t", "search")
 	if query != "" {
 		params.Set("q", query)
 	}
-	if len(categories) > 0 {
-		cats := make([]string, len(categories))
-		for i, cat := range categories {
-			cats[i] = strconv.Itoa(cat)
-		}
-		params.Set("cat", strings.Join(cats, ","))
-	}
+	setCategories(params, categories)
 	if limit > 0 {
 		params.Set("limit", strconv.Itoa(limit))
 	}
 	if offset > 0 {
 		params.Set("offset", strconv.Itoa(offset))
 	}
-	reqURL.RawQuery = params.Encode()
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	rss, err := c.query(ctx, reqURL, params)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
+	releases := c.releases(rss.Channel.Items)
 
-	// Execute
-	resp, err := c.httpClient.Do(req)
+	if c.log != nil {
+		c.log.Debug("search complete", "query", query, "results", len(releases), "duration_ms", time.Since(start).Milliseconds())
+	}
+
+	return releases, nil
+}
+
+// ListRecent returns the indexer's newest TV releases in the categories, up
+// to limit (0 for the indexer's default): a tvsearch without a query, which
+// indexers answer newest first like their RSS feeds.
+func (c *Client) ListRecent(ctx context.Context, categories []int, limit int) ([]Release, error) {
+	start := time.Now()
+
+	reqURL, err := url.Parse(c.baseURL + "/api")
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("invalid base URL: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	params := url.Values{}
+	params.Set("t", "tvsearch")
+	setCategories(params, categories)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
 	}
 
-	// Parse XML
-	var rss rssResponse
-	if err := xml.NewDecoder(resp.Body).Decode(&rss); err != nil {
-		return nil, fmt.Errorf("parse response: %w", err)
+	rss, err := c.query(ctx, reqURL, params)
+	if err != nil {
+		return nil, err
+	}
+	releases := c.releases(rss.Channel.Items)
+
+	if c.log != nil {
+		c.log.Debug("recent releases listed", "results", len(releases), "duration_ms", time.Since(start).Milliseconds())
+	}
+	return releases, nil
+}
+
+// setCategories sets the cat parameter to a comma-separated category list.
+func setCategories(params url.Values, categories []int) {
+	if len(categories) == 0 {
+		return
 	}
+	cats := make([]string, len(categories))
+	for i, cat := range categories {
+		cats[i] = strconv.Itoa(cat)
+	}
+	params.Set("cat", strings.Join(cats, ","))
+}
 
-	// Convert to releases
-	releases := make([]Release, 0, len(rss.Channel.Items))
-	for _, item := range rss.Channel.Items {
+// releases converts feed items to releases of this indexer.
+func (c *Client) releases(items []rssItem) []Release {
+	releases := make([]Release, 0, len(items))
+	for _, item := range items {
 		rel := Release{
 			Title:       item.Title,
 			GUID:        item.GUID,
@@ -224,12 +344,137 @@ func (c *Client) SearchWithOffset(ctx context.Context, query string, categories
 			}
 		}
 
+		applyTorznabAttrs(&rel, item.Torznab)
+		rel.DownloadType = downloadType(rel.DownloadURL, item.Enclosure.Type, len(item.Torznab) > 0)
+
 		releases = append(releases, rel)
 	}
+	return releases
+}
 
-	if c.log != nil {
-		c.log.Debug("search complete", "query", query, "results", len(releases), "duration_ms", time.Since(start).Milliseconds())
+// query makes an API call with the current key and parses the RSS response.
+// When the indexer reports the key's limit reached, the call is retried with
+// the next key, which later calls keep using.
+func (c *Client) query(ctx context.Context, reqURL *url.URL, params url.Values) (*rssResponse, error) {
+	for {
+		i, key, err := c.keys.acquire()
+		if err != nil {
+			return nil, err
+		}
+		params.Set("apikey", key)
+		reqURL.RawQuery = params.Encode()
+
+		rss, err := c.fetch(ctx, reqURL.String())
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.LimitReached() {
+			more := c.keys.exhaust(i)
+			if c.log != nil {
+				c.log.Warn("api key limit reached", "key", maskKey(key), "error", apiErr, "rotating", more)
+			}
+			if more {
+				continue
+			}
+			return nil, fmt.Errorf("%w: %w", ErrLimitReached, apiErr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rss.Channel.APILimits != nil {
+			c.keys.report(i, *rss.Channel.APILimits)
+		}
+		return rss, nil
 	}
+}
 
-	return releases, nil
+// fetch requests an API URL and parses the response, returning an *APIError
+// for a Newznab error body or HTTP 429, with any Retry-After delay.
+func (c *Client) fetch(ctx context.Context, reqURL string) (*rssResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &APIError{Code: ErrorCodeTooManyRequests, Description: "too many requests", RetryAfter: retryAfter}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var apiErr errorResponse
+	if xml.Unmarshal(body, &apiErr) == nil {
+		return nil, &APIError{Code: apiErr.Code, Description: apiErr.Description, RetryAfter: retryAfter}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	var rss rssResponse
+	if err := xml.Unmarshal(body, &rss); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &rss, nil
+}
+
+// parseRetryAfter returns the delay a Retry-After header asks for, given
+// as seconds or an HTTP date; 0 if the header is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now).Round(time.Second), 0)
+	}
+	return 0
+}
+
+// applyTorznabAttrs copies torznab:attr values onto a release.
+// Feeds without a download link may still carry a magnet URL.
+func applyTorznabAttrs(rel *Release, attrs []newznabAttr) {
+	for _, attr := range attrs {
+		switch attr.Name {
+		case "seeders":
+			rel.Seeders, _ = strconv.Atoi(attr.Value)
+		case "peers":
+			rel.Peers, _ = strconv.Atoi(attr.Value)
+		case "infohash":
+			rel.InfoHash = strings.ToLower(attr.Value)
+		case "magneturl":
+			if rel.DownloadURL == "" {
+				rel.DownloadURL = attr.Value
+			}
+		case "size":
+			if rel.Size == 0 {
+				rel.Size, _ = strconv.ParseInt(attr.Value, 10, 64)
+			}
+		}
+	}
+}
+
+// downloadType classifies a download URL. Torznab download links are often
+// proxy URLs without a .torrent suffix, so the enclosure type and the
+// presence of torznab attributes also mark a release as a torrent.
+func downloadType(downloadURL, enclosureType string, torznab bool) DownloadType {
+	lower := strings.ToLower(downloadURL)
+	switch {
+	case strings.HasPrefix(lower, "magnet:"):
+		return DownloadMagnet
+	case enclosureType == torrentMIMEType, torznab:
+		return DownloadTorrent
+	}
+	if u, err := url.Parse(downloadURL); err == nil && strings.HasSuffix(strings.ToLower(u.Path), ".torrent") {
+		return DownloadTorrent
+	}
+	return DownloadNZB
 }
@@ -32,6 +32,26 @@ const testXMLResponse = `<?xml version="1.0" encoding="UTF-8"?>
   </channel>
 </rss>`
 
+func TestClient_Search_Timeout(t *testing.T) {
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never respond
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	defer server.Close()
+	defer close(stop)
+
+	client := NewClient("TestIndexer", server.URL, "test-key", nil, WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.Search(context.Background(), "test query", nil)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second, "search should give up after the configured timeout")
+}
+
 func TestClient_Search(t *testing.T) {
 	// Create mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -133,7 +153,6 @@ func TestSearch_EmptyResponse(t *testing.T) {
 
 func TestSearch_ErrorResponse(t *testing.T) {
 	// Newznab API error responses have a different root element (<error> instead of <rss>).
-	// The XML parser returns an error because it expects <rss>.
 	const errorXML = `<?xml version="1.0" encoding="UTF-8"?>
 <error code="100" description="Incorrect user credentials"/>
 `
@@ -145,9 +164,38 @@ func TestSearch_ErrorResponse(t *testing.T) {
 
 	client := NewClient("Test", server.URL, "key", nil)
 	_, err := client.Search(context.Background(), "test", nil)
-	// Current implementation: XML parser fails because root element is <error> not <rss>
-	require.Error(t, err, "error response should cause parsing error")
-	assert.Contains(t, err.Error(), "parse response", "error should indicate parsing issue")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr, "error response should be returned as an APIError")
+	assert.Equal(t, 100, apiErr.Code)
+	assert.Equal(t, "Incorrect user credentials", apiErr.Description)
+	assert.False(t, apiErr.LimitReached())
+	assert.True(t, apiErr.AccountRejected())
+}
+
+func TestSearch_TooManyRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient("Test", server.URL, "key", nil)
+	_, err := client.Search(context.Background(), "test", nil)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, ErrorCodeTooManyRequests, apiErr.Code)
+	assert.Equal(t, 10*time.Minute, apiErr.RetryAfter)
+	assert.False(t, apiErr.LimitReached(), "a throttle with a delay doesn't use up the key for the day")
+	assert.NotErrorIs(t, err, ErrLimitReached)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 90*time.Second, parseRetryAfter("90", now))
+	assert.Equal(t, 5*time.Minute, parseRetryAfter(now.Add(5*time.Minute).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now), "date in the past")
+	assert.Zero(t, parseRetryAfter("", now))
+	assert.Zero(t, parseRetryAfter("soon", now))
 }
 
 func TestSearch_HTTPError(t *testing.T) {
@@ -464,3 +512,74 @@ func TestSearch_EmptyQuery(t *testing.T) {
 	require.NoError(t, err, "empty query search should succeed")
 	assert.Len(t, releases, 2, "expected 2 releases")
 }
+
+func TestClient_ListRecent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "tvsearch", q.Get("t"))
+		assert.False(t, q.Has("q"), "recent releases are listed without a query")
+		assert.Equal(t, "5000,5040", q.Get("cat"))
+		assert.Equal(t, "100", q.Get("limit"))
+		assert.Equal(t, "key", q.Get("apikey"))
+		_, _ = w.Write([]byte(testXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient("Test", server.URL, "key", nil)
+	releases, err := client.ListRecent(context.Background(), []int{5000, 5040}, 100)
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	assert.Equal(t, "Test", releases[0].Indexer)
+	assert.Equal(t, time.Date(2026, 1, 18, 12, 0, 0, 0, time.UTC), releases[0].PublishDate.UTC())
+}
+
+func TestSearch_Torznab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed">
+  <channel>
+    <item>
+      <title>Movie.2024.1080p.BluRay.x264-GROUP</title>
+      <guid>https://tracker.example/details/1</guid>
+      <link>http://jackett:9117/dl/tracker/?jackett_apikey=key&amp;path=abc&amp;file=Movie</link>
+      <enclosure url="http://jackett:9117/dl/tracker/?jackett_apikey=key&amp;path=abc&amp;file=Movie" length="2000000000" type="application/x-bittorrent" />
+      <torznab:attr name="seeders" value="42" />
+      <torznab:attr name="peers" value="50" />
+      <torznab:attr name="infohash" value="ABCDEF0123456789ABCDEF0123456789ABCDEF01" />
+    </item>
+    <item>
+      <title>Movie.2024.720p.WEB-DL-GROUP</title>
+      <guid>https://tracker.example/details/2</guid>
+      <torznab:attr name="magneturl" value="magnet:?xt=urn:btih:abc" />
+      <torznab:attr name="seeders" value="3" />
+      <torznab:attr name="size" value="900000000" />
+    </item>
+  </channel>
+</rss>`))
+	}))
+	defer server.Close()
+
+	client := NewClient("jackett", server.URL, "key", nil)
+	releases, err := client.Search(context.Background(), "movie", nil)
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+
+	assert.Equal(t, DownloadTorrent, releases[0].DownloadType)
+	assert.True(t, releases[0].IsTorrent())
+	assert.Equal(t, 42, releases[0].Seeders)
+	assert.Equal(t, 50, releases[0].Peers)
+	assert.Equal(t, "abcdef0123456789abcdef0123456789abcdef01", releases[0].InfoHash)
+
+	assert.Equal(t, DownloadMagnet, releases[1].DownloadType)
+	assert.Equal(t, "magnet:?xt=urn:btih:abc", releases[1].DownloadURL)
+	assert.Equal(t, 3, releases[1].Seeders)
+	assert.Equal(t, int64(900000000), releases[1].Size)
+}
+
+func TestDownloadType(t *testing.T) {
+	assert.Equal(t, DownloadNZB, downloadType("http://example.com/getnzb/abc", "application/x-nzb", false))
+	assert.Equal(t, DownloadMagnet, downloadType("magnet:?xt=urn:btih:abc", "", true))
+	assert.Equal(t, DownloadTorrent, downloadType("https://tracker.example/dl/Movie.torrent", "", false))
+	assert.Equal(t, DownloadTorrent, downloadType("http://jackett/dl/x", torrentMIMEType, false))
+	assert.Equal(t, DownloadTorrent, downloadType("http://jackett/dl/x", "", true))
+}
@@ -0,0 +1,235 @@
+package newznab
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Newznab error codes arrgo acts on.
+const (
+	ErrorCodeIncorrectCredentials = 100 // Wrong API key
+	ErrorCodeAccountSuspended     = 101 // Account suspended
+	ErrorCodeRequestLimit         = 500 // Request limit reached
+	ErrorCodeDownloadLimit        = 501 // Download limit reached
+
+	// ErrorCodeTooManyRequests is HTTP 429, which isn't a Newznab code but
+	// is reported the same way.
+	ErrorCodeTooManyRequests = 429
+)
+
+// ErrLimitReached is returned when every API key of an indexer has reached
+// its limit for the day.
+var ErrLimitReached = errors.New("request limit reached for all api keys")
+
+// APIError is an error response from a Newznab indexer:
+// <error code="100" description="Incorrect user credentials"/>, or HTTP 429.
+type APIError struct {
+	Code        int
+	Description string
+	RetryAfter  time.Duration // From the Retry-After header; 0 if not sent
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("newznab error %d: %s", e.Code, e.Description)
+}
+
+// LimitReached reports whether the error means the API key is out of
+// requests or downloads for the day. An HTTP 429 with a Retry-After delay
+// is a short throttle rather than the day's limit.
+func (e *APIError) LimitReached() bool {
+	switch e.Code {
+	case ErrorCodeRequestLimit, ErrorCodeDownloadLimit:
+		return true
+	case ErrorCodeTooManyRequests:
+		return e.RetryAfter == 0
+	}
+	return false
+}
+
+// AccountRejected reports whether the indexer refused the API key or
+// account, which retrying won't fix.
+func (e *APIError) AccountRejected() bool {
+	return e.Code == ErrorCodeIncorrectCredentials || e.Code == ErrorCodeAccountSuspended
+}
+
+// KeyUsage describes how one API key of an indexer has been used today.
+// The API and grab counts are as last reported by the indexer in
+// <newznab:apilimits>; they are 0 if it never reported them.
+type KeyUsage struct {
+	Key         string // Masked to its last 4 characters
+	Requests    int    // API calls made with the key today
+	Grabs       int    // Download URLs handed out with the key today
+	Exhausted   bool   // The indexer reported the key's request limit reached
+	APICurrent  int
+	APIMax      int
+	GrabCurrent int
+	GrabMax     int
+}
+
+// GrabsLeft returns the grabs the indexer last reported left for the key,
+// less those handed out since, or -1 if it never reported a grab limit.
+func (u KeyUsage) GrabsLeft() int {
+	if u.GrabMax == 0 {
+		return -1
+	}
+	return max(u.GrabMax-u.GrabCurrent, 0)
+}
+
+// apiLimits is the <newznab:apilimits> element some indexers add to responses.
+type apiLimits struct {
+	APICurrent  int `xml:"apicurrent,attr"`
+	APIMax      int `xml:"apimax,attr"`
+	GrabCurrent int `xml:"grabcurrent,attr"`
+	GrabMax     int `xml:"grabmax,attr"`
+}
+
+// keyRing rotates between an indexer's API keys. Calls use the current key
+// until the indexer reports its limit reached, then move on to the next.
+// Exhausted keys and usage counts reset when the UTC day changes.
+type keyRing struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	day     string // UTC date the usage applies to
+	current int
+	keys    []string
+	usage   []KeyUsage
+}
+
+// newKeyRing creates a key ring, dropping empty and repeated keys.
+func newKeyRing(keys ...string) *keyRing {
+	r := &keyRing{now: time.Now}
+	for _, k := range keys {
+		if k != "" && !slices.Contains(r.keys, k) {
+			r.keys = append(r.keys, k)
+		}
+	}
+	r.usage = make([]KeyUsage, len(r.keys))
+	return r
+}
+
+// acquire returns the key to use for the next call and its index, counting
+// the call. Returns ErrLimitReached if every key is exhausted.
+func (r *keyRing) acquire() (int, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rollover()
+	for n := range r.keys {
+		i := (r.current + n) % len(r.keys)
+		if !r.usage[i].Exhausted {
+			r.current = i
+			r.usage[i].Requests++
+			return i, r.keys[i], nil
+		}
+	}
+	if len(r.keys) == 0 {
+		return 0, "", nil
+	}
+	return 0, "", ErrLimitReached
+}
+
+// exhaust marks the key at i out of requests for the day and moves on to the
+// next key. Reports whether another key is left to try.
+func (r *keyRing) exhaust(i int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.usage[i].Exhausted = true
+	r.current = (i + 1) % len(r.keys)
+	for _, u := range r.usage {
+		if !u.Exhausted {
+			return true
+		}
+	}
+	return false
+}
+
+// report records the limits the indexer reported for the key at i. A key
+// reported at its API limit is exhausted without waiting for an error.
+func (r *keyRing) report(i int, limits apiLimits) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u := &r.usage[i]
+	u.APICurrent, u.APIMax = limits.APICurrent, limits.APIMax
+	u.GrabCurrent, u.GrabMax = limits.GrabCurrent, limits.GrabMax
+	if u.APIMax > 0 && u.APICurrent >= u.APIMax {
+		u.Exhausted = true
+		r.current = (i + 1) % len(r.keys)
+	}
+}
+
+// grabKey returns the key to hand out in a download URL, counting the grab:
+// the key with the most grabs left as last reported, else the first usable
+// key from the current one. Keys out of grabs or requests are used only if
+// every key is.
+func (r *keyRing) grabKey() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rollover()
+	if len(r.keys) == 0 {
+		return ""
+	}
+	best, bestLeft := -1, 0
+	for n := range r.keys {
+		i := (r.current + n) % len(r.keys)
+		left := r.usage[i].GrabsLeft()
+		if left == 0 || r.usage[i].Exhausted {
+			continue
+		}
+		// Unknown quota (-1) loses to any reported grabs left
+		if best < 0 || left > bestLeft {
+			best, bestLeft = i, left
+		}
+	}
+	if best < 0 {
+		best = r.current
+	}
+	u := &r.usage[best]
+	u.Grabs++
+	if u.GrabMax > 0 {
+		u.GrabCurrent++
+	}
+	return r.keys[best]
+}
+
+// has reports whether key is one of the ring's keys.
+func (r *keyRing) has(key string) bool {
+	return key != "" && slices.Contains(r.keys, key)
+}
+
+// snapshot returns the usage of every key with the keys masked.
+func (r *keyRing) snapshot() []KeyUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rollover()
+	out := slices.Clone(r.usage)
+	for i := range out {
+		out[i].Key = maskKey(r.keys[i])
+	}
+	return out
+}
+
+// rollover resets usage when the UTC day changes; the caller holds the lock.
+func (r *keyRing) rollover() {
+	today := r.now().UTC().Format(time.DateOnly)
+	if today != r.day {
+		r.day = today
+		r.current = 0
+		r.usage = make([]KeyUsage, len(r.keys))
+	}
+}
+
+// maskKey hides all but the last 4 characters of an API key.
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
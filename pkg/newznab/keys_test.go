@@ -0,0 +1,132 @@
+package newznab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const limitReachedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<error code="500" description="Request limit reached"/>`
+
+// limitsXML is an empty search response reporting the key's limits.
+func limitsXML(apiCurrent, apiMax, grabCurrent, grabMax int) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:newznab="http://www.newznab.com/DTD/2010/feeds/attributes/">
+  <channel>
+    <newznab:apilimits apicurrent="%d" apimax="%d" grabcurrent="%d" grabmax="%d"/>
+  </channel>
+</rss>`, apiCurrent, apiMax, grabCurrent, grabMax)
+}
+
+func TestClient_Search_RotatesKeyOnLimit(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("apikey")
+		keys = append(keys, key)
+		if key == "first-key" {
+			_, _ = w.Write([]byte(limitReachedXML))
+			return
+		}
+		_, _ = w.Write([]byte(testXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient("Test", server.URL, "first-key", nil, WithAPIKeys("second-key"))
+
+	releases, err := client.Search(context.Background(), "test", nil)
+	require.NoError(t, err, "the call fails over to the next key")
+	assert.Len(t, releases, 2)
+
+	_, err = client.Search(context.Background(), "test", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first-key", "second-key", "second-key"}, keys, "later calls keep the next key")
+
+	usage := client.KeyUsage()
+	require.Len(t, usage, 2)
+	assert.Equal(t, "****-key", usage[0].Key, "keys are masked")
+	assert.True(t, usage[0].Exhausted)
+	assert.Equal(t, 1, usage[0].Requests)
+	assert.False(t, usage[1].Exhausted)
+	assert.Equal(t, 2, usage[1].Requests)
+}
+
+func TestClient_Search_AllKeysExhausted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(limitReachedXML))
+	}))
+	defer server.Close()
+
+	client := NewClient("Test", server.URL, "key-a", nil, WithAPIKeys("key-b"))
+	_, err := client.Search(context.Background(), "test", nil)
+	require.ErrorIs(t, err, ErrLimitReached)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, ErrorCodeRequestLimit, apiErr.Code)
+}
+
+func TestKeyRing_ResetsDaily(t *testing.T) {
+	now := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	r := newKeyRing("key-a", "key-b")
+	r.now = func() time.Time { return now }
+
+	i, key, err := r.acquire()
+	require.NoError(t, err)
+	assert.Equal(t, "key-a", key)
+	assert.True(t, r.exhaust(i), "key-b is left")
+	_, key, _ = r.acquire()
+	assert.Equal(t, "key-b", key)
+
+	now = now.Add(2 * time.Hour) // Next UTC day
+	_, key, err = r.acquire()
+	require.NoError(t, err)
+	assert.Equal(t, "key-a", key, "exhausted keys reset on the day boundary")
+	assert.Equal(t, 1, r.snapshot()[0].Requests)
+}
+
+func TestClient_GrabURL(t *testing.T) {
+	limits := map[string]string{
+		"key-a": limitsXML(10, 1000, 49, 50),
+		"key-b": limitsXML(3, 1000, 5, 50),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(limits[r.URL.Query().Get("apikey")]))
+	}))
+	defer server.Close()
+
+	client := NewClient("Test", server.URL, "key-a", nil, WithAPIKeys("key-b"))
+	_, err := client.Search(context.Background(), "test", nil)
+	require.NoError(t, err)
+
+	// Only key-a has reported limits so far; unknown quota loses to known grabs left
+	assert.Equal(t, server.URL+"/api?apikey=key-a&id=abc&t=get", client.GrabURL(server.URL+"/api?t=get&id=abc&apikey=key-a"))
+	assert.Equal(t, 50, client.KeyUsage()[0].GrabCurrent, "the grab is counted against the quota")
+
+	// key-a is out of grabs; the next grab uses key-b even though searches still use key-a
+	got := client.GrabURL(server.URL + "/getnzb/abc.nzb?i=1&r=key-a")
+	assert.Equal(t, server.URL+"/getnzb/abc.nzb?i=1&r=key-b", got, "any parameter holding a key is replaced")
+
+	assert.Equal(t, "https://other.example/nzb?id=1", client.GrabURL("https://other.example/nzb?id=1"), "URLs without a key are unchanged")
+
+	usage := client.KeyUsage()
+	assert.Equal(t, 1, usage[0].Grabs)
+	assert.Equal(t, 1, usage[1].Grabs)
+}
+
+func TestKeyRing_ReportedAPILimitExhausts(t *testing.T) {
+	r := newKeyRing("key-a", "key-b")
+	i, _, err := r.acquire()
+	require.NoError(t, err)
+	r.report(i, apiLimits{APICurrent: 100, APIMax: 100})
+
+	_, key, err := r.acquire()
+	require.NoError(t, err)
+	assert.Equal(t, "key-b", key, "a key at its reported limit is skipped")
+}
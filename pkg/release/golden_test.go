@@ -397,8 +397,7 @@ var goldenCases = []struct {
 		codec:      CodecX264,
 		title:      "Miss Marple A Caribbean Mystery",
 		year:       1989,
-		// NOTE: Group extraction stops at extra periods in suffix like [NO.RAR]
-		group: "YELLOWBiRD.[NO",
+		group:      "YELLOWBiRD",
 	},
 	{
 		name:       "x265 standard",
@@ -2,10 +2,13 @@
 package release
 
 import (
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // Pre-compiled regex patterns (compiled once at package init)
@@ -68,6 +71,52 @@ var serviceMap = map[string]string{
 	"it":        "iT",
 }
 
+// languageTokens maps release name words to the audio language they indicate.
+// Short codes that are also common words ("de", "it") are left out.
+var languageTokens = map[string]string{
+	"english":    LanguageEnglish,
+	"eng":        LanguageEnglish,
+	"german":     "german",
+	"ger":        "german",
+	"deutsch":    "german",
+	"french":     "french",
+	"truefrench": "french",
+	"vff":        "french",
+	"vfq":        "french",
+	"vfi":        "french",
+	"vf":         "french",
+	"vf2":        "french",
+	"vostfr":     "french",
+	"italian":    "italian",
+	"ita":        "italian",
+	"spanish":    "spanish",
+	"spa":        "spanish",
+	"esp":        "spanish",
+	"castellano": "spanish",
+	"latino":     "spanish",
+	"dutch":      "dutch",
+	"flemish":    "dutch",
+	"swedish":    "swedish",
+	"norwegian":  "norwegian",
+	"danish":     "danish",
+	"finnish":    "finnish",
+	"polish":     "polish",
+	"russian":    "russian",
+	"rus":        "russian",
+	"portuguese": "portuguese",
+	"japanese":   "japanese",
+	"jpn":        "japanese",
+	"korean":     "korean",
+	"kor":        "korean",
+	"chinese":    "chinese",
+	"mandarin":   "chinese",
+	"cantonese":  "chinese",
+	"hindi":      "hindi",
+	"turkish":    "turkish",
+	"czech":      "czech",
+	"hungarian":  "hungarian",
+}
+
 // Parse extracts information from a release name.
 func Parse(name string) *Info {
 	info := &Info{}
@@ -200,15 +249,7 @@ func Parse(name string) *Info {
 		}
 	}
 
-	// Group (usually last, after hyphen)
-	if idx := strings.LastIndex(name, "-"); idx > 0 {
-		group := strings.TrimSpace(name[idx+1:])
-		// Remove file extension if present
-		if dotIdx := strings.LastIndex(group, "."); dotIdx > 0 {
-			group = group[:dotIdx]
-		}
-		info.Group = group
-	}
+	info.Group = ParseGroup(name)
 
 	// Title - extract from start up to the earliest title boundary
 	// Boundaries: release year, season/episode marker, resolution, daily date, etc.
@@ -248,9 +289,142 @@ func Parse(name string) *Info {
 	// Clean title for matching
 	info.CleanTitle = CleanTitle(info.Title)
 
+	// Languages - only after the title, so "The Good German" isn't German
+	rest := normalized
+	if titleEnd > 0 {
+		rest = normalized[titleEnd:]
+	}
+	info.Languages, info.Multi = parseLanguages(rest)
+
 	return info
 }
 
+// ParseGroup returns the release group of a release or file name: the
+// "-GROUP" suffix, else a leading or trailing "[GROUP]" tag. Site tags after
+// the group ("[rartv]"), file extensions, domains the group appends
+// ("-YTS.MX" is "YTS") and a "NoGroup" placeholder are ignored. Returns ""
+// for releases with no group.
+func ParseGroup(name string) string {
+	name = strings.TrimSpace(name)
+	if ext := strings.ToLower(filepath.Ext(name)); groupFileExts[ext] {
+		name = name[:len(name)-len(ext)]
+	}
+
+	// Strip trailing tags, keeping the first that could be the group itself
+	bracketed := ""
+	for strings.HasSuffix(name, "]") {
+		open := strings.LastIndex(name, "[")
+		if open < 0 {
+			break
+		}
+		if tag := name[open+1 : len(name)-1]; bracketed == "" && isBracketGroup(tag) {
+			bracketed = tag
+		}
+		name = strings.TrimRight(name[:open], " ._")
+	}
+
+	group := ""
+	if idx := strings.LastIndex(name, "-"); idx > 0 && isSuffixGroup(name[idx+1:]) {
+		group = name[idx+1:]
+		if m := groupDomainRegex.FindStringSubmatch(group); m != nil {
+			group = m[1]
+		}
+	} else if m := leadingGroupRegex.FindStringSubmatch(name); m != nil && isBracketGroup(m[1]) {
+		group = m[1]
+	} else {
+		group = bracketed
+	}
+	if strings.EqualFold(group, "NoGroup") {
+		return ""
+	}
+	return group
+}
+
+// groupFileExts are file extensions removed before looking for a group.
+var groupFileExts = map[string]bool{
+	".mkv": true, ".mp4": true, ".avi": true, ".m4v": true, ".ts": true, ".wmv": true,
+	".nzb": true, ".torrent": true,
+}
+
+// groupSiteTags are bracketed tags indexers and sites add after the group.
+var groupSiteTags = map[string]bool{
+	"rartv": true, "rarbg": true, "eztv": true, "eztv.re": true, "ettv": true, "tgx": true,
+	"publichd": true, "no.rar": true, "norar": true, "vtv": true,
+}
+
+// groupTechWords are technical tokens that follow a hyphen inside a release
+// name ("WEB-DL", "DTS-HD.MA", "Blu-ray") and are never a group.
+var groupTechWords = map[string]bool{
+	"dl": true, "hd": true, "ma": true, "ray": true, "rip": true, "x": true, "es": true,
+	"web": true, "hdr": true, "hdr10": true, "dv": true, "sdr": true, "atmos": true,
+	"x264": true, "x265": true, "h264": true, "h265": true, "hevc": true, "avc": true,
+	"aac": true, "ac3": true, "dts": true, "ddp": true, "flac": true,
+}
+
+var (
+	leadingGroupRegex    = regexp.MustCompile(`^\[([^\]]+)\]`)
+	groupHashRegex       = regexp.MustCompile(`^[0-9A-Fa-f]{8}$`) // CRC32 tags of anime releases
+	groupResolutionRegex = regexp.MustCompile(`(?i)^(\d{3,4}[pi]|4k|uhd|\d{3,4}x\d{3,4})$`)
+	groupEpisodeRegex    = regexp.MustCompile(`(?i)^(s\d{1,2}(e\d{1,4})*|e\d{1,4})$`)
+	groupDomainRegex     = regexp.MustCompile(`^(\w{2,})\.(?:[a-z]{2}|com|net|org)$`)
+)
+
+// isSuffixGroup reports whether the text after a release name's last hyphen
+// is a group rather than the tail of a technical token or an episode number.
+func isSuffixGroup(s string) bool {
+	return !strings.ContainsAny(s, " []()") && isGroupName(s)
+}
+
+// isBracketGroup reports whether a bracketed tag could be a group rather than
+// a site tag, checksum or list of technical details.
+func isBracketGroup(tag string) bool {
+	tag = strings.TrimSpace(tag)
+	if strings.Contains(tag, " ") || groupSiteTags[strings.ToLower(tag)] || groupHashRegex.MatchString(tag) {
+		return false
+	}
+	return isGroupName(tag)
+}
+
+// isGroupName reports whether s could name a group. Groups may contain dots
+// ("E.N.D") but no empty, numbers-only or technical parts.
+func isGroupName(s string) bool {
+	if s == "" || groupEpisodeRegex.MatchString(s) {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "" || groupTechWords[strings.ToLower(part)] || groupResolutionRegex.MatchString(part) {
+			return false
+		}
+		if strings.Trim(part, "0123456789") == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseLanguages returns the audio languages named in a release and whether it
+// is MULTi. Releases that name no language and aren't MULTi are English.
+func parseLanguages(name string) ([]string, bool) {
+	var languages []string
+	multi := false
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if w == "multi" {
+			multi = true
+			continue
+		}
+		if lang, ok := languageTokens[w]; ok && !slices.Contains(languages, lang) {
+			languages = append(languages, lang)
+		}
+	}
+	if len(languages) == 0 && !multi {
+		languages = []string{LanguageEnglish}
+	}
+	return languages, multi
+}
+
 func parseResolution(name string) Resolution {
 	name = strings.ToLower(name)
 	switch {
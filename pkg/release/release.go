@@ -1,6 +1,8 @@
 // Package release provides types for parsing and representing media release information.
 package release
 
+import "time"
+
 // Resolution represents the video resolution of a release.
 type Resolution int
 
@@ -171,6 +173,11 @@ type Info struct {
 	Edition string // "Directors Cut", "Extended", "IMAX", etc.
 	Service string // Streaming service: NF, AMZN, DSNP, etc.
 
+	// Audio languages as lowercase English names, in the order named ("german", "french").
+	// English is assumed when a release names no language and isn't MULTi.
+	Languages []string
+	Multi     bool // MULTi: several audio tracks, usually the original plus a dub
+
 	// Season pack detection
 	IsCompleteSeason bool // Complete season release (e.g., "Season 01", "S01")
 	IsSplitSeason    bool // Split/partial season (e.g., "Season 1 Part 2")
@@ -182,3 +189,18 @@ type Info struct {
 	// Match confidence (set during title matching, not parsing)
 	MatchConfidence MatchConfidence `json:"match_confidence,omitempty"`
 }
+
+// LanguageEnglish is the language assumed for releases that name none.
+const LanguageEnglish = "english"
+
+// AirDate returns DailyDate as a UTC date, or false if the release has no date.
+func (i *Info) AirDate() (time.Time, bool) {
+	if i.DailyDate == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.DateOnly, i.DailyDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
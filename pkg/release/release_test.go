@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResolution_String(t *testing.T) {
@@ -1040,3 +1041,97 @@ func TestIsValidReleaseYear(t *testing.T) {
 		})
 	}
 }
+
+func TestInfo_AirDate(t *testing.T) {
+	info := Parse("The.Daily.Show.2024.03.14.Guest.Name.1080p.WEB")
+	date, ok := info.AirDate()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC), date)
+	assert.Equal(t, 0, info.Season)
+
+	info = Parse("Show.S01E02.1080p.WEB")
+	_, ok = info.AirDate()
+	assert.False(t, ok)
+}
+
+func TestParse_Languages(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantLangs []string
+		wantMulti bool
+	}{
+		{"No language is English", "Movie.2024.1080p.BluRay.x264-GRP", []string{"english"}, false},
+		{"German", "Movie.2024.GERMAN.DL.1080p.BluRay.x264-GRP", []string{"german"}, false},
+		{"TrueFrench", "Movie.2024.TRUEFRENCH.1080p.WEB-DL.x264-GRP", []string{"french"}, false},
+		{"VOSTFR", "Show.S01E02.VOSTFR.720p.WEB.x264-GRP", []string{"french"}, false},
+		{"Mixed case iTALiAN", "Movie.2024.iTALiAN.1080p.WEB-DL.x264-GRP", []string{"italian"}, false},
+		{"MULTi alone", "Movie.2024.MULTi.1080p.BluRay.x264-GRP", nil, true},
+		{"MULTi with named language", "Movie.2024.MULTi.VFF.2160p.WEB-DL.x265-GRP", []string{"french"}, true},
+		{"Language word in title", "The.Good.German.2006.1080p.BluRay.x264-GRP", []string{"english"}, false},
+		{"Several languages", "Movie.2024.German.English.1080p.BluRay.x264-GRP", []string{"german", "english"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.input)
+			assert.Equal(t, tt.wantLangs, got.Languages, "Languages")
+			assert.Equal(t, tt.wantMulti, got.Multi, "Multi")
+		})
+	}
+}
+
+func TestParseGroup(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Suffix", "Movie.2024.1080p.BluRay.x264-SPARKS", "SPARKS"},
+		{"Dotted group", "Movie.2024.1080p.BluRay.x264-E.N.D", "E.N.D"},
+		{"Site tag after group", "Show.S01E02.720p.HDTV.x264-KILLERS[rartv]", "KILLERS"},
+		{"Several site tags", "Show.S01E02.1080p.WEB.h264-GOSSIP [eztv] [TGx]", "GOSSIP"},
+		{"Bracketed suffix", "Movie.2024.1080p.BluRay.x264 [FraMeSToR]", "FraMeSToR"},
+		{"Leading bracket", "[SubsPlease] Frieren - 12 (1080p) [A1B2C3D4].mkv", "SubsPlease"},
+		{"Leading bracket over detail tags", "[DKB] Show-S01E14 [1080p][HEVC x265 10bit][Multi-Subs][E2366D39]", "DKB"},
+		{"Domain dropped", "movie.2024.1080p.webrip.x264.aac-yts.mx", "yts"},
+		{"File extension", "Movie.2024.1080p.WEB-DL.DDP5.1.H.264-FLUX.mkv", "FLUX"},
+		{"NoGroup", "Movie.2024.1080p.WEB-DL.x264-NoGroup", ""},
+		{"WEB-DL without group", "Movie.2024.1080p.WEB-DL.x264", ""},
+		{"WEB without group", "Show.S01E01.1080p.WEB.H264", ""},
+		{"Audio without group", "Movie.1992.1080p.Blu-ray.AVC.DTS-HD.MA.2.0", ""},
+		{"Hyphen in title", "Spider-Man.2002.1080p.BluRay.x264", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseGroup(tt.input))
+			assert.Equal(t, tt.want, Parse(tt.input).Group)
+		})
+	}
+}
+
+func TestResolveSeriesTarget(t *testing.T) {
+	season := 3
+
+	target, err := ResolveSeriesTarget("Some.Show.S02E05E06.1080p.WEB-DL", false, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, target.Season)
+	assert.Equal(t, []int{5, 6}, target.Episodes)
+	assert.False(t, target.IsCompleteSeason)
+
+	target, err = ResolveSeriesTarget("Some.Show.S02.1080p.WEB-DL", false, &season, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, target.Season, "season override")
+	assert.True(t, target.IsCompleteSeason)
+
+	target, err = ResolveSeriesTarget("The.Daily.Show.2024.03.14.1080p.WEB", true, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, target.AirDate)
+	assert.Equal(t, "2024-03-14", target.AirDate.Format(time.DateOnly))
+
+	_, err = ResolveSeriesTarget("Some.Show.1080p.WEB-DL", false, nil, nil)
+	require.ErrorIs(t, err, ErrNoSeason)
+	_, err = ResolveSeriesTarget("Some.Show.1080p.WEB-DL", false, &season, nil)
+	require.ErrorIs(t, err, ErrNoEpisodes)
+}
@@ -23,8 +23,32 @@ const (
 	BonusHDR    = 15
 	BonusAudio  = 15
 	BonusRemux  = 20
+
+	BonusLanguage = 10
+	BonusGroup    = 15 // Top tier of a profile's preferred groups
 )
 
+// DefaultMultiLanguages are the languages a MULTi release is assumed to carry
+// when the profile doesn't say: the original English audio plus a French dub.
+var DefaultMultiLanguages = []string{release.LanguageEnglish, "french"}
+
+// Breakdown is a release's score split by what earned each part.
+// Total is the sum of the parts, or 0 when the profile rejects the release.
+type Breakdown struct {
+	Resolution int `json:"resolution"`
+	Source     int `json:"source"`
+	Codec      int `json:"codec"`
+	HDR        int `json:"hdr"`
+	Audio      int `json:"audio"`
+	Remux      int `json:"remux"`
+	Language   int `json:"language"`
+	Group      int `json:"group"`    // Preferred group tier
+	Keywords   int `json:"keywords"` // Preferred keyword weights
+	// SequelPenalty is set for a sequel the query didn't ask for; Total is negated to rank it last.
+	SequelPenalty bool `json:"sequel_penalty,omitempty"`
+	Total         int  `json:"total"`
+}
+
 // ResolutionBaseScore returns the base score for a given resolution.
 func ResolutionBaseScore(r release.Resolution) int {
 	switch r {
@@ -39,6 +63,27 @@ func ResolutionBaseScore(r release.Resolution) int {
 	}
 }
 
+// SourceRank returns a numeric rank for source comparison.
+// Higher is better: BluRay=6, WEB-DL=5, WEBRip=4, HDTV=3, Telesync=2, CAM=1, unknown=0
+func SourceRank(s release.Source) int {
+	switch s {
+	case release.SourceBluRay:
+		return 6
+	case release.SourceWEBDL:
+		return 5
+	case release.SourceWEBRip:
+		return 4
+	case release.SourceHDTV:
+		return 3
+	case release.SourceTelesync:
+		return 2
+	case release.SourceCAM:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // HDRMatches checks if an HDR format matches a preference string.
 func HDRMatches(hdr release.HDRFormat, pref string) bool {
 	prefLower := strings.ToLower(pref)
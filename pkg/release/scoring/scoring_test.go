@@ -58,6 +58,24 @@ func TestResolutionBaseScore(t *testing.T) {
 	}
 }
 
+func TestSourceRank(t *testing.T) {
+	order := []release.Source{
+		release.SourceUnknown,
+		release.SourceCAM,
+		release.SourceTelesync,
+		release.SourceHDTV,
+		release.SourceWEBRip,
+		release.SourceWEBDL,
+		release.SourceBluRay,
+	}
+	for n := 1; n < len(order); n++ {
+		if SourceRank(order[n]) <= SourceRank(order[n-1]) {
+			t.Errorf("SourceRank(%v) = %d, want above SourceRank(%v) = %d",
+				order[n], SourceRank(order[n]), order[n-1], SourceRank(order[n-1]))
+		}
+	}
+}
+
 func TestHDRMatches(t *testing.T) {
 	tests := []struct {
 		hdr  release.HDRFormat
@@ -0,0 +1,55 @@
+package release
+
+import (
+	"errors"
+	"time"
+)
+
+// Errors returned by ResolveSeriesTarget.
+var (
+	ErrNoSeason   = errors.New("cannot determine season from release title")
+	ErrNoEpisodes = errors.New("cannot determine episodes from release title")
+)
+
+// SeriesTarget is the part of a series a release covers: a season pack, some
+// episodes of a season, or the episode that aired on a date.
+type SeriesTarget struct {
+	Season           int        // 0 for dated releases
+	Episodes         []int      // Episode numbers; empty for season packs and dated releases
+	IsCompleteSeason bool       // Season pack; its episodes are matched at import
+	AirDate          *time.Time // Dated releases: the episode is matched by air date
+}
+
+// ResolveSeriesTarget determines the season and episodes a series release
+// covers by parsing its title. A non-nil season or non-empty episodes
+// override the parsed values. Without overrides, dated releases of daily
+// series, or of any series when the title has no season, target an air date.
+// Returns ErrNoSeason or ErrNoEpisodes if the title doesn't say.
+func ResolveSeriesTarget(title string, daily bool, season *int, episodes []int) (*SeriesTarget, error) {
+	parsed := Parse(title)
+	if airDate, ok := parsed.AirDate(); ok && season == nil && len(episodes) == 0 &&
+		(daily || parsed.Season == 0) {
+		return &SeriesTarget{AirDate: &airDate}, nil
+	}
+
+	target := &SeriesTarget{Season: parsed.Season, Episodes: parsed.Episodes}
+	if season != nil {
+		target.Season = *season
+	}
+	if len(episodes) > 0 {
+		target.Episodes = episodes
+	}
+	if target.Season == 0 {
+		return nil, ErrNoSeason
+	}
+
+	switch {
+	case parsed.IsCompleteSeason && len(target.Episodes) == 0:
+		target.IsCompleteSeason = true
+	case len(target.Episodes) > 0:
+	default:
+		// No episode info and not a season pack
+		return nil, ErrNoEpisodes
+	}
+	return target, nil
+}
@@ -16,6 +16,9 @@ import (
 
 const defaultBaseURL = "https://api4.thetvdb.com/v4"
 
+// DefaultTimeout bounds each TVDB request when no timeout is given.
+const DefaultTimeout = 30 * time.Second
+
 // Sentinel errors for TVDB API responses.
 var (
 	ErrNotFound     = errors.New("series not found")
@@ -52,6 +55,15 @@ func WithHTTPClient(hc *http.Client) Option {
 	}
 }
 
+// WithTimeout sets the timeout for each request to TVDB.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Timeout = d
+		c.httpClient = &hc
+	}
+}
+
 // WithLogger sets a logger for debug output.
 func WithLogger(log *slog.Logger) Option {
 	return func(c *Client) {
@@ -65,7 +77,7 @@ func New(apiKey string, opts ...Option) *Client {
 		apiKey:  apiKey,
 		baseURL: defaultBaseURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultTimeout,
 		},
 	}
 	for _, opt := range opts {
@@ -278,6 +290,7 @@ func (c *Client) GetSeries(ctx context.Context, id int) (*Series, error) {
 		Year:     year,
 		Status:   seriesResp.Data.Status.Name,
 		Overview: seriesResp.Data.Overview,
+		Aliases:  aliasNames(seriesResp.Data.Aliases),
 	}
 
 	if c.log != nil {
@@ -287,6 +300,56 @@ func (c *Client) GetSeries(ctx context.Context, id int) (*Series, error) {
 	return series, nil
 }
 
+// GetSeriesExtended fetches series metadata including poster, runtime, and genres.
+func (c *Client) GetSeriesExtended(ctx context.Context, id int) (*Series, error) {
+	start := time.Now()
+
+	endpoint := fmt.Sprintf("/series/%d/extended?short=true", id)
+	resp, err := c.doRequest(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := c.checkResponse(resp); err != nil {
+		if c.log != nil && errors.Is(err, ErrNotFound) {
+			c.log.Debug("series not found", "id", id)
+		}
+		return nil, err
+	}
+
+	var seriesResp seriesExtendedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&seriesResp); err != nil {
+		return nil, fmt.Errorf("decode series response: %w", err)
+	}
+
+	// Extract year from firstAired (format: YYYY-MM-DD)
+	var year int
+	if len(seriesResp.Data.FirstAired) >= 4 {
+		year, _ = strconv.Atoi(seriesResp.Data.FirstAired[:4])
+	}
+
+	series := &Series{
+		ID:       seriesResp.Data.ID,
+		Name:     seriesResp.Data.Name,
+		Year:     year,
+		Status:   seriesResp.Data.Status.Name,
+		Overview: seriesResp.Data.Overview,
+		Image:    seriesResp.Data.Image,
+		Runtime:  seriesResp.Data.AverageRuntime,
+		Aliases:  aliasNames(seriesResp.Data.Aliases),
+	}
+	for _, g := range seriesResp.Data.Genres {
+		series.Genres = append(series.Genres, g.Name)
+	}
+
+	if c.log != nil {
+		c.log.Debug("fetched series extended", "id", id, "name", series.Name, "duration_ms", time.Since(start).Milliseconds())
+	}
+
+	return series, nil
+}
+
 // GetEpisodes fetches all episodes for a series, handling pagination automatically.
 func (c *Client) GetEpisodes(ctx context.Context, seriesID int) ([]Episode, error) {
 	start := time.Now()
@@ -97,6 +97,35 @@ func TestNew_WithOptions(t *testing.T) {
 	assert.Same(t, customHTTP, client.httpClient)
 }
 
+func TestNew_WithTimeout(t *testing.T) {
+	customHTTP := &http.Client{Timeout: 5 * time.Second}
+
+	client := New("test-key", WithHTTPClient(customHTTP), WithTimeout(time.Second))
+
+	assert.Equal(t, time.Second, client.httpClient.Timeout)
+	assert.Equal(t, 5*time.Second, customHTTP.Timeout, "caller's client is not modified")
+}
+
+func TestClient_Timeout(t *testing.T) {
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never respond
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	defer server.Close()
+	defer close(stop)
+
+	client := New("test-key", WithBaseURL(server.URL), WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.GetEpisodes(context.Background(), 81189)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second, "request should give up after the configured timeout")
+}
+
 func TestLogin_Success(t *testing.T) {
 	server := mockTVDB(t, map[string]http.HandlerFunc{
 		"/login": loginHandler("valid-key", "jwt-token-123"),
@@ -239,8 +268,9 @@ func TestGetSeries_Success(t *testing.T) {
 					Status struct {
 						Name string `json:"name"`
 					} `json:"status"`
-					Overview   string `json:"overview"`
-					FirstAired string `json:"firstAired"`
+					Overview   string        `json:"overview"`
+					FirstAired string        `json:"firstAired"`
+					Aliases    []seriesAlias `json:"aliases"`
 				}{
 					ID:   81189,
 					Name: "Breaking Bad",
@@ -249,6 +279,7 @@ func TestGetSeries_Success(t *testing.T) {
 					}{Name: "Ended"},
 					Overview:   "A high school chemistry teacher diagnosed with terminal lung cancer...",
 					FirstAired: "2008-01-20",
+					Aliases:    []seriesAlias{{Language: "spa", Name: "Breaking Bad: Reacciones Químicas"}},
 				},
 			})
 		}),
@@ -264,6 +295,7 @@ func TestGetSeries_Success(t *testing.T) {
 	assert.Equal(t, 2008, series.Year)
 	assert.Equal(t, "Ended", series.Status)
 	assert.Contains(t, series.Overview, "chemistry teacher")
+	assert.Equal(t, []string{"Breaking Bad: Reacciones Químicas"}, series.Aliases)
 }
 
 func TestGetSeries_NotFound(t *testing.T) {
@@ -284,6 +316,44 @@ func TestGetSeries_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, ErrNotFound)
 }
 
+func TestGetSeriesExtended_Success(t *testing.T) {
+	const token = "test-token"
+
+	server := mockTVDB(t, map[string]http.HandlerFunc{
+		"/login": loginHandler("api-key", token),
+		"/series/81189/extended": requireAuth(token, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "true", r.URL.Query().Get("short"))
+			w.Header().Set("Content-Type", "application/json")
+			writeJSON(w, map[string]any{
+				"status": "success",
+				"data": map[string]any{
+					"id":             81189,
+					"name":           "Breaking Bad",
+					"status":         map[string]string{"name": "Ended"},
+					"overview":       "A high school chemistry teacher...",
+					"firstAired":     "2008-01-20",
+					"image":          "https://artworks.thetvdb.com/banners/posters/81189-10.jpg",
+					"averageRuntime": 47,
+					"genres":         []map[string]any{{"id": 1, "name": "Drama"}, {"id": 2, "name": "Crime"}},
+					"aliases":        []map[string]any{{"language": "spa", "name": "Breaking Bad: Reacciones Químicas"}},
+				},
+			})
+		}),
+	})
+	defer server.Close()
+
+	client := New("api-key", WithBaseURL(server.URL))
+	series, err := client.GetSeriesExtended(context.Background(), 81189)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Breaking Bad", series.Name)
+	assert.Equal(t, 2008, series.Year)
+	assert.Equal(t, "https://artworks.thetvdb.com/banners/posters/81189-10.jpg", series.Image)
+	assert.Equal(t, 47, series.Runtime)
+	assert.Equal(t, []string{"Drama", "Crime"}, series.Genres)
+	assert.Equal(t, []string{"Breaking Bad: Reacciones Químicas"}, series.Aliases)
+}
+
 func TestGetEpisodes_Success(t *testing.T) {
 	const token = "test-token"
 
@@ -466,8 +536,9 @@ func TestTokenRefresh_OnExpiry(t *testing.T) {
 						Status struct {
 							Name string `json:"name"`
 						} `json:"status"`
-						Overview   string `json:"overview"`
-						FirstAired string `json:"firstAired"`
+						Overview   string        `json:"overview"`
+						FirstAired string        `json:"firstAired"`
+						Aliases    []seriesAlias `json:"aliases"`
 					}{
 						ID:         123,
 						Name:       "Test Series",
@@ -608,8 +679,9 @@ func TestGetSeries_HandlesEmptyFirstAired(t *testing.T) {
 					Status struct {
 						Name string `json:"name"`
 					} `json:"status"`
-					Overview   string `json:"overview"`
-					FirstAired string `json:"firstAired"`
+					Overview   string        `json:"overview"`
+					FirstAired string        `json:"firstAired"`
+					Aliases    []seriesAlias `json:"aliases"`
 				}{
 					ID:         123,
 					Name:       "Upcoming Show",
@@ -5,11 +5,17 @@ import "time"
 
 // Series represents a TV series from TVDB.
 type Series struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	Year     int    `json:"year"`   // Extracted from firstAired
-	Status   string `json:"status"` // "Continuing" or "Ended"
-	Overview string `json:"overview"`
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Year     int      `json:"year"`   // Extracted from firstAired
+	Status   string   `json:"status"` // "Continuing" or "Ended"
+	Overview string   `json:"overview"`
+	Aliases  []string `json:"aliases,omitempty"` // Alternate and localized names
+
+	// Set by GetSeriesExtended only
+	Image   string   `json:"image,omitempty"`   // Poster URL
+	Runtime int      `json:"runtime,omitempty"` // Average episode runtime in minutes
+	Genres  []string `json:"genres,omitempty"`
 }
 
 // Episode represents a single episode from TVDB.
@@ -55,6 +61,24 @@ type searchResponse struct {
 	} `json:"data"`
 }
 
+// seriesAlias is an alternate name in a TVDB series record.
+type seriesAlias struct {
+	Language string `json:"language"`
+	Name     string `json:"name"`
+}
+
+// aliasNames returns the names of aliases, in order.
+func aliasNames(aliases []seriesAlias) []string {
+	if len(aliases) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(aliases))
+	for _, a := range aliases {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
 // seriesResponse is the TVDB get series API response.
 type seriesResponse struct {
 	Status string `json:"status"`
@@ -64,8 +88,29 @@ type seriesResponse struct {
 		Status struct {
 			Name string `json:"name"`
 		} `json:"status"`
-		Overview   string `json:"overview"`
-		FirstAired string `json:"firstAired"` // YYYY-MM-DD
+		Overview   string        `json:"overview"`
+		FirstAired string        `json:"firstAired"` // YYYY-MM-DD
+		Aliases    []seriesAlias `json:"aliases"`
+	} `json:"data"`
+}
+
+// seriesExtendedResponse is the TVDB get series extended API response.
+type seriesExtendedResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Status struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		Overview       string `json:"overview"`
+		FirstAired     string `json:"firstAired"` // YYYY-MM-DD
+		Image          string `json:"image"`
+		AverageRuntime int    `json:"averageRuntime"`
+		Genres         []struct {
+			Name string `json:"name"`
+		} `json:"genres"`
+		Aliases []seriesAlias `json:"aliases"`
 	} `json:"data"`
 }
 